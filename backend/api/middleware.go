@@ -120,6 +120,26 @@ func MasterOnlyMiddleware() mux.MiddlewareFunc {
 	}
 }
 
+// FeatureHandlerFunc wraps next so it only runs for accounts the admin has
+// not denied feature. Master accounts always pass.
+func FeatureHandlerFunc(accountsSvc *accounts.Service, feature string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions || IsMaster(r) {
+			next(w, r)
+			return
+		}
+
+		if accountsSvc == nil || !accountsSvc.HasFeature(GetAccountID(r), feature) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]string{"error": "feature not enabled for this account", "feature": feature})
+			return
+		}
+
+		next(w, r)
+	}
+}
+
 // ProfileOwnershipMiddleware creates middleware that verifies profile ownership.
 // Master accounts can access any profile; regular accounts can only access their own.
 func ProfileOwnershipMiddleware(usersSvc *users.Service) mux.MiddlewareFunc {
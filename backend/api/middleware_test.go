@@ -1,6 +1,15 @@
 package api
 
-import "testing"
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"novastream/internal/auth"
+	"novastream/models"
+	"novastream/services/accounts"
+)
 
 func TestIsStreamScopedPathAllowed(t *testing.T) {
 	allowed := []string{
@@ -30,3 +39,42 @@ func TestIsStreamScopedPathAllowed(t *testing.T) {
 		}
 	}
 }
+
+func TestFeatureHandlerFunc(t *testing.T) {
+	svc, err := accounts.NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("accounts service: %v", err)
+	}
+	account, err := svc.Create("kid", "secret")
+	if err != nil {
+		t.Fatalf("create account: %v", err)
+	}
+	if err := svc.SetDeniedFeatures(account.ID, []string{models.FeatureCacheRefresh}); err != nil {
+		t.Fatalf("deny feature: %v", err)
+	}
+
+	handler := FeatureHandlerFunc(svc, models.FeatureCacheRefresh, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	serve := func(accountID string, isMaster bool) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/cache/refresh", nil)
+		ctx := context.WithValue(req.Context(), auth.ContextKeyAccountID, accountID)
+		ctx = context.WithValue(ctx, auth.ContextKeyIsMaster, isMaster)
+		rec := httptest.NewRecorder()
+		handler(rec, req.WithContext(ctx))
+		return rec.Code
+	}
+
+	if code := serve(account.ID, false); code != http.StatusForbidden {
+		t.Errorf("denied account: got %d, want %d", code, http.StatusForbidden)
+	}
+	if code := serve("master", true); code != http.StatusNoContent {
+		t.Errorf("master account: got %d, want %d", code, http.StatusNoContent)
+	}
+	if err := svc.SetDeniedFeatures(account.ID, nil); err != nil {
+		t.Fatalf("allow feature: %v", err)
+	}
+	if code := serve(account.ID, false); code != http.StatusNoContent {
+		t.Errorf("allowed account: got %d, want %d", code, http.StatusNoContent)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"novastream/handlers"
+	"novastream/models"
 	"novastream/services/accounts"
 	"novastream/services/sessions"
 	"novastream/services/users"
@@ -70,7 +71,8 @@ func corsMiddleware(next http.Handler) http.Handler {
 			w.Header().Set("Access-Control-Allow-Origin", origin)
 			w.Header().Set("Vary", "Origin")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, PATCH, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, Accept, X-PIN, X-Client-ID, Cache-Control, Pragma")
+			w.Header().Set("Access-Control-Allow-Headers", "Authorization, Content-Type, Accept, X-PIN, X-Parental-PIN, X-Client-ID, Cache-Control, Pragma, If-None-Match")
+			w.Header().Set("Access-Control-Expose-Headers", "ETag")
 		}
 
 		// Handle preflight requests
@@ -123,6 +125,10 @@ func Register(
 	sessionsSvc *sessions.Service,
 	usersSvc *users.Service,
 	shareHandler *handlers.ShareHandler,
+	listSharesHandler *handlers.ListSharesHandler,
+	savedFiltersHandler *handlers.SavedFiltersHandler,
+	watchTimeHandler *handlers.WatchTimeHandler,
+	playbackGuard *handlers.PlaybackGuard,
 	homepageAPIKey string,
 ) {
 	api := r.PathPrefix("/api").Subrouter()
@@ -166,6 +172,17 @@ func Register(
 		api.Handle("/video/internal-stream", localhostOnlyMiddleware(http.HandlerFunc(videoHandler.StreamVideo))).Methods(http.MethodGet, http.MethodHead, http.MethodOptions)
 	}
 
+	// Subscribable calendar feeds authenticate with a per-profile feed token
+	// rather than a session, so they sit outside the protected routes.
+	if calendarHandler != nil {
+		api.HandleFunc("/users/{userID}/calendar.ics", calendarHandler.GetCalendarICS).Methods(http.MethodGet)
+		api.HandleFunc("/users/{userID}/calendar.ics", calendarHandler.Options).Methods(http.MethodOptions)
+		api.HandleFunc("/users/{userID}/releases.rss", calendarHandler.GetReleaseFeed).Methods(http.MethodGet)
+		api.HandleFunc("/users/{userID}/releases.rss", calendarHandler.Options).Methods(http.MethodOptions)
+		api.HandleFunc("/users/{userID}/releases.json", calendarHandler.GetReleaseFeed).Methods(http.MethodGet)
+		api.HandleFunc("/users/{userID}/releases.json", calendarHandler.Options).Methods(http.MethodOptions)
+	}
+
 	// Protected routes - require authentication
 	protected := api.PathPrefix("").Subrouter()
 	protected.Use(AccountAuthMiddleware(sessionsSvc, accountsSvc))
@@ -208,6 +225,8 @@ func Register(
 	masterOnly.HandleFunc("/{accountID}/password", accountsHandler.Options).Methods(http.MethodOptions)
 	masterOnly.HandleFunc("/{accountID}/max-streams", accountsHandler.SetMaxStreams).Methods(http.MethodPut)
 	masterOnly.HandleFunc("/{accountID}/max-streams", accountsHandler.Options).Methods(http.MethodOptions)
+	masterOnly.HandleFunc("/{accountID}/features", accountsHandler.SetFeatures).Methods(http.MethodPut)
+	masterOnly.HandleFunc("/{accountID}/features", accountsHandler.Options).Methods(http.MethodOptions)
 
 	// Profile reassignment (master only)
 	masterOnly2 := protected.PathPrefix("/profiles").Subrouter()
@@ -228,6 +247,8 @@ func Register(
 	settingsWriteRouter.HandleFunc("", settingsHandler.PutSettings).Methods(http.MethodPut)
 	settingsWriteRouter.HandleFunc("/cache/clear", settingsHandler.ClearMetadataCache).Methods(http.MethodPost)
 	settingsWriteRouter.HandleFunc("/cache/clear", handleOptions).Methods(http.MethodOptions)
+	settingsWriteRouter.HandleFunc("/cache/stats", settingsHandler.MetadataCacheStats).Methods(http.MethodGet)
+	settingsWriteRouter.HandleFunc("/cache/stats", handleOptions).Methods(http.MethodOptions)
 	settingsWriteRouter.HandleFunc("/branding/{slot}/image", settingsHandler.GetBrandingImageStatus).Methods(http.MethodGet)
 	settingsWriteRouter.HandleFunc("/branding/{slot}/image", settingsHandler.UploadBrandingImage).Methods(http.MethodPost)
 	settingsWriteRouter.HandleFunc("/branding/{slot}/image", settingsHandler.DeleteBrandingImage).Methods(http.MethodDelete)
@@ -236,10 +257,14 @@ func Register(
 	// Content discovery and metadata (all authenticated users)
 	protected.HandleFunc("/discover/new", metadataHandler.DiscoverNew).Methods(http.MethodGet)
 	protected.HandleFunc("/discover/new", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/discover/trending-sources", metadataHandler.TrendingSources).Methods(http.MethodGet)
+	protected.HandleFunc("/discover/trending-sources", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/lists/custom", metadataHandler.CustomList).Methods(http.MethodGet)
 	protected.HandleFunc("/lists/custom", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/lists/trakt", metadataHandler.TraktList).Methods(http.MethodGet)
 	protected.HandleFunc("/lists/trakt", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/lists/trakt/recommendations/hide", metadataHandler.HideTraktRecommendation).Methods(http.MethodPost)
+	protected.HandleFunc("/lists/trakt/recommendations/hide", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/lists/simkl", metadataHandler.SimklList).Methods(http.MethodGet)
 	protected.HandleFunc("/lists/simkl", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/lists/letterboxd", metadataHandler.LetterboxdList).Methods(http.MethodGet)
@@ -250,23 +275,41 @@ func Register(
 	protected.HandleFunc("/lists/curated", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/discover/genre", metadataHandler.DiscoverByGenre).Methods(http.MethodGet)
 	protected.HandleFunc("/discover/genre", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/discover/genre/hub", metadataHandler.GenreHub).Methods(http.MethodGet)
+	protected.HandleFunc("/discover/genre/hub", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/discover/decade", metadataHandler.DiscoverByDecade).Methods(http.MethodGet)
 	protected.HandleFunc("/discover/decade", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/discover/advanced", metadataHandler.DiscoverAdvanced).Methods(http.MethodGet)
+	protected.HandleFunc("/discover/advanced", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/discover/moods", metadataHandler.DiscoverMoods).Methods(http.MethodGet)
+	protected.HandleFunc("/discover/moods", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/discover/mood", metadataHandler.DiscoverByMood).Methods(http.MethodGet)
+	protected.HandleFunc("/discover/mood", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/discover/keywords", metadataHandler.SearchKeywords).Methods(http.MethodGet)
+	protected.HandleFunc("/discover/keywords", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/discover/top-ten", metadataHandler.TopTen).Methods(http.MethodGet)
 	protected.HandleFunc("/discover/top-ten", handleOptions).Methods(http.MethodOptions)
-	protected.HandleFunc("/recommendations", metadataHandler.GetAIRecommendations).Methods(http.MethodGet)
+	protected.HandleFunc("/recommendations", FeatureHandlerFunc(accountsSvc, models.FeatureAIRecommendations, metadataHandler.GetAIRecommendations)).Methods(http.MethodGet)
 	protected.HandleFunc("/recommendations", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/recommendations/personalized", metadataHandler.GetPersonalizedRecommendations).Methods(http.MethodGet)
 	protected.HandleFunc("/recommendations/personalized", handleOptions).Methods(http.MethodOptions)
-	protected.HandleFunc("/recommendations/similar", metadataHandler.GetAISimilar).Methods(http.MethodGet)
+	protected.HandleFunc("/recommendations/collections", metadataHandler.GetCompleteCollections).Methods(http.MethodGet)
+	protected.HandleFunc("/recommendations/collections", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/recommendations/similar", FeatureHandlerFunc(accountsSvc, models.FeatureAIRecommendations, metadataHandler.GetAISimilar)).Methods(http.MethodGet)
 	protected.HandleFunc("/recommendations/similar", handleOptions).Methods(http.MethodOptions)
-	protected.HandleFunc("/recommendations/custom", metadataHandler.GetAICustomRecommendations).Methods(http.MethodGet)
+	protected.HandleFunc("/recommendations/custom", FeatureHandlerFunc(accountsSvc, models.FeatureAIRecommendations, metadataHandler.GetAICustomRecommendations)).Methods(http.MethodGet)
 	protected.HandleFunc("/recommendations/custom", handleOptions).Methods(http.MethodOptions)
-	protected.HandleFunc("/recommendations/surprise", metadataHandler.GetAISurprise).Methods(http.MethodGet)
+	protected.HandleFunc("/recommendations/surprise", FeatureHandlerFunc(accountsSvc, models.FeatureAIRecommendations, metadataHandler.GetAISurprise)).Methods(http.MethodGet)
 	protected.HandleFunc("/recommendations/surprise", handleOptions).Methods(http.MethodOptions)
 
 	protected.HandleFunc("/search", metadataHandler.Search).Methods(http.MethodGet)
 	protected.HandleFunc("/search", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/search/local", metadataHandler.SearchLocal).Methods(http.MethodGet)
+	protected.HandleFunc("/search/local", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/search/multi", metadataHandler.MultiSearch).Methods(http.MethodGet)
+	protected.HandleFunc("/search/multi", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/search/smart", FeatureHandlerFunc(accountsSvc, models.FeatureAIRecommendations, metadataHandler.SmartSearch)).Methods(http.MethodGet)
+	protected.HandleFunc("/search/smart", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/youtube/search", metadataHandler.SearchYouTubeVideos).Methods(http.MethodGet)
 	protected.HandleFunc("/youtube/search", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/youtube/hls/start", videoHandler.StartYouTubeHLSSession).Methods(http.MethodGet)
@@ -276,10 +319,16 @@ func Register(
 
 	protected.HandleFunc("/metadata/series/details", metadataHandler.SeriesDetails).Methods(http.MethodGet)
 	protected.HandleFunc("/metadata/series/details", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/metadata/series/next-episode", metadataHandler.SeriesNextEpisode).Methods(http.MethodGet)
+	protected.HandleFunc("/metadata/series/next-episode", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/metadata/series/season", metadataHandler.SeasonDetails).Methods(http.MethodGet)
+	protected.HandleFunc("/metadata/series/season", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/metadata/series/batch", metadataHandler.BatchSeriesDetails).Methods(http.MethodPost)
 	protected.HandleFunc("/metadata/series/batch", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/metadata/movies/details", metadataHandler.MovieDetails).Methods(http.MethodGet)
 	protected.HandleFunc("/metadata/movies/details", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/metadata/request", metadataHandler.RequestTitle).Methods(http.MethodPost)
+	protected.HandleFunc("/metadata/request", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/metadata/movies/releases", metadataHandler.BatchMovieReleases).Methods(http.MethodPost)
 	protected.HandleFunc("/metadata/movies/releases", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/metadata/collection", metadataHandler.CollectionDetails).Methods(http.MethodGet)
@@ -300,24 +349,46 @@ func Register(
 	protected.HandleFunc("/metadata/trailers/prequeue/status", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/metadata/trailers/prequeue/serve", metadataHandler.TrailerPrequeueServe).Methods(http.MethodGet)
 	protected.HandleFunc("/metadata/trailers/prequeue/serve", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/metadata/trailers/previews", metadataHandler.TrailerPreviews).Methods(http.MethodGet)
+	protected.HandleFunc("/metadata/trailers/previews", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/metadata/trailers/previews/serve", metadataHandler.TrailerPreviewServe).Methods(http.MethodGet)
+	protected.HandleFunc("/metadata/trailers/previews/serve", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/metadata/progress", metadataHandler.GetProgress).Methods(http.MethodGet)
 	protected.HandleFunc("/metadata/progress", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/metadata/progress/stream", metadataHandler.StreamProgress).Methods(http.MethodGet)
+	protected.HandleFunc("/metadata/progress/stream", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/metadata/progress/cancel", metadataHandler.CancelProgress).Methods(http.MethodPost)
+	protected.HandleFunc("/metadata/progress/cancel", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/metadata/artwork/refresh", metadataHandler.RefreshArtwork).Methods(http.MethodPost)
+	protected.HandleFunc("/metadata/artwork/refresh", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/metadata/identity-overrides", metadataHandler.ListIdentityOverrides).Methods(http.MethodGet)
+	protected.HandleFunc("/metadata/identity-overrides", metadataHandler.SetIdentityOverride).Methods(http.MethodPost)
+	protected.HandleFunc("/metadata/identity-overrides", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/metadata/identity-overrides/{titleID}", metadataHandler.RemoveIdentityOverride).Methods(http.MethodDelete)
+	protected.HandleFunc("/metadata/identity-overrides/{titleID}", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/metadata/title-edits", metadataHandler.ListTitleEdits).Methods(http.MethodGet)
+	protected.HandleFunc("/metadata/title-edits", metadataHandler.SetTitleEdits).Methods(http.MethodPut)
+	protected.HandleFunc("/metadata/title-edits", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/metadata/title-edits/lock", metadataHandler.LockTitleFields).Methods(http.MethodPost)
+	protected.HandleFunc("/metadata/title-edits/lock", handleOptions).Methods(http.MethodOptions)
+	protected.HandleFunc("/metadata/title-edits/{titleID}", metadataHandler.RemoveTitleEdits).Methods(http.MethodDelete)
+	protected.HandleFunc("/metadata/title-edits/{titleID}", handleOptions).Methods(http.MethodOptions)
 
 	protected.HandleFunc("/indexers/search", indexerHandler.Search).Methods(http.MethodGet)
 	protected.HandleFunc("/indexers/search", indexerHandler.Options).Methods(http.MethodOptions)
 	protected.HandleFunc("/indexers/search-test", indexerHandler.SearchTest).Methods(http.MethodGet)
 	protected.HandleFunc("/indexers/search-test", indexerHandler.Options).Methods(http.MethodOptions)
 
-	protected.HandleFunc("/playback/resolve", playbackHandler.Resolve).Methods(http.MethodPost)
+	protected.HandleFunc("/playback/resolve", playbackGuard.Wrap(playbackHandler.Resolve)).Methods(http.MethodPost)
 	protected.HandleFunc("/playback/resolve", handleOptions).Methods(http.MethodOptions)
-	protected.HandleFunc("/playback/resolve-batch", playbackHandler.ResolveBatch).Methods(http.MethodPost)
+	protected.HandleFunc("/playback/resolve-batch", playbackGuard.Wrap(playbackHandler.ResolveBatch)).Methods(http.MethodPost)
 	protected.HandleFunc("/playback/resolve-batch", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/playback/queue/{queueID}", playbackHandler.QueueStatus).Methods(http.MethodGet)
 	protected.HandleFunc("/playback/queue/{queueID}", handleOptions).Methods(http.MethodOptions)
 
 	// Prequeue endpoints for pre-loading playback streams
 	if prequeueHandler != nil {
-		protected.HandleFunc("/playback/prequeue", prequeueHandler.Prequeue).Methods(http.MethodPost)
+		protected.HandleFunc("/playback/prequeue", playbackGuard.Wrap(prequeueHandler.Prequeue)).Methods(http.MethodPost)
 		protected.HandleFunc("/playback/prequeue", prequeueHandler.Options).Methods(http.MethodOptions)
 		protected.HandleFunc("/playback/prequeue/{prequeueID}", prequeueHandler.GetStatus).Methods(http.MethodGet)
 		protected.HandleFunc("/playback/prequeue/{prequeueID}", prequeueHandler.Options).Methods(http.MethodOptions)
@@ -345,9 +416,9 @@ func Register(
 	protected.HandleFunc("/live/stremio/streams", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/live/categories", liveHandler.GetCategories).Methods(http.MethodGet)
 	protected.HandleFunc("/live/categories", handleOptions).Methods(http.MethodOptions)
-	protected.HandleFunc("/live/cache/clear", liveHandler.ClearCache).Methods(http.MethodPost)
+	protected.HandleFunc("/live/cache/clear", FeatureHandlerFunc(accountsSvc, models.FeatureLiveTVManagement, liveHandler.ClearCache)).Methods(http.MethodPost)
 	protected.HandleFunc("/live/cache/clear", handleOptions).Methods(http.MethodOptions)
-	protected.HandleFunc("/live/stream", liveHandler.StreamChannel).Methods(http.MethodGet, http.MethodHead)
+	protected.HandleFunc("/live/stream", playbackGuard.Wrap(liveHandler.StreamChannel)).Methods(http.MethodGet, http.MethodHead)
 	protected.HandleFunc("/live/stream", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/library/libraries", localMediaHandler.ListLibraries).Methods(http.MethodGet)
 	protected.HandleFunc("/library/libraries", handleOptions).Methods(http.MethodOptions)
@@ -357,22 +428,30 @@ func Register(
 	protected.HandleFunc("/library/matches", handleOptions).Methods(http.MethodOptions)
 	protected.HandleFunc("/library/items/{itemID}/playback", localMediaHandler.GetPlayback).Methods(http.MethodGet)
 	protected.HandleFunc("/library/items/{itemID}/playback", handleOptions).Methods(http.MethodOptions)
-	protected.HandleFunc("/live/hls/start", RateLimitHandlerFunc(hlsStartLimiter, videoHandler.StartLiveHLSSession)).Methods(http.MethodGet, http.MethodOptions)
+	protected.HandleFunc("/live/hls/start", RateLimitHandlerFunc(hlsStartLimiter, playbackGuard.Wrap(videoHandler.StartLiveHLSSession))).Methods(http.MethodGet, http.MethodOptions)
 	protected.HandleFunc("/live/usage", videoHandler.GetLiveUsage).Methods(http.MethodGet)
 	protected.HandleFunc("/live/usage", handleOptions).Methods(http.MethodOptions)
 	if recordingsHandler != nil {
 		protected.HandleFunc("/live/recordings", recordingsHandler.List).Methods(http.MethodGet)
 		protected.HandleFunc("/live/recordings", recordingsHandler.Options).Methods(http.MethodOptions)
-		protected.HandleFunc("/live/recordings/epg", recordingsHandler.CreateEPG).Methods(http.MethodPost)
+		protected.HandleFunc("/live/recordings/epg", FeatureHandlerFunc(accountsSvc, models.FeatureLiveTVManagement, recordingsHandler.CreateEPG)).Methods(http.MethodPost)
 		protected.HandleFunc("/live/recordings/epg", recordingsHandler.Options).Methods(http.MethodOptions)
-		protected.HandleFunc("/live/recordings/time-block", recordingsHandler.CreateTimeBlock).Methods(http.MethodPost)
+		protected.HandleFunc("/live/recordings/time-block", FeatureHandlerFunc(accountsSvc, models.FeatureLiveTVManagement, recordingsHandler.CreateTimeBlock)).Methods(http.MethodPost)
 		protected.HandleFunc("/live/recordings/time-block", recordingsHandler.Options).Methods(http.MethodOptions)
+		protected.HandleFunc("/live/recordings/rules", recordingsHandler.ListRules).Methods(http.MethodGet)
+		protected.HandleFunc("/live/recordings/rules", FeatureHandlerFunc(accountsSvc, models.FeatureLiveTVManagement, recordingsHandler.CreateRule)).Methods(http.MethodPost)
+		protected.HandleFunc("/live/recordings/rules", recordingsHandler.Options).Methods(http.MethodOptions)
+		protected.HandleFunc("/live/recordings/rules/evaluate", FeatureHandlerFunc(accountsSvc, models.FeatureLiveTVManagement, recordingsHandler.EvaluateRules)).Methods(http.MethodPost)
+		protected.HandleFunc("/live/recordings/rules/evaluate", recordingsHandler.Options).Methods(http.MethodOptions)
+		protected.HandleFunc("/live/recordings/rules/{ruleID}", FeatureHandlerFunc(accountsSvc, models.FeatureLiveTVManagement, recordingsHandler.UpdateRule)).Methods(http.MethodPut)
+		protected.HandleFunc("/live/recordings/rules/{ruleID}", FeatureHandlerFunc(accountsSvc, models.FeatureLiveTVManagement, recordingsHandler.DeleteRule)).Methods(http.MethodDelete)
+		protected.HandleFunc("/live/recordings/rules/{ruleID}", recordingsHandler.Options).Methods(http.MethodOptions)
 		protected.HandleFunc("/live/recordings/{recordingID}", recordingsHandler.Get).Methods(http.MethodGet)
 		protected.HandleFunc("/live/recordings/{recordingID}", recordingsHandler.Options).Methods(http.MethodOptions)
-		protected.HandleFunc("/live/recordings/{recordingID}", recordingsHandler.Delete).Methods(http.MethodDelete)
+		protected.HandleFunc("/live/recordings/{recordingID}", FeatureHandlerFunc(accountsSvc, models.FeatureLiveTVManagement, recordingsHandler.Delete)).Methods(http.MethodDelete)
 		protected.HandleFunc("/live/recordings/{recordingID}/stream", recordingsHandler.Stream).Methods(http.MethodGet)
 		protected.HandleFunc("/live/recordings/{recordingID}/stream", recordingsHandler.Options).Methods(http.MethodOptions)
-		protected.HandleFunc("/live/recordings/{recordingID}/cancel", recordingsHandler.Cancel).Methods(http.MethodPost)
+		protected.HandleFunc("/live/recordings/{recordingID}/cancel", FeatureHandlerFunc(accountsSvc, models.FeatureLiveTVManagement, recordingsHandler.Cancel)).Methods(http.MethodPost)
 		protected.HandleFunc("/live/recordings/{recordingID}/cancel", recordingsHandler.Options).Methods(http.MethodOptions)
 	}
 
@@ -380,6 +459,10 @@ func Register(
 	if epgHandler != nil {
 		protected.HandleFunc("/live/epg/now", epgHandler.GetNowPlaying).Methods(http.MethodGet)
 		protected.HandleFunc("/live/epg/now", epgHandler.Options).Methods(http.MethodOptions)
+		protected.HandleFunc("/live/epg/now-next", epgHandler.GetNowNext).Methods(http.MethodGet)
+		protected.HandleFunc("/live/epg/now-next", epgHandler.Options).Methods(http.MethodOptions)
+		protected.HandleFunc("/live/epg/search", epgHandler.Search).Methods(http.MethodGet)
+		protected.HandleFunc("/live/epg/search", epgHandler.Options).Methods(http.MethodOptions)
 		protected.HandleFunc("/live/epg/schedule", epgHandler.GetSchedule).Methods(http.MethodGet)
 		protected.HandleFunc("/live/epg/schedule", epgHandler.Options).Methods(http.MethodOptions)
 		protected.HandleFunc("/live/epg/schedule/batch", epgHandler.GetScheduleMultiple).Methods(http.MethodGet)
@@ -388,7 +471,7 @@ func Register(
 		protected.HandleFunc("/live/epg/channel/{id}", epgHandler.Options).Methods(http.MethodOptions)
 		protected.HandleFunc("/live/epg/status", epgHandler.GetStatus).Methods(http.MethodGet)
 		protected.HandleFunc("/live/epg/status", epgHandler.Options).Methods(http.MethodOptions)
-		protected.HandleFunc("/live/epg/refresh", epgHandler.Refresh).Methods(http.MethodPost)
+		protected.HandleFunc("/live/epg/refresh", FeatureHandlerFunc(accountsSvc, models.FeatureLiveTVManagement, epgHandler.Refresh)).Methods(http.MethodPost)
 		protected.HandleFunc("/live/epg/refresh", epgHandler.Options).Methods(http.MethodOptions)
 	}
 
@@ -402,9 +485,10 @@ func Register(
 	}
 
 	// Video streaming endpoints
-	protected.HandleFunc("/video/stream", videoHandler.StreamVideo).Methods(http.MethodGet, http.MethodHead, http.MethodOptions)
-	protected.HandleFunc("/video/stream/{displayName}", videoHandler.StreamVideo).Methods(http.MethodGet, http.MethodHead, http.MethodOptions)
+	protected.HandleFunc("/video/stream", playbackGuard.Wrap(videoHandler.StreamVideo)).Methods(http.MethodGet, http.MethodHead, http.MethodOptions)
+	protected.HandleFunc("/video/stream/{displayName}", playbackGuard.Wrap(videoHandler.StreamVideo)).Methods(http.MethodGet, http.MethodHead, http.MethodOptions)
 	protected.HandleFunc("/video/metadata", RateLimitHandlerFunc(probeLimiter, videoHandler.ProbeVideo)).Methods(http.MethodGet, http.MethodOptions)
+	protected.HandleFunc("/video/negotiate", RateLimitHandlerFunc(probeLimiter, videoHandler.NegotiatePlayback)).Methods(http.MethodPost, http.MethodOptions)
 	protected.HandleFunc("/video/direct-url", videoHandler.GetDirectURL).Methods(http.MethodGet, http.MethodOptions)
 	protected.HandleFunc("/video/cropdetect", RateLimitHandlerFunc(probeLimiter, videoHandler.CropDetect)).Methods(http.MethodGet, http.MethodOptions)
 	protected.HandleFunc("/video/thumbnails/start", RateLimitHandlerFunc(probeLimiter, videoHandler.StartThumbnails)).Methods(http.MethodPost, http.MethodOptions)
@@ -414,7 +498,7 @@ func Register(
 	protected.HandleFunc("/video/credits/status", videoHandler.GetCreditsStatus).Methods(http.MethodGet, http.MethodOptions)
 
 	// HLS streaming endpoints for Dolby Vision
-	protected.HandleFunc("/video/hls/start", RateLimitHandlerFunc(hlsStartLimiter, videoHandler.StartHLSSession)).Methods(http.MethodGet, http.MethodOptions)
+	protected.HandleFunc("/video/hls/start", RateLimitHandlerFunc(hlsStartLimiter, playbackGuard.Wrap(videoHandler.StartHLSSession))).Methods(http.MethodGet, http.MethodOptions)
 	protected.HandleFunc("/video/hls/{sessionID}/master.m3u8", videoHandler.ServeHLSMasterPlaylist).Methods(http.MethodGet, http.MethodOptions)
 	protected.HandleFunc("/video/hls/{sessionID}/stream.m3u8", videoHandler.ServeHLSPlaylist).Methods(http.MethodGet, http.MethodOptions)
 	protected.HandleFunc("/video/hls/{sessionID}/subtitle-{track}.m3u8", videoHandler.ServeHLSSubtitlePlaylist).Methods(http.MethodGet, http.MethodOptions)
@@ -443,6 +527,8 @@ func Register(
 	protected.HandleFunc("/subtitles/translate", subtitlesHandler.Options).Methods(http.MethodOptions)
 
 	protected.HandleFunc("/debug/log", debugHandler.Capture).Methods(http.MethodPost, http.MethodOptions)
+	protected.HandleFunc("/debug/title/{titleID}", metadataHandler.DebugTitle).Methods(http.MethodGet)
+	protected.HandleFunc("/debug/title/{titleID}", handleOptions).Methods(http.MethodOptions)
 
 	// Log submission endpoint
 	protected.HandleFunc("/logs/submit", logsHandler.Submit).Methods(http.MethodPost)
@@ -597,10 +683,17 @@ func Register(
 	profileProtected.HandleFunc("/{userID}/kids/lists", usersHandler.AddKidsAllowedList).Methods(http.MethodPost)
 	profileProtected.HandleFunc("/{userID}/kids/lists", usersHandler.RemoveKidsAllowedList).Methods(http.MethodDelete)
 	profileProtected.HandleFunc("/{userID}/kids/lists", usersHandler.Options).Methods(http.MethodOptions)
+	profileProtected.HandleFunc("/{userID}/kids/genres", usersHandler.SetKidsAllowedGenres).Methods(http.MethodPut)
+	profileProtected.HandleFunc("/{userID}/kids/genres", usersHandler.Options).Methods(http.MethodOptions)
 
 	profileProtected.HandleFunc("/{userID}/settings", userSettingsHandler.GetSettings).Methods(http.MethodGet)
 	profileProtected.HandleFunc("/{userID}/settings", userSettingsHandler.PutSettings).Methods(http.MethodPut)
 	profileProtected.HandleFunc("/{userID}/settings", userSettingsHandler.Options).Methods(http.MethodOptions)
+	profileProtected.HandleFunc("/{userID}/locale", userSettingsHandler.GetLocale).Methods(http.MethodGet)
+	profileProtected.HandleFunc("/{userID}/locale", userSettingsHandler.Options).Methods(http.MethodOptions)
+	profileProtected.HandleFunc("/{userID}/live/lineup", userSettingsHandler.GetLiveLineup).Methods(http.MethodGet)
+	profileProtected.HandleFunc("/{userID}/live/lineup", userSettingsHandler.PutLiveLineup).Methods(http.MethodPut)
+	profileProtected.HandleFunc("/{userID}/live/lineup", userSettingsHandler.Options).Methods(http.MethodOptions)
 
 	// Client device management routes
 	if clientsHandler != nil {
@@ -649,8 +742,40 @@ func Register(
 	profileProtected.HandleFunc("/{userID}/custom-lists/{listID}/items/{mediaType}/{id}", customListsHandler.RemoveItem).Methods(http.MethodDelete)
 	profileProtected.HandleFunc("/{userID}/custom-lists/{listID}/items/{mediaType}/{id}", customListsHandler.Options).Methods(http.MethodOptions)
 
+	// Public read-only list links (viewers open /shared/lists/{token})
+	if listSharesHandler != nil {
+		profileProtected.HandleFunc("/{userID}/shares", listSharesHandler.List).Methods(http.MethodGet)
+		profileProtected.HandleFunc("/{userID}/shares", listSharesHandler.Create).Methods(http.MethodPost)
+		profileProtected.HandleFunc("/{userID}/shares", listSharesHandler.Options).Methods(http.MethodOptions)
+		profileProtected.HandleFunc("/{userID}/shares/{shareID}", listSharesHandler.Revoke).Methods(http.MethodDelete)
+		profileProtected.HandleFunc("/{userID}/shares/{shareID}", listSharesHandler.Options).Methods(http.MethodOptions)
+	}
+
+	// Saved discover filters (browse entries and "saved-filter" shelves)
+	if savedFiltersHandler != nil {
+		profileProtected.HandleFunc("/{userID}/saved-filters", savedFiltersHandler.List).Methods(http.MethodGet)
+		profileProtected.HandleFunc("/{userID}/saved-filters", savedFiltersHandler.Create).Methods(http.MethodPost)
+		profileProtected.HandleFunc("/{userID}/saved-filters", savedFiltersHandler.Options).Methods(http.MethodOptions)
+		profileProtected.HandleFunc("/{userID}/saved-filters/{filterID}", savedFiltersHandler.Update).Methods(http.MethodPut)
+		profileProtected.HandleFunc("/{userID}/saved-filters/{filterID}", savedFiltersHandler.Delete).Methods(http.MethodDelete)
+		profileProtected.HandleFunc("/{userID}/saved-filters/{filterID}", savedFiltersHandler.Options).Methods(http.MethodOptions)
+		profileProtected.HandleFunc("/{userID}/saved-filters/{filterID}/items", savedFiltersHandler.Items).Methods(http.MethodGet)
+		profileProtected.HandleFunc("/{userID}/saved-filters/{filterID}/items", savedFiltersHandler.Options).Methods(http.MethodOptions)
+	}
+
+	// Weekly watch time and goals
+	if watchTimeHandler != nil {
+		profileProtected.HandleFunc("/{userID}/watch-time", watchTimeHandler.Get).Methods(http.MethodGet)
+		profileProtected.HandleFunc("/{userID}/watch-time", watchTimeHandler.Options).Methods(http.MethodOptions)
+		profileProtected.HandleFunc("/{userID}/watch-time/goal", watchTimeHandler.SetGoal).Methods(http.MethodPut)
+		profileProtected.HandleFunc("/{userID}/watch-time/goal", watchTimeHandler.DeleteGoal).Methods(http.MethodDelete)
+		profileProtected.HandleFunc("/{userID}/watch-time/goal", watchTimeHandler.Options).Methods(http.MethodOptions)
+	}
+
 	profileProtected.HandleFunc("/{userID}/history/continue", historyHandler.ListContinueWatching).Methods(http.MethodGet)
 	profileProtected.HandleFunc("/{userID}/history/continue", historyHandler.Options).Methods(http.MethodOptions)
+	profileProtected.HandleFunc("/{userID}/history/continue/shelf", historyHandler.ListContinueWatchingShelf).Methods(http.MethodGet)
+	profileProtected.HandleFunc("/{userID}/history/continue/shelf", historyHandler.Options).Methods(http.MethodOptions)
 	profileProtected.HandleFunc("/{userID}/history/continue/revision", historyHandler.GetContinueWatchingRevision).Methods(http.MethodGet)
 	profileProtected.HandleFunc("/{userID}/history/continue/revision", historyHandler.Options).Methods(http.MethodOptions)
 	profileProtected.HandleFunc("/{userID}/history/continue/hide", historyHandler.HideFromContinueWatchingByBody).Methods(http.MethodPost)
@@ -668,6 +793,8 @@ func Register(
 	profileProtected.HandleFunc("/{userID}/history/watched", historyHandler.Options).Methods(http.MethodOptions)
 	profileProtected.HandleFunc("/{userID}/history/watched/bulk", historyHandler.BulkUpdateWatchHistory).Methods(http.MethodPost)
 	profileProtected.HandleFunc("/{userID}/history/watched/bulk", historyHandler.Options).Methods(http.MethodOptions)
+	profileProtected.HandleFunc("/{userID}/history/watched/through", historyHandler.MarkWatchedThrough).Methods(http.MethodPost)
+	profileProtected.HandleFunc("/{userID}/history/watched/through", historyHandler.Options).Methods(http.MethodOptions)
 	// Body-based delete: legacy rows keyed by URLs/file paths cannot be addressed
 	// via {mediaType}/{id} path params (encoded slashes get normalised away).
 	profileProtected.HandleFunc("/{userID}/history/watched/delete", historyHandler.DeleteWatchHistoryItemByBody).Methods(http.MethodPost)
@@ -719,6 +846,9 @@ func Register(
 	if calendarHandler != nil {
 		profileProtected.HandleFunc("/{userID}/calendar", calendarHandler.GetCalendar).Methods(http.MethodGet)
 		profileProtected.HandleFunc("/{userID}/calendar", calendarHandler.Options).Methods(http.MethodOptions)
+		profileProtected.HandleFunc("/{userID}/calendar/feed-token", calendarHandler.CreateFeedToken).Methods(http.MethodPost)
+		profileProtected.HandleFunc("/{userID}/calendar/feed-token", calendarHandler.RevokeFeedToken).Methods(http.MethodDelete)
+		profileProtected.HandleFunc("/{userID}/calendar/feed-token", calendarHandler.Options).Methods(http.MethodOptions)
 	}
 }
 
@@ -747,3 +877,21 @@ func RegisterTraktRoutes(r *mux.Router, traktHandler *handlers.TraktAccountsHand
 	api.HandleFunc("/accounts/{accountID}/history", traktHandler.GetHistory).Methods(http.MethodGet)
 	api.HandleFunc("/accounts/{accountID}/history", handleOptions).Methods(http.MethodOptions)
 }
+
+// RegisterWatchPartyRoutes registers the synchronized playback session API.
+// The WebSocket endpoint takes the session token as ?token= since browsers
+// cannot set headers on a WebSocket upgrade.
+func RegisterWatchPartyRoutes(r *mux.Router, watchPartyHandler *handlers.WatchPartyHandler, sessionsSvc *sessions.Service, accountsSvc *accounts.Service) {
+	api := r.PathPrefix("/api/watch-parties").Subrouter()
+	api.Use(corsMiddleware)
+	api.Use(AccountAuthMiddleware(sessionsSvc, accountsSvc))
+
+	api.HandleFunc("", watchPartyHandler.Create).Methods(http.MethodPost)
+	api.HandleFunc("", watchPartyHandler.Options).Methods(http.MethodOptions)
+	api.HandleFunc("/{partyID}", watchPartyHandler.Get).Methods(http.MethodGet)
+	api.HandleFunc("/{partyID}", watchPartyHandler.End).Methods(http.MethodDelete)
+	api.HandleFunc("/{partyID}", watchPartyHandler.Options).Methods(http.MethodOptions)
+	api.HandleFunc("/{partyID}/control", watchPartyHandler.Control).Methods(http.MethodPost)
+	api.HandleFunc("/{partyID}/control", watchPartyHandler.Options).Methods(http.MethodOptions)
+	api.HandleFunc("/{partyID}/ws", watchPartyHandler.Connect).Methods(http.MethodGet)
+}
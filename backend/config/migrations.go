@@ -1,6 +1,153 @@
 package config
 
-import "log"
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// CurrentSettingsVersion is the schema version Save stamps on settings files.
+// Bump it together with a new step at the end of settingsMigrations.
+const CurrentSettingsVersion = 1
+
+// settingsMigration upgrades a raw settings map from version-1 to version.
+type settingsMigration struct {
+	version     int
+	description string
+	apply       func(raw map[string]interface{}) error
+}
+
+// settingsMigrations are the versioned upgrade steps, oldest first. Unlike
+// MigrateRawSettings, which runs on every load, each step runs once per file:
+// afterwards an explicit value, including a zero that turns a feature off, is
+// the admin's choice and is left alone. Files are backed up before the first
+// step runs.
+var settingsMigrations = []settingsMigration{
+	{version: 1, description: "backfill trailer prequeue limits and the maintenance window", apply: migrateV1BackfillZeroDefaults},
+}
+
+// settingsFileVersion reads the schema version of a raw settings map. Files
+// written before versioning have none and are version 0.
+func settingsFileVersion(raw map[string]interface{}) int {
+	version, _ := raw["schemaVersion"].(float64)
+	return int(version)
+}
+
+// migrateSettingsVersions applies the steps newer than the raw map's version
+// in order and stamps it with the version reached. A file from a newer build
+// is left as is.
+func migrateSettingsVersions(raw map[string]interface{}, migrations []settingsMigration) (from, to int, err error) {
+	from = settingsFileVersion(raw)
+	to = from
+	for _, step := range migrations {
+		if step.version <= to {
+			continue
+		}
+		if err := step.apply(raw); err != nil {
+			return from, to, fmt.Errorf("settings migration to v%d (%s): %w", step.version, step.description, err)
+		}
+		log.Printf("[config] migrated settings v%d → v%d: %s", to, step.version, step.description)
+		to = step.version
+	}
+	if to > from {
+		raw["schemaVersion"] = float64(to)
+	}
+	return from, to, nil
+}
+
+// persistSettingsUpgrade writes settings migrated from version from back to
+// disk, after copying the original file next to it as
+// settings.json.v<from>-<timestamp>.bak. Without a backup the file is left
+// untouched and the upgrade is redone on the next load.
+func (m *Manager) persistSettingsUpgrade(from int, s Settings) error {
+	m.upgradeMu.Lock()
+	defer m.upgradeMu.Unlock()
+
+	original, err := os.ReadFile(m.path)
+	if err != nil {
+		return fmt.Errorf("read settings for backup: %w", err)
+	}
+	var onDisk map[string]interface{}
+	if err := json.Unmarshal(original, &onDisk); err == nil && settingsFileVersion(onDisk) >= CurrentSettingsVersion {
+		return nil // another load already upgraded the file
+	}
+	backup := fmt.Sprintf("%s.v%d-%s.bak", m.path, from, time.Now().UTC().Format("20060102-150405"))
+	if err := os.WriteFile(backup, original, 0o600); err != nil {
+		return fmt.Errorf("back up settings: %w", err)
+	}
+	if err := m.Save(s); err != nil {
+		return err
+	}
+	log.Printf("[config] upgraded settings file to v%d (backup: %s)", CurrentSettingsVersion, backup)
+	return nil
+}
+
+// migrateV1BackfillZeroDefaults fills in settings whose zero value used to be
+// replaced with the default on every load, which made it impossible to turn
+// them off. From v1 on a zeroed trailer prequeue quota or an empty
+// maintenance window is kept.
+func migrateV1BackfillZeroDefaults(raw map[string]interface{}) error {
+	playback, ok := raw["playback"].(map[string]interface{})
+	if !ok {
+		playback = map[string]interface{}{}
+		raw["playback"] = playback
+	}
+	prequeue, _ := playback["trailerPrequeue"].(map[string]interface{})
+	if rawSectionIsZero(prequeue) {
+		if prequeue == nil {
+			prequeue = map[string]interface{}{}
+			playback["trailerPrequeue"] = prequeue
+		}
+		prequeue["heroItems"] = float64(5)
+		prequeue["trendingRowItems"] = float64(5)
+		prequeue["maxDiskMb"] = float64(1024)
+	}
+
+	tasks, ok := raw["scheduledTasks"].(map[string]interface{})
+	if !ok {
+		tasks = map[string]interface{}{}
+		raw["scheduledTasks"] = tasks
+	}
+	window, _ := tasks["maintenanceWindow"].(map[string]interface{})
+	if window == nil {
+		window = map[string]interface{}{}
+		tasks["maintenanceWindow"] = window
+	}
+	start, _ := window["start"].(string)
+	end, _ := window["end"].(string)
+	if start == "" && end == "" {
+		window["start"] = "02:00"
+		window["end"] = "05:00"
+	}
+	return nil
+}
+
+// rawSectionIsZero reports whether every value of a decoded JSON object is
+// its zero value, as Go writes a struct nobody has set.
+func rawSectionIsZero(section map[string]interface{}) bool {
+	for _, value := range section {
+		switch v := value.(type) {
+		case nil:
+		case bool:
+			if v {
+				return false
+			}
+		case float64:
+			if v != 0 {
+				return false
+			}
+		case string:
+			if v != "" {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
 
 // MigrateRawSettings applies all known migrations to a raw settings JSON map.
 // Migrations move or rename fields between sections, ensuring backward
@@ -5,10 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"io/fs"
+	"log"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -17,38 +19,48 @@ func boolPtr(v bool) *bool        { return &v }
 
 // Settings represents the application configuration persisted to disk.
 type Settings struct {
-	Server          ServerSettings          `json:"server"`
-	Usenet          []UsenetSettings        `json:"usenet"`
-	UsenetEngines   []UsenetEngineSettings  `json:"usenetEngines,omitempty"`
-	Indexers        []IndexerConfig         `json:"indexers"`
-	TorrentScrapers []TorrentScraperConfig  `json:"torrentScrapers"`
-	Metadata        MetadataSettings        `json:"metadata"`
-	Cache           CacheSettings           `json:"cache"`
-	WebDAV          WebDAVSettings          `json:"webdav"`
-	Database        DatabaseSettings        `json:"database"`
-	Streaming       StreamingSettings       `json:"streaming"`
-	Import          ImportSettings          `json:"import"`
-	SABnzbd         SABnzbdSettings         `json:"sabnzbd"`
-	AltMount        *AltMountSettings       `json:"altmount,omitempty"`
-	Transmux        TransmuxSettings        `json:"transmux"`
-	Playback        PlaybackSettings        `json:"playback"`
-	Live            LiveSettings            `json:"live"`
-	HomeShelves     HomeShelvesSettings     `json:"homeShelves"`
-	Filtering       FilterSettings          `json:"filtering"`
-	AnimeFiltering  AnimeFilteringSettings  `json:"animeFiltering"`
-	UI              UISettings              `json:"ui"`
-	Display         DisplaySettings         `json:"display"`
-	Subtitles       SubtitleSettings        `json:"subtitles"`
-	MDBList         MDBListSettings         `json:"mdblist"`
-	Trakt           TraktSettings           `json:"trakt,omitempty"`
-	Simkl           SimklSettings           `json:"simkl,omitempty"`
-	Plex            PlexSettings            `json:"plex,omitempty"`
-	Jellyfin        JellyfinSettings        `json:"jellyfin,omitempty"`
-	Log             LogConfig               `json:"log"`
-	ScheduledTasks  ScheduledTasksSettings  `json:"scheduledTasks,omitempty"`
-	Network         NetworkSettings         `json:"network,omitempty"`
-	Ranking         RankingSettings         `json:"ranking,omitempty"`
-	BackupRetention BackupRetentionSettings `json:"backupRetention,omitempty"`
+	// SchemaVersion is the settings file format; Load upgrades older files
+	// through settingsMigrations and Save stamps CurrentSettingsVersion.
+	SchemaVersion    int                      `json:"schemaVersion"`
+	Server           ServerSettings           `json:"server"`
+	Usenet           []UsenetSettings         `json:"usenet"`
+	UsenetEngines    []UsenetEngineSettings   `json:"usenetEngines,omitempty"`
+	Indexers         []IndexerConfig          `json:"indexers"`
+	TorrentScrapers  []TorrentScraperConfig   `json:"torrentScrapers"`
+	Metadata         MetadataSettings         `json:"metadata"`
+	Cache            CacheSettings            `json:"cache"`
+	WebDAV           WebDAVSettings           `json:"webdav"`
+	Database         DatabaseSettings         `json:"database"`
+	Streaming        StreamingSettings        `json:"streaming"`
+	Import           ImportSettings           `json:"import"`
+	SABnzbd          SABnzbdSettings          `json:"sabnzbd"`
+	AltMount         *AltMountSettings        `json:"altmount,omitempty"`
+	Transmux         TransmuxSettings         `json:"transmux"`
+	Playback         PlaybackSettings         `json:"playback"`
+	Live             LiveSettings             `json:"live"`
+	HomeShelves      HomeShelvesSettings      `json:"homeShelves"`
+	Filtering        FilterSettings           `json:"filtering"`
+	AnimeFiltering   AnimeFilteringSettings   `json:"animeFiltering"`
+	UI               UISettings               `json:"ui"`
+	Display          DisplaySettings          `json:"display"`
+	Subtitles        SubtitleSettings         `json:"subtitles"`
+	MDBList          MDBListSettings          `json:"mdblist"`
+	Trakt            TraktSettings            `json:"trakt,omitempty"`
+	Simkl            SimklSettings            `json:"simkl,omitempty"`
+	Plex             PlexSettings             `json:"plex,omitempty"`
+	Jellyfin         JellyfinSettings         `json:"jellyfin,omitempty"`
+	Log              LogConfig                `json:"log"`
+	ScheduledTasks   ScheduledTasksSettings   `json:"scheduledTasks,omitempty"`
+	Network          NetworkSettings          `json:"network,omitempty"`
+	Ranking          RankingSettings          `json:"ranking,omitempty"`
+	BackupRetention  BackupRetentionSettings  `json:"backupRetention,omitempty"`
+	SMTP             SMTPSettings             `json:"smtp"`
+	ParentalControls ParentalControlsSettings `json:"parentalControls"`
+	Storage          StorageSettings          `json:"storage"`
+	Overseerr        OverseerrSettings        `json:"overseerr"`
+	// StreamingAvailability supplies streaming catalog end dates for the
+	// "Leaving soon" shelf and alerts.
+	StreamingAvailability StreamingAvailabilitySettings `json:"streamingAvailability"`
 }
 
 type ServerSettings struct {
@@ -56,6 +68,9 @@ type ServerSettings struct {
 	Port           int    `json:"port"`
 	BasePath       string `json:"basePath,omitempty"`       // URL path prefix for reverse proxy (e.g. "/mediastorm")
 	HomepageAPIKey string `json:"homepageApiKey,omitempty"` // API key for Homepage dashboard integration
+	// TrustedProxies lists reverse proxy addresses (IPs or CIDRs) whose
+	// X-Forwarded-For and X-Real-IP headers are believed for login throttling.
+	TrustedProxies []string `json:"trustedProxies,omitempty"`
 }
 
 type UsenetSettings struct {
@@ -139,6 +154,138 @@ type MetadataSettings struct {
 	Language         []string `json:"language"`
 	PrimaryLanguage  string   `json:"primaryLanguage"`
 	AllowAdultSearch bool     `json:"allowAdultSearch"`
+	FanartAPIKey     string   `json:"fanartApiKey,omitempty"`
+	// ArtworkProviderPriority orders artwork providers ("tmdb", "fanart", "tvdb")
+	// when more than one can supply the same image type, e.g. logos.
+	ArtworkProviderPriority []string `json:"artworkProviderPriority,omitempty"`
+	// Artwork picks posters and backdrops by language, text, resolution and
+	// provider. Profiles can override it.
+	Artwork ArtworkPreferences `json:"artwork"`
+	// ProxyArtwork rewrites TMDB/TVDB artwork URLs in metadata responses to the
+	// server's image proxy, so clients never fetch from the CDNs themselves.
+	ProxyArtwork bool `json:"proxyArtwork"`
+	// AniListEnabled attaches AniList metadata (romaji/native titles, artwork,
+	// absolute numbering) to anime series by default. Profiles can override it
+	// per series through content preferences.
+	AniListEnabled bool `json:"anilistEnabled"`
+	// CertificationCountry selects whose content ratings and release dates are
+	// shown and used for parental controls (ISO 3166-1, e.g. "DE"). Titles
+	// without data for that country fall back to US data. Empty means US.
+	// Profiles can override it.
+	CertificationCountry string `json:"certificationCountry,omitempty"`
+	// WatchProviderCountry selects whose streaming catalogs are shown on
+	// movie and series details (ISO 3166-1). Empty follows the content
+	// rating country.
+	WatchProviderCountry string `json:"watchProviderCountry,omitempty"`
+	// RateLimits tunes upstream request rates and enrichment fan-out. Zero
+	// values keep the built-in defaults.
+	RateLimits MetadataRateLimits `json:"rateLimits"`
+	// Offline freezes metadata: responses come from the cache only, cached
+	// entries don't expire, cache misses fail fast, and the cache manager and
+	// scheduled tasks that reach external services pause. For metered
+	// connections and provider outages.
+	Offline bool `json:"offline"`
+	// ExternalProviders are HTTP metadata sources declared in settings, such
+	// as region-specific databases, searched alongside TVDB and TMDB.
+	ExternalProviders []ExternalMetadataProvider `json:"externalProviders,omitempty"`
+}
+
+// ExternalMetadataProvider declares an HTTP metadata source. Requests only
+// ever go to BaseURL's host; paths are relative to BaseURL and may use the
+// {query}, {mediaType} and {id} placeholders.
+type ExternalMetadataProvider struct {
+	// Name prefixes the provider's title IDs ("douban:movie:123").
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+	BaseURL string `json:"baseUrl"`
+	// AuthHeader and AuthValue are sent with every request, e.g.
+	// "Authorization" and "Bearer <token>".
+	AuthHeader string `json:"authHeader,omitempty"`
+	AuthValue  string `json:"authValue,omitempty"`
+	// SearchPath returns a list of titles, e.g. "/search?q={query}&type={mediaType}".
+	SearchPath string `json:"searchPath"`
+	// DetailsPath returns one title, e.g. "/{mediaType}/{id}".
+	DetailsPath string `json:"detailsPath"`
+	// ImageBaseURL is prepended to relative image paths.
+	ImageBaseURL string `json:"imageBaseUrl,omitempty"`
+	// TimeoutSeconds bounds each request. Zero uses the default of 8 seconds.
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+	// Mapping locates title fields in the responses.
+	Mapping ExternalProviderMapping `json:"mapping"`
+}
+
+// ExternalProviderMapping holds dot-separated paths into a provider's JSON
+// responses ("data.items", "images.poster", "ids.0.value"). Results is the
+// path to the list of titles in a search response and Item the path to the
+// title in a details response (empty for the root); the other paths are
+// relative to a title.
+type ExternalProviderMapping struct {
+	Results   string `json:"results"`
+	Item      string `json:"item,omitempty"`
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	MediaType string `json:"mediaType,omitempty"`
+	Year      string `json:"year,omitempty"`
+	Overview  string `json:"overview,omitempty"`
+	Poster    string `json:"poster,omitempty"`
+	Backdrop  string `json:"backdrop,omitempty"`
+	IMDBID    string `json:"imdbId,omitempty"`
+	TMDBID    string `json:"tmdbId,omitempty"`
+	TVDBID    string `json:"tvdbId,omitempty"`
+}
+
+// ArtworkPreferences steers which poster and backdrop a title gets when TMDB
+// and TVDB offer several. Zero values keep the default choice. Profiles
+// override the server's preferences field by field.
+type ArtworkPreferences struct {
+	// Language picks artwork whose text is in this language ("de", "deu").
+	// Empty follows the metadata language.
+	Language string `json:"language,omitempty"`
+	// Textless is "prefer" to pick artwork without text (the default) or
+	// "avoid" to pick artwork with the title on it.
+	Textless string `json:"textless,omitempty"`
+	// MinPosterWidth and MinBackdropWidth skip smaller images whenever a
+	// large enough one exists.
+	MinPosterWidth   int `json:"minPosterWidth,omitempty"`
+	MinBackdropWidth int `json:"minBackdropWidth,omitempty"`
+	// Source is the provider tried first, "tmdb" (the default) or "tvdb".
+	Source string `json:"source,omitempty"`
+}
+
+// Merge returns p with every field set in override replacing its own.
+func (p ArtworkPreferences) Merge(override ArtworkPreferences) ArtworkPreferences {
+	if override.Language != "" {
+		p.Language = override.Language
+	}
+	if override.Textless != "" {
+		p.Textless = override.Textless
+	}
+	if override.MinPosterWidth > 0 {
+		p.MinPosterWidth = override.MinPosterWidth
+	}
+	if override.MinBackdropWidth > 0 {
+		p.MinBackdropWidth = override.MinBackdropWidth
+	}
+	if override.Source != "" {
+		p.Source = override.Source
+	}
+	return p
+}
+
+// MetadataRateLimits configures per-provider request limits for metadata APIs.
+type MetadataRateLimits struct {
+	TMDB              ProviderRateLimit `json:"tmdb"`
+	TVDB              ProviderRateLimit `json:"tvdb"`
+	MDBList           ProviderRateLimit `json:"mdblist"`
+	EnrichConcurrency int               `json:"enrichConcurrency"`
+	EnrichLimit       int               `json:"enrichLimit"`
+}
+
+// ProviderRateLimit caps requests per second and concurrent requests for one
+// upstream API. Zero concurrency means unlimited.
+type ProviderRateLimit struct {
+	QPS         float64 `json:"qps"`
+	Concurrency int     `json:"concurrency"`
 }
 
 func normalizeMetadataLanguages(languages []string) []string {
@@ -209,6 +356,10 @@ func normalizeAIProvider(provider string) string {
 		return "nanogpt"
 	case "linkapi", "link-api", "link_api":
 		return "linkapi"
+	case "ollama":
+		return "ollama"
+	case "openai-compatible", "openai_compatible", "openaicompatible", "custom":
+		return "openai-compatible"
 	default:
 		return strings.ToLower(strings.TrimSpace(provider))
 	}
@@ -233,6 +384,10 @@ func (m *MetadataSettings) NormalizeAISettings() {
 type CacheSettings struct {
 	Directory        string `json:"directory"`
 	MetadataTTLHours int    `json:"metadataTtlHours"`
+	// MetadataBackend selects the metadata cache store: "file" (one JSON file per
+	// entry) or "sqlite" (single database with size-based eviction).
+	MetadataBackend   string `json:"metadataBackend,omitempty"`
+	MetadataMaxSizeMB int    `json:"metadataMaxSizeMb,omitempty"` // SQLite backend only; 0 = unbounded
 }
 
 // LogConfig represents logging configuration (for altmount compatibility)
@@ -386,10 +541,12 @@ type PlaybackSettings struct {
 	CreditsAutoSkip               bool                      `json:"creditsAutoSkip"`                     // Automatically play the next episode after credits are detected
 	CreditsDetection              bool                      `json:"creditsDetection"`                    // Legacy name for creditsAutoSkip
 	MatchFrameRate                bool                      `json:"matchFrameRate"`                      // Request TV display refresh rate matching during playback
+	DisableAutoPlayNext           bool                      `json:"disableAutoPlayNext,omitempty"`       // Stay on the finished episode instead of starting the next one
 	MaxConcurrentStreams          int                       `json:"maxConcurrentStreams"`                // Global max concurrent VOD streams across all accounts (0 = unlimited)
 	MaxResultsPerResolution       int                       `json:"maxResultsPerResolution"`             // Maximum number of results per resolution tier (0 = no limit)
 	YouTubeProxyURL               string                    `json:"youtubeProxyUrl,omitempty"`           // Optional proxy URL passed to yt-dlp for YouTube extraction/downloads
 	Thumbnails                    PlaybackThumbnailSettings `json:"thumbnails"`                          // Seek-preview thumbnail generation settings
+	TrailerPrequeue               TrailerPrequeueSettings   `json:"trailerPrequeue"`                     // Automatic trailer downloads for hero/trending titles
 }
 
 type PlaybackThumbnailSettings struct {
@@ -397,6 +554,18 @@ type PlaybackThumbnailSettings struct {
 	Workers int  `json:"workers"`
 }
 
+// TrailerPrequeueSettings configures the background policy that downloads
+// trailers for the hero (Top 10) and first trending titles ahead of time.
+type TrailerPrequeueSettings struct {
+	Enabled          bool   `json:"enabled"`
+	HeroItems        int    `json:"heroItems"`        // Top 10 titles to prequeue
+	TrendingRowItems int    `json:"trendingRowItems"` // Titles from the start of each trending row
+	MaxDiskMB        int    `json:"maxDiskMb"`        // Disk quota for downloaded trailers
+	WindowStart      string `json:"windowStart"`      // "HH:MM" server time; empty = any time
+	WindowEnd        string `json:"windowEnd"`        // "HH:MM" server time; may wrap midnight
+	PreviewClips     bool   `json:"previewClips"`     // Cut a short muted preview clip from each trailer
+}
+
 // LiveTVFilterSettings controls backend-side filtering for Live TV channels.
 type LiveTVFilterSettings struct {
 	EnabledCategories []string `json:"enabledCategories"` // Only show channels in these categories (empty = show all)
@@ -501,6 +670,9 @@ type LiveSettings struct {
 	StreamFormat          string               `json:"streamFormat"`       // "hls" (default) or "direct" - how to deliver live streams to clients
 	Filtering             LiveTVFilterSettings `json:"filtering"`          // Backend-side channel filtering
 	EPG                   EPGSettings          `json:"epg"`                // Electronic Program Guide settings
+	// MaxConcurrentRecordings caps overlapping DVR recordings scheduled by
+	// recording rules (0 = unlimited).
+	MaxConcurrentRecordings int `json:"maxConcurrentRecordings,omitempty"`
 }
 
 // GetEffectivePlaylistURL returns the playlist URL based on the configured mode.
@@ -521,11 +693,12 @@ type ShelfConfig struct {
 	Enabled                bool                   `json:"enabled"`                          // Whether the shelf is visible
 	Order                  int                    `json:"order"`                            // Sort order (lower numbers appear first)
 	Type                   string                 `json:"type,omitempty"`                   // "builtin" (default), "mdblist", "trakt", "simkl", "letterboxd", "genre", "decade", "collection-hub", or "local-library"
-	ListURL                string                 `json:"listUrl,omitempty"`                // MDBList URL for custom lists (e.g., https://mdblist.com/lists/username/list-name/json)
+	ListURL                string                 `json:"listUrl,omitempty"`                // MDBList, Letterboxd, IMDb or Trakt URL for custom lists (e.g., https://mdblist.com/lists/username/list-name/json)
+	TrendingSource         string                 `json:"trendingSource,omitempty"`         // For trending shelves: "mdblist" (default) or "provider:list", e.g. "trakt:popular"
 	StreamingServices      []StreamingServiceLink `json:"streamingServices,omitempty"`      // Service cards for the built-in Streaming Services shelf
 	CollectionItems        []CollectionHubLink    `json:"collectionItems,omitempty"`        // Shelf cards for collection hub shelves
-	TraktAccountID         string                 `json:"traktAccountId,omitempty"`         // Trakt account ID, or "__all__" for master-account global watchlists
-	TraktListType          string                 `json:"traktListType,omitempty"`          // "watchlist" or "custom"
+	TraktAccountID         string                 `json:"traktAccountId,omitempty"`         // Trakt account ID, "__all__" for master-account global watchlists, or "__profile__" for the profile's linked account (recommendations only)
+	TraktListType          string                 `json:"traktListType,omitempty"`          // "watchlist", "custom" or "recommendations"
 	TraktListID            string                 `json:"traktListId,omitempty"`            // Trakt custom list slug/ID when traktListType == "custom"
 	SimklAccountID         string                 `json:"simklAccountId,omitempty"`         // Simkl account ID
 	SimklListType          string                 `json:"simklListType,omitempty"`          // Simkl status bucket: "plantowatch", "watching", "completed", "hold", or "dropped"
@@ -619,6 +792,8 @@ func DefaultHomeShelfConfigs() []ShelfConfig {
 		{ID: "trending-movies", Name: "Trending Movies", Enabled: true, Order: 7},
 		{ID: "trending-tv", Name: "Trending TV Shows", Enabled: true, Order: 8},
 		{ID: "streaming-services", Name: "Streaming Services", Enabled: true, Order: 9},
+		{ID: "leaving-soon", Name: "Leaving Soon", Enabled: false, Order: 10},
+		{ID: "complete-collection", Name: "Complete the Collection", Enabled: true, Order: 11},
 	}
 }
 
@@ -796,6 +971,39 @@ func EnsureDefaultHomeShelves(shelves []ShelfConfig) ([]ShelfConfig, bool) {
 		changed = true
 	}
 
+	if !hasShelf("leaving-soon") {
+		// Off until a Streaming Availability key is set; goes last.
+		insertOrder := 0
+		for _, shelf := range nextShelves {
+			if shelf.Order >= insertOrder {
+				insertOrder = shelf.Order + 1
+			}
+		}
+		nextShelves = append(nextShelves, ShelfConfig{
+			ID:      "leaving-soon",
+			Name:    "Leaving Soon",
+			Enabled: false,
+			Order:   insertOrder,
+		})
+		changed = true
+	}
+
+	if !hasShelf("complete-collection") {
+		insertOrder := 0
+		for _, shelf := range nextShelves {
+			if shelf.Order >= insertOrder {
+				insertOrder = shelf.Order + 1
+			}
+		}
+		nextShelves = append(nextShelves, ShelfConfig{
+			ID:      "complete-collection",
+			Name:    "Complete the Collection",
+			Enabled: true,
+			Order:   insertOrder,
+		})
+		changed = true
+	}
+
 	return nextShelves, changed
 }
 
@@ -905,6 +1113,12 @@ type DisplaySettings struct {
 	BlurUnwatchedEpisodeOverviewsIncludeCurrent bool `json:"blurUnwatchedEpisodeOverviewsIncludeCurrent,omitempty"`
 	// AppLanguage overrides the app UI language (ISO 639-1 code, e.g. "en", "fr"). Empty = use device locale.
 	AppLanguage string `json:"appLanguage,omitempty"`
+	// DateFormat orders numeric dates in server-rendered text: "mdy", "dmy" or "ymd". Empty = language default.
+	DateFormat string `json:"dateFormat,omitempty"`
+	// TimeFormat is the clock style of server-rendered text: "12h" or "24h". Empty = language default.
+	TimeFormat string `json:"timeFormat,omitempty"`
+	// LandingTab is the navigation tab the app opens on. Empty = "home".
+	LandingTab string `json:"landingTab,omitempty"`
 	// Appearance controls app-wide visual accessibility and theming preferences.
 	Appearance AppearanceSettings `json:"appearance,omitempty"`
 	// Branding controls runtime-customizable app branding images.
@@ -1211,12 +1425,18 @@ const (
 	ScheduledTaskTypeLocalMediaScan        ScheduledTaskType = "local_media_scan"
 	ScheduledTaskTypeTraktHistorySync      ScheduledTaskType = "trakt_history_sync"
 	ScheduledTaskTypeSimklHistorySync      ScheduledTaskType = "simkl_history_sync"
+	ScheduledTaskTypeSimklWatchlistSync    ScheduledTaskType = "simkl_watchlist_sync" // Plan-to-watch list
 	ScheduledTaskTypePrewarm               ScheduledTaskType = "prewarm"
 	ScheduledTaskTypePlexHistorySync       ScheduledTaskType = "plex_history_sync"
 	ScheduledTaskTypeJellyfinFavoritesSync ScheduledTaskType = "jellyfin_favorites_sync"
 	ScheduledTaskTypeJellyfinHistorySync   ScheduledTaskType = "jellyfin_history_sync"
+	ScheduledTaskTypeJellyfinSync          ScheduledTaskType = "jellyfin_sync" // Favorites and watched state, any direction
 	ScheduledTaskTypeMDBListWatchlistSync  ScheduledTaskType = "mdblist_watchlist_sync"
 	ScheduledTaskTypeMDBListHistorySync    ScheduledTaskType = "mdblist_history_sync"
+	ScheduledTaskTypeEmailDigest           ScheduledTaskType = "email_digest"    // Per-profile digest mailed to config "to"
+	ScheduledTaskTypeTVDBReidentify        ScheduledTaskType = "tvdb_reidentify" // Migrate stored references off merged/deleted TVDB series
+	ScheduledTaskTypeLeavingSoon           ScheduledTaskType = "leaving_soon"    // Per-profile alert for watchlist titles leaving its streaming services
+	ScheduledTaskTypeWatchTime             ScheduledTaskType = "watch_time"      // Per-profile warning when nearing or reaching the weekly watch time goal
 )
 
 const ScheduledTaskLocalMediaAllLibraries = "__all__"
@@ -1239,7 +1459,11 @@ const (
 	ScheduledTaskFrequency6Hours  ScheduledTaskFrequency = "6hours"
 	ScheduledTaskFrequency12Hours ScheduledTaskFrequency = "12hours"
 	ScheduledTaskFrequencyDaily   ScheduledTaskFrequency = "daily"
+	ScheduledTaskFrequencyWeekly  ScheduledTaskFrequency = "weekly"
 	ScheduledTaskFrequencyOnce    ScheduledTaskFrequency = "once"
+	// ScheduledTaskFrequencyCron runs the task on the task's CronExpression,
+	// evaluated in its Timezone, instead of a fixed interval since LastRunAt.
+	ScheduledTaskFrequencyCron ScheduledTaskFrequency = "cron"
 )
 
 // ScheduledTaskStatus represents the last run status
@@ -1267,24 +1491,151 @@ type DryRunItem struct {
 
 // ScheduledTask represents a single scheduled task configuration
 type ScheduledTask struct {
-	ID            string                 `json:"id"`
-	Type          ScheduledTaskType      `json:"type"`
-	Name          string                 `json:"name"`
-	Enabled       bool                   `json:"enabled"`
-	Frequency     ScheduledTaskFrequency `json:"frequency"`
-	Config        map[string]string      `json:"config"` // Task-specific config (e.g., plexAccountId, profileId)
-	LastRunAt     *time.Time             `json:"lastRunAt,omitempty"`
-	LastStatus    ScheduledTaskStatus    `json:"lastStatus"`
-	LastError     string                 `json:"lastError,omitempty"`
-	ItemsImported int                    `json:"itemsImported,omitempty"`
-	DryRunDetails *DryRunDetails         `json:"dryRunDetails,omitempty"` // Results from dry run (what would be added/removed)
-	CreatedAt     time.Time              `json:"createdAt"`
+	ID             string                 `json:"id"`
+	Type           ScheduledTaskType      `json:"type"`
+	Name           string                 `json:"name"`
+	Enabled        bool                   `json:"enabled"`
+	Frequency      ScheduledTaskFrequency `json:"frequency"`
+	CronExpression string                 `json:"cronExpression,omitempty"` // Five-field cron expression, used when Frequency is "cron"
+	Timezone       string                 `json:"timezone,omitempty"`       // IANA timezone for CronExpression (empty = server local time)
+	Config         map[string]string      `json:"config"`                   // Task-specific config (e.g., plexAccountId, profileId)
+	LastRunAt      *time.Time             `json:"lastRunAt,omitempty"`
+	LastStatus     ScheduledTaskStatus    `json:"lastStatus"`
+	LastError      string                 `json:"lastError,omitempty"`
+	ItemsImported  int                    `json:"itemsImported,omitempty"`
+	DryRunDetails  *DryRunDetails         `json:"dryRunDetails,omitempty"` // Results from dry run (what would be added/removed)
+	CreatedAt      time.Time              `json:"createdAt"`
+	// StartedAt is persisted before a run begins and cleared when it finishes,
+	// so a run interrupted by a restart can be detected on the next start.
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+	// SyncWriteKeys are idempotency keys for external writes made by a run
+	// that has not yet completed successfully; a resumed run skips them.
+	SyncWriteKeys []string `json:"syncWriteKeys,omitempty"`
+	// Notifications are sent when a run finishes.
+	Notifications []TaskNotification `json:"notifications,omitempty"`
+	// RunHistory holds the most recent runs, newest first.
+	RunHistory []ScheduledTaskRun `json:"runHistory,omitempty"`
+}
+
+// ScheduledTaskRun records the outcome of a single scheduled task run.
+type ScheduledTaskRun struct {
+	StartedAt     time.Time           `json:"startedAt"`
+	FinishedAt    time.Time           `json:"finishedAt"`
+	DurationMs    int64               `json:"durationMs"`
+	Status        ScheduledTaskStatus `json:"status"`
+	Error         string              `json:"error,omitempty"`
+	ItemsImported int                 `json:"itemsImported"`
+	Message       string              `json:"message,omitempty"`
+	DryRun        bool                `json:"dryRun,omitempty"`
+	DryRunDetails *DryRunDetails      `json:"dryRunDetails,omitempty"` // Truncated to keep settings small
+}
+
+// TaskNotificationType identifies a notification service.
+type TaskNotificationType string
+
+const (
+	TaskNotificationTypeDiscord TaskNotificationType = "discord"
+	TaskNotificationTypeGotify  TaskNotificationType = "gotify"
+	TaskNotificationTypeNtfy    TaskNotificationType = "ntfy"
+	TaskNotificationTypeWebhook TaskNotificationType = "webhook"
+	TaskNotificationTypeEmail   TaskNotificationType = "email"
+)
+
+// TaskNotification sends a scheduled task's outcome to an external service.
+type TaskNotification struct {
+	Type      TaskNotificationType `json:"type"`
+	URL       string               `json:"url"`             // Discord/generic webhook URL, Gotify server URL, ntfy topic URL or email recipients (mailto: optional)
+	Token     string               `json:"token,omitempty"` // Gotify application token or ntfy access token
+	OnSuccess bool                 `json:"onSuccess"`
+	OnFailure bool                 `json:"onFailure"`
+}
+
+// SMTP connection security modes.
+const (
+	SMTPSecurityStartTLS = "starttls"
+	SMTPSecurityTLS      = "tls"
+	SMTPSecurityNone     = "none"
+)
+
+// SMTPSettings configures the mail server used for email notifications and
+// digests.
+type SMTPSettings struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`               // default 587
+	Security string `json:"security,omitempty"` // starttls (default), tls or none
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	From     string `json:"from"` // Sender address, e.g. "mediastorm <media@example.com>"
+}
+
+// ParentalControlsSettings configures server-side enforcement of profile
+// content rating ceilings.
+type ParentalControlsSettings struct {
+	// OverridePIN lifts a profile's rating ceiling for requests that send it in
+	// the X-Parental-PIN header. Empty disables the override.
+	OverridePIN string `json:"overridePin,omitempty"`
+}
+
+// StorageSettings configures the free disk space monitor. Prequeue, DVR
+// recordings, trailer downloads and backups pause while a volume they write
+// to is below MinFreeGB.
+type StorageSettings struct {
+	MinFreeGB            int                `json:"minFreeGB"`            // 0 disables the guardrails
+	CheckIntervalSeconds int                `json:"checkIntervalSeconds"` // default 300
+	Notifications        []TaskNotification `json:"notifications,omitempty"`
+}
+
+// OverseerrSettings connects an Overseerr or Jellyseerr instance. When
+// enabled, title details include the title's request state there and
+// profiles can request missing titles through it.
+type OverseerrSettings struct {
+	Enabled bool   `json:"enabled"`
+	URL     string `json:"url"` // e.g. http://overseerr:5055
+	APIKey  string `json:"apiKey"`
+}
+
+// defaultLeavingSoonDays is the "Leaving soon" window when none is set.
+const defaultLeavingSoonDays = 14
+
+// StreamingAvailabilitySettings configures the Streaming Availability API
+// (RapidAPI), which reports when titles leave streaming services.
+type StreamingAvailabilitySettings struct {
+	APIKey string `json:"apiKey"`
+	// Services are the streaming service IDs (e.g. "netflix", "prime")
+	// profiles subscribe to unless they choose their own. Empty means any.
+	Services []string `json:"services,omitempty"`
+	// LeavingSoonDays is how far ahead departures count as leaving soon.
+	// Zero means 14.
+	LeavingSoonDays int `json:"leavingSoonDays,omitempty"`
+}
+
+// LeavingSoonWindow returns how far ahead departures count as leaving soon.
+func (s StreamingAvailabilitySettings) LeavingSoonWindow() time.Duration {
+	days := s.LeavingSoonDays
+	if days <= 0 {
+		days = defaultLeavingSoonDays
+	}
+	return time.Duration(days) * 24 * time.Hour
 }
 
 // ScheduledTasksSettings contains all scheduled task configurations
 type ScheduledTasksSettings struct {
 	Tasks                []ScheduledTask `json:"tasks"`
 	CheckIntervalSeconds int             `json:"checkIntervalSeconds"` // How often scheduler checks for due tasks (default: 60)
+
+	// MaintenanceWindow is when heavy background work (backups, history
+	// syncs, library scans, trending cache refreshes) prefers to run.
+	MaintenanceWindow MaintenanceWindowSettings `json:"maintenanceWindow"`
+}
+
+// MaintenanceWindowSettings is a daily wall-clock window such as 02:00-05:00.
+// End may be earlier than Start for windows that cross midnight. Manual runs
+// are never held back by the window.
+type MaintenanceWindowSettings struct {
+	Enabled  bool   `json:"enabled"`
+	Start    string `json:"start"`              // "HH:MM", inclusive
+	End      string `json:"end"`                // "HH:MM", exclusive
+	Timezone string `json:"timezone,omitempty"` // IANA timezone (empty = server local time)
 }
 
 // NetworkSettings configures network-aware backend URL switching.
@@ -1308,6 +1659,7 @@ const (
 	RankingLanguage          RankingCriterionID = "language"
 	RankingSize              RankingCriterionID = "size"
 	RankingPreferredScraper  RankingCriterionID = "preferred-scraper"
+	RankingHDRFormat         RankingCriterionID = "hdr-format"
 )
 
 // RankingCriterion represents a single ranking criterion with its configuration.
@@ -1333,6 +1685,7 @@ func DefaultRankingCriteria() []RankingCriterion {
 		{ID: RankingLanguage, Name: "Language", Enabled: true, Order: 4},
 		{ID: RankingSize, Name: "File Size", Enabled: true, Order: 5},
 		{ID: RankingPreferredScraper, Name: "Preferred Scraper", Enabled: false, Order: 6},
+		{ID: RankingHDRFormat, Name: "HDR Format", Enabled: false, Order: 7},
 	}
 }
 
@@ -1340,6 +1693,7 @@ func DefaultRankingCriteria() []RankingCriterion {
 func DefaultSettings() Settings {
 	sabnzbdEnabled := false
 	return Settings{
+		SchemaVersion: CurrentSettingsVersion,
 		Server:        ServerSettings{Host: "0.0.0.0", Port: 7777},
 		Usenet:        []UsenetSettings{},
 		UsenetEngines: DefaultUsenetEngineSettings(),
@@ -1347,8 +1701,8 @@ func DefaultSettings() Settings {
 		TorrentScrapers: []TorrentScraperConfig{
 			{Name: "Torrentio", Type: "torrentio", Enabled: true, Options: "sort=qualitysize|qualityfilter=480p,scr,cam"},
 		},
-		Metadata:  MetadataSettings{TVDBAPIKey: "", TMDBAPIKey: "", Language: []string{"eng"}, PrimaryLanguage: "eng", AllowAdultSearch: false},
-		Cache:     CacheSettings{Directory: "cache", MetadataTTLHours: 24},
+		Metadata:  MetadataSettings{TVDBAPIKey: "", TMDBAPIKey: "", Language: []string{"eng"}, PrimaryLanguage: "eng", AllowAdultSearch: false, ArtworkProviderPriority: []string{"tmdb", "fanart", "tvdb"}},
+		Cache:     CacheSettings{Directory: "cache", MetadataTTLHours: 24, MetadataBackend: "file", MetadataMaxSizeMB: 2048},
 		WebDAV:    WebDAVSettings{Enabled: true, Prefix: "/webdav", Username: "novastream", Password: ""},
 		Database:  DatabaseSettings{Path: "cache/queue.db"},
 		Streaming: StreamingSettings{MaxDownloadWorkers: 15, MaxCacheSizeMB: 100, ServiceMode: StreamingServiceModeHybrid, SearchMode: SearchModeFast, DebridProviders: []DebridProviderSettings{}, UsenetResolutionTimeoutSec: 0, IndexerTimeoutSec: 5, HealthCheckTimeoutSec: 15, MaxAlternateTitleSearches: 5},
@@ -1356,7 +1710,7 @@ func DefaultSettings() Settings {
 		SABnzbd:   SABnzbdSettings{Enabled: &sabnzbdEnabled, FallbackHost: "", FallbackAPIKey: ""},
 		AltMount:  nil,
 		Transmux:  TransmuxSettings{Enabled: true, FFmpegPath: "ffmpeg", FFprobePath: "ffprobe", HLSTempDirectory: "/tmp/novastream-hls"},
-		Playback:  PlaybackSettings{PreferredPlayer: "native", PreferredAudioLanguage: "eng", PauseWhenAppInactive: false, UseLoadingScreen: false, SubtitleSize: 1.0, SubtitleUseCropDetectPosition: true, SubtitleColor: "#FFFFFF", SubtitleOpacity: 1.0, SubtitleBold: false, SubtitleOutlineEnabled: false, SubtitleOutlineColor: "#000000", SubtitleOutlineWeight: 0.35, SubtitleBackgroundEnabled: true, SubtitleBackgroundColor: "#000000", SubtitleBackgroundOpacity: 0.6, SeekForwardSeconds: 30, SeekBackwardSeconds: 10, CreditsDetectionEnabled: false, MatchFrameRate: false, Thumbnails: PlaybackThumbnailSettings{Enabled: false, Workers: 1}},
+		Playback:  PlaybackSettings{PreferredPlayer: "native", PreferredAudioLanguage: "eng", PauseWhenAppInactive: false, UseLoadingScreen: false, SubtitleSize: 1.0, SubtitleUseCropDetectPosition: true, SubtitleColor: "#FFFFFF", SubtitleOpacity: 1.0, SubtitleBold: false, SubtitleOutlineEnabled: false, SubtitleOutlineColor: "#000000", SubtitleOutlineWeight: 0.35, SubtitleBackgroundEnabled: true, SubtitleBackgroundColor: "#000000", SubtitleBackgroundOpacity: 0.6, SeekForwardSeconds: 30, SeekBackwardSeconds: 10, CreditsDetectionEnabled: false, MatchFrameRate: false, Thumbnails: PlaybackThumbnailSettings{Enabled: false, Workers: 1}, TrailerPrequeue: TrailerPrequeueSettings{Enabled: false, HeroItems: 5, TrendingRowItems: 5, MaxDiskMB: 1024}},
 		Live:      LiveSettings{Mode: "m3u", PlaylistURL: "", MaxStreams: 0, PlaylistCacheTTLHours: 24},
 		HomeShelves: HomeShelvesSettings{
 			Shelves:        DefaultHomeShelfConfigs(),
@@ -1416,6 +1770,14 @@ func DefaultSettings() Settings {
 		ScheduledTasks: ScheduledTasksSettings{
 			Tasks:                []ScheduledTask{},
 			CheckIntervalSeconds: 60, // Check every 60 seconds
+			MaintenanceWindow: MaintenanceWindowSettings{
+				Start: "02:00",
+				End:   "05:00",
+			},
+		},
+		Storage: StorageSettings{
+			MinFreeGB:            5,
+			CheckIntervalSeconds: 300,
 		},
 		Network: NetworkSettings{
 			HomeWifiSSID:     "",
@@ -1429,6 +1791,10 @@ func DefaultSettings() Settings {
 			RetentionDays:  30, // Delete backups older than 30 days
 			RetentionCount: 10, // Keep at most 10 backups
 		},
+		SMTP: SMTPSettings{
+			Port:     587,
+			Security: SMTPSecurityStartTLS,
+		},
 	}
 }
 
@@ -1477,6 +1843,9 @@ func backfillUsenetEngineDefaults(existing []UsenetEngineSettings) []UsenetEngin
 // Manager loads and persists settings to a JSON file.
 type Manager struct {
 	path string
+	// upgradeMu keeps concurrent loads from backing up and rewriting an old
+	// settings file twice.
+	upgradeMu sync.Mutex
 }
 
 func NewManager(configPath string) *Manager {
@@ -1711,6 +2080,11 @@ func (m *Manager) Load() (Settings, error) {
 		}
 	}
 
+	// Turn the disk space guardrails on for configs that predate them.
+	if _, exists := raw["storage"]; !exists {
+		raw["storage"] = map[string]interface{}{"minFreeGB": 5}
+	}
+
 	// Backfill subtitles.enableTranslatedSubs default (true) for existing configs.
 	if subtitlesRaw, ok := raw["subtitles"]; ok {
 		if subtitlesMap, ok := subtitlesRaw.(map[string]interface{}); ok {
@@ -1790,6 +2164,11 @@ func (m *Manager) Load() (Settings, error) {
 		delete(playbackRaw, "creditsDetection")
 	}
 
+	fromVersion, toVersion, err := migrateSettingsVersions(raw, settingsMigrations)
+	if err != nil {
+		return Settings{}, err
+	}
+
 	// Re-encode and decode into Settings struct
 	rawJSON, err := json.Marshal(raw)
 	if err != nil {
@@ -1927,6 +2306,9 @@ func (m *Manager) Load() (Settings, error) {
 	if s.Live.MaxStreams < 0 {
 		s.Live.MaxStreams = 0
 	}
+	if s.Live.MaxConcurrentRecordings < 0 {
+		s.Live.MaxConcurrentRecordings = 0
+	}
 	// Backfill Mode to "m3u" for backward compatibility
 	if s.Live.Mode == "" {
 		s.Live.Mode = "m3u"
@@ -2054,6 +2436,9 @@ func (m *Manager) Load() (Settings, error) {
 	if s.ScheduledTasks.Tasks == nil {
 		s.ScheduledTasks.Tasks = []ScheduledTask{}
 	}
+	if s.Storage.CheckIntervalSeconds <= 0 {
+		s.Storage.CheckIntervalSeconds = 300
+	}
 	// Note: Auto-creation of EPG and playlist refresh tasks is handled in
 	// handlers/settings.go PutSettings() when features are enabled, not here.
 	// This prevents tasks from being recreated after user manually deletes them.
@@ -2113,6 +2498,12 @@ func (m *Manager) Load() (Settings, error) {
 		}
 	}
 
+	if toVersion > fromVersion {
+		if err := m.persistSettingsUpgrade(fromVersion, s); err != nil {
+			log.Printf("[config] WARNING: settings upgraded in memory only: %v", err)
+		}
+	}
+
 	return s, nil
 }
 
@@ -2255,6 +2646,7 @@ func (m *Manager) Save(s Settings) error {
 	if m.path == "" {
 		return errors.New("config path not set")
 	}
+	s.SchemaVersion = CurrentSettingsVersion
 	s.Metadata.NormalizeAISettings()
 	s.Metadata.NormalizeLanguages()
 	s.UsenetEngines = normalizeEnabledUsenetEngines(s.UsenetEngines)
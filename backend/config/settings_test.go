@@ -621,3 +621,97 @@ func containsString(values []string, needle string) bool {
 	}
 	return false
 }
+
+func TestLoadUpgradesUnversionedSettingsWithBackup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "settings.json")
+	raw := []byte(`{"playback":{"preferredPlayer":"native"},"scheduledTasks":{"checkIntervalSeconds":60}}`)
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		t.Fatalf("write settings: %v", err)
+	}
+
+	settings, err := NewManager(path).Load()
+	if err != nil {
+		t.Fatalf("load settings: %v", err)
+	}
+	if settings.SchemaVersion != CurrentSettingsVersion {
+		t.Fatalf("schema version = %d, want %d", settings.SchemaVersion, CurrentSettingsVersion)
+	}
+	if got := settings.Playback.TrailerPrequeue; got.HeroItems != 5 || got.TrendingRowItems != 5 || got.MaxDiskMB != 1024 {
+		t.Fatalf("expected trailer prequeue defaults, got %+v", got)
+	}
+	if got := settings.ScheduledTasks.MaintenanceWindow; got.Start != "02:00" || got.End != "05:00" {
+		t.Fatalf("expected the default maintenance window, got %+v", got)
+	}
+
+	backups, _ := filepath.Glob(filepath.Join(dir, "settings.json.v0-*.bak"))
+	if len(backups) != 1 {
+		t.Fatalf("expected one backup of the original file, got %v", backups)
+	}
+	if backup, _ := os.ReadFile(backups[0]); string(backup) != string(raw) {
+		t.Fatalf("backup does not hold the original file: %s", backup)
+	}
+	var onDisk map[string]interface{}
+	data, _ := os.ReadFile(path)
+	if err := json.Unmarshal(data, &onDisk); err != nil || settingsFileVersion(onDisk) != CurrentSettingsVersion {
+		t.Fatalf("expected the upgraded file on disk, got %s (%v)", data, err)
+	}
+
+	// Once upgraded, loads neither migrate nor back up again.
+	if _, err := NewManager(path).Load(); err != nil {
+		t.Fatalf("reload settings: %v", err)
+	}
+	if backups, _ := filepath.Glob(filepath.Join(dir, "*.bak")); len(backups) != 1 {
+		t.Fatalf("expected no further backups, got %v", backups)
+	}
+}
+
+func TestLoadKeepsZeroValuesAfterUpgrade(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "settings.json")
+	mgr := NewManager(path)
+	settings := DefaultSettings()
+	settings.Playback.TrailerPrequeue = TrailerPrequeueSettings{}
+	settings.ScheduledTasks.MaintenanceWindow = MaintenanceWindowSettings{}
+	if err := mgr.Save(settings); err != nil {
+		t.Fatalf("save settings: %v", err)
+	}
+
+	loaded, err := mgr.Load()
+	if err != nil {
+		t.Fatalf("load settings: %v", err)
+	}
+	if loaded.Playback.TrailerPrequeue != (TrailerPrequeueSettings{}) {
+		t.Fatalf("expected the cleared trailer prequeue to stay cleared, got %+v", loaded.Playback.TrailerPrequeue)
+	}
+	if loaded.ScheduledTasks.MaintenanceWindow != (MaintenanceWindowSettings{}) {
+		t.Fatalf("expected the cleared maintenance window to stay cleared, got %+v", loaded.ScheduledTasks.MaintenanceWindow)
+	}
+}
+
+func TestMigrateSettingsVersionsRunsPendingStepsInOrder(t *testing.T) {
+	var ran []int
+	step := func(version int) settingsMigration {
+		return settingsMigration{version: version, description: "test", apply: func(raw map[string]interface{}) error {
+			ran = append(ran, version)
+			return nil
+		}}
+	}
+	migrations := []settingsMigration{step(1), step(2), step(3)}
+
+	raw := map[string]interface{}{"schemaVersion": float64(1)}
+	from, to, err := migrateSettingsVersions(raw, migrations)
+	if err != nil || from != 1 || to != 3 {
+		t.Fatalf("migrate = %d → %d (%v), want 1 → 3", from, to, err)
+	}
+	if len(ran) != 2 || ran[0] != 2 || ran[1] != 3 {
+		t.Fatalf("expected steps 2 and 3 to run, got %v", ran)
+	}
+	if settingsFileVersion(raw) != 3 {
+		t.Fatalf("expected raw to be stamped v3, got %v", raw["schemaVersion"])
+	}
+
+	ran = nil
+	if _, to, _ := migrateSettingsVersions(map[string]interface{}{"schemaVersion": float64(5)}, migrations); to != 5 || len(ran) != 0 {
+		t.Fatalf("expected a newer file to be left alone, got v%d after %v", to, ran)
+	}
+}
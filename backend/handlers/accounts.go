@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 
@@ -271,6 +272,38 @@ func (h *AccountsHandler) SetMaxStreams(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(account)
 }
 
+// SetFeatures replaces the features denied to an account.
+func (h *AccountsHandler) SetFeatures(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	accountID := vars["accountID"]
+
+	var req struct {
+		DeniedFeatures []string `json:"deniedFeatures"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, `{"error": "invalid request body"}`, http.StatusBadRequest)
+		return
+	}
+
+	if err := h.accounts.SetDeniedFeatures(accountID, req.DeniedFeatures); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, accounts.ErrAccountNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, accounts.ErrUnknownFeature):
+			status = http.StatusBadRequest
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	account, _ := h.accounts.Get(accountID)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(account)
+}
+
 // HasDefaultPassword returns whether the master account has the default password.
 func (h *AccountsHandler) HasDefaultPassword(w http.ResponseWriter, r *http.Request) {
 	hasDefault := h.accounts.HasDefaultPassword()
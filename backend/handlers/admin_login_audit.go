@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"novastream/services/accounts"
+)
+
+const defaultLoginAttemptsLimit = 100
+
+// LoginAuditResponse is the failed-login trail and the active lockouts.
+type LoginAuditResponse struct {
+	Attempts []accounts.LoginAttempt `json:"attempts"`
+	Lockouts []accounts.LoginLockout `json:"lockouts"`
+}
+
+// GetLoginAudit returns recent failed login attempts, newest first, and the
+// IP addresses and usernames currently locked out.
+func (h *AdminUIHandler) GetLoginAudit(w http.ResponseWriter, r *http.Request) {
+	if h.accountsService == nil {
+		http.Error(w, "Accounts service not available", http.StatusInternalServerError)
+		return
+	}
+
+	limit := defaultLoginAttemptsLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			http.Error(w, "limit must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	resp := LoginAuditResponse{
+		Attempts: h.accountsService.LoginAttempts(limit),
+		Lockouts: h.accountsService.LoginLockouts(),
+	}
+	if resp.Lockouts == nil {
+		resp.Lockouts = []accounts.LoginLockout{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// ClearLoginLockouts lifts all login lockouts.
+func (h *AdminUIHandler) ClearLoginLockouts(w http.ResponseWriter, r *http.Request) {
+	if h.accountsService == nil {
+		http.Error(w, "Accounts service not available", http.StatusInternalServerError)
+		return
+	}
+	h.accountsService.ClearLoginLockouts()
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "lockouts cleared"})
+}
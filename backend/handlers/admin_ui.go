@@ -218,9 +218,10 @@ var SettingsSchema = map[string]interface{}{
 		"order":       0,
 		"description": "Changing host or port requires a container restart to take effect. Only modify these if you know what you're doing.",
 		"fields": map[string]interface{}{
-			"host":     map[string]interface{}{"type": "text", "label": "Host", "description": "Server bind address (leave empty to bind all interfaces)", "order": 0},
-			"port":     map[string]interface{}{"type": "number", "label": "Port", "description": "Server port (default: 7777)", "order": 1},
-			"basePath": map[string]interface{}{"type": "text", "label": "Base Path", "description": "URL path prefix for reverse proxy (e.g. /mediastorm). Requires restart.", "placeholder": "/mediastorm", "order": 2},
+			"host":           map[string]interface{}{"type": "text", "label": "Host", "description": "Server bind address (leave empty to bind all interfaces)", "order": 0},
+			"port":           map[string]interface{}{"type": "number", "label": "Port", "description": "Server port (default: 7777)", "order": 1},
+			"basePath":       map[string]interface{}{"type": "text", "label": "Base Path", "description": "URL path prefix for reverse proxy (e.g. /mediastorm). Requires restart.", "placeholder": "/mediastorm", "order": 2},
+			"trustedProxies": map[string]interface{}{"type": "tags", "label": "Trusted Proxies", "description": "Reverse proxy IPs or CIDRs (e.g. 172.18.0.0/16) allowed to report the client address through X-Forwarded-For. Requests from other addresses are identified by their connection address.", "order": 3},
 		},
 	},
 	"network": map[string]interface{}{
@@ -252,6 +253,31 @@ var SettingsSchema = map[string]interface{}{
 			},
 		},
 	},
+	"smtp": map[string]interface{}{
+		"label":       "Email (SMTP)",
+		"icon":        "mail",
+		"group":       "server",
+		"order":       2,
+		"description": "Mail server for email task notifications and the weekly email digest scheduled task.",
+		"fields": map[string]interface{}{
+			"host":     map[string]interface{}{"type": "text", "label": "Host", "description": "SMTP server hostname", "placeholder": "smtp.example.com", "order": 0},
+			"port":     map[string]interface{}{"type": "number", "label": "Port", "description": "SMTP port (587 for STARTTLS, 465 for TLS)", "order": 1},
+			"security": map[string]interface{}{"type": "select", "label": "Security", "options": []string{"starttls", "tls", "none"}, "description": "STARTTLS upgrades a plain connection, TLS connects encrypted; none sends in clear text", "order": 2},
+			"username": map[string]interface{}{"type": "text", "label": "Username", "description": "Leave empty if the server does not require authentication", "order": 3},
+			"password": map[string]interface{}{"type": "password", "label": "Password", "description": "SMTP password or app password", "order": 4},
+			"from":     map[string]interface{}{"type": "text", "label": "From Address", "description": "Sender address for outgoing email", "placeholder": "mediastorm <media@example.com>", "order": 5},
+		},
+	},
+	"parentalControls": map[string]interface{}{
+		"label":       "Parental Controls",
+		"icon":        "shield",
+		"group":       "server",
+		"order":       3,
+		"description": "Profiles with a content rating ceiling, and kids profiles in rating mode, only see titles at or below their ratings in trending, search, lists, similar titles and recommendations.",
+		"fields": map[string]interface{}{
+			"overridePin": map[string]interface{}{"type": "password", "label": "Override PIN", "description": "Clients that send this PIN in the X-Parental-PIN header see unfiltered results. Leave empty to disable the override", "order": 0},
+		},
+	},
 	"streaming": map[string]interface{}{
 		"label": "Search & Resolution",
 		"icon":  "search",
@@ -508,6 +534,7 @@ var SettingsSchema = map[string]interface{}{
 			"epg.refreshIntervalHours": map[string]interface{}{"type": "number", "label": "EPG Refresh Interval (hours)", "description": "How often to refresh EPG data (default: 12)", "showWhen": map[string]interface{}{"field": "epg.enabled", "value": true}, "order": 15},
 			"epg.retentionDays":        map[string]interface{}{"type": "number", "label": "EPG Retention (days)", "description": "How many days of EPG data to keep (default: 7)", "showWhen": map[string]interface{}{"field": "epg.enabled", "value": true}, "order": 16},
 			"epg.timeOffsetMinutes":    map[string]interface{}{"type": "number", "label": "EPG Time Offset (minutes)", "description": "Shift EPG program times by this many minutes. Use positive values to move programs forward, negative to move them backward.", "showWhen": map[string]interface{}{"field": "epg.enabled", "value": true}, "order": 17},
+			"maxConcurrentRecordings":  map[string]interface{}{"type": "number", "label": "Max Concurrent Recordings", "description": "Recording rules skip airings that would exceed this many overlapping recordings; higher priority rules win (0 = unlimited)", "order": 18},
 		},
 	},
 	"live.sources": map[string]interface{}{
@@ -626,19 +653,26 @@ var SettingsSchema = map[string]interface{}{
 			"seekBackwardSeconds":           map[string]interface{}{"type": "number", "label": "Skip Backward", "description": "Seconds to skip backward (default 10)", "step": 5, "min": 5, "max": 120, "order": 19},
 			// Temporarily hidden: loading screen disabled across the board
 			// "useLoadingScreen":          map[string]interface{}{"type": "boolean", "label": "Loading Screen", "description": "Show loading screen during playback init"},
-			"forceAacTranscoding":     map[string]interface{}{"type": "boolean", "label": "Force AAC Audio Transcoding", "description": "Transcode AC3/EAC3/DTS surround audio to AAC. Enable this if using Bluetooth headphones, as they cannot decode surround codecs directly.", "order": 99},
-			"autoPlayTrailersTV":      map[string]interface{}{"type": "boolean", "label": "Auto-Play Trailers (TV)", "description": "Replace backdrop artwork with playing trailer on TV details pages once loaded", "order": 100},
-			"rewindOnResumeFromPause": map[string]interface{}{"type": "number", "label": "Rewind on Unpause", "description": "Seconds to rewind when resuming from pause (default 0)", "step": 1, "min": 0, "max": 30},
-			"rewindOnPlaybackStart":   map[string]interface{}{"type": "number", "label": "Rewind on Resume", "description": "Seconds to rewind when resuming from saved progress (default 0)", "step": 1, "min": 0, "max": 60},
-			"disablePrequeue":         map[string]interface{}{"type": "boolean", "label": "Disable Prequeue", "description": "Disable automatic stream pre-loading when opening a details page. Streams will only be resolved when you press Play. Useful to reduce unnecessary backend load or API calls.", "order": 101},
-			"creditsDetectionEnabled": map[string]interface{}{"type": "boolean", "label": "Credits Detection", "description": "Run on-device OCR near the end of eligible episodes to detect credits and show next-episode actions. Off by default — on low-powered devices such as original Android Fire Sticks this can cause crashes around 90% playback.", "order": 102},
-			"creditsAutoSkip":         map[string]interface{}{"type": "boolean", "label": "Auto-Skip Credits", "description": "Automatically start the next episode after on-device credits detection fires.", "order": 103},
-			"matchFrameRate":          map[string]interface{}{"type": "boolean", "label": "Match Frame Rate", "description": "On supported TV devices, request a display refresh rate that matches the video's frame rate during native playback.", "order": 104},
-			"maxResultsPerResolution": map[string]interface{}{"type": "number", "label": "Max Results Per Resolution", "description": "Maximum number of results per resolution tier (0 = no limit)", "order": 105},
-			"thumbnails.enabled":      map[string]interface{}{"type": "boolean", "label": "Seek Preview Thumbnails", "description": "Generate seek-preview thumbnails during playback. Off by default to avoid extra provider and CPU load.", "order": 106, "group": "seekPreviewThumbnails", "groupLabel": "Seek Preview Thumbnails", "groupDescription": "Server-side thumbnail generation used by the playback scrubber.", "globalOnly": true},
-			"thumbnails.workers":      map[string]interface{}{"type": "number", "label": "Thumbnail Workers", "description": "Concurrent ffmpeg thumbnail workers per generation pass. Default 1.", "step": 1, "min": 1, "max": 8, "order": 107, "group": "seekPreviewThumbnails", "groupLabel": "Seek Preview Thumbnails", "groupDescription": "Server-side thumbnail generation used by the playback scrubber.", "globalOnly": true},
-			"youtubeProxyUrl":         map[string]interface{}{"type": "password", "label": "Proxy URL", "description": "Optional HTTP proxy for YouTube extraction and HLS playback. For Gluetun, use http://gluetun:8888.", "placeholder": "http://gluetun:8888", "order": 108, "group": "youtubeYTDLP", "groupLabel": "YouTube / yt-dlp", "groupDescription": "Server-side YouTube extraction settings used for trailers, YouTube video search, and HLS playback.", "globalOnly": true},
-			"ytdlpCookies":            map[string]interface{}{"type": "file_upload", "label": "Cookies", "description": "Upload a Netscape-format cookies.txt file to help yt-dlp bypass YouTube restrictions on VPS/cloud servers. Export cookies from a browser where you are logged into YouTube using a browser extension like 'Get cookies.txt LOCALLY'.", "order": 109, "endpoint": "/admin/api/ytdlp-cookies", "accept": ".txt", "globalOnly": true, "group": "youtubeYTDLP", "groupLabel": "YouTube / yt-dlp", "groupDescription": "Server-side YouTube extraction settings used for trailers, YouTube video search, and HLS playback."},
+			"forceAacTranscoding":              map[string]interface{}{"type": "boolean", "label": "Force AAC Audio Transcoding", "description": "Transcode AC3/EAC3/DTS surround audio to AAC. Enable this if using Bluetooth headphones, as they cannot decode surround codecs directly.", "order": 99},
+			"autoPlayTrailersTV":               map[string]interface{}{"type": "boolean", "label": "Auto-Play Trailers (TV)", "description": "Replace backdrop artwork with playing trailer on TV details pages once loaded", "order": 100},
+			"rewindOnResumeFromPause":          map[string]interface{}{"type": "number", "label": "Rewind on Unpause", "description": "Seconds to rewind when resuming from pause (default 0)", "step": 1, "min": 0, "max": 30},
+			"rewindOnPlaybackStart":            map[string]interface{}{"type": "number", "label": "Rewind on Resume", "description": "Seconds to rewind when resuming from saved progress (default 0)", "step": 1, "min": 0, "max": 60},
+			"disablePrequeue":                  map[string]interface{}{"type": "boolean", "label": "Disable Prequeue", "description": "Disable automatic stream pre-loading when opening a details page. Streams will only be resolved when you press Play. Useful to reduce unnecessary backend load or API calls.", "order": 101},
+			"creditsDetectionEnabled":          map[string]interface{}{"type": "boolean", "label": "Credits Detection", "description": "Run on-device OCR near the end of eligible episodes to detect credits and show next-episode actions. Off by default — on low-powered devices such as original Android Fire Sticks this can cause crashes around 90% playback.", "order": 102},
+			"creditsAutoSkip":                  map[string]interface{}{"type": "boolean", "label": "Auto-Skip Credits", "description": "Automatically start the next episode after on-device credits detection fires.", "order": 103},
+			"matchFrameRate":                   map[string]interface{}{"type": "boolean", "label": "Match Frame Rate", "description": "On supported TV devices, request a display refresh rate that matches the video's frame rate during native playback.", "order": 104},
+			"maxResultsPerResolution":          map[string]interface{}{"type": "number", "label": "Max Results Per Resolution", "description": "Maximum number of results per resolution tier (0 = no limit)", "order": 105},
+			"thumbnails.enabled":               map[string]interface{}{"type": "boolean", "label": "Seek Preview Thumbnails", "description": "Generate seek-preview thumbnails during playback. Off by default to avoid extra provider and CPU load.", "order": 106, "group": "seekPreviewThumbnails", "groupLabel": "Seek Preview Thumbnails", "groupDescription": "Server-side thumbnail generation used by the playback scrubber.", "globalOnly": true},
+			"thumbnails.workers":               map[string]interface{}{"type": "number", "label": "Thumbnail Workers", "description": "Concurrent ffmpeg thumbnail workers per generation pass. Default 1.", "step": 1, "min": 1, "max": 8, "order": 107, "group": "seekPreviewThumbnails", "groupLabel": "Seek Preview Thumbnails", "groupDescription": "Server-side thumbnail generation used by the playback scrubber.", "globalOnly": true},
+			"trailerPrequeue.enabled":          map[string]interface{}{"type": "boolean", "label": "Prequeue Hero Trailers", "description": "Download trailers for Top 10 and trending titles in the background while no one is streaming.", "order": 110, "group": "trailerPrequeue", "groupLabel": "Trailer Prequeue", "groupDescription": "Download trailers for the hero carousel and first trending titles ahead of time so they start instantly.", "globalOnly": true},
+			"trailerPrequeue.heroItems":        map[string]interface{}{"type": "number", "label": "Hero Titles", "description": "Number of Top 10 titles to prequeue trailers for.", "step": 1, "min": 0, "max": 10, "order": 111, "group": "trailerPrequeue", "groupLabel": "Trailer Prequeue", "groupDescription": "Download trailers for the hero carousel and first trending titles ahead of time so they start instantly.", "globalOnly": true},
+			"trailerPrequeue.trendingRowItems": map[string]interface{}{"type": "number", "label": "Trending Row Titles", "description": "Number of titles from the start of the trending movie and series rows.", "step": 1, "min": 0, "max": 20, "order": 112, "group": "trailerPrequeue", "groupLabel": "Trailer Prequeue", "groupDescription": "Download trailers for the hero carousel and first trending titles ahead of time so they start instantly.", "globalOnly": true},
+			"trailerPrequeue.maxDiskMb":        map[string]interface{}{"type": "number", "label": "Trailer Disk Quota (MB)", "description": "Stop prequeueing once downloaded trailers use this much disk space.", "step": 128, "min": 128, "order": 113, "group": "trailerPrequeue", "groupLabel": "Trailer Prequeue", "groupDescription": "Download trailers for the hero carousel and first trending titles ahead of time so they start instantly.", "globalOnly": true},
+			"trailerPrequeue.windowStart":      map[string]interface{}{"type": "text", "label": "Download Window Start", "description": "Only download between these times (HH:MM, server time). Leave both empty to allow any time.", "placeholder": "01:00", "order": 114, "group": "trailerPrequeue", "groupLabel": "Trailer Prequeue", "groupDescription": "Download trailers for the hero carousel and first trending titles ahead of time so they start instantly.", "globalOnly": true},
+			"trailerPrequeue.windowEnd":        map[string]interface{}{"type": "text", "label": "Download Window End", "description": "End of the download window (HH:MM). May be earlier than the start to span midnight.", "placeholder": "06:00", "order": 115, "group": "trailerPrequeue", "groupLabel": "Trailer Prequeue", "groupDescription": "Download trailers for the hero carousel and first trending titles ahead of time so they start instantly.", "globalOnly": true},
+			"trailerPrequeue.previewClips":     map[string]interface{}{"type": "boolean", "label": "Preview Clips", "description": "Also cut a short muted clip from the start of each prequeued trailer so apps can autoplay a preview when a title is focused.", "order": 116, "group": "trailerPrequeue", "groupLabel": "Trailer Prequeue", "groupDescription": "Download trailers for the hero carousel and first trending titles ahead of time so they start instantly.", "globalOnly": true},
+			"youtubeProxyUrl":                  map[string]interface{}{"type": "password", "label": "Proxy URL", "description": "Optional HTTP proxy for YouTube extraction and HLS playback. For Gluetun, use http://gluetun:8888.", "placeholder": "http://gluetun:8888", "order": 108, "group": "youtubeYTDLP", "groupLabel": "YouTube / yt-dlp", "groupDescription": "Server-side YouTube extraction settings used for trailers, YouTube video search, and HLS playback.", "globalOnly": true},
+			"ytdlpCookies":                     map[string]interface{}{"type": "file_upload", "label": "Cookies", "description": "Upload a Netscape-format cookies.txt file to help yt-dlp bypass YouTube restrictions on VPS/cloud servers. Export cookies from a browser where you are logged into YouTube using a browser extension like 'Get cookies.txt LOCALLY'.", "order": 109, "endpoint": "/admin/api/ytdlp-cookies", "accept": ".txt", "globalOnly": true, "group": "youtubeYTDLP", "groupLabel": "YouTube / yt-dlp", "groupDescription": "Server-side YouTube extraction settings used for trailers, YouTube video search, and HLS playback."},
 		},
 	},
 	"homeShelves": map[string]interface{}{
@@ -678,7 +712,7 @@ var SettingsSchema = map[string]interface{}{
 			"listUrl": map[string]interface{}{
 				"type":        "text",
 				"label":       "MDBList URL",
-				"description": "Format: https://mdblist.com/lists/{username}/{list-name}/json",
+				"description": "Format: https://mdblist.com/lists/{username}/{list-name}/json, a public Letterboxd or IMDb list/watchlist URL, a public Trakt list URL, or a link to an IMDb CSV export",
 				"showWhen":    "type=mdblist",
 				"order":       3,
 			},
@@ -773,6 +807,29 @@ var SettingsSchema = map[string]interface{}{
 					{"value": "fr", "label": "Français"},
 				},
 			},
+			"dateFormat": map[string]interface{}{
+				"type":        "select",
+				"label":       "Date Format",
+				"description": "Date order used in notifications and other text the server writes. Defaults to the convention of the app language.",
+				"order":       23,
+				"options": []map[string]interface{}{
+					{"value": "", "label": "Language Default"},
+					{"value": "mdy", "label": "MM/DD/YYYY"},
+					{"value": "dmy", "label": "DD/MM/YYYY"},
+					{"value": "ymd", "label": "YYYY-MM-DD"},
+				},
+			},
+			"timeFormat": map[string]interface{}{
+				"type":        "select",
+				"label":       "Time Format",
+				"description": "Clock style used in notifications and other text the server writes. Defaults to the convention of the app language.",
+				"order":       24,
+				"options": []map[string]interface{}{
+					{"value": "", "label": "Language Default"},
+					{"value": "12h", "label": "12-hour"},
+					{"value": "24h", "label": "24-hour"},
+				},
+			},
 			"appearance.fontScale": map[string]interface{}{
 				"type":        "number",
 				"label":       "App Font Scale",
@@ -973,11 +1030,13 @@ var SettingsSchema = map[string]interface{}{
 					{"value": "openrouter", "label": "OpenRouter"},
 					{"value": "nanogpt", "label": "NanoGPT"},
 					{"value": "linkapi", "label": "LinkAPI"},
+					{"value": "ollama", "label": "Ollama (local)"},
+					{"value": "openai-compatible", "label": "Custom OpenAI-compatible endpoint"},
 				},
 			},
-			"aiApiKey":  map[string]interface{}{"type": "password", "label": "AI API Key", "description": "API key for the selected AI provider", "order": 3, "globalOnly": true},
+			"aiApiKey":  map[string]interface{}{"type": "password", "label": "AI API Key", "description": "API key for the selected AI provider. Not needed for Ollama or keyless custom endpoints.", "order": 3, "globalOnly": true},
 			"aiModel":   map[string]interface{}{"type": "text", "label": "AI Model", "description": "Optional. Leave blank to use the default model for the selected provider.", "order": 4, "globalOnly": true},
-			"aiBaseUrl": map[string]interface{}{"type": "text", "label": "AI Base URL", "description": "Optional OpenAI-compatible base URL override for providers that need a custom endpoint.", "order": 5, "globalOnly": true},
+			"aiBaseUrl": map[string]interface{}{"type": "text", "label": "AI Base URL", "description": "Optional base URL override. Required for a custom OpenAI-compatible endpoint (e.g. http://localhost:1234/v1); Ollama defaults to http://localhost:11434.", "order": 5, "globalOnly": true},
 			"allowAdultSearch": map[string]interface{}{
 				"type":        "boolean",
 				"label":       "Allow Adult Search Results",
@@ -1026,6 +1085,75 @@ var SettingsSchema = map[string]interface{}{
 				"order":       8,
 				"optionsFrom": "metadataLanguages",
 			},
+			"fanartApiKey": map[string]interface{}{"type": "password", "label": "Fanart.tv API Key", "description": "Optional. Enables clearlogos, banners, and disc art from Fanart.tv.", "order": 9, "globalOnly": true},
+			"artworkProviderPriority": map[string]interface{}{
+				"type":        "tags",
+				"label":       "Artwork Provider Priority",
+				"description": "Preferred order of artwork providers (tmdb, fanart, tvdb). Put fanart first to prefer Fanart.tv logos over TMDB ones.",
+				"order":       10,
+				"globalOnly":  true,
+			},
+			"anilistEnabled": map[string]interface{}{
+				"type":        "boolean",
+				"label":       "AniList Anime Metadata",
+				"description": "Add AniList titles (romaji/native), artwork, and absolute episode numbering to anime series. Can be toggled per series from the details page.",
+				"order":       11,
+				"globalOnly":  true,
+			},
+			"certificationCountry": map[string]interface{}{
+				"type":        "select",
+				"label":       "Content Rating Country",
+				"description": "Rating system and release dates used for certifications, release badges and kids profile limits. Ratings from other countries are mapped to the equivalent US tier; titles without data for this country use US data. Profiles can choose their own country.",
+				"order":       12,
+				"options": []map[string]interface{}{
+					{"value": "", "label": "United States (MPAA / TV Parental Guidelines)"},
+					{"value": "GB", "label": "United Kingdom (BBFC)"},
+					{"value": "IE", "label": "Ireland (IFCO)"},
+					{"value": "DE", "label": "Germany (FSK)"},
+					{"value": "FR", "label": "France (CNC)"},
+					{"value": "ES", "label": "Spain (ICAA)"},
+					{"value": "IT", "label": "Italy"},
+					{"value": "NL", "label": "Netherlands (Kijkwijzer)"},
+					{"value": "SE", "label": "Sweden"},
+					{"value": "AU", "label": "Australia (ACB)"},
+					{"value": "NZ", "label": "New Zealand"},
+					{"value": "CA", "label": "Canada"},
+					{"value": "BR", "label": "Brazil (ClassInd)"},
+					{"value": "MX", "label": "Mexico"},
+					{"value": "IN", "label": "India (CBFC)"},
+					{"value": "JP", "label": "Japan (Eirin)"},
+					{"value": "KR", "label": "South Korea (KMRB)"},
+				},
+			},
+			"proxyArtwork": map[string]interface{}{
+				"type":        "boolean",
+				"label":       "Proxy Artwork",
+				"description": "Serve TMDB/TVDB artwork through this server's cached image proxy instead of linking clients to the CDNs directly. Uses WebP/AVIF when ffmpeg supports them.",
+				"order":       13,
+				"globalOnly":  true,
+			},
+			"watchProviderCountry": map[string]interface{}{
+				"type":        "text",
+				"label":       "Streaming Availability Country",
+				"description": "Two-letter country code (e.g. GB) whose streaming services are listed on movie and series details. Leave empty to follow each profile's content rating country.",
+				"placeholder": "Same as content rating country",
+				"order":       14,
+				"globalOnly":  true,
+			},
+			"artwork.source":                 map[string]interface{}{"type": "select", "label": "Preferred Artwork Source", "description": "Provider whose posters and backdrops are tried first.", "order": 15, "group": "artwork", "groupLabel": "Artwork Selection", "groupDescription": "How posters and backdrops are picked on movie and series details when TMDB and TVDB offer several. Profiles can override these; use Refresh Artwork on a title to re-pick cached artwork.", "options": []map[string]interface{}{{"value": "", "label": "TMDB"}, {"value": "tvdb", "label": "TVDB"}}},
+			"artwork.textless":               map[string]interface{}{"type": "select", "label": "Text on Artwork", "description": "Whether posters and backdrops without the title on them are preferred.", "order": 16, "group": "artwork", "groupLabel": "Artwork Selection", "groupDescription": "How posters and backdrops are picked on movie and series details when TMDB and TVDB offer several. Profiles can override these; use Refresh Artwork on a title to re-pick cached artwork.", "options": []map[string]interface{}{{"value": "", "label": "Prefer textless"}, {"value": "avoid", "label": "Prefer titled"}}},
+			"artwork.language":               map[string]interface{}{"type": "text", "label": "Artwork Language", "description": "Language of titled artwork (e.g. de or deu). Leave empty to follow the metadata language.", "placeholder": "Same as metadata language", "order": 17, "group": "artwork", "groupLabel": "Artwork Selection", "groupDescription": "How posters and backdrops are picked on movie and series details when TMDB and TVDB offer several. Profiles can override these; use Refresh Artwork on a title to re-pick cached artwork."},
+			"artwork.minPosterWidth":         map[string]interface{}{"type": "number", "label": "Minimum Poster Width", "description": "Skip posters narrower than this (pixels) when a larger one exists. 0 = any size.", "step": 100, "min": 0, "order": 18, "group": "artwork", "groupLabel": "Artwork Selection", "groupDescription": "How posters and backdrops are picked on movie and series details when TMDB and TVDB offer several. Profiles can override these; use Refresh Artwork on a title to re-pick cached artwork."},
+			"artwork.minBackdropWidth":       map[string]interface{}{"type": "number", "label": "Minimum Backdrop Width", "description": "Skip backdrops narrower than this (pixels) when a larger one exists. 0 = any size.", "step": 100, "min": 0, "order": 19, "group": "artwork", "groupLabel": "Artwork Selection", "groupDescription": "How posters and backdrops are picked on movie and series details when TMDB and TVDB offer several. Profiles can override these; use Refresh Artwork on a title to re-pick cached artwork."},
+			"rateLimits.tmdb.qps":            map[string]interface{}{"type": "number", "label": "TMDB Requests/sec", "description": "Maximum TMDB requests per second (default 200). Lower this if TMDB returns 429 errors.", "step": 1, "min": 0, "order": 20, "group": "rateLimits", "groupLabel": "API Rate Limits", "groupDescription": "Tune request rates for metadata providers. Leave a value at 0 to use the built-in default.", "globalOnly": true},
+			"rateLimits.tmdb.concurrency":    map[string]interface{}{"type": "number", "label": "TMDB Concurrent Requests", "description": "Maximum TMDB requests in flight (0 = unlimited).", "step": 1, "min": 0, "order": 21, "group": "rateLimits", "groupLabel": "API Rate Limits", "groupDescription": "Tune request rates for metadata providers. Leave a value at 0 to use the built-in default.", "globalOnly": true},
+			"rateLimits.tvdb.qps":            map[string]interface{}{"type": "number", "label": "TVDB Requests/sec", "description": "Maximum TVDB requests per second (default 100).", "step": 1, "min": 0, "order": 22, "group": "rateLimits", "groupLabel": "API Rate Limits", "groupDescription": "Tune request rates for metadata providers. Leave a value at 0 to use the built-in default.", "globalOnly": true},
+			"rateLimits.tvdb.concurrency":    map[string]interface{}{"type": "number", "label": "TVDB Concurrent Requests", "description": "Maximum TVDB requests in flight (0 = unlimited).", "step": 1, "min": 0, "order": 23, "group": "rateLimits", "groupLabel": "API Rate Limits", "groupDescription": "Tune request rates for metadata providers. Leave a value at 0 to use the built-in default.", "globalOnly": true},
+			"rateLimits.mdblist.qps":         map[string]interface{}{"type": "number", "label": "MDBList Requests/sec", "description": "Maximum MDBList rating requests per second (default 0.9, the free tier limit).", "step": 0.1, "min": 0, "order": 24, "group": "rateLimits", "groupLabel": "API Rate Limits", "groupDescription": "Tune request rates for metadata providers. Leave a value at 0 to use the built-in default.", "globalOnly": true},
+			"rateLimits.mdblist.concurrency": map[string]interface{}{"type": "number", "label": "MDBList Concurrent Requests", "description": "Maximum MDBList requests in flight (0 = unlimited).", "step": 1, "min": 0, "order": 25, "group": "rateLimits", "groupLabel": "API Rate Limits", "groupDescription": "Tune request rates for metadata providers. Leave a value at 0 to use the built-in default.", "globalOnly": true},
+			"rateLimits.enrichConcurrency":   map[string]interface{}{"type": "number", "label": "Enrichment Workers", "description": "Parallel lookups per enrichment batch (0 = built-in 5-10 depending on the task).", "step": 1, "min": 0, "order": 26, "group": "rateLimits", "groupLabel": "API Rate Limits", "groupDescription": "Tune request rates for metadata providers. Leave a value at 0 to use the built-in default.", "globalOnly": true},
+			"rateLimits.enrichLimit":         map[string]interface{}{"type": "number", "label": "Enrichment Batch Limit", "description": "Maximum TMDB lookups per trending enrichment pass (default 200). Remaining items are enriched on later refreshes.", "step": 10, "min": 0, "order": 27, "group": "rateLimits", "groupLabel": "API Rate Limits", "groupDescription": "Tune request rates for metadata providers. Leave a value at 0 to use the built-in default.", "globalOnly": true},
+			"offline":                        map[string]interface{}{"type": "boolean", "label": "Offline Mode", "description": "Serve metadata from the cache only. Cached entries stop expiring, titles that aren't cached fail with an error instead of contacting TMDB, TVDB or other providers, and the cache manager and scheduled tasks that reach external services pause (backups keep running). For metered connections and provider outages.", "order": 28, "globalOnly": true},
 		},
 	},
 	"cache": map[string]interface{}{
@@ -1035,8 +1163,10 @@ var SettingsSchema = map[string]interface{}{
 		"order":  99,
 		"hidden": true,
 		"fields": map[string]interface{}{
-			"directory":        map[string]interface{}{"type": "text", "label": "Directory", "description": "Cache directory path"},
-			"metadataTtlHours": map[string]interface{}{"type": "number", "label": "Metadata TTL (hours)", "description": "Metadata cache duration"},
+			"directory":         map[string]interface{}{"type": "text", "label": "Directory", "description": "Cache directory path"},
+			"metadataTtlHours":  map[string]interface{}{"type": "number", "label": "Metadata TTL (hours)", "description": "Metadata cache duration"},
+			"metadataBackend":   map[string]interface{}{"type": "select", "label": "Metadata Cache Backend", "options": []string{"file", "sqlite"}, "description": "Store metadata as one file per entry or in a single SQLite database. Existing file entries are migrated to SQLite automatically. Requires restart"},
+			"metadataMaxSizeMb": map[string]interface{}{"type": "number", "label": "Metadata Cache Max Size (MB)", "description": "SQLite backend only: least recently used entries are evicted above this size (0 = unlimited). Requires restart"},
 		},
 	},
 	"import": map[string]interface{}{
@@ -1078,6 +1208,30 @@ var SettingsSchema = map[string]interface{}{
 			"enableTranslatedSubs":  map[string]interface{}{"type": "boolean", "label": "Enable Translated Subtitles", "description": "Allow automatic translation of embedded English subtitles into the preferred subtitle language", "order": 4},
 		},
 	},
+	"overseerr": map[string]interface{}{
+		"label":       "Overseerr / Jellyseerr",
+		"icon":        "download",
+		"group":       "services",
+		"order":       3,
+		"description": "Show request status on title details and pass requests through to an existing Overseerr or Jellyseerr instance.",
+		"fields": map[string]interface{}{
+			"enabled": map[string]interface{}{"type": "boolean", "label": "Enabled", "description": "Merge availability into title details and allow requests", "order": 0},
+			"url":     map[string]interface{}{"type": "text", "label": "URL", "description": "Base URL of the Overseerr or Jellyseerr server", "placeholder": "http://overseerr:5055", "order": 1},
+			"apiKey":  map[string]interface{}{"type": "password", "label": "API Key", "description": "API key from Settings → General. Requests are made as the key's owner", "order": 2},
+		},
+	},
+	"streamingAvailability": map[string]interface{}{
+		"label":       "Streaming Availability",
+		"icon":        "clock",
+		"group":       "services",
+		"order":       4,
+		"description": "Streaming Availability API (RapidAPI) key for the Leaving Soon shelf and alerts. TMDB's provider data has no end dates, so these features need it.",
+		"fields": map[string]interface{}{
+			"apiKey":          map[string]interface{}{"type": "password", "label": "RapidAPI Key", "description": "Key subscribed to the Streaming Availability API. Each watchlist title is looked up at most twice a day", "order": 0},
+			"services":        map[string]interface{}{"type": "tags", "label": "Subscribed Services", "description": "Service IDs whose departures count (e.g. netflix, prime, disney, hbo, hulu, apple). Empty means any service. Profiles can choose their own", "order": 1},
+			"leavingSoonDays": map[string]interface{}{"type": "number", "label": "Leaving Soon Window (days)", "description": "How far ahead a departure counts as leaving soon. Default 14", "min": 1, "max": 90, "step": 1, "order": 2},
+		},
+	},
 	"mdblist": map[string]interface{}{
 		"label":    "MDBList",
 		"icon":     "star",
@@ -1340,6 +1494,10 @@ type resolvedNZBService interface {
 // MetadataService interface for metadata operations
 type MetadataService interface {
 	ClearCache() error
+	CacheNamespaces() ([]metadata.CacheNamespaceSummary, error)
+	CacheEntries(namespace string, limit int) ([]metadata.CacheEntryInfo, error)
+	InvalidateCacheNamespace(namespace string) (int, error)
+	InvalidateCachedTitle(id string) (int, error)
 	MovieDetails(ctx context.Context, req models.MovieDetailsQuery) (*models.Title, error)
 	SeriesDetails(ctx context.Context, req models.SeriesDetailsQuery) (*models.SeriesDetails, error)
 	SeriesInfo(ctx context.Context, req models.SeriesDetailsQuery) (*models.Title, error)
@@ -1347,6 +1505,8 @@ type MetadataService interface {
 	RefreshTrendingCache()
 	GetTopTenWorkerStatus() metadata.TopTenWorkerStatus
 	TriggerTopTenRefresh()
+	EnrichmentFailures() []metadata.EnrichmentFailure
+	RetryEnrichmentFailures(ctx context.Context) (metadata.EnrichmentRetryResult, error)
 }
 
 // SetMetadataService sets the metadata service for cache clearing and overview fetching
@@ -3090,6 +3250,7 @@ func (h *AdminUIHandler) GetUserSettings(w http.ResponseWriter, r *http.Request)
 			CreditsDetectionEnabled:    models.BoolPtr(globalSettings.Playback.CreditsDetectionEnabled),
 			CreditsAutoSkip:            globalSettings.Playback.CreditsAutoSkip || globalSettings.Playback.CreditsDetection,
 			MatchFrameRate:             models.BoolPtr(globalSettings.Playback.MatchFrameRate),
+			DisableAutoPlayNext:        models.BoolPtr(globalSettings.Playback.DisableAutoPlayNext),
 			MaxResultsPerResolution:    models.IntPtr(globalSettings.Playback.MaxResultsPerResolution),
 		},
 		HomeShelves: models.HomeShelvesSettings{
@@ -3124,6 +3285,7 @@ func (h *AdminUIHandler) GetUserSettings(w http.ResponseWriter, r *http.Request)
 			BypassFilteringForAIOStreamsOnly: models.BoolPtr(globalSettings.Display.BypassFilteringForAIOStreamsOnly),
 			DisableMobileTopCarousel:         models.BoolPtr(globalSettings.Display.DisableMobileTopCarousel),
 			AppLanguage:                      globalSettings.Display.AppLanguage,
+			LandingTab:                       globalSettings.Display.LandingTab,
 			Appearance: models.AppearanceSettings{
 				FontScale:            globalSettings.Display.Appearance.FontScale,
 				AccentColor:          globalSettings.Display.Appearance.AccentColor,
@@ -3290,6 +3452,7 @@ func (h *AdminUIHandler) PropagateSettings(w http.ResponseWriter, r *http.Reques
 						CreditsDetectionEnabled:    models.BoolPtr(globalSettings.Playback.CreditsDetectionEnabled),
 						CreditsAutoSkip:            globalSettings.Playback.CreditsAutoSkip || globalSettings.Playback.CreditsDetection,
 						MatchFrameRate:             models.BoolPtr(globalSettings.Playback.MatchFrameRate),
+						DisableAutoPlayNext:        models.BoolPtr(globalSettings.Playback.DisableAutoPlayNext),
 						MaxResultsPerResolution:    models.IntPtr(globalSettings.Playback.MaxResultsPerResolution),
 					},
 					HomeShelves: models.HomeShelvesSettings{
@@ -3563,6 +3726,23 @@ func (h *AdminUIHandler) RequireMasterAuth(next http.HandlerFunc) http.HandlerFu
 	}
 }
 
+// RequireFeature only runs next for accounts the admin has not denied
+// feature. It expects RequireAuth to have populated the account context;
+// master accounts always pass.
+func (h *AdminUIHandler) RequireFeature(feature string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if auth.IsMaster(r) {
+			next(w, r)
+			return
+		}
+		if h.accountsService == nil || !h.accountsService.HasFeature(auth.GetAccountID(r), feature) {
+			http.Error(w, "This feature is not enabled for your account", http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
 // LoginPage serves the login page (GET)
 func (h *AdminUIHandler) LoginPage(w http.ResponseWriter, r *http.Request) {
 	// If already authenticated, redirect to dashboard
@@ -3589,14 +3769,13 @@ type RegisterPageData struct {
 func (h *AdminUIHandler) RegisterPage(w http.ResponseWriter, r *http.Request) {
 	token := r.URL.Query().Get("token")
 
-	// Validate the token if provided
+	// Validate the token if provided; without one the page asks for an
+	// invitation code instead.
 	var validationError string
 	if token != "" && h.invitationsService != nil {
 		if err := h.invitationsService.Validate(token); err != nil {
 			validationError = err.Error()
 		}
-	} else if token == "" {
-		validationError = "No invitation token provided"
 	}
 
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
@@ -3639,11 +3818,23 @@ func (h *AdminUIHandler) LoginSubmit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Authenticate using accounts service
-	account, err := h.accountsService.Authenticate(username, password)
-	if err != nil {
-		if errors.Is(err, accounts.ErrAccountExpired) {
+	userAgent := r.Header.Get("User-Agent")
+	ipAddress := getClientIPAddress(r)
+	login := h.accountsService.Login
+	if forwardedByUntrustedProxy(r) {
+		log.Printf("[admin-ui] login forwarded by untrusted proxy %s; add it to server.trustedProxies to throttle by client address", ipAddress)
+		login = h.accountsService.LoginFromSharedAddress
+	}
+	account, err := login(username, password, ipAddress, userAgent)
+	if err != nil {
+		var locked *accounts.LockedOutError
+		switch {
+		case errors.As(err, &locked):
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(locked.Until)))
+			h.renderLoginError(w, "Too many failed login attempts. Try again later")
+		case errors.Is(err, accounts.ErrAccountExpired):
 			h.renderLoginError(w, "This account has expired")
-		} else {
+		default:
 			h.renderLoginError(w, "Invalid username or password")
 		}
 		return
@@ -3657,8 +3848,6 @@ func (h *AdminUIHandler) LoginSubmit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create session with appropriate duration
-	userAgent := r.Header.Get("User-Agent")
-	ipAddress := getClientIPAddress(r)
 	session, err := h.sessionsService.CreateWithDuration(account.ID, account.IsMaster, userAgent, ipAddress, sessionDuration)
 	if err != nil {
 		h.renderLoginError(w, "Failed to create session")
@@ -6219,6 +6408,9 @@ type ProfileWithPinStatus struct {
 	KidsMaxMovieRating string    `json:"kidsMaxMovieRating,omitempty"`
 	KidsMaxTVRating    string    `json:"kidsMaxTVRating,omitempty"`
 	KidsAllowedLists   []string  `json:"kidsAllowedLists,omitempty"`
+	KidsAllowedGenres  []string  `json:"kidsAllowedGenres,omitempty"`
+	MaxMovieRating     string    `json:"maxMovieRating,omitempty"`
+	MaxTVRating        string    `json:"maxTVRating,omitempty"`
 	TraktAccountID     string    `json:"traktAccountId,omitempty"`
 	MdblistAccountID   string    `json:"mdblistAccountId,omitempty"`
 	SimklAccountID     string    `json:"simklAccountId,omitempty"`
@@ -6253,6 +6445,9 @@ func (h *AdminUIHandler) GetProfiles(w http.ResponseWriter, r *http.Request) {
 			KidsMaxMovieRating: u.KidsMaxMovieRating,
 			KidsMaxTVRating:    u.KidsMaxTVRating,
 			KidsAllowedLists:   u.KidsAllowedLists,
+			KidsAllowedGenres:  u.KidsAllowedGenres,
+			MaxMovieRating:     u.MaxMovieRating,
+			MaxTVRating:        u.MaxTVRating,
 			TraktAccountID:     u.TraktAccountID,
 			MdblistAccountID:   u.MdblistAccountID,
 			SimklAccountID:     u.SimklAccountID,
@@ -6319,6 +6514,9 @@ func (h *AdminUIHandler) SetProfilePin(w http.ResponseWriter, r *http.Request) {
 		KidsMaxMovieRating: user.KidsMaxMovieRating,
 		KidsMaxTVRating:    user.KidsMaxTVRating,
 		KidsAllowedLists:   user.KidsAllowedLists,
+		KidsAllowedGenres:  user.KidsAllowedGenres,
+		MaxMovieRating:     user.MaxMovieRating,
+		MaxTVRating:        user.MaxTVRating,
 		CreatedAt:          user.CreatedAt,
 		UpdatedAt:          user.UpdatedAt,
 	})
@@ -6365,6 +6563,9 @@ func (h *AdminUIHandler) ClearProfilePin(w http.ResponseWriter, r *http.Request)
 		KidsMaxMovieRating: user.KidsMaxMovieRating,
 		KidsMaxTVRating:    user.KidsMaxTVRating,
 		KidsAllowedLists:   user.KidsAllowedLists,
+		KidsAllowedGenres:  user.KidsAllowedGenres,
+		MaxMovieRating:     user.MaxMovieRating,
+		MaxTVRating:        user.MaxTVRating,
 		CreatedAt:          user.CreatedAt,
 		UpdatedAt:          user.UpdatedAt,
 	})
@@ -6448,6 +6649,9 @@ func (h *AdminUIHandler) CreateProfile(w http.ResponseWriter, r *http.Request) {
 		KidsMaxMovieRating: user.KidsMaxMovieRating,
 		KidsMaxTVRating:    user.KidsMaxTVRating,
 		KidsAllowedLists:   user.KidsAllowedLists,
+		KidsAllowedGenres:  user.KidsAllowedGenres,
+		MaxMovieRating:     user.MaxMovieRating,
+		MaxTVRating:        user.MaxTVRating,
 		CreatedAt:          user.CreatedAt,
 		UpdatedAt:          user.UpdatedAt,
 	})
@@ -6507,6 +6711,9 @@ func (h *AdminUIHandler) RenameProfile(w http.ResponseWriter, r *http.Request) {
 		KidsMaxMovieRating: user.KidsMaxMovieRating,
 		KidsMaxTVRating:    user.KidsMaxTVRating,
 		KidsAllowedLists:   user.KidsAllowedLists,
+		KidsAllowedGenres:  user.KidsAllowedGenres,
+		MaxMovieRating:     user.MaxMovieRating,
+		MaxTVRating:        user.MaxTVRating,
 		CreatedAt:          user.CreatedAt,
 		UpdatedAt:          user.UpdatedAt,
 	})
@@ -6597,6 +6804,9 @@ func (h *AdminUIHandler) SetProfileColor(w http.ResponseWriter, r *http.Request)
 		KidsMaxMovieRating: user.KidsMaxMovieRating,
 		KidsMaxTVRating:    user.KidsMaxTVRating,
 		KidsAllowedLists:   user.KidsAllowedLists,
+		KidsAllowedGenres:  user.KidsAllowedGenres,
+		MaxMovieRating:     user.MaxMovieRating,
+		MaxTVRating:        user.MaxTVRating,
 		CreatedAt:          user.CreatedAt,
 		UpdatedAt:          user.UpdatedAt,
 	})
@@ -6654,6 +6864,9 @@ func (h *AdminUIHandler) SetKidsProfile(w http.ResponseWriter, r *http.Request)
 		KidsMaxMovieRating: user.KidsMaxMovieRating,
 		KidsMaxTVRating:    user.KidsMaxTVRating,
 		KidsAllowedLists:   user.KidsAllowedLists,
+		KidsAllowedGenres:  user.KidsAllowedGenres,
+		MaxMovieRating:     user.MaxMovieRating,
+		MaxTVRating:        user.MaxTVRating,
 		CreatedAt:          user.CreatedAt,
 		UpdatedAt:          user.UpdatedAt,
 	})
@@ -6713,6 +6926,9 @@ func (h *AdminUIHandler) SetProfileIcon(w http.ResponseWriter, r *http.Request)
 		KidsMaxMovieRating: user.KidsMaxMovieRating,
 		KidsMaxTVRating:    user.KidsMaxTVRating,
 		KidsAllowedLists:   user.KidsAllowedLists,
+		KidsAllowedGenres:  user.KidsAllowedGenres,
+		MaxMovieRating:     user.MaxMovieRating,
+		MaxTVRating:        user.MaxTVRating,
 		CreatedAt:          user.CreatedAt,
 		UpdatedAt:          user.UpdatedAt,
 	})
@@ -6759,6 +6975,9 @@ func (h *AdminUIHandler) ClearProfileIcon(w http.ResponseWriter, r *http.Request
 		KidsMaxMovieRating: user.KidsMaxMovieRating,
 		KidsMaxTVRating:    user.KidsMaxTVRating,
 		KidsAllowedLists:   user.KidsAllowedLists,
+		KidsAllowedGenres:  user.KidsAllowedGenres,
+		MaxMovieRating:     user.MaxMovieRating,
+		MaxTVRating:        user.MaxTVRating,
 		CreatedAt:          user.CreatedAt,
 		UpdatedAt:          user.UpdatedAt,
 	})
@@ -6831,6 +7050,9 @@ func (h *AdminUIHandler) UploadProfileIcon(w http.ResponseWriter, r *http.Reques
 		KidsMaxMovieRating: user.KidsMaxMovieRating,
 		KidsMaxTVRating:    user.KidsMaxTVRating,
 		KidsAllowedLists:   user.KidsAllowedLists,
+		KidsAllowedGenres:  user.KidsAllowedGenres,
+		MaxMovieRating:     user.MaxMovieRating,
+		MaxTVRating:        user.MaxTVRating,
 		CreatedAt:          user.CreatedAt,
 		UpdatedAt:          user.UpdatedAt,
 	})
@@ -6970,14 +7192,15 @@ func (h *AdminUIHandler) DeleteYTDLPCookies(w http.ResponseWriter, r *http.Reque
 
 // AdminAccountWithProfiles represents an account with its associated profiles for admin UI
 type AdminAccountWithProfiles struct {
-	ID         string        `json:"id"`
-	Username   string        `json:"username"`
-	IsMaster   bool          `json:"isMaster"`
-	MaxStreams int           `json:"maxStreams"`
-	ExpiresAt  *time.Time    `json:"expiresAt,omitempty"`
-	CreatedAt  time.Time     `json:"createdAt"`
-	UpdatedAt  time.Time     `json:"updatedAt"`
-	Profiles   []models.User `json:"profiles"`
+	ID             string        `json:"id"`
+	Username       string        `json:"username"`
+	IsMaster       bool          `json:"isMaster"`
+	MaxStreams     int           `json:"maxStreams"`
+	DeniedFeatures []string      `json:"deniedFeatures,omitempty"`
+	ExpiresAt      *time.Time    `json:"expiresAt,omitempty"`
+	CreatedAt      time.Time     `json:"createdAt"`
+	UpdatedAt      time.Time     `json:"updatedAt"`
+	Profiles       []models.User `json:"profiles"`
 }
 
 // GetUserAccounts returns all user accounts with their profiles
@@ -6995,14 +7218,15 @@ func (h *AdminUIHandler) GetUserAccounts(w http.ResponseWriter, r *http.Request)
 	for _, acc := range accountsList {
 		profiles := h.usersService.ListForAccount(acc.ID)
 		result = append(result, AdminAccountWithProfiles{
-			ID:         acc.ID,
-			Username:   acc.Username,
-			IsMaster:   acc.IsMaster,
-			MaxStreams: acc.MaxStreams,
-			ExpiresAt:  acc.ExpiresAt,
-			CreatedAt:  acc.CreatedAt,
-			UpdatedAt:  acc.UpdatedAt,
-			Profiles:   profiles,
+			ID:             acc.ID,
+			Username:       acc.Username,
+			IsMaster:       acc.IsMaster,
+			MaxStreams:     acc.MaxStreams,
+			DeniedFeatures: acc.DeniedFeatures,
+			ExpiresAt:      acc.ExpiresAt,
+			CreatedAt:      acc.CreatedAt,
+			UpdatedAt:      acc.UpdatedAt,
+			Profiles:       profiles,
 		})
 	}
 
@@ -7188,6 +7412,43 @@ func (h *AdminUIHandler) SetAccountMaxStreams(w http.ResponseWriter, r *http.Req
 	json.NewEncoder(w).Encode(map[string]string{"status": "max streams updated"})
 }
 
+// SetAccountFeatures replaces the features denied to an account
+func (h *AdminUIHandler) SetAccountFeatures(w http.ResponseWriter, r *http.Request) {
+	if h.accountsService == nil {
+		http.Error(w, "Accounts service not available", http.StatusInternalServerError)
+		return
+	}
+
+	accountID := r.URL.Query().Get("accountId")
+	if accountID == "" {
+		http.Error(w, "accountId parameter required", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		DeniedFeatures []string `json:"deniedFeatures"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.accountsService.SetDeniedFeatures(accountID, req.DeniedFeatures); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, accounts.ErrAccountNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, accounts.ErrUnknownFeature):
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "features updated"})
+}
+
 // RenameAccountRequest represents a request to rename an account
 type RenameAccountRequest struct {
 	Username string `json:"username"`
@@ -7307,17 +7568,48 @@ func (h *AdminUIHandler) HasDefaultPassword(w http.ResponseWriter, r *http.Reque
 type InvitationResponse struct {
 	ID                    string     `json:"id"`
 	Token                 string     `json:"token"`
+	Code                  string     `json:"code,omitempty"`
 	URL                   string     `json:"url"`
 	ExpiresAt             time.Time  `json:"expiresAt"`
 	AccountExpiresInHours int        `json:"accountExpiresInHours"`
+	MaxStreams            int        `json:"maxStreams,omitempty"`
+	DeniedFeatures        []string   `json:"deniedFeatures,omitempty"`
+	ProfileTemplateID     string     `json:"profileTemplateId,omitempty"`
+	ProfileTemplateName   string     `json:"profileTemplateName,omitempty"`
 	UsedAt                *time.Time `json:"usedAt,omitempty"`
 	CreatedAt             time.Time  `json:"createdAt"`
 }
 
 // CreateInvitationRequest represents a request to create an invitation
 type CreateInvitationRequest struct {
-	ExpiresInHours        int `json:"expiresInHours"`
-	AccountExpiresInHours int `json:"accountExpiresInHours"` // 0 = permanent
+	ExpiresInHours        int      `json:"expiresInHours"`
+	AccountExpiresInHours int      `json:"accountExpiresInHours"` // 0 = permanent
+	MaxStreams            int      `json:"maxStreams"`            // 0 = unlimited
+	DeniedFeatures        []string `json:"deniedFeatures"`
+	ProfileTemplateID     string   `json:"profileTemplateId"`
+}
+
+// invitationResponse builds the API view of an invitation.
+func (h *AdminUIHandler) invitationResponse(inv models.Invitation, baseURL string) InvitationResponse {
+	resp := InvitationResponse{
+		ID:                    inv.ID,
+		Token:                 inv.Token,
+		Code:                  inv.Code,
+		URL:                   fmt.Sprintf("%s/register?token=%s", baseURL, inv.Token),
+		ExpiresAt:             inv.ExpiresAt,
+		AccountExpiresInHours: inv.AccountExpiresInHours,
+		MaxStreams:            inv.MaxStreams,
+		DeniedFeatures:        inv.DeniedFeatures,
+		ProfileTemplateID:     inv.ProfileTemplateID,
+		UsedAt:                inv.UsedAt,
+		CreatedAt:             inv.CreatedAt,
+	}
+	if inv.ProfileTemplateID != "" && h.usersService != nil {
+		if profile, ok := h.usersService.Get(inv.ProfileTemplateID); ok {
+			resp.ProfileTemplateName = profile.Name
+		}
+	}
+	return resp
 }
 
 // ListInvitations returns all invitations
@@ -7345,15 +7637,7 @@ func (h *AdminUIHandler) ListInvitations(w http.ResponseWriter, r *http.Request)
 	baseURL := fmt.Sprintf("%s://%s", scheme, host)
 
 	for i, inv := range invs {
-		result[i] = InvitationResponse{
-			ID:                    inv.ID,
-			Token:                 inv.Token,
-			URL:                   fmt.Sprintf("%s/register?token=%s", baseURL, inv.Token),
-			ExpiresAt:             inv.ExpiresAt,
-			AccountExpiresInHours: inv.AccountExpiresInHours,
-			UsedAt:                inv.UsedAt,
-			CreatedAt:             inv.CreatedAt,
-		}
+		result[i] = h.invitationResponse(inv, baseURL)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -7384,9 +7668,30 @@ func (h *AdminUIHandler) CreateInvitation(w http.ResponseWriter, r *http.Request
 	if req.ExpiresInHours <= 0 {
 		req.ExpiresInHours = 168 // 7 days
 	}
+	if req.MaxStreams < 0 {
+		http.Error(w, "maxStreams must not be negative", http.StatusBadRequest)
+		return
+	}
+	for _, feature := range req.DeniedFeatures {
+		if !models.IsAccountFeature(feature) {
+			http.Error(w, fmt.Sprintf("unknown feature %q", feature), http.StatusBadRequest)
+			return
+		}
+	}
+	if req.ProfileTemplateID != "" {
+		if h.usersService == nil || !h.usersService.Exists(req.ProfileTemplateID) {
+			http.Error(w, "template profile not found", http.StatusBadRequest)
+			return
+		}
+	}
 
 	expiresIn := time.Duration(req.ExpiresInHours) * time.Hour
-	inv, err := h.invitationsService.Create(session.AccountID, expiresIn, req.AccountExpiresInHours)
+	inv, err := h.invitationsService.CreateWithOptions(session.AccountID, expiresIn, invitations.Options{
+		AccountExpiresInHours: req.AccountExpiresInHours,
+		MaxStreams:            req.MaxStreams,
+		DeniedFeatures:        req.DeniedFeatures,
+		ProfileTemplateID:     req.ProfileTemplateID,
+	})
 	if err != nil {
 		http.Error(w, "Failed to create invitation", http.StatusInternalServerError)
 		return
@@ -7408,14 +7713,7 @@ func (h *AdminUIHandler) CreateInvitation(w http.ResponseWriter, r *http.Request
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(InvitationResponse{
-		ID:                    inv.ID,
-		Token:                 inv.Token,
-		URL:                   fmt.Sprintf("%s/register?token=%s", baseURL, inv.Token),
-		ExpiresAt:             inv.ExpiresAt,
-		AccountExpiresInHours: inv.AccountExpiresInHours,
-		CreatedAt:             inv.CreatedAt,
-	})
+	json.NewEncoder(w).Encode(h.invitationResponse(inv, baseURL))
 }
 
 // DeleteInvitation deletes an invitation
@@ -7541,11 +7839,21 @@ func (h *AdminUIHandler) RegisterWithInvitation(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	// Apply the invitation's account template
+	if inv.MaxStreams > 0 {
+		if err := h.accountsService.SetMaxStreams(account.ID, inv.MaxStreams); err != nil {
+			fmt.Printf("Warning: failed to set max streams for account %s: %v\n", account.ID, err)
+		}
+	}
+	if len(inv.DeniedFeatures) > 0 {
+		if err := h.accountsService.SetDeniedFeatures(account.ID, inv.DeniedFeatures); err != nil {
+			fmt.Printf("Warning: failed to set feature access for account %s: %v\n", account.ID, err)
+		}
+	}
+
 	// Auto-create a default profile for the new account
 	if h.usersService != nil {
-		if _, err := h.usersService.CreateForAccount(account.ID, req.Username); err != nil {
-			fmt.Printf("Warning: failed to auto-create profile for account %s: %v\n", account.ID, err)
-		}
+		h.createInvitedProfile(account.ID, req.Username, inv.ProfileTemplateID)
 	}
 
 	// Mark the invitation as used
@@ -7562,6 +7870,28 @@ func (h *AdminUIHandler) RegisterWithInvitation(w http.ResponseWriter, r *http.R
 	})
 }
 
+// createInvitedProfile creates the first profile of an invited account, modelled
+// on the invitation's template profile when it still exists.
+func (h *AdminUIHandler) createInvitedProfile(accountID, name, templateID string) {
+	if templateID != "" {
+		profile, err := h.usersService.CreateFromTemplate(accountID, name, templateID)
+		if err == nil {
+			if h.userSettingsService != nil {
+				if settings, err := h.userSettingsService.Get(templateID); err == nil && settings != nil {
+					if err := h.userSettingsService.Update(profile.ID, *settings); err != nil {
+						fmt.Printf("Warning: failed to copy template settings to profile %s: %v\n", profile.ID, err)
+					}
+				}
+			}
+			return
+		}
+		fmt.Printf("Warning: failed to create profile from template %s for account %s: %v\n", templateID, accountID, err)
+	}
+	if _, err := h.usersService.CreateForAccount(accountID, name); err != nil {
+		fmt.Printf("Warning: failed to auto-create profile for account %s: %v\n", accountID, err)
+	}
+}
+
 // ClearMetadataCache clears all cached metadata files
 func (h *AdminUIHandler) ClearMetadataCache(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -7579,6 +7909,96 @@ func (h *AdminUIHandler) ClearMetadataCache(w http.ResponseWriter, r *http.Reque
 	json.NewEncoder(w).Encode(map[string]string{"status": "ok", "message": "Metadata cache cleared"})
 }
 
+// adminCacheEntriesLimit caps the entries listed for a cache namespace when
+// the request does not set a limit.
+const adminCacheEntriesLimit = 200
+
+// ListCacheNamespaces reports entry counts, sizes, and ages per metadata
+// cache namespace.
+func (h *AdminUIHandler) ListCacheNamespaces(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if h.metadataService == nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "metadata service not available"})
+		return
+	}
+	namespaces, err := h.metadataService.CacheNamespaces()
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"namespaces": namespaces})
+}
+
+// ListCacheEntries lists the newest entries of one cache namespace. The
+// optional limit query parameter overrides the default of 200; 0 lists all.
+func (h *AdminUIHandler) ListCacheEntries(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if h.metadataService == nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "metadata service not available"})
+		return
+	}
+	limit := adminCacheEntriesLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			writeServiceError(w, fmt.Errorf("invalid limit %q", raw), http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+	namespace := mux.Vars(r)["namespace"]
+	entries, err := h.metadataService.CacheEntries(namespace, limit)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"namespace": namespace, "entries": entries})
+}
+
+// InvalidateCacheNamespace removes every entry of one cache namespace.
+func (h *AdminUIHandler) InvalidateCacheNamespace(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if h.metadataService == nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "metadata service not available"})
+		return
+	}
+	namespace := mux.Vars(r)["namespace"]
+	removed, err := h.metadataService.InvalidateCacheNamespace(namespace)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError)
+		return
+	}
+	log.Printf("[admin] metadata cache namespace %q invalidated by user request (%d entries)", namespace, removed)
+	json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "removed": removed})
+}
+
+// InvalidateCachedTitle removes the cached details, artwork, trailers,
+// ratings, and ID mappings of one title. The ID is an IMDB ID or a TMDB/TVDB
+// ID, optionally qualified as "tmdb:1396" or "tvdb:81189".
+func (h *AdminUIHandler) InvalidateCachedTitle(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if h.metadataService == nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "metadata service not available"})
+		return
+	}
+	titleID := strings.TrimSpace(mux.Vars(r)["titleID"])
+	if titleID == "" {
+		writeServiceError(w, errors.New("title id required"), http.StatusBadRequest)
+		return
+	}
+	removed, err := h.metadataService.InvalidateCachedTitle(titleID)
+	if err != nil {
+		writeServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+	log.Printf("[admin] metadata cache entries for title %q invalidated by user request (%d entries)", titleID, removed)
+	json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "removed": removed})
+}
+
 // GetCacheManagerStatus returns the current status of the background metadata cache manager.
 func (h *AdminUIHandler) GetCacheManagerStatus(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -7625,6 +8045,39 @@ func (h *AdminUIHandler) RefreshTopTenWorker(w http.ResponseWriter, r *http.Requ
 	json.NewEncoder(w).Encode(map[string]string{"status": "ok", "message": "Refresh started"})
 }
 
+// GetEnrichmentFailures returns items whose metadata enrichment failed, with
+// the failure reason and retry schedule.
+func (h *AdminUIHandler) GetEnrichmentFailures(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if h.metadataService == nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "metadata service not available"})
+		return
+	}
+	failures := h.metadataService.EnrichmentFailures()
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"failures": failures,
+		"total":    len(failures),
+	})
+}
+
+// RetryEnrichmentFailures retries every failed enrichment immediately,
+// ignoring the backoff schedule.
+func (h *AdminUIHandler) RetryEnrichmentFailures(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if h.metadataService == nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "metadata service not available"})
+		return
+	}
+	result, err := h.metadataService.RetryEnrichmentFailures(r.Context())
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError)
+		return
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
 // GetCalendarWorkerStatus returns the current status of the calendar background worker.
 func (h *AdminUIHandler) GetCalendarWorkerStatus(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -7986,6 +8439,10 @@ func normalizeAdminAIProvider(provider string) string {
 		return "nanogpt"
 	case "linkapi", "link-api", "link_api":
 		return "linkapi"
+	case "ollama":
+		return "ollama"
+	case "openai-compatible", "openai_compatible", "openaicompatible", "custom":
+		return "openai-compatible"
 	default:
 		return strings.ToLower(strings.TrimSpace(provider))
 	}
@@ -8034,6 +8491,21 @@ func buildAIValidationRequest(provider, apiKey, baseURL string) (string, *http.R
 		}
 		endpoint = baseURL + "/models"
 		headers["Authorization"] = "Bearer " + apiKey
+	case "ollama":
+		providerName = "Ollama"
+		if baseURL == "" {
+			baseURL = "http://localhost:11434"
+		}
+		endpoint = baseURL + "/api/tags"
+		if apiKey != "" {
+			headers["Authorization"] = "Bearer " + apiKey
+		}
+	case "openai-compatible":
+		providerName = "OpenAI-compatible"
+		endpoint = baseURL + "/models"
+		if apiKey != "" {
+			headers["Authorization"] = "Bearer " + apiKey
+		}
 	default:
 		providerName = "Gemini"
 		endpoint = "https://generativelanguage.googleapis.com/v1beta/models?key=" + apiKey
@@ -8061,7 +8533,11 @@ func (h *AdminUIHandler) TestMetadata(w http.ResponseWriter, r *http.Request) {
 		req.AIApiKey = req.GeminiApiKey
 	}
 
-	if req.TVDBApiKey == "" && req.TMDBApiKey == "" && req.AIApiKey == "" {
+	// Ollama and custom OpenAI-compatible endpoints can run without a key.
+	aiProvider := normalizeAdminAIProvider(req.AIProvider)
+	aiKeyless := aiProvider == "ollama" || (aiProvider == "openai-compatible" && strings.TrimSpace(req.AIBaseURL) != "")
+
+	if req.TVDBApiKey == "" && req.TMDBApiKey == "" && req.AIApiKey == "" && !aiKeyless {
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"success": false,
 			"error":   "No API keys configured",
@@ -8123,8 +8599,7 @@ func (h *AdminUIHandler) TestMetadata(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Test AI provider key
-	if req.AIApiKey != "" {
-		aiProvider := normalizeAdminAIProvider(req.AIProvider)
+	if req.AIApiKey != "" || aiKeyless {
 		providerName, aiReq := buildAIValidationRequest(aiProvider, req.AIApiKey, req.AIBaseURL)
 		resp, err := client.Do(aiReq)
 		if err != nil {
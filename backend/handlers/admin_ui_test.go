@@ -33,6 +33,22 @@ func (m *mockMetadataService) ClearCache() error {
 	return nil
 }
 
+func (m *mockMetadataService) CacheNamespaces() ([]metadata.CacheNamespaceSummary, error) {
+	return nil, nil
+}
+
+func (m *mockMetadataService) CacheEntries(namespace string, limit int) ([]metadata.CacheEntryInfo, error) {
+	return nil, nil
+}
+
+func (m *mockMetadataService) InvalidateCacheNamespace(namespace string) (int, error) {
+	return 0, nil
+}
+
+func (m *mockMetadataService) InvalidateCachedTitle(id string) (int, error) {
+	return 0, nil
+}
+
 func (m *mockMetadataService) MovieDetails(ctx context.Context, req models.MovieDetailsQuery) (*models.Title, error) {
 	return &models.Title{}, nil
 }
@@ -59,6 +75,14 @@ func (m *mockMetadataService) GetTopTenWorkerStatus() metadata.TopTenWorkerStatu
 func (m *mockMetadataService) TriggerTopTenRefresh() {
 }
 
+func (m *mockMetadataService) EnrichmentFailures() []metadata.EnrichmentFailure {
+	return nil
+}
+
+func (m *mockMetadataService) RetryEnrichmentFailures(ctx context.Context) (metadata.EnrichmentRetryResult, error) {
+	return metadata.EnrichmentRetryResult{}, nil
+}
+
 // setupAdminUIHandler creates an AdminUIHandler with all required dependencies for testing
 func setupAdminUIHandler(t *testing.T) (*handlers.AdminUIHandler, string) {
 	t.Helper()
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"novastream/models"
+)
+
+// artworkProxySizes picks the proxy variant served for each artwork type.
+// Types not listed are served at their original size.
+var artworkProxySizes = map[string]string{
+	"poster":   "w342",
+	"backdrop": "w780",
+	"banner":   "w780",
+	"still":    "w780",
+	"logo":     "w780",
+}
+
+var imageType = reflect.TypeOf(models.Image{})
+
+// proxyArtwork rewrites the TMDB/TVDB artwork of a metadata response to the
+// image proxy when metadata.proxyArtwork is enabled. The payload is copied
+// first: services return cached values that must keep their upstream URLs.
+func (h *MetadataHandler) proxyArtwork(r *http.Request, payload any) any {
+	if h.CfgManager == nil {
+		return payload
+	}
+	settings, err := h.CfgManager.Load()
+	if err != nil || !settings.Metadata.ProxyArtwork {
+		return payload
+	}
+	return rewriteArtworkURLs(payload, requestBaseURL(r))
+}
+
+// rewriteArtworkURLs returns a copy of payload with every models.Image URL
+// on a proxyable host pointing at baseURL's image proxy. The payload is
+// returned unchanged if it cannot be copied.
+func rewriteArtworkURLs(payload any, baseURL string) any {
+	copied, ok := copyPayload(payload)
+	if !ok {
+		return payload
+	}
+	walkArtwork(copied, func(img *models.Image) {
+		img.URL = artworkProxyURL(baseURL, img.URL, artworkProxySizes[img.Type])
+	})
+	return copied.Interface()
+}
+
+// copyPayload returns an addressable deep copy of payload made through its
+// JSON form, so response rewrites never touch cached values.
+func copyPayload(payload any) (reflect.Value, bool) {
+	if payload == nil {
+		return reflect.Value{}, false
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return reflect.Value{}, false
+	}
+	copied := reflect.New(reflect.TypeOf(payload))
+	if err := json.Unmarshal(body, copied.Interface()); err != nil {
+		return reflect.Value{}, false
+	}
+	return copied.Elem(), true
+}
+
+// walkArtwork calls fn for every models.Image reachable from v.
+func walkArtwork(v reflect.Value, fn func(*models.Image)) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			walkArtwork(v.Elem(), fn)
+		}
+	case reflect.Struct:
+		if v.Type() == imageType {
+			if v.CanAddr() {
+				fn(v.Addr().Interface().(*models.Image))
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				walkArtwork(v.Field(i), fn)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkArtwork(v.Index(i), fn)
+		}
+	case reflect.Map:
+		// Map values are not addressable: rewrite a copy and store it back.
+		iter := v.MapRange()
+		for iter.Next() {
+			value := reflect.New(iter.Value().Type()).Elem()
+			value.Set(iter.Value())
+			walkArtwork(value, fn)
+			v.SetMapIndex(iter.Key(), value)
+		}
+	}
+}
+
+// artworkProxyURL returns the image proxy URL serving src at the named
+// size. URLs the proxy does not accept are returned unchanged.
+func artworkProxyURL(baseURL, src, size string) string {
+	if src == "" || validateProxyImageURL(src) != nil {
+		return src
+	}
+	if size == "" {
+		size = "original"
+	}
+	query := url.Values{}
+	query.Set("url", src)
+	query.Set("size", size)
+	return baseURL + "/api/images/proxy?" + query.Encode()
+}
+
+// requestBaseURL returns the scheme and host clients used to reach the
+// server, honouring reverse proxy headers.
+func requestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if forwarded := strings.TrimSpace(r.Header.Get("X-Forwarded-Proto")); forwarded != "" {
+		scheme = strings.TrimSpace(strings.Split(forwarded, ",")[0])
+	}
+	host := strings.TrimSpace(r.Header.Get("X-Forwarded-Host"))
+	if host == "" {
+		host = r.Host
+	}
+	return scheme + "://" + host
+}
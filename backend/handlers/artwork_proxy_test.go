@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"novastream/models"
+)
+
+func TestRewriteArtworkURLsProxiesCopy(t *testing.T) {
+	poster := "https://image.tmdb.org/t/p/w500/poster.jpg"
+	title := &models.Title{
+		Poster:    &models.Image{URL: poster, Type: "poster"},
+		Backdrops: []models.Image{{URL: "https://artworks.thetvdb.com/banners/fanart.jpg", Type: "backdrop"}},
+		Logo:      &models.Image{URL: "https://assets.fanart.tv/logo.png", Type: "logo"},
+	}
+	payload := map[string][]*models.Title{"items": {title}}
+
+	got := rewriteArtworkURLs(payload, "https://media.example.com").(map[string][]*models.Title)
+	rewritten := got["items"][0]
+
+	proxied, err := url.Parse(rewritten.Poster.URL)
+	if err != nil || proxied.Host != "media.example.com" || proxied.Path != "/api/images/proxy" {
+		t.Fatalf("poster URL = %q, want image proxy", rewritten.Poster.URL)
+	}
+	if proxied.Query().Get("url") != poster || proxied.Query().Get("size") != "w342" {
+		t.Fatalf("poster proxy query = %v", proxied.Query())
+	}
+	if !strings.Contains(rewritten.Backdrops[0].URL, "size=w780") {
+		t.Fatalf("backdrop URL = %q, want w780 variant", rewritten.Backdrops[0].URL)
+	}
+	if rewritten.Logo.URL != "https://assets.fanart.tv/logo.png" {
+		t.Fatalf("non-proxyable logo was rewritten: %q", rewritten.Logo.URL)
+	}
+	if title.Poster.URL != poster {
+		t.Fatalf("original payload was modified: %q", title.Poster.URL)
+	}
+}
+
+func TestRequestBaseURLHonoursForwardedHeaders(t *testing.T) {
+	r := httptest.NewRequest("GET", "http://10.0.0.2:7777/api/discover/new", nil)
+	if got := requestBaseURL(r); got != "http://10.0.0.2:7777" {
+		t.Fatalf("base URL = %q", got)
+	}
+	r.Header.Set("X-Forwarded-Proto", "https, http")
+	r.Header.Set("X-Forwarded-Host", "media.example.com")
+	if got := requestBaseURL(r); got != "https://media.example.com" {
+		t.Fatalf("forwarded base URL = %q", got)
+	}
+}
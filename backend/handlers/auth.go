@@ -4,8 +4,13 @@ import (
 	"encoding/json"
 	"errors"
 	"log"
+	"net"
 	"net/http"
+	"net/netip"
+	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"novastream/models"
 	"novastream/services/accounts"
@@ -73,22 +78,34 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 	log.Printf("[auth] login payload username_len=%d rememberMe=%t", len(strings.TrimSpace(req.Username)), req.RememberMe)
 
-	account, err := h.accounts.Authenticate(req.Username, req.Password)
+	userAgent := r.Header.Get("User-Agent")
+	ipAddress := getClientIPAddress(r)
+	login := h.accounts.Login
+	if forwardedByUntrustedProxy(r) {
+		log.Printf("[auth] login forwarded by untrusted proxy %s; add it to server.trustedProxies to throttle by client address", ipAddress)
+		login = h.accounts.LoginFromSharedAddress
+	}
+	account, err := login(req.Username, req.Password, ipAddress, userAgent)
 	if err != nil {
-		log.Printf("[auth] login authentication failed user=%q ip=%s err=%v", strings.TrimSpace(req.Username), getClientIPAddress(r), err)
+		log.Printf("[auth] login authentication failed user=%q ip=%s err=%v", strings.TrimSpace(req.Username), ipAddress, err)
+		w.Header().Set("Content-Type", "application/json")
+		var locked *accounts.LockedOutError
+		if errors.As(err, &locked) {
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(locked.Until)))
+			w.WriteHeader(http.StatusTooManyRequests)
+			json.NewEncoder(w).Encode(map[string]string{"error": "too many failed login attempts, try again later"})
+			return
+		}
 		msg := "invalid username or password"
 		if errors.Is(err, accounts.ErrAccountExpired) {
 			msg = "this account has expired"
 		}
-		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusUnauthorized)
 		json.NewEncoder(w).Encode(map[string]string{"error": msg})
 		return
 	}
 
 	// Create session
-	userAgent := r.Header.Get("User-Agent")
-	ipAddress := getClientIPAddress(r)
 	var session models.Session
 	if req.RememberMe {
 		session, err = h.sessions.CreatePersistent(account.ID, account.IsMaster, userAgent, ipAddress)
@@ -272,28 +289,102 @@ func extractBearerToken(r *http.Request) string {
 	return strings.TrimSpace(parts[1])
 }
 
-// getClientIPAddress extracts the client IP address from the request.
+// trustedProxies holds the reverse proxies whose forwarding headers are
+// believed. It is empty until SetTrustedProxies is called.
+var trustedProxies atomic.Pointer[[]netip.Prefix]
+
+// SetTrustedProxies sets the reverse proxy addresses, as IPs or CIDRs, whose
+// X-Forwarded-For and X-Real-IP headers identify the client. Invalid entries
+// are logged and skipped.
+func SetTrustedProxies(entries []string) {
+	prefixes := make([]netip.Prefix, 0, len(entries))
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if prefix, err := netip.ParsePrefix(entry); err == nil {
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		log.Printf("[auth] ignoring invalid trusted proxy %q", entry)
+	}
+	trustedProxies.Store(&prefixes)
+}
+
+func isTrustedProxy(ip string) bool {
+	prefixes := trustedProxies.Load()
+	if prefixes == nil {
+		return false
+	}
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range *prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// getClientIPAddress returns the address the request came from. Forwarding
+// headers are only honoured when the connection comes from a trusted proxy;
+// the X-Forwarded-For chain is then read right to left, skipping further
+// trusted hops, so a client cannot choose the address it is throttled under.
 func getClientIPAddress(r *http.Request) string {
-	// Check X-Forwarded-For header first
-	xff := r.Header.Get("X-Forwarded-For")
-	if xff != "" {
-		// Take the first IP in the chain
-		if idx := strings.Index(xff, ","); idx != -1 {
-			return strings.TrimSpace(xff[:idx])
+	remote, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remote = r.RemoteAddr
+	}
+	if !isTrustedProxy(remote) {
+		return remote
+	}
+
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		hops := strings.Split(xff, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if _, err := netip.ParseAddr(hop); err != nil {
+				break
+			}
+			if !isTrustedProxy(hop) || i == 0 {
+				return hop
+			}
 		}
-		return strings.TrimSpace(xff)
 	}
 
-	// Check X-Real-IP header
-	xri := r.Header.Get("X-Real-IP")
-	if xri != "" {
-		return strings.TrimSpace(xri)
+	if xri := strings.TrimSpace(r.Header.Get("X-Real-IP")); xri != "" {
+		if _, err := netip.ParseAddr(xri); err == nil {
+			return xri
+		}
 	}
+	return remote
+}
 
-	// Fall back to RemoteAddr
-	addr := r.RemoteAddr
-	if idx := strings.LastIndex(addr, ":"); idx != -1 {
-		return addr[:idx]
+// forwardedByUntrustedProxy reports whether the request carries forwarding
+// headers from a peer that is not a trusted proxy. Such a peer is most likely
+// a reverse proxy fronting many clients, so its address must not be throttled
+// as a single client.
+func forwardedByUntrustedProxy(r *http.Request) bool {
+	if r.Header.Get("X-Forwarded-For") == "" && r.Header.Get("X-Real-IP") == "" {
+		return false
 	}
-	return addr
+	remote, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remote = r.RemoteAddr
+	}
+	return !isTrustedProxy(remote)
+}
+
+// retryAfterSeconds converts a lockout end into a Retry-After value.
+func retryAfterSeconds(until time.Time) int {
+	return max(1, int(time.Until(until).Seconds()+0.5))
 }
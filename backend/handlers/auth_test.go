@@ -3,6 +3,7 @@ package handlers_test
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -203,6 +204,7 @@ func TestLogin_WithRememberMe(t *testing.T) {
 
 func TestLogin_CapturesMetadata(t *testing.T) {
 	handler, _, sessionsSvc := setupAuthHandler(t)
+	trustProxies(t, "192.0.2.1")
 
 	reqBody := handlers.LoginRequest{
 		Username: "admin",
@@ -573,8 +575,17 @@ func TestExtractBearerToken_NoPrefix(t *testing.T) {
 	}
 }
 
+// trustProxies configures trusted proxies for the duration of the test.
+// httptest requests arrive from 192.0.2.1.
+func trustProxies(t *testing.T, entries ...string) {
+	t.Helper()
+	handlers.SetTrustedProxies(entries)
+	t.Cleanup(func() { handlers.SetTrustedProxies(nil) })
+}
+
 func TestGetClientIPAddress_XForwardedFor(t *testing.T) {
-	handler, _, _ := setupAuthHandler(t)
+	handler, _, sessionsSvc := setupAuthHandler(t)
+	trustProxies(t, "192.0.2.0/24", "192.168.1.1")
 
 	reqBody := handlers.LoginRequest{
 		Username: "admin",
@@ -588,12 +599,87 @@ func TestGetClientIPAddress_XForwardedFor(t *testing.T) {
 
 	handler.Login(rec, req)
 
-	// The first IP in X-Forwarded-For should be used
-	// We can verify this through the session metadata
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	var resp handlers.LoginResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+
+	// Trusted hops are skipped from the right; the first untrusted one is
+	// the client.
+	session, _ := sessionsSvc.Validate(resp.Token)
+	if session.IPAddress != "10.0.0.1" {
+		t.Errorf("expected IP '10.0.0.1', got %q", session.IPAddress)
+	}
+}
+
+func TestGetClientIPAddress_IgnoresHeadersFromUntrustedPeers(t *testing.T) {
+	handler, _, sessionsSvc := setupAuthHandler(t)
+
+	reqBody := handlers.LoginRequest{
+		Username: "admin",
+		Password: "admin",
+	}
+	body, _ := json.Marshal(reqBody)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/login", bytes.NewReader(body))
+	req.RemoteAddr = "203.0.113.7:40000"
+	req.Header.Set("X-Forwarded-For", "10.0.0.1")
+	req.Header.Set("X-Real-IP", "10.0.0.2")
+	rec := httptest.NewRecorder()
+
+	handler.Login(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	var resp handlers.LoginResponse
+	json.Unmarshal(rec.Body.Bytes(), &resp)
+
+	session, _ := sessionsSvc.Validate(resp.Token)
+	if session.IPAddress != "203.0.113.7" {
+		t.Errorf("expected spoofed headers to be ignored, got %q", session.IPAddress)
+	}
+}
+
+func TestLogin_UntrustedProxyAddressIsNotLockedOut(t *testing.T) {
+	handler, _, _ := setupAuthHandler(t)
+
+	login := func(username, password string) int {
+		body, _ := json.Marshal(handlers.LoginRequest{Username: username, Password: password})
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/login", bytes.NewReader(body))
+		req.RemoteAddr = "172.18.0.2:40000"
+		req.Header.Set("X-Forwarded-For", "10.0.0.1")
+		rec := httptest.NewRecorder()
+		handler.Login(rec, req)
+		return rec.Code
+	}
+
+	// Every client behind an untrusted proxy shares its address, so failures
+	// across many usernames must not lock the address out.
+	for i := 0; i < 25; i++ {
+		if code := login(fmt.Sprintf("user%d", i), "wrong"); code != http.StatusUnauthorized {
+			t.Fatalf("attempt %d: expected 401, got %d", i+1, code)
+		}
+	}
+	if code := login("admin", "admin"); code != http.StatusOK {
+		t.Fatalf("expected login through the proxy to succeed, got %d", code)
+	}
+
+	// Repeated failures against one username are still throttled.
+	for i := 0; i < 5; i++ {
+		login("admin", "wrong")
+	}
+	if code := login("admin", "admin"); code != http.StatusTooManyRequests {
+		t.Fatalf("expected the username to be locked out, got %d", code)
+	}
 }
 
 func TestGetClientIPAddress_XRealIP(t *testing.T) {
 	handler, _, sessionsSvc := setupAuthHandler(t)
+	trustProxies(t, "192.0.2.1")
 
 	reqBody := handlers.LoginRequest{
 		Username: "admin",
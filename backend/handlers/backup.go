@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -12,6 +13,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"novastream/services/backup"
+	"novastream/services/storage"
 )
 
 // BackupHandler handles backup API endpoints
@@ -50,8 +52,12 @@ func (h *BackupHandler) ListBackups(w http.ResponseWriter, r *http.Request) {
 func (h *BackupHandler) CreateBackup(w http.ResponseWriter, r *http.Request) {
 	info, err := h.backupService.CreateBackup(backup.BackupTypeManual)
 	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, storage.ErrLowDiskSpace) {
+			status = http.StatusInsufficientStorage
+		}
 		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
+		w.WriteHeader(status)
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"error": "Failed to create backup: " + err.Error(),
 		})
@@ -1,24 +1,53 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
+	"net/url"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"novastream/models"
 	"novastream/services/calendar"
+	"novastream/services/users"
 
 	"github.com/gorilla/mux"
 )
 
+// calendarWatchProviderService resolves where a series season streams in a region.
+type calendarWatchProviderService interface {
+	EpisodeWatchProviders(ctx context.Context, tmdbID int64, seasonNumber int, region string) (*models.WatchProviderAvailability, error)
+	// WatchProviderRegion is the server's default region.
+	WatchProviderRegion() string
+}
+
+// calendarFeedTokenService issues and checks the per-profile tokens that
+// calendar apps and feed readers subscribe with.
+type calendarFeedTokenService interface {
+	RotateCalendarFeedToken(id string) (string, error)
+	RevokeCalendarFeedToken(id string) error
+	ValidCalendarFeedToken(id, token string) bool
+}
+
+// calendarWatchProviderLimit caps provider lookups per request so a large
+// calendar can't fan out into hundreds of TMDB calls on a cold cache.
+const calendarWatchProviderLimit = 40
+
 // CalendarHandler serves the calendar API endpoint.
 type CalendarHandler struct {
-	Service  *calendar.Service
-	Users    userService
-	DemoMode bool
+	Service        *calendar.Service
+	Users          userService
+	DemoMode       bool
+	WatchProviders calendarWatchProviderService
+	FeedTokens     calendarFeedTokenService
+	UserSettings   userSettingsProvider
+	serverBasePath string
 }
 
 // NewCalendarHandler creates a new CalendarHandler.
@@ -30,6 +59,30 @@ func NewCalendarHandler(service *calendar.Service, users userService, demoMode b
 	}
 }
 
+// SetWatchProviderService enables per-episode watch provider enrichment.
+func (h *CalendarHandler) SetWatchProviderService(svc calendarWatchProviderService) {
+	h.WatchProviders = svc
+}
+
+// SetUserSettingsService lets the profile's region pick the watch providers
+// shown when the client doesn't pass one.
+func (h *CalendarHandler) SetUserSettingsService(svc userSettingsProvider) {
+	h.UserSettings = svc
+}
+
+// SetFeedTokenService enables the subscribable iCal and release feeds, which
+// authenticate with a per-profile feed token instead of a session.
+// serverBasePath prefixes the feed paths it hands out, as with list share
+// links.
+func (h *CalendarHandler) SetFeedTokenService(svc calendarFeedTokenService, serverBasePath string) {
+	serverBasePath = "/" + strings.Trim(serverBasePath, "/")
+	if serverBasePath == "/" {
+		serverBasePath = ""
+	}
+	h.FeedTokens = svc
+	h.serverBasePath = serverBasePath
+}
+
 // GetCalendar returns upcoming content for the user, adjusted to the requested timezone.
 func (h *CalendarHandler) GetCalendar(w http.ResponseWriter, r *http.Request) {
 	userID, ok := h.requireUser(w, r)
@@ -152,6 +205,9 @@ func (h *CalendarHandler) GetCalendar(w http.ResponseWriter, r *http.Request) {
 	if home {
 		result = calendar.LimitForHomeShelf(result, loc, homeLimit)
 	}
+	if region := h.watchProviderRegion(userID, r.URL.Query().Get("region")); region != "" {
+		h.enrichWatchProviders(r.Context(), result, region)
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(models.CalendarResponse{
@@ -164,6 +220,194 @@ func (h *CalendarHandler) GetCalendar(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// calendarICSSources are the sources included in the iCal feed by default:
+// titles the profile follows, not trending or list suggestions.
+var calendarICSSources = map[string]bool{"watchlist": true, "history": true}
+
+// GetCalendarICS exports upcoming episodes and movie releases for the
+// profile's watchlist and shows in progress as an iCalendar feed that
+// calendar apps can subscribe to, authenticating with the profile's feed
+// token in ?token=.
+// ?source= picks a single source, or "all" for every source; ?days= sets how
+// far ahead to include (default 90).
+func (h *CalendarHandler) GetCalendarICS(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireFeedToken(w, r)
+	if !ok {
+		return
+	}
+
+	days := 90
+	if parsed, err := strconv.Atoi(r.URL.Query().Get("days")); err == nil && parsed > 0 && parsed < days {
+		days = parsed
+	}
+	sourceFilter := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("source")))
+
+	now := time.Now().UTC()
+	var items []models.CalendarItem
+	if cached := h.Service.Get(userID); cached != nil {
+		from := now.AddDate(0, 0, -calendar.RecentDaysWindow)
+		to := now.AddDate(0, 0, days)
+		for _, item := range cached.Items {
+			switch sourceFilter {
+			case "all":
+			case "":
+				if !calendarICSSources[item.Source] {
+					continue
+				}
+			default:
+				if item.Source != sourceFilter {
+					continue
+				}
+			}
+			at := calendar.ParseAirDateTime(item.AirDate, item.AirTime, item.AirTimezone)
+			if at.IsZero() || at.Before(from) || at.After(to) {
+				continue
+			}
+			items = append(items, item)
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="mediastorm.ics"`)
+	w.Header().Set("Cache-Control", "private, max-age=900")
+	w.Write(calendar.ICS(items, "mediastorm", now))
+}
+
+// GetReleaseFeed publishes movies that just got a home release and series or
+// season premieres from the profile's watchlist and custom lists over the
+// last calendar.ReleaseFeedDays days, for feed readers and automation
+// (authenticating with the profile's feed token in ?token=). Served as RSS 2.0 at releases.rss and JSON
+// Feed 1.1 at releases.json. Profiles must opt in via calendar.releaseFeed.
+func (h *CalendarHandler) GetReleaseFeed(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireFeedToken(w, r)
+	if !ok {
+		return
+	}
+	if !h.Service.ReleaseFeedEnabled(userID) {
+		http.Error(w, "release feed is not enabled for this profile", http.StatusNotFound)
+		return
+	}
+
+	now := time.Now().UTC()
+	var items []models.CalendarItem
+	if cached := h.Service.Get(userID); cached != nil {
+		items = calendar.NewReleases(cached.Items, now.AddDate(0, 0, -calendar.ReleaseFeedDays), now)
+	}
+
+	// The self link leaves out the query so the feed doesn't echo the token.
+	scheme := "http"
+	if r.TLS != nil || strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+	feedURL := scheme + "://" + r.Host + r.URL.Path
+
+	var (
+		data        []byte
+		err         error
+		contentType string
+	)
+	if strings.HasSuffix(r.URL.Path, ".json") {
+		data, err = calendar.JSONFeed(items, "mediastorm new releases", feedURL)
+		contentType = "application/feed+json; charset=utf-8"
+	} else {
+		data, err = calendar.RSS(items, "mediastorm new releases", feedURL, now)
+		contentType = "application/rss+xml; charset=utf-8"
+	}
+	if err != nil {
+		http.Error(w, "failed to render release feed", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Cache-Control", "private, max-age=900")
+	w.Write(data)
+}
+
+// watchProviderRegion picks the region for watch provider enrichment: the
+// requested one, then the profile's certification country, then the server
+// default. It returns "" when enrichment is unavailable.
+func (h *CalendarHandler) watchProviderRegion(userID, requested string) string {
+	if h.WatchProviders == nil {
+		return ""
+	}
+	if region := strings.TrimSpace(requested); region != "" {
+		return strings.ToUpper(region)
+	}
+	if h.UserSettings != nil && userID != "" {
+		if settings, err := h.UserSettings.Get(userID); err == nil && settings != nil {
+			if region := strings.TrimSpace(settings.Metadata.CertificationCountry); region != "" {
+				return strings.ToUpper(region)
+			}
+		}
+	}
+	return h.WatchProviders.WatchProviderRegion()
+}
+
+// enrichWatchProviders attaches regional watch providers to series episodes so
+// clients can say "now on Hulu". Lookups are deduped per series season.
+func (h *CalendarHandler) enrichWatchProviders(ctx context.Context, items []models.CalendarItem, region string) {
+	if h.WatchProviders == nil {
+		return
+	}
+
+	type seasonKey struct {
+		tmdbID int64
+		season int
+	}
+	indexes := make(map[seasonKey][]int)
+	var order []seasonKey
+	for i, item := range items {
+		if item.MediaType != "series" || item.SeasonNumber <= 0 {
+			continue
+		}
+		tmdbID, err := strconv.ParseInt(item.ExternalIDs["tmdb"], 10, 64)
+		if err != nil || tmdbID <= 0 {
+			continue
+		}
+		key := seasonKey{tmdbID: tmdbID, season: item.SeasonNumber}
+		if _, ok := indexes[key]; !ok {
+			if len(order) >= calendarWatchProviderLimit {
+				continue
+			}
+			order = append(order, key)
+		}
+		indexes[key] = append(indexes[key], i)
+	}
+	if len(order) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	results := make([]*models.WatchProviderAvailability, len(order))
+	sem := make(chan struct{}, 4)
+	var wg sync.WaitGroup
+	for i, key := range order {
+		wg.Add(1)
+		go func(i int, key seasonKey) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			availability, err := h.WatchProviders.EpisodeWatchProviders(ctx, key.tmdbID, key.season, region)
+			if err != nil {
+				log.Printf("[calendar] watch providers lookup failed tmdbId=%d season=%d: %v", key.tmdbID, key.season, err)
+				return
+			}
+			results[i] = availability
+		}(i, key)
+	}
+	wg.Wait()
+
+	for i, key := range order {
+		if results[i] == nil {
+			continue
+		}
+		for _, idx := range indexes[key] {
+			items[idx].WatchProviders = results[i]
+		}
+	}
+}
+
 func parseCalendarBoolQuery(value string) bool {
 	switch strings.ToLower(strings.TrimSpace(value)) {
 	case "1", "true", "yes", "on":
@@ -190,6 +434,73 @@ func (h *CalendarHandler) Options(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// CreateFeedToken issues a new feed token for the profile, replacing the
+// previous one, and returns it with the feed paths to subscribe to. The
+// token is only shown here; a lost token is replaced by issuing another.
+func (h *CalendarHandler) CreateFeedToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+	if h.FeedTokens == nil {
+		writeJSONError(w, "calendar feeds are not available", http.StatusNotImplemented)
+		return
+	}
+	token, err := h.FeedTokens.RotateCalendarFeedToken(userID)
+	if err != nil {
+		writeCalendarFeedTokenError(w, err)
+		return
+	}
+
+	query := "?token=" + url.QueryEscape(token)
+	base := h.serverBasePath + "/api/users/" + url.PathEscape(userID)
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(map[string]string{
+		"token":        token,
+		"calendarPath": base + "/calendar.ics" + query,
+		"rssPath":      base + "/releases.rss" + query,
+		"jsonFeedPath": base + "/releases.json" + query,
+	})
+}
+
+// RevokeFeedToken stops the profile's feed token from working.
+func (h *CalendarHandler) RevokeFeedToken(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+	if h.FeedTokens == nil {
+		writeJSONError(w, "calendar feeds are not available", http.StatusNotImplemented)
+		return
+	}
+	if err := h.FeedTokens.RevokeCalendarFeedToken(userID); err != nil {
+		writeCalendarFeedTokenError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeCalendarFeedTokenError(w http.ResponseWriter, err error) {
+	if errors.Is(err, users.ErrUserNotFound) {
+		writeJSONError(w, "user not found", http.StatusNotFound)
+		return
+	}
+	writeJSONError(w, err.Error(), http.StatusInternalServerError)
+}
+
+// requireFeedToken authorizes a feed request by the profile's feed token in
+// ?token=. Session tokens are not accepted, so subscription URLs stored by
+// calendar providers never carry account credentials.
+func (h *CalendarHandler) requireFeedToken(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID := strings.TrimSpace(mux.Vars(r)["userID"])
+	if userID == "" || h.FeedTokens == nil || !h.FeedTokens.ValidCalendarFeedToken(userID, r.URL.Query().Get("token")) {
+		http.Error(w, "invalid or missing feed token", http.StatusUnauthorized)
+		return "", false
+	}
+	return userID, true
+}
+
 func (h *CalendarHandler) requireUser(w http.ResponseWriter, r *http.Request) (string, bool) {
 	vars := mux.Vars(r)
 	userID := strings.TrimSpace(vars["userID"])
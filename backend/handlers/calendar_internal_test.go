@@ -1,6 +1,9 @@
 package handlers
 
 import (
+	"context"
+	"fmt"
+	"sync"
 	"testing"
 	"time"
 
@@ -50,3 +53,77 @@ func TestLimitCalendarForHomePreservesRecentItemsPerSource(t *testing.T) {
 		t.Fatalf("expected 5 recent trending items to survive source limiting, got %d", trendingCount)
 	}
 }
+
+type fakeCalendarWatchProviders struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (f *fakeCalendarWatchProviders) EpisodeWatchProviders(_ context.Context, tmdbID int64, season int, region string) (*models.WatchProviderAvailability, error) {
+	f.mu.Lock()
+	f.calls[fmt.Sprintf("%d:%d", tmdbID, season)]++
+	f.mu.Unlock()
+	if tmdbID != 1399 {
+		return nil, nil
+	}
+	return &models.WatchProviderAvailability{
+		Region:    region,
+		Providers: []models.WatchProvider{{ID: 15, Name: "Hulu", Type: "flatrate"}},
+	}, nil
+}
+
+func (f *fakeCalendarWatchProviders) WatchProviderRegion() string { return "GB" }
+
+func TestCalendarWatchProviderRegion(t *testing.T) {
+	h := &CalendarHandler{}
+	if got := h.watchProviderRegion("user1", "us"); got != "" {
+		t.Fatalf("expected no region without a watch provider service, got %q", got)
+	}
+
+	h.SetWatchProviderService(&fakeCalendarWatchProviders{calls: make(map[string]int)})
+	h.SetUserSettingsService(&mockUserSettingsProvider{settings: map[string]*models.UserSettings{
+		"user1": {Metadata: models.MetadataSettings{CertificationCountry: "de"}},
+	}})
+	cases := []struct {
+		userID, requested, want string
+	}{
+		{"user1", "us", "US"},
+		{"user1", "", "DE"},
+		{"user2", "", "GB"},
+	}
+	for _, tc := range cases {
+		if got := h.watchProviderRegion(tc.userID, tc.requested); got != tc.want {
+			t.Errorf("watchProviderRegion(%q, %q) = %q, want %q", tc.userID, tc.requested, got, tc.want)
+		}
+	}
+}
+
+func TestEnrichWatchProvidersDedupesSeasonLookups(t *testing.T) {
+	fake := &fakeCalendarWatchProviders{calls: make(map[string]int)}
+	h := &CalendarHandler{}
+	h.SetWatchProviderService(fake)
+
+	items := []models.CalendarItem{
+		{MediaType: "series", SeasonNumber: 2, EpisodeNumber: 1, ExternalIDs: map[string]string{"tmdb": "1399"}},
+		{MediaType: "series", SeasonNumber: 2, EpisodeNumber: 2, ExternalIDs: map[string]string{"tmdb": "1399"}},
+		{MediaType: "series", SeasonNumber: 1, EpisodeNumber: 1, ExternalIDs: map[string]string{"tmdb": "42"}},
+		{MediaType: "movie", ExternalIDs: map[string]string{"tmdb": "603"}},
+	}
+	h.enrichWatchProviders(context.Background(), items, "US")
+
+	if got := fake.calls["1399:2"]; got != 1 {
+		t.Fatalf("expected one lookup for tmdb 1399 season 2, got %d", got)
+	}
+	if _, ok := fake.calls["603:0"]; ok {
+		t.Fatalf("movies should not be enriched")
+	}
+	for i := 0; i < 2; i++ {
+		wp := items[i].WatchProviders
+		if wp == nil || len(wp.Providers) != 1 || wp.Providers[0].Name != "Hulu" {
+			t.Fatalf("item %d: expected Hulu provider, got %+v", i, wp)
+		}
+	}
+	if items[2].WatchProviders != nil {
+		t.Fatalf("expected no providers for series without availability, got %+v", items[2].WatchProviders)
+	}
+}
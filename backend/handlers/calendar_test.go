@@ -31,6 +31,28 @@ func (m *mockCalendarUserService) Get(id string) (models.User, bool) {
 	return models.User{}, m.exists[id]
 }
 
+// mockFeedTokens accepts a single token per profile.
+type mockFeedTokens struct {
+	tokens map[string]string
+}
+
+func (m *mockFeedTokens) RotateCalendarFeedToken(id string) (string, error) {
+	if m.tokens == nil {
+		m.tokens = map[string]string{}
+	}
+	m.tokens[id] = "feed-" + id
+	return m.tokens[id], nil
+}
+
+func (m *mockFeedTokens) RevokeCalendarFeedToken(id string) error {
+	delete(m.tokens, id)
+	return nil
+}
+
+func (m *mockFeedTokens) ValidCalendarFeedToken(id, token string) bool {
+	return token != "" && m.tokens[id] == token
+}
+
 // --- Helpers ---
 
 func setupCalendarHandler(t *testing.T) (*handlers.CalendarHandler, *calendar.Service) {
@@ -575,3 +597,179 @@ func TestGetCalendarHomeLimitsCandidateWindow(t *testing.T) {
 		t.Fatalf("expected enough home candidates for shelf filtering, got %d", resp.Total)
 	}
 }
+
+func TestGetCalendarICS_ExportsWatchlistEpisodes(t *testing.T) {
+	futureDate := time.Now().AddDate(0, 0, 5).Format("2006-01-02")
+
+	meta := &calendarMockMetadataWithData{
+		series: map[int64]*models.SeriesDetails{
+			100: {
+				Title: models.Title{
+					Name: "Test Show", TVDBID: 100,
+					AirsTime: "21:00", AirsTimezone: "America/New_York",
+				},
+				Seasons: []models.SeriesSeason{
+					{Number: 1, Episodes: []models.SeriesEpisode{
+						{Name: "Ep 1", SeasonNumber: 1, EpisodeNumber: 1, AiredDate: futureDate},
+					}},
+				},
+			},
+		},
+	}
+	wl := &calendarMockWatchlistWithData{
+		items: []models.WatchlistItem{
+			{ID: "tvdb:100", MediaType: "series", Name: "Test Show", ExternalIDs: map[string]string{"tvdb": "100"}},
+		},
+	}
+
+	svc := calendar.New(
+		meta, wl,
+		&calendarMockHistory{},
+		&calendarMockUserSettings{},
+		&calendarMockUsers{},
+	)
+	svc.StartBackgroundRefresh(24 * time.Hour)
+	defer svc.Stop()
+	time.Sleep(200 * time.Millisecond)
+
+	usersSvc := &mockCalendarUserService{exists: map[string]bool{"user1": true}}
+	h := handlers.NewCalendarHandler(svc, usersSvc, false)
+	h.SetFeedTokenService(&mockFeedTokens{tokens: map[string]string{"user1": "feed-user1"}}, "")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users/user1/calendar.ics?token=feed-user1", nil)
+	req = mux.SetURLVars(req, map[string]string{"userID": "user1"})
+	rec := httptest.NewRecorder()
+
+	h.GetCalendarICS(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+		t.Fatalf("expected text/calendar content type, got %q", ct)
+	}
+
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	start, err := time.ParseInLocation("2006-01-02 15:04", futureDate+" 21:00", loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"SUMMARY:Test Show S01E01 - Ep 1\r\n",
+		"DTSTART:" + start.UTC().Format("20060102T150405Z") + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("feed missing %q:\n%s", want, body)
+		}
+	}
+
+	// Trending suggestions are left out unless asked for.
+	req = httptest.NewRequest(http.MethodGet, "/api/users/user1/calendar.ics?source=trending&token=feed-user1", nil)
+	req = mux.SetURLVars(req, map[string]string{"userID": "user1"})
+	rec = httptest.NewRecorder()
+	h.GetCalendarICS(rec, req)
+	if strings.Contains(rec.Body.String(), "BEGIN:VEVENT") {
+		t.Errorf("expected no events for trending source:\n%s", rec.Body.String())
+	}
+}
+
+func TestGetReleaseFeed_RequiresOptIn(t *testing.T) {
+	svc := calendar.New(
+		&calendarMockMetadataWithData{}, &calendarMockWatchlistWithData{},
+		&calendarMockHistory{},
+		&calendarMockUserSettings{},
+		&calendarMockUsers{},
+	)
+	usersSvc := &mockCalendarUserService{exists: map[string]bool{"user1": true}}
+	h := handlers.NewCalendarHandler(svc, usersSvc, false)
+	h.SetFeedTokenService(&mockFeedTokens{tokens: map[string]string{"user1": "feed-user1"}}, "")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users/user1/releases.rss?token=feed-user1", nil)
+	req = mux.SetURLVars(req, map[string]string{"userID": "user1"})
+	rec := httptest.NewRecorder()
+
+	h.GetReleaseFeed(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 when the feed is not enabled, got %d", rec.Code)
+	}
+}
+
+func TestCalendarFeeds_RequireFeedToken(t *testing.T) {
+	h, _ := setupCalendarHandler(t)
+	tokens := &mockFeedTokens{}
+	h.SetFeedTokenService(tokens, "")
+
+	do := func(method, target string, fn http.HandlerFunc) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		req = mux.SetURLVars(req, map[string]string{"userID": "user1"})
+		rec := httptest.NewRecorder()
+		fn(rec, req)
+		return rec
+	}
+
+	for _, target := range []string{
+		"/api/users/user1/calendar.ics",
+		"/api/users/user1/calendar.ics?token=session-token",
+	} {
+		if rec := do(http.MethodGet, target, h.GetCalendarICS); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("%s: expected 401, got %d", target, rec.Code)
+		}
+	}
+
+	rec := do(http.MethodPost, "/api/users/user1/calendar/feed-token", h.CreateFeedToken)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 creating a feed token, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var created map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if created["token"] == "" || created["calendarPath"] != "/api/users/user1/calendar.ics?token="+created["token"] {
+		t.Fatalf("unexpected feed token response %v", created)
+	}
+	if rec := do(http.MethodGet, created["calendarPath"], h.GetCalendarICS); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 with the feed token, got %d", rec.Code)
+	}
+
+	if rec := do(http.MethodDelete, "/api/users/user1/calendar/feed-token", h.RevokeFeedToken); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204 revoking the feed token, got %d", rec.Code)
+	}
+	if rec := do(http.MethodGet, created["calendarPath"], h.GetCalendarICS); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 after revoking, got %d", rec.Code)
+	}
+}
+
+func TestCreateFeedToken_PrefixesServerBasePath(t *testing.T) {
+	h, _ := setupCalendarHandler(t)
+	h.SetFeedTokenService(&mockFeedTokens{}, "mediastorm/")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/users/user1/calendar/feed-token", nil)
+	req = mux.SetURLVars(req, map[string]string{"userID": "user1"})
+	rec := httptest.NewRecorder()
+	h.CreateFeedToken(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var created map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	query := "?token=" + created["token"]
+	for key, want := range map[string]string{
+		"calendarPath": "/mediastorm/api/users/user1/calendar.ics" + query,
+		"rssPath":      "/mediastorm/api/users/user1/releases.rss" + query,
+		"jsonFeedPath": "/mediastorm/api/users/user1/releases.json" + query,
+	} {
+		if created[key] != want {
+			t.Errorf("%s = %q, want %q", key, created[key], want)
+		}
+	}
+}
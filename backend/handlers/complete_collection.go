@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"novastream/models"
+)
+
+const (
+	completeCollectionDefaultLimit = 20
+	completeCollectionMaxLimit     = 40
+	// completeCollectionSeedLimit caps how many recently watched movies are
+	// looked up for a collection on each request.
+	completeCollectionSeedLimit = 40
+	completeCollectionWorkers   = 6
+)
+
+// completeCollectionSeed is a watched movie and the last time it was watched.
+type completeCollectionSeed struct {
+	query     models.MovieDetailsQuery
+	watchedAt time.Time
+}
+
+// GetCompleteCollections returns the "Complete the Collection" shelf: the
+// released, unwatched movies of every TMDB collection the profile has watched
+// at least one movie from. Collections are ordered by the most recent watch
+// and movies keep their release order within a collection.
+func (h *MetadataHandler) GetCompleteCollections(w http.ResponseWriter, r *http.Request) {
+	userID := strings.TrimSpace(r.URL.Query().Get("userId"))
+	if userID == "" {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "userId is required"})
+		return
+	}
+	if h.HistoryService == nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": "history service not configured"})
+		return
+	}
+
+	limit := parsePersonalizedIntParam(r, "limit", completeCollectionDefaultLimit, 1, completeCollectionMaxLimit)
+
+	history, err := h.HistoryService.ListWatchHistory(userID)
+	if err != nil {
+		log.Printf("[metadata] complete collection history error user=%s: %v", userID, err)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadGateway)
+		_ = json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	progress, _ := h.HistoryService.ListPlaybackProgress(userID)
+
+	service := h.serviceForUser(userID)
+	items := h.buildCompleteCollectionItems(r, userID, service, history, progress, limit)
+	if len(items) > 0 {
+		enrichTrendingRatings(items, service)
+		cw, _ := h.HistoryService.ListSeriesStates(userID)
+		enrichTrendingItems(items, buildWatchStateIndex(history, cw, progress))
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(DiscoverNewResponse{Items: items, Total: len(items)})
+}
+
+func (h *MetadataHandler) buildCompleteCollectionItems(
+	r *http.Request,
+	userID string,
+	service metadataService,
+	history []models.WatchHistoryItem,
+	progress []models.PlaybackProgress,
+	limit int,
+) []models.TrendingItem {
+	ctx := r.Context()
+	seeds := buildCompleteCollectionSeeds(history, completeCollectionSeedLimit)
+	collections := lookupMovieCollections(ctx, service, seeds)
+
+	now := time.Now()
+	items := make([]models.TrendingItem, 0, limit)
+	seen := make(map[string]struct{})
+	for _, collectionID := range collections {
+		if len(items) >= limit || ctx.Err() != nil {
+			break
+		}
+		details, err := service.CollectionDetails(ctx, collectionID)
+		if err != nil || details == nil {
+			log.Printf("[metadata] complete collection skipped user=%s collection=%d: %v", userID, collectionID, err)
+			continue
+		}
+		details = withCollectionWatchProgress(details, history, progress, now)
+		if details.WatchProgress == nil || details.WatchProgress.WatchedCount == 0 {
+			continue
+		}
+
+		var pending []models.Title
+		for _, movie := range details.Movies {
+			if movie.WatchState == "complete" || movie.Year > now.Year() {
+				continue
+			}
+			if _, ok := seen[movie.ID]; ok {
+				continue
+			}
+			seen[movie.ID] = struct{}{}
+			if movie.Collection == nil {
+				movie.Collection = &models.Collection{ID: details.ID, Name: details.Name, Poster: details.Poster, Backdrop: details.Backdrop}
+			}
+			pending = append(pending, movie)
+		}
+		for _, movie := range h.filterTitlesByRating(r, userID, service, pending) {
+			if len(items) >= limit {
+				break
+			}
+			items = append(items, models.TrendingItem{Rank: len(items) + 1, Title: movie})
+		}
+	}
+	return items
+}
+
+// buildCompleteCollectionSeeds returns the profile's watched movies, most
+// recently watched first.
+func buildCompleteCollectionSeeds(history []models.WatchHistoryItem, limit int) []completeCollectionSeed {
+	byTMDB := make(map[int64]completeCollectionSeed)
+	for _, item := range history {
+		if !item.Watched || normalizePersonalizedMediaType(item.MediaType) != "movie" {
+			continue
+		}
+		tmdbID := extractTMDBID("movie", item.ItemID, item.ExternalIDs)
+		if tmdbID <= 0 {
+			continue
+		}
+		watchedAt := firstNonZeroTime(item.WatchedAt, item.UpdatedAt)
+		if existing, ok := byTMDB[tmdbID]; ok && !watchedAt.After(existing.watchedAt) {
+			continue
+		}
+		byTMDB[tmdbID] = completeCollectionSeed{
+			query: models.MovieDetailsQuery{
+				TitleID: item.ItemID,
+				Name:    item.Name,
+				Year:    item.Year,
+				IMDBID:  item.ExternalIDs["imdb"],
+				TMDBID:  tmdbID,
+			},
+			watchedAt: watchedAt,
+		}
+	}
+
+	seeds := make([]completeCollectionSeed, 0, len(byTMDB))
+	for _, seed := range byTMDB {
+		seeds = append(seeds, seed)
+	}
+	sort.Slice(seeds, func(i, j int) bool {
+		if seeds[i].watchedAt.Equal(seeds[j].watchedAt) {
+			return seeds[i].query.TMDBID < seeds[j].query.TMDBID
+		}
+		return seeds[i].watchedAt.After(seeds[j].watchedAt)
+	})
+	if len(seeds) > limit {
+		seeds = seeds[:limit]
+	}
+	return seeds
+}
+
+// lookupMovieCollections resolves the TMDB collection of each seed movie and
+// returns the distinct collection IDs in seed order.
+func lookupMovieCollections(ctx context.Context, service metadataService, seeds []completeCollectionSeed) []int64 {
+	found := make([]int64, len(seeds))
+	sem := make(chan struct{}, completeCollectionWorkers)
+	var wg sync.WaitGroup
+	for i, seed := range seeds {
+		wg.Add(1)
+		go func(i int, query models.MovieDetailsQuery) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				return
+			}
+			title, err := service.MovieDetails(ctx, query)
+			if err != nil || title == nil || title.Collection == nil {
+				return
+			}
+			found[i] = title.Collection.ID
+		}(i, seed.query)
+	}
+	wg.Wait()
+
+	collections := make([]int64, 0, len(found))
+	seen := make(map[int64]struct{})
+	for _, id := range found {
+		if id <= 0 {
+			continue
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		collections = append(collections, id)
+	}
+	return collections
+}
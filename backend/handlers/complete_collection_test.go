@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"novastream/models"
+)
+
+type fakeCollectionMetadataService struct {
+	*fakeMetadataService
+	movieCollections map[int64]int64
+	collections      map[int64]*models.CollectionDetails
+}
+
+func (f *fakeCollectionMetadataService) MovieDetails(_ context.Context, query models.MovieDetailsQuery) (*models.Title, error) {
+	title := &models.Title{ID: query.TitleID, TMDBID: query.TMDBID, MediaType: "movie"}
+	if id, ok := f.movieCollections[query.TMDBID]; ok {
+		title.Collection = &models.Collection{ID: id}
+	}
+	return title, nil
+}
+
+func (f *fakeCollectionMetadataService) CollectionDetails(_ context.Context, id int64) (*models.CollectionDetails, error) {
+	return f.collections[id], nil
+}
+
+func collectionMovie(tmdbID int64, name string, year int) models.Title {
+	return models.Title{ID: "tmdb:movie:" + strconv.FormatInt(tmdbID, 10), Name: name, Year: year, TMDBID: tmdbID, MediaType: "movie"}
+}
+
+func TestMetadataHandler_GetCompleteCollections(t *testing.T) {
+	now := time.Now().UTC()
+	fake := &fakeCollectionMetadataService{
+		fakeMetadataService: &fakeMetadataService{},
+		movieCollections:    map[int64]int64{603: 2344, 120: 119},
+		collections: map[int64]*models.CollectionDetails{
+			2344: {ID: 2344, Name: "The Matrix Collection", Movies: []models.Title{
+				collectionMovie(603, "The Matrix", 1999),
+				collectionMovie(604, "The Matrix Reloaded", 2003),
+				collectionMovie(605, "The Matrix Revolutions", 2003),
+				collectionMovie(999, "The Matrix 5", now.Year()+2),
+			}},
+			119: {ID: 119, Name: "The Lord of the Rings Collection", Movies: []models.Title{
+				collectionMovie(120, "The Fellowship of the Ring", 2001),
+				collectionMovie(121, "The Two Towers", 2002),
+			}},
+		},
+	}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+	handler.SetHistoryService(&fakeMetadataHistoryService{
+		history: []models.WatchHistoryItem{
+			{MediaType: "movie", ItemID: "tmdb:movie:120", Name: "The Fellowship of the Ring", Watched: true, WatchedAt: now.AddDate(0, 0, -30), ExternalIDs: map[string]string{"tmdb": "120"}},
+			{MediaType: "movie", ItemID: "tmdb:movie:121", Name: "The Two Towers", Watched: true, WatchedAt: now.AddDate(0, 0, -29), ExternalIDs: map[string]string{"tmdb": "121"}},
+			{MediaType: "movie", ItemID: "tmdb:movie:603", Name: "The Matrix", Watched: true, WatchedAt: now.AddDate(0, 0, -1), ExternalIDs: map[string]string{"tmdb": "603"}},
+			{MediaType: "movie", ItemID: "tmdb:movie:604", Name: "The Matrix Reloaded", Watched: false, WatchedAt: now, ExternalIDs: map[string]string{"tmdb": "604"}},
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/recommendations/collections?userId=user1", nil)
+	rec := httptest.NewRecorder()
+	handler.GetCompleteCollections(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var payload DiscoverNewResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	// The finished Lord of the Rings collection and the unreleased sequel are
+	// left out; the Matrix entries keep their collection order.
+	if payload.Total != 2 || len(payload.Items) != 2 {
+		t.Fatalf("expected 2 items, got %+v", payload)
+	}
+	if payload.Items[0].Title.TMDBID != 604 || payload.Items[1].Title.TMDBID != 605 {
+		t.Fatalf("unexpected items %d, %d", payload.Items[0].Title.TMDBID, payload.Items[1].Title.TMDBID)
+	}
+	if payload.Items[0].Title.Collection == nil || payload.Items[0].Title.Collection.Name != "The Matrix Collection" {
+		t.Fatalf("expected collection on item, got %+v", payload.Items[0].Title.Collection)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/recommendations/collections?userId=user1&limit=1", nil)
+	rec = httptest.NewRecorder()
+	handler.GetCompleteCollections(rec, req)
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if len(payload.Items) != 1 {
+		t.Fatalf("expected limit to cap items, got %d", len(payload.Items))
+	}
+}
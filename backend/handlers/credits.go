@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"novastream/models"
+)
+
+// Credit departments clients can ask for with ?departments= besides TMDB's
+// crew departments (directing, writing, production, sound, ...).
+const (
+	creditDepartmentCast   = "cast"
+	creditDepartmentGuests = "guests"
+)
+
+// creditDepartments parses ?departments=cast,directing,... into a lowercase
+// set. A nil set means the client wants every department.
+func creditDepartments(r *http.Request) map[string]bool {
+	raw := strings.TrimSpace(r.URL.Query().Get("departments"))
+	if raw == "" {
+		return nil
+	}
+	departments := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		if part = strings.ToLower(strings.TrimSpace(part)); part != "" {
+			departments[part] = true
+		}
+	}
+	if len(departments) == 0 {
+		return nil
+	}
+	return departments
+}
+
+// filterCredits returns a copy of credits holding only the requested
+// departments. The original may be shared with the metadata cache, so it is
+// never modified.
+func filterCredits(credits *models.Credits, departments map[string]bool) *models.Credits {
+	if credits == nil || departments == nil {
+		return credits
+	}
+	filtered := &models.Credits{Cast: []models.CastMember{}}
+	if departments[creditDepartmentCast] {
+		filtered.Cast = credits.Cast
+	}
+	filtered.Crew = filterCrew(credits.Crew, departments)
+	filtered.Directors = filterCrew(credits.Directors, departments)
+	filtered.Writers = filterCrew(credits.Writers, departments)
+	filtered.Creators = filterCrew(credits.Creators, departments)
+	return filtered
+}
+
+func filterCrew(crew []models.CrewMember, departments map[string]bool) []models.CrewMember {
+	var filtered []models.CrewMember
+	for _, member := range crew {
+		if departments[strings.ToLower(member.Department)] {
+			filtered = append(filtered, member)
+		}
+	}
+	return filtered
+}
+
+// withMovieCreditDepartments narrows a movie's credits to the departments
+// requested with ?departments=.
+func withMovieCreditDepartments(r *http.Request, title *models.Title) *models.Title {
+	departments := creditDepartments(r)
+	if title == nil || departments == nil {
+		return title
+	}
+	copied := *title
+	copied.Credits = filterCredits(title.Credits, departments)
+	return &copied
+}
+
+// withSeriesCreditDepartments narrows a series' credits to the departments
+// requested with ?departments=, dropping episode guest stars unless "guests"
+// is among them.
+func withSeriesCreditDepartments(r *http.Request, details *models.SeriesDetails) *models.SeriesDetails {
+	departments := creditDepartments(r)
+	if details == nil || departments == nil {
+		return details
+	}
+	copied := *details
+	copied.Title.Credits = filterCredits(details.Title.Credits, departments)
+	if departments[creditDepartmentGuests] {
+		return &copied
+	}
+	copied.Seasons = make([]models.SeriesSeason, len(details.Seasons))
+	for i, season := range details.Seasons {
+		episodes := make([]models.SeriesEpisode, len(season.Episodes))
+		for j, episode := range season.Episodes {
+			episode.GuestStars = nil
+			episodes[j] = episode
+		}
+		season.Episodes = episodes
+		copied.Seasons[i] = season
+	}
+	return &copied
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"novastream/models"
+)
+
+func TestWithSeriesCreditDepartments(t *testing.T) {
+	details := &models.SeriesDetails{
+		Title: models.Title{Credits: &models.Credits{
+			Cast:      []models.CastMember{{ID: 1, Name: "Adam Scott"}},
+			Crew:      []models.CrewMember{{ID: 2, Name: "Ben Stiller", Job: "Director", Department: "Directing"}, {ID: 3, Name: "Theodore Shapiro", Job: "Original Music Composer", Department: "Sound"}},
+			Directors: []models.CrewMember{{ID: 2, Name: "Ben Stiller", Job: "Director", Department: "Directing"}},
+			Creators:  []models.CrewMember{{ID: 4, Name: "Dan Erickson", Job: "Creator", Department: "Writing"}},
+		}},
+		Seasons: []models.SeriesSeason{{Number: 1, Episodes: []models.SeriesEpisode{
+			{EpisodeNumber: 1, GuestStars: []models.CastMember{{ID: 5, Name: "Yul Vazquez"}}},
+		}}},
+	}
+
+	// Without ?departments the details pass through untouched.
+	if got := withSeriesCreditDepartments(httptest.NewRequest(http.MethodGet, "/api/metadata/series/details", nil), details); got != details {
+		t.Fatal("expected unfiltered details to be returned as-is")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/metadata/series/details?departments=Directing,%20writing", nil)
+	got := withSeriesCreditDepartments(req, details)
+	credits := got.Title.Credits
+	if len(credits.Cast) != 0 || len(credits.Crew) != 1 || len(credits.Directors) != 1 || len(credits.Creators) != 1 {
+		t.Fatalf("unexpected filtered credits %+v", credits)
+	}
+	if len(got.Seasons[0].Episodes[0].GuestStars) != 0 {
+		t.Fatal("expected guest stars to be dropped")
+	}
+	// The cached details are left alone.
+	if len(details.Title.Credits.Cast) != 1 || len(details.Seasons[0].Episodes[0].GuestStars) != 1 {
+		t.Fatal("filtering modified the original details")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/metadata/series/details?departments=cast,guests", nil)
+	got = withSeriesCreditDepartments(req, details)
+	if len(got.Title.Credits.Cast) != 1 || len(got.Title.Credits.Crew) != 0 || len(got.Seasons[0].Episodes[0].GuestStars) != 1 {
+		t.Fatalf("unexpected cast-only details %+v", got)
+	}
+}
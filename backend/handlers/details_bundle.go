@@ -15,6 +15,7 @@ import (
 	"novastream/config"
 	"novastream/models"
 	metadatapkg "novastream/services/metadata"
+	"novastream/services/playback"
 
 	"github.com/gorilla/mux"
 )
@@ -29,6 +30,13 @@ type DetailsBundleHandler struct {
 	users        userService
 	cfgManager   *config.Manager
 	userSettings userSettingsProvider
+	instantPlay  instantPlayResolver
+	requests     requestPassthrough
+}
+
+// instantPlayResolver speculatively resolves the next-up source for a title.
+type instantPlayResolver interface {
+	PreResolve(userID, clientID, titleID, titleName, imdbID, mediaType string, year int) *playback.PrequeueResponse
 }
 
 type seriesDetailsLiteProvider interface {
@@ -39,6 +47,11 @@ type localizedMetadataProvider interface {
 	WithLanguage(string) *metadatapkg.Service
 }
 
+// animeMetadataProvider attaches AniList metadata to anime series.
+type animeMetadataProvider interface {
+	WithAnimeDetails(context.Context, *models.SeriesDetails) *models.SeriesDetails
+}
+
 // NewDetailsBundleHandler constructs a DetailsBundleHandler.
 func NewDetailsBundleHandler(
 	metadata metadataService,
@@ -62,6 +75,17 @@ func (h *DetailsBundleHandler) SetUserSettingsProvider(provider userSettingsProv
 	h.userSettings = provider
 }
 
+// SetInstantPlayResolver enables speculative source resolution when a details
+// bundle is requested, so Play can reuse an already-resolved stream.
+func (h *DetailsBundleHandler) SetInstantPlayResolver(resolver instantPlayResolver) {
+	h.instantPlay = resolver
+}
+
+// SetRequestPassthrough merges Overseerr availability into bundled details.
+func (h *DetailsBundleHandler) SetRequestPassthrough(passthrough requestPassthrough) {
+	h.requests = passthrough
+}
+
 func (h *DetailsBundleHandler) metadataForUser(userID string) metadataService {
 	if h.metadata == nil || h.cfgManager == nil {
 		return h.metadata
@@ -87,7 +111,7 @@ func (h *DetailsBundleHandler) metadataForUser(userID string) metadataService {
 			}
 		}
 	}
-	return localized.WithLanguage(language)
+	return withMetadataRegion(localized.WithLanguage(language), resolveCertificationRegion(settings, h.userSettings, userID))
 }
 
 // DetailsBundleResponse is the combined payload returned by
@@ -100,6 +124,9 @@ type DetailsBundleResponse struct {
 	ContentPreference *models.ContentPreference `json:"contentPreference"`
 	WatchState        *models.SeriesWatchState  `json:"watchState"`
 	PlaybackProgress  []models.PlaybackProgress `json:"playbackProgress"`
+	// Prequeue is the speculative ("instant play") resolution for the next-up
+	// episode or movie, when one was started or already exists.
+	Prequeue *playback.PrequeueResponse `json:"prequeue,omitempty"`
 }
 
 // DetailsShellResponse is a lightweight early payload returned by
@@ -130,6 +157,11 @@ func writeCompressedJSON(w http.ResponseWriter, r *http.Request, payload any, lo
 		return
 	}
 
+	if checkNotModified(w, r, body) {
+		log.Printf("[%s timing] payload: not modified", logPrefix)
+		return
+	}
+
 	rawSize := len(body)
 	encoding := "identity"
 	w.Header().Set("Content-Type", "application/json")
@@ -291,6 +323,7 @@ func (h *DetailsBundleHandler) GetDetailsBundle(w http.ResponseWriter, r *http.R
 				log.Printf("[details-bundle] series details error: %v", err)
 				return
 			}
+			details = withSeriesAvailability(r.Context(), h.requests, details)
 			mu.Lock()
 			resp.SeriesDetails = details
 			if details != nil && details.Title.TMDBID > 0 {
@@ -311,6 +344,7 @@ func (h *DetailsBundleHandler) GetDetailsBundle(w http.ResponseWriter, r *http.R
 				log.Printf("[details-bundle] movie details error: %v", err)
 				return
 			}
+			details = withMovieAvailability(r.Context(), h.requests, details)
 			mu.Lock()
 			resp.MovieDetails = details
 			if details != nil && details.TMDBID > 0 {
@@ -435,7 +469,50 @@ func (h *DetailsBundleHandler) GetDetailsBundle(w http.ResponseWriter, r *http.R
 		mu.Unlock()
 	}()
 
+	// 7. Instant play — start resolving the next-up source alongside the
+	// metadata fetches so Play can pick up a ready prequeue entry.
+	if h.instantPlay != nil && (contentType == "series" || contentType == "movie") {
+		clientID := strings.TrimSpace(r.Header.Get("X-Client-ID"))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			start := time.Now()
+			prequeue := h.instantPlay.PreResolve(userID, clientID, titleID, name, imdbID, contentType, year)
+			log.Printf("[details-bundle timing] instant play: %dms (started=%v)", time.Since(start).Milliseconds(), prequeue != nil)
+			mu.Lock()
+			resp.Prequeue = prequeue
+			mu.Unlock()
+		}()
+	}
+
+	// 8. Watch history for the per-season progress map (series only)
+	var watchHistory []models.WatchHistoryItem
+	if contentType == "series" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			items, err := h.history.ListWatchHistory(userID)
+			if err != nil {
+				log.Printf("[details-bundle] watch history error: %v", err)
+				return
+			}
+			mu.Lock()
+			watchHistory = items
+			mu.Unlock()
+		}()
+	}
+
 	wg.Wait()
+	if resp.SeriesDetails != nil && watchHistory != nil {
+		resp.SeriesDetails = withSeriesWatchProgress(resp.SeriesDetails, watchHistory, resp.PlaybackProgress)
+	}
+	if resp.SeriesDetails != nil && h.animeMetadataEnabled(resp.ContentPreference) {
+		if anime, ok := metadataSvc.(animeMetadataProvider); ok {
+			start := time.Now()
+			resp.SeriesDetails = anime.WithAnimeDetails(r.Context(), resp.SeriesDetails)
+			log.Printf("[details-bundle timing] anime metadata: %dms (matched=%v)", time.Since(start).Milliseconds(), resp.SeriesDetails.Anime != nil)
+		}
+	}
 	log.Printf("[details-bundle timing] TOTAL: %dms (type=%s, titleId=%s)", time.Since(bundleStart).Milliseconds(), contentType, titleID)
 
 	// Ensure nil slices become empty arrays in JSON
@@ -449,7 +526,7 @@ func (h *DetailsBundleHandler) GetDetailsBundle(w http.ResponseWriter, r *http.R
 		resp.Trailers.Trailers = []models.Trailer{}
 	}
 
-	writeCompressedJSON(w, r, resp, "details-bundle")
+	writeCompressedJSON(w, r, applyTitleDisplay(resp, resolveTitleDisplay(h.userSettings, userID)), "details-bundle")
 }
 
 // Options handles CORS preflight for the details-bundle endpoint.
@@ -585,3 +662,19 @@ func trimAndParseInt64(value string) int64 {
 	}
 	return parsed
 }
+
+// animeMetadataEnabled reports whether AniList metadata should be attached for
+// a series: the profile's per-series preference wins over the global setting.
+func (h *DetailsBundleHandler) animeMetadataEnabled(pref *models.ContentPreference) bool {
+	if pref != nil && pref.AnimeMetadata != nil {
+		return *pref.AnimeMetadata
+	}
+	if h.cfgManager == nil {
+		return false
+	}
+	settings, err := h.cfgManager.Load()
+	if err != nil {
+		return false
+	}
+	return settings.Metadata.AniListEnabled
+}
@@ -56,7 +56,7 @@ func (m *mockMetadataServiceDetailsBundle) DiscoverByGenre(_ context.Context, _
 func (m *mockMetadataServiceDetailsBundle) DiscoverByDecade(_ context.Context, _ string, _, _, _ int) ([]models.TrendingItem, int, error) {
 	return nil, 0, nil
 }
-func (m *mockMetadataServiceDetailsBundle) GetAIRecommendations(_ context.Context, _ []string, _ []string, _ string) ([]models.TrendingItem, error) {
+func (m *mockMetadataServiceDetailsBundle) GetAIRecommendations(_ context.Context, _ []string, _ []string, _ string, _ bool) ([]models.TrendingItem, error) {
 	return nil, nil
 }
 func (m *mockMetadataServiceDetailsBundle) GetAISimilar(_ context.Context, _ string, _ string) ([]models.TrendingItem, error) {
@@ -122,6 +122,14 @@ func (m *mockMetadataServiceDetailsBundle) ServePrequeuedTrailer(_ string, _ htt
 	return nil
 }
 
+func (m *mockMetadataServiceDetailsBundle) TrailerPreviews() []metadatapkg.TrailerPreview {
+	return nil
+}
+
+func (m *mockMetadataServiceDetailsBundle) ServeTrailerPreview(_ string, _ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
 func (m *mockMetadataServiceDetailsBundle) EnrichSearchCertifications(_ context.Context, _ []models.SearchResult) {
 }
 
@@ -184,6 +192,9 @@ func (m *mockHistoryServiceDetailsBundle) RecordEpisode(_ string, _ models.Episo
 func (m *mockHistoryServiceDetailsBundle) ListContinueWatching(_ string) ([]models.SeriesWatchState, error) {
 	return nil, nil
 }
+func (m *mockHistoryServiceDetailsBundle) ContinueWatchingShelf(userID string) ([]models.ContinueWatchingShelfItem, error) {
+	return nil, nil
+}
 func (m *mockHistoryServiceDetailsBundle) GetContinueWatchingRevision(_ string) (string, error) {
 	return "", nil
 }
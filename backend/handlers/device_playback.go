@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"novastream/models"
+)
+
+// devicePlaybackOverrides are the playback settings stored against a
+// registered device (client). The server applies them whenever that device
+// starts a stream, so they hold even for app versions that don't know them.
+type devicePlaybackOverrides struct {
+	ForceTranscode   bool
+	MaxHeight        int
+	DisableHDR       bool
+	AudioPassthrough bool
+}
+
+func (o devicePlaybackOverrides) isSet() bool {
+	return o.ForceTranscode || o.MaxHeight > 0 || o.DisableHDR || o.AudioPassthrough
+}
+
+// requestClientID returns the device a request comes from, taken from the
+// clientId query parameter or the X-Client-ID header.
+func requestClientID(r *http.Request) string {
+	if clientID := strings.TrimSpace(r.URL.Query().Get("clientId")); clientID != "" {
+		return clientID
+	}
+	return strings.TrimSpace(r.Header.Get("X-Client-ID"))
+}
+
+// devicePlaybackOverrides loads the playback overrides of a device. Unknown
+// devices and lookup failures yield no overrides.
+func (h *VideoHandler) devicePlaybackOverrides(clientID string) devicePlaybackOverrides {
+	var overrides devicePlaybackOverrides
+	if h.clientSettingsSvc == nil || clientID == "" {
+		return overrides
+	}
+	settings, err := h.clientSettingsSvc.Get(clientID)
+	if err != nil || settings == nil {
+		return overrides
+	}
+	overrides.ForceTranscode = models.BoolVal(settings.ForceTranscode, false)
+	overrides.DisableHDR = models.BoolVal(settings.DisableHDR, false)
+	overrides.AudioPassthrough = models.BoolVal(settings.PreferAudioPassthrough, false)
+	if settings.MaxPlaybackResolution != nil {
+		overrides.MaxHeight = playbackResolutionHeight(*settings.MaxPlaybackResolution)
+	}
+	return overrides
+}
+
+// playbackResolutionHeight converts a resolution setting ("1080p", "4k") to
+// a pixel height, or 0 when it is empty or not recognized.
+func playbackResolutionHeight(resolution string) int {
+	resolution = strings.ToLower(strings.TrimSpace(resolution))
+	switch resolution {
+	case "4k", "uhd":
+		return 2160
+	case "":
+		return 0
+	}
+	height, err := strconv.Atoi(strings.TrimSuffix(resolution, "p"))
+	if err != nil || height <= 0 {
+		return 0
+	}
+	return height
+}
+
+// hlsStreamOptions are the stream choices of an HLS session start that
+// device overrides can change.
+type hlsStreamOptions struct {
+	hasDV             bool
+	dvProfile         string
+	hasHDR            bool
+	forceAAC          bool
+	forceAACRequested bool // the client itself asked for AAC
+	target            string
+	maxVideoHeight    int
+}
+
+// applyToHLS adjusts an HLS session start for the device. sourceHeight is
+// only called when a resolution cap is set and the video would otherwise be
+// copied; it returns 0 when the height is unknown. The returned notes
+// describe each change, for logging.
+func (o devicePlaybackOverrides) applyToHLS(opts *hlsStreamOptions, sourceHeight func() int) []string {
+	var notes []string
+	transcode := func(reason string) {
+		if opts.target != playbackTargetH264 {
+			opts.target = playbackTargetH264
+			notes = append(notes, reason)
+		}
+	}
+
+	if o.ForceTranscode {
+		transcode("device always transcodes video")
+	}
+	if o.DisableHDR && (opts.hasDV || opts.hasHDR) {
+		opts.hasDV, opts.dvProfile, opts.hasHDR = false, "", false
+		transcode("device has HDR disabled")
+	}
+	if o.MaxHeight > 0 {
+		if opts.target != playbackTargetH264 {
+			if height := sourceHeight(); height > o.MaxHeight {
+				transcode(fmt.Sprintf("%dp exceeds device maximum of %dp", height, o.MaxHeight))
+			}
+		}
+		if opts.target == playbackTargetH264 && (opts.maxVideoHeight == 0 || o.MaxHeight < opts.maxVideoHeight) {
+			opts.maxVideoHeight = o.MaxHeight
+		}
+	}
+	if o.AudioPassthrough && opts.forceAAC && !opts.forceAACRequested {
+		opts.forceAAC = false
+		notes = append(notes, "device prefers audio passthrough")
+	}
+	return notes
+}
+
+// applyToCapabilities narrows the capabilities a device declared during
+// playback negotiation to its stored overrides.
+func (o devicePlaybackOverrides) applyToCapabilities(caps ClientCapabilities) ClientCapabilities {
+	if o.ForceTranscode {
+		caps.forceTranscode = true
+	}
+	if o.MaxHeight > 0 && (caps.MaxHeight == 0 || o.MaxHeight < caps.MaxHeight) {
+		caps.MaxHeight = o.MaxHeight
+	}
+	if o.DisableHDR {
+		caps.HDR = nil
+		caps.DolbyVisionProfiles = nil
+	}
+	return caps
+}
+
+// sourceVideoHeight returns the height of a source's first video stream from
+// the shared metadata cache, probing it if needed; 0 when unknown.
+func (h *VideoHandler) sourceVideoHeight(r *http.Request, path string) int {
+	meta, err := h.probeNegotiationCandidate(r.Context(), path)
+	if err != nil {
+		log.Printf("[video] device resolution cap: probe failed for path=%q: %v", path, err)
+		return 0
+	}
+	return meta.VideoStreams[0].Height
+}
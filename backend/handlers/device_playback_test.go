@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"novastream/models"
+)
+
+type stubClientSettings map[string]*models.ClientFilterSettings
+
+func (s stubClientSettings) Get(clientID string) (*models.ClientFilterSettings, error) {
+	return s[clientID], nil
+}
+
+func TestDevicePlaybackOverridesApplyToHLS(t *testing.T) {
+	hdrSource := func() hlsStreamOptions {
+		return hlsStreamOptions{hasDV: true, dvProfile: "dvhe.08.06", forceAAC: true}
+	}
+	noProbe := func() int {
+		t.Fatal("source height should not be probed")
+		return 0
+	}
+
+	opts := hdrSource()
+	(devicePlaybackOverrides{DisableHDR: true, MaxHeight: 1080}).applyToHLS(&opts, noProbe)
+	if opts.hasDV || opts.dvProfile != "" || opts.target != playbackTargetH264 || opts.maxVideoHeight != 1080 {
+		t.Fatalf("HDR disabled: got %+v", opts)
+	}
+
+	// A capped device only transcodes sources taller than its cap.
+	opts = hlsStreamOptions{}
+	(devicePlaybackOverrides{MaxHeight: 1080}).applyToHLS(&opts, func() int { return 1080 })
+	if opts.target != "" || opts.maxVideoHeight != 0 {
+		t.Fatalf("1080p source on a 1080p device should be copied: %+v", opts)
+	}
+	(devicePlaybackOverrides{MaxHeight: 720}).applyToHLS(&opts, func() int { return 1080 })
+	if opts.target != playbackTargetH264 || opts.maxVideoHeight != 720 {
+		t.Fatalf("1080p source on a 720p device should be scaled: %+v", opts)
+	}
+
+	// Passthrough only overrides AAC forced by the global setting.
+	opts = hdrSource()
+	(devicePlaybackOverrides{AudioPassthrough: true}).applyToHLS(&opts, noProbe)
+	if opts.forceAAC || !opts.hasDV {
+		t.Fatalf("passthrough should keep audio and HDR: %+v", opts)
+	}
+	opts = hlsStreamOptions{forceAAC: true, forceAACRequested: true}
+	(devicePlaybackOverrides{AudioPassthrough: true, ForceTranscode: true}).applyToHLS(&opts, noProbe)
+	if !opts.forceAAC || opts.target != playbackTargetH264 {
+		t.Fatalf("client-requested AAC should be kept: %+v", opts)
+	}
+}
+
+func TestPlaybackResolutionHeight(t *testing.T) {
+	for res, want := range map[string]int{"1080p": 1080, " 720P ": 720, "4K": 2160, "": 0, "best": 0} {
+		if got := playbackResolutionHeight(res); got != want {
+			t.Errorf("playbackResolutionHeight(%q) = %d, want %d", res, got, want)
+		}
+	}
+}
+
+func TestNegotiatePlaybackAppliesDeviceOverrides(t *testing.T) {
+	h := &VideoHandler{ffprobePath: "/nonexistent/ffprobe", metadataCache: make(map[string]*cachedMetadataEntry)}
+	h.SetClientSettingsService(stubClientSettings{
+		"living-room-tv": {ForceTranscode: models.BoolPtr(true), MaxPlaybackResolution: models.StringPtr("720p")},
+	})
+	h.setCachedMetadata("/streams/movie.mp4", negotiationMeta("mov,mp4,m4a,3gp,3g2,mj2",
+		videoStreamSummary{CodecName: "h264", Height: 1080}, audioStreamSummary{Index: 1, CodecName: "aac", Channels: 2}))
+
+	body := `{"path":"/streams/movie.mp4","capabilities":{"videoCodecs":["h264"],"audioCodecs":["aac"]}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/video/negotiate", strings.NewReader(body))
+	req.Header.Set("X-Client-ID", "living-room-tv")
+	rec := httptest.NewRecorder()
+	h.NegotiatePlayback(rec, req)
+
+	var resp PlaybackNegotiationResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v (%s)", err, rec.Body.String())
+	}
+	if resp.Decision == nil || resp.Decision.Method != PlaybackMethodTranscode || resp.Decision.HLSParams["maxHeight"] != "720" {
+		t.Fatalf("expected a 720p transcode, got %+v", resp.Decision)
+	}
+}
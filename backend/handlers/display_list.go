@@ -9,6 +9,7 @@ import (
 	"os"
 	"strings"
 
+	"novastream/config"
 	"novastream/models"
 	"novastream/services/customlists"
 	"novastream/services/watchlist"
@@ -24,6 +25,11 @@ type DisplayListHandler struct {
 	HistoryHandler     *HistoryHandler
 	MetadataService    metadataService
 	MetadataHandler    *MetadataHandler
+	LeavingSoon        leavingSoonSource
+	SavedFilters       *SavedFiltersHandler
+
+	cfgManager   *config.Manager
+	userSettings userSettingsProvider
 }
 
 type DisplayListResponse struct {
@@ -57,6 +63,12 @@ func (h *DisplayListHandler) SetMetadataHandler(handler *MetadataHandler) {
 	h.MetadataHandler = handler
 }
 
+// SetSavedFilters enables the "saved-filter" source, which lists the titles
+// matching one of the profile's saved discover filters (listId).
+func (h *DisplayListHandler) SetSavedFilters(handler *SavedFiltersHandler) {
+	h.SavedFilters = handler
+}
+
 func (h *DisplayListHandler) Get(w http.ResponseWriter, r *http.Request) {
 	userID, ok := h.requireUser(w, r)
 	if !ok {
@@ -72,7 +84,9 @@ func (h *DisplayListHandler) Get(w http.ResponseWriter, r *http.Request) {
 		source != "custom_user_list" &&
 		source != "custom-user-list" &&
 		source != "continue-watching" &&
-		source != "continue_watching"
+		source != "continue_watching" &&
+		source != "leaving-soon" &&
+		source != "leaving_soon"
 	if metadataSource && h.MetadataHandler == nil {
 		http.Error(w, "metadata source is unavailable", http.StatusServiceUnavailable)
 		return
@@ -100,6 +114,13 @@ func (h *DisplayListHandler) Get(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		items, err = h.CustomListsService.ListItems(userID, listID)
+	case "leaving-soon", "leaving_soon":
+		source = "leaving-soon"
+		items, err = h.leavingSoonItems(r.Context(), userID)
+		if errors.Is(err, errLeavingSoonUnavailable) {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
 	case "continue-watching", "continue_watching":
 		source = "continue-watching"
 		if h.HistoryHandler == nil {
@@ -128,6 +149,20 @@ func (h *DisplayListHandler) Get(w http.ResponseWriter, r *http.Request) {
 			"type": firstQueryValue(r, "mediaType", "type"),
 		}))
 		return
+	case "saved-filter", "saved_filter":
+		source = "saved-filter"
+		if h.SavedFilters == nil {
+			http.Error(w, "saved filter source is unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if listID == "" {
+			http.Error(w, "listId is required for saved-filter source", http.StatusBadRequest)
+			return
+		}
+		h.delegateMetadata(w, r, source, func(w http.ResponseWriter, r *http.Request) {
+			h.SavedFilters.serveItems(w, r, userID, listID)
+		}, displayListQuery(r, userID, nil))
+		return
 	case "mdblist", "mdblist-url", "mdblist-shelf", "seasonal":
 		source = "mdblist"
 		h.delegateMetadata(w, r, source, h.MetadataHandler.CustomList, displayListQuery(r, userID, nil))
@@ -145,6 +180,10 @@ func (h *DisplayListHandler) Get(w http.ResponseWriter, r *http.Request) {
 		source = "personalized"
 		h.delegateMetadata(w, r, source, h.MetadataHandler.GetPersonalizedRecommendations, displayListQuery(r, userID, nil))
 		return
+	case "complete-collection", "complete_collection":
+		source = "complete-collection"
+		h.delegateMetadata(w, r, source, h.MetadataHandler.GetCompleteCollections, displayListQuery(r, userID, nil))
+		return
 	case "custom-ai":
 		h.delegateMetadata(w, r, source, h.MetadataHandler.GetAICustomRecommendations, displayListQuery(r, userID, nil))
 		return
@@ -176,6 +215,12 @@ func (h *DisplayListHandler) Get(w http.ResponseWriter, r *http.Request) {
 	}
 
 	h.enrich(userID, items, r)
+	if source == "watchlist" {
+		if err := watchlist.SortItems(items, r.URL.Query().Get("sort"), r.URL.Query().Get("dir")); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
 
 	if items == nil {
 		items = []models.WatchlistItem{}
@@ -18,6 +18,7 @@ type EPGHandler struct {
 	epgService      *epg.Service
 	cfgManager      *config.Manager
 	userSettingsSvc LiveUserSettingsProvider
+	liveHandler     *LiveHandler // resolves per-profile channel lineups; optional
 }
 
 // NewEPGHandler creates a new EPG handler.
@@ -29,9 +30,35 @@ func NewEPGHandler(epgService *epg.Service, cfgManager *config.Manager, userSett
 	}
 }
 
+// SetLiveHandler enables per-profile lineups (hidden channels and ordering)
+// in guide responses.
+func (h *EPGHandler) SetLiveHandler(liveHandler *LiveHandler) {
+	h.liveHandler = liveHandler
+}
+
+// profileGuideLineup resolves the request profile's lineup for guide
+// responses. It returns nil when there is nothing to apply.
+func (h *EPGHandler) profileGuideLineup(r *http.Request) *guideLineup {
+	if h.liveHandler == nil || r.URL.Query().Get("profileId") == "" {
+		return nil
+	}
+	lineup, err := h.liveHandler.resolveGuideLineup(r)
+	if err != nil {
+		log.Printf("[epg] failed to resolve channel lineup profileId=%q: %v", r.URL.Query().Get("profileId"), err)
+		return nil
+	}
+	return lineup
+}
+
 // getEPGTimeOffset resolves the EPG time offset for the current request,
 // merging global settings with any per-profile override.
 func (h *EPGHandler) getEPGTimeOffset(r *http.Request) time.Duration {
+	return h.epgTimeOffsetForProfile(r.URL.Query().Get("profileId"))
+}
+
+// epgTimeOffsetForProfile resolves the EPG time offset of profileID (empty
+// for the global offset).
+func (h *EPGHandler) epgTimeOffsetForProfile(profileID string) time.Duration {
 	offset := 0
 	if h.cfgManager != nil {
 		if settings, err := h.cfgManager.Load(); err == nil {
@@ -39,7 +66,6 @@ func (h *EPGHandler) getEPGTimeOffset(r *http.Request) time.Duration {
 		}
 	}
 
-	profileID := r.URL.Query().Get("profileId")
 	if profileID != "" && h.userSettingsSvc != nil {
 		userSettings, err := h.userSettingsSvc.Get(profileID)
 		if err == nil && userSettings != nil && userSettings.LiveTV.EPG != nil && userSettings.LiveTV.EPG.TimeOffsetMinutes != nil {
@@ -105,11 +131,15 @@ func (h *EPGHandler) GetNowPlaying(w http.ResponseWriter, r *http.Request) {
 		channelIDs[i] = strings.TrimSpace(channelIDs[i])
 	}
 
+	lineup := h.profileGuideLineup(r)
+	channelIDs = lineup.filter(channelIDs)
+
 	offset := h.getEPGTimeOffset(r)
 
 	// Query with the negative offset so we find the correct programs in stored data,
 	// then shift response times by the positive offset.
 	result := h.epgService.GetNowPlaying(channelIDs, -offset)
+	lineup.sortNowPlaying(result)
 	currentCount := 0
 	nextCount := 0
 	for _, item := range result {
@@ -236,6 +266,7 @@ func (h *EPGHandler) GetScheduleMultiple(w http.ResponseWriter, r *http.Request)
 	for i := range channelIDs {
 		channelIDs[i] = strings.TrimSpace(channelIDs[i])
 	}
+	channelIDs = h.profileGuideLineup(r).filter(channelIDs)
 
 	// Default to 4 hours window
 	hours := 4
@@ -295,6 +326,111 @@ func (h *EPGHandler) GetScheduleMultiple(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// Search finds upcoming and airing programs by title or description.
+// GET /api/live/epg/search?q=news&limit=50&hours=24&channels=ch1,ch2
+func (h *EPGHandler) Search(w http.ResponseWriter, r *http.Request) {
+	if h.epgService == nil {
+		http.Error(w, `{"error":"EPG service not available"}`, http.StatusServiceUnavailable)
+		return
+	}
+	if !h.resolveEPGEnabled(r, h.epgService.IsEnabled()) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+		return
+	}
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		http.Error(w, `{"error":"missing q parameter"}`, http.StatusBadRequest)
+		return
+	}
+
+	var opts epg.SearchOptions
+	if limitParam := r.URL.Query().Get("limit"); limitParam != "" {
+		parseIntParam(limitParam, &opts.Limit)
+	}
+	if channelsParam := r.URL.Query().Get("channels"); channelsParam != "" {
+		opts.ChannelIDs = strings.Split(channelsParam, ",")
+	}
+
+	// Search the stored data at the offset-adjusted "now", then shift
+	// response times by the offset.
+	offset := h.getEPGTimeOffset(r)
+	now := time.Now().UTC().Add(-offset)
+	if hoursParam := r.URL.Query().Get("hours"); hoursParam != "" {
+		var hours int
+		if _, err := parseIntParam(hoursParam, &hours); err == nil && hours > 0 && hours <= 24*14 {
+			opts.To = now.Add(time.Duration(hours) * time.Hour)
+		}
+	}
+
+	lineup := h.profileGuideLineup(r)
+	results := h.epgService.Search(query, opts, now)
+	filtered := results[:0]
+	for _, result := range results {
+		if lineup.hides(result.Program.ChannelID) {
+			continue
+		}
+		if offset != 0 {
+			result.Program = applyOffsetToProgram(result.Program, offset)
+		}
+		filtered = append(filtered, result)
+	}
+	log.Printf("[epg] search profileId=%q query=%q results=%d offsetMinutes=%d",
+		r.URL.Query().Get("profileId"),
+		query,
+		len(filtered),
+		int(offset.Minutes()),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(filtered); err != nil {
+		log.Printf("[epg] Search JSON encode error: %v", err)
+	}
+}
+
+// GetNowNext returns what is on now and next for every channel in the guide.
+// GET /api/live/epg/now-next
+func (h *EPGHandler) GetNowNext(w http.ResponseWriter, r *http.Request) {
+	if h.epgService == nil {
+		http.Error(w, `{"error":"EPG service not available"}`, http.StatusServiceUnavailable)
+		return
+	}
+	if !h.resolveEPGEnabled(r, h.epgService.IsEnabled()) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[]`))
+		return
+	}
+
+	offset := h.getEPGTimeOffset(r)
+	lineup := h.profileGuideLineup(r)
+
+	all := h.epgService.GetNowNext(time.Now().UTC().Add(-offset))
+	result := all[:0]
+	for _, item := range all {
+		if lineup.hides(item.ChannelID) {
+			continue
+		}
+		if offset != 0 {
+			if item.Current != nil {
+				shifted := applyOffsetToProgram(*item.Current, offset)
+				item.Current = &shifted
+			}
+			if item.Next != nil {
+				shifted := applyOffsetToProgram(*item.Next, offset)
+				item.Next = &shifted
+			}
+		}
+		result = append(result, item)
+	}
+	lineup.sortNowPlaying(result)
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Printf("[epg] GetNowNext JSON encode error: %v", err)
+	}
+}
+
 // GetChannelSchedule returns the full day schedule for a channel.
 // GET /api/live/epg/channel/{id}?date=2024-01-15
 func (h *EPGHandler) GetChannelSchedule(w http.ResponseWriter, r *http.Request) {
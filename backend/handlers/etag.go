@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// etagVolatileFields matches response fields derived from the current time,
+// such as a next episode's countdown. They change every second while the
+// content they describe does not, so they are left out of the entity tag;
+// clients count down from the air time they already hold.
+var etagVolatileFields = regexp.MustCompile(`"countdownSeconds":-?[0-9]+`)
+
+// contentETag returns a strong entity tag derived from the response body.
+// Metadata responses are enriched per profile (watch state, pagination), so the
+// tag is computed from the final payload rather than the cached metadata.
+func contentETag(body []byte) string {
+	sum := sha256.Sum256(etagVolatileFields.ReplaceAll(body, nil))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether an If-None-Match header value matches etag.
+// Weak comparison is used, as RFC 9110 requires for If-None-Match.
+func etagMatches(ifNoneMatch, etag string) bool {
+	ifNoneMatch = strings.TrimSpace(ifNoneMatch)
+	if ifNoneMatch == "" {
+		return false
+	}
+	if ifNoneMatch == "*" {
+		return true
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkNotModified sets the ETag for body and, when the request's If-None-Match
+// already names it, writes 304 Not Modified. Returns true if the response has
+// been written.
+func checkNotModified(w http.ResponseWriter, r *http.Request, body []byte) bool {
+	etag := contentETag(body)
+	w.Header().Set("ETag", etag)
+	// Clients may reuse their copy, but must revalidate it on every request.
+	w.Header().Set("Cache-Control", "no-cache")
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		return false
+	}
+	if !etagMatches(r.Header.Get("If-None-Match"), etag) {
+		return false
+	}
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// writeJSONWithETag encodes payload as JSON, answering conditional requests
+// for unchanged content with 304 Not Modified.
+func writeJSONWithETag(w http.ResponseWriter, r *http.Request, payload any) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		http.Error(w, "failed to encode response", http.StatusInternalServerError)
+		return
+	}
+	body = append(body, '\n')
+	if checkNotModified(w, r, body) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(body)
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -17,6 +18,7 @@ import (
 type historyService interface {
 	RecordEpisode(userID string, payload models.EpisodeWatchPayload) (models.SeriesWatchState, error)
 	ListContinueWatching(userID string) ([]models.SeriesWatchState, error)
+	ContinueWatchingShelf(userID string) ([]models.ContinueWatchingShelfItem, error)
 	GetContinueWatchingRevision(userID string) (string, error)
 	ListSeriesStates(userID string) ([]models.SeriesWatchState, error)
 	GetSeriesWatchState(userID, seriesID string) (*models.SeriesWatchState, error)
@@ -41,6 +43,13 @@ type historyService interface {
 
 var _ historyService = (*history.Service)(nil)
 
+// watchedThroughService marks a run of episodes watched in one call.
+type watchedThroughService interface {
+	MarkWatchedThrough(ctx context.Context, userID string, req models.MarkWatchedThroughRequest) (models.MarkWatchedThroughResult, error)
+}
+
+var _ watchedThroughService = (*history.Service)(nil)
+
 type continueWatchingPrequeueStore interface {
 	GetByTitleUser(titleID, userID string) (*playback.PrequeueEntry, bool)
 }
@@ -88,6 +97,40 @@ func (h *HistoryHandler) ListContinueWatching(w http.ResponseWriter, r *http.Req
 	json.NewEncoder(w).Encode(h.withPrequeueStatus(userID, items))
 }
 
+// ListContinueWatchingShelf returns the continue-watching shelf with the next
+// episode, artwork and remaining runtime already resolved, so clients do not
+// need to look up each series themselves.
+func (h *HistoryHandler) ListContinueWatchingShelf(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	items, err := h.Service.ContinueWatchingShelf(userID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, history.ErrUserIDRequired) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	if h.PrequeueStore != nil {
+		for i := range items {
+			entry, ok := h.PrequeueStore.GetByTitleUser(items[i].SeriesID, userID)
+			if !ok || entry == nil || !prequeueMatchesContinueWatchingItem(entry, items[i].SeriesWatchState) {
+				continue
+			}
+			items[i].PrequeueID = entry.ID
+			items[i].PrequeueStatus = string(entry.Status)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(items)
+}
+
 func (h *HistoryHandler) withPrequeueStatus(userID string, items []models.SeriesWatchState) []models.SeriesWatchState {
 	if h.PrequeueStore == nil || len(items) == 0 {
 		return items
@@ -486,6 +529,46 @@ func (h *HistoryHandler) BulkUpdateWatchHistory(w http.ResponseWriter, r *http.R
 	json.NewEncoder(w).Encode(items)
 }
 
+// MarkWatchedThrough marks every aired episode of a series up to and
+// including the given season/episode as watched, syncing them to Trakt in one
+// batch.
+func (h *HistoryHandler) MarkWatchedThrough(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+	svc, ok := h.Service.(watchedThroughService)
+	if !ok {
+		http.Error(w, "marking episodes watched through is not supported", http.StatusNotImplemented)
+		return
+	}
+
+	var req models.MarkWatchedThroughRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := svc.MarkWatchedThrough(r.Context(), userID, req)
+	if err != nil {
+		status := watchHistoryErrorStatus(err)
+		switch {
+		case errors.Is(err, history.ErrSeriesIDRequired):
+			status = http.StatusBadRequest
+		case errors.Is(err, history.ErrEpisodeNotFound):
+			status = http.StatusNotFound
+		case status == http.StatusInternalServerError:
+			// The episode list comes from the metadata providers.
+			status = http.StatusBadGateway
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
 // DeleteWatchHistoryItem removes a watch-history item entirely.
 func (h *HistoryHandler) DeleteWatchHistoryItem(w http.ResponseWriter, r *http.Request) {
 	userID, ok := h.requireUser(w, r)
@@ -538,6 +621,10 @@ func (h *HistoryHandler) UpdatePlaybackProgress(w http.ResponseWriter, r *http.R
 		http.Error(w, "mediaType and itemID are required", http.StatusBadRequest)
 		return
 	}
+	// Clients that do not send a playback session still identify the device.
+	if strings.TrimSpace(update.SessionID) == "" {
+		update.SessionID = strings.TrimSpace(r.Header.Get("X-Client-ID"))
+	}
 
 	progress, err := h.Service.UpdatePlaybackProgress(userID, update)
 	if err != nil {
@@ -2,6 +2,7 @@ package handlers_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -12,12 +13,14 @@ import (
 
 	"novastream/handlers"
 	"novastream/models"
+	"novastream/services/history"
 )
 
 type fakeHistoryService struct {
 	state        models.SeriesWatchState
 	items        []models.SeriesWatchState
 	watchItems   []models.WatchHistoryItem
+	shelf        []models.ContinueWatchingShelfItem
 	revision     string
 	err          error
 	hideUserID   string
@@ -32,6 +35,10 @@ func (f *fakeHistoryService) ListContinueWatching(userID string) ([]models.Serie
 	return f.items, f.err
 }
 
+func (f *fakeHistoryService) ContinueWatchingShelf(userID string) ([]models.ContinueWatchingShelfItem, error) {
+	return f.shelf, f.err
+}
+
 func (f *fakeHistoryService) GetContinueWatchingRevision(userID string) (string, error) {
 	return f.revision, f.err
 }
@@ -316,3 +323,64 @@ func TestHistoryHandler_HideFromContinueWatchingByBody(t *testing.T) {
 		t.Fatalf("unexpected series id %q", svc.hideSeriesID)
 	}
 }
+
+type fakeWatchedThroughService struct {
+	*fakeHistoryService
+	req models.MarkWatchedThroughRequest
+	err error
+}
+
+func (f *fakeWatchedThroughService) MarkWatchedThrough(_ context.Context, _ string, req models.MarkWatchedThroughRequest) (models.MarkWatchedThroughResult, error) {
+	f.req = req
+	if f.err != nil {
+		return models.MarkWatchedThroughResult{}, f.err
+	}
+	return models.MarkWatchedThroughResult{
+		Items:          []models.WatchHistoryItem{{MediaType: "episode", SeasonNumber: 1, EpisodeNumber: 1, Watched: true}},
+		AlreadyWatched: 2,
+	}, nil
+}
+
+func TestHistoryHandler_MarkWatchedThrough(t *testing.T) {
+	svc := &fakeWatchedThroughService{fakeHistoryService: &fakeHistoryService{}}
+	handler := handlers.NewHistoryHandler(svc, fakeUserService{}, false)
+
+	body := bytes.NewBufferString(`{"seriesId":"tvdb:series:121361","seasonNumber":1,"episodeNumber":3}`)
+	req := httptest.NewRequest(http.MethodPost, "/users/user/history/watched/through", body)
+	req = mux.SetURLVars(req, map[string]string{"userID": "user"})
+	rec := httptest.NewRecorder()
+
+	handler.MarkWatchedThrough(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("unexpected status %d: %s", rec.Code, rec.Body.String())
+	}
+	var response models.MarkWatchedThroughResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(response.Items) != 1 || response.AlreadyWatched != 2 {
+		t.Fatalf("unexpected response %+v", response)
+	}
+	if svc.req.SeriesID != "tvdb:series:121361" || svc.req.SeasonNumber != 1 || svc.req.EpisodeNumber != 3 {
+		t.Fatalf("unexpected request %+v", svc.req)
+	}
+
+	svc.err = history.ErrEpisodeNotFound
+	req = httptest.NewRequest(http.MethodPost, "/users/user/history/watched/through", bytes.NewBufferString(`{"seriesId":"s1","seasonNumber":9,"episodeNumber":1}`))
+	req = mux.SetURLVars(req, map[string]string{"userID": "user"})
+	rec = httptest.NewRecorder()
+	handler.MarkWatchedThrough(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected %d for unknown episode, got %d", http.StatusNotFound, rec.Code)
+	}
+
+	plain := handlers.NewHistoryHandler(&fakeHistoryService{}, fakeUserService{}, false)
+	req = httptest.NewRequest(http.MethodPost, "/users/user/history/watched/through", bytes.NewBufferString(`{}`))
+	req = mux.SetURLVars(req, map[string]string{"userID": "user"})
+	rec = httptest.NewRecorder()
+	plain.MarkWatchedThrough(rec, req)
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("expected %d without service support, got %d", http.StatusNotImplemented, rec.Code)
+	}
+}
@@ -367,6 +367,10 @@ type HLSSession struct {
 	ClientIP      string
 	MediaMetadata StreamMediaMetadata
 
+	// watchTimeExempt skips the watch time limit on segment requests, for
+	// sessions started with the parental override PIN and for trailers.
+	watchTimeExempt bool
+
 	// Track selection (-1 means use default)
 	AudioTrackIndex    int // Selected audio stream index (ffprobe index), -1 = all/default
 	SubtitleTrackIndex int // Selected subtitle track index, -1 = none
@@ -438,6 +442,10 @@ type HLSSession struct {
 	PrequeueType   string // "", "details" (details page), or "next_episode" (auto-play next)
 	CastMode       bool   // True when the session is being prepared for Chromecast-style HLS playback
 	PlaybackTarget string // Optional client target hint, e.g. "web"
+	MaxVideoHeight int    // When set with the "h264" target, video is scaled down to this height
+
+	// Delivery rate and rebuffering reports, for quality hints and step-downs
+	bandwidth sessionBandwidth
 
 	// YouTube HLS sessions are assembled from separate direct video/audio URLs.
 	YouTubeVideoURL string
@@ -785,6 +793,43 @@ type HLSManager struct {
 	// Global probe cache - shared between prequeue (ProbeVideoFull) and HLS (probeAllMetadata)
 	probeCache   map[string]*cachedProbeEntry
 	probeCacheMu sync.RWMutex
+	// ffmpeg's video filters, detected on first use to decide whether HDR
+	// sources can be tone mapped for the "h264" target.
+	filterOnce sync.Once
+	filterCaps map[string]bool
+}
+
+// hlsToneMapToSDRFilter converts PQ or HLG video to BT.709 SDR for 8-bit
+// H.264 output.
+const hlsToneMapToSDRFilter = "zscale=t=linear:npl=100,format=gbrpf32le,tonemap=tonemap=hable:desat=0,zscale=p=bt709:t=bt709:m=bt709:r=tv,format=yuv420p"
+
+// canToneMapToSDR reports whether ffmpeg has the zscale and tonemap filters
+// that hlsToneMapToSDRFilter needs.
+func (m *HLSManager) canToneMapToSDR() bool {
+	m.filterOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 8*time.Second)
+		defer cancel()
+		caps, err := detectFFmpegVideoFilters(ctx, m.ffmpegPath)
+		if err != nil {
+			log.Printf("[hls] unable to inspect ffmpeg filters: %v", err)
+		}
+		m.filterCaps = caps
+	})
+	return m.filterCaps["zscale"] && m.filterCaps["tonemap"]
+}
+
+// hlsSourceIsHDR reports whether the session's source is PQ, HLG or Dolby
+// Vision video.
+func hlsSourceIsHDR(session *HLSSession) bool {
+	if session.HasDV || session.HasHDR {
+		return true
+	}
+	probe := session.ProbeData
+	if probe == nil {
+		return false
+	}
+	transfer := strings.ToLower(strings.TrimSpace(probe.ColorTransfer))
+	return probe.HasDolbyVision || probe.HasHDR10 || transfer == "smpte2084" || transfer == "arib-std-b67"
 }
 
 // inputLooksLikeHLS reports whether a live source URL is an HLS playlist. The
@@ -1130,7 +1175,7 @@ func (m *HLSManager) buildLocalWebDAVURLFromPath(path string) (string, bool) {
 }
 
 // CreateSession starts a new HLS transcoding session
-func (m *HLSManager) CreateSession(ctx context.Context, path string, originalPath string, hasDV bool, dvProfile string, hasHDR bool, forceAAC bool, startOffset float64, transcodingOffset float64, audioTrackIndex int, subtitleTrackIndex int, profileID string, profileName string, clientIP string, castMode bool, prequeueType string, playbackTarget string) (*HLSSession, error) {
+func (m *HLSManager) CreateSession(ctx context.Context, path string, originalPath string, hasDV bool, dvProfile string, hasHDR bool, forceAAC bool, startOffset float64, transcodingOffset float64, audioTrackIndex int, subtitleTrackIndex int, profileID string, profileName string, clientIP string, castMode bool, prequeueType string, playbackTarget string, maxVideoHeight int) (*HLSSession, error) {
 	sessionID := generateSessionID()
 	outputDir := filepath.Join(m.baseDir, sessionID)
 
@@ -1250,6 +1295,7 @@ func (m *HLSManager) CreateSession(ctx context.Context, path string, originalPat
 		CastMode:                castMode,
 		PrequeueType:            prequeueType, // "", "details", or "next_episode"
 		PlaybackTarget:          normalizedPlaybackTarget,
+		MaxVideoHeight:          maxVideoHeight,
 	}
 
 	m.mu.Lock()
@@ -2367,6 +2413,10 @@ func (m *HLSManager) startTranscoding(ctx context.Context, session *HLSSession,
 			log.Printf("[hls] session %s: web target has no video probe data; transcoding to H.264", session.ID)
 		}
 	}
+	if session.PlaybackTarget == playbackTargetH264 && (session.MaxVideoHeight > 0 || !isBrowserCopyCompatibleVideo(session.ProbeData)) {
+		needsVideoTranscode = true
+		log.Printf("[hls] session %s: h264 target requested by negotiated playback; transcoding video (max height %d)", session.ID, session.MaxVideoHeight)
+	}
 	if forceVideoTranscodeForWebSubtitleSeek {
 		needsVideoTranscode = true
 		log.Printf("[hls] session %s: web subtitle seek/resume requires video transcode for accurate subtitle sync", session.ID)
@@ -2393,6 +2443,33 @@ func (m *HLSManager) startTranscoding(ctx context.Context, session *HLSSession,
 			"-force_key_frames", fmt.Sprintf("expr:gte(t,n_forced*%.3f)", hlsSegmentDuration),
 			"-threads", "0", // Use all available CPU cores
 		)
+		session.mu.Lock()
+		session.bandwidth.videoTranscoding = true
+		maxVideoHeight, capKbps := session.bandwidth.transcodeCap(session.MaxVideoHeight)
+		session.mu.Unlock()
+		if capKbps > 0 {
+			log.Printf("[hls] session %s: bandwidth step-down active, capping video at %dp / %d kbps", session.ID, maxVideoHeight, capKbps)
+			args = append(args, x264RateControlArgs(capKbps)...)
+		}
+		var videoFilters []string
+		if maxVideoHeight > 0 {
+			videoFilters = append(videoFilters, fmt.Sprintf("scale=-2:'min(%d,ih)'", maxVideoHeight))
+		}
+		if session.PlaybackTarget == playbackTargetH264 && hlsSourceIsHDR(session) {
+			dvProfile := session.DVProfile
+			if dvProfile == "" && session.ProbeData != nil {
+				dvProfile = session.ProbeData.DolbyVisionProfile
+			}
+			if m.canToneMapToSDR() && !isDolbyVisionProfile5(dvProfile) {
+				log.Printf("[hls] session %s: tone mapping HDR source to SDR for h264 target", session.ID)
+				videoFilters = append(videoFilters, hlsToneMapToSDRFilter)
+			} else {
+				log.Printf("[hls] session %s: WARNING - HDR source cannot be tone mapped (dvProfile=%q); h264 output will look washed out", session.ID, dvProfile)
+			}
+		}
+		if len(videoFilters) > 0 {
+			args = append(args, "-vf", strings.Join(videoFilters, ","))
+		}
 		// When transcoding video for fMP4, also check if audio needs transcoding
 		// MP3 audio doesn't work well in fMP4 containers on iOS - must use AAC
 		if len(audioStreams) > 0 && audioStreams[0].Codec == "mp3" {
@@ -2400,6 +2477,9 @@ func (m *HLSManager) startTranscoding(ctx context.Context, session *HLSSession,
 			forceAAC = true
 		}
 	} else {
+		session.mu.Lock()
+		session.bandwidth.videoTranscoding = false
+		session.mu.Unlock()
 		args = append(args,
 			"-c:v", "copy", // Copy video codec (H.264/HEVC compatible)
 		)
@@ -3498,6 +3578,8 @@ func (m *HLSManager) GetSession(sessionID string) (*HLSSession, bool) {
 // KeepAlive updates the last activity time for a session to prevent idle timeout
 // This is used by the frontend to keep paused streams alive
 // Optional query param: time=<seconds> to report current playback position for rate limiting
+// Optional query param: buffering=true when the player is rebuffering; sustained reports step
+// a video transcode down a quality tier
 func (m *HLSManager) KeepAlive(w http.ResponseWriter, r *http.Request, sessionID string) {
 	session, exists := m.GetSession(sessionID)
 	if !exists {
@@ -3545,6 +3627,15 @@ func (m *HLSManager) KeepAlive(w http.ResponseWriter, r *http.Request, sessionID
 		}
 	}
 
+	if buffering, _ := strconv.ParseBool(r.URL.Query().Get("buffering")); buffering {
+		if session.bandwidth.reportBuffering(time.Now(), session.MaxVideoHeight) {
+			tier := qualityTiers[session.bandwidth.capTier]
+			log.Printf("[hls] session %s: sustained buffering reported, stepping transcode down to %s (%d kbps)",
+				sessionID, tier.Name, tier.VideoKbps)
+		}
+	}
+	bandwidth := session.bandwidth.hint()
+
 	// Capture timing info while we have the lock
 	startOffset := session.StartOffset
 	actualStartOffset := session.ActualStartOffset
@@ -3559,12 +3650,13 @@ func (m *HLSManager) KeepAlive(w http.ResponseWriter, r *http.Request, sessionID
 	// mediaTime = startOffset + (segmentIndex * segmentDuration) + positionInSegment
 	// keyframeDelta is the offset between actual keyframe and requested position for subtitle sync
 	response := struct {
-		Status            string  `json:"status"`
-		StartOffset       float64 `json:"startOffset"`
-		ActualStartOffset float64 `json:"actualStartOffset"`
-		KeyframeDelta     float64 `json:"keyframeDelta"`
-		SegmentDuration   float64 `json:"segmentDuration"`
-		Duration          float64 `json:"duration,omitempty"`
+		Status            string         `json:"status"`
+		StartOffset       float64        `json:"startOffset"`
+		ActualStartOffset float64        `json:"actualStartOffset"`
+		KeyframeDelta     float64        `json:"keyframeDelta"`
+		SegmentDuration   float64        `json:"segmentDuration"`
+		Duration          float64        `json:"duration,omitempty"`
+		Bandwidth         *BandwidthHint `json:"bandwidth,omitempty"`
 	}{
 		Status:            "ok",
 		StartOffset:       startOffset,
@@ -3572,6 +3664,7 @@ func (m *HLSManager) KeepAlive(w http.ResponseWriter, r *http.Request, sessionID
 		KeyframeDelta:     keyframeDelta,
 		SegmentDuration:   hlsSegmentDuration,
 		Duration:          duration,
+		Bandwidth:         bandwidth,
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -3844,18 +3937,19 @@ func (m *HLSManager) clearSessionSegments(session *HLSSession) error {
 
 // HLSSessionStatus represents the status of an HLS session for frontend polling
 type HLSSessionStatus struct {
-	SessionID           string  `json:"sessionId"`
-	Status              string  `json:"status"` // "active", "completed", "error"
-	FatalError          string  `json:"fatalError,omitempty"`
-	FatalErrorTime      int64   `json:"fatalErrorTime,omitempty"` // Unix timestamp
-	Duration            float64 `json:"duration,omitempty"`
-	SegmentsCreated     int     `json:"segmentsCreated"`
-	MaxSegmentRequested int     `json:"maxSegmentRequested"` // Highest segment requested by player
-	Paused              bool    `json:"paused"`              // True if FFmpeg is paused (rate limited)
-	BitstreamErrors     int     `json:"bitstreamErrors"`
-	HDRMetadataDisabled bool    `json:"hdrMetadataDisabled"`
-	DVDisabled          bool    `json:"dvDisabled"`
-	RecoveryAttempts    int     `json:"recoveryAttempts"`
+	SessionID           string         `json:"sessionId"`
+	Status              string         `json:"status"` // "active", "completed", "error"
+	FatalError          string         `json:"fatalError,omitempty"`
+	FatalErrorTime      int64          `json:"fatalErrorTime,omitempty"` // Unix timestamp
+	Duration            float64        `json:"duration,omitempty"`
+	SegmentsCreated     int            `json:"segmentsCreated"`
+	MaxSegmentRequested int            `json:"maxSegmentRequested"` // Highest segment requested by player
+	Paused              bool           `json:"paused"`              // True if FFmpeg is paused (rate limited)
+	BitstreamErrors     int            `json:"bitstreamErrors"`
+	HDRMetadataDisabled bool           `json:"hdrMetadataDisabled"`
+	DVDisabled          bool           `json:"dvDisabled"`
+	RecoveryAttempts    int            `json:"recoveryAttempts"`
+	Bandwidth           *BandwidthHint `json:"bandwidth,omitempty"`
 }
 
 // GetSessionStatus returns the current status of an HLS session
@@ -3877,6 +3971,7 @@ func (m *HLSManager) GetSessionStatus(w http.ResponseWriter, r *http.Request, se
 		HDRMetadataDisabled: session.HDRMetadataDisabled,
 		DVDisabled:          session.DVDisabled,
 		RecoveryAttempts:    session.RecoveryAttempts,
+		Bandwidth:           session.bandwidth.hint(),
 	}
 
 	if session.FatalError != "" {
@@ -4374,6 +4469,7 @@ func (m *HLSManager) ServeSegment(w http.ResponseWriter, r *http.Request, sessio
 		if servedSegmentNum > session.LastSegmentServed {
 			session.LastSegmentServed = servedSegmentNum
 		}
+		session.bandwidth.recordDelivery(segmentSize, serveDuration)
 		session.mu.Unlock()
 	}
 
@@ -0,0 +1,186 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+)
+
+// qualityTier is a rung of the quality ladder used for bandwidth hints and
+// transcode step-downs.
+type qualityTier struct {
+	Name      string
+	MaxHeight int
+	VideoKbps int // H.264 bitrate cap when a transcode is stepped down to this tier
+}
+
+var qualityTiers = []qualityTier{
+	{Name: "2160p", MaxHeight: 2160, VideoKbps: 20000},
+	{Name: "1080p", MaxHeight: 1080, VideoKbps: 8000},
+	{Name: "720p", MaxHeight: 720, VideoKbps: 4000},
+	{Name: "480p", MaxHeight: 480, VideoKbps: 1500},
+	{Name: "360p", MaxHeight: 360, VideoKbps: 800},
+}
+
+const (
+	// A tier is recommended when the measured throughput covers its bitrate
+	// with this much headroom.
+	bandwidthHeadroom = 1.5
+	// Segment deliveries smaller or faster than this mostly measure socket
+	// buffers rather than the link, so they are not sampled.
+	bandwidthMinSampleBytes    = 256 * 1024
+	bandwidthMinSampleDuration = 20 * time.Millisecond
+	bandwidthEWMAAlpha         = 0.3
+
+	// Buffering is sustained when the client reports it this many times
+	// within the window. Step-downs are spaced so a restart can take effect.
+	bufferingReportThreshold = 3
+	bufferingReportWindow    = 30 * time.Second
+	stepDownCooldown         = 60 * time.Second
+)
+
+// sessionBandwidth tracks how fast segments reach the client and whether it
+// keeps rebuffering. Guarded by the owning HLSSession's mu.
+type sessionBandwidth struct {
+	deliveryBps      float64 // EWMA of per-segment delivery rate
+	samples          int
+	bufferingReports []time.Time
+	lastStepDown     time.Time
+	capped           bool // a step-down caps the transcode at capTier
+	capTier          int  // index into qualityTiers
+	capPending       bool // cap not yet applied by a transcode restart
+	videoTranscoding bool // the running transcode encodes video, so a cap can apply
+}
+
+// BandwidthHint is the quality advice returned with keepalive and status
+// responses.
+type BandwidthHint struct {
+	EstimatedBps         int64  `json:"estimatedBps,omitempty"`
+	RecommendedTier      string `json:"recommendedTier,omitempty"`
+	RecommendedMaxHeight int    `json:"recommendedMaxHeight,omitempty"`
+	// VideoBitrateKbps is the transcode bitrate cap after a step-down.
+	VideoBitrateKbps int `json:"videoBitrateKbps,omitempty"`
+	// StepDownPending is set until the transcode restarts with the new cap;
+	// clients apply it by seeking to their current position.
+	StepDownPending bool `json:"stepDownPending,omitempty"`
+}
+
+// recordDelivery folds one served segment into the delivery rate estimate.
+func (b *sessionBandwidth) recordDelivery(bytes int64, elapsed time.Duration) {
+	if bytes < bandwidthMinSampleBytes || elapsed < bandwidthMinSampleDuration {
+		return
+	}
+	bps := float64(bytes) * 8 / elapsed.Seconds()
+	if b.samples == 0 {
+		b.deliveryBps = bps
+	} else {
+		b.deliveryBps = bandwidthEWMAAlpha*bps + (1-bandwidthEWMAAlpha)*b.deliveryBps
+	}
+	b.samples++
+}
+
+// recommendedTier returns the index of the best tier the measured throughput
+// sustains, or -1 before any segment has been measured.
+func (b *sessionBandwidth) recommendedTier() int {
+	if b.samples == 0 {
+		return -1
+	}
+	for i, tier := range qualityTiers {
+		if float64(tier.VideoKbps)*1000*bandwidthHeadroom <= b.deliveryBps {
+			return i
+		}
+	}
+	return len(qualityTiers) - 1
+}
+
+// reportBuffering records a rebuffering report from the client and steps the
+// transcode down one tier (or to the recommended tier, if lower) when
+// buffering is sustained. currentHeight is the session's existing height
+// cap, 0 when uncapped. It returns whether a step-down happened.
+func (b *sessionBandwidth) reportBuffering(now time.Time, currentHeight int) bool {
+	recent := b.bufferingReports[:0]
+	for _, at := range b.bufferingReports {
+		if now.Sub(at) < bufferingReportWindow {
+			recent = append(recent, at)
+		}
+	}
+	b.bufferingReports = append(recent, now)
+
+	if !b.videoTranscoding || len(b.bufferingReports) < bufferingReportThreshold {
+		return false
+	}
+	if !b.lastStepDown.IsZero() && now.Sub(b.lastStepDown) < stepDownCooldown {
+		return false
+	}
+
+	current := tierIndexForHeight(currentHeight)
+	if b.capped {
+		current = b.capTier
+	}
+	next := current + 1
+	if recommended := b.recommendedTier(); recommended > next {
+		next = recommended
+	}
+	if next >= len(qualityTiers) {
+		return false
+	}
+
+	b.capped, b.capTier = true, next
+	b.capPending = true
+	b.lastStepDown = now
+	b.bufferingReports = nil
+	return true
+}
+
+// tierIndexForHeight returns the tier a height cap corresponds to; an
+// uncapped session counts as the top tier.
+func tierIndexForHeight(height int) int {
+	if height <= 0 {
+		return 0
+	}
+	for i, tier := range qualityTiers {
+		if tier.MaxHeight <= height {
+			return i
+		}
+	}
+	return len(qualityTiers) - 1
+}
+
+// transcodeCap returns the height and bitrate limits of an active step-down
+// and marks it applied. maxHeight is the session's own height cap (0 = none)
+// and is only lowered.
+func (b *sessionBandwidth) transcodeCap(maxHeight int) (height, kbps int) {
+	if !b.capped {
+		return maxHeight, 0
+	}
+	b.capPending = false
+	tier := qualityTiers[b.capTier]
+	if maxHeight == 0 || tier.MaxHeight < maxHeight {
+		maxHeight = tier.MaxHeight
+	}
+	return maxHeight, tier.VideoKbps
+}
+
+func (b *sessionBandwidth) hint() *BandwidthHint {
+	hint := &BandwidthHint{StepDownPending: b.capPending}
+	if recommended := b.recommendedTier(); recommended >= 0 {
+		hint.EstimatedBps = int64(b.deliveryBps)
+		hint.RecommendedTier = qualityTiers[recommended].Name
+		hint.RecommendedMaxHeight = qualityTiers[recommended].MaxHeight
+	}
+	if b.capped {
+		hint.VideoBitrateKbps = qualityTiers[b.capTier].VideoKbps
+	}
+	if *hint == (BandwidthHint{}) {
+		return nil
+	}
+	return hint
+}
+
+// x264RateControlArgs caps the H.264 bitrate of a stepped-down transcode
+// while keeping its CRF quality target.
+func x264RateControlArgs(kbps int) []string {
+	if kbps <= 0 {
+		return nil
+	}
+	return []string{"-maxrate", fmt.Sprintf("%dk", kbps), "-bufsize", fmt.Sprintf("%dk", kbps*2)}
+}
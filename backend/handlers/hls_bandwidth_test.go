@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionBandwidthRecommendsTierFromDeliveryRate(t *testing.T) {
+	var b sessionBandwidth
+	if b.hint() != nil {
+		t.Fatalf("hint before any delivery = %+v, want nil", b.hint())
+	}
+
+	// Tiny or instant deliveries are not measured.
+	b.recordDelivery(64*1024, time.Second)
+	b.recordDelivery(4*1024*1024, time.Millisecond)
+	if b.samples != 0 {
+		t.Fatalf("samples = %d, want 0", b.samples)
+	}
+
+	// 2 MB segments in one second: ~16.8 Mbps sustains 1080p (8 Mbps with headroom).
+	for i := 0; i < 3; i++ {
+		b.recordDelivery(2*1024*1024, time.Second)
+	}
+	hint := b.hint()
+	if hint == nil || hint.RecommendedTier != "1080p" || hint.RecommendedMaxHeight != 1080 || hint.StepDownPending {
+		t.Fatalf("hint = %+v, want 1080p recommendation", hint)
+	}
+}
+
+func TestSessionBandwidthStepsDownOnSustainedBuffering(t *testing.T) {
+	b := sessionBandwidth{videoTranscoding: true}
+	now := time.Now()
+
+	// Reports spread beyond the window are not sustained buffering.
+	for i := 0; i < 3; i++ {
+		if b.reportBuffering(now.Add(time.Duration(i)*bufferingReportWindow), 1080) {
+			t.Fatal("stepped down on scattered buffering reports")
+		}
+	}
+
+	now = now.Add(5 * bufferingReportWindow)
+	b.reportBuffering(now, 1080)
+	b.reportBuffering(now.Add(time.Second), 1080)
+	if !b.reportBuffering(now.Add(2*time.Second), 1080) {
+		t.Fatal("expected a step-down after sustained buffering")
+	}
+	if hint := b.hint(); hint == nil || hint.VideoBitrateKbps != 4000 || !hint.StepDownPending {
+		t.Fatalf("hint = %+v, want pending 720p cap", hint)
+	}
+
+	height, kbps := b.transcodeCap(1080)
+	if height != 720 || kbps != 4000 || b.capPending {
+		t.Fatalf("transcodeCap = %dp/%dk pending=%t, want 720p/4000k applied", height, kbps, b.capPending)
+	}
+
+	// The cooldown holds further step-downs until the restart has had time to help.
+	for i := 0; i < 3; i++ {
+		if b.reportBuffering(now.Add(10*time.Second), 0) {
+			t.Fatal("stepped down again during cooldown")
+		}
+	}
+	later := now.Add(stepDownCooldown + time.Minute)
+	for i := 0; i < 3; i++ {
+		b.reportBuffering(later.Add(time.Duration(i)*time.Second), 0)
+	}
+	if height, kbps := b.transcodeCap(0); height != 480 || kbps != 1500 {
+		t.Fatalf("second step-down cap = %dp/%dk, want 480p/1500k", height, kbps)
+	}
+}
+
+func TestSessionBandwidthIgnoresBufferingWhenCopyingVideo(t *testing.T) {
+	var b sessionBandwidth
+	now := time.Now()
+	for i := 0; i < 5; i++ {
+		if b.reportBuffering(now.Add(time.Duration(i)*time.Second), 0) {
+			t.Fatal("stepped down a session that copies video")
+		}
+	}
+}
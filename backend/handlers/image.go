@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
@@ -16,6 +17,7 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -27,6 +29,42 @@ import (
 
 const imageProxyDefaultQuality = 80
 
+// imageFFmpegTimeout bounds a single WebP/AVIF encode so a stuck ffmpeg
+// can't hold up the requests waiting on the same image.
+const imageFFmpegTimeout = 30 * time.Second
+
+// Output formats of the image proxy. WebP and AVIF are encoded with ffmpeg
+// and only offered when its build has the encoders.
+const (
+	imageFormatJPEG = "jpeg"
+	imageFormatPNG  = "png"
+	imageFormatWebP = "webp"
+	imageFormatAVIF = "avif"
+)
+
+var imageFormatExtensions = map[string]string{
+	imageFormatJPEG: ".jpg",
+	imageFormatPNG:  ".png",
+	imageFormatWebP: ".webp",
+	imageFormatAVIF: ".avif",
+}
+
+var imageFormatContentTypes = map[string]string{
+	imageFormatJPEG: "image/jpeg",
+	imageFormatPNG:  "image/png",
+	imageFormatWebP: "image/webp",
+	imageFormatAVIF: "image/avif",
+}
+
+// imageProxySizes are the named artwork variants, using TMDB's size names.
+// "original" keeps the source dimensions.
+var imageProxySizes = map[string]int{
+	"w185":     185,
+	"w342":     342,
+	"w780":     780,
+	"original": 0,
+}
+
 type imageWarmRequest struct {
 	Images []imageWarmItem `json:"images"`
 }
@@ -34,13 +72,16 @@ type imageWarmRequest struct {
 type imageWarmItem struct {
 	URL     string `json:"url"`
 	Width   int    `json:"width,omitempty"`
+	Size    string `json:"size,omitempty"`
 	Quality int    `json:"quality,omitempty"`
+	Format  string `json:"format,omitempty"`
 }
 
 type imageWarmResult struct {
 	URL     string `json:"url"`
 	Width   int    `json:"width,omitempty"`
 	Quality int    `json:"quality,omitempty"`
+	Format  string `json:"format,omitempty"`
 	Cached  bool   `json:"cached"`
 	Error   string `json:"error,omitempty"`
 }
@@ -52,16 +93,29 @@ type imageWarmResponse struct {
 	Failed  int               `json:"failed"`
 }
 
+// imageFetch is an image being fetched and cached. Concurrent requests for the
+// same image wait on done and then share err.
+type imageFetch struct {
+	done chan struct{}
+	err  error
+}
+
 // ImageHandler handles image proxying with resize and caching
 type ImageHandler struct {
 	cacheDir   string
+	ffmpegPath string
 	httpc      *http.Client
 	mu         sync.RWMutex
-	inProgress map[string]chan struct{} // Prevent duplicate fetches
+	inProgress map[string]*imageFetch // Prevent duplicate fetches
+
+	encodersOnce sync.Once
+	encoders     map[string]string // output format -> ffmpeg encoder
 }
 
-// NewImageHandler creates a new image proxy handler
-func NewImageHandler(cacheDir string) *ImageHandler {
+// NewImageHandler creates a new image proxy handler. ffmpegPath enables
+// WebP and AVIF output; when empty, images are served as JPEG (or PNG when
+// they have transparency).
+func NewImageHandler(cacheDir, ffmpegPath string) *ImageHandler {
 	// Create cache directory if needed
 	imgCacheDir := filepath.Join(cacheDir, "images")
 	if err := os.MkdirAll(imgCacheDir, 0755); err != nil {
@@ -69,19 +123,22 @@ func NewImageHandler(cacheDir string) *ImageHandler {
 	}
 
 	return &ImageHandler{
-		cacheDir: imgCacheDir,
+		cacheDir:   imgCacheDir,
+		ffmpegPath: ffmpegPath,
 		httpc: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		inProgress: make(map[string]chan struct{}),
+		inProgress: make(map[string]*imageFetch),
 	}
 }
 
 // Proxy handles image proxy requests
 // Query params:
 //   - url: source image URL (required)
-//   - w: target width (optional, default: original)
-//   - q: JPEG quality 1-100 (optional, default: 80)
+//   - size: named variant w185, w342, w780 or original (optional)
+//   - w: target width (optional, default: original; ignored when size is set)
+//   - q: quality 1-100 (optional, default: 80)
+//   - format: jpeg, webp or avif (optional, default: negotiated from Accept)
 func (h *ImageHandler) Proxy(w http.ResponseWriter, r *http.Request) {
 	sourceURL := r.URL.Query().Get("url")
 
@@ -97,7 +154,14 @@ func (h *ImageHandler) Proxy(w http.ResponseWriter, r *http.Request) {
 
 	// Parse target width (0 = original size)
 	targetWidth := 0
-	if wStr := r.URL.Query().Get("w"); wStr != "" {
+	if size := r.URL.Query().Get("size"); size != "" {
+		width, ok := imageProxySizes[size]
+		if !ok {
+			http.Error(w, "invalid size", http.StatusBadRequest)
+			return
+		}
+		targetWidth = width
+	} else if wStr := r.URL.Query().Get("w"); wStr != "" {
 		if w, err := strconv.Atoi(wStr); err == nil && w > 0 && w <= 2000 {
 			targetWidth = w
 		}
@@ -111,7 +175,15 @@ func (h *ImageHandler) Proxy(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	_, data, cached, err := h.ensureCached(sourceURL, targetWidth, quality)
+	var format string
+	if requested := r.URL.Query().Get("format"); requested != "" {
+		format = h.outputFormat(requested)
+	} else {
+		format = h.negotiateFormat(r.Header.Get("Accept"))
+		w.Header().Set("Vary", "Accept")
+	}
+
+	variant, cached, err := h.ensureCached(sourceURL, targetWidth, quality, format)
 	if err != nil {
 		status := http.StatusBadGateway
 		if strings.Contains(err.Error(), "decode") || strings.Contains(err.Error(), "encode") {
@@ -121,14 +193,74 @@ func (h *ImageHandler) Proxy(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "image/jpeg")
+	w.Header().Set("Content-Type", imageFormatContentTypes[variant.format])
 	w.Header().Set("Cache-Control", "public, max-age=2592000") // 30 days
 	if cached {
 		w.Header().Set("X-Cache", "HIT")
 	} else {
 		w.Header().Set("X-Cache", "MISS")
 	}
-	w.Write(data)
+	w.Write(variant.data)
+}
+
+// outputFormat returns the requested format if the proxy can produce it,
+// falling back to JPEG.
+func (h *ImageHandler) outputFormat(requested string) string {
+	format := strings.ToLower(strings.TrimSpace(requested))
+	if format == "jpg" {
+		format = imageFormatJPEG
+	}
+	switch format {
+	case imageFormatWebP, imageFormatAVIF:
+		if h.ffmpegEncoder(format) != "" {
+			return format
+		}
+	}
+	return imageFormatJPEG
+}
+
+// negotiateFormat picks the smallest format the client accepts: AVIF, then
+// WebP, then JPEG.
+func (h *ImageHandler) negotiateFormat(accept string) string {
+	accept = strings.ToLower(accept)
+	for _, format := range []string{imageFormatAVIF, imageFormatWebP} {
+		if strings.Contains(accept, "image/"+format) && h.ffmpegEncoder(format) != "" {
+			return format
+		}
+	}
+	return imageFormatJPEG
+}
+
+// ffmpegEncoder returns the ffmpeg encoder used for a format, or "" when
+// ffmpeg is not configured or lacks one. Encoders are detected once.
+func (h *ImageHandler) ffmpegEncoder(format string) string {
+	h.encodersOnce.Do(func() {
+		h.encoders = make(map[string]string)
+		if h.ffmpegPath == "" {
+			return
+		}
+		out, err := exec.Command(h.ffmpegPath, "-hide_banner", "-encoders").Output()
+		if err != nil {
+			log.Printf("[ImageProxy] ffmpeg encoder detection failed, serving JPEG only: %v", err)
+			return
+		}
+		available := make(map[string]bool)
+		for _, line := range strings.Split(string(out), "\n") {
+			if fields := strings.Fields(line); len(fields) >= 2 {
+				available[fields[1]] = true
+			}
+		}
+		if available["libwebp"] {
+			h.encoders[imageFormatWebP] = "libwebp"
+		}
+		for _, encoder := range []string{"libaom-av1", "libsvtav1"} {
+			if available[encoder] {
+				h.encoders[imageFormatAVIF] = encoder
+				break
+			}
+		}
+	})
+	return h.encoders[format]
 }
 
 func validateProxyImageURL(sourceURL string) error {
@@ -190,36 +322,76 @@ func normalizeProxyQuality(quality int) int {
 	return imageProxyDefaultQuality
 }
 
-func (h *ImageHandler) ensureCached(sourceURL string, targetWidth, quality int) (string, []byte, bool, error) {
-	cacheKey := h.cacheKey(sourceURL, targetWidth, quality)
-	cachePath := filepath.Join(h.cacheDir, cacheKey+".jpg")
-	if data, err := os.ReadFile(cachePath); err == nil {
-		return cachePath, data, true, nil
+// cachedImage is an encoded image variant in the proxy cache.
+type cachedImage struct {
+	path   string
+	data   []byte
+	format string
+}
+
+// alphaFormat returns the format used instead of format for images with
+// transparency: JPEG has no alpha channel and ffmpeg's AVIF output drops it.
+func (h *ImageHandler) alphaFormat(format string) string {
+	switch format {
+	case imageFormatJPEG:
+		return imageFormatPNG
+	case imageFormatAVIF:
+		if h.ffmpegEncoder(imageFormatWebP) != "" {
+			return imageFormatWebP
+		}
+		return imageFormatPNG
+	}
+	return format
+}
+
+// readCachedImage returns a cached variant, which may be stored in the
+// format's alpha fallback.
+func (h *ImageHandler) readCachedImage(cacheKey, format string) (cachedImage, bool) {
+	for _, f := range []string{format, h.alphaFormat(format)} {
+		path := filepath.Join(h.cacheDir, cacheKey+imageFormatExtensions[f])
+		if data, err := os.ReadFile(path); err == nil {
+			return cachedImage{path: path, data: data, format: f}, true
+		}
+	}
+	return cachedImage{}, false
+}
+
+// variantCacheKey keys a resized variant. JPEG keeps the original key so
+// existing caches stay valid.
+func (h *ImageHandler) variantCacheKey(sourceURL string, width, quality int, format string) string {
+	if format == imageFormatJPEG {
+		return h.cacheKey(sourceURL, width, quality)
+	}
+	return h.cacheKey(format+":"+sourceURL, width, quality)
+}
+
+func (h *ImageHandler) ensureCached(sourceURL string, targetWidth, quality int, format string) (_ cachedImage, _ bool, err error) {
+	cacheKey := h.variantCacheKey(sourceURL, targetWidth, quality, format)
+	if cached, ok := h.readCachedImage(cacheKey, format); ok {
+		return cached, true, nil
 	}
 
 	// Prevent duplicate fetches for the same image
 	h.mu.Lock()
-	if ch, exists := h.inProgress[cacheKey]; exists {
+	if fetch, exists := h.inProgress[cacheKey]; exists {
 		h.mu.Unlock()
 		// Wait for other request to finish
-		<-ch
+		<-fetch.done
+		if fetch.err != nil {
+			return cachedImage{}, false, fetch.err
+		}
 		// Now try to serve from cache
-		if data, err := os.ReadFile(cachePath); err == nil {
-			return cachePath, data, true, nil
+		if cached, ok := h.readCachedImage(cacheKey, format); ok {
+			return cached, true, nil
 		}
-		return cachePath, nil, false, fmt.Errorf("failed to load image")
+		return cachedImage{}, false, fmt.Errorf("failed to load image")
 	}
 	// Mark as in progress
-	ch := make(chan struct{})
-	h.inProgress[cacheKey] = ch
+	fetch := &imageFetch{done: make(chan struct{})}
+	h.inProgress[cacheKey] = fetch
 	h.mu.Unlock()
 
-	defer func() {
-		h.mu.Lock()
-		delete(h.inProgress, cacheKey)
-		close(ch)
-		h.mu.Unlock()
-	}()
+	defer func() { h.finishFetch(cacheKey, fetch, err) }()
 
 	// Fetch the image
 	client := *h.httpc
@@ -235,20 +407,20 @@ func (h *ImageHandler) ensureCached(sourceURL string, targetWidth, quality int)
 	resp, err := client.Get(sourceURL)
 	if err != nil {
 		log.Printf("[ImageProxy] Fetch error for %s: %v", sourceURL, err)
-		return cachePath, nil, false, fmt.Errorf("failed to fetch image")
+		return cachedImage{}, false, fmt.Errorf("failed to fetch image")
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		log.Printf("[ImageProxy] Fetch returned %d for %s", resp.StatusCode, sourceURL)
-		return cachePath, nil, false, fmt.Errorf("image source error")
+		return cachedImage{}, false, fmt.Errorf("image source error")
 	}
 
 	// Decode the image
 	img, _, err := image.Decode(resp.Body)
 	if err != nil {
 		log.Printf("[ImageProxy] Decode error for %s: %v", sourceURL, err)
-		return cachePath, nil, false, fmt.Errorf("failed to decode image")
+		return cachedImage{}, false, fmt.Errorf("failed to decode image")
 	}
 
 	// Resize if requested
@@ -271,26 +443,27 @@ func (h *ImageHandler) ensureCached(sourceURL string, targetWidth, quality int)
 		}
 	}
 
-	// Encode as JPEG for consistent output and better compression
-	tmpPath := cachePath + ".tmp"
-	f, err := os.Create(tmpPath)
-	if err != nil {
-		log.Printf("[ImageProxy] Cache create error: %v", err)
-		var buf bytes.Buffer
-		if encodeErr := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); encodeErr != nil {
-			return cachePath, nil, false, fmt.Errorf("failed to encode image")
-		}
-		return cachePath, buf.Bytes(), false, nil
+	// Keep logos and other transparent artwork in a format with alpha
+	if !imageIsOpaque(img) {
+		format = h.alphaFormat(format)
 	}
+	cachePath := filepath.Join(h.cacheDir, cacheKey+imageFormatExtensions[format])
 
 	// Encode to temp file
-	if err := jpeg.Encode(f, img, &jpeg.Options{Quality: quality}); err != nil {
-		f.Close()
+	tmpPath := cachePath + ".tmp"
+	if err := h.encodeImageFile(tmpPath, img, format, quality); err != nil {
 		os.Remove(tmpPath)
-		log.Printf("[ImageProxy] Encode error: %v", err)
-		return cachePath, nil, false, fmt.Errorf("failed to encode image")
+		log.Printf("[ImageProxy] Encode error (%s): %v", format, err)
+		if format == imageFormatWebP || format == imageFormatAVIF {
+			return cachedImage{}, false, fmt.Errorf("failed to encode image")
+		}
+		// Cache dir not writable: serve without caching
+		var buf bytes.Buffer
+		if encodeErr := encodeStdImage(&buf, img, format, quality); encodeErr != nil {
+			return cachedImage{}, false, fmt.Errorf("failed to encode image")
+		}
+		return cachedImage{path: cachePath, data: buf.Bytes(), format: format}, false, nil
 	}
-	f.Close()
 
 	// Atomic rename
 	if err := os.Rename(tmpPath, cachePath); err != nil {
@@ -301,10 +474,87 @@ func (h *ImageHandler) ensureCached(sourceURL string, targetWidth, quality int)
 	// Serve from cache
 	data, err := os.ReadFile(cachePath)
 	if err != nil {
-		return cachePath, nil, false, fmt.Errorf("failed to read cached image")
+		return cachedImage{}, false, fmt.Errorf("failed to read cached image")
 	}
 
-	return cachePath, data, false, nil
+	return cachedImage{path: cachePath, data: data, format: format}, false, nil
+}
+
+// encodeImageFile writes img to path in the given format.
+func (h *ImageHandler) encodeImageFile(path string, img image.Image, format string, quality int) error {
+	if format == imageFormatWebP || format == imageFormatAVIF {
+		return h.encodeWithFFmpeg(path, img, format, quality)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := encodeStdImage(f, img, format, quality); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func encodeStdImage(w io.Writer, img image.Image, format string, quality int) error {
+	if format == imageFormatPNG {
+		return png.Encode(w, img)
+	}
+	return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+}
+
+// encodeWithFFmpeg pipes img to ffmpeg as PNG and encodes it to path. The
+// AVIF muxer needs a seekable output, so ffmpeg writes the file itself.
+func (h *ImageHandler) encodeWithFFmpeg(path string, img image.Image, format string, quality int) error {
+	encoder := h.ffmpegEncoder(format)
+	if encoder == "" {
+		return fmt.Errorf("no %s encoder available", format)
+	}
+	var input bytes.Buffer
+	if err := png.Encode(&input, img); err != nil {
+		return err
+	}
+
+	args := []string{"-hide_banner", "-loglevel", "error", "-y", "-f", "png_pipe", "-i", "pipe:0", "-frames:v", "1", "-c:v", encoder}
+	switch format {
+	case imageFormatWebP:
+		args = append(args, "-quality", strconv.Itoa(quality))
+	case imageFormatAVIF:
+		// Map quality 1-100 onto the AV1 CRF scale (63 = lowest quality)
+		args = append(args, "-crf", strconv.Itoa(63-quality*63/100), "-still-picture", "1")
+	}
+	args = append(args, "-f", format, path)
+
+	ctx, cancel := context.WithTimeout(context.Background(), imageFFmpegTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, h.ffmpegPath, args...)
+	cmd.Stdin = &input
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("ffmpeg timed out after %s", imageFFmpegTimeout)
+		}
+		return fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// finishFetch removes a finished fetch and releases its waiters with err.
+func (h *ImageHandler) finishFetch(cacheKey string, fetch *imageFetch, err error) {
+	h.mu.Lock()
+	delete(h.inProgress, cacheKey)
+	fetch.err = err
+	close(fetch.done)
+	h.mu.Unlock()
+}
+
+// imageIsOpaque reports whether an image has no transparent pixels.
+func imageIsOpaque(img image.Image) bool {
+	if o, ok := img.(interface{ Opaque() bool }); ok {
+		return o.Opaque()
+	}
+	return true
 }
 
 // GIFFirstFrame returns the first frame of an external GIF as a cached PNG.
@@ -335,7 +585,7 @@ func (h *ImageHandler) GIFFirstFrame(w http.ResponseWriter, r *http.Request) {
 	w.Write(data)
 }
 
-func (h *ImageHandler) ensureGIFFirstFrameCached(sourceURL string) (string, []byte, bool, error) {
+func (h *ImageHandler) ensureGIFFirstFrameCached(sourceURL string) (_ string, _ []byte, _ bool, err error) {
 	cacheKey := h.cacheKey("gif-first-frame:"+sourceURL, 0, 0)
 	cachePath := filepath.Join(h.cacheDir, cacheKey+".png")
 	if data, err := os.ReadFile(cachePath); err == nil {
@@ -343,24 +593,22 @@ func (h *ImageHandler) ensureGIFFirstFrameCached(sourceURL string) (string, []by
 	}
 
 	h.mu.Lock()
-	if ch, exists := h.inProgress[cacheKey]; exists {
+	if fetch, exists := h.inProgress[cacheKey]; exists {
 		h.mu.Unlock()
-		<-ch
+		<-fetch.done
+		if fetch.err != nil {
+			return cachePath, nil, false, fetch.err
+		}
 		if data, err := os.ReadFile(cachePath); err == nil {
 			return cachePath, data, true, nil
 		}
 		return cachePath, nil, false, fmt.Errorf("failed to load first frame")
 	}
-	ch := make(chan struct{})
-	h.inProgress[cacheKey] = ch
+	fetch := &imageFetch{done: make(chan struct{})}
+	h.inProgress[cacheKey] = fetch
 	h.mu.Unlock()
 
-	defer func() {
-		h.mu.Lock()
-		delete(h.inProgress, cacheKey)
-		close(ch)
-		h.mu.Unlock()
-	}()
+	defer func() { h.finishFetch(cacheKey, fetch, err) }()
 
 	resp, err := h.httpc.Get(sourceURL)
 	if err != nil {
@@ -425,9 +673,13 @@ func (h *ImageHandler) Warm(w http.ResponseWriter, r *http.Request) {
 	for _, item := range req.Images {
 		sourceURL := strings.TrimSpace(item.URL)
 		width := normalizeProxyWidth(item.Width)
+		if sizeWidth, ok := imageProxySizes[item.Size]; ok {
+			width = sizeWidth
+		}
 		quality := normalizeProxyQuality(item.Quality)
-		result := imageWarmResult{URL: sourceURL, Width: width, Quality: quality}
-		key := h.cacheKey(sourceURL, width, quality)
+		format := h.outputFormat(item.Format)
+		result := imageWarmResult{URL: sourceURL, Width: width, Quality: quality, Format: format}
+		key := h.variantCacheKey(sourceURL, width, quality, format)
 		if _, ok := seen[key]; ok {
 			continue
 		}
@@ -446,7 +698,7 @@ func (h *ImageHandler) Warm(w http.ResponseWriter, r *http.Request) {
 			continue
 		}
 
-		_, _, cached, err := h.ensureCached(sourceURL, width, quality)
+		_, cached, err := h.ensureCached(sourceURL, width, quality, format)
 		if err != nil {
 			result.Error = err.Error()
 			response.Failed++
@@ -484,7 +736,7 @@ func (h *ImageHandler) ClearCache() error {
 
 	var errs []error
 	for _, entry := range entries {
-		if !entry.IsDir() && (strings.HasSuffix(entry.Name(), ".jpg") || strings.HasSuffix(entry.Name(), ".png")) {
+		if !entry.IsDir() && isCachedImageFile(entry.Name()) {
 			if err := os.Remove(filepath.Join(h.cacheDir, entry.Name())); err != nil {
 				errs = append(errs, err)
 			}
@@ -505,7 +757,7 @@ func (h *ImageHandler) CacheStats() (count int, sizeBytes int64) {
 	}
 
 	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".jpg") {
+		if !entry.IsDir() && isCachedImageFile(entry.Name()) {
 			count++
 			if info, err := entry.Info(); err == nil {
 				sizeBytes += info.Size()
@@ -515,6 +767,15 @@ func (h *ImageHandler) CacheStats() (count int, sizeBytes int64) {
 	return
 }
 
+func isCachedImageFile(name string) bool {
+	for _, ext := range imageFormatExtensions {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	return false
+}
+
 // Unused imports guard - these are actually used
 var _ = jpeg.Encode
 var _ = png.Decode
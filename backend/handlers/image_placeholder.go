@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+
+	"novastream/utils/placeholder"
+)
+
+// imagePlaceholderWidth is the proxy variant placeholders are computed from.
+const imagePlaceholderWidth = 185
+
+type imagePlaceholder struct {
+	BlurHash      string `json:"blurhash"`
+	DominantColor string `json:"dominantColor"`
+}
+
+// Placeholder returns the blurhash and dominant color of an artwork image.
+// They are computed from the image's w185 proxy variant and stored next to
+// it, so each image is analysed once.
+func (h *ImageHandler) Placeholder(ctx context.Context, imageURL string) (string, string, error) {
+	if err := validateProxyImageURL(imageURL); err != nil {
+		return "", "", err
+	}
+	if err := ctx.Err(); err != nil {
+		return "", "", err
+	}
+
+	cachePath := filepath.Join(h.cacheDir, h.cacheKey("placeholder:"+imageURL, 0, 0)+".json")
+	var cached imagePlaceholder
+	if data, err := os.ReadFile(cachePath); err == nil && json.Unmarshal(data, &cached) == nil {
+		return cached.BlurHash, cached.DominantColor, nil
+	}
+
+	variant, _, err := h.ensureCached(imageURL, imagePlaceholderWidth, imageProxyDefaultQuality, imageFormatJPEG)
+	if err != nil {
+		return "", "", err
+	}
+	img, _, err := image.Decode(bytes.NewReader(variant.data))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to decode image")
+	}
+
+	result := imagePlaceholder{}
+	result.BlurHash, result.DominantColor = placeholder.Compute(img)
+	if data, err := json.Marshal(result); err == nil {
+		_ = os.WriteFile(cachePath, data, 0644)
+	}
+	return result.BlurHash, result.DominantColor, nil
+}
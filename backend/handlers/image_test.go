@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type imageRoundTripper map[string][]byte
+
+func (rt imageRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, ok := rt[req.URL.String()]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(bytes.NewReader(nil)), Request: req}, nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(body)), Request: req}, nil
+}
+
+func encodeTestPNG(t *testing.T, width, height int, alpha uint8) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.NRGBA{R: 200, G: 40, B: 40, A: alpha})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encode png: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestImageProxyServesNamedSizeVariants(t *testing.T) {
+	const posterURL = "https://image.tmdb.org/t/p/original/poster.png"
+	const logoURL = "https://image.tmdb.org/t/p/original/logo.png"
+	h := NewImageHandler(t.TempDir(), "")
+	h.httpc.Transport = imageRoundTripper{
+		posterURL: encodeTestPNG(t, 1000, 1500, 255),
+		logoURL:   encodeTestPNG(t, 800, 200, 0),
+	}
+
+	get := func(sourceURL, size, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/images/proxy?size="+size+"&url="+sourceURL, nil)
+		req.Header.Set("Accept", accept)
+		rec := httptest.NewRecorder()
+		h.Proxy(rec, req)
+		return rec
+	}
+
+	// Without ffmpeg, WebP/AVIF requests fall back to JPEG.
+	rec := get(posterURL, "w342", "image/avif,image/webp,*/*")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/jpeg" || rec.Header().Get("X-Cache") != "MISS" {
+		t.Fatalf("status=%d headers=%v: %s", rec.Code, rec.Header(), rec.Body.String())
+	}
+	img, err := jpeg.Decode(rec.Body)
+	if err != nil {
+		t.Fatalf("decode proxied poster: %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != 342 || b.Dy() != 513 {
+		t.Fatalf("poster variant is %dx%d, want 342x513", b.Dx(), b.Dy())
+	}
+	if rec := get(posterURL, "w342", ""); rec.Header().Get("X-Cache") != "HIT" {
+		t.Fatalf("second request X-Cache = %q, want HIT", rec.Header().Get("X-Cache"))
+	}
+
+	// Transparent artwork keeps its alpha channel.
+	rec = get(logoURL, "w185", "")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("logo status=%d content-type=%q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if rec := get(logoURL, "w185", ""); rec.Header().Get("X-Cache") != "HIT" || rec.Header().Get("Content-Type") != "image/png" {
+		t.Fatalf("cached logo headers = %v", rec.Header())
+	}
+
+	if rec := get(posterURL, "w9000", ""); rec.Code != http.StatusBadRequest {
+		t.Fatalf("unknown size status = %d, want 400", rec.Code)
+	}
+}
+
+func TestImageProxyWaitersShareFetchError(t *testing.T) {
+	const posterURL = "https://image.tmdb.org/t/p/original/missing.png"
+	h := NewImageHandler(t.TempDir(), "")
+	h.httpc.Transport = imageRoundTripper{}
+
+	// A request arriving while another fetches the same image gets its error.
+	key := h.variantCacheKey(posterURL, 342, imageProxyDefaultQuality, imageFormatJPEG)
+	fetch := &imageFetch{done: make(chan struct{}), err: errors.New("image source error")}
+	close(fetch.done)
+	h.inProgress[key] = fetch
+	if _, _, err := h.ensureCached(posterURL, 342, imageProxyDefaultQuality, imageFormatJPEG); err == nil || err.Error() != "image source error" {
+		t.Fatalf("waiter error = %v, want the fetch's error", err)
+	}
+	delete(h.inProgress, key)
+
+	if _, _, err := h.ensureCached(posterURL, 342, imageProxyDefaultQuality, imageFormatJPEG); err == nil {
+		t.Fatal("expected an error for a missing source image")
+	}
+	if len(h.inProgress) != 0 {
+		t.Fatalf("expected the failed fetch to be released, got %d in progress", len(h.inProgress))
+	}
+}
+
+func TestImagePlaceholderIsComputedOnceFromProxyVariant(t *testing.T) {
+	const backdropURL = "https://image.tmdb.org/t/p/w1280/backdrop.png"
+	transport := imageRoundTripper{backdropURL: encodeTestPNG(t, 1280, 720, 255)}
+	h := NewImageHandler(t.TempDir(), "")
+	h.httpc.Transport = transport
+
+	blurHash, dominant, err := h.Placeholder(context.Background(), backdropURL)
+	if err != nil {
+		t.Fatalf("Placeholder: %v", err)
+	}
+	if len(blurHash) != 28 || blurHash[0] != 'L' || dominant != "#c82828" {
+		t.Fatalf("placeholder = %q %q", blurHash, dominant)
+	}
+
+	// The stored result is reused without fetching the image again.
+	delete(transport, backdropURL)
+	if again, _, err := h.Placeholder(context.Background(), backdropURL); err != nil || again != blurHash {
+		t.Fatalf("cached placeholder = %q, %v", again, err)
+	}
+
+	if _, _, err := h.Placeholder(context.Background(), "https://example.com/poster.jpg"); err == nil {
+		t.Fatal("expected an error for a host the proxy does not serve")
+	}
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"log"
+	"strings"
+	"sync"
+
+	"novastream/models"
+	"novastream/services/playback"
+)
+
+// prequeueReasonInstantPlay marks prequeue entries started speculatively when a
+// details page opens. Such entries may be cancelled when the profile moves on
+// to another title, until a real prequeue request claims them.
+const prequeueReasonInstantPlay = "instant-play"
+
+// instantPlayMaxConcurrent bounds how many speculative resolutions run at once
+// across all profiles. Detail pages opened beyond the limit are not pre-resolved.
+const instantPlayMaxConcurrent = 2
+
+// instantPlayState tracks in-flight speculative resolutions.
+type instantPlayState struct {
+	slots  chan struct{}
+	mu     sync.Mutex
+	byUser map[string]instantPlayRun // userID -> speculative resolution
+}
+
+// instantPlayRun is one speculative resolution and the slot it holds.
+// release frees the slot; it is safe to call more than once.
+type instantPlayRun struct {
+	entryID string
+	release func()
+}
+
+func newInstantPlayState() *instantPlayState {
+	return &instantPlayState{
+		slots:  make(chan struct{}, instantPlayMaxConcurrent),
+		byUser: make(map[string]instantPlayRun),
+	}
+}
+
+// PreResolve speculatively resolves the best source for the title a profile is
+// viewing so that pressing Play can reuse a ready prequeue entry. For series the
+// next-up episode is targeted. Existing entries for the same scope are reused,
+// the profile's previous speculative resolution is cancelled when it targets a
+// different title, and nothing is started when prequeue is disabled for the
+// profile/client or all instant-play slots are busy.
+//
+// The returned response describes the entry the client can poll, or nil when
+// nothing was started or reused.
+func (h *PrequeueHandler) PreResolve(userID, clientID, titleID, titleName, imdbID, mediaType string, year int) *playback.PrequeueResponse {
+	userID = strings.TrimSpace(userID)
+	titleID = strings.TrimSpace(titleID)
+	titleName = strings.TrimSpace(titleName)
+	if h == nil || h.instantPlay == nil || h.demoMode || userID == "" || titleID == "" || titleName == "" {
+		return nil
+	}
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	switch mediaType {
+	case "tv", "show":
+		mediaType = "series"
+	case "series", "movie":
+	default:
+		return nil
+	}
+	if h.prequeueDisabled(userID, clientID) || h.playbackGuard.blocked(userID) != nil {
+		return nil
+	}
+
+	var targetEpisode *models.EpisodeReference
+	if mediaType == "series" {
+		targetEpisode = h.nextUpEpisode(userID, titleID)
+	}
+
+	settingsScopeKey := h.prequeueSettingsScopeKey(userID, clientID, titleID)
+	if existing, ok := h.store.GetByTitleUserScope(titleID, userID, settingsScopeKey); ok &&
+		prequeueEpisodeMatches(targetEpisode, existing.TargetEpisode) &&
+		(existing.Status == playback.PrequeueStatusReady || isPrequeueInProgress(existing.Status)) {
+		return &playback.PrequeueResponse{
+			PrequeueID:    existing.ID,
+			TargetEpisode: existing.TargetEpisode,
+			Status:        existing.Status,
+		}
+	}
+
+	// The profile's previous run gives up its slot first, so moving between
+	// titles is never refused for slots the profile itself is holding.
+	h.cancelInstantPlay(userID, titleID)
+
+	select {
+	case h.instantPlay.slots <- struct{}{}:
+	default:
+		log.Printf("[instant-play] Skipping title=%s user=%s: all %d slots busy", titleID, userID, instantPlayMaxConcurrent)
+		return nil
+	}
+
+	entry, _ := h.store.CreateScoped(titleID, titleName, userID, mediaType, year, targetEpisode, prequeueReasonInstantPlay, settingsScopeKey)
+	run := instantPlayRun{entryID: entry.ID, release: sync.OnceFunc(func() { <-h.instantPlay.slots })}
+	h.instantPlay.mu.Lock()
+	h.instantPlay.byUser[userID] = run
+	h.instantPlay.mu.Unlock()
+
+	log.Printf("[instant-play] Pre-resolving title=%s (%q) user=%s scope=%s entry=%s", titleID, titleName, userID, settingsScopeKey, entry.ID)
+	go func() {
+		defer run.release()
+		h.runPrequeueWorker(entry.ID, titleID, titleName, imdbID, mediaType, year, userID, clientID, targetEpisode, 0, true)
+
+		h.instantPlay.mu.Lock()
+		if h.instantPlay.byUser[userID].entryID == entry.ID {
+			delete(h.instantPlay.byUser, userID)
+		}
+		h.instantPlay.mu.Unlock()
+	}()
+
+	return &playback.PrequeueResponse{
+		PrequeueID:    entry.ID,
+		TargetEpisode: targetEpisode,
+		Status:        playback.PrequeueStatusQueued,
+	}
+}
+
+// cancelInstantPlay stops the profile's previous speculative resolution if it
+// is for another title, is still running, and has not been claimed by Play.
+// A cancelled run's slot is freed at once rather than when its worker exits.
+func (h *PrequeueHandler) cancelInstantPlay(userID, titleID string) {
+	h.instantPlay.mu.Lock()
+	run, ok := h.instantPlay.byUser[userID]
+	if ok {
+		delete(h.instantPlay.byUser, userID)
+	}
+	h.instantPlay.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	previous, exists := h.store.Get(run.entryID)
+	if !exists || previous.TitleID == titleID || previous.Reason != prequeueReasonInstantPlay || !isPrequeueInProgress(previous.Status) {
+		return
+	}
+	log.Printf("[instant-play] Cancelling speculative entry %s for title=%s user=%s", run.entryID, previous.TitleID, userID)
+	h.store.Delete(run.entryID)
+	if run.release != nil {
+		run.release()
+	}
+}
+
+// claimInstantPlayEntry converts a speculative entry into a regular prequeue
+// once a client request reuses it, so it is no longer cancelled on navigation
+// and is kept alive by prewarm like any other prequeue.
+func (h *PrequeueHandler) claimInstantPlayEntry(id, reason string) {
+	if h.instantPlay == nil {
+		return
+	}
+	claimed := false
+	h.store.Update(id, func(e *playback.PrequeueEntry) {
+		if e.Reason == prequeueReasonInstantPlay {
+			e.Reason = reason
+			claimed = true
+		}
+	})
+	if !claimed {
+		return
+	}
+
+	h.instantPlay.mu.Lock()
+	for userID, run := range h.instantPlay.byUser {
+		if run.entryID == id {
+			delete(h.instantPlay.byUser, userID)
+		}
+	}
+	h.instantPlay.mu.Unlock()
+
+	if h.prewarmSvc != nil {
+		h.prewarmSvc.AdoptEntry(id)
+	}
+	log.Printf("[instant-play] Entry %s claimed by prequeue request (reason=%q)", id, reason)
+}
+
+// prequeueDisabled reports whether automatic prequeue is turned off for the
+// profile, with a client-level override taking precedence.
+func (h *PrequeueHandler) prequeueDisabled(userID, clientID string) bool {
+	disabled := false
+	defaults := models.UserSettings{}
+	if h.configManager != nil {
+		if globalSettings, err := h.configManager.Load(); err == nil {
+			disabled = globalSettings.Playback.DisablePrequeue
+			defaults.Playback = configPlaybackToUserPlayback(globalSettings.Playback)
+			defaults.Playback.DisablePrequeue = globalSettings.Playback.DisablePrequeue
+		}
+	}
+	if h.userSettingsSvc != nil {
+		if userSettings, err := h.userSettingsSvc.GetWithDefaults(userID, defaults); err == nil {
+			disabled = userSettings.Playback.DisablePrequeue
+		}
+	}
+	if clientID != "" && h.clientSettingsSvc != nil {
+		if cs, err := h.clientSettingsSvc.Get(clientID); err == nil && cs != nil && cs.DisablePrequeue != nil {
+			disabled = *cs.DisablePrequeue
+		}
+	}
+	return disabled
+}
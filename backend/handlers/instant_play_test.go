@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"novastream/services/playback"
+)
+
+func newInstantPlayTestHandler() *PrequeueHandler {
+	return &PrequeueHandler{
+		store:       playback.NewPrequeueStore(time.Minute),
+		instantPlay: newInstantPlayState(),
+	}
+}
+
+func TestPreResolveReusesExistingEntry(t *testing.T) {
+	h := newInstantPlayTestHandler()
+	existing, _ := h.store.CreateScoped("tmdb:movie:603", "The Matrix", "user-1", "movie", 1999, nil, "details", playback.DefaultPrequeueSettingsScopeKey)
+	h.store.Update(existing.ID, func(e *playback.PrequeueEntry) {
+		e.Status = playback.PrequeueStatusSearching
+	})
+
+	resp := h.PreResolve("user-1", "", "tmdb:movie:603", "The Matrix", "tt0133093", "movie", 1999)
+	if resp == nil || resp.PrequeueID != existing.ID || resp.Status != playback.PrequeueStatusSearching {
+		t.Fatalf("expected existing in-progress entry to be reused, got %+v", resp)
+	}
+	if len(h.instantPlay.slots) != 0 {
+		t.Fatalf("expected no instant-play slot to be taken, got %d", len(h.instantPlay.slots))
+	}
+}
+
+func TestPreResolveSkipsWhenSlotsBusy(t *testing.T) {
+	h := newInstantPlayTestHandler()
+	for i := 0; i < instantPlayMaxConcurrent; i++ {
+		h.instantPlay.slots <- struct{}{}
+	}
+
+	if resp := h.PreResolve("user-1", "", "tmdb:movie:603", "The Matrix", "", "movie", 1999); resp != nil {
+		t.Fatalf("expected nil when all slots are busy, got %+v", resp)
+	}
+	if _, ok := h.store.GetByTitleUserScope("tmdb:movie:603", "user-1", playback.DefaultPrequeueSettingsScopeKey); ok {
+		t.Fatal("expected no prequeue entry to be created")
+	}
+}
+
+func TestPreResolveIgnoresIncompleteRequests(t *testing.T) {
+	h := newInstantPlayTestHandler()
+	if resp := h.PreResolve("user-1", "", "tmdb:movie:603", "", "", "movie", 0); resp != nil {
+		t.Fatalf("expected nil without a title name, got %+v", resp)
+	}
+	if resp := h.PreResolve("user-1", "", "tmdb:movie:603", "The Matrix", "", "channel", 0); resp != nil {
+		t.Fatalf("expected nil for unsupported media type, got %+v", resp)
+	}
+}
+
+func TestCancelInstantPlay(t *testing.T) {
+	h := newInstantPlayTestHandler()
+	speculative, _ := h.store.CreateScoped("tmdb:movie:1", "First", "user-1", "movie", 0, nil, prequeueReasonInstantPlay, "")
+	h.instantPlay.byUser["user-1"] = instantPlayRun{entryID: speculative.ID}
+
+	h.cancelInstantPlay("user-1", "tmdb:movie:2")
+	if _, ok := h.store.Get(speculative.ID); ok {
+		t.Fatal("expected speculative entry for a previous title to be cancelled")
+	}
+
+	claimed, _ := h.store.CreateScoped("tmdb:movie:3", "Third", "user-1", "movie", 0, nil, prequeueReasonInstantPlay, "")
+	h.instantPlay.byUser["user-1"] = instantPlayRun{entryID: claimed.ID}
+	h.claimInstantPlayEntry(claimed.ID, "details")
+
+	h.cancelInstantPlay("user-1", "tmdb:movie:4")
+	entry, ok := h.store.Get(claimed.ID)
+	if !ok {
+		t.Fatal("expected claimed entry to survive navigation")
+	}
+	if entry.Reason != "details" {
+		t.Fatalf("expected claimed entry reason to be updated, got %q", entry.Reason)
+	}
+}
+
+func TestCancelInstantPlayFreesSlot(t *testing.T) {
+	h := newInstantPlayTestHandler()
+	// The profile's previous run and another profile's run fill every slot.
+	for i := 0; i < instantPlayMaxConcurrent; i++ {
+		h.instantPlay.slots <- struct{}{}
+	}
+	previous, _ := h.store.CreateScoped("tmdb:movie:1", "First", "user-1", "movie", 0, nil, prequeueReasonInstantPlay, "")
+	run := instantPlayRun{entryID: previous.ID, release: sync.OnceFunc(func() { <-h.instantPlay.slots })}
+	h.instantPlay.byUser["user-1"] = run
+
+	h.cancelInstantPlay("user-1", "tmdb:movie:603")
+	if _, ok := h.store.Get(previous.ID); ok {
+		t.Fatal("expected the previous speculative entry to be cancelled")
+	}
+	if got := len(h.instantPlay.slots); got != instantPlayMaxConcurrent-1 {
+		t.Fatalf("expected the cancelled run's slot to be freed, %d of %d still taken", got, instantPlayMaxConcurrent)
+	}
+
+	// The worker exiting later must not free a second slot.
+	run.release()
+	if got := len(h.instantPlay.slots); got != instantPlayMaxConcurrent-1 {
+		t.Fatalf("expected release to be idempotent, %d slots taken", got)
+	}
+}
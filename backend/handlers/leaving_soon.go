@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"novastream/config"
+	"novastream/models"
+	"novastream/services/streamingavailability"
+)
+
+// leavingSoonSource reports watchlist titles about to leave a streaming
+// service.
+type leavingSoonSource interface {
+	IsConfigured() bool
+	LeavingSoon(ctx context.Context, items []models.WatchlistItem, opts streamingavailability.Options) []models.WatchlistItem
+}
+
+// errLeavingSoonUnavailable is returned when no departure data source is
+// configured.
+var errLeavingSoonUnavailable = errors.New("leaving-soon source is unavailable")
+
+// SetLeavingSoon enables the "leaving-soon" source, which lists the
+// profile's watchlist titles leaving one of its streaming services.
+func (h *DisplayListHandler) SetLeavingSoon(source leavingSoonSource, cfg *config.Manager, userSettings userSettingsProvider) {
+	h.LeavingSoon = source
+	h.cfgManager = cfg
+	h.userSettings = userSettings
+}
+
+// leavingSoonItems returns the profile's watchlist titles leaving soon,
+// soonest first.
+func (h *DisplayListHandler) leavingSoonItems(ctx context.Context, userID string) ([]models.WatchlistItem, error) {
+	if h.LeavingSoon == nil || !h.LeavingSoon.IsConfigured() || h.WatchlistService == nil || h.cfgManager == nil {
+		return nil, errLeavingSoonUnavailable
+	}
+	settings, err := h.cfgManager.Load()
+	if err != nil {
+		return nil, err
+	}
+	var profile *models.UserSettings
+	if h.userSettings != nil {
+		profile, _ = h.userSettings.Get(userID)
+	}
+	items, err := h.WatchlistService.List(userID)
+	if err != nil {
+		return nil, err
+	}
+	return h.LeavingSoon.LeavingSoon(ctx, items, streamingavailability.OptionsFor(settings, profile)), nil
+}
@@ -0,0 +1,298 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"novastream/models"
+	"novastream/services/listshares"
+)
+
+type listShareService interface {
+	Create(userID, listType, listID, title string) (models.ListShare, error)
+	GetByToken(token string) (models.ListShare, error)
+	ListByUser(userID string) []models.ListShare
+	Revoke(userID, id string) error
+}
+
+var _ listShareService = (*listshares.Service)(nil)
+
+type listShareWatchlist interface {
+	List(userID string) ([]models.WatchlistItem, error)
+}
+
+type listShareCustomLists interface {
+	ListLists(userID string) ([]models.CustomList, error)
+	ListItems(userID, listID string) ([]models.WatchlistItem, error)
+}
+
+type listShareUsers interface {
+	Get(id string) (models.User, bool)
+}
+
+// ListSharesHandler manages public read-only links to watchlists and custom
+// lists, and serves the shared views at /shared/lists/{token} without auth.
+type ListSharesHandler struct {
+	Service        listShareService
+	Watchlist      listShareWatchlist
+	CustomLists    listShareCustomLists
+	Users          listShareUsers
+	serverBasePath string
+}
+
+// NewListSharesHandler creates a ListSharesHandler. serverBasePath prefixes
+// the links it hands out, as with playback share links.
+func NewListSharesHandler(service listShareService, watchlist listShareWatchlist, customLists listShareCustomLists, users listShareUsers, serverBasePath string) *ListSharesHandler {
+	serverBasePath = "/" + strings.Trim(serverBasePath, "/")
+	if serverBasePath == "/" {
+		serverBasePath = ""
+	}
+	return &ListSharesHandler{
+		Service:        service,
+		Watchlist:      watchlist,
+		CustomLists:    customLists,
+		Users:          users,
+		serverBasePath: serverBasePath,
+	}
+}
+
+type listShareResponse struct {
+	models.ListShare
+	URL string `json:"url"`
+}
+
+func (h *ListSharesHandler) withURL(share models.ListShare) listShareResponse {
+	return listShareResponse{ListShare: share, URL: h.serverBasePath + "/shared/lists/" + share.Token}
+}
+
+// List returns the profile's share links.
+func (h *ListSharesHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+	shares := h.Service.ListByUser(userID)
+	resp := make([]listShareResponse, 0, len(shares))
+	for _, share := range shares {
+		resp = append(resp, h.withURL(share))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Create makes a share link for the profile's watchlist or one of its
+// custom lists.
+func (h *ListSharesHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		ListType string `json:"listType"`
+		ListID   string `json:"listId"`
+		Title    string `json:"title"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSONError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if strings.EqualFold(strings.TrimSpace(body.ListType), models.ShareListCustom) {
+		if _, found := h.customList(userID, strings.TrimSpace(body.ListID)); !found {
+			writeJSONError(w, "list not found", http.StatusNotFound)
+			return
+		}
+	}
+
+	share, err := h.Service.Create(userID, body.ListType, body.ListID, body.Title)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, listshares.ErrInvalidListType), errors.Is(err, listshares.ErrListIDRequired):
+			status = http.StatusBadRequest
+		}
+		writeJSONError(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(h.withURL(share))
+}
+
+// Revoke deletes one of the profile's share links.
+func (h *ListSharesHandler) Revoke(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+	if err := h.Service.Revoke(userID, mux.Vars(r)["shareID"]); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, listshares.ErrShareNotFound) {
+			status = http.StatusNotFound
+		}
+		writeJSONError(w, err.Error(), status)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *ListSharesHandler) Options(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+// sharedListItem is the public view of a list entry. It leaves out watch
+// state, sync sources and anything else about the profile.
+type sharedListItem struct {
+	Name        string            `json:"name"`
+	MediaType   string            `json:"mediaType"`
+	Year        int               `json:"year,omitempty"`
+	Overview    string            `json:"overview,omitempty"`
+	PosterURL   string            `json:"posterUrl,omitempty"`
+	ExternalIDs map[string]string `json:"externalIds,omitempty"`
+}
+
+type sharedListView struct {
+	Title    string           `json:"title"`
+	SharedBy string           `json:"sharedBy,omitempty"`
+	Items    []sharedListItem `json:"items"`
+}
+
+// View renders a shared list (GET /shared/lists/{token}). It needs no auth:
+// the token is the credential. Browsers get an HTML page; API clients and
+// ?format=json get JSON. Revoked or unknown links return 404.
+func (h *ListSharesHandler) View(w http.ResponseWriter, r *http.Request) {
+	token := strings.TrimSpace(mux.Vars(r)["token"])
+	share, err := h.Service.GetByToken(token)
+	if err != nil {
+		h.writeUnavailable(w, r)
+		return
+	}
+
+	view := sharedListView{Title: share.Title}
+	var items []models.WatchlistItem
+	switch share.ListType {
+	case models.ShareListWatchlist:
+		if h.Watchlist != nil {
+			items, err = h.Watchlist.List(share.UserID)
+		}
+		if view.Title == "" {
+			view.Title = "Watchlist"
+		}
+	case models.ShareListCustom:
+		list, found := h.customList(share.UserID, share.ListID)
+		if !found {
+			h.writeUnavailable(w, r)
+			return
+		}
+		items, err = h.CustomLists.ListItems(share.UserID, share.ListID)
+		if view.Title == "" {
+			view.Title = list.Name
+		}
+	}
+	if err != nil {
+		writeJSONError(w, "failed to load list", http.StatusInternalServerError)
+		return
+	}
+	if h.Users != nil {
+		if user, ok := h.Users.Get(share.UserID); ok {
+			view.SharedBy = user.Name
+		}
+	}
+
+	view.Items = make([]sharedListItem, 0, len(items))
+	for _, item := range items {
+		view.Items = append(view.Items, sharedListItem{
+			Name:        item.Name,
+			MediaType:   item.MediaType,
+			Year:        item.Year,
+			Overview:    item.Overview,
+			PosterURL:   item.PosterURL,
+			ExternalIDs: item.ExternalIDs,
+		})
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	if !wantsSharedListHTML(r) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(view)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	sharedListTemplate.Execute(w, view)
+}
+
+func (h *ListSharesHandler) customList(userID, listID string) (models.CustomList, bool) {
+	if h.CustomLists == nil || listID == "" {
+		return models.CustomList{}, false
+	}
+	lists, err := h.CustomLists.ListLists(userID)
+	if err != nil {
+		return models.CustomList{}, false
+	}
+	for _, list := range lists {
+		if list.ID == listID {
+			return list, true
+		}
+	}
+	return models.CustomList{}, false
+}
+
+func (h *ListSharesHandler) writeUnavailable(w http.ResponseWriter, r *http.Request) {
+	if !wantsSharedListHTML(r) {
+		writeJSONError(w, "shared list not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusNotFound)
+	sharedListTemplate.Execute(w, sharedListView{Title: "This list is no longer shared"})
+}
+
+func (h *ListSharesHandler) requireUser(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID := strings.TrimSpace(mux.Vars(r)["userID"])
+	if userID == "" {
+		writeJSONError(w, "user id is required", http.StatusBadRequest)
+		return "", false
+	}
+	if h.Users != nil {
+		if _, ok := h.Users.Get(userID); !ok {
+			writeJSONError(w, "user not found", http.StatusNotFound)
+			return "", false
+		}
+	}
+	return userID, true
+}
+
+// wantsSharedListHTML reports whether the shared view should be rendered as a
+// page: an explicit ?format wins, otherwise browsers asking for HTML get it.
+func wantsSharedListHTML(r *http.Request) bool {
+	switch strings.ToLower(r.URL.Query().Get("format")) {
+	case "json":
+		return false
+	case "html":
+		return true
+	}
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
+
+var sharedListTemplate = template.Must(template.New("shared-list").Parse(`<!DOCTYPE html><html lang="en"><head><meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Title}}</title><style>
+body{margin:0;background:#0b0d12;color:#e7eaf0;font-family:-apple-system,BlinkMacSystemFont,"Segoe UI",Roboto,sans-serif}
+main{max-width:960px;margin:0 auto;padding:32px 20px}h1{font-size:24px;margin:0 0 4px}
+.by{opacity:.6;margin:0 0 24px}.grid{display:grid;grid-template-columns:repeat(auto-fill,minmax(150px,1fr));gap:20px}
+.item img,.item .ph{width:100%;aspect-ratio:2/3;object-fit:cover;border-radius:8px;background:#1a1e27}
+.name{font-size:14px;margin:8px 0 2px}.meta{font-size:12px;opacity:.6}
+</style></head><body><main><h1>{{.Title}}</h1>
+{{if .SharedBy}}<p class="by">Shared by {{.SharedBy}}</p>{{end}}
+<div class="grid">{{range .Items}}<div class="item">
+{{if .PosterURL}}<img src="{{.PosterURL}}" alt="" loading="lazy">{{else}}<div class="ph"></div>{{end}}
+<div class="name">{{.Name}}</div><div class="meta">{{if .Year}}{{.Year}} · {{end}}{{if eq .MediaType "series"}}Series{{else}}Movie{{end}}</div>
+</div>{{end}}</div></main></body></html>`))
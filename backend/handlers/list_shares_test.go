@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"novastream/models"
+	"novastream/services/listshares"
+)
+
+type stubShareWatchlist map[string][]models.WatchlistItem
+
+func (s stubShareWatchlist) List(userID string) ([]models.WatchlistItem, error) {
+	return s[userID], nil
+}
+
+type stubShareCustomLists struct {
+	lists map[string][]models.CustomList
+	items map[string][]models.WatchlistItem
+}
+
+func (s stubShareCustomLists) ListLists(userID string) ([]models.CustomList, error) {
+	return s.lists[userID], nil
+}
+
+func (s stubShareCustomLists) ListItems(userID, listID string) ([]models.WatchlistItem, error) {
+	return s.items[listID], nil
+}
+
+type stubShareUsers map[string]models.User
+
+func (s stubShareUsers) Get(id string) (models.User, bool) {
+	user, ok := s[id]
+	return user, ok
+}
+
+func newTestListSharesHandler(t *testing.T) *ListSharesHandler {
+	t.Helper()
+	svc, err := listshares.NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	watchlist := stubShareWatchlist{
+		"u1": {{ID: "603", MediaType: "movie", Name: "The Matrix", Year: 1999, SyncSource: "plex:acc:task", WatchState: "complete"}},
+	}
+	customLists := stubShareCustomLists{
+		lists: map[string][]models.CustomList{"u1": {{ID: "list-1", Name: "Comfort Films"}}},
+		items: map[string][]models.WatchlistItem{"list-1": {{ID: "8587", MediaType: "movie", Name: "The Lion King"}}},
+	}
+	users := stubShareUsers{"u1": {ID: "u1", Name: "Sam"}}
+	return NewListSharesHandler(svc, watchlist, customLists, users, "/mediastorm")
+}
+
+func createListShare(t *testing.T, h *ListSharesHandler, body string) listShareResponse {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/api/users/u1/shares", strings.NewReader(body))
+	req = mux.SetURLVars(req, map[string]string{"userID": "u1"})
+	rec := httptest.NewRecorder()
+	h.Create(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: expected %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	var share listShareResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &share); err != nil {
+		t.Fatalf("decode share: %v", err)
+	}
+	return share
+}
+
+func viewListShare(h *ListSharesHandler, token, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/shared/lists/"+token, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	req = mux.SetURLVars(req, map[string]string{"token": token})
+	rec := httptest.NewRecorder()
+	h.View(rec, req)
+	return rec
+}
+
+func TestListShareWatchlistView(t *testing.T) {
+	h := newTestListSharesHandler(t)
+	share := createListShare(t, h, `{"listType":"watchlist"}`)
+	if share.URL != "/mediastorm/shared/lists/"+share.Token {
+		t.Fatalf("unexpected url %q", share.URL)
+	}
+
+	rec := viewListShare(h, share.Token, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("view: expected %d, got %d", http.StatusOK, rec.Code)
+	}
+	var view sharedListView
+	if err := json.Unmarshal(rec.Body.Bytes(), &view); err != nil {
+		t.Fatalf("decode view: %v", err)
+	}
+	if view.Title != "Watchlist" || view.SharedBy != "Sam" || len(view.Items) != 1 || view.Items[0].Name != "The Matrix" {
+		t.Fatalf("unexpected view %+v", view)
+	}
+	// Profile details such as sync sources and watch state stay private.
+	if strings.Contains(rec.Body.String(), "plex:acc:task") || strings.Contains(rec.Body.String(), "watchState") {
+		t.Fatalf("view leaked profile details: %s", rec.Body.String())
+	}
+
+	html := viewListShare(h, share.Token, "text/html,application/xhtml+xml")
+	if ct := html.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/html") {
+		t.Fatalf("expected html for browsers, got %q", ct)
+	}
+	if !strings.Contains(html.Body.String(), "The Matrix") {
+		t.Fatalf("html view missing item: %s", html.Body.String())
+	}
+}
+
+func TestListShareCustomListAndRevoke(t *testing.T) {
+	h := newTestListSharesHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/users/u1/shares", strings.NewReader(`{"listType":"custom","listId":"missing"}`))
+	req = mux.SetURLVars(req, map[string]string{"userID": "u1"})
+	rec := httptest.NewRecorder()
+	h.Create(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected %d for unknown list, got %d", http.StatusNotFound, rec.Code)
+	}
+
+	share := createListShare(t, h, `{"listType":"custom","listId":"list-1"}`)
+	rec = viewListShare(h, share.Token, "")
+	var view sharedListView
+	if err := json.Unmarshal(rec.Body.Bytes(), &view); err != nil {
+		t.Fatalf("decode view: %v", err)
+	}
+	if view.Title != "Comfort Films" || len(view.Items) != 1 {
+		t.Fatalf("unexpected view %+v", view)
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/users/u1/shares/"+share.ID, nil)
+	req = mux.SetURLVars(req, map[string]string{"userID": "u1", "shareID": share.ID})
+	rec = httptest.NewRecorder()
+	h.Revoke(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("revoke: expected %d, got %d", http.StatusNoContent, rec.Code)
+	}
+	if rec := viewListShare(h, share.Token, ""); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected revoked link to 404, got %d", rec.Code)
+	}
+}
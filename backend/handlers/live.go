@@ -34,7 +34,7 @@ const (
 	playlistContentTypePlain = "text/plain; charset=utf-8"
 	liveStreamTimeout        = 30 * time.Minute
 	defaultCacheTTL          = 24 * time.Hour
-	cacheDir                 = "cache/live"
+	defaultLiveCacheDir      = "cache/live"
 
 	// liveStreamUserAgent is sent on all upstream live stream requests. Some
 	// providers redirect .ts requests to tokenized CDN nodes that drop any
@@ -66,6 +66,8 @@ type LiveChannel struct {
 	SourceID    string `json:"sourceId,omitempty"`
 	SourceName  string `json:"sourceName,omitempty"`
 	StreamURL   string `json:"streamUrl,omitempty"` // Backend-proxied stream URL
+	Favorite    bool   `json:"favorite,omitempty"`  // In the profile's favorites
+	Hidden      bool   `json:"hidden,omitempty"`    // Hidden by the profile (only returned with includeHidden)
 }
 
 // LiveSourceOption represents a selectable M3U source exposed to clients.
@@ -171,6 +173,7 @@ type LiveHandler struct {
 	lowLatency         bool // Enable low-latency mode
 	cfgManager         *config.Manager
 	userSettingsSvc    LiveUserSettingsProvider
+	cacheDir           string // directory holding cached playlists
 
 	stremioMu    sync.Mutex
 	stremioCache map[string]stremioChannelsCacheEntry
@@ -186,11 +189,6 @@ func NewLiveHandler(client *http.Client, transmuxEnabled bool, ffmpegPath string
 		}
 	}
 
-	// Ensure cache directory exists
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
-		log.Printf("[live] failed to create cache directory: %v", err)
-	}
-
 	cacheTTL := defaultCacheTTL
 	if cacheTTLHours > 0 {
 		cacheTTL = time.Duration(cacheTTLHours) * time.Hour
@@ -207,6 +205,7 @@ func NewLiveHandler(client *http.Client, transmuxEnabled bool, ffmpegPath string
 		lowLatency:         lowLatency,
 		cfgManager:         cfgManager,
 		userSettingsSvc:    userSettingsSvc,
+		cacheDir:           defaultLiveCacheDir,
 		stremioCache:       make(map[string]stremioChannelsCacheEntry),
 	}
 }
@@ -646,11 +645,11 @@ func (h *LiveHandler) getCacheKey(playlistURL string) string {
 }
 
 func (h *LiveHandler) getCacheFilePath(key string) string {
-	return filepath.Join(cacheDir, key+".m3u")
+	return filepath.Join(h.cacheDir, key+".m3u")
 }
 
 func (h *LiveHandler) getMetaFilePath(key string) string {
-	return filepath.Join(cacheDir, key+".meta")
+	return filepath.Join(h.cacheDir, key+".meta")
 }
 
 func (h *LiveHandler) getFromCache(key string) ([]byte, string, error) {
@@ -691,7 +690,7 @@ func (h *LiveHandler) saveToCache(key string, data []byte, contentType string) e
 	defer h.cacheMu.Unlock()
 
 	// Ensure cache directory exists
-	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+	if err := os.MkdirAll(h.cacheDir, 0755); err != nil {
 		return fmt.Errorf("failed to create cache directory: %w", err)
 	}
 
@@ -717,7 +716,7 @@ func (h *LiveHandler) ClearCache(w http.ResponseWriter, r *http.Request) {
 	defer h.cacheMu.Unlock()
 
 	// Read all files in cache directory
-	entries, err := os.ReadDir(cacheDir)
+	entries, err := os.ReadDir(h.cacheDir)
 	if err != nil {
 		if os.IsNotExist(err) {
 			// Cache directory doesn't exist, nothing to clear
@@ -738,7 +737,7 @@ func (h *LiveHandler) ClearCache(w http.ResponseWriter, r *http.Request) {
 		name := entry.Name()
 		// Only remove .m3u and .meta files
 		if strings.HasSuffix(name, ".m3u") || strings.HasSuffix(name, ".meta") {
-			path := filepath.Join(cacheDir, name)
+			path := filepath.Join(h.cacheDir, name)
 			if err := os.Remove(path); err != nil {
 				log.Printf("[live] failed to remove cache file %s: %v", name, err)
 			} else {
@@ -1495,7 +1494,12 @@ func (h *LiveHandler) isXtreamMode() bool {
 // If a profileId query parameter is provided and the profile has per-profile
 // IPTV overrides, those are merged with the global settings.
 func (h *LiveHandler) resolveProfileLiveSource(r *http.Request, globalSettings config.Settings) models.ResolvedLiveSource {
-	profileID := r.URL.Query().Get("profileId")
+	return h.liveSourceForProfile(r.URL.Query().Get("profileId"), globalSettings)
+}
+
+// liveSourceForProfile resolves the Live TV source of profileID (empty for
+// the global source).
+func (h *LiveHandler) liveSourceForProfile(profileID string, globalSettings config.Settings) models.ResolvedLiveSource {
 	globalSettings = config.FilterSettingsForProfile(globalSettings, profileID)
 	global := models.ResolvedLiveSource{
 		Mode:                    globalSettings.Live.Mode,
@@ -1569,37 +1573,23 @@ func (h *LiveHandler) GetChannels(w http.ResponseWriter, r *http.Request) {
 		if liveSource.HasFilterOverride {
 			sourceFilter = liveSource.Filter
 		}
-		var sourceChannels []LiveChannel
-		if liveSource.Mode == "xtream" {
-			channels, err := h.fetchXtreamChannels(r.Context(), liveSource.XtreamHost, liveSource.XtreamUsername, liveSource.XtreamPassword, liveSource.ProxyURL)
-			if err != nil {
-				log.Printf("[live] GetChannels Xtream error for source %q: %v", liveSource.ID, err)
-				http.Error(w, `{"error":"failed to fetch channels"}`, http.StatusBadGateway)
-				return
-			}
-			sourceChannels = channels
-		} else if liveSource.Mode == "stremio" {
-			channels, err := h.fetchStremioChannels(r.Context(), liveSource.ManifestURL, liveSource.ProxyURL)
-			if err != nil {
-				log.Printf("[live] GetChannels Stremio error for source %q: %v", liveSource.ID, err)
+		sourceChannels, err := h.fetchSourceChannels(r.Context(), liveSource)
+		if err != nil {
+			log.Printf("[live] GetChannels %s error for source %q: %v", liveSource.Mode, liveSource.ID, err)
+			if liveSource.Mode == "xtream" || liveSource.Mode == "stremio" {
 				http.Error(w, `{"error":"failed to fetch channels"}`, http.StatusBadGateway)
-				return
-			}
-			sourceChannels = channels
-		} else {
-			contents, err := h.fetchPlaylistContents(r.Context(), liveSource.PlaylistURL, liveSource.ProxyURL)
-			if err != nil {
-				log.Printf("[live] GetChannels error for source %q: %v", liveSource.ID, err)
+			} else {
 				http.Error(w, `{"error":"failed to fetch playlist"}`, http.StatusBadGateway)
-				return
 			}
-			sourceChannels = parseM3UPlaylist(contents)
+			return
 		}
 		totalBeforeFilter += len(sourceChannels)
 		allChannels = append(allChannels, tagChannelsWithSource(filterChannels(sourceChannels, sourceFilter), liveSource, includeSourceInID)...)
 	}
 
-	filteredChannels := allChannels
+	// Apply the profile's favorites, hidden channels and custom order.
+	includeHidden := r.URL.Query().Get("includeHidden") == "true"
+	filteredChannels := applyChannelLineup(allChannels, profileLiveLineup(r, h.userSettingsSvc), includeHidden)
 
 	// Extract available categories from filtered channels (only categories with actual channels)
 	categoryInfos := extractCategories(filteredChannels)
@@ -1624,6 +1614,22 @@ func (h *LiveHandler) GetChannels(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// fetchSourceChannels fetches the unfiltered channel list of one source.
+func (h *LiveHandler) fetchSourceChannels(ctx context.Context, liveSource resolvedM3USource) ([]LiveChannel, error) {
+	switch liveSource.Mode {
+	case "xtream":
+		return h.fetchXtreamChannels(ctx, liveSource.XtreamHost, liveSource.XtreamUsername, liveSource.XtreamPassword, liveSource.ProxyURL)
+	case "stremio":
+		return h.fetchStremioChannels(ctx, liveSource.ManifestURL, liveSource.ProxyURL)
+	default:
+		contents, err := h.fetchPlaylistContents(ctx, liveSource.PlaylistURL, liveSource.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		return parseM3UPlaylist(contents), nil
+	}
+}
+
 // GetCategories returns all available categories from the configured playlist.
 func (h *LiveHandler) GetCategories(w http.ResponseWriter, r *http.Request) {
 	categoryCounts := make(map[string]int)
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"novastream/models"
+)
+
+// LiveLineup is a profile's personal channel lineup: favorites are listed
+// first, hidden channels are dropped, and ChannelOrder positions the rest.
+type LiveLineup struct {
+	FavoriteChannels []string `json:"favoriteChannels"`
+	HiddenChannels   []string `json:"hiddenChannels"`
+	ChannelOrder     []string `json:"channelOrder"`
+}
+
+// LiveLineupUpdate is the body of PUT /users/{userID}/live/lineup. Omitted
+// fields are left unchanged.
+type LiveLineupUpdate struct {
+	FavoriteChannels *[]string `json:"favoriteChannels"`
+	HiddenChannels   *[]string `json:"hiddenChannels"`
+	ChannelOrder     *[]string `json:"channelOrder"`
+}
+
+func liveLineupFromSettings(s models.LiveTVSettings) LiveLineup {
+	return LiveLineup{
+		FavoriteChannels: normalizeChannelIDs(s.FavoriteChannels),
+		HiddenChannels:   normalizeChannelIDs(s.HiddenChannels),
+		ChannelOrder:     normalizeChannelIDs(s.ChannelOrder),
+	}
+}
+
+func (l LiveLineup) isEmpty() bool {
+	return len(l.FavoriteChannels) == 0 && len(l.HiddenChannels) == 0 && len(l.ChannelOrder) == 0
+}
+
+// normalizeChannelIDs trims, de-duplicates and drops empty IDs, keeping order.
+func normalizeChannelIDs(ids []string) []string {
+	out := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		out = append(out, id)
+	}
+	return out
+}
+
+// applyChannelLineup marks favorite and hidden channels and orders them for
+// the profile: favorites first, then channels listed in ChannelOrder, then
+// the rest in playlist order. Hidden channels are dropped unless
+// includeHidden is set (for lineup editors that need to unhide them).
+func applyChannelLineup(channels []LiveChannel, lineup LiveLineup, includeHidden bool) []LiveChannel {
+	if lineup.isEmpty() {
+		return channels
+	}
+	favorites := make(map[string]bool, len(lineup.FavoriteChannels))
+	for _, id := range lineup.FavoriteChannels {
+		favorites[id] = true
+	}
+	hidden := make(map[string]bool, len(lineup.HiddenChannels))
+	for _, id := range lineup.HiddenChannels {
+		hidden[id] = true
+	}
+	position := make(map[string]int, len(lineup.ChannelOrder))
+	for i, id := range lineup.ChannelOrder {
+		position[id] = i
+	}
+
+	out := make([]LiveChannel, 0, len(channels))
+	for _, ch := range channels {
+		ch.Favorite = favorites[ch.ID]
+		ch.Hidden = hidden[ch.ID]
+		if ch.Hidden && !includeHidden {
+			continue
+		}
+		out = append(out, ch)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].Favorite != out[j].Favorite {
+			return out[i].Favorite
+		}
+		pi, iOrdered := position[out[i].ID]
+		pj, jOrdered := position[out[j].ID]
+		if iOrdered != jOrdered {
+			return iOrdered
+		}
+		return iOrdered && pi < pj
+	})
+	return out
+}
+
+// profileLiveLineup returns the lineup of the request's profileId, if any.
+func profileLiveLineup(r *http.Request, userSettingsSvc LiveUserSettingsProvider) LiveLineup {
+	profileID := r.URL.Query().Get("profileId")
+	if profileID == "" || userSettingsSvc == nil {
+		return LiveLineup{}
+	}
+	userSettings, err := userSettingsSvc.Get(profileID)
+	if err != nil || userSettings == nil {
+		return LiveLineup{}
+	}
+	return liveLineupFromSettings(userSettings.LiveTV)
+}
+
+// guideLineup maps a profile's lineup onto EPG channel IDs (tvg-id), which
+// is how guide data is requested.
+type guideLineup struct {
+	hidden map[string]bool
+	rank   map[string]int
+}
+
+// resolveGuideLineup returns the EPG view of the request profile's lineup,
+// or nil when the profile has not customized its lineup.
+func (h *LiveHandler) resolveGuideLineup(r *http.Request) (*guideLineup, error) {
+	lineup := profileLiveLineup(r, h.userSettingsSvc)
+	if lineup.isEmpty() {
+		return nil, nil
+	}
+	settings, err := h.cfgManager.Load()
+	if err != nil {
+		return nil, err
+	}
+	src := h.resolveProfileLiveSource(r, settings)
+	sources := resolvedLiveSources(src)
+	includeSourceInID := len(sources) > 1
+
+	var channels []LiveChannel
+	for _, liveSource := range sources {
+		sourceChannels, err := h.fetchSourceChannels(r.Context(), liveSource)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, tagChannelsWithSource(sourceChannels, liveSource, includeSourceInID)...)
+	}
+
+	guide := &guideLineup{hidden: make(map[string]bool), rank: make(map[string]int)}
+	for i, ch := range applyChannelLineup(channels, lineup, true) {
+		key := strings.ToLower(ch.TvgID)
+		if key == "" {
+			continue
+		}
+		if ch.Hidden {
+			if _, visible := guide.rank[key]; !visible {
+				guide.hidden[key] = true
+			}
+			continue
+		}
+		// A guide ID shared with a visible channel stays in the guide.
+		delete(guide.hidden, key)
+		if _, ok := guide.rank[key]; !ok {
+			guide.rank[key] = i
+		}
+	}
+	return guide, nil
+}
+
+// filter drops guide channels that belong only to hidden channels.
+func (g *guideLineup) filter(channelIDs []string) []string {
+	if g == nil {
+		return channelIDs
+	}
+	out := channelIDs[:0]
+	for _, id := range channelIDs {
+		if !g.hides(id) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// hides reports whether a guide channel belongs only to hidden channels.
+func (g *guideLineup) hides(channelID string) bool {
+	return g != nil && g.hidden[strings.ToLower(channelID)]
+}
+
+// sortNowPlaying orders guide rows by the profile's lineup; channels the
+// lineup doesn't know keep their requested order at the end.
+func (g *guideLineup) sortNowPlaying(rows []models.EPGNowPlaying) {
+	if g == nil {
+		return
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		ri, iOK := g.rank[strings.ToLower(rows[i].ChannelID)]
+		rj, jOK := g.rank[strings.ToLower(rows[j].ChannelID)]
+		if iOK != jOK {
+			return iOK
+		}
+		return iOK && ri < rj
+	})
+}
+
+// GetLiveLineup returns the profile's favorite, hidden and ordered channels.
+func (h *UserSettingsHandler) GetLiveLineup(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	settings, err := h.Service.Get(userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var live models.LiveTVSettings
+	if settings != nil {
+		live = settings.LiveTV
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(liveLineupFromSettings(live))
+}
+
+// PutLiveLineup updates the profile's channel lineup, leaving its other
+// settings untouched.
+func (h *UserSettingsHandler) PutLiveLineup(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	var update LiveLineupUpdate
+	if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	existing, err := h.Service.Get(userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var settings models.UserSettings
+	if existing != nil {
+		settings = *existing
+	}
+	if update.FavoriteChannels != nil {
+		settings.LiveTV.FavoriteChannels = normalizeChannelIDs(*update.FavoriteChannels)
+	}
+	if update.HiddenChannels != nil {
+		settings.LiveTV.HiddenChannels = normalizeChannelIDs(*update.HiddenChannels)
+	}
+	if update.ChannelOrder != nil {
+		settings.LiveTV.ChannelOrder = normalizeChannelIDs(*update.ChannelOrder)
+	}
+
+	if err := h.Service.Update(userID, settings); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(liveLineupFromSettings(settings.LiveTV))
+}
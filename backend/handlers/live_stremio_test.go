@@ -165,6 +165,7 @@ func TestWarmPlaylistCacheIncludesStremioSource(t *testing.T) {
 	}
 
 	h := NewLiveHandler(srv.Client(), false, "", 24, 0, 0, false, mgr, nil)
+	h.cacheDir = t.TempDir()
 	got, err := h.WarmPlaylistCache(context.Background())
 	if err != nil {
 		t.Fatalf("WarmPlaylistCache error: %v", err)
@@ -189,6 +190,7 @@ func TestTopLevelStremioSourceBuildsChannels(t *testing.T) {
 	}
 
 	h := NewLiveHandler(srv.Client(), false, "", 24, 0, 0, false, mgr, nil)
+	h.cacheDir = t.TempDir()
 	req := httptest.NewRequest(http.MethodGet, "/live/channels", nil)
 	rec := httptest.NewRecorder()
 	h.GetChannels(rec, req)
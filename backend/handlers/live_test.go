@@ -303,6 +303,7 @@ http://stream.example/sports`))
 	}
 
 	h := NewLiveHandler(playlistServer.Client(), false, "", 24, 0, 0, false, mgr, nil)
+	h.cacheDir = t.TempDir()
 	req := httptest.NewRequest(http.MethodGet, "/live/channels?sourceId=sports-src", nil)
 	rec := httptest.NewRecorder()
 	h.GetChannels(rec, req)
@@ -361,6 +362,7 @@ http://stream.example/sports`))
 	}
 
 	h := NewLiveHandler(playlistServer.Client(), false, "", 24, 0, 0, false, mgr, nil)
+	h.cacheDir = t.TempDir()
 	req := httptest.NewRequest(http.MethodGet, "/live/channels", nil)
 	rec := httptest.NewRecorder()
 	h.GetChannels(rec, req)
@@ -390,6 +392,85 @@ func (m *mockUserSettingsProvider) Get(userID string) (*models.UserSettings, err
 	return nil, nil
 }
 
+func TestGetChannelsAppliesProfileLineup(t *testing.T) {
+	playlistServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`#EXTM3U
+#EXTINF:-1 tvg-id="news",News
+http://stream.example/news
+#EXTINF:-1 tvg-id="sports",Sports
+http://stream.example/sports
+#EXTINF:-1 tvg-id="kids",Kids
+http://stream.example/kids
+#EXTINF:-1 tvg-id="movies",Movies
+http://stream.example/movies`))
+	}))
+	defer playlistServer.Close()
+
+	mgr := config.NewManager(filepath.Join(t.TempDir(), "settings.json"))
+	if err := mgr.Save(config.Settings{
+		Live: config.LiveSettings{Mode: "m3u", PlaylistURL: playlistServer.URL},
+	}); err != nil {
+		t.Fatalf("save settings: %v", err)
+	}
+	provider := &mockUserSettingsProvider{settings: map[string]*models.UserSettings{
+		"parent": {LiveTV: models.LiveTVSettings{
+			FavoriteChannels: []string{"movies"},
+			HiddenChannels:   []string{"kids"},
+			ChannelOrder:     []string{"sports", "news"},
+		}},
+	}}
+	h := NewLiveHandler(playlistServer.Client(), false, "", 24, 0, 0, false, mgr, provider)
+	h.cacheDir = t.TempDir()
+
+	var favorites []string
+	channelIDs := func(query string) []string {
+		req := httptest.NewRequest(http.MethodGet, "/live/channels?"+query, nil)
+		rec := httptest.NewRecorder()
+		h.GetChannels(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, body=%s", rec.Code, rec.Body.String())
+		}
+		var resp LiveChannelsResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		ids := make([]string, len(resp.Channels))
+		for i, ch := range resp.Channels {
+			ids[i] = ch.ID
+			if ch.Favorite {
+				favorites = append(favorites, ch.ID)
+			}
+		}
+		return ids
+	}
+
+	if got := strings.Join(channelIDs("profileId=parent"), ","); got != "movies,sports,news" {
+		t.Fatalf("parent lineup = %s, want movies,sports,news", got)
+	}
+	if len(favorites) != 1 || favorites[0] != "movies" {
+		t.Fatalf("favorites = %v, want [movies]", favorites)
+	}
+	if got := strings.Join(channelIDs("profileId=parent&includeHidden=true"), ","); got != "movies,sports,news,kids" {
+		t.Fatalf("parent lineup with hidden = %s, want movies,sports,news,kids", got)
+	}
+	if got := strings.Join(channelIDs("profileId=child"), ","); got != "news,sports,kids,movies" {
+		t.Fatalf("default lineup = %s, want playlist order", got)
+	}
+
+	guide, err := h.resolveGuideLineup(httptest.NewRequest(http.MethodGet, "/live/epg/now?profileId=parent", nil))
+	if err != nil || guide == nil {
+		t.Fatalf("resolveGuideLineup() = %v, %v", guide, err)
+	}
+	if got := strings.Join(guide.filter([]string{"news", "KIDS", "movies"}), ","); got != "news,movies" {
+		t.Fatalf("guide filter = %s, want hidden kids dropped", got)
+	}
+	rows := []models.EPGNowPlaying{{ChannelID: "unknown"}, {ChannelID: "news"}, {ChannelID: "Movies"}}
+	guide.sortNowPlaying(rows)
+	if rows[0].ChannelID != "Movies" || rows[1].ChannelID != "news" || rows[2].ChannelID != "unknown" {
+		t.Fatalf("sorted guide rows = %+v", rows)
+	}
+}
+
 func TestResolveProfileLiveSource_NoProfileID(t *testing.T) {
 	h := &LiveHandler{
 		userSettingsSvc: &mockUserSettingsProvider{},
@@ -495,6 +576,7 @@ func TestResolveProfileLiveSource_NilProvider(t *testing.T) {
 
 func TestLiveStreamHTTPClientDoesNotUseBodyTimeout(t *testing.T) {
 	h := NewLiveHandler(nil, false, "", 24, 0, 0, false, nil, nil)
+	h.cacheDir = t.TempDir()
 
 	client := h.liveStreamHTTPClient("")
 	if client.Timeout != 0 {
@@ -515,6 +597,7 @@ func TestLiveStreamHTTPClientDoesNotUseBodyTimeout(t *testing.T) {
 
 func TestLivePlaylistHTTPClientUsesLongBodyTimeout(t *testing.T) {
 	h := NewLiveHandler(nil, false, "", 24, 0, 0, false, nil, nil)
+	h.cacheDir = t.TempDir()
 
 	if h.client.Timeout != defaultPlaylistTimeout {
 		t.Fatalf("client.Timeout = %v, want %v", h.client.Timeout, defaultPlaylistTimeout)
@@ -531,6 +614,7 @@ func TestLivePlaylistHTTPClientUsesLongBodyTimeout(t *testing.T) {
 
 func TestLivePlaylistScanHTTPClientDoesNotUseBodyTimeout(t *testing.T) {
 	h := NewLiveHandler(nil, false, "", 24, 0, 0, false, nil, nil)
+	h.cacheDir = t.TempDir()
 
 	client := h.livePlaylistScanHTTPClient("")
 	if client.Timeout != 0 {
@@ -557,6 +641,7 @@ func TestFetchM3UCategoriesIgnoresPlaylistBodyLimit(t *testing.T) {
 	defer playlistServer.Close()
 
 	h := NewLiveHandler(playlistServer.Client(), false, "", 24, 0, 0, false, nil, nil)
+	h.cacheDir = t.TempDir()
 	h.maxSize = 64
 
 	categories, err := h.fetchM3UCategories(t.Context(), playlistServer.URL, "")
@@ -619,6 +704,7 @@ func TestStreamChannelWebRequestUsesProxyAndUserAgent(t *testing.T) {
 	}
 
 	h := NewLiveHandler(proxyServer.Client(), true, scriptPath, 24, 0, 0, false, mgr, nil)
+	h.cacheDir = t.TempDir()
 
 	req := httptest.NewRequest(http.MethodGet, "/live/stream?target=web&url=http://provider.example/live/user/pass/1.ts", nil)
 	rec := httptest.NewRecorder()
@@ -657,6 +743,7 @@ func TestFetchXtreamChannelsSendsUserAgent(t *testing.T) {
 
 	mgr := config.NewManager(filepath.Join(t.TempDir(), "settings.json"))
 	h := NewLiveHandler(provider.Client(), false, "", 24, 0, 0, false, mgr, nil)
+	h.cacheDir = t.TempDir()
 
 	channels, err := h.fetchXtreamChannels(context.Background(), provider.URL, "user", "pass", "")
 	if err != nil {
@@ -703,6 +790,7 @@ func TestFetchXtreamChannelsFallsBackToBrowserUA(t *testing.T) {
 
 	mgr := config.NewManager(filepath.Join(t.TempDir(), "settings.json"))
 	h := NewLiveHandler(provider.Client(), false, "", 24, 0, 0, false, mgr, nil)
+	h.cacheDir = t.TempDir()
 
 	channels, err := h.fetchXtreamChannels(context.Background(), provider.URL, "user", "pass", "")
 	if err != nil {
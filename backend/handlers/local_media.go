@@ -77,10 +77,9 @@ func (h *LocalMediaHandler) ListLibraries(w http.ResponseWriter, r *http.Request
 	_ = json.NewEncoder(w).Encode(libraries)
 }
 
-// kidsRatingCaps resolves the (movie, tv) rating caps for the requesting
-// profile when it is a kids profile in rating mode. Returns empty strings when
-// no rating restriction applies.
-func (h *LocalMediaHandler) kidsRatingCaps(r *http.Request) (movieRating, tvRating string) {
+// ratingCaps resolves the (movie, tv) rating caps enforced for the requesting
+// profile. Returns empty strings when no rating restriction applies.
+func (h *LocalMediaHandler) ratingCaps(r *http.Request) (movieRating, tvRating string) {
 	if h.usersSvc == nil {
 		return "", ""
 	}
@@ -88,19 +87,7 @@ func (h *LocalMediaHandler) kidsRatingCaps(r *http.Request) (movieRating, tvRati
 	if profileID == "" {
 		profileID = strings.TrimSpace(r.URL.Query().Get("userId"))
 	}
-	if profileID == "" {
-		return "", ""
-	}
-	user, ok := h.usersSvc.Get(profileID)
-	if !ok || !user.IsKidsProfile || user.KidsMode != "rating" {
-		return "", ""
-	}
-	movieRating = user.KidsMaxMovieRating
-	tvRating = user.KidsMaxTVRating
-	if movieRating == "" && tvRating == "" && user.KidsMaxRating != "" {
-		movieRating = user.KidsMaxRating
-		tvRating = user.KidsMaxRating
-	}
+	movieRating, tvRating, _ = profileRatingLimits(r, h.usersSvc, h.cfgManager, profileID)
 	return movieRating, tvRating
 }
 
@@ -119,7 +106,7 @@ func (h *LocalMediaHandler) ListGroups(w http.ResponseWriter, r *http.Request) {
 
 	// Apply kids profile rating caps so restricted profiles never see local
 	// content above their limit (or content we can't verify a rating for).
-	maxMovieRating, maxTVRating := h.kidsRatingCaps(r)
+	maxMovieRating, maxTVRating := h.ratingCaps(r)
 
 	log.Printf("[localmedia] ListGroups: libraryID=%s limit=%d offset=%d filter=%q sort=%q", libraryID, limit, offset, r.URL.Query().Get("filter"), r.URL.Query().Get("sort"))
 	t0 := time.Now()
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"novastream/models"
+	"novastream/services/kids"
+)
+
+// localSearchService answers searches from titles already fetched, without
+// calling TVDB or TMDB.
+type localSearchService interface {
+	SearchLocal(query, mediaType string, extra []models.Title, limit int) []models.SearchResult
+}
+
+// SearchLocal returns instant search results from the server's local index
+// of cached titles plus the profile's watchlist and watch history. Clients
+// call it alongside /search for search-as-you-type and merge the remote
+// results in when they arrive.
+func (h *MetadataHandler) SearchLocal(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if strings.TrimSpace(q) == "" {
+		q = r.URL.Query().Get("query")
+	}
+	mediaType := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("type")))
+	userID := strings.TrimSpace(r.URL.Query().Get("userId"))
+	limit := 0
+	if parsed, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && parsed > 0 {
+		limit = parsed
+	}
+	service := h.serviceForRequest(r, userID)
+	svc, ok := service.(localSearchService)
+	if !ok {
+		writeJSONError(w, "local search not supported", http.StatusNotImplemented)
+		return
+	}
+
+	results := []models.SearchResult{}
+	restricted := false
+	if userID != "" && h.UsersService != nil {
+		// Curated-list profiles can't search; allow-list profiles rely on
+		// the remote search, which matches results to their list.
+		if user, ok := h.UsersService.Get(userID); ok && user.IsKidsProfile && user.KidsMode == "content_list" {
+			restricted = true
+		}
+	}
+	if _, ok := h.profileAllowList(r, userID); ok {
+		restricted = true
+	}
+	if !restricted {
+		results = svc.SearchLocal(q, mediaType, h.profileTitles(userID), limit)
+		if movieRating, tvRating, ok := h.ratingLimits(r, userID); ok {
+			service.EnrichSearchCertifications(r.Context(), results)
+			results = kids.FilterSearchByRatings(results, movieRating, tvRating)
+		}
+	}
+	if results == nil {
+		results = []models.SearchResult{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.proxyArtwork(r, h.displayTitles(r, userID, results)))
+}
+
+// profileTitles returns the titles on the profile's watchlist and in its
+// watch history, episodes folded into their series.
+func (h *MetadataHandler) profileTitles(userID string) []models.Title {
+	if userID == "" {
+		return nil
+	}
+	var titles []models.Title
+	if h.WatchlistService != nil {
+		if items, err := h.WatchlistService.List(userID); err == nil {
+			for _, item := range items {
+				title := models.Title{ID: item.ID, Name: item.Name, Overview: item.Overview, Year: item.Year, MediaType: item.MediaType, Genres: item.Genres}
+				if item.PosterURL != "" {
+					title.Poster = &models.Image{URL: item.PosterURL, Type: "poster"}
+				}
+				applyExternalIDs(&title, item.ExternalIDs)
+				titles = append(titles, title)
+			}
+		}
+	}
+	if h.HistoryService != nil {
+		if items, err := h.HistoryService.ListWatchHistory(userID); err == nil {
+			seen := make(map[string]bool)
+			for _, item := range items {
+				title := models.Title{ID: item.ItemID, Name: item.Name, Year: item.Year, MediaType: item.MediaType}
+				if item.MediaType == "episode" {
+					// Episodes stand in for their series.
+					title = models.Title{ID: item.SeriesID, Name: item.SeriesName, MediaType: "series"}
+				} else {
+					applyExternalIDs(&title, item.ExternalIDs)
+				}
+				if (title.MediaType != "movie" && title.MediaType != "series") || title.Name == "" || title.ID == "" || seen[title.MediaType+":"+title.ID] {
+					continue
+				}
+				seen[title.MediaType+":"+title.ID] = true
+				titles = append(titles, title)
+			}
+		}
+	}
+	return titles
+}
+
+func applyExternalIDs(title *models.Title, ids map[string]string) {
+	if id, err := strconv.ParseInt(strings.TrimSpace(ids["tmdb"]), 10, 64); err == nil {
+		title.TMDBID = id
+	}
+	if id, err := strconv.ParseInt(strings.TrimSpace(ids["tvdb"]), 10, 64); err == nil {
+		title.TVDBID = id
+	}
+	title.IMDBID = strings.TrimSpace(ids["imdb"])
+}
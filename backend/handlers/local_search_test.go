@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"novastream/models"
+)
+
+type fakeLocalSearchService struct {
+	*fakeMetadataService
+	lastQuery string
+	extra     []models.Title
+}
+
+func (f *fakeLocalSearchService) SearchLocal(query, _ string, extra []models.Title, _ int) []models.SearchResult {
+	f.lastQuery, f.extra = query, extra
+	return []models.SearchResult{{Title: models.Title{ID: "tmdb:movie:603", Name: "The Matrix", MediaType: "movie"}, Score: 80}}
+}
+
+type fakeSearchWatchlist struct {
+	items []models.WatchlistItem
+}
+
+func (f fakeSearchWatchlist) List(string) ([]models.WatchlistItem, error) {
+	return f.items, nil
+}
+
+func TestMetadataHandler_SearchLocal(t *testing.T) {
+	fake := &fakeLocalSearchService{fakeMetadataService: &fakeMetadataService{}}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+	handler.SetWatchlistService(fakeSearchWatchlist{items: []models.WatchlistItem{
+		{ID: "tmdb:movie:604", MediaType: "movie", Name: "The Matrix Reloaded", ExternalIDs: map[string]string{"tmdb": "604"}},
+	}})
+	handler.SetHistoryService(&fakeMetadataHistoryService{history: []models.WatchHistoryItem{
+		{MediaType: "episode", ItemID: "tvdb:81189:s01e01", SeriesID: "tvdb:series:81189", SeriesName: "Breaking Bad"},
+		{MediaType: "episode", ItemID: "tvdb:81189:s01e02", SeriesID: "tvdb:series:81189", SeriesName: "Breaking Bad"},
+	}})
+
+	rec := httptest.NewRecorder()
+	handler.SearchLocal(rec, httptest.NewRequest(http.MethodGet, "/api/search/local?q=matr&userId=u1", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "The Matrix") {
+		t.Fatalf("expected local results, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if fake.lastQuery != "matr" || len(fake.extra) != 2 {
+		t.Fatalf("unexpected local search call query=%q extra=%+v", fake.lastQuery, fake.extra)
+	}
+	if fake.extra[0].TMDBID != 604 || fake.extra[1].Name != "Breaking Bad" || fake.extra[1].MediaType != "series" {
+		t.Fatalf("unexpected profile titles %+v", fake.extra)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"sync"
 	"time"
 
+	"novastream/config"
 	"novastream/internal/datastore"
 	"novastream/models"
 )
@@ -47,11 +48,11 @@ var logRedactionPatterns = []struct {
 		replacement: `${1}` + logRedacted,
 	},
 	{
-		pattern:     regexp.MustCompile(`(?i)(["']?(?:api[_-]?key|apikey|homepageApiKey|tmdbApiKey|tvdbApiKey|aiApiKey|geminiApiKey|youtubeProxyUrl|fallbackApiKey|openSubtitlesPassword|subdlApiKey|subsourceApiKey|token|access[_-]?token|refresh[_-]?token|auth[_-]?token|client[_-]?secret|password|passwd|pass|secret|databaseURL|databaseUrl|DATABASE_URL)["']?\s*[:=]\s*["'])([^"']+)(["'])`),
+		pattern:     regexp.MustCompile(`(?i)(["']?(?:api[_-]?key|apikey|homepageApiKey|tmdbApiKey|tvdbApiKey|aiApiKey|geminiApiKey|fanartApiKey|youtubeProxyUrl|fallbackApiKey|openSubtitlesPassword|subdlApiKey|subsourceApiKey|token|access[_-]?token|refresh[_-]?token|auth[_-]?token|client[_-]?secret|password|passwd|pass|secret|databaseURL|databaseUrl|DATABASE_URL)["']?\s*[:=]\s*["'])([^"']+)(["'])`),
 		replacement: `${1}` + logRedacted + `${3}`,
 	},
 	{
-		pattern:     regexp.MustCompile(`(?i)(["']?(?:api[_-]?key|apikey|homepageApiKey|tmdbApiKey|tvdbApiKey|aiApiKey|geminiApiKey|youtubeProxyUrl|fallbackApiKey|openSubtitlesPassword|subdlApiKey|subsourceApiKey|token|access[_-]?token|refresh[_-]?token|auth[_-]?token|client[_-]?secret|password|passwd|pass|secret|databaseURL|databaseUrl|DATABASE_URL)["']?\s*[:=]\s*)[^\s"',;}]+`),
+		pattern:     regexp.MustCompile(`(?i)(["']?(?:api[_-]?key|apikey|homepageApiKey|tmdbApiKey|tvdbApiKey|aiApiKey|geminiApiKey|fanartApiKey|youtubeProxyUrl|fallbackApiKey|openSubtitlesPassword|subdlApiKey|subsourceApiKey|token|access[_-]?token|refresh[_-]?token|auth[_-]?token|client[_-]?secret|password|passwd|pass|secret|databaseURL|databaseUrl|DATABASE_URL)["']?\s*[:=]\s*)[^\s"',;}]+`),
 		replacement: `${1}` + logRedacted,
 	},
 	{
@@ -120,6 +121,8 @@ type LogsHandler struct {
 	frontendLogsDir string
 	frontendLogsMu  sync.RWMutex
 	dataStore       *datastore.DataStore
+	configManager   *config.Manager
+	diagnostics     map[string]func() interface{} // extra support bundle snapshots by name
 }
 
 type submitLogsRequest struct {
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"archive/zip"
 	"bytes"
 	"encoding/json"
 	"fmt"
@@ -13,6 +14,7 @@ import (
 	"strings"
 	"testing"
 
+	"novastream/config"
 	"novastream/models"
 )
 
@@ -788,3 +790,55 @@ func TestPasteServicesConfiguration(t *testing.T) {
 		}
 	}
 }
+
+func TestLogsHandler_SupportBundleRedactsAndIncludesDiagnostics(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "backend.log")
+	secret := "sk9aB3dE5fG7hJ2kL4mN6pQ8rS1tU0vWxYz"
+	if err := os.WriteFile(logFile, []byte("2026/01/02 03:04:05 token "+secret+"\n2026/01/02 03:04:06 ready\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfgManager := config.NewManager(filepath.Join(dir, "settings.json"))
+	settings := config.DefaultSettings()
+	settings.Metadata.TMDBAPIKey = "tmdb-secret"
+	settings.Storage.Notifications = []config.TaskNotification{{Type: config.TaskNotificationTypeDiscord, URL: "https://discord.com/api/webhooks/1/secret"}}
+	if err := cfgManager.Save(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewLogsHandler(log.New(io.Discard, "", 0), logFile)
+	h.frontendLogsDir = filepath.Join(dir, "frontend")
+	h.SetConfigManager(cfgManager)
+	h.AddDiagnostics("storage", func() interface{} { return map[string]bool{"low": false} })
+
+	rec := httptest.NewRecorder()
+	h.SupportBundle(rec, httptest.NewRequest(http.MethodGet, "/admin/api/support-bundle", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(rec.Body.Bytes()), int64(rec.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+
+	if !strings.Contains(files["logs/backend.log"], "ready") || strings.Contains(files["logs/backend.log"], secret) {
+		t.Errorf("backend log not included or not redacted: %q", files["logs/backend.log"])
+	}
+	if strings.Contains(files["settings.json"], "tmdb-secret") || strings.Contains(files["settings.json"], "webhooks/1/secret") {
+		t.Errorf("settings not redacted: %s", files["settings.json"])
+	}
+	if !strings.Contains(files["diagnostics.json"], `"storage"`) || !strings.Contains(files["diagnostics.json"], `"goVersion"`) {
+		t.Errorf("diagnostics missing subsystems or runtime: %s", files["diagnostics.json"])
+	}
+}
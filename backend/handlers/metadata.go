@@ -14,13 +14,19 @@ import (
 	"time"
 
 	"novastream/config"
+	"novastream/internal/auth"
 	"novastream/models"
+	"novastream/services/imdb"
 	"novastream/services/kids"
 	"novastream/services/letterboxd"
 	"novastream/services/mdblist"
 	metadatapkg "novastream/services/metadata"
+	"novastream/services/savedfilters"
 	"novastream/services/simkl"
 	"novastream/services/trakt"
+	"novastream/services/watchlist"
+
+	"github.com/gorilla/mux"
 )
 
 type metadataService interface {
@@ -39,7 +45,7 @@ type metadataService interface {
 	Similar(context.Context, string, int64) ([]models.Title, error)
 	DiscoverByGenre(context.Context, string, int64, int, int) ([]models.TrendingItem, int, error)
 	DiscoverByDecade(context.Context, string, int, int, int) ([]models.TrendingItem, int, error)
-	GetAIRecommendations(context.Context, []string, []string, string) ([]models.TrendingItem, error)
+	GetAIRecommendations(context.Context, []string, []string, string, bool) ([]models.TrendingItem, error)
 	GetAISimilar(context.Context, string, string) ([]models.TrendingItem, error)
 	GetAICustomRecommendations(context.Context, string) ([]models.TrendingItem, error)
 	GetAISurprise(context.Context, string, string) (*models.TrendingItem, error)
@@ -54,6 +60,8 @@ type metadataService interface {
 	PrequeueTrailer(videoURL string) (string, error)
 	GetTrailerPrequeueStatus(id string) (*metadatapkg.TrailerPrequeueItem, error)
 	ServePrequeuedTrailer(id string, w http.ResponseWriter, r *http.Request) error
+	TrailerPreviews() []metadatapkg.TrailerPreview
+	ServeTrailerPreview(id string, w http.ResponseWriter, r *http.Request) error
 	// Progress tracking for long-running enrichment operations
 	GetProgressSnapshot() metadatapkg.ProgressSnapshot
 	// MDBList rating helpers for watchlist/list rating sort
@@ -73,10 +81,59 @@ type trendingOptionsService interface {
 	TrendingWithOptions(context.Context, string, metadatapkg.ShelfLoadOptions) ([]models.TrendingItem, error)
 }
 
+// trendingPageService paginates trending lists in the metadata service so
+// only the requested page is copied and artwork-enriched.
+type trendingPageService interface {
+	TrendingPage(ctx context.Context, mediaType, source string, limit, offset int, opts metadatapkg.ShelfLoadOptions) ([]models.TrendingItem, int, error)
+}
+
+type trendingSourceService interface {
+	TrendingFromSource(ctx context.Context, mediaType, source string, opts metadatapkg.ShelfLoadOptions) ([]models.TrendingItem, error)
+	TrendingSources() []string
+}
+
 type discoverByGenreOptionsService interface {
 	DiscoverByGenreWithOptions(context.Context, string, int64, int, int, metadatapkg.ShelfLoadOptions) ([]models.TrendingItem, int, error)
 }
 
+// discoverFilterService runs TMDB discover queries for saved filters.
+type discoverFilterService interface {
+	DiscoverWithFilter(context.Context, models.DiscoverFilter, int, int, metadatapkg.ShelfLoadOptions) ([]models.TrendingItem, int, error)
+}
+
+// moodDiscoverService runs curated mood and TMDB keyword discovery.
+type moodDiscoverService interface {
+	Moods() []models.Mood
+	DiscoverByMood(ctx context.Context, mediaType, mood string, limit, offset int, opts metadatapkg.ShelfLoadOptions) ([]models.TrendingItem, int, error)
+	SearchKeywords(ctx context.Context, query string) ([]models.Keyword, error)
+}
+
+// genreHubService builds genre hub pages mixing movies and series.
+type genreHubService interface {
+	GenreHub(context.Context, string, int64, int, metadatapkg.ShelfLoadOptions) (*models.GenreHub, error)
+}
+
+// allowListService restricts results to a kids profile's allow-list.
+type allowListService interface {
+	FilterTrendingByAllowList(context.Context, []models.TrendingItem, metadatapkg.AllowList) []models.TrendingItem
+	SearchWithAllowList(context.Context, string, string, metadatapkg.AllowList) ([]models.SearchResult, error)
+}
+
+// nextEpisodeService resolves the episode that follows a given one.
+type nextEpisodeService interface {
+	NextEpisode(ctx context.Context, req models.SeriesDetailsQuery, lastSeason, lastEpisode int) (*models.ResolvedNextEpisode, error)
+}
+
+// seasonDetailsService loads one season's episodes with their TMDB extras.
+type seasonDetailsService interface {
+	SeasonDetails(ctx context.Context, req models.SeriesDetailsQuery, seasonNumber int) (*models.SeriesSeason, error)
+}
+
+// titleDebugService reports where a title's metadata came from.
+type titleDebugService interface {
+	DebugTitle(ctx context.Context, id, mediaType string) (*metadatapkg.TitleDebugReport, error)
+}
+
 type discoverByDecadeOptionsService interface {
 	DiscoverByDecadeWithOptions(context.Context, string, int, int, int, metadatapkg.ShelfLoadOptions) ([]models.TrendingItem, int, error)
 }
@@ -115,9 +172,7 @@ func (h *MetadataHandler) SearchYouTubeVideos(w http.ResponseWriter, r *http.Req
 	results, err := h.Service.SearchYouTubeVideos(r.Context(), query, limit)
 	if err != nil {
 		log.Printf("[metadata] youtube search error query=%q err=%v", query, err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 	if results == nil {
@@ -174,6 +229,7 @@ type MetadataHandler struct {
 	SimklClient        *simkl.Client
 	MDBListListsClient *mdblist.ListsClient
 	LetterboxdClient   *letterboxd.Client
+	Requests           requestPassthrough
 }
 
 func NewMetadataHandler(s metadataService, cfgManager *config.Manager) *MetadataHandler {
@@ -190,58 +246,70 @@ func (h *MetadataHandler) SetHistoryService(service historyServiceInterface) {
 	h.HistoryService = service
 }
 
-// SetUsersService sets the users service for kids profile filtering.
+// SetUsersService sets the users service for profile rating filtering.
 func (h *MetadataHandler) SetUsersService(service usersServiceInterface) {
 	h.UsersService = service
 }
 
-// kidsRatingLimits resolves the (movie, tv) max ratings for a kids profile in
-// rating mode. ok is false when no rating filtering should be applied (profile
-// is not a kids profile, not in rating mode, or has no rating configured).
-func (h *MetadataHandler) kidsRatingLimits(userID string) (movieRating, tvRating string, ok bool) {
-	if userID == "" || h.UsersService == nil {
-		return "", "", false
-	}
-	user, found := h.UsersService.Get(userID)
-	if !found || !user.IsKidsProfile || user.KidsMode != "rating" {
-		return "", "", false
-	}
-	movieRating = user.KidsMaxMovieRating
-	tvRating = user.KidsMaxTVRating
-	if movieRating == "" && tvRating == "" && user.KidsMaxRating != "" {
-		movieRating = user.KidsMaxRating
-		tvRating = user.KidsMaxRating
-	}
-	if movieRating == "" && tvRating == "" {
-		return "", "", false
-	}
-	return movieRating, tvRating, true
+// ratingLimits resolves the (movie, tv) max ratings enforced for the profile:
+// its content rating ceiling combined with the kids limits for kids profiles
+// in rating mode. ok is false when no rating filtering should be applied
+// (profile is unrestricted or the request carries the parental override PIN).
+func (h *MetadataHandler) ratingLimits(r *http.Request, userID string) (movieRating, tvRating string, ok bool) {
+	return profileRatingLimits(r, h.UsersService, h.CfgManager, userID)
 }
 
-// filterTrendingByKids enriches certifications and filters trending items by the
-// kids rating limit for the given profile. It is a no-op for non-kids profiles
-// or profiles not in rating mode.
-func (h *MetadataHandler) filterTrendingByKids(ctx context.Context, userID string, service metadataService, items []models.TrendingItem) []models.TrendingItem {
-	movieRating, tvRating, ok := h.kidsRatingLimits(userID)
+// filterTrendingByRating enriches certifications and filters trending items by
+// the profile's rating limits. It is a no-op for unrestricted profiles.
+func (h *MetadataHandler) filterTrendingByRating(r *http.Request, userID string, service metadataService, items []models.TrendingItem) []models.TrendingItem {
+	movieRating, tvRating, ok := h.ratingLimits(r, userID)
 	if !ok {
 		return items
 	}
 	// Populate certifications so items without one aren't all fail-closed.
-	service.EnrichTrendingCertifications(ctx, items)
+	service.EnrichTrendingCertifications(r.Context(), items)
 	return kids.FilterTrendingByRatings(items, movieRating, tvRating)
 }
 
-// filterTitlesByKids enriches certifications and filters titles by the kids
-// rating limit for the given profile. No-op for non-kids/rating profiles.
-func (h *MetadataHandler) filterTitlesByKids(ctx context.Context, userID string, service metadataService, titles []models.Title) []models.Title {
-	movieRating, tvRating, ok := h.kidsRatingLimits(userID)
+// filterTitlesByRating enriches certifications and filters titles by the
+// profile's rating limits. No-op for unrestricted profiles.
+func (h *MetadataHandler) filterTitlesByRating(r *http.Request, userID string, service metadataService, titles []models.Title) []models.Title {
+	movieRating, tvRating, ok := h.ratingLimits(r, userID)
 	if !ok {
 		return titles
 	}
-	service.EnrichTitleCertifications(ctx, titles)
+	service.EnrichTitleCertifications(r.Context(), titles)
 	return kids.FilterTitlesByRatings(titles, movieRating, tvRating)
 }
 
+// profileAllowList returns the allow-list of a kids profile in allow_list
+// mode. ok is false for other profiles and when the request carries the
+// parental override PIN.
+func (h *MetadataHandler) profileAllowList(r *http.Request, userID string) (metadatapkg.AllowList, bool) {
+	if userID == "" || h.UsersService == nil {
+		return metadatapkg.AllowList{}, false
+	}
+	user, found := h.UsersService.Get(userID)
+	if !found || !user.IsKidsProfile || user.KidsMode != "allow_list" || parentalOverride(r, h.CfgManager) {
+		return metadatapkg.AllowList{}, false
+	}
+	return metadatapkg.AllowList{Genres: user.KidsAllowedGenres, Lists: user.KidsAllowedLists}, true
+}
+
+// filterTrendingByAllowList drops items outside the profile's allow-list.
+// Services that cannot apply an allow-list return nothing for such profiles.
+func (h *MetadataHandler) filterTrendingByAllowList(r *http.Request, userID string, service metadataService, items []models.TrendingItem) []models.TrendingItem {
+	allow, ok := h.profileAllowList(r, userID)
+	if !ok {
+		return items
+	}
+	svc, ok := service.(allowListService)
+	if !ok {
+		return []models.TrendingItem{}
+	}
+	return svc.FilterTrendingByAllowList(r.Context(), items, allow)
+}
+
 func (h *MetadataHandler) SetAccountsService(service accountsServiceInterface) {
 	h.AccountsService = service
 }
@@ -275,6 +343,13 @@ func (h *MetadataHandler) serviceForUser(userID string) metadataService {
 	return metadataServiceForUser(h.Service, h.CfgManager, h.UserSettings, userID)
 }
 
+// serviceForRequest returns the metadata service for the request's profile,
+// honouring an optional "lang" query parameter that overrides the profile's
+// metadata language for this request only.
+func (h *MetadataHandler) serviceForRequest(r *http.Request, userID string) metadataService {
+	return metadataServiceForRequest(h.Service, h.CfgManager, h.UserSettings, userID, r.URL.Query().Get("lang"))
+}
+
 // DiscoverNewResponse wraps trending items with total count for pagination
 type DiscoverNewResponse struct {
 	Items           []models.TrendingItem `json:"items"`
@@ -321,22 +396,37 @@ func (h *MetadataHandler) DiscoverNew(w http.ResponseWriter, r *http.Request) {
 	}
 
 	loadOpts := parseShelfLoadOptions(r)
+	trendingSource := strings.TrimSpace(r.URL.Query().Get("trendingSource"))
+	maxMovieRating, maxTVRating, ratingFilter := h.ratingLimits(r, userID)
+	_, allowListFilter := h.profileAllowList(r, userID)
+	filtered := hideUnreleased || (hideWatched && userID != "" && h.HistoryService != nil) || ratingFilter || allowListFilter
+
 	var items []models.TrendingItem
 	var err error
-	if svc, ok := service.(trendingOptionsService); ok {
+	// Without filters the total is known up front, so let the service slice
+	// the list before per-user enrichment.
+	paged := false
+	total := 0
+	if svc, ok := service.(trendingPageService); ok && !filtered && (limit > 0 || offset > 0) {
+		items, total, err = svc.TrendingPage(r.Context(), mediaType, trendingSource, limit, offset, loadOpts)
+		paged = true
+	} else if svc, ok := service.(trendingSourceService); ok && trendingSource != "" {
+		items, err = svc.TrendingFromSource(r.Context(), mediaType, trendingSource, loadOpts)
+	} else if svc, ok := service.(trendingOptionsService); ok {
 		items, err = svc.TrendingWithOptions(r.Context(), mediaType, loadOpts)
 	} else {
 		items, err = service.Trending(r.Context(), mediaType)
 	}
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
 	// Track pre-filter total for explore card logic
 	unfilteredTotal := len(items)
+	if paged {
+		unfilteredTotal = total
+	}
 
 	// Apply unreleased filter if requested
 	if hideUnreleased {
@@ -348,19 +438,12 @@ func (h *MetadataHandler) DiscoverNew(w http.ResponseWriter, r *http.Request) {
 		items = filterWatchedItems(items, userID, h.HistoryService)
 	}
 
-	// Apply kids rating filter if user is a kids profile
-	if userID != "" && h.UsersService != nil {
-		if user, ok := h.UsersService.Get(userID); ok && user.IsKidsProfile {
-			if user.KidsMode == "rating" {
-				movieRating := user.KidsMaxMovieRating
-				tvRating := user.KidsMaxTVRating
-				if movieRating == "" && tvRating == "" && user.KidsMaxRating != "" {
-					movieRating = user.KidsMaxRating
-					tvRating = user.KidsMaxRating
-				}
-				items = kids.FilterTrendingByRatings(items, movieRating, tvRating)
-			}
-		}
+	// Apply the profile's rating limits
+	if ratingFilter {
+		items = kids.FilterTrendingByRatings(items, maxMovieRating, maxTVRating)
+	}
+	if allowListFilter {
+		items = h.filterTrendingByAllowList(r, userID, service, items)
 	}
 
 	// Enrich with pre-computed watch state if user context is available
@@ -375,27 +458,38 @@ func (h *MetadataHandler) DiscoverNew(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Apply pagination
-	total := len(items)
-	if offset > 0 {
-		if offset >= total {
-			items = []models.TrendingItem{}
-		} else {
-			items = items[offset:]
+	if !paged {
+		total = len(items)
+		if offset > 0 {
+			if offset >= total {
+				items = []models.TrendingItem{}
+			} else {
+				items = items[offset:]
+			}
+		}
+		if limit > 0 && limit < len(items) {
+			items = items[:limit]
 		}
-	}
-	if limit > 0 && limit < len(items) {
-		items = items[:limit]
 	}
 
 	// Enrich with MDBList ratings for sort-by-rating support
 	enrichTrendingRatings(items, service)
 
-	w.Header().Set("Content-Type", "application/json")
 	resp := DiscoverNewResponse{Items: items, Total: total}
 	if hideUnreleased || hideWatched {
 		resp.UnfilteredTotal = unfilteredTotal
 	}
-	json.NewEncoder(w).Encode(resp)
+	writeJSONWithETag(w, r, h.proxyArtwork(r, h.displayTitles(r, userID, resp)))
+}
+
+// TrendingSources lists the trending sources a shelf can select via trendingSource.
+func (h *MetadataHandler) TrendingSources(w http.ResponseWriter, r *http.Request) {
+	sources := []string{metadatapkg.TrendingSourceMDBList}
+	if svc, ok := h.Service.(trendingSourceService); ok {
+		sources = svc.TrendingSources()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]string{"sources": sources})
 }
 
 func (h *MetadataHandler) Search(w http.ResponseWriter, r *http.Request) {
@@ -405,7 +499,7 @@ func (h *MetadataHandler) Search(w http.ResponseWriter, r *http.Request) {
 	}
 	mediaType := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("type")))
 	userID := strings.TrimSpace(r.URL.Query().Get("userId"))
-	service := h.serviceForUser(userID)
+	service := h.serviceForRequest(r, userID)
 
 	// Check kids profile restrictions before searching
 	if userID != "" && h.UsersService != nil {
@@ -419,28 +513,26 @@ func (h *MetadataHandler) Search(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	results, err := service.Search(r.Context(), q, mediaType)
+	var results []models.SearchResult
+	var err error
+	if allow, ok := h.profileAllowList(r, userID); ok {
+		// Allow-list profiles only see results the service matches to the list
+		if svc, ok := service.(allowListService); ok {
+			results, err = svc.SearchWithAllowList(r.Context(), q, mediaType, allow)
+		}
+	} else {
+		results, err = service.Search(r.Context(), q, mediaType)
+	}
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
-	// Apply kids rating filter if user is a kids profile with rating mode
-	if userID != "" && h.UsersService != nil {
-		if user, ok := h.UsersService.Get(userID); ok && user.IsKidsProfile && user.KidsMode == "rating" {
-			// Enrich results with certification data
-			service.EnrichSearchCertifications(r.Context(), results)
-
-			movieRating := user.KidsMaxMovieRating
-			tvRating := user.KidsMaxTVRating
-			if movieRating == "" && tvRating == "" && user.KidsMaxRating != "" {
-				movieRating = user.KidsMaxRating
-				tvRating = user.KidsMaxRating
-			}
-			results = kids.FilterSearchByRatings(results, movieRating, tvRating)
-		}
+	// Apply the profile's rating limits
+	if movieRating, tvRating, ok := h.ratingLimits(r, userID); ok {
+		// Enrich results with certification data
+		service.EnrichSearchCertifications(r.Context(), results)
+		results = kids.FilterSearchByRatings(results, movieRating, tvRating)
 	}
 
 	// Ensure we return [] instead of null for empty results
@@ -449,12 +541,16 @@ func (h *MetadataHandler) Search(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(results)
+	json.NewEncoder(w).Encode(h.proxyArtwork(r, h.displayTitles(r, userID, results)))
 }
 
 func (h *MetadataHandler) SeriesDetails(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
-	service := h.serviceForUser(query.Get("userId"))
+	service := h.serviceForRequest(r, query.Get("userId"))
+	includeRaw, ok := h.includeRawPayloads(w, r, service)
+	if !ok {
+		return
+	}
 
 	trimAndParseInt := func(value string) int {
 		value = strings.TrimSpace(value)
@@ -490,18 +586,444 @@ func (h *MetadataHandler) SeriesDetails(w http.ResponseWriter, r *http.Request)
 
 	details, err := service.SeriesDetails(r.Context(), req)
 	if err != nil {
+		writeServiceError(w, err, http.StatusBadGateway)
+		return
+	}
+
+	if userID := strings.TrimSpace(query.Get("userId")); userID != "" && h.HistoryService != nil {
+		if history, err := h.HistoryService.ListWatchHistory(userID); err == nil {
+			progress, _ := h.HistoryService.ListPlaybackProgress(userID)
+			details = withSeriesWatchProgress(details, history, progress)
+		}
+	}
+	details = withSeriesAvailability(r.Context(), h.Requests, details)
+	details = withSeriesCreditDepartments(r, details)
+	if strings.EqualFold(strings.TrimSpace(query.Get("includeEpisodes")), "false") {
+		details = withoutSeriesEpisodes(details)
+	}
+
+	if includeRaw {
+		raw := service.(rawPayloadService).RawProviderPayloads(r.Context(), "series", details.Title.TVDBID, details.Title.TMDBID)
 		w.Header().Set("Content-Type", "application/json")
-		status := http.StatusBadGateway
-		if strings.Contains(err.Error(), "404 Not Found") || strings.Contains(err.Error(), "unable to resolve") {
-			status = http.StatusNotFound
+		json.NewEncoder(w).Encode(h.proxyArtwork(r, h.displayTitles(r, query.Get("userId"), seriesDetailsWithRaw{SeriesDetails: details, Raw: raw})))
+		return
+	}
+	writeJSONWithETag(w, r, h.proxyArtwork(r, h.displayTitles(r, query.Get("userId"), details)))
+}
+
+type rawPayloadService interface {
+	RawProviderPayloads(ctx context.Context, mediaType string, tvdbID, tmdbID int64) *metadatapkg.RawProviderPayloads
+}
+
+// seriesDetailsWithRaw and movieDetailsWithRaw add the upstream payloads to
+// a details response without touching the cached models.
+type seriesDetailsWithRaw struct {
+	*models.SeriesDetails
+	Raw *metadatapkg.RawProviderPayloads `json:"raw"`
+}
+
+type movieDetailsWithRaw struct {
+	*models.Title
+	Raw *metadatapkg.RawProviderPayloads `json:"raw"`
+}
+
+// includeRawPayloads reports whether a details request asked for the raw
+// TVDB/TMDB responses with ?includeRaw=true. Only the master account may ask;
+// ok is false once an error response has been written.
+func (h *MetadataHandler) includeRawPayloads(w http.ResponseWriter, r *http.Request, service metadataService) (includeRaw, ok bool) {
+	if !strings.EqualFold(strings.TrimSpace(r.URL.Query().Get("includeRaw")), "true") {
+		return false, true
+	}
+	if !auth.IsMaster(r) {
+		writeJSONError(w, "admin access required", http.StatusForbidden)
+		return false, false
+	}
+	if _, supported := service.(rawPayloadService); !supported {
+		writeJSONError(w, "raw provider payloads not supported", http.StatusNotImplemented)
+		return false, false
+	}
+	return true, true
+}
+
+// SeriesNextEpisode returns the episode to watch after ?season=&episode= for
+// the series identified like SeriesDetails. nextEpisode is null when nothing
+// follows.
+func (h *MetadataHandler) SeriesNextEpisode(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	svc, ok := h.serviceForRequest(r, query.Get("userId")).(nextEpisodeService)
+	if !ok {
+		writeJSONError(w, "next episode resolution not available", http.StatusNotImplemented)
+		return
+	}
+
+	season, err := strconv.Atoi(strings.TrimSpace(query.Get("season")))
+	if err != nil || season < 0 {
+		writeJSONError(w, "season must be a non-negative number", http.StatusBadRequest)
+		return
+	}
+	episode, err := strconv.Atoi(strings.TrimSpace(query.Get("episode")))
+	if err != nil || episode < 0 {
+		writeJSONError(w, "episode must be a non-negative number", http.StatusBadRequest)
+		return
+	}
+	year, _ := strconv.Atoi(strings.TrimSpace(query.Get("year")))
+	tvdbID, _ := strconv.ParseInt(strings.TrimSpace(query.Get("tvdbId")), 10, 64)
+	tmdbID, _ := strconv.ParseInt(strings.TrimSpace(query.Get("tmdbId")), 10, 64)
+
+	next, err := svc.NextEpisode(r.Context(), models.SeriesDetailsQuery{
+		TitleID: strings.TrimSpace(query.Get("titleId")),
+		Name:    strings.TrimSpace(query.Get("name")),
+		Year:    year,
+		TVDBID:  tvdbID,
+		TMDBID:  tmdbID,
+	}, season, episode)
+	if err != nil {
+		writeServiceError(w, err, http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]*models.ResolvedNextEpisode{"nextEpisode": next})
+}
+
+// SeasonDetails returns one season (?season=) of the series identified like
+// SeriesDetails, with episode stills, localized overviews, ratings and guest
+// stars. Clients that load details with ?includeEpisodes=false fetch each
+// season here as it is viewed.
+func (h *MetadataHandler) SeasonDetails(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	svc, ok := h.serviceForRequest(r, query.Get("userId")).(seasonDetailsService)
+	if !ok {
+		writeJSONError(w, "season details not available", http.StatusNotImplemented)
+		return
+	}
+
+	season, err := strconv.Atoi(strings.TrimSpace(query.Get("season")))
+	if err != nil || season < 0 {
+		writeJSONError(w, "season must be a non-negative number", http.StatusBadRequest)
+		return
+	}
+	year, _ := strconv.Atoi(strings.TrimSpace(query.Get("year")))
+	tvdbID, _ := strconv.ParseInt(strings.TrimSpace(query.Get("tvdbId")), 10, 64)
+	tmdbID, _ := strconv.ParseInt(strings.TrimSpace(query.Get("tmdbId")), 10, 64)
+
+	details, err := svc.SeasonDetails(r.Context(), models.SeriesDetailsQuery{
+		TitleID: strings.TrimSpace(query.Get("titleId")),
+		Name:    strings.TrimSpace(query.Get("name")),
+		Year:    year,
+		TVDBID:  tvdbID,
+		TMDBID:  tmdbID,
+	}, season)
+	if err != nil {
+		writeServiceError(w, err, http.StatusBadGateway)
+		return
+	}
+
+	switch strings.ToLower(strings.TrimSpace(query.Get("sort"))) {
+	case "", "episode":
+	case "rating":
+		sortEpisodesByRating(details.Episodes)
+	default:
+		writeJSONError(w, "sort must be episode or rating", http.StatusBadRequest)
+		return
+	}
+
+	writeJSONWithETag(w, r, h.proxyArtwork(r, details))
+}
+
+// sortEpisodesByRating orders episodes best first by TMDB rating, breaking
+// ties by vote count and then episode order. Unrated episodes go last.
+func sortEpisodesByRating(episodes []models.SeriesEpisode) {
+	sort.SliceStable(episodes, func(i, j int) bool {
+		a, aok := tmdbEpisodeRating(episodes[i])
+		b, bok := tmdbEpisodeRating(episodes[j])
+		if aok != bok {
+			return aok
 		}
-		w.WriteHeader(status)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		if a.Value != b.Value {
+			return a.Value > b.Value
+		}
+		if a.Votes != b.Votes {
+			return a.Votes > b.Votes
+		}
+		return episodes[i].EpisodeNumber < episodes[j].EpisodeNumber
+	})
+}
+
+func tmdbEpisodeRating(episode models.SeriesEpisode) (models.Rating, bool) {
+	for _, rating := range episode.Ratings {
+		if rating.Source == "tmdb" && rating.Votes > 0 {
+			return rating, true
+		}
+	}
+	return models.Rating{}, false
+}
+
+type artworkRefresher interface {
+	RefreshArtwork(mediaType string, tmdbID, tvdbID int64) (int, error)
+}
+
+// RefreshArtwork drops a title's cached artwork and the details built from
+// it, so the next details request picks its poster and backdrop again, e.g.
+// after the artwork preferences changed. Only the master account may refresh.
+func (h *MetadataHandler) RefreshArtwork(w http.ResponseWriter, r *http.Request) {
+	if !auth.IsMaster(r) {
+		writeJSONError(w, "admin access required", http.StatusForbidden)
+		return
+	}
+	refresher, ok := h.Service.(artworkRefresher)
+	if !ok {
+		writeJSONError(w, "artwork refresh not supported", http.StatusNotImplemented)
+		return
+	}
+	var req struct {
+		MediaType string `json:"mediaType"`
+		TMDBID    int64  `json:"tmdbId"`
+		TVDBID    int64  `json:"tvdbId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	mediaType := strings.ToLower(strings.TrimSpace(req.MediaType))
+	if mediaType != "movie" && mediaType != "series" {
+		writeJSONError(w, "mediaType must be movie or series", http.StatusBadRequest)
+		return
+	}
+	if req.TMDBID <= 0 && req.TVDBID <= 0 {
+		writeJSONError(w, "tmdbId or tvdbId is required", http.StatusBadRequest)
+		return
+	}
+	removed, err := refresher.RefreshArtwork(mediaType, req.TMDBID, req.TVDBID)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError)
+		return
+	}
+	log.Printf("[metadata] artwork refreshed for %s tmdb:%d tvdb:%d (%d cache entries)", mediaType, req.TMDBID, req.TVDBID, removed)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "removed": removed})
+}
+
+type identityOverrideService interface {
+	IdentityOverrides() []metadatapkg.IdentityOverride
+	SetIdentityOverride(override metadatapkg.IdentityOverride) (metadatapkg.IdentityOverride, error)
+	RemoveIdentityOverride(titleID string) error
+}
+
+// identityOverrides returns the service's identity override support, writing
+// the error response when the caller isn't the master account or the service
+// has none.
+func (h *MetadataHandler) identityOverrides(w http.ResponseWriter, r *http.Request) (identityOverrideService, bool) {
+	if !auth.IsMaster(r) {
+		writeJSONError(w, "admin access required", http.StatusForbidden)
+		return nil, false
+	}
+	svc, ok := h.Service.(identityOverrideService)
+	if !ok {
+		writeJSONError(w, "identity overrides not supported", http.StatusNotImplemented)
+		return nil, false
+	}
+	return svc, true
+}
+
+// ListIdentityOverrides returns the titles pinned to a TVDB/TMDB entry.
+func (h *MetadataHandler) ListIdentityOverrides(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.identityOverrides(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"overrides": svc.IdentityOverrides()})
+}
+
+// SetIdentityOverride pins a title to the TVDB and/or TMDB entry it really
+// is, fixing a wrong automatic match without clearing the cache.
+func (h *MetadataHandler) SetIdentityOverride(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.identityOverrides(w, r)
+	if !ok {
+		return
+	}
+	var req metadatapkg.IdentityOverride
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.TitleID) == "" {
+		writeJSONError(w, "titleId is required", http.StatusBadRequest)
+		return
+	}
+	mediaType := strings.ToLower(strings.TrimSpace(req.MediaType))
+	if mediaType != "movie" && mediaType != "series" {
+		writeJSONError(w, "mediaType must be movie or series", http.StatusBadRequest)
+		return
+	}
+	if req.TMDBID < 0 || req.TVDBID < 0 || (req.TMDBID == 0 && req.TVDBID == 0) {
+		writeJSONError(w, "tmdbId or tvdbId is required", http.StatusBadRequest)
+		return
+	}
+	override, err := svc.SetIdentityOverride(req)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(override)
+}
+
+// RemoveIdentityOverride drops a title's override so it is matched
+// automatically again.
+func (h *MetadataHandler) RemoveIdentityOverride(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.identityOverrides(w, r)
+	if !ok {
+		return
+	}
+	titleID := strings.TrimSpace(mux.Vars(r)["titleID"])
+	if titleID == "" {
+		writeJSONError(w, "titleId is required", http.StatusBadRequest)
+		return
+	}
+	if err := svc.RemoveIdentityOverride(titleID); err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError)
 		return
 	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type titleEditService interface {
+	AllTitleEdits() []metadatapkg.TitleEdits
+	SetTitleEdits(edits metadatapkg.TitleEdits) (metadatapkg.TitleEdits, error)
+	LockTitleFields(ctx context.Context, mediaType, titleID string, fields []string) (metadatapkg.TitleEdits, error)
+	RemoveTitleEdits(titleID string) error
+}
+
+// titleEdits returns the service's title edit support, writing the error
+// response when the caller isn't the master account or the service has none.
+func (h *MetadataHandler) titleEdits(w http.ResponseWriter, r *http.Request) (titleEditService, bool) {
+	if !auth.IsMaster(r) {
+		writeJSONError(w, "admin access required", http.StatusForbidden)
+		return nil, false
+	}
+	svc, ok := h.Service.(titleEditService)
+	if !ok {
+		writeJSONError(w, "title edits not supported", http.StatusNotImplemented)
+		return nil, false
+	}
+	return svc, true
+}
+
+// ListTitleEdits returns the titles with manually edited or locked fields.
+func (h *MetadataHandler) ListTitleEdits(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.titleEdits(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"edits": svc.AllTitleEdits()})
+}
+
+// SetTitleEdits replaces a title's manual field values (name, overview,
+// posterUrl, isDaily). Omitted fields follow the providers again.
+func (h *MetadataHandler) SetTitleEdits(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.titleEdits(w, r)
+	if !ok {
+		return
+	}
+	var req metadatapkg.TitleEdits
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	edits, err := svc.SetTitleEdits(req)
+	if err != nil {
+		writeServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(edits)
+}
+
+// LockTitleFields pins fields of a title to their current values so
+// provider refreshes can't change them.
+func (h *MetadataHandler) LockTitleFields(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.titleEdits(w, r)
+	if !ok {
+		return
+	}
+	var req struct {
+		TitleID   string   `json:"titleId"`
+		MediaType string   `json:"mediaType"`
+		Fields    []string `json:"fields"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if strings.TrimSpace(req.TitleID) == "" || len(req.Fields) == 0 {
+		writeJSONError(w, "titleId and fields are required", http.StatusBadRequest)
+		return
+	}
+	edits, err := svc.LockTitleFields(r.Context(), req.MediaType, req.TitleID, req.Fields)
+	if err != nil {
+		writeServiceError(w, err, http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(edits)
+}
+
+// RemoveTitleEdits drops every manual value of a title.
+func (h *MetadataHandler) RemoveTitleEdits(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.titleEdits(w, r)
+	if !ok {
+		return
+	}
+	titleID := strings.TrimSpace(mux.Vars(r)["titleID"])
+	if titleID == "" {
+		writeJSONError(w, "titleId is required", http.StatusBadRequest)
+		return
+	}
+	if err := svc.RemoveTitleEdits(titleID); err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
 
+// withoutSeriesEpisodes returns a copy of details whose seasons keep their
+// summaries and episode counts but drop the episode lists.
+func withoutSeriesEpisodes(details *models.SeriesDetails) *models.SeriesDetails {
+	if details == nil {
+		return nil
+	}
+	copied := *details
+	copied.Seasons = make([]models.SeriesSeason, len(details.Seasons))
+	for i, season := range details.Seasons {
+		if season.EpisodeCount < len(season.Episodes) {
+			season.EpisodeCount = len(season.Episodes)
+		}
+		season.Episodes = []models.SeriesEpisode{}
+		copied.Seasons[i] = season
+	}
+	return &copied
+}
+
+// DebugTitle reports, for one title, which provider supplied each field,
+// the cache entries keyed by its IDs with their ages, and recent enrichment
+// failures. The ID is an IMDB ID or "tvdb:<id>" / "tmdb:<id>"; ?type=movie
+// selects the movie details path (default series).
+func (h *MetadataHandler) DebugTitle(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.Service.(titleDebugService)
+	if !ok {
+		writeJSONError(w, "title debugging not available", http.StatusNotImplemented)
+		return
+	}
+	report, err := svc.DebugTitle(r.Context(), mux.Vars(r)["titleID"], r.URL.Query().Get("type"))
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(details)
+	json.NewEncoder(w).Encode(report)
 }
 
 func (h *MetadataHandler) BatchSeriesDetails(w http.ResponseWriter, r *http.Request) {
@@ -527,7 +1049,7 @@ func (h *MetadataHandler) BatchSeriesDetails(w http.ResponseWriter, r *http.Requ
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(response)
+	json.NewEncoder(w).Encode(h.proxyArtwork(r, h.displayTitles(r, userID, response)))
 }
 
 func (h *MetadataHandler) BatchMovieReleases(w http.ResponseWriter, r *http.Request) {
@@ -551,7 +1073,11 @@ func (h *MetadataHandler) BatchMovieReleases(w http.ResponseWriter, r *http.Requ
 
 func (h *MetadataHandler) MovieDetails(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
-	service := h.serviceForUser(query.Get("userId"))
+	service := h.serviceForRequest(r, query.Get("userId"))
+	includeRaw, ok := h.includeRawPayloads(w, r, service)
+	if !ok {
+		return
+	}
 
 	trimAndParseInt := func(value string) int {
 		value = strings.TrimSpace(value)
@@ -588,14 +1114,19 @@ func (h *MetadataHandler) MovieDetails(w http.ResponseWriter, r *http.Request) {
 
 	details, err := service.MovieDetails(r.Context(), req)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
+	details = withMovieAvailability(r.Context(), h.Requests, details)
+	details = withMovieCreditDepartments(r, details)
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(details)
+	if includeRaw {
+		raw := service.(rawPayloadService).RawProviderPayloads(r.Context(), "movie", details.TVDBID, details.TMDBID)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(h.proxyArtwork(r, h.displayTitles(r, query.Get("userId"), movieDetailsWithRaw{Title: details, Raw: raw})))
+		return
+	}
+	writeJSONWithETag(w, r, h.proxyArtwork(r, h.displayTitles(r, query.Get("userId"), details)))
 }
 
 func (h *MetadataHandler) CollectionDetails(w http.ResponseWriter, r *http.Request) {
@@ -623,22 +1154,26 @@ func (h *MetadataHandler) CollectionDetails(w http.ResponseWriter, r *http.Reque
 	details, err := service.CollectionDetails(r.Context(), collectionID)
 	if err != nil {
 		log.Printf("[metadata] collection details error collectionId=%d err=%v", collectionID, err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
 	// Apply kids rating filter to collection members for kids profiles.
-	details.Movies = h.filterTitlesByKids(r.Context(), strings.TrimSpace(query.Get("userId")), service, details.Movies)
+	details.Movies = h.filterTitlesByRating(r, strings.TrimSpace(query.Get("userId")), service, details.Movies)
+
+	if userID := strings.TrimSpace(query.Get("userId")); userID != "" && h.HistoryService != nil {
+		if history, err := h.HistoryService.ListWatchHistory(userID); err == nil {
+			progress, _ := h.HistoryService.ListPlaybackProgress(userID)
+			details = withCollectionWatchProgress(details, history, progress, time.Now())
+		}
+	}
 
 	log.Printf("[metadata] collection details success collectionId=%d name=%q movieCount=%d", collectionID, details.Name, len(details.Movies))
 	for i, movie := range details.Movies {
 		log.Printf("[metadata]   movie[%d]: id=%s name=%q year=%d hasPoster=%v", i, movie.ID, movie.Name, movie.Year, movie.Poster != nil)
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(details)
+	writeJSONWithETag(w, r, h.proxyArtwork(r, h.displayTitles(r, query.Get("userId"), details)))
 }
 
 func (h *MetadataHandler) Similar(w http.ResponseWriter, r *http.Request) {
@@ -666,13 +1201,11 @@ func (h *MetadataHandler) Similar(w http.ResponseWriter, r *http.Request) {
 	titles, err := service.Similar(r.Context(), mediaType, tmdbID)
 	if err != nil {
 		log.Printf("[metadata] similar error type=%s tmdbId=%d err=%v", mediaType, tmdbID, err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
-	titles = h.filterTitlesByKids(r.Context(), strings.TrimSpace(query.Get("userId")), service, titles)
+	titles = h.filterTitlesByRating(r, strings.TrimSpace(query.Get("userId")), service, titles)
 
 	// Return empty array instead of null if no results
 	if titles == nil {
@@ -680,7 +1213,7 @@ func (h *MetadataHandler) Similar(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(titles)
+	json.NewEncoder(w).Encode(h.proxyArtwork(r, h.displayTitles(r, query.Get("userId"), titles)))
 }
 
 func (h *MetadataHandler) PersonDetails(w http.ResponseWriter, r *http.Request) {
@@ -705,14 +1238,12 @@ func (h *MetadataHandler) PersonDetails(w http.ResponseWriter, r *http.Request)
 	details, err := h.serviceForUser(query.Get("userId")).PersonDetails(r.Context(), personID)
 	if err != nil {
 		log.Printf("[metadata] person details error personId=%d err=%v", personID, err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(details)
+	json.NewEncoder(w).Encode(h.proxyArtwork(r, details))
 }
 
 func (h *MetadataHandler) Trailers(w http.ResponseWriter, r *http.Request) {
@@ -755,9 +1286,7 @@ func (h *MetadataHandler) Trailers(w http.ResponseWriter, r *http.Request) {
 
 	response, err := h.serviceForUser(r.URL.Query().Get("userId")).Trailers(r.Context(), req)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
@@ -811,9 +1340,7 @@ func (h *MetadataHandler) TrailerStream(w http.ResponseWriter, r *http.Request)
 
 	streamURL, err := h.Service.ExtractTrailerStreamURL(r.Context(), videoURL)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
@@ -861,10 +1388,11 @@ type TrailerPrequeueRequest struct {
 
 // TrailerPrequeueResponse is the response for trailer prequeue operations
 type TrailerPrequeueResponse struct {
-	ID       string `json:"id"`
-	Status   string `json:"status"`
-	Error    string `json:"error,omitempty"`
-	FileSize int64  `json:"fileSize,omitempty"`
+	ID            string `json:"id"`
+	Status        string `json:"status"`
+	Error         string `json:"error,omitempty"`
+	FileSize      int64  `json:"fileSize,omitempty"`
+	PreviewStatus string `json:"previewStatus,omitempty"`
 }
 
 // TrailerPrequeue starts downloading a trailer in the background
@@ -917,10 +1445,11 @@ func (h *MetadataHandler) TrailerPrequeueStatus(w http.ResponseWriter, r *http.R
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(TrailerPrequeueResponse{
-		ID:       item.ID,
-		Status:   string(item.Status),
-		Error:    item.Error,
-		FileSize: item.FileSize,
+		ID:            item.ID,
+		Status:        string(item.Status),
+		Error:         item.Error,
+		FileSize:      item.FileSize,
+		PreviewStatus: string(item.PreviewStatus),
 	})
 }
 
@@ -943,6 +1472,33 @@ func (h *MetadataHandler) TrailerPrequeueServe(w http.ResponseWriter, r *http.Re
 	}
 }
 
+// TrailerPreviews lists the muted preview clips prepared for hero and
+// trending titles, so clients can autoplay a preview when a title is focused.
+func (h *MetadataHandler) TrailerPreviews(w http.ResponseWriter, r *http.Request) {
+	previews := h.Service.TrailerPreviews()
+	if previews == nil {
+		previews = []metadatapkg.TrailerPreview{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"previews": previews})
+}
+
+// TrailerPreviewServe serves the preview clip of a prequeued trailer
+func (h *MetadataHandler) TrailerPreviewServe(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimSpace(r.URL.Query().Get("id"))
+	if id == "" {
+		http.Error(w, "id parameter required", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.Service.ServeTrailerPreview(id, w, r); err != nil {
+		log.Printf("[trailer-prequeue] preview serve error: %v", err)
+		if w.Header().Get("Content-Type") == "" {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		}
+	}
+}
+
 // CustomListResponse wraps custom list items with total count for pagination
 type CustomListResponse struct {
 	Items           []models.TrendingItem `json:"items"`
@@ -1093,18 +1649,22 @@ func (h *MetadataHandler) CustomList(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Validate URL contains mdblist.com/lists/
-	if !strings.Contains(listURL, "mdblist.com/lists/") {
+	// Accept MDBList list URLs, public Letterboxd lists/watchlists, IMDb
+	// lists/watchlists/CSV exports and public Trakt lists
+	isMDBList := !letterboxd.IsListURL(listURL) && !imdb.IsListURL(listURL) && !trakt.IsUserListURL(listURL)
+	if isMDBList && !strings.Contains(listURL, "mdblist.com/lists/") {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"error": "invalid MDBList URL format"})
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid custom list URL: expected an MDBList, Letterboxd, IMDb or Trakt list URL"})
 		return
 	}
 
 	// Auto-fix: remove trailing slashes and add /json if missing
-	listURL = strings.TrimRight(listURL, "/")
-	if !strings.HasSuffix(listURL, "/json") {
-		listURL = listURL + "/json"
+	if isMDBList {
+		listURL = strings.TrimRight(listURL, "/")
+		if !strings.HasSuffix(listURL, "/json") {
+			listURL = listURL + "/json"
+		}
 	}
 
 	// Build options — filtering + pagination handled inside the service
@@ -1125,14 +1685,12 @@ func (h *MetadataHandler) CustomList(w http.ResponseWriter, r *http.Request) {
 	service := h.serviceForUser(userID)
 	items, filteredTotal, unfilteredTotal, err := service.GetCustomList(r.Context(), listURL, opts)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
 	// Apply kids rating filter for kids profiles.
-	if filtered := h.filterTrendingByKids(r.Context(), userID, service, items); len(filtered) != len(items) {
+	if filtered := h.filterTrendingByRating(r, userID, service, items); len(filtered) != len(items) {
 		filteredTotal = len(filtered)
 		items = filtered
 	}
@@ -1145,7 +1703,7 @@ func (h *MetadataHandler) CustomList(w http.ResponseWriter, r *http.Request) {
 	if hideUnreleased || hideWatched {
 		resp.UnfilteredTotal = unfilteredTotal
 	}
-	json.NewEncoder(w).Encode(resp)
+	json.NewEncoder(w).Encode(h.proxyArtwork(r, resp))
 }
 
 type traktShelfSourceItem struct {
@@ -1202,13 +1760,23 @@ func (h *MetadataHandler) TraktList(w http.ResponseWriter, r *http.Request) {
 	accountID := strings.TrimSpace(r.URL.Query().Get("accountId"))
 	listType := strings.TrimSpace(r.URL.Query().Get("listType"))
 	listID := strings.TrimSpace(r.URL.Query().Get("listId"))
+	if listType == "recommendations" && (accountID == "" || accountID == traktProfileAccount) {
+		// Recommendations are personal, so they default to the profile's own
+		// linked account. Profiles without one just get an empty shelf.
+		accountID = user.TraktAccountID
+		if accountID == "" {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(TraktShelfResponse{Items: []models.TrendingItem{}})
+			return
+		}
+	}
 	if accountID == "" {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "accountId parameter required"})
 		return
 	}
-	if listType != "watchlist" && listType != "custom" {
+	if listType != "watchlist" && listType != "custom" && listType != "recommendations" {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]string{"error": "invalid listType"})
@@ -1264,9 +1832,7 @@ func (h *MetadataHandler) TraktList(w http.ResponseWriter, r *http.Request) {
 
 	sourceItems, err := h.fetchTraktShelfItems(r.Context(), settings, traktAccounts, listType, listID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
@@ -1282,9 +1848,12 @@ func (h *MetadataHandler) TraktList(w http.ResponseWriter, r *http.Request) {
 
 	label := strings.TrimSpace(r.URL.Query().Get("name"))
 	if label == "" {
-		if listType == "watchlist" {
+		switch listType {
+		case "watchlist":
 			label = "Trakt Watchlist"
-		} else {
+		case "recommendations":
+			label = "Trakt Recommendations"
+		default:
 			label = "Trakt List"
 		}
 	}
@@ -1292,9 +1861,7 @@ func (h *MetadataHandler) TraktList(w http.ResponseWriter, r *http.Request) {
 	service := h.serviceForUser(userID)
 	items, err := service.GetCuratedList(r.Context(), curated, label)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
@@ -1305,7 +1872,7 @@ func (h *MetadataHandler) TraktList(w http.ResponseWriter, r *http.Request) {
 	if hideWatched && h.HistoryService != nil {
 		items = filterWatchedItems(items, userID, h.HistoryService)
 	}
-	items = h.filterTrendingByKids(r.Context(), userID, service, items)
+	items = h.filterTrendingByRating(r, userID, service, items)
 	filteredTotal := len(items)
 
 	if offset > 0 && offset < len(items) {
@@ -1327,7 +1894,7 @@ func (h *MetadataHandler) TraktList(w http.ResponseWriter, r *http.Request) {
 	if hideUnreleased || hideWatched {
 		resp.UnfilteredTotal = unfilteredTotal
 	}
-	json.NewEncoder(w).Encode(resp)
+	json.NewEncoder(w).Encode(h.proxyArtwork(r, resp))
 }
 
 func (h *MetadataHandler) resolveTraktShelfAccounts(user models.User, settings config.Settings, requestedAccountID string) ([]config.TraktAccount, error) {
@@ -1404,6 +1971,14 @@ func (h *MetadataHandler) fetchTraktShelfItems(ctx context.Context, settings con
 					upsertTraktShelfItem(seen, normalized)
 				}
 			}
+		case "recommendations":
+			recommended, err := h.fetchTraktRecommendations(accessToken)
+			if err != nil {
+				return nil, fmt.Errorf("fetch trakt recommendations for %s: %w", account.Name, err)
+			}
+			for _, item := range recommended {
+				upsertTraktShelfItem(seen, item)
+			}
 		}
 	}
 
@@ -1558,7 +2133,7 @@ func (h *MetadataHandler) SimklList(w http.ResponseWriter, r *http.Request) {
 
 	listItems, err := h.SimklClient.GetListItems(account.ClientID, account.AccessToken, mediaType, listType)
 	if err != nil {
-		writeJSONError(w, err.Error(), http.StatusBadGateway)
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
@@ -1637,7 +2212,7 @@ func (h *MetadataHandler) LetterboxdList(w http.ResponseWriter, r *http.Request)
 		}
 		result, err := h.LetterboxdClient.GetListResult(r.Context(), listURL, maxItems)
 		if err != nil {
-			writeJSONError(w, err.Error(), http.StatusBadGateway)
+			writeServiceError(w, err, http.StatusBadGateway)
 			return
 		}
 		sourceTotal = result.Total
@@ -1656,7 +2231,7 @@ func (h *MetadataHandler) LetterboxdList(w http.ResponseWriter, r *http.Request)
 		}
 		listItems, err := h.MDBListListsClient.GetExternalListItems(r.Context(), listID, maxItems)
 		if err != nil {
-			writeJSONError(w, err.Error(), http.StatusBadGateway)
+			writeServiceError(w, err, http.StatusBadGateway)
 			return
 		}
 		curated = make([]metadatapkg.CuratedItem, 0, len(listItems))
@@ -1707,7 +2282,7 @@ func (h *MetadataHandler) LetterboxdSources(w http.ResponseWriter, r *http.Reque
 
 	lists, err := h.MDBListListsClient.GetExternalLists(r.Context())
 	if err != nil {
-		writeJSONError(w, err.Error(), http.StatusBadGateway)
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
@@ -1735,7 +2310,7 @@ func (h *MetadataHandler) buildShelfFromCurated(w http.ResponseWriter, r *http.R
 	service := h.serviceForUser(userID)
 	items, err := service.GetCuratedList(r.Context(), curated, label)
 	if err != nil {
-		writeJSONError(w, err.Error(), http.StatusBadGateway)
+		writeServiceError(w, err, http.StatusBadGateway)
 		return nil
 	}
 
@@ -1746,7 +2321,7 @@ func (h *MetadataHandler) buildShelfFromCurated(w http.ResponseWriter, r *http.R
 	if hideWatched && userID != "" && h.HistoryService != nil {
 		items = filterWatchedItems(items, userID, h.HistoryService)
 	}
-	items = h.filterTrendingByKids(r.Context(), userID, service, items)
+	items = h.filterTrendingByRating(r, userID, service, items)
 	filteredTotal := len(items)
 
 	if offset > 0 && offset < len(items) {
@@ -1808,9 +2383,7 @@ func (h *MetadataHandler) CuratedList(w http.ResponseWriter, r *http.Request) {
 	service := h.serviceForUser(userID)
 	items, err := service.GetCuratedList(r.Context(), req.Items, req.Label)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
@@ -1877,9 +2450,7 @@ func (h *MetadataHandler) DiscoverByGenre(w http.ResponseWriter, r *http.Request
 	}
 	if err != nil {
 		log.Printf("[metadata] discover genre error type=%s genreId=%d: %v", mediaType, genreID, err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
@@ -1887,8 +2458,9 @@ func (h *MetadataHandler) DiscoverByGenre(w http.ResponseWriter, r *http.Request
 		items = []models.TrendingItem{}
 	}
 
-	// Apply kids rating filter for kids profiles.
-	if filtered := h.filterTrendingByKids(r.Context(), strings.TrimSpace(r.URL.Query().Get("userId")), service, items); len(filtered) != len(items) {
+	// Apply the profile's rating limits and allow-list.
+	userID := strings.TrimSpace(r.URL.Query().Get("userId"))
+	if filtered := h.filterTrendingByAllowList(r, userID, service, h.filterTrendingByRating(r, userID, service, items)); len(filtered) != len(items) {
 		total -= len(items) - len(filtered)
 		items = filtered
 	}
@@ -1907,7 +2479,56 @@ func (h *MetadataHandler) DiscoverByGenre(w http.ResponseWriter, r *http.Request
 	)
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(DiscoverNewResponse{Items: items, Total: total})
+	json.NewEncoder(w).Encode(h.displayTitles(r, userID, DiscoverNewResponse{Items: items, Total: total}))
+}
+
+// GenreHub returns the trending, top-rated and new rows for a genre, each
+// mixing movies and series. type names the TMDB genre list genreId comes
+// from (movie or series).
+func (h *MetadataHandler) GenreHub(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	mediaType := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("type")))
+	userID := strings.TrimSpace(r.URL.Query().Get("userId"))
+	service := h.serviceForUser(userID)
+	svc, ok := service.(genreHubService)
+	if !ok {
+		writeJSONError(w, "genre hubs not supported", http.StatusNotImplemented)
+		return
+	}
+
+	genreID, err := strconv.ParseInt(strings.TrimSpace(r.URL.Query().Get("genreId")), 10, 64)
+	if err != nil || genreID <= 0 {
+		writeJSONError(w, "invalid genreId", http.StatusBadRequest)
+		return
+	}
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	hub, err := svc.GenreHub(r.Context(), mediaType, genreID, limit, parseShelfLoadOptions(r))
+	if err != nil {
+		log.Printf("[metadata] genre hub error type=%s genreId=%d: %v", mediaType, genreID, err)
+		writeServiceError(w, err, http.StatusBadGateway)
+		return
+	}
+
+	// Apply the profile's rating limits and allow-list to every row.
+	for i := range hub.Rows {
+		items := h.filterTrendingByAllowList(r, userID, service, h.filterTrendingByRating(r, userID, service, hub.Rows[i].Items))
+		if items == nil {
+			items = []models.TrendingItem{}
+		}
+		enrichTrendingRatings(items, service)
+		hub.Rows[i].Items = items
+	}
+	log.Printf("[metadata] genre hub handler complete type=%s genreId=%d rows=%d duration=%s",
+		mediaType, genreID, len(hub.Rows), time.Since(start).Round(time.Millisecond))
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(hub)
 }
 
 // DiscoverByDecade returns TMDB discover results for a specific decade
@@ -1955,9 +2576,7 @@ func (h *MetadataHandler) DiscoverByDecade(w http.ResponseWriter, r *http.Reques
 	}
 	if err != nil {
 		log.Printf("[metadata] discover decade error type=%s decade=%d: %v", mediaType, decade, err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
@@ -1965,8 +2584,9 @@ func (h *MetadataHandler) DiscoverByDecade(w http.ResponseWriter, r *http.Reques
 		items = []models.TrendingItem{}
 	}
 
-	// Apply kids rating filter for kids profiles.
-	if filtered := h.filterTrendingByKids(r.Context(), strings.TrimSpace(r.URL.Query().Get("userId")), service, items); len(filtered) != len(items) {
+	// Apply the profile's rating limits and allow-list.
+	userID := strings.TrimSpace(r.URL.Query().Get("userId"))
+	if filtered := h.filterTrendingByAllowList(r, userID, service, h.filterTrendingByRating(r, userID, service, items)); len(filtered) != len(items) {
 		total -= len(items) - len(filtered)
 		items = filtered
 	}
@@ -1985,7 +2605,230 @@ func (h *MetadataHandler) DiscoverByDecade(w http.ResponseWriter, r *http.Reques
 	)
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(DiscoverNewResponse{Items: items, Total: total})
+	json.NewEncoder(w).Encode(h.displayTitles(r, userID, DiscoverNewResponse{Items: items, Total: total}))
+}
+
+// DiscoverAdvanced returns TMDB discover results for an ad-hoc filter built
+// from query parameters: type, genreIds, keywordIds, yearFrom, yearTo,
+// runtimeMin, runtimeMax, minRating, minVotes, originalLanguage,
+// watchProviders, watchRegion and sort. List parameters are comma-separated.
+// The criteria are the same ones saved filters accept, so a filter UI can
+// preview a set before saving it.
+func (h *MetadataHandler) DiscoverAdvanced(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseDiscoverFilterQuery(r.URL.Query())
+	if err == nil {
+		filter, err = savedfilters.NormalizeFilter(filter)
+	}
+	if err != nil {
+		writeJSONError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.discoverWithFilter(w, r, strings.TrimSpace(r.URL.Query().Get("userId")), filter)
+}
+
+// parseDiscoverFilterQuery reads DiscoverAdvanced's query parameters. Values
+// are only parsed here; savedfilters.NormalizeFilter validates the ranges.
+func parseDiscoverFilterQuery(query url.Values) (models.DiscoverFilter, error) {
+	filter := models.DiscoverFilter{
+		MediaType:        query.Get("type"),
+		OriginalLanguage: query.Get("originalLanguage"),
+		WatchRegion:      query.Get("watchRegion"),
+		Sort:             query.Get("sort"),
+	}
+	var err error
+	if filter.GenreIDs, err = parseInt64ListParam(query, "genreIds"); err != nil {
+		return filter, err
+	}
+	if filter.KeywordIDs, err = parseInt64ListParam(query, "keywordIds"); err != nil {
+		return filter, err
+	}
+	if filter.CastIDs, err = parseInt64ListParam(query, "castIds"); err != nil {
+		return filter, err
+	}
+	if filter.WatchProviderIDs, err = parseInt64ListParam(query, "watchProviders"); err != nil {
+		return filter, err
+	}
+	for _, param := range []struct {
+		name string
+		dst  *int
+	}{
+		{"yearFrom", &filter.YearFrom},
+		{"yearTo", &filter.YearTo},
+		{"runtimeMin", &filter.RuntimeMin},
+		{"runtimeMax", &filter.RuntimeMax},
+		{"minVotes", &filter.MinVotes},
+	} {
+		raw := strings.TrimSpace(query.Get(param.name))
+		if raw == "" {
+			continue
+		}
+		if *param.dst, err = strconv.Atoi(raw); err != nil {
+			return filter, fmt.Errorf("invalid %s", param.name)
+		}
+	}
+	if raw := strings.TrimSpace(query.Get("minRating")); raw != "" {
+		if filter.MinRating, err = strconv.ParseFloat(raw, 64); err != nil {
+			return filter, fmt.Errorf("invalid minRating")
+		}
+	}
+	return filter, nil
+}
+
+// parseInt64ListParam parses a comma-separated list of IDs, skipping blanks.
+func parseInt64ListParam(query url.Values, name string) ([]int64, error) {
+	var ids []int64
+	for _, part := range strings.Split(query.Get(name), ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// discoverWithFilter writes TMDB discover results for a saved filter, paged
+// and filtered for the request's profile like the genre and decade shelves.
+func (h *MetadataHandler) discoverWithFilter(w http.ResponseWriter, r *http.Request, userID string, filter models.DiscoverFilter) {
+	service := h.serviceForUser(userID)
+	svc, ok := service.(discoverFilterService)
+	if !ok {
+		writeJSONError(w, "discover filters not supported", http.StatusNotImplemented)
+		return
+	}
+	h.serveDiscoverShelf(w, r, userID, service, "filter type="+filter.MediaType,
+		func(limit, offset int, opts metadatapkg.ShelfLoadOptions) ([]models.TrendingItem, int, error) {
+			return svc.DiscoverWithFilter(r.Context(), filter, limit, offset, opts)
+		})
+}
+
+// serveDiscoverShelf writes one page of a discover shelf loaded by load,
+// reading limit and offset from the query and filtering the items by the
+// profile's rating limits and allow-list.
+func (h *MetadataHandler) serveDiscoverShelf(w http.ResponseWriter, r *http.Request, userID string, service metadataService, logLabel string, load func(limit, offset int, opts metadatapkg.ShelfLoadOptions) ([]models.TrendingItem, int, error)) {
+	start := time.Now()
+	limit := 0
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+	offset := 0
+	if offsetStr := r.URL.Query().Get("offset"); offsetStr != "" {
+		if parsed, err := strconv.Atoi(offsetStr); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	items, total, err := load(limit, offset, parseShelfLoadOptions(r))
+	if err != nil {
+		log.Printf("[metadata] discover %s error: %v", logLabel, err)
+		writeServiceError(w, err, http.StatusBadGateway)
+		return
+	}
+	if items == nil {
+		items = []models.TrendingItem{}
+	}
+
+	// Apply the profile's rating limits and allow-list.
+	if filtered := h.filterTrendingByAllowList(r, userID, service, h.filterTrendingByRating(r, userID, service, items)); len(filtered) != len(items) {
+		total -= len(items) - len(filtered)
+		items = filtered
+	}
+
+	// Enrich with MDBList ratings for sort-by-rating support
+	enrichTrendingRatings(items, service)
+	log.Printf(
+		"[metadata] discover %s handler complete limit=%d offset=%d count=%d total=%d duration=%s",
+		logLabel,
+		limit,
+		offset,
+		len(items),
+		total,
+		time.Since(start).Round(time.Millisecond),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.displayTitles(r, userID, DiscoverNewResponse{Items: items, Total: total}))
+}
+
+// DiscoverMoods lists the curated moods DiscoverByMood accepts.
+func (h *MetadataHandler) DiscoverMoods(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.Service.(moodDiscoverService)
+	if !ok {
+		writeJSONError(w, "mood discovery not supported", http.StatusNotImplemented)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]models.Mood{"moods": svc.Moods()})
+}
+
+// DiscoverByMood returns TMDB discover results for a curated mood
+// (?mood=heist), paged with limit and offset like the genre and decade
+// discover endpoints.
+func (h *MetadataHandler) DiscoverByMood(w http.ResponseWriter, r *http.Request) {
+	userID := strings.TrimSpace(r.URL.Query().Get("userId"))
+	service := h.serviceForUser(userID)
+	svc, ok := service.(moodDiscoverService)
+	if !ok {
+		writeJSONError(w, "mood discovery not supported", http.StatusNotImplemented)
+		return
+	}
+	mood := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("mood")))
+	if mood == "" {
+		writeJSONError(w, "mood is required", http.StatusBadRequest)
+		return
+	}
+	mediaType := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("type")))
+	h.serveDiscoverShelf(w, r, userID, service, fmt.Sprintf("mood=%s type=%s", mood, mediaType),
+		func(limit, offset int, opts metadatapkg.ShelfLoadOptions) ([]models.TrendingItem, int, error) {
+			return svc.DiscoverByMood(r.Context(), mediaType, mood, limit, offset, opts)
+		})
+}
+
+// SearchKeywords looks up TMDB keywords by name (?query=), for building
+// keywordIds filters on the advanced discover endpoint and saved filters.
+func (h *MetadataHandler) SearchKeywords(w http.ResponseWriter, r *http.Request) {
+	svc, ok := h.Service.(moodDiscoverService)
+	if !ok {
+		writeJSONError(w, "keyword search not supported", http.StatusNotImplemented)
+		return
+	}
+	query := strings.TrimSpace(r.URL.Query().Get("query"))
+	if query == "" {
+		writeJSONError(w, "query is required", http.StatusBadRequest)
+		return
+	}
+	keywords, err := svc.SearchKeywords(r.Context(), query)
+	if err != nil {
+		writeServiceError(w, err, http.StatusBadGateway)
+		return
+	}
+	if keywords == nil {
+		keywords = []models.Keyword{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]models.Keyword{"keywords": keywords})
+}
+
+// aiFeatureEnabled reports whether the profile's account has AI
+// recommendations turned on. Master accounts pass the route's feature gate
+// for any profile, so recommendations they fetch for a profile whose account
+// has the feature off are returned without AI-written reasons.
+func (h *MetadataHandler) aiFeatureEnabled(userID string) bool {
+	if h.UsersService == nil || h.AccountsService == nil {
+		return true
+	}
+	user, ok := h.UsersService.Get(userID)
+	if !ok {
+		return true
+	}
+	account, ok := h.AccountsService.Get(user.AccountID)
+	return !ok || account.HasFeature(models.FeatureAIRecommendations)
 }
 
 // GetAIRecommendations returns AI-powered personalized recommendations.
@@ -2005,6 +2848,26 @@ func (h *MetadataHandler) GetAIRecommendations(w http.ResponseWriter, r *http.Re
 	var mediaTypes []string
 	seen := make(map[string]bool)
 
+	// High-priority watchlist titles lead the seeds so they survive the cap
+	// below, normal ones follow the history and "someday" ones are left out.
+	var wl []models.WatchlistItem
+	if h.WatchlistService != nil {
+		if items, err := h.WatchlistService.List(userID); err == nil {
+			wl = items
+			_ = watchlist.SortItems(wl, watchlist.SortPriority, "")
+		}
+	}
+	for _, item := range wl {
+		if item.Priority != models.WatchlistPriorityHigh {
+			break
+		}
+		if item.Name != "" && !seen[item.Name] {
+			seen[item.Name] = true
+			watchedTitles = append(watchedTitles, item.Name)
+			mediaTypes = append(mediaTypes, item.MediaType)
+		}
+	}
+
 	// From watch history (recently watched items)
 	if h.HistoryService != nil {
 		history, err := h.HistoryService.ListWatchHistory(userID)
@@ -2030,16 +2893,14 @@ func (h *MetadataHandler) GetAIRecommendations(w http.ResponseWriter, r *http.Re
 	}
 
 	// From watchlist
-	if h.WatchlistService != nil {
-		wl, err := h.WatchlistService.List(userID)
-		if err == nil {
-			for _, item := range wl {
-				if item.Name != "" && !seen[item.Name] {
-					seen[item.Name] = true
-					watchedTitles = append(watchedTitles, item.Name)
-					mediaTypes = append(mediaTypes, item.MediaType)
-				}
-			}
+	for _, item := range wl {
+		if item.Priority == models.WatchlistPrioritySomeday {
+			break
+		}
+		if item.Name != "" && !seen[item.Name] {
+			seen[item.Name] = true
+			watchedTitles = append(watchedTitles, item.Name)
+			mediaTypes = append(mediaTypes, item.MediaType)
 		}
 	}
 
@@ -2056,19 +2917,17 @@ func (h *MetadataHandler) GetAIRecommendations(w http.ResponseWriter, r *http.Re
 	}
 
 	service := h.serviceForUser(userID)
-	items, err := service.GetAIRecommendations(r.Context(), watchedTitles, mediaTypes, userID)
+	items, err := service.GetAIRecommendations(r.Context(), watchedTitles, mediaTypes, userID, h.aiFeatureEnabled(userID))
 	if err != nil {
 		log.Printf("[metadata] ai recommendations error user=%s: %v", userID, err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
 	if items == nil {
 		items = []models.TrendingItem{}
 	}
-	items = h.filterTrendingByKids(r.Context(), userID, service, items)
+	items = h.filterTrendingByRating(r, userID, service, items)
 	enrichTrendingRatings(items, service)
 
 	w.Header().Set("Content-Type", "application/json")
@@ -2099,16 +2958,14 @@ func (h *MetadataHandler) GetAISimilar(w http.ResponseWriter, r *http.Request) {
 	items, err := service.GetAISimilar(aiCtx, seedTitle, mediaType)
 	if err != nil {
 		log.Printf("[metadata] ai similar error seed=%q: %v", seedTitle, err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
 	if items == nil {
 		items = []models.TrendingItem{}
 	}
-	items = h.filterTrendingByKids(r.Context(), strings.TrimSpace(r.URL.Query().Get("userId")), service, items)
+	items = h.filterTrendingByRating(r, strings.TrimSpace(r.URL.Query().Get("userId")), service, items)
 	enrichTrendingRatings(items, service)
 
 	w.Header().Set("Content-Type", "application/json")
@@ -2129,16 +2986,14 @@ func (h *MetadataHandler) GetAICustomRecommendations(w http.ResponseWriter, r *h
 	items, err := service.GetAICustomRecommendations(r.Context(), query)
 	if err != nil {
 		log.Printf("[metadata] ai custom recommendations error query=%q: %v", query, err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
 	if items == nil {
 		items = []models.TrendingItem{}
 	}
-	items = h.filterTrendingByKids(r.Context(), strings.TrimSpace(r.URL.Query().Get("userId")), service, items)
+	items = h.filterTrendingByRating(r, strings.TrimSpace(r.URL.Query().Get("userId")), service, items)
 	enrichTrendingRatings(items, service)
 
 	w.Header().Set("Content-Type", "application/json")
@@ -2153,15 +3008,13 @@ func (h *MetadataHandler) GetAISurprise(w http.ResponseWriter, r *http.Request)
 	item, err := service.GetAISurprise(r.Context(), decade, mediaType)
 	if err != nil {
 		log.Printf("[metadata] ai surprise error: %v", err)
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
 	// Drop the suggestion if it exceeds a kids profile's rating limit.
 	if item != nil {
-		if filtered := h.filterTrendingByKids(r.Context(), strings.TrimSpace(r.URL.Query().Get("userId")), service, []models.TrendingItem{*item}); len(filtered) == 0 {
+		if filtered := h.filterTrendingByRating(r, strings.TrimSpace(r.URL.Query().Get("userId")), service, []models.TrendingItem{*item}); len(filtered) == 0 {
 			item = nil
 		}
 	}
@@ -2203,13 +3056,11 @@ func (h *MetadataHandler) TopTen(w http.ResponseWriter, r *http.Request) {
 		items, err = service.GetTopTen(r.Context(), mediaType, nil)
 	}
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		writeServiceError(w, err, http.StatusBadGateway)
 		return
 	}
 
-	items = h.filterTrendingByKids(r.Context(), strings.TrimSpace(r.URL.Query().Get("userId")), service, items)
+	items = h.filterTrendingByRating(r, strings.TrimSpace(r.URL.Query().Get("userId")), service, items)
 
 	enrichTrendingRatings(items, service)
 
@@ -2223,3 +3074,92 @@ func (h *MetadataHandler) GetProgress(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(snapshot)
 }
+
+type progressSubscriber interface {
+	SubscribeProgress() (<-chan metadatapkg.ProgressSnapshot, func())
+}
+
+// progressKeepaliveInterval keeps idle progress streams open through proxies.
+const progressKeepaliveInterval = 25 * time.Second
+
+// StreamProgress streams metadata enrichment progress via Server-Sent Events,
+// sending a snapshot whenever it changes.
+func (h *MetadataHandler) StreamProgress(w http.ResponseWriter, r *http.Request) {
+	subscriber, ok := h.Service.(progressSubscriber)
+	if !ok {
+		writeJSONError(w, "progress streaming not supported", http.StatusNotImplemented)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSONError(w, "streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	updates, unsubscribe := subscriber.SubscribeProgress()
+	defer unsubscribe()
+
+	keepalive := time.NewTicker(progressKeepaliveInterval)
+	defer keepalive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case snapshot, ok := <-updates:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(snapshot)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		case <-keepalive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+type progressCanceller interface {
+	CancelProgressTask(id string) bool
+}
+
+// CancelProgress stops a running progress task (a custom list enrichment or a
+// manual refresh). Tasks are shared across accounts, so only the master
+// account may cancel them. Returns 404 when the task is not running.
+func (h *MetadataHandler) CancelProgress(w http.ResponseWriter, r *http.Request) {
+	if !auth.IsMaster(r) {
+		writeJSONError(w, "admin access required", http.StatusForbidden)
+		return
+	}
+	canceller, ok := h.Service.(progressCanceller)
+	if !ok {
+		writeJSONError(w, "progress cancellation not supported", http.StatusNotImplemented)
+		return
+	}
+	var req struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	id := strings.TrimSpace(req.ID)
+	if id == "" {
+		writeJSONError(w, "id is required", http.StatusBadRequest)
+		return
+	}
+	if !canceller.CancelProgressTask(id) {
+		writeJSONError(w, "progress task not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -22,7 +22,48 @@ func metadataServiceForUser(service metadataService, cfg *config.Manager, userSe
 		return service
 	}
 	language, _ := resolveMetadataLanguage(settings, userSettings, userID)
-	return localized.WithLanguage(language)
+	regional := withMetadataRegion(localized.WithLanguage(language), resolveCertificationRegion(settings, userSettings, userID))
+	return withArtworkPreferences(regional, resolveArtworkPreferences(settings, userSettings, userID))
+}
+
+// metadataServiceForRequest is metadataServiceForUser with a per-request
+// language override. A requested language that is not enabled in the metadata
+// settings is ignored, falling back to the profile and then global language.
+func metadataServiceForRequest(service metadataService, cfg *config.Manager, userSettings userSettingsProvider, userID, requested string) metadataService {
+	if strings.TrimSpace(requested) == "" || service == nil || cfg == nil {
+		return metadataServiceForUser(service, cfg, userSettings, userID)
+	}
+	localized, ok := service.(interface {
+		WithLanguage(string) *metadatapkg.Service
+	})
+	if !ok {
+		return service
+	}
+	settings, err := cfg.Load()
+	if err != nil {
+		return service
+	}
+	language, enabled := enabledMetadataLanguage(settings, requested)
+	if !enabled {
+		language, _ = resolveMetadataLanguage(settings, userSettings, userID)
+	}
+	regional := withMetadataRegion(localized.WithLanguage(language), resolveCertificationRegion(settings, userSettings, userID))
+	return withArtworkPreferences(regional, resolveArtworkPreferences(settings, userSettings, userID))
+}
+
+// enabledMetadataLanguage returns the configured spelling of language if it is
+// one of the enabled metadata languages.
+func enabledMetadataLanguage(settings config.Settings, language string) (string, bool) {
+	language = strings.TrimSpace(language)
+	if language == "" {
+		return "", false
+	}
+	for _, enabled := range settings.Metadata.Language {
+		if strings.EqualFold(strings.TrimSpace(enabled), language) {
+			return strings.TrimSpace(enabled), true
+		}
+	}
+	return "", false
 }
 
 // resolveMetadataLanguage returns the effective metadata language for the given
@@ -33,15 +74,59 @@ func resolveMetadataLanguage(settings config.Settings, userSettings userSettings
 	language = global
 	if userSettings != nil && strings.TrimSpace(userID) != "" {
 		if profileSettings, err := userSettings.Get(userID); err == nil && profileSettings != nil {
-			if profileLanguage := strings.TrimSpace(profileSettings.Metadata.PrimaryLanguage); profileLanguage != "" {
-				for _, enabled := range settings.Metadata.Language {
-					if strings.EqualFold(strings.TrimSpace(enabled), profileLanguage) {
-						language = strings.TrimSpace(enabled)
-						break
-					}
-				}
+			if profileLanguage, ok := enabledMetadataLanguage(settings, profileSettings.Metadata.PrimaryLanguage); ok {
+				language = profileLanguage
 			}
 		}
 	}
 	return language, strings.EqualFold(language, global)
 }
+
+// resolveCertificationRegion returns the country whose content ratings and
+// release dates the profile sees: its own override, else the global setting.
+func resolveCertificationRegion(settings config.Settings, userSettings userSettingsProvider, userID string) string {
+	if userSettings != nil && strings.TrimSpace(userID) != "" {
+		if profileSettings, err := userSettings.Get(userID); err == nil && profileSettings != nil {
+			if region := strings.TrimSpace(profileSettings.Metadata.CertificationCountry); region != "" {
+				return strings.ToUpper(region)
+			}
+		}
+	}
+	return strings.ToUpper(strings.TrimSpace(settings.Metadata.CertificationCountry))
+}
+
+// withMetadataRegion scopes service to region when it supports per-request
+// regions.
+func withMetadataRegion(service metadataService, region string) metadataService {
+	regional, ok := service.(interface {
+		WithRegion(string) *metadatapkg.Service
+	})
+	if !ok {
+		return service
+	}
+	return regional.WithRegion(region)
+}
+
+// resolveArtworkPreferences returns the profile's artwork preferences layered
+// over the server's.
+func resolveArtworkPreferences(settings config.Settings, userSettings userSettingsProvider, userID string) config.ArtworkPreferences {
+	prefs := settings.Metadata.Artwork
+	if userSettings != nil && strings.TrimSpace(userID) != "" {
+		if profileSettings, err := userSettings.Get(userID); err == nil && profileSettings != nil {
+			prefs = prefs.Merge(profileSettings.Metadata.Artwork)
+		}
+	}
+	return prefs
+}
+
+// withArtworkPreferences scopes service to prefs when it supports per-request
+// artwork preferences.
+func withArtworkPreferences(service metadataService, prefs config.ArtworkPreferences) metadataService {
+	artwork, ok := service.(interface {
+		WithArtworkPreferences(metadatapkg.ArtworkPreferences) *metadatapkg.Service
+	})
+	if !ok {
+		return service
+	}
+	return artwork.WithArtworkPreferences(ArtworkPreferences(prefs))
+}
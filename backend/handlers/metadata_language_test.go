@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"testing"
+
+	"novastream/config"
+	"novastream/models"
+)
+
+func TestEnabledMetadataLanguage(t *testing.T) {
+	settings := config.Settings{}
+	settings.Metadata.Language = []string{"en-US", "de-DE"}
+
+	if got, ok := enabledMetadataLanguage(settings, " de-de "); !ok || got != "de-DE" {
+		t.Fatalf("expected enabled de-DE, got %q ok=%v", got, ok)
+	}
+	if _, ok := enabledMetadataLanguage(settings, "fr-FR"); ok {
+		t.Fatal("expected fr-FR to be rejected when not enabled")
+	}
+	if _, ok := enabledMetadataLanguage(settings, ""); ok {
+		t.Fatal("expected empty language to be rejected")
+	}
+}
+
+type stubProfileSettings struct{ settings *models.UserSettings }
+
+func (s stubProfileSettings) Get(string) (*models.UserSettings, error) { return s.settings, nil }
+
+func TestResolveCertificationRegion(t *testing.T) {
+	settings := config.Settings{}
+	settings.Metadata.CertificationCountry = "gb"
+
+	if got := resolveCertificationRegion(settings, nil, "user"); got != "GB" {
+		t.Fatalf("expected global GB, got %q", got)
+	}
+	profile := stubProfileSettings{settings: &models.UserSettings{}}
+	if got := resolveCertificationRegion(settings, profile, "user"); got != "GB" {
+		t.Fatalf("expected profile without override to inherit GB, got %q", got)
+	}
+	profile.settings.Metadata.CertificationCountry = "DE"
+	if got := resolveCertificationRegion(settings, profile, "user"); got != "DE" {
+		t.Fatalf("expected profile override DE, got %q", got)
+	}
+}
+
+func TestResolveArtworkPreferences(t *testing.T) {
+	settings := config.Settings{}
+	settings.Metadata.Artwork = config.ArtworkPreferences{Source: "tvdb", MinPosterWidth: 1000}
+
+	if got := resolveArtworkPreferences(settings, nil, "user"); got != settings.Metadata.Artwork {
+		t.Fatalf("expected server preferences, got %+v", got)
+	}
+	profile := stubProfileSettings{settings: &models.UserSettings{}}
+	profile.settings.Metadata.Artwork = config.ArtworkPreferences{Language: "de", Textless: "avoid"}
+	got := resolveArtworkPreferences(settings, profile, "user")
+	want := config.ArtworkPreferences{Language: "de", Textless: "avoid", Source: "tvdb", MinPosterWidth: 1000}
+	if got != want {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+	if got := resolveArtworkPreferences(settings, profile, ""); got != settings.Metadata.Artwork {
+		t.Fatalf("expected server preferences without a profile, got %+v", got)
+	}
+}
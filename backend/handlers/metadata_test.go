@@ -9,13 +9,17 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/gorilla/mux"
+
 	"novastream/config"
+	"novastream/internal/auth"
 	"novastream/models"
 	"novastream/services/letterboxd"
 	"novastream/services/mdblist"
@@ -233,6 +237,14 @@ func (f *fakeMetadataService) ServePrequeuedTrailer(_ string, _ http.ResponseWri
 	return nil
 }
 
+func (f *fakeMetadataService) TrailerPreviews() []metadata.TrailerPreview {
+	return nil
+}
+
+func (f *fakeMetadataService) ServeTrailerPreview(_ string, _ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
 func (f *fakeMetadataService) PersonDetails(_ context.Context, _ int64) (*models.PersonDetails, error) {
 	return nil, nil
 }
@@ -285,7 +297,7 @@ func (f *fakeMetadataService) DiscoverByDecadeWithOptions(_ context.Context, med
 	return f.discoverByDecadeResp, f.discoverByDecadeTotal, f.discoverByDecadeErr
 }
 
-func (f *fakeMetadataService) GetAIRecommendations(_ context.Context, _ []string, _ []string, _ string) ([]models.TrendingItem, error) {
+func (f *fakeMetadataService) GetAIRecommendations(_ context.Context, _ []string, _ []string, _ string, _ bool) ([]models.TrendingItem, error) {
 	return nil, nil
 }
 
@@ -461,6 +473,169 @@ func TestMetadataHandler_DiscoverNew(t *testing.T) {
 	}
 }
 
+type fakeTrendingPageService struct {
+	*fakeMetadataService
+	pageCalls  int
+	lastLimit  int
+	lastOffset int
+}
+
+func (f *fakeTrendingPageService) TrendingPage(_ context.Context, mediaType, _ string, limit, offset int, _ metadata.ShelfLoadOptions) ([]models.TrendingItem, int, error) {
+	f.pageCalls++
+	f.lastTrendingType = mediaType
+	f.lastLimit = limit
+	f.lastOffset = offset
+	items := f.trendingResp
+	if offset >= len(items) {
+		return []models.TrendingItem{}, len(f.trendingResp), f.trendingErr
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items, len(f.trendingResp), f.trendingErr
+}
+
+func TestMetadataHandler_DiscoverNewRejectsUnknownTrendingSource(t *testing.T) {
+	fake := &fakeTrendingPageService{fakeMetadataService: &fakeMetadataService{
+		trendingErr: metadata.ErrUnknownTrendingSource,
+	}}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+
+	rec := httptest.NewRecorder()
+	handler.DiscoverNew(rec, httptest.NewRequest(http.MethodGet, "/api/discover/new?type=movie&trendingSource=bogus&limit=10", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d for an unknown trending source, got %d: %s", http.StatusBadRequest, rec.Code, rec.Body.String())
+	}
+}
+
+func TestMetadataHandler_DiscoverNewPaginatesInService(t *testing.T) {
+	fake := &fakeTrendingPageService{fakeMetadataService: &fakeMetadataService{
+		trendingResp: []models.TrendingItem{
+			{Rank: 1, Title: models.Title{Name: "Lost", MediaType: "series"}},
+			{Rank: 2, Title: models.Title{Name: "Fringe", MediaType: "series"}},
+			{Rank: 3, Title: models.Title{Name: "Alias", MediaType: "series"}},
+		},
+	}}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+
+	rec := httptest.NewRecorder()
+	handler.DiscoverNew(rec, httptest.NewRequest(http.MethodGet, "/api/discover/new?type=series&limit=1&offset=1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+	}
+	if fake.pageCalls != 1 || fake.lastLimit != 1 || fake.lastOffset != 1 {
+		t.Fatalf("expected service pagination with limit=1 offset=1, got calls=%d limit=%d offset=%d", fake.pageCalls, fake.lastLimit, fake.lastOffset)
+	}
+	var payload DiscoverNewResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.Total != 3 || len(payload.Items) != 1 || payload.Items[0].Title.Name != "Fringe" {
+		t.Fatalf("unexpected payload: %+v", payload)
+	}
+
+	// Filters need the whole list, so the handler paginates after filtering.
+	rec = httptest.NewRecorder()
+	handler.DiscoverNew(rec, httptest.NewRequest(http.MethodGet, "/api/discover/new?type=series&limit=1&hideUnreleased=true", nil))
+	if fake.pageCalls != 1 {
+		t.Fatalf("expected filtered request to bypass service pagination, got %d calls", fake.pageCalls)
+	}
+}
+
+func TestMetadataHandler_DiscoverNewNotModified(t *testing.T) {
+	fake := &fakeMetadataService{
+		trendingResp: []models.TrendingItem{{Rank: 1, Title: models.Title{Name: "Lost", MediaType: "tv"}}},
+	}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+
+	first := httptest.NewRecorder()
+	handler.DiscoverNew(first, httptest.NewRequest(http.MethodGet, "/api/discover/new?type=movie", nil))
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with ETag, got %d etag=%q", first.Code, etag)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/discover/new?type=movie", nil)
+	req.Header.Set("If-None-Match", etag)
+	second := httptest.NewRecorder()
+	handler.DiscoverNew(second, req)
+	if second.Code != http.StatusNotModified {
+		t.Fatalf("expected %d, got %d", http.StatusNotModified, second.Code)
+	}
+	if second.Body.Len() != 0 {
+		t.Fatalf("expected empty body for 304, got %q", second.Body.String())
+	}
+
+	fake.trendingResp = append(fake.trendingResp, models.TrendingItem{Rank: 2, Title: models.Title{Name: "Fringe", MediaType: "tv"}})
+	req = httptest.NewRequest(http.MethodGet, "/api/discover/new?type=movie", nil)
+	req.Header.Set("If-None-Match", etag)
+	third := httptest.NewRecorder()
+	handler.DiscoverNew(third, req)
+	if third.Code != http.StatusOK {
+		t.Fatalf("expected %d after content changed, got %d", http.StatusOK, third.Code)
+	}
+	if third.Header().Get("ETag") == etag {
+		t.Fatal("expected a new ETag after content changed")
+	}
+}
+
+func TestMetadataHandler_SeriesDetailsNotModifiedAsCountdownTicks(t *testing.T) {
+	fake := &fakeMetadataService{seriesResp: &models.SeriesDetails{
+		Title: models.Title{Name: "Severance", MediaType: "series", TVDBID: 371980},
+		NextEpisode: &models.NextEpisode{
+			SeasonNumber: 3, EpisodeNumber: 1,
+			AiredDateTimeUTC: "2027-01-17T02:00:00Z",
+			CountdownSeconds: 7200,
+		},
+	}}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+	const target = "/api/metadata/series/details?tvdbId=371980"
+
+	first := httptest.NewRecorder()
+	handler.SeriesDetails(first, httptest.NewRequest(http.MethodGet, target, nil))
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with ETag, got %d etag=%q", first.Code, etag)
+	}
+
+	// A second later the countdown has ticked but nothing else changed.
+	fake.seriesResp.NextEpisode.CountdownSeconds--
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("If-None-Match", etag)
+	second := httptest.NewRecorder()
+	handler.SeriesDetails(second, req)
+	if second.Code != http.StatusNotModified {
+		t.Fatalf("expected %d a second later, got %d", http.StatusNotModified, second.Code)
+	}
+
+	fake.seriesResp.NextEpisode.AiredDateTimeUTC = "2027-01-24T02:00:00Z"
+	req = httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("If-None-Match", etag)
+	third := httptest.NewRecorder()
+	handler.SeriesDetails(third, req)
+	if third.Code != http.StatusOK {
+		t.Fatalf("expected %d after the air date moved, got %d", http.StatusOK, third.Code)
+	}
+}
+
+func TestEtagMatches(t *testing.T) {
+	etag := `"abc"`
+	cases := map[string]bool{
+		"":             false,
+		`"abc"`:        true,
+		`W/"abc"`:      true,
+		`"xyz", "abc"`: true,
+		"*":            true,
+		`"xyz"`:        false,
+	}
+	for header, want := range cases {
+		if got := etagMatches(header, etag); got != want {
+			t.Errorf("etagMatches(%q) = %v, want %v", header, got, want)
+		}
+	}
+}
+
 func TestMetadataHandler_DiscoverNewError(t *testing.T) {
 	fake := &fakeMetadataService{trendingErr: errors.New("tmdb unavailable")}
 	handler := NewMetadataHandler(fake, testConfigManager(t))
@@ -1012,6 +1187,98 @@ func TestMetadataHandler_SearchKidsContentListReturnsEmpty(t *testing.T) {
 	}
 }
 
+// fakeAllowListMetadataService applies allow-lists by genre only.
+type fakeAllowListMetadataService struct {
+	*fakeMetadataService
+	lastAllow metadata.AllowList
+}
+
+func (f *fakeAllowListMetadataService) allowed(title models.Title) bool {
+	for _, genre := range title.Genres {
+		for _, allowed := range f.lastAllow.Genres {
+			if strings.EqualFold(genre, allowed) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (f *fakeAllowListMetadataService) FilterTrendingByAllowList(_ context.Context, items []models.TrendingItem, allow metadata.AllowList) []models.TrendingItem {
+	f.lastAllow = allow
+	var out []models.TrendingItem
+	for _, item := range items {
+		if f.allowed(item.Title) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func (f *fakeAllowListMetadataService) SearchWithAllowList(ctx context.Context, query, mediaType string, allow metadata.AllowList) ([]models.SearchResult, error) {
+	f.lastAllow = allow
+	results, err := f.Search(ctx, query, mediaType)
+	var out []models.SearchResult
+	for _, result := range results {
+		if f.allowed(result.Title) {
+			out = append(out, result)
+		}
+	}
+	return out, err
+}
+
+func TestMetadataHandler_KidsAllowListFiltersSearchAndDiscover(t *testing.T) {
+	fake := &fakeAllowListMetadataService{fakeMetadataService: &fakeMetadataService{
+		searchResp: []models.SearchResult{
+			{Title: models.Title{Name: "Cartoon", MediaType: "movie", Genres: []string{"Animation"}}},
+			{Title: models.Title{Name: "Slasher", MediaType: "movie", Genres: []string{"Horror"}}},
+		},
+		discoverByGenreResp: []models.TrendingItem{
+			{Rank: 1, Title: models.Title{Name: "Cartoon", MediaType: "movie", Genres: []string{"Animation"}}},
+			{Rank: 2, Title: models.Title{Name: "Slasher", MediaType: "movie", Genres: []string{"Horror"}}},
+		},
+		discoverByGenreTotal: 2,
+	}}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+	handler.SetUsersService(&fakeUsersServiceForSearch{
+		users: map[string]models.User{
+			"kid1": {ID: "kid1", IsKidsProfile: true, KidsMode: "allow_list", KidsAllowedGenres: []string{"Animation"}, KidsAllowedLists: []string{"tmdb:list:1"}},
+		},
+	})
+
+	rec := httptest.NewRecorder()
+	handler.Search(rec, httptest.NewRequest(http.MethodGet, "/api/search?q=x&type=movie&userId=kid1", nil))
+	var results []models.SearchResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil {
+		t.Fatalf("decode search: %v", err)
+	}
+	if len(results) != 1 || results[0].Title.Name != "Cartoon" {
+		t.Fatalf("expected only the allowed title from search, got %+v", results)
+	}
+	if len(fake.lastAllow.Lists) != 1 || fake.lastAllow.Lists[0] != "tmdb:list:1" {
+		t.Fatalf("expected the profile's lists to reach the service, got %+v", fake.lastAllow)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.DiscoverByGenre(rec, httptest.NewRequest(http.MethodGet, "/api/discover/genre?type=movie&genreId=16&userId=kid1", nil))
+	var payload DiscoverNewResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode discover: %v", err)
+	}
+	if len(payload.Items) != 1 || payload.Total != 1 {
+		t.Fatalf("expected one allowed discover item, got %+v", payload)
+	}
+
+	// Services that cannot apply an allow-list show nothing rather than everything.
+	plain := NewMetadataHandler(fake.fakeMetadataService, testConfigManager(t))
+	plain.SetUsersService(handler.UsersService)
+	rec = httptest.NewRecorder()
+	plain.Search(rec, httptest.NewRequest(http.MethodGet, "/api/search?q=x&type=movie&userId=kid1", nil))
+	if strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Fatalf("expected empty results without allow-list support, got %s", rec.Body.String())
+	}
+}
+
 func TestMetadataHandler_TopTenKidsRatingFilters(t *testing.T) {
 	fake := &fakeMetadataService{
 		trendingResp: []models.TrendingItem{
@@ -1237,6 +1504,59 @@ func TestMetadataHandler_DiscoverByGenreInvalidGenreID(t *testing.T) {
 	}
 }
 
+type fakeGenreHubService struct {
+	*fakeMetadataService
+	lastType    string
+	lastGenreID int64
+	lastLimit   int
+}
+
+func (f *fakeGenreHubService) GenreHub(_ context.Context, mediaType string, genreID int64, limit int, _ metadata.ShelfLoadOptions) (*models.GenreHub, error) {
+	f.lastType, f.lastGenreID, f.lastLimit = mediaType, genreID, limit
+	return &models.GenreHub{
+		Name:          "Sci-Fi",
+		MovieGenreID:  878,
+		SeriesGenreID: 10765,
+		Rows: []models.GenreHubRow{
+			{ID: "trending", Title: "Trending", Items: []models.TrendingItem{
+				{Rank: 1, Title: models.Title{ID: "tmdb:movie:603", Name: "The Matrix", MediaType: "movie"}},
+				{Rank: 2, Title: models.Title{ID: "tmdb:tv:93740", Name: "Foundation", MediaType: "series"}},
+			}},
+			{ID: "new", Title: "New"},
+		},
+	}, nil
+}
+
+func TestMetadataHandler_GenreHub(t *testing.T) {
+	fake := &fakeGenreHubService{fakeMetadataService: &fakeMetadataService{}}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/discover/genre/hub?type=movie&genreId=878&limit=12", nil)
+	rec := httptest.NewRecorder()
+	handler.GenreHub(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var hub models.GenreHub
+	if err := json.Unmarshal(rec.Body.Bytes(), &hub); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if len(hub.Rows) != 2 || len(hub.Rows[0].Items) != 2 || hub.Rows[1].Items == nil {
+		t.Fatalf("unexpected hub %+v", hub)
+	}
+	if fake.lastType != "movie" || fake.lastGenreID != 878 || fake.lastLimit != 12 {
+		t.Fatalf("unexpected call type=%s genre=%d limit=%d", fake.lastType, fake.lastGenreID, fake.lastLimit)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/discover/genre/hub?type=movie", nil)
+	rec = httptest.NewRecorder()
+	handler.GenreHub(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("missing genreId: expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
 func TestMetadataHandler_DiscoverByGenreError(t *testing.T) {
 	fake := &fakeMetadataService{
 		discoverByGenreErr: errors.New("tmdb unavailable"),
@@ -1411,6 +1731,27 @@ func TestMetadataHandler_GetAIRecommendationsMissingUserId(t *testing.T) {
 	}
 }
 
+func TestMetadataHandler_AIFeatureEnabled(t *testing.T) {
+	handler := NewMetadataHandler(&fakeMetadataService{}, testConfigManager(t))
+	if !handler.aiFeatureEnabled("user-1") {
+		t.Fatal("expected reasons without account information")
+	}
+	handler.SetUsersService(&fakeUsersServiceForSearch{users: map[string]models.User{
+		"kid":   {ID: "kid", AccountID: "acct-kids"},
+		"adult": {ID: "adult", AccountID: "acct-adult"},
+	}})
+	handler.SetAccountsService(&fakeAccountsServiceForMetadata{accounts: map[string]models.Account{
+		"acct-kids":  {ID: "acct-kids", DeniedFeatures: []string{models.FeatureAIRecommendations}},
+		"acct-adult": {ID: "acct-adult"},
+	}})
+	if handler.aiFeatureEnabled("kid") {
+		t.Fatal("expected no reasons for a profile whose account has AI recommendations off")
+	}
+	if !handler.aiFeatureEnabled("adult") {
+		t.Fatal("expected reasons for a profile whose account has AI recommendations on")
+	}
+}
+
 func TestMetadataHandler_GetAIRecommendationsEmptyHistory(t *testing.T) {
 	fake := &fakeMetadataService{}
 	handler := NewMetadataHandler(fake, testConfigManager(t))
@@ -1972,3 +2313,469 @@ func TestMetadataHandler_SearchYouTubeVideos_ClampsLimit(t *testing.T) {
 		t.Fatalf("unexpected response: %+v", resp)
 	}
 }
+
+type fakeSeasonDetailsService struct {
+	*fakeMetadataService
+	seasonResp *models.SeriesSeason
+	lastSeason int
+}
+
+func (f *fakeSeasonDetailsService) SeasonDetails(_ context.Context, query models.SeriesDetailsQuery, seasonNumber int) (*models.SeriesSeason, error) {
+	f.lastSeriesQuery = query
+	f.lastSeason = seasonNumber
+	return f.seasonResp, nil
+}
+
+func TestMetadataHandler_SeasonDetails(t *testing.T) {
+	fake := &fakeSeasonDetailsService{
+		fakeMetadataService: &fakeMetadataService{},
+		seasonResp: &models.SeriesSeason{Number: 2, Episodes: []models.SeriesEpisode{
+			{SeasonNumber: 2, EpisodeNumber: 1, Ratings: []models.Rating{{Source: "tmdb", Value: 8.4, Max: 10}}},
+		}},
+	}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+
+	rec := httptest.NewRecorder()
+	handler.SeasonDetails(rec, httptest.NewRequest(http.MethodGet, "/api/metadata/series/season?tvdbId=371980&season=2", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if fake.lastSeason != 2 || fake.lastSeriesQuery.TVDBID != 371980 {
+		t.Fatalf("unexpected query season=%d %+v", fake.lastSeason, fake.lastSeriesQuery)
+	}
+	var season models.SeriesSeason
+	if err := json.Unmarshal(rec.Body.Bytes(), &season); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(season.Episodes) != 1 || len(season.Episodes[0].Ratings) != 1 {
+		t.Fatalf("unexpected season %+v", season)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.SeasonDetails(rec, httptest.NewRequest(http.MethodGet, "/api/metadata/series/season?tvdbId=371980", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without a season, got %d", rec.Code)
+	}
+}
+
+func TestMetadataHandler_SeasonDetailsSortByRating(t *testing.T) {
+	tmdb := func(value float64, votes int) []models.Rating {
+		return []models.Rating{{Source: "tmdb", Value: value, Max: 10, Votes: votes}}
+	}
+	fake := &fakeSeasonDetailsService{
+		fakeMetadataService: &fakeMetadataService{},
+		seasonResp: &models.SeriesSeason{Number: 1, Episodes: []models.SeriesEpisode{
+			{EpisodeNumber: 1, Ratings: tmdb(7.9, 300)},
+			{EpisodeNumber: 2},
+			{EpisodeNumber: 3, Ratings: tmdb(9.1, 150)},
+			{EpisodeNumber: 4, Ratings: tmdb(7.9, 420)},
+		}},
+	}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+
+	rec := httptest.NewRecorder()
+	handler.SeasonDetails(rec, httptest.NewRequest(http.MethodGet, "/api/metadata/series/season?tvdbId=371980&season=1&sort=rating", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var season models.SeriesSeason
+	if err := json.Unmarshal(rec.Body.Bytes(), &season); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	var order []int
+	for _, episode := range season.Episodes {
+		order = append(order, episode.EpisodeNumber)
+	}
+	if want := []int{3, 4, 1, 2}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("expected episode order %v, got %v", want, order)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.SeasonDetails(rec, httptest.NewRequest(http.MethodGet, "/api/metadata/series/season?tvdbId=371980&season=1&sort=popularity", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown sort, got %d", rec.Code)
+	}
+}
+
+func TestMetadataHandler_SeriesDetailsWithoutEpisodes(t *testing.T) {
+	fake := &fakeMetadataService{
+		seriesResp: &models.SeriesDetails{
+			Title: models.Title{ID: "tvdb:series:371980", Name: "Severance"},
+			Seasons: []models.SeriesSeason{{Number: 1, Episodes: []models.SeriesEpisode{
+				{SeasonNumber: 1, EpisodeNumber: 1}, {SeasonNumber: 1, EpisodeNumber: 2},
+			}}},
+		},
+	}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+
+	rec := httptest.NewRecorder()
+	handler.SeriesDetails(rec, httptest.NewRequest(http.MethodGet, "/api/metadata/series/details?tvdbId=371980&includeEpisodes=false", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var details models.SeriesDetails
+	if err := json.Unmarshal(rec.Body.Bytes(), &details); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(details.Seasons) != 1 || len(details.Seasons[0].Episodes) != 0 || details.Seasons[0].EpisodeCount != 2 {
+		t.Fatalf("expected season summary without episodes, got %+v", details.Seasons)
+	}
+	if len(fake.seriesResp.Seasons[0].Episodes) != 2 {
+		t.Fatal("stripping episodes modified the service's details")
+	}
+}
+
+type fakeProgressCanceller struct {
+	*fakeMetadataService
+	running   map[string]bool
+	cancelled []string
+}
+
+func (f *fakeProgressCanceller) CancelProgressTask(id string) bool {
+	if !f.running[id] {
+		return false
+	}
+	f.cancelled = append(f.cancelled, id)
+	return true
+}
+
+func TestMetadataHandler_CancelProgress(t *testing.T) {
+	fake := &fakeProgressCanceller{
+		fakeMetadataService: &fakeMetadataService{},
+		running:             map[string]bool{"cache-refresh": true},
+	}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+
+	cancel := func(body string, master bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/metadata/progress/cancel", strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyIsMaster, master))
+		rec := httptest.NewRecorder()
+		handler.CancelProgress(rec, req)
+		return rec
+	}
+
+	if rec := cancel(`{"id":"cache-refresh"}`, false); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for non-master, got %d", rec.Code)
+	}
+	if rec := cancel(`{"id":""}`, true); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for missing id, got %d", rec.Code)
+	}
+	if rec := cancel(`{"id":"custom-list:nope"}`, true); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown task, got %d", rec.Code)
+	}
+	if rec := cancel(`{"id":"cache-refresh"}`, true); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(fake.cancelled) != 1 || fake.cancelled[0] != "cache-refresh" {
+		t.Fatalf("unexpected cancellations: %v", fake.cancelled)
+	}
+}
+
+type fakeArtworkRefresher struct {
+	*fakeMetadataService
+	calls []string
+}
+
+func (f *fakeArtworkRefresher) RefreshArtwork(mediaType string, tmdbID, tvdbID int64) (int, error) {
+	f.calls = append(f.calls, mediaType+":"+strconv.FormatInt(tmdbID, 10)+":"+strconv.FormatInt(tvdbID, 10))
+	return 3, nil
+}
+
+func TestMetadataHandler_RefreshArtwork(t *testing.T) {
+	fake := &fakeArtworkRefresher{fakeMetadataService: &fakeMetadataService{}}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+
+	refresh := func(body string, master bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/metadata/artwork/refresh", strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyIsMaster, master))
+		rec := httptest.NewRecorder()
+		handler.RefreshArtwork(rec, req)
+		return rec
+	}
+
+	if rec := refresh(`{"mediaType":"movie","tmdbId":603}`, false); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for non-master, got %d", rec.Code)
+	}
+	if rec := refresh(`{"mediaType":"episode","tmdbId":603}`, true); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for unknown media type, got %d", rec.Code)
+	}
+	if rec := refresh(`{"mediaType":"series"}`, true); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without ids, got %d", rec.Code)
+	}
+	rec := refresh(`{"mediaType":"Series","tvdbId":81189}`, true)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Removed int `json:"removed"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || resp.Removed != 3 {
+		t.Fatalf("unexpected response %s (%v)", rec.Body.String(), err)
+	}
+	if len(fake.calls) != 1 || fake.calls[0] != "series:0:81189" {
+		t.Fatalf("unexpected refresh calls: %v", fake.calls)
+	}
+}
+
+type fakeIdentityOverrides struct {
+	*fakeMetadataService
+	overrides map[string]metadata.IdentityOverride
+}
+
+func (f *fakeIdentityOverrides) IdentityOverrides() []metadata.IdentityOverride {
+	list := make([]metadata.IdentityOverride, 0, len(f.overrides))
+	for _, override := range f.overrides {
+		list = append(list, override)
+	}
+	return list
+}
+
+func (f *fakeIdentityOverrides) SetIdentityOverride(override metadata.IdentityOverride) (metadata.IdentityOverride, error) {
+	f.overrides[override.TitleID] = override
+	return override, nil
+}
+
+func (f *fakeIdentityOverrides) RemoveIdentityOverride(titleID string) error {
+	if _, ok := f.overrides[titleID]; !ok {
+		return metadata.ErrNotFound
+	}
+	delete(f.overrides, titleID)
+	return nil
+}
+
+func TestMetadataHandler_IdentityOverrides(t *testing.T) {
+	fake := &fakeIdentityOverrides{fakeMetadataService: &fakeMetadataService{}, overrides: map[string]metadata.IdentityOverride{}}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+	asMaster := func(req *http.Request, master bool) *http.Request {
+		return req.WithContext(context.WithValue(req.Context(), auth.ContextKeyIsMaster, master))
+	}
+	set := func(body string, master bool) *httptest.ResponseRecorder {
+		req := asMaster(httptest.NewRequest(http.MethodPost, "/api/metadata/identity-overrides", strings.NewReader(body)), master)
+		rec := httptest.NewRecorder()
+		handler.SetIdentityOverride(rec, req)
+		return rec
+	}
+	remove := func(titleID string) *httptest.ResponseRecorder {
+		req := asMaster(httptest.NewRequest(http.MethodDelete, "/api/metadata/identity-overrides/"+titleID, nil), true)
+		req = mux.SetURLVars(req, map[string]string{"titleID": titleID})
+		rec := httptest.NewRecorder()
+		handler.RemoveIdentityOverride(rec, req)
+		return rec
+	}
+
+	if rec := set(`{"titleId":"tmdb:tv:1396","mediaType":"series","tvdbId":81189}`, false); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for non-master, got %d", rec.Code)
+	}
+	if rec := set(`{"titleId":"tmdb:tv:1396","mediaType":"series"}`, true); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without ids, got %d", rec.Code)
+	}
+	if rec := set(`{"mediaType":"series","tvdbId":81189}`, true); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without a title id, got %d", rec.Code)
+	}
+	if rec := set(`{"titleId":"tmdb:tv:1396","mediaType":"series","tvdbId":81189}`, true); rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	req := asMaster(httptest.NewRequest(http.MethodGet, "/api/metadata/identity-overrides", nil), true)
+	rec := httptest.NewRecorder()
+	handler.ListIdentityOverrides(rec, req)
+	var resp struct {
+		Overrides []metadata.IdentityOverride `json:"overrides"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || len(resp.Overrides) != 1 || resp.Overrides[0].TVDBID != 81189 {
+		t.Fatalf("unexpected list response %s (%v)", rec.Body.String(), err)
+	}
+
+	if rec := remove("tmdb:tv:1396"); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", rec.Code)
+	}
+	if rec := remove("tmdb:tv:1396"); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a missing override, got %d", rec.Code)
+	}
+}
+
+type fakeTitleEdits struct {
+	*fakeMetadataService
+	edits  map[string]metadata.TitleEdits
+	locked []string
+}
+
+func (f *fakeTitleEdits) AllTitleEdits() []metadata.TitleEdits {
+	list := make([]metadata.TitleEdits, 0, len(f.edits))
+	for _, edits := range f.edits {
+		list = append(list, edits)
+	}
+	return list
+}
+
+func (f *fakeTitleEdits) SetTitleEdits(edits metadata.TitleEdits) (metadata.TitleEdits, error) {
+	if edits.MediaType != "movie" && edits.MediaType != "series" {
+		return metadata.TitleEdits{}, errors.New("media type must be movie or series")
+	}
+	f.edits[edits.TitleID] = edits
+	return edits, nil
+}
+
+func (f *fakeTitleEdits) LockTitleFields(_ context.Context, mediaType, titleID string, fields []string) (metadata.TitleEdits, error) {
+	f.locked = append(f.locked, fields...)
+	return metadata.TitleEdits{TitleID: titleID, MediaType: mediaType}, nil
+}
+
+func (f *fakeTitleEdits) RemoveTitleEdits(titleID string) error {
+	if _, ok := f.edits[titleID]; !ok {
+		return metadata.ErrNotFound
+	}
+	delete(f.edits, titleID)
+	return nil
+}
+
+func TestMetadataHandler_TitleEdits(t *testing.T) {
+	fake := &fakeTitleEdits{fakeMetadataService: &fakeMetadataService{}, edits: map[string]metadata.TitleEdits{}}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+	request := func(method, target, body string, master bool) *http.Request {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		return req.WithContext(context.WithValue(req.Context(), auth.ContextKeyIsMaster, master))
+	}
+
+	rec := httptest.NewRecorder()
+	handler.SetTitleEdits(rec, request(http.MethodPut, "/api/metadata/title-edits", `{"titleId":"tvdb:series:1","mediaType":"series","name":"Fixed"}`, false))
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for non-master, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	handler.SetTitleEdits(rec, request(http.MethodPut, "/api/metadata/title-edits", `{"titleId":"tvdb:series:1","mediaType":"episode"}`, true))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid edit, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	handler.SetTitleEdits(rec, request(http.MethodPut, "/api/metadata/title-edits", `{"titleId":"tvdb:series:1","mediaType":"series","name":"Fixed"}`, true))
+	if rec.Code != http.StatusOK || fake.edits["tvdb:series:1"].Name == nil {
+		t.Fatalf("expected the edit to be stored, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.LockTitleFields(rec, request(http.MethodPost, "/api/metadata/title-edits/lock", `{"titleId":"tvdb:series:1","mediaType":"series"}`, true))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without fields, got %d", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	handler.LockTitleFields(rec, request(http.MethodPost, "/api/metadata/title-edits/lock", `{"titleId":"tvdb:series:1","mediaType":"series","fields":["overview"]}`, true))
+	if rec.Code != http.StatusOK || len(fake.locked) != 1 || fake.locked[0] != "overview" {
+		t.Fatalf("expected the overview to be locked, got %d (%v)", rec.Code, fake.locked)
+	}
+
+	remove := func(titleID string) int {
+		req := mux.SetURLVars(request(http.MethodDelete, "/api/metadata/title-edits/"+titleID, "", true), map[string]string{"titleID": titleID})
+		rec := httptest.NewRecorder()
+		handler.RemoveTitleEdits(rec, req)
+		return rec.Code
+	}
+	if code := remove("tvdb:series:1"); code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d", code)
+	}
+	if code := remove("tvdb:series:1"); code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a title without edits, got %d", code)
+	}
+}
+
+type fakeRawPayloadService struct {
+	*fakeMetadataService
+	calls []string
+}
+
+func (f *fakeRawPayloadService) RawProviderPayloads(_ context.Context, mediaType string, tvdbID, tmdbID int64) *metadata.RawProviderPayloads {
+	f.calls = append(f.calls, mediaType+":"+strconv.FormatInt(tvdbID, 10)+":"+strconv.FormatInt(tmdbID, 10))
+	return &metadata.RawProviderPayloads{Payloads: map[string]json.RawMessage{"tmdb": json.RawMessage(`{"id":603,"budget":63000000}`)}}
+}
+
+func TestMetadataHandler_MovieDetailsIncludeRaw(t *testing.T) {
+	fake := &fakeRawPayloadService{fakeMetadataService: &fakeMetadataService{
+		movieResp: &models.Title{ID: "tmdb:movie:603", Name: "The Matrix", MediaType: "movie", TMDBID: 603, TVDBID: 169},
+	}}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+
+	details := func(query string, master bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/metadata/movies/details?titleId=tmdb:movie:603"+query, nil)
+		req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyIsMaster, master))
+		rec := httptest.NewRecorder()
+		handler.MovieDetails(rec, req)
+		return rec
+	}
+
+	if rec := details("&includeRaw=true", false); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for non-master, got %d", rec.Code)
+	}
+	if rec := details("", true); rec.Code != http.StatusOK || strings.Contains(rec.Body.String(), `"raw"`) || len(fake.calls) != 0 {
+		t.Fatalf("expected no raw payloads unless asked, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec := details("&includeRaw=true", true)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var payload struct {
+		Name string `json:"name"`
+		Raw  struct {
+			Payloads map[string]json.RawMessage `json:"payloads"`
+		} `json:"raw"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.Name != "The Matrix" || !strings.Contains(string(payload.Raw.Payloads["tmdb"]), `"budget":63000000`) {
+		t.Fatalf("unexpected payload: %s", rec.Body.String())
+	}
+	if len(fake.calls) != 1 || fake.calls[0] != "movie:169:603" {
+		t.Fatalf("unexpected raw payload calls: %v", fake.calls)
+	}
+}
+
+type fakeMoodDiscoverService struct {
+	*fakeMetadataService
+	lastMood, lastType string
+}
+
+func (f *fakeMoodDiscoverService) Moods() []models.Mood {
+	return []models.Mood{{ID: "heist", Name: "Heist"}}
+}
+
+func (f *fakeMoodDiscoverService) DiscoverByMood(_ context.Context, mediaType, mood string, _, _ int, _ metadata.ShelfLoadOptions) ([]models.TrendingItem, int, error) {
+	f.lastMood, f.lastType = mood, mediaType
+	return []models.TrendingItem{{Rank: 1, Title: models.Title{ID: "tmdb:movie:161", Name: "Ocean's Eleven", MediaType: "movie"}}}, 1, nil
+}
+
+func (f *fakeMoodDiscoverService) SearchKeywords(_ context.Context, query string) ([]models.Keyword, error) {
+	return []models.Keyword{{ID: 10051, Name: query}}, nil
+}
+
+func TestMetadataHandler_DiscoverByMood(t *testing.T) {
+	fake := &fakeMoodDiscoverService{fakeMetadataService: &fakeMetadataService{}}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+
+	rec := httptest.NewRecorder()
+	handler.DiscoverByMood(rec, httptest.NewRequest(http.MethodGet, "/api/discover/mood?mood=Heist&type=movie", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Ocean's Eleven") {
+		t.Fatalf("expected mood results, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if fake.lastMood != "heist" || fake.lastType != "movie" {
+		t.Fatalf("unexpected mood call mood=%q type=%q", fake.lastMood, fake.lastType)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.DiscoverByMood(rec, httptest.NewRequest(http.MethodGet, "/api/discover/mood?type=movie", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without a mood, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.DiscoverMoods(rec, httptest.NewRequest(http.MethodGet, "/api/discover/moods", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"id":"heist"`) {
+		t.Fatalf("unexpected moods response %d: %s", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	handler.SearchKeywords(rec, httptest.NewRequest(http.MethodGet, "/api/discover/keywords?query=heist", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"id":10051`) {
+		t.Fatalf("unexpected keyword response %d: %s", rec.Code, rec.Body.String())
+	}
+}
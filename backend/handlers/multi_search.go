@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"novastream/models"
+	"novastream/services/kids"
+)
+
+// multiSearchService searches movies, series and people in one call.
+type multiSearchService interface {
+	MultiSearch(ctx context.Context, query string, limit int) ([]models.MultiSearchResult, error)
+}
+
+// MultiSearch returns movies, series and people matching the query as one
+// ranked list, so clients don't issue a search per media type. Each result
+// carries its type and either a title or a person.
+func (h *MetadataHandler) MultiSearch(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if strings.TrimSpace(q) == "" {
+		q = r.URL.Query().Get("query")
+	}
+	userID := strings.TrimSpace(r.URL.Query().Get("userId"))
+	limit := 0
+	if parsed, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && parsed > 0 {
+		limit = parsed
+	}
+	service := h.serviceForRequest(r, userID)
+	svc, ok := service.(multiSearchService)
+	if !ok {
+		writeJSONError(w, "multi-search not supported", http.StatusNotImplemented)
+		return
+	}
+
+	results := []models.MultiSearchResult{}
+	if userID != "" && h.UsersService != nil {
+		if user, ok := h.UsersService.Get(userID); ok && user.IsKidsProfile && user.KidsMode == "content_list" {
+			// Search is disabled for curated-list profiles
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(results)
+			return
+		}
+	}
+
+	var err error
+	if allow, ok := h.profileAllowList(r, userID); ok {
+		// Allow-list profiles only see titles the service matches to the
+		// list, and no people.
+		if alSvc, ok := service.(allowListService); ok {
+			var titles []models.SearchResult
+			if titles, err = alSvc.SearchWithAllowList(r.Context(), q, "", allow); err == nil {
+				results = titleResultsToMulti(titles)
+			}
+		}
+	} else {
+		results, err = svc.MultiSearch(r.Context(), q, limit)
+	}
+	if err != nil {
+		writeServiceError(w, err, http.StatusBadGateway)
+		return
+	}
+
+	// Apply the profile's rating limits to the titles; people are kept.
+	if movieRating, tvRating, ok := h.ratingLimits(r, userID); ok {
+		results = filterMultiSearchByRatings(r.Context(), service, results, movieRating, tvRating)
+	}
+	if results == nil {
+		results = []models.MultiSearchResult{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.proxyArtwork(r, h.displayTitles(r, userID, results)))
+}
+
+func titleResultsToMulti(results []models.SearchResult) []models.MultiSearchResult {
+	out := make([]models.MultiSearchResult, 0, len(results))
+	for _, result := range results {
+		title := result.Title
+		kind := models.MultiSearchTypeSeries
+		if title.MediaType == "movie" {
+			kind = models.MultiSearchTypeMovie
+		}
+		out = append(out, models.MultiSearchResult{Type: kind, Title: &title, Score: result.Score})
+	}
+	return out
+}
+
+// filterMultiSearchByRatings drops titles above the rating limits, keeping
+// the order of what remains.
+func filterMultiSearchByRatings(ctx context.Context, service metadataService, results []models.MultiSearchResult, movieRating, tvRating string) []models.MultiSearchResult {
+	titles := make([]models.SearchResult, 0, len(results))
+	for _, result := range results {
+		if result.Title != nil {
+			titles = append(titles, models.SearchResult{Title: *result.Title, Score: result.Score})
+		}
+	}
+	service.EnrichSearchCertifications(ctx, titles)
+	allowed := make(map[string]models.Title)
+	for _, result := range kids.FilterSearchByRatings(titles, movieRating, tvRating) {
+		allowed[result.Title.MediaType+":"+result.Title.ID] = result.Title
+	}
+
+	filtered := make([]models.MultiSearchResult, 0, len(results))
+	for _, result := range results {
+		if result.Title != nil {
+			title, ok := allowed[result.Title.MediaType+":"+result.Title.ID]
+			if !ok {
+				continue
+			}
+			result.Title = &title
+		}
+		filtered = append(filtered, result)
+	}
+	return filtered
+}
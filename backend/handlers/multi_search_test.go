@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"novastream/models"
+)
+
+type fakeMultiSearchService struct {
+	*fakeMetadataService
+	lastLimit int
+}
+
+func (f *fakeMultiSearchService) MultiSearch(_ context.Context, _ string, limit int) ([]models.MultiSearchResult, error) {
+	f.lastLimit = limit
+	return []models.MultiSearchResult{
+		{Type: models.MultiSearchTypeMovie, Title: &models.Title{ID: "tmdb:movie:603", Name: "The Matrix", MediaType: "movie"}, Score: 90},
+		{Type: models.MultiSearchTypePerson, Person: &models.Person{ID: 6384, Name: "Keanu Reeves"}, Score: 70},
+	}, nil
+}
+
+func TestMetadataHandler_MultiSearch(t *testing.T) {
+	fake := &fakeMultiSearchService{fakeMetadataService: &fakeMetadataService{}}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+
+	rec := httptest.NewRecorder()
+	handler.MultiSearch(rec, httptest.NewRequest(http.MethodGet, "/api/search/multi?q=matrix&limit=5", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var results []models.MultiSearchResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(results) != 2 || results[0].Title == nil || results[1].Person == nil || results[1].Person.Name != "Keanu Reeves" {
+		t.Fatalf("unexpected results %+v", results)
+	}
+	if fake.lastLimit != 5 {
+		t.Fatalf("limit = %d, want 5", fake.lastLimit)
+	}
+
+	rec = httptest.NewRecorder()
+	NewMetadataHandler(&fakeMetadataService{}, testConfigManager(t)).MultiSearch(rec, httptest.NewRequest(http.MethodGet, "/api/search/multi?q=matrix", nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("expected 501 without multi-search support, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"novastream/config"
+	"novastream/services/kids"
+)
+
+// parentalPINHeader carries the admin parental-controls PIN. When it matches
+// the configured override PIN the profile's rating ceiling is not applied to
+// that request.
+const parentalPINHeader = "X-Parental-PIN"
+
+// parentalOverride reports whether the request carries the configured
+// parental-controls override PIN.
+func parentalOverride(r *http.Request, cfgManager *config.Manager) bool {
+	if r == nil || cfgManager == nil {
+		return false
+	}
+	pin := strings.TrimSpace(r.Header.Get(parentalPINHeader))
+	if pin == "" {
+		return false
+	}
+	settings, err := cfgManager.Load()
+	if err != nil {
+		return false
+	}
+	want := strings.TrimSpace(settings.ParentalControls.OverridePIN)
+	return want != "" && subtle.ConstantTimeCompare([]byte(pin), []byte(want)) == 1
+}
+
+// profileRatingLimits resolves the (movie, tv) maximum ratings enforced for a
+// profile on this request. ok is false when the profile is unrestricted or
+// the request carries the override PIN.
+func profileRatingLimits(r *http.Request, users usersServiceInterface, cfgManager *config.Manager, userID string) (movieRating, tvRating string, ok bool) {
+	if userID == "" || users == nil {
+		return "", "", false
+	}
+	user, found := users.Get(userID)
+	if !found {
+		return "", "", false
+	}
+	movieRating, tvRating, ok = kids.ProfileRatingLimits(user)
+	if !ok || parentalOverride(r, cfgManager) {
+		return "", "", false
+	}
+	return movieRating, tvRating, true
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"novastream/models"
+)
+
+func TestSearchEnforcesProfileRatingCeiling(t *testing.T) {
+	fake := &fakeMetadataService{
+		searchResp: []models.SearchResult{
+			{Title: models.Title{Name: "Paddington", MediaType: "movie", Certification: "PG"}},
+			{Title: models.Title{Name: "Heat", MediaType: "movie", Certification: "R"}},
+			{Title: models.Title{Name: "Bluey", MediaType: "series", Certification: "TV-Y"}},
+			{Title: models.Title{Name: "The Sopranos", MediaType: "series", Certification: "TV-MA"}},
+		},
+	}
+	mgr := testConfigManager(t)
+	settings, err := mgr.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.ParentalControls.OverridePIN = "2468"
+	if err := mgr.Save(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := NewMetadataHandler(fake, mgr)
+	handler.SetUsersService(&fakeUsersServiceForSearch{
+		users: map[string]models.User{
+			"teen":  {ID: "teen", MaxMovieRating: "PG-13", MaxTVRating: "TV-14"},
+			"adult": {ID: "adult"},
+		},
+	})
+
+	search := func(userID, pin string) []string {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/search?q=x&userId="+userID, nil)
+		if pin != "" {
+			req.Header.Set(parentalPINHeader, pin)
+		}
+		rec := httptest.NewRecorder()
+		handler.Search(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+		}
+		var results []models.SearchResult
+		if err := json.Unmarshal(rec.Body.Bytes(), &results); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		names := make([]string, len(results))
+		for i, result := range results {
+			names[i] = result.Title.Name
+		}
+		return names
+	}
+
+	if got := search("teen", ""); len(got) != 2 || got[0] != "Paddington" || got[1] != "Bluey" {
+		t.Fatalf("expected the ceiling to drop R and TV-MA titles, got %v", got)
+	}
+	if got := search("teen", "0000"); len(got) != 2 {
+		t.Fatalf("a wrong override PIN must not lift the ceiling, got %v", got)
+	}
+	if got := search("teen", "2468"); len(got) != 4 {
+		t.Fatalf("the override PIN should lift the ceiling, got %v", got)
+	}
+	if got := search("adult", ""); len(got) != 4 {
+		t.Fatalf("profiles without a ceiling should be unfiltered, got %v", got)
+	}
+}
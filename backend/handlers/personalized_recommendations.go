@@ -76,7 +76,7 @@ type personalizedTopTenResult struct {
 	Err   error
 }
 
-type personalizedKidsRatingFilter struct {
+type personalizedRatingFilter struct {
 	enabled        bool
 	maxMovieRating string
 	maxTVRating    string
@@ -122,8 +122,8 @@ func (h *MetadataHandler) GetPersonalizedRecommendations(w http.ResponseWriter,
 		return
 	}
 
-	kidsRatingFilter := h.personalizedKidsRatingFilter(userID)
-	resp := h.buildPersonalizedRecommendations(r.Context(), userID, history, progress, days, limitPerType, kidsRatingFilter)
+	ratingFilter := h.personalizedRatingLimits(r, userID)
+	resp := h.buildPersonalizedRecommendations(r.Context(), userID, history, progress, days, limitPerType, ratingFilter)
 	service := h.serviceForUser(userID)
 	if resp.Items == nil {
 		resp.Items = []models.TrendingItem{}
@@ -156,24 +156,10 @@ func (h *MetadataHandler) GetPersonalizedRecommendations(w http.ResponseWriter,
 	_ = json.NewEncoder(w).Encode(resp)
 }
 
-func (h *MetadataHandler) personalizedKidsRatingFilter(userID string) personalizedKidsRatingFilter {
-	if userID == "" || h.UsersService == nil {
-		return personalizedKidsRatingFilter{}
-	}
-
-	user, ok := h.UsersService.Get(userID)
-	if !ok || !user.IsKidsProfile || user.KidsMode != "rating" {
-		return personalizedKidsRatingFilter{}
-	}
-	movieRating := user.KidsMaxMovieRating
-	tvRating := user.KidsMaxTVRating
-	if movieRating == "" && tvRating == "" && user.KidsMaxRating != "" {
-		movieRating = user.KidsMaxRating
-		tvRating = user.KidsMaxRating
-	}
-
-	return personalizedKidsRatingFilter{
-		enabled:        strings.TrimSpace(movieRating) != "" || strings.TrimSpace(tvRating) != "",
+func (h *MetadataHandler) personalizedRatingLimits(r *http.Request, userID string) personalizedRatingFilter {
+	movieRating, tvRating, ok := h.ratingLimits(r, userID)
+	return personalizedRatingFilter{
+		enabled:        ok,
 		maxMovieRating: movieRating,
 		maxTVRating:    tvRating,
 	}
@@ -256,7 +242,7 @@ func (h *MetadataHandler) buildPersonalizedRecommendations(
 	progress []models.PlaybackProgress,
 	days int,
 	limitPerType int,
-	kidsRatingFilter personalizedKidsRatingFilter,
+	ratingFilter personalizedRatingFilter,
 ) PersonalizedRecommendationsResponse {
 	service := h.serviceForUser(userID)
 	now := time.Now().UTC()
@@ -301,7 +287,7 @@ func (h *MetadataHandler) buildPersonalizedRecommendations(
 			return
 		}
 		title.MediaType = mediaType
-		if kidsRatingFilter.enabled && !isPersonalizedRatingAllowed(title, kidsRatingFilter) {
+		if ratingFilter.enabled && !isPersonalizedRatingAllowed(title, ratingFilter) {
 			return
 		}
 		keys := titleExclusionKeys(title)
@@ -417,7 +403,7 @@ func (h *MetadataHandler) buildPersonalizedRecommendations(
 	}
 }
 
-func isPersonalizedRatingAllowed(title models.Title, filter personalizedKidsRatingFilter) bool {
+func isPersonalizedRatingAllowed(title models.Title, filter personalizedRatingFilter) bool {
 	maxRating := filter.maxTVRating
 	if strings.EqualFold(title.MediaType, "movie") {
 		maxRating = filter.maxMovieRating
@@ -425,7 +411,7 @@ func isPersonalizedRatingAllowed(title models.Title, filter personalizedKidsRati
 	if strings.TrimSpace(maxRating) == "" {
 		return true
 	}
-	return kids.IsRatingAllowed(title.Certification, maxRating, title.MediaType)
+	return kids.IsTitleRatingAllowed(title, maxRating)
 }
 
 func buildPersonalizedSeeds(userID string, history []models.WatchHistoryItem, progress []models.PlaybackProgress, cutoff, now time.Time, limit int) []personalizedSeed {
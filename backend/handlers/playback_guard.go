@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"novastream/config"
+	"novastream/internal/auth"
+)
+
+// maxGuardedBodyBytes bounds how much of a request body the playback guard
+// reads to find the profile; playback request bodies are small JSON objects.
+// Anything past the limit is still passed on to the handler.
+const maxGuardedBodyBytes = 1 << 20
+
+// PlaybackGuard refuses playback for profiles past a hard weekly watch time
+// limit. It wraps every route that starts a stream and is consulted for each
+// HLS segment, so a session that crosses the limit mid-play is cut off too.
+// Requests carrying the parental override PIN are always allowed.
+type PlaybackGuard struct {
+	check           func(userID string) error
+	accountProfiles func(accountID string) []string
+	configManager   *config.Manager
+}
+
+// NewPlaybackGuard creates a guard that asks check whether a profile may
+// play. check returns an error while the profile is blocked. accountProfiles
+// lists the profiles of an account, to judge requests that name no profile.
+func NewPlaybackGuard(check func(userID string) error, accountProfiles func(accountID string) []string, cfgManager *config.Manager) *PlaybackGuard {
+	return &PlaybackGuard{check: check, accountProfiles: accountProfiles, configManager: cfgManager}
+}
+
+// overridden reports whether the request carries the parental override PIN.
+func (g *PlaybackGuard) overridden(r *http.Request) bool {
+	return g != nil && parentalOverride(r, g.configManager)
+}
+
+// blocked returns the reason the profile may not play, or nil.
+func (g *PlaybackGuard) blocked(userID string) error {
+	userID = strings.TrimSpace(userID)
+	if g == nil || g.check == nil || userID == "" {
+		return nil
+	}
+	return g.check(userID)
+}
+
+// requestBlocked returns the reason the request may not play, or nil. A
+// request that names no profile is refused while any profile of the signed-in
+// account is blocked, so leaving the profile out does not get around a limit.
+func (g *PlaybackGuard) requestBlocked(r *http.Request) error {
+	if userID := playbackProfileID(r); userID != "" {
+		return g.blocked(userID)
+	}
+	accountID := auth.GetAccountID(r)
+	if accountID == "" || g.accountProfiles == nil {
+		return nil
+	}
+	for _, userID := range g.accountProfiles(accountID) {
+		if err := g.blocked(userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Wrap refuses the request with 403 when the profile it plays for is
+// blocked. The profile is read from the profileId or userId query parameter,
+// or from the same fields of a JSON body, which is restored for next.
+func (g *PlaybackGuard) Wrap(next http.HandlerFunc) http.HandlerFunc {
+	if g == nil {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions || g.overridden(r) {
+			next(w, r)
+			return
+		}
+		if err := g.requestBlocked(r); err != nil {
+			log.Printf("[watch-time] refusing %s: %v", r.URL.Path, err)
+			writeJSONError(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// playbackProfileID finds the profile a playback request is made for.
+func playbackProfileID(r *http.Request) string {
+	query := r.URL.Query()
+	for _, key := range []string{"profileId", "userId"} {
+		if id := strings.TrimSpace(query.Get(key)); id != "" {
+			return id
+		}
+	}
+	if r.Method != http.MethodPost || r.Body == nil || r.Body == http.NoBody {
+		return ""
+	}
+
+	original := r.Body
+	body, err := io.ReadAll(io.LimitReader(original, maxGuardedBodyBytes))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), original), original}
+	if err != nil {
+		return ""
+	}
+	var fields struct {
+		ProfileID string `json:"profileId"`
+		UserID    string `json:"userId"`
+	}
+	if json.Unmarshal(body, &fields) != nil {
+		return ""
+	}
+	if id := strings.TrimSpace(fields.ProfileID); id != "" {
+		return id
+	}
+	return strings.TrimSpace(fields.UserID)
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"novastream/internal/auth"
+
+	"github.com/gorilla/mux"
+)
+
+var errTestLimitReached = errors.New("weekly watch time limit reached")
+
+func newTestPlaybackGuard(t *testing.T) *PlaybackGuard {
+	t.Helper()
+	mgr := testConfigManager(t)
+	settings, err := mgr.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings.ParentalControls.OverridePIN = "2468"
+	if err := mgr.Save(settings); err != nil {
+		t.Fatal(err)
+	}
+	return NewPlaybackGuard(func(userID string) error {
+		if userID == "kids" {
+			return errTestLimitReached
+		}
+		return nil
+	}, func(accountID string) []string {
+		if accountID == "family" {
+			return []string{"adult", "kids"}
+		}
+		return []string{"adult"}
+	}, mgr)
+}
+
+func TestPlaybackGuardWrap(t *testing.T) {
+	guard := newTestPlaybackGuard(t)
+	var gotBody string
+	handler := guard.Wrap(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	serve := func(req *http.Request) int {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec.Code
+	}
+
+	if code := serve(httptest.NewRequest(http.MethodGet, "/api/video/stream?profileId=kids", nil)); code != http.StatusForbidden {
+		t.Fatalf("expected 403 for blocked profile in query, got %d", code)
+	}
+	if code := serve(httptest.NewRequest(http.MethodGet, "/api/live/stream?userId=adult", nil)); code != http.StatusOK {
+		t.Fatalf("expected 200 for unrestricted profile, got %d", code)
+	}
+
+	body := `{"result":{"title":"Bluey"},"profileId":"kids"}`
+	if code := serve(httptest.NewRequest(http.MethodPost, "/api/playback/resolve", strings.NewReader(body))); code != http.StatusForbidden {
+		t.Fatalf("expected 403 for blocked profile in body, got %d", code)
+	}
+	body = `{"titleName":"Bluey","userId":"adult"}`
+	if code := serve(httptest.NewRequest(http.MethodPost, "/api/playback/prequeue", strings.NewReader(body))); code != http.StatusOK || gotBody != body {
+		t.Fatalf("expected body to reach the handler intact, got %d %q", code, gotBody)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/video/hls/start?profileId=kids", nil)
+	req.Header.Set(parentalPINHeader, "2468")
+	if code := serve(req); code != http.StatusOK {
+		t.Fatalf("expected override PIN to bypass the limit, got %d", code)
+	}
+}
+
+func TestPlaybackGuardWrapWithoutProfile(t *testing.T) {
+	guard := newTestPlaybackGuard(t)
+	handler := guard.Wrap(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	serve := func(accountID string) int {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/video/stream?path=movie.mkv", nil)
+		req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyAccountID, accountID))
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec.Code
+	}
+
+	if code := serve("family"); code != http.StatusForbidden {
+		t.Fatalf("expected 403 without a profile while one of the account's profiles is blocked, got %d", code)
+	}
+	if code := serve("solo"); code != http.StatusOK {
+		t.Fatalf("expected 200 without a profile for an unrestricted account, got %d", code)
+	}
+}
+
+func TestServeHLSSegmentEndsBlockedSession(t *testing.T) {
+	manager := NewHLSManager(t.TempDir(), "", "", nil)
+	manager.sessions["kids-session"] = &HLSSession{ID: "kids-session", ProfileID: "kids", OutputDir: t.TempDir()}
+	manager.sessions["override-session"] = &HLSSession{ID: "override-session", ProfileID: "kids", OutputDir: t.TempDir(), watchTimeExempt: true}
+	handler := &VideoHandler{hlsManager: manager}
+	handler.SetPlaybackGuard(newTestPlaybackGuard(t))
+
+	serve := func(sessionID string) int {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/video/hls/"+sessionID+"/segment0.ts", nil)
+		req = mux.SetURLVars(req, map[string]string{"sessionID": sessionID, "segment": "segment0.ts"})
+		rec := httptest.NewRecorder()
+		handler.ServeHLSSegment(rec, req)
+		return rec.Code
+	}
+
+	if code := serve("kids-session"); code != http.StatusForbidden {
+		t.Fatalf("expected 403 for blocked session, got %d", code)
+	}
+	if _, ok := manager.GetSession("kids-session"); ok {
+		t.Fatal("expected blocked session to be ended")
+	}
+	if code := serve("override-session"); code == http.StatusForbidden {
+		t.Fatal("expected session started with the override PIN to keep playing")
+	}
+}
@@ -0,0 +1,502 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// maxNegotiationCandidates bounds how many sources one request may probe.
+	maxNegotiationCandidates = 10
+
+	// playbackTargetH264 asks an HLS session to re-encode video to 8-bit
+	// H.264, for clients that cannot decode the source codec or HDR.
+	playbackTargetH264 = "h264"
+)
+
+// Playback methods, cheapest first.
+const (
+	PlaybackMethodDirect    = "direct"    // client plays the file as is
+	PlaybackMethodRemux     = "remux"     // streams copied into HLS
+	PlaybackMethodTranscode = "transcode" // audio and/or video re-encoded into HLS
+	// PlaybackMethodUnsupported means the source needs a conversion the
+	// server cannot do, such as tone mapping HDR for an SDR-only client.
+	PlaybackMethodUnsupported = "unsupported"
+)
+
+// ClientCapabilities is what a client declares it can decode. Empty lists
+// mean "no restriction", so a client only needs to declare what it knows
+// to be limited. Names follow ffprobe (h264, hevc, eac3, ...); common
+// aliases such as avc, h265 and ec-3 are accepted.
+type ClientCapabilities struct {
+	Containers       []string `json:"containers,omitempty"` // mp4, mkv, webm, ts, avi, hls
+	VideoCodecs      []string `json:"videoCodecs,omitempty"`
+	AudioCodecs      []string `json:"audioCodecs,omitempty"`
+	MaxHeight        int      `json:"maxHeight,omitempty"`
+	MaxAudioChannels int      `json:"maxAudioChannels,omitempty"`
+	// HDR formats the display can show: hdr10, hlg, dolbyvision. Unlike the
+	// lists above, an empty list means SDR only.
+	HDR []string `json:"hdr,omitempty"`
+	// Dolby Vision profiles the client decodes; empty accepts any profile
+	// when "dolbyvision" is listed in HDR.
+	DolbyVisionProfiles []int `json:"dolbyVisionProfiles,omitempty"`
+	// DRM systems the client supports. Sources served by this backend are
+	// never encrypted, so it does not affect the decision today.
+	DRM []string `json:"drm,omitempty"`
+
+	// forceTranscode is set from the device's stored playback overrides.
+	forceTranscode bool
+	// toneMapping is set when the server's ffmpeg can tone map HDR video to
+	// SDR while transcoding.
+	toneMapping bool
+}
+
+// NegotiationCandidate is one source the client could play.
+type NegotiationCandidate struct {
+	Path  string `json:"path"`
+	Label string `json:"label,omitempty"`
+}
+
+// PlaybackNegotiationRequest is the body of POST /video/negotiate.
+// Candidates are listed in the client's order of preference.
+type PlaybackNegotiationRequest struct {
+	Candidates   []NegotiationCandidate `json:"candidates"`
+	Path         string                 `json:"path,omitempty"` // shorthand for a single candidate
+	Capabilities ClientCapabilities     `json:"capabilities"`
+	AudioLang    string                 `json:"audioLang,omitempty"`
+	// ClientID identifies the device so its stored playback overrides are
+	// applied; defaults to the clientId parameter or X-Client-ID header.
+	ClientID string `json:"clientId,omitempty"`
+}
+
+// PlaybackDecision describes how a probed source should be delivered to a
+// client. For HLS delivery, HLSParams are the query parameters to pass to
+// /video/hls/start alongside path.
+type PlaybackDecision struct {
+	Method         string            `json:"method"`
+	Delivery       string            `json:"delivery"` // "direct" or "hls"
+	TranscodeVideo bool              `json:"transcodeVideo"`
+	TranscodeAudio bool              `json:"transcodeAudio"`
+	AudioIndex     int               `json:"audioIndex"`
+	Container      string            `json:"container,omitempty"`
+	VideoCodec     string            `json:"videoCodec,omitempty"`
+	AudioCodec     string            `json:"audioCodec,omitempty"`
+	HDRFormat      string            `json:"hdrFormat,omitempty"` // HDR format the client will receive
+	Reasons        []string          `json:"reasons,omitempty"`
+	HLSParams      map[string]string `json:"hlsParams,omitempty"`
+	cost           int
+}
+
+// NegotiationCandidateResult is the outcome of probing one candidate.
+type NegotiationCandidateResult struct {
+	Path     string            `json:"path"`
+	Label    string            `json:"label,omitempty"`
+	Probed   bool              `json:"probed"`
+	Error    string            `json:"error,omitempty"`
+	Decision *PlaybackDecision `json:"decision,omitempty"`
+}
+
+// PlaybackNegotiationResponse reports the chosen candidate and how every
+// candidate was judged. Selected is -1 when no candidate could be probed or
+// none can be delivered.
+type PlaybackNegotiationResponse struct {
+	Selected   int                          `json:"selected"`
+	Path       string                       `json:"path,omitempty"`
+	Decision   *PlaybackDecision            `json:"decision,omitempty"`
+	Candidates []NegotiationCandidateResult `json:"candidates"`
+}
+
+// NegotiatePlayback probes the candidate sources and picks the one the
+// client can play with the least work, saying whether to play it directly,
+// remux it or transcode it. It replaces client-side guesses about codec
+// support, which fail silently on older TVs.
+func (h *VideoHandler) NegotiatePlayback(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodOptions {
+		h.HandleOptions(w, r)
+		return
+	}
+
+	var req PlaybackNegotiationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	candidates := req.Candidates
+	if len(candidates) == 0 && strings.TrimSpace(req.Path) != "" {
+		candidates = []NegotiationCandidate{{Path: req.Path}}
+	}
+	if len(candidates) == 0 {
+		http.Error(w, "at least one candidate is required", http.StatusBadRequest)
+		return
+	}
+	if len(candidates) > maxNegotiationCandidates {
+		http.Error(w, fmt.Sprintf("at most %d candidates are allowed", maxNegotiationCandidates), http.StatusBadRequest)
+		return
+	}
+	if h.ffprobePath == "" {
+		http.Error(w, "ffprobe unavailable on server", http.StatusServiceUnavailable)
+		return
+	}
+
+	caps := normalizeClientCapabilities(req.Capabilities)
+	clientID := strings.TrimSpace(req.ClientID)
+	if clientID == "" {
+		clientID = requestClientID(r)
+	}
+	caps = h.devicePlaybackOverrides(clientID).applyToCapabilities(caps)
+	caps.toneMapping = h.hlsManager != nil && h.hlsManager.canToneMapToSDR()
+	results := make([]NegotiationCandidateResult, len(candidates))
+	var wg sync.WaitGroup
+	for i, candidate := range candidates {
+		results[i] = NegotiationCandidateResult{Path: candidate.Path, Label: candidate.Label}
+		wg.Add(1)
+		go func(result *NegotiationCandidateResult) {
+			defer wg.Done()
+			meta, err := h.probeNegotiationCandidate(r.Context(), result.Path)
+			if err != nil {
+				result.Error = err.Error()
+				return
+			}
+			result.Probed = true
+			decision := decidePlayback(meta, caps, req.AudioLang)
+			result.Decision = &decision
+		}(&results[i])
+	}
+	wg.Wait()
+
+	resp := PlaybackNegotiationResponse{Selected: selectNegotiatedCandidate(results), Candidates: results}
+	if resp.Selected >= 0 {
+		resp.Path = results[resp.Selected].Path
+		resp.Decision = results[resp.Selected].Decision
+		log.Printf("[video] negotiated playback path=%q method=%s reasons=%v", resp.Path, resp.Decision.Method, resp.Decision.Reasons)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// probeNegotiationCandidate returns the source's stream metadata, sharing the
+// metadata cache with ProbeVideo.
+func (h *VideoHandler) probeNegotiationCandidate(ctx context.Context, rawPath string) (*videoMetadataResponse, error) {
+	cleanPath := strings.TrimSpace(rawPath)
+	if cleanPath == "" {
+		return nil, errors.New("missing path")
+	}
+	if strings.HasPrefix(cleanPath, "/webdav/") {
+		cleanPath = strings.TrimPrefix(cleanPath, "/webdav")
+	} else if strings.HasPrefix(cleanPath, "webdav/") {
+		cleanPath = "/" + strings.TrimPrefix(cleanPath, "webdav/")
+	}
+
+	response := h.getCachedMetadata(cleanPath)
+	if response == nil {
+		meta, err := h.runFFProbeFromProvider(ctx, cleanPath)
+		if err != nil {
+			return nil, err
+		}
+		probed := composeMetadataResponse(meta, cleanPath, determineAudioPlan(meta, false))
+		h.setCachedMetadata(cleanPath, &probed)
+		response = &probed
+	}
+	if len(response.VideoStreams) == 0 {
+		return nil, errors.New("no video stream detected")
+	}
+	return response, nil
+}
+
+// selectNegotiatedCandidate returns the probed candidate needing the least
+// work, preferring earlier candidates on ties, or -1 if none can be played.
+func selectNegotiatedCandidate(results []NegotiationCandidateResult) int {
+	selected := -1
+	for i, result := range results {
+		if result.Decision == nil || result.Decision.Method == PlaybackMethodUnsupported {
+			continue
+		}
+		if selected < 0 || result.Decision.cost < results[selected].Decision.cost {
+			selected = i
+		}
+	}
+	return selected
+}
+
+var (
+	videoCodecAliases = map[string]string{
+		"avc": "h264", "avc1": "h264", "x264": "h264",
+		"h265": "hevc", "hvc1": "hevc", "hev1": "hevc", "x265": "hevc",
+		"mpeg2": "mpeg2video", "xvid": "mpeg4", "divx": "mpeg4",
+		"vc-1": "vc1", "wmv3": "vc1",
+	}
+	audioCodecAliases = map[string]string{
+		"ac-3": "ac3", "ec-3": "eac3", "e-ac-3": "eac3", "ddp": "eac3",
+		"dts-hd": "dts", "dca": "dts", "mlp": "truehd",
+		"mp4a": "aac", "mpeg3": "mp3",
+	}
+	containerAliases = map[string]string{
+		"matroska": "mkv", "m4v": "mp4", "mov": "mp4",
+		"mpegts": "ts", "m2ts": "ts", "mts": "ts", "m3u8": "hls",
+	}
+)
+
+func normalizeClientCapabilities(caps ClientCapabilities) ClientCapabilities {
+	normalize := func(values []string, aliases map[string]string) []string {
+		out := make([]string, 0, len(values))
+		for _, v := range values {
+			v = strings.ToLower(strings.TrimSpace(v))
+			if alias, ok := aliases[v]; ok {
+				v = alias
+			}
+			if v != "" && !slices.Contains(out, v) {
+				out = append(out, v)
+			}
+		}
+		return out
+	}
+	caps.Containers = normalize(caps.Containers, containerAliases)
+	caps.VideoCodecs = normalize(caps.VideoCodecs, videoCodecAliases)
+	caps.AudioCodecs = normalize(caps.AudioCodecs, audioCodecAliases)
+	caps.HDR = normalize(caps.HDR, map[string]string{"hdr10+": "hdr10", "dv": "dolbyvision", "dolby vision": "dolbyvision"})
+	return caps
+}
+
+// supports reports whether value is allowed by a capability list, where an
+// empty list allows everything.
+func supports(list []string, value string) bool {
+	return len(list) == 0 || slices.Contains(list, value)
+}
+
+// sourceContainer maps ffprobe's format_name to the container names clients
+// declare.
+func sourceContainer(formatName string) string {
+	formats := strings.Split(strings.ToLower(formatName), ",")
+	switch {
+	case slices.Contains(formats, "matroska"):
+		return "mkv"
+	case slices.Contains(formats, "mp4"), slices.Contains(formats, "mov"):
+		return "mp4"
+	case slices.Contains(formats, "mpegts"):
+		return "ts"
+	case slices.Contains(formats, "hls"):
+		return "hls"
+	case len(formats) > 0:
+		return formats[0]
+	}
+	return ""
+}
+
+// containerSupported accepts Matroska sources as WebM when the client lists
+// webm and the streams are WebM-legal.
+func containerSupported(caps ClientCapabilities, container, videoCodec, audioCodec string) bool {
+	if supports(caps.Containers, container) {
+		return true
+	}
+	if container == "mkv" && slices.Contains(caps.Containers, "webm") {
+		webmVideo := videoCodec == "vp8" || videoCodec == "vp9" || videoCodec == "av1"
+		webmAudio := audioCodec == "" || audioCodec == "opus" || audioCodec == "vorbis"
+		return webmVideo && webmAudio
+	}
+	return false
+}
+
+func normalizedCodec(codec string, aliases map[string]string) string {
+	codec = strings.ToLower(strings.TrimSpace(codec))
+	if alias, ok := aliases[codec]; ok {
+		return alias
+	}
+	return codec
+}
+
+// decidePlayback works out the cheapest way to deliver a probed source to a
+// client with the given capabilities.
+func decidePlayback(meta *videoMetadataResponse, caps ClientCapabilities, audioLang string) PlaybackDecision {
+	video := meta.VideoStreams[0]
+	videoCodec := normalizedCodec(video.CodecName, videoCodecAliases)
+	decision := PlaybackDecision{
+		Container:  sourceContainer(meta.FormatName),
+		VideoCodec: videoCodec,
+		AudioIndex: -1,
+	}
+	params := map[string]string{}
+	stripDolbyVision := false
+
+	if !supports(caps.VideoCodecs, videoCodec) {
+		decision.TranscodeVideo = true
+		decision.Reasons = append(decision.Reasons, fmt.Sprintf("video codec %s not supported", videoCodec))
+	}
+	if caps.forceTranscode {
+		decision.TranscodeVideo = true
+		decision.Reasons = append(decision.Reasons, "device is set to always transcode")
+	}
+	if caps.MaxHeight > 0 && video.Height > caps.MaxHeight {
+		decision.TranscodeVideo = true
+		params["maxHeight"] = strconv.Itoa(caps.MaxHeight)
+		decision.Reasons = append(decision.Reasons, fmt.Sprintf("%dp exceeds client maximum of %dp", video.Height, caps.MaxHeight))
+	}
+
+	switch hdr := strings.ToUpper(video.HdrFormat); {
+	case video.HasDolbyVision:
+		profile := parseDVProfileNumber(video.DolbyVisionProfile)
+		switch {
+		case slices.Contains(caps.HDR, "dolbyvision") && (len(caps.DolbyVisionProfiles) == 0 || slices.Contains(caps.DolbyVisionProfiles, profile)):
+			decision.HDRFormat = "dolbyvision"
+		case (profile == 7 || profile == 8) && slices.Contains(caps.HDR, "hdr10"):
+			// Profiles 7 and 8 carry an HDR10 base layer.
+			stripDolbyVision = true
+			decision.HDRFormat = "hdr10"
+			decision.Reasons = append(decision.Reasons, fmt.Sprintf("Dolby Vision profile %d not supported; using HDR10 base layer", profile))
+		default:
+			decision.TranscodeVideo = true
+			decision.Reasons = append(decision.Reasons, fmt.Sprintf("Dolby Vision profile %d not supported", profile))
+		}
+	case hdr == "HDR10" || hdr == "HLG":
+		if slices.Contains(caps.HDR, strings.ToLower(hdr)) {
+			decision.HDRFormat = strings.ToLower(hdr)
+		} else {
+			decision.TranscodeVideo = true
+			decision.Reasons = append(decision.Reasons, fmt.Sprintf("%s not supported", hdr))
+		}
+	}
+
+	audio, audioOK := selectNegotiatedAudio(meta.AudioStreams, caps, audioLang)
+	if audio != nil {
+		decision.AudioIndex = audio.Index
+		decision.AudioCodec = normalizedCodec(audio.CodecName, audioCodecAliases)
+		if !audioOK {
+			decision.TranscodeAudio = true
+			decision.Reasons = append(decision.Reasons, fmt.Sprintf("audio codec %s (%d channels) not supported", decision.AudioCodec, audio.Channels))
+		}
+	}
+
+	containerOK := containerSupported(caps, decision.Container, videoCodec, decision.AudioCodec)
+	if !containerOK {
+		decision.Reasons = append(decision.Reasons, fmt.Sprintf("container %s not supported", decision.Container))
+	}
+
+	if decision.TranscodeVideo && thumbnailNeedsToneMap(meta) {
+		// H.264 output is 8-bit SDR, so HDR has to be tone mapped on the way.
+		// Profile 5 Dolby Vision has no HDR10 base layer that zscale can map.
+		reason := ""
+		switch {
+		case !caps.toneMapping:
+			reason = "HDR video cannot be tone mapped to SDR on this server"
+		case video.HasDolbyVision && isDolbyVisionProfile5(video.DolbyVisionProfile):
+			reason = "Dolby Vision profile 5 cannot be tone mapped to SDR"
+		}
+		if reason != "" {
+			decision.Method = PlaybackMethodUnsupported
+			decision.Reasons = append(decision.Reasons, reason)
+			return decision
+		}
+	}
+
+	switch {
+	case decision.TranscodeVideo || decision.TranscodeAudio:
+		decision.Method = PlaybackMethodTranscode
+	case !containerOK || stripDolbyVision:
+		decision.Method = PlaybackMethodRemux
+	default:
+		decision.Method = PlaybackMethodDirect
+	}
+	decision.cost = playbackDecisionCost(decision)
+
+	if decision.Method == PlaybackMethodDirect {
+		decision.Delivery = "direct"
+		return decision
+	}
+	decision.Delivery = "hls"
+	if decision.AudioIndex >= 0 {
+		params["audioTrack"] = strconv.Itoa(decision.AudioIndex)
+	}
+	if decision.TranscodeAudio {
+		params["forceAAC"] = "true"
+	}
+	if decision.TranscodeVideo {
+		// H.264 output is 8-bit SDR; HDR sources are tone mapped.
+		params["target"] = playbackTargetH264
+		decision.VideoCodec = "h264"
+		decision.HDRFormat = ""
+	} else {
+		switch decision.HDRFormat {
+		case "dolbyvision":
+			params["dv"] = "true"
+			params["dvProfile"] = video.DolbyVisionProfile
+		case "hdr10", "hlg":
+			params["hdr"] = "true"
+		}
+	}
+	if decision.TranscodeAudio {
+		decision.AudioCodec = "aac"
+	}
+	decision.HLSParams = params
+	return decision
+}
+
+// selectNegotiatedAudio picks the audio stream to play: the first stream the
+// client can decode, preferring the requested language and then the default
+// track. When none is decodable it returns the preferred stream with ok=false
+// so it is transcoded.
+func selectNegotiatedAudio(streams []audioStreamSummary, caps ClientCapabilities, audioLang string) (*audioStreamSummary, bool) {
+	if len(streams) == 0 {
+		return nil, true
+	}
+	audioLang = strings.ToLower(strings.TrimSpace(audioLang))
+	rank := func(s audioStreamSummary) int {
+		score := 0
+		if audioLang != "" && strings.HasPrefix(strings.ToLower(s.Language), audioLang) {
+			score += 4
+		}
+		if IsCommentaryTrack(s.Title) {
+			score -= 8
+		}
+		if s.Disposition["default"] > 0 {
+			score++
+		}
+		return score
+	}
+	decodable := func(s audioStreamSummary) bool {
+		if caps.MaxAudioChannels > 0 && s.Channels > caps.MaxAudioChannels {
+			return false
+		}
+		return supports(caps.AudioCodecs, normalizedCodec(s.CodecName, audioCodecAliases))
+	}
+
+	var best, bestDecodable *audioStreamSummary
+	for i := range streams {
+		s := &streams[i]
+		if best == nil || rank(*s) > rank(*best) {
+			best = s
+		}
+		if decodable(*s) && (bestDecodable == nil || rank(*s) > rank(*bestDecodable)) {
+			bestDecodable = s
+		}
+	}
+	// A decodable track in another language is no substitute for the
+	// requested one.
+	if bestDecodable != nil && rank(*bestDecodable) >= rank(*best)-1 {
+		return bestDecodable, true
+	}
+	return best, false
+}
+
+// playbackDecisionCost ranks decisions by server and quality cost: remuxing
+// is cheap, audio transcoding moderate and video transcoding expensive.
+func playbackDecisionCost(d PlaybackDecision) int {
+	cost := 0
+	if d.Method != PlaybackMethodDirect {
+		cost++
+	}
+	if d.TranscodeAudio {
+		cost += 2
+	}
+	if d.TranscodeVideo {
+		cost += 4
+	}
+	return cost
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func negotiationMeta(format string, video videoStreamSummary, audio ...audioStreamSummary) *videoMetadataResponse {
+	return &videoMetadataResponse{
+		FormatName:   format,
+		VideoStreams: []videoStreamSummary{video},
+		AudioStreams: audio,
+	}
+}
+
+func TestDecidePlayback(t *testing.T) {
+	legacyTV := normalizeClientCapabilities(ClientCapabilities{
+		Containers:       []string{"mp4", "ts", "hls"},
+		VideoCodecs:      []string{"avc"},
+		AudioCodecs:      []string{"aac", "ac-3"},
+		MaxHeight:        1080,
+		MaxAudioChannels: 6,
+	})
+	legacyTV.toneMapping = true
+	noToneMapping := legacyTV
+	noToneMapping.toneMapping = false
+	dvTV := normalizeClientCapabilities(ClientCapabilities{
+		VideoCodecs:         []string{"h264", "hevc"},
+		HDR:                 []string{"hdr10", "dolbyvision"},
+		DolbyVisionProfiles: []int{5},
+	})
+
+	tests := []struct {
+		name           string
+		meta           *videoMetadataResponse
+		caps           ClientCapabilities
+		wantMethod     string
+		transcodeVideo bool
+		transcodeAudio bool
+		wantParams     map[string]string
+	}{
+		{
+			name:       "compatible mp4 plays directly",
+			meta:       negotiationMeta("mov,mp4,m4a,3gp,3g2,mj2", videoStreamSummary{CodecName: "h264", Height: 1080}, audioStreamSummary{Index: 1, CodecName: "aac", Channels: 2}),
+			caps:       legacyTV,
+			wantMethod: PlaybackMethodDirect,
+		},
+		{
+			name:       "unsupported container is remuxed",
+			meta:       negotiationMeta("matroska,webm", videoStreamSummary{CodecName: "h264", Height: 720}, audioStreamSummary{Index: 1, CodecName: "ac3", Channels: 6}),
+			caps:       legacyTV,
+			wantMethod: PlaybackMethodRemux,
+			wantParams: map[string]string{"audioTrack": "1"},
+		},
+		{
+			name:           "4k hevc with truehd is transcoded and scaled",
+			meta:           negotiationMeta("matroska,webm", videoStreamSummary{CodecName: "hevc", Height: 2160, HdrFormat: "HDR10"}, audioStreamSummary{Index: 1, CodecName: "truehd", Channels: 8}),
+			caps:           legacyTV,
+			wantMethod:     PlaybackMethodTranscode,
+			transcodeVideo: true,
+			transcodeAudio: true,
+			wantParams:     map[string]string{"audioTrack": "1", "forceAAC": "true", "target": playbackTargetH264, "maxHeight": "1080"},
+		},
+		{
+			name:           "hdr10 is not offered as sdr without tone mapping",
+			meta:           negotiationMeta("matroska,webm", videoStreamSummary{CodecName: "hevc", Height: 1080, HdrFormat: "HDR10"}, audioStreamSummary{Index: 1, CodecName: "aac", Channels: 2}),
+			caps:           noToneMapping,
+			wantMethod:     PlaybackMethodUnsupported,
+			transcodeVideo: true,
+		},
+		{
+			name:           "dolby vision profile 5 cannot be tone mapped",
+			meta:           negotiationMeta("mov,mp4,m4a,3gp,3g2,mj2", videoStreamSummary{CodecName: "hevc", Height: 1080, HasDolbyVision: true, DolbyVisionProfile: "dvhe.05.06", HdrFormat: "DV"}),
+			caps:           legacyTV,
+			wantMethod:     PlaybackMethodUnsupported,
+			transcodeVideo: true,
+		},
+		{
+			name:       "dolby vision profile 8 falls back to hdr10",
+			meta:       negotiationMeta("matroska,webm", videoStreamSummary{CodecName: "hevc", HasDolbyVision: true, DolbyVisionProfile: "dvhe.08.06", HdrFormat: "DV"}),
+			caps:       dvTV,
+			wantMethod: PlaybackMethodRemux,
+			wantParams: map[string]string{"hdr": "true"},
+		},
+		{
+			name:       "supported dolby vision profile is kept",
+			meta:       negotiationMeta("matroska,webm", videoStreamSummary{CodecName: "hevc", HasDolbyVision: true, DolbyVisionProfile: "dvhe.05.06", HdrFormat: "DV"}),
+			caps:       dvTV,
+			wantMethod: PlaybackMethodDirect,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := decidePlayback(tc.meta, tc.caps, "")
+			if got.Method != tc.wantMethod || got.TranscodeVideo != tc.transcodeVideo || got.TranscodeAudio != tc.transcodeAudio {
+				t.Fatalf("decision = %+v, want method=%s video=%t audio=%t", got, tc.wantMethod, tc.transcodeVideo, tc.transcodeAudio)
+			}
+			for key, want := range tc.wantParams {
+				if got.HLSParams[key] != want {
+					t.Fatalf("hlsParams[%q] = %q, want %q (all: %v)", key, got.HLSParams[key], want, got.HLSParams)
+				}
+			}
+			if tc.wantMethod == PlaybackMethodDirect && (got.Delivery != "direct" || got.HLSParams != nil) {
+				t.Fatalf("direct decision should not carry HLS params: %+v", got)
+			}
+			if tc.wantMethod == PlaybackMethodUnsupported && (got.Delivery != "" || got.HLSParams != nil) {
+				t.Fatalf("unsupported decision should not be deliverable: %+v", got)
+			}
+		})
+	}
+}
+
+func TestSelectNegotiatedAudioPrefersDecodableTrackInRequestedLanguage(t *testing.T) {
+	caps := normalizeClientCapabilities(ClientCapabilities{AudioCodecs: []string{"aac", "eac3"}})
+	streams := []audioStreamSummary{
+		{Index: 1, CodecName: "truehd", Language: "eng", Disposition: map[string]int{"default": 1}},
+		{Index: 2, CodecName: "eac3", Language: "eng"},
+		{Index: 3, CodecName: "aac", Language: "fre"},
+		{Index: 4, CodecName: "aac", Language: "eng", Title: "Director's Commentary"},
+	}
+
+	got, ok := selectNegotiatedAudio(streams, caps, "en")
+	if got == nil || got.Index != 2 || !ok {
+		t.Fatalf("selected %+v ok=%t, want stream 2", got, ok)
+	}
+
+	// Only a foreign-language track is decodable: transcode the requested one.
+	got, ok = selectNegotiatedAudio([]audioStreamSummary{streams[0], streams[2]}, caps, "en")
+	if got == nil || got.Index != 1 || ok {
+		t.Fatalf("selected %+v ok=%t, want stream 1 transcoded", got, ok)
+	}
+}
+
+func TestNegotiatePlaybackPicksCheapestCandidate(t *testing.T) {
+	h := &VideoHandler{ffprobePath: "/nonexistent/ffprobe", metadataCache: make(map[string]*cachedMetadataEntry)}
+	h.setCachedMetadata("/streams/remux.mkv", negotiationMeta("matroska,webm",
+		videoStreamSummary{CodecName: "hevc", Height: 2160}, audioStreamSummary{Index: 1, CodecName: "dts", Channels: 6}))
+	h.setCachedMetadata("/streams/web-dl.mp4", negotiationMeta("mov,mp4,m4a,3gp,3g2,mj2",
+		videoStreamSummary{CodecName: "h264", Height: 1080}, audioStreamSummary{Index: 1, CodecName: "aac", Channels: 2}))
+
+	body := `{"candidates":[{"path":"/streams/remux.mkv"},{"path":"/webdav/streams/web-dl.mp4"},{"path":""}],
+		"capabilities":{"videoCodecs":["h264"],"audioCodecs":["aac"],"maxHeight":1080}}`
+	rec := httptest.NewRecorder()
+	h.NegotiatePlayback(rec, httptest.NewRequest(http.MethodPost, "/api/video/negotiate", strings.NewReader(body)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var resp PlaybackNegotiationResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Selected != 1 || resp.Decision == nil || resp.Decision.Method != PlaybackMethodDirect {
+		t.Fatalf("unexpected selection: %+v", resp)
+	}
+	if len(resp.Candidates) != 3 || resp.Candidates[0].Decision.Method != PlaybackMethodTranscode || resp.Candidates[2].Probed {
+		t.Fatalf("unexpected candidates: %+v", resp.Candidates)
+	}
+}
+
+func TestSelectNegotiatedCandidateSkipsUnsupported(t *testing.T) {
+	results := []NegotiationCandidateResult{
+		{Probed: true, Decision: &PlaybackDecision{Method: PlaybackMethodUnsupported}},
+		{Probed: true, Decision: &PlaybackDecision{Method: PlaybackMethodTranscode, cost: 5}},
+	}
+	if got := selectNegotiatedCandidate(results); got != 1 {
+		t.Fatalf("selected %d, want 1", got)
+	}
+	if got := selectNegotiatedCandidate(results[:1]); got != -1 {
+		t.Fatalf("selected %d, want -1 when nothing can be delivered", got)
+	}
+}
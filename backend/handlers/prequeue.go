@@ -67,6 +67,9 @@ type PrequeueHandler struct {
 	failures              *streamFailureRegistry
 	externalURLValidator  func(context.Context, string) error
 	demoMode              bool
+	instantPlay           *instantPlayState // Speculative resolutions started from details pages
+	spaceCheck            func() error      // Pauses prequeueing while the cache volume is low
+	playbackGuard         *PlaybackGuard    // Refuses playback past a kids profile's hard weekly limit
 }
 
 func hasTrackMetadata(entry *playback.PrequeueEntry) bool {
@@ -542,6 +545,7 @@ func NewPrequeueHandler(
 		hlsCreator:  hlsCreator,
 		failures:    defaultStreamFailureRegistry,
 		demoMode:    demoMode,
+		instantPlay: newInstantPlayState(),
 	}
 }
 
@@ -605,6 +609,25 @@ func (h *PrequeueHandler) SetPrewarmService(svc PrewarmService) {
 	h.prewarmSvc = svc
 }
 
+// SetDiskSpaceCheck sets a function that is consulted before starting a
+// prequeue; while it returns an error new prequeues are refused.
+func (h *PrequeueHandler) SetDiskSpaceCheck(fn func() error) {
+	h.spaceCheck = fn
+}
+
+// SetPlaybackGuard sets the watch time guard. The prequeue route is wrapped
+// by it; here it keeps blocked profiles from being pre-resolved on browse.
+func (h *PrequeueHandler) SetPlaybackGuard(guard *PlaybackGuard) {
+	h.playbackGuard = guard
+}
+
+func (h *PrequeueHandler) diskSpaceError() error {
+	if h.spaceCheck == nil {
+		return nil
+	}
+	return h.spaceCheck()
+}
+
 // GetStore returns the prequeue store for external access (e.g., prewarm service, admin viewer)
 func (h *PrequeueHandler) GetStore() *playback.PrequeueStore {
 	return h.store
@@ -619,6 +642,10 @@ func (h *PrequeueHandler) RunWorkerSync(ctx context.Context, titleID, titleName,
 
 // RunWorkerSyncScoped runs the prequeue worker synchronously for an explicit settings scope.
 func (h *PrequeueHandler) RunWorkerSyncScoped(ctx context.Context, titleID, titleName, imdbID, mediaType string, year int, userID, clientID, settingsScopeKey string, targetEpisode *models.EpisodeReference) (string, error) {
+	if err := h.diskSpaceError(); err != nil {
+		return "", fmt.Errorf("prequeue paused: %w", err)
+	}
+
 	// Create prequeue entry with a long TTL (inherits store TTL, prewarm service will extend)
 	entry, _ := h.store.CreateScoped(titleID, titleName, userID, mediaType, year, targetEpisode, "prewarm", settingsScopeKey)
 
@@ -673,6 +700,11 @@ func (h *PrequeueHandler) Prequeue(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := h.diskSpaceError(); err != nil {
+		http.Error(w, "prequeue paused: "+err.Error(), http.StatusInsufficientStorage)
+		return
+	}
+
 	// Get client ID from request body or header
 	clientID := strings.TrimSpace(req.ClientID)
 	if clientID == "" {
@@ -696,35 +728,8 @@ func (h *PrequeueHandler) Prequeue(w http.ResponseWriter, r *http.Request) {
 			} else {
 				log.Printf("[prequeue] Using explicit episode S%02dE%02d", req.SeasonNumber, req.EpisodeNumber)
 			}
-		} else if h.historySvc != nil {
-			// Try to get next episode from watch history
-			watchState, err := h.historySvc.GetSeriesWatchState(req.UserID, req.TitleID)
-			if err == nil && watchState != nil && watchState.NextEpisode != nil {
-				// Exclude season 0 (specials)
-				if watchState.NextEpisode.SeasonNumber > 0 {
-					targetEpisode = watchState.NextEpisode
-					log.Printf("[prequeue] Using next episode from watch history: S%02dE%02d",
-						targetEpisode.SeasonNumber, targetEpisode.EpisodeNumber)
-				} else {
-					log.Printf("[prequeue] Skipping season 0 episode from watch history")
-				}
-			}
-
-			// If no next episode, default to S01E01
-			if targetEpisode == nil {
-				targetEpisode = &models.EpisodeReference{
-					SeasonNumber:  1,
-					EpisodeNumber: 1,
-				}
-				log.Printf("[prequeue] Defaulting to S01E01 (no watch history)")
-			}
 		} else {
-			// No history service, default to S01E01
-			targetEpisode = &models.EpisodeReference{
-				SeasonNumber:  1,
-				EpisodeNumber: 1,
-			}
-			log.Printf("[prequeue] Defaulting to S01E01 (no history service)")
+			targetEpisode = h.nextUpEpisode(req.UserID, req.TitleID)
 		}
 	}
 
@@ -780,6 +785,7 @@ func (h *PrequeueHandler) Prequeue(w http.ResponseWriter, r *http.Request) {
 					h.store.Delete(existing.ID)
 				} else {
 					log.Printf("[prequeue] Reusing existing ready entry %s for title=%s user=%s scope=%s", existing.ID, req.TitleID, req.UserID, settingsScopeKey)
+					h.claimInstantPlayEntry(existing.ID, req.Reason)
 					resp := playback.PrequeueResponse{
 						PrequeueID:    existing.ID,
 						TargetEpisode: existing.TargetEpisode,
@@ -795,6 +801,7 @@ func (h *PrequeueHandler) Prequeue(w http.ResponseWriter, r *http.Request) {
 		} else if isPrequeueInProgress(existing.Status) {
 			log.Printf("[prequeue] Reusing existing in-progress entry %s status=%s for title=%s user=%s scope=%s",
 				existing.ID, existing.Status, req.TitleID, req.UserID, settingsScopeKey)
+			h.claimInstantPlayEntry(existing.ID, req.Reason)
 			resp := playback.PrequeueResponse{
 				PrequeueID:    existing.ID,
 				TargetEpisode: existing.TargetEpisode,
@@ -830,6 +837,29 @@ func (h *PrequeueHandler) Prequeue(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(resp)
 }
 
+// nextUpEpisode picks the episode a series prequeue should target: the next
+// episode from the profile's watch history, or S01E01 when there is none.
+func (h *PrequeueHandler) nextUpEpisode(userID, titleID string) *models.EpisodeReference {
+	if h.historySvc == nil {
+		log.Printf("[prequeue] Defaulting to S01E01 (no history service)")
+		return &models.EpisodeReference{SeasonNumber: 1, EpisodeNumber: 1}
+	}
+
+	watchState, err := h.historySvc.GetSeriesWatchState(userID, titleID)
+	if err == nil && watchState != nil && watchState.NextEpisode != nil {
+		// Exclude season 0 (specials)
+		if watchState.NextEpisode.SeasonNumber > 0 {
+			log.Printf("[prequeue] Using next episode from watch history: S%02dE%02d",
+				watchState.NextEpisode.SeasonNumber, watchState.NextEpisode.EpisodeNumber)
+			return watchState.NextEpisode
+		}
+		log.Printf("[prequeue] Skipping season 0 episode from watch history")
+	}
+
+	log.Printf("[prequeue] Defaulting to S01E01 (no watch history)")
+	return &models.EpisodeReference{SeasonNumber: 1, EpisodeNumber: 1}
+}
+
 // GetStatus returns the status of a prequeue request
 func (h *PrequeueHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
 	if r.Method == http.MethodOptions {
@@ -0,0 +1,209 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"novastream/internal/auth"
+	"novastream/models"
+	"novastream/services/recordings"
+
+	"github.com/gorilla/mux"
+)
+
+type recordingRuleService interface {
+	ListRules(userID string) ([]models.RecordingRule, error)
+	GetRule(id string) (*models.RecordingRule, error)
+	CreateRule(req models.RecordingRuleRequest) (models.RecordingRule, error)
+	UpdateRule(id string, req models.RecordingRuleRequest) (models.RecordingRule, error)
+	DeleteRule(id string) error
+	EvaluateRules(ctx context.Context) (recordings.RuleEvaluation, error)
+}
+
+// SetRuleService enables the recording rule endpoints.
+func (h *RecordingsHandler) SetRuleService(rules recordingRuleService) {
+	h.rules = rules
+}
+
+func (h *RecordingsHandler) ListRules(w http.ResponseWriter, r *http.Request) {
+	if h.rules == nil {
+		http.Error(w, "recording rules unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	userID := strings.TrimSpace(r.URL.Query().Get("userId"))
+	if !auth.IsMaster(r) {
+		profileID, ok := h.requireProfileOwnership(w, r, r.URL.Query().Get("profileId"))
+		if !ok {
+			return
+		}
+		userID = profileID
+	}
+	rules, err := h.rules.ListRules(userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if rules == nil {
+		rules = []models.RecordingRule{}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"rules": rules})
+}
+
+func (h *RecordingsHandler) CreateRule(w http.ResponseWriter, r *http.Request) {
+	if h.rules == nil {
+		http.Error(w, "recording rules unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	var req models.RecordingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if !auth.IsMaster(r) {
+		profileID, ok := h.requireProfileOwnership(w, r, req.ProfileID)
+		if !ok {
+			return
+		}
+		req.ProfileID = profileID
+	}
+	rule, err := h.rules.CreateRule(req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(rule)
+}
+
+func (h *RecordingsHandler) UpdateRule(w http.ResponseWriter, r *http.Request) {
+	rule, ok := h.requireRule(w, r)
+	if !ok {
+		return
+	}
+	var req models.RecordingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	updated, err := h.rules.UpdateRule(rule.ID, req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(updated)
+}
+
+func (h *RecordingsHandler) DeleteRule(w http.ResponseWriter, r *http.Request) {
+	rule, ok := h.requireRule(w, r)
+	if !ok {
+		return
+	}
+	if err := h.rules.DeleteRule(rule.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// EvaluateRules schedules matching airings now instead of waiting for the
+// next guide refresh.
+func (h *RecordingsHandler) EvaluateRules(w http.ResponseWriter, r *http.Request) {
+	if h.rules == nil {
+		http.Error(w, "recording rules unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	result, err := h.rules.EvaluateRules(r.Context())
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, recordings.ErrRulesUnavailable) {
+			status = http.StatusServiceUnavailable
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// requireRule loads the {ruleID} rule and checks the caller may manage it.
+func (h *RecordingsHandler) requireRule(w http.ResponseWriter, r *http.Request) (*models.RecordingRule, bool) {
+	if h.rules == nil {
+		http.Error(w, "recording rules unavailable", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	rule, err := h.rules.GetRule(strings.TrimSpace(mux.Vars(r)["ruleID"]))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, recordings.ErrRuleNotFound) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return nil, false
+	}
+	if !auth.IsMaster(r) {
+		if _, ok := h.requireProfileOwnership(w, r, rule.UserID); !ok {
+			return nil, false
+		}
+	}
+	return rule, true
+}
+
+// RecordingRuleGuide gives recording rules the guide schedule and each
+// profile's channels and EPG offset.
+type RecordingRuleGuide struct {
+	epg *EPGHandler
+}
+
+// NewRecordingRuleGuide builds the rule guide from an EPG handler whose live
+// handler has been set.
+func NewRecordingRuleGuide(epgHandler *EPGHandler) *RecordingRuleGuide {
+	return &RecordingRuleGuide{epg: epgHandler}
+}
+
+func (g *RecordingRuleGuide) Schedule() *models.EPGSchedule {
+	return g.epg.epgService.Schedule()
+}
+
+func (g *RecordingRuleGuide) ProfileChannels(ctx context.Context, profileID string) ([]recordings.RuleChannel, time.Duration, error) {
+	live := g.epg.liveHandler
+	if live == nil || live.cfgManager == nil {
+		return nil, 0, errors.New("live tv is not configured")
+	}
+	settings, err := live.cfgManager.Load()
+	if err != nil {
+		return nil, 0, err
+	}
+	src := live.liveSourceForProfile(profileID, settings)
+	sources := resolvedLiveSources(src)
+	includeSourceInID := len(sources) > 1
+
+	var channels []recordings.RuleChannel
+	for _, liveSource := range sources {
+		sourceChannels, err := live.fetchSourceChannels(ctx, liveSource)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, ch := range tagChannelsWithSource(sourceChannels, liveSource, includeSourceInID) {
+			channels = append(channels, recordings.RuleChannel{ID: ch.ID, TvgID: ch.TvgID, Name: ch.Name, SourceURL: ch.URL})
+		}
+	}
+	return channels, g.epg.epgTimeOffsetForProfile(profileID), nil
+}
+
+func (g *RecordingRuleGuide) MaxConcurrentRecordings() int {
+	if g.epg.cfgManager == nil {
+		return 0
+	}
+	settings, err := g.epg.cfgManager.Load()
+	if err != nil {
+		return 0
+	}
+	return settings.Live.MaxConcurrentRecordings
+}
@@ -37,6 +37,7 @@ type recordingService interface {
 type RecordingsHandler struct {
 	service recordingService
 	users   recordingUsersProvider
+	rules   recordingRuleService // optional
 }
 
 func NewRecordingsHandler(service recordingService, users recordingUsersProvider) *RecordingsHandler {
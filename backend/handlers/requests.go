@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"novastream/config"
+	"novastream/models"
+	"novastream/services/overseerr"
+)
+
+// requestAvailabilityTimeout bounds the Overseerr lookup merged into title
+// responses, so a slow request manager doesn't hold up details pages.
+const requestAvailabilityTimeout = 3 * time.Second
+
+// requestPassthrough proxies title requests to an external request manager
+// (Overseerr/Jellyseerr) and reports titles' state there.
+type requestPassthrough interface {
+	IsConfigured() bool
+	Availability(ctx context.Context, mediaType string, tmdbID int64) (*models.RequestAvailability, error)
+	Request(ctx context.Context, mediaType string, tmdbID int64, seasons []int) (*models.RequestAvailability, error)
+}
+
+// ConfigureOverseerr points the Overseerr client at the configured instance,
+// or leaves it unconfigured when the integration is disabled.
+func ConfigureOverseerr(client *overseerr.Client, s config.OverseerrSettings) {
+	if client == nil {
+		return
+	}
+	if !s.Enabled {
+		client.Configure("", "")
+		return
+	}
+	client.Configure(s.URL, s.APIKey)
+}
+
+// SetRequestPassthrough enables Overseerr availability on title details and
+// the request endpoint.
+func (h *MetadataHandler) SetRequestPassthrough(passthrough requestPassthrough) {
+	h.Requests = passthrough
+}
+
+// requestAvailability looks up a title's request state. It returns nil when
+// no request manager is configured, the title has no TMDB ID, or the lookup
+// fails.
+func requestAvailability(ctx context.Context, passthrough requestPassthrough, mediaType string, tmdbID int64) *models.RequestAvailability {
+	if passthrough == nil || tmdbID <= 0 || !passthrough.IsConfigured() {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, requestAvailabilityTimeout)
+	defer cancel()
+	availability, err := passthrough.Availability(ctx, mediaType, tmdbID)
+	if err != nil {
+		log.Printf("[requests] availability lookup for %s tmdb:%d failed: %v", mediaType, tmdbID, err)
+		return nil
+	}
+	return availability
+}
+
+// withMovieAvailability returns a copy of title carrying its request state.
+// The metadata service hands out cached titles, so they are never modified
+// in place.
+func withMovieAvailability(ctx context.Context, passthrough requestPassthrough, title *models.Title) *models.Title {
+	if title == nil {
+		return nil
+	}
+	availability := requestAvailability(ctx, passthrough, "movie", title.TMDBID)
+	if availability == nil {
+		return title
+	}
+	copied := *title
+	copied.Availability = availability
+	return &copied
+}
+
+// withSeriesAvailability is withMovieAvailability for series details.
+func withSeriesAvailability(ctx context.Context, passthrough requestPassthrough, details *models.SeriesDetails) *models.SeriesDetails {
+	if details == nil {
+		return nil
+	}
+	availability := requestAvailability(ctx, passthrough, "series", details.Title.TMDBID)
+	if availability == nil {
+		return details
+	}
+	copied := *details
+	copied.Title.Availability = availability
+	return &copied
+}
+
+type titleRequestBody struct {
+	MediaType string `json:"mediaType"` // movie | series
+	TMDBID    int64  `json:"tmdbId"`
+	Seasons   []int  `json:"seasons,omitempty"` // series only; empty requests all seasons
+}
+
+// RequestTitle passes a request for a movie or series through to Overseerr
+// and returns the title's resulting request state.
+func (h *MetadataHandler) RequestTitle(w http.ResponseWriter, r *http.Request) {
+	if h.Requests == nil || !h.Requests.IsConfigured() {
+		writeJSONError(w, "requests are not enabled", http.StatusNotImplemented)
+		return
+	}
+	var body titleRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSONError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	body.MediaType = strings.ToLower(strings.TrimSpace(body.MediaType))
+	if body.MediaType != "movie" && body.MediaType != "series" {
+		writeJSONError(w, "mediaType must be movie or series", http.StatusBadRequest)
+		return
+	}
+	if body.TMDBID <= 0 {
+		writeJSONError(w, "tmdbId is required", http.StatusBadRequest)
+		return
+	}
+	if body.MediaType == "movie" {
+		body.Seasons = nil
+	}
+
+	availability, err := h.Requests.Request(r.Context(), body.MediaType, body.TMDBID, body.Seasons)
+	if err != nil {
+		var apiErr *overseerr.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 {
+			// Overseerr rejects duplicates, quota overruns and missing
+			// permissions with 4xx; pass its message on.
+			writeJSONError(w, err.Error(), http.StatusConflict)
+			return
+		}
+		writeJSONError(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(availability)
+}
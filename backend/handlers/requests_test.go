@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"novastream/models"
+	"novastream/services/overseerr"
+)
+
+type fakeRequestPassthrough struct {
+	availability *models.RequestAvailability
+	err          error
+	lastType     string
+	lastTMDBID   int64
+	lastSeasons  []int
+}
+
+func (f *fakeRequestPassthrough) IsConfigured() bool { return true }
+
+func (f *fakeRequestPassthrough) Availability(_ context.Context, mediaType string, tmdbID int64) (*models.RequestAvailability, error) {
+	f.lastType, f.lastTMDBID = mediaType, tmdbID
+	return f.availability, f.err
+}
+
+func (f *fakeRequestPassthrough) Request(_ context.Context, mediaType string, tmdbID int64, seasons []int) (*models.RequestAvailability, error) {
+	f.lastType, f.lastTMDBID, f.lastSeasons = mediaType, tmdbID, seasons
+	return f.availability, f.err
+}
+
+func TestMetadataHandler_MovieDetailsMergesRequestAvailability(t *testing.T) {
+	cached := &models.Title{ID: "tmdb:movie:603", Name: "The Matrix", MediaType: "movie", TMDBID: 603}
+	fake := &fakeMetadataService{movieResp: cached}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+	passthrough := &fakeRequestPassthrough{availability: &models.RequestAvailability{Source: "overseerr", Status: models.RequestStatusAvailable}}
+	handler.SetRequestPassthrough(passthrough)
+
+	rec := httptest.NewRecorder()
+	handler.MovieDetails(rec, httptest.NewRequest(http.MethodGet, "/api/metadata/movies/details?tmdbId=603", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d", http.StatusOK, rec.Code)
+	}
+	var payload models.Title
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.Availability == nil || payload.Availability.Status != models.RequestStatusAvailable {
+		t.Fatalf("expected availability merged into title, got %+v", payload.Availability)
+	}
+	if passthrough.lastType != "movie" || passthrough.lastTMDBID != 603 {
+		t.Fatalf("unexpected lookup %s %d", passthrough.lastType, passthrough.lastTMDBID)
+	}
+	if cached.Availability != nil {
+		t.Fatalf("expected the service's title to be left unmodified")
+	}
+}
+
+func TestMetadataHandler_RequestTitle(t *testing.T) {
+	handler := NewMetadataHandler(&fakeMetadataService{}, testConfigManager(t))
+
+	rec := httptest.NewRecorder()
+	handler.RequestTitle(rec, httptest.NewRequest(http.MethodPost, "/api/metadata/request", strings.NewReader(`{"mediaType":"movie","tmdbId":1}`)))
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("expected %d without a request manager, got %d", http.StatusNotImplemented, rec.Code)
+	}
+
+	passthrough := &fakeRequestPassthrough{availability: &models.RequestAvailability{Source: "overseerr", Status: models.RequestStatusPending, RequestID: 7}}
+	handler.SetRequestPassthrough(passthrough)
+
+	rec = httptest.NewRecorder()
+	handler.RequestTitle(rec, httptest.NewRequest(http.MethodPost, "/api/metadata/request", strings.NewReader(`{"mediaType":"series","tmdbId":1396,"seasons":[1,2]}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if passthrough.lastType != "series" || passthrough.lastTMDBID != 1396 || len(passthrough.lastSeasons) != 2 {
+		t.Fatalf("unexpected request %s %d %v", passthrough.lastType, passthrough.lastTMDBID, passthrough.lastSeasons)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.RequestTitle(rec, httptest.NewRequest(http.MethodPost, "/api/metadata/request", strings.NewReader(`{"mediaType":"episode","tmdbId":1}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d for unsupported media type, got %d", http.StatusBadRequest, rec.Code)
+	}
+
+	passthrough.err = &overseerr.APIError{StatusCode: http.StatusConflict, Message: "Request for this media already exists"}
+	rec = httptest.NewRecorder()
+	handler.RequestTitle(rec, httptest.NewRequest(http.MethodPost, "/api/metadata/request", strings.NewReader(`{"mediaType":"movie","tmdbId":1}`)))
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected %d when overseerr rejects the request, got %d", http.StatusConflict, rec.Code)
+	}
+}
@@ -0,0 +1,157 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	"novastream/models"
+	"novastream/services/savedfilters"
+)
+
+type savedFilterService interface {
+	Create(userID, name string, filter models.DiscoverFilter) (models.SavedFilter, error)
+	Update(userID, id, name string, filter models.DiscoverFilter) (models.SavedFilter, error)
+	Get(userID, id string) (models.SavedFilter, error)
+	ListByUser(userID string) []models.SavedFilter
+	Delete(userID, id string) error
+}
+
+var _ savedFilterService = (*savedfilters.Service)(nil)
+
+// SavedFiltersHandler manages a profile's named discover filters and serves
+// the titles matching each one.
+type SavedFiltersHandler struct {
+	Service  savedFilterService
+	Users    userService
+	Metadata *MetadataHandler
+}
+
+// NewSavedFiltersHandler creates a SavedFiltersHandler. metadata serves the
+// discover results for the items endpoint.
+func NewSavedFiltersHandler(service savedFilterService, users userService, metadata *MetadataHandler) *SavedFiltersHandler {
+	return &SavedFiltersHandler{Service: service, Users: users, Metadata: metadata}
+}
+
+type savedFilterRequest struct {
+	Name   string                `json:"name"`
+	Filter models.DiscoverFilter `json:"filter"`
+}
+
+// List returns the profile's saved filters.
+func (h *SavedFiltersHandler) List(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.Service.ListByUser(userID))
+}
+
+// Create saves a named filter for the profile.
+func (h *SavedFiltersHandler) Create(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+	var body savedFilterRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSONError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	saved, err := h.Service.Create(userID, body.Name, body.Filter)
+	if err != nil {
+		writeSavedFilterError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(saved)
+}
+
+// Update renames a saved filter and replaces its criteria.
+func (h *SavedFiltersHandler) Update(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+	var body savedFilterRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSONError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	saved, err := h.Service.Update(userID, mux.Vars(r)["filterID"], body.Name, body.Filter)
+	if err != nil {
+		writeSavedFilterError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(saved)
+}
+
+// Delete removes one of the profile's saved filters.
+func (h *SavedFiltersHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+	if err := h.Service.Delete(userID, mux.Vars(r)["filterID"]); err != nil {
+		writeSavedFilterError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Items returns the titles matching a saved filter, paged with limit and
+// offset like the genre and decade discover endpoints.
+func (h *SavedFiltersHandler) Items(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+	h.serveItems(w, r, userID, mux.Vars(r)["filterID"])
+}
+
+func (h *SavedFiltersHandler) serveItems(w http.ResponseWriter, r *http.Request, userID, filterID string) {
+	saved, err := h.Service.Get(userID, filterID)
+	if err != nil {
+		writeSavedFilterError(w, err)
+		return
+	}
+	if h.Metadata == nil {
+		writeJSONError(w, "metadata source is unavailable", http.StatusServiceUnavailable)
+		return
+	}
+	h.Metadata.discoverWithFilter(w, r, userID, saved.Filter)
+}
+
+func (h *SavedFiltersHandler) Options(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func writeSavedFilterError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, savedfilters.ErrFilterNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, savedfilters.ErrNameRequired), errors.Is(err, savedfilters.ErrInvalidFilter):
+		status = http.StatusBadRequest
+	}
+	writeJSONError(w, err.Error(), status)
+}
+
+func (h *SavedFiltersHandler) requireUser(w http.ResponseWriter, r *http.Request) (string, bool) {
+	userID := strings.TrimSpace(mux.Vars(r)["userID"])
+	if userID == "" {
+		writeJSONError(w, "user id is required", http.StatusBadRequest)
+		return "", false
+	}
+	if h.Users != nil && !h.Users.Exists(userID) {
+		writeJSONError(w, "user not found", http.StatusNotFound)
+		return "", false
+	}
+	return userID, true
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"novastream/models"
+	"novastream/services/metadata"
+	"novastream/services/savedfilters"
+)
+
+type fakeDiscoverFilterService struct {
+	*fakeMetadataService
+	lastFilter models.DiscoverFilter
+	lastLimit  int
+	lastOffset int
+}
+
+func (f *fakeDiscoverFilterService) DiscoverWithFilter(_ context.Context, filter models.DiscoverFilter, limit, offset int, _ metadata.ShelfLoadOptions) ([]models.TrendingItem, int, error) {
+	f.lastFilter, f.lastLimit, f.lastOffset = filter, limit, offset
+	return []models.TrendingItem{{Rank: 1, Title: models.Title{ID: "tmdb:movie:129", Name: "Spirited Away", MediaType: "movie"}}}, 40, nil
+}
+
+func newTestSavedFiltersHandler(t *testing.T) (*SavedFiltersHandler, *fakeDiscoverFilterService) {
+	t.Helper()
+	svc, err := savedfilters.NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	fake := &fakeDiscoverFilterService{fakeMetadataService: &fakeMetadataService{}}
+	return NewSavedFiltersHandler(svc, nil, NewMetadataHandler(fake, testConfigManager(t))), fake
+}
+
+func TestSavedFiltersHandler_CRUDAndItems(t *testing.T) {
+	h, fake := newTestSavedFiltersHandler(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/users/u1/saved-filters",
+		strings.NewReader(`{"name":"90s anime movies","filter":{"mediaType":"movie","genreIds":[16],"yearFrom":1990,"yearTo":1999}}`))
+	req = mux.SetURLVars(req, map[string]string{"userID": "u1"})
+	rec := httptest.NewRecorder()
+	h.Create(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: expected %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+	var saved models.SavedFilter
+	if err := json.Unmarshal(rec.Body.Bytes(), &saved); err != nil {
+		t.Fatalf("decode saved filter: %v", err)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/users/u1/saved-filters", strings.NewReader(`{"name":"Bad","filter":{"mediaType":"episode"}}`))
+	req = mux.SetURLVars(req, map[string]string{"userID": "u1"})
+	rec = httptest.NewRecorder()
+	h.Create(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid filter: expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/users/u1/saved-filters/"+saved.ID+"/items?limit=20&offset=20", nil)
+	req = mux.SetURLVars(req, map[string]string{"userID": "u1", "filterID": saved.ID})
+	rec = httptest.NewRecorder()
+	h.Items(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("items: expected %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var resp DiscoverNewResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode items: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Total != 40 {
+		t.Fatalf("unexpected items response %+v", resp)
+	}
+	if fake.lastFilter.YearFrom != 1990 || fake.lastLimit != 20 || fake.lastOffset != 20 {
+		t.Fatalf("unexpected discover call filter=%+v limit=%d offset=%d", fake.lastFilter, fake.lastLimit, fake.lastOffset)
+	}
+
+	// Other profiles can't read or delete the filter.
+	req = httptest.NewRequest(http.MethodGet, "/api/users/u2/saved-filters/"+saved.ID+"/items", nil)
+	req = mux.SetURLVars(req, map[string]string{"userID": "u2", "filterID": saved.ID})
+	rec = httptest.NewRecorder()
+	h.Items(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("other profile items: expected %d, got %d", http.StatusNotFound, rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/api/users/u1/saved-filters/"+saved.ID,
+		strings.NewReader(`{"name":"Short comedies","filter":{"mediaType":"movie","genreIds":[35],"runtimeMax":90}}`))
+	req = mux.SetURLVars(req, map[string]string{"userID": "u1", "filterID": saved.ID})
+	rec = httptest.NewRecorder()
+	h.Update(rec, req)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Short comedies") {
+		t.Fatalf("update: expected %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/api/users/u1/saved-filters/"+saved.ID, nil)
+	req = mux.SetURLVars(req, map[string]string{"userID": "u1", "filterID": saved.ID})
+	rec = httptest.NewRecorder()
+	h.Delete(rec, req)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("delete: expected %d, got %d", http.StatusNoContent, rec.Code)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/users/u1/saved-filters", nil)
+	req = mux.SetURLVars(req, map[string]string{"userID": "u1"})
+	rec = httptest.NewRecorder()
+	h.List(rec, req)
+	if strings.TrimSpace(rec.Body.String()) != "[]" {
+		t.Fatalf("expected no saved filters after delete, got %s", rec.Body.String())
+	}
+}
+
+func TestDisplayListHandler_SavedFilterSource(t *testing.T) {
+	h, _ := newTestSavedFiltersHandler(t)
+	saved, err := h.Service.Create("u1", "Short comedies", models.DiscoverFilter{MediaType: "movie", GenreIDs: []int64{35}, RuntimeMax: 90})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	display := NewDisplayListHandler(nil, nil, nil)
+	display.SetMetadataHandler(h.Metadata)
+	display.SetSavedFilters(h)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users/u1/display-list?source=saved-filter&listId="+saved.ID, nil)
+	req = mux.SetURLVars(req, map[string]string{"userID": "u1"})
+	rec := httptest.NewRecorder()
+	display.Get(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if payload["source"] != "saved-filter" || payload["total"] != float64(40) {
+		t.Fatalf("unexpected payload %v", payload)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/users/u1/display-list?source=saved-filter", nil)
+	req = mux.SetURLVars(req, map[string]string{"userID": "u1"})
+	rec = httptest.NewRecorder()
+	display.Get(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("missing listId: expected %d, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestMetadataHandler_DiscoverAdvanced(t *testing.T) {
+	h, fake := newTestSavedFiltersHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/discover/advanced?type=tv&genreIds=18,80,18&yearFrom=1990&yearTo=1999&runtimeMax=60&minRating=7.5&watchProviders=337,8&watchRegion=gb&sort=rating&limit=10&offset=20", nil)
+	rec := httptest.NewRecorder()
+	h.Metadata.DiscoverAdvanced(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	want := models.DiscoverFilter{
+		MediaType:        "series",
+		GenreIDs:         []int64{18, 80},
+		YearFrom:         1990,
+		YearTo:           1999,
+		RuntimeMax:       60,
+		MinRating:        7.5,
+		WatchProviderIDs: []int64{8, 337},
+		WatchRegion:      "GB",
+		Sort:             "rating",
+	}
+	if !reflect.DeepEqual(fake.lastFilter, want) || fake.lastLimit != 10 || fake.lastOffset != 20 {
+		t.Fatalf("unexpected discover call filter=%+v limit=%d offset=%d", fake.lastFilter, fake.lastLimit, fake.lastOffset)
+	}
+
+	for _, query := range []string{
+		"type=movie&yearFrom=1999&yearTo=1990",
+		"type=movie&yearFrom=nineties",
+		"type=movie&watchProviders=8,netflix",
+		"type=episode",
+	} {
+		rec = httptest.NewRecorder()
+		h.Metadata.DiscoverAdvanced(rec, httptest.NewRequest(http.MethodGet, "/api/discover/advanced?"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected %d, got %d", query, http.StatusBadRequest, rec.Code)
+		}
+	}
+}
@@ -14,7 +14,9 @@ import (
 
 	"novastream/config"
 	"novastream/models"
+	"novastream/services/notifications"
 	"novastream/services/scheduler"
+	"novastream/utils/locale"
 )
 
 // ScheduledTasksHandler handles scheduled tasks API endpoints
@@ -22,6 +24,7 @@ type ScheduledTasksHandler struct {
 	configManager    *config.Manager
 	schedulerService *scheduler.Service
 	usersService     scheduledTaskUsersProvider
+	notifier         *notifications.Sender
 }
 
 type scheduledTaskUsersProvider interface {
@@ -35,9 +38,18 @@ func NewScheduledTasksHandler(configManager *config.Manager, schedulerService *s
 		configManager:    configManager,
 		schedulerService: schedulerService,
 		usersService:     usersService,
+		notifier:         newTaskNotificationSender(configManager),
 	}
 }
 
+func newTaskNotificationSender(configManager *config.Manager) *notifications.Sender {
+	sender := notifications.NewSender()
+	if configManager != nil {
+		sender.SetSettingsSource(configManager)
+	}
+	return sender
+}
+
 func validateScheduledTaskConfig(taskType config.ScheduledTaskType, taskConfig map[string]string, usersService scheduledTaskUsersProvider) error {
 	requireProfile := func(accountKey, message string) error {
 		if taskConfig == nil || taskConfig[accountKey] == "" || taskConfig["profileId"] == "" {
@@ -70,6 +82,20 @@ func validateScheduledTaskConfig(taskType config.ScheduledTaskType, taskConfig m
 		return requireProfile("jellyfinAccountId", "Jellyfin favorites sync requires jellyfinAccountId and profileId in config")
 	case config.ScheduledTaskTypeJellyfinHistorySync:
 		return requireProfile("jellyfinAccountId", "Jellyfin history sync requires jellyfinAccountId and profileId in config")
+	case config.ScheduledTaskTypeJellyfinSync:
+		if err := requireProfile("jellyfinAccountId", "Jellyfin sync requires jellyfinAccountId and profileId in config"); err != nil {
+			return err
+		}
+		switch taskConfig["syncDirection"] {
+		case "", "source_to_target", "target_to_source", "bidirectional":
+		default:
+			return fmt.Errorf("Invalid sync direction. Must be source_to_target, target_to_source, or bidirectional")
+		}
+		switch taskConfig["syncScope"] {
+		case "", "all", "watchlist", "history":
+		default:
+			return fmt.Errorf("Invalid sync scope. Must be all, watchlist, or history")
+		}
 	case config.ScheduledTaskTypeTraktHistorySync:
 		if err := requireProfile("traktAccountId", "Trakt history sync requires traktAccountId and profileId in config"); err != nil {
 			return err
@@ -80,11 +106,45 @@ func validateScheduledTaskConfig(taskType config.ScheduledTaskType, taskConfig m
 			return fmt.Errorf("Invalid sync direction. Must be trakt_to_local, local_to_trakt, or bidirectional")
 		}
 	case config.ScheduledTaskTypeSimklHistorySync:
-		return requireProfile("simklAccountId", "Simkl history sync requires simklAccountId and profileId in config")
+		if err := requireProfile("simklAccountId", "Simkl history sync requires simklAccountId and profileId in config"); err != nil {
+			return err
+		}
+		if taskConfig["syncDirection"] == "" {
+			taskConfig["syncDirection"] = "simkl_to_local"
+		} else if taskConfig["syncDirection"] != "simkl_to_local" && taskConfig["syncDirection"] != "local_to_simkl" && taskConfig["syncDirection"] != "bidirectional" {
+			return fmt.Errorf("Invalid sync direction. Must be simkl_to_local, local_to_simkl, or bidirectional")
+		}
+	case config.ScheduledTaskTypeSimklWatchlistSync:
+		if err := requireProfile("simklAccountId", "Simkl watchlist sync requires simklAccountId and profileId in config"); err != nil {
+			return err
+		}
+		switch taskConfig["syncDirection"] {
+		case "", "source_to_target", "target_to_source", "bidirectional":
+		default:
+			return fmt.Errorf("Invalid sync direction. Must be source_to_target, target_to_source, or bidirectional")
+		}
 	case config.ScheduledTaskTypeLocalMediaScan:
 		if taskConfig == nil || strings.TrimSpace(taskConfig["libraryId"]) == "" {
 			return errors.New("Local media scan requires libraryId in config")
 		}
+	case config.ScheduledTaskTypeEmailDigest:
+		if taskConfig == nil || strings.TrimSpace(taskConfig["profileId"]) == "" {
+			return errors.New("Email digest requires profileId in config")
+		}
+		if _, err := notifications.ParseRecipients(taskConfig["to"]); err != nil {
+			return fmt.Errorf("Email digest requires recipients in config: %w", err)
+		}
+		return validateScheduledTaskProfileID(taskConfig["profileId"], usersService)
+	case config.ScheduledTaskTypeLeavingSoon:
+		if taskConfig == nil || strings.TrimSpace(taskConfig["profileId"]) == "" {
+			return errors.New("Leaving soon alerts require profileId in config")
+		}
+		return validateScheduledTaskProfileID(taskConfig["profileId"], usersService)
+	case config.ScheduledTaskTypeWatchTime:
+		if taskConfig == nil || strings.TrimSpace(taskConfig["profileId"]) == "" {
+			return errors.New("Watch time warnings require profileId in config")
+		}
+		return validateScheduledTaskProfileID(taskConfig["profileId"], usersService)
 	case config.ScheduledTaskTypeMDBListWatchlistSync:
 		return requireProfile("mdblistAccountId", "MDBList watchlist sync requires mdblistAccountId and profileId in config")
 	case config.ScheduledTaskTypeMDBListHistorySync:
@@ -102,17 +162,39 @@ func validateScheduledTaskConfig(taskType config.ScheduledTaskType, taskConfig m
 }
 
 func validateScheduledTaskFrequency(taskType config.ScheduledTaskType, frequency config.ScheduledTaskFrequency) error {
-	if taskType != config.ScheduledTaskTypeSimklHistorySync {
+	var name string
+	switch taskType {
+	case config.ScheduledTaskTypeSimklHistorySync:
+		name = "Simkl history sync"
+	case config.ScheduledTaskTypeSimklWatchlistSync:
+		name = "Simkl watchlist sync"
+	default:
 		return nil
 	}
 	switch frequency {
 	case config.ScheduledTaskFrequency1Min, config.ScheduledTaskFrequency5Min, config.ScheduledTaskFrequency15Min:
-		return errors.New("Simkl history sync must be scheduled no more frequently than every 30 minutes")
+		return fmt.Errorf("%s must be scheduled no more frequently than every 30 minutes", name)
 	default:
 		return nil
 	}
 }
 
+// normalizeScheduledTaskNotifications trims and validates a task's
+// notification targets.
+func normalizeScheduledTaskNotifications(targets []config.TaskNotification) ([]config.TaskNotification, error) {
+	out := make([]config.TaskNotification, 0, len(targets))
+	for i, target := range targets {
+		target.Type = config.TaskNotificationType(strings.ToLower(strings.TrimSpace(string(target.Type))))
+		target.URL = strings.TrimSpace(target.URL)
+		target.Token = strings.TrimSpace(target.Token)
+		if err := notifications.Validate(target); err != nil {
+			return nil, fmt.Errorf("notification %d: %w", i+1, err)
+		}
+		out = append(out, target)
+	}
+	return out, nil
+}
+
 func validateScheduledTaskProfileID(profileID string, usersService scheduledTaskUsersProvider) error {
 	profileID = strings.TrimSpace(profileID)
 	if profileID == "" || usersService == nil {
@@ -148,9 +230,19 @@ func validateScheduledTaskProfileID(profileID string, usersService scheduledTask
 func (h *ScheduledTasksHandler) ListTasks(w http.ResponseWriter, r *http.Request) {
 	tasks := h.schedulerService.GetTaskStatus()
 
+	// Cron tasks can't be projected from LastRunAt client-side, so send their
+	// next scheduled time keyed by task ID.
+	nextRuns := make(map[string]time.Time)
+	for _, task := range tasks {
+		if next, ok := scheduler.NextCronRun(task); ok {
+			nextRuns[task.ID] = next
+		}
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"tasks": tasks,
+		"tasks":    tasks,
+		"nextRuns": nextRuns,
 	})
 }
 
@@ -158,11 +250,14 @@ func (h *ScheduledTasksHandler) ListTasks(w http.ResponseWriter, r *http.Request
 // POST /admin/api/scheduled-tasks
 func (h *ScheduledTasksHandler) CreateTask(w http.ResponseWriter, r *http.Request) {
 	var req struct {
-		Type      config.ScheduledTaskType      `json:"type"`
-		Name      string                        `json:"name"`
-		Frequency config.ScheduledTaskFrequency `json:"frequency"`
-		Config    map[string]string             `json:"config"`
-		Enabled   bool                          `json:"enabled"`
+		Type           config.ScheduledTaskType      `json:"type"`
+		Name           string                        `json:"name"`
+		Frequency      config.ScheduledTaskFrequency `json:"frequency"`
+		CronExpression string                        `json:"cronExpression"`
+		Timezone       string                        `json:"timezone"`
+		Config         map[string]string             `json:"config"`
+		Enabled        bool                          `json:"enabled"`
+		Notifications  []config.TaskNotification     `json:"notifications"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -211,15 +306,36 @@ func (h *ScheduledTasksHandler) CreateTask(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	taskNotifications, err := normalizeScheduledTaskNotifications(req.Notifications)
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
 	task := config.ScheduledTask{
-		ID:         uuid.New().String(),
-		Type:       req.Type,
-		Name:       req.Name,
-		Frequency:  req.Frequency,
-		Config:     req.Config,
-		Enabled:    req.Enabled,
-		LastStatus: config.ScheduledTaskStatusPending,
-		CreatedAt:  time.Now().UTC(),
+		ID:             uuid.New().String(),
+		Type:           req.Type,
+		Name:           req.Name,
+		Frequency:      req.Frequency,
+		CronExpression: strings.TrimSpace(req.CronExpression),
+		Timezone:       strings.TrimSpace(req.Timezone),
+		Config:         req.Config,
+		Enabled:        req.Enabled,
+		LastStatus:     config.ScheduledTaskStatusPending,
+		CreatedAt:      time.Now().UTC(),
+		Notifications:  taskNotifications,
+	}
+	if err := scheduler.ValidateTaskSchedule(task); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
 	}
 
 	settings, err := h.configManager.Load()
@@ -271,10 +387,13 @@ func (h *ScheduledTasksHandler) UpdateTask(w http.ResponseWriter, r *http.Reques
 	}
 
 	var req struct {
-		Name      string                        `json:"name"`
-		Frequency config.ScheduledTaskFrequency `json:"frequency"`
-		Config    map[string]string             `json:"config"`
-		Enabled   *bool                         `json:"enabled"`
+		Name           string                        `json:"name"`
+		Frequency      config.ScheduledTaskFrequency `json:"frequency"`
+		CronExpression *string                       `json:"cronExpression"`
+		Timezone       *string                       `json:"timezone"`
+		Config         map[string]string             `json:"config"`
+		Enabled        *bool                         `json:"enabled"`
+		Notifications  *[]config.TaskNotification    `json:"notifications"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -286,6 +405,19 @@ func (h *ScheduledTasksHandler) UpdateTask(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	var taskNotifications []config.TaskNotification
+	if req.Notifications != nil {
+		var err error
+		if taskNotifications, err = normalizeScheduledTaskNotifications(*req.Notifications); err != nil {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error": err.Error(),
+			})
+			return
+		}
+	}
+
 	settings, err := h.configManager.Load()
 	if err != nil {
 		w.Header().Set("Content-Type", "application/json")
@@ -305,12 +437,21 @@ func (h *ScheduledTasksHandler) UpdateTask(w http.ResponseWriter, r *http.Reques
 			if req.Frequency != "" {
 				settings.ScheduledTasks.Tasks[i].Frequency = req.Frequency
 			}
+			if req.CronExpression != nil {
+				settings.ScheduledTasks.Tasks[i].CronExpression = strings.TrimSpace(*req.CronExpression)
+			}
+			if req.Timezone != nil {
+				settings.ScheduledTasks.Tasks[i].Timezone = strings.TrimSpace(*req.Timezone)
+			}
 			if req.Config != nil {
 				settings.ScheduledTasks.Tasks[i].Config = req.Config
 			}
 			if req.Enabled != nil {
 				settings.ScheduledTasks.Tasks[i].Enabled = *req.Enabled
 			}
+			if req.Notifications != nil {
+				settings.ScheduledTasks.Tasks[i].Notifications = taskNotifications
+			}
 			updatedTask = &settings.ScheduledTasks.Tasks[i]
 			break
 		}
@@ -334,6 +475,15 @@ func (h *ScheduledTasksHandler) UpdateTask(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
+	if err := scheduler.ValidateTaskSchedule(*updatedTask); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
 	if err := validateScheduledTaskConfig(updatedTask.Type, updatedTask.Config, h.usersService); err != nil {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusBadRequest)
@@ -428,6 +578,65 @@ func (h *ScheduledTasksHandler) DeleteTask(w http.ResponseWriter, r *http.Reques
 	})
 }
 
+// GetTaskRunHistory returns a task's recent runs, newest first
+// GET /admin/api/scheduled-tasks/{taskID}/history
+func (h *ScheduledTasksHandler) GetTaskRunHistory(w http.ResponseWriter, r *http.Request) {
+	taskID := mux.Vars(r)["taskID"]
+	runs, err := h.schedulerService.GetTaskRunHistory(taskID)
+	if err != nil {
+		writeServiceError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+		"runs":    runs,
+	})
+}
+
+// TestNotification sends a sample event to a notification target
+// POST /admin/api/scheduled-tasks/notifications/test
+func (h *ScheduledTasksHandler) TestNotification(w http.ResponseWriter, r *http.Request) {
+	var target config.TaskNotification
+	if err := json.NewDecoder(r.Body).Decode(&target); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+	normalized, err := normalizeScheduledTaskNotifications([]config.TaskNotification{target})
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var display config.DisplaySettings
+	if settings, err := h.configManager.Load(); err == nil {
+		display = settings.Display
+	}
+	event := notifications.TestEvent(locale.Instance(display), time.Now().UTC())
+	if err := h.notifier.Send(r.Context(), normalized[0], event); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadGateway)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"success": true,
+	})
+}
+
 // RunTaskNow triggers immediate execution of a task
 // POST /admin/api/scheduled-tasks/{taskID}/run
 func (h *ScheduledTasksHandler) RunTaskNow(w http.ResponseWriter, r *http.Request) {
@@ -442,11 +651,7 @@ func (h *ScheduledTasksHandler) RunTaskNow(w http.ResponseWriter, r *http.Reques
 	}
 
 	if err := h.schedulerService.RunTaskNow(taskID); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]interface{}{
-			"error": err.Error(),
-		})
+		writeServiceError(w, err, http.StatusBadRequest)
 		return
 	}
 
@@ -527,3 +732,74 @@ func (h *ScheduledTasksHandler) ToggleTask(w http.ResponseWriter, r *http.Reques
 		"enabled": req.Enabled,
 	})
 }
+
+// GetMaintenanceWindow returns the maintenance window and whether it is open now
+// GET /admin/api/scheduled-tasks/maintenance-window
+func (h *ScheduledTasksHandler) GetMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.configManager.Load()
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Failed to load settings: " + err.Error(),
+		})
+		return
+	}
+
+	window := settings.ScheduledTasks.MaintenanceWindow
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"maintenanceWindow": window,
+		"active":            window.Enabled && scheduler.InMaintenanceWindow(window, time.Now()),
+	})
+}
+
+// UpdateMaintenanceWindow replaces the maintenance window
+// PUT /admin/api/scheduled-tasks/maintenance-window
+func (h *ScheduledTasksHandler) UpdateMaintenanceWindow(w http.ResponseWriter, r *http.Request) {
+	var window config.MaintenanceWindowSettings
+	if err := json.NewDecoder(r.Body).Decode(&window); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+	window.Start = strings.TrimSpace(window.Start)
+	window.End = strings.TrimSpace(window.End)
+	window.Timezone = strings.TrimSpace(window.Timezone)
+	if err := scheduler.ValidateMaintenanceWindow(window); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Invalid maintenance window: " + err.Error(),
+		})
+		return
+	}
+
+	settings, err := h.configManager.Load()
+	if err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Failed to load settings: " + err.Error(),
+		})
+		return
+	}
+	settings.ScheduledTasks.MaintenanceWindow = window
+	if err := h.configManager.Save(settings); err != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"error": "Failed to save settings: " + err.Error(),
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"maintenanceWindow": window,
+		"active":            window.Enabled && scheduler.InMaintenanceWindow(window, time.Now()),
+	})
+}
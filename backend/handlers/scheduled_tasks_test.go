@@ -205,6 +205,32 @@ func TestCreateTask_LocalMediaScanAllLibrariesValidation(t *testing.T) {
 	}
 }
 
+func TestCreateTask_EmailDigestValidation(t *testing.T) {
+	h := newTestScheduledTasksHandler(t)
+
+	tests := []struct {
+		name       string
+		config     map[string]string
+		wantStatus int
+	}{
+		{"missing recipients", map[string]string{"profileId": "prof-1"}, http.StatusBadRequest},
+		{"invalid recipients", map[string]string{"profileId": "prof-1", "to": "not an address"}, http.StatusBadRequest},
+		{"missing profile", map[string]string{"to": "me@example.com"}, http.StatusBadRequest},
+		{"valid", map[string]string{"profileId": "prof-1", "to": "me@example.com, you@example.com"}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		rec := postCreateTask(t, h, map[string]interface{}{
+			"type":      string(config.ScheduledTaskTypeEmailDigest),
+			"name":      "Weekly digest",
+			"frequency": string(config.ScheduledTaskFrequencyWeekly),
+			"config":    tt.config,
+		})
+		if rec.Code != tt.wantStatus {
+			t.Errorf("%s: expected %d, got %d: %s", tt.name, tt.wantStatus, rec.Code, rec.Body.String())
+		}
+	}
+}
+
 func TestCreateTask_JellyfinFavoritesSyncValidation(t *testing.T) {
 	h := newTestScheduledTasksHandler(t)
 
@@ -455,3 +481,40 @@ func TestUpdateTask_InvalidProfileIDValidation(t *testing.T) {
 		t.Fatalf("expected invalid profile error, got %v", got)
 	}
 }
+
+func TestCreateTask_NotificationValidation(t *testing.T) {
+	h := newTestScheduledTasksHandler(t)
+
+	body := map[string]interface{}{
+		"type": string(config.ScheduledTaskTypeBackup),
+		"name": "Nightly backup",
+		"notifications": []map[string]interface{}{
+			{"type": "Discord", "url": " https://discord.com/api/webhooks/1/abc ", "onFailure": true},
+		},
+	}
+	rec := postCreateTask(t, h, body)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp struct {
+		Task config.ScheduledTask `json:"task"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Task.Notifications) != 1 {
+		t.Fatalf("expected 1 notification, got %d", len(resp.Task.Notifications))
+	}
+	n := resp.Task.Notifications[0]
+	if n.Type != config.TaskNotificationTypeDiscord || n.URL != "https://discord.com/api/webhooks/1/abc" || !n.OnFailure || n.OnSuccess {
+		t.Fatalf("unexpected notification: %+v", n)
+	}
+
+	body["notifications"] = []map[string]interface{}{
+		{"type": "gotify", "url": "https://gotify.local", "onFailure": true},
+	}
+	rec = postCreateTask(t, h, body)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for gotify without token, got %d: %s", rec.Code, rec.Body.String())
+	}
+}
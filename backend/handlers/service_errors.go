@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	metadatapkg "novastream/services/metadata"
+	"novastream/services/scheduler"
+)
+
+// Machine-readable error codes returned alongside the error message.
+const (
+	errorCodeNotFound            = "not_found"
+	errorCodeNotConfigured       = "not_configured"
+	errorCodeUpstreamUnavailable = "upstream_unavailable"
+	errorCodeRateLimited         = "rate_limited"
+	errorCodeOffline             = "offline"
+	errorCodeConflict            = "conflict"
+	errorCodeUpstream            = "upstream_error"
+	errorCodeBadRequest          = "bad_request"
+	errorCodeInternal            = "internal_error"
+)
+
+// serviceErrorStatus maps typed metadata and scheduler errors to an HTTP
+// status and error code. Untyped errors keep the handler's fallback status.
+func serviceErrorStatus(err error, fallback int) (int, string) {
+	switch {
+	case errors.Is(err, metadatapkg.ErrNotFound), errors.Is(err, metadatapkg.ErrUnknownCacheNamespace), errors.Is(err, scheduler.ErrNotFound):
+		return http.StatusNotFound, errorCodeNotFound
+	case errors.Is(err, metadatapkg.ErrRateLimited):
+		return http.StatusTooManyRequests, errorCodeRateLimited
+	case errors.Is(err, metadatapkg.ErrNotConfigured), errors.Is(err, scheduler.ErrNotConfigured):
+		return http.StatusServiceUnavailable, errorCodeNotConfigured
+	case errors.Is(err, metadatapkg.ErrOffline), errors.Is(err, scheduler.ErrOffline):
+		return http.StatusServiceUnavailable, errorCodeOffline
+	case errors.Is(err, metadatapkg.ErrUpstreamUnavailable), errors.Is(err, scheduler.ErrUpstreamUnavailable):
+		return http.StatusServiceUnavailable, errorCodeUpstreamUnavailable
+	case errors.Is(err, scheduler.ErrTaskRunning), errors.Is(err, metadatapkg.ErrRetryInProgress):
+		return http.StatusConflict, errorCodeConflict
+	case errors.Is(err, metadatapkg.ErrUnknownTrendingSource):
+		return http.StatusBadRequest, errorCodeBadRequest
+	}
+	switch {
+	case fallback == http.StatusBadGateway:
+		return fallback, errorCodeUpstream
+	case fallback >= 500:
+		return fallback, errorCodeInternal
+	default:
+		return fallback, errorCodeBadRequest
+	}
+}
+
+// writeServiceError writes err as a JSON error response with a status and
+// code derived from its type.
+func writeServiceError(w http.ResponseWriter, err error, fallback int) {
+	status, code := serviceErrorStatus(err, fallback)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": err.Error(), "code": code})
+}
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	metadatapkg "novastream/services/metadata"
+	"novastream/services/scheduler"
+)
+
+func TestWriteServiceError(t *testing.T) {
+	cases := []struct {
+		err        error
+		fallback   int
+		wantStatus int
+		wantCode   string
+	}{
+		{fmt.Errorf("series: %w", metadatapkg.ErrNotFound), http.StatusBadGateway, http.StatusNotFound, errorCodeNotFound},
+		{metadatapkg.ErrRateLimited, http.StatusBadGateway, http.StatusTooManyRequests, errorCodeRateLimited},
+		{metadatapkg.ErrNotConfigured, http.StatusBadGateway, http.StatusServiceUnavailable, errorCodeNotConfigured},
+		{metadatapkg.ErrUpstreamUnavailable, http.StatusBadGateway, http.StatusServiceUnavailable, errorCodeUpstreamUnavailable},
+		{scheduler.ErrTaskRunning, http.StatusBadRequest, http.StatusConflict, errorCodeConflict},
+		{fmt.Errorf("tmdb: %w", metadatapkg.ErrOffline), http.StatusBadGateway, http.StatusServiceUnavailable, errorCodeOffline},
+		{fmt.Errorf("task: %w", scheduler.ErrOffline), http.StatusBadRequest, http.StatusServiceUnavailable, errorCodeOffline},
+		{fmt.Errorf("task %w", scheduler.ErrNotFound), http.StatusBadRequest, http.StatusNotFound, errorCodeNotFound},
+		{fmt.Errorf("source: %w", metadatapkg.ErrUnknownTrendingSource), http.StatusBadGateway, http.StatusBadRequest, errorCodeBadRequest},
+		{fmt.Errorf("boom"), http.StatusBadGateway, http.StatusBadGateway, errorCodeUpstream},
+	}
+	for _, tc := range cases {
+		rec := httptest.NewRecorder()
+		writeServiceError(rec, tc.err, tc.fallback)
+		if rec.Code != tc.wantStatus {
+			t.Errorf("%v: status = %d, want %d", tc.err, rec.Code, tc.wantStatus)
+		}
+		var body map[string]string
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode body: %v", err)
+		}
+		if body["code"] != tc.wantCode || body["error"] != tc.err.Error() {
+			t.Errorf("%v: body = %v, want code %q", tc.err, body, tc.wantCode)
+		}
+	}
+}
@@ -25,6 +25,8 @@ import (
 	"novastream/services/epg"
 	"novastream/services/mdblist"
 	"novastream/services/metadata"
+	"novastream/services/overseerr"
+	"novastream/services/streamingavailability"
 	user_settings "novastream/services/user_settings"
 )
 
@@ -52,6 +54,8 @@ type SettingsHandler struct {
 	PoolManager         pool.Manager
 	MetadataService     *metadata.Service
 	MDBListListsClient  *mdblist.ListsClient
+	OverseerrClient     *overseerr.Client
+	LeavingSoonClient   *streamingavailability.Client
 	DebridSearchService *debrid.SearchService
 	ImageHandler        *ImageHandler
 	EPGService          *epg.Service
@@ -84,6 +88,17 @@ func (h *SettingsHandler) SetMDBListListsClient(client *mdblist.ListsClient) {
 	h.MDBListListsClient = client
 }
 
+// SetOverseerrClient sets the Overseerr client for hot reloading its URL and API key.
+func (h *SettingsHandler) SetOverseerrClient(client *overseerr.Client) {
+	h.OverseerrClient = client
+}
+
+// SetStreamingAvailabilityClient sets the Streaming Availability client for
+// hot reloading its API key.
+func (h *SettingsHandler) SetStreamingAvailabilityClient(client *streamingavailability.Client) {
+	h.LeavingSoonClient = client
+}
+
 // SetDebridSearchService sets the debrid search service for hot reloading scrapers
 func (h *SettingsHandler) SetDebridSearchService(ds *debrid.SearchService) {
 	h.DebridSearchService = ds
@@ -571,6 +586,10 @@ func redactSettings(s *config.Settings) {
 	mask(&s.Metadata.TMDBAPIKey)
 	mask(&s.Metadata.AIAPIKey)
 	mask(&s.Metadata.GeminiAPIKey)
+	mask(&s.Metadata.FanartAPIKey)
+	for i := range s.Metadata.ExternalProviders {
+		mask(&s.Metadata.ExternalProviders[i].AuthValue)
+	}
 	mask(&s.Playback.YouTubeProxyURL)
 
 	// WebDAV
@@ -630,6 +649,31 @@ func redactSettings(s *config.Settings) {
 
 	// Database URL (may contain credentials in the connection string)
 	mask(&s.Database.URL)
+
+	// SMTP
+	mask(&s.SMTP.Password)
+
+	// Overseerr
+	mask(&s.Overseerr.APIKey)
+
+	// Streaming Availability
+	mask(&s.StreamingAvailability.APIKey)
+
+	// Parental controls
+	mask(&s.ParentalControls.OverridePIN)
+
+	// Notification targets: webhook URLs embed their secret, and Gotify/ntfy
+	// targets carry an access token.
+	maskTargets := func(targets []config.TaskNotification) {
+		for i := range targets {
+			mask(&targets[i].URL)
+			mask(&targets[i].Token)
+		}
+	}
+	for i := range s.ScheduledTasks.Tasks {
+		maskTargets(s.ScheduledTasks.Tasks[i].Notifications)
+	}
+	maskTargets(s.Storage.Notifications)
 }
 
 const redactedPlaceholder = "••••••••"
@@ -680,6 +724,12 @@ func preserveRedactedFields(incoming *config.Settings, existing *config.Settings
 	restore(&incoming.Metadata.TMDBAPIKey, existing.Metadata.TMDBAPIKey)
 	restore(&incoming.Metadata.AIAPIKey, existing.Metadata.AIAPIKey)
 	restore(&incoming.Metadata.GeminiAPIKey, existing.Metadata.GeminiAPIKey)
+	restore(&incoming.Metadata.FanartAPIKey, existing.Metadata.FanartAPIKey)
+	for i := range incoming.Metadata.ExternalProviders {
+		if i < len(existing.Metadata.ExternalProviders) {
+			restore(&incoming.Metadata.ExternalProviders[i].AuthValue, existing.Metadata.ExternalProviders[i].AuthValue)
+		}
+	}
 	restore(&incoming.Playback.YouTubeProxyURL, existing.Playback.YouTubeProxyURL)
 
 	// WebDAV
@@ -753,6 +803,38 @@ func preserveRedactedFields(incoming *config.Settings, existing *config.Settings
 
 	// Database URL
 	restore(&incoming.Database.URL, existing.Database.URL)
+
+	// SMTP
+	restore(&incoming.SMTP.Password, existing.SMTP.Password)
+
+	// Overseerr
+	restore(&incoming.Overseerr.APIKey, existing.Overseerr.APIKey)
+
+	// Streaming Availability
+	restore(&incoming.StreamingAvailability.APIKey, existing.StreamingAvailability.APIKey)
+
+	// Parental controls
+	restore(&incoming.ParentalControls.OverridePIN, existing.ParentalControls.OverridePIN)
+
+	// Notification targets (tasks match by ID, targets by index)
+	restoreTargets := func(incoming, existing []config.TaskNotification) {
+		for i := range incoming {
+			if i < len(existing) {
+				restore(&incoming[i].URL, existing[i].URL)
+				restore(&incoming[i].Token, existing[i].Token)
+			}
+		}
+	}
+	existingTasks := make(map[string]config.ScheduledTask, len(existing.ScheduledTasks.Tasks))
+	for _, task := range existing.ScheduledTasks.Tasks {
+		existingTasks[task.ID] = task
+	}
+	for i := range incoming.ScheduledTasks.Tasks {
+		if task, ok := existingTasks[incoming.ScheduledTasks.Tasks[i].ID]; ok {
+			restoreTargets(incoming.ScheduledTasks.Tasks[i].Notifications, task.Notifications)
+		}
+	}
+	restoreTargets(incoming.Storage.Notifications, existing.Storage.Notifications)
 }
 
 func (h *SettingsHandler) PutSettings(w http.ResponseWriter, r *http.Request) {
@@ -1010,6 +1092,8 @@ func displayName(name, fallback string) string {
 
 // reloadServices reloads services that cache configuration at startup
 func (h *SettingsHandler) reloadServices(s config.Settings) {
+	SetTrustedProxies(s.Server.TrustedProxies)
+
 	// Reload NNTP connection pool with new usenet providers
 	if h.PoolManager != nil {
 		providers := config.ToNNTPProviders(s.Usenet)
@@ -1030,6 +1114,14 @@ func (h *SettingsHandler) reloadServices(s config.Settings) {
 			Model:    s.Metadata.AIModel,
 			BaseURL:  s.Metadata.AIBaseURL,
 		})
+		h.MetadataService.SetArtworkProviders(s.Metadata.FanartAPIKey, s.Metadata.ArtworkProviderPriority)
+		h.MetadataService.SetTrailerPrequeuePolicy(TrailerPrequeuePolicy(s.Playback.TrailerPrequeue))
+		h.MetadataService.SetRateLimits(MetadataRateLimits(s.Metadata.RateLimits))
+		h.MetadataService.SetCertificationCountry(s.Metadata.CertificationCountry)
+		h.MetadataService.SetWatchProviderCountry(s.Metadata.WatchProviderCountry)
+		h.MetadataService.SetArtworkPreferences(ArtworkPreferences(s.Metadata.Artwork))
+		h.MetadataService.SetOffline(s.Metadata.Offline)
+		h.MetadataService.SetExternalProviders(ExternalMetadataProviders(s.Metadata.ExternalProviders))
 		log.Printf("[settings] reloaded metadata service API keys")
 
 		// Reload MDBList settings (rating sources, API key, enabled state)
@@ -1043,6 +1135,10 @@ func (h *SettingsHandler) reloadServices(s config.Settings) {
 	if h.MDBListListsClient != nil {
 		h.MDBListListsClient.UpdateAPIKey(s.MDBList.APIKey)
 	}
+	ConfigureOverseerr(h.OverseerrClient, s.Overseerr)
+	if h.LeavingSoonClient != nil {
+		h.LeavingSoonClient.Configure(s.StreamingAvailability.APIKey)
+	}
 
 	// Reload debrid scrapers (Torrentio, Jackett, etc.)
 	if h.DebridSearchService != nil {
@@ -1050,6 +1146,78 @@ func (h *SettingsHandler) reloadServices(s config.Settings) {
 	}
 }
 
+// MetadataRateLimits converts the metadata rate limit settings into the metadata service config.
+func MetadataRateLimits(cfg config.MetadataRateLimits) metadata.RateLimitConfig {
+	return metadata.RateLimitConfig{
+		TMDB:              metadata.ProviderRateLimit{QPS: cfg.TMDB.QPS, Concurrency: cfg.TMDB.Concurrency},
+		TVDB:              metadata.ProviderRateLimit{QPS: cfg.TVDB.QPS, Concurrency: cfg.TVDB.Concurrency},
+		MDBList:           metadata.ProviderRateLimit{QPS: cfg.MDBList.QPS, Concurrency: cfg.MDBList.Concurrency},
+		EnrichConcurrency: cfg.EnrichConcurrency,
+		EnrichLimit:       cfg.EnrichLimit,
+	}
+}
+
+// ExternalMetadataProviders converts the enabled external provider settings
+// into the metadata service config.
+func ExternalMetadataProviders(cfgs []config.ExternalMetadataProvider) []metadata.ExternalProviderConfig {
+	var out []metadata.ExternalProviderConfig
+	for _, cfg := range cfgs {
+		if !cfg.Enabled {
+			continue
+		}
+		m := cfg.Mapping
+		out = append(out, metadata.ExternalProviderConfig{
+			Name:         cfg.Name,
+			BaseURL:      cfg.BaseURL,
+			AuthHeader:   cfg.AuthHeader,
+			AuthValue:    cfg.AuthValue,
+			SearchPath:   cfg.SearchPath,
+			DetailsPath:  cfg.DetailsPath,
+			ImageBaseURL: cfg.ImageBaseURL,
+			Timeout:      time.Duration(cfg.TimeoutSeconds) * time.Second,
+			Mapping: metadata.ExternalProviderMapping{
+				Results:   m.Results,
+				Item:      m.Item,
+				ID:        m.ID,
+				Name:      m.Name,
+				MediaType: m.MediaType,
+				Year:      m.Year,
+				Overview:  m.Overview,
+				Poster:    m.Poster,
+				Backdrop:  m.Backdrop,
+				IMDBID:    m.IMDBID,
+				TMDBID:    m.TMDBID,
+				TVDBID:    m.TVDBID,
+			},
+		})
+	}
+	return out
+}
+
+// ArtworkPreferences converts the artwork settings into the metadata service preferences.
+func ArtworkPreferences(cfg config.ArtworkPreferences) metadata.ArtworkPreferences {
+	return metadata.ArtworkPreferences{
+		Language:         cfg.Language,
+		Textless:         cfg.Textless,
+		MinPosterWidth:   cfg.MinPosterWidth,
+		MinBackdropWidth: cfg.MinBackdropWidth,
+		Source:           cfg.Source,
+	}
+}
+
+// TrailerPrequeuePolicy converts the trailer prequeue settings into the metadata service policy.
+func TrailerPrequeuePolicy(cfg config.TrailerPrequeueSettings) metadata.TrailerPrequeuePolicy {
+	return metadata.TrailerPrequeuePolicy{
+		Enabled:          cfg.Enabled,
+		HeroItems:        cfg.HeroItems,
+		TrendingRowItems: cfg.TrendingRowItems,
+		MaxDiskMB:        cfg.MaxDiskMB,
+		WindowStart:      cfg.WindowStart,
+		WindowEnd:        cfg.WindowEnd,
+		PreviewClips:     cfg.PreviewClips,
+	}
+}
+
 // ClearMetadataCache clears all cached metadata files and images
 func (h *SettingsHandler) ClearMetadataCache(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -1078,6 +1246,23 @@ func (h *SettingsHandler) ClearMetadataCache(w http.ResponseWriter, r *http.Requ
 	json.NewEncoder(w).Encode(map[string]string{"status": "ok", "message": "Metadata and image cache cleared"})
 }
 
+// MetadataCacheStats returns entry counts and sizes for the metadata caches
+func (h *SettingsHandler) MetadataCacheStats(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if h.MetadataService == nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": "metadata service not available"})
+		return
+	}
+	stats, err := h.MetadataService.CacheStats()
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"caches": stats})
+}
+
 type epgGuideConfigSummary struct {
 	globalEnabled         bool
 	globalXMLTVConfigured bool
@@ -1,6 +1,10 @@
 package handlers
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
 	"testing"
 
 	"novastream/config"
@@ -296,3 +300,53 @@ func TestRedactSettings_EmptyFieldsNotRedacted(t *testing.T) {
 		t.Errorf("non-empty TMDBAPIKey should be redacted, got %q", s.Metadata.TMDBAPIKey)
 	}
 }
+
+func TestGetSettings_RedactsNotificationTargets(t *testing.T) {
+	cfg := config.DefaultSettings()
+	cfg.ScheduledTasks.Tasks = append(cfg.ScheduledTasks.Tasks, config.ScheduledTask{
+		ID:            "notify-task",
+		Name:          "Backup",
+		Type:          config.ScheduledTaskTypeBackup,
+		Frequency:     config.ScheduledTaskFrequencyDaily,
+		Notifications: []config.TaskNotification{{Type: config.TaskNotificationTypeGotify, URL: "https://gotify.example", Token: "gotify-token", OnFailure: true}},
+	})
+	cfg.Storage.Notifications = []config.TaskNotification{{Type: config.TaskNotificationTypeDiscord, URL: "https://discord.com/api/webhooks/1/secret", OnFailure: true}}
+	mgr := config.NewManager(filepath.Join(t.TempDir(), "settings.json"))
+	if err := mgr.Save(cfg); err != nil {
+		t.Fatalf("save settings: %v", err)
+	}
+
+	rec := httptest.NewRecorder()
+	NewSettingsHandler(mgr).GetSettings(rec, httptest.NewRequest(http.MethodGet, "/api/settings", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+	var got config.Settings
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	var task *config.ScheduledTask
+	for i := range got.ScheduledTasks.Tasks {
+		if got.ScheduledTasks.Tasks[i].ID == "notify-task" {
+			task = &got.ScheduledTasks.Tasks[i]
+		}
+	}
+	if task == nil || len(task.Notifications) != 1 {
+		t.Fatalf("expected the task's notification target, got %+v", task)
+	}
+	if target := task.Notifications[0]; target.URL != redactedPlaceholder || target.Token != redactedPlaceholder {
+		t.Errorf("task notification target not redacted: %+v", target)
+	}
+	if len(got.Storage.Notifications) != 1 || got.Storage.Notifications[0].URL != redactedPlaceholder {
+		t.Errorf("storage notification target not redacted: %+v", got.Storage.Notifications)
+	}
+
+	// Saving the redacted values back keeps the real ones.
+	preserveRedactedFields(&got, &cfg)
+	if target := task.Notifications[0]; target.URL != "https://gotify.example" || target.Token != "gotify-token" {
+		t.Errorf("task notification target not restored: %+v", target)
+	}
+	if got.Storage.Notifications[0].URL != "https://discord.com/api/webhooks/1/secret" {
+		t.Errorf("storage notification target not restored: %+v", got.Storage.Notifications)
+	}
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"novastream/models"
+)
+
+// smartSearchService answers natural language queries with discover filters.
+type smartSearchService interface {
+	SmartSearch(ctx context.Context, query string, limit int) (*models.SmartSearchResult, error)
+}
+
+// SmartSearch answers a spoken or typed query such as "90s sci-fi movies
+// with Keanu Reeves" by reading it into discover filters and running them.
+// The response carries what was understood alongside the titles, so
+// clients can show the filters back to the user.
+func (h *MetadataHandler) SmartSearch(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if strings.TrimSpace(q) == "" {
+		q = r.URL.Query().Get("query")
+	}
+	userID := strings.TrimSpace(r.URL.Query().Get("userId"))
+	limit := 0
+	if parsed, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && parsed > 0 {
+		limit = parsed
+	}
+	service := h.serviceForRequest(r, userID)
+	svc, ok := service.(smartSearchService)
+	if !ok {
+		writeJSONError(w, "smart search not supported", http.StatusNotImplemented)
+		return
+	}
+
+	if userID != "" && h.UsersService != nil {
+		if user, ok := h.UsersService.Get(userID); ok && user.IsKidsProfile && user.KidsMode == "content_list" {
+			// Search is disabled for curated-list profiles
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(models.SmartSearchResult{Query: strings.TrimSpace(q), Source: models.SmartSearchSourceSearch, Items: []models.TrendingItem{}})
+			return
+		}
+	}
+
+	result, err := svc.SmartSearch(r.Context(), q, limit)
+	if err != nil {
+		writeServiceError(w, err, http.StatusBadGateway)
+		return
+	}
+
+	result.Items = h.filterTrendingByAllowList(r, userID, service, result.Items)
+	result.Items = h.filterTrendingByRating(r, userID, service, result.Items)
+	if result.Items == nil {
+		result.Items = []models.TrendingItem{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.proxyArtwork(r, h.displayTitles(r, userID, result)))
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"novastream/models"
+)
+
+type fakeSmartSearchService struct {
+	*fakeMetadataService
+	lastQuery string
+	lastLimit int
+}
+
+func (f *fakeSmartSearchService) SmartSearch(_ context.Context, query string, limit int) (*models.SmartSearchResult, error) {
+	f.lastQuery, f.lastLimit = query, limit
+	return &models.SmartSearchResult{
+		Query:  query,
+		Parsed: models.SmartSearchParse{MediaType: "movie", Genres: []string{"Sci-Fi"}, YearFrom: 1990, YearTo: 1999},
+		Source: models.SmartSearchSourceDiscover,
+		Items:  []models.TrendingItem{{Rank: 1, Title: models.Title{ID: "tmdb:movie:603", Name: "The Matrix", MediaType: "movie"}}},
+	}, nil
+}
+
+func TestMetadataHandler_SmartSearch(t *testing.T) {
+	fake := &fakeSmartSearchService{fakeMetadataService: &fakeMetadataService{}}
+	handler := NewMetadataHandler(fake, testConfigManager(t))
+
+	rec := httptest.NewRecorder()
+	handler.SmartSearch(rec, httptest.NewRequest(http.MethodGet, "/api/search/smart?q=90s+sci-fi+movies&limit=10", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var result models.SmartSearchResult
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if result.Source != models.SmartSearchSourceDiscover || result.Parsed.YearFrom != 1990 || len(result.Items) != 1 || result.Items[0].Title.Name != "The Matrix" {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if fake.lastQuery != "90s sci-fi movies" || fake.lastLimit != 10 {
+		t.Fatalf("query=%q limit=%d, want %q and 10", fake.lastQuery, fake.lastLimit, "90s sci-fi movies")
+	}
+
+	rec = httptest.NewRecorder()
+	NewMetadataHandler(&fakeMetadataService{}, testConfigManager(t)).SmartSearch(rec, httptest.NewRequest(http.MethodGet, "/api/search/smart?q=matrix", nil))
+	if rec.Code != http.StatusNotImplemented {
+		t.Fatalf("expected 501 without smart search support, got %d", rec.Code)
+	}
+}
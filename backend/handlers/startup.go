@@ -360,7 +360,7 @@ func (h *StartupHandler) GetStartup(w http.ResponseWriter, r *http.Request) {
 						log.Printf("[startup] trending movies error: %v", err)
 						return
 					}
-					items = h.applyFilters(items, userID, hideUnreleased, hideWatched)
+					items = h.applyFilters(r, items, userID, hideUnreleased, hideWatched)
 					total := len(items)
 					if len(items) > startupPayloadLimit {
 						items = items[:startupPayloadLimit]
@@ -382,7 +382,7 @@ func (h *StartupHandler) GetStartup(w http.ResponseWriter, r *http.Request) {
 						log.Printf("[startup] trending series error: %v", err)
 						return
 					}
-					items = h.applyFilters(items, userID, hideUnreleased, hideWatched)
+					items = h.applyFilters(r, items, userID, hideUnreleased, hideWatched)
 					total := len(items)
 					if len(items) > startupPayloadLimit {
 						items = items[:startupPayloadLimit]
@@ -710,6 +710,8 @@ func homeShelfSourceKey(shelf models.ShelfConfig) string {
 		return fmt.Sprintf("letterboxd:%s:%s", shelf.LetterboxdListID, shelf.LetterboxdListURL)
 	case "genre", "decade", "collection-hub", "local-library":
 		return shelf.Type + ":" + shelf.ID
+	case "saved-filter":
+		return "saved-filter:" + shelf.SavedFilterID
 	default:
 		if strings.TrimSpace(shelf.ListURL) != "" {
 			return "mdblist:" + strings.TrimSpace(shelf.ListURL)
@@ -736,27 +738,17 @@ func hashForManifest(values ...interface{}) string {
 	return hex.EncodeToString(sum[:])
 }
 
-// applyFilters applies hideUnreleased, hideWatched, and kids rating filters to trending items.
-func (h *StartupHandler) applyFilters(items []models.TrendingItem, userID string, hideUnreleased, hideWatched bool) []models.TrendingItem {
+// applyFilters applies hideUnreleased, hideWatched, and profile rating filters to trending items.
+func (h *StartupHandler) applyFilters(r *http.Request, items []models.TrendingItem, userID string, hideUnreleased, hideWatched bool) []models.TrendingItem {
 	if hideUnreleased {
 		items = filterUnreleasedItems(items)
 	}
 	if hideWatched && userID != "" && h.history != nil {
 		items = filterWatchedItems(items, userID, h.history)
 	}
-	// Apply kids rating filter
-	if userID != "" && h.usersProvider != nil {
-		if user, ok := h.usersProvider.Get(userID); ok && user.IsKidsProfile {
-			if user.KidsMode == "rating" {
-				movieRating := user.KidsMaxMovieRating
-				tvRating := user.KidsMaxTVRating
-				if movieRating == "" && tvRating == "" && user.KidsMaxRating != "" {
-					movieRating = user.KidsMaxRating
-					tvRating = user.KidsMaxRating
-				}
-				items = kids.FilterTrendingByRatings(items, movieRating, tvRating)
-			}
-		}
+	// Apply the profile's rating limits
+	if movieRating, tvRating, ok := profileRatingLimits(r, h.usersProvider, h.cfgManager, userID); ok {
+		items = kids.FilterTrendingByRatings(items, movieRating, tvRating)
 	}
 	return items
 }
@@ -897,25 +889,26 @@ func slimTrendingItems(items []models.TrendingItem) []models.TrendingItem {
 		slim[i] = models.TrendingItem{
 			Rank: item.Rank,
 			Title: models.Title{
-				ID:            item.Title.ID,
-				Name:          item.Title.Name,
-				OriginalName:  item.Title.OriginalName,
-				Overview:      item.Title.Overview,
-				Year:          item.Title.Year,
-				Language:      item.Title.Language,
-				Poster:        item.Title.Poster,
-				TextPoster:    item.Title.TextPoster,
-				Backdrop:      item.Title.Backdrop,
-				TextBackdrop:  item.Title.TextBackdrop,
-				Backdrops:     item.Title.Backdrops,
-				MediaType:     item.Title.MediaType,
-				TVDBID:        item.Title.TVDBID,
-				IMDBID:        item.Title.IMDBID,
-				TMDBID:        item.Title.TMDBID,
-				Theatrical:    item.Title.Theatrical,
-				HomeRelease:   item.Title.HomeRelease,
-				Certification: item.Title.Certification,
-				Genres:        item.Title.Genres,
+				ID:                   item.Title.ID,
+				Name:                 item.Title.Name,
+				OriginalName:         item.Title.OriginalName,
+				Overview:             item.Title.Overview,
+				Year:                 item.Title.Year,
+				Language:             item.Title.Language,
+				Poster:               item.Title.Poster,
+				TextPoster:           item.Title.TextPoster,
+				Backdrop:             item.Title.Backdrop,
+				TextBackdrop:         item.Title.TextBackdrop,
+				Backdrops:            item.Title.Backdrops,
+				MediaType:            item.Title.MediaType,
+				TVDBID:               item.Title.TVDBID,
+				IMDBID:               item.Title.IMDBID,
+				TMDBID:               item.Title.TMDBID,
+				Theatrical:           item.Title.Theatrical,
+				HomeRelease:          item.Title.HomeRelease,
+				Certification:        item.Title.Certification,
+				CertificationCountry: item.Title.CertificationCountry,
+				Genres:               item.Title.Genres,
 			},
 		}
 	}
@@ -972,6 +965,7 @@ func (h *StartupHandler) getDefaultsFromGlobal() models.UserSettings {
 			CreditsDetectionEnabled:       models.BoolPtr(globalSettings.Playback.CreditsDetectionEnabled),
 			CreditsAutoSkip:               globalSettings.Playback.CreditsAutoSkip || globalSettings.Playback.CreditsDetection,
 			MatchFrameRate:                models.BoolPtr(globalSettings.Playback.MatchFrameRate),
+			DisableAutoPlayNext:           models.BoolPtr(globalSettings.Playback.DisableAutoPlayNext),
 			MaxResultsPerResolution:       models.IntPtr(globalSettings.Playback.MaxResultsPerResolution),
 		},
 		HomeShelves: models.HomeShelvesSettings{
@@ -1001,6 +995,7 @@ func (h *StartupHandler) getDefaultsFromGlobal() models.UserSettings {
 			BypassFilteringForAIOStreamsOnly: models.BoolPtr(globalSettings.Display.BypassFilteringForAIOStreamsOnly),
 			DisableMobileTopCarousel:         models.BoolPtr(globalSettings.Display.DisableMobileTopCarousel),
 			AppLanguage:                      globalSettings.Display.AppLanguage,
+			LandingTab:                       globalSettings.Display.LandingTab,
 			Appearance: models.AppearanceSettings{
 				FontScale:            globalSettings.Display.Appearance.FontScale,
 				AccentColor:          globalSettings.Display.Appearance.AccentColor,
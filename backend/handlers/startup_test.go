@@ -82,6 +82,9 @@ func (m *mockHistoryService) RecordEpisode(userID string, payload models.Episode
 func (m *mockHistoryService) ListContinueWatching(userID string) ([]models.SeriesWatchState, error) {
 	return m.continueWatching, m.cwErr
 }
+func (m *mockHistoryService) ContinueWatchingShelf(userID string) ([]models.ContinueWatchingShelfItem, error) {
+	return nil, nil
+}
 func (m *mockHistoryService) GetContinueWatchingRevision(userID string) (string, error) {
 	return m.revision, nil
 }
@@ -217,7 +220,7 @@ func (m *mockMetadataServiceStartup) DiscoverByGenre(context.Context, string, in
 func (m *mockMetadataServiceStartup) DiscoverByDecade(context.Context, string, int, int, int) ([]models.TrendingItem, int, error) {
 	return nil, 0, nil
 }
-func (m *mockMetadataServiceStartup) GetAIRecommendations(context.Context, []string, []string, string) ([]models.TrendingItem, error) {
+func (m *mockMetadataServiceStartup) GetAIRecommendations(context.Context, []string, []string, string, bool) ([]models.TrendingItem, error) {
 	return nil, nil
 }
 func (m *mockMetadataServiceStartup) GetAISimilar(context.Context, string, string) ([]models.TrendingItem, error) {
@@ -260,6 +263,14 @@ func (m *mockMetadataServiceStartup) ServePrequeuedTrailer(_ string, _ http.Resp
 	return nil
 }
 
+func (m *mockMetadataServiceStartup) TrailerPreviews() []metadatapkg.TrailerPreview {
+	return nil
+}
+
+func (m *mockMetadataServiceStartup) ServeTrailerPreview(_ string, _ http.ResponseWriter, _ *http.Request) error {
+	return nil
+}
+
 func (m *mockMetadataServiceStartup) EnrichSearchCertifications(_ context.Context, _ []models.SearchResult) {
 }
 
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"novastream/services/storage"
+)
+
+type storageMonitor interface {
+	Status() []storage.VolumeStatus
+	Check() time.Duration
+}
+
+// StorageHandler reports free space on the monitored volumes.
+type StorageHandler struct {
+	monitor storageMonitor
+}
+
+// NewStorageHandler creates a storage handler.
+func NewStorageHandler(monitor storageMonitor) *StorageHandler {
+	return &StorageHandler{monitor: monitor}
+}
+
+// GetStatus returns the last free space check for each volume. ?refresh=true
+// checks again first.
+// GET /admin/api/storage
+func (h *StorageHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	if h.monitor == nil {
+		writeJSONError(w, "storage monitor not available", http.StatusServiceUnavailable)
+		return
+	}
+	if r.URL.Query().Get("refresh") == "true" {
+		h.monitor.Check()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"volumes": h.monitor.Status(),
+	})
+}
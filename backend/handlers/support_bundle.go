@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"novastream/config"
+)
+
+// supportBundleLogLines bounds how much of each log goes into a support
+// bundle; the bundle is meant to be attached to an issue.
+const supportBundleLogLines = 5000
+
+const supportBundleReadme = `mediastorm support bundle
+
+diagnostics.json   versions, runtime and subsystem status when the bundle was made
+settings.json      server settings with passwords, API keys, tokens and
+                   notification targets removed
+logs/backend.log   the most recent backend log lines
+logs/frontend.log  the most recent log lines uploaded by apps
+
+Secrets that look like tokens are redacted from the logs, but please skim
+them before attaching the bundle to a public issue.
+`
+
+// supportBundleDiagnostics is the diagnostics.json snapshot.
+type supportBundleDiagnostics struct {
+	GeneratedAt time.Time              `json:"generatedAt"`
+	Version     string                 `json:"version"`
+	BuildID     string                 `json:"buildId"`
+	Runtime     PerformanceMetrics     `json:"runtime"`
+	Log         config.LogConfig       `json:"log"`
+	Frontends   []frontendLogSummary   `json:"frontends,omitempty"`
+	Subsystems  map[string]interface{} `json:"subsystems,omitempty"`
+	Errors      []string               `json:"errors,omitempty"`
+}
+
+// SetConfigManager lets support bundles include the redacted settings.
+func (h *LogsHandler) SetConfigManager(cfgManager *config.Manager) {
+	h.configManager = cfgManager
+}
+
+// AddDiagnostics registers a named status snapshot to include in support
+// bundles, e.g. the storage monitor or background worker status.
+func (h *LogsHandler) AddDiagnostics(name string, fn func() interface{}) {
+	if h.diagnostics == nil {
+		h.diagnostics = make(map[string]func() interface{})
+	}
+	h.diagnostics[name] = fn
+}
+
+// SupportBundle downloads a zip of recent logs, redacted settings and a
+// diagnostics snapshot to attach to bug reports.
+// GET /admin/api/support-bundle
+func (h *LogsHandler) SupportBundle(w http.ResponseWriter, r *http.Request) {
+	now := time.Now().UTC()
+	data, err := h.buildSupportBundle(now)
+	if err != nil {
+		h.respondError(w, fmt.Sprintf("failed to build support bundle: %v", err), http.StatusInternalServerError)
+		return
+	}
+	filename := "mediastorm-support-" + now.Format("20060102-150405") + ".zip"
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="`+filename+`"`)
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(data)
+}
+
+func (h *LogsHandler) buildSupportBundle(now time.Time) ([]byte, error) {
+	diag := supportBundleDiagnostics{
+		GeneratedAt: now,
+		Version:     GetBackendVersion(),
+		BuildID:     GetBackendBuildID(),
+		Runtime:     buildPerformanceMetrics(),
+	}
+	if len(h.diagnostics) > 0 {
+		diag.Subsystems = make(map[string]interface{}, len(h.diagnostics))
+		for name, fn := range h.diagnostics {
+			diag.Subsystems[name] = fn()
+		}
+	}
+
+	files := make(map[string][]byte)
+
+	if h.configManager != nil {
+		if settings, err := h.configManager.Load(); err != nil {
+			diag.Errors = append(diag.Errors, "settings: "+err.Error())
+		} else {
+			diag.Log = settings.Log
+			redactSettings(&settings)
+			if data, err := json.MarshalIndent(settings, "", "  "); err == nil {
+				files["settings.json"] = data
+			}
+		}
+	}
+
+	backendLines, err := h.readBackendLogEntries(supportBundleLogLines)
+	if err != nil {
+		diag.Errors = append(diag.Errors, "backend log: "+err.Error())
+	}
+	var backend strings.Builder
+	for _, entry := range backendLines {
+		backend.WriteString(entry.Line)
+		backend.WriteByte('\n')
+	}
+	files["logs/backend.log"] = []byte(redactLogUploadContent(backend.String()))
+
+	frontend, summaries, err := h.readAggregatedFrontendLogs(supportBundleLogLines, "")
+	if err != nil {
+		diag.Errors = append(diag.Errors, "frontend logs: "+err.Error())
+	}
+	diag.Frontends = summaries
+	files["logs/frontend.log"] = []byte(redactLogUploadContent(frontend))
+
+	diagJSON, err := json.MarshalIndent(diag, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	files["diagnostics.json"] = diagJSON
+	files["README.txt"] = []byte(supportBundleReadme)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"README.txt", "diagnostics.json", "settings.json", "logs/backend.log", "logs/frontend.log"} {
+		content, ok := files[name]
+		if !ok {
+			continue
+		}
+		f, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := f.Write(content); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"novastream/models"
+)
+
+var (
+	titleType         = reflect.TypeOf(models.Title{})
+	seriesDetailsType = reflect.TypeOf(models.SeriesDetails{})
+)
+
+// titleDisplay is a profile's choice of title names.
+type titleDisplay struct {
+	original    bool   // name titles in their original language
+	animeScript string // "romaji", "native" or empty to follow original
+}
+
+func (d titleDisplay) isDefault() bool {
+	return !d.original && d.animeScript == ""
+}
+
+// resolveTitleDisplay returns the title display preferences of the profile.
+// Unknown values keep the translated names.
+func resolveTitleDisplay(userSettings userSettingsProvider, userID string) titleDisplay {
+	if userSettings == nil || strings.TrimSpace(userID) == "" {
+		return titleDisplay{}
+	}
+	profileSettings, err := userSettings.Get(userID)
+	if err != nil || profileSettings == nil {
+		return titleDisplay{}
+	}
+	display := titleDisplay{
+		original: strings.EqualFold(strings.TrimSpace(profileSettings.Metadata.TitleLanguage), "original"),
+	}
+	switch script := strings.ToLower(strings.TrimSpace(profileSettings.Metadata.AnimeTitles)); script {
+	case "romaji", "native":
+		display.animeScript = script
+	}
+	return display
+}
+
+// displayTitles renames the titles of a metadata response by the profile's
+// title display preferences. Like proxyArtwork it rewrites a copy, so cached
+// values keep their translated names.
+func (h *MetadataHandler) displayTitles(r *http.Request, userID string, payload any) any {
+	return applyTitleDisplay(payload, resolveTitleDisplay(h.UserSettings, userID))
+}
+
+// applyTitleDisplay returns a copy of payload with every models.Title named
+// by display. Series details carrying AniList data use its romaji or native
+// title; other titles switch to OriginalName, keeping the translated name as
+// an alternate title so search and matching still find it.
+func applyTitleDisplay(payload any, display titleDisplay) any {
+	if display.isDefault() {
+		return payload
+	}
+	copied, ok := copyPayload(payload)
+	if !ok {
+		return payload
+	}
+	walkTitles(copied, func(title *models.Title, anime *models.AnimeDetails) {
+		name := ""
+		if anime != nil {
+			switch display.animeScript {
+			case "romaji":
+				name = anime.TitleRomaji
+			case "native":
+				name = anime.TitleNative
+			}
+		}
+		if name == "" && display.original {
+			name = title.OriginalName
+		}
+		name = strings.TrimSpace(name)
+		if name == "" || name == title.Name {
+			return
+		}
+		if title.Name != "" && !containsFold(title.AlternateTitles, title.Name) {
+			title.AlternateTitles = append([]string{title.Name}, title.AlternateTitles...)
+		}
+		title.Name = name
+	})
+	return copied.Interface()
+}
+
+// walkTitles calls fn for every models.Title reachable from v, passing the
+// AniList data of the series details a title belongs to, if any.
+func walkTitles(v reflect.Value, fn func(*models.Title, *models.AnimeDetails)) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			walkTitles(v.Elem(), fn)
+		}
+	case reflect.Struct:
+		if !v.CanAddr() {
+			return
+		}
+		switch v.Type() {
+		case titleType:
+			fn(v.Addr().Interface().(*models.Title), nil)
+			return
+		case seriesDetailsType:
+			details := v.Addr().Interface().(*models.SeriesDetails)
+			fn(&details.Title, details.Anime)
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				walkTitles(v.Field(i), fn)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkTitles(v.Index(i), fn)
+		}
+	case reflect.Map:
+		// Map values are not addressable: rewrite a copy and store it back.
+		iter := v.MapRange()
+		for iter.Next() {
+			value := reflect.New(iter.Value().Type()).Elem()
+			value.Set(iter.Value())
+			walkTitles(value, fn)
+			v.SetMapIndex(iter.Key(), value)
+		}
+	}
+}
+
+func containsFold(values []string, target string) bool {
+	for _, value := range values {
+		if strings.EqualFold(value, target) {
+			return true
+		}
+	}
+	return false
+}
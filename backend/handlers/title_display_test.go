@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+
+	"novastream/models"
+)
+
+func TestResolveTitleDisplay(t *testing.T) {
+	settings := &mockUserSettingsProvider{settings: map[string]*models.UserSettings{
+		"u1": {Metadata: models.MetadataSettings{TitleLanguage: "Original", AnimeTitles: "kanji"}},
+	}}
+	if got := resolveTitleDisplay(settings, "u1"); got != (titleDisplay{original: true}) {
+		t.Fatalf("unexpected display %+v", got)
+	}
+	if got := resolveTitleDisplay(settings, ""); !got.isDefault() {
+		t.Fatalf("expected the default without a profile, got %+v", got)
+	}
+}
+
+func TestApplyTitleDisplay(t *testing.T) {
+	amelie := models.Title{Name: "Amélie", OriginalName: "Le Fabuleux Destin d'Amélie Poulain"}
+	series := &models.SeriesDetails{
+		Title: models.Title{Name: "Attack on Titan", OriginalName: "進撃の巨人"},
+		Anime: &models.AnimeDetails{TitleRomaji: "Shingeki no Kyojin", TitleNative: "進撃の巨人"},
+	}
+	payload := DiscoverNewResponse{Items: []models.TrendingItem{{Title: amelie}}}
+
+	if got := applyTitleDisplay(payload, titleDisplay{}); !reflect.DeepEqual(got, payload) {
+		t.Fatalf("expected the payload unchanged by default, got %+v", got)
+	}
+
+	got := applyTitleDisplay(payload, titleDisplay{original: true}).(DiscoverNewResponse)
+	if title := got.Items[0].Title; title.Name != amelie.OriginalName || !reflect.DeepEqual(title.AlternateTitles, []string{"Amélie"}) {
+		t.Fatalf("expected the original title with the translation kept, got %+v", title)
+	}
+	if payload.Items[0].Title.Name != "Amélie" {
+		t.Fatal("expected the input payload to be left untouched")
+	}
+
+	romaji := applyTitleDisplay(series, titleDisplay{animeScript: "romaji"}).(*models.SeriesDetails)
+	if romaji.Title.Name != "Shingeki no Kyojin" {
+		t.Fatalf("expected the romaji title, got %q", romaji.Title.Name)
+	}
+	native := applyTitleDisplay(series, titleDisplay{original: true}).(*models.SeriesDetails)
+	if native.Title.Name != "進撃の巨人" {
+		t.Fatalf("expected the original title, got %q", native.Title.Name)
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"novastream/services/trakt"
+)
+
+// traktProfileAccount is the shelf account ID that stands for whichever
+// Trakt account the requesting profile has linked. It is only meaningful for
+// recommendation shelves.
+const traktProfileAccount = "__profile__"
+
+// fetchTraktRecommendations returns the account's Trakt recommendations.
+// ListedAt carries the rank (negated, as shelves sort newest first) so the
+// shelf keeps Trakt's order, alternating movies and shows.
+func (h *MetadataHandler) fetchTraktRecommendations(accessToken string) ([]traktShelfSourceItem, error) {
+	movies, err := h.TraktClient.GetRecommendedMovies(accessToken)
+	if err != nil {
+		return nil, err
+	}
+	shows, err := h.TraktClient.GetRecommendedShows(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]traktShelfSourceItem, 0, len(movies)+len(shows))
+	for i, movie := range movies {
+		ids := trakt.IDsToMap(movie.IDs)
+		items = append(items, traktShelfSourceItem{
+			Title:     movie.Title,
+			Year:      movie.Year,
+			MediaType: "movie",
+			IMDBID:    ids["imdb"],
+			TMDBID:    ids["tmdb"],
+			TVDBID:    ids["tvdb"],
+			TraktID:   ids["trakt"],
+			ListedAt:  -int64(2 * i),
+		})
+	}
+	for i, show := range shows {
+		ids := trakt.IDsToMap(show.IDs)
+		items = append(items, traktShelfSourceItem{
+			Title:     show.Title,
+			Year:      show.Year,
+			MediaType: "series",
+			IMDBID:    ids["imdb"],
+			TMDBID:    ids["tmdb"],
+			TVDBID:    ids["tvdb"],
+			TraktID:   ids["trakt"],
+			ListedAt:  -int64(2*i + 1),
+		})
+	}
+	return items, nil
+}
+
+type hideTraktRecommendationRequest struct {
+	AccountID string `json:"accountId,omitempty"` // defaults to the profile's linked account
+	MediaType string `json:"mediaType"`           // movie | series
+	IMDBID    string `json:"imdbId,omitempty"`
+	TraktID   int64  `json:"traktId,omitempty"`
+}
+
+// HideTraktRecommendation dismisses a title from a profile's Trakt
+// recommendations shelf. The dismissal is written back to Trakt, so the title
+// stops being recommended everywhere the account is used.
+func (h *MetadataHandler) HideTraktRecommendation(w http.ResponseWriter, r *http.Request) {
+	if h.TraktClient == nil {
+		writeJSONError(w, "trakt client unavailable", http.StatusInternalServerError)
+		return
+	}
+	if h.UsersService == nil {
+		writeJSONError(w, "users service unavailable", http.StatusInternalServerError)
+		return
+	}
+
+	userID := strings.TrimSpace(r.URL.Query().Get("userId"))
+	if userID == "" {
+		writeJSONError(w, "userId parameter required", http.StatusBadRequest)
+		return
+	}
+	user, ok := h.UsersService.Get(userID)
+	if !ok {
+		writeJSONError(w, "user not found", http.StatusNotFound)
+		return
+	}
+
+	var body hideTraktRecommendationRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSONError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	var traktType string
+	switch strings.ToLower(strings.TrimSpace(body.MediaType)) {
+	case "movie":
+		traktType = "movies"
+	case "series", "show", "tv":
+		traktType = "shows"
+	default:
+		writeJSONError(w, "mediaType must be movie or series", http.StatusBadRequest)
+		return
+	}
+	// Trakt identifies recommendations by Trakt ID, slug or IMDB ID.
+	id := strings.TrimSpace(body.IMDBID)
+	if body.TraktID > 0 {
+		id = strconv.FormatInt(body.TraktID, 10)
+	}
+	if id == "" {
+		writeJSONError(w, "imdbId or traktId is required", http.StatusBadRequest)
+		return
+	}
+
+	accountID := strings.TrimSpace(body.AccountID)
+	if accountID == "" || accountID == traktProfileAccount {
+		accountID = user.TraktAccountID
+	}
+	if accountID == "" || accountID == "__all__" {
+		writeJSONError(w, "profile has no linked trakt account", http.StatusBadRequest)
+		return
+	}
+
+	settings, err := h.CfgManager.Load()
+	if err != nil {
+		writeJSONError(w, "failed to load settings", http.StatusInternalServerError)
+		return
+	}
+	accounts, err := h.resolveTraktShelfAccounts(user, settings, accountID)
+	if err != nil {
+		status := http.StatusForbidden
+		if strings.Contains(err.Error(), "not found") {
+			status = http.StatusNotFound
+		}
+		writeJSONError(w, err.Error(), status)
+		return
+	}
+	account := accounts[0]
+	accessToken, err := h.TraktClient.EnsureValidToken(&account, h.CfgManager)
+	if err != nil || accessToken == "" {
+		writeJSONError(w, "trakt account is not connected", http.StatusBadGateway)
+		return
+	}
+	h.TraktClient.UpdateCredentials(account.ClientID, account.ClientSecret)
+
+	// A recommendation Trakt no longer knows about is already dismissed.
+	if err := h.TraktClient.HideRecommendation(accessToken, traktType, id); err != nil && !errors.Is(err, trakt.ErrNotFound) {
+		writeServiceError(w, err, http.StatusBadGateway)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
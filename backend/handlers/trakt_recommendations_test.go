@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"novastream/config"
+	"novastream/models"
+	"novastream/services/trakt"
+)
+
+func newTraktRecommendationsHandler(t *testing.T, fake *fakeMetadataService, transport roundTripFunc) *MetadataHandler {
+	t.Helper()
+	origURL := trakt.GetBaseURLForTest()
+	t.Cleanup(func() { trakt.SetBaseURLForTest(origURL) })
+	trakt.SetBaseURLForTest("https://trakt.test")
+
+	mgr := testConfigManager(t)
+	settings := config.DefaultSettings()
+	settings.Trakt.Accounts = []config.TraktAccount{
+		{
+			ID:             "trakt-1",
+			Name:           "Main Trakt",
+			OwnerAccountID: "acct-1",
+			ClientID:       "client-id",
+			ClientSecret:   "client-secret",
+			AccessToken:    "access-token",
+			ExpiresAt:      time.Now().Add(24 * time.Hour).Unix(),
+		},
+	}
+	if err := mgr.Save(settings); err != nil {
+		t.Fatalf("save settings: %v", err)
+	}
+
+	handler := NewMetadataHandler(fake, mgr)
+	traktClient := trakt.NewClient("client-id", "client-secret")
+	traktClient.SetHTTPClientForTest(&http.Client{Transport: transport})
+	handler.SetTraktClient(traktClient)
+	handler.SetUsersService(&fakeUsersServiceForSearch{
+		users: map[string]models.User{
+			"linked":   {ID: "linked", AccountID: "acct-1", Name: "Linked", TraktAccountID: "trakt-1"},
+			"unlinked": {ID: "unlinked", AccountID: "acct-1", Name: "Unlinked"},
+		},
+	})
+	handler.SetAccountsService(&fakeAccountsServiceForMetadata{
+		accounts: map[string]models.Account{
+			"acct-1": {ID: "acct-1", Username: "account"},
+		},
+	})
+	return handler
+}
+
+func jsonResponse(body string) *http.Response {
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(body))}
+}
+
+func TestMetadataHandler_TraktListRecommendationsUsesLinkedAccount(t *testing.T) {
+	fake := &fakeMetadataService{
+		curatedResp: []models.TrendingItem{
+			{Rank: 1, Title: models.Title{ID: "movie:329865", Name: "Arrival", MediaType: "movie"}},
+		},
+	}
+	handler := newTraktRecommendationsHandler(t, fake, func(r *http.Request) (*http.Response, error) {
+		switch r.URL.Path {
+		case "/recommendations/movies":
+			return jsonResponse(`[{"title":"Arrival","year":2016,"ids":{"trakt":1,"imdb":"tt2543164"}},{"title":"Heat","year":1995,"ids":{"trakt":3,"imdb":"tt0113277"}}]`), nil
+		case "/recommendations/shows":
+			return jsonResponse(`[{"title":"Severance","year":2022,"ids":{"trakt":2,"imdb":"tt11280740"}}]`), nil
+		}
+		t.Fatalf("unexpected trakt path %s", r.URL.Path)
+		return nil, nil
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/lists/trakt?userId=linked&accountId=__profile__&listType=recommendations", nil)
+	rec := httptest.NewRecorder()
+	handler.TraktList(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	if fake.lastCuratedLabel != "Trakt Recommendations" {
+		t.Fatalf("unexpected label %q", fake.lastCuratedLabel)
+	}
+	var got []string
+	for _, item := range fake.lastCuratedItems {
+		got = append(got, item.IMDBID)
+	}
+	// Trakt's ranking is kept, alternating movies and shows.
+	if strings.Join(got, ",") != "tt2543164,tt11280740,tt0113277" {
+		t.Fatalf("unexpected curated order %v", got)
+	}
+
+	// A profile without a linked account gets an empty shelf.
+	rec = httptest.NewRecorder()
+	handler.TraktList(rec, httptest.NewRequest(http.MethodGet, "/api/lists/trakt?userId=unlinked&listType=recommendations", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected %d for unlinked profile, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var payload TraktShelfResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload.Total != 0 || len(payload.Items) != 0 {
+		t.Fatalf("expected empty shelf, got %+v", payload)
+	}
+}
+
+func TestMetadataHandler_HideTraktRecommendation(t *testing.T) {
+	var hidden []string
+	handler := newTraktRecommendationsHandler(t, &fakeMetadataService{}, func(r *http.Request) (*http.Response, error) {
+		if r.Method != http.MethodDelete {
+			t.Fatalf("expected DELETE, got %s", r.Method)
+		}
+		hidden = append(hidden, r.URL.Path)
+		return &http.Response{StatusCode: http.StatusNoContent, Header: http.Header{}, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/lists/trakt/recommendations/hide?userId=linked", strings.NewReader(`{"mediaType":"series","imdbId":"tt11280740"}`))
+	rec := httptest.NewRecorder()
+	handler.HideTraktRecommendation(rec, req)
+
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected %d, got %d: %s", http.StatusNoContent, rec.Code, rec.Body.String())
+	}
+	if len(hidden) != 1 || hidden[0] != "/recommendations/shows/tt11280740" {
+		t.Fatalf("unexpected trakt calls %v", hidden)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/lists/trakt/recommendations/hide?userId=unlinked", strings.NewReader(`{"mediaType":"movie","imdbId":"tt2543164"}`))
+	rec = httptest.NewRecorder()
+	handler.HideTraktRecommendation(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected %d for unlinked profile, got %d", http.StatusBadRequest, rec.Code)
+	}
+}
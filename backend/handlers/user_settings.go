@@ -11,6 +11,7 @@ import (
 	"novastream/config"
 	"novastream/models"
 	user_settings "novastream/services/user_settings"
+	"novastream/utils/locale"
 
 	"github.com/gorilla/mux"
 )
@@ -73,6 +74,47 @@ func (h *UserSettingsHandler) GetSettings(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode(settings)
 }
 
+// localeResponse reports the locale server-written text uses for the
+// instance and for one profile.
+type localeResponse struct {
+	Instance locale.Locale `json:"instance"`
+	Profile  locale.Locale `json:"profile"`
+}
+
+// GetLocale returns the instance locale and the profile's effective locale,
+// which inherits any value the profile leaves unset.
+func (h *UserSettingsHandler) GetLocale(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+
+	var display config.DisplaySettings
+	if h.ConfigManager != nil {
+		globalSettings, err := h.ConfigManager.Load()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		display = globalSettings.Display
+	}
+	profile, err := h.Service.Get(userID)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var profileDisplay models.DisplaySettings
+	if profile != nil {
+		profileDisplay = profile.Display
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(localeResponse{
+		Instance: locale.Instance(display),
+		Profile:  locale.Profile(profileDisplay, display),
+	})
+}
+
 // PutSettings updates the user's settings.
 func (h *UserSettingsHandler) PutSettings(w http.ResponseWriter, r *http.Request) {
 	userID, ok := h.requireUser(w, r)
@@ -151,7 +193,9 @@ func (h *UserSettingsHandler) getDefaultsFromGlobal() models.UserSettings {
 
 	return models.UserSettings{
 		Metadata: models.MetadataSettings{
-			PrimaryLanguage: globalSettings.Metadata.EffectivePrimaryLanguage(),
+			PrimaryLanguage:      globalSettings.Metadata.EffectivePrimaryLanguage(),
+			CertificationCountry: globalSettings.Metadata.CertificationCountry,
+			Artwork:              globalSettings.Metadata.Artwork,
 		},
 		Playback: models.PlaybackSettings{
 			PreferredPlayer:            globalSettings.Playback.PreferredPlayer,
@@ -182,6 +226,7 @@ func (h *UserSettingsHandler) getDefaultsFromGlobal() models.UserSettings {
 			CreditsDetectionEnabled:    models.BoolPtr(globalSettings.Playback.CreditsDetectionEnabled),
 			CreditsAutoSkip:            globalSettings.Playback.CreditsAutoSkip || globalSettings.Playback.CreditsDetection,
 			MatchFrameRate:             models.BoolPtr(globalSettings.Playback.MatchFrameRate),
+			DisableAutoPlayNext:        models.BoolPtr(globalSettings.Playback.DisableAutoPlayNext),
 			MaxResultsPerResolution:    models.IntPtr(globalSettings.Playback.MaxResultsPerResolution),
 		},
 		HomeShelves: models.HomeShelvesSettings{
@@ -212,6 +257,9 @@ func (h *UserSettingsHandler) getDefaultsFromGlobal() models.UserSettings {
 			BypassFilteringForAIOStreamsOnly: models.BoolPtr(globalSettings.Display.BypassFilteringForAIOStreamsOnly),
 			DisableMobileTopCarousel:         models.BoolPtr(globalSettings.Display.DisableMobileTopCarousel),
 			AppLanguage:                      globalSettings.Display.AppLanguage,
+			LandingTab:                       globalSettings.Display.LandingTab,
+			DateFormat:                       globalSettings.Display.DateFormat,
+			TimeFormat:                       globalSettings.Display.TimeFormat,
 			Appearance: models.AppearanceSettings{
 				FontScale:            globalSettings.Display.Appearance.FontScale,
 				AccentColor:          globalSettings.Display.Appearance.AccentColor,
@@ -245,6 +293,7 @@ func convertShelves(configShelves []config.ShelfConfig) []models.ShelfConfig {
 			Order:                  s.Order,
 			Type:                   s.Type,
 			ListURL:                s.ListURL,
+			TrendingSource:         s.TrendingSource,
 			StreamingServices:      convertStreamingServices(s.StreamingServices),
 			CollectionItems:        convertCollectionHubItems(s.CollectionItems),
 			TraktAccountID:         s.TraktAccountID,
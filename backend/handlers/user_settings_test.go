@@ -25,6 +25,7 @@ type fakeUserSettingsService struct {
 	getWithDefaultsErr error
 	lastDefaults       models.UserSettings
 	updateErr          error
+	updated            *models.UserSettings
 	deleteErr          error
 }
 
@@ -38,6 +39,7 @@ func (f *fakeUserSettingsService) GetWithDefaults(userID string, defaults models
 }
 
 func (f *fakeUserSettingsService) Update(userID string, settings models.UserSettings) error {
+	f.updated = &settings
 	return f.updateErr
 }
 
@@ -162,6 +164,44 @@ func TestUserSettingsHandler_PutSettings_Success(t *testing.T) {
 	}
 }
 
+func TestUserSettingsHandler_PutLiveLineup_KeepsOtherSettings(t *testing.T) {
+	settingsSvc := &fakeUserSettingsService{getSettings: &models.UserSettings{
+		Playback: models.PlaybackSettings{PreferredPlayer: "vlc"},
+		LiveTV:   models.LiveTVSettings{HiddenChannels: []string{"kids"}},
+	}}
+	h := handlers.NewUserSettingsHandler(settingsSvc, &fakeUserExistsService{exists: true}, config.NewManager(t.TempDir()))
+
+	body := map[string]any{"favoriteChannels": []string{" news ", "sports", "news", ""}}
+	r := userSettingsRequest(http.MethodPut, "/", body, map[string]string{"userID": "u1"})
+	w := httptest.NewRecorder()
+	h.PutLiveLineup(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body=%s", w.Code, w.Body.String())
+	}
+	saved := settingsSvc.updated
+	if saved == nil {
+		t.Fatal("expected settings to be saved")
+	}
+	if saved.Playback.PreferredPlayer != "vlc" {
+		t.Fatalf("playback settings were not preserved: %+v", saved.Playback)
+	}
+	if got := saved.LiveTV.FavoriteChannels; len(got) != 2 || got[0] != "news" || got[1] != "sports" {
+		t.Fatalf("favorites = %v, want [news sports]", got)
+	}
+	if got := saved.LiveTV.HiddenChannels; len(got) != 1 || got[0] != "kids" {
+		t.Fatalf("hidden channels = %v, want unchanged [kids]", got)
+	}
+
+	var lineup handlers.LiveLineup
+	if err := json.NewDecoder(w.Body).Decode(&lineup); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(lineup.ChannelOrder) != 0 || len(lineup.FavoriteChannels) != 2 {
+		t.Fatalf("unexpected lineup response: %+v", lineup)
+	}
+}
+
 func TestUserSettingsHandler_PutSettings_InvalidJSON(t *testing.T) {
 	settingsSvc := &fakeUserSettingsService{}
 	usersSvc := &fakeUserExistsService{exists: true}
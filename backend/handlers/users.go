@@ -9,6 +9,7 @@ import (
 
 	"novastream/internal/auth"
 	"novastream/models"
+	"novastream/services/kids"
 	"novastream/services/users"
 
 	"github.com/gorilla/mux"
@@ -46,7 +47,9 @@ type usersService interface {
 	SetKidsMaxRating(id, rating string) (models.User, error)
 	SetKidsMaxMovieRating(id, rating string) (models.User, error)
 	SetKidsMaxTVRating(id, rating string) (models.User, error)
+	SetRatingCeiling(id, movieRating, tvRating string) (models.User, error)
 	SetKidsAllowedLists(id string, lists []string) (models.User, error)
+	SetKidsAllowedGenres(id string, genres []string) (models.User, error)
 	AddKidsAllowedList(id, listURL string) (models.User, error)
 	RemoveKidsAllowedList(id, listURL string) (models.User, error)
 }
@@ -895,7 +898,7 @@ func (h *UsersHandler) SetKidsMode(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var body struct {
-		Mode string `json:"mode"` // "rating", "content_list", or ""
+		Mode string `json:"mode"` // "rating", "content_list", "allow_list", or ""
 	}
 	dec := json.NewDecoder(r.Body)
 	dec.DisallowUnknownFields()
@@ -905,9 +908,8 @@ func (h *UsersHandler) SetKidsMode(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate mode
-	validModes := map[string]bool{"": true, "rating": true, "content_list": true}
-	if !validModes[body.Mode] {
-		http.Error(w, "invalid mode, must be 'rating', 'content_list', or empty", http.StatusBadRequest)
+	if !kids.ValidateKidsMode(body.Mode) {
+		http.Error(w, "invalid mode, must be 'rating', 'content_list', 'allow_list', or empty", http.StatusBadRequest)
 		return
 	}
 
@@ -1042,6 +1044,49 @@ func (h *UsersHandler) SetKidsMaxTVRating(w http.ResponseWriter, r *http.Request
 	json.NewEncoder(w).Encode(user)
 }
 
+// SetRatingCeiling sets the maximum movie and TV ratings a profile may see.
+func (h *UsersHandler) SetRatingCeiling(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := strings.TrimSpace(vars["userID"])
+	if id == "" {
+		http.Error(w, "user id is required", http.StatusBadRequest)
+		return
+	}
+
+	// Verify caller can configure this profile
+	if !h.canConfigureKidsProfile(r, id) {
+		http.Error(w, "cannot configure parental controls for this profile", http.StatusForbidden)
+		return
+	}
+
+	var body struct {
+		MovieRating string `json:"movieRating"` // G, PG, PG-13, R, NC-17 or empty
+		TVRating    string `json:"tvRating"`    // TV-Y, TV-Y7, TV-G, TV-PG, TV-14, TV-MA or empty
+	}
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.Service.SetRatingCeiling(id, body.MovieRating, body.TVRating)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, users.ErrUserNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, users.ErrInvalidRating):
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
 // SetKidsAllowedLists replaces the allowed lists for a kids profile.
 func (h *UsersHandler) SetKidsAllowedLists(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -1058,7 +1103,7 @@ func (h *UsersHandler) SetKidsAllowedLists(w http.ResponseWriter, r *http.Reques
 	}
 
 	var body struct {
-		Lists []string `json:"lists"` // MDBList URLs
+		Lists []string `json:"lists"` // MDBList URLs, or TMDB list URLs for allow_list mode
 	}
 	dec := json.NewDecoder(r.Body)
 	dec.DisallowUnknownFields()
@@ -1081,6 +1126,46 @@ func (h *UsersHandler) SetKidsAllowedLists(w http.ResponseWriter, r *http.Reques
 	json.NewEncoder(w).Encode(user)
 }
 
+// SetKidsAllowedGenres replaces the allowed genres for a kids profile in
+// allow_list mode.
+func (h *UsersHandler) SetKidsAllowedGenres(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := strings.TrimSpace(vars["userID"])
+	if id == "" {
+		http.Error(w, "user id is required", http.StatusBadRequest)
+		return
+	}
+
+	// Verify caller can configure this profile
+	if !h.canConfigureKidsProfile(r, id) {
+		http.Error(w, "cannot configure kids settings for this profile", http.StatusForbidden)
+		return
+	}
+
+	var body struct {
+		Genres []string `json:"genres"` // TMDB genre names
+	}
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	user, err := h.Service.SetKidsAllowedGenres(id, body.Genres)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, users.ErrUserNotFound) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(user)
+}
+
 // AddKidsAllowedList adds a list to the allowed lists for a kids profile.
 func (h *UsersHandler) AddKidsAllowedList(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
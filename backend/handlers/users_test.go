@@ -68,6 +68,8 @@ type fakeUsersService struct {
 	addAllowedErr       error
 	removeAllowedUser   models.User
 	removeAllowedErr    error
+	setCeilingUser      models.User
+	setCeilingErr       error
 }
 
 func (f *fakeUsersService) List() []models.User { return nil }
@@ -154,12 +156,18 @@ func (f *fakeUsersService) SetKidsMaxTVRating(id, rating string) (models.User, e
 func (f *fakeUsersService) SetKidsAllowedLists(id string, lists []string) (models.User, error) {
 	return f.setAllowedUser, f.setAllowedErr
 }
+func (f *fakeUsersService) SetKidsAllowedGenres(id string, genres []string) (models.User, error) {
+	return f.setAllowedUser, f.setAllowedErr
+}
 func (f *fakeUsersService) AddKidsAllowedList(id, listURL string) (models.User, error) {
 	return f.addAllowedUser, f.addAllowedErr
 }
 func (f *fakeUsersService) RemoveKidsAllowedList(id, listURL string) (models.User, error) {
 	return f.removeAllowedUser, f.removeAllowedErr
 }
+func (f *fakeUsersService) SetRatingCeiling(id, movieRating, tvRating string) (models.User, error) {
+	return f.setCeilingUser, f.setCeilingErr
+}
 
 // helper to build a request with mux vars and auth context
 func usersRequest(method, path string, body any, vars map[string]string, accountID string, isMaster bool) *http.Request {
@@ -631,6 +639,36 @@ func TestUsersHandler_SetKidsMode_MasterBypass(t *testing.T) {
 	}
 }
 
+func TestUsersHandler_SetRatingCeiling(t *testing.T) {
+	expected := models.User{ID: "u1", MaxMovieRating: "PG-13", MaxTVRating: "TV-14"}
+	svc := &fakeUsersService{getOK: true, getUser: models.User{ID: "u1"}, belongsTo: true, setCeilingUser: expected}
+	h := handlers.NewUsersHandler(svc)
+
+	body := map[string]string{"movieRating": "PG-13", "tvRating": "TV-14"}
+	r := usersRequest(http.MethodPut, "/", body, map[string]string{"userID": "u1"}, "acct-1", false)
+	w := httptest.NewRecorder()
+	h.SetRatingCeiling(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+
+	svc.setCeilingErr = users.ErrInvalidRating
+	r = usersRequest(http.MethodPut, "/", map[string]string{"movieRating": "TV-14"}, map[string]string{"userID": "u1"}, "acct-1", false)
+	w = httptest.NewRecorder()
+	h.SetRatingCeiling(w, r)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	svc.belongsTo = false
+	r = usersRequest(http.MethodPut, "/", body, map[string]string{"userID": "u1"}, "acct-2", false)
+	w = httptest.NewRecorder()
+	h.SetRatingCeiling(w, r)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
 func TestUsersHandler_ClearIconURL_Success(t *testing.T) {
 	expected := models.User{ID: "u1"}
 	svc := &fakeUsersService{belongsTo: true, clearIconURLUser: expected}
@@ -119,6 +119,7 @@ type VideoHandler struct {
 	failures      *streamFailureRegistry
 	prequeueStore *playback.PrequeueStore
 	prewarmSvc    PrewarmService
+	playbackGuard *PlaybackGuard
 
 	// Subtitle extraction for non-HLS streams
 	subtitleExtractManager *SubtitleExtractManager
@@ -406,6 +407,11 @@ func (h *VideoHandler) SetPrewarmService(svc PrewarmService) {
 	h.prewarmSvc = svc
 }
 
+// SetPlaybackGuard lets the watch time limit end HLS sessions mid-play.
+func (h *VideoHandler) SetPlaybackGuard(guard *PlaybackGuard) {
+	h.playbackGuard = guard
+}
+
 func (h *VideoHandler) invalidatePrequeuesForFailedPath(streamPath string) {
 	if h == nil || h.prequeueStore == nil {
 		return
@@ -2248,6 +2254,91 @@ func detectDolbyVision(stream *ffprobeStream) (hasDV bool, dvProfile string, hdr
 	return false, "", ""
 }
 
+// pixFmtBitDepthRegex captures the bit depth of high bit depth pixel formats (e.g., yuv420p10le)
+var pixFmtBitDepthRegex = regexp.MustCompile(`p(\d{2})(?:le|be)?$`)
+
+// videoBitDepth returns the bit depth of a video stream, preferring
+// bits_per_raw_sample and falling back to the pixel format.
+func videoBitDepth(stream *ffprobeStream) int {
+	if depth := parseInt(stream.BitsPerSample); depth > 0 {
+		return depth
+	}
+	pixFmt := strings.ToLower(strings.TrimSpace(stream.PixFmt))
+	if pixFmt == "" {
+		return 0
+	}
+	if m := pixFmtBitDepthRegex.FindStringSubmatch(pixFmt); m != nil {
+		return parseInt(m[1])
+	}
+	return 8
+}
+
+// probedMediaInfo summarizes the primary video and audio streams of a probe
+// in the same shape search results report from release names, so clients
+// can confirm or correct the parsed HDR format and DV profile.
+func probedMediaInfo(resp *videoMetadataResponse) *models.SourceMediaInfo {
+	if resp == nil || len(resp.VideoStreams) == 0 {
+		return nil
+	}
+	video := resp.VideoStreams[0]
+	info := &models.SourceMediaInfo{BitDepth: video.BitDepth, Probed: true}
+
+	if video.HasDolbyVision {
+		info.HDRFormats = append(info.HDRFormats, models.HDRFormatDolbyVision)
+		if m := dvProfileNumberRegex.FindStringSubmatch(video.DolbyVisionProfile); m != nil {
+			info.DVProfile = parseInt(m[1])
+		}
+	}
+	switch strings.ToLower(video.ColorTransfer) {
+	case "smpte2084":
+		info.HDRFormats = append(info.HDRFormats, models.HDRFormatHDR10)
+	case "arib-std-b67":
+		info.HDRFormats = append(info.HDRFormats, models.HDRFormatHLG)
+	}
+
+	if resp.SelectedAudioIndex >= 0 {
+		for _, audio := range resp.AudioStreams {
+			if audio.Index != resp.SelectedAudioIndex {
+				continue
+			}
+			info.AudioCodecs = []string{audio.CodecName}
+			info.AudioChannels = audioChannelLayout(audio)
+			break
+		}
+	}
+	return info
+}
+
+// dvProfileNumberRegex captures the profile from a DV codec string (e.g., "dvhe.05.06")
+var dvProfileNumberRegex = regexp.MustCompile(`^dv[a-z0-9]{2}\.0?(\d+)`)
+
+// audioChannelLayout returns a "5.1"-style layout for an audio stream.
+func audioChannelLayout(audio audioStreamSummary) string {
+	layout := strings.ToLower(audio.ChannelLayout)
+	if idx := strings.IndexByte(layout, '('); idx > 0 {
+		layout = layout[:idx]
+	}
+	switch {
+	case layout == "mono", layout == "stereo":
+		return layout
+	case strings.Contains(layout, "."):
+		return layout
+	}
+	switch audio.Channels {
+	case 0:
+		return ""
+	case 1:
+		return "mono"
+	case 2:
+		return "stereo"
+	case 6:
+		return "5.1"
+	case 8:
+		return "7.1"
+	}
+	return strconv.Itoa(audio.Channels) + "ch"
+}
+
 func isDolbyVisionProfile7(profile string) bool {
 	profile = strings.ToLower(strings.TrimSpace(profile))
 	if profile == "" {
@@ -2408,6 +2499,7 @@ func composeMetadataResponse(meta *ffprobeOutput, sanitizedPath string, plan aud
 				Height:             stream.Height,
 				BitRate:            getStreamBitrate(stream.BitRate, stream.Tags),
 				PixFmt:             strings.TrimSpace(stream.PixFmt),
+				BitDepth:           videoBitDepth(stream),
 				Profile:            strings.TrimSpace(stream.Profile),
 				AvgFrameRate:       strings.TrimSpace(stream.AvgFrameRate),
 				HasDolbyVision:     hasDV,
@@ -2454,6 +2546,7 @@ func composeMetadataResponse(meta *ffprobeOutput, sanitizedPath string, plan aud
 	}
 
 	resp.AudioCopySupported = copyableFound
+	resp.Media = probedMediaInfo(&resp)
 	if !copyableFound && len(resp.AudioStreams) > 0 {
 		resp.Notes = append(resp.Notes, "source audio codec requires transcoding for MP4 playback")
 	}
@@ -2592,6 +2685,7 @@ type ffprobeStream struct {
 	Width          int               `json:"width"`
 	Height         int               `json:"height"`
 	PixFmt         string            `json:"pix_fmt"`
+	BitsPerSample  string            `json:"bits_per_raw_sample"`
 	Profile        string            `json:"profile"`
 	AvgFrameRate   string            `json:"avg_frame_rate"`
 	ColorSpace     string            `json:"color_space"`
@@ -2645,6 +2739,7 @@ type videoStreamSummary struct {
 	Height             int    `json:"height,omitempty"`
 	BitRate            int64  `json:"bitRate,omitempty"`
 	PixFmt             string `json:"pixFmt,omitempty"`
+	BitDepth           int    `json:"bitDepth,omitempty"`
 	Profile            string `json:"profile,omitempty"`
 	AvgFrameRate       string `json:"avgFrameRate,omitempty"`
 	HasDolbyVision     bool   `json:"hasDolbyVision"`
@@ -2683,6 +2778,7 @@ type videoMetadataResponse struct {
 	AudioCopySupported    bool                    `json:"audioCopySupported"`
 	NeedsAudioTranscode   bool                    `json:"needsAudioTranscode"`
 	SelectedSubtitleIndex int                     `json:"selectedSubtitleIndex"`
+	Media                 *models.SourceMediaInfo `json:"media,omitempty"`
 	Notes                 []string                `json:"notes,omitempty"`
 }
 
@@ -2920,8 +3016,17 @@ func (h *VideoHandler) StartHLSSession(w http.ResponseWriter, r *http.Request) {
 	dvProfile := r.URL.Query().Get("dvProfile")
 	hasHDR := r.URL.Query().Get("hdr") == "true"
 	forceAAC := r.URL.Query().Get("forceAAC") == "true"
+	forceAACRequested := forceAAC
 	castMode := r.URL.Query().Get("cast") == "true"
 	playbackTarget := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("target")))
+	// maxHeight scales transcoded video down; it only applies to the "h264"
+	// target chosen by playback negotiation.
+	maxVideoHeight := 0
+	if playbackTarget == playbackTargetH264 {
+		if parsed, err := strconv.Atoi(r.URL.Query().Get("maxHeight")); err == nil && parsed > 0 {
+			maxVideoHeight = parsed
+		}
+	}
 	// Check global setting for forced AAC transcoding (for Bluetooth compatibility)
 	if !forceAAC && h.configManager != nil {
 		if settings, err := h.configManager.Load(); err == nil {
@@ -3018,6 +3123,25 @@ func (h *VideoHandler) StartHLSSession(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Apply the device's stored playback overrides (force transcode, resolution cap, HDR off, audio passthrough)
+	if overrides := h.devicePlaybackOverrides(clientID); overrides.isSet() {
+		opts := hlsStreamOptions{
+			hasDV:             hasDV,
+			dvProfile:         dvProfile,
+			hasHDR:            hasHDR,
+			forceAAC:          forceAAC,
+			forceAACRequested: forceAACRequested,
+			target:            playbackTarget,
+			maxVideoHeight:    maxVideoHeight,
+		}
+		notes := overrides.applyToHLS(&opts, func() int { return h.sourceVideoHeight(r, cleanPath) })
+		hasDV, dvProfile, hasHDR = opts.hasDV, opts.dvProfile, opts.hasHDR
+		forceAAC, playbackTarget, maxVideoHeight = opts.forceAAC, opts.target, opts.maxVideoHeight
+		if len(notes) > 0 {
+			log.Printf("[video] applied playback overrides for client %s on path=%q: %s", clientID, cleanPath, strings.Join(notes, "; "))
+		}
+	}
+
 	// For warm start sessions, probe for the actual keyframe position FFmpeg will seek to BEFORE creating session
 	// This is critical because FFmpeg seeks to the nearest keyframe, not the exact requested time
 	// Both video and subtitles must start from the same keyframe position for sync
@@ -3032,7 +3156,7 @@ func (h *VideoHandler) StartHLSSession(w http.ResponseWriter, r *http.Request) {
 	videoTracef("[video] creating HLS session for path=%q dv=%v dvProfile=%q hdr=%v start=%.3fs transcodingOffset=%.3fs audioTrack=%d subtitleTrack=%d",
 		cleanPath, hasDV, dvProfile, hasHDR, startSeconds, transcodingOffset, audioTrackIndex, subtitleTrackIndex)
 
-	session, err := h.hlsManager.CreateSession(r.Context(), cleanPath, path, hasDV, dvProfile, hasHDR, forceAAC, startSeconds, transcodingOffset, audioTrackIndex, subtitleTrackIndex, profileID, profileName, getClientIP(r), castMode, "", playbackTarget)
+	session, err := h.hlsManager.CreateSession(r.Context(), cleanPath, path, hasDV, dvProfile, hasHDR, forceAAC, startSeconds, transcodingOffset, audioTrackIndex, subtitleTrackIndex, profileID, profileName, getClientIP(r), castMode, "", playbackTarget, maxVideoHeight)
 	if err != nil {
 		log.Printf("[video] failed to create HLS session: %v", err)
 		if errors.Is(err, streaming.ErrStaleTorrent) {
@@ -3044,6 +3168,7 @@ func (h *VideoHandler) StartHLSSession(w http.ResponseWriter, r *http.Request) {
 	}
 	session.mu.Lock()
 	session.MediaMetadata = mediaMetadata
+	session.watchTimeExempt = h.playbackGuard.overridden(r)
 	session.mu.Unlock()
 
 	session.mu.RLock()
@@ -3141,6 +3266,7 @@ func (h *VideoHandler) StartYouTubeHLSSession(w http.ResponseWriter, r *http.Req
 	}
 	session.mu.Lock()
 	session.MediaMetadata = parseStreamMediaMetadata(r)
+	session.watchTimeExempt = true
 	session.mu.Unlock()
 
 	w.Header().Set("Content-Type", "application/json")
@@ -3869,6 +3995,7 @@ func (h *VideoHandler) StartLiveHLSSession(w http.ResponseWriter, r *http.Reques
 	}
 	session.mu.Lock()
 	session.MediaMetadata = mediaMetadata
+	session.watchTimeExempt = h.playbackGuard.overridden(r)
 	session.mu.Unlock()
 
 	response := map[string]interface{}{
@@ -3998,6 +4125,20 @@ func (h *VideoHandler) ServeHLSSegment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if session, ok := h.hlsManager.GetSession(sessionID); ok && h.playbackGuard != nil {
+		session.mu.RLock()
+		profileID, exempt := session.ProfileID, session.watchTimeExempt
+		session.mu.RUnlock()
+		if !exempt {
+			if err := h.playbackGuard.blocked(profileID); err != nil {
+				log.Printf("[watch-time] ending HLS session %s for profile %s: %v", sessionID, profileID, err)
+				h.hlsManager.CleanupSession(sessionID)
+				writeJSONError(w, err.Error(), http.StatusForbidden)
+				return
+			}
+		}
+	}
+
 	h.hlsManager.ServeSegment(w, r, sessionID, segmentName)
 }
 
@@ -4435,7 +4576,7 @@ func (h *VideoHandler) CreateHLSSession(ctx context.Context, path string, hasDV
 		}
 	}
 
-	session, err := h.hlsManager.CreateSession(ctx, path, path, hasDV, dvProfile, hasHDR, false, startOffset, 0, audioTrackIndex, subtitleTrackIndex, profileID, "", "", false, prequeueType, "")
+	session, err := h.hlsManager.CreateSession(ctx, path, path, hasDV, dvProfile, hasHDR, false, startOffset, 0, audioTrackIndex, subtitleTrackIndex, profileID, "", "", false, prequeueType, "", 0)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create HLS session: %w", err)
 	}
@@ -308,6 +308,57 @@ func TestDetectDolbyVision(t *testing.T) {
 	}
 }
 
+// --- probedMediaInfo tests ---
+
+func TestVideoBitDepth(t *testing.T) {
+	tests := []struct {
+		stream ffprobeStream
+		want   int
+	}{
+		{ffprobeStream{BitsPerSample: "10", PixFmt: "yuv420p"}, 10},
+		{ffprobeStream{PixFmt: "yuv420p10le"}, 10},
+		{ffprobeStream{PixFmt: "yuv420p12le"}, 12},
+		{ffprobeStream{PixFmt: "yuv420p"}, 8},
+		{ffprobeStream{}, 0},
+	}
+	for _, tc := range tests {
+		if got := videoBitDepth(&tc.stream); got != tc.want {
+			t.Errorf("videoBitDepth(%+v) = %d, want %d", tc.stream, got, tc.want)
+		}
+	}
+}
+
+func TestProbedMediaInfo(t *testing.T) {
+	resp := &videoMetadataResponse{
+		VideoStreams: []videoStreamSummary{{
+			CodecName: "hevc", BitDepth: 10, HasDolbyVision: true,
+			DolbyVisionProfile: "dvhe.08.06", HdrFormat: "DV", ColorTransfer: "smpte2084",
+		}},
+		AudioStreams: []audioStreamSummary{
+			{Index: 1, CodecName: "truehd", Channels: 8, ChannelLayout: "7.1"},
+			{Index: 2, CodecName: "eac3", Channels: 6, ChannelLayout: "5.1(side)"},
+		},
+		SelectedAudioIndex: 2,
+	}
+
+	info := probedMediaInfo(resp)
+	if info == nil || !info.Probed {
+		t.Fatalf("expected probed media info, got %+v", info)
+	}
+	if len(info.HDRFormats) != 2 || info.HDRFormats[0] != "DV" || info.HDRFormats[1] != "HDR10" {
+		t.Errorf("HDRFormats = %v, want [DV HDR10]", info.HDRFormats)
+	}
+	if info.DVProfile != 8 || info.BitDepth != 10 {
+		t.Errorf("DVProfile = %d, BitDepth = %d, want 8 and 10", info.DVProfile, info.BitDepth)
+	}
+	if len(info.AudioCodecs) != 1 || info.AudioCodecs[0] != "eac3" || info.AudioChannels != "5.1" {
+		t.Errorf("audio = %v %q, want [eac3] 5.1", info.AudioCodecs, info.AudioChannels)
+	}
+	if probedMediaInfo(&videoMetadataResponse{}) != nil {
+		t.Error("expected nil media info without video streams")
+	}
+}
+
 // --- isDolbyVisionProfile7 tests ---
 
 func TestIsDolbyVisionProfile7(t *testing.T) {
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/net/websocket"
+
+	"novastream/internal/auth"
+	"novastream/models"
+	"novastream/services/watchparty"
+)
+
+// watchPartyPingInterval is how often an idle watch party socket is pinged so
+// dead connections are noticed and proxies keep the connection open.
+const watchPartyPingInterval = 30 * time.Second
+
+// watchPartyWriteTimeout bounds a single write to a watch party socket.
+const watchPartyWriteTimeout = 10 * time.Second
+
+// watchPartyProfiles resolves and authorizes the profiles taking part in a
+// watch party. Satisfied by *users.Service.
+type watchPartyProfiles interface {
+	Get(id string) (models.User, bool)
+	BelongsToAccount(profileID, accountID string) bool
+}
+
+// WatchPartyHandler serves synchronized playback sessions. Members connect
+// over a WebSocket to send play/pause/seek and receive everyone else's.
+type WatchPartyHandler struct {
+	parties *watchparty.Service
+	users   watchPartyProfiles
+}
+
+// NewWatchPartyHandler creates a WatchPartyHandler.
+func NewWatchPartyHandler(parties *watchparty.Service, users watchPartyProfiles) *WatchPartyHandler {
+	return &WatchPartyHandler{parties: parties, users: users}
+}
+
+type watchPartyCreateRequest struct {
+	ProfileID string  `json:"profileId"`
+	MediaID   string  `json:"mediaId"`
+	Title     string  `json:"title"`
+	HostOnly  bool    `json:"hostOnly"`
+	Position  float64 `json:"position"`
+	Paused    bool    `json:"paused"`
+}
+
+// watchPartyCommand is a playback command sent by a member, either over the
+// socket or to the control endpoint. The "sync" action only asks for the
+// current state.
+type watchPartyCommand struct {
+	ProfileID string  `json:"profileId,omitempty"`
+	Action    string  `json:"action"`
+	Position  float64 `json:"position"`
+}
+
+type watchPartySocketError struct {
+	Type  string `json:"type"`
+	Error string `json:"error"`
+}
+
+// Create starts a watch party hosted by one of the caller's profiles.
+func (h *WatchPartyHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req watchPartyCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	profile, ok := h.profile(r, req.ProfileID)
+	if !ok {
+		writeJSONError(w, "profile not found", http.StatusNotFound)
+		return
+	}
+
+	party, err := h.parties.Create(watchparty.CreateRequest{
+		HostUserID: profile.ID,
+		HostName:   profile.Name,
+		MediaID:    req.MediaID,
+		Title:      req.Title,
+		HostOnly:   req.HostOnly,
+		Position:   req.Position,
+		Paused:     req.Paused,
+	})
+	if err != nil {
+		writeWatchPartyError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(party)
+}
+
+// Get returns a watch party, so a client can show what it is about to join.
+func (h *WatchPartyHandler) Get(w http.ResponseWriter, r *http.Request) {
+	party, ok := h.parties.Get(mux.Vars(r)["partyID"])
+	if !ok {
+		writeJSONError(w, watchparty.ErrPartyNotFound.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(party)
+}
+
+// Control applies a playback command without a socket, for clients that only
+// need to drive the party.
+func (h *WatchPartyHandler) Control(w http.ResponseWriter, r *http.Request) {
+	var cmd watchPartyCommand
+	if err := json.NewDecoder(r.Body).Decode(&cmd); err != nil {
+		writeJSONError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	profile, ok := h.profile(r, cmd.ProfileID)
+	if !ok {
+		writeJSONError(w, "profile not found", http.StatusNotFound)
+		return
+	}
+
+	state, err := h.parties.Control(mux.Vars(r)["partyID"], profile.ID, cmd.Action, cmd.Position)
+	if err != nil {
+		writeWatchPartyError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(state)
+}
+
+// End closes a watch party for every member. Only the host profile may end it.
+func (h *WatchPartyHandler) End(w http.ResponseWriter, r *http.Request) {
+	profile, ok := h.profile(r, r.URL.Query().Get("profileId"))
+	if !ok {
+		writeJSONError(w, "profile not found", http.StatusNotFound)
+		return
+	}
+	if err := h.parties.End(mux.Vars(r)["partyID"], profile.ID); err != nil {
+		writeWatchPartyError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": "ended"})
+}
+
+// Connect upgrades to a WebSocket that joins the party as ?profileId=. The
+// server first sends the current state, then every change made by other
+// members; the client sends watchPartyCommand messages.
+func (h *WatchPartyHandler) Connect(w http.ResponseWriter, r *http.Request) {
+	partyID := mux.Vars(r)["partyID"]
+	profile, ok := h.profile(r, r.URL.Query().Get("profileId"))
+	if !ok {
+		writeJSONError(w, "profile not found", http.StatusNotFound)
+		return
+	}
+	if _, ok := h.parties.Get(partyID); !ok {
+		writeJSONError(w, watchparty.ErrPartyNotFound.Error(), http.StatusNotFound)
+		return
+	}
+
+	// Clients authenticate with a session token rather than cookies, so the
+	// Origin check websocket.Handler performs adds nothing here.
+	websocket.Server{Handler: func(ws *websocket.Conn) {
+		h.serveSocket(ws, partyID, profile)
+	}}.ServeHTTP(w, r)
+}
+
+func (h *WatchPartyHandler) serveSocket(ws *websocket.Conn, partyID string, profile models.User) {
+	defer ws.Close()
+	// The server's read timeout still applies to the hijacked connection;
+	// members can sit idle for a whole film.
+	ws.SetReadDeadline(time.Time{})
+
+	sub, err := h.parties.Join(partyID, profile.ID, profile.Name)
+	if err != nil {
+		sendWatchPartySocket(ws, watchPartySocketError{Type: "error", Error: err.Error()})
+		return
+	}
+	defer h.parties.Leave(sub)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			var cmd watchPartyCommand
+			if err := websocket.JSON.Receive(ws, &cmd); err != nil {
+				return
+			}
+			if cmd.Action == "sync" {
+				if party, ok := h.parties.Get(partyID); ok {
+					sendWatchPartySocket(ws, watchparty.Event{Type: watchparty.EventState, State: party.State, Members: party.Members, ServerTime: time.Now().UTC()})
+				}
+				continue
+			}
+			if _, err := h.parties.Control(partyID, profile.ID, cmd.Action, cmd.Position); err != nil {
+				sendWatchPartySocket(ws, watchPartySocketError{Type: "error", Error: err.Error()})
+			}
+		}
+	}()
+
+	ping := time.NewTicker(watchPartyPingInterval)
+	defer ping.Stop()
+	for {
+		select {
+		case event, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			if err := sendWatchPartySocket(ws, event); err != nil {
+				return
+			}
+		case <-ping.C:
+			if err := sendWatchPartySocket(ws, map[string]string{"type": "ping"}); err != nil {
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// profile returns profileID if the caller may act as it. Master accounts may
+// act as any profile.
+func (h *WatchPartyHandler) profile(r *http.Request, profileID string) (models.User, bool) {
+	profileID = strings.TrimSpace(profileID)
+	if profileID == "" {
+		return models.User{}, false
+	}
+	if !auth.IsMaster(r) && !h.users.BelongsToAccount(profileID, auth.GetAccountID(r)) {
+		return models.User{}, false
+	}
+	return h.users.Get(profileID)
+}
+
+// Options handles CORS preflight requests.
+func (h *WatchPartyHandler) Options(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func sendWatchPartySocket(ws *websocket.Conn, v any) error {
+	ws.SetWriteDeadline(time.Now().Add(watchPartyWriteTimeout))
+	err := websocket.JSON.Send(ws, v)
+	if err != nil {
+		log.Printf("[watch-party] send failed: %v", err)
+	}
+	return err
+}
+
+func writeWatchPartyError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, watchparty.ErrPartyNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, watchparty.ErrHostOnly), errors.Is(err, watchparty.ErrNotHost), errors.Is(err, watchparty.ErrNotMember):
+		status = http.StatusForbidden
+	case errors.Is(err, watchparty.ErrUnknownAction), errors.Is(err, watchparty.ErrInvalidRequest):
+		status = http.StatusBadRequest
+	}
+	writeJSONError(w, err.Error(), status)
+}
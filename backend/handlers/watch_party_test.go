@@ -0,0 +1,108 @@
+package handlers_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"golang.org/x/net/websocket"
+
+	"novastream/handlers"
+	"novastream/internal/auth"
+	"novastream/services/users"
+	"novastream/services/watchparty"
+)
+
+func TestWatchPartySyncsPlaybackOverWebSocket(t *testing.T) {
+	usersSvc, err := users.NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create users service: %v", err)
+	}
+	host, err := usersSvc.CreateForAccount("acct-1", "Host")
+	if err != nil {
+		t.Fatalf("create host profile: %v", err)
+	}
+	guest, err := usersSvc.CreateForAccount("acct-2", "Guest")
+	if err != nil {
+		t.Fatalf("create guest profile: %v", err)
+	}
+
+	handler := handlers.NewWatchPartyHandler(watchparty.NewService(), usersSvc)
+	r := mux.NewRouter()
+	r.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			ctx := context.WithValue(req.Context(), auth.ContextKeyAccountID, req.URL.Query().Get("account"))
+			next.ServeHTTP(w, req.WithContext(ctx))
+		})
+	})
+	r.HandleFunc("/api/watch-parties", handler.Create).Methods(http.MethodPost)
+	r.HandleFunc("/api/watch-parties/{partyID}/control", handler.Control).Methods(http.MethodPost)
+	r.HandleFunc("/api/watch-parties/{partyID}/ws", handler.Connect).Methods(http.MethodGet)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	body, _ := json.Marshal(map[string]any{"profileId": host.ID, "mediaId": "tmdb:movie:603", "hostOnly": true, "paused": true})
+	resp, err := http.Post(srv.URL+"/api/watch-parties?account=acct-1", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("create party: %v", err)
+	}
+	var party watchparty.Party
+	json.NewDecoder(resp.Body).Decode(&party)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated || party.ID == "" {
+		t.Fatalf("expected party to be created, got %d %+v", resp.StatusCode, party)
+	}
+
+	// A profile from another account cannot be borrowed.
+	resp, err = http.Get(srv.URL + "/api/watch-parties/" + party.ID + "/ws?account=acct-2&profileId=" + host.ID)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for another account's profile, got %d", resp.StatusCode)
+	}
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/api/watch-parties/" + party.ID + "/ws?account=acct-2&profileId=" + guest.ID
+	ws, err := websocket.Dial(wsURL, "", srv.URL)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer ws.Close()
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var event watchparty.Event
+	if err := websocket.JSON.Receive(ws, &event); err != nil || event.Type != watchparty.EventState {
+		t.Fatalf("expected initial state, got %+v (%v)", event, err)
+	}
+
+	// Guests cannot drive a host-only party.
+	websocket.JSON.Send(ws, map[string]any{"action": "play", "position": 5})
+	var socketErr map[string]string
+	if err := websocket.JSON.Receive(ws, &socketErr); err != nil || socketErr["type"] != "error" {
+		t.Fatalf("expected host-only error, got %+v (%v)", socketErr, err)
+	}
+
+	body, _ = json.Marshal(map[string]any{"profileId": host.ID, "action": "seek", "position": 90})
+	resp, err = http.Post(srv.URL+"/api/watch-parties/"+party.ID+"/control?account=acct-1", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("control: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected host seek to succeed, got %d", resp.StatusCode)
+	}
+
+	if err := websocket.JSON.Receive(ws, &event); err != nil {
+		t.Fatalf("receive: %v", err)
+	}
+	if event.Action != watchparty.ActionSeek || event.By != host.ID || event.State.Position != 90 {
+		t.Fatalf("expected the host's seek to reach the guest, got %+v", event)
+	}
+}
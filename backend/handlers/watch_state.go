@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"strconv"
+	"time"
 
 	"novastream/internal/mediaidentity"
 	"novastream/models"
@@ -250,3 +251,142 @@ func firstNonEmpty(values ...string) string {
 	}
 	return ""
 }
+
+// seriesWatchProgressMinPercent is the playback percentage above which an
+// unwatched episode is reported as in progress.
+const seriesWatchProgressMinPercent = 5
+
+// withSeriesWatchProgress returns a shallow copy of details with per-season
+// watched state for the profile whose history and progress are supplied.
+// The metadata service may share details across callers, so it is not mutated.
+func withSeriesWatchProgress(details *models.SeriesDetails, history []models.WatchHistoryItem, progress []models.PlaybackProgress) *models.SeriesDetails {
+	if details == nil || len(details.Seasons) == 0 {
+		return details
+	}
+
+	// Match on index keys rather than canonical IDs: history rows may carry a
+	// tvdb series ID while details canonicalize to tmdb, or vice versa.
+	seriesKeys := make(map[string]bool)
+	for _, key := range mediaidentity.Resolve(mediaidentity.Input{
+		MediaType:   "series",
+		ID:          details.Title.ID,
+		ExternalIDs: seriesExternalIDs(details.Title),
+	}).IndexKeys() {
+		seriesKeys[key] = true
+	}
+	sameSeries := func(seriesID string, externalIDs map[string]string) bool {
+		candidate := mediaidentity.Resolve(mediaidentity.Input{
+			MediaType:   "series",
+			ID:          seriesID,
+			ExternalIDs: externalIDs,
+		})
+		for _, key := range candidate.IndexKeys() {
+			if seriesKeys[key] {
+				return true
+			}
+		}
+		return false
+	}
+
+	type episodeKey struct{ season, episode int }
+	watched := make(map[episodeKey]bool)
+	seriesMarkedWatched := false
+	for _, wh := range history {
+		if !wh.Watched {
+			continue
+		}
+		switch wh.MediaType {
+		case "series":
+			if sameSeries(wh.ItemID, wh.ExternalIDs) {
+				seriesMarkedWatched = true
+			}
+		case "episode":
+			if wh.SeriesID != "" && sameSeries(wh.SeriesID, wh.ExternalIDs) {
+				watched[episodeKey{wh.SeasonNumber, wh.EpisodeNumber}] = true
+			}
+		}
+	}
+	inProgress := make(map[episodeKey]bool)
+	for _, pp := range progress {
+		if pp.MediaType != "episode" || pp.SeriesID == "" || pp.PercentWatched < seriesWatchProgressMinPercent {
+			continue
+		}
+		if sameSeries(pp.SeriesID, pp.ExternalIDs) {
+			inProgress[episodeKey{pp.SeasonNumber, pp.EpisodeNumber}] = true
+		}
+	}
+
+	seasons := make([]models.SeasonWatchProgress, 0, len(details.Seasons))
+	for _, season := range details.Seasons {
+		entry := models.SeasonWatchProgress{
+			SeasonNumber: season.Number,
+			EpisodeCount: len(season.Episodes),
+		}
+		bitmap := make([]byte, len(season.Episodes))
+		for i, ep := range season.Episodes {
+			key := episodeKey{ep.SeasonNumber, ep.EpisodeNumber}
+			if seriesMarkedWatched || watched[key] {
+				bitmap[i] = '1'
+				entry.WatchedCount++
+				continue
+			}
+			bitmap[i] = '0'
+			if inProgress[key] {
+				entry.InProgressEpisodes = append(entry.InProgressEpisodes, ep.EpisodeNumber)
+			}
+		}
+		entry.Bitmap = string(bitmap)
+		if entry.EpisodeCount > 0 {
+			entry.Percent = float64(entry.WatchedCount) * 100 / float64(entry.EpisodeCount)
+		}
+		seasons = append(seasons, entry)
+	}
+
+	out := *details
+	out.WatchProgress = seasons
+	return &out
+}
+
+// seriesExternalIDs collects the provider IDs of a series title for identity matching.
+func seriesExternalIDs(title models.Title) map[string]string {
+	ids := make(map[string]string)
+	if title.TVDBID > 0 {
+		ids["tvdb"] = strconv.FormatInt(title.TVDBID, 10)
+	}
+	if title.TMDBID > 0 {
+		ids["tmdb"] = strconv.FormatInt(title.TMDBID, 10)
+	}
+	if title.IMDBID != "" {
+		ids["imdb"] = title.IMDBID
+	}
+	return ids
+}
+
+// withCollectionWatchProgress returns a copy of details with each movie's watch
+// state and a watched/next-unwatched rollup for the profile whose history and
+// progress are supplied. Movies dated after the current year count towards the
+// total but are never offered as the next entry.
+func withCollectionWatchProgress(details *models.CollectionDetails, history []models.WatchHistoryItem, progress []models.PlaybackProgress, now time.Time) *models.CollectionDetails {
+	if details == nil {
+		return nil
+	}
+	idx := buildWatchStateIndex(history, nil, progress)
+
+	out := *details
+	out.Movies = make([]models.Title, len(details.Movies))
+	rollup := &models.CollectionWatchProgress{TotalCount: len(details.Movies)}
+	for i, movie := range details.Movies {
+		movie.WatchState, movie.UnwatchedCount = idx.computeWithExternalIDs("movie", movie.ID, titleWatchStateExternalIDs(movie))
+		out.Movies[i] = movie
+		if movie.WatchState == "complete" {
+			rollup.WatchedCount++
+			continue
+		}
+		if rollup.NextUnwatched == nil && (movie.Year == 0 || movie.Year <= now.Year()) {
+			next := movie
+			rollup.NextUnwatched = &next
+		}
+	}
+	out.WatchProgress = rollup
+	return &out
+}
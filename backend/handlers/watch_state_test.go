@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"testing"
+	"time"
 
 	"novastream/models"
 )
@@ -229,3 +230,105 @@ func TestCompute_UnknownMediaType(t *testing.T) {
 		t.Errorf("expected nil unwatched for unknown media type, got %v", unwatched)
 	}
 }
+
+func seriesWatchProgressFixture() *models.SeriesDetails {
+	episodes := func(season, count int) []models.SeriesEpisode {
+		out := make([]models.SeriesEpisode, count)
+		for i := range out {
+			out[i] = models.SeriesEpisode{SeasonNumber: season, EpisodeNumber: i + 1}
+		}
+		return out
+	}
+	return &models.SeriesDetails{
+		Title: models.Title{ID: "tvdb:series:100", MediaType: "series", TVDBID: 100, TMDBID: 200},
+		Seasons: []models.SeriesSeason{
+			{Number: 1, Episodes: episodes(1, 4)},
+			{Number: 2, Episodes: episodes(2, 2)},
+		},
+	}
+}
+
+func TestWithSeriesWatchProgress(t *testing.T) {
+	details := seriesWatchProgressFixture()
+	history := []models.WatchHistoryItem{
+		{MediaType: "episode", SeriesID: "tvdb:series:100", SeasonNumber: 1, EpisodeNumber: 1, Watched: true},
+		{MediaType: "episode", SeriesID: "tmdb:tv:200", SeasonNumber: 1, EpisodeNumber: 3, Watched: true},
+		{MediaType: "episode", SeriesID: "tvdb:series:100", SeasonNumber: 1, EpisodeNumber: 4, Watched: false},
+		{MediaType: "episode", SeriesID: "tvdb:series:999", SeasonNumber: 2, EpisodeNumber: 1, Watched: true},
+	}
+	progress := []models.PlaybackProgress{
+		{MediaType: "episode", SeriesID: "tvdb:series:100", SeasonNumber: 1, EpisodeNumber: 2, PercentWatched: 40},
+		{MediaType: "episode", SeriesID: "tvdb:series:100", SeasonNumber: 2, EpisodeNumber: 1, PercentWatched: 2},
+	}
+
+	got := withSeriesWatchProgress(details, history, progress)
+	if details.WatchProgress != nil {
+		t.Fatal("expected input details to be left unmodified")
+	}
+	if len(got.WatchProgress) != 2 {
+		t.Fatalf("expected 2 seasons, got %d", len(got.WatchProgress))
+	}
+
+	s1 := got.WatchProgress[0]
+	if s1.SeasonNumber != 1 || s1.Bitmap != "1010" || s1.WatchedCount != 2 || s1.Percent != 50 {
+		t.Errorf("unexpected season 1 progress: %+v", s1)
+	}
+	if len(s1.InProgressEpisodes) != 1 || s1.InProgressEpisodes[0] != 2 {
+		t.Errorf("expected episode 2 in progress, got %v", s1.InProgressEpisodes)
+	}
+
+	s2 := got.WatchProgress[1]
+	if s2.Bitmap != "00" || s2.WatchedCount != 0 || len(s2.InProgressEpisodes) != 0 {
+		t.Errorf("expected season 2 untouched by other series and low progress, got %+v", s2)
+	}
+}
+
+func TestWithSeriesWatchProgress_SeriesMarkedWatched(t *testing.T) {
+	history := []models.WatchHistoryItem{
+		{MediaType: "series", ItemID: "tvdb:series:100", Watched: true},
+	}
+	got := withSeriesWatchProgress(seriesWatchProgressFixture(), history, nil)
+	for _, season := range got.WatchProgress {
+		if season.Percent != 100 || season.WatchedCount != season.EpisodeCount {
+			t.Errorf("expected season %d fully watched, got %+v", season.SeasonNumber, season)
+		}
+	}
+}
+
+func TestWithCollectionWatchProgress(t *testing.T) {
+	details := &models.CollectionDetails{
+		ID:   10,
+		Name: "Saga",
+		Movies: []models.Title{
+			{ID: "tmdb:movie:1", Name: "Part I", Year: 2001, TMDBID: 1, IMDBID: "tt0000001"},
+			{ID: "tmdb:movie:2", Name: "Part II", Year: 2003, TMDBID: 2},
+			{ID: "tmdb:movie:3", Name: "Part III", Year: 2005, TMDBID: 3},
+			{ID: "tmdb:movie:4", Name: "Part IV", Year: 2030, TMDBID: 4},
+		},
+	}
+	history := []models.WatchHistoryItem{
+		{MediaType: "movie", ItemID: "tt0000001", Watched: true},
+	}
+	progress := []models.PlaybackProgress{
+		{MediaType: "movie", ItemID: "tmdb:movie:2", PercentWatched: 95},
+		{MediaType: "movie", ItemID: "tmdb:movie:3", PercentWatched: 30},
+	}
+
+	got := withCollectionWatchProgress(details, history, progress, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	if details.WatchProgress != nil || details.Movies[0].WatchState != "" {
+		t.Fatal("expected input details to be left unmodified")
+	}
+	if got.WatchProgress == nil || got.WatchProgress.WatchedCount != 2 || got.WatchProgress.TotalCount != 4 {
+		t.Fatalf("unexpected rollup: %+v", got.WatchProgress)
+	}
+	if next := got.WatchProgress.NextUnwatched; next == nil || next.Name != "Part III" || next.WatchState != "partial" {
+		t.Fatalf("expected Part III as next unwatched, got %+v", next)
+	}
+
+	history = append(history, models.WatchHistoryItem{MediaType: "movie", ItemID: "tmdb:movie:3", Watched: true})
+	got = withCollectionWatchProgress(details, history, progress, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	if got.WatchProgress.WatchedCount != 3 || got.WatchProgress.NextUnwatched != nil {
+		t.Fatalf("expected unreleased Part IV not to be offered, got %+v", got.WatchProgress)
+	}
+}
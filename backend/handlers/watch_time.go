@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"novastream/internal/auth"
+	"novastream/models"
+	"novastream/services/watchtime"
+)
+
+type watchTimeService interface {
+	Summary(userID string, now time.Time) models.WatchTimeSummary
+	Goal(userID string) (models.WatchTimeGoal, bool)
+	SetGoal(goal models.WatchTimeGoal) (models.WatchTimeGoal, error)
+	DeleteGoal(userID string) error
+}
+
+var _ watchTimeService = (*watchtime.Service)(nil)
+
+type watchTimeUserService interface {
+	Get(id string) (models.User, bool)
+}
+
+// WatchTimeHandler serves a profile's weekly watch time and manages its
+// goal. Any profile may set a soft goal for itself; only an admin may put a
+// hard limit on a kids profile, and only an admin may lift one.
+type WatchTimeHandler struct {
+	Service watchTimeService
+	Users   watchTimeUserService
+}
+
+// NewWatchTimeHandler creates a WatchTimeHandler.
+func NewWatchTimeHandler(service watchTimeService, users watchTimeUserService) *WatchTimeHandler {
+	return &WatchTimeHandler{Service: service, Users: users}
+}
+
+type watchTimeGoalRequest struct {
+	WeeklyMinutes int  `json:"weeklyMinutes"`
+	WarnPercent   int  `json:"warnPercent"`
+	HardLimit     bool `json:"hardLimit"`
+}
+
+// Get returns the profile's watch time for the current week.
+func (h *WatchTimeHandler) Get(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.Service.Summary(user.ID, time.Now()))
+}
+
+// SetGoal creates or replaces the profile's weekly goal and returns the
+// updated summary.
+func (h *WatchTimeHandler) SetGoal(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+	var body watchTimeGoalRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSONError(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if !auth.IsMaster(r) {
+		if existing, ok := h.Service.Goal(user.ID); ok && existing.HardLimit {
+			writeJSONError(w, "only an admin can change a hard watch time limit", http.StatusForbidden)
+			return
+		}
+		if body.HardLimit {
+			writeJSONError(w, "only an admin can set a hard watch time limit", http.StatusForbidden)
+			return
+		}
+	}
+	if body.HardLimit && !user.IsKidsProfile {
+		writeJSONError(w, "hard watch time limits are only available for kids profiles", http.StatusBadRequest)
+		return
+	}
+	if _, err := h.Service.SetGoal(models.WatchTimeGoal{
+		UserID:        user.ID,
+		WeeklyMinutes: body.WeeklyMinutes,
+		WarnPercent:   body.WarnPercent,
+		HardLimit:     body.HardLimit,
+	}); err != nil {
+		writeWatchTimeError(w, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(h.Service.Summary(user.ID, time.Now()))
+}
+
+// DeleteGoal removes the profile's goal.
+func (h *WatchTimeHandler) DeleteGoal(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.requireUser(w, r)
+	if !ok {
+		return
+	}
+	if existing, ok := h.Service.Goal(user.ID); ok && existing.HardLimit && !auth.IsMaster(r) {
+		writeJSONError(w, "only an admin can remove a hard watch time limit", http.StatusForbidden)
+		return
+	}
+	if err := h.Service.DeleteGoal(user.ID); err != nil {
+		writeWatchTimeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *WatchTimeHandler) Options(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func writeWatchTimeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, watchtime.ErrGoalNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, watchtime.ErrInvalidGoal), errors.Is(err, watchtime.ErrUserIDRequired):
+		status = http.StatusBadRequest
+	}
+	writeJSONError(w, err.Error(), status)
+}
+
+func (h *WatchTimeHandler) requireUser(w http.ResponseWriter, r *http.Request) (models.User, bool) {
+	userID := strings.TrimSpace(mux.Vars(r)["userID"])
+	if userID == "" {
+		writeJSONError(w, "user id is required", http.StatusBadRequest)
+		return models.User{}, false
+	}
+	user, ok := h.Users.Get(userID)
+	if !ok {
+		writeJSONError(w, "user not found", http.StatusNotFound)
+		return models.User{}, false
+	}
+	return user, true
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"novastream/internal/auth"
+	"novastream/models"
+	"novastream/services/watchtime"
+)
+
+type fakeWatchTimeUsers map[string]models.User
+
+func (f fakeWatchTimeUsers) Get(id string) (models.User, bool) {
+	user, ok := f[id]
+	return user, ok
+}
+
+func TestWatchTimeHandlerHardLimitsAreAdminOnly(t *testing.T) {
+	svc, err := watchtime.NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	h := NewWatchTimeHandler(svc, fakeWatchTimeUsers{
+		"kids":  {ID: "kids", Name: "Kids", IsKidsProfile: true},
+		"adult": {ID: "adult", Name: "Adult"},
+	})
+	router := mux.NewRouter()
+	router.HandleFunc("/users/{userID}/watch-time", h.Get).Methods(http.MethodGet)
+	router.HandleFunc("/users/{userID}/watch-time/goal", h.SetGoal).Methods(http.MethodPut)
+	router.HandleFunc("/users/{userID}/watch-time/goal", h.DeleteGoal).Methods(http.MethodDelete)
+
+	do := func(method, path, body string, master bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), auth.ContextKeyIsMaster, master))
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := do(http.MethodPut, "/users/kids/watch-time/goal", `{"weeklyMinutes":300,"hardLimit":true}`, false); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected a profile to be refused a hard limit, got %d", rec.Code)
+	}
+	if rec := do(http.MethodPut, "/users/adult/watch-time/goal", `{"weeklyMinutes":300,"hardLimit":true}`, true); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected hard limits to be kids-only, got %d", rec.Code)
+	}
+	if rec := do(http.MethodPut, "/users/kids/watch-time/goal", `{"weeklyMinutes":300,"hardLimit":true}`, true); rec.Code != http.StatusOK {
+		t.Fatalf("expected an admin to set a hard limit, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodPut, "/users/kids/watch-time/goal", `{"weeklyMinutes":600}`, false); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected a profile to be refused changing a hard limit, got %d", rec.Code)
+	}
+	if rec := do(http.MethodDelete, "/users/kids/watch-time/goal", "", false); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected a profile to be refused removing a hard limit, got %d", rec.Code)
+	}
+
+	if rec := do(http.MethodPut, "/users/adult/watch-time/goal", `{"weeklyMinutes":600,"warnPercent":90}`, false); rec.Code != http.StatusOK {
+		t.Fatalf("expected a profile to set a soft goal, got %d", rec.Code)
+	}
+	rec := do(http.MethodGet, "/users/adult/watch-time", "", false)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"weeklyMinutes":600`) || !strings.Contains(rec.Body.String(), `"status":"ok"`) {
+		t.Fatalf("unexpected summary %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := do(http.MethodDelete, "/users/adult/watch-time/goal", "", false); rec.Code != http.StatusNoContent {
+		t.Fatalf("expected the soft goal to be removed, got %d", rec.Code)
+	}
+	if rec := do(http.MethodGet, "/users/missing/watch-time", "", false); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected an unknown profile to 404, got %d", rec.Code)
+	}
+}
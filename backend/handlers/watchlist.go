@@ -25,6 +25,14 @@ type watchlistService interface {
 
 var _ watchlistService = (*watchlist.Service)(nil)
 
+// watchlistPrioritySetter is implemented by watchlist services that store a
+// priority level per item.
+type watchlistPrioritySetter interface {
+	SetPriority(userID, mediaType, id, priority string) (models.WatchlistItem, error)
+}
+
+var _ watchlistPrioritySetter = (*watchlist.Service)(nil)
+
 type userService interface {
 	Exists(id string) bool
 }
@@ -62,11 +70,20 @@ func (h *WatchlistHandler) metadataForUser(userID string) metadataService {
 	return metadataServiceForUser(h.MetadataService, h.CfgManager, h.UserSettings, userID)
 }
 
+// List returns the profile's watchlist. The optional sort (priority, added,
+// release or runtime) and dir (asc or desc) query parameters order it on the
+// server; without them the newest additions come first.
 func (h *WatchlistHandler) List(w http.ResponseWriter, r *http.Request) {
 	userID, ok := h.requireUser(w, r)
 	if !ok {
 		return
 	}
+	sortBy := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("sort")))
+	sortDir := r.URL.Query().Get("dir")
+	if err := watchlist.SortItems(nil, sortBy, sortDir); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
 
 	items, err := h.Service.List(userID)
 	if err != nil {
@@ -92,6 +109,11 @@ func (h *WatchlistHandler) List(w http.ResponseWriter, r *http.Request) {
 	// Enrich with artwork URLs from metadata cache
 	enrichWatchlistArtwork(items, metadataSvc)
 
+	if sortBy == watchlist.SortRelease {
+		enrichDisplayListReleases(r, items, metadataSvc)
+	}
+	_ = watchlist.SortItems(items, sortBy, sortDir)
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(items)
 }
@@ -116,7 +138,8 @@ func (h *WatchlistHandler) Add(w http.ResponseWriter, r *http.Request) {
 		switch {
 		case errors.Is(err, watchlist.ErrUserIDRequired):
 			status = http.StatusBadRequest
-		case errors.Is(err, watchlist.ErrIDRequired), errors.Is(err, watchlist.ErrMediaTypeRequired):
+		case errors.Is(err, watchlist.ErrIDRequired), errors.Is(err, watchlist.ErrMediaTypeRequired),
+			errors.Is(err, watchlist.ErrInvalidPriority):
 			status = http.StatusBadRequest
 		case errors.Is(err, watchlist.ErrStorageDirRequired):
 			status = http.StatusInternalServerError
@@ -143,6 +166,7 @@ func (h *WatchlistHandler) UpdateState(w http.ResponseWriter, r *http.Request) {
 	var body struct {
 		Watched  *bool       `json:"watched,omitempty"`
 		Progress interface{} `json:"progress,omitempty"`
+		Priority *string     `json:"priority,omitempty"`
 	}
 	dec := json.NewDecoder(r.Body)
 	dec.DisallowUnknownFields()
@@ -151,13 +175,24 @@ func (h *WatchlistHandler) UpdateState(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	item, err := h.Service.UpdateState(userID, mediaType, id, body.Watched, body.Progress)
+	var item models.WatchlistItem
+	var err error
+	if body.Priority != nil {
+		setter, ok := h.Service.(watchlistPrioritySetter)
+		if !ok {
+			http.Error(w, "watchlist priority is not supported", http.StatusNotImplemented)
+			return
+		}
+		item, err = setter.SetPriority(userID, mediaType, id, *body.Priority)
+	} else {
+		item, err = h.Service.UpdateState(userID, mediaType, id, body.Watched, body.Progress)
+	}
 	if err != nil {
 		status := http.StatusInternalServerError
 		switch {
 		case errors.Is(err, watchlist.ErrUserIDRequired):
 			status = http.StatusBadRequest
-		case errors.Is(err, watchlist.ErrIdentifierRequired):
+		case errors.Is(err, watchlist.ErrIdentifierRequired), errors.Is(err, watchlist.ErrInvalidPriority):
 			status = http.StatusBadRequest
 		case errors.Is(err, os.ErrNotExist):
 			status = http.StatusNotFound
@@ -302,3 +302,67 @@ func TestEnrichMissingArtwork_SkipsItemsWithThumbnail(t *testing.T) {
 		t.Fatalf("expected no details fetch for an item with a thumbnail, got %d", meta.detailsCalls)
 	}
 }
+
+func TestWatchlistPriorityAndSort(t *testing.T) {
+	dir := t.TempDir()
+	svc, err := watchlist.NewService(dir)
+	if err != nil {
+		t.Fatalf("failed to create watchlist service: %v", err)
+	}
+	userSvc, err := users.NewService(dir)
+	if err != nil {
+		t.Fatalf("failed to create users service: %v", err)
+	}
+	userID := userSvc.ListAll()[0].ID
+
+	for _, id := range []string{"m1", "m2", "m3"} {
+		if _, err := svc.AddOrUpdate(userID, models.WatchlistUpsert{ID: id, MediaType: "movie", Name: id}); err != nil {
+			t.Fatalf("failed to seed watchlist: %v", err)
+		}
+	}
+
+	h := handlers.NewWatchlistHandler(svc, userSvc, false)
+
+	patch := func(id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/api/users/"+userID+"/watchlist/movie/"+id, bytes.NewReader([]byte(body)))
+		req = mux.SetURLVars(req, map[string]string{"userID": userID, "mediaType": "movie", "id": id})
+		rec := httptest.NewRecorder()
+		h.UpdateState(rec, req)
+		return rec
+	}
+	if rec := patch("m1", `{"priority":"high"}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := patch("m3", `{"priority":"someday"}`); rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if rec := patch("m2", `{"priority":"asap"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for invalid priority, got %d", rec.Code)
+	}
+	if rec := patch("missing", `{"priority":"high"}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status 404 for unknown item, got %d", rec.Code)
+	}
+
+	list := func(query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/users/"+userID+"/watchlist?"+query, nil)
+		req = mux.SetURLVars(req, map[string]string{"userID": userID})
+		rec := httptest.NewRecorder()
+		h.List(rec, req)
+		return rec
+	}
+	rec := list("sort=priority")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected list status 200, got %d", rec.Code)
+	}
+	var items []models.WatchlistItem
+	if err := json.Unmarshal(rec.Body.Bytes(), &items); err != nil {
+		t.Fatalf("failed to decode list response: %v", err)
+	}
+	if len(items) != 3 || items[0].ID != "m1" || items[1].ID != "m2" || items[2].ID != "m3" {
+		t.Fatalf("expected high, normal, someday order, got %+v", items)
+	}
+
+	if rec := list("sort=popularity"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 for unknown sort, got %d", rec.Code)
+	}
+}
@@ -102,6 +102,12 @@ func (ds *DataStore) FileHealth() FileHealthRepository   { return &pgFileHealthR
 func (ds *DataStore) MediaFiles() MediaFileRepository    { return &pgMediaFileRepo{pool: ds.pool} }
 func (ds *DataStore) LocalMedia() LocalMediaRepository   { return &pgLocalMediaRepo{pool: ds.pool} }
 func (ds *DataStore) Recordings() RecordingRepository    { return &pgRecordingRepo{pool: ds.pool} }
+func (ds *DataStore) RecordingRules() RecordingRuleRepository {
+	return &pgRecordingRuleRepo{pool: ds.pool}
+}
+func (ds *DataStore) ListShares() ListShareRepository     { return &pgListShareRepo{pool: ds.pool} }
+func (ds *DataStore) SavedFilters() SavedFilterRepository { return &pgSavedFilterRepo{pool: ds.pool} }
+func (ds *DataStore) WatchTime() WatchTimeRepository      { return &pgWatchTimeRepo{pool: ds.pool} }
 
 // --- Transaction support ---
 
@@ -152,3 +158,9 @@ func (t *Tx) FileHealth() FileHealthRepository   { return &pgFileHealthRepo{pool
 func (t *Tx) MediaFiles() MediaFileRepository    { return &pgMediaFileRepo{pool: t.tx} }
 func (t *Tx) LocalMedia() LocalMediaRepository   { return &pgLocalMediaRepo{pool: t.tx} }
 func (t *Tx) Recordings() RecordingRepository    { return &pgRecordingRepo{pool: t.tx} }
+func (t *Tx) RecordingRules() RecordingRuleRepository {
+	return &pgRecordingRuleRepo{pool: t.tx}
+}
+func (t *Tx) ListShares() ListShareRepository     { return &pgListShareRepo{pool: t.tx} }
+func (t *Tx) SavedFilters() SavedFilterRepository { return &pgSavedFilterRepo{pool: t.tx} }
+func (t *Tx) WatchTime() WatchTimeRepository      { return &pgWatchTimeRepo{pool: t.tx} }
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE content_preferences
+    ADD COLUMN IF NOT EXISTS anime_metadata BOOLEAN;
+
+-- +goose Down
+ALTER TABLE content_preferences
+    DROP COLUMN IF EXISTS anime_metadata;
@@ -0,0 +1,30 @@
+-- +goose Up
+CREATE TABLE recording_rules (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    match_type TEXT NOT NULL,
+    query TEXT NOT NULL,
+    channel_id TEXT NOT NULL DEFAULT '',
+    tvg_id TEXT NOT NULL DEFAULT '',
+    channel_name TEXT NOT NULL DEFAULT '',
+    new_only BOOLEAN NOT NULL DEFAULT FALSE,
+    priority INTEGER NOT NULL DEFAULT 0,
+    padding_before_seconds INTEGER NOT NULL DEFAULT 0,
+    padding_after_seconds INTEGER NOT NULL DEFAULT 0,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_recording_rules_user_id ON recording_rules(user_id);
+
+ALTER TABLE recordings
+    ADD COLUMN IF NOT EXISTS rule_id TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS episode TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE recordings
+    DROP COLUMN IF EXISTS rule_id,
+    DROP COLUMN IF EXISTS episode;
+
+DROP TABLE IF EXISTS recording_rules;
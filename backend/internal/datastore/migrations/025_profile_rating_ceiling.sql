@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS max_movie_rating TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS max_tv_rating TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE users
+    DROP COLUMN IF EXISTS max_movie_rating,
+    DROP COLUMN IF EXISTS max_tv_rating;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS kids_allowed_genres JSONB NOT NULL DEFAULT '[]';
+
+-- +goose Down
+ALTER TABLE users
+    DROP COLUMN IF EXISTS kids_allowed_genres;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE accounts
+    ADD COLUMN IF NOT EXISTS denied_features JSONB NOT NULL DEFAULT '[]';
+
+-- +goose Down
+ALTER TABLE accounts
+    DROP COLUMN IF EXISTS denied_features;
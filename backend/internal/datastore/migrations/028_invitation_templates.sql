@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE invitations
+    ADD COLUMN IF NOT EXISTS code TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS max_streams INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS denied_features JSONB NOT NULL DEFAULT '[]',
+    ADD COLUMN IF NOT EXISTS profile_template_id TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE invitations
+    DROP COLUMN IF EXISTS code,
+    DROP COLUMN IF EXISTS max_streams,
+    DROP COLUMN IF EXISTS denied_features,
+    DROP COLUMN IF EXISTS profile_template_id;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE playback_progress
+    ADD COLUMN IF NOT EXISTS session_id TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE playback_progress
+    DROP COLUMN IF EXISTS session_id;
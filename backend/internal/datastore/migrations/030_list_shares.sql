@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE list_shares (
+    id TEXT PRIMARY KEY,
+    token TEXT NOT NULL UNIQUE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    list_type TEXT NOT NULL,
+    list_id TEXT NOT NULL DEFAULT '',
+    title TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_list_shares_user_id ON list_shares(user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS list_shares;
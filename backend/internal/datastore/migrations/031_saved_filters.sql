@@ -0,0 +1,14 @@
+-- +goose Up
+CREATE TABLE saved_filters (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    filter JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX idx_saved_filters_user_id ON saved_filters(user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS saved_filters;
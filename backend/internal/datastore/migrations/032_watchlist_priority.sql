@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE watchlist ADD COLUMN priority TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE watchlist DROP COLUMN priority;
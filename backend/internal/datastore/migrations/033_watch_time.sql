@@ -0,0 +1,19 @@
+-- +goose Up
+CREATE TABLE watch_time_goals (
+    user_id TEXT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    weekly_minutes INTEGER NOT NULL,
+    warn_percent INTEGER NOT NULL DEFAULT 0,
+    hard_limit BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE watch_time_days (
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    seconds DOUBLE PRECISION NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, day)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS watch_time_days;
+DROP TABLE IF EXISTS watch_time_goals;
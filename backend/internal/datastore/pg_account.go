@@ -2,6 +2,7 @@ package datastore
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -16,21 +17,21 @@ type pgAccountRepo struct {
 
 func (r *pgAccountRepo) Get(ctx context.Context, id string) (*models.Account, error) {
 	row := r.pool.QueryRow(ctx, `
-		SELECT id, username, password_hash, is_master, max_streams, expires_at, created_at, updated_at
+		SELECT id, username, password_hash, is_master, max_streams, expires_at, denied_features, created_at, updated_at
 		FROM accounts WHERE id = $1`, id)
 	return scanAccount(row)
 }
 
 func (r *pgAccountRepo) GetByUsername(ctx context.Context, username string) (*models.Account, error) {
 	row := r.pool.QueryRow(ctx, `
-		SELECT id, username, password_hash, is_master, max_streams, expires_at, created_at, updated_at
+		SELECT id, username, password_hash, is_master, max_streams, expires_at, denied_features, created_at, updated_at
 		FROM accounts WHERE username = $1`, username)
 	return scanAccount(row)
 }
 
 func (r *pgAccountRepo) List(ctx context.Context) ([]models.Account, error) {
 	rows, err := r.pool.Query(ctx, `
-		SELECT id, username, password_hash, is_master, max_streams, expires_at, created_at, updated_at
+		SELECT id, username, password_hash, is_master, max_streams, expires_at, denied_features, created_at, updated_at
 		FROM accounts ORDER BY created_at`)
 	if err != nil {
 		return nil, fmt.Errorf("list accounts: %w", err)
@@ -49,11 +50,12 @@ func (r *pgAccountRepo) List(ctx context.Context) ([]models.Account, error) {
 }
 
 func (r *pgAccountRepo) Create(ctx context.Context, acct *models.Account) error {
+	deniedJSON, _ := json.Marshal(acct.DeniedFeatures)
 	_, err := r.pool.Exec(ctx, `
-		INSERT INTO accounts (id, username, password_hash, is_master, max_streams, expires_at, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		INSERT INTO accounts (id, username, password_hash, is_master, max_streams, expires_at, denied_features, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
 		acct.ID, acct.Username, acct.PasswordHash, acct.IsMaster, acct.MaxStreams,
-		acct.ExpiresAt, acct.CreatedAt, acct.UpdatedAt)
+		acct.ExpiresAt, deniedJSON, acct.CreatedAt, acct.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("create account: %w", err)
 	}
@@ -61,12 +63,13 @@ func (r *pgAccountRepo) Create(ctx context.Context, acct *models.Account) error
 }
 
 func (r *pgAccountRepo) Update(ctx context.Context, acct *models.Account) error {
+	deniedJSON, _ := json.Marshal(acct.DeniedFeatures)
 	_, err := r.pool.Exec(ctx, `
 		UPDATE accounts SET username=$2, password_hash=$3, is_master=$4, max_streams=$5,
-		expires_at=$6, updated_at=$7
+		expires_at=$6, denied_features=$7, updated_at=$8
 		WHERE id=$1`,
 		acct.ID, acct.Username, acct.PasswordHash, acct.IsMaster, acct.MaxStreams,
-		acct.ExpiresAt, acct.UpdatedAt)
+		acct.ExpiresAt, deniedJSON, acct.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("update account: %w", err)
 	}
@@ -89,23 +92,31 @@ func (r *pgAccountRepo) Count(ctx context.Context) (int64, error) {
 
 func scanAccount(row pgx.Row) (*models.Account, error) {
 	var a models.Account
+	var deniedJSON []byte
 	err := row.Scan(&a.ID, &a.Username, &a.PasswordHash, &a.IsMaster, &a.MaxStreams,
-		&a.ExpiresAt, &a.CreatedAt, &a.UpdatedAt)
+		&a.ExpiresAt, &deniedJSON, &a.CreatedAt, &a.UpdatedAt)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("scan account: %w", err)
 	}
+	if deniedJSON != nil {
+		_ = json.Unmarshal(deniedJSON, &a.DeniedFeatures)
+	}
 	return &a, nil
 }
 
 func scanAccountRows(rows pgx.Rows) (*models.Account, error) {
 	var a models.Account
+	var deniedJSON []byte
 	err := rows.Scan(&a.ID, &a.Username, &a.PasswordHash, &a.IsMaster, &a.MaxStreams,
-		&a.ExpiresAt, &a.CreatedAt, &a.UpdatedAt)
+		&a.ExpiresAt, &deniedJSON, &a.CreatedAt, &a.UpdatedAt)
 	if err != nil {
 		return nil, fmt.Errorf("scan account: %w", err)
 	}
+	if deniedJSON != nil {
+		_ = json.Unmarshal(deniedJSON, &a.DeniedFeatures)
+	}
 	return &a, nil
 }
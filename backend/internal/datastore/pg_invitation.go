@@ -2,6 +2,7 @@ package datastore
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -14,7 +15,7 @@ type pgInvitationRepo struct {
 	pool DB
 }
 
-const invCols = `id, token, created_by, expires_at, account_expires_in_hours, used_at, used_by, created_at`
+const invCols = `id, token, code, created_by, expires_at, account_expires_in_hours, max_streams, denied_features, profile_template_id, used_at, used_by, created_at`
 
 func (r *pgInvitationRepo) Get(ctx context.Context, id string) (*models.Invitation, error) {
 	row := r.pool.QueryRow(ctx, `SELECT `+invCols+` FROM invitations WHERE id = $1`, id)
@@ -35,22 +36,23 @@ func (r *pgInvitationRepo) List(ctx context.Context) ([]models.Invitation, error
 
 	var result []models.Invitation
 	for rows.Next() {
-		var inv models.Invitation
-		if err := rows.Scan(&inv.ID, &inv.Token, &inv.CreatedBy, &inv.ExpiresAt,
-			&inv.AccountExpiresInHours, &inv.UsedAt, &inv.UsedBy, &inv.CreatedAt); err != nil {
-			return nil, fmt.Errorf("scan invitation: %w", err)
+		inv, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
 		}
-		result = append(result, inv)
+		result = append(result, *inv)
 	}
 	return result, rows.Err()
 }
 
 func (r *pgInvitationRepo) Create(ctx context.Context, inv *models.Invitation) error {
+	deniedJSON, _ := json.Marshal(inv.DeniedFeatures)
 	_, err := r.pool.Exec(ctx, `
 		INSERT INTO invitations (`+invCols+`)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
-		inv.ID, inv.Token, inv.CreatedBy, inv.ExpiresAt,
-		inv.AccountExpiresInHours, inv.UsedAt, inv.UsedBy, inv.CreatedAt)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		inv.ID, inv.Token, inv.Code, inv.CreatedBy, inv.ExpiresAt,
+		inv.AccountExpiresInHours, inv.MaxStreams, deniedJSON, inv.ProfileTemplateID,
+		inv.UsedAt, inv.UsedBy, inv.CreatedAt)
 	if err != nil {
 		return fmt.Errorf("create invitation: %w", err)
 	}
@@ -58,12 +60,15 @@ func (r *pgInvitationRepo) Create(ctx context.Context, inv *models.Invitation) e
 }
 
 func (r *pgInvitationRepo) Update(ctx context.Context, inv *models.Invitation) error {
+	deniedJSON, _ := json.Marshal(inv.DeniedFeatures)
 	_, err := r.pool.Exec(ctx, `
-		UPDATE invitations SET token=$2, created_by=$3, expires_at=$4,
-		account_expires_in_hours=$5, used_at=$6, used_by=$7
+		UPDATE invitations SET token=$2, code=$3, created_by=$4, expires_at=$5,
+		account_expires_in_hours=$6, max_streams=$7, denied_features=$8,
+		profile_template_id=$9, used_at=$10, used_by=$11
 		WHERE id=$1`,
-		inv.ID, inv.Token, inv.CreatedBy, inv.ExpiresAt,
-		inv.AccountExpiresInHours, inv.UsedAt, inv.UsedBy)
+		inv.ID, inv.Token, inv.Code, inv.CreatedBy, inv.ExpiresAt,
+		inv.AccountExpiresInHours, inv.MaxStreams, deniedJSON,
+		inv.ProfileTemplateID, inv.UsedAt, inv.UsedBy)
 	if err != nil {
 		return fmt.Errorf("update invitation: %w", err)
 	}
@@ -83,13 +88,18 @@ func (r *pgInvitationRepo) Count(ctx context.Context) (int64, error) {
 
 func scanInvitation(row pgx.Row) (*models.Invitation, error) {
 	var inv models.Invitation
-	err := row.Scan(&inv.ID, &inv.Token, &inv.CreatedBy, &inv.ExpiresAt,
-		&inv.AccountExpiresInHours, &inv.UsedAt, &inv.UsedBy, &inv.CreatedAt)
+	var deniedJSON []byte
+	err := row.Scan(&inv.ID, &inv.Token, &inv.Code, &inv.CreatedBy, &inv.ExpiresAt,
+		&inv.AccountExpiresInHours, &inv.MaxStreams, &deniedJSON, &inv.ProfileTemplateID,
+		&inv.UsedAt, &inv.UsedBy, &inv.CreatedAt)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
 	}
 	if err != nil {
 		return nil, fmt.Errorf("scan invitation: %w", err)
 	}
+	if deniedJSON != nil {
+		_ = json.Unmarshal(deniedJSON, &inv.DeniedFeatures)
+	}
 	return &inv, nil
 }
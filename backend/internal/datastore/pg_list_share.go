@@ -0,0 +1,54 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+
+	"novastream/models"
+)
+
+type pgListShareRepo struct {
+	pool DB
+}
+
+const listShareCols = `id, token, user_id, list_type, list_id, title, created_at`
+
+func (r *pgListShareRepo) List(ctx context.Context) ([]models.ListShare, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+listShareCols+` FROM list_shares ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("list list shares: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.ListShare
+	for rows.Next() {
+		var share models.ListShare
+		if err := rows.Scan(&share.ID, &share.Token, &share.UserID, &share.ListType, &share.ListID, &share.Title, &share.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan list share: %w", err)
+		}
+		result = append(result, share)
+	}
+	return result, rows.Err()
+}
+
+func (r *pgListShareRepo) Create(ctx context.Context, share *models.ListShare) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO list_shares (`+listShareCols+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		share.ID, share.Token, share.UserID, share.ListType, share.ListID, share.Title, share.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("create list share: %w", err)
+	}
+	return nil
+}
+
+func (r *pgListShareRepo) Delete(ctx context.Context, id string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM list_shares WHERE id = $1`, id)
+	return err
+}
+
+func (r *pgListShareRepo) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM list_shares`).Scan(&count)
+	return count, err
+}
@@ -19,7 +19,7 @@ func (r *pgPlaybackProgressRepo) Get(ctx context.Context, userID, itemKey string
 	row := r.pool.QueryRow(ctx, `
 		SELECT item_key, media_type, item_id, position, duration, percent_watched, updated_at,
 		is_paused, watched_seconds, external_ids, season_number, episode_number, series_id, series_name,
-		episode_name, movie_name, year, hidden_from_continue_watching
+		episode_name, movie_name, year, hidden_from_continue_watching, session_id
 		FROM playback_progress WHERE user_id = $1 AND item_key = $2`, userID, itemKey)
 	return scanPlaybackProgress(row)
 }
@@ -28,7 +28,7 @@ func (r *pgPlaybackProgressRepo) ListByUser(ctx context.Context, userID string)
 	rows, err := r.pool.Query(ctx, `
 		SELECT item_key, media_type, item_id, position, duration, percent_watched, updated_at,
 		is_paused, watched_seconds, external_ids, season_number, episode_number, series_id, series_name,
-		episode_name, movie_name, year, hidden_from_continue_watching
+		episode_name, movie_name, year, hidden_from_continue_watching, session_id
 		FROM playback_progress WHERE user_id = $1 ORDER BY updated_at DESC`, userID)
 	if err != nil {
 		return nil, fmt.Errorf("list playback progress: %w", err)
@@ -41,7 +41,7 @@ func (r *pgPlaybackProgressRepo) ListAll(ctx context.Context) (map[string][]mode
 	rows, err := r.pool.Query(ctx, `
 		SELECT user_id, item_key, media_type, item_id, position, duration, percent_watched, updated_at,
 		is_paused, watched_seconds, external_ids, season_number, episode_number, series_id, series_name,
-		episode_name, movie_name, year, hidden_from_continue_watching
+		episode_name, movie_name, year, hidden_from_continue_watching, session_id
 		FROM playback_progress ORDER BY updated_at DESC`)
 	if err != nil {
 		return nil, fmt.Errorf("list all playback progress: %w", err)
@@ -56,7 +56,7 @@ func (r *pgPlaybackProgressRepo) ListAll(ctx context.Context) (map[string][]mode
 		if err := rows.Scan(&userID, &p.ID, &p.MediaType, &p.ItemID, &p.Position, &p.Duration,
 			&p.PercentWatched, &p.UpdatedAt, &p.IsPaused, &p.WatchedSeconds, &idsJSON,
 			&p.SeasonNumber, &p.EpisodeNumber, &p.SeriesID, &p.SeriesName,
-			&p.EpisodeName, &p.MovieName, &p.Year, &p.HiddenFromContinueWatching); err != nil {
+			&p.EpisodeName, &p.MovieName, &p.Year, &p.HiddenFromContinueWatching, &p.SessionID); err != nil {
 			return nil, fmt.Errorf("scan playback progress: %w", err)
 		}
 		_ = json.Unmarshal(idsJSON, &p.ExternalIDs)
@@ -70,16 +70,16 @@ func (r *pgPlaybackProgressRepo) Upsert(ctx context.Context, userID string, p *m
 	_, err := r.pool.Exec(ctx, `
 		INSERT INTO playback_progress (user_id, item_key, media_type, item_id, position, duration,
 		percent_watched, updated_at, is_paused, watched_seconds, external_ids, season_number, episode_number,
-		series_id, series_name, episode_name, movie_name, year, hidden_from_continue_watching)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19)
+		series_id, series_name, episode_name, movie_name, year, hidden_from_continue_watching, session_id)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20)
 		ON CONFLICT (user_id, item_key) DO UPDATE SET
 		position=$5, duration=$6, percent_watched=$7, updated_at=$8, is_paused=$9,
 		watched_seconds=$10, external_ids=$11, season_number=$12, episode_number=$13, series_id=$14, series_name=$15,
-		episode_name=$16, movie_name=$17, year=$18, hidden_from_continue_watching=$19`,
+		episode_name=$16, movie_name=$17, year=$18, hidden_from_continue_watching=$19, session_id=$20`,
 		userID, p.ID, p.MediaType, p.ItemID, p.Position, p.Duration,
 		p.PercentWatched, p.UpdatedAt, p.IsPaused, p.WatchedSeconds, idsJSON,
 		p.SeasonNumber, p.EpisodeNumber, p.SeriesID, p.SeriesName,
-		p.EpisodeName, p.MovieName, p.Year, p.HiddenFromContinueWatching)
+		p.EpisodeName, p.MovieName, p.Year, p.HiddenFromContinueWatching, p.SessionID)
 	if err != nil {
 		return fmt.Errorf("upsert playback progress: %w", err)
 	}
@@ -124,7 +124,7 @@ func scanPlaybackProgress(row pgx.Row) (*models.PlaybackProgress, error) {
 	err := row.Scan(&p.ID, &p.MediaType, &p.ItemID, &p.Position, &p.Duration,
 		&p.PercentWatched, &p.UpdatedAt, &p.IsPaused, &p.WatchedSeconds, &idsJSON,
 		&p.SeasonNumber, &p.EpisodeNumber, &p.SeriesID, &p.SeriesName,
-		&p.EpisodeName, &p.MovieName, &p.Year, &p.HiddenFromContinueWatching)
+		&p.EpisodeName, &p.MovieName, &p.Year, &p.HiddenFromContinueWatching, &p.SessionID)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
 	}
@@ -143,7 +143,7 @@ func collectPlaybackProgress(rows pgx.Rows) ([]models.PlaybackProgress, error) {
 		if err := rows.Scan(&p.ID, &p.MediaType, &p.ItemID, &p.Position, &p.Duration,
 			&p.PercentWatched, &p.UpdatedAt, &p.IsPaused, &p.WatchedSeconds, &idsJSON,
 			&p.SeasonNumber, &p.EpisodeNumber, &p.SeriesID, &p.SeriesName,
-			&p.EpisodeName, &p.MovieName, &p.Year, &p.HiddenFromContinueWatching); err != nil {
+			&p.EpisodeName, &p.MovieName, &p.Year, &p.HiddenFromContinueWatching, &p.SessionID); err != nil {
 			return nil, fmt.Errorf("scan playback progress: %w", err)
 		}
 		_ = json.Unmarshal(idsJSON, &p.ExternalIDs)
@@ -0,0 +1,127 @@
+package datastore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"novastream/models"
+)
+
+type pgRecordingRuleRepo struct {
+	pool DB
+}
+
+const recordingRuleColumns = `id, user_id, match_type, query, channel_id, tvg_id, channel_name, new_only, priority,
+		       padding_before_seconds, padding_after_seconds, enabled, created_at, updated_at`
+
+func (r *pgRecordingRuleRepo) Get(ctx context.Context, id string) (*models.RecordingRule, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+recordingRuleColumns+` FROM recording_rules WHERE id = $1`, id)
+	return scanRecordingRule(row)
+}
+
+func (r *pgRecordingRuleRepo) List(ctx context.Context, userID string) ([]models.RecordingRule, error) {
+	query := `SELECT ` + recordingRuleColumns + ` FROM recording_rules`
+	args := []any{}
+	if userID = strings.TrimSpace(userID); userID != "" {
+		query += " WHERE user_id = $1"
+		args = append(args, userID)
+	}
+	query += " ORDER BY priority DESC, created_at ASC"
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list recording rules: %w", err)
+	}
+	defer rows.Close()
+
+	var rules []models.RecordingRule
+	for rows.Next() {
+		rule, err := scanRecordingRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *pgRecordingRuleRepo) Create(ctx context.Context, rule *models.RecordingRule) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO recording_rules (
+			id, user_id, match_type, query, channel_id, tvg_id, channel_name, new_only, priority,
+			padding_before_seconds, padding_after_seconds, enabled, created_at, updated_at
+		) VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14)`,
+		rule.ID, rule.UserID, string(rule.Match), rule.Query, rule.ChannelID, rule.TvgID, rule.ChannelName,
+		rule.NewOnly, rule.Priority, rule.PaddingBeforeSeconds, rule.PaddingAfterSeconds, rule.Enabled,
+		rule.CreatedAt, rule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("create recording rule: %w", err)
+	}
+	return nil
+}
+
+func (r *pgRecordingRuleRepo) Update(ctx context.Context, rule *models.RecordingRule) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE recording_rules
+		SET match_type = $2,
+		    query = $3,
+		    channel_id = $4,
+		    tvg_id = $5,
+		    channel_name = $6,
+		    new_only = $7,
+		    priority = $8,
+		    padding_before_seconds = $9,
+		    padding_after_seconds = $10,
+		    enabled = $11,
+		    updated_at = $12
+		WHERE id = $1`,
+		rule.ID, string(rule.Match), rule.Query, rule.ChannelID, rule.TvgID, rule.ChannelName, rule.NewOnly,
+		rule.Priority, rule.PaddingBeforeSeconds, rule.PaddingAfterSeconds, rule.Enabled, rule.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("update recording rule: %w", err)
+	}
+	return nil
+}
+
+func (r *pgRecordingRuleRepo) Delete(ctx context.Context, id string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM recording_rules WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete recording rule: %w", err)
+	}
+	return nil
+}
+
+func scanRecordingRule(row interface {
+	Scan(dest ...any) error
+}) (*models.RecordingRule, error) {
+	var rule models.RecordingRule
+	var match string
+	err := row.Scan(
+		&rule.ID,
+		&rule.UserID,
+		&match,
+		&rule.Query,
+		&rule.ChannelID,
+		&rule.TvgID,
+		&rule.ChannelName,
+		&rule.NewOnly,
+		&rule.Priority,
+		&rule.PaddingBeforeSeconds,
+		&rule.PaddingAfterSeconds,
+		&rule.Enabled,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("scan recording rule: %w", err)
+	}
+	rule.Match = models.RecordingRuleMatch(match)
+	return &rule, nil
+}
@@ -20,7 +20,7 @@ func (r *pgRecordingRepo) Get(ctx context.Context, id string) (*models.Recording
 	row := r.pool.QueryRow(ctx, `
 		SELECT id, user_id, type, status, channel_id, tvg_id, channel_name, title, description,
 		       source_url, start_at, end_at, padding_before_seconds, padding_after_seconds,
-		       output_path, output_size_bytes, actual_start_at, actual_end_at, error, created_at, updated_at
+		       output_path, output_size_bytes, actual_start_at, actual_end_at, error, rule_id, episode, created_at, updated_at
 		FROM recordings WHERE id = $1`, id)
 	return scanRecording(row)
 }
@@ -48,7 +48,7 @@ func (r *pgRecordingRepo) List(ctx context.Context, filter models.RecordingListF
 	query := `
 		SELECT id, user_id, type, status, channel_id, tvg_id, channel_name, title, description,
 		       source_url, start_at, end_at, padding_before_seconds, padding_after_seconds,
-		       output_path, output_size_bytes, actual_start_at, actual_end_at, error, created_at, updated_at
+		       output_path, output_size_bytes, actual_start_at, actual_end_at, error, rule_id, episode, created_at, updated_at
 		FROM recordings`
 	if len(clauses) > 0 {
 		query += " WHERE " + strings.Join(clauses, " AND ")
@@ -81,17 +81,17 @@ func (r *pgRecordingRepo) Create(ctx context.Context, recording *models.Recordin
 		INSERT INTO recordings (
 			id, user_id, type, status, channel_id, tvg_id, channel_name, title, description, source_url,
 			start_at, end_at, padding_before_seconds, padding_after_seconds,
-			output_path, output_size_bytes, actual_start_at, actual_end_at, error, created_at, updated_at
+			output_path, output_size_bytes, actual_start_at, actual_end_at, error, rule_id, episode, created_at, updated_at
 		) VALUES (
 			$1,$2,$3,$4,$5,$6,$7,$8,$9,$10,
 			$11,$12,$13,$14,
-			$15,$16,$17,$18,$19,$20,$21
+			$15,$16,$17,$18,$19,$20,$21,$22,$23
 		)`,
 		recording.ID, recording.UserID, string(recording.Type), string(recording.Status), recording.ChannelID, recording.TvgID,
 		recording.ChannelName, recording.Title, recording.Description, recording.SourceURL,
 		recording.StartAt, recording.EndAt, recording.PaddingBeforeSeconds, recording.PaddingAfterSeconds,
 		recording.OutputPath, recording.OutputSizeBytes, recording.ActualStartAt, recording.ActualEndAt, recording.Error,
-		recording.CreatedAt, recording.UpdatedAt)
+		recording.RuleID, recording.Episode, recording.CreatedAt, recording.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("create recording: %w", err)
 	}
@@ -185,6 +185,8 @@ func scanRecording(row interface {
 		&recording.ActualStartAt,
 		&recording.ActualEndAt,
 		&recording.Error,
+		&recording.RuleID,
+		&recording.Episode,
 		&recording.CreatedAt,
 		&recording.UpdatedAt,
 	)
@@ -0,0 +1,62 @@
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"novastream/models"
+)
+
+type pgSavedFilterRepo struct {
+	pool DB
+}
+
+const savedFilterCols = `id, user_id, name, filter, created_at, updated_at`
+
+func (r *pgSavedFilterRepo) List(ctx context.Context) ([]models.SavedFilter, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+savedFilterCols+` FROM saved_filters ORDER BY created_at`)
+	if err != nil {
+		return nil, fmt.Errorf("list saved filters: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.SavedFilter
+	for rows.Next() {
+		var saved models.SavedFilter
+		var filterJSON []byte
+		if err := rows.Scan(&saved.ID, &saved.UserID, &saved.Name, &filterJSON, &saved.CreatedAt, &saved.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan saved filter: %w", err)
+		}
+		_ = json.Unmarshal(filterJSON, &saved.Filter)
+		result = append(result, saved)
+	}
+	return result, rows.Err()
+}
+
+func (r *pgSavedFilterRepo) Upsert(ctx context.Context, saved *models.SavedFilter) error {
+	filterJSON, _ := json.Marshal(saved.Filter)
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO saved_filters (`+savedFilterCols+`)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (id) DO UPDATE SET
+			name = EXCLUDED.name,
+			filter = EXCLUDED.filter,
+			updated_at = EXCLUDED.updated_at`,
+		saved.ID, saved.UserID, saved.Name, filterJSON, saved.CreatedAt, saved.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("upsert saved filter: %w", err)
+	}
+	return nil
+}
+
+func (r *pgSavedFilterRepo) Delete(ctx context.Context, id string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM saved_filters WHERE id = $1`, id)
+	return err
+}
+
+func (r *pgSavedFilterRepo) Count(ctx context.Context) (int64, error) {
+	var count int64
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM saved_filters`).Scan(&count)
+	return count, err
+}
@@ -17,7 +17,7 @@ type pgUserRepo struct {
 
 const userColumns = `id, account_id, name, color, icon_url, pin_hash, trakt_account_id, plex_account_id,
 	mdblist_account_id, simkl_account_id, is_kids_profile, kids_mode, kids_max_rating, kids_max_movie_rating, kids_max_tv_rating,
-	kids_allowed_lists, created_at, updated_at`
+	kids_allowed_lists, kids_allowed_genres, max_movie_rating, max_tv_rating, calendar_feed_token_hash, created_at, updated_at`
 
 func (r *pgUserRepo) Get(ctx context.Context, id string) (*models.User, error) {
 	row := r.pool.QueryRow(ctx, `SELECT `+userColumns+` FROM users WHERE id = $1`, id)
@@ -44,13 +44,14 @@ func (r *pgUserRepo) List(ctx context.Context) ([]models.User, error) {
 
 func (r *pgUserRepo) Create(ctx context.Context, user *models.User) error {
 	listsJSON, _ := json.Marshal(user.KidsAllowedLists)
+	genresJSON, _ := json.Marshal(user.KidsAllowedGenres)
 	_, err := r.pool.Exec(ctx, `
 		INSERT INTO users (`+userColumns+`)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18)`,
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22)`,
 		user.ID, user.AccountID, user.Name, user.Color, user.IconURL, user.PinHash,
 		user.TraktAccountID, user.PlexAccountID, user.MdblistAccountID, user.SimklAccountID, user.IsKidsProfile,
 		user.KidsMode, user.KidsMaxRating, user.KidsMaxMovieRating, user.KidsMaxTVRating,
-		listsJSON, user.CreatedAt, user.UpdatedAt)
+		listsJSON, genresJSON, user.MaxMovieRating, user.MaxTVRating, user.CalendarFeedTokenHash, user.CreatedAt, user.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("create user: %w", err)
 	}
@@ -59,16 +60,17 @@ func (r *pgUserRepo) Create(ctx context.Context, user *models.User) error {
 
 func (r *pgUserRepo) Update(ctx context.Context, user *models.User) error {
 	listsJSON, _ := json.Marshal(user.KidsAllowedLists)
+	genresJSON, _ := json.Marshal(user.KidsAllowedGenres)
 	_, err := r.pool.Exec(ctx, `
 		UPDATE users SET account_id=$2, name=$3, color=$4, icon_url=$5, pin_hash=$6,
 		trakt_account_id=$7, plex_account_id=$8, mdblist_account_id=$9, simkl_account_id=$10, is_kids_profile=$11,
 		kids_mode=$12, kids_max_rating=$13, kids_max_movie_rating=$14, kids_max_tv_rating=$15,
-		kids_allowed_lists=$16, updated_at=$17
+		kids_allowed_lists=$16, kids_allowed_genres=$17, max_movie_rating=$18, max_tv_rating=$19, calendar_feed_token_hash=$20, updated_at=$21
 		WHERE id=$1`,
 		user.ID, user.AccountID, user.Name, user.Color, user.IconURL, user.PinHash,
 		user.TraktAccountID, user.PlexAccountID, user.MdblistAccountID, user.SimklAccountID, user.IsKidsProfile,
 		user.KidsMode, user.KidsMaxRating, user.KidsMaxMovieRating, user.KidsMaxTVRating,
-		listsJSON, user.UpdatedAt)
+		listsJSON, genresJSON, user.MaxMovieRating, user.MaxTVRating, user.CalendarFeedTokenHash, user.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("update user: %w", err)
 	}
@@ -91,11 +93,11 @@ func (r *pgUserRepo) Count(ctx context.Context) (int64, error) {
 
 func scanUser(row pgx.Row) (*models.User, error) {
 	var u models.User
-	var listsJSON []byte
+	var listsJSON, genresJSON []byte
 	err := row.Scan(&u.ID, &u.AccountID, &u.Name, &u.Color, &u.IconURL, &u.PinHash,
 		&u.TraktAccountID, &u.PlexAccountID, &u.MdblistAccountID, &u.SimklAccountID, &u.IsKidsProfile,
 		&u.KidsMode, &u.KidsMaxRating, &u.KidsMaxMovieRating, &u.KidsMaxTVRating,
-		&listsJSON, &u.CreatedAt, &u.UpdatedAt)
+		&listsJSON, &genresJSON, &u.MaxMovieRating, &u.MaxTVRating, &u.CalendarFeedTokenHash, &u.CreatedAt, &u.UpdatedAt)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
 	}
@@ -105,6 +107,9 @@ func scanUser(row pgx.Row) (*models.User, error) {
 	if listsJSON != nil {
 		_ = json.Unmarshal(listsJSON, &u.KidsAllowedLists)
 	}
+	if genresJSON != nil {
+		_ = json.Unmarshal(genresJSON, &u.KidsAllowedGenres)
+	}
 	return &u, nil
 }
 
@@ -112,17 +117,20 @@ func collectUsers(rows pgx.Rows) ([]models.User, error) {
 	var result []models.User
 	for rows.Next() {
 		var u models.User
-		var listsJSON []byte
+		var listsJSON, genresJSON []byte
 		err := rows.Scan(&u.ID, &u.AccountID, &u.Name, &u.Color, &u.IconURL, &u.PinHash,
 			&u.TraktAccountID, &u.PlexAccountID, &u.MdblistAccountID, &u.SimklAccountID, &u.IsKidsProfile,
 			&u.KidsMode, &u.KidsMaxRating, &u.KidsMaxMovieRating, &u.KidsMaxTVRating,
-			&listsJSON, &u.CreatedAt, &u.UpdatedAt)
+			&listsJSON, &genresJSON, &u.MaxMovieRating, &u.MaxTVRating, &u.CalendarFeedTokenHash, &u.CreatedAt, &u.UpdatedAt)
 		if err != nil {
 			return nil, fmt.Errorf("scan user: %w", err)
 		}
 		if listsJSON != nil {
 			_ = json.Unmarshal(listsJSON, &u.KidsAllowedLists)
 		}
+		if genresJSON != nil {
+			_ = json.Unmarshal(genresJSON, &u.KidsAllowedGenres)
+		}
 		result = append(result, u)
 	}
 	return result, rows.Err()
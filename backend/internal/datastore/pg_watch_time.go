@@ -0,0 +1,91 @@
+package datastore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"novastream/models"
+)
+
+type pgWatchTimeRepo struct {
+	pool DB
+}
+
+func (r *pgWatchTimeRepo) ListGoals(ctx context.Context) ([]models.WatchTimeGoal, error) {
+	rows, err := r.pool.Query(ctx, `SELECT user_id, weekly_minutes, warn_percent, hard_limit, updated_at FROM watch_time_goals`)
+	if err != nil {
+		return nil, fmt.Errorf("list watch time goals: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.WatchTimeGoal
+	for rows.Next() {
+		var goal models.WatchTimeGoal
+		if err := rows.Scan(&goal.UserID, &goal.WeeklyMinutes, &goal.WarnPercent, &goal.HardLimit, &goal.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan watch time goal: %w", err)
+		}
+		result = append(result, goal)
+	}
+	return result, rows.Err()
+}
+
+func (r *pgWatchTimeRepo) UpsertGoal(ctx context.Context, goal *models.WatchTimeGoal) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO watch_time_goals (user_id, weekly_minutes, warn_percent, hard_limit, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE SET
+			weekly_minutes = EXCLUDED.weekly_minutes,
+			warn_percent = EXCLUDED.warn_percent,
+			hard_limit = EXCLUDED.hard_limit,
+			updated_at = EXCLUDED.updated_at`,
+		goal.UserID, goal.WeeklyMinutes, goal.WarnPercent, goal.HardLimit, goal.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("upsert watch time goal: %w", err)
+	}
+	return nil
+}
+
+func (r *pgWatchTimeRepo) DeleteGoal(ctx context.Context, userID string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM watch_time_goals WHERE user_id = $1`, userID)
+	return err
+}
+
+// ListDays returns the daily totals on or after since (YYYY-MM-DD).
+func (r *pgWatchTimeRepo) ListDays(ctx context.Context, since string) ([]models.WatchTimeDay, error) {
+	rows, err := r.pool.Query(ctx, `SELECT user_id, day, seconds FROM watch_time_days WHERE day >= $1::date`, since)
+	if err != nil {
+		return nil, fmt.Errorf("list watch time days: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.WatchTimeDay
+	for rows.Next() {
+		var day models.WatchTimeDay
+		var date time.Time
+		if err := rows.Scan(&day.UserID, &date, &day.Seconds); err != nil {
+			return nil, fmt.Errorf("scan watch time day: %w", err)
+		}
+		day.Date = date.Format("2006-01-02")
+		result = append(result, day)
+	}
+	return result, rows.Err()
+}
+
+func (r *pgWatchTimeRepo) UpsertDay(ctx context.Context, day *models.WatchTimeDay) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO watch_time_days (user_id, day, seconds)
+		VALUES ($1, $2::date, $3)
+		ON CONFLICT (user_id, day) DO UPDATE SET seconds = EXCLUDED.seconds`,
+		day.UserID, day.Date, day.Seconds)
+	if err != nil {
+		return fmt.Errorf("upsert watch time day: %w", err)
+	}
+	return nil
+}
+
+// DeleteDaysBefore removes daily totals older than before (YYYY-MM-DD).
+func (r *pgWatchTimeRepo) DeleteDaysBefore(ctx context.Context, before string) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM watch_time_days WHERE day < $1::date`, before)
+	return err
+}
@@ -53,6 +53,33 @@ type InvitationRepository interface {
 	Count(ctx context.Context) (int64, error)
 }
 
+// ListShareRepository manages public list sharing links.
+type ListShareRepository interface {
+	List(ctx context.Context) ([]models.ListShare, error)
+	Create(ctx context.Context, share *models.ListShare) error
+	Delete(ctx context.Context, id string) error
+	Count(ctx context.Context) (int64, error)
+}
+
+// SavedFilterRepository manages profiles' saved discover filters.
+type SavedFilterRepository interface {
+	List(ctx context.Context) ([]models.SavedFilter, error)
+	Upsert(ctx context.Context, saved *models.SavedFilter) error
+	Delete(ctx context.Context, id string) error
+	Count(ctx context.Context) (int64, error)
+}
+
+// WatchTimeRepository manages profiles' watch time goals and daily playback
+// totals. Days are YYYY-MM-DD strings.
+type WatchTimeRepository interface {
+	ListGoals(ctx context.Context) ([]models.WatchTimeGoal, error)
+	UpsertGoal(ctx context.Context, goal *models.WatchTimeGoal) error
+	DeleteGoal(ctx context.Context, userID string) error
+	ListDays(ctx context.Context, since string) ([]models.WatchTimeDay, error)
+	UpsertDay(ctx context.Context, day *models.WatchTimeDay) error
+	DeleteDaysBefore(ctx context.Context, before string) error
+}
+
 type RemoteAccessInviteRepository interface {
 	Get(ctx context.Context, id string) (*models.RemoteAccessInvite, error)
 	GetByTokenHash(ctx context.Context, tokenHash string) (*models.RemoteAccessInvite, error)
@@ -223,3 +250,12 @@ type RecordingRepository interface {
 	Count(ctx context.Context) (int64, error)
 	MarkStaleActiveAsFailed(ctx context.Context, now time.Time) (int64, error)
 }
+
+type RecordingRuleRepository interface {
+	Get(ctx context.Context, id string) (*models.RecordingRule, error)
+	// List returns the rules of userID, or every rule when userID is empty.
+	List(ctx context.Context, userID string) ([]models.RecordingRule, error)
+	Create(ctx context.Context, rule *models.RecordingRule) error
+	Update(ctx context.Context, rule *models.RecordingRule) error
+	Delete(ctx context.Context, id string) error
+}
@@ -333,3 +333,29 @@ func TestResolveSanitizesDoublePrefixedAndTokenIDs(t *testing.T) {
 		t.Fatalf("URL ID = %q, want token stripped", urlIdentity.ID)
 	}
 }
+
+func TestReplaceSeriesTVDBID(t *testing.T) {
+	cases := []struct {
+		id, want string
+		ok       bool
+	}{
+		{"tvdb:81189", "tvdb:99999", true},
+		{"tvdb:series:81189", "tvdb:series:99999", true},
+		{"tvdb:series:81189:s01e02", "tvdb:series:99999:s01e02", true},
+		{"tvdb:811890", "tvdb:811890", false},
+		{"tmdb:tv:81189", "tmdb:tv:81189", false},
+		{"tvdb:movie:81189", "tvdb:movie:81189", false},
+	}
+	for _, tc := range cases {
+		got, ok := ReplaceSeriesTVDBID(tc.id, "81189", "99999")
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("ReplaceSeriesTVDBID(%q) = %q, %v; want %q, %v", tc.id, got, ok, tc.want, tc.ok)
+		}
+	}
+
+	ext := map[string]string{"tvdb": "81189", "imdb": "tt0903747"}
+	replaced, ok := ReplaceSeriesTVDBExternalID(ext, "81189", "99999")
+	if !ok || replaced["tvdb"] != "99999" || replaced["imdb"] != "tt0903747" || ext["tvdb"] != "81189" {
+		t.Fatalf("unexpected external IDs %v (original %v)", replaced, ext)
+	}
+}
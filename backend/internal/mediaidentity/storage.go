@@ -238,3 +238,39 @@ func isSeriesExternalIDKey(key string) bool {
 		return false
 	}
 }
+
+// ReplaceSeriesTVDBID rewrites a series or episode ID that names TVDB series
+// oldID ("tvdb:81189", "tvdb:series:81189:s01e02") to name newID instead, for
+// when TVDB merges or replaces a series. Other IDs are returned unchanged.
+func ReplaceSeriesTVDBID(id, oldID, newID string) (string, bool) {
+	lower := strings.ToLower(id)
+	for _, prefix := range []string{"tvdb:series:", "tvdb:"} {
+		if !strings.HasPrefix(lower, prefix) {
+			continue
+		}
+		numeric, rest, hasRest := strings.Cut(id[len(prefix):], ":")
+		if numeric != oldID {
+			return id, false
+		}
+		replaced := id[:len(prefix)] + newID
+		if hasRest {
+			replaced += ":" + rest
+		}
+		return replaced, true
+	}
+	return id, false
+}
+
+// ReplaceSeriesTVDBExternalID returns a copy of externalIDs whose series
+// "tvdb" entry is oldID rewritten to newID.
+func ReplaceSeriesTVDBExternalID(externalIDs map[string]string, oldID, newID string) (map[string]string, bool) {
+	if strings.TrimSpace(externalIDs["tvdb"]) != oldID {
+		return externalIDs, false
+	}
+	replaced := make(map[string]string, len(externalIDs))
+	for k, v := range externalIDs {
+		replaced[k] = v
+	}
+	replaced["tvdb"] = newID
+	return replaced, true
+}
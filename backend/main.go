@@ -28,6 +28,7 @@ import (
 	"novastream/internal/pool"
 	internalusenet "novastream/internal/usenet"
 	"novastream/internal/webdav"
+	"novastream/models"
 	"novastream/services/accounts"
 	"novastream/services/backup"
 	"novastream/services/calendar"
@@ -39,27 +40,36 @@ import (
 	"novastream/services/debrid"
 	"novastream/services/epg"
 	"novastream/services/history"
+	"novastream/services/imdb"
 	"novastream/services/indexer"
 	"novastream/services/invitations"
 	"novastream/services/jellyfin"
 	"novastream/services/letterboxd"
+	"novastream/services/listshares"
 	"novastream/services/localmedia"
 	"novastream/services/mdblist"
 	"novastream/services/metadata"
+	"novastream/services/notifications"
+	"novastream/services/overseerr"
 	"novastream/services/playback"
 	"novastream/services/plex"
 	"novastream/services/prewarm"
 	"novastream/services/recordings"
 	"novastream/services/remoteaccess"
+	"novastream/services/savedfilters"
 	"novastream/services/scheduler"
 	"novastream/services/sessions"
 	"novastream/services/simkl"
+	"novastream/services/storage"
 	"novastream/services/streaming"
+	"novastream/services/streamingavailability"
 	"novastream/services/trakt"
 	"novastream/services/usenet"
 	user_settings "novastream/services/user_settings"
 	"novastream/services/users"
 	"novastream/services/watchlist"
+	"novastream/services/watchparty"
+	"novastream/services/watchtime"
 	"novastream/utils"
 
 	"github.com/gorilla/mux"
@@ -126,6 +136,7 @@ func main() {
 	if *portOverride > 0 {
 		settings.Server.Port = *portOverride
 	}
+	handlers.SetTrustedProxies(settings.Server.TrustedProxies)
 
 	// Initialize PostgreSQL DataStore if configured
 	var store *datastore.DataStore
@@ -206,9 +217,37 @@ func main() {
 		Model:    settings.Metadata.AIModel,
 		BaseURL:  settings.Metadata.AIBaseURL,
 	})
+	if err := metadataService.ConfigureCacheBackend(settings.Cache.MetadataBackend, settings.Cache.MetadataMaxSizeMB); err != nil {
+		log.Printf("warning: %v; falling back to file metadata cache", err)
+	}
 	metadataService.SetAllowAdultSearch(settings.Metadata.AllowAdultSearch)
+	metadataService.SetArtworkProviders(settings.Metadata.FanartAPIKey, settings.Metadata.ArtworkProviderPriority)
 	metadataService.SetYTDLPProxyURL(settings.Playback.YouTubeProxyURL)
+	metadataService.SetTrailerPrequeuePolicy(handlers.TrailerPrequeuePolicy(settings.Playback.TrailerPrequeue))
+	metadataService.SetRateLimits(handlers.MetadataRateLimits(settings.Metadata.RateLimits))
+	metadataService.SetCertificationCountry(settings.Metadata.CertificationCountry)
+	metadataService.SetWatchProviderCountry(settings.Metadata.WatchProviderCountry)
+	metadataService.SetArtworkPreferences(handlers.ArtworkPreferences(settings.Metadata.Artwork))
+	metadataService.SetOffline(settings.Metadata.Offline)
+	metadataService.SetExternalProviders(handlers.ExternalMetadataProviders(settings.Metadata.ExternalProviders))
+	metadataService.SetTrailerPolicyIdleCheck(func() bool {
+		return len(handlers.GetStreamTracker().GetActiveStreams()) == 0
+	})
 	metadataHandler := handlers.NewMetadataHandler(metadataService, cfgManager)
+
+	// Storage monitor pauses prequeue, DVR, trailer downloads and backups
+	// while the volume they write to is low on free space.
+	storageMonitor := storage.NewMonitor(cfgManager,
+		storage.Volume{Name: storage.VolumeCache, Path: settings.Cache.Directory},
+		storage.Volume{Name: storage.VolumeTrailers, Path: metadataService.TrailerDir()},
+		storage.Volume{Name: storage.VolumeRecordings, Path: filepath.Join(settings.Cache.Directory, "recordings")},
+		storage.Volume{Name: storage.VolumeBackups, Path: filepath.Join(settings.Cache.Directory, "backups")},
+	)
+	storageNotifier := notifications.NewSender()
+	storageNotifier.SetSettingsSource(cfgManager)
+	storageMonitor.SetNotifier(storageNotifier)
+	storageMonitor.Start()
+	metadataService.SetTrailerDiskSpaceCheck(storageMonitor.SpaceCheck(storage.VolumeTrailers))
 	debridSearchService := debrid.NewSearchService(cfgManager)
 	indexerService := indexer.NewService(cfgManager, metadataService, debridSearchService)
 	indexerHandler := handlers.NewIndexerHandler(indexerService, *demoMode)
@@ -420,6 +459,9 @@ func main() {
 	debugHandler := handlers.NewDebugHandler(log.New(os.Stdout, "[debug] ", log.LstdFlags))
 	logsHandler := handlers.NewLogsHandler(log.New(os.Stdout, "[logs] ", log.LstdFlags), settings.Log.File)
 	logsHandler.SetDataStore(store)
+	logsHandler.SetConfigManager(cfgManager)
+	logsHandler.AddDiagnostics("storage", func() interface{} { return storageMonitor.Status() })
+	logsHandler.AddDiagnostics("metadataCache", func() interface{} { return metadataService.GetCacheManagerStatus() })
 
 	var watchlistService *watchlist.Service
 	if store != nil {
@@ -441,6 +483,36 @@ func main() {
 		log.Fatalf("failed to initialise custom lists: %v", err)
 	}
 	customListsHandler := handlers.NewCustomListsHandler(customListsService, userService)
+	var listSharesService *listshares.Service
+	if store != nil {
+		listSharesService, err = listshares.NewServiceWithStore(store)
+	} else {
+		listSharesService, err = listshares.NewService(settings.Cache.Directory)
+	}
+	if err != nil {
+		log.Fatalf("failed to initialise list shares: %v", err)
+	}
+	listSharesHandler := handlers.NewListSharesHandler(listSharesService, watchlistService, customListsService, userService, settings.Server.BasePath)
+	var savedFiltersService *savedfilters.Service
+	if store != nil {
+		savedFiltersService, err = savedfilters.NewServiceWithStore(store)
+	} else {
+		savedFiltersService, err = savedfilters.NewService(settings.Cache.Directory)
+	}
+	if err != nil {
+		log.Fatalf("failed to initialise saved filters: %v", err)
+	}
+	savedFiltersHandler := handlers.NewSavedFiltersHandler(savedFiltersService, userService, metadataHandler)
+	var watchTimeService *watchtime.Service
+	if store != nil {
+		watchTimeService, err = watchtime.NewServiceWithStore(store)
+	} else {
+		watchTimeService, err = watchtime.NewService(settings.Cache.Directory)
+	}
+	if err != nil {
+		log.Fatalf("failed to initialise watch time: %v", err)
+	}
+	watchTimeHandler := handlers.NewWatchTimeHandler(watchTimeService, userService)
 	displayListHandler := handlers.NewDisplayListHandler(watchlistService, customListsService, userService)
 
 	var userSettingsService *user_settings.Service
@@ -509,6 +581,12 @@ func main() {
 	}
 	// Wire up metadata service for continue watching generation
 	historyService.SetMetadataService(metadataService)
+	// Count playback progress towards weekly watch time goals
+	historyService.SetWatchTimeRecorder(func(userID string, seconds float64, at time.Time) {
+		if err := watchTimeService.Record(userID, seconds, at); err != nil {
+			log.Printf("[watchtime] failed to record playback for %s: %v", userID, err)
+		}
+	})
 
 	// Wire up Trakt scrobbler for syncing watch history
 	traktClient := trakt.NewClient("", "") // Credentials are per-account now
@@ -552,17 +630,31 @@ func main() {
 	customListsHandler.SetMetadataLanguageProviders(cfgManager, userSettingsService)
 	displayListHandler.SetMetadataService(metadataService)
 	displayListHandler.SetMetadataHandler(metadataHandler)
+	displayListHandler.SetSavedFilters(savedFiltersHandler)
 	// Wire up users service to metadata handler for kids profile filtering
 	metadataHandler.SetUsersService(userService)
 	metadataHandler.SetAccountsService(accountsService)
 	// Wire up watchlist service to metadata handler for AI recommendations
 	metadataHandler.SetWatchlistService(watchlistService)
 	metadataHandler.SetTraktClient(traktClient)
+	metadataService.RegisterTrendingProvider(trakt.NewTrendingProvider(traktClient, cfgManager))
+	metadataService.SetTraktListSource(trakt.NewListProvider(traktClient, cfgManager))
 	metadataHandler.SetSimklClient(simklClient)
 	mdblistListsClient := mdblist.NewListsClient(settings.MDBList.APIKey)
 	metadataHandler.SetMDBListListsClient(mdblistListsClient)
 	settingsHandler.SetMDBListListsClient(mdblistListsClient)
-	metadataHandler.SetLetterboxdClient(letterboxd.NewClient())
+	letterboxdClient := letterboxd.NewClient()
+	metadataService.SetLetterboxdClient(letterboxdClient)
+	overseerrClient := overseerr.NewClient("", "")
+	handlers.ConfigureOverseerr(overseerrClient, settings.Overseerr)
+	metadataHandler.SetRequestPassthrough(overseerrClient)
+	settingsHandler.SetOverseerrClient(overseerrClient)
+	streamingAvailabilityClient := streamingavailability.NewClient(settings.StreamingAvailability.APIKey)
+	settingsHandler.SetStreamingAvailabilityClient(streamingAvailabilityClient)
+	displayListHandler.SetLeavingSoon(streamingAvailabilityClient, cfgManager, userSettingsService)
+	metadataHandler.SetLetterboxdClient(letterboxdClient)
+	metadataService.SetIMDbClient(imdb.NewClient())
+	metadataService.SetSharedListClient(listshares.NewClient())
 
 	// Enrich missing artwork for existing watchlist items (one-time, background).
 	// Warms the metadata cache for externally-synced items (Trakt/MDBList/Plex)
@@ -598,11 +690,15 @@ func main() {
 	)
 	detailsBundleHandler.SetConfigManager(cfgManager)
 	detailsBundleHandler.SetUserSettingsProvider(userSettingsService)
+	detailsBundleHandler.SetRequestPassthrough(overseerrClient)
 
 	// Calendar service provides upcoming content from watchlist, history, and MDBList
 	calendarService := calendar.New(metadataService, watchlistService, historyService, userSettingsService, userService)
 	historyService.SetWatchStateChangedHook(calendarService.Invalidate)
 	calendarHandler := handlers.NewCalendarHandler(calendarService, userService, *demoMode)
+	calendarHandler.SetWatchProviderService(metadataService)
+	calendarHandler.SetUserSettingsService(userSettingsService)
+	calendarHandler.SetFeedTokenService(userService, settings.Server.BasePath)
 	startupHandler.SetCalendar(calendarService)
 
 	// Create prequeue handler now that history service is available
@@ -612,8 +708,20 @@ func main() {
 		prequeueHandler.GetStore().SetDataStore(store)
 	}
 	prequeueHandler.GetStore().SetStoragePath(settings.Cache.Directory)
+	prequeueHandler.SetDiskSpaceCheck(storageMonitor.SpaceCheck(storage.VolumeCache))
+	playbackGuard := handlers.NewPlaybackGuard(func(userID string) error {
+		return watchTimeService.CheckPlayback(userID, time.Now())
+	}, func(accountID string) []string {
+		var ids []string
+		for _, user := range userService.ListForAccount(accountID) {
+			ids = append(ids, user.ID)
+		}
+		return ids
+	}, cfgManager)
+	prequeueHandler.SetPlaybackGuard(playbackGuard)
 	historyHandler.SetPrequeueStore(prequeueHandler.GetStore())
 	startupHandler.SetPrequeueStore(prequeueHandler.GetStore())
+	detailsBundleHandler.SetInstantPlayResolver(prequeueHandler)
 
 	// Restore magnet links from persisted prequeue entries into the magnet registry
 	// so stale torrents can be re-added after a server restart
@@ -633,6 +741,7 @@ func main() {
 	var recordingsService *recordings.Service
 	if store != nil {
 		recordingsService = recordings.NewService(store.Recordings(), settings.Transmux.FFmpegPath, filepath.Join(settings.Cache.Directory, "recordings"))
+		recordingsService.SetDiskSpaceCheck(storageMonitor.SpaceCheck(storage.VolumeRecordings))
 	}
 	var recordingsStreamProvider streaming.Provider
 	if recordingsService != nil {
@@ -680,6 +789,7 @@ func main() {
 	)
 	videoHandler.SetThumbnailCacheDir(settings.Cache.Directory)
 	videoHandler.SetPrequeueStore(prequeueHandler.GetStore())
+	videoHandler.SetPlaybackGuard(playbackGuard)
 	localBaseURL := fmt.Sprintf("http://127.0.0.1:%d", settings.Server.Port)
 	videoHandler.SetLocalBaseURL(localBaseURL)
 
@@ -744,6 +854,7 @@ func main() {
 	// Create EPG service and handler for Electronic Program Guide
 	epgService := epg.NewService(settings.Cache.Directory, cfgManager)
 	epgHandler := handlers.NewEPGHandler(epgService, cfgManager, userSettingsService)
+	epgHandler.SetLiveHandler(liveHandler)
 	settingsHandler.SetEPGService(epgService)                     // Enable auto-refresh when new EPG sources are added
 	settingsHandler.SetUserSettingsService(userSettingsService)   // Enable stripping redundant overrides
 	settingsHandler.SetClientsLister(clientsService)              // Enable client→profile mapping
@@ -760,11 +871,18 @@ func main() {
 	subtitlesHandler := handlers.NewSubtitlesHandlerWithConfig(cfgManager)
 
 	// Create image proxy handler for resizing and caching TMDB images
-	imageHandler := handlers.NewImageHandler(settings.Cache.Directory)
+	imageHandler := handlers.NewImageHandler(settings.Cache.Directory, settings.Transmux.FFmpegPath)
 	settingsHandler.SetImageHandler(imageHandler)                // Enable clearing image cache
+	metadataService.SetArtworkPlaceholderSource(imageHandler)    // Blurhash/dominant color for posters and backdrops
 	settingsHandler.SetPrequeueStore(prequeueHandler.GetStore()) // Clear prequeue when ShowParsedBadges changes
 
 	recordingsHandler := handlers.NewRecordingsHandler(recordingsService, userService)
+	if recordingsService != nil {
+		// Recording rules schedule matching airings whenever the guide refreshes.
+		recordingsService.EnableRules(store.RecordingRules(), handlers.NewRecordingRuleGuide(epgHandler))
+		recordingsHandler.SetRuleService(recordingsService)
+		epgService.OnRefresh(func() { recordingsService.RunRuleEvaluation("epg refresh") })
+	}
 
 	// One-time shareable playback links: capture current stream + tracks, mint a
 	// short-lived stream-scoped session on open (single use).
@@ -804,6 +922,10 @@ func main() {
 		sessionsService,
 		userService,
 		shareHandler,
+		listSharesHandler,
+		savedFiltersHandler,
+		watchTimeHandler,
+		playbackGuard,
 		settings.Server.HomepageAPIKey,
 	)
 
@@ -811,6 +933,10 @@ func main() {
 	traktAccountsHandler := handlers.NewTraktAccountsHandler(cfgManager, traktClient, userService, accountsService)
 	api.RegisterTraktRoutes(r, traktAccountsHandler, sessionsService, accountsService)
 
+	// Watch parties: profiles share a playback session kept in sync over WebSocket.
+	watchPartyHandler := handlers.NewWatchPartyHandler(watchparty.NewService(), userService)
+	api.RegisterWatchPartyRoutes(r, watchPartyHandler, sessionsService, accountsService)
+
 	// Create Plex client and register Plex accounts handler
 	plexClient := plex.NewClient(plex.GenerateClientID())
 	plexAccountsHandler := handlers.NewPlexAccountsHandler(cfgManager, plexClient, userService, accountsService)
@@ -827,6 +953,11 @@ func main() {
 	schedulerService.SetMetadataService(metadataService)
 	schedulerService.SetSimklClient(simklClient)
 	schedulerService.SetUsersService(userService)
+	schedulerService.SetUserSettingsService(userSettingsService)
+	schedulerService.SetCalendarService(calendarService)
+	schedulerService.SetWatchProviderService(metadataService)
+	schedulerService.SetLeavingSoonSource(streamingAvailabilityClient)
+	schedulerService.SetWatchTimeService(watchTimeService)
 	schedulerService.SetJellyfinClient(jellyfinClient)
 	schedulerService.SetLocalMediaService(localMediaService)
 	scheduledTasksHandler := handlers.NewScheduledTasksHandler(cfgManager, schedulerService, userService)
@@ -973,9 +1104,11 @@ func main() {
 	r.HandleFunc("/admin/api/users/{userID}/kids/rating", adminUIHandler.RequireAuth(usersHandler.SetKidsMaxRating)).Methods(http.MethodPut)
 	r.HandleFunc("/admin/api/users/{userID}/kids/rating/movie", adminUIHandler.RequireAuth(usersHandler.SetKidsMaxMovieRating)).Methods(http.MethodPut)
 	r.HandleFunc("/admin/api/users/{userID}/kids/rating/tv", adminUIHandler.RequireAuth(usersHandler.SetKidsMaxTVRating)).Methods(http.MethodPut)
+	r.HandleFunc("/admin/api/users/{userID}/rating-ceiling", adminUIHandler.RequireAuth(usersHandler.SetRatingCeiling)).Methods(http.MethodPut)
 	r.HandleFunc("/admin/api/users/{userID}/kids/lists", adminUIHandler.RequireAuth(usersHandler.SetKidsAllowedLists)).Methods(http.MethodPut)
 	r.HandleFunc("/admin/api/users/{userID}/kids/lists", adminUIHandler.RequireAuth(usersHandler.AddKidsAllowedList)).Methods(http.MethodPost)
 	r.HandleFunc("/admin/api/users/{userID}/kids/lists", adminUIHandler.RequireAuth(usersHandler.RemoveKidsAllowedList)).Methods(http.MethodDelete)
+	r.HandleFunc("/admin/api/users/{userID}/kids/genres", adminUIHandler.RequireAuth(usersHandler.SetKidsAllowedGenres)).Methods(http.MethodPut)
 	r.HandleFunc("/admin/api/profiles/icon", adminUIHandler.RequireAuth(adminUIHandler.SetProfileIcon)).Methods(http.MethodPut)
 	r.HandleFunc("/admin/api/profiles/icon", adminUIHandler.RequireAuth(adminUIHandler.ClearProfileIcon)).Methods(http.MethodDelete)
 	r.HandleFunc("/admin/api/profiles/icon", adminUIHandler.RequireAuth(adminUIHandler.ServeProfileIcon)).Methods(http.MethodGet)
@@ -991,12 +1124,17 @@ func main() {
 	r.HandleFunc("/admin/api/live/epg/schedule", adminUIHandler.RequireAuth(epgHandler.GetSchedule)).Methods(http.MethodGet)
 	r.HandleFunc("/admin/api/live/epg/schedule/batch", adminUIHandler.RequireAuth(epgHandler.GetScheduleMultiple)).Methods(http.MethodGet)
 	r.HandleFunc("/admin/api/live/recordings", adminUIHandler.RequireAuth(recordingsHandler.List)).Methods(http.MethodGet)
-	r.HandleFunc("/admin/api/live/recordings/epg", adminUIHandler.RequireAuth(recordingsHandler.CreateEPG)).Methods(http.MethodPost)
-	r.HandleFunc("/admin/api/live/recordings/time-block", adminUIHandler.RequireAuth(recordingsHandler.CreateTimeBlock)).Methods(http.MethodPost)
+	r.HandleFunc("/admin/api/live/recordings/epg", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureLiveTVManagement, recordingsHandler.CreateEPG))).Methods(http.MethodPost)
+	r.HandleFunc("/admin/api/live/recordings/time-block", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureLiveTVManagement, recordingsHandler.CreateTimeBlock))).Methods(http.MethodPost)
+	r.HandleFunc("/admin/api/live/recordings/rules", adminUIHandler.RequireAuth(recordingsHandler.ListRules)).Methods(http.MethodGet)
+	r.HandleFunc("/admin/api/live/recordings/rules", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureLiveTVManagement, recordingsHandler.CreateRule))).Methods(http.MethodPost)
+	r.HandleFunc("/admin/api/live/recordings/rules/evaluate", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureLiveTVManagement, recordingsHandler.EvaluateRules))).Methods(http.MethodPost)
+	r.HandleFunc("/admin/api/live/recordings/rules/{ruleID}", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureLiveTVManagement, recordingsHandler.UpdateRule))).Methods(http.MethodPut)
+	r.HandleFunc("/admin/api/live/recordings/rules/{ruleID}", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureLiveTVManagement, recordingsHandler.DeleteRule))).Methods(http.MethodDelete)
 	r.HandleFunc("/admin/api/live/recordings/{recordingID}", adminUIHandler.RequireAuth(recordingsHandler.Get)).Methods(http.MethodGet)
-	r.HandleFunc("/admin/api/live/recordings/{recordingID}", adminUIHandler.RequireAuth(recordingsHandler.Delete)).Methods(http.MethodDelete)
+	r.HandleFunc("/admin/api/live/recordings/{recordingID}", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureLiveTVManagement, recordingsHandler.Delete))).Methods(http.MethodDelete)
 	r.HandleFunc("/admin/api/live/recordings/{recordingID}/stream", adminUIHandler.RequireAuth(recordingsHandler.Stream)).Methods(http.MethodGet)
-	r.HandleFunc("/admin/api/live/recordings/{recordingID}/cancel", adminUIHandler.RequireAuth(recordingsHandler.Cancel)).Methods(http.MethodPost)
+	r.HandleFunc("/admin/api/live/recordings/{recordingID}/cancel", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureLiveTVManagement, recordingsHandler.Cancel))).Methods(http.MethodPost)
 
 	// User account management endpoints (master account only)
 	r.HandleFunc("/admin/api/accounts", adminUIHandler.RequireAuth(adminUIHandler.GetUserAccounts)).Methods(http.MethodGet)
@@ -1005,6 +1143,9 @@ func main() {
 	r.HandleFunc("/admin/api/accounts", adminUIHandler.RequireAuth(adminUIHandler.DeleteUserAccount)).Methods(http.MethodDelete)
 	r.HandleFunc("/admin/api/accounts/password", adminUIHandler.RequireAuth(adminUIHandler.ResetUserAccountPassword)).Methods(http.MethodPut)
 	r.HandleFunc("/admin/api/accounts/max-streams", adminUIHandler.RequireMasterAuth(adminUIHandler.SetAccountMaxStreams)).Methods(http.MethodPut)
+	r.HandleFunc("/admin/api/accounts/features", adminUIHandler.RequireMasterAuth(adminUIHandler.SetAccountFeatures)).Methods(http.MethodPut)
+	r.HandleFunc("/admin/api/security/logins", adminUIHandler.RequireMasterAuth(adminUIHandler.GetLoginAudit)).Methods(http.MethodGet)
+	r.HandleFunc("/admin/api/security/lockouts", adminUIHandler.RequireMasterAuth(adminUIHandler.ClearLoginLockouts)).Methods(http.MethodDelete)
 	r.HandleFunc("/admin/api/accounts/default-password", adminUIHandler.RequireAuth(adminUIHandler.HasDefaultPassword)).Methods(http.MethodGet)
 	r.HandleFunc("/admin/api/library/libraries", adminUIHandler.RequireAuth(adminUIHandler.ListLocalMediaLibraries)).Methods(http.MethodGet)
 	r.HandleFunc("/admin/api/library/libraries", adminUIHandler.RequireAuth(adminUIHandler.CreateLocalMediaLibrary)).Methods(http.MethodPost)
@@ -1036,13 +1177,19 @@ func main() {
 	r.HandleFunc("/api/register", adminUIHandler.RegisterWithInvitation).Methods(http.MethodPost)
 
 	// Cache management endpoints
-	r.HandleFunc("/admin/api/cache/clear", adminUIHandler.RequireAuth(adminUIHandler.ClearMetadataCache)).Methods(http.MethodPost)
+	r.HandleFunc("/admin/api/cache/clear", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureCacheRefresh, adminUIHandler.ClearMetadataCache))).Methods(http.MethodPost)
+	r.HandleFunc("/admin/api/cache/namespaces", adminUIHandler.RequireAuth(adminUIHandler.ListCacheNamespaces)).Methods(http.MethodGet)
+	r.HandleFunc("/admin/api/cache/namespaces/{namespace}", adminUIHandler.RequireAuth(adminUIHandler.ListCacheEntries)).Methods(http.MethodGet)
+	r.HandleFunc("/admin/api/cache/namespaces/{namespace}", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureCacheRefresh, adminUIHandler.InvalidateCacheNamespace))).Methods(http.MethodDelete)
+	r.HandleFunc("/admin/api/cache/titles/{titleID}", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureCacheRefresh, adminUIHandler.InvalidateCachedTitle))).Methods(http.MethodDelete)
 	r.HandleFunc("/admin/api/cache/manager/status", adminUIHandler.RequireAuth(adminUIHandler.GetCacheManagerStatus)).Methods(http.MethodGet)
-	r.HandleFunc("/admin/api/cache/manager/refresh", adminUIHandler.RequireAuth(adminUIHandler.RefreshTrendingCache)).Methods(http.MethodPost)
+	r.HandleFunc("/admin/api/cache/manager/refresh", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureCacheRefresh, adminUIHandler.RefreshTrendingCache))).Methods(http.MethodPost)
 	r.HandleFunc("/admin/api/topten/worker/status", adminUIHandler.RequireAuth(adminUIHandler.GetTopTenWorkerStatus)).Methods(http.MethodGet)
-	r.HandleFunc("/admin/api/topten/worker/refresh", adminUIHandler.RequireAuth(adminUIHandler.RefreshTopTenWorker)).Methods(http.MethodPost)
+	r.HandleFunc("/admin/api/topten/worker/refresh", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureCacheRefresh, adminUIHandler.RefreshTopTenWorker))).Methods(http.MethodPost)
+	r.HandleFunc("/admin/api/metadata/enrichment-failures", adminUIHandler.RequireAuth(adminUIHandler.GetEnrichmentFailures)).Methods(http.MethodGet)
+	r.HandleFunc("/admin/api/metadata/enrichment-failures/retry", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureCacheRefresh, adminUIHandler.RetryEnrichmentFailures))).Methods(http.MethodPost)
 	r.HandleFunc("/admin/api/calendar/worker/status", adminUIHandler.RequireAuth(adminUIHandler.GetCalendarWorkerStatus)).Methods(http.MethodGet)
-	r.HandleFunc("/admin/api/calendar/worker/refresh", adminUIHandler.RequireAuth(adminUIHandler.RefreshCalendar)).Methods(http.MethodPost)
+	r.HandleFunc("/admin/api/calendar/worker/refresh", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureCacheRefresh, adminUIHandler.RefreshCalendar))).Methods(http.MethodPost)
 
 	// yt-dlp cookies management (experimental)
 	r.HandleFunc("/admin/api/ytdlp-cookies", adminUIHandler.RequireMasterAuth(adminUIHandler.GetYTDLPCookiesStatus)).Methods(http.MethodGet)
@@ -1147,8 +1294,12 @@ func main() {
 	// Scheduled tasks routes (master account only)
 	r.HandleFunc("/admin/api/scheduled-tasks", adminUIHandler.RequireMasterAuth(scheduledTasksHandler.ListTasks)).Methods(http.MethodGet)
 	r.HandleFunc("/admin/api/scheduled-tasks", adminUIHandler.RequireMasterAuth(scheduledTasksHandler.CreateTask)).Methods(http.MethodPost)
+	r.HandleFunc("/admin/api/scheduled-tasks/maintenance-window", adminUIHandler.RequireMasterAuth(scheduledTasksHandler.GetMaintenanceWindow)).Methods(http.MethodGet)
+	r.HandleFunc("/admin/api/scheduled-tasks/maintenance-window", adminUIHandler.RequireMasterAuth(scheduledTasksHandler.UpdateMaintenanceWindow)).Methods(http.MethodPut)
+	r.HandleFunc("/admin/api/scheduled-tasks/notifications/test", adminUIHandler.RequireMasterAuth(scheduledTasksHandler.TestNotification)).Methods(http.MethodPost)
 	r.HandleFunc("/admin/api/scheduled-tasks/{taskID}", adminUIHandler.RequireMasterAuth(scheduledTasksHandler.UpdateTask)).Methods(http.MethodPut)
 	r.HandleFunc("/admin/api/scheduled-tasks/{taskID}", adminUIHandler.RequireMasterAuth(scheduledTasksHandler.DeleteTask)).Methods(http.MethodDelete)
+	r.HandleFunc("/admin/api/scheduled-tasks/{taskID}/history", adminUIHandler.RequireMasterAuth(scheduledTasksHandler.GetTaskRunHistory)).Methods(http.MethodGet)
 	r.HandleFunc("/admin/api/scheduled-tasks/{taskID}/run", adminUIHandler.RequireMasterAuth(scheduledTasksHandler.RunTaskNow)).Methods(http.MethodPost)
 	r.HandleFunc("/admin/api/scheduled-tasks/{taskID}/toggle", adminUIHandler.RequireMasterAuth(scheduledTasksHandler.ToggleTask)).Methods(http.MethodPost)
 
@@ -1160,6 +1311,7 @@ func main() {
 		if store != nil {
 			backupService.SetDataStore(store)
 		}
+		backupService.SetDiskSpaceCheck(storageMonitor.SpaceCheck(storage.VolumeBackups))
 		backupHandler := handlers.NewBackupHandler(backupService)
 		schedulerService.SetBackupService(backupService)
 		r.HandleFunc("/admin/backup", adminUIHandler.RequireMasterAuth(adminUIHandler.BackupPage)).Methods(http.MethodGet)
@@ -1173,6 +1325,9 @@ func main() {
 		fmt.Println("💾 Backup management available at /admin/backup")
 	}
 
+	storageHandler := handlers.NewStorageHandler(storageMonitor)
+	r.HandleFunc("/admin/api/storage", adminUIHandler.RequireAuth(storageHandler.GetStatus)).Methods(http.MethodGet)
+
 	// Prewarm service for pre-resolving continue watching items
 	prewarmService := prewarm.NewService(cfgManager, settings.Cache.Directory)
 	if store != nil {
@@ -1208,6 +1363,7 @@ func main() {
 	r.HandleFunc("/admin/performance", adminUIHandler.RequireMasterAuth(adminUIHandler.PerformancePage)).Methods(http.MethodGet)
 	r.HandleFunc("/admin/logs", adminUIHandler.RequireAuth(adminUIHandler.LogsPage)).Methods(http.MethodGet)
 	r.HandleFunc("/admin/api/logs", adminUIHandler.RequireAuth(adminUIHandler.GetLogs)).Methods(http.MethodGet)
+	r.HandleFunc("/admin/api/support-bundle", adminUIHandler.RequireMasterAuth(logsHandler.SupportBundle)).Methods(http.MethodGet)
 	r.HandleFunc("/admin/api/logs/package", adminUIHandler.RequireAuth(adminUIHandler.SubmitLogsPackage)).Methods(http.MethodPost)
 	r.HandleFunc("/admin/api/performance", adminUIHandler.RequireMasterAuth(adminUIHandler.GetPerformanceMetrics)).Methods(http.MethodGet)
 	r.HandleFunc("/admin/api/performance/sse", adminUIHandler.RequireMasterAuth(adminUIHandler.GetPerformanceSSE)).Methods(http.MethodGet)
@@ -1248,12 +1404,17 @@ func main() {
 	r.HandleFunc("/account/api/live/epg/schedule", adminUIHandler.RequireAuth(epgHandler.GetSchedule)).Methods(http.MethodGet)
 	r.HandleFunc("/account/api/live/epg/schedule/batch", adminUIHandler.RequireAuth(epgHandler.GetScheduleMultiple)).Methods(http.MethodGet)
 	r.HandleFunc("/account/api/live/recordings", adminUIHandler.RequireAuth(recordingsHandler.List)).Methods(http.MethodGet)
-	r.HandleFunc("/account/api/live/recordings/epg", adminUIHandler.RequireAuth(recordingsHandler.CreateEPG)).Methods(http.MethodPost)
-	r.HandleFunc("/account/api/live/recordings/time-block", adminUIHandler.RequireAuth(recordingsHandler.CreateTimeBlock)).Methods(http.MethodPost)
+	r.HandleFunc("/account/api/live/recordings/epg", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureLiveTVManagement, recordingsHandler.CreateEPG))).Methods(http.MethodPost)
+	r.HandleFunc("/account/api/live/recordings/time-block", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureLiveTVManagement, recordingsHandler.CreateTimeBlock))).Methods(http.MethodPost)
+	r.HandleFunc("/account/api/live/recordings/rules", adminUIHandler.RequireAuth(recordingsHandler.ListRules)).Methods(http.MethodGet)
+	r.HandleFunc("/account/api/live/recordings/rules", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureLiveTVManagement, recordingsHandler.CreateRule))).Methods(http.MethodPost)
+	r.HandleFunc("/account/api/live/recordings/rules/evaluate", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureLiveTVManagement, recordingsHandler.EvaluateRules))).Methods(http.MethodPost)
+	r.HandleFunc("/account/api/live/recordings/rules/{ruleID}", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureLiveTVManagement, recordingsHandler.UpdateRule))).Methods(http.MethodPut)
+	r.HandleFunc("/account/api/live/recordings/rules/{ruleID}", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureLiveTVManagement, recordingsHandler.DeleteRule))).Methods(http.MethodDelete)
 	r.HandleFunc("/account/api/live/recordings/{recordingID}", adminUIHandler.RequireAuth(recordingsHandler.Get)).Methods(http.MethodGet)
-	r.HandleFunc("/account/api/live/recordings/{recordingID}", adminUIHandler.RequireAuth(recordingsHandler.Delete)).Methods(http.MethodDelete)
+	r.HandleFunc("/account/api/live/recordings/{recordingID}", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureLiveTVManagement, recordingsHandler.Delete))).Methods(http.MethodDelete)
 	r.HandleFunc("/account/api/live/recordings/{recordingID}/stream", adminUIHandler.RequireAuth(recordingsHandler.Stream)).Methods(http.MethodGet)
-	r.HandleFunc("/account/api/live/recordings/{recordingID}/cancel", adminUIHandler.RequireAuth(recordingsHandler.Cancel)).Methods(http.MethodPost)
+	r.HandleFunc("/account/api/live/recordings/{recordingID}/cancel", adminUIHandler.RequireAuth(adminUIHandler.RequireFeature(models.FeatureLiveTVManagement, recordingsHandler.Cancel))).Methods(http.MethodPost)
 
 	// Protected account routes - Playback UI APIs (session-auth aliases for existing API handlers)
 	r.HandleFunc("/account/api/search", adminUIHandler.RequireAuth(metadataHandler.Search)).Methods(http.MethodGet)
@@ -1306,9 +1467,11 @@ func main() {
 	r.HandleFunc("/account/api/users/{userID}/kids/rating", adminUIHandler.RequireAuth(usersHandler.SetKidsMaxRating)).Methods(http.MethodPut)
 	r.HandleFunc("/account/api/users/{userID}/kids/rating/movie", adminUIHandler.RequireAuth(usersHandler.SetKidsMaxMovieRating)).Methods(http.MethodPut)
 	r.HandleFunc("/account/api/users/{userID}/kids/rating/tv", adminUIHandler.RequireAuth(usersHandler.SetKidsMaxTVRating)).Methods(http.MethodPut)
+	r.HandleFunc("/account/api/users/{userID}/rating-ceiling", adminUIHandler.RequireAuth(usersHandler.SetRatingCeiling)).Methods(http.MethodPut)
 	r.HandleFunc("/account/api/users/{userID}/kids/lists", adminUIHandler.RequireAuth(usersHandler.SetKidsAllowedLists)).Methods(http.MethodPut)
 	r.HandleFunc("/account/api/users/{userID}/kids/lists", adminUIHandler.RequireAuth(usersHandler.AddKidsAllowedList)).Methods(http.MethodPost)
 	r.HandleFunc("/account/api/users/{userID}/kids/lists", adminUIHandler.RequireAuth(usersHandler.RemoveKidsAllowedList)).Methods(http.MethodDelete)
+	r.HandleFunc("/account/api/users/{userID}/kids/genres", adminUIHandler.RequireAuth(usersHandler.SetKidsAllowedGenres)).Methods(http.MethodPut)
 	r.HandleFunc("/account/api/profiles/max-streams", accountUIHandler.RequireAuth(accountUIHandler.GetProfileMaxStreams)).Methods(http.MethodGet)
 	r.HandleFunc("/account/api/profiles/max-streams", accountUIHandler.RequireAuth(accountUIHandler.SetProfileMaxStreams)).Methods(http.MethodPut)
 	r.HandleFunc("/account/api/profiles/mdblist", accountUIHandler.RequireAuth(accountUIHandler.SetProfileMdblist)).Methods(http.MethodPut)
@@ -1366,6 +1529,9 @@ func main() {
 	// One-time share link consumption (public, no auth — opening mints a scoped session).
 	r.HandleFunc("/share/{token}", shareHandler.Open).Methods(http.MethodGet)
 
+	// Shared read-only lists (public, no auth — the token is the credential).
+	r.HandleFunc("/shared/lists/{token}", listSharesHandler.View).Methods(http.MethodGet)
+
 	// Dedicated consumer web app served from the frontend Expo web export.
 	webAppHandler := handlers.NewWebAppHandler(handlers.ResolveWebAppDir(), "/watch")
 	r.Handle("/watch", webAppHandler).Methods(http.MethodGet, http.MethodHead)
@@ -1523,8 +1689,56 @@ func main() {
 
 		return items
 	})
+	metadataService.SetDiscoverFiltersProvider(savedFiltersService.AllFilters)
+	metadataService.SetWarmItemsProvider(func() []metadata.WarmItem {
+		var items []metadata.WarmItem
+		add := func(mediaType, name string, year int, externalIDs map[string]string) {
+			tmdbID, tvdbID := watchlist.NumericIDs(externalIDs)
+			items = append(items, metadata.WarmItem{
+				MediaType: mediaType,
+				Name:      name,
+				Year:      year,
+				IMDBID:    externalIDs["imdb"],
+				TMDBID:    tmdbID,
+				TVDBID:    tvdbID,
+			})
+		}
+
+		for _, user := range userService.ListAll() {
+			if wl, err := watchlistService.List(user.ID); err == nil {
+				for _, item := range wl {
+					add(item.MediaType, item.Name, item.Year, item.ExternalIDs)
+				}
+			}
+
+			// Continue watching: in-progress series and partially watched movies
+			if states, err := historyService.ListSeriesStates(user.ID); err == nil {
+				for _, state := range states {
+					add("series", state.SeriesTitle, state.Year, state.ExternalIDs)
+				}
+			}
+			if pp, err := historyService.ListPlaybackProgress(user.ID); err == nil {
+				for _, p := range pp {
+					if p.MediaType == "movie" && !p.HiddenFromContinueWatching {
+						add("movie", p.MovieName, p.Year, p.ExternalIDs)
+					}
+				}
+			}
+		}
+
+		return items
+	})
+	metadataService.SetMaintenanceWindowCheck(func(now time.Time) bool {
+		current, err := cfgManager.Load()
+		if err != nil {
+			return false
+		}
+		return scheduler.OutsideMaintenanceWindow(current.ScheduledTasks.MaintenanceWindow, now)
+	})
 	metadataService.StartBackgroundCacheManager(2 * time.Hour)
 	metadataService.StartBackgroundTopTenWorker(12 * time.Hour)
+	metadataService.StartTrailerPrequeuePolicyWorker(30 * time.Minute)
+	metadataService.StartEnrichmentRetryWorker(5 * time.Minute)
 	calendarService.StartBackgroundRefresh(4 * time.Hour)
 
 	if strings.EqualFold(strings.TrimSpace(os.Getenv("STRMR_RUNTIME_LOGS")), "1") ||
@@ -1604,6 +1818,7 @@ func main() {
 
 	// Stop calendar service background refresh
 	calendarService.Stop()
+	storageMonitor.Stop()
 
 	// Stop NZB system workers first to cancel background processing
 	log.Println("🧹 Stopping NZB system workers...")
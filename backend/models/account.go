@@ -14,6 +14,27 @@ const (
 	MasterAccountUsername = "admin"
 )
 
+// Features an admin can turn off for individual accounts. Master accounts
+// always have every feature.
+const (
+	FeatureAIRecommendations = "ai_recommendations" // AI recommendation, similar, custom and surprise picks, and smart search
+	FeatureLiveTVManagement  = "live_tv_management" // recordings, recording rules, guide and playlist refreshes
+	FeatureCacheRefresh      = "cache_refresh"      // manual metadata cache clears and worker refreshes
+)
+
+// AccountFeatures lists every feature that can be toggled per account.
+var AccountFeatures = []string{FeatureAIRecommendations, FeatureLiveTVManagement, FeatureCacheRefresh}
+
+// IsAccountFeature reports whether feature is one of AccountFeatures.
+func IsAccountFeature(feature string) bool {
+	for _, f := range AccountFeatures {
+		if f == feature {
+			return true
+		}
+	}
+	return false
+}
+
 // Account represents a user account that can own multiple profiles.
 // Master accounts can manage all profiles and other accounts.
 // Regular accounts can only see and manage their own profiles.
@@ -22,10 +43,25 @@ type Account struct {
 	Username     string     `json:"username"`
 	PasswordHash string     `json:"-"` // bcrypt hash, excluded from JSON API responses (security)
 	IsMaster     bool       `json:"isMaster"`
-	MaxStreams   int        `json:"maxStreams"` // Max concurrent VOD streams for this account (0 = unlimited)
+	MaxStreams   int        `json:"maxStreams"`          // Max concurrent VOD streams for this account (0 = unlimited)
 	ExpiresAt    *time.Time `json:"expiresAt,omitempty"` // nil = permanent account
-	CreatedAt    time.Time  `json:"createdAt"`
-	UpdatedAt    time.Time  `json:"updatedAt"`
+	// DeniedFeatures lists the AccountFeatures turned off for this account.
+	DeniedFeatures []string  `json:"deniedFeatures,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+// HasFeature reports whether the account may use feature.
+func (a Account) HasFeature(feature string) bool {
+	if a.IsMaster {
+		return true
+	}
+	for _, denied := range a.DeniedFeatures {
+		if denied == feature {
+			return false
+		}
+	}
+	return true
 }
 
 // IsExpired reports whether the account has a set expiry that is in the past.
@@ -46,40 +82,43 @@ func (a Account) MarshalJSON() ([]byte, error) {
 // AccountStorage is the internal representation used for file persistence.
 // Unlike Account, this includes the password hash for storage.
 type AccountStorage struct {
-	ID           string     `json:"id"`
-	Username     string     `json:"username"`
-	PasswordHash string     `json:"passwordHash"` // Included for storage only
-	IsMaster     bool       `json:"isMaster"`
-	MaxStreams   int        `json:"maxStreams,omitempty"`
-	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
-	CreatedAt    time.Time  `json:"createdAt"`
-	UpdatedAt    time.Time  `json:"updatedAt"`
+	ID             string     `json:"id"`
+	Username       string     `json:"username"`
+	PasswordHash   string     `json:"passwordHash"` // Included for storage only
+	IsMaster       bool       `json:"isMaster"`
+	MaxStreams     int        `json:"maxStreams,omitempty"`
+	ExpiresAt      *time.Time `json:"expiresAt,omitempty"`
+	DeniedFeatures []string   `json:"deniedFeatures,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
 }
 
 // ToStorage converts an Account to AccountStorage for persistence.
 func (a Account) ToStorage() AccountStorage {
 	return AccountStorage{
-		ID:           a.ID,
-		Username:     a.Username,
-		PasswordHash: a.PasswordHash,
-		IsMaster:     a.IsMaster,
-		MaxStreams:   a.MaxStreams,
-		ExpiresAt:    a.ExpiresAt,
-		CreatedAt:    a.CreatedAt,
-		UpdatedAt:    a.UpdatedAt,
+		ID:             a.ID,
+		Username:       a.Username,
+		PasswordHash:   a.PasswordHash,
+		IsMaster:       a.IsMaster,
+		MaxStreams:     a.MaxStreams,
+		ExpiresAt:      a.ExpiresAt,
+		DeniedFeatures: a.DeniedFeatures,
+		CreatedAt:      a.CreatedAt,
+		UpdatedAt:      a.UpdatedAt,
 	}
 }
 
 // ToAccount converts an AccountStorage back to Account.
 func (as AccountStorage) ToAccount() Account {
 	return Account{
-		ID:           as.ID,
-		Username:     as.Username,
-		PasswordHash: as.PasswordHash,
-		IsMaster:     as.IsMaster,
-		MaxStreams:   as.MaxStreams,
-		ExpiresAt:    as.ExpiresAt,
-		CreatedAt:    as.CreatedAt,
-		UpdatedAt:    as.UpdatedAt,
+		ID:             as.ID,
+		Username:       as.Username,
+		PasswordHash:   as.PasswordHash,
+		IsMaster:       as.IsMaster,
+		MaxStreams:     as.MaxStreams,
+		ExpiresAt:      as.ExpiresAt,
+		DeniedFeatures: as.DeniedFeatures,
+		CreatedAt:      as.CreatedAt,
+		UpdatedAt:      as.UpdatedAt,
 	}
 }
@@ -28,6 +28,9 @@ type CalendarItem struct {
 	Year            int               `json:"year,omitempty"`
 	ExternalIDs     map[string]string `json:"externalIds,omitempty"` // imdb, tvdb, tmdb
 	Source          string            `json:"source"`                // "watchlist" | "history" | "trending" | "top-trending" | "mdblist"
+	// WatchProviders is filled per request for series episodes, in the requested
+	// region or else the profile's or server's default region.
+	WatchProviders *WatchProviderAvailability `json:"watchProviders,omitempty"`
 }
 
 // CalendarResponse is the API response for the calendar endpoint.
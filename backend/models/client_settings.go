@@ -66,6 +66,13 @@ type ClientFilterSettings struct {
 	MatchFrameRate                *bool    `json:"matchFrameRate,omitempty"`
 	MaxResultsPerResolution       *int     `json:"maxResultsPerResolution,omitempty"`
 
+	// Device playback overrides, applied by the server whenever this client
+	// starts a stream. They have no profile or global counterpart.
+	ForceTranscode         *bool   `json:"forceTranscode,omitempty"`         // Always re-encode video to H.264
+	MaxPlaybackResolution  *string `json:"maxPlaybackResolution,omitempty"`  // "720p", "1080p", "2160p"; taller sources are scaled down
+	DisableHDR             *bool   `json:"disableHdr,omitempty"`             // Deliver HDR and Dolby Vision sources as SDR
+	PreferAudioPassthrough *bool   `json:"preferAudioPassthrough,omitempty"` // Copy compatible audio even when AAC transcoding is forced globally
+
 	// Ranking criteria overrides
 	RankingCriteria *[]ClientRankingCriterion `json:"rankingCriteria,omitempty"`
 
@@ -127,6 +134,10 @@ func (c *ClientFilterSettings) IsEmpty() bool {
 		c.CreditsAutoSkip == nil &&
 		c.MatchFrameRate == nil &&
 		c.MaxResultsPerResolution == nil &&
+		c.ForceTranscode == nil &&
+		c.MaxPlaybackResolution == nil &&
+		c.DisableHDR == nil &&
+		c.PreferAudioPassthrough == nil &&
 		c.HomeWifiSSID == nil &&
 		c.HomeBackendUrl == nil &&
 		c.RemoteBackendUrl == nil &&
@@ -11,6 +11,7 @@ type ContentPreference struct {
 	AudioLanguage    string    `json:"audioLanguage,omitempty"`    // ISO 639-2 code (eng, jpn, spa, etc.)
 	SubtitleLanguage string    `json:"subtitleLanguage,omitempty"` // ISO 639-2 code or empty
 	SubtitleMode     string    `json:"subtitleMode,omitempty"`     // "off", "on", "forced-only"
+	AnimeMetadata    *bool     `json:"animeMetadata,omitempty"`    // series only: overrides the global AniList toggle
 	UpdatedAt        time.Time `json:"updatedAt"`
 }
 
@@ -21,4 +22,5 @@ type ContentPreferenceUpdate struct {
 	AudioLanguage    string `json:"audioLanguage,omitempty"`
 	SubtitleLanguage string `json:"subtitleLanguage,omitempty"`
 	SubtitleMode     string `json:"subtitleMode,omitempty"`
+	AnimeMetadata    *bool  `json:"animeMetadata,omitempty"`
 }
@@ -15,6 +15,8 @@ type EPGProgram struct {
 	Categories  []string  `json:"categories,omitempty"`
 	Episode     string    `json:"episode,omitempty"` // Episode number in standard format (e.g., "S01E05")
 	Rating      string    `json:"rating,omitempty"`
+	// PreviouslyShown marks reruns (XMLTV <previously-shown>).
+	PreviouslyShown bool `json:"previouslyShown,omitempty"`
 }
 
 // EPGChannel represents a channel's metadata from EPG data.
@@ -34,9 +36,17 @@ type EPGSchedule struct {
 
 // EPGNowPlaying represents the current and next program for a channel.
 type EPGNowPlaying struct {
-	ChannelID string      `json:"channelId"`
-	Current   *EPGProgram `json:"current,omitempty"`
-	Next      *EPGProgram `json:"next,omitempty"`
+	ChannelID   string      `json:"channelId"`
+	ChannelName string      `json:"channelName,omitempty"`
+	Current     *EPGProgram `json:"current,omitempty"`
+	Next        *EPGProgram `json:"next,omitempty"`
+}
+
+// EPGSearchResult is a program matching an EPG search query.
+type EPGSearchResult struct {
+	Program     EPGProgram `json:"program"`
+	ChannelName string     `json:"channelName,omitempty"`
+	Score       int        `json:"score"`
 }
 
 // EPGStatus represents the status of the EPG service.
@@ -50,6 +50,18 @@ type SeriesWatchState struct {
 	HomeRelease *Release `json:"homeRelease,omitempty"`
 }
 
+// ContinueWatchingShelfItem is a continue-watching entry carrying everything a
+// client needs to render and start it, so the home shelf is a single request.
+type ContinueWatchingShelfItem struct {
+	SeriesWatchState
+	MediaType        string            `json:"mediaType"`                  // "movie" | "series"
+	Episode          *EpisodeReference `json:"episode,omitempty"`          // Episode to play; nil for movies
+	ArtworkURL       string            `json:"artworkUrl,omitempty"`       // Episode still, falling back to the title's backdrop or poster
+	ResumePosition   float64           `json:"resumePosition,omitempty"`   // Seconds into the item to resume from
+	DurationSeconds  float64           `json:"durationSeconds,omitempty"`  // Total runtime in seconds, when known
+	RemainingSeconds float64           `json:"remainingSeconds,omitempty"` // Runtime left to watch, when known
+}
+
 // EpisodeWatchPayload represents a request to record that a user started an episode.
 type EpisodeWatchPayload struct {
 	SeriesID    string            `json:"seriesId"`
@@ -100,6 +112,33 @@ type WatchHistoryUpdate struct {
 	SeriesName    string `json:"seriesName,omitempty"`
 }
 
+// MarkWatchedThroughRequest marks every aired episode of a series up to and
+// including SeasonNumber/EpisodeNumber as watched. Specials are left alone.
+type MarkWatchedThroughRequest struct {
+	SeriesID      string            `json:"seriesId"`
+	SeriesName    string            `json:"seriesName,omitempty"`
+	ExternalIDs   map[string]string `json:"externalIds,omitempty"`
+	SeasonNumber  int               `json:"seasonNumber"`
+	EpisodeNumber int               `json:"episodeNumber"`
+	WatchedAt     time.Time         `json:"watchedAt,omitempty"` // When the last episode was watched; defaults to now
+}
+
+// MarkWatchedThroughResult reports the episodes a mark-watched-through
+// request recorded.
+type MarkWatchedThroughResult struct {
+	Items          []WatchHistoryItem `json:"items"`          // Episodes newly marked as watched, in episode order
+	AlreadyWatched int                `json:"alreadyWatched"` // Episodes in range that were already watched and left as they were
+}
+
+// EpisodeScrobble is one episode in a batched history sync to a scrobbling
+// service.
+type EpisodeScrobble struct {
+	SeasonNumber  int
+	EpisodeNumber int
+	WatchedAt     time.Time
+	ExternalIDs   map[string]string // Episode-scoped IDs (episodeTvdb, episodeTmdb, ...)
+}
+
 // PlaybackProgressUpdate represents a playback progress update from the player.
 type PlaybackProgressUpdate struct {
 	MediaType      string            `json:"mediaType"`      // "movie" | "episode" | "live"
@@ -112,6 +151,12 @@ type PlaybackProgressUpdate struct {
 	IsBuffering    bool              `json:"isBuffering"`    // Whether the player is currently stalled/buffering (not paused)
 	ExternalIDs    map[string]string `json:"externalIds,omitempty"`
 
+	// Multi-device resume. SessionID identifies one playback session on one
+	// device; ExplicitSeek marks a position the viewer seeked to on purpose, so
+	// a deliberate rewind is kept even when another session got further.
+	SessionID    string `json:"sessionId,omitempty"`
+	ExplicitSeek bool   `json:"explicitSeek,omitempty"`
+
 	// Episode-specific fields
 	SeasonNumber  int    `json:"seasonNumber,omitempty"`
 	EpisodeNumber int    `json:"episodeNumber,omitempty"`
@@ -152,6 +197,12 @@ type PlaybackProgress struct {
 	// Hidden from continue watching (user dismissed)
 	HiddenFromContinueWatching bool `json:"hiddenFromContinueWatching,omitempty"`
 
+	// Playback session that wrote Position (see PlaybackProgressUpdate.SessionID)
+	SessionID string `json:"sessionId,omitempty"`
+
 	// Runtime playback control response fields. Not persisted.
 	AllowedToContinue *bool `json:"allowedToContinue,omitempty"`
+	// PositionConflict is set when an update was behind the position another
+	// session saved and the further position was kept.
+	PositionConflict bool `json:"positionConflict,omitempty"`
 }
@@ -24,6 +24,64 @@ type NZBResult struct {
 	ServiceType  ContentServiceType `json:"serviceType,omitempty"`
 	EpisodeCount int                `json:"episodeCount,omitempty"` // Number of episodes in pack (0 if not a pack)
 	SizePerFile  bool               `json:"sizePerFile,omitempty"`  // True when sizeBytes is per-file (Stremio scrapers), false when total pack
+	Media        *SourceMediaInfo   `json:"media,omitempty"`        // HDR, bit depth and audio layout parsed from the release name
+}
+
+// HDR formats reported in SourceMediaInfo.HDRFormats.
+const (
+	HDRFormatDolbyVision = "DV"
+	HDRFormatHDR10Plus   = "HDR10+"
+	HDRFormatHDR10       = "HDR10"
+	HDRFormatHLG         = "HLG"
+)
+
+// SourceMediaInfo describes the video and audio format of a source. It is
+// parsed from the release name at search time and refined by ffprobe once
+// the file has been probed.
+type SourceMediaInfo struct {
+	HDRFormats []string `json:"hdrFormats,omitempty"` // normalized HDR formats, best first ("DV", "HDR10+", "HDR10", "HLG")
+	// DVProfile is the Dolby Vision profile (5, 7 or 8), 0 when unknown or not DV.
+	DVProfile int `json:"dvProfile,omitempty"`
+	// DVProfileInferred is set when DVProfile was guessed rather than read:
+	// a DV release that names no HDR10 fallback layer is most likely profile 5.
+	DVProfileInferred bool     `json:"dvProfileInferred,omitempty"`
+	BitDepth          int      `json:"bitDepth,omitempty"`
+	AudioCodecs       []string `json:"audioCodecs,omitempty"`
+	AudioChannels     string   `json:"audioChannels,omitempty"` // channel layout, e.g. "5.1" or "7.1"
+	Probed            bool     `json:"probed,omitempty"`        // true when the values come from ffprobe
+}
+
+// HasDolbyVision reports whether the source carries a Dolby Vision layer.
+func (m *SourceMediaInfo) HasDolbyVision() bool {
+	return m != nil && m.hasFormat(HDRFormatDolbyVision)
+}
+
+// HasHDRFallback reports whether non-DV displays get HDR from the source:
+// an HDR10/HDR10+/HLG layer or a DV profile that carries one (7 and 8).
+func (m *SourceMediaInfo) HasHDRFallback() bool {
+	if m == nil {
+		return false
+	}
+	if m.DVProfile == 7 || m.DVProfile == 8 {
+		return true
+	}
+	return m.hasFormat(HDRFormatHDR10Plus) || m.hasFormat(HDRFormatHDR10) || m.hasFormat(HDRFormatHLG)
+}
+
+// IsDolbyVisionProfile5 reports whether the source is (or is most likely)
+// DV profile 5, which has no HDR fallback and renders with wrong colors on
+// devices without Dolby Vision support.
+func (m *SourceMediaInfo) IsDolbyVisionProfile5() bool {
+	return m.HasDolbyVision() && m.DVProfile == 5
+}
+
+func (m *SourceMediaInfo) hasFormat(format string) bool {
+	for _, f := range m.HDRFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
 }
 
 // ScoreBreakdownItem represents a single scoring criterion's contribution to a result's total score.
@@ -4,14 +4,19 @@ import "time"
 
 // Invitation represents a one-time use invitation link for account creation.
 type Invitation struct {
-	ID                    string     `json:"id"`
-	Token                 string     `json:"token"`
-	CreatedBy             string     `json:"createdBy"` // Account ID of the creator
-	ExpiresAt             time.Time  `json:"expiresAt"`
-	AccountExpiresInHours int        `json:"accountExpiresInHours,omitempty"` // 0 = permanent
-	UsedAt                *time.Time `json:"usedAt,omitempty"`
-	UsedBy                string     `json:"usedBy,omitempty"` // Account ID of the user who used it
-	CreatedAt             time.Time  `json:"createdAt"`
+	ID                    string    `json:"id"`
+	Token                 string    `json:"token"`
+	Code                  string    `json:"code,omitempty"` // Short code that can be typed in place of the link, e.g. "7KQM-P3XD"
+	CreatedBy             string    `json:"createdBy"`      // Account ID of the creator
+	ExpiresAt             time.Time `json:"expiresAt"`
+	AccountExpiresInHours int       `json:"accountExpiresInHours,omitempty"` // 0 = permanent
+	// Template applied to the account created with this invitation
+	MaxStreams        int        `json:"maxStreams,omitempty"`        // Concurrent stream limit (0 = unlimited)
+	DeniedFeatures    []string   `json:"deniedFeatures,omitempty"`    // Features the account starts without (see AccountFeatures)
+	ProfileTemplateID string     `json:"profileTemplateId,omitempty"` // Profile whose restrictions and settings the first profile copies
+	UsedAt            *time.Time `json:"usedAt,omitempty"`
+	UsedBy            string     `json:"usedBy,omitempty"` // Account ID of the user who used it
+	CreatedAt         time.Time  `json:"createdAt"`
 }
 
 // IsValid checks if the invitation is still valid (not expired and not used).
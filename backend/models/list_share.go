@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// List types that can be shared through a public link.
+const (
+	ShareListWatchlist = "watchlist"
+	ShareListCustom    = "custom"
+)
+
+// ListShare is a public, read-only link to a profile's watchlist or custom
+// list. Anyone holding the token can view the list without signing in until
+// the link is revoked.
+type ListShare struct {
+	ID        string    `json:"id"`
+	Token     string    `json:"token"`
+	UserID    string    `json:"userId"`           // Profile that owns the list
+	ListType  string    `json:"listType"`         // watchlist | custom
+	ListID    string    `json:"listId,omitempty"` // Custom list ID; empty for the watchlist
+	Title     string    `json:"title,omitempty"`  // Heading shown to viewers; defaults to the list name
+	CreatedAt time.Time `json:"createdAt"`
+}
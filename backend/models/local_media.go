@@ -179,6 +179,8 @@ type LocalMediaItemGroup struct {
 	LatestCreatedAt  *time.Time              `json:"latestCreatedAt,omitempty"`
 	Items            []LocalMediaItem        `json:"items,omitempty"`
 	Seasons          []LocalMediaSeasonGroup `json:"seasons,omitempty"`
+	// CertificationCountry is the rating system of Certification (ISO 3166-1). Empty means US.
+	CertificationCountry string `json:"certificationCountry,omitempty"`
 }
 
 type LocalMediaGroupListResult struct {
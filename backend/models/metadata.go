@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // Basic metadata structures for titles and images.
 
 // LanguageAlias is a language-tagged alternate title (e.g. from TVDB aliases).
@@ -17,6 +19,8 @@ type Image struct {
 	IsTextless         bool   `json:"is_textless,omitempty"`
 	Language           string `json:"language,omitempty"`
 	IsFallbackLanguage bool   `json:"is_fallback_language,omitempty"`
+	BlurHash           string `json:"blurhash,omitempty"`       // Placeholder preview (https://blurha.sh)
+	DominantColor      string `json:"dominant_color,omitempty"` // "#rrggbb"
 }
 
 type Trailer struct {
@@ -39,55 +43,106 @@ type Trailer struct {
 
 // Rating represents a single rating from a source
 type Rating struct {
-	Source string  `json:"source"` // imdb, tmdb, trakt, letterboxd, tomatoes, audience, metacritic
-	Value  float64 `json:"value"`  // Rating value (scale varies by source)
-	Max    float64 `json:"max"`    // Maximum possible value (e.g., 10 for IMDB, 100 for RT)
+	Source string  `json:"source"`          // imdb, tmdb, trakt, letterboxd, tomatoes, audience, metacritic
+	Value  float64 `json:"value"`           // Rating value (scale varies by source)
+	Max    float64 `json:"max"`             // Maximum possible value (e.g., 10 for IMDB, 100 for RT)
+	Votes  int     `json:"votes,omitempty"` // Number of votes, when the source reports it
 }
 
 type Title struct {
-	ID              string      `json:"id"`
-	Name            string      `json:"name"`
-	OriginalName    string      `json:"originalName,omitempty"`
-	AlternateTitles []string    `json:"alternateTitles,omitempty"`
-	Overview        string      `json:"overview"`
-	Year            int         `json:"year"`
-	Language        string      `json:"language"`
-	Poster          *Image      `json:"poster,omitempty"`
-	TextPoster      *Image      `json:"textPoster,omitempty"` // Original poster with text (preserved when Poster is overridden with textless)
-	Backdrop        *Image      `json:"backdrop,omitempty"`
-	TextBackdrop    *Image      `json:"textBackdrop,omitempty"` // Original backdrop with text (preserved when Backdrop is overridden with textless)
-	Backdrops       []Image     `json:"backdrops,omitempty"`    // Additional backdrop options beyond the primary
-	Logo            *Image      `json:"logo,omitempty"`
-	MediaType       string      `json:"mediaType"` // series | movie
-	TVDBID          int64       `json:"tvdbId,omitempty"`
-	IMDBID          string      `json:"imdbId,omitempty"`
-	TMDBID          int64       `json:"tmdbId,omitempty"`
-	Popularity      float64     `json:"popularity,omitempty"`
-	VoteCount       int         `json:"voteCount,omitempty"`
-	Network         string      `json:"network,omitempty"`
-	AirsTime        string      `json:"airsTime,omitempty"`      // e.g. "21:00" — local air time from TVDB
-	AirsTimezone    string      `json:"airsTimezone,omitempty"`  // IANA timezone inferred from network/country
-	Status          string      `json:"status,omitempty"`        // For series: Continuing, Ended, Upcoming, etc.
-	IsDaily         bool        `json:"isDaily,omitempty"`       // True for daily shows (talk shows, news, etc.) that use date-based episode naming
-	Certification   string      `json:"certification,omitempty"` // MPAA/TV content rating (G, PG, PG-13, R, TV-Y, TV-G, TV-PG, TV-14, TV-MA)
-	PrimaryTrailer  *Trailer    `json:"primaryTrailer,omitempty"`
-	Trailers        []Trailer   `json:"trailers,omitempty"`
-	Releases        []Release   `json:"releases,omitempty"`
-	Theatrical      *Release    `json:"theatricalRelease,omitempty"`
-	HomeRelease     *Release    `json:"homeRelease,omitempty"`
-	Ratings         []Rating    `json:"ratings,omitempty"`        // Aggregated ratings from MDBList
-	Credits         *Credits    `json:"credits,omitempty"`        // Top billed cast
-	RuntimeMinutes  int         `json:"runtimeMinutes,omitempty"` // Runtime in minutes (movies only)
-	Collection      *Collection `json:"collection,omitempty"`     // Movie collection (movies only)
-	Genres          []string    `json:"genres,omitempty"`         // Genre names from TMDB
-	Adult           bool        `json:"adult,omitempty"`          // True when the metadata provider marks this title as adult content
-	WatchState      string      `json:"watchState,omitempty"`     // "none" | "partial" | "complete"
-	UnwatchedCount  *int        `json:"unwatchedCount,omitempty"` // series only: total - watched
+	ID              string       `json:"id"`
+	Name            string       `json:"name"`
+	OriginalName    string       `json:"originalName,omitempty"`
+	AlternateTitles []string     `json:"alternateTitles,omitempty"`
+	Overview        string       `json:"overview"`
+	Year            int          `json:"year"`
+	Language        string       `json:"language"`
+	Poster          *Image       `json:"poster,omitempty"`
+	TextPoster      *Image       `json:"textPoster,omitempty"` // Original poster with text (preserved when Poster is overridden with textless)
+	Backdrop        *Image       `json:"backdrop,omitempty"`
+	TextBackdrop    *Image       `json:"textBackdrop,omitempty"` // Original backdrop with text (preserved when Backdrop is overridden with textless)
+	Backdrops       []Image      `json:"backdrops,omitempty"`    // Additional backdrop options beyond the primary
+	Logo            *Image       `json:"logo,omitempty"`
+	Banner          *Image       `json:"banner,omitempty"`  // Wide banner art (Fanart.tv)
+	DiscArt         *Image       `json:"discArt,omitempty"` // Disc art (Fanart.tv, movies only)
+	MediaType       string       `json:"mediaType"`         // series | movie
+	TVDBID          int64        `json:"tvdbId,omitempty"`
+	IMDBID          string       `json:"imdbId,omitempty"`
+	TMDBID          int64        `json:"tmdbId,omitempty"`
+	Popularity      float64      `json:"popularity,omitempty"`
+	VoteCount       int          `json:"voteCount,omitempty"`
+	Network         string       `json:"network,omitempty"`
+	AirsTime        string       `json:"airsTime,omitempty"`      // e.g. "21:00" — local air time from TVDB
+	AirsTimezone    string       `json:"airsTimezone,omitempty"`  // IANA timezone inferred from network/country
+	Status          string       `json:"status,omitempty"`        // For series: Continuing, Ended, Upcoming, etc.
+	IsDaily         bool         `json:"isDaily,omitempty"`       // True for daily shows (talk shows, news, etc.) that use date-based episode naming
+	Certification   string       `json:"certification,omitempty"` // MPAA/TV content rating (G, PG, PG-13, R, TV-Y, TV-G, TV-PG, TV-14, TV-MA)
+	PrimaryTrailer  *Trailer     `json:"primaryTrailer,omitempty"`
+	Trailers        []Trailer    `json:"trailers,omitempty"`
+	Releases        []Release    `json:"releases,omitempty"`
+	Theatrical      *Release     `json:"theatricalRelease,omitempty"`
+	HomeRelease     *Release     `json:"homeRelease,omitempty"`
+	Ratings         []Rating     `json:"ratings,omitempty"`        // Aggregated ratings from MDBList
+	Credits         *Credits     `json:"credits,omitempty"`        // Top billed cast and key crew
+	RuntimeMinutes  int          `json:"runtimeMinutes,omitempty"` // Runtime in minutes (movies only)
+	Collection      *Collection  `json:"collection,omitempty"`     // Movie collection (movies only)
+	Genres          []string     `json:"genres,omitempty"`         // Genre names from TMDB
+	Adult           bool         `json:"adult,omitempty"`          // True when the metadata provider marks this title as adult content
+	WatchState      string       `json:"watchState,omitempty"`     // "none" | "partial" | "complete"
+	UnwatchedCount  *int         `json:"unwatchedCount,omitempty"` // series only: total - watched
+	NextEpisode     *NextEpisode `json:"nextEpisode,omitempty"`    // series only: next unaired episode (SeriesInfo)
+	// CertificationCountry is the ISO 3166-1 country whose rating system
+	// Certification belongs to. Empty means US.
+	CertificationCountry string `json:"certificationCountry,omitempty"`
+	// Availability is the title's state in an external request manager
+	// (Overseerr/Jellyseerr), when one is configured.
+	Availability *RequestAvailability `json:"availability,omitempty"`
+	// WatchProviders lists the streaming services carrying the title in the
+	// configured watch-provider country. Only set on details responses.
+	WatchProviders *WatchProviderAvailability `json:"watchProviders,omitempty"`
 }
 
 type TrendingItem struct {
 	Rank  int   `json:"rank"`
 	Title Title `json:"title"`
+	// Reason is a short explanation of why the item was recommended, set on
+	// AI recommendations so shelves can show it.
+	Reason string `json:"reason,omitempty"`
+	// BecauseYouWatched names the title from the user's history the
+	// recommendation follows from ("Because you watched Dark").
+	BecauseYouWatched string `json:"becauseYouWatched,omitempty"`
+}
+
+// GenreHub is a genre landing page: trending, top-rated and new rows, each
+// mixing the genre's movies and series. TMDB numbers movie and TV genres
+// separately, so the hub carries both IDs; either is zero when the genre only
+// exists for one media type.
+type GenreHub struct {
+	Name          string        `json:"name"`
+	MovieGenreID  int64         `json:"movieGenreId,omitempty"`
+	SeriesGenreID int64         `json:"seriesGenreId,omitempty"`
+	Rows          []GenreHubRow `json:"rows"`
+}
+
+// GenreHubRow is one row of a genre hub.
+type GenreHubRow struct {
+	ID    string         `json:"id"` // trending | top-rated | new
+	Title string         `json:"title"`
+	Items []TrendingItem `json:"items"`
+}
+
+// Keyword is a TMDB keyword, usable as a discover filter.
+type Keyword struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// Mood is a curated discover preset such as "feel-good" or "heist", mapped
+// onto TMDB keywords and genres.
+type Mood struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
 }
 
 type SearchResult struct {
@@ -95,6 +150,53 @@ type SearchResult struct {
 	Score int   `json:"score"`
 }
 
+// Multi-search result types.
+const (
+	MultiSearchTypeMovie  = "movie"
+	MultiSearchTypeSeries = "series"
+	MultiSearchTypePerson = "person"
+)
+
+// SmartSearchParse is the structure SmartSearch read from a natural
+// language query.
+type SmartSearchParse struct {
+	MediaType  string    `json:"mediaType,omitempty"` // movie | series; empty for both
+	Genres     []string  `json:"genres,omitempty"`
+	YearFrom   int       `json:"yearFrom,omitempty"`
+	YearTo     int       `json:"yearTo,omitempty"`
+	Cast       []Person  `json:"cast,omitempty"`
+	Keywords   []Keyword `json:"keywords,omitempty"`
+	Sort       string    `json:"sort,omitempty"`      // rating | newest | oldest; empty for popularity
+	Unmatched  string    `json:"unmatched,omitempty"` // words that matched nothing
+	AIAssisted bool      `json:"aiAssisted,omitempty"`
+}
+
+// SmartSearch result sources.
+const (
+	SmartSearchSourceDiscover    = "discover"    // TMDB discover with Filters
+	SmartSearchSourceFilmography = "filmography" // a cast member's credits
+	SmartSearchSourceSearch      = "search"      // plain title search
+)
+
+// SmartSearchResult is the outcome of a natural language search: what was
+// understood, the discover filters it ran and the titles they returned.
+type SmartSearchResult struct {
+	Query   string           `json:"query"`
+	Parsed  SmartSearchParse `json:"parsed"`
+	Source  string           `json:"source"`
+	Filters []DiscoverFilter `json:"filters,omitempty"`
+	Items   []TrendingItem   `json:"items"`
+}
+
+// MultiSearchResult is one entry of a multi-search: a movie or series in
+// Title, or a person in Person, as given by Type.
+type MultiSearchResult struct {
+	Type   string  `json:"type"`
+	Title  *Title  `json:"title,omitempty"`
+	Person *Person `json:"person,omitempty"`
+	Score  int     `json:"score"`
+}
+
 type YouTubeVideoSearchResult struct {
 	ID           string `json:"id"`
 	URL          string `json:"url"`
@@ -115,12 +217,26 @@ type SeriesEpisode struct {
 	SeasonNumber          int    `json:"seasonNumber"`
 	EpisodeNumber         int    `json:"episodeNumber"`
 	AbsoluteEpisodeNumber int    `json:"absoluteEpisodeNumber,omitempty"`
-	AiredDate             string `json:"airedDate,omitempty"`
-	AiredDateTimeUTC      string `json:"airedDateTimeUTC,omitempty"`
-	Runtime               int    `json:"runtimeMinutes,omitempty"`
-	Image                 *Image `json:"image,omitempty"`
+	// AbsoluteNumberConfidence reports where AbsoluteEpisodeNumber came from;
+	// see the AbsoluteNumberConfidence* constants.
+	AbsoluteNumberConfidence string `json:"absoluteNumberConfidence,omitempty"`
+	AiredDate                string `json:"airedDate,omitempty"`
+	AiredDateTimeUTC         string `json:"airedDateTimeUTC,omitempty"`
+	Runtime                  int    `json:"runtimeMinutes,omitempty"`
+	Image                    *Image `json:"image,omitempty"`
+	// GuestStars lists the episode's guest cast from TMDB.
+	GuestStars []CastMember `json:"guestStars,omitempty"`
+	// Ratings holds the episode's TMDB rating and vote count.
+	Ratings []Rating `json:"ratings,omitempty"`
 }
 
+// Confidence levels for SeriesEpisode.AbsoluteEpisodeNumber.
+const (
+	AbsoluteNumberConfidenceHigh   = "high"   // supplied by TVDB
+	AbsoluteNumberConfidenceMedium = "medium" // mapped from a TMDB absolute episode group
+	AbsoluteNumberConfidenceLow    = "low"    // counted from the ordered episode list
+)
+
 type SeriesSeason struct {
 	ID           string          `json:"id"`
 	TVDBID       int64           `json:"tvdbId,omitempty"`
@@ -137,6 +253,106 @@ type SeriesDetails struct {
 	Title           Title          `json:"title"`
 	Seasons         []SeriesSeason `json:"seasons"`
 	PreferredSeason *int           `json:"preferredSeason,omitempty"`
+	NextEpisode     *NextEpisode   `json:"nextEpisode,omitempty"` // Computed per response; never cached
+	// WatchProgress is the requesting profile's per-season watched state. Filled by
+	// handlers when a userId is supplied; never cached.
+	WatchProgress []SeasonWatchProgress `json:"watchProgress,omitempty"`
+	// Anime holds AniList metadata when anime metadata is enabled for the series.
+	// Filled per response; never cached with the series.
+	Anime *AnimeDetails `json:"anime,omitempty"`
+}
+
+// AnimeDetails is anime-specific metadata sourced from AniList, matched to the
+// series through its TVDB/TMDB IDs.
+type AnimeDetails struct {
+	AniListID    int      `json:"anilistId"`
+	AniDBID      int      `json:"anidbId,omitempty"`
+	MALID        int      `json:"malId,omitempty"`
+	TitleRomaji  string   `json:"titleRomaji,omitempty"`
+	TitleEnglish string   `json:"titleEnglish,omitempty"`
+	TitleNative  string   `json:"titleNative,omitempty"` // kanji/kana
+	Synonyms     []string `json:"synonyms,omitempty"`
+	Poster       *Image   `json:"poster,omitempty"`
+	Banner       *Image   `json:"banner,omitempty"`
+	EpisodeCount int      `json:"episodeCount,omitempty"`
+	// AbsoluteOrdering is true when every regular episode of the series carries
+	// an absoluteEpisodeNumber, so clients can present continuous numbering.
+	AbsoluteOrdering bool `json:"absoluteOrdering"`
+}
+
+// SeasonWatchProgress summarizes a profile's watched state for one season so
+// season pickers can render progress without fetching full history.
+type SeasonWatchProgress struct {
+	SeasonNumber int     `json:"seasonNumber"`
+	EpisodeCount int     `json:"episodeCount"`
+	WatchedCount int     `json:"watchedCount"`
+	Percent      float64 `json:"percent"`
+	// Bitmap has one character per episode in season order: '1' watched, '0' not.
+	Bitmap string `json:"bitmap"`
+	// InProgressEpisodes lists episode numbers with partial playback progress.
+	InProgressEpisodes []int `json:"inProgressEpisodes,omitempty"`
+}
+
+// Request states reported in RequestAvailability.Status.
+const (
+	RequestStatusNotRequested       = "not_requested"
+	RequestStatusPending            = "pending" // awaiting approval
+	RequestStatusProcessing         = "processing"
+	RequestStatusPartiallyAvailable = "partially_available"
+	RequestStatusAvailable          = "available"
+	RequestStatusDeclined           = "declined"
+)
+
+// RequestAvailability is a title's state in an external request manager.
+type RequestAvailability struct {
+	Source    string `json:"source"` // "overseerr" (also used for Jellyseerr)
+	Status    string `json:"status"`
+	RequestID int    `json:"requestId,omitempty"`
+}
+
+// NextEpisode describes the next unaired episode of a series along with a
+// countdown relative to the time the response was built.
+type NextEpisode struct {
+	SeasonNumber     int    `json:"seasonNumber"`
+	EpisodeNumber    int    `json:"episodeNumber"`
+	Name             string `json:"name,omitempty"`
+	AiredDateTimeUTC string `json:"airedDateTimeUTC"`
+	CountdownSeconds int64  `json:"countdownSeconds"`
+}
+
+// ResolvedNextEpisode is the episode that follows a given one in a series.
+type ResolvedNextEpisode struct {
+	Episode          SeriesEpisode `json:"episode"`
+	Available        bool          `json:"available"`                  // Already aired
+	AirsAt           string        `json:"airsAt,omitempty"`           // RFC3339 UTC air time, when known
+	CountdownSeconds int64         `json:"countdownSeconds,omitempty"` // Seconds until AirsAt while upcoming
+	NewSeason        bool          `json:"newSeason,omitempty"`        // Starts a different season than the last episode
+}
+
+// WatchProvider is a streaming/rental service offering a title in a region.
+type WatchProvider struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	LogoURL string `json:"logoUrl,omitempty"`
+	Type    string `json:"type"` // flatrate, free, ads, rent, buy
+}
+
+// WatchProviderAvailability lists where a season or series can be watched in a region.
+// Link points at the provider overview page, which deep links to each service.
+type WatchProviderAvailability struct {
+	Region    string          `json:"region"`
+	Link      string          `json:"link,omitempty"`
+	Providers []WatchProvider `json:"providers"`
+}
+
+// ProviderDeparture is a streaming service dropping a title from its catalog
+// in a region.
+type ProviderDeparture struct {
+	ServiceID string    `json:"serviceId"` // e.g. netflix, prime, disney
+	Service   string    `json:"service"`
+	Type      string    `json:"type"` // subscription, free, addon
+	Link      string    `json:"link,omitempty"`
+	LeavesAt  time.Time `json:"leavesAt"`
 }
 
 type SeriesDetailsQuery struct {
@@ -193,9 +409,24 @@ type CastMember struct {
 	ProfileURL  string `json:"profileUrl,omitempty"`
 }
 
-// Credits contains cast information for a title
+// CrewMember represents a person credited behind the camera
+type CrewMember struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Job         string `json:"job"`
+	Department  string `json:"department"` // TMDB department: Directing, Writing, Production, Sound, ...
+	ProfilePath string `json:"profilePath,omitempty"`
+	ProfileURL  string `json:"profileUrl,omitempty"`
+}
+
+// Credits contains cast and crew information for a title. Directors, Writers
+// and Creators (series only) are picked out of the crew for convenience.
 type Credits struct {
-	Cast []CastMember `json:"cast"`
+	Cast      []CastMember `json:"cast"`
+	Crew      []CrewMember `json:"crew,omitempty"`
+	Directors []CrewMember `json:"directors,omitempty"`
+	Writers   []CrewMember `json:"writers,omitempty"`
+	Creators  []CrewMember `json:"creators,omitempty"`
 }
 
 // Collection represents a movie collection (e.g., "The Matrix Collection")
@@ -214,6 +445,17 @@ type CollectionDetails struct {
 	Poster   *Image  `json:"poster,omitempty"`
 	Backdrop *Image  `json:"backdrop,omitempty"`
 	Movies   []Title `json:"movies"`
+	// WatchProgress is set when the collection is requested for a profile.
+	WatchProgress *CollectionWatchProgress `json:"watchProgress,omitempty"`
+}
+
+// CollectionWatchProgress summarizes a profile's progress through a collection.
+type CollectionWatchProgress struct {
+	WatchedCount int `json:"watchedCount"`
+	TotalCount   int `json:"totalCount"`
+	// NextUnwatched is the first released movie, in collection order, that the
+	// profile has not finished. Nil when every released movie is watched.
+	NextUnwatched *Title `json:"nextUnwatched,omitempty"`
 }
 
 // Person represents an actor/crew member with detailed info
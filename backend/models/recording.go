@@ -40,6 +40,8 @@ type Recording struct {
 	ActualStartAt        *time.Time      `json:"actualStartAt,omitempty"`
 	ActualEndAt          *time.Time      `json:"actualEndAt,omitempty"`
 	Error                string          `json:"error,omitempty"`
+	RuleID               string          `json:"ruleId,omitempty"`  // recording rule that scheduled it
+	Episode              string          `json:"episode,omitempty"` // guide episode number, e.g. "S01E05"
 	CreatedAt            time.Time       `json:"createdAt"`
 	UpdatedAt            time.Time       `json:"updatedAt"`
 }
@@ -79,3 +81,49 @@ type CreateTimeBlockRecordingRequest struct {
 	PaddingBeforeSeconds int    `json:"paddingBeforeSeconds"`
 	PaddingAfterSeconds  int    `json:"paddingAfterSeconds"`
 }
+
+// RecordingRuleMatch selects how a recording rule matches guide programs.
+type RecordingRuleMatch string
+
+const (
+	// RecordingRuleMatchSeries records programs whose title equals the query.
+	RecordingRuleMatchSeries RecordingRuleMatch = "series"
+	// RecordingRuleMatchKeyword records programs whose title or description
+	// contains the query as whole words.
+	RecordingRuleMatchKeyword RecordingRuleMatch = "keyword"
+)
+
+// RecordingRule is a recurring recording: every guide program it matches is
+// scheduled as an EPG recording when the guide refreshes.
+type RecordingRule struct {
+	ID                   string             `json:"id"`
+	UserID               string             `json:"userId"`
+	Match                RecordingRuleMatch `json:"match"`
+	Query                string             `json:"query"`
+	ChannelID            string             `json:"channelId,omitempty"` // empty = any channel
+	TvgID                string             `json:"tvgId,omitempty"`
+	ChannelName          string             `json:"channelName,omitempty"`
+	NewOnly              bool               `json:"newOnly"`  // skip reruns and episodes already recorded
+	Priority             int                `json:"priority"` // higher wins when recordings conflict
+	PaddingBeforeSeconds int                `json:"paddingBeforeSeconds"`
+	PaddingAfterSeconds  int                `json:"paddingAfterSeconds"`
+	Enabled              bool               `json:"enabled"`
+	CreatedAt            time.Time          `json:"createdAt"`
+	UpdatedAt            time.Time          `json:"updatedAt"`
+}
+
+// RecordingRuleRequest creates or updates a recording rule. On update,
+// omitted fields are left unchanged.
+type RecordingRuleRequest struct {
+	ProfileID            string              `json:"profileId"`
+	Match                *RecordingRuleMatch `json:"match"`
+	Query                *string             `json:"query"`
+	ChannelID            *string             `json:"channelId"`
+	TvgID                *string             `json:"tvgId"`
+	ChannelName          *string             `json:"channelName"`
+	NewOnly              *bool               `json:"newOnly"`
+	Priority             *int                `json:"priority"`
+	PaddingBeforeSeconds *int                `json:"paddingBeforeSeconds"`
+	PaddingAfterSeconds  *int                `json:"paddingAfterSeconds"`
+	Enabled              *bool               `json:"enabled"`
+}
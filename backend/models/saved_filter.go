@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// DiscoverFilter is a set of TMDB discover criteria. Zero values leave a
+// criterion unset.
+type DiscoverFilter struct {
+	MediaType        string  `json:"mediaType"`                  // movie | series
+	GenreIDs         []int64 `json:"genreIds,omitempty"`         // TMDB genre IDs; titles must match all of them
+	KeywordIDs       []int64 `json:"keywordIds,omitempty"`       // TMDB keyword IDs; titles must match any of them
+	CastIDs          []int64 `json:"castIds,omitempty"`          // TMDB person IDs; movies must feature all of them (TMDB can't filter series by cast)
+	YearFrom         int     `json:"yearFrom,omitempty"`         // First release (or first air) year, inclusive
+	YearTo           int     `json:"yearTo,omitempty"`           // Last release (or first air) year, inclusive
+	RuntimeMin       int     `json:"runtimeMin,omitempty"`       // Minutes
+	RuntimeMax       int     `json:"runtimeMax,omitempty"`       // Minutes
+	MinRating        float64 `json:"minRating,omitempty"`        // TMDB vote average, 0-10
+	MinVotes         int     `json:"minVotes,omitempty"`         // TMDB vote count floor
+	OriginalLanguage string  `json:"originalLanguage,omitempty"` // ISO 639-1 code, e.g. "ja"
+	WatchProviderIDs []int64 `json:"watchProviderIds,omitempty"` // TMDB watch provider IDs; titles must stream on any of them
+	WatchRegion      string  `json:"watchRegion,omitempty"`      // ISO 3166-1 country for WatchProviderIDs; defaults to the server's watch provider country
+	Sort             string  `json:"sort,omitempty"`             // popularity (default) | rating | newest | oldest | title
+}
+
+// SavedFilter is a named discover filter saved by a profile. Saved filters
+// show up as browse entries and can back a home shelf.
+type SavedFilter struct {
+	ID        string         `json:"id"`
+	UserID    string         `json:"userId"`
+	Name      string         `json:"name"`
+	Filter    DiscoverFilter `json:"filter"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
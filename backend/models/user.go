@@ -26,13 +26,21 @@ type User struct {
 	SimklAccountID   string `json:"simklAccountId,omitempty"`   // ID of the linked Simkl account (from config.SimklAccount)
 	IsKidsProfile    bool   `json:"isKidsProfile"`              // Whether this is a kids profile with content restrictions
 	// Kids profile content restriction settings
-	KidsMode           string    `json:"kidsMode,omitempty"`           // "rating", "content_list", or "" (disabled)
-	KidsMaxRating      string    `json:"kidsMaxRating,omitempty"`      // Deprecated: use KidsMaxMovieRating/KidsMaxTVRating instead
-	KidsMaxMovieRating string    `json:"kidsMaxMovieRating,omitempty"` // Max allowed movie rating: "G", "PG", "PG-13", "R", "NC-17"
-	KidsMaxTVRating    string    `json:"kidsMaxTVRating,omitempty"`    // Max allowed TV rating: "TV-Y", "TV-Y7", "TV-G", "TV-PG", "TV-14", "TV-MA"
-	KidsAllowedLists   []string  `json:"kidsAllowedLists,omitempty"`   // MDBList URLs allowed for content_list mode
-	CreatedAt          time.Time `json:"createdAt"`
-	UpdatedAt          time.Time `json:"updatedAt"`
+	KidsMode           string   `json:"kidsMode,omitempty"`           // "rating", "content_list", "allow_list", or "" (disabled)
+	KidsMaxRating      string   `json:"kidsMaxRating,omitempty"`      // Deprecated: use KidsMaxMovieRating/KidsMaxTVRating instead
+	KidsMaxMovieRating string   `json:"kidsMaxMovieRating,omitempty"` // Max allowed movie rating: "G", "PG", "PG-13", "R", "NC-17"
+	KidsMaxTVRating    string   `json:"kidsMaxTVRating,omitempty"`    // Max allowed TV rating: "TV-Y", "TV-Y7", "TV-G", "TV-PG", "TV-14", "TV-MA"
+	KidsAllowedLists   []string `json:"kidsAllowedLists,omitempty"`   // MDBList URLs allowed for content_list mode; MDBList or TMDB lists for allow_list mode
+	KidsAllowedGenres  []string `json:"kidsAllowedGenres,omitempty"`  // TMDB genre names allowed for allow_list mode
+	// Content rating ceiling for any profile, enforced by the server on top of
+	// the kids settings. An admin parental-controls PIN lifts it per request.
+	MaxMovieRating string `json:"maxMovieRating,omitempty"` // Max allowed movie rating, e.g. "PG-13"
+	MaxTVRating    string `json:"maxTVRating,omitempty"`    // Max allowed TV rating, e.g. "TV-14"
+	// SHA-256 of the token calendar apps subscribe to the profile's feeds
+	// with — persisted to disk, stripped from API responses by MarshalJSON.
+	CalendarFeedTokenHash string    `json:"calendarFeedTokenHash,omitempty"`
+	CreatedAt             time.Time `json:"createdAt"`
+	UpdatedAt             time.Time `json:"updatedAt"`
 }
 
 // HasPin returns true if the user has a PIN set.
@@ -52,7 +60,9 @@ func (u User) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
 		UserAlias
 		PinHash          *struct{} `json:"pinHash,omitempty"` // shadow to exclude from output (nil + omitempty = dropped)
+		FeedTokenHash    *struct{} `json:"calendarFeedTokenHash,omitempty"`
 		HasPin           bool      `json:"hasPin"`
+		HasCalendarFeed  bool      `json:"hasCalendarFeed"`
 		HasIcon          bool      `json:"hasIcon"`
 		TraktAccountID   string    `json:"traktAccountId,omitempty"`
 		PlexAccountID    string    `json:"plexAccountId,omitempty"`
@@ -62,6 +72,7 @@ func (u User) MarshalJSON() ([]byte, error) {
 		UserAlias:        UserAlias(u),
 		PinHash:          nil,
 		HasPin:           u.HasPin(),
+		HasCalendarFeed:  u.CalendarFeedTokenHash != "",
 		HasIcon:          u.HasIcon(),
 		TraktAccountID:   u.TraktAccountID,
 		PlexAccountID:    u.PlexAccountID,
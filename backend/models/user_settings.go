@@ -1,5 +1,7 @@
 package models
 
+import "novastream/config"
+
 // Helper functions for creating pointers (exported for use by other packages)
 func FloatPtr(v float64) *float64 { return &v }
 func BoolPtr(v bool) *bool        { return &v }
@@ -47,6 +49,22 @@ type UserSettings struct {
 // MetadataSettings contains per-profile metadata presentation preferences.
 type MetadataSettings struct {
 	PrimaryLanguage string `json:"primaryLanguage,omitempty"`
+	// CertificationCountry overrides the server's content rating and release
+	// date country (ISO 3166-1) for this profile. Empty inherits it.
+	CertificationCountry string `json:"certificationCountry,omitempty"`
+	// StreamingServices are the streaming service IDs (e.g. "netflix") the
+	// profile subscribes to, for "Leaving soon". Empty inherits the server's.
+	StreamingServices []string `json:"streamingServices,omitempty"`
+	// Artwork overrides the server's artwork preferences; empty fields
+	// inherit them.
+	Artwork config.ArtworkPreferences `json:"artwork"`
+	// TitleLanguage is "original" to name titles in their original language
+	// instead of the metadata language ("translated", the default).
+	TitleLanguage string `json:"titleLanguage,omitempty"`
+	// AnimeTitles is "romaji" or "native" to name anime by their AniList
+	// romaji or native-script title where AniList data is attached. Empty
+	// follows TitleLanguage.
+	AnimeTitles string `json:"animeTitles,omitempty"`
 }
 
 // CalendarSettings controls which content sources populate the calendar.
@@ -58,6 +76,7 @@ type CalendarSettings struct {
 	TopTrending    *bool           `json:"topTrending,omitempty"`    // Include the top 20 entries from the trending lists
 	MDBLists       *bool           `json:"mdblists,omitempty"`       // Include content from enabled MDBList shelves
 	MDBListShelves map[string]bool `json:"mdblistShelves,omitempty"` // Per-shelf calendar enable: shelf ID -> enabled (nil = all enabled)
+	ReleaseFeed    *bool           `json:"releaseFeed,omitempty"`    // Publish new releases from the watchlist and lists as an RSS/JSON feed (off by default)
 }
 
 // MDBListShelfEnabled returns whether a specific MDBList shelf is enabled for the calendar.
@@ -109,10 +128,29 @@ type DisplaySettings struct {
 	BlurUnwatchedEpisodeOverviewsIncludeCurrent *bool `json:"blurUnwatchedEpisodeOverviewsIncludeCurrent,omitempty"`
 	// AppLanguage overrides the app UI language (ISO 639-1 code, e.g. "en", "fr"). Empty = use device locale.
 	AppLanguage string `json:"appLanguage,omitempty"`
+	// DateFormat orders numeric dates in server-rendered text: "mdy", "dmy" or "ymd". Empty = inherit.
+	DateFormat string `json:"dateFormat,omitempty"`
+	// TimeFormat is the clock style of server-rendered text: "12h" or "24h". Empty = inherit.
+	TimeFormat string `json:"timeFormat,omitempty"`
+	// LandingTab is the navigation tab the app opens on, one of NavigationTabs. Empty = inherit.
+	LandingTab string `json:"landingTab,omitempty"`
 	// Appearance controls app-wide visual accessibility and theming preferences.
 	Appearance AppearanceSettings `json:"appearance,omitempty"`
 }
 
+// NavigationTabs lists the client navigation tabs, in their default order.
+var NavigationTabs = []string{"home", "search", "lists", "live", "profiles", "downloads", "settings", "admin"}
+
+// IsNavigationTab reports whether tab is one of NavigationTabs.
+func IsNavigationTab(tab string) bool {
+	for _, known := range NavigationTabs {
+		if tab == known {
+			return true
+		}
+	}
+	return false
+}
+
 // AddMissingSystemNavigationTabs appends tabs that became configurable after
 // the original navigation visibility setting shipped. It only changes non-empty
 // lists; empty lists keep their existing "use defaults" behavior.
@@ -153,9 +191,10 @@ type AppearanceSettings struct {
 
 // LiveTVSettings contains per-user Live TV preferences.
 type LiveTVSettings struct {
-	HiddenChannels     []string `json:"hiddenChannels"`     // Channel IDs that are hidden
-	FavoriteChannels   []string `json:"favoriteChannels"`   // Channel IDs that are favorited
-	SelectedCategories []string `json:"selectedCategories"` // Selected category filters
+	HiddenChannels     []string `json:"hiddenChannels"`         // Channel IDs that are hidden
+	FavoriteChannels   []string `json:"favoriteChannels"`       // Channel IDs that are favorited
+	ChannelOrder       []string `json:"channelOrder,omitempty"` // Custom channel order by ID; unlisted channels keep playlist order
+	SelectedCategories []string `json:"selectedCategories"`     // Selected category filters
 	// Per-profile IPTV source override (nil = use global)
 	Mode            *string              `json:"mode,omitempty"`
 	PlaylistURL     *string              `json:"playlistUrl,omitempty"`
@@ -366,6 +405,7 @@ type PlaybackSettings struct {
 	CreditsAutoSkip               bool     `json:"creditsAutoSkip,omitempty"`                     // Automatically play the next episode after credits are detected
 	CreditsDetection              bool     `json:"creditsDetection,omitempty"`                    // Legacy name for creditsAutoSkip
 	MatchFrameRate                *bool    `json:"matchFrameRate,omitempty"`                      // Request TV display refresh rate matching during playback
+	DisableAutoPlayNext           *bool    `json:"disableAutoPlayNext,omitempty"`                 // Stay on the finished episode instead of starting the next one
 	MaxConcurrentStreams          *int     `json:"maxConcurrentStreams,omitempty"`                // Per-profile concurrent stream limit (nil = use account limit)
 	MaxResultsPerResolution       *int     `json:"maxResultsPerResolution,omitempty"`             // Maximum number of results per resolution tier (0 = no limit)
 }
@@ -376,18 +416,20 @@ type ShelfConfig struct {
 	Name                   string                 `json:"name"`                             // Display name
 	Enabled                bool                   `json:"enabled"`                          // Whether the shelf is visible
 	Order                  int                    `json:"order"`                            // Sort order (lower numbers appear first)
-	Type                   string                 `json:"type,omitempty"`                   // "builtin" (default), "mdblist", "trakt", "simkl", "letterboxd", "genre", "decade", "collection-hub", or "local-library"
+	Type                   string                 `json:"type,omitempty"`                   // "builtin" (default), "mdblist", "trakt", "simkl", "letterboxd", "genre", "decade", "collection-hub", "local-library", or "saved-filter"
 	ListURL                string                 `json:"listUrl,omitempty"`                // MDBList URL for custom lists (e.g., https://mdblist.com/lists/username/list-name/json)
+	TrendingSource         string                 `json:"trendingSource,omitempty"`         // For trending shelves: "mdblist" (default) or "provider:list", e.g. "trakt:popular"
 	StreamingServices      []StreamingServiceLink `json:"streamingServices,omitempty"`      // Service cards for the built-in Streaming Services shelf
 	CollectionItems        []CollectionHubLink    `json:"collectionItems,omitempty"`        // Shelf cards for collection hub shelves
-	TraktAccountID         string                 `json:"traktAccountId,omitempty"`         // Trakt account ID, or "__all__" for master-account global watchlists
-	TraktListType          string                 `json:"traktListType,omitempty"`          // "watchlist" or "custom"
+	TraktAccountID         string                 `json:"traktAccountId,omitempty"`         // Trakt account ID, "__all__" for master-account global watchlists, or "__profile__" for the profile's linked account (recommendations only)
+	TraktListType          string                 `json:"traktListType,omitempty"`          // "watchlist", "custom" or "recommendations"
 	TraktListID            string                 `json:"traktListId,omitempty"`            // Trakt custom list slug/ID when traktListType == "custom"
 	SimklAccountID         string                 `json:"simklAccountId,omitempty"`         // Simkl account ID
 	SimklListType          string                 `json:"simklListType,omitempty"`          // Simkl status bucket: "plantowatch", "watching", "completed", "hold", or "dropped"
 	SimklMediaType         string                 `json:"simklMediaType,omitempty"`         // Simkl media bucket: "movies", "shows", or "anime"
 	LetterboxdListID       string                 `json:"letterboxdListId,omitempty"`       // MDBList external-list ID for an imported Letterboxd list
 	LetterboxdListURL      string                 `json:"letterboxdListUrl,omitempty"`      // Public Letterboxd list URL
+	SavedFilterID          string                 `json:"savedFilterId,omitempty"`          // Profile saved discover filter ID for "saved-filter" shelves
 	Limit                  int                    `json:"limit,omitempty"`                  // Optional limit on number of items returned (0 = no limit)
 	HideUnreleased         bool                   `json:"hideUnreleased,omitempty"`         // Filter out unreleased/in-theaters content
 	Sort                   string                 `json:"sort,omitempty"`                   // Optional shelf-specific sort mode
@@ -457,6 +499,8 @@ func DefaultHomeShelfConfigs() []ShelfConfig {
 		{ID: "trending-movies", Name: "Trending Movies", Enabled: true, Order: 7},
 		{ID: "trending-tv", Name: "Trending TV Shows", Enabled: true, Order: 8},
 		{ID: "streaming-services", Name: "Streaming Services", Enabled: true, Order: 9},
+		{ID: "leaving-soon", Name: "Leaving Soon", Enabled: false, Order: 10},
+		{ID: "complete-collection", Name: "Complete the Collection", Enabled: true, Order: 11},
 	}
 }
 
@@ -634,6 +678,39 @@ func EnsureDefaultHomeShelves(shelves []ShelfConfig) ([]ShelfConfig, bool) {
 		changed = true
 	}
 
+	if !hasShelf("leaving-soon") {
+		// Off until a Streaming Availability key is set; goes last.
+		insertOrder := 0
+		for _, shelf := range nextShelves {
+			if shelf.Order >= insertOrder {
+				insertOrder = shelf.Order + 1
+			}
+		}
+		nextShelves = append(nextShelves, ShelfConfig{
+			ID:      "leaving-soon",
+			Name:    "Leaving Soon",
+			Enabled: false,
+			Order:   insertOrder,
+		})
+		changed = true
+	}
+
+	if !hasShelf("complete-collection") {
+		insertOrder := 0
+		for _, shelf := range nextShelves {
+			if shelf.Order >= insertOrder {
+				insertOrder = shelf.Order + 1
+			}
+		}
+		nextShelves = append(nextShelves, ShelfConfig{
+			ID:      "complete-collection",
+			Name:    "Complete the Collection",
+			Enabled: true,
+			Order:   insertOrder,
+		})
+		changed = true
+	}
+
 	return nextShelves, changed
 }
 
@@ -694,6 +771,7 @@ func DefaultUserSettings() UserSettings {
 			IgnoreDVCompatibilityCheck:    BoolPtr(false),
 			CreditsDetectionEnabled:       BoolPtr(true),
 			MatchFrameRate:                BoolPtr(false),
+			DisableAutoPlayNext:           BoolPtr(false),
 		},
 		HomeShelves: HomeShelvesSettings{
 			Shelves:                         DefaultHomeShelfConfigs(),
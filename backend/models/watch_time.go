@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// Watch time statuses reported in a WatchTimeSummary.
+const (
+	WatchTimeStatusNone    = "none"    // No goal is set
+	WatchTimeStatusOK      = "ok"      // Below the warning threshold
+	WatchTimeStatusWarning = "warning" // Past the warning threshold
+	WatchTimeStatusReached = "reached" // The weekly goal is used up
+)
+
+// WatchTimeGoal is a profile's weekly screen-time goal. Passing WarnPercent
+// of it sends a soft warning; with HardLimit set (kids profiles only, set by
+// an admin) playback is refused once the week's time reaches it.
+type WatchTimeGoal struct {
+	UserID        string    `json:"userId"`
+	WeeklyMinutes int       `json:"weeklyMinutes"`
+	WarnPercent   int       `json:"warnPercent,omitempty"` // 0 uses the default of 80
+	HardLimit     bool      `json:"hardLimit,omitempty"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// WatchTimeDay is the playback time a profile logged on one day.
+type WatchTimeDay struct {
+	UserID  string  `json:"userId,omitempty"`
+	Date    string  `json:"date"` // YYYY-MM-DD in the server's time zone
+	Seconds float64 `json:"seconds"`
+}
+
+// WatchTimeSummary reports a profile's playback time for the current week,
+// which starts on Monday, against its goal.
+type WatchTimeSummary struct {
+	WeekStart        time.Time      `json:"weekStart"`
+	Minutes          int            `json:"minutes"`
+	Days             []WatchTimeDay `json:"days"`
+	Goal             *WatchTimeGoal `json:"goal,omitempty"`
+	RemainingMinutes int            `json:"remainingMinutes,omitempty"`
+	Status           string         `json:"status"`  // none | ok | warning | reached
+	Blocked          bool           `json:"blocked"` // Playback is refused until the week ends
+}
@@ -4,29 +4,38 @@ import "time"
 
 // WatchlistItem represents a media entry saved by the user for quick access.
 type WatchlistItem struct {
-	ID              string            `json:"id"`
-	MediaType       string            `json:"mediaType"` // movie | series
-	Name            string            `json:"name"`
-	Overview        string            `json:"overview,omitempty"`
-	Year            int               `json:"year,omitempty"`
-	PosterURL       string            `json:"posterUrl,omitempty"`
-	TextPosterURL   string            `json:"textPosterUrl,omitempty"` // Poster with title text (enriched at response time)
-	BackdropURL     string            `json:"backdropUrl,omitempty"`
-	TextBackdropURL string            `json:"textBackdropUrl,omitempty"` // Backdrop with title text (enriched at response time)
-	BackdropURLs    []string          `json:"backdropUrls,omitempty"`    // Ranked alternate backdrops (enriched at response time)
-	AddedAt         time.Time         `json:"addedAt"`
-	ExternalIDs     map[string]string `json:"externalIds,omitempty"`
-	Genres          []string          `json:"genres,omitempty"`
-	RuntimeMinutes  int               `json:"runtimeMinutes,omitempty"`
-	SyncSource      string            `json:"syncSource,omitempty"`     // e.g., "plex:<accountId>:<taskId>" for synced items
-	SyncedAt        *time.Time        `json:"syncedAt,omitempty"`       // when last synced from external source
-	WatchState      string            `json:"watchState,omitempty"`     // "none" | "partial" | "complete"
-	UnwatchedCount  *int              `json:"unwatchedCount,omitempty"` // series only: total - watched
-	Ratings         []Rating          `json:"ratings,omitempty"`        // hydrated at response time from MDBList
-	Theatrical      *Release          `json:"theatricalRelease,omitempty"`
-	HomeRelease     *Release          `json:"homeRelease,omitempty"`
+	ID              string             `json:"id"`
+	MediaType       string             `json:"mediaType"` // movie | series
+	Name            string             `json:"name"`
+	Overview        string             `json:"overview,omitempty"`
+	Year            int                `json:"year,omitempty"`
+	PosterURL       string             `json:"posterUrl,omitempty"`
+	TextPosterURL   string             `json:"textPosterUrl,omitempty"` // Poster with title text (enriched at response time)
+	BackdropURL     string             `json:"backdropUrl,omitempty"`
+	TextBackdropURL string             `json:"textBackdropUrl,omitempty"` // Backdrop with title text (enriched at response time)
+	BackdropURLs    []string           `json:"backdropUrls,omitempty"`    // Ranked alternate backdrops (enriched at response time)
+	AddedAt         time.Time          `json:"addedAt"`
+	ExternalIDs     map[string]string  `json:"externalIds,omitempty"`
+	Genres          []string           `json:"genres,omitempty"`
+	RuntimeMinutes  int                `json:"runtimeMinutes,omitempty"`
+	SyncSource      string             `json:"syncSource,omitempty"`     // e.g., "plex:<accountId>:<taskId>" for synced items
+	SyncedAt        *time.Time         `json:"syncedAt,omitempty"`       // when last synced from external source
+	Priority        string             `json:"priority,omitempty"`       // high | normal | someday; empty means normal
+	WatchState      string             `json:"watchState,omitempty"`     // "none" | "partial" | "complete"
+	UnwatchedCount  *int               `json:"unwatchedCount,omitempty"` // series only: total - watched
+	Ratings         []Rating           `json:"ratings,omitempty"`        // hydrated at response time from MDBList
+	Theatrical      *Release           `json:"theatricalRelease,omitempty"`
+	HomeRelease     *Release           `json:"homeRelease,omitempty"`
+	Leaving         *ProviderDeparture `json:"leaving,omitempty"` // "Leaving soon" shelf only
 }
 
+// Watchlist priority levels. Items without a priority are treated as normal.
+const (
+	WatchlistPriorityHigh    = "high"
+	WatchlistPriorityNormal  = "normal"
+	WatchlistPrioritySomeday = "someday"
+)
+
 // WatchlistTombstone records an explicit user removal so source syncs do not
 // silently re-add the same item under a different provider ID.
 type WatchlistTombstone struct {
@@ -53,6 +62,7 @@ type WatchlistUpsert struct {
 	RuntimeMinutes int               `json:"runtimeMinutes,omitempty"`
 	SyncSource     string            `json:"syncSource,omitempty"` // sync source identifier for tracking origin
 	SyncedAt       *time.Time        `json:"syncedAt,omitempty"`   // sync timestamp
+	Priority       string            `json:"priority,omitempty"`   // high | normal | someday; empty keeps the current priority
 }
 
 // Key returns a stable identifier for the watchlist item combining media type and ID.
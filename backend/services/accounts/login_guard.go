@@ -0,0 +1,292 @@
+package accounts
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"novastream/models"
+)
+
+// Login throttling: once a username collects loginFreeAttempts consecutive
+// failures from one IP address, or the address collects loginFreeAttemptsPerIP
+// across all usernames, further attempts are refused for a lockout that
+// doubles with every additional failure. Lockouts never span addresses, so a
+// stranger cannot lock an account's owner out.
+const (
+	loginFreeAttempts      = 5
+	loginFreeAttemptsPerIP = 20
+	loginBaseLockout       = 30 * time.Second
+	loginMaxLockout        = time.Hour
+	loginFailureWindow     = 24 * time.Hour // failure streaks idle this long are forgotten
+	loginAuditCapacity     = 500
+)
+
+// Reasons recorded in the login audit trail.
+const (
+	LoginFailureInvalidCredentials = "invalid_credentials"
+	LoginFailureExpired            = "expired"
+	LoginFailureLockedOut          = "locked_out"
+)
+
+// ErrLoginLockedOut matches a *LockedOutError with errors.Is.
+var ErrLoginLockedOut = errors.New("too many failed login attempts")
+
+// LockedOutError is returned by Login while the IP address, or the username
+// from that address, is locked out.
+type LockedOutError struct {
+	Until time.Time
+}
+
+func (e *LockedOutError) Error() string {
+	return fmt.Sprintf("%s, try again after %s", ErrLoginLockedOut, e.Until.Format(time.RFC3339))
+}
+
+func (e *LockedOutError) Is(target error) bool { return target == ErrLoginLockedOut }
+
+// LoginAttempt is a failed login recorded in the audit trail.
+type LoginAttempt struct {
+	Time      time.Time `json:"time"`
+	Username  string    `json:"username"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"userAgent,omitempty"`
+	Reason    string    `json:"reason"`
+	Shared    bool      `json:"shared,omitempty"` // IP is an untrusted proxy fronting many clients
+}
+
+// LoginLockout is an IP address, or a username from one IP address, that is
+// currently locked out.
+type LoginLockout struct {
+	Kind     string    `json:"kind"` // "ip" or "account"
+	Key      string    `json:"key"`
+	IP       string    `json:"ip,omitempty"` // the address an account lockout applies to
+	Failures int       `json:"failures"`
+	Until    time.Time `json:"until"`
+}
+
+type loginStreak struct {
+	failures    int
+	last        time.Time
+	lockedUntil time.Time
+}
+
+// loginGuard tracks failure streaks per IP and per (IP, username) pair, and
+// keeps a bounded trail of failed attempts. State is in memory and resets on
+// restart.
+type loginGuard struct {
+	mu      sync.Mutex
+	streaks map[string]*loginStreak // keyed "ip:<addr>" or "account:<addr>|<username>"
+	audit   []LoginAttempt
+	next    int
+	now     func() time.Time
+}
+
+func newLoginGuard() *loginGuard {
+	return &loginGuard{
+		streaks: make(map[string]*loginStreak),
+		now:     time.Now,
+	}
+}
+
+// loginKeys returns the streak keys an attempt counts against. A shared
+// address only throttles the usernames tried from it: locking the address
+// itself would lock out everyone behind the proxy.
+func loginKeys(username, ip string, shared bool) []string {
+	keys := make([]string, 0, 2)
+	if ip = strings.TrimSpace(ip); ip != "" && !shared {
+		keys = append(keys, "ip:"+ip)
+	}
+	if username = strings.ToLower(strings.TrimSpace(username)); username != "" {
+		keys = append(keys, "account:"+ip+"|"+username)
+	}
+	return keys
+}
+
+// loginThreshold returns how many consecutive failures a streak key allows
+// before locking out.
+func loginThreshold(key string) int {
+	if strings.HasPrefix(key, "ip:") {
+		return loginFreeAttemptsPerIP
+	}
+	return loginFreeAttempts
+}
+
+// lockedUntil returns the latest lockout covering the IP or the username from
+// that IP.
+func (g *loginGuard) lockedUntil(username, ip string, shared bool, now time.Time) (time.Time, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var until time.Time
+	for _, key := range loginKeys(username, ip, shared) {
+		if streak, ok := g.streaks[key]; ok && streak.lockedUntil.After(now) && streak.lockedUntil.After(until) {
+			until = streak.lockedUntil
+		}
+	}
+	return until, !until.IsZero()
+}
+
+// fail records a failed attempt. Only credential failures extend the streaks;
+// the returned time is the lockout the failure triggered, if any.
+func (g *loginGuard) fail(attempt LoginAttempt) time.Time {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.recordLocked(attempt)
+	if attempt.Reason != LoginFailureInvalidCredentials {
+		return time.Time{}
+	}
+
+	var until time.Time
+	for _, key := range loginKeys(attempt.Username, attempt.IP, attempt.Shared) {
+		streak, ok := g.streaks[key]
+		if !ok || attempt.Time.Sub(streak.last) > loginFailureWindow {
+			streak = &loginStreak{}
+			g.streaks[key] = streak
+		}
+		streak.failures++
+		streak.last = attempt.Time
+		if threshold := loginThreshold(key); streak.failures >= threshold {
+			streak.lockedUntil = attempt.Time.Add(loginLockoutFor(streak.failures - threshold + loginFreeAttempts))
+			if streak.lockedUntil.After(until) {
+				until = streak.lockedUntil
+			}
+		}
+	}
+	g.pruneLocked(attempt.Time)
+	return until
+}
+
+// succeed clears the streaks of the IP and the username from it after a good
+// login.
+func (g *loginGuard) succeed(username, ip string, shared bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, key := range loginKeys(username, ip, shared) {
+		delete(g.streaks, key)
+	}
+}
+
+func (g *loginGuard) recordLocked(attempt LoginAttempt) {
+	if len(g.audit) < loginAuditCapacity {
+		g.audit = append(g.audit, attempt)
+		return
+	}
+	g.audit[g.next] = attempt
+	g.next = (g.next + 1) % loginAuditCapacity
+}
+
+func (g *loginGuard) pruneLocked(now time.Time) {
+	for key, streak := range g.streaks {
+		if now.Sub(streak.last) > loginFailureWindow && !streak.lockedUntil.After(now) {
+			delete(g.streaks, key)
+		}
+	}
+}
+
+func (g *loginGuard) attempts(limit int) []LoginAttempt {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	out := make([]LoginAttempt, 0, len(g.audit))
+	for i := len(g.audit) - 1; i >= 0; i-- {
+		out = append(out, g.audit[(g.next+i)%len(g.audit)])
+		if limit > 0 && len(out) == limit {
+			break
+		}
+	}
+	return out
+}
+
+func (g *loginGuard) lockouts(now time.Time) []LoginLockout {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var out []LoginLockout
+	for key, streak := range g.streaks {
+		if !streak.lockedUntil.After(now) {
+			continue
+		}
+		kind, value, _ := strings.Cut(key, ":")
+		lockout := LoginLockout{Kind: kind, Key: value, Failures: streak.failures, Until: streak.lockedUntil}
+		if ip, username, ok := strings.Cut(value, "|"); ok && kind == "account" {
+			lockout.Key, lockout.IP = username, ip
+		}
+		out = append(out, lockout)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Until.After(out[j].Until) })
+	return out
+}
+
+func (g *loginGuard) reset() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.streaks = make(map[string]*loginStreak)
+}
+
+// loginLockoutFor returns the lockout after the given number of consecutive
+// failures: the base lockout at the threshold, doubling from there.
+func loginLockoutFor(failures int) time.Duration {
+	lockout := loginBaseLockout
+	for i := loginFreeAttempts; i < failures && lockout < loginMaxLockout; i++ {
+		lockout *= 2
+	}
+	return min(lockout, loginMaxLockout)
+}
+
+// Login authenticates like Authenticate, refusing attempts from locked-out IP
+// addresses or against usernames locked out for that address, and recording
+// failures in the login audit trail.
+func (s *Service) Login(username, password, ip, userAgent string) (models.Account, error) {
+	return s.login(username, password, ip, userAgent, false)
+}
+
+// LoginFromSharedAddress is Login for an address that stands in for many
+// clients, such as a reverse proxy that is not trusted to report the real
+// client: only the username from that address is throttled, never the
+// address as a whole.
+func (s *Service) LoginFromSharedAddress(username, password, ip, userAgent string) (models.Account, error) {
+	return s.login(username, password, ip, userAgent, true)
+}
+
+func (s *Service) login(username, password, ip, userAgent string, shared bool) (models.Account, error) {
+	now := s.guard.now()
+	attempt := LoginAttempt{Time: now, Username: strings.TrimSpace(username), IP: ip, UserAgent: userAgent, Shared: shared}
+	if until, locked := s.guard.lockedUntil(username, ip, shared, now); locked {
+		attempt.Reason = LoginFailureLockedOut
+		s.guard.fail(attempt)
+		return models.Account{}, &LockedOutError{Until: until}
+	}
+
+	account, err := s.Authenticate(username, password)
+	if err != nil {
+		attempt.Reason = LoginFailureInvalidCredentials
+		if errors.Is(err, ErrAccountExpired) {
+			attempt.Reason = LoginFailureExpired
+		}
+		if until := s.guard.fail(attempt); !until.IsZero() {
+			log.Printf("[auth] locking out user=%q ip=%s until %s after repeated failed logins", attempt.Username, ip, until.Format(time.RFC3339))
+		}
+		return models.Account{}, err
+	}
+	s.guard.succeed(username, ip, shared)
+	return account, nil
+}
+
+// LoginAttempts returns the most recent failed login attempts, newest first.
+// A limit of zero returns the whole trail.
+func (s *Service) LoginAttempts(limit int) []LoginAttempt {
+	return s.guard.attempts(limit)
+}
+
+// LoginLockouts returns the IP addresses and per-address usernames currently
+// locked out.
+func (s *Service) LoginLockouts() []LoginLockout {
+	return s.guard.lockouts(s.guard.now())
+}
+
+// ClearLoginLockouts lifts all lockouts and resets failure streaks. The audit
+// trail is kept.
+func (s *Service) ClearLoginLockouts() {
+	s.guard.reset()
+}
@@ -0,0 +1,156 @@
+package accounts
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLogin_LocksOutAfterRepeatedFailures(t *testing.T) {
+	svc := setupTestService(t)
+	if _, err := svc.Create("alice", "correct-horse"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.guard.now = func() time.Time { return now }
+
+	for i := 0; i < loginFreeAttempts; i++ {
+		if _, err := svc.Login("alice", "wrong", "10.0.0.1", "test"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("attempt %d: expected invalid credentials, got %v", i+1, err)
+		}
+	}
+
+	_, err := svc.Login("alice", "correct-horse", "10.0.0.1", "test")
+	var locked *LockedOutError
+	if !errors.As(err, &locked) || !errors.Is(err, ErrLoginLockedOut) {
+		t.Fatalf("expected lockout, got %v", err)
+	}
+	if want := now.Add(loginBaseLockout); !locked.Until.Equal(want) {
+		t.Fatalf("lockout until %s, want %s", locked.Until, want)
+	}
+
+	// The lockout is tied to the address, so the owner can still sign in
+	// from elsewhere.
+	if _, err := svc.Login("ALICE", "correct-horse", "10.0.0.2", "test"); err != nil {
+		t.Fatalf("expected login from another IP, got %v", err)
+	}
+
+	lockouts := svc.LoginLockouts()
+	if len(lockouts) != 1 || lockouts[0].Kind != "account" || lockouts[0].Key != "alice" || lockouts[0].IP != "10.0.0.1" {
+		t.Fatalf("expected alice locked out from 10.0.0.1 only, got %+v", lockouts)
+	}
+
+	now = now.Add(loginBaseLockout + time.Second)
+	if _, err := svc.Login("alice", "correct-horse", "10.0.0.1", "test"); err != nil {
+		t.Fatalf("expected login after lockout expired, got %v", err)
+	}
+	if lockouts := svc.LoginLockouts(); len(lockouts) != 0 {
+		t.Fatalf("expected success to clear lockouts, got %+v", lockouts)
+	}
+}
+
+func TestLogin_LocksOutAddressSprayingUsernames(t *testing.T) {
+	svc := setupTestService(t)
+	if _, err := svc.Create("alice", "correct-horse"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.guard.now = func() time.Time { return now }
+
+	for i := 0; i < loginFreeAttemptsPerIP; i++ {
+		svc.Login(fmt.Sprintf("user%d", i), "wrong", "10.0.0.9", "test")
+	}
+	if _, err := svc.Login("alice", "correct-horse", "10.0.0.9", "test"); !errors.Is(err, ErrLoginLockedOut) {
+		t.Fatalf("expected address lockout, got %v", err)
+	}
+	if _, err := svc.Login("alice", "correct-horse", "10.0.0.1", "test"); err != nil {
+		t.Fatalf("expected other addresses to be unaffected, got %v", err)
+	}
+}
+
+func TestLoginFromSharedAddress_ThrottlesAccountsOnly(t *testing.T) {
+	svc := setupTestService(t)
+	if _, err := svc.Create("alice", "correct-horse"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	svc.guard.now = func() time.Time { return now }
+
+	for i := 0; i < loginFreeAttemptsPerIP; i++ {
+		svc.LoginFromSharedAddress(fmt.Sprintf("user%d", i), "wrong", "10.0.0.9", "test")
+	}
+	if _, err := svc.LoginFromSharedAddress("alice", "correct-horse", "10.0.0.9", "test"); err != nil {
+		t.Fatalf("expected the shared address not to be locked out, got %v", err)
+	}
+
+	for i := 0; i < loginFreeAttempts; i++ {
+		svc.LoginFromSharedAddress("alice", "wrong", "10.0.0.9", "test")
+	}
+	if _, err := svc.LoginFromSharedAddress("alice", "correct-horse", "10.0.0.9", "test"); !errors.Is(err, ErrLoginLockedOut) {
+		t.Fatalf("expected the username to be locked out, got %v", err)
+	}
+}
+
+func TestLogin_LockoutDoubles(t *testing.T) {
+	if got := loginLockoutFor(loginFreeAttempts); got != loginBaseLockout {
+		t.Fatalf("first lockout = %s, want %s", got, loginBaseLockout)
+	}
+	if got := loginLockoutFor(loginFreeAttempts + 2); got != 4*loginBaseLockout {
+		t.Fatalf("third lockout = %s, want %s", got, 4*loginBaseLockout)
+	}
+	if got := loginLockoutFor(100); got != loginMaxLockout {
+		t.Fatalf("lockout should be capped at %s, got %s", loginMaxLockout, got)
+	}
+}
+
+func TestLogin_AuditTrail(t *testing.T) {
+	svc := setupTestService(t)
+	if _, err := svc.Create("bob", "secret"); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	expired := time.Now().Add(-time.Hour)
+	if _, err := svc.CreateWithExpiry("carol", "secret", &expired); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	svc.Login("bob", "nope", "10.0.0.1", "agent-1")
+	svc.Login("carol", "secret", "10.0.0.2", "agent-2")
+	svc.Login("bob", "secret", "10.0.0.1", "agent-1")
+
+	attempts := svc.LoginAttempts(0)
+	if len(attempts) != 2 {
+		t.Fatalf("expected 2 failed attempts, got %+v", attempts)
+	}
+	if attempts[0].Username != "carol" || attempts[0].Reason != LoginFailureExpired {
+		t.Fatalf("expected newest attempt to be carol/expired, got %+v", attempts[0])
+	}
+	if attempts[1].Username != "bob" || attempts[1].Reason != LoginFailureInvalidCredentials || attempts[1].UserAgent != "agent-1" {
+		t.Fatalf("unexpected oldest attempt %+v", attempts[1])
+	}
+	if got := svc.LoginAttempts(1); len(got) != 1 || got[0].Username != "carol" {
+		t.Fatalf("limit not applied: %+v", got)
+	}
+
+	// Expired-account failures do not count toward a lockout.
+	for i := 0; i < loginFreeAttempts; i++ {
+		svc.Login("carol", "secret", "10.0.0.3", "")
+	}
+	if lockouts := svc.LoginLockouts(); len(lockouts) != 0 {
+		t.Fatalf("expected no lockouts, got %+v", lockouts)
+	}
+}
+
+func TestLogin_AuditTrailIsBounded(t *testing.T) {
+	g := newLoginGuard()
+	for i := 0; i < loginAuditCapacity+10; i++ {
+		g.recordLocked(LoginAttempt{Username: "u", IP: string(rune('a' + i%26)), Reason: LoginFailureExpired})
+	}
+	got := g.attempts(0)
+	if len(got) != loginAuditCapacity {
+		t.Fatalf("expected %d attempts, got %d", loginAuditCapacity, len(got))
+	}
+	if want := string(rune('a' + (loginAuditCapacity+9)%26)); got[0].IP != want {
+		t.Fatalf("newest attempt IP = %q, want %q", got[0].IP, want)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -29,6 +30,7 @@ var (
 	ErrCannotDeleteMaster   = errors.New("cannot delete the master account")
 	ErrCannotDeleteLastAcct = errors.New("cannot delete the last account")
 	ErrAccountExpired       = errors.New("account has expired")
+	ErrUnknownFeature       = errors.New("unknown feature")
 )
 
 const (
@@ -43,6 +45,7 @@ type Service struct {
 	path     string
 	store    *datastore.DataStore
 	accounts map[string]models.Account
+	guard    *loginGuard
 }
 
 // useDB returns true when the service is backed by PostgreSQL.
@@ -53,6 +56,7 @@ func NewServiceWithStore(store *datastore.DataStore) (*Service, error) {
 	svc := &Service{
 		store:    store,
 		accounts: make(map[string]models.Account),
+		guard:    newLoginGuard(),
 	}
 	if err := svc.load(); err != nil {
 		return nil, err
@@ -76,6 +80,7 @@ func NewService(storageDir string) (*Service, error) {
 	svc := &Service{
 		path:     filepath.Join(storageDir, "accounts.json"),
 		accounts: make(map[string]models.Account),
+		guard:    newLoginGuard(),
 	}
 
 	if err := svc.load(); err != nil {
@@ -372,6 +377,47 @@ func (s *Service) SetMaxStreams(id string, maxStreams int) error {
 	return s.saveLocked()
 }
 
+// SetDeniedFeatures replaces the features turned off for an account. Every
+// entry must be one of models.AccountFeatures.
+func (s *Service) SetDeniedFeatures(id string, features []string) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return ErrAccountNotFound
+	}
+
+	denied := make([]string, 0, len(features))
+	for _, feature := range features {
+		feature = strings.TrimSpace(feature)
+		if !models.IsAccountFeature(feature) {
+			return fmt.Errorf("%w: %q", ErrUnknownFeature, feature)
+		}
+		if !slices.Contains(denied, feature) {
+			denied = append(denied, feature)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	account, ok := s.accounts[id]
+	if !ok {
+		return ErrAccountNotFound
+	}
+
+	account.DeniedFeatures = denied
+	account.UpdatedAt = time.Now().UTC()
+	s.accounts[id] = account
+
+	return s.saveLocked()
+}
+
+// HasFeature reports whether the account may use feature. Unknown accounts
+// have no features.
+func (s *Service) HasFeature(id, feature string) bool {
+	account, ok := s.Get(id)
+	return ok && account.HasFeature(feature)
+}
+
 // Delete removes an account by ID. The master account cannot be deleted.
 func (s *Service) Delete(id string) error {
 	id = strings.TrimSpace(id)
@@ -1,6 +1,7 @@
 package accounts
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -818,3 +819,41 @@ func TestPersistence_FileCreated(t *testing.T) {
 		t.Error("expected accounts.json to be created")
 	}
 }
+
+func TestSetDeniedFeatures(t *testing.T) {
+	svc := setupTestService(t)
+	account, err := svc.Create("kid", "secret")
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	if err := svc.SetDeniedFeatures(account.ID, []string{"teleport"}); !errors.Is(err, ErrUnknownFeature) {
+		t.Fatalf("expected ErrUnknownFeature, got %v", err)
+	}
+	if err := svc.SetDeniedFeatures("missing", nil); !errors.Is(err, ErrAccountNotFound) {
+		t.Fatalf("expected ErrAccountNotFound, got %v", err)
+	}
+
+	if !svc.HasFeature(account.ID, models.FeatureCacheRefresh) {
+		t.Fatal("expected features to be allowed by default")
+	}
+	if err := svc.SetDeniedFeatures(account.ID, []string{models.FeatureCacheRefresh, models.FeatureCacheRefresh}); err != nil {
+		t.Fatalf("set denied features: %v", err)
+	}
+	if svc.HasFeature(account.ID, models.FeatureCacheRefresh) {
+		t.Fatal("expected cache refresh to be denied")
+	}
+	if !svc.HasFeature(account.ID, models.FeatureAIRecommendations) {
+		t.Fatal("expected AI recommendations to stay allowed")
+	}
+	if got, _ := svc.Get(account.ID); len(got.DeniedFeatures) != 1 {
+		t.Fatalf("expected duplicates to be dropped, got %v", got.DeniedFeatures)
+	}
+
+	if err := svc.SetDeniedFeatures(account.ID, nil); err != nil {
+		t.Fatalf("clear denied features: %v", err)
+	}
+	if !svc.HasFeature(account.ID, models.FeatureCacheRefresh) {
+		t.Fatal("expected cache refresh to be allowed again")
+	}
+}
@@ -56,6 +56,7 @@ type Service struct {
 	cacheDir      string
 	configManager *config.Manager
 	store         *datastore.DataStore
+	spaceCheck    func() error
 }
 
 // Files to backup (relative to cacheDir).
@@ -84,6 +85,12 @@ func (s *Service) SetDataStore(store *datastore.DataStore) {
 	s.store = store
 }
 
+// SetDiskSpaceCheck sets a function that is consulted before writing a
+// backup; an error from it skips the backup. Pre-restore backups always run.
+func (s *Service) SetDiskSpaceCheck(fn func() error) {
+	s.spaceCheck = fn
+}
+
 // NewService creates a new backup service
 func NewService(cacheDir string, configManager *config.Manager) (*Service, error) {
 	backupDir := filepath.Join(cacheDir, "backups")
@@ -100,6 +107,12 @@ func NewService(cacheDir string, configManager *config.Manager) (*Service, error
 
 // CreateBackup creates a new backup archive
 func (s *Service) CreateBackup(backupType BackupType) (*BackupInfo, error) {
+	if s.spaceCheck != nil && backupType != BackupTypePreRestore {
+		if err := s.spaceCheck(); err != nil {
+			return nil, fmt.Errorf("skip backup: %w", err)
+		}
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
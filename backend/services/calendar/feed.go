@@ -0,0 +1,221 @@
+package calendar
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"novastream/models"
+)
+
+// ReleaseFeedDays is how far back the release feed looks. Feed readers poll
+// infrequently, so items stay listed for a while after their release.
+const ReleaseFeedDays = 14
+
+// releaseFeedSources are the followed sources: the watchlist and custom lists,
+// not trending suggestions or shows merely in progress.
+var releaseFeedSources = map[string]bool{"watchlist": true, "mdblist": true}
+
+// homeReleaseTypes are the movie release types that make a title watchable
+// at home.
+var homeReleaseTypes = map[string]bool{"digital": true, "physical": true, "tv": true}
+
+// NewReleases picks the items from followed sources that became available
+// between from and to: movies with a home release and series or season
+// premieres. Results are newest first.
+func NewReleases(items []models.CalendarItem, from, to time.Time) []models.CalendarItem {
+	var result []models.CalendarItem
+	for _, item := range items {
+		if !releaseFeedSources[item.Source] {
+			continue
+		}
+		switch item.MediaType {
+		case "movie":
+			if !homeReleaseTypes[item.ReleaseType] {
+				continue
+			}
+		case "series":
+			if item.EpisodeNumber != 1 || item.SeasonNumber <= 0 {
+				continue
+			}
+		default:
+			continue
+		}
+		at := releaseFeedTime(item)
+		if at.IsZero() || at.Before(from) || at.After(to) {
+			continue
+		}
+		result = append(result, item)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return releaseFeedTime(result[i]).After(releaseFeedTime(result[j]))
+	})
+	return result
+}
+
+// releaseFeedTime is when an item became available. Unlike ParseAirDateTime,
+// date-only releases count from the start of their day so a release shows up
+// in the feed on the day it happens.
+func releaseFeedTime(item models.CalendarItem) time.Time {
+	if item.AirTime != "" && item.AirTimezone != "" {
+		return ParseAirDateTime(item.AirDate, item.AirTime, item.AirTimezone)
+	}
+	date, err := time.Parse("2006-01-02", strings.TrimSpace(item.AirDate))
+	if err != nil {
+		return time.Time{}
+	}
+	return date
+}
+
+// releaseFeedTitle describes what was released, e.g. "Dune: Part Two is out
+// on digital" or "Severance season 2 premiered".
+func releaseFeedTitle(item models.CalendarItem) string {
+	if item.MediaType == "series" {
+		if item.SeasonNumber == 1 {
+			return item.Title + " premiered"
+		}
+		return item.Title + " season " + strconv.Itoa(item.SeasonNumber) + " premiered"
+	}
+	switch item.ReleaseType {
+	case "physical":
+		return item.Title + " is out on disc"
+	case "tv":
+		return item.Title + " aired on TV"
+	default:
+		return item.Title + " is out on digital"
+	}
+}
+
+// releaseFeedLink points at the title's IMDb page when known.
+func releaseFeedLink(item models.CalendarItem) string {
+	if imdb := item.ExternalIDs["imdb"]; imdb != "" {
+		return "https://www.imdb.com/title/" + imdb + "/"
+	}
+	return ""
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link,omitempty"`
+	Description string        `xml:"description,omitempty"`
+	GUID        rssGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Categories  []string      `xml:"category,omitempty"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length int    `xml:"length,attr"`
+}
+
+// RSS renders release feed items as an RSS 2.0 document. link is the URL
+// of the feed itself.
+func RSS(items []models.CalendarItem, title, link string, stamp time.Time) ([]byte, error) {
+	channel := rssChannel{
+		Title:         title,
+		Link:          link,
+		Description:   "New releases from your watchlist and lists",
+		LastBuildDate: stamp.UTC().Format(time.RFC1123Z),
+		Items:         make([]rssItem, 0, len(items)),
+	}
+	for _, item := range items {
+		entry := rssItem{
+			Title:       releaseFeedTitle(item),
+			Link:        releaseFeedLink(item),
+			Description: item.Overview,
+			GUID:        rssGUID{Value: icsUID(item)},
+			PubDate:     releaseFeedTime(item).UTC().Format(time.RFC1123Z),
+			Categories:  []string{item.MediaType},
+		}
+		if item.PosterURL != "" {
+			entry.Enclosure = &rssEnclosure{URL: item.PosterURL, Type: posterMIMEType(item.PosterURL)}
+		}
+		channel.Items = append(channel.Items, entry)
+	}
+	out, err := xml.MarshalIndent(rssFeed{Version: "2.0", Channel: channel}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+func posterMIMEType(url string) string {
+	lower := strings.ToLower(url)
+	switch {
+	case strings.HasSuffix(lower, ".png"):
+		return "image/png"
+	case strings.HasSuffix(lower, ".webp"):
+		return "image/webp"
+	default:
+		return "image/jpeg"
+	}
+}
+
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	FeedURL     string         `json:"feed_url,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string              `json:"id"`
+	URL           string              `json:"url,omitempty"`
+	Title         string              `json:"title"`
+	ContentText   string              `json:"content_text"`
+	Image         string              `json:"image,omitempty"`
+	DatePublished string              `json:"date_published"`
+	Tags          []string            `json:"tags,omitempty"`
+	Release       models.CalendarItem `json:"_mediastorm"`
+}
+
+// JSONFeed renders release feed items as a JSON Feed 1.1 document. Each item
+// carries the full calendar item under "_mediastorm" so automation can match
+// on external IDs.
+func JSONFeed(items []models.CalendarItem, title, feedURL string) ([]byte, error) {
+	feed := jsonFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       title,
+		FeedURL:     feedURL,
+		Description: "New releases from your watchlist and lists",
+		Items:       make([]jsonFeedItem, 0, len(items)),
+	}
+	for _, item := range items {
+		feed.Items = append(feed.Items, jsonFeedItem{
+			ID:            icsUID(item),
+			URL:           releaseFeedLink(item),
+			Title:         releaseFeedTitle(item),
+			ContentText:   item.Overview,
+			Image:         item.PosterURL,
+			DatePublished: releaseFeedTime(item).UTC().Format(time.RFC3339),
+			Tags:          []string{item.MediaType, item.Source},
+			Release:       item,
+		})
+	}
+	return json.MarshalIndent(feed, "", "  ")
+}
@@ -0,0 +1,78 @@
+package calendar
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"novastream/models"
+)
+
+func TestNewReleasesPicksHomeReleasesAndPremieres(t *testing.T) {
+	now := time.Date(2026, 5, 20, 12, 0, 0, 0, time.UTC)
+	items := []models.CalendarItem{
+		{Title: "Digital", MediaType: "movie", ReleaseType: "digital", AirDate: "2026-05-18", Source: "watchlist"},
+		{Title: "Theatrical", MediaType: "movie", ReleaseType: "theatrical", AirDate: "2026-05-18", Source: "watchlist"},
+		{Title: "Premiere", MediaType: "series", SeasonNumber: 2, EpisodeNumber: 1, AirDate: "2026-05-19", Source: "mdblist"},
+		{Title: "Midseason", MediaType: "series", SeasonNumber: 2, EpisodeNumber: 4, AirDate: "2026-05-19", Source: "watchlist"},
+		{Title: "Trending", MediaType: "movie", ReleaseType: "digital", AirDate: "2026-05-18", Source: "trending"},
+		{Title: "Old", MediaType: "movie", ReleaseType: "physical", AirDate: "2026-04-01", Source: "watchlist"},
+		{Title: "Upcoming", MediaType: "movie", ReleaseType: "digital", AirDate: "2026-05-25", Source: "watchlist"},
+	}
+
+	got := NewReleases(items, now.AddDate(0, 0, -ReleaseFeedDays), now)
+	if len(got) != 2 || got[0].Title != "Premiere" || got[1].Title != "Digital" {
+		t.Fatalf("unexpected releases: %+v", got)
+	}
+	if title := releaseFeedTitle(got[0]); title != "Premiere season 2 premiered" {
+		t.Errorf("title = %q", title)
+	}
+}
+
+func TestReleaseFeedRendering(t *testing.T) {
+	items := []models.CalendarItem{{
+		Title:       "Dune & Friends",
+		MediaType:   "movie",
+		ReleaseType: "physical",
+		AirDate:     "2026-05-18",
+		PosterURL:   "https://image.tmdb.org/p/poster.jpg",
+		ExternalIDs: map[string]string{"imdb": "tt15239678"},
+		Source:      "watchlist",
+	}}
+	stamp := time.Date(2026, 5, 20, 0, 0, 0, 0, time.UTC)
+
+	rss, err := RSS(items, "feed", "https://example.test/releases.rss", stamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed rssFeed
+	if err := xml.Unmarshal(rss, &parsed); err != nil {
+		t.Fatalf("invalid RSS: %v\n%s", err, rss)
+	}
+	if len(parsed.Channel.Items) != 1 {
+		t.Fatalf("expected 1 RSS item, got %d", len(parsed.Channel.Items))
+	}
+	entry := parsed.Channel.Items[0]
+	if entry.Title != "Dune & Friends is out on disc" || entry.Link != "https://www.imdb.com/title/tt15239678/" {
+		t.Errorf("unexpected RSS item: %+v", entry)
+	}
+	if entry.GUID.Value != icsUID(items[0]) {
+		t.Errorf("RSS guid = %q, want %q", entry.GUID.Value, icsUID(items[0]))
+	}
+
+	data, err := JSONFeed(items, "feed", "https://example.test/releases.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var feed jsonFeed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		t.Fatal(err)
+	}
+	if feed.Version != "https://jsonfeed.org/version/1.1" || len(feed.Items) != 1 {
+		t.Fatalf("unexpected JSON feed: %s", data)
+	}
+	if feed.Items[0].DatePublished != "2026-05-18T00:00:00Z" || feed.Items[0].Release.ExternalIDs["imdb"] != "tt15239678" {
+		t.Errorf("unexpected JSON feed item: %+v", feed.Items[0])
+	}
+}
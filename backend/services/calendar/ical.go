@@ -0,0 +1,167 @@
+package calendar
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"novastream/models"
+)
+
+// defaultEventMinutes is the length of timed events whose runtime is unknown.
+const defaultEventMinutes = 30
+
+// icsLineLimit is the RFC 5545 maximum line length in octets, excluding CRLF.
+const icsLineLimit = 75
+
+// ICS renders calendar items as an iCalendar (RFC 5545) feed. Episodes with
+// a known air time and timezone become timed events; everything else,
+// including movie releases, becomes an all-day event on its date.
+func ICS(items []models.CalendarItem, name string, stamp time.Time) []byte {
+	sorted := append([]models.CalendarItem(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].AirDate != sorted[j].AirDate {
+			return sorted[i].AirDate < sorted[j].AirDate
+		}
+		return sorted[i].AirTime < sorted[j].AirTime
+	})
+
+	var b strings.Builder
+	line := func(s string) { writeICSLine(&b, s) }
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//mediastorm//calendar//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	if name != "" {
+		line("X-WR-CALNAME:" + escapeICSText(name))
+	}
+	dtstamp := stamp.UTC().Format("20060102T150405Z")
+	for _, item := range sorted {
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(item.AirDate))
+		if err != nil {
+			continue
+		}
+		line("BEGIN:VEVENT")
+		line("UID:" + icsUID(item))
+		line("DTSTAMP:" + dtstamp)
+		if start, ok := icsStart(item, date); ok {
+			minutes := item.RuntimeMinutes
+			if minutes <= 0 {
+				minutes = defaultEventMinutes
+			}
+			line("DTSTART:" + start.Format("20060102T150405Z"))
+			line("DTEND:" + start.Add(time.Duration(minutes)*time.Minute).Format("20060102T150405Z"))
+		} else {
+			line("DTSTART;VALUE=DATE:" + date.Format("20060102"))
+			line("DTEND;VALUE=DATE:" + date.AddDate(0, 0, 1).Format("20060102"))
+			line("TRANSP:TRANSPARENT")
+		}
+		line("SUMMARY:" + escapeICSText(icsSummary(item)))
+		if desc := icsDescription(item); desc != "" {
+			line("DESCRIPTION:" + escapeICSText(desc))
+		}
+		if item.Network != "" {
+			line("LOCATION:" + escapeICSText(item.Network))
+		}
+		line("CATEGORIES:" + escapeICSText(item.MediaType))
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return []byte(b.String())
+}
+
+// icsStart returns the UTC start of an episode with a usable air time.
+func icsStart(item models.CalendarItem, date time.Time) (time.Time, bool) {
+	if item.MediaType != "series" || item.AirTime == "" || item.AirTimezone == "" {
+		return time.Time{}, false
+	}
+	loc, err := time.LoadLocation(item.AirTimezone)
+	if err != nil {
+		return time.Time{}, false
+	}
+	hh, mm, ok := strings.Cut(item.AirTime, ":")
+	if !ok {
+		return time.Time{}, false
+	}
+	hour, errH := strconv.Atoi(hh)
+	minute, errM := strconv.Atoi(mm)
+	if errH != nil || errM != nil {
+		return time.Time{}, false
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(), hour, minute, 0, 0, loc).UTC(), true
+}
+
+func icsSummary(item models.CalendarItem) string {
+	if item.MediaType == "series" {
+		summary := fmt.Sprintf("%s S%02dE%02d", item.Title, item.SeasonNumber, item.EpisodeNumber)
+		if item.EpisodeTitle != "" {
+			summary += " - " + item.EpisodeTitle
+		}
+		return summary
+	}
+	if item.ReleaseType != "" {
+		return fmt.Sprintf("%s (%s release)", item.Title, item.ReleaseType)
+	}
+	return item.Title
+}
+
+func icsDescription(item models.CalendarItem) string {
+	desc := item.EpisodeOverview
+	if desc == "" {
+		desc = item.Overview
+	}
+	if imdb := item.ExternalIDs["imdb"]; imdb != "" {
+		if desc != "" {
+			desc += "\n\n"
+		}
+		desc += "https://www.imdb.com/title/" + imdb + "/"
+	}
+	return desc
+}
+
+// icsUID identifies an event stably across feed refreshes so calendar apps
+// update it in place when the air date moves.
+func icsUID(item models.CalendarItem) string {
+	id := item.ExternalIDs["tvdb"]
+	if id == "" {
+		id = item.ExternalIDs["tmdb"]
+	}
+	if id == "" {
+		id = item.ExternalIDs["imdb"]
+	}
+	if id == "" {
+		id = fmt.Sprintf("%s|%d", strings.ToLower(item.Title), item.Year)
+	}
+	key := fmt.Sprintf("%s|%s|%d|%d|%s", item.MediaType, id, item.SeasonNumber, item.EpisodeNumber, item.ReleaseType)
+	sum := sha1.Sum([]byte(key))
+	return hex.EncodeToString(sum[:10]) + "@mediastorm"
+}
+
+// escapeICSText escapes a TEXT value per RFC 5545 section 3.3.11.
+func escapeICSText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", "").Replace(s)
+}
+
+// writeICSLine writes a content line, folding it at icsLineLimit octets
+// without splitting a UTF-8 sequence.
+func writeICSLine(b *strings.Builder, s string) {
+	limit := icsLineLimit
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && cut < len(s) && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		limit = icsLineLimit - 1 // continuation lines start with a space
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,43 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"novastream/models"
+)
+
+func TestICSAllDayMovieReleaseAndEscaping(t *testing.T) {
+	items := []models.CalendarItem{{
+		Title:       "Dune; Part Two, Again",
+		MediaType:   "movie",
+		ReleaseType: "digital",
+		AirDate:     "2026-04-02",
+		Overview:    strings.Repeat("A long overview line. ", 8),
+		ExternalIDs: map[string]string{"tmdb": "693134"},
+	}}
+	out := string(ICS(items, "mediastorm", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)))
+
+	for _, want := range []string{
+		"DTSTART;VALUE=DATE:20260402\r\n",
+		"DTEND;VALUE=DATE:20260403\r\n",
+		`SUMMARY:Dune\; Part Two\, Again (digital release)` + "\r\n",
+		"DTSTAMP:20260301T000000Z\r\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("feed missing %q:\n%s", want, out)
+		}
+	}
+	for _, line := range strings.Split(out, "\r\n") {
+		if len(line) > icsLineLimit {
+			t.Errorf("line longer than %d octets: %q", icsLineLimit, line)
+		}
+	}
+
+	moved := items[0]
+	moved.AirDate = "2026-04-09"
+	if icsUID(items[0]) != icsUID(moved) {
+		t.Error("UID should not change when the release date moves")
+	}
+}
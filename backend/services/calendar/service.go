@@ -303,6 +303,24 @@ func (s *Service) Get(userID string) *userCalendar {
 	return s.buildAndCacheUserCalendar(userID, false)
 }
 
+// ItemsBetween returns the user's calendar items airing or released in
+// [from, to), building the calendar if it isn't cached yet.
+func (s *Service) ItemsBetween(userID string, from, to time.Time) []models.CalendarItem {
+	cal := s.Get(userID)
+	if cal == nil {
+		return nil
+	}
+	var items []models.CalendarItem
+	for _, item := range cal.Items {
+		at := ParseAirDateTime(item.AirDate, item.AirTime, item.AirTimezone)
+		if at.IsZero() || at.Before(from) || !at.Before(to) {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
 // Invalidate clears cached calendar data for a single user. The next request
 // will rebuild from current watchlist/history/settings data.
 func (s *Service) Invalidate(userID string) {
@@ -638,6 +656,15 @@ func (s *Service) calendarSourcesEnabled(userID string) models.CalendarSettings
 	return cal
 }
 
+// ReleaseFeedEnabled reports whether the user opted in to the new-release feed.
+func (s *Service) ReleaseFeedEnabled(userID string) bool {
+	settings, err := s.userSettings.Get(userID)
+	if err != nil || settings == nil {
+		return false
+	}
+	return settings.Calendar.ReleaseFeed != nil && *settings.Calendar.ReleaseFeed
+}
+
 // buildUserCalendar collects upcoming content from all enabled sources for a single user.
 func (s *Service) buildUserCalendar(userID string) []models.CalendarItem {
 	ctx := context.Background()
@@ -0,0 +1,260 @@
+package epg
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"novastream/models"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 200
+
+	// titleMatchFlag marks postings whose term appears in the program title.
+	titleMatchFlag = uint32(1) << 31
+)
+
+// SearchOptions narrows an EPG search.
+type SearchOptions struct {
+	// From and To bound the programs returned to those airing within the
+	// window. A zero From means "now"; a zero To leaves the window open.
+	From       time.Time
+	To         time.Time
+	ChannelIDs []string // restrict to these EPG channel IDs (case-insensitive)
+	Limit      int
+}
+
+// searchIndex is an inverted index over program titles and descriptions,
+// rebuilt whenever a new schedule is installed.
+type searchIndex struct {
+	programs []models.EPGProgram
+	channels []string            // EPG channel ID of programs[i]
+	postings map[string][]uint32 // term -> program indexes, titleMatchFlag set for title hits
+	terms    []string            // sorted keys of postings, for prefix lookups
+}
+
+// tokenize splits text into lowercase letter/digit terms, dropping single
+// letters that would match nearly every program.
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	out := fields[:0]
+	for _, f := range fields {
+		if len([]rune(f)) == 1 && !unicode.IsDigit([]rune(f)[0]) {
+			continue
+		}
+		out = append(out, f)
+	}
+	return out
+}
+
+func buildSearchIndex(schedule *models.EPGSchedule) *searchIndex {
+	idx := &searchIndex{postings: make(map[string][]uint32)}
+	if schedule == nil {
+		return idx
+	}
+
+	channelIDs := make([]string, 0, len(schedule.Programs))
+	for channelID := range schedule.Programs {
+		channelIDs = append(channelIDs, channelID)
+	}
+	sort.Strings(channelIDs)
+
+	for _, channelID := range channelIDs {
+		for _, prog := range schedule.Programs[channelID] {
+			if len(idx.programs) >= int(titleMatchFlag) {
+				break
+			}
+			i := uint32(len(idx.programs))
+			idx.programs = append(idx.programs, prog)
+			idx.channels = append(idx.channels, channelID)
+
+			seen := make(map[string]bool)
+			for _, term := range tokenize(prog.Title) {
+				if !seen[term] {
+					seen[term] = true
+					idx.postings[term] = append(idx.postings[term], i|titleMatchFlag)
+				}
+			}
+			for _, term := range tokenize(prog.Description) {
+				if !seen[term] {
+					seen[term] = true
+					idx.postings[term] = append(idx.postings[term], i)
+				}
+			}
+		}
+	}
+
+	idx.terms = make([]string, 0, len(idx.postings))
+	for term := range idx.postings {
+		idx.terms = append(idx.terms, term)
+	}
+	sort.Strings(idx.terms)
+	return idx
+}
+
+// match returns the programs containing term, scored 3 for a title hit and 1
+// for a description hit. Terms of two or more characters also match as a
+// prefix, so partially typed words still find results.
+func (idx *searchIndex) match(term string) map[uint32]int {
+	scores := make(map[uint32]int)
+	add := func(postings []uint32) {
+		for _, p := range postings {
+			score := 1
+			if p&titleMatchFlag != 0 {
+				score = 3
+			}
+			i := p &^ titleMatchFlag
+			if score > scores[i] {
+				scores[i] = score
+			}
+		}
+	}
+	if len([]rune(term)) < 2 {
+		add(idx.postings[term])
+		return scores
+	}
+	for i := sort.SearchStrings(idx.terms, term); i < len(idx.terms) && strings.HasPrefix(idx.terms[i], term); i++ {
+		add(idx.postings[idx.terms[i]])
+	}
+	return scores
+}
+
+func (idx *searchIndex) search(query string, opts SearchOptions, now time.Time) []models.EPGSearchResult {
+	terms := tokenize(query)
+	if idx == nil || len(terms) == 0 {
+		return []models.EPGSearchResult{}
+	}
+
+	// Every term must match; start from the first term's programs.
+	scores := idx.match(terms[0])
+	for _, term := range terms[1:] {
+		if len(scores) == 0 {
+			break
+		}
+		termScores := idx.match(term)
+		for i, score := range scores {
+			if termScore, ok := termScores[i]; ok {
+				scores[i] = score + termScore
+			} else {
+				delete(scores, i)
+			}
+		}
+	}
+
+	from := opts.From
+	if from.IsZero() {
+		from = now
+	}
+	var channelFilter map[string]bool
+	if len(opts.ChannelIDs) > 0 {
+		channelFilter = make(map[string]bool, len(opts.ChannelIDs))
+		for _, id := range opts.ChannelIDs {
+			channelFilter[strings.ToLower(strings.TrimSpace(id))] = true
+		}
+	}
+	phrase := strings.Join(terms, " ")
+
+	results := make([]models.EPGSearchResult, 0, len(scores))
+	for i, score := range scores {
+		prog := idx.programs[i]
+		if !prog.Stop.After(from) || (!opts.To.IsZero() && !prog.Start.Before(opts.To)) {
+			continue
+		}
+		if channelFilter != nil && !channelFilter[idx.channels[i]] {
+			continue
+		}
+		if strings.HasPrefix(strings.Join(tokenize(prog.Title), " "), phrase) {
+			score += 2
+		}
+		results = append(results, models.EPGSearchResult{Program: prog, Score: score})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		if !results[i].Program.Start.Equal(results[j].Program.Start) {
+			return results[i].Program.Start.Before(results[j].Program.Start)
+		}
+		return results[i].Program.ChannelID < results[j].Program.ChannelID
+	})
+
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// Search finds programs whose title or description contains every word of
+// query, best matches first. now is the effective current time, already
+// adjusted for any configured EPG offset.
+func (s *Service) Search(query string, opts SearchOptions, now time.Time) []models.EPGSearchResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	results := s.index.search(query, opts, now)
+	for i := range results {
+		if ch, ok := s.schedule.Channels[strings.ToLower(results[i].Program.ChannelID)]; ok {
+			results[i].ChannelName = ch.Name
+		}
+	}
+	return results
+}
+
+// nowAndNext returns the program airing at now and the one after it from a
+// start-sorted program list.
+func nowAndNext(programs []models.EPGProgram, now time.Time) (current, next *models.EPGProgram) {
+	for i, prog := range programs {
+		if prog.Start.Before(now) && prog.Stop.After(now) {
+			current = &programs[i]
+			if i+1 < len(programs) {
+				next = &programs[i+1]
+			}
+			return current, next
+		}
+		if prog.Start.After(now) {
+			return nil, &programs[i]
+		}
+	}
+	return nil, nil
+}
+
+// GetNowNext returns what is on now and next for every channel with guide
+// data, ordered by channel name. now is the effective current time.
+func (s *Service) GetNowNext(now time.Time) []models.EPGNowPlaying {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make([]models.EPGNowPlaying, 0, len(s.schedule.Programs))
+	for channelID, programs := range s.schedule.Programs {
+		current, next := nowAndNext(programs, now)
+		if current == nil && next == nil {
+			continue
+		}
+		np := models.EPGNowPlaying{ChannelID: channelID, Current: current, Next: next}
+		if ch, ok := s.schedule.Channels[channelID]; ok {
+			np.ChannelName = ch.Name
+		}
+		result = append(result, np)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		ni, nj := strings.ToLower(result[i].ChannelName), strings.ToLower(result[j].ChannelName)
+		if ni != nj {
+			return ni < nj
+		}
+		return result[i].ChannelID < result[j].ChannelID
+	})
+	return result
+}
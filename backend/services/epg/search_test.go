@@ -0,0 +1,80 @@
+package epg
+
+import (
+	"testing"
+	"time"
+
+	"novastream/models"
+)
+
+func newSearchTestService(now time.Time) *Service {
+	at := func(minutes int) time.Time { return now.Add(time.Duration(minutes) * time.Minute) }
+	schedule := &models.EPGSchedule{
+		Channels: map[string]models.EPGChannel{
+			"news.us":   {ID: "news.us", Name: "News Channel"},
+			"sports.us": {ID: "sports.us", Name: "Sports Channel"},
+		},
+		Programs: map[string][]models.EPGProgram{
+			"news.us": {
+				{ChannelID: "news.us", Title: "Morning News", Start: at(-120), Stop: at(-60)},
+				{ChannelID: "news.us", Title: "World News Tonight", Description: "Headlines from around the world", Start: at(-30), Stop: at(30)},
+				{ChannelID: "news.us", Title: "Cooking Hour", Description: "Recipes and kitchen news", Start: at(30), Stop: at(90)},
+			},
+			"sports.us": {
+				{ChannelID: "sports.us", Title: "Football Live", Description: "Premier League football", Start: at(15), Stop: at(135)},
+			},
+		},
+	}
+	return &Service{schedule: schedule, index: buildSearchIndex(schedule)}
+}
+
+func TestSearchRanksTitleMatchesAndSkipsEndedPrograms(t *testing.T) {
+	now := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	s := newSearchTestService(now)
+
+	results := s.Search("news", SearchOptions{}, now)
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d: %+v", len(results), results)
+	}
+	if results[0].Program.Title != "World News Tonight" || results[0].ChannelName != "News Channel" {
+		t.Fatalf("expected title match first, got %+v", results[0])
+	}
+	if results[1].Program.Title != "Cooking Hour" {
+		t.Fatalf("expected description match second, got %+v", results[1])
+	}
+}
+
+func TestSearchMatchesAllTermsByPrefix(t *testing.T) {
+	now := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	s := newSearchTestService(now)
+
+	if results := s.Search("foot prem", SearchOptions{}, now); len(results) != 1 || results[0].Program.Title != "Football Live" {
+		t.Fatalf("expected prefix match on all terms, got %+v", results)
+	}
+	if results := s.Search("football cooking", SearchOptions{}, now); len(results) != 0 {
+		t.Fatalf("expected no results when a term is missing, got %+v", results)
+	}
+	if results := s.Search("news", SearchOptions{To: now.Add(20 * time.Minute)}, now); len(results) != 1 {
+		t.Fatalf("expected window to exclude later programs, got %+v", results)
+	}
+	if results := s.Search("live", SearchOptions{ChannelIDs: []string{"NEWS.US"}}, now); len(results) != 0 {
+		t.Fatalf("expected channel filter to exclude sports, got %+v", results)
+	}
+}
+
+func TestGetNowNextCoversAllChannels(t *testing.T) {
+	now := time.Date(2026, 3, 1, 20, 0, 0, 0, time.UTC)
+	s := newSearchTestService(now)
+
+	result := s.GetNowNext(now)
+	if len(result) != 2 {
+		t.Fatalf("expected 2 channels, got %d", len(result))
+	}
+	news, sports := result[0], result[1]
+	if news.ChannelName != "News Channel" || news.Current == nil || news.Current.Title != "World News Tonight" || news.Next == nil || news.Next.Title != "Cooking Hour" {
+		t.Fatalf("unexpected news now/next: %+v", news)
+	}
+	if sports.Current != nil || sports.Next == nil || sports.Next.Title != "Football Live" {
+		t.Fatalf("unexpected sports now/next: %+v", sports)
+	}
+}
@@ -39,8 +39,10 @@ type Service struct {
 
 	mu         sync.RWMutex
 	schedule   *models.EPGSchedule
+	index      *searchIndex // search index over schedule
 	refreshing bool
 	lastError  string
+	onRefresh  []func()
 }
 
 type epgXMLTVSource struct {
@@ -420,8 +422,10 @@ func (s *Service) Refresh(ctx context.Context) error {
 	}
 
 	// Update the schedule
+	index := buildSearchIndex(newSchedule)
 	s.mu.Lock()
 	s.schedule = newSchedule
+	s.index = index
 	s.mu.Unlock()
 
 	// Save to disk
@@ -432,9 +436,32 @@ func (s *Service) Refresh(ctx context.Context) error {
 	log.Printf("[epg] refresh complete: %d channels, %d programs",
 		len(newSchedule.Channels), s.countPrograms())
 
+	s.mu.RLock()
+	listeners := s.onRefresh
+	s.mu.RUnlock()
+	for _, fn := range listeners {
+		go fn()
+	}
+
 	return nil
 }
 
+// OnRefresh registers fn to run in its own goroutine after each successful
+// refresh installs a new schedule.
+func (s *Service) OnRefresh(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onRefresh = append(s.onRefresh, fn)
+}
+
+// Schedule returns the installed schedule. A refresh replaces it rather than
+// modifying it, so callers may read it without locking but must not change it.
+func (s *Service) Schedule() *models.EPGSchedule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.schedule
+}
+
 // fetchXtreamEPG fetches EPG data from the Xtream Codes xmltv.php endpoint.
 func (s *Service) fetchXtreamEPG(ctx context.Context, settings *config.Settings, schedule *models.EPGSchedule) error {
 	host := strings.TrimRight(settings.Live.XtreamHost, "/")
@@ -557,6 +584,7 @@ type xmltvProgramme struct {
 	EpNum    []xmltvEpisode `xml:"episode-num"`
 	Icon     []xmltvIcon    `xml:"icon"`
 	Rating   []xmltvRating  `xml:"rating"`
+	Previous *struct{}      `xml:"previously-shown"`
 }
 
 type xmltvLang struct {
@@ -636,11 +664,12 @@ func (s *Service) parseXMLTV(reader io.Reader, schedule *models.EPGSchedule) err
 				// Normalize channel ID to lowercase to merge duplicates
 				normalizedChannelID := strings.ToLower(prog.Channel)
 				epgProgram := models.EPGProgram{
-					ChannelID:   normalizedChannelID,
-					Title:       getFirstLangValue(prog.Title),
-					Description: getFirstLangValue(prog.Desc),
-					Start:       start,
-					Stop:        stop,
+					ChannelID:       normalizedChannelID,
+					Title:           getFirstLangValue(prog.Title),
+					Description:     getFirstLangValue(prog.Desc),
+					Start:           start,
+					Stop:            stop,
+					PreviouslyShown: prog.Previous != nil,
 				}
 
 				// Parse categories
@@ -798,22 +827,7 @@ func (s *Service) GetNowPlaying(channelIDs []string, timeOffset ...time.Duration
 			programs = s.findProgramsByChannelMatch(channelID)
 		}
 
-		for i, prog := range programs {
-			// Check if this is the current program
-			if prog.Start.Before(now) && prog.Stop.After(now) {
-				np.Current = &programs[i]
-				// Get next program
-				if i+1 < len(programs) {
-					np.Next = &programs[i+1]
-				}
-				break
-			}
-			// Check if this is the next upcoming program
-			if prog.Start.After(now) && np.Current == nil {
-				np.Next = &programs[i]
-				break
-			}
-		}
+		np.Current, np.Next = nowAndNext(programs, now)
 
 		result = append(result, np)
 	}
@@ -1028,8 +1042,10 @@ func (s *Service) loadFromDisk() error {
 		return fmt.Errorf("unmarshal EPG data: %w", err)
 	}
 
+	index := buildSearchIndex(&schedule)
 	s.mu.Lock()
 	s.schedule = &schedule
+	s.index = index
 	s.mu.Unlock()
 
 	return nil
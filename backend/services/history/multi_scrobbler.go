@@ -8,7 +8,7 @@ import (
 )
 
 // MultiScrobbler fans out scrobble calls to multiple providers.
-// Implements TraktScrobbler.
+// Implements TraktScrobbler and BatchEpisodeScrobbler.
 type MultiScrobbler struct {
 	scrobblers []TraktScrobbler
 }
@@ -44,6 +44,39 @@ func (m *MultiScrobbler) ScrobbleEpisode(userID string, showTVDBID, season, epis
 	return firstErr
 }
 
+// ScrobbleEpisodes syncs a batch of episodes of one show. Providers that
+// support batches get a single call; the rest get one call per episode.
+func (m *MultiScrobbler) ScrobbleEpisodes(userID string, showTVDBID int, showExternalIDs map[string]string, episodes []models.EpisodeScrobble) error {
+	var firstErr error
+	record := func(err error) {
+		log.Printf("[multi-scrobbler] episode batch scrobble error: %v", err)
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	for _, s := range m.scrobblers {
+		if batcher, ok := s.(BatchEpisodeScrobbler); ok {
+			if err := batcher.ScrobbleEpisodes(userID, showTVDBID, showExternalIDs, episodes); err != nil {
+				record(err)
+			}
+			continue
+		}
+		for _, episode := range episodes {
+			externalIDs := make(map[string]string, len(showExternalIDs)+len(episode.ExternalIDs))
+			for key, value := range showExternalIDs {
+				externalIDs[key] = value
+			}
+			for key, value := range episode.ExternalIDs {
+				externalIDs[key] = value
+			}
+			if err := s.ScrobbleEpisode(userID, showTVDBID, episode.SeasonNumber, episode.EpisodeNumber, episode.WatchedAt, externalIDs); err != nil {
+				record(err)
+			}
+		}
+	}
+	return firstErr
+}
+
 func (m *MultiScrobbler) IsEnabled() bool {
 	for _, s := range m.scrobblers {
 		if s.IsEnabled() {
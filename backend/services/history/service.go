@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"os"
 	"path/filepath"
 	"sort"
@@ -95,6 +96,13 @@ type cachedContinueWatching struct {
 	expiresAt time.Time
 }
 
+// cachedContinueWatchingShelf is an enriched shelf built from one continue
+// watching cache entry. It is only valid while that entry is still current.
+type cachedContinueWatchingShelf struct {
+	source *cachedContinueWatching
+	items  []models.ContinueWatchingShelfItem
+}
+
 // Service persists watch history for all content (movies, series, episodes).
 type Service struct {
 	mu                   sync.RWMutex
@@ -120,8 +128,10 @@ type Service struct {
 	metadataCacheTTL       time.Duration
 	continueWatchingCache  map[string]*cachedContinueWatching // userID -> continue watching
 	continueWatchingTTL    time.Duration
+	shelfCache             map[string]*cachedContinueWatchingShelf // userID -> continue watching shelf
 	changeMu               sync.RWMutex
 	watchStateChanged      func(userID string)
+	watchTimeRecorder      func(userID string, seconds float64, at time.Time)
 }
 
 type continueWatchingRevisionStats struct {
@@ -148,6 +158,7 @@ func NewServiceWithStore(store *datastore.DataStore) (*Service, error) {
 		movieMetadataCache:     make(map[string]*cachedMovieMetadata),
 		metadataCacheTTL:       24 * time.Hour,
 		continueWatchingCache:  make(map[string]*cachedContinueWatching),
+		shelfCache:             make(map[string]*cachedContinueWatchingShelf),
 		continueWatchingTTL:    10 * time.Minute,
 	}
 
@@ -185,6 +196,7 @@ func NewService(storageDir string) (*Service, error) {
 		movieMetadataCache:     make(map[string]*cachedMovieMetadata),
 		metadataCacheTTL:       24 * time.Hour, // Cache metadata for 24 hours - ensures new episodes are detected daily
 		continueWatchingCache:  make(map[string]*cachedContinueWatching),
+		shelfCache:             make(map[string]*cachedContinueWatchingShelf),
 		continueWatchingTTL:    10 * time.Minute, // Cache continue watching response for 10 minutes - reduces frequent rebuilds
 	}
 
@@ -220,6 +232,7 @@ func (s *Service) SetWatchStateChangedHook(fn func(userID string)) {
 
 func (s *Service) invalidateContinueWatchingLocked(userID string) {
 	delete(s.continueWatchingCache, userID)
+	delete(s.shelfCache, userID)
 	s.notifyWatchStateChanged(userID)
 }
 
@@ -232,6 +245,23 @@ func (s *Service) notifyWatchStateChanged(userID string) {
 	}
 }
 
+// SetWatchTimeRecorder registers a callback fed the playback time each
+// progress heartbeat adds, for screen-time tracking.
+func (s *Service) SetWatchTimeRecorder(fn func(userID string, seconds float64, at time.Time)) {
+	s.changeMu.Lock()
+	defer s.changeMu.Unlock()
+	s.watchTimeRecorder = fn
+}
+
+func (s *Service) recordWatchTime(userID string, seconds float64, at time.Time) {
+	s.changeMu.RLock()
+	fn := s.watchTimeRecorder
+	s.changeMu.RUnlock()
+	if fn != nil {
+		fn(userID, seconds, at)
+	}
+}
+
 // SetTraktScrobbler sets the Trakt scrobbler for syncing watch history.
 func (s *Service) SetTraktScrobbler(scrobbler TraktScrobbler) {
 	s.mu.Lock()
@@ -524,6 +554,115 @@ func (s *Service) ListContinueWatching(userID string) ([]models.SeriesWatchState
 	return items, nil
 }
 
+// ContinueWatchingShelf returns the continue-watching shelf ready to render:
+// the same items and order as ListContinueWatching, each with the episode to
+// play, artwork and the runtime left. The shelf is cached per profile for as
+// long as the continue watching result it was built from.
+func (s *Service) ContinueWatchingShelf(userID string) ([]models.ContinueWatchingShelfItem, error) {
+	items, err := s.ListContinueWatching(userID)
+	if err != nil {
+		return nil, err
+	}
+	userID = strings.TrimSpace(userID)
+
+	s.mu.RLock()
+	source := s.continueWatchingCache[userID]
+	cached, exists := s.shelfCache[userID]
+	s.mu.RUnlock()
+
+	if exists && source != nil && cached.source == source {
+		return cloneShelfItems(cached.items), nil
+	}
+
+	progress, err := s.ListPlaybackProgress(userID)
+	if err != nil {
+		return nil, err
+	}
+	shelf := buildContinueWatchingShelf(items, progress)
+
+	if source != nil {
+		s.mu.Lock()
+		s.shelfCache[userID] = &cachedContinueWatchingShelf{source: source, items: shelf}
+		s.mu.Unlock()
+	}
+	return cloneShelfItems(shelf), nil
+}
+
+func buildContinueWatchingShelf(items []models.SeriesWatchState, progress []models.PlaybackProgress) []models.ContinueWatchingShelfItem {
+	shelf := make([]models.ContinueWatchingShelfItem, 0, len(items))
+	for _, state := range items {
+		item := models.ContinueWatchingShelfItem{SeriesWatchState: state, MediaType: "series"}
+		isMovie := state.NextEpisode == nil && len(state.WatchedEpisodes) == 0 && state.LastWatched.EpisodeNumber == 0
+		if isMovie {
+			item.MediaType = "movie"
+		} else if state.NextEpisode != nil {
+			episode := *state.NextEpisode
+			item.Episode = &episode
+		}
+
+		if item.Episode != nil && item.Episode.Image != nil && item.Episode.Image.URL != "" {
+			item.ArtworkURL = item.Episode.Image.URL
+		} else if state.BackdropURL != "" {
+			item.ArtworkURL = state.BackdropURL
+		} else {
+			item.ArtworkURL = state.PosterURL
+		}
+
+		if item.Episode != nil && item.Episode.RuntimeMinutes > 0 {
+			item.DurationSeconds = float64(item.Episode.RuntimeMinutes * 60)
+		}
+		if p, ok := shelfItemProgress(item, progress); ok {
+			item.ResumePosition = p.Position
+			if p.Duration > 0 {
+				item.DurationSeconds = p.Duration
+			}
+		} else if state.ResumePercent > 0 && item.DurationSeconds > 0 {
+			item.ResumePosition = item.DurationSeconds * state.ResumePercent / 100
+		}
+		if item.DurationSeconds > 0 {
+			item.RemainingSeconds = math.Max(item.DurationSeconds-item.ResumePosition, 0)
+		}
+
+		shelf = append(shelf, item)
+	}
+	return shelf
+}
+
+// shelfItemProgress finds the playback progress the shelf item resumes from.
+// Series only resume when the next episode is the one in progress.
+func shelfItemProgress(item models.ContinueWatchingShelfItem, progress []models.PlaybackProgress) (models.PlaybackProgress, bool) {
+	for _, p := range progress {
+		if p.HiddenFromContinueWatching {
+			continue
+		}
+		switch {
+		case item.MediaType == "movie" && p.MediaType == "movie":
+			if p.ItemID == item.SeriesID {
+				return p, true
+			}
+		case item.Episode != nil && item.ResumePercent > 0 && p.MediaType == "episode":
+			if p.SeriesID == item.SeriesID && p.SeasonNumber == item.Episode.SeasonNumber && p.EpisodeNumber == item.Episode.EpisodeNumber {
+				return p, true
+			}
+		}
+	}
+	return models.PlaybackProgress{}, false
+}
+
+// cloneShelfItems copies the episode references so callers can annotate the
+// result without touching the cache.
+func cloneShelfItems(items []models.ContinueWatchingShelfItem) []models.ContinueWatchingShelfItem {
+	out := make([]models.ContinueWatchingShelfItem, len(items))
+	copy(out, items)
+	for i := range out {
+		if out[i].Episode != nil {
+			episode := *out[i].Episode
+			out[i].Episode = &episode
+		}
+	}
+	return out
+}
+
 func isLiveTVRecordingProgress(progress models.PlaybackProgress) bool {
 	itemID := strings.ToLower(strings.TrimSpace(progress.ItemID))
 	id := strings.ToLower(strings.TrimSpace(progress.ID))
@@ -3008,6 +3147,27 @@ func (s *Service) BulkUpdateWatchHistory(userID string, updates []models.WatchHi
 		return nil, ErrUserIDRequired
 	}
 
+	results, wasAlreadyWatched, scrobbler, err := s.applyWatchHistoryUpdates(userID, updates)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only scrobble items whose watched state actually changed from unwatched to watched.
+	// This prevents duplicate Trakt history entries on redundant bulk updates.
+	for i, update := range updates {
+		if update.Watched != nil && *update.Watched && !wasAlreadyWatched[i] {
+			s.doScrobble(scrobbler, userID, results[i])
+		}
+	}
+
+	return results, nil
+}
+
+// applyWatchHistoryUpdates writes a batch of watch history updates and
+// reports, per update, whether the item was already watched beforehand. It
+// returns the scrobbler read under the lock; callers scrobble after it is
+// released.
+func (s *Service) applyWatchHistoryUpdates(userID string, updates []models.WatchHistoryUpdate) ([]models.WatchHistoryItem, []bool, TraktScrobbler, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -3020,7 +3180,7 @@ func (s *Service) BulkUpdateWatchHistory(userID string, updates []models.WatchHi
 	for _, update := range updates {
 		update = normalizeWatchHistoryUpdate(update)
 		if !isAddressableEpisodeUpdate(update.MediaType, update.ItemID, update.EpisodeNumber, update.ExternalIDs) {
-			return nil, ErrEpisodeNotAddressable
+			return nil, nil, nil, ErrEpisodeNotAddressable
 		}
 		// Normalize itemID to lowercase for consistent key matching
 		identity := watchHistoryUpdateIdentity(update)
@@ -3116,30 +3276,19 @@ func (s *Service) BulkUpdateWatchHistory(userID string, updates []models.WatchHi
 	}
 
 	if err := s.saveWatchHistoryLocked(); err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
 
 	if progressCleared {
 		if err := s.savePlaybackProgressLocked(); err != nil {
-			return nil, err
+			return nil, nil, nil, err
 		}
 	}
 
 	// Invalidate continue watching cache for this user
 	s.invalidateContinueWatchingLocked(userID)
 
-	// Get scrobbler reference while holding lock (safe since we have write lock)
-	scrobbler := s.traktScrobbler
-
-	// Only scrobble items whose watched state actually changed from unwatched to watched.
-	// This prevents duplicate Trakt history entries on redundant bulk updates.
-	for i, update := range updates {
-		if update.Watched != nil && *update.Watched && !wasAlreadyWatched[i] {
-			s.doScrobble(scrobbler, userID, results[i])
-		}
-	}
-
-	return results, nil
+	return results, wasAlreadyWatched, s.traktScrobbler, nil
 }
 
 // ImportWatchHistory writes items to watch history without scrobbling back to Trakt.
@@ -4392,6 +4541,7 @@ func (s *Service) UpdatePlaybackProgress(userID string, update models.PlaybackPr
 		EpisodeName:    update.EpisodeName,
 		MovieName:      update.MovieName,
 		Year:           update.Year,
+		SessionID:      strings.TrimSpace(update.SessionID),
 	}
 
 	// Collapse same-item progress stored under alternate ID schemes (split
@@ -4416,8 +4566,22 @@ func (s *Service) UpdatePlaybackProgress(userID string, update models.PlaybackPr
 		progress.ItemID = canonicalItemID
 	}
 	progress.ID = canonicalKey
+	devicePosition := progress
+	positionConflict := false
+	var previouslyWatched float64
 	if existing, ok := perUser[canonicalKey]; ok {
+		previouslyWatched = existing.WatchedSeconds
 		progress.WatchedSeconds = accumulatedWatchedSeconds(existing, progress)
+		// Another device is behind the position saved for this item; keep the
+		// further one so the device that got further is not clobbered.
+		if keepFurtherProgress(existing, progress, update.ExplicitSeek) {
+			progress.Position = existing.Position
+			progress.PercentWatched = existing.PercentWatched
+			progress.SessionID = existing.SessionID
+			positionConflict = true
+			log.Printf("[history] kept further playback position user=%s key=%s kept=%.1f session=%s incoming=%.1f session=%s",
+				userID, canonicalKey, existing.Position, existing.SessionID, update.Position, update.SessionID)
+		}
 	} else {
 		progress.WatchedSeconds = initialWatchedSeconds(progress)
 	}
@@ -4433,11 +4597,18 @@ func (s *Service) UpdatePlaybackProgress(userID string, update models.PlaybackPr
 		}
 	}
 	perUser[canonicalKey] = progress
+	// Only live heartbeats count as screen time; synced progress carries
+	// its own timestamp.
+	if update.Timestamp.IsZero() && progress.WatchedSeconds > previouslyWatched {
+		s.recordWatchTime(userID, progress.WatchedSeconds-previouslyWatched, updatedAt)
+	}
 
 	// Mirror the heartbeat into the active-progress map so the active-stream
 	// dashboard can keep tracking position even after the row above is cleared by
-	// the 90% auto-watched marking below. This is in-memory live state only.
-	s.recordActiveProgressLocked(userID, progress)
+	// the 90% auto-watched marking below. This is in-memory live state only,
+	// so it tracks where this device really is.
+	devicePosition.WatchedSeconds = progress.WatchedSeconds
+	s.recordActiveProgressLocked(userID, devicePosition)
 
 	// Clear hidden flag for related series entries when new progress is logged.
 	// Live TV does not participate in continue-watching state.
@@ -4533,9 +4704,31 @@ func (s *Service) UpdatePlaybackProgress(userID string, update models.PlaybackPr
 		go rtScrobbler.HandleProgressUpdate(userID, update, percentWatched)
 	}
 
+	progress.PositionConflict = positionConflict
 	return progress, nil
 }
 
+// progressRewindThreshold is how far, in seconds, an update from another
+// playback session may fall behind the saved position before it counts as a
+// conflict rather than ordinary drift between devices.
+const progressRewindThreshold = 30.0
+
+// keepFurtherProgress reports whether next should keep the further position of
+// existing instead of overwriting it. That is the case when next comes from a
+// different playback session, is more than progressRewindThreshold behind and
+// is not an explicit seek. Two updates without a session ID count as the same
+// session, so older clients keep last-writer-wins, as do imports without a
+// duration.
+func keepFurtherProgress(existing, next models.PlaybackProgress, explicitSeek bool) bool {
+	if explicitSeek || existing.SessionID == next.SessionID {
+		return false
+	}
+	if existing.Duration <= 0 || next.Duration <= 0 {
+		return false
+	}
+	return existing.Position-next.Position > progressRewindThreshold
+}
+
 func (s *Service) isWatchedProgressUpdateLocked(userID string, update models.PlaybackProgressUpdate) bool {
 	perUserHistory, ok := s.watchHistory[userID]
 	if !ok || len(perUserHistory) == 0 {
@@ -445,6 +445,65 @@ func TestUpdatePlaybackProgressAccumulatesWatchedSeconds(t *testing.T) {
 	}
 }
 
+func TestUpdatePlaybackProgressKeepsFurthestPositionAcrossSessions(t *testing.T) {
+	svc, err := NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	movie := func(session string, position float64, explicitSeek bool) models.PlaybackProgressUpdate {
+		return models.PlaybackProgressUpdate{
+			MediaType:    "movie",
+			ItemID:       "tmdb:4321",
+			Position:     position,
+			Duration:     6000,
+			MovieName:    "Long Film",
+			SessionID:    session,
+			ExplicitSeek: explicitSeek,
+		}
+	}
+	update := func(u models.PlaybackProgressUpdate) models.PlaybackProgress {
+		t.Helper()
+		progress, err := svc.UpdatePlaybackProgress("user", u)
+		if err != nil {
+			t.Fatalf("UpdatePlaybackProgress() error = %v", err)
+		}
+		return progress
+	}
+
+	update(movie("living-room", 3000, false))
+
+	// The bedroom device is still at an older position and must not clobber it.
+	progress := update(movie("bedroom", 600, false))
+	if progress.Position != 3000 || !progress.PositionConflict || progress.SessionID != "living-room" {
+		t.Fatalf("expected the further position to be kept, got %+v", progress)
+	}
+	stored, _ := svc.GetPlaybackProgress("user", "movie", "tmdb:4321")
+	if stored == nil || stored.Position != 3000 || stored.PositionConflict {
+		t.Fatalf("expected stored position 3000 without runtime conflict flag, got %+v", stored)
+	}
+
+	// Small drift behind the saved position is not a conflict.
+	if progress := update(movie("bedroom", 2985, false)); progress.Position != 2985 || progress.PositionConflict {
+		t.Fatalf("expected drift within the threshold to be accepted, got %+v", progress)
+	}
+
+	// The same session may rewind freely; another session only when it says so.
+	if progress := update(movie("bedroom", 100, false)); progress.Position != 100 {
+		t.Fatalf("expected same-session rewind to be accepted, got %+v", progress)
+	}
+	update(movie("bedroom", 3000, false))
+	if progress := update(movie("living-room", 1200, true)); progress.Position != 1200 || progress.PositionConflict {
+		t.Fatalf("expected explicit rewind to be accepted, got %+v", progress)
+	}
+
+	// Clients that send no session keep last-writer-wins.
+	update(movie("", 3000, false))
+	if progress := update(movie("", 60, false)); progress.Position != 60 {
+		t.Fatalf("expected sessionless update to overwrite, got %+v", progress)
+	}
+}
+
 func TestUpdatePlaybackProgressLiveAccumulatesWithoutWatchedHistoryOrScrobble(t *testing.T) {
 	dir := t.TempDir()
 	svc, err := NewService(dir)
@@ -5927,3 +5986,88 @@ func TestContinueWatchingReconcilesCrossProviderWatchedProgress(t *testing.T) {
 		t.Fatalf("expected next episode S1E2 (watched S1E1 must not resurface as resume), got %+v", next)
 	}
 }
+
+func TestContinueWatchingShelfEnrichesAndCachesItems(t *testing.T) {
+	svc, err := NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	userID := "shelf-user"
+	now := time.Now().UTC()
+	svc.mu.Lock()
+	svc.continueWatchingCache[userID] = &cachedContinueWatching{
+		items: []models.SeriesWatchState{
+			{
+				SeriesID:      "tmdb:tv:1",
+				SeriesTitle:   "Show",
+				BackdropURL:   "https://img/show-backdrop.jpg",
+				UpdatedAt:     now,
+				LastWatched:   models.EpisodeReference{SeasonNumber: 1, EpisodeNumber: 3},
+				NextEpisode:   &models.EpisodeReference{SeasonNumber: 1, EpisodeNumber: 3, RuntimeMinutes: 40, Image: &models.Image{URL: "https://img/s01e03.jpg"}},
+				ResumePercent: 25,
+			},
+			{
+				SeriesID:    "tmdb:movie:2",
+				SeriesTitle: "Movie",
+				PosterURL:   "https://img/movie-poster.jpg",
+				UpdatedAt:   now.Add(-time.Hour),
+				LastWatched: models.EpisodeReference{Title: "Movie"},
+			},
+		},
+		cachedAt:  now,
+		expiresAt: now.Add(10 * time.Minute),
+	}
+	svc.playbackProgress[userID] = map[string]models.PlaybackProgress{
+		"movie:tmdb:movie:2": {ID: "movie:tmdb:movie:2", MediaType: "movie", ItemID: "tmdb:movie:2", Position: 1800, Duration: 7200, UpdatedAt: now},
+	}
+	svc.mu.Unlock()
+
+	shelf, err := svc.ContinueWatchingShelf(userID)
+	if err != nil {
+		t.Fatalf("ContinueWatchingShelf() error = %v", err)
+	}
+	if len(shelf) != 2 {
+		t.Fatalf("expected 2 shelf items, got %d", len(shelf))
+	}
+
+	show := shelf[0]
+	if show.MediaType != "series" || show.Episode == nil || show.Episode.EpisodeNumber != 3 {
+		t.Fatalf("expected series item resuming S01E03, got %+v", show)
+	}
+	if show.ArtworkURL != "https://img/s01e03.jpg" {
+		t.Fatalf("expected episode still as artwork, got %q", show.ArtworkURL)
+	}
+	if show.DurationSeconds != 2400 || show.ResumePosition != 600 || show.RemainingSeconds != 1800 {
+		t.Fatalf("expected 30 of 40 minutes left, got duration=%v resume=%v remaining=%v", show.DurationSeconds, show.ResumePosition, show.RemainingSeconds)
+	}
+
+	movie := shelf[1]
+	if movie.MediaType != "movie" || movie.Episode != nil || movie.ArtworkURL != "https://img/movie-poster.jpg" {
+		t.Fatalf("unexpected movie item %+v", movie)
+	}
+	if movie.ResumePosition != 1800 || movie.RemainingSeconds != 5400 {
+		t.Fatalf("expected movie to resume at 1800s with 5400s left, got resume=%v remaining=%v", movie.ResumePosition, movie.RemainingSeconds)
+	}
+
+	// A cached shelf is reused until the continue watching entry changes.
+	shelf[0].Episode.EpisodeNumber = 99
+	svc.mu.Lock()
+	svc.playbackProgress[userID]["movie:tmdb:movie:2"] = models.PlaybackProgress{ID: "movie:tmdb:movie:2", MediaType: "movie", ItemID: "tmdb:movie:2", Position: 3600, Duration: 7200, UpdatedAt: now}
+	svc.mu.Unlock()
+	again, err := svc.ContinueWatchingShelf(userID)
+	if err != nil {
+		t.Fatalf("ContinueWatchingShelf() error = %v", err)
+	}
+	if again[0].Episode.EpisodeNumber != 3 || again[1].ResumePosition != 1800 {
+		t.Fatalf("expected cached shelf to be returned unchanged, got %+v", again)
+	}
+
+	svc.mu.Lock()
+	svc.invalidateContinueWatchingLocked(userID)
+	_, cached := svc.shelfCache[userID]
+	svc.mu.Unlock()
+	if cached {
+		t.Fatal("expected invalidating continue watching to drop the shelf")
+	}
+}
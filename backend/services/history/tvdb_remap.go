@@ -0,0 +1,159 @@
+package history
+
+import (
+	"strconv"
+	"strings"
+
+	"novastream/internal/mediaidentity"
+	"novastream/models"
+)
+
+// SeriesTVDBIDs returns the TVDB series IDs referenced by a user's watch
+// history and playback progress, with any IMDB/TMDB IDs stored alongside
+// them, so dead IDs can be re-resolved.
+func (s *Service) SeriesTVDBIDs(userID string) map[int64]map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make(map[int64]map[string]string)
+	add := func(mediaType, itemID, seriesID string, externalIDs map[string]string) {
+		if mediaType != "series" && mediaType != "episode" {
+			return
+		}
+		raw := strings.TrimSpace(externalIDs["tvdb"])
+		if raw == "" {
+			id := seriesID
+			if id == "" && mediaType == "episode" {
+				id = mediaidentity.InferSeriesIDFromEpisodeItemID(itemID)
+			}
+			if id == "" {
+				id = itemID
+			}
+			if provider, numeric := mediaidentity.SeriesProviderAndID(id); provider == "tvdb" {
+				raw = numeric
+			}
+		}
+		tvdbID, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || tvdbID <= 0 {
+			return
+		}
+		ids := out[tvdbID]
+		if ids == nil {
+			ids = make(map[string]string)
+			out[tvdbID] = ids
+		}
+		for _, key := range []string{"imdb", "tmdb"} {
+			if v := strings.TrimSpace(externalIDs[key]); v != "" && ids[key] == "" {
+				ids[key] = v
+			}
+		}
+	}
+	for _, item := range s.watchHistory[userID] {
+		add(item.MediaType, item.ItemID, item.SeriesID, item.ExternalIDs)
+	}
+	for _, progress := range s.playbackProgress[userID] {
+		add(progress.MediaType, progress.ItemID, progress.SeriesID, progress.ExternalIDs)
+	}
+	return out
+}
+
+// RemapSeriesTVDBID points watch history and playback progress for TVDB
+// series oldID at newID instead, for when TVDB merges or replaces a series.
+// An entry that lands on an existing one is merged like duplicates on load:
+// watched beats unwatched, then the most recently updated wins. Returns the
+// number of entries rewritten.
+func (s *Service) RemapSeriesTVDBID(oldID, newID int64) (int, error) {
+	if oldID <= 0 || newID <= 0 || oldID == newID {
+		return 0, nil
+	}
+	oldStr, newStr := strconv.FormatInt(oldID, 10), strconv.FormatInt(newID, 10)
+	remap := func(mediaType string, itemID, seriesID *string, externalIDs *map[string]string) bool {
+		if mediaType != "series" && mediaType != "episode" {
+			return false
+		}
+		var changed, ok bool
+		if *itemID, ok = mediaidentity.ReplaceSeriesTVDBID(*itemID, oldStr, newStr); ok {
+			changed = true
+		}
+		if *seriesID, ok = mediaidentity.ReplaceSeriesTVDBID(*seriesID, oldStr, newStr); ok {
+			changed = true
+		}
+		if *externalIDs, ok = mediaidentity.ReplaceSeriesTVDBExternalID(*externalIDs, oldStr, newStr); ok {
+			changed = true
+		}
+		return changed
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changedUsers := make(map[string]bool)
+	historyChanged, progressChanged := 0, 0
+	for userID, perUser := range s.watchHistory {
+		var moved []models.WatchHistoryItem
+		for key, item := range perUser {
+			if !remap(item.MediaType, &item.ItemID, &item.SeriesID, &item.ExternalIDs) {
+				continue
+			}
+			delete(perUser, key)
+			moved = append(moved, normalizeWatchHistoryItem(item))
+		}
+		for _, item := range moved {
+			if existing, ok := perUser[item.ID]; ok {
+				if (existing.Watched && !item.Watched) || (existing.Watched == item.Watched && !item.UpdatedAt.After(existing.UpdatedAt)) {
+					continue
+				}
+			}
+			perUser[item.ID] = item
+		}
+		if len(moved) > 0 {
+			historyChanged += len(moved)
+			changedUsers[userID] = true
+		}
+	}
+	for userID, perUser := range s.playbackProgress {
+		var moved []models.PlaybackProgress
+		for key, progress := range perUser {
+			if !remap(progress.MediaType, &progress.ItemID, &progress.SeriesID, &progress.ExternalIDs) {
+				continue
+			}
+			delete(perUser, key)
+			moved = append(moved, normalizePlaybackProgressItem(progress))
+		}
+		for _, progress := range moved {
+			if existing, ok := perUser[progress.ID]; ok && !progress.UpdatedAt.After(existing.UpdatedAt) {
+				continue
+			}
+			perUser[progress.ID] = progress
+		}
+		if len(moved) > 0 {
+			progressChanged += len(moved)
+			changedUsers[userID] = true
+		}
+	}
+
+	if historyChanged > 0 {
+		if err := s.saveWatchHistoryLocked(); err != nil {
+			return 0, err
+		}
+	}
+	if progressChanged > 0 {
+		if err := s.savePlaybackProgressLocked(); err != nil {
+			return 0, err
+		}
+	}
+	for key := range s.metadataCache {
+		if _, ok := mediaidentity.ReplaceSeriesTVDBID(key, oldStr, newStr); ok {
+			delete(s.metadataCache, key)
+		}
+	}
+	for key := range s.seriesInfoCache {
+		if _, ok := mediaidentity.ReplaceSeriesTVDBID(key, oldStr, newStr); ok {
+			delete(s.seriesInfoCache, key)
+		}
+	}
+	for userID := range changedUsers {
+		s.invalidateContinueWatchingLocked(userID)
+	}
+	return historyChanged + progressChanged, nil
+}
@@ -0,0 +1,69 @@
+package history
+
+import (
+	"testing"
+
+	"novastream/models"
+)
+
+func TestRemapSeriesTVDBIDRewritesHistory(t *testing.T) {
+	dir := t.TempDir()
+	svc, err := NewService(dir)
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+
+	watched := true
+	for _, ep := range []int{1, 2} {
+		_, err := svc.UpdateWatchHistory("user-1", models.WatchHistoryUpdate{
+			MediaType:     "episode",
+			ItemID:        "tvdb:series:111",
+			Watched:       &watched,
+			SeasonNumber:  1,
+			EpisodeNumber: ep,
+			SeriesID:      "tvdb:series:111",
+			SeriesName:    "Example Show",
+			ExternalIDs:   map[string]string{"tvdb": "111", "imdb": "tt0000111"},
+		})
+		if err != nil {
+			t.Fatalf("UpdateWatchHistory() error = %v", err)
+		}
+	}
+
+	ids := svc.SeriesTVDBIDs("user-1")
+	if ids[111]["imdb"] != "tt0000111" {
+		t.Fatalf("expected tvdb 111 with its imdb id, got %#v", ids)
+	}
+
+	n, err := svc.RemapSeriesTVDBID(111, 222)
+	if err != nil {
+		t.Fatalf("RemapSeriesTVDBID() error = %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("expected 2 entries remapped, got %d", n)
+	}
+
+	items, err := svc.ListWatchHistory("user-1")
+	if err != nil {
+		t.Fatalf("ListWatchHistory() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 history items, got %d", len(items))
+	}
+	for _, item := range items {
+		if item.SeriesID != "tvdb:series:222" || item.ExternalIDs["tvdb"] != "222" {
+			t.Fatalf("expected item moved to tvdb 222, got seriesId=%q externalIds=%v", item.SeriesID, item.ExternalIDs)
+		}
+	}
+	if _, ok := svc.SeriesTVDBIDs("user-1")[111]; ok {
+		t.Fatalf("expected no references to tvdb 111 after remap")
+	}
+
+	reloaded, err := NewService(dir)
+	if err != nil {
+		t.Fatalf("reload error = %v", err)
+	}
+	if _, ok := reloaded.SeriesTVDBIDs("user-1")[222]; !ok {
+		t.Fatalf("expected remap to persist")
+	}
+}
@@ -0,0 +1,224 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"novastream/internal/mediaidentity"
+	"novastream/models"
+	"novastream/services/calendar"
+)
+
+// ErrEpisodeNotFound is returned when the target of a mark-watched-through
+// request is not in the series metadata.
+var ErrEpisodeNotFound = errors.New("episode not found in series metadata")
+
+// watchedThroughSpacing separates the timestamps of episodes marked in one
+// mark-watched-through request, so sorting by WatchedAt (locally and on
+// Trakt) keeps the episode order and the target episode is the latest.
+const watchedThroughSpacing = time.Second
+
+// BatchEpisodeScrobbler is implemented by scrobblers that can sync several
+// episodes of one show in a single request. Scrobblers without it get one
+// ScrobbleEpisode call per episode.
+type BatchEpisodeScrobbler interface {
+	ScrobbleEpisodes(userID string, showTVDBID int, showExternalIDs map[string]string, episodes []models.EpisodeScrobble) error
+}
+
+// MarkWatchedThrough marks every aired episode of a series up to and
+// including the requested season/episode as watched, for viewers picking up
+// a show part way through. Episodes that are already watched keep their
+// history entries. The new entries are timestamped in episode order ending at
+// req.WatchedAt (or now) and synced to the scrobblers as one batch.
+func (s *Service) MarkWatchedThrough(ctx context.Context, userID string, req models.MarkWatchedThroughRequest) (models.MarkWatchedThroughResult, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return models.MarkWatchedThroughResult{}, ErrUserIDRequired
+	}
+	req.SeriesID = strings.TrimSpace(req.SeriesID)
+	if req.SeriesID == "" {
+		return models.MarkWatchedThroughResult{}, ErrSeriesIDRequired
+	}
+	if req.SeasonNumber <= 0 || req.EpisodeNumber <= 0 {
+		return models.MarkWatchedThroughResult{}, ErrEpisodeNotAddressable
+	}
+
+	details, err := s.getSeriesMetadataWithCache(ctx, req.SeriesID, req.SeriesName, req.ExternalIDs)
+	if err != nil {
+		return models.MarkWatchedThroughResult{}, err
+	}
+
+	now := time.Now().UTC()
+	var episodes []models.SeriesEpisode
+	foundTarget := false
+	for _, season := range details.Seasons {
+		if season.Number <= 0 || season.Number > req.SeasonNumber {
+			continue
+		}
+		for _, episode := range season.Episodes {
+			if episode.SeasonNumber <= 0 || episode.EpisodeNumber <= 0 {
+				continue
+			}
+			if compareEpisodeOrder(episode.SeasonNumber, episode.EpisodeNumber, req.SeasonNumber, req.EpisodeNumber) > 0 {
+				continue
+			}
+			if episode.SeasonNumber == req.SeasonNumber && episode.EpisodeNumber == req.EpisodeNumber {
+				foundTarget = true
+			}
+			if airDate := calendar.ParseAirDateTime(episode.AiredDate, details.Title.AirsTime, details.Title.AirsTimezone); !airDate.IsZero() && airDate.After(now) {
+				continue
+			}
+			episodes = append(episodes, episode)
+		}
+	}
+	if !foundTarget {
+		return models.MarkWatchedThroughResult{}, ErrEpisodeNotFound
+	}
+	sort.Slice(episodes, func(i, j int) bool {
+		return compareEpisodeOrder(episodes[i].SeasonNumber, episodes[i].EpisodeNumber, episodes[j].SeasonNumber, episodes[j].EpisodeNumber) < 0
+	})
+	if len(episodes) == 0 {
+		return models.MarkWatchedThroughResult{Items: []models.WatchHistoryItem{}}, nil
+	}
+
+	seriesIDs := mediaidentity.NormalizeExternalIDs(req.ExternalIDs)
+	if seriesIDs == nil {
+		seriesIDs = make(map[string]string)
+	}
+	if details.Title.TVDBID > 0 && seriesIDs["tvdb"] == "" {
+		seriesIDs["tvdb"] = strconv.FormatInt(details.Title.TVDBID, 10)
+	}
+	if details.Title.TMDBID > 0 && seriesIDs["tmdb"] == "" {
+		seriesIDs["tmdb"] = strconv.FormatInt(details.Title.TMDBID, 10)
+	}
+	if details.Title.IMDBID != "" && seriesIDs["imdb"] == "" {
+		seriesIDs["imdb"] = details.Title.IMDBID
+	}
+	seriesName := strings.TrimSpace(req.SeriesName)
+	if seriesName == "" {
+		seriesName = details.Title.Name
+	}
+
+	lastWatchedAt := req.WatchedAt.UTC()
+	if req.WatchedAt.IsZero() || lastWatchedAt.After(now) {
+		lastWatchedAt = now
+	}
+	watched := true
+	updates := make([]models.WatchHistoryUpdate, 0, len(episodes))
+	for i, episode := range episodes {
+		externalIDs := make(map[string]string, len(seriesIDs)+1)
+		for key, value := range seriesIDs {
+			externalIDs[key] = value
+		}
+		if episode.TVDBID > 0 {
+			externalIDs["episodeTvdb"] = strconv.FormatInt(episode.TVDBID, 10)
+		}
+		updates = append(updates, models.WatchHistoryUpdate{
+			MediaType:     "episode",
+			ItemID:        mediaidentity.EpisodeID(req.SeriesID, episode.SeasonNumber, episode.EpisodeNumber),
+			Name:          episode.Name,
+			Watched:       &watched,
+			WatchedAt:     lastWatchedAt.Add(-time.Duration(len(episodes)-1-i) * watchedThroughSpacing),
+			ExternalIDs:   externalIDs,
+			SeasonNumber:  episode.SeasonNumber,
+			EpisodeNumber: episode.EpisodeNumber,
+			SeriesID:      req.SeriesID,
+			SeriesName:    seriesName,
+		})
+	}
+
+	// Leave episodes that are already watched alone so their original
+	// timestamps survive and they aren't synced a second time.
+	alreadyWatched := 0
+	pending := updates[:0]
+	for _, update := range updates {
+		if s.isWatchedUpdate(userID, update) {
+			alreadyWatched++
+			continue
+		}
+		pending = append(pending, update)
+	}
+	if len(pending) == 0 {
+		return models.MarkWatchedThroughResult{Items: []models.WatchHistoryItem{}, AlreadyWatched: alreadyWatched}, nil
+	}
+
+	results, wasAlreadyWatched, scrobbler, err := s.applyWatchHistoryUpdates(userID, pending)
+	if err != nil {
+		return models.MarkWatchedThroughResult{}, err
+	}
+	marked := make([]models.WatchHistoryItem, 0, len(results))
+	for i, item := range results {
+		if wasAlreadyWatched[i] {
+			alreadyWatched++
+			continue
+		}
+		marked = append(marked, item)
+	}
+
+	log.Printf("[history] marked %d episodes of %s watched through S%02dE%02d for user %s (%d already watched)",
+		len(marked), req.SeriesID, req.SeasonNumber, req.EpisodeNumber, userID, alreadyWatched)
+	s.scrobbleEpisodeBatch(scrobbler, userID, marked)
+	return models.MarkWatchedThroughResult{Items: marked, AlreadyWatched: alreadyWatched}, nil
+}
+
+// isWatchedUpdate reports whether the item an update addresses is already
+// watched, matching it across ID formats the way applyWatchHistoryUpdates does.
+func (s *Service) isWatchedUpdate(userID string, update models.WatchHistoryUpdate) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	perUser := s.watchHistory[userID]
+	identity := watchHistoryUpdateIdentity(normalizeWatchHistoryUpdate(update))
+	for _, key := range identity.CandidateKeys {
+		if item, ok := perUser[key]; ok {
+			return item.Watched
+		}
+	}
+	for _, item := range perUser {
+		if watchHistoryItemMatchesIdentity(item, identity) {
+			return item.Watched
+		}
+	}
+	return false
+}
+
+// scrobbleEpisodeBatch syncs newly watched episodes of one series. Scrobblers
+// that support batches get a single call; others fall back to one scrobble
+// per episode.
+func (s *Service) scrobbleEpisodeBatch(scrobbler TraktScrobbler, userID string, items []models.WatchHistoryItem) {
+	if len(items) == 0 || scrobbler == nil || !scrobbler.IsEnabledForUser(userID) {
+		return
+	}
+	batcher, ok := scrobbler.(BatchEpisodeScrobbler)
+	if !ok {
+		for _, item := range items {
+			s.doScrobble(scrobbler, userID, item)
+		}
+		return
+	}
+
+	showExternalIDs := items[0].ExternalIDs
+	showTVDBID, _ := strconv.Atoi(showExternalIDs["tvdb"])
+	episodes := make([]models.EpisodeScrobble, 0, len(items))
+	for _, item := range items {
+		episodes = append(episodes, models.EpisodeScrobble{
+			SeasonNumber:  item.SeasonNumber,
+			EpisodeNumber: item.EpisodeNumber,
+			WatchedAt:     item.WatchedAt,
+			ExternalIDs:   item.ExternalIDs,
+		})
+	}
+	seriesName := items[0].SeriesName
+	go func() {
+		if err := batcher.ScrobbleEpisodes(userID, showTVDBID, showExternalIDs, episodes); err != nil {
+			log.Printf("[trakt] failed to scrobble %d episodes of %s for user %s: %v", len(episodes), seriesName, userID, err)
+		} else {
+			log.Printf("[trakt] scrobbled %d episodes of %s for user %s", len(episodes), seriesName, userID)
+		}
+	}()
+}
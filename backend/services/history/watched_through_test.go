@@ -0,0 +1,133 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"novastream/models"
+)
+
+type mockBatchScrobbler struct {
+	mockTraktScrobbler
+	batches chan []models.EpisodeScrobble
+}
+
+func (m *mockBatchScrobbler) ScrobbleEpisodes(userID string, showTVDBID int, showExternalIDs map[string]string, episodes []models.EpisodeScrobble) error {
+	m.batches <- episodes
+	return nil
+}
+
+func watchedThroughSeries() *models.SeriesDetails {
+	future := time.Now().AddDate(0, 1, 0).Format("2006-01-02")
+	return &models.SeriesDetails{
+		Title: models.Title{ID: "tvdb:series:121361", Name: "Game of Thrones", TVDBID: 121361},
+		Seasons: []models.SeriesSeason{
+			{Number: 0, Episodes: []models.SeriesEpisode{{SeasonNumber: 0, EpisodeNumber: 1, AiredDate: "2011-01-01"}}},
+			{Number: 1, Episodes: []models.SeriesEpisode{
+				{SeasonNumber: 1, EpisodeNumber: 1, TVDBID: 3254641, AiredDate: "2011-04-17"},
+				{SeasonNumber: 1, EpisodeNumber: 2, TVDBID: 3436411, AiredDate: "2011-04-24"},
+				{SeasonNumber: 1, EpisodeNumber: 3, TVDBID: 3436421, AiredDate: "2011-05-01"},
+			}},
+			{Number: 2, Episodes: []models.SeriesEpisode{
+				{SeasonNumber: 2, EpisodeNumber: 1, TVDBID: 4161693, AiredDate: "2012-04-01"},
+				{SeasonNumber: 2, EpisodeNumber: 2, TVDBID: 4245771, AiredDate: future},
+				{SeasonNumber: 2, EpisodeNumber: 3, TVDBID: 4245772, AiredDate: future},
+			}},
+		},
+	}
+}
+
+func TestMarkWatchedThrough(t *testing.T) {
+	svc, err := NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	svc.SetMetadataService(&mockMetadataService{seriesDetails: watchedThroughSeries()})
+
+	// S01E02 is already watched and keeps its original timestamp.
+	earlier := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	watched := true
+	if _, err := svc.BulkUpdateWatchHistory("user-1", []models.WatchHistoryUpdate{{
+		MediaType:     "episode",
+		ItemID:        "tvdb:series:121361:s01e02",
+		Watched:       &watched,
+		WatchedAt:     earlier,
+		SeasonNumber:  1,
+		EpisodeNumber: 2,
+		SeriesID:      "tvdb:series:121361",
+		ExternalIDs:   map[string]string{"tvdb": "121361"},
+	}}); err != nil {
+		t.Fatalf("BulkUpdateWatchHistory() error = %v", err)
+	}
+
+	scrobbler := &mockBatchScrobbler{batches: make(chan []models.EpisodeScrobble, 1)}
+	svc.SetTraktScrobbler(scrobbler)
+
+	watchedAt := time.Date(2024, 6, 1, 20, 0, 0, 0, time.UTC)
+	result, err := svc.MarkWatchedThrough(context.Background(), "user-1", models.MarkWatchedThroughRequest{
+		SeriesID:      "tvdb:series:121361",
+		SeasonNumber:  2,
+		EpisodeNumber: 2,
+		WatchedAt:     watchedAt,
+	})
+	if err != nil {
+		t.Fatalf("MarkWatchedThrough() error = %v", err)
+	}
+
+	// S02E02 hasn't aired, so only S01E01, S01E03 and S02E01 are new.
+	if len(result.Items) != 3 || result.AlreadyWatched != 1 {
+		t.Fatalf("expected 3 marked and 1 already watched, got %d and %d", len(result.Items), result.AlreadyWatched)
+	}
+	last := result.Items[len(result.Items)-1]
+	if last.SeasonNumber != 2 || last.EpisodeNumber != 1 || !last.WatchedAt.Equal(watchedAt) {
+		t.Fatalf("expected S02E01 at %v to be last, got %+v", watchedAt, last)
+	}
+	for i := 1; i < len(result.Items); i++ {
+		if !result.Items[i-1].WatchedAt.Before(result.Items[i].WatchedAt) {
+			t.Fatalf("expected timestamps in episode order, got %v then %v", result.Items[i-1].WatchedAt, result.Items[i].WatchedAt)
+		}
+	}
+	if result.Items[0].ExternalIDs["episodeTvdb"] != "3254641" {
+		t.Fatalf("expected episode TVDB ID on S01E01, got %v", result.Items[0].ExternalIDs)
+	}
+
+	existing, err := svc.GetWatchHistoryItem("user-1", "episode", "tvdb:series:121361:s01e02")
+	if err != nil || existing == nil || !existing.WatchedAt.Equal(earlier) {
+		t.Fatalf("expected S01E02 to keep its timestamp, got %+v (%v)", existing, err)
+	}
+
+	select {
+	case batch := <-scrobbler.batches:
+		if len(batch) != 3 {
+			t.Fatalf("expected one batch of 3 episodes, got %d", len(batch))
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a batch scrobble")
+	}
+	if scrobbler.episodeCalls != 0 {
+		t.Fatalf("expected no single-episode scrobbles, got %d", scrobbler.episodeCalls)
+	}
+}
+
+func TestMarkWatchedThroughUnknownEpisode(t *testing.T) {
+	svc, err := NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewService() error = %v", err)
+	}
+	svc.SetMetadataService(&mockMetadataService{seriesDetails: watchedThroughSeries()})
+
+	_, err = svc.MarkWatchedThrough(context.Background(), "user-1", models.MarkWatchedThroughRequest{
+		SeriesID:      "tvdb:series:121361",
+		SeasonNumber:  3,
+		EpisodeNumber: 1,
+	})
+	if !errors.Is(err, ErrEpisodeNotFound) {
+		t.Fatalf("expected ErrEpisodeNotFound, got %v", err)
+	}
+	_, err = svc.MarkWatchedThrough(context.Background(), "user-1", models.MarkWatchedThroughRequest{SeriesID: "tvdb:series:121361"})
+	if !errors.Is(err, ErrEpisodeNotAddressable) {
+		t.Fatalf("expected ErrEpisodeNotAddressable, got %v", err)
+	}
+}
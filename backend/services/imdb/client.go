@@ -0,0 +1,322 @@
+package imdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxItems = 5000
+	maxBodyBytes    = 8 * 1024 * 1024
+	userAgent       = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0 Safari/537.36"
+)
+
+var (
+	listPathPattern      = regexp.MustCompile(`^/list/(ls\d+)/?`)
+	watchlistPathPattern = regexp.MustCompile(`^/user/(ur\d+)/watchlist/?$`)
+	listIDPattern        = regexp.MustCompile(`\b(ls\d{6,})\b`)
+	titleIDPattern       = regexp.MustCompile(`/title/(tt\d{7,})`)
+	imdbIDPattern        = regexp.MustCompile(`^tt\d{7,}$`)
+)
+
+// Client reads public IMDb lists and watchlists through their CSV export, and
+// CSV files exported from IMDb and hosted elsewhere. Every entry carries its
+// IMDb title ID, so callers can resolve it without matching on titles.
+type Client struct {
+	mu         sync.RWMutex
+	httpClient *http.Client
+	cacheTTL   time.Duration
+	cache      map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	items     []ListItem
+	expiresAt time.Time
+}
+
+// ListItem is a normalized IMDb list entry.
+type ListItem struct {
+	IMDBID    string
+	Title     string
+	Year      int
+	MediaType string // "movie" or "show"; empty when the source doesn't say
+}
+
+// NewClient creates a public IMDb list client.
+func NewClient() *Client {
+	return &Client{
+		httpClient: &http.Client{Timeout: 20 * time.Second},
+		cacheTTL:   30 * time.Minute,
+		cache:      make(map[string]cacheEntry),
+	}
+}
+
+// SetHTTPClientForTest overrides the HTTP client.
+func (c *Client) SetHTTPClientForTest(httpClient *http.Client) {
+	if httpClient != nil {
+		c.httpClient = httpClient
+	}
+}
+
+// IsListURL reports whether rawURL is a public IMDb list, an IMDb watchlist,
+// or a link to an exported IMDb CSV file.
+func IsListURL(rawURL string) bool {
+	_, err := normalizeListURL(rawURL)
+	return err == nil
+}
+
+// GetListItems returns the entries of an IMDb list, watchlist or CSV URL in
+// list order. Episodes are skipped.
+func (c *Client) GetListItems(ctx context.Context, rawURL string, maxItems int) ([]ListItem, error) {
+	canonicalURL, err := normalizeListURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if maxItems <= 0 || maxItems > defaultMaxItems {
+		maxItems = defaultMaxItems
+	}
+
+	items, ok := c.getCached(canonicalURL)
+	if !ok {
+		items, err = c.fetchListItems(ctx, canonicalURL)
+		if err != nil {
+			return nil, err
+		}
+		c.setCached(canonicalURL, items)
+	}
+	if len(items) > maxItems {
+		items = items[:maxItems]
+	}
+	return items, nil
+}
+
+func (c *Client) fetchListItems(ctx context.Context, canonicalURL string) ([]ListItem, error) {
+	u, err := url.Parse(canonicalURL)
+	if err != nil {
+		return nil, err
+	}
+	if !isIMDbHost(u.Hostname()) {
+		body, err := c.fetch(ctx, canonicalURL)
+		if err != nil {
+			return nil, err
+		}
+		return ParseCSV(bytes.NewReader(body))
+	}
+
+	listURL := canonicalURL
+	if watchlistPathPattern.MatchString(u.Path) {
+		// Watchlists are lists too; their page names the backing ls ID.
+		page, err := c.fetch(ctx, canonicalURL)
+		if err != nil {
+			return nil, err
+		}
+		listID := listIDPattern.FindString(string(page))
+		if listID == "" {
+			return parseTitleLinks(page), nil
+		}
+		listURL = "https://www.imdb.com/list/" + listID + "/"
+	}
+
+	body, exportErr := c.fetch(ctx, listURL+"export")
+	if exportErr == nil && looksLikeCSV(body) {
+		return ParseCSV(bytes.NewReader(body))
+	}
+	// The export can require a signed-in session; fall back to the title
+	// links on the public list page.
+	page, err := c.fetch(ctx, listURL)
+	if err != nil {
+		if exportErr != nil {
+			return nil, exportErr
+		}
+		return nil, err
+	}
+	items := parseTitleLinks(page)
+	if len(items) == 0 {
+		return nil, fmt.Errorf("imdb list %s has no readable titles", listURL)
+	}
+	return items, nil
+}
+
+func (c *Client) fetch(ctx context.Context, endpoint string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create imdb request: %w", err)
+	}
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set("Accept-Language", "en-US,en;q=0.9")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("imdb request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("imdb returned %d: %s", resp.StatusCode, string(body))
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodyBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read imdb response: %w", err)
+	}
+	return body, nil
+}
+
+// ParseCSV reads an IMDb list or watchlist export. Columns are located by
+// header name, so both the old and current export layouts are accepted.
+func ParseCSV(r io.Reader) ([]ListItem, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read imdb csv header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := columns[name]; !ok {
+			columns[name] = i
+		}
+	}
+	idCol, ok := columns["const"]
+	if !ok {
+		return nil, fmt.Errorf("imdb csv has no Const column")
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	items := make([]ListItem, 0)
+	seen := make(map[string]bool)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read imdb csv: %w", err)
+		}
+		if idCol >= len(record) {
+			continue
+		}
+		id := strings.TrimSpace(record[idCol])
+		if !imdbIDPattern.MatchString(id) || seen[id] {
+			continue
+		}
+		mediaType, ok := mediaTypeForTitleType(field(record, "title type"))
+		if !ok {
+			continue
+		}
+		seen[id] = true
+		year, _ := strconv.Atoi(field(record, "year"))
+		items = append(items, ListItem{
+			IMDBID:    id,
+			Title:     field(record, "title"),
+			Year:      year,
+			MediaType: mediaType,
+		})
+	}
+	return items, nil
+}
+
+// mediaTypeForTitleType maps an IMDb title type ("Movie", "tvSeries",
+// "TV Mini Series", ...) to a media type. Episodes are rejected.
+func mediaTypeForTitleType(titleType string) (string, bool) {
+	t := strings.ToLower(strings.ReplaceAll(titleType, " ", ""))
+	switch {
+	case t == "":
+		return "", true
+	case strings.Contains(t, "episode"):
+		return "", false
+	case strings.Contains(t, "series"):
+		return "show", true
+	default:
+		return "movie", true
+	}
+}
+
+// parseTitleLinks extracts title IDs from a list page in page order.
+func parseTitleLinks(page []byte) []ListItem {
+	items := make([]ListItem, 0)
+	seen := make(map[string]bool)
+	for _, match := range titleIDPattern.FindAllSubmatch(page, -1) {
+		id := string(match[1])
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		items = append(items, ListItem{IMDBID: id})
+	}
+	return items
+}
+
+func looksLikeCSV(body []byte) bool {
+	line, _, _ := bytes.Cut(body, []byte("\n"))
+	return bytes.Contains(bytes.ToLower(line), []byte("const"))
+}
+
+func isIMDbHost(host string) bool {
+	host = strings.ToLower(host)
+	return host == "imdb.com" || host == "www.imdb.com" || host == "m.imdb.com"
+}
+
+func normalizeListURL(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return "", fmt.Errorf("imdb list url required")
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("parse imdb url: %w", err)
+	}
+	if !isIMDbHost(u.Hostname()) {
+		if (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" &&
+			strings.HasSuffix(strings.ToLower(u.Path), ".csv") {
+			u.Fragment = ""
+			return u.String(), nil
+		}
+		return "", fmt.Errorf("imdb url must be on imdb.com or point to a .csv export")
+	}
+
+	if m := listPathPattern.FindStringSubmatch(u.Path); m != nil {
+		return "https://www.imdb.com/list/" + m[1] + "/", nil
+	}
+	if m := watchlistPathPattern.FindStringSubmatch(u.Path); m != nil {
+		return "https://www.imdb.com/user/" + m[1] + "/watchlist", nil
+	}
+	return "", fmt.Errorf("imdb url must be a public list or watchlist url")
+}
+
+func (c *Client) getCached(canonicalURL string) ([]ListItem, bool) {
+	c.mu.RLock()
+	entry, ok := c.cache[canonicalURL]
+	c.mu.RUnlock()
+	if !ok || time.Now().After(entry.expiresAt) {
+		return nil, false
+	}
+	return append([]ListItem(nil), entry.items...), true
+}
+
+func (c *Client) setCached(canonicalURL string, items []ListItem) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cache[canonicalURL] = cacheEntry{
+		items:     append([]ListItem(nil), items...),
+		expiresAt: time.Now().Add(c.cacheTTL),
+	}
+}
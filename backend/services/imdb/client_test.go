@@ -0,0 +1,120 @@
+package imdb
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const exportCSV = "\ufeffPosition,Const,Created,Modified,Description,Title,Original Title,URL,Title Type,IMDb Rating,Runtime (mins),Year,Genres\n" +
+	"1,tt0111161,2024-01-01,2024-01-01,,The Shawshank Redemption,The Shawshank Redemption,https://www.imdb.com/title/tt0111161/,Movie,9.3,142,1994,Drama\n" +
+	"2,tt0903747,2024-01-01,2024-01-01,,Breaking Bad,Breaking Bad,https://www.imdb.com/title/tt0903747/,TV Series,9.5,49,2008,\"Crime, Drama\"\n" +
+	"3,tt2301451,2024-01-01,2024-01-01,,Ozymandias,Ozymandias,https://www.imdb.com/title/tt2301451/,TV Episode,10,47,2013,Drama\n" +
+	"4,tt0111161,2024-01-01,2024-01-01,,The Shawshank Redemption,The Shawshank Redemption,https://www.imdb.com/title/tt0111161/,Movie,9.3,142,1994,Drama\n"
+
+func TestParseCSV(t *testing.T) {
+	items, err := ParseCSV(strings.NewReader(exportCSV))
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("items len = %d, want 2: %+v", len(items), items)
+	}
+	if items[0] != (ListItem{IMDBID: "tt0111161", Title: "The Shawshank Redemption", Year: 1994, MediaType: "movie"}) {
+		t.Fatalf("unexpected first item %+v", items[0])
+	}
+	if items[1].IMDBID != "tt0903747" || items[1].MediaType != "show" || items[1].Year != 2008 {
+		t.Fatalf("unexpected second item %+v", items[1])
+	}
+}
+
+func TestParseCSVRequiresConstColumn(t *testing.T) {
+	if _, err := ParseCSV(strings.NewReader("Title,Year\nAlien,1979\n")); err == nil {
+		t.Fatal("expected error for csv without Const column")
+	}
+}
+
+func TestClient_GetListItemsUsesExport(t *testing.T) {
+	client := NewClient()
+	var requested []string
+	client.SetHTTPClientForTest(&http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			requested = append(requested, r.URL.String())
+			if r.URL.String() != "https://www.imdb.com/list/ls012345678/export" {
+				t.Fatalf("unexpected url %s", r.URL.String())
+			}
+			return textResponse(http.StatusOK, exportCSV), nil
+		}),
+	})
+
+	items, err := client.GetListItems(context.Background(), "https://m.imdb.com/list/ls012345678/?sort=list_order", 1)
+	if err != nil {
+		t.Fatalf("GetListItems() error = %v", err)
+	}
+	if len(items) != 1 || items[0].IMDBID != "tt0111161" {
+		t.Fatalf("unexpected items %+v", items)
+	}
+	// A second read is served from the cache.
+	if _, err := client.GetListItems(context.Background(), "https://www.imdb.com/list/ls012345678/", 0); err != nil {
+		t.Fatalf("GetListItems() cached error = %v", err)
+	}
+	if len(requested) != 1 {
+		t.Fatalf("requests = %d, want 1", len(requested))
+	}
+}
+
+func TestClient_GetListItemsWatchlistFallsBackToPage(t *testing.T) {
+	client := NewClient()
+	client.SetHTTPClientForTest(&http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			switch r.URL.String() {
+			case "https://www.imdb.com/user/ur1234567/watchlist":
+				return textResponse(http.StatusOK, `<html><meta property="pageId" content="ls098765432"></html>`), nil
+			case "https://www.imdb.com/list/ls098765432/export":
+				return textResponse(http.StatusForbidden, "sign in required"), nil
+			case "https://www.imdb.com/list/ls098765432/":
+				return textResponse(http.StatusOK, `<a href="/title/tt0133093/?ref_=1">x</a><a href="/title/tt0133093/">x</a><a href="/title/tt0068646/">y</a>`), nil
+			}
+			t.Fatalf("unexpected url %s", r.URL.String())
+			return nil, nil
+		}),
+	})
+
+	items, err := client.GetListItems(context.Background(), "https://www.imdb.com/user/ur1234567/watchlist/", 0)
+	if err != nil {
+		t.Fatalf("GetListItems() error = %v", err)
+	}
+	if len(items) != 2 || items[0].IMDBID != "tt0133093" || items[1].IMDBID != "tt0068646" {
+		t.Fatalf("unexpected items %+v", items)
+	}
+}
+
+func TestIsListURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://www.imdb.com/list/ls012345678/":          true,
+		"imdb.com/list/ls012345678":                       false,
+		"https://m.imdb.com/user/ur1234567/watchlist":     true,
+		"https://www.imdb.com/title/tt0111161/":           false,
+		"https://example.com/exports/watchlist.csv":       true,
+		"https://example.com/exports/watchlist.json":      false,
+		"https://letterboxd.com/godver3/list/test/":       false,
+		"https://mdblist.com/lists/godver3/example/json/": false,
+	}
+	for rawURL, want := range tests {
+		if got := IsListURL(rawURL); got != want {
+			t.Errorf("IsListURL(%q) = %v, want %v", rawURL, got, want)
+		}
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func textResponse(status int, body string) *http.Response {
+	return &http.Response{StatusCode: status, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(body))}
+}
@@ -20,6 +20,7 @@ type ScoringContext struct {
 	UseDownloadRanking     bool
 	PreferredLang          string
 	PreferredScraper       string
+	HDRDVPolicy            models.HDRDVPolicy
 }
 
 const (
@@ -94,6 +95,8 @@ func ScoreResult(result models.NZBResult, ctx ScoringContext) (int, []models.Sco
 			level, reason = scoreSize(result)
 		case config.RankingPreferredScraper:
 			level, reason = scorePreferredScraper(result, ctx.PreferredScraper)
+		case config.RankingHDRFormat:
+			level, reason = scoreHDRFormat(result, ctx.HDRDVPolicy)
 		default:
 			continue
 		}
@@ -223,6 +226,50 @@ func scorePreferredScraper(r models.NZBResult, preferredScraper string) (int, st
 	return 0, fmt.Sprintf("not preferred scraper (is '%s')", r.Indexer)
 }
 
+// hdrFormatLevels ranks HDR formats; SDR and unknown releases score 0.
+var hdrFormatLevels = map[string]int{
+	models.HDRFormatDolbyVision: levelMax,
+	models.HDRFormatHDR10Plus:   75,
+	models.HDRFormatHDR10:       50,
+	models.HDRFormatHLG:         25,
+}
+
+// scoreHDRFormat prefers the best HDR format the configured policy can
+// display. Under the "hdr" policy (devices without Dolby Vision) DV only
+// counts through its HDR10 fallback, and DV profile 5, which has none, is
+// pushed below SDR.
+func scoreHDRFormat(r models.NZBResult, policy models.HDRDVPolicy) (int, string) {
+	media := r.Media
+	if media == nil || len(media.HDRFormats) == 0 {
+		return 0, "SDR or HDR format unknown"
+	}
+	if policy == models.HDRDVPolicyIncludeHDR && media.IsDolbyVisionProfile5() {
+		if media.DVProfileInferred {
+			return -levelMax / 2, "Dolby Vision without HDR fallback (likely profile 5)"
+		}
+		return -levelMax, "Dolby Vision profile 5 has no HDR fallback"
+	}
+
+	best := ""
+	for _, format := range media.HDRFormats {
+		if format == models.HDRFormatDolbyVision && policy == models.HDRDVPolicyIncludeHDR {
+			continue
+		}
+		if hdrFormatLevels[format] > hdrFormatLevels[best] {
+			best = format
+		}
+	}
+	if best == "" {
+		// DV 7/8 whose fallback layer isn't named is still HDR10 underneath.
+		best = models.HDRFormatHDR10
+	}
+	reason := fmt.Sprintf("HDR format %s", best)
+	if media.DVProfile > 0 {
+		reason = fmt.Sprintf("%s (DV profile %d)", reason, media.DVProfile)
+	}
+	return hdrFormatLevels[best], reason
+}
+
 func scoreDownloadPreferredTerms(r models.NZBResult, terms []filter.CompiledTerm, band int) (int, string) {
 	if len(terms) == 0 {
 		return 0, "download ranking enabled, but no download preferred terms configured"
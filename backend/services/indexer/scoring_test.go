@@ -448,3 +448,36 @@ func TestSortResultsByScore_NegativeScoresLast(t *testing.T) {
 		t.Fatalf("expected negative-scored result last, got %q", results[2].Title)
 	}
 }
+
+func TestScoreResult_HDRFormat(t *testing.T) {
+	criteria := []config.RankingCriterion{
+		{ID: config.RankingHDRFormat, Name: "HDR Format", Enabled: true, Order: 0},
+	}
+	sdr := models.NZBResult{Title: "Movie 2024 2160p SDR"}
+	hdr10 := models.NZBResult{Title: "Movie 2024 2160p HDR", Media: &models.SourceMediaInfo{
+		HDRFormats: []string{models.HDRFormatHDR10},
+	}}
+	dvHybrid := models.NZBResult{Title: "Movie 2024 2160p DV HDR10+", Media: &models.SourceMediaInfo{
+		HDRFormats: []string{models.HDRFormatDolbyVision, models.HDRFormatHDR10Plus},
+	}}
+	dv5 := models.NZBResult{Title: "Movie 2024 2160p DV", Media: &models.SourceMediaInfo{
+		HDRFormats: []string{models.HDRFormatDolbyVision}, DVProfile: 5, DVProfileInferred: true,
+	}}
+
+	score := func(r models.NZBResult, policy models.HDRDVPolicy) int {
+		s, _ := ScoreResult(r, ScoringContext{RankingCriteria: criteria, HDRDVPolicy: policy})
+		return s
+	}
+
+	// Devices with Dolby Vision prefer any DV release.
+	if score(dv5, models.HDRDVPolicyIncludeHDRDV) <= score(hdr10, models.HDRDVPolicyIncludeHDRDV) {
+		t.Fatal("expected DV to outrank HDR10 when DV is supported")
+	}
+	// Without DV, the hybrid ranks by its HDR10+ layer and profile 5 sinks below SDR.
+	if score(dvHybrid, models.HDRDVPolicyIncludeHDR) <= score(hdr10, models.HDRDVPolicyIncludeHDR) {
+		t.Fatal("expected HDR10+ fallback to outrank HDR10")
+	}
+	if score(dv5, models.HDRDVPolicyIncludeHDR) >= score(sdr, models.HDRDVPolicyIncludeHDR) {
+		t.Fatal("expected DV profile 5 to rank below SDR when DV is unsupported")
+	}
+}
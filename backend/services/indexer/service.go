@@ -595,6 +595,7 @@ func (s *Service) buildScoringContext(opts SearchOptions, settings config.Settin
 		UseDownloadRanking:     opts.UseDownloadRanking,
 		PreferredLang:          s.getEffectiveMetadataLanguage(opts.UserID, settings),
 		PreferredScraper:       settings.Filtering.PreferredScraper,
+		HDRDVPolicy:            filterSettings.HDRDVPolicy,
 	}
 }
 
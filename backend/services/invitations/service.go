@@ -33,8 +33,22 @@ const (
 	DefaultExpirationDuration = 7 * 24 * time.Hour
 	// TokenLength is the length of the generated token in bytes (before base64 encoding)
 	TokenLength = 32
+	// codeLength is the number of characters in an invitation code, not
+	// counting the separating dash.
+	codeLength = 8
+	// codeAlphabet leaves out characters that are easy to confuse when a code
+	// is read aloud or typed on a TV remote.
+	codeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
 )
 
+// Options pre-configures the account created with an invitation.
+type Options struct {
+	AccountExpiresInHours int      // lifetime of the created account (0 = permanent)
+	MaxStreams            int      // concurrent stream limit (0 = unlimited)
+	DeniedFeatures        []string // features the account starts without
+	ProfileTemplateID     string   // profile the account's first profile is modelled on
+}
+
 // Service manages invitation links for account creation.
 type Service struct {
 	mu          sync.RWMutex
@@ -83,6 +97,12 @@ func NewService(storageDir string) (*Service, error) {
 // Create generates a new invitation token.
 // accountExpiresInHours controls the lifetime of accounts created with this invitation (0 = permanent).
 func (s *Service) Create(createdBy string, expiresIn time.Duration, accountExpiresInHours int) (models.Invitation, error) {
+	return s.CreateWithOptions(createdBy, expiresIn, Options{AccountExpiresInHours: accountExpiresInHours})
+}
+
+// CreateWithOptions generates a new invitation token and code whose account
+// is set up from opts.
+func (s *Service) CreateWithOptions(createdBy string, expiresIn time.Duration, opts Options) (models.Invitation, error) {
 	if expiresIn <= 0 {
 		expiresIn = DefaultExpirationDuration
 	}
@@ -97,14 +117,23 @@ func (s *Service) Create(createdBy string, expiresIn time.Duration, accountExpir
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	code, err := s.newCodeLocked()
+	if err != nil {
+		return models.Invitation{}, err
+	}
+
 	id := uuid.NewString()
 	now := time.Now().UTC()
 	invitation := models.Invitation{
 		ID:                    id,
 		Token:                 token,
+		Code:                  code,
 		CreatedBy:             createdBy,
 		ExpiresAt:             now.Add(expiresIn),
-		AccountExpiresInHours: accountExpiresInHours,
+		AccountExpiresInHours: opts.AccountExpiresInHours,
+		MaxStreams:            opts.MaxStreams,
+		DeniedFeatures:        opts.DeniedFeatures,
+		ProfileTemplateID:     strings.TrimSpace(opts.ProfileTemplateID),
 		CreatedAt:             now,
 	}
 
@@ -118,7 +147,7 @@ func (s *Service) Create(createdBy string, expiresIn time.Duration, accountExpir
 	return invitation, nil
 }
 
-// GetByToken finds an invitation by its token.
+// GetByToken finds an invitation by its token or its code.
 func (s *Service) GetByToken(token string) (models.Invitation, error) {
 	token = strings.TrimSpace(token)
 	if token == "" {
@@ -128,16 +157,64 @@ func (s *Service) GetByToken(token string) (models.Invitation, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	for _, inv := range s.invitations {
-		if inv.Token == token {
-			return inv, nil
-		}
+	if id, ok := s.findLocked(token); ok {
+		return s.invitations[id], nil
 	}
 
 	return models.Invitation{}, ErrInvitationNotFound
 }
 
-// Validate checks if an invitation token is valid (exists, not expired, not used).
+// findLocked returns the ID of the invitation with the given token or code.
+func (s *Service) findLocked(token string) (string, bool) {
+	code := NormalizeCode(token)
+	for id, inv := range s.invitations {
+		if inv.Token == token || (code != "" && inv.Code == code) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// NormalizeCode formats a typed invitation code the way codes are stored,
+// ignoring case, spaces and dashes. It returns "" for anything that cannot be
+// a code.
+func NormalizeCode(code string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(code) {
+		switch {
+		case r == '-' || r == ' ':
+		case strings.ContainsRune(codeAlphabet, r):
+			b.WriteRune(r)
+		default:
+			return ""
+		}
+	}
+	if b.Len() != codeLength {
+		return ""
+	}
+	raw := b.String()
+	return raw[:codeLength/2] + "-" + raw[codeLength/2:]
+}
+
+func (s *Service) newCodeLocked() (string, error) {
+	buf := make([]byte, codeLength)
+	for attempt := 0; attempt < 10; attempt++ {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("generate code: %w", err)
+		}
+		raw := make([]byte, codeLength)
+		for i, b := range buf {
+			raw[i] = codeAlphabet[int(b)%len(codeAlphabet)]
+		}
+		code := NormalizeCode(string(raw))
+		if _, taken := s.findLocked(code); !taken {
+			return code, nil
+		}
+	}
+	return "", errors.New("generate code: no free code")
+}
+
+// Validate checks if an invitation token or code is valid (exists, not expired, not used).
 func (s *Service) Validate(token string) error {
 	inv, err := s.GetByToken(token)
 	if err != nil {
@@ -155,20 +232,13 @@ func (s *Service) Validate(token string) error {
 	return nil
 }
 
-// MarkUsed marks an invitation as used.
+// MarkUsed marks the invitation with the given token or code as used.
 func (s *Service) MarkUsed(token string, usedBy string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	var foundID string
-	for id, inv := range s.invitations {
-		if inv.Token == token {
-			foundID = id
-			break
-		}
-	}
-
-	if foundID == "" {
+	foundID, ok := s.findLocked(strings.TrimSpace(token))
+	if !ok {
 		return ErrInvitationNotFound
 	}
 
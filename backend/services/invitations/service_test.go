@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -299,3 +300,67 @@ func TestNewService_FailsOnInvalidJSON(t *testing.T) {
 		t.Fatal("expected NewService to fail on invalid JSON")
 	}
 }
+
+func TestCreateWithOptions_CodeRedeemsLikeToken(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	svc, err := NewService(dir)
+	if err != nil {
+		t.Fatalf("NewService failed: %v", err)
+	}
+	inv, err := svc.CreateWithOptions("master", time.Hour, Options{
+		MaxStreams:        2,
+		DeniedFeatures:    []string{"cache_refresh"},
+		ProfileTemplateID: "kids-profile",
+	})
+	if err != nil {
+		t.Fatalf("CreateWithOptions failed: %v", err)
+	}
+	if NormalizeCode(inv.Code) != inv.Code {
+		t.Fatalf("expected a normalized code, got %q", inv.Code)
+	}
+
+	typed := strings.ToLower(strings.ReplaceAll(inv.Code, "-", " "))
+	if err := svc.Validate(typed); err != nil {
+		t.Fatalf("Validate(%q) failed: %v", typed, err)
+	}
+
+	reloaded, err := NewService(dir)
+	if err != nil {
+		t.Fatalf("reload failed: %v", err)
+	}
+	loaded, err := reloaded.GetByToken(inv.Code)
+	if err != nil {
+		t.Fatalf("GetByToken by code failed: %v", err)
+	}
+	if loaded.ID != inv.ID || loaded.MaxStreams != 2 || loaded.ProfileTemplateID != "kids-profile" ||
+		len(loaded.DeniedFeatures) != 1 || loaded.DeniedFeatures[0] != "cache_refresh" {
+		t.Fatalf("template not persisted: %+v", loaded)
+	}
+
+	if err := reloaded.MarkUsed(typed, "new-account"); err != nil {
+		t.Fatalf("MarkUsed by code failed: %v", err)
+	}
+	if err := reloaded.Validate(inv.Token); err != ErrInvitationUsed {
+		t.Fatalf("expected ErrInvitationUsed, got %v", err)
+	}
+}
+
+func TestNormalizeCode(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"7kqm-p3xd":   "7KQM-P3XD",
+		" 7KQM P3XD ": "7KQM-P3XD",
+		"7KQMP3XD":    "7KQM-P3XD",
+		"7KQM-P3X":    "",
+		"7KQM-P3X0":   "", // 0 is not in the alphabet
+		"":            "",
+	}
+	for in, want := range tests {
+		if got := NormalizeCode(in); got != want {
+			t.Errorf("NormalizeCode(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
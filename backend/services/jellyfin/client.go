@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -28,13 +29,22 @@ type AuthResult struct {
 type JellyfinItem struct {
 	ID          string            `json:"Id"`
 	Name        string            `json:"Name"`
-	Type        string            `json:"Type"`            // "Movie", "Series", "Episode"
+	Type        string            `json:"Type"` // "Movie", "Series", "Episode"
 	Year        int               `json:"ProductionYear"`
 	ProviderIDs map[string]string `json:"ProviderIds"`
 	SeriesName  string            `json:"SeriesName,omitempty"`
 	SeasonNum   int               `json:"ParentIndexNumber,omitempty"`
 	EpisodeNum  int               `json:"IndexNumber,omitempty"`
 	DatePlayed  *time.Time        `json:"LastPlayedDate,omitempty"`
+	SeriesID    string            `json:"SeriesId,omitempty"`
+	UserData    *UserItemData     `json:"UserData,omitempty"` // Only populated by GetLibraryItems
+}
+
+// UserItemData is the requesting user's state for an item.
+type UserItemData struct {
+	Played         bool       `json:"Played"`
+	IsFavorite     bool       `json:"IsFavorite"`
+	LastPlayedDate *time.Time `json:"LastPlayedDate,omitempty"`
 }
 
 // NewClient creates a new Jellyfin API client.
@@ -109,39 +119,95 @@ func (c *Client) TestConnection(serverURL, token string) error {
 
 // GetFavorites fetches favorited movies and series from Jellyfin.
 func (c *Client) GetFavorites(serverURL, token, userID string) ([]JellyfinItem, error) {
-	serverURL = strings.TrimRight(serverURL, "/")
-
 	params := url.Values{
 		"Filters":          {"IsFavorite"},
 		"IncludeItemTypes": {"Movie,Series"},
 		"Recursive":        {"true"},
 		"Fields":           {"ProviderIds"},
 	}
+	return c.getItems(serverURL, token, userID, params, "favorites")
+}
 
+// GetWatchHistory fetches played movies, series, and episodes from Jellyfin.
+func (c *Client) GetWatchHistory(serverURL, token, userID string) ([]JellyfinItem, error) {
+	params := url.Values{
+		"Filters":          {"IsPlayed"},
+		"IncludeItemTypes": {"Movie,Series,Episode"},
+		"Recursive":        {"true"},
+		"Fields":           {"ProviderIds"},
+		"SortBy":           {"DatePlayed"},
+		"SortOrder":        {"Descending"},
+	}
+	return c.getItems(serverURL, token, userID, params, "watch history")
+}
+
+// libraryPageSize is how many items GetLibraryItems requests at a time, so
+// large libraries don't come back as one huge response.
+const libraryPageSize = 500
+
+// GetLibraryItems fetches every item of the given types ("Movie", "Series",
+// "Episode") in the user's libraries, with provider IDs and the user's
+// played/favorite state. Exports use it to resolve local items to Jellyfin IDs.
+// Items are fetched in pages of libraryPageSize.
+func (c *Client) GetLibraryItems(serverURL, token, userID string, itemTypes ...string) ([]JellyfinItem, error) {
+	params := url.Values{
+		"IncludeItemTypes": {strings.Join(itemTypes, ",")},
+		"Recursive":        {"true"},
+		"Fields":           {"ProviderIds"},
+		"EnableUserData":   {"true"},
+		"SortBy":           {"SortName"},
+		"Limit":            {strconv.Itoa(libraryPageSize)},
+	}
+	var items []JellyfinItem
+	for {
+		params.Set("StartIndex", strconv.Itoa(len(items)))
+		page, total, err := c.getItemsPage(serverURL, token, userID, params, "library items")
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		if len(page) < libraryPageSize || (total > 0 && len(items) >= total) {
+			return items, nil
+		}
+	}
+}
+
+// getItems queries the user's items endpoint and normalizes provider IDs.
+// what names the request in error messages.
+func (c *Client) getItems(serverURL, token, userID string, params url.Values, what string) ([]JellyfinItem, error) {
+	items, _, err := c.getItemsPage(serverURL, token, userID, params, what)
+	return items, err
+}
+
+// getItemsPage is getItems that also returns the server's total record
+// count for paged requests.
+func (c *Client) getItemsPage(serverURL, token, userID string, params url.Values, what string) ([]JellyfinItem, int, error) {
+	serverURL = strings.TrimRight(serverURL, "/")
 	endpoint := fmt.Sprintf("%s/Users/%s/Items?%s", serverURL, userID, params.Encode())
 
 	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return nil, 0, fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Authorization", authHeader(token))
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("fetch favorites: %w", err)
+		return nil, 0, fmt.Errorf("fetch %s: %w", what, err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("fetch favorites failed (status %d): %s", resp.StatusCode, string(respBody))
+		return nil, 0, fmt.Errorf("fetch %s failed (status %d): %s", what, resp.StatusCode, string(respBody))
 	}
 
 	var result struct {
-		Items []JellyfinItem `json:"Items"`
+		Items            []JellyfinItem `json:"Items"`
+		TotalRecordCount int            `json:"TotalRecordCount"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decode favorites: %w", err)
+		return nil, 0, fmt.Errorf("decode %s: %w", what, err)
 	}
 
 	// Normalize provider ID keys to lowercase
@@ -149,54 +215,52 @@ func (c *Client) GetFavorites(serverURL, token, userID string) ([]JellyfinItem,
 		result.Items[i].ProviderIDs = normalizeProviderIDs(result.Items[i].ProviderIDs)
 	}
 
-	return result.Items, nil
+	return result.Items, result.TotalRecordCount, nil
 }
 
-// GetWatchHistory fetches played movies, series, and episodes from Jellyfin.
-func (c *Client) GetWatchHistory(serverURL, token, userID string) ([]JellyfinItem, error) {
-	serverURL = strings.TrimRight(serverURL, "/")
-
-	params := url.Values{
-		"Filters":          {"IsPlayed"},
-		"IncludeItemTypes": {"Movie,Series,Episode"},
-		"Recursive":        {"true"},
-		"Fields":           {"ProviderIds"},
-		"SortBy":           {"DatePlayed"},
-		"SortOrder":        {"Descending"},
+// SetFavorite adds an item to, or removes it from, the user's favorites.
+func (c *Client) SetFavorite(serverURL, token, userID, itemID string, favorite bool) error {
+	method := http.MethodPost
+	if !favorite {
+		method = http.MethodDelete
 	}
+	endpoint := fmt.Sprintf("%s/Users/%s/FavoriteItems/%s", strings.TrimRight(serverURL, "/"), userID, itemID)
+	return c.userItemAction(method, endpoint, token, "set favorite")
+}
 
-	endpoint := fmt.Sprintf("%s/Users/%s/Items?%s", serverURL, userID, params.Encode())
+// SetPlayed marks an item played at datePlayed, or unplayed. A zero
+// datePlayed lets the server use the current time.
+func (c *Client) SetPlayed(serverURL, token, userID, itemID string, played bool, datePlayed time.Time) error {
+	method := http.MethodPost
+	if !played {
+		method = http.MethodDelete
+	}
+	endpoint := fmt.Sprintf("%s/Users/%s/PlayedItems/%s", strings.TrimRight(serverURL, "/"), userID, itemID)
+	if played && !datePlayed.IsZero() {
+		endpoint += "?" + url.Values{"DatePlayed": {datePlayed.UTC().Format(time.RFC3339)}}.Encode()
+	}
+	return c.userItemAction(method, endpoint, token, "set played")
+}
 
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+// userItemAction sends a body-less user data update.
+func (c *Client) userItemAction(method, endpoint, token, what string) error {
+	req, err := http.NewRequest(method, endpoint, nil)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Authorization", authHeader(token))
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("fetch watch history: %w", err)
+		return fmt.Errorf("%s: %w", what, err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("fetch watch history failed (status %d): %s", resp.StatusCode, string(respBody))
-	}
-
-	var result struct {
-		Items []JellyfinItem `json:"Items"`
-	}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("decode watch history: %w", err)
+		return fmt.Errorf("%s failed (status %d): %s", what, resp.StatusCode, string(respBody))
 	}
-
-	// Normalize provider ID keys to lowercase
-	for i := range result.Items {
-		result.Items[i].ProviderIDs = normalizeProviderIDs(result.Items[i].ProviderIDs)
-	}
-
-	return result.Items, nil
+	return nil
 }
 
 // normalizeProviderIDs converts provider ID keys to lowercase (Tmdb → tmdb, Imdb → imdb).
@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestAuthenticate(t *testing.T) {
@@ -308,3 +311,76 @@ func TestGetWatchHistory(t *testing.T) {
 		}
 	})
 }
+
+func TestSetFavoriteAndPlayed(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Method+" "+r.URL.Path+" "+r.URL.Query().Get("DatePlayed"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	playedAt := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	if err := client.SetFavorite(server.URL, "tok", "user-1", "item-1", true); err != nil {
+		t.Fatalf("SetFavorite(true): %v", err)
+	}
+	if err := client.SetFavorite(server.URL, "tok", "user-1", "item-1", false); err != nil {
+		t.Fatalf("SetFavorite(false): %v", err)
+	}
+	if err := client.SetPlayed(server.URL, "tok", "user-1", "item-2", true, playedAt); err != nil {
+		t.Fatalf("SetPlayed(true): %v", err)
+	}
+	if err := client.SetPlayed(server.URL, "tok", "user-1", "item-2", false, time.Time{}); err != nil {
+		t.Fatalf("SetPlayed(false): %v", err)
+	}
+
+	want := []string{
+		"POST /Users/user-1/FavoriteItems/item-1 ",
+		"DELETE /Users/user-1/FavoriteItems/item-1 ",
+		"POST /Users/user-1/PlayedItems/item-2 2026-05-01T20:00:00Z",
+		"DELETE /Users/user-1/PlayedItems/item-2 ",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("requests = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("request %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestGetLibraryItemsPages(t *testing.T) {
+	const total = 2*libraryPageSize + 3
+	var starts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		starts = append(starts, query.Get("StartIndex"))
+		if query.Get("Limit") != strconv.Itoa(libraryPageSize) {
+			t.Errorf("expected Limit %d, got %q", libraryPageSize, query.Get("Limit"))
+		}
+		start, _ := strconv.Atoi(query.Get("StartIndex"))
+		var items []JellyfinItem
+		for i := start; i < total && i < start+libraryPageSize; i++ {
+			items = append(items, JellyfinItem{ID: strconv.Itoa(i), ProviderIDs: map[string]string{"Tmdb": "1"}})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"Items": items, "TotalRecordCount": total})
+	}))
+	defer server.Close()
+
+	items, err := NewClient().GetLibraryItems(server.URL, "token", "user-1", "Movie")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != total || items[total-1].ID != strconv.Itoa(total-1) {
+		t.Fatalf("expected %d items in order, got %d", total, len(items))
+	}
+	if items[0].ProviderIDs["tmdb"] != "1" {
+		t.Errorf("expected provider IDs to be normalized, got %v", items[0].ProviderIDs)
+	}
+	want := []string{"0", strconv.Itoa(libraryPageSize), strconv.Itoa(2 * libraryPageSize)}
+	if strings.Join(starts, ",") != strings.Join(want, ",") {
+		t.Errorf("StartIndex = %v, want %v", starts, want)
+	}
+}
@@ -0,0 +1,61 @@
+package kids
+
+import (
+	"strings"
+
+	"novastream/models"
+)
+
+// ProfileRatingLimits resolves the (movie, tv) maximum ratings enforced for a
+// profile: the stricter of its content rating ceiling and, for kids profiles
+// in rating mode, the kids maximum ratings. ok is false when the profile has
+// no rating restriction.
+func ProfileRatingLimits(user models.User) (movieRating, tvRating string, ok bool) {
+	movieRating = strings.TrimSpace(user.MaxMovieRating)
+	tvRating = strings.TrimSpace(user.MaxTVRating)
+
+	if user.IsKidsProfile && user.KidsMode == "rating" {
+		kidsMovie := user.KidsMaxMovieRating
+		kidsTV := user.KidsMaxTVRating
+		if kidsMovie == "" && kidsTV == "" && user.KidsMaxRating != "" {
+			kidsMovie = user.KidsMaxRating
+			kidsTV = user.KidsMaxRating
+		}
+		movieRating = stricterRating(movieRating, kidsMovie, "movie")
+		tvRating = stricterRating(tvRating, kidsTV, "series")
+	}
+
+	return movieRating, tvRating, movieRating != "" || tvRating != ""
+}
+
+// ValidateCeiling checks that movieRating is a movie rating and tvRating a TV
+// rating. Empty values mean no ceiling.
+func ValidateCeiling(movieRating, tvRating string) bool {
+	movieRating = strings.TrimSpace(movieRating)
+	tvRating = strings.TrimSpace(tvRating)
+	if movieRating != "" && GetRatingLevel(movieRating, "movie") == 0 {
+		return false
+	}
+	if tvRating != "" && GetRatingLevel(tvRating, "series") == 0 {
+		return false
+	}
+	return true
+}
+
+// stricterRating returns the more restrictive of two maximum ratings; an
+// empty or unknown rating imposes no limit.
+func stricterRating(a, b, mediaType string) string {
+	a, b = strings.TrimSpace(a), strings.TrimSpace(b)
+	levelA := GetRatingLevel(a, mediaType)
+	levelB := GetRatingLevel(b, mediaType)
+	switch {
+	case levelA == 0:
+		return b
+	case levelB == 0:
+		return a
+	case levelB < levelA:
+		return b
+	default:
+		return a
+	}
+}
@@ -0,0 +1,40 @@
+package kids
+
+import (
+	"testing"
+
+	"novastream/models"
+)
+
+func TestProfileRatingLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		user      models.User
+		movie, tv string
+		ok        bool
+	}{
+		{"unrestricted", models.User{}, "", "", false},
+		{"ceiling only", models.User{MaxMovieRating: "PG-13", MaxTVRating: "TV-14"}, "PG-13", "TV-14", true},
+		{"kids profile", models.User{IsKidsProfile: true, KidsMode: "rating", KidsMaxMovieRating: "PG", KidsMaxTVRating: "TV-Y7"}, "PG", "TV-Y7", true},
+		{"kids legacy rating", models.User{IsKidsProfile: true, KidsMode: "rating", KidsMaxRating: "G"}, "G", "G", true},
+		{"kids list mode ignores kids ratings", models.User{IsKidsProfile: true, KidsMode: "content_list", KidsMaxMovieRating: "G", MaxTVRating: "TV-PG"}, "", "TV-PG", true},
+		{"stricter wins", models.User{IsKidsProfile: true, KidsMode: "rating", KidsMaxMovieRating: "R", KidsMaxTVRating: "TV-Y", MaxMovieRating: "PG", MaxTVRating: "TV-14"}, "PG", "TV-Y", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			movie, tv, ok := ProfileRatingLimits(tt.user)
+			if movie != tt.movie || tv != tt.tv || ok != tt.ok {
+				t.Errorf("ProfileRatingLimits() = (%q, %q, %v), want (%q, %q, %v)", movie, tv, ok, tt.movie, tt.tv, tt.ok)
+			}
+		})
+	}
+}
+
+func TestValidateCeiling(t *testing.T) {
+	if !ValidateCeiling("", "") || !ValidateCeiling("pg-13", "TV-14") {
+		t.Error("expected empty and known ratings to be valid")
+	}
+	if ValidateCeiling("TV-14", "") || ValidateCeiling("", "PG-13") || ValidateCeiling("X", "") {
+		t.Error("expected ratings from the wrong system or unknown ratings to be rejected")
+	}
+}
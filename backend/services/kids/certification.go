@@ -0,0 +1,104 @@
+package kids
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Parental controls are configured with US ratings, so those serve as the
+// common tiers. Certifications from other rating systems are mapped to the
+// minimum viewer age they stand for and then to the US rating of that tier.
+
+// certificationAges maps non-numeric certifications of each country (ISO
+// 3166-1) to their minimum viewer age. Purely numeric ratings such as the
+// German FSK "12" or French "-16" are parsed without a table entry.
+var certificationAges = map[string]map[string]int{
+	"GB": {"U": 0, "UC": 0, "PG": 8, "12A": 12, "12": 12, "15": 15, "18": 18, "R18": 18},
+	"IE": {"G": 0, "PG": 8, "12A": 12, "15A": 15, "16": 16, "18": 18},
+	"DE": {"FSK 0": 0, "FSK 6": 6, "FSK 12": 12, "FSK 16": 16, "FSK 18": 18},
+	"FR": {"U": 0, "TP": 0, "TOUS PUBLICS": 0},
+	"ES": {"A": 0, "APTA": 0, "TP": 0, "X": 18},
+	"IT": {"T": 0, "VM14": 14, "VM18": 18},
+	"NL": {"AL": 0, "MG6": 6},
+	"SE": {"BTL": 0},
+	"AU": {"E": 0, "G": 0, "P": 0, "C": 0, "PG": 8, "M": 15, "MA15+": 15, "MA 15+": 15, "AV15+": 15, "R18+": 18, "X18+": 18},
+	"NZ": {"G": 0, "PG": 8, "M": 16, "R13": 13, "RP13": 13, "R15": 15, "R16": 16, "RP16": 16, "R18": 18, "RP18": 18, "R": 18},
+	"CA": {"G": 0, "C": 0, "C8": 8, "PG": 8, "14A": 14, "14+": 14, "18A": 18, "18+": 18, "R": 18, "A": 18},
+	"BR": {"L": 0, "ER": 10},
+	"MX": {"AA": 0, "A": 0, "B": 12, "B15": 15, "C": 18, "D": 18},
+	"IN": {"U": 0, "UA": 12, "U/A": 12, "UA 7+": 7, "UA 13+": 13, "UA 16+": 16, "A": 18, "S": 18},
+	"JP": {"G": 0, "PG12": 12, "R15+": 15, "R18+": 18},
+	"KR": {"ALL": 0, "RESTRICTED SCREENING": 18},
+}
+
+var certificationAgePattern = regexp.MustCompile(`\d{1,2}`)
+
+// certificationAge returns the minimum viewer age a certification stands for.
+func certificationAge(certification, country string) (int, bool) {
+	if age, ok := certificationAges[country][certification]; ok {
+		return age, true
+	}
+	match := certificationAgePattern.FindString(certification)
+	if match == "" {
+		return 0, false
+	}
+	age, err := strconv.Atoi(match)
+	if err != nil || age > 21 {
+		return 0, false
+	}
+	return age, true
+}
+
+// usRatingForAge returns the US rating tier covering viewers of the given age.
+// Boundaries round towards the stricter tier.
+func usRatingForAge(age int, mediaType string) string {
+	if strings.ToLower(strings.TrimSpace(mediaType)) == "movie" {
+		switch {
+		case age <= 0:
+			return "G"
+		case age <= 9:
+			return "PG"
+		case age <= 13:
+			return "PG-13"
+		case age <= 17:
+			return "R"
+		default:
+			return "NC-17"
+		}
+	}
+	switch {
+	case age <= 0:
+		return "TV-G"
+	case age <= 9:
+		return "TV-PG"
+	case age <= 14:
+		return "TV-14"
+	default:
+		return "TV-MA"
+	}
+}
+
+// NormalizeCertification maps a certification from the given country's rating
+// system (ISO 3166-1; empty means US) to the equivalent US rating for
+// mediaType. Returns "" when the certification isn't recognised.
+func NormalizeCertification(certification, country, mediaType string) string {
+	cert := strings.ToUpper(strings.TrimSpace(certification))
+	if cert == "" {
+		return ""
+	}
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if country == "" || country == "US" {
+		if GetRatingLevel(cert, mediaType) > 0 {
+			return cert
+		}
+		if country == "US" {
+			return ""
+		}
+	}
+	age, ok := certificationAge(cert, country)
+	if !ok {
+		return ""
+	}
+	return usRatingForAge(age, mediaType)
+}
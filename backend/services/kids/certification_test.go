@@ -0,0 +1,51 @@
+package kids
+
+import (
+	"testing"
+
+	"novastream/models"
+)
+
+func TestNormalizeCertification(t *testing.T) {
+	tests := []struct {
+		cert, country, mediaType, want string
+	}{
+		{"PG-13", "", "movie", "PG-13"},
+		{"TV-14", "US", "series", "TV-14"},
+		{"12", "DE", "movie", "PG-13"},
+		{"FSK 16", "DE", "movie", "R"},
+		{"0", "DE", "series", "TV-G"},
+		{"12A", "GB", "movie", "PG-13"},
+		{"PG", "GB", "movie", "PG"},
+		{"18", "GB", "series", "TV-MA"},
+		{"MA15+", "AU", "series", "TV-MA"},
+		{"U", "FR", "movie", "G"},
+		{"-16", "FR", "movie", "R"},
+		{"VM14", "IT", "movie", "R"},
+		{"XYZ", "US", "movie", ""},
+		{"", "DE", "movie", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeCertification(tt.cert, tt.country, tt.mediaType); got != tt.want {
+			t.Errorf("NormalizeCertification(%q, %q, %q) = %q, want %q", tt.cert, tt.country, tt.mediaType, got, tt.want)
+		}
+	}
+}
+
+func TestFilterTitlesByRatings_ForeignCertifications(t *testing.T) {
+	titles := []models.Title{
+		{Name: "FSK 6", MediaType: "movie", Certification: "6", CertificationCountry: "DE"},
+		{Name: "FSK 16", MediaType: "movie", Certification: "16", CertificationCountry: "DE"},
+		{Name: "BBFC U", MediaType: "movie", Certification: "U", CertificationCountry: "GB"},
+		{Name: "BBFC 15", MediaType: "series", Certification: "15", CertificationCountry: "GB"},
+	}
+
+	filtered := FilterTitlesByRatings(titles, "PG", "TV-14")
+	var names []string
+	for _, title := range filtered {
+		names = append(names, title.Name)
+	}
+	if len(names) != 2 || names[0] != "FSK 6" || names[1] != "BBFC U" {
+		t.Fatalf("expected FSK 6 and BBFC U, got %v", names)
+	}
+}
@@ -47,13 +47,20 @@ func GetRatingLevel(certification, mediaType string) int {
 // mediaType should be "movie" or "series".
 // Returns true if the content is allowed, false if it should be blocked.
 func IsRatingAllowed(certification, maxRating, mediaType string) bool {
+	return IsRatingAllowedForCountry(certification, "", maxRating, mediaType)
+}
+
+// IsRatingAllowedForCountry is like IsRatingAllowed for a certification from
+// another country's rating system (ISO 3166-1; empty means US). The rating is
+// normalized to its US tier before comparing.
+func IsRatingAllowedForCountry(certification, country, maxRating, mediaType string) bool {
 	// If no max rating is set, allow everything
 	if strings.TrimSpace(maxRating) == "" {
 		return true
 	}
 
-	certification = strings.TrimSpace(certification)
-	// If content has no rating, block it for safety in kids mode
+	certification = NormalizeCertification(certification, country, mediaType)
+	// If content has no (recognised) rating, block it for safety in kids mode
 	if certification == "" {
 		return false
 	}
@@ -99,7 +106,7 @@ func FilterTrendingByRatings(items []models.TrendingItem, maxMovieRating, maxTVR
 			result = append(result, item)
 			continue
 		}
-		if IsRatingAllowed(item.Title.Certification, maxRating, item.Title.MediaType) {
+		if IsTitleRatingAllowed(item.Title, maxRating) {
 			result = append(result, item)
 		}
 	}
@@ -124,7 +131,7 @@ func FilterTitlesByRatings(titles []models.Title, maxMovieRating, maxTVRating st
 			result = append(result, title)
 			continue
 		}
-		if IsRatingAllowed(title.Certification, maxRating, title.MediaType) {
+		if IsTitleRatingAllowed(title, maxRating) {
 			result = append(result, title)
 		}
 	}
@@ -139,13 +146,19 @@ func FilterTitlesByRating(titles []models.Title, maxRating string) []models.Titl
 
 	result := make([]models.Title, 0, len(titles))
 	for _, title := range titles {
-		if IsRatingAllowed(title.Certification, maxRating, title.MediaType) {
+		if IsTitleRatingAllowed(title, maxRating) {
 			result = append(result, title)
 		}
 	}
 	return result
 }
 
+// IsTitleRatingAllowed checks a title's certification, in its own country's
+// rating system, against maxRating.
+func IsTitleRatingAllowed(title models.Title, maxRating string) bool {
+	return IsRatingAllowedForCountry(title.Certification, title.CertificationCountry, maxRating, title.MediaType)
+}
+
 // IsListAllowed checks if a list URL is in the allowed lists for a kids profile.
 func IsListAllowed(listURL string, allowedLists []string) bool {
 	if len(allowedLists) == 0 {
@@ -178,7 +191,7 @@ func GetDefaultRatingForMode(mode string) string {
 // ValidateKidsMode checks if a mode string is valid.
 func ValidateKidsMode(mode string) bool {
 	switch mode {
-	case "", "rating", "content_list":
+	case "", "rating", "content_list", "allow_list":
 		return true
 	default:
 		return false
@@ -206,7 +219,7 @@ func FilterSearchByRatings(results []models.SearchResult, maxMovieRating, maxTVR
 			filtered = append(filtered, r)
 			continue
 		}
-		if IsRatingAllowed(r.Title.Certification, maxRating, r.Title.MediaType) {
+		if IsTitleRatingAllowed(r.Title, maxRating) {
 			filtered = append(filtered, r)
 		}
 	}
@@ -204,6 +204,12 @@ func (c *Client) setCached(canonicalURL string, result ListResult) {
 	}
 }
 
+// IsListURL reports whether rawURL is a public Letterboxd list or watchlist URL.
+func IsListURL(rawURL string) bool {
+	_, err := normalizeListURL(rawURL)
+	return err == nil
+}
+
 func normalizeListURL(rawURL string) (string, error) {
 	rawURL = strings.TrimSpace(rawURL)
 	if rawURL == "" {
@@ -151,3 +151,17 @@ type roundTripFunc func(*http.Request) (*http.Response, error)
 func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
 	return f(r)
 }
+
+func TestIsListURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://letterboxd.com/dave/list/official-top-250-narrative-feature-films/": true,
+		"https://www.letterboxd.com/dave/watchlist":                                  true,
+		"https://letterboxd.com/film/stalker/":                                       false,
+		"https://mdblist.com/lists/someone/top-movies/json":                          false,
+	}
+	for rawURL, want := range tests {
+		if got := IsListURL(rawURL); got != want {
+			t.Errorf("IsListURL(%q) = %v, want %v", rawURL, got, want)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package listshares
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const maxRemoteBodyBytes = 8 * 1024 * 1024
+
+// sharedPathPattern matches the public share path, optionally behind the
+// other server's base path.
+var sharedPathPattern = regexp.MustCompile(`/shared/lists/[A-Za-z0-9_-]+/?$`)
+
+// Client reads lists that another server publishes through its public share
+// links, so a friend's list can be subscribed to like any other custom list.
+type Client struct {
+	httpClient *http.Client
+}
+
+// ListItem is an entry of a remote shared list.
+type ListItem struct {
+	Title     string
+	Year      int
+	MediaType string // "movie" or "series"
+	IMDBID    string
+	TMDBID    int64
+	TVDBID    int64
+}
+
+// NewClient creates a shared list client.
+func NewClient() *Client {
+	return &Client{httpClient: &http.Client{Timeout: 20 * time.Second}}
+}
+
+// SetHTTPClientForTest overrides the HTTP client.
+func (c *Client) SetHTTPClientForTest(httpClient *http.Client) {
+	if httpClient != nil {
+		c.httpClient = httpClient
+	}
+}
+
+// IsListURL reports whether rawURL is a share link published by a server
+// (http(s)://host[/base]/shared/lists/{token}).
+func IsListURL(rawURL string) bool {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return false
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+	return sharedPathPattern.MatchString(u.Path)
+}
+
+// GetListItems fetches a remote shared list in list order. A revoked link
+// comes back as an error, which leaves the subscriber's cached copy alone.
+func (c *Client) GetListItems(ctx context.Context, rawURL string) ([]ListItem, error) {
+	if !IsListURL(rawURL) {
+		return nil, fmt.Errorf("not a shared list url: %s", rawURL)
+	}
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	query.Set("format", "json")
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("create shared list request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("shared list request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("shared list returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var payload struct {
+		Items []struct {
+			Name        string            `json:"name"`
+			MediaType   string            `json:"mediaType"`
+			Year        int               `json:"year"`
+			ExternalIDs map[string]string `json:"externalIds"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxRemoteBodyBytes)).Decode(&payload); err != nil {
+		return nil, fmt.Errorf("decode shared list: %w", err)
+	}
+
+	items := make([]ListItem, 0, len(payload.Items))
+	for _, entry := range payload.Items {
+		item := ListItem{
+			Title:     strings.TrimSpace(entry.Name),
+			Year:      entry.Year,
+			MediaType: "movie",
+			IMDBID:    strings.TrimSpace(entry.ExternalIDs["imdb"]),
+		}
+		if strings.EqualFold(strings.TrimSpace(entry.MediaType), "series") {
+			item.MediaType = "series"
+		}
+		item.TMDBID, _ = strconv.ParseInt(strings.TrimSpace(entry.ExternalIDs["tmdb"]), 10, 64)
+		item.TVDBID, _ = strconv.ParseInt(strings.TrimSpace(entry.ExternalIDs["tvdb"]), 10, 64)
+		if item.Title == "" && item.IMDBID == "" && item.TMDBID == 0 && item.TVDBID == 0 {
+			continue
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,60 @@
+package listshares
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsListURL(t *testing.T) {
+	cases := map[string]bool{
+		"https://friend.example/shared/lists/abc_DEF-123":            true,
+		"http://friend.example:7777/mediastorm/shared/lists/abc123/": true,
+		"https://friend.example/shared/lists/":                       false,
+		"https://friend.example/api/users/u1/shares":                 false,
+		"ftp://friend.example/shared/lists/abc123":                   false,
+		"/shared/lists/abc123":                                       false,
+	}
+	for rawURL, want := range cases {
+		if got := IsListURL(rawURL); got != want {
+			t.Errorf("IsListURL(%q) = %v, want %v", rawURL, got, want)
+		}
+	}
+}
+
+func TestClientGetListItems(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/mediastorm/shared/lists/tok123" || r.URL.Query().Get("format") != "json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"title":"Comfort Films","sharedBy":"Sam","items":[
+			{"name":"The Matrix","mediaType":"movie","year":1999,"externalIds":{"imdb":"tt0133093","tmdb":"603"}},
+			{"name":"Severance","mediaType":"series","year":2022,"externalIds":{"tvdb":"371980"}},
+			{"name":"","mediaType":"movie"}
+		]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	items, err := client.GetListItems(context.Background(), server.URL+"/mediastorm/shared/lists/tok123")
+	if err != nil {
+		t.Fatalf("GetListItems: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %+v", items)
+	}
+	if items[0].IMDBID != "tt0133093" || items[0].TMDBID != 603 || items[0].MediaType != "movie" {
+		t.Fatalf("unexpected movie %+v", items[0])
+	}
+	if items[1].TVDBID != 371980 || items[1].MediaType != "series" {
+		t.Fatalf("unexpected series %+v", items[1])
+	}
+
+	// A revoked link surfaces as an error so the subscriber keeps its cache.
+	if _, err := client.GetListItems(context.Background(), server.URL+"/shared/lists/revoked"); err == nil {
+		t.Fatal("expected an error for a revoked link")
+	}
+}
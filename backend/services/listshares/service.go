@@ -0,0 +1,289 @@
+// Package listshares manages public, read-only links to profiles' watchlists
+// and custom lists. A link stays valid until its owner revokes it.
+package listshares
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"novastream/internal/datastore"
+	"novastream/models"
+)
+
+var (
+	ErrStorageDirRequired = errors.New("storage directory not provided")
+	ErrUserIDRequired     = errors.New("user id is required")
+	ErrInvalidListType    = errors.New("list type must be watchlist or custom")
+	ErrListIDRequired     = errors.New("list id is required for custom lists")
+	ErrShareNotFound      = errors.New("share link not found")
+)
+
+// tokenLength is the number of random bytes in a share token.
+const tokenLength = 24
+
+// Service stores list sharing links.
+type Service struct {
+	mu     sync.RWMutex
+	path   string
+	store  *datastore.DataStore
+	shares map[string]models.ListShare // id -> share
+}
+
+// useDB returns true when the service is backed by PostgreSQL.
+func (s *Service) useDB() bool { return s.store != nil }
+
+// NewServiceWithStore creates a list shares service backed by PostgreSQL.
+func NewServiceWithStore(store *datastore.DataStore) (*Service, error) {
+	svc := &Service{
+		store:  store,
+		shares: make(map[string]models.ListShare),
+	}
+	if err := svc.load(); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+// NewService creates a list shares service storing data inside the provided directory.
+func NewService(storageDir string) (*Service, error) {
+	if strings.TrimSpace(storageDir) == "" {
+		return nil, ErrStorageDirRequired
+	}
+	if err := os.MkdirAll(storageDir, 0o755); err != nil {
+		return nil, fmt.Errorf("create list shares dir: %w", err)
+	}
+
+	svc := &Service{
+		path:   filepath.Join(storageDir, "list_shares.json"),
+		shares: make(map[string]models.ListShare),
+	}
+	if err := svc.load(); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+// Create generates a new sharing link for one of userID's lists. listID is
+// required for custom lists and ignored for the watchlist.
+func (s *Service) Create(userID, listType, listID, title string) (models.ListShare, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return models.ListShare{}, ErrUserIDRequired
+	}
+	listType = strings.ToLower(strings.TrimSpace(listType))
+	listID = strings.TrimSpace(listID)
+	switch listType {
+	case models.ShareListWatchlist:
+		listID = ""
+	case models.ShareListCustom:
+		if listID == "" {
+			return models.ListShare{}, ErrListIDRequired
+		}
+	default:
+		return models.ListShare{}, ErrInvalidListType
+	}
+
+	tokenBytes := make([]byte, tokenLength)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return models.ListShare{}, fmt.Errorf("generate token: %w", err)
+	}
+
+	share := models.ListShare{
+		ID:        uuid.NewString(),
+		Token:     base64.RawURLEncoding.EncodeToString(tokenBytes),
+		UserID:    userID,
+		ListType:  listType,
+		ListID:    listID,
+		Title:     strings.TrimSpace(title),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.shares[share.ID] = share
+	if err := s.saveLocked(); err != nil {
+		delete(s.shares, share.ID)
+		return models.ListShare{}, err
+	}
+	return share, nil
+}
+
+// GetByToken finds the share link with the given token.
+func (s *Service) GetByToken(token string) (models.ListShare, error) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return models.ListShare{}, ErrShareNotFound
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, share := range s.shares {
+		if share.Token == token {
+			return share, nil
+		}
+	}
+	return models.ListShare{}, ErrShareNotFound
+}
+
+// ListByUser returns userID's share links, newest first.
+func (s *Service) ListByUser(userID string) []models.ListShare {
+	userID = strings.TrimSpace(userID)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	shares := make([]models.ListShare, 0)
+	for _, share := range s.shares {
+		if share.UserID == userID {
+			shares = append(shares, share)
+		}
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		return shares[i].CreatedAt.After(shares[j].CreatedAt)
+	})
+	return shares
+}
+
+// Revoke deletes one of userID's share links; the link stops working
+// immediately.
+func (s *Service) Revoke(userID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	share, ok := s.shares[strings.TrimSpace(id)]
+	if !ok || share.UserID != strings.TrimSpace(userID) {
+		return ErrShareNotFound
+	}
+	delete(s.shares, share.ID)
+	if err := s.saveLocked(); err != nil {
+		s.shares[share.ID] = share
+		return err
+	}
+	return nil
+}
+
+func (s *Service) load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.useDB() {
+		list, err := s.store.ListShares().List(context.Background())
+		if err != nil {
+			return fmt.Errorf("load list shares from db: %w", err)
+		}
+		s.shares = make(map[string]models.ListShare, len(list))
+		for _, share := range list {
+			s.shares[share.ID] = share
+		}
+		return nil
+	}
+
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open list shares file: %w", err)
+	}
+	defer file.Close()
+
+	var stored []models.ListShare
+	if err := json.NewDecoder(file).Decode(&stored); err != nil {
+		return fmt.Errorf("decode list shares: %w", err)
+	}
+
+	s.shares = make(map[string]models.ListShare, len(stored))
+	for _, share := range stored {
+		if strings.TrimSpace(share.ID) == "" || strings.TrimSpace(share.Token) == "" {
+			continue
+		}
+		s.shares[share.ID] = share
+	}
+	return nil
+}
+
+func (s *Service) saveLocked() error {
+	if s.useDB() {
+		return s.syncToDB()
+	}
+
+	shares := make([]models.ListShare, 0, len(s.shares))
+	for _, share := range s.shares {
+		shares = append(shares, share)
+	}
+	sort.Slice(shares, func(i, j int) bool {
+		return shares[i].CreatedAt.Before(shares[j].CreatedAt)
+	})
+
+	tmp := s.path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("create list shares temp file: %w", err)
+	}
+
+	enc := json.NewEncoder(file)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(shares); err != nil {
+		file.Close()
+		_ = os.Remove(tmp)
+		return fmt.Errorf("encode list shares: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		_ = os.Remove(tmp)
+		return fmt.Errorf("sync list shares: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("close list shares temp file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("replace list shares file: %w", err)
+	}
+	return nil
+}
+
+// syncToDB writes the full in-memory share state to PostgreSQL. Links are
+// never edited, only created and revoked.
+func (s *Service) syncToDB() error {
+	ctx := context.Background()
+	return s.store.WithTx(ctx, func(tx *datastore.Tx) error {
+		existing, err := tx.ListShares().List(ctx)
+		if err != nil {
+			return err
+		}
+		dbIDs := make(map[string]bool, len(existing))
+		for _, share := range existing {
+			dbIDs[share.ID] = true
+		}
+		for _, share := range s.shares {
+			share := share
+			if !dbIDs[share.ID] {
+				if err := tx.ListShares().Create(ctx, &share); err != nil {
+					return err
+				}
+			}
+			delete(dbIDs, share.ID)
+		}
+		for id := range dbIDs {
+			if err := tx.ListShares().Delete(ctx, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,77 @@
+package listshares
+
+import (
+	"errors"
+	"testing"
+
+	"novastream/models"
+)
+
+func TestCreateAndLookup(t *testing.T) {
+	dir := t.TempDir()
+	svc, err := NewService(dir)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	share, err := svc.Create("user-1", "Watchlist", "ignored", "My picks")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if share.Token == "" || share.ListType != models.ShareListWatchlist || share.ListID != "" {
+		t.Fatalf("unexpected share %+v", share)
+	}
+
+	// Links survive a restart.
+	reloaded, err := NewService(dir)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	got, err := reloaded.GetByToken(share.Token)
+	if err != nil || got.ID != share.ID || got.Title != "My picks" {
+		t.Fatalf("GetByToken = %+v, %v", got, err)
+	}
+	if shares := reloaded.ListByUser("user-1"); len(shares) != 1 {
+		t.Fatalf("expected 1 share, got %d", len(shares))
+	}
+	if shares := reloaded.ListByUser("user-2"); len(shares) != 0 {
+		t.Fatalf("expected no shares for another profile, got %d", len(shares))
+	}
+}
+
+func TestCreateValidatesList(t *testing.T) {
+	svc, err := NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	if _, err := svc.Create("user-1", "custom", "", ""); !errors.Is(err, ErrListIDRequired) {
+		t.Fatalf("expected ErrListIDRequired, got %v", err)
+	}
+	if _, err := svc.Create("user-1", "history", "", ""); !errors.Is(err, ErrInvalidListType) {
+		t.Fatalf("expected ErrInvalidListType, got %v", err)
+	}
+	if _, err := svc.Create("", "watchlist", "", ""); !errors.Is(err, ErrUserIDRequired) {
+		t.Fatalf("expected ErrUserIDRequired, got %v", err)
+	}
+}
+
+func TestRevoke(t *testing.T) {
+	svc, err := NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	share, err := svc.Create("user-1", "custom", "list-1", "")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	if err := svc.Revoke("user-2", share.ID); !errors.Is(err, ErrShareNotFound) {
+		t.Fatalf("expected another profile's revoke to fail, got %v", err)
+	}
+	if err := svc.Revoke("user-1", share.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, err := svc.GetByToken(share.Token); !errors.Is(err, ErrShareNotFound) {
+		t.Fatalf("expected revoked link to be gone, got %v", err)
+	}
+}
@@ -685,7 +685,7 @@ func filterLocalMediaGroupsByRating(groups []models.LocalMediaItemGroup, maxMovi
 			result = append(result, group)
 			continue
 		}
-		if kids.IsRatingAllowed(group.Certification, maxRating, mediaType) {
+		if kids.IsRatingAllowedForCountry(group.Certification, group.CertificationCountry, maxRating, mediaType) {
 			result = append(result, group)
 		}
 	}
@@ -735,6 +735,7 @@ func updateLocalMediaGroupSummary(group *models.LocalMediaItemGroup, item models
 	}
 	if group.Certification == "" && item.Metadata != nil && strings.TrimSpace(item.Metadata.Certification) != "" {
 		group.Certification = strings.TrimSpace(item.Metadata.Certification)
+		group.CertificationCountry = item.Metadata.CertificationCountry
 	}
 	if group.Year == 0 {
 		group.Year = localMediaResolvedYear(item)
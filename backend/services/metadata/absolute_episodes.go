@@ -0,0 +1,112 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"novastream/models"
+)
+
+// backfillAbsoluteEpisodeNumbers fills in absolute episode numbers that TVDB
+// left out, which breaks absolute-numbered release matching for anime and
+// long-running shows. Numbers are taken from the series' TMDB absolute episode
+// group when tmdbID is set, matching episodes by season/episode and falling
+// back to a unique air date. Remaining gaps are counted from the ordered
+// episode list, but only when the series already has some absolute numbers to
+// anchor the count; shows with no absolute numbering are left alone.
+func (s *Service) backfillAbsoluteEpisodeNumbers(ctx context.Context, details *models.SeriesDetails, tmdbID int64) {
+	if details == nil {
+		return
+	}
+	missing, anchored := 0, false
+	for i := range details.Seasons {
+		season := &details.Seasons[i]
+		if season.Number <= 0 {
+			continue
+		}
+		for j := range season.Episodes {
+			ep := &season.Episodes[j]
+			if ep.AbsoluteEpisodeNumber > 0 {
+				if ep.AbsoluteNumberConfidence == "" {
+					ep.AbsoluteNumberConfidence = models.AbsoluteNumberConfidenceHigh
+				}
+				anchored = true
+			} else {
+				missing++
+			}
+		}
+	}
+	if missing == 0 {
+		return
+	}
+
+	if tmdbID > 0 && s.tmdb != nil && s.tmdb.isConfigured() {
+		order, err := s.tmdb.fetchAbsoluteEpisodeOrder(ctx, tmdbID)
+		if err != nil {
+			log.Printf("[metadata] absolute episode order unavailable tmdbId=%d: %v", tmdbID, err)
+		} else if filled := applyTMDBAbsoluteOrder(details.Seasons, order); filled > 0 {
+			log.Printf("[metadata] backfilled %d absolute episode numbers from TMDB tmdbId=%d", filled, tmdbID)
+			missing -= filled
+			anchored = true
+		}
+	}
+
+	if missing > 0 && anchored {
+		details.Seasons, _ = withAbsoluteEpisodeNumbers(details.Seasons)
+	}
+}
+
+// applyTMDBAbsoluteOrder sets missing absolute numbers from a TMDB absolute
+// episode group and returns how many episodes were filled.
+func applyTMDBAbsoluteOrder(seasons []models.SeriesSeason, order []tmdbAbsoluteEpisode) int {
+	if len(order) == 0 {
+		return 0
+	}
+	byEpisode := make(map[string]tmdbAbsoluteEpisode, len(order))
+	byAirDate := make(map[string]tmdbAbsoluteEpisode, len(order))
+	sharedAirDates := make(map[string]bool)
+	for _, entry := range order {
+		byEpisode[fmt.Sprintf("%d:%d", entry.Season, entry.Episode)] = entry
+		if entry.AirDate == "" {
+			continue
+		}
+		if _, dup := byAirDate[entry.AirDate]; dup {
+			sharedAirDates[entry.AirDate] = true
+		}
+		byAirDate[entry.AirDate] = entry
+	}
+
+	filled := 0
+	for i := range seasons {
+		if seasons[i].Number <= 0 {
+			continue
+		}
+		for j := range seasons[i].Episodes {
+			ep := &seasons[i].Episodes[j]
+			if ep.AbsoluteEpisodeNumber > 0 {
+				continue
+			}
+			airDate := ep.AiredDate
+			if len(airDate) > 10 {
+				airDate = airDate[:10]
+			}
+			entry, ok := byEpisode[fmt.Sprintf("%d:%d", ep.SeasonNumber, ep.EpisodeNumber)]
+			// TMDB and TVDB often split seasons differently; only trust a
+			// season/episode match when the air dates agree.
+			if ok && airDate != "" && entry.AirDate != "" && entry.AirDate != airDate {
+				ok = false
+			}
+			if !ok && airDate != "" && !sharedAirDates[airDate] {
+				entry, ok = byAirDate[airDate]
+			}
+			if !ok {
+				continue
+			}
+			ep.AbsoluteEpisodeNumber = entry.Absolute
+			ep.AbsoluteNumberConfidence = models.AbsoluteNumberConfidenceMedium
+			filled++
+		}
+	}
+	return filled
+}
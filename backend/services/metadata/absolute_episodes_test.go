@@ -0,0 +1,95 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"novastream/models"
+)
+
+func TestBackfillAbsoluteEpisodeNumbersFromTMDBGroup(t *testing.T) {
+	var requests []string
+	httpc := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			requests = append(requests, req.URL.Path)
+			var body string
+			switch req.URL.Path {
+			case "/3/tv/37854/episode_groups":
+				body = `{"results":[{"id":"dvd","type":3,"episode_count":10},{"id":"abs","type":2,"episode_count":4}]}`
+			case "/3/tv/episode_group/abs":
+				body = `{"groups":[
+					{"order":1,"episodes":[
+						{"season_number":1,"episode_number":4,"order":1,"air_date":"2020-01-22"},
+						{"season_number":1,"episode_number":3,"order":0,"air_date":"2020-01-15"}]},
+					{"order":0,"episodes":[
+						{"season_number":0,"episode_number":1,"order":0,"air_date":"2019-12-25"},
+						{"season_number":1,"episode_number":1,"order":1,"air_date":"2020-01-01"},
+						{"season_number":1,"episode_number":2,"order":2,"air_date":"2020-01-08"}]}]}`
+			default:
+				return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(bytes.NewBufferString(`{}`)), Header: make(http.Header)}, nil
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(body)), Header: make(http.Header)}, nil
+		}),
+	}
+	svc := &Service{tmdb: newTMDBClient("tmdb-key", "en", httpc, newFileCache(t.TempDir(), 24))}
+	svc.tmdb.limiter = nil
+
+	// TVDB splits TMDB's single season in two; episode 3 only matches by air date.
+	details := &models.SeriesDetails{Seasons: []models.SeriesSeason{
+		{Number: 0, Episodes: []models.SeriesEpisode{{SeasonNumber: 0, EpisodeNumber: 1, AiredDate: "2019-12-25"}}},
+		{Number: 1, Episodes: []models.SeriesEpisode{
+			{SeasonNumber: 1, EpisodeNumber: 1, AbsoluteEpisodeNumber: 1, AiredDate: "2020-01-01"},
+			{SeasonNumber: 1, EpisodeNumber: 2, AiredDate: "2020-01-08"},
+		}},
+		{Number: 2, Episodes: []models.SeriesEpisode{
+			{SeasonNumber: 2, EpisodeNumber: 1, AiredDate: "2020-01-15"},
+			{SeasonNumber: 2, EpisodeNumber: 2},
+		}},
+	}}
+
+	svc.backfillAbsoluteEpisodeNumbers(context.Background(), details, 37854)
+
+	want := map[[2]int]struct {
+		abs        int
+		confidence string
+	}{
+		{0, 1}: {0, ""},
+		{1, 1}: {1, models.AbsoluteNumberConfidenceHigh},
+		{1, 2}: {2, models.AbsoluteNumberConfidenceMedium},
+		{2, 1}: {3, models.AbsoluteNumberConfidenceMedium},
+		{2, 2}: {4, models.AbsoluteNumberConfidenceLow},
+	}
+	for _, season := range details.Seasons {
+		for _, ep := range season.Episodes {
+			w := want[[2]int{ep.SeasonNumber, ep.EpisodeNumber}]
+			if ep.AbsoluteEpisodeNumber != w.abs || ep.AbsoluteNumberConfidence != w.confidence {
+				t.Errorf("S%02dE%02d = %d/%q, want %d/%q", ep.SeasonNumber, ep.EpisodeNumber,
+					ep.AbsoluteEpisodeNumber, ep.AbsoluteNumberConfidence, w.abs, w.confidence)
+			}
+		}
+	}
+
+	// The absolute order is cached, including for the next series fetch.
+	before := len(requests)
+	if _, err := svc.tmdb.fetchAbsoluteEpisodeOrder(context.Background(), 37854); err != nil {
+		t.Fatalf("fetchAbsoluteEpisodeOrder: %v", err)
+	}
+	if len(requests) != before {
+		t.Fatalf("expected cached absolute order, got requests %v", requests[before:])
+	}
+}
+
+func TestBackfillAbsoluteEpisodeNumbersLeavesUnnumberedSeries(t *testing.T) {
+	details := &models.SeriesDetails{Seasons: []models.SeriesSeason{
+		{Number: 1, Episodes: []models.SeriesEpisode{{SeasonNumber: 1, EpisodeNumber: 1}, {SeasonNumber: 1, EpisodeNumber: 2}}},
+	}}
+	(&Service{}).backfillAbsoluteEpisodeNumbers(context.Background(), details, 0)
+	for _, ep := range details.Seasons[0].Episodes {
+		if ep.AbsoluteEpisodeNumber != 0 || ep.AbsoluteNumberConfidence != "" {
+			t.Fatalf("expected series without absolute numbering to be left alone, got %+v", ep)
+		}
+	}
+}
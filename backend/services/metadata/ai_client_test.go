@@ -3,8 +3,11 @@ package metadata
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -98,3 +101,167 @@ func TestAIClientAnthropicProviderUsesMessagesAPI(t *testing.T) {
 		t.Fatalf("unexpected recommendations: %+v", recs)
 	}
 }
+
+func TestAIClientOllamaProviderUsesLocalChatAPI(t *testing.T) {
+	var gotPath, gotAuth string
+	var gotReq ollamaChatRequest
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("Authorization")
+		if err := json.NewDecoder(r.Body).Decode(&gotReq); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"message":{"role":"assistant","content":"[{\"title\":\"Primer\",\"year\":2004,\"mediaType\":\"movie\"}]"},"done":true}`))
+	}))
+	defer server.Close()
+
+	client := newAIClient(AIConfig{Provider: "ollama", BaseURL: server.URL}, server.Client(), nil)
+	if !client.isConfigured() {
+		t.Fatal("ollama without an API key should be configured")
+	}
+
+	recs, err := client.getCustomRecommendations(context.Background(), "low budget time travel")
+	if err != nil {
+		t.Fatalf("getCustomRecommendations error: %v", err)
+	}
+	if gotPath != "/api/chat" {
+		t.Fatalf("path = %q, want /api/chat", gotPath)
+	}
+	if gotAuth != "" {
+		t.Fatalf("Authorization = %q, want none", gotAuth)
+	}
+	if gotReq.Model != "llama3.1" || gotReq.Stream || len(gotReq.Messages) != 2 || gotReq.Messages[0].Role != "system" {
+		t.Fatalf("unexpected request %+v", gotReq)
+	}
+	if len(recs) != 1 || recs[0].Title != "Primer" {
+		t.Fatalf("unexpected recommendations: %+v", recs)
+	}
+}
+
+func TestNewLLMProviderRequiresCredentials(t *testing.T) {
+	tests := []struct {
+		cfg  AIConfig
+		want string // provider name, or "" for none
+	}{
+		{AIConfig{}, ""},
+		{AIConfig{APIKey: "key"}, "Gemini"},
+		{AIConfig{Provider: "openai"}, ""},
+		{AIConfig{Provider: "gpt", APIKey: "key"}, "OpenAI"},
+		{AIConfig{Provider: "ollama"}, "Ollama"},
+		{AIConfig{Provider: "custom"}, ""},
+		{AIConfig{Provider: "openai-compatible", BaseURL: "http://localhost:1234/v1"}, "OpenAI-compatible"},
+	}
+	for _, tt := range tests {
+		provider := newLLMProvider(tt.cfg, http.DefaultClient)
+		got := ""
+		if provider != nil {
+			got = provider.Name()
+		}
+		if got != tt.want {
+			t.Errorf("newLLMProvider(%+v) = %q, want %q", tt.cfg, got, tt.want)
+		}
+	}
+}
+
+func TestAIClientCustomEndpointOmitsAuthWithoutKey(t *testing.T) {
+	var gotAuth string
+	gotAuthSet := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth, gotAuthSet = r.Header.Get("Authorization"), r.Header["Authorization"] != nil
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"[]"}}]}`))
+	}))
+	defer server.Close()
+
+	client := newAIClient(AIConfig{Provider: "openai-compatible", Model: "local-model", BaseURL: server.URL + "/"}, server.Client(), nil)
+	if _, err := client.getCustomRecommendations(context.Background(), "anything"); err != nil {
+		t.Fatalf("getCustomRecommendations error: %v", err)
+	}
+	if gotAuthSet {
+		t.Fatalf("Authorization = %q, want none for a keyless endpoint", gotAuth)
+	}
+	if client.modelName() != "local-model" {
+		t.Fatalf("model = %q, want local-model", client.modelName())
+	}
+}
+
+func TestGetAIRecommendationsExplainsEachItem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		content := `[{"title":"Arrival","year":2016,"mediaType":"movie","becauseOf":"interstellar","reason":"  Quiet, cerebral\nfirst contact. "},` +
+			`{"title":"Primer","year":2004,"mediaType":"movie","becauseOf":"Tenet 2","reason":"Time travel done on a shoestring."}]`
+		raw, _ := json.Marshal(content)
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":` + string(raw) + `}}]}`))
+	}))
+	defer server.Close()
+
+	tmdbHTTP := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		body := `{"results":[]}`
+		switch req.URL.Query().Get("query") {
+		case "Arrival":
+			body = `{"results":[{"id":329865,"title":"Arrival","release_date":"2016-11-10"}]}`
+		case "Primer":
+			body = `{"results":[{"id":14337,"title":"Primer","release_date":"2004-10-08"}]}`
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+	})}
+	dir := t.TempDir()
+	svc := &Service{
+		client:  newTVDBClient("test-tvdb-key", "eng", tmdbHTTP, 24),
+		tmdb:    newTMDBClient("test-tmdb-key", "eng", tmdbHTTP, newFileCache(dir, 24)),
+		cache:   newFileCache(dir, 24),
+		idCache: newFileCache(filepath.Join(dir, "ids"), 24),
+		ai:      newAIClient(AIConfig{Provider: "openai", APIKey: "key", BaseURL: server.URL}, server.Client(), nil),
+	}
+
+	items, err := svc.GetAIRecommendations(context.Background(), []string{"Interstellar", "Dark"}, []string{"movie", "series"}, "user-1", true)
+	if err != nil {
+		t.Fatalf("GetAIRecommendations error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %+v", items)
+	}
+	if items[0].Reason != "Quiet, cerebral first contact." || items[0].BecauseYouWatched != "Interstellar" {
+		t.Fatalf("unexpected explanation %+v", items[0])
+	}
+	if items[1].Reason == "" || items[1].BecauseYouWatched != "" {
+		t.Fatalf("unwatched source title should be dropped: %+v", items[1])
+	}
+}
+
+func TestAIRecommendationsPromptAsksForReasonsOnlyWhenExplaining(t *testing.T) {
+	var prompts []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		prompts = append(prompts, string(body))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"choices":[{"message":{"content":"[]"}}]}`))
+	}))
+	defer server.Close()
+	client := newAIClient(AIConfig{Provider: "openai", APIKey: "key", BaseURL: server.URL}, server.Client(), nil)
+
+	for _, explain := range []bool{false, true} {
+		if _, err := client.getRecommendations(context.Background(), []string{"Interstellar"}, []string{"movie"}, explain); err != nil {
+			t.Fatalf("getRecommendations(explain=%t) error: %v", explain, err)
+		}
+	}
+	if len(prompts) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(prompts))
+	}
+	if strings.Contains(prompts[0], "becauseOf") || strings.Contains(prompts[0], "reason") {
+		t.Fatalf("expected no reasons to be requested:\n%s", prompts[0])
+	}
+	if !strings.Contains(prompts[1], "becauseOf") || !strings.Contains(prompts[1], "reason") {
+		t.Fatalf("expected reasons to be requested:\n%s", prompts[1])
+	}
+}
+
+func TestAIRecommendationReasonTruncatesLongReasons(t *testing.T) {
+	long := strings.Repeat("a very long reason ", 20)
+	got := aiRecommendationReason(long)
+	if n := len([]rune(got)); n > aiRecommendationReasonMax+1 || !strings.HasSuffix(got, "…") || strings.HasSuffix(got, " …") {
+		t.Fatalf("aiRecommendationReason = %q (%d runes)", got, n)
+	}
+}
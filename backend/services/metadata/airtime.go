@@ -2,8 +2,10 @@ package metadata
 
 import (
 	"novastream/models"
+	"novastream/services/calendar"
 	"sort"
 	"strings"
+	"time"
 )
 
 // networkTimezoneMap maps known network names to IANA timezones.
@@ -183,3 +185,57 @@ func applyAirTimeFromTVDB(title *models.Title, airsTime string, networkName stri
 		}
 	}
 }
+
+// computeNextEpisode returns the earliest regular-season episode that airs
+// after now, or nil when the series has nothing scheduled. Specials (season 0)
+// are skipped because they rarely follow the regular release cadence.
+func computeNextEpisode(details *models.SeriesDetails, now time.Time) *models.NextEpisode {
+	if details == nil {
+		return nil
+	}
+	var (
+		next   *models.SeriesEpisode
+		nextAt time.Time
+	)
+	for i := range details.Seasons {
+		if details.Seasons[i].Number <= 0 {
+			continue
+		}
+		for j := range details.Seasons[i].Episodes {
+			ep := &details.Seasons[i].Episodes[j]
+			airAt := episodeAirTime(ep, details.Title.AirsTime, details.Title.AirsTimezone)
+			if airAt.IsZero() || !airAt.After(now) {
+				continue
+			}
+			if next == nil || airAt.Before(nextAt) {
+				next = ep
+				nextAt = airAt
+			}
+		}
+	}
+	if next == nil {
+		return nil
+	}
+	return &models.NextEpisode{
+		SeasonNumber:     next.SeasonNumber,
+		EpisodeNumber:    next.EpisodeNumber,
+		Name:             next.Name,
+		AiredDateTimeUTC: nextAt.UTC().Format(time.RFC3339),
+		CountdownSeconds: int64(nextAt.Sub(now) / time.Second),
+	}
+}
+
+// episodeAirTime resolves the UTC air time of an episode, preferring the
+// precomputed AiredDateTimeUTC and falling back to the air date plus the
+// series air time.
+func episodeAirTime(ep *models.SeriesEpisode, airsTime, airsTimezone string) time.Time {
+	if ts := strings.TrimSpace(ep.AiredDateTimeUTC); ts != "" {
+		if parsed, err := time.Parse(time.RFC3339, ts); err == nil {
+			return parsed
+		}
+	}
+	if strings.TrimSpace(ep.AiredDate) == "" {
+		return time.Time{}
+	}
+	return calendar.ParseAirDateTime(ep.AiredDate, airsTime, airsTimezone)
+}
@@ -3,6 +3,7 @@ package metadata
 import (
 	"novastream/models"
 	"testing"
+	"time"
 )
 
 func TestInferTimezoneFromNetwork(t *testing.T) {
@@ -76,3 +77,51 @@ func TestApplyAirTimeFromTVDB_NoTime(t *testing.T) {
 		t.Errorf("AirsTimezone should be empty when no air time, got %q", title.AirsTimezone)
 	}
 }
+
+func TestComputeNextEpisode(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	details := &models.SeriesDetails{
+		Title: models.Title{AirsTime: "21:00", AirsTimezone: "America/New_York"},
+		Seasons: []models.SeriesSeason{
+			{Number: 0, Episodes: []models.SeriesEpisode{
+				{SeasonNumber: 0, EpisodeNumber: 1, Name: "Special", AiredDate: "2025-03-11"},
+			}},
+			{Number: 2, Episodes: []models.SeriesEpisode{
+				{SeasonNumber: 2, EpisodeNumber: 4, Name: "Aired", AiredDateTimeUTC: "2025-03-03T01:00:00Z"},
+				{SeasonNumber: 2, EpisodeNumber: 6, Name: "Later", AiredDate: "2025-03-24"},
+				{SeasonNumber: 2, EpisodeNumber: 5, Name: "Next", AiredDate: "2025-03-17"},
+			}},
+		},
+	}
+
+	next := computeNextEpisode(details, now)
+	if next == nil {
+		t.Fatal("expected a next episode")
+	}
+	if next.SeasonNumber != 2 || next.EpisodeNumber != 5 || next.Name != "Next" {
+		t.Fatalf("next = S%02dE%02d %q, want S02E05 \"Next\"", next.SeasonNumber, next.EpisodeNumber, next.Name)
+	}
+	// 21:00 EDT on 2025-03-17 is 01:00 UTC on 2025-03-18.
+	if next.AiredDateTimeUTC != "2025-03-18T01:00:00Z" {
+		t.Errorf("AiredDateTimeUTC = %q, want %q", next.AiredDateTimeUTC, "2025-03-18T01:00:00Z")
+	}
+	wantCountdown := int64(time.Date(2025, 3, 18, 1, 0, 0, 0, time.UTC).Sub(now) / time.Second)
+	if next.CountdownSeconds != wantCountdown {
+		t.Errorf("CountdownSeconds = %d, want %d", next.CountdownSeconds, wantCountdown)
+	}
+}
+
+func TestComputeNextEpisode_NothingScheduled(t *testing.T) {
+	now := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	details := &models.SeriesDetails{
+		Seasons: []models.SeriesSeason{
+			{Number: 1, Episodes: []models.SeriesEpisode{
+				{SeasonNumber: 1, EpisodeNumber: 1, AiredDate: "2024-01-01"},
+				{SeasonNumber: 1, EpisodeNumber: 2},
+			}},
+		},
+	}
+	if next := computeNextEpisode(details, now); next != nil {
+		t.Fatalf("expected nil next episode, got %+v", next)
+	}
+}
@@ -0,0 +1,258 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"novastream/models"
+)
+
+// tmdbListMaxPages caps how much of a curated TMDB list is read; allow-lists
+// are hand-picked and rarely run past a few pages.
+const tmdbListMaxPages = 10
+
+var tmdbListURLPattern = regexp.MustCompile(`themoviedb\.org/list/(\d+)`)
+
+// AllowList restricts a profile to titles in one of Genres or on one of the
+// curated Lists. A title passing either check is allowed.
+type AllowList struct {
+	Genres []string // TMDB genre names, compared case-insensitively
+	Lists  []string // MDBList list URLs, TMDB list URLs or "tmdb:list:<id>"
+}
+
+// ParseTMDBListRef returns the TMDB list ID of a themoviedb.org list URL or a
+// "tmdb:list:<id>" reference.
+func ParseTMDBListRef(ref string) (string, bool) {
+	ref = strings.TrimSpace(ref)
+	if id, ok := strings.CutPrefix(ref, "tmdb:list:"); ok {
+		if _, err := strconv.ParseInt(id, 10, 64); err == nil {
+			return id, true
+		}
+		return "", false
+	}
+	if m := tmdbListURLPattern.FindStringSubmatch(ref); m != nil {
+		return m[1], true
+	}
+	return "", false
+}
+
+// allowListIndex is the resolved form of an AllowList.
+type allowListIndex struct {
+	genres map[string]bool
+	titles map[string]bool
+}
+
+func (idx allowListIndex) allows(title models.Title) bool {
+	for _, genre := range title.Genres {
+		if idx.genres[strings.ToLower(strings.TrimSpace(genre))] {
+			return true
+		}
+	}
+	for _, key := range allowListTitleKeys(title) {
+		if idx.titles[key] {
+			return true
+		}
+	}
+	return false
+}
+
+func (idx allowListIndex) add(title models.Title) {
+	for _, key := range allowListTitleKeys(title) {
+		idx.titles[key] = true
+	}
+}
+
+// allowListTitleKeys returns the IDs a title can be matched on across list
+// sources, which do not agree on a single ID.
+func allowListTitleKeys(title models.Title) []string {
+	mediaType := "series"
+	if strings.EqualFold(title.MediaType, "movie") {
+		mediaType = "movie"
+	}
+	var keys []string
+	if title.TMDBID > 0 {
+		keys = append(keys, fmt.Sprintf("tmdb:%s:%d", mediaType, title.TMDBID))
+	}
+	if title.TVDBID > 0 {
+		keys = append(keys, fmt.Sprintf("tvdb:%s:%d", mediaType, title.TVDBID))
+	}
+	if imdbID := strings.TrimSpace(title.IMDBID); imdbID != "" {
+		keys = append(keys, "imdb:"+strings.ToLower(imdbID))
+	}
+	return keys
+}
+
+// resolveAllowList fetches the curated lists of an allow-list. Lists that fail
+// to load are skipped, so an outage narrows the allow-list rather than
+// widening it.
+func (s *Service) resolveAllowList(ctx context.Context, allow AllowList) allowListIndex {
+	idx := allowListIndex{genres: make(map[string]bool), titles: make(map[string]bool)}
+	for _, genre := range allow.Genres {
+		if genre = strings.ToLower(strings.TrimSpace(genre)); genre != "" {
+			idx.genres[genre] = true
+		}
+	}
+	for _, ref := range allow.Lists {
+		ref = strings.TrimSpace(ref)
+		if ref == "" {
+			continue
+		}
+		if listID, ok := ParseTMDBListRef(ref); ok {
+			titles, err := s.tmdbListTitles(ctx, listID)
+			if err != nil {
+				log.Printf("[metadata] allow-list: tmdb list %s unavailable: %v", listID, err)
+				continue
+			}
+			for _, title := range titles {
+				idx.add(title)
+			}
+			continue
+		}
+		items, _, _, err := s.GetCustomList(ctx, ref, CustomListOptions{Lite: true, SuppressProgress: true})
+		if err != nil {
+			log.Printf("[metadata] allow-list: list %s unavailable: %v", ref, err)
+			continue
+		}
+		for _, item := range items {
+			idx.add(item.Title)
+		}
+	}
+	return idx
+}
+
+// tmdbListTitles returns the titles on a TMDB list, cached like other TMDB
+// lookups.
+func (s *Service) tmdbListTitles(ctx context.Context, listID string) ([]models.Title, error) {
+	cacheID := cacheKey("tmdb", "list", "v1", listID)
+	var cached []models.Title
+	if s.cache != nil {
+		if ok, _ := s.cache.get(cacheID, &cached); ok {
+			return cached, nil
+		}
+	}
+	if s.tmdb == nil {
+		return nil, errTMDBNotConfigured
+	}
+	titles, err := s.tmdb.fetchListTitles(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	if s.cache != nil {
+		_ = s.cache.set(cacheID, titles)
+	}
+	return titles, nil
+}
+
+// fetchListTitles reads a public TMDB list.
+func (c *tmdbClient) fetchListTitles(ctx context.Context, listID string) ([]models.Title, error) {
+	if !c.isConfigured() {
+		return nil, errTMDBNotConfigured
+	}
+	var titles []models.Title
+	for page := 1; page <= tmdbListMaxPages; page++ {
+		endpoint := fmt.Sprintf("%s/list/%s?api_key=%s&page=%d", tmdbBaseURL, listID, c.apiKey, page)
+		var payload struct {
+			Items []struct {
+				ID        int64  `json:"id"`
+				MediaType string `json:"media_type"`
+				Name      string `json:"name"`
+				Title     string `json:"title"`
+				GenreIDs  []int  `json:"genre_ids"`
+			} `json:"items"`
+			TotalPages int `json:"total_pages"`
+		}
+		if err := c.doGET(ctx, endpoint, &payload); err != nil {
+			return nil, fmt.Errorf("tmdb list %s failed: %w", listID, err)
+		}
+		for _, item := range payload.Items {
+			apiMediaType := strings.ToLower(item.MediaType)
+			mediaType := "movie"
+			switch apiMediaType {
+			case "tv":
+				mediaType = "series"
+			case "movie":
+			default:
+				continue
+			}
+			titles = append(titles, models.Title{
+				ID:        fmt.Sprintf("tmdb:%s:%d", apiMediaType, item.ID),
+				Name:      pickTMDBName(apiMediaType, item.Name, item.Title),
+				MediaType: mediaType,
+				TMDBID:    item.ID,
+				Genres:    resolveGenreIDs(item.GenreIDs, apiMediaType),
+			})
+		}
+		if page >= payload.TotalPages {
+			break
+		}
+	}
+	return titles, nil
+}
+
+// enrichMissingGenres fills in genres for titles that have none so they can be
+// matched against allowed genres.
+func (s *Service) enrichMissingGenres(ctx context.Context, titles []*models.Title) {
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, foregroundCustomListEnrichConcurrency)
+	for _, title := range titles {
+		if len(title.Genres) > 0 || title.TMDBID <= 0 {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			s.applyTMDBGenreFallback(ctx, title)
+		}()
+	}
+	wg.Wait()
+}
+
+// FilterTrendingByAllowList keeps the items that pass the allow-list.
+func (s *Service) FilterTrendingByAllowList(ctx context.Context, items []models.TrendingItem, allow AllowList) []models.TrendingItem {
+	idx := s.resolveAllowList(ctx, allow)
+	if len(idx.genres) > 0 {
+		titles := make([]*models.Title, len(items))
+		for i := range items {
+			titles[i] = &items[i].Title
+		}
+		s.enrichMissingGenres(ctx, titles)
+	}
+	result := make([]models.TrendingItem, 0, len(items))
+	for _, item := range items {
+		if idx.allows(item.Title) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// SearchWithAllowList searches like Search and drops results outside the
+// allow-list.
+func (s *Service) SearchWithAllowList(ctx context.Context, query, mediaType string, allow AllowList) ([]models.SearchResult, error) {
+	results, err := s.Search(ctx, query, mediaType)
+	if err != nil || len(results) == 0 {
+		return results, err
+	}
+	idx := s.resolveAllowList(ctx, allow)
+	if len(idx.genres) > 0 {
+		titles := make([]*models.Title, len(results))
+		for i := range results {
+			titles[i] = &results[i].Title
+		}
+		s.enrichMissingGenres(ctx, titles)
+	}
+	filtered := make([]models.SearchResult, 0, len(results))
+	for _, result := range results {
+		if idx.allows(result.Title) {
+			filtered = append(filtered, result)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,76 @@
+package metadata
+
+import (
+	"context"
+	"testing"
+
+	"novastream/models"
+)
+
+func TestParseTMDBListRef(t *testing.T) {
+	tests := []struct {
+		ref    string
+		want   string
+		wantOK bool
+	}{
+		{"https://www.themoviedb.org/list/8136-kids-picks", "8136", true},
+		{"tmdb:list:42", "42", true},
+		{"tmdb:list:abc", "", false},
+		{"https://mdblist.com/lists/someone/kids", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseTMDBListRef(tt.ref)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseTMDBListRef(%q) = (%q, %v), want (%q, %v)", tt.ref, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestFilterTrendingByAllowList(t *testing.T) {
+	svc := &Service{
+		client: &tvdbClient{language: "eng"},
+		cache:  newFileCache(t.TempDir(), 24),
+	}
+	// Seed the TMDB list so the test stays offline.
+	curated := []models.Title{{MediaType: "series", TMDBID: 100}, {MediaType: "movie", IMDBID: "tt0000200"}}
+	if err := svc.cache.set(cacheKey("tmdb", "list", "v1", "77"), curated); err != nil {
+		t.Fatal(err)
+	}
+
+	items := []models.TrendingItem{
+		{Title: models.Title{Name: "Cartoon", MediaType: "movie", Genres: []string{"Animation"}}},
+		{Title: models.Title{Name: "Thriller", MediaType: "movie", Genres: []string{"Thriller"}}},
+		{Title: models.Title{Name: "Listed Show", MediaType: "series", TMDBID: 100, Genres: []string{"Drama"}}},
+		{Title: models.Title{Name: "Listed Movie", MediaType: "movie", IMDBID: "TT0000200"}},
+		{Title: models.Title{Name: "Same ID Other Type", MediaType: "movie", TMDBID: 100, Genres: []string{"Crime"}}},
+	}
+	allow := AllowList{Genres: []string{"animation"}, Lists: []string{"https://www.themoviedb.org/list/77"}}
+
+	got := svc.FilterTrendingByAllowList(context.Background(), items, allow)
+	var names []string
+	for _, item := range got {
+		names = append(names, item.Title.Name)
+	}
+	want := []string{"Cartoon", "Listed Show", "Listed Movie"}
+	if len(names) != len(want) {
+		t.Fatalf("allowed = %v, want %v", names, want)
+	}
+	for i := range want {
+		if names[i] != want[i] {
+			t.Fatalf("allowed = %v, want %v", names, want)
+		}
+	}
+}
+
+func TestFilterTrendingByAllowListUnavailableListAllowsNothingExtra(t *testing.T) {
+	svc := &Service{
+		client: &tvdbClient{language: "eng"},
+		cache:  newFileCache(t.TempDir(), 24),
+	}
+	items := []models.TrendingItem{{Title: models.Title{Name: "Show", MediaType: "series", TMDBID: 5, Genres: []string{"Drama"}}}}
+
+	got := svc.FilterTrendingByAllowList(context.Background(), items, AllowList{Lists: []string{"tmdb:list:9"}})
+	if len(got) != 0 {
+		t.Fatalf("expected nothing allowed when the only list cannot load, got %+v", got)
+	}
+}
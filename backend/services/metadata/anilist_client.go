@@ -0,0 +1,467 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"novastream/models"
+)
+
+const anilistGraphQLURL = "https://graphql.anilist.co"
+
+// animeMappingURL is the community-maintained Fribb anime-lists mapping between
+// AniList, AniDB, MAL, TVDB and TMDB IDs. AniList itself has no TVDB/TMDB lookup.
+const animeMappingURL = "https://raw.githubusercontent.com/Fribb/anime-lists/master/anime-list-full.json"
+
+// animeMappingRefreshInterval is how long the downloaded ID mapping is used
+// before it is fetched again.
+const animeMappingRefreshInterval = 24 * time.Hour
+
+// Failed mapping downloads are retried after animeMappingRetryBase, doubling
+// with each consecutive failure up to animeMappingRetryMax.
+const (
+	animeMappingRetryBase = time.Minute
+	animeMappingRetryMax  = time.Hour
+)
+
+const anilistMediaQuery = `query ($id: Int) {
+  Media(id: $id, type: ANIME) {
+    id
+    idMal
+    episodes
+    synonyms
+    title { romaji english native }
+    coverImage { extraLarge large }
+    bannerImage
+  }
+}`
+
+// animeMappingEntry is one row of the Fribb mapping. IDs are decoded leniently
+// because the list mixes numbers and strings.
+type animeMappingEntry struct {
+	AniListID flexibleNumber `json:"anilist_id"`
+	AniDBID   flexibleNumber `json:"anidb_id"`
+	MALID     flexibleNumber `json:"mal_id"`
+	TVDBID    flexibleNumber `json:"thetvdb_id"`
+	TMDBID    flexibleNumber `json:"themoviedb_id"`
+	Type      string         `json:"type"`
+	Season    struct {
+		TVDB flexibleNumber `json:"tvdb"`
+	} `json:"season"`
+}
+
+// animeMapping is the compact form of the mapping kept in memory and cached.
+type animeMapping struct {
+	AniListID int   `json:"a"`
+	AniDBID   int   `json:"d,omitempty"`
+	MALID     int   `json:"m,omitempty"`
+	TVDBID    int64 `json:"tv,omitempty"`
+	TMDBID    int64 `json:"tm,omitempty"`
+	Season    int   `json:"s,omitempty"`
+}
+
+type anilistMedia struct {
+	ID       int      `json:"id"`
+	IDMal    int      `json:"idMal"`
+	Episodes int      `json:"episodes"`
+	Synonyms []string `json:"synonyms"`
+	Title    struct {
+		Romaji  string `json:"romaji"`
+		English string `json:"english"`
+		Native  string `json:"native"`
+	} `json:"title"`
+	CoverImage struct {
+		ExtraLarge string `json:"extraLarge"`
+		Large      string `json:"large"`
+	} `json:"coverImage"`
+	BannerImage string `json:"bannerImage"`
+}
+
+// anilistClient maps TVDB/TMDB series to AniList entries and fetches anime
+// metadata from the AniList GraphQL API.
+type anilistClient struct {
+	httpc      *http.Client
+	graphqlURL string
+	mappingURL string
+
+	mappingMu      sync.Mutex
+	byTVDB         map[int64][]animeMapping
+	byTMDB         map[int64][]animeMapping
+	mappingFetched time.Time
+	// Backoff after failed downloads; mappingErr is returned while no
+	// mapping has been loaded yet.
+	mappingFailures int
+	mappingRetryAt  time.Time
+	mappingErr      error
+
+	// Rate limiting: AniList allows ~90 requests per minute.
+	throttleMu  sync.Mutex
+	lastRequest time.Time
+	minInterval time.Duration
+}
+
+func newAniListClient(httpc *http.Client) *anilistClient {
+	if httpc == nil {
+		httpc = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &anilistClient{
+		httpc:       httpc,
+		graphqlURL:  anilistGraphQLURL,
+		mappingURL:  animeMappingURL,
+		minInterval: 700 * time.Millisecond,
+	}
+}
+
+// loadMappings installs the compact mapping and indexes it by TVDB and TMDB ID.
+func (c *anilistClient) loadMappings(mappings []animeMapping, fetched time.Time) {
+	byTVDB := make(map[int64][]animeMapping)
+	byTMDB := make(map[int64][]animeMapping)
+	for _, m := range mappings {
+		if m.TVDBID > 0 {
+			byTVDB[m.TVDBID] = append(byTVDB[m.TVDBID], m)
+		}
+		if m.TMDBID > 0 {
+			byTMDB[m.TMDBID] = append(byTMDB[m.TMDBID], m)
+		}
+	}
+	c.byTVDB = byTVDB
+	c.byTMDB = byTMDB
+	c.mappingFetched = fetched
+}
+
+// fetchMappings downloads the Fribb mapping and reduces it to TV entries that
+// reference AniList and at least one of TVDB or TMDB.
+func (c *anilistClient) fetchMappings(ctx context.Context) ([]animeMapping, error) {
+	if err := offlineError("anilist mapping"); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.mappingURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("anime mapping request failed: %s", resp.Status)
+	}
+
+	var entries []animeMappingEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("decode anime mapping: %w", err)
+	}
+	mappings := make([]animeMapping, 0, len(entries))
+	for _, e := range entries {
+		if e.AniListID.int() == 0 || (e.TVDBID.int64() == 0 && e.TMDBID.int64() == 0) {
+			continue
+		}
+		if e.Type != "" && !strings.EqualFold(e.Type, "TV") && !strings.EqualFold(e.Type, "ONA") {
+			continue
+		}
+		mappings = append(mappings, animeMapping{
+			AniListID: e.AniListID.int(),
+			AniDBID:   e.AniDBID.int(),
+			MALID:     e.MALID.int(),
+			TVDBID:    e.TVDBID.int64(),
+			TMDBID:    e.TMDBID.int64(),
+			Season:    e.Season.TVDB.int(),
+		})
+	}
+	return mappings, nil
+}
+
+// lookup returns the AniList entry for a series. A series split into several
+// AniList entries (one per cour/season) resolves to its first season.
+func (c *anilistClient) lookup(tvdbID, tmdbID int64) (animeMapping, bool) {
+	var candidates []animeMapping
+	if tvdbID > 0 {
+		candidates = c.byTVDB[tvdbID]
+	}
+	if len(candidates) == 0 && tmdbID > 0 {
+		candidates = c.byTMDB[tmdbID]
+	}
+	if len(candidates) == 0 {
+		return animeMapping{}, false
+	}
+	best := candidates[0]
+	for _, m := range candidates[1:] {
+		if m.Season > 0 && (best.Season <= 0 || m.Season < best.Season) {
+			best = m
+		}
+	}
+	return best, true
+}
+
+// fetchMedia retrieves an anime by AniList ID.
+func (c *anilistClient) fetchMedia(ctx context.Context, id int) (*anilistMedia, error) {
+	if id <= 0 {
+		return nil, errors.New("anilist id is required")
+	}
+	payload, err := json.Marshal(map[string]any{
+		"query":     anilistMediaQuery,
+		"variables": map[string]any{"id": id},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.throttleMu.Lock()
+	wait := c.minInterval - time.Since(c.lastRequest)
+	if wait > 0 {
+		c.lastRequest = time.Now().Add(wait)
+	} else {
+		c.lastRequest = time.Now()
+		wait = 0
+	}
+	c.throttleMu.Unlock()
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+
+	if err := offlineError("anilist"); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.graphqlURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("anilist request failed: %s", resp.Status)
+	}
+
+	var body struct {
+		Data struct {
+			Media *anilistMedia `json:"Media"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+	if len(body.Errors) > 0 {
+		return nil, fmt.Errorf("anilist: %s", body.Errors[0].Message)
+	}
+	if body.Data.Media == nil {
+		return nil, fmt.Errorf("anilist: media %d not found", id)
+	}
+	return body.Data.Media, nil
+}
+
+// ensureAnimeMapping loads the ID mapping, fetching it (through the cache)
+// when it has never been loaded or is older than animeMappingRefreshInterval.
+// A failed refresh keeps serving the previous mapping, and downloads are
+// retried with exponential backoff rather than on every lookup.
+func (s *Service) ensureAnimeMapping(ctx context.Context) error {
+	c := s.anilist
+	c.mappingMu.Lock()
+	defer c.mappingMu.Unlock()
+	if c.byTVDB != nil && time.Since(c.mappingFetched) < animeMappingRefreshInterval {
+		return nil
+	}
+	if time.Now().Before(c.mappingRetryAt) {
+		if c.byTVDB != nil {
+			return nil
+		}
+		return c.mappingErr
+	}
+
+	key := cacheKey("anilist", "mapping", "v1")
+	var cached struct {
+		Fetched  time.Time      `json:"fetched"`
+		Mappings []animeMapping `json:"mappings"`
+	}
+	if ok, _ := s.cache.getWithMaxAge(key, &cached, animeMappingRefreshInterval); ok && len(cached.Mappings) > 0 {
+		c.loadMappings(cached.Mappings, cached.Fetched)
+		return nil
+	}
+
+	mappings, err := c.fetchMappings(ctx)
+	if err != nil {
+		c.mappingFailures++
+		backoff := animeMappingRetryBackoff(c.mappingFailures)
+		c.mappingRetryAt = time.Now().Add(backoff)
+		c.mappingErr = err
+		if c.byTVDB != nil {
+			log.Printf("[anilist] mapping refresh failed, keeping previous mapping and retrying in %s: %v", backoff, err)
+			return nil
+		}
+		log.Printf("[anilist] mapping download failed, retrying in %s: %v", backoff, err)
+		return err
+	}
+	c.mappingFailures = 0
+	c.mappingRetryAt = time.Time{}
+	c.mappingErr = nil
+	cached.Fetched = time.Now()
+	cached.Mappings = mappings
+	_ = s.cache.set(key, cached)
+	c.loadMappings(mappings, cached.Fetched)
+	log.Printf("[anilist] loaded anime ID mapping (%d entries)", len(mappings))
+	return nil
+}
+
+// animeMappingRetryBackoff is the wait before retrying after the given
+// number of consecutive failed mapping downloads.
+func animeMappingRetryBackoff(failures int) time.Duration {
+	backoff := animeMappingRetryBase
+	for i := 1; i < failures && backoff < animeMappingRetryMax; i++ {
+		backoff *= 2
+	}
+	return min(backoff, animeMappingRetryMax)
+}
+
+// AnimeDetails returns AniList metadata for the series with the given TVDB or
+// TMDB ID, or nil when the series is not in the anime mapping.
+func (s *Service) AnimeDetails(ctx context.Context, tvdbID, tmdbID int64) (*models.AnimeDetails, error) {
+	if s.anilist == nil || (tvdbID <= 0 && tmdbID <= 0) {
+		return nil, nil
+	}
+	if err := s.ensureAnimeMapping(ctx); err != nil {
+		return nil, err
+	}
+	s.anilist.mappingMu.Lock()
+	mapping, ok := s.anilist.lookup(tvdbID, tmdbID)
+	s.anilist.mappingMu.Unlock()
+	if !ok {
+		return nil, nil
+	}
+
+	key := cacheKey("anilist", "media", "v1", strconv.Itoa(mapping.AniListID))
+	var media anilistMedia
+	if ok, _ := s.cache.get(key, &media); !ok {
+		value, err := s.singleflightCachedFetch(ctx, key, func() (any, error) {
+			fetched, err := s.anilist.fetchMedia(ctx, mapping.AniListID)
+			if err != nil {
+				return nil, err
+			}
+			_ = s.cache.set(key, fetched)
+			return fetched, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		fetched, _ := value.(*anilistMedia)
+		if fetched == nil {
+			return nil, nil
+		}
+		media = *fetched
+	}
+
+	details := &models.AnimeDetails{
+		AniListID:    media.ID,
+		AniDBID:      mapping.AniDBID,
+		MALID:        media.IDMal,
+		TitleRomaji:  media.Title.Romaji,
+		TitleEnglish: media.Title.English,
+		TitleNative:  media.Title.Native,
+		Synonyms:     media.Synonyms,
+		EpisodeCount: media.Episodes,
+	}
+	if details.MALID == 0 {
+		details.MALID = mapping.MALID
+	}
+	if poster := firstNonEmpty(media.CoverImage.ExtraLarge, media.CoverImage.Large); poster != "" {
+		details.Poster = &models.Image{URL: poster, Type: "poster"}
+	}
+	if media.BannerImage != "" {
+		details.Banner = &models.Image{URL: media.BannerImage, Type: "banner"}
+	}
+	return details, nil
+}
+
+// WithAnimeDetails returns a copy of details with AniList metadata attached and
+// absolute episode numbers filled in. Details are returned unchanged when the
+// series is not anime or AniList is unavailable.
+func (s *Service) WithAnimeDetails(ctx context.Context, details *models.SeriesDetails) *models.SeriesDetails {
+	if details == nil {
+		return nil
+	}
+	anime, err := s.AnimeDetails(ctx, details.Title.TVDBID, details.Title.TMDBID)
+	if err != nil {
+		log.Printf("[anilist] anime details unavailable for %q: %v", details.Title.Name, err)
+		return details
+	}
+	if anime == nil {
+		return details
+	}
+
+	out := *details
+	out.Seasons, anime.AbsoluteOrdering = withAbsoluteEpisodeNumbers(details.Seasons)
+	out.Anime = anime
+	return &out
+}
+
+// withAbsoluteEpisodeNumbers returns seasons whose regular episodes all carry an
+// absolute number. Numbers supplied by TVDB are kept; missing ones are derived
+// by counting regular episodes in season order and flagged low confidence.
+// Specials (season 0) are never numbered. Returns the original seasons when nothing had to be filled in.
+func withAbsoluteEpisodeNumbers(seasons []models.SeriesSeason) ([]models.SeriesSeason, bool) {
+	missing := false
+	regular := 0
+	for _, season := range seasons {
+		if season.Number <= 0 {
+			continue
+		}
+		for _, ep := range season.Episodes {
+			regular++
+			if ep.AbsoluteEpisodeNumber <= 0 {
+				missing = true
+			}
+		}
+	}
+	if regular == 0 {
+		return seasons, false
+	}
+	if !missing {
+		return seasons, true
+	}
+
+	ordered := make([]int, 0, len(seasons))
+	for i, season := range seasons {
+		if season.Number > 0 {
+			ordered = append(ordered, i)
+		}
+	}
+	sort.SliceStable(ordered, func(a, b int) bool {
+		return seasons[ordered[a]].Number < seasons[ordered[b]].Number
+	})
+
+	out := make([]models.SeriesSeason, len(seasons))
+	copy(out, seasons)
+	absolute := 0
+	for _, idx := range ordered {
+		episodes := make([]models.SeriesEpisode, len(seasons[idx].Episodes))
+		copy(episodes, seasons[idx].Episodes)
+		for i := range episodes {
+			absolute++
+			if episodes[i].AbsoluteEpisodeNumber <= 0 {
+				episodes[i].AbsoluteEpisodeNumber = absolute
+				episodes[i].AbsoluteNumberConfidence = models.AbsoluteNumberConfidenceLow
+			} else {
+				absolute = episodes[i].AbsoluteEpisodeNumber
+			}
+		}
+		out[idx].Episodes = episodes
+	}
+	return out, true
+}
@@ -0,0 +1,147 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"novastream/models"
+)
+
+const animeMappingFixture = `[
+	{"anilist_id": 21, "anidb_id": 69, "mal_id": 21, "thetvdb_id": 81797, "themoviedb_id": 37854, "type": "TV", "season": {"tvdb": 1}},
+	{"anilist_id": 30, "thetvdb_id": 81797, "type": "TV", "season": {"tvdb": 2}},
+	{"anilist_id": 99, "thetvdb_id": "81797", "type": "MOVIE"},
+	{"anilist_id": 5114, "mal_id": "5114", "themoviedb_id": 31911, "type": "TV"}
+]`
+
+const anilistMediaFixture = `{"data": {"Media": {
+	"id": 21,
+	"idMal": 21,
+	"episodes": 1100,
+	"synonyms": ["OP"],
+	"title": {"romaji": "ONE PIECE", "english": "One Piece", "native": "ワンピース"},
+	"coverImage": {"extraLarge": "https://s4.anilist.co/cover/21.jpg"},
+	"bannerImage": "https://s4.anilist.co/banner/21.jpg"
+}}}`
+
+func newAniListTestService(t *testing.T) (*Service, *int32) {
+	t.Helper()
+	var mediaCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/mapping.json":
+			w.Write([]byte(animeMappingFixture))
+		case "/graphql":
+			atomic.AddInt32(&mediaCalls, 1)
+			w.Write([]byte(anilistMediaFixture))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := newAniListClient(server.Client())
+	client.graphqlURL = server.URL + "/graphql"
+	client.mappingURL = server.URL + "/mapping.json"
+	client.minInterval = 0
+	return &Service{cache: newFileCache(t.TempDir(), 24), anilist: client}, &mediaCalls
+}
+
+func TestAnimeDetails(t *testing.T) {
+	svc, mediaCalls := newAniListTestService(t)
+	ctx := context.Background()
+
+	got, err := svc.AnimeDetails(ctx, 81797, 0)
+	if err != nil {
+		t.Fatalf("AnimeDetails: %v", err)
+	}
+	if got == nil || got.AniListID != 21 || got.AniDBID != 69 {
+		t.Fatalf("expected first-season AniList entry, got %+v", got)
+	}
+	if got.TitleRomaji != "ONE PIECE" || got.TitleNative != "ワンピース" || got.Poster == nil || got.Banner == nil {
+		t.Fatalf("unexpected anime details: %+v", got)
+	}
+
+	if _, err := svc.AnimeDetails(ctx, 81797, 0); err != nil {
+		t.Fatalf("AnimeDetails (cached): %v", err)
+	}
+	if calls := atomic.LoadInt32(mediaCalls); calls != 1 {
+		t.Fatalf("expected AniList media to be cached, got %d requests", calls)
+	}
+
+	if got, err := svc.AnimeDetails(ctx, 12345, 0); err != nil || got != nil {
+		t.Fatalf("expected nil for non-anime series, got %+v err=%v", got, err)
+	}
+}
+
+func TestEnsureAnimeMappingBacksOffAfterFailedDownloads(t *testing.T) {
+	var downloads int32
+	var failing atomic.Bool
+	failing.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&downloads, 1)
+		if failing.Load() {
+			http.Error(w, "unavailable", http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(animeMappingFixture))
+	}))
+	t.Cleanup(server.Close)
+	client := newAniListClient(server.Client())
+	client.mappingURL = server.URL
+	svc := &Service{cache: newFileCache(t.TempDir(), 24), anilist: client}
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := svc.ensureAnimeMapping(ctx); err == nil {
+			t.Fatalf("attempt %d: expected the download error while no mapping is loaded", i)
+		}
+	}
+	if got := atomic.LoadInt32(&downloads); got != 1 {
+		t.Fatalf("expected one download during the backoff, got %d", got)
+	}
+
+	failing.Store(false)
+	client.mappingRetryAt = time.Now().Add(-time.Second)
+	if err := svc.ensureAnimeMapping(ctx); err != nil {
+		t.Fatalf("expected the retry to load the mapping, got %v", err)
+	}
+	if client.mappingFailures != 0 || client.byTVDB == nil {
+		t.Fatalf("expected a successful retry to reset the backoff, failures=%d", client.mappingFailures)
+	}
+
+	for failures, want := range map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 4: 8 * time.Minute, 7: time.Hour, 30: time.Hour} {
+		if got := animeMappingRetryBackoff(failures); got != want {
+			t.Errorf("animeMappingRetryBackoff(%d) = %s, want %s", failures, got, want)
+		}
+	}
+}
+
+func TestWithAbsoluteEpisodeNumbers(t *testing.T) {
+	seasons := []models.SeriesSeason{
+		{Number: 2, Episodes: []models.SeriesEpisode{{EpisodeNumber: 1}, {EpisodeNumber: 2}}},
+		{Number: 0, Episodes: []models.SeriesEpisode{{EpisodeNumber: 1}}},
+		{Number: 1, Episodes: []models.SeriesEpisode{{EpisodeNumber: 1, AbsoluteEpisodeNumber: 1}, {EpisodeNumber: 2}, {EpisodeNumber: 3}}},
+	}
+
+	got, ordered := withAbsoluteEpisodeNumbers(seasons)
+	if !ordered {
+		t.Fatal("expected absolute ordering to be available")
+	}
+	if seasons[0].Episodes[0].AbsoluteEpisodeNumber != 0 {
+		t.Fatal("expected input seasons to be left unmodified")
+	}
+	want := map[[2]int]int{{1, 1}: 1, {1, 2}: 2, {1, 3}: 3, {2, 1}: 4, {2, 2}: 5, {0, 1}: 0}
+	for _, season := range got {
+		for _, ep := range season.Episodes {
+			if abs := want[[2]int{season.Number, ep.EpisodeNumber}]; ep.AbsoluteEpisodeNumber != abs {
+				t.Errorf("S%02dE%02d absolute = %d, want %d", season.Number, ep.EpisodeNumber, ep.AbsoluteEpisodeNumber, abs)
+			}
+		}
+	}
+}
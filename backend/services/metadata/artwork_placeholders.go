@@ -0,0 +1,40 @@
+package metadata
+
+import (
+	"context"
+
+	"novastream/models"
+)
+
+// artworkPlaceholderSource computes the blurhash and dominant color of an
+// image. It is implemented by the image proxy, so the thumbnails it fetches
+// are shared with artwork requests from clients.
+type artworkPlaceholderSource interface {
+	Placeholder(ctx context.Context, imageURL string) (blurHash, dominantColor string, err error)
+}
+
+// SetArtworkPlaceholderSource enables blurhash and dominant color enrichment
+// of poster and backdrop images.
+func (s *Service) SetArtworkPlaceholderSource(source artworkPlaceholderSource) {
+	s.placeholders = source
+}
+
+// applyArtworkPlaceholders fills in the placeholders of a title's posters
+// and backdrops that don't have one yet. Failures leave the image as is.
+func (s *Service) applyArtworkPlaceholders(ctx context.Context, title *models.Title) {
+	if s.placeholders == nil || title == nil {
+		return
+	}
+	for _, img := range []*models.Image{title.Poster, title.TextPoster, title.Backdrop, title.TextBackdrop} {
+		if img == nil || img.URL == "" || img.BlurHash != "" {
+			continue
+		}
+		blurHash, dominantColor, err := s.placeholders.Placeholder(ctx, img.URL)
+		if err != nil {
+			metadataTracef("[metadata] artwork placeholder failed for %s: %v", img.URL, err)
+			continue
+		}
+		img.BlurHash = blurHash
+		img.DominantColor = dominantColor
+	}
+}
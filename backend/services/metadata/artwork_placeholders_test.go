@@ -0,0 +1,44 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"novastream/models"
+)
+
+type stubPlaceholderSource map[string]string
+
+func (s stubPlaceholderSource) Placeholder(_ context.Context, imageURL string) (string, string, error) {
+	hash, ok := s[imageURL]
+	if !ok {
+		return "", "", errors.New("not found")
+	}
+	return hash, "#102030", nil
+}
+
+func TestApplyArtworkPlaceholders(t *testing.T) {
+	svc := &Service{}
+	svc.SetArtworkPlaceholderSource(stubPlaceholderSource{
+		"https://image.tmdb.org/t/p/w500/poster.jpg":    "poster-hash",
+		"https://image.tmdb.org/t/p/w1280/backdrop.jpg": "backdrop-hash",
+	})
+	title := &models.Title{
+		Poster:       &models.Image{URL: "https://image.tmdb.org/t/p/w500/poster.jpg", Type: "poster"},
+		Backdrop:     &models.Image{URL: "https://image.tmdb.org/t/p/w1280/backdrop.jpg", Type: "backdrop", BlurHash: "existing"},
+		TextBackdrop: &models.Image{URL: "https://image.tmdb.org/t/p/w1280/missing.jpg", Type: "backdrop"},
+	}
+
+	svc.applyArtworkPlaceholders(context.Background(), title)
+
+	if title.Poster.BlurHash != "poster-hash" || title.Poster.DominantColor != "#102030" {
+		t.Fatalf("poster = %+v", title.Poster)
+	}
+	if title.Backdrop.BlurHash != "existing" || title.Backdrop.DominantColor != "" {
+		t.Fatalf("existing placeholder was recomputed: %+v", title.Backdrop)
+	}
+	if title.TextBackdrop.BlurHash != "" {
+		t.Fatalf("failed lookup set a placeholder: %+v", title.TextBackdrop)
+	}
+}
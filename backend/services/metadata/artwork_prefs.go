@@ -0,0 +1,349 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+
+	"novastream/models"
+)
+
+// artworkTextlessAvoid is the ArtworkPreferences.Textless value that favours
+// artwork with text; preferring textless artwork is the default.
+const artworkTextlessAvoid = "avoid"
+
+// ArtworkPreferences mirrors config.ArtworkPreferences: which poster and
+// backdrop a title gets when TMDB and TVDB offer several.
+type ArtworkPreferences struct {
+	Language         string
+	Textless         string
+	MinPosterWidth   int
+	MinBackdropWidth int
+	Source           string
+}
+
+// normalizeArtworkPreferences canonicalizes prefs so that spelling out a
+// default ("prefer", "tmdb") compares equal to leaving it unset.
+func normalizeArtworkPreferences(prefs ArtworkPreferences) ArtworkPreferences {
+	prefs.Language = artworkLanguage(prefs.Language)
+	if strings.ToLower(strings.TrimSpace(prefs.Textless)) == artworkTextlessAvoid {
+		prefs.Textless = artworkTextlessAvoid
+	} else {
+		prefs.Textless = ""
+	}
+	if strings.ToLower(strings.TrimSpace(prefs.Source)) == artworkProviderTVDB {
+		prefs.Source = artworkProviderTVDB
+	} else {
+		prefs.Source = ""
+	}
+	if prefs.MinPosterWidth < 0 {
+		prefs.MinPosterWidth = 0
+	}
+	if prefs.MinBackdropWidth < 0 {
+		prefs.MinBackdropWidth = 0
+	}
+	return prefs
+}
+
+// artworkLanguage reduces a TMDB ("de", "pt-BR") or TVDB ("deu") language to
+// its ISO 639-1 code. Empty stays empty, meaning no text.
+func artworkLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "" || lang == "null" {
+		return ""
+	}
+	if base, _, ok := strings.Cut(strings.ReplaceAll(lang, "_", "-"), "-"); ok {
+		lang = base
+	}
+	if len(lang) == 3 {
+		// iso639_2to1 falls back to English for codes it doesn't know.
+		if short := iso639_2to1(lang); short != "en" || lang == "eng" {
+			return short
+		}
+	}
+	return lang
+}
+
+// SetArtworkPreferences configures how posters and backdrops are picked for
+// movie and series details. Profiles can override them per request through
+// WithArtworkPreferences.
+func (s *Service) SetArtworkPreferences(prefs ArtworkPreferences) {
+	prefs = normalizeArtworkPreferences(prefs)
+	s.artworkMu.Lock()
+	s.artworkPrefs = prefs
+	s.artworkMu.Unlock()
+	log.Printf("[metadata] artwork preferences configured (%+v)", prefs)
+}
+
+func (s *Service) artworkPreferences() ArtworkPreferences {
+	s.artworkMu.RLock()
+	defer s.artworkMu.RUnlock()
+	return s.artworkPrefs
+}
+
+// WithArtworkPreferences returns a service that picks artwork by prefs,
+// or s itself when they match its own.
+func (s *Service) WithArtworkPreferences(prefs ArtworkPreferences) *Service {
+	prefs = normalizeArtworkPreferences(prefs)
+	if prefs == s.artworkPreferences() {
+		return s
+	}
+	language := ""
+	if s.client != nil {
+		language = s.client.language
+	}
+	local := s.localCopy(language)
+	local.artworkPrefs = prefs
+	return local
+}
+
+// artworkCandidate is one poster or backdrop a provider offers for a title.
+type artworkCandidate struct {
+	image    models.Image
+	provider string
+	language string  // ISO 639-1; empty for textless artwork
+	width    int     // source resolution
+	score    float64 // provider's own rating, comparable within a provider only
+}
+
+// applyArtworkPreferences re-picks the poster and backdrop of title by the
+// service's artwork preferences. Cached details keep the default choice, so
+// this runs per request like applyRegion; without preferences it does
+// nothing.
+func (s *Service) applyArtworkPreferences(ctx context.Context, title *models.Title) {
+	if title == nil {
+		return
+	}
+	prefs := s.artworkPreferences()
+	if prefs == (ArtworkPreferences{}) {
+		return
+	}
+	language := prefs.Language
+	if language == "" {
+		language = "en"
+		if s.tmdb != nil {
+			language = artworkLanguage(s.tmdb.logoLanguage())
+		}
+	}
+
+	posters, backdrops := s.artworkCandidates(ctx, title)
+	if pick, ok := pickArtwork(posters, prefs, language, prefs.MinPosterWidth); ok {
+		image := pick.image
+		title.Poster = &image
+	}
+	if pick, ok := pickArtwork(backdrops, prefs, language, prefs.MinBackdropWidth); ok {
+		image := pick.image
+		title.Backdrop = &image
+	}
+}
+
+// artworkCandidates gathers the posters and backdrops TMDB and TVDB offer
+// for title. Both sources are read through their caches.
+func (s *Service) artworkCandidates(ctx context.Context, title *models.Title) (posters, backdrops []artworkCandidate) {
+	mediaType := "series"
+	if strings.EqualFold(title.MediaType, "movie") {
+		mediaType = "movie"
+	}
+
+	if title.TMDBID > 0 && s.tmdb != nil && s.tmdb.isConfigured() {
+		images, err := s.cachedFetchTMDBImageList(ctx, mediaType, title.TMDBID)
+		if err != nil {
+			log.Printf("[metadata] artwork candidates: TMDB images failed for %s tmdbId=%d: %v", mediaType, title.TMDBID, err)
+		} else {
+			posters = append(posters, tmdbArtworkCandidates(images.Posters, tmdbPosterSize, "poster")...)
+			backdrops = append(backdrops, tmdbArtworkCandidates(images.Backdrops, tmdbBackdropSize, "backdrop")...)
+		}
+	}
+
+	if title.TVDBID > 0 && s.client != nil {
+		var arts []tvdbArtwork
+		var err error
+		if mediaType == "movie" {
+			var ext tvdbMovieExtendedData
+			ext, err = s.cachedMovieExtended(title.TVDBID, []string{"artwork"})
+			arts = ext.Artworks
+		} else {
+			var ext tvdbSeriesExtendedData
+			ext, err = s.cachedSeriesExtended(title.TVDBID, []string{"artworks"})
+			arts = ext.Artworks
+		}
+		if err != nil {
+			log.Printf("[metadata] artwork candidates: TVDB artwork failed for %s tvdbId=%d: %v", mediaType, title.TVDBID, err)
+		}
+		for _, art := range arts {
+			imageURL := normalizeTVDBImageURL(art.Image)
+			if imageURL == "" {
+				continue
+			}
+			candidate := artworkCandidate{
+				provider: artworkProviderTVDB,
+				language: artworkLanguage(art.Language),
+				width:    art.Width,
+				score:    art.Score,
+			}
+			candidate.image = models.Image{URL: imageURL, Width: art.Width, Height: art.Height, Language: candidate.language, IsTextless: candidate.language == ""}
+			switch {
+			case artworkLooksLikePoster(art):
+				candidate.image.Type = "poster"
+				posters = append(posters, candidate)
+			case artworkLooksLikeBackdrop(art):
+				candidate.image.Type = "backdrop"
+				backdrops = append(backdrops, candidate)
+			}
+		}
+	}
+	return posters, backdrops
+}
+
+func tmdbArtworkCandidates(items []tmdbImageItem, size, imageType string) []artworkCandidate {
+	candidates := make([]artworkCandidate, 0, len(items))
+	for _, item := range items {
+		image := buildTMDBImage(item.FilePath, size, imageType)
+		if image == nil {
+			continue
+		}
+		language := artworkLanguage(item.ISO6391)
+		image.Language = language
+		image.IsTextless = language == ""
+		candidates = append(candidates, artworkCandidate{
+			image:    *image,
+			provider: artworkProviderTMDB,
+			language: language,
+			width:    item.Width,
+			score:    item.VoteAverage,
+		})
+	}
+	return candidates
+}
+
+// pickArtwork chooses the best candidate for prefs. Candidates narrower than
+// minWidth are dropped unless none is wide enough. The rest are ranked by
+// text language (artworkLanguageTier), then the preferred provider, then the
+// provider's own score, then width, with the URL as a final tiebreak so the
+// choice never depends on response order.
+func pickArtwork(candidates []artworkCandidate, prefs ArtworkPreferences, language string, minWidth int) (artworkCandidate, bool) {
+	if len(candidates) == 0 {
+		return artworkCandidate{}, false
+	}
+	eligible := candidates
+	if minWidth > 0 {
+		wide := make([]artworkCandidate, 0, len(candidates))
+		for _, candidate := range candidates {
+			if candidate.width >= minWidth {
+				wide = append(wide, candidate)
+			}
+		}
+		if len(wide) > 0 {
+			eligible = wide
+		}
+	}
+	ranked := append([]artworkCandidate(nil), eligible...)
+
+	preferred := artworkProviderTMDB
+	if prefs.Source != "" {
+		preferred = prefs.Source
+	}
+	avoidTextless := prefs.Textless == artworkTextlessAvoid
+	sort.SliceStable(ranked, func(i, j int) bool {
+		a, b := ranked[i], ranked[j]
+		if ta, tb := artworkLanguageTier(a.language, language, avoidTextless), artworkLanguageTier(b.language, language, avoidTextless); ta != tb {
+			return ta < tb
+		}
+		if pa, pb := a.provider == preferred, b.provider == preferred; pa != pb {
+			return pa
+		}
+		if a.provider == b.provider && a.score != b.score {
+			return a.score > b.score
+		}
+		if a.width != b.width {
+			return a.width > b.width
+		}
+		return a.image.URL < b.image.URL
+	})
+	return ranked[0], true
+}
+
+// artworkLanguageTier ranks artwork by its text: textless artwork first
+// unless avoidTextless, then text in the wanted language, then English, then
+// anything else.
+func artworkLanguageTier(lang, wanted string, avoidTextless bool) int {
+	switch {
+	case lang == "":
+		if avoidTextless {
+			return 2
+		}
+		return 0
+	case lang == wanted:
+		return 1
+	case lang == "en":
+		return 2
+	default:
+		return 3
+	}
+}
+
+// cachedFetchTMDBImageList fetches every TMDB image of a title with file
+// caching. The list isn't filtered by language, so one entry serves all.
+func (s *Service) cachedFetchTMDBImageList(ctx context.Context, mediaType string, tmdbID int64) (*tmdbImagesResponse, error) {
+	key := cacheKey("tmdb", "images", "list", "v1", mediaType, fmt.Sprintf("%d", tmdbID))
+	var cached tmdbImagesResponse
+	if ok, _ := s.cache.get(key, &cached); ok {
+		return &cached, nil
+	}
+	value, err := s.singleflightCachedFetch(ctx, key, func() (any, error) {
+		var cached tmdbImagesResponse
+		if ok, _ := s.cache.get(key, &cached); ok {
+			return &cached, nil
+		}
+		result, err := s.tmdb.fetchImageList(ctx, mediaType, tmdbID)
+		if err != nil {
+			return nil, err
+		}
+		_ = s.cache.set(key, result)
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result, _ := value.(*tmdbImagesResponse)
+	return result, nil
+}
+
+// RefreshArtwork drops the cached artwork of one title, along with the
+// details that embed it, so the next request picks its poster and backdrop
+// again. mediaType is "movie" or "series"; either ID may be zero. Returns the
+// number of cache entries removed.
+func (s *Service) RefreshArtwork(mediaType string, tmdbID, tvdbID int64) (int, error) {
+	detailsNamespace := CacheNamespaceSeries
+	if mediaType == "movie" {
+		detailsNamespace = CacheNamespaceMovies
+	}
+	type titleID struct{ provider, id string }
+	var ids []titleID
+	if tmdbID > 0 {
+		ids = append(ids, titleID{artworkProviderTMDB, strconv.FormatInt(tmdbID, 10)})
+	}
+	if tvdbID > 0 {
+		ids = append(ids, titleID{artworkProviderTVDB, strconv.FormatInt(tvdbID, 10)})
+	}
+	if len(ids) == 0 {
+		return 0, fmt.Errorf("tmdb or tvdb id required")
+	}
+	return s.deleteCacheEntries(func(key string) bool {
+		namespace := cacheKeyNamespace(key)
+		if namespace != CacheNamespaceArtwork && namespace != detailsNamespace {
+			return false
+		}
+		segments := cacheKeySegments(key)
+		for _, id := range ids {
+			// Fanart.tv keys carry the TMDB or TVDB ID without naming it.
+			if containsSegment(segments, id.id) && (namespace == CacheNamespaceArtwork || mentionsProvider(segments, id.provider)) {
+				return true
+			}
+		}
+		return false
+	})
+}
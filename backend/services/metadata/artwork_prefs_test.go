@@ -0,0 +1,214 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"novastream/models"
+)
+
+func TestPickArtwork(t *testing.T) {
+	candidate := func(provider, lang string, width int, score float64, url string) artworkCandidate {
+		return artworkCandidate{image: models.Image{URL: url}, provider: provider, language: lang, width: width, score: score}
+	}
+	candidates := []artworkCandidate{
+		candidate("tmdb", "en", 2000, 6.0, "tmdb-en"),
+		candidate("tmdb", "de", 1000, 5.0, "tmdb-de"),
+		candidate("tmdb", "", 500, 5.5, "tmdb-textless-small"),
+		candidate("tmdb", "", 1400, 5.2, "tmdb-textless"),
+		candidate("tvdb", "", 1000, 900, "tvdb-textless"),
+		candidate("tvdb", "de", 1000, 100, "tvdb-de"),
+	}
+
+	cases := []struct {
+		name     string
+		prefs    ArtworkPreferences
+		language string
+		minWidth int
+		want     string
+	}{
+		{"textless by score", ArtworkPreferences{}, "en", 0, "tmdb-textless-small"},
+		{"minimum width", ArtworkPreferences{MinPosterWidth: 1000}, "en", 1000, "tmdb-textless"},
+		{"minimum width nobody meets", ArtworkPreferences{}, "en", 5000, "tmdb-textless-small"},
+		{"tvdb first", ArtworkPreferences{Source: "tvdb"}, "en", 0, "tvdb-textless"},
+		{"titled in language", ArtworkPreferences{Textless: "avoid"}, "de", 0, "tmdb-de"},
+		{"titled tvdb in language", ArtworkPreferences{Textless: "avoid", Source: "tvdb"}, "de", 0, "tvdb-de"},
+		{"titled falls back to english", ArtworkPreferences{Textless: "avoid"}, "fr", 0, "tmdb-en"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := pickArtwork(candidates, tc.prefs, tc.language, tc.minWidth)
+			if !ok || got.image.URL != tc.want {
+				t.Fatalf("expected %s, got %s", tc.want, got.image.URL)
+			}
+			// The choice doesn't depend on the order candidates arrive in.
+			reversed := make([]artworkCandidate, len(candidates))
+			for i, c := range candidates {
+				reversed[len(candidates)-1-i] = c
+			}
+			if again, _ := pickArtwork(reversed, tc.prefs, tc.language, tc.minWidth); again.image.URL != tc.want {
+				t.Fatalf("expected %s regardless of order, got %s", tc.want, again.image.URL)
+			}
+		})
+	}
+
+	if _, ok := pickArtwork(nil, ArtworkPreferences{}, "en", 0); ok {
+		t.Fatal("expected no pick without candidates")
+	}
+}
+
+func TestNormalizeArtworkPreferences(t *testing.T) {
+	got := normalizeArtworkPreferences(ArtworkPreferences{Language: "DEU", Textless: "Prefer", Source: "TMDB", MinPosterWidth: -1})
+	if got != (ArtworkPreferences{Language: "de"}) {
+		t.Fatalf("unexpected normalized preferences %+v", got)
+	}
+	if got := normalizeArtworkPreferences(ArtworkPreferences{Textless: " avoid ", Source: "tvdb"}); got.Textless != "avoid" || got.Source != "tvdb" {
+		t.Fatalf("unexpected normalized preferences %+v", got)
+	}
+	for input, want := range map[string]string{"pt-BR": "pt", "eng": "en", "xyz": "xyz", "": ""} {
+		if got := artworkLanguage(input); got != want {
+			t.Fatalf("artworkLanguage(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestWithArtworkPreferences(t *testing.T) {
+	svc := &Service{cache: newFileCache(t.TempDir(), 24)}
+	svc.SetArtworkPreferences(ArtworkPreferences{Source: "tvdb"})
+
+	if got := svc.WithArtworkPreferences(ArtworkPreferences{Source: "TVDB"}); got != svc {
+		t.Fatal("expected matching preferences to reuse the service")
+	}
+	local := svc.WithArtworkPreferences(ArtworkPreferences{Source: "tvdb", Textless: "avoid"})
+	if local == svc {
+		t.Fatal("expected a scoped copy for different preferences")
+	}
+	if got := local.artworkPreferences(); got.Textless != "avoid" || got.Source != "tvdb" {
+		t.Fatalf("unexpected scoped preferences %+v", got)
+	}
+	if got := svc.artworkPreferences(); got.Textless != "" {
+		t.Fatalf("scoping changed the server preferences: %+v", got)
+	}
+}
+
+func TestRankTVDBArtworks(t *testing.T) {
+	arts := []tvdbArtwork{
+		{ID: 3, Image: "/banners/posters/3.jpg", Type: "2", Score: 10},
+		{ID: 2, Image: "/banners/posters/2.jpg", Type: "2", Score: 50},
+		{ID: 1, Image: "/banners/posters/1.jpg", Type: "2", Score: 10},
+	}
+	ranked := rankTVDBArtworks(arts)
+	if ranked[0].ID != 2 || ranked[1].ID != 1 || ranked[2].ID != 3 {
+		t.Fatalf("unexpected ranking %+v", ranked)
+	}
+	if arts[0].ID != 3 {
+		t.Fatal("ranking reordered the caller's slice")
+	}
+
+	title := &models.Title{}
+	applyTVDBArtworks(title, arts)
+	if title.Poster == nil || title.Poster.URL != "https://artworks.thetvdb.com/banners/posters/2.jpg" {
+		t.Fatalf("expected the best scored poster, got %+v", title.Poster)
+	}
+}
+
+func TestApplyArtworkPreferences(t *testing.T) {
+	requests := 0
+	httpc := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path != "/3/movie/603/images" {
+				t.Fatalf("unhandled request: %s", req.URL.String())
+			}
+			requests++
+			body := bytes.NewBufferString(`{
+				"posters":[
+					{"file_path":"/textless.jpg","iso_639_1":null,"width":2000,"vote_average":5.3},
+					{"file_path":"/german.jpg","iso_639_1":"de","width":2000,"vote_average":5.1},
+					{"file_path":"/english.jpg","iso_639_1":"en","width":2000,"vote_average":5.6}
+				],
+				"backdrops":[
+					{"file_path":"/backdrop-small.jpg","iso_639_1":null,"width":1280,"vote_average":6.0},
+					{"file_path":"/backdrop-large.jpg","iso_639_1":null,"width":3840,"vote_average":5.0}
+				]
+			}`)
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(body), Header: make(http.Header)}, nil
+		}),
+	}
+	svc := &Service{
+		tmdb:  newTMDBClient("tmdb-key", "deu", httpc, newFileCache(t.TempDir(), 24)),
+		cache: newFileCache(t.TempDir(), 24),
+	}
+	svc.tmdb.limiter = nil
+
+	original := &models.Image{URL: "https://image.tmdb.org/t/p/w780/original.jpg", Type: "poster"}
+	title := &models.Title{MediaType: "movie", TMDBID: 603, Poster: original}
+	svc.applyArtworkPreferences(context.Background(), title)
+	if title.Poster != original || requests != 0 {
+		t.Fatal("expected no change and no requests without preferences")
+	}
+
+	local := svc.WithArtworkPreferences(ArtworkPreferences{Language: "de", Textless: "avoid", MinBackdropWidth: 1920})
+	local.tmdb.limiter = nil
+	local.tmdb.httpc = httpc
+	for i := 0; i < 2; i++ {
+		title := &models.Title{MediaType: "movie", TMDBID: 603, Poster: original}
+		local.applyArtworkPreferences(context.Background(), title)
+		if title.Poster == nil || title.Poster.URL != "https://image.tmdb.org/t/p/w780/german.jpg" || title.Poster.Language != "de" {
+			t.Fatalf("expected the German titled poster, got %+v", title.Poster)
+		}
+		if title.Backdrop == nil || title.Backdrop.URL != "https://image.tmdb.org/t/p/original/backdrop-large.jpg" {
+			t.Fatalf("expected the large backdrop, got %+v", title.Backdrop)
+		}
+	}
+	if requests != 1 {
+		t.Fatalf("expected the image list to be cached, got %d requests", requests)
+	}
+}
+
+func TestRefreshArtwork(t *testing.T) {
+	cache := newFileCache(t.TempDir(), 24)
+	svc := &Service{cache: cache}
+	keep := []string{
+		cacheKey("tmdb", "images", "v6", "eng", "movie", "604"),
+		cacheKey("tmdb", "credits", "v2", "movie", "603"),
+		cacheKey("ratings", "imdb", "tt0133093"),
+		cacheKey("tvdb", "movie", "details", "v6", "eng", "603"),
+	}
+	drop := []string{
+		cacheKey("tmdb", "images", "v6", "eng", "movie", "603"),
+		cacheKey("tmdb", "images", "list", "v1", "movie", "603"),
+		cacheKey("fanart", "artwork", "v1", "en", "movie", "603"),
+		cacheKey("tmdb", "movie", "details", "v4", "eng", "603"),
+	}
+	for _, key := range append(append([]string{}, keep...), drop...) {
+		if err := cache.set(key, map[string]string{"k": key}); err != nil {
+			t.Fatalf("set %s: %v", key, err)
+		}
+	}
+
+	removed, err := svc.RefreshArtwork("movie", 603, 0)
+	if err != nil {
+		t.Fatalf("RefreshArtwork: %v", err)
+	}
+	if removed != len(drop) {
+		t.Fatalf("expected %d entries removed, got %d", len(drop), removed)
+	}
+	var value map[string]string
+	for _, key := range keep {
+		if ok, _ := cache.get(key, &value); !ok {
+			t.Fatalf("expected %s to be kept", key)
+		}
+	}
+	for _, key := range drop {
+		if ok, _ := cache.get(key, &value); ok {
+			t.Fatalf("expected %s to be removed", key)
+		}
+	}
+
+	if _, err := svc.RefreshArtwork("movie", 0, 0); err == nil {
+		t.Fatal("expected an error without IDs")
+	}
+}
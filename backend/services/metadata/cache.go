@@ -7,14 +7,43 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
+// cacheStore is the persistence layer behind the metadata, ID, and ratings
+// caches. Values are JSON-encoded; entries older than the store's TTL (or the
+// caller-supplied maxAge) are treated as misses. In offline mode nothing
+// expires and writes are dropped.
+type cacheStore interface {
+	get(key string, v any) (bool, error)
+	getWithMaxAge(key string, v any, maxAge time.Duration) (bool, error)
+	set(key string, v any) error
+	clear() error
+	// keys lists every cached key, including expired entries not yet removed.
+	keys() ([]string, error)
+	// stats reports the number of entries and their total size in bytes.
+	stats() (entries int, sizeBytes int64, err error)
+	// entries lists every cached key with its size and last write time.
+	entries() ([]cacheEntry, error)
+	// delete removes a single entry. Missing keys are not an error.
+	delete(key string) error
+}
+
+// cacheEntry describes one stored value, for cache inspection.
+type cacheEntry struct {
+	key       string
+	sizeBytes int64
+	updatedAt time.Time
+}
+
 type fileCache struct {
 	dir string
 	ttl time.Duration
 }
 
+var _ cacheStore = (*fileCache)(nil)
+
 func newFileCache(dir string, ttlHours int) *fileCache {
 	return &fileCache{dir: dir, ttl: time.Duration(ttlHours) * time.Hour}
 }
@@ -23,10 +52,14 @@ func newFileCache(dir string, ttlHours int) *fileCache {
 // between the base TTL and base TTL + 6 hours. The jitter is derived from the key
 // hash so the same key always gets the same TTL, preventing cache churn.
 func (c *fileCache) jitteredTTL(key string) time.Duration {
+	return jitteredCacheTTL(c.ttl, key)
+}
+
+func jitteredCacheTTL(base time.Duration, key string) time.Duration {
 	h := sha256.Sum256([]byte(key))
 	n := binary.BigEndian.Uint64(h[:8])
 	jitter := time.Duration(n%uint64(6*time.Hour)) // 0 to 6 hours
-	return c.ttl + jitter
+	return base + jitter
 }
 
 func (c *fileCache) get(key string, v any) (bool, error) {
@@ -51,7 +84,8 @@ func (c *fileCache) getWithMaxAge(key string, v any, maxAge time.Duration) (bool
 	if maxAge > 0 {
 		ttl = maxAge
 	}
-	if time.Since(fi.ModTime()) > ttl {
+	// Offline mode can't replace what expires, so nothing does.
+	if time.Since(fi.ModTime()) > ttl && !offlineMode.Load() {
 		_ = os.Remove(path)
 		return false, nil
 	}
@@ -71,6 +105,11 @@ func (c *fileCache) set(key string, v any) error {
 	if key == "" {
 		return errors.New("empty key")
 	}
+	if offlineMode.Load() {
+		// The cache is frozen; values built around missing data would
+		// otherwise outlive offline mode.
+		return nil
+	}
 	if err := os.MkdirAll(c.dir, 0o755); err != nil {
 		return err
 	}
@@ -119,3 +158,86 @@ func (c *fileCache) clear() error {
 	}
 	return nil
 }
+
+// keys returns the cache keys of every .json file in the cache directory.
+func (c *fileCache) keys() ([]string, error) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		keys = append(keys, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	return keys, nil
+}
+
+// stats counts the .json files in the cache directory and their total size.
+func (c *fileCache) stats() (int, int64, error) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, 0, nil
+		}
+		return 0, 0, err
+	}
+	var count int
+	var size int64
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		count++
+		size += info.Size()
+	}
+	return count, size, nil
+}
+
+// entries lists the .json files in the cache directory with their size and
+// modification time.
+func (c *fileCache) entries() ([]cacheEntry, error) {
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	entries := make([]cacheEntry, 0, len(dirEntries))
+	for _, entry := range dirEntries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, cacheEntry{
+			key:       strings.TrimSuffix(entry.Name(), ".json"),
+			sizeBytes: info.Size(),
+			updatedAt: info.ModTime(),
+		})
+	}
+	return entries, nil
+}
+
+func (c *fileCache) delete(key string) error {
+	if key == "" {
+		return errors.New("empty key")
+	}
+	err := os.Remove(filepath.Join(c.dir, key+".json"))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package metadata
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+// sqliteCacheFile is the SQLite cache database, relative to the cache directory.
+const sqliteCacheFile = "metadata-cache.db"
+
+// CacheStats summarizes one metadata cache namespace.
+type CacheStats struct {
+	Namespace    string `json:"namespace"`
+	Backend      string `json:"backend"`
+	Entries      int    `json:"entries"`
+	SizeBytes    int64  `json:"sizeBytes"`
+	MaxSizeBytes int64  `json:"maxSizeBytes,omitempty"`
+}
+
+// NormalizeCacheBackend returns a supported cache backend name, defaulting to file.
+func NormalizeCacheBackend(backend string) string {
+	if strings.EqualFold(strings.TrimSpace(backend), CacheBackendSQLite) {
+		return CacheBackendSQLite
+	}
+	return CacheBackendFile
+}
+
+// ConfigureCacheBackend selects the store behind the metadata, ID, and ratings
+// caches. Switching to SQLite opens <cacheDir>/metadata-cache.db, evicting the
+// least recently used entries once it grows past maxSizeMB (0 = unbounded), and
+// migrates any existing file cache entries into it in the background. It must be
+// called during startup, before the service handles requests.
+func (s *Service) ConfigureCacheBackend(backend string, maxSizeMB int) error {
+	if NormalizeCacheBackend(backend) != CacheBackendSQLite {
+		return nil
+	}
+	if s.cacheDB != nil {
+		return nil
+	}
+
+	maxBytes := int64(maxSizeMB) * 1024 * 1024
+	db, err := openSQLiteCacheDB(filepath.Join(s.cacheDir, sqliteCacheFile), maxBytes)
+	if err != nil {
+		return fmt.Errorf("sqlite metadata cache: %w", err)
+	}
+
+	metadataCache := db.namespace("metadata", s.ttlHours)
+	idCache := db.namespace("ids", s.ttlHours*stableIDCacheTTLMultiplier)
+	ratingsCache := db.namespace("ratings", s.ttlHours*stableIDCacheTTLMultiplier)
+
+	s.cacheDB = db
+	s.cache = metadataCache
+	s.idCache = idCache
+	s.ratingsCache = ratingsCache
+	if s.tmdb != nil {
+		s.tmdb.cache = metadataCache
+	}
+	if s.ai != nil {
+		s.ai.cache = metadataCache
+	}
+	log.Printf("[metadata] using sqlite metadata cache at %s (max %d MB)", db.path, maxSizeMB)
+
+	metadataDir := filepath.Join(s.cacheDir, "metadata")
+	go func() {
+		for _, m := range []struct {
+			cache *sqliteCache
+			dir   string
+		}{
+			{metadataCache, metadataDir},
+			{idCache, filepath.Join(metadataDir, "ids")},
+			{ratingsCache, filepath.Join(metadataDir, "ratings")},
+		} {
+			imported, err := m.cache.migrateFromDir(m.dir)
+			if err != nil {
+				log.Printf("[metadata] sqlite cache: migrating %s from %s failed after %d entries: %v", m.cache.namespace, m.dir, imported, err)
+				continue
+			}
+			if imported > 0 {
+				log.Printf("[metadata] sqlite cache: migrated %d %s entries from %s", imported, m.cache.namespace, m.dir)
+			}
+		}
+	}()
+	return nil
+}
+
+// CacheStats reports entry counts and sizes for each metadata cache namespace.
+func (s *Service) CacheStats() ([]CacheStats, error) {
+	backend := CacheBackendFile
+	var maxBytes int64
+	if s.cacheDB != nil {
+		backend = CacheBackendSQLite
+		maxBytes = s.cacheDB.maxBytes
+	}
+
+	var stats []CacheStats
+	for _, ns := range s.cacheStores() {
+		entries, size, err := ns.cache.stats()
+		if err != nil {
+			return nil, fmt.Errorf("%s cache stats: %w", ns.name, err)
+		}
+		stats = append(stats, CacheStats{
+			Namespace:    ns.name,
+			Backend:      backend,
+			Entries:      entries,
+			SizeBytes:    size,
+			MaxSizeBytes: maxBytes,
+		})
+	}
+	return stats, nil
+}
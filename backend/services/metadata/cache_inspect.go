@@ -0,0 +1,418 @@
+package metadata
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Cache namespaces group cache entries by what they hold. The namespace is
+// the first segment of every key built by cacheKey.
+const (
+	CacheNamespaceTrending = "trending"
+	CacheNamespaceLists    = "lists"
+	CacheNamespaceDiscover = "discover"
+	CacheNamespaceSeries   = "series"
+	CacheNamespaceMovies   = "movies"
+	CacheNamespaceTrailers = "trailers"
+	CacheNamespaceIDs      = "ids"
+	CacheNamespaceRatings  = "ratings"
+	CacheNamespaceArtwork  = "artwork"
+	CacheNamespaceSearch   = "search"
+	CacheNamespacePeople   = "people"
+	CacheNamespaceAI       = "ai"
+	CacheNamespaceAniList  = "anilist"
+	CacheNamespaceOther    = "other"
+	// CacheNamespaceLegacy holds entries written before keys carried a
+	// namespace. They are never read again and are purged when the cache
+	// manager starts.
+	CacheNamespaceLegacy = "legacy"
+)
+
+var cacheNamespaces = map[string]bool{
+	CacheNamespaceTrending: true,
+	CacheNamespaceLists:    true,
+	CacheNamespaceDiscover: true,
+	CacheNamespaceSeries:   true,
+	CacheNamespaceMovies:   true,
+	CacheNamespaceTrailers: true,
+	CacheNamespaceIDs:      true,
+	CacheNamespaceRatings:  true,
+	CacheNamespaceArtwork:  true,
+	CacheNamespaceSearch:   true,
+	CacheNamespacePeople:   true,
+	CacheNamespaceAI:       true,
+	CacheNamespaceAniList:  true,
+	CacheNamespaceOther:    true,
+	CacheNamespaceLegacy:   true,
+}
+
+const (
+	// cacheKeySegmentMax drops longer parts (URLs, queries, name lists) from
+	// the readable part of a key; the hash still covers them.
+	cacheKeySegmentMax = 40
+	// cacheKeyLabelMax keeps keys well below file name limits.
+	cacheKeyLabelMax = 120
+)
+
+// CacheNamespaceSummary reports the size and age range of one namespace
+// across the metadata, ID, and ratings caches.
+type CacheNamespaceSummary struct {
+	Namespace        string `json:"namespace"`
+	Entries          int    `json:"entries"`
+	SizeBytes        int64  `json:"sizeBytes"`
+	OldestAgeSeconds int64  `json:"oldestAgeSeconds"`
+	NewestAgeSeconds int64  `json:"newestAgeSeconds"`
+}
+
+// CacheEntryInfo describes one cache entry.
+type CacheEntryInfo struct {
+	Key        string    `json:"key"`
+	Cache      string    `json:"cache"` // metadata, ids or ratings
+	SizeBytes  int64     `json:"sizeBytes"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+	AgeSeconds int64     `json:"ageSeconds"`
+}
+
+// cacheNamespace maps the parts of a cache key to its namespace.
+func cacheNamespace(parts []string) string {
+	if len(parts) == 0 {
+		return CacheNamespaceOther
+	}
+	kind := ""
+	if len(parts) > 1 {
+		kind = parts[1]
+	}
+	switch parts[0] {
+	case "trending", "curated", "topten":
+		return CacheNamespaceTrending
+	case "mdblist":
+		if kind == "trending" {
+			return CacheNamespaceTrending
+		}
+		return CacheNamespaceLists
+	case "discover":
+		return CacheNamespaceDiscover
+	case "id", "resolve":
+		return CacheNamespaceIDs
+	case "ratings":
+		return CacheNamespaceRatings
+	case "metadata":
+		return CacheNamespaceSearch
+	case "fanart", "demo":
+		return CacheNamespaceArtwork
+	case "trailer-stream-v2":
+		return CacheNamespaceTrailers
+	case "ai":
+		return CacheNamespaceAI
+	case "anilist":
+		return CacheNamespaceAniList
+	case "tmdb":
+		switch kind {
+		case "movie":
+			return CacheNamespaceMovies
+		case "series", "tv":
+			return CacheNamespaceSeries
+		case "trailers":
+			return CacheNamespaceTrailers
+		case "images":
+			return CacheNamespaceArtwork
+		case "person", "credits":
+			return CacheNamespacePeople
+		case "discover":
+			return CacheNamespaceDiscover
+		case "list":
+			return CacheNamespaceLists
+		}
+	case "tvdb":
+		switch kind {
+		case "series", "series-fallback", "season", "aliases", "aliases_lang":
+			return CacheNamespaceSeries
+		case "movie":
+			return CacheNamespaceMovies
+		case "trailers":
+			return CacheNamespaceTrailers
+		case "resolve":
+			return CacheNamespaceIDs
+		case "search":
+			return CacheNamespaceSearch
+		}
+	}
+	return CacheNamespaceOther
+}
+
+// cacheKeyLabel returns the readable part of a cache key: the namespace
+// followed by the short parts, separated by underscores.
+func cacheKeyLabel(parts []string) string {
+	namespace := cacheNamespace(parts)
+	var b strings.Builder
+	b.WriteString(namespace)
+	for i, part := range parts {
+		if i == 0 && part == namespace {
+			continue
+		}
+		segment := cacheKeySegment(part)
+		if segment == "" || len(segment) > cacheKeySegmentMax {
+			continue
+		}
+		if b.Len()+1+len(segment) > cacheKeyLabelMax {
+			break
+		}
+		b.WriteByte('_')
+		b.WriteString(segment)
+	}
+	return b.String()
+}
+
+// cacheKeySegment reduces a key part to characters that are safe in file
+// names and cannot be mistaken for the segment separator.
+func cacheKeySegment(part string) string {
+	segment := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-':
+			return r
+		}
+		return '-'
+	}, strings.TrimSpace(part))
+	return strings.Trim(segment, "-")
+}
+
+// cacheKeyNamespace returns the namespace of a stored key.
+func cacheKeyNamespace(key string) string {
+	if prefix, _, ok := strings.Cut(key, "_"); ok && cacheNamespaces[prefix] {
+		return prefix
+	}
+	if isLegacyCacheKey(key) {
+		return CacheNamespaceLegacy
+	}
+	return CacheNamespaceOther
+}
+
+// isLegacyCacheKey reports whether key is a bare SHA-1 hex digest, the key
+// format used before namespaces.
+func isLegacyCacheKey(key string) bool {
+	if len(key) != 40 {
+		return false
+	}
+	for _, r := range key {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// cacheKeySegments returns the readable segments of a key, without the
+// namespace and the trailing hash.
+func cacheKeySegments(key string) []string {
+	segments := strings.Split(key, "_")
+	if len(segments) < 3 {
+		return nil
+	}
+	return segments[1 : len(segments)-1]
+}
+
+type namedCacheStore struct {
+	name  string
+	cache cacheStore
+}
+
+// cacheStores returns the configured caches by name.
+func (s *Service) cacheStores() []namedCacheStore {
+	var stores []namedCacheStore
+	for _, store := range []namedCacheStore{
+		{"metadata", s.cache},
+		{"ids", s.idCache},
+		{"ratings", s.ratingsCache},
+	} {
+		if store.cache != nil {
+			stores = append(stores, store)
+		}
+	}
+	return stores
+}
+
+// CacheNamespaces summarizes every non-empty cache namespace, sorted by name.
+func (s *Service) CacheNamespaces() ([]CacheNamespaceSummary, error) {
+	now := time.Now()
+	byName := make(map[string]*CacheNamespaceSummary)
+	for _, store := range s.cacheStores() {
+		entries, err := store.cache.entries()
+		if err != nil {
+			return nil, fmt.Errorf("%s cache entries: %w", store.name, err)
+		}
+		for _, entry := range entries {
+			namespace := cacheKeyNamespace(entry.key)
+			age := int64(now.Sub(entry.updatedAt).Seconds())
+			summary, ok := byName[namespace]
+			if !ok {
+				summary = &CacheNamespaceSummary{Namespace: namespace, OldestAgeSeconds: age, NewestAgeSeconds: age}
+				byName[namespace] = summary
+			}
+			summary.Entries++
+			summary.SizeBytes += entry.sizeBytes
+			if age > summary.OldestAgeSeconds {
+				summary.OldestAgeSeconds = age
+			}
+			if age < summary.NewestAgeSeconds {
+				summary.NewestAgeSeconds = age
+			}
+		}
+	}
+
+	summaries := make([]CacheNamespaceSummary, 0, len(byName))
+	for _, summary := range byName {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Namespace < summaries[j].Namespace })
+	return summaries, nil
+}
+
+// CacheEntries lists the entries of a namespace, newest first. A limit of 0
+// or less returns every entry.
+func (s *Service) CacheEntries(namespace string, limit int) ([]CacheEntryInfo, error) {
+	if !cacheNamespaces[namespace] {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCacheNamespace, namespace)
+	}
+	infos, err := s.matchingCacheEntries(func(key string) bool {
+		return cacheKeyNamespace(key) == namespace
+	})
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(infos) > limit {
+		infos = infos[:limit]
+	}
+	return infos, nil
+}
+
+// InvalidateCacheNamespace removes every entry of a namespace and returns
+// how many were removed.
+func (s *Service) InvalidateCacheNamespace(namespace string) (int, error) {
+	if !cacheNamespaces[namespace] {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownCacheNamespace, namespace)
+	}
+	return s.deleteCacheEntries(func(key string) bool {
+		return cacheKeyNamespace(key) == namespace
+	})
+}
+
+// purgeLegacyCacheEntries deletes the entries written before keys carried a
+// namespace. Nothing reads them anymore, so they only take up space.
+func (s *Service) purgeLegacyCacheEntries() {
+	removed, err := s.InvalidateCacheNamespace(CacheNamespaceLegacy)
+	if err != nil {
+		log.Printf("[metadata] failed to purge legacy cache entries: %v", err)
+		return
+	}
+	if removed > 0 {
+		log.Printf("[metadata] purged %d legacy cache entries", removed)
+	}
+}
+
+// InvalidateCachedTitle removes every entry whose key carries the given
+// title ID: details, artwork, trailers, ratings, and ID mappings. The ID may
+// be an IMDB ID ("tt0903747") or a TMDB/TVDB ID, optionally qualified by its
+// provider ("tmdb:1396", "tvdb:81189") to avoid touching entries of the other
+// provider that happen to share the number. Entries that only embed the title
+// in their contents, such as trending lists, are left alone.
+func (s *Service) InvalidateCachedTitle(id string) (int, error) {
+	match, err := titleCacheKeyMatcher(id)
+	if err != nil {
+		return 0, err
+	}
+	return s.deleteCacheEntries(match)
+}
+
+// titleCacheKeyMatcher returns a matcher for the cache keys that carry a
+// title ID, in the forms InvalidateCachedTitle accepts.
+func titleCacheKeyMatcher(id string) (func(key string) bool, error) {
+	provider, titleID, qualified := strings.Cut(strings.TrimSpace(id), ":")
+	if !qualified {
+		provider, titleID = "", provider
+	}
+	provider = strings.ToLower(strings.TrimSpace(provider))
+	if provider == "imdb" {
+		provider = ""
+	}
+	titleID = cacheKeySegment(titleID)
+	if titleID == "" {
+		return nil, fmt.Errorf("invalid title id %q", id)
+	}
+	return func(key string) bool {
+		segments := cacheKeySegments(key)
+		return containsSegment(segments, titleID) && (provider == "" || mentionsProvider(segments, provider))
+	}, nil
+}
+
+// matchingCacheEntries lists the entries whose key matches across every
+// cache, newest first.
+func (s *Service) matchingCacheEntries(match func(key string) bool) ([]CacheEntryInfo, error) {
+	now := time.Now()
+	var infos []CacheEntryInfo
+	for _, store := range s.cacheStores() {
+		entries, err := store.cache.entries()
+		if err != nil {
+			return nil, fmt.Errorf("%s cache entries: %w", store.name, err)
+		}
+		for _, entry := range entries {
+			if !match(entry.key) {
+				continue
+			}
+			infos = append(infos, CacheEntryInfo{
+				Key:        entry.key,
+				Cache:      store.name,
+				SizeBytes:  entry.sizeBytes,
+				UpdatedAt:  entry.updatedAt,
+				AgeSeconds: int64(now.Sub(entry.updatedAt).Seconds()),
+			})
+		}
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].UpdatedAt.After(infos[j].UpdatedAt) })
+	return infos, nil
+}
+
+func containsSegment(segments []string, want string) bool {
+	for _, segment := range segments {
+		if segment == want {
+			return true
+		}
+	}
+	return false
+}
+
+// mentionsProvider reports whether a segment names the provider, on its own
+// ("tmdb") or as part of a mapping ("tmdb-to-imdb").
+func mentionsProvider(segments []string, provider string) bool {
+	for _, segment := range segments {
+		for _, word := range strings.Split(segment, "-") {
+			if word == provider {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// deleteCacheEntries removes the entries whose key matches from every cache.
+func (s *Service) deleteCacheEntries(match func(key string) bool) (int, error) {
+	var removed int
+	for _, store := range s.cacheStores() {
+		entries, err := store.cache.entries()
+		if err != nil {
+			return removed, fmt.Errorf("%s cache entries: %w", store.name, err)
+		}
+		for _, entry := range entries {
+			if !match(entry.key) {
+				continue
+			}
+			if err := store.cache.delete(entry.key); err != nil {
+				return removed, fmt.Errorf("%s cache delete: %w", store.name, err)
+			}
+			removed++
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,165 @@
+package metadata
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCacheKeyCarriesNamespaceAndIDs(t *testing.T) {
+	tests := []struct {
+		parts  []string
+		prefix string
+	}{
+		{[]string{"tvdb", "series", "details", "v12", "eng", "81189"}, "series_tvdb_series_details_v12_eng_81189_"},
+		{[]string{"tmdb", "movie", "details", "v4", "eng", "1396"}, "movies_tmdb_movie_details_v4_eng_1396_"},
+		{[]string{"tmdb", "trailers", "movie", "1396"}, "trailers_tmdb_trailers_movie_1396_"},
+		{[]string{"id", "tmdb-to-imdb", "movie", "1396"}, "ids_id_tmdb-to-imdb_movie_1396_"},
+		{[]string{"ratings", "all", "movie", "tt0903747"}, "ratings_all_movie_tt0903747_"},
+		{[]string{"mdblist", "trending", "movie", "v7", "eng"}, "trending_mdblist_trending_movie_v7_eng_"},
+		{[]string{"tvdb", "aliases_lang", "series", "81189"}, "series_tvdb_aliases-lang_series_81189_"},
+		// Long parts such as list URLs are left to the hash.
+		{[]string{"mdblist", "custom", "v7", "https://mdblist.com/lists/someone/some-long-list-name/json", "eng"}, "lists_mdblist_custom_v7_eng_"},
+		{[]string{"unknown", "thing"}, "other_unknown_thing_"},
+	}
+	for _, tt := range tests {
+		key := cacheKey(tt.parts...)
+		if !strings.HasPrefix(key, tt.prefix) {
+			t.Errorf("cacheKey(%v) = %q, want prefix %q", tt.parts, key, tt.prefix)
+		}
+		if got, want := cacheKeyNamespace(key), strings.SplitN(tt.prefix, "_", 2)[0]; got != want {
+			t.Errorf("cacheKeyNamespace(%q) = %q, want %q", key, got, want)
+		}
+	}
+
+	if cacheKey("tvdb", "search", "series", "a b") == cacheKey("tvdb", "search", "series", "a-b") {
+		t.Error("expected parts that sanitize alike to keep distinct keys")
+	}
+	if got := cacheKeyNamespace("0123456789abcdef0123456789abcdef01234567"); got != CacheNamespaceLegacy {
+		t.Errorf("expected bare sha1 keys to be legacy, got %q", got)
+	}
+	if got := cacheKeyNamespace("tv:1396:episode_count"); got != CacheNamespaceOther {
+		t.Errorf("expected foreign keys to be other, got %q", got)
+	}
+}
+
+func newInspectTestService(t *testing.T) *Service {
+	t.Helper()
+	dir := t.TempDir()
+	return &Service{
+		cache:        newFileCache(dir, 24),
+		idCache:      newFileCache(filepath.Join(dir, "ids"), 24),
+		ratingsCache: newFileCache(filepath.Join(dir, "ratings"), 24),
+	}
+}
+
+func TestCacheNamespacesReportsSizesAndAges(t *testing.T) {
+	svc := newInspectTestService(t)
+	fc := svc.cache.(*fileCache)
+	seriesKey := cacheKey("tvdb", "series", "details", "v12", "eng", "81189")
+	trendingKey := cacheKey("mdblist", "trending", "movie", "v7", "eng")
+	for _, key := range []string{seriesKey, trendingKey} {
+		if err := fc.set(key, map[string]string{"name": "x"}); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(filepath.Join(fc.dir, trendingKey+".json"), old, old); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+	if err := svc.idCache.set(cacheKey("id", "tmdb-to-imdb", "tv", "1396"), "tt0903747"); err != nil {
+		t.Fatalf("set id: %v", err)
+	}
+
+	summaries, err := svc.CacheNamespaces()
+	if err != nil {
+		t.Fatalf("CacheNamespaces: %v", err)
+	}
+	byName := make(map[string]CacheNamespaceSummary)
+	for _, summary := range summaries {
+		byName[summary.Namespace] = summary
+	}
+	if len(byName) != 3 || byName["series"].Entries != 1 || byName["ids"].Entries != 1 {
+		t.Fatalf("unexpected summaries: %+v", summaries)
+	}
+	trending := byName["trending"]
+	if trending.SizeBytes == 0 || trending.OldestAgeSeconds < 7000 {
+		t.Fatalf("expected trending size and age, got %+v", trending)
+	}
+
+	entries, err := svc.CacheEntries("ids", 0)
+	if err != nil || len(entries) != 1 || entries[0].Cache != "ids" {
+		t.Fatalf("expected one ids entry from the id cache, got %+v err=%v", entries, err)
+	}
+	if _, err := svc.CacheEntries("nope", 0); !errors.Is(err, ErrUnknownCacheNamespace) {
+		t.Fatalf("expected ErrUnknownCacheNamespace, got %v", err)
+	}
+}
+
+func TestInvalidateCachedTitle(t *testing.T) {
+	svc := newInspectTestService(t)
+	seriesKey := cacheKey("tvdb", "series", "details", "v12", "eng", "81189")
+	tmdbKey := cacheKey("tmdb", "series", "details-fallback", "v1", "eng", "81189")
+	otherKey := cacheKey("tvdb", "series", "details", "v12", "eng", "75805")
+	ratingsKey := cacheKey("ratings", "all", "show", "tt0903747")
+	for _, key := range []string{seriesKey, tmdbKey, otherKey} {
+		if err := svc.cache.set(key, "v"); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+	}
+	if err := svc.ratingsCache.set(ratingsKey, "v"); err != nil {
+		t.Fatalf("set ratings: %v", err)
+	}
+
+	removed, err := svc.InvalidateCachedTitle("tvdb:81189")
+	if err != nil || removed != 1 {
+		t.Fatalf("expected the tvdb entry alone to be removed, got removed=%d err=%v", removed, err)
+	}
+	var v string
+	if ok, _ := svc.cache.get(tmdbKey, &v); !ok {
+		t.Fatal("expected the tmdb entry sharing the number to survive a tvdb-qualified invalidation")
+	}
+	if removed, _ := svc.InvalidateCachedTitle("81189"); removed != 1 {
+		t.Fatalf("expected an unqualified id to match any provider, removed %d", removed)
+	}
+	if removed, _ := svc.InvalidateCachedTitle("imdb:tt0903747"); removed != 1 {
+		t.Fatalf("expected the ratings entry to be removed, removed %d", removed)
+	}
+	if ok, _ := svc.cache.get(otherKey, &v); !ok {
+		t.Fatal("expected other titles to be untouched")
+	}
+
+	if removed, err := svc.InvalidateCacheNamespace("series"); err != nil || removed != 1 {
+		t.Fatalf("expected namespace invalidation to remove the remaining series entry, got removed=%d err=%v", removed, err)
+	}
+}
+
+func TestPurgeLegacyCacheEntries(t *testing.T) {
+	svc := newInspectTestService(t)
+	legacyKey := "0123456789abcdef0123456789abcdef01234567"
+	seriesKey := cacheKey("tvdb", "series", "details", "v12", "eng", "81189")
+	for _, key := range []string{legacyKey, seriesKey} {
+		if err := svc.cache.set(key, "v"); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+	}
+	if err := svc.idCache.set(legacyKey, "v"); err != nil {
+		t.Fatalf("set id: %v", err)
+	}
+
+	svc.purgeLegacyCacheEntries()
+
+	var v string
+	if ok, _ := svc.cache.get(legacyKey, &v); ok {
+		t.Fatal("expected the legacy metadata entry to be purged")
+	}
+	if ok, _ := svc.idCache.get(legacyKey, &v); ok {
+		t.Fatal("expected the legacy id entry to be purged")
+	}
+	if ok, _ := svc.cache.get(seriesKey, &v); !ok {
+		t.Fatal("expected namespaced entries to survive")
+	}
+}
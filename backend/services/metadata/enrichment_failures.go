@@ -0,0 +1,334 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	enrichmentRetryBaseDelay   = 15 * time.Minute
+	enrichmentRetryMaxDelay    = 24 * time.Hour
+	enrichmentRetryMaxAttempts = 10
+	// maxEnrichmentFailures bounds the tracker; the oldest failures are dropped first.
+	maxEnrichmentFailures = 2000
+)
+
+// Enrichment failure sources.
+const (
+	EnrichmentSourceCustomList = "custom_list"
+	EnrichmentSourceTrending   = "trending"
+)
+
+// EnrichmentFailure describes a list item that could not be matched to TVDB
+// metadata and was served with placeholder details.
+type EnrichmentFailure struct {
+	Key           string    `json:"key"`
+	MediaType     string    `json:"mediaType"`
+	Title         string    `json:"title"`
+	Year          int       `json:"year,omitempty"`
+	IMDBID        string    `json:"imdbId,omitempty"`
+	TMDBID        int64     `json:"tmdbId,omitempty"`
+	TVDBID        int64     `json:"tvdbId,omitempty"`
+	Language      string    `json:"language,omitempty"`
+	Source        string    `json:"source"`
+	Reason        string    `json:"reason"`
+	Attempts      int       `json:"attempts"`
+	FirstFailedAt time.Time `json:"firstFailedAt"`
+	LastFailedAt  time.Time `json:"lastFailedAt"`
+	NextRetryAt   time.Time `json:"nextRetryAt,omitempty"` // zero once retries are exhausted
+}
+
+// EnrichmentRetryResult summarizes one retry pass.
+type EnrichmentRetryResult struct {
+	Attempted int `json:"attempted"`
+	Resolved  int `json:"resolved"`
+	Failed    int `json:"failed"`
+}
+
+type enrichmentFailureRecord struct {
+	EnrichmentFailure
+	item     mdblistItem
+	svc      *Service            // language-scoped service that enriched the item
+	cacheIDs map[string]struct{} // list caches holding the placeholder entry
+}
+
+// enrichmentFailureTracker keeps failed enrichments in memory, shared by all
+// language clones of a Service. Failures are rediscovered on the next list
+// enrichment after a restart.
+type enrichmentFailureTracker struct {
+	mu       sync.Mutex
+	failures map[string]*enrichmentFailureRecord
+	retrying bool
+}
+
+func newEnrichmentFailureTracker() *enrichmentFailureTracker {
+	return &enrichmentFailureTracker{failures: make(map[string]*enrichmentFailureRecord)}
+}
+
+// enrichmentFailureKey identifies an item across lists by its most stable ID.
+func enrichmentFailureKey(item mdblistItem, language string) string {
+	mediaType := mdblistItemMediaType(item)
+	var id string
+	switch {
+	case item.IMDBID != "":
+		id = "imdb:" + item.IMDBID
+	case item.TVDBID != nil && *item.TVDBID > 0:
+		id = fmt.Sprintf("tvdb:%d", *item.TVDBID)
+	case item.TMDBID != nil && *item.TMDBID > 0:
+		id = fmt.Sprintf("tmdb:%d", *item.TMDBID)
+	default:
+		id = fmt.Sprintf("title:%s:%d", strings.ToLower(strings.TrimSpace(item.Title)), item.ReleaseYear)
+	}
+	return mediaType + ":" + id + ":" + language
+}
+
+// enrichmentRetryDelay returns the backoff after the given number of failed
+// attempts: 15m, 30m, 1h, ... capped at 24h.
+func enrichmentRetryDelay(attempts int) time.Duration {
+	delay := enrichmentRetryBaseDelay
+	for i := 1; i < attempts && delay < enrichmentRetryMaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, enrichmentRetryMaxDelay)
+}
+
+// record notes a failed enrichment, scheduling its next retry. cacheIDs are
+// list caches known to contain the placeholder.
+func (t *enrichmentFailureTracker) record(svc *Service, item mdblistItem, source, reason string, cacheIDs ...string) {
+	if t == nil {
+		return
+	}
+	key := enrichmentFailureKey(item, svc.client.language)
+	now := time.Now()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	rec, ok := t.failures[key]
+	if !ok {
+		if len(t.failures) >= maxEnrichmentFailures {
+			t.evictOldestLocked()
+		}
+		rec = &enrichmentFailureRecord{
+			EnrichmentFailure: EnrichmentFailure{
+				Key:           key,
+				MediaType:     mdblistItemMediaType(item),
+				Title:         item.Title,
+				Year:          item.ReleaseYear,
+				IMDBID:        item.IMDBID,
+				Language:      svc.client.language,
+				Source:        source,
+				FirstFailedAt: now,
+			},
+			cacheIDs: make(map[string]struct{}),
+		}
+		if item.TMDBID != nil {
+			rec.TMDBID = *item.TMDBID
+		}
+		if item.TVDBID != nil {
+			rec.TVDBID = *item.TVDBID
+		}
+		t.failures[key] = rec
+	}
+	rec.item = item
+	rec.svc = svc
+	rec.Reason = reason
+	rec.Attempts++
+	rec.LastFailedAt = now
+	rec.NextRetryAt = time.Time{}
+	if rec.Attempts < enrichmentRetryMaxAttempts {
+		rec.NextRetryAt = now.Add(enrichmentRetryDelay(rec.Attempts))
+	}
+	for _, id := range cacheIDs {
+		rec.cacheIDs[id] = struct{}{}
+	}
+}
+
+func (t *enrichmentFailureTracker) evictOldestLocked() {
+	var oldestKey string
+	var oldest time.Time
+	for key, rec := range t.failures {
+		if oldestKey == "" || rec.LastFailedAt.Before(oldest) {
+			oldestKey, oldest = key, rec.LastFailedAt
+		}
+	}
+	delete(t.failures, oldestKey)
+}
+
+// attachCache associates a list cache entry with any of its items that are
+// currently failing, so the list is invalidated once they resolve.
+func (t *enrichmentFailureTracker) attachCache(cacheID, language string, items []mdblistItem) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.failures) == 0 {
+		return
+	}
+	for _, item := range items {
+		if rec, ok := t.failures[enrichmentFailureKey(item, language)]; ok {
+			rec.cacheIDs[cacheID] = struct{}{}
+		}
+	}
+}
+
+// resolve removes a failure and returns the list caches that held it.
+func (t *enrichmentFailureTracker) resolve(item mdblistItem, language string) []string {
+	if t == nil {
+		return nil
+	}
+	key := enrichmentFailureKey(item, language)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	rec, ok := t.failures[key]
+	if !ok {
+		return nil
+	}
+	delete(t.failures, key)
+	cacheIDs := make([]string, 0, len(rec.cacheIDs))
+	for id := range rec.cacheIDs {
+		cacheIDs = append(cacheIDs, id)
+	}
+	return cacheIDs
+}
+
+// list returns a snapshot of all failures, most recent first.
+func (t *enrichmentFailureTracker) list() []EnrichmentFailure {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	out := make([]EnrichmentFailure, 0, len(t.failures))
+	for _, rec := range t.failures {
+		out = append(out, rec.EnrichmentFailure)
+	}
+	t.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].LastFailedAt.Equal(out[j].LastFailedAt) {
+			return out[i].LastFailedAt.After(out[j].LastFailedAt)
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+type enrichmentRetry struct {
+	item mdblistItem
+	svc  *Service
+}
+
+// due returns failures whose backoff has elapsed. With force set, every
+// failure is returned, including those that exhausted their retries.
+func (t *enrichmentFailureTracker) due(now time.Time, force bool) []enrichmentRetry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var out []enrichmentRetry
+	for _, rec := range t.failures {
+		if rec.svc == nil {
+			continue
+		}
+		if force || (!rec.NextRetryAt.IsZero() && !now.Before(rec.NextRetryAt)) {
+			out = append(out, enrichmentRetry{item: rec.item, svc: rec.svc})
+		}
+	}
+	return out
+}
+
+// recordEnrichmentFailure tracks an item served without TVDB metadata.
+// Misses in offline mode aren't failures and aren't tracked.
+func (s *Service) recordEnrichmentFailure(item mdblistItem, source, reason string, cacheIDs ...string) {
+	if offlineMode.Load() {
+		return
+	}
+	s.enrichFailures.record(s, item, source, reason, cacheIDs...)
+}
+
+// resolveEnrichmentFailure clears a tracked failure after a successful
+// enrichment and invalidates the list caches still serving its placeholder.
+func (s *Service) resolveEnrichmentFailure(item mdblistItem) {
+	cacheIDs := s.enrichFailures.resolve(item, s.client.language)
+	if len(cacheIDs) == 0 {
+		return
+	}
+	log.Printf("[metadata] enrichment recovered for %q; invalidating %d list cache(s)", item.Title, len(cacheIDs))
+	for _, id := range cacheIDs {
+		_ = s.cache.set(id, []struct{}{})
+	}
+}
+
+// EnrichmentFailures returns items whose TVDB enrichment failed, most recent first.
+func (s *Service) EnrichmentFailures() []EnrichmentFailure {
+	failures := s.enrichFailures.list()
+	if failures == nil {
+		failures = []EnrichmentFailure{}
+	}
+	return failures
+}
+
+// RetryEnrichmentFailures immediately retries every tracked failure,
+// ignoring the backoff schedule.
+func (s *Service) RetryEnrichmentFailures(ctx context.Context) (EnrichmentRetryResult, error) {
+	return s.retryEnrichmentFailures(ctx, true)
+}
+
+func (s *Service) retryEnrichmentFailures(ctx context.Context, force bool) (EnrichmentRetryResult, error) {
+	var result EnrichmentRetryResult
+	t := s.enrichFailures
+	if t == nil {
+		return result, nil
+	}
+	if err := offlineError("enrichment retry"); err != nil {
+		return result, err
+	}
+	t.mu.Lock()
+	if t.retrying {
+		t.mu.Unlock()
+		return result, ErrRetryInProgress
+	}
+	t.retrying = true
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		t.retrying = false
+		t.mu.Unlock()
+	}()
+
+	for _, r := range t.due(time.Now(), force) {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		result.Attempted++
+		// enrichCustomListItem records the outcome; lite mode skips the TMDB
+		// release lookups that do not affect the match.
+		enriched := r.svc.enrichCustomListItem(ctx, r.item, true)
+		if enriched.Title.TVDBID > 0 {
+			result.Resolved++
+		} else {
+			result.Failed++
+		}
+	}
+	if result.Attempted > 0 {
+		log.Printf("[metadata] enrichment retry: attempted=%d resolved=%d failed=%d", result.Attempted, result.Resolved, result.Failed)
+	}
+	return result, nil
+}
+
+// StartEnrichmentRetryWorker periodically retries failed enrichments whose
+// backoff has elapsed.
+func (s *Service) StartEnrichmentRetryWorker(interval time.Duration) {
+	if s.demo || s.enrichFailures == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			_, _ = s.retryEnrichmentFailures(context.Background(), false)
+		}
+	}()
+}
@@ -0,0 +1,99 @@
+package metadata
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"novastream/models"
+)
+
+func TestEnrichmentRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 15 * time.Minute},
+		{2, 30 * time.Minute},
+		{4, 2 * time.Hour},
+		{8, 24 * time.Hour},
+		{20, 24 * time.Hour},
+	}
+	for _, tt := range tests {
+		if got := enrichmentRetryDelay(tt.attempts); got != tt.want {
+			t.Errorf("enrichmentRetryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestEnrichmentFailureTrackingAndRecovery(t *testing.T) {
+	svc := &Service{
+		client:         &tvdbClient{language: "eng"},
+		cache:          newFileCache(t.TempDir(), 24),
+		enrichFailures: newEnrichmentFailureTracker(),
+	}
+	tvdbID := int64(42)
+	item := mdblistItem{ID: 7, Title: "Obscure Film", ReleaseYear: 2024, IMDBID: "tt0000042", MediaType: "movie", TVDBID: &tvdbID}
+
+	svc.recordEnrichmentFailure(item, EnrichmentSourceCustomList, "tvdb search returned no results")
+	svc.recordEnrichmentFailure(item, EnrichmentSourceCustomList, "tvdb movie 42 lookup failed")
+
+	failures := svc.EnrichmentFailures()
+	if len(failures) != 1 {
+		t.Fatalf("expected 1 failure, got %d", len(failures))
+	}
+	f := failures[0]
+	if f.Attempts != 2 || f.Reason != "tvdb movie 42 lookup failed" || f.TVDBID != 42 || f.Source != EnrichmentSourceCustomList {
+		t.Fatalf("unexpected failure record: %+v", f)
+	}
+	if wait := time.Until(f.NextRetryAt); wait < 29*time.Minute || wait > 30*time.Minute {
+		t.Fatalf("expected second retry in ~30m, got %v", wait)
+	}
+	if due := svc.enrichFailures.due(time.Now(), false); len(due) != 0 {
+		t.Fatalf("expected no failures due before backoff, got %d", len(due))
+	}
+	if due := svc.enrichFailures.due(f.NextRetryAt, false); len(due) != 1 {
+		t.Fatalf("expected failure due after backoff, got %d", len(due))
+	}
+
+	// The list cache holding the placeholder is invalidated once the item resolves.
+	listKey := "custom-list"
+	if err := svc.cache.set(listKey, []models.TrendingItem{{Rank: 1}}); err != nil {
+		t.Fatalf("cache set: %v", err)
+	}
+	svc.enrichFailures.attachCache(listKey, "eng", []mdblistItem{item})
+
+	svc.resolveEnrichmentFailure(item)
+	if failures := svc.EnrichmentFailures(); len(failures) != 0 {
+		t.Fatalf("expected failure to be cleared, got %d", len(failures))
+	}
+	var cached []models.TrendingItem
+	if ok, _ := svc.cache.get(listKey, &cached); ok && len(cached) > 0 {
+		t.Fatalf("expected list cache to be invalidated, got %d items", len(cached))
+	}
+}
+
+func TestEnrichmentFailureRetriesStopAfterMaxAttempts(t *testing.T) {
+	svc := &Service{client: &tvdbClient{language: "eng"}, enrichFailures: newEnrichmentFailureTracker()}
+	item := mdblistItem{Title: "Nowhere", MediaType: "show"}
+	for i := 0; i < enrichmentRetryMaxAttempts; i++ {
+		svc.recordEnrichmentFailure(item, EnrichmentSourceTrending, "no tvdb match")
+	}
+	f := svc.EnrichmentFailures()[0]
+	if !f.NextRetryAt.IsZero() {
+		t.Fatalf("expected retries to be exhausted, next retry at %v", f.NextRetryAt)
+	}
+	if due := svc.enrichFailures.due(time.Now().Add(48*time.Hour), false); len(due) != 0 {
+		t.Fatalf("expected exhausted failure not to be scheduled, got %d", len(due))
+	}
+	if due := svc.enrichFailures.due(time.Now(), true); len(due) != 1 {
+		t.Fatalf("expected forced retry to include exhausted failure, got %d", len(due))
+	}
+}
+
+func TestRetryEnrichmentFailuresWithoutTrackerIsNoop(t *testing.T) {
+	result, err := (&Service{}).RetryEnrichmentFailures(context.Background())
+	if err != nil || result.Attempted != 0 {
+		t.Fatalf("expected no-op retry, got %+v err=%v", result, err)
+	}
+}
@@ -0,0 +1,66 @@
+package metadata
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Error kinds returned by the metadata service and its upstream clients.
+// Callers should match them with errors.Is; the concrete errors keep their
+// descriptive messages.
+var (
+	ErrNotFound            = errors.New("not found")
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	ErrNotConfigured       = errors.New("not configured")
+	ErrRateLimited         = errors.New("rate limited")
+	// ErrRetryInProgress is returned when an enrichment retry pass is already running.
+	ErrRetryInProgress = errors.New("enrichment retry already in progress")
+	// ErrUnknownCacheNamespace is returned for a cache namespace that does not exist.
+	ErrUnknownCacheNamespace = errors.New("unknown cache namespace")
+	// ErrUnknownTrendingSource is returned for a trending source or list no
+	// registered provider serves.
+	ErrUnknownTrendingSource = errors.New("unknown trending source")
+	// ErrOffline is returned for cache misses while offline mode blocks
+	// upstream requests.
+	ErrOffline = errors.New("offline mode")
+)
+
+var errTMDBNotConfigured = fmt.Errorf("tmdb api key %w", ErrNotConfigured)
+
+// kindError carries a descriptive message while unwrapping to one of the
+// error kinds above.
+type kindError struct {
+	msg  string
+	kind error
+}
+
+func (e *kindError) Error() string { return e.msg }
+func (e *kindError) Unwrap() error { return e.kind }
+
+func newKindError(kind error, format string, args ...any) error {
+	return &kindError{msg: fmt.Sprintf(format, args...), kind: kind}
+}
+
+// upstreamStatusKind classifies an upstream HTTP status code. Statuses that
+// don't map to a kind return nil.
+func upstreamStatusKind(status int) error {
+	switch {
+	case status == http.StatusNotFound:
+		return ErrNotFound
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		// The API key was rejected, which only the admin can fix.
+		return ErrNotConfigured
+	case status >= 500:
+		return ErrUpstreamUnavailable
+	}
+	return nil
+}
+
+// upstreamError formats an upstream failure and tags it with the kind for
+// the response status.
+func upstreamError(status int, format string, args ...any) error {
+	return newKindError(upstreamStatusKind(status), format, args...)
+}
@@ -0,0 +1,36 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestMovieDetailsNotFoundIsTyped(t *testing.T) {
+	rt := &countingRoundTripper{status: http.StatusNotFound, body: `{"status_message":"not found"}`}
+	c := newTMDBClient("test-key", "en", &http.Client{Transport: rt}, newFileCache(t.TempDir(), 24))
+
+	_, err := c.movieDetails(context.Background(), 1)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestUpstreamStatusKind(t *testing.T) {
+	cases := map[int]error{
+		http.StatusNotFound:           ErrNotFound,
+		http.StatusTooManyRequests:    ErrRateLimited,
+		http.StatusUnauthorized:       ErrNotConfigured,
+		http.StatusServiceUnavailable: ErrUpstreamUnavailable,
+		http.StatusBadRequest:         nil,
+	}
+	for status, want := range cases {
+		if got := upstreamStatusKind(status); got != want {
+			t.Errorf("upstreamStatusKind(%d) = %v, want %v", status, got, want)
+		}
+	}
+	if !errors.Is(errTMDBNotConfigured, ErrNotConfigured) || errTMDBNotConfigured.Error() != "tmdb api key not configured" {
+		t.Fatalf("unexpected tmdb not configured error: %v", errTMDBNotConfigured)
+	}
+}
@@ -0,0 +1,412 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"novastream/models"
+)
+
+const (
+	externalProviderDefaultTimeout = 8 * time.Second
+	externalProviderMaxTimeout     = 30 * time.Second
+	// externalProviderMaxBody caps how much of a response is read.
+	externalProviderMaxBody = 2 << 20
+	// externalProviderFailureThreshold is the number of failures in a row
+	// that opens a provider's circuit.
+	externalProviderFailureThreshold = 5
+	// externalProviderCooldown is how long an open circuit refuses requests
+	// before letting one through to test the provider.
+	externalProviderCooldown = time.Minute
+)
+
+// ErrProviderUnavailable is returned while an external provider's circuit is
+// open after repeated failures.
+var ErrProviderUnavailable = errors.New("metadata provider temporarily unavailable")
+
+// ExternalProviderConfig declares an HTTP metadata provider. See
+// config.ExternalMetadataProvider for the meaning of each field.
+type ExternalProviderConfig struct {
+	Name         string
+	BaseURL      string
+	AuthHeader   string
+	AuthValue    string
+	SearchPath   string
+	DetailsPath  string
+	ImageBaseURL string
+	Timeout      time.Duration
+	Mapping      ExternalProviderMapping
+}
+
+// ExternalProviderMapping holds dot-separated paths into a provider's JSON
+// responses.
+type ExternalProviderMapping struct {
+	Results   string
+	Item      string
+	ID        string
+	Name      string
+	MediaType string
+	Year      string
+	Overview  string
+	Poster    string
+	Backdrop  string
+	IMDBID    string
+	TMDBID    string
+	TVDBID    string
+}
+
+// externalProvider is a MetadataProvider backed by a declared HTTP API. It is
+// sandboxed to its config: requests only go to the base URL's host, with the
+// provider's own credentials, a per-request timeout and a capped response
+// size, and a circuit breaker stops calling it while it keeps failing.
+type externalProvider struct {
+	cfg     ExternalProviderConfig
+	base    *url.URL
+	httpc   *http.Client
+	breaker *circuitBreaker
+}
+
+// newExternalProvider validates cfg and builds its provider.
+func newExternalProvider(cfg ExternalProviderConfig) (*externalProvider, error) {
+	cfg.Name = strings.ToLower(strings.TrimSpace(cfg.Name))
+	base, err := url.Parse(strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/"))
+	if err != nil || (base.Scheme != "http" && base.Scheme != "https") || base.Host == "" {
+		return nil, fmt.Errorf("provider %q: base URL must be an absolute http(s) URL", cfg.Name)
+	}
+	for _, path := range []string{cfg.SearchPath, cfg.DetailsPath} {
+		if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") || strings.Contains(path, "://") {
+			return nil, fmt.Errorf("provider %q: paths must be relative to the base URL", cfg.Name)
+		}
+	}
+	if cfg.Mapping.ID == "" || cfg.Mapping.Name == "" {
+		return nil, fmt.Errorf("provider %q: mapping needs id and name", cfg.Name)
+	}
+	if cfg.AuthHeader != "" && !validHeaderName(cfg.AuthHeader) {
+		return nil, fmt.Errorf("provider %q: invalid auth header %q", cfg.Name, cfg.AuthHeader)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = externalProviderDefaultTimeout
+	}
+	if cfg.Timeout > externalProviderMaxTimeout {
+		cfg.Timeout = externalProviderMaxTimeout
+	}
+	host := base.Host
+	return &externalProvider{
+		cfg:  cfg,
+		base: base,
+		httpc: &http.Client{
+			Timeout: cfg.Timeout,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				if req.URL.Host != host {
+					return fmt.Errorf("redirect to %s leaves the provider's host", req.URL.Host)
+				}
+				if len(via) >= 3 {
+					return errors.New("too many redirects")
+				}
+				return nil
+			},
+		},
+		breaker: &circuitBreaker{threshold: externalProviderFailureThreshold, cooldown: externalProviderCooldown},
+	}, nil
+}
+
+// validHeaderName rejects headers the provider mustn't control.
+func validHeaderName(name string) bool {
+	switch strings.ToLower(name) {
+	case "host", "cookie", "content-length", "transfer-encoding", "connection":
+		return false
+	}
+	for _, r := range name {
+		if !(r == '-' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return name != ""
+}
+
+func (p *externalProvider) Name() string { return p.cfg.Name }
+
+func (p *externalProvider) Search(ctx context.Context, query, mediaType string) ([]models.Title, error) {
+	var body any
+	if err := p.get(ctx, p.cfg.SearchPath, map[string]string{"query": query, "mediaType": mediaType}, &body); err != nil {
+		return nil, err
+	}
+	items, _ := jsonPath(body, p.cfg.Mapping.Results).([]any)
+	titles := make([]models.Title, 0, len(items))
+	for _, item := range items {
+		if title, ok := p.mapTitle(item, mediaType); ok {
+			titles = append(titles, title)
+		}
+	}
+	return titles, nil
+}
+
+func (p *externalProvider) Details(ctx context.Context, mediaType, id string) (*models.Title, error) {
+	var body any
+	if err := p.get(ctx, p.cfg.DetailsPath, map[string]string{"id": id, "mediaType": mediaType}, &body); err != nil {
+		return nil, err
+	}
+	title, ok := p.mapTitle(jsonPath(body, p.cfg.Mapping.Item), mediaType)
+	if !ok {
+		return nil, nil
+	}
+	return &title, nil
+}
+
+// Artwork returns the poster and backdrop mapped from the details response.
+func (p *externalProvider) Artwork(ctx context.Context, mediaType, id string) ([]models.Image, error) {
+	title, err := p.Details(ctx, mediaType, id)
+	if err != nil || title == nil {
+		return nil, err
+	}
+	var images []models.Image
+	if title.Poster != nil {
+		images = append(images, *title.Poster)
+	}
+	if title.Backdrop != nil {
+		images = append(images, *title.Backdrop)
+	}
+	return images, nil
+}
+
+// ExternalIDs returns the IDs mapped from the details response.
+func (p *externalProvider) ExternalIDs(ctx context.Context, mediaType, id string) (ProviderIDs, error) {
+	if p.cfg.Mapping.IMDBID == "" && p.cfg.Mapping.TMDBID == "" && p.cfg.Mapping.TVDBID == "" {
+		return ProviderIDs{}, nil
+	}
+	title, err := p.Details(ctx, mediaType, id)
+	if err != nil || title == nil {
+		return ProviderIDs{}, err
+	}
+	return ProviderIDs{IMDBID: title.IMDBID, TMDBID: title.TMDBID, TVDBID: title.TVDBID}, nil
+}
+
+// get fills the path's placeholders, fetches it from the provider's host and
+// decodes the JSON response into out.
+func (p *externalProvider) get(ctx context.Context, path string, values map[string]string, out any) error {
+	if !p.breaker.allow() {
+		return fmt.Errorf("%s: %w", p.cfg.Name, ErrProviderUnavailable)
+	}
+	err := p.fetch(ctx, path, values, out)
+	if err != nil && ctx.Err() != nil && !errors.Is(err, context.DeadlineExceeded) {
+		// The caller gave up; that says nothing about the provider.
+		p.breaker.abort()
+		return err
+	}
+	p.breaker.record(err == nil)
+	return err
+}
+
+func (p *externalProvider) fetch(ctx context.Context, path string, values map[string]string, out any) error {
+	for key, value := range values {
+		path = strings.ReplaceAll(path, "{"+key+"}", url.QueryEscape(value))
+	}
+	target, err := p.base.Parse(p.base.Path + path)
+	if err != nil || target.Host != p.base.Host {
+		return fmt.Errorf("%s: invalid request path", p.cfg.Name)
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.cfg.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.AuthHeader != "" {
+		req.Header.Set(p.cfg.AuthHeader, p.cfg.AuthValue)
+	}
+	resp, err := p.httpc.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", p.cfg.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: unexpected status %d", p.cfg.Name, resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, externalProviderMaxBody)).Decode(out); err != nil {
+		return fmt.Errorf("%s: decode response: %w", p.cfg.Name, err)
+	}
+	return nil
+}
+
+// mapTitle builds a title from one provider record using the mapping.
+func (p *externalProvider) mapTitle(item any, mediaType string) (models.Title, bool) {
+	m := p.cfg.Mapping
+	title := models.Title{
+		ID:        jsonString(item, m.ID),
+		Name:      jsonString(item, m.Name),
+		MediaType: mediaType,
+		Overview:  jsonString(item, m.Overview),
+		IMDBID:    jsonString(item, m.IMDBID),
+	}
+	if title.ID == "" || title.Name == "" {
+		return models.Title{}, false
+	}
+	if m.MediaType != "" {
+		switch strings.ToLower(jsonString(item, m.MediaType)) {
+		case "movie", "movies", "film":
+			title.MediaType = "movie"
+		case "series", "tv", "show":
+			title.MediaType = "series"
+		}
+	}
+	if m.Year != "" {
+		title.Year = extractYearCandidate(jsonString(item, m.Year))
+	}
+	title.TMDBID, _ = strconv.ParseInt(jsonString(item, m.TMDBID), 10, 64)
+	title.TVDBID, _ = strconv.ParseInt(jsonString(item, m.TVDBID), 10, 64)
+	if poster := p.imageURL(jsonString(item, m.Poster)); poster != "" {
+		title.Poster = &models.Image{URL: poster, Type: "poster"}
+	}
+	if backdrop := p.imageURL(jsonString(item, m.Backdrop)); backdrop != "" {
+		title.Backdrop = &models.Image{URL: backdrop, Type: "backdrop"}
+	}
+	return title, true
+}
+
+func (p *externalProvider) imageURL(value string) string {
+	if value == "" || strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://") {
+		return value
+	}
+	if p.cfg.ImageBaseURL == "" {
+		return ""
+	}
+	return strings.TrimRight(p.cfg.ImageBaseURL, "/") + "/" + strings.TrimLeft(value, "/")
+}
+
+// jsonPath follows a dot-separated path of object keys and array indexes
+// through decoded JSON. An empty path returns v itself.
+func jsonPath(v any, path string) any {
+	if path == "" {
+		return v
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch node := v.(type) {
+		case map[string]any:
+			v = node[key]
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil
+			}
+			v = node[i]
+		default:
+			return nil
+		}
+	}
+	return v
+}
+
+// jsonString returns the value at path as a string; numbers are formatted
+// without a fraction when they have none.
+func jsonString(v any, path string) string {
+	if path == "" {
+		return ""
+	}
+	switch value := jsonPath(v, path).(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	default:
+		return ""
+	}
+}
+
+// circuitBreaker stops calls to a failing provider. After threshold failures
+// in a row it opens for cooldown, then lets a single call through; success
+// closes it again and failure reopens it.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	probing   bool
+	now       func() time.Time
+}
+
+func (b *circuitBreaker) clock() time.Time {
+	if b.now != nil {
+		return b.now()
+	}
+	return time.Now()
+}
+
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return true
+	}
+	if b.probing || b.clock().Before(b.openUntil) {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+// abort ends a call without counting it: an interrupted probe leaves the
+// circuit ready for the next one.
+func (b *circuitBreaker) abort() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *circuitBreaker) record(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+	if success {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = b.clock().Add(b.cooldown)
+	}
+}
+
+// SetExternalProviders replaces the HTTP providers declared in settings.
+// Invalid declarations are logged and skipped.
+func (s *Service) SetExternalProviders(cfgs []ExternalProviderConfig) {
+	if s.metadataProviders == nil {
+		return
+	}
+	s.metadataProviders.mu.Lock()
+	previous := s.metadataProviders.external
+	s.metadataProviders.external = nil
+	s.metadataProviders.mu.Unlock()
+	for _, name := range previous {
+		s.UnregisterMetadataProvider(name)
+	}
+
+	var registered []string
+	for _, cfg := range cfgs {
+		provider, err := newExternalProvider(cfg)
+		if err == nil {
+			err = s.RegisterMetadataProvider(provider)
+		}
+		if err != nil {
+			log.Printf("[metadata] external provider skipped: %v", err)
+			continue
+		}
+		registered = append(registered, provider.Name())
+	}
+	s.metadataProviders.mu.Lock()
+	s.metadataProviders.external = registered
+	s.metadataProviders.mu.Unlock()
+}
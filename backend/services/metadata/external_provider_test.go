@@ -0,0 +1,197 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestExternalProviderMapsResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/search":
+			if r.URL.Query().Get("q") != "let the bullets" {
+				t.Errorf("unexpected query %q", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"data":{"items":[
+				{"id":1291843,"title":"Let the Bullets Fly","release":"2010-12-16","images":{"poster":"/p/1.jpg"}},
+				{"title":"missing id"}
+			]}}`))
+		case "/api/movie/1291843":
+			w.Write([]byte(`{"subject":{"id":"1291843","title":"Let the Bullets Fly","summary":"Bandits.","imdb":"tt1533117"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := newExternalProvider(ExternalProviderConfig{
+		Name:         "Douban",
+		BaseURL:      server.URL + "/api",
+		AuthHeader:   "X-Api-Key",
+		AuthValue:    "secret",
+		SearchPath:   "/search?q={query}&type={mediaType}",
+		DetailsPath:  "/{mediaType}/{id}",
+		ImageBaseURL: "https://img.test",
+		Mapping: ExternalProviderMapping{
+			Results: "data.items", Item: "subject",
+			ID: "id", Name: "title", Year: "release", Overview: "summary", Poster: "images.poster", IMDBID: "imdb",
+		},
+	})
+	if err != nil {
+		t.Fatalf("newExternalProvider: %v", err)
+	}
+
+	titles, err := provider.Search(context.Background(), "let the bullets", "movie")
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(titles) != 1 || titles[0].ID != "1291843" || titles[0].Year != 2010 || titles[0].Poster == nil || titles[0].Poster.URL != "https://img.test/p/1.jpg" {
+		t.Fatalf("unexpected titles %+v", titles)
+	}
+	ids, err := provider.ExternalIDs(context.Background(), "movie", "1291843")
+	if err != nil || ids.IMDBID != "tt1533117" {
+		t.Fatalf("unexpected ids %+v err=%v", ids, err)
+	}
+
+	svc := &Service{metadataProviders: newMetadataProviderRegistry()}
+	svc.SetExternalProviders([]ExternalProviderConfig{provider.cfg, {Name: "bad", BaseURL: "ftp://x"}})
+	if got := svc.MetadataProviders(); len(got) != 1 || got[0] != "douban" {
+		t.Fatalf("expected only the valid provider, got %v", got)
+	}
+	svc.SetExternalProviders(nil)
+	if got := svc.MetadataProviders(); len(got) != 0 {
+		t.Fatalf("expected the providers to be removed, got %v", got)
+	}
+}
+
+func TestExternalProviderSandbox(t *testing.T) {
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request escaped the provider's host")
+	}))
+	defer other.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, other.URL+"/steal", http.StatusFound)
+	}))
+	defer server.Close()
+
+	base := ExternalProviderConfig{Name: "x", BaseURL: server.URL, SearchPath: "/s?q={query}", DetailsPath: "/d/{id}", Mapping: ExternalProviderMapping{ID: "id", Name: "name"}}
+	for _, path := range []string{"https://evil.test/s", "//evil.test/s", "s"} {
+		cfg := base
+		cfg.SearchPath = path
+		if _, err := newExternalProvider(cfg); err == nil {
+			t.Errorf("expected path %q to be refused", path)
+		}
+	}
+	cfg := base
+	cfg.AuthHeader = "Host"
+	if _, err := newExternalProvider(cfg); err == nil {
+		t.Error("expected the Host header to be refused")
+	}
+
+	provider, err := newExternalProvider(base)
+	if err != nil {
+		t.Fatalf("newExternalProvider: %v", err)
+	}
+	if _, err := provider.Search(context.Background(), "x", "movie"); err == nil {
+		t.Fatal("expected an off-host redirect to fail")
+	}
+}
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	b := &circuitBreaker{threshold: 2, cooldown: time.Minute, now: func() time.Time { return now }}
+
+	for i := 0; i < 2; i++ {
+		if !b.allow() {
+			t.Fatalf("expected call %d to be allowed", i)
+		}
+		b.record(false)
+	}
+	if b.allow() {
+		t.Fatal("expected the circuit to open")
+	}
+
+	now = now.Add(time.Minute)
+	if !b.allow() {
+		t.Fatal("expected a probe after the cooldown")
+	}
+	if b.allow() {
+		t.Fatal("expected only one probe at a time")
+	}
+	b.record(false)
+	if b.allow() {
+		t.Fatal("expected a failed probe to reopen the circuit")
+	}
+
+	now = now.Add(time.Minute)
+	b.allow()
+	b.record(true)
+	if !b.allow() || !b.allow() {
+		t.Fatal("expected a successful probe to close the circuit")
+	}
+}
+
+func TestExternalProviderOpenCircuitSkipsRequests(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	provider, err := newExternalProvider(ExternalProviderConfig{Name: "x", BaseURL: server.URL, SearchPath: "/s", DetailsPath: "/d", Mapping: ExternalProviderMapping{ID: "id", Name: "name"}})
+	if err != nil {
+		t.Fatalf("newExternalProvider: %v", err)
+	}
+	for i := 0; i < externalProviderFailureThreshold+2; i++ {
+		_, err = provider.Search(context.Background(), "q", "movie")
+	}
+	if calls != externalProviderFailureThreshold || !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("expected the circuit to stop requests after %d failures, got calls=%d err=%v", externalProviderFailureThreshold, calls, err)
+	}
+}
+
+func TestExternalProviderCancelledProbeAllowsNextProbe(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := false
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if !cancelled {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		cancel()
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	provider, err := newExternalProvider(ExternalProviderConfig{Name: "x", BaseURL: server.URL, SearchPath: "/s", DetailsPath: "/d", Mapping: ExternalProviderMapping{ID: "id", Name: "name"}})
+	if err != nil {
+		t.Fatalf("newExternalProvider: %v", err)
+	}
+	now := time.Now()
+	provider.breaker.now = func() time.Time { return now }
+	for i := 0; i < externalProviderFailureThreshold; i++ {
+		provider.Search(context.Background(), "q", "movie")
+	}
+
+	now = now.Add(externalProviderCooldown)
+	cancelled = true
+	if _, err := provider.Search(ctx, "q", "movie"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the probe to be cancelled, got %v", err)
+	}
+	if !provider.breaker.allow() {
+		t.Fatal("expected a cancelled probe to let the next probe through")
+	}
+	if calls != externalProviderFailureThreshold+1 {
+		t.Fatalf("expected %d requests, got %d", externalProviderFailureThreshold+1, calls)
+	}
+}
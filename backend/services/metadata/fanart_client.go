@@ -0,0 +1,425 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"novastream/models"
+)
+
+const fanartBaseURL = "https://webservice.fanart.tv/v3"
+
+// Artwork provider identifiers used in MetadataSettings.ArtworkProviderPriority.
+const (
+	artworkProviderTMDB   = "tmdb"
+	artworkProviderFanart = "fanart"
+	artworkProviderTVDB   = "tvdb"
+)
+
+// defaultArtworkProviderPriority is used when no priority has been configured.
+// TMDB logos stay preferred so existing installs see no change until they opt in.
+var defaultArtworkProviderPriority = []string{artworkProviderTMDB, artworkProviderFanart, artworkProviderTVDB}
+
+// fanartClient fetches clearlogos, banners, and disc art from Fanart.tv.
+type fanartClient struct {
+	apiKey   string
+	language string
+	httpc    *http.Client
+
+	// Rate limiting
+	throttleMu  sync.Mutex
+	lastRequest time.Time
+	minInterval time.Duration
+}
+
+// fanartImage is a single artwork entry in a Fanart.tv response.
+// Likes is returned as a string by the API.
+type fanartImage struct {
+	ID    string `json:"id"`
+	URL   string `json:"url"`
+	Lang  string `json:"lang"`
+	Likes string `json:"likes"`
+}
+
+type fanartMovieResponse struct {
+	HDMovieLogo []fanartImage `json:"hdmovielogo"`
+	MovieLogo   []fanartImage `json:"movielogo"`
+	MovieBanner []fanartImage `json:"moviebanner"`
+	MovieDisc   []fanartImage `json:"moviedisc"`
+}
+
+type fanartTVResponse struct {
+	HDTVLogo  []fanartImage `json:"hdtvlogo"`
+	ClearLogo []fanartImage `json:"clearlogo"`
+	TVBanner  []fanartImage `json:"tvbanner"`
+}
+
+// fanartArtworkResult is the cached, already-selected artwork for a title.
+type fanartArtworkResult struct {
+	Logo    *models.Image `json:"logo,omitempty"`
+	Banner  *models.Image `json:"banner,omitempty"`
+	DiscArt *models.Image `json:"discArt,omitempty"`
+}
+
+func newFanartClient(apiKey, language string, httpc *http.Client) *fanartClient {
+	if httpc == nil {
+		httpc = &http.Client{Timeout: 15 * time.Second}
+	}
+	return &fanartClient{
+		apiKey:      strings.TrimSpace(apiKey),
+		language:    language,
+		httpc:       httpc,
+		minInterval: 50 * time.Millisecond,
+	}
+}
+
+func (c *fanartClient) isConfigured() bool {
+	return c != nil && c.apiKey != ""
+}
+
+// preferredLanguage returns the 2-letter language code Fanart.tv uses in its "lang" field.
+func (c *fanartClient) preferredLanguage() string {
+	lang := strings.TrimSpace(strings.ReplaceAll(c.language, "_", "-"))
+	if len(lang) == 3 {
+		return iso639_2to1(lang)
+	}
+	if len(lang) >= 2 {
+		return strings.ToLower(lang[:2])
+	}
+	return "en"
+}
+
+// fetchArtwork retrieves artwork for a movie (keyed by TMDB or IMDB ID) or a
+// series (keyed by TVDB ID). A title unknown to Fanart.tv yields an empty result.
+func (c *fanartClient) fetchArtwork(ctx context.Context, mediaType, id string) (*fanartArtworkResult, error) {
+	if !c.isConfigured() {
+		return nil, fmt.Errorf("fanart api key %w", ErrNotConfigured)
+	}
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return nil, errors.New("fanart id is required")
+	}
+
+	segment := "tv"
+	if strings.EqualFold(mediaType, "movie") {
+		segment = "movies"
+	}
+	endpoint, err := url.JoinPath(fanartBaseURL, segment, id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint += "?api_key=" + url.QueryEscape(c.apiKey)
+
+	lang := c.preferredLanguage()
+	result := &fanartArtworkResult{}
+	if segment == "movies" {
+		var payload fanartMovieResponse
+		found, err := c.doGET(ctx, endpoint, &payload)
+		if err != nil || !found {
+			return result, err
+		}
+		result.Logo = selectFanartImage(lang, "logo", payload.HDMovieLogo, payload.MovieLogo)
+		result.Banner = selectFanartImage(lang, "banner", payload.MovieBanner)
+		result.DiscArt = selectFanartImage(lang, "discart", payload.MovieDisc)
+		return result, nil
+	}
+
+	var payload fanartTVResponse
+	found, err := c.doGET(ctx, endpoint, &payload)
+	if err != nil || !found {
+		return result, err
+	}
+	result.Logo = selectFanartImage(lang, "logo", payload.HDTVLogo, payload.ClearLogo)
+	result.Banner = selectFanartImage(lang, "banner", payload.TVBanner)
+	return result, nil
+}
+
+// doGET performs a throttled GET. It reports found=false when Fanart.tv has no
+// entry for the requested ID.
+func (c *fanartClient) doGET(ctx context.Context, endpoint string, v any) (bool, error) {
+	c.throttleMu.Lock()
+	wait := c.minInterval - time.Since(c.lastRequest)
+	if wait > 0 {
+		c.lastRequest = time.Now().Add(wait)
+	} else {
+		c.lastRequest = time.Now()
+		wait = 0
+	}
+	c.throttleMu.Unlock()
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+
+	if err := offlineError("fanart.tv"); err != nil {
+		return false, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := c.httpc.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode >= 400 {
+		return false, fmt.Errorf("fanart request failed: %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// selectFanartImage picks the best image across the given groups. Groups are
+// ordered by quality (e.g. HD logos before standard ones); within the combined
+// set the preferred language wins, then English, then language-neutral art,
+// then the most liked.
+func selectFanartImage(preferredLang, imageType string, groups ...[]fanartImage) *models.Image {
+	type candidate struct {
+		img   fanartImage
+		group int
+		likes int
+	}
+	var candidates []candidate
+	for gi, group := range groups {
+		for _, img := range group {
+			if strings.TrimSpace(img.URL) == "" {
+				continue
+			}
+			likes, _ := strconv.Atoi(img.Likes)
+			candidates = append(candidates, candidate{img: img, group: gi, likes: likes})
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	langRank := func(lang string) int {
+		switch {
+		case lang == preferredLang:
+			return 0
+		case lang == "en":
+			return 1
+		case lang == "" || lang == "00":
+			return 2
+		default:
+			return 3
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		ri, rj := langRank(candidates[i].img.Lang), langRank(candidates[j].img.Lang)
+		if ri != rj {
+			return ri < rj
+		}
+		if candidates[i].group != candidates[j].group {
+			return candidates[i].group < candidates[j].group
+		}
+		return candidates[i].likes > candidates[j].likes
+	})
+
+	best := candidates[0].img
+	lang := best.Lang
+	if lang == "00" {
+		lang = ""
+	}
+	return &models.Image{
+		URL:                best.URL,
+		Type:               imageType,
+		Language:           lang,
+		IsFallbackLanguage: lang != "" && lang != preferredLang,
+	}
+}
+
+// fanartPreviewURL returns the downscaled preview variant of a Fanart.tv asset,
+// which is much cheaper to download for brightness analysis.
+func fanartPreviewURL(imageURL string) string {
+	return strings.Replace(imageURL, "/fanart/", "/preview/", 1)
+}
+
+// normalizeArtworkProviderPriority lowercases and dedupes the configured order,
+// dropping unknown providers. Providers not listed keep their default relative order
+// after the configured ones.
+func normalizeArtworkProviderPriority(priority []string) []string {
+	seen := make(map[string]bool, len(defaultArtworkProviderPriority))
+	out := make([]string, 0, len(defaultArtworkProviderPriority))
+	for _, p := range priority {
+		p = strings.ToLower(strings.TrimSpace(p))
+		switch p {
+		case artworkProviderTMDB, artworkProviderFanart, artworkProviderTVDB:
+		default:
+			continue
+		}
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+		out = append(out, p)
+	}
+	for _, p := range defaultArtworkProviderPriority {
+		if !seen[p] {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// artworkProviderForURL infers which provider served an image from its host.
+func artworkProviderForURL(imageURL string) string {
+	lower := strings.ToLower(imageURL)
+	switch {
+	case strings.Contains(lower, "image.tmdb.org"):
+		return artworkProviderTMDB
+	case strings.Contains(lower, "fanart.tv"):
+		return artworkProviderFanart
+	case strings.Contains(lower, "thetvdb.com"):
+		return artworkProviderTVDB
+	default:
+		return ""
+	}
+}
+
+// artworkProviderRank returns the index of provider in priority; unknown providers sort last.
+func artworkProviderRank(priority []string, provider string) int {
+	for i, p := range priority {
+		if p == provider {
+			return i
+		}
+	}
+	return len(priority)
+}
+
+// SetArtworkProviders configures the Fanart.tv API key and the order in which
+// artwork providers are preferred when more than one supplies the same image type.
+func (s *Service) SetArtworkProviders(fanartAPIKey string, priority []string) {
+	language := ""
+	if s.client != nil {
+		language = s.client.language
+	}
+	normalized := normalizeArtworkProviderPriority(priority)
+	s.artworkMu.Lock()
+	s.fanart = newFanartClient(fanartAPIKey, language, &http.Client{Timeout: 15 * time.Second})
+	s.artworkPriority = normalized
+	s.artworkMu.Unlock()
+	log.Printf("[metadata] artwork providers configured (fanart=%v, priority=%v)", s.fanart.isConfigured(), normalized)
+}
+
+func (s *Service) artworkProviders() (*fanartClient, []string) {
+	s.artworkMu.RLock()
+	defer s.artworkMu.RUnlock()
+	priority := s.artworkPriority
+	if len(priority) == 0 {
+		priority = defaultArtworkProviderPriority
+	}
+	return s.fanart, priority
+}
+
+// cachedFetchFanartArtwork fetches Fanart.tv artwork with file caching. Empty
+// results are cached too so titles Fanart.tv doesn't know about aren't re-requested.
+func (s *Service) cachedFetchFanartArtwork(ctx context.Context, fanart *fanartClient, mediaType, id string) (*fanartArtworkResult, error) {
+	key := cacheKey("fanart", "artwork", "v1", fanart.preferredLanguage(), mediaType, id)
+	var cached fanartArtworkResult
+	if ok, _ := s.cache.get(key, &cached); ok {
+		return &cached, nil
+	}
+	value, err := s.singleflightCachedFetch(ctx, key, func() (any, error) {
+		var cached fanartArtworkResult
+		if ok, _ := s.cache.get(key, &cached); ok {
+			return &cached, nil
+		}
+		result, err := fanart.fetchArtwork(ctx, mediaType, id)
+		if err != nil {
+			return nil, err
+		}
+		if result.Logo != nil && s.tmdb != nil {
+			result.Logo.IsDark = s.tmdb.isImageDark(ctx, fanartPreviewURL(result.Logo.URL))
+		}
+		_ = s.cache.set(key, result)
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result, _ := value.(*fanartArtworkResult)
+	return result, nil
+}
+
+// applyFanartArtwork layers Fanart.tv artwork onto title. Banners and disc art
+// only come from Fanart.tv and fill in when missing; the logo is replaced when
+// Fanart.tv ranks above the provider that supplied the current one.
+func (s *Service) applyFanartArtwork(ctx context.Context, title *models.Title) bool {
+	if title == nil {
+		return false
+	}
+	fanart, priority := s.artworkProviders()
+	if !fanart.isConfigured() {
+		return false
+	}
+
+	var mediaType, id string
+	switch title.MediaType {
+	case "movie":
+		mediaType = "movie"
+		if title.TMDBID > 0 {
+			id = strconv.FormatInt(title.TMDBID, 10)
+		} else {
+			id = strings.TrimSpace(title.IMDBID)
+		}
+	case "series":
+		mediaType = "series"
+		if title.TVDBID > 0 {
+			id = strconv.FormatInt(title.TVDBID, 10)
+		}
+	}
+	if id == "" {
+		return false
+	}
+
+	artwork, err := s.cachedFetchFanartArtwork(ctx, fanart, mediaType, id)
+	if err != nil {
+		log.Printf("[metadata] failed to fetch fanart artwork for %s id=%s: %v", mediaType, id, err)
+		return false
+	}
+	if artwork == nil {
+		return false
+	}
+
+	updated := false
+	if artwork.Logo != nil {
+		current := ""
+		if title.Logo != nil {
+			current = artworkProviderForURL(title.Logo.URL)
+		}
+		if title.Logo == nil || artworkProviderRank(priority, artworkProviderFanart) < artworkProviderRank(priority, current) {
+			logo := *artwork.Logo
+			title.Logo = &logo
+			updated = true
+			metadataTracef("[metadata] fanart logo applied to %s id=%s (replaced=%q)", mediaType, id, current)
+		}
+	}
+	if artwork.Banner != nil && title.Banner == nil {
+		banner := *artwork.Banner
+		title.Banner = &banner
+		updated = true
+	}
+	if artwork.DiscArt != nil && title.DiscArt == nil {
+		disc := *artwork.DiscArt
+		title.DiscArt = &disc
+		updated = true
+	}
+	return updated
+}
@@ -0,0 +1,139 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"novastream/models"
+)
+
+const fanartMovieFixture = `{
+	"hdmovielogo": [
+		{"id":"1","url":"https://assets.fanart.tv/fanart/movies/603/hdmovielogo/de.png","lang":"de","likes":"9"},
+		{"id":"2","url":"https://assets.fanart.tv/fanart/movies/603/hdmovielogo/en-low.png","lang":"en","likes":"1"},
+		{"id":"3","url":"https://assets.fanart.tv/fanart/movies/603/hdmovielogo/en-high.png","lang":"en","likes":"7"}
+	],
+	"movielogo": [
+		{"id":"4","url":"https://assets.fanart.tv/fanart/movies/603/movielogo/en.png","lang":"en","likes":"20"}
+	],
+	"moviebanner": [
+		{"id":"5","url":"https://assets.fanart.tv/fanart/movies/603/moviebanner/banner.jpg","lang":"en","likes":"2"}
+	],
+	"moviedisc": [
+		{"id":"6","url":"https://assets.fanart.tv/fanart/movies/603/moviedisc/disc.png","lang":"00","likes":"3"}
+	]
+}`
+
+func TestSelectFanartImage(t *testing.T) {
+	logos := []fanartImage{
+		{URL: "https://assets.fanart.tv/fanart/a.png", Lang: "de", Likes: "9"},
+		{URL: "https://assets.fanart.tv/fanart/b.png", Lang: "en", Likes: "1"},
+		{URL: "https://assets.fanart.tv/fanart/c.png", Lang: "en", Likes: "7"},
+	}
+	sdLogos := []fanartImage{{URL: "https://assets.fanart.tv/fanart/d.png", Lang: "en", Likes: "50"}}
+
+	got := selectFanartImage("en", "logo", logos, sdLogos)
+	if got == nil || got.URL != "https://assets.fanart.tv/fanart/c.png" {
+		t.Fatalf("expected most-liked English HD logo, got %+v", got)
+	}
+	if got.IsFallbackLanguage {
+		t.Fatalf("expected preferred-language logo not to be flagged as fallback")
+	}
+
+	got = selectFanartImage("de", "logo", logos, sdLogos)
+	if got == nil || got.URL != "https://assets.fanart.tv/fanart/a.png" {
+		t.Fatalf("expected German logo for de preference, got %+v", got)
+	}
+
+	got = selectFanartImage("fr", "logo", logos, sdLogos)
+	if got == nil || got.URL != "https://assets.fanart.tv/fanart/c.png" || !got.IsFallbackLanguage {
+		t.Fatalf("expected English fallback for fr preference, got %+v", got)
+	}
+
+	if got := selectFanartImage("en", "logo"); got != nil {
+		t.Fatalf("expected nil for empty groups, got %+v", got)
+	}
+}
+
+func TestNormalizeArtworkProviderPriority(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{"empty uses default", nil, []string{"tmdb", "fanart", "tvdb"}},
+		{"fanart first", []string{"Fanart"}, []string{"fanart", "tmdb", "tvdb"}},
+		{"drops unknown and dupes", []string{"tvdb", "bogus", "tvdb", "fanart"}, []string{"tvdb", "fanart", "tmdb"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeArtworkProviderPriority(tt.in); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("normalizeArtworkProviderPriority(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyFanartArtwork_RespectsProviderPriority(t *testing.T) {
+	tmdbLogo := &models.Image{URL: "https://image.tmdb.org/t/p/w500/logo.png", Type: "logo"}
+
+	newSvc := func(priority []string, rt *countingRoundTripper) *Service {
+		return &Service{
+			cache:           newFileCache(t.TempDir(), 24),
+			fanart:          newFanartClient("test-key", "eng", &http.Client{Transport: rt}),
+			artworkPriority: normalizeArtworkProviderPriority(priority),
+		}
+	}
+
+	t.Run("tmdb preferred keeps tmdb logo", func(t *testing.T) {
+		rt := &countingRoundTripper{body: fanartMovieFixture}
+		svc := newSvc([]string{"tmdb", "fanart"}, rt)
+		title := &models.Title{MediaType: "movie", TMDBID: 603, Logo: tmdbLogo}
+
+		if !svc.applyFanartArtwork(context.Background(), title) {
+			t.Fatalf("expected banner/disc art to be applied")
+		}
+		if title.Logo.URL != tmdbLogo.URL {
+			t.Fatalf("expected TMDB logo to be kept, got %q", title.Logo.URL)
+		}
+		if title.Banner == nil || title.Banner.Type != "banner" {
+			t.Fatalf("expected banner to be applied, got %+v", title.Banner)
+		}
+		if title.DiscArt == nil || title.DiscArt.Language != "" {
+			t.Fatalf("expected language-neutral disc art, got %+v", title.DiscArt)
+		}
+	})
+
+	t.Run("fanart preferred replaces tmdb logo and caches", func(t *testing.T) {
+		rt := &countingRoundTripper{body: fanartMovieFixture}
+		svc := newSvc([]string{"fanart", "tmdb"}, rt)
+		title := &models.Title{MediaType: "movie", TMDBID: 603, Logo: tmdbLogo}
+
+		svc.applyFanartArtwork(context.Background(), title)
+		if title.Logo.URL != "https://assets.fanart.tv/fanart/movies/603/hdmovielogo/en-high.png" {
+			t.Fatalf("expected fanart logo, got %q", title.Logo.URL)
+		}
+
+		again := &models.Title{MediaType: "movie", TMDBID: 603}
+		svc.applyFanartArtwork(context.Background(), again)
+		if rt.callCount() != 1 {
+			t.Fatalf("expected cached artwork on second call, got %d requests", rt.callCount())
+		}
+	})
+
+	t.Run("unknown title is cached as empty", func(t *testing.T) {
+		rt := &countingRoundTripper{status: http.StatusNotFound, body: `{"status":"error"}`}
+		svc := newSvc(nil, rt)
+		for i := 0; i < 2; i++ {
+			title := &models.Title{MediaType: "series", TVDBID: 12345}
+			if svc.applyFanartArtwork(context.Background(), title) {
+				t.Fatalf("expected no artwork for unknown series")
+			}
+		}
+		if rt.callCount() != 1 {
+			t.Fatalf("expected 404 result to be cached, got %d requests", rt.callCount())
+		}
+	})
+}
@@ -1,271 +1,58 @@
 package metadata
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"log"
 	"net/http"
 	"strings"
 	"sync"
 	"time"
 )
 
-const geminiBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+// aiRecommendationSystem is the system prompt for recommendation requests.
+const aiRecommendationSystem = "You are a movie and TV show recommendation engine. Respond only with the requested JSON array."
 
-const (
-	aiProviderGemini     = "gemini"
-	aiProviderOpenAI     = "openai"
-	aiProviderAnthropic  = "anthropic"
-	aiProviderOpenRouter = "openrouter"
-	aiProviderNanoGPT    = "nanogpt"
-	aiProviderLinkAPI    = "linkapi"
-)
-
-type AIConfig struct {
-	Provider string
-	APIKey   string
-	Model    string
-	BaseURL  string
-}
-
-type geminiClient struct {
+// aiClient builds the prompts for the AI features and sends them to the
+// configured LLMProvider, spacing requests at least minInterval apart.
+type aiClient struct {
 	provider    string
-	apiKey      string
-	model       string
-	baseURL     string
-	httpc       *http.Client
-	cache       *fileCache
+	llm         LLMProvider
+	cache       cacheStore
 	throttleMu  sync.Mutex
 	lastRequest time.Time
 	minInterval time.Duration
 }
 
-func newGeminiClient(apiKey string, httpc *http.Client, cache *fileCache) *geminiClient {
-	return newAIClient(AIConfig{Provider: aiProviderGemini, APIKey: apiKey}, httpc, cache)
-}
-
-func newAIClient(cfg AIConfig, httpc *http.Client, cache *fileCache) *geminiClient {
+func newAIClient(cfg AIConfig, httpc *http.Client, cache cacheStore) *aiClient {
 	if httpc == nil {
 		httpc = &http.Client{Timeout: 30 * time.Second}
 	}
-	provider := normalizeMetadataAIProvider(cfg.Provider, cfg.APIKey)
-	return &geminiClient{
-		provider:    provider,
-		apiKey:      strings.TrimSpace(cfg.APIKey),
-		model:       strings.TrimSpace(cfg.Model),
-		baseURL:     strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/"),
-		httpc:       httpc,
+	return &aiClient{
+		provider:    normalizeMetadataAIProvider(cfg.Provider, cfg.APIKey),
+		llm:         newLLMProvider(cfg, httpc),
 		cache:       cache,
 		minInterval: 100 * time.Millisecond,
 	}
 }
 
-func (c *geminiClient) isConfigured() bool {
-	return c != nil && c.apiKey != "" && c.provider != ""
+func (c *aiClient) isConfigured() bool {
+	return c != nil && c.llm != nil
 }
 
-func normalizeMetadataAIProvider(provider, apiKey string) string {
-	switch strings.ToLower(strings.TrimSpace(provider)) {
-	case "", "none":
-		if strings.TrimSpace(apiKey) == "" {
-			return ""
-		}
-		return aiProviderGemini
-	case "gemini", "google", "google-gemini":
-		return aiProviderGemini
-	case "openai", "chatgpt", "gpt":
-		return aiProviderOpenAI
-	case "anthropic", "claude":
-		return aiProviderAnthropic
-	case "openrouter", "open-router":
-		return aiProviderOpenRouter
-	case "nanogpt", "nano-gpt", "nano_gpt":
-		return aiProviderNanoGPT
-	case "linkapi", "link-api", "link_api":
-		return aiProviderLinkAPI
-	default:
-		return strings.ToLower(strings.TrimSpace(provider))
+func (c *aiClient) providerLabel() string {
+	if c == nil || c.llm == nil {
+		return "AI"
 	}
+	return c.llm.Name()
 }
 
-func (c *geminiClient) providerLabel() string {
-	switch c.provider {
-	case aiProviderOpenAI:
-		return "OpenAI"
-	case aiProviderAnthropic:
-		return "Anthropic"
-	case aiProviderOpenRouter:
-		return "OpenRouter"
-	case aiProviderNanoGPT:
-		return "NanoGPT"
-	case aiProviderLinkAPI:
-		return "LinkAPI"
-	default:
-		return "Gemini"
+func (c *aiClient) modelName() string {
+	if c == nil || c.llm == nil {
+		return ""
 	}
-}
-
-func (c *geminiClient) defaultModel() string {
-	switch c.provider {
-	case aiProviderOpenAI:
-		return "gpt-5.5"
-	case aiProviderAnthropic:
-		return "claude-sonnet-4-5"
-	case aiProviderOpenRouter:
-		return "~openai/gpt-latest"
-	case aiProviderNanoGPT, aiProviderLinkAPI:
-		return "gpt-4o-mini"
-	default:
-		return "gemma-4-26b-a4b-it"
-	}
-}
-
-func (c *geminiClient) modelName() string {
-	if strings.TrimSpace(c.model) != "" {
-		return strings.TrimSpace(c.model)
-	}
-	return c.defaultModel()
-}
-
-func (c *geminiClient) defaultBaseURL() string {
-	switch c.provider {
-	case aiProviderOpenAI:
-		return "https://api.openai.com/v1"
-	case aiProviderAnthropic:
-		return "https://api.anthropic.com/v1"
-	case aiProviderOpenRouter:
-		return "https://openrouter.ai/api/v1"
-	case aiProviderNanoGPT:
-		return "https://nano-gpt.com/api/v1"
-	case aiProviderLinkAPI:
-		return "https://api.linkapi.org/v1"
-	default:
-		return geminiBaseURL
-	}
-}
-
-func (c *geminiClient) resolvedBaseURL() string {
-	if c.baseURL != "" {
-		return c.baseURL
-	}
-	return c.defaultBaseURL()
-}
-
-// geminiRequest is the request body for the Gemini generateContent API.
-type geminiRequest struct {
-	SystemInstruction *geminiContent          `json:"systemInstruction,omitempty"`
-	Contents          []geminiContent         `json:"contents"`
-	GenerationConfig  *geminiGenerationConfig `json:"generationConfig,omitempty"`
-}
-
-type geminiContent struct {
-	Parts []geminiPart `json:"parts"`
-}
-
-type geminiPart struct {
-	Text string `json:"text"`
-}
-
-// noThinkSystem is the system instruction that suppresses Gemma 4 thinking tokens.
-var noThinkSystem = &geminiContent{Parts: []geminiPart{{Text: "You are a helpful assistant. no thought tokens. Respond directly without reasoning."}}}
-
-// noThinkPrompt wraps a prompt with the <thought off> prefix that, combined with
-// noThinkSystem, reliably suppresses the Gemma 4 thinking chain (~14s vs ~36s).
-func noThinkPrompt(prompt string) string {
-	return "<thought off> " + prompt
-}
-
-type geminiGenerationConfig struct {
-	Temperature      float64 `json:"temperature"`
-	MaxOutputTokens  int     `json:"maxOutputTokens"`
-	ResponseMIMEType string  `json:"responseMimeType,omitempty"`
-}
-
-// geminiResponse is the response from the Gemini generateContent API.
-type geminiResponse struct {
-	Candidates []struct {
-		Content struct {
-			Parts []struct {
-				Text    string `json:"text"`
-				Thought bool   `json:"thought"`
-			} `json:"parts"`
-		} `json:"content"`
-	} `json:"candidates"`
-	Error *struct {
-		Message string `json:"message"`
-		Code    int    `json:"code"`
-	} `json:"error,omitempty"`
-}
-
-// geminiResponseText returns the first non-thought part text from a response.
-// Gemma 4 thinking models split output into a thought part (reasoning chain)
-// and an answer part — we always want the answer.
-func geminiResponseText(resp geminiResponse) (string, error) {
-	if len(resp.Candidates) == 0 {
-		return "", errors.New("gemini returned empty response")
-	}
-	parts := resp.Candidates[0].Content.Parts
-	for _, p := range parts {
-		if !p.Thought {
-			return p.Text, nil
-		}
-	}
-	if len(parts) > 0 {
-		return parts[len(parts)-1].Text, nil
-	}
-	return "", errors.New("gemini returned empty response")
-}
-
-type openAIChatRequest struct {
-	Model       string              `json:"model"`
-	Messages    []openAIChatMessage `json:"messages"`
-	Temperature float64             `json:"temperature,omitempty"`
-	MaxTokens   int                 `json:"max_tokens,omitempty"`
-}
-
-type openAIChatMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-type openAIChatResponse struct {
-	Choices []struct {
-		Message struct {
-			Content string `json:"content"`
-		} `json:"message"`
-	} `json:"choices"`
-	Error *struct {
-		Message string `json:"message"`
-		Type    string `json:"type"`
-	} `json:"error,omitempty"`
-}
-
-type anthropicRequest struct {
-	Model       string             `json:"model"`
-	MaxTokens   int                `json:"max_tokens"`
-	Temperature float64            `json:"temperature,omitempty"`
-	System      string             `json:"system,omitempty"`
-	Messages    []anthropicMessage `json:"messages"`
-}
-
-type anthropicMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-}
-
-type anthropicResponse struct {
-	Content []struct {
-		Type string `json:"type"`
-		Text string `json:"text"`
-	} `json:"content"`
-	Error *struct {
-		Message string `json:"message"`
-		Type    string `json:"type"`
-	} `json:"error,omitempty"`
+	return c.llm.Model()
 }
 
 // GeminiRecommendation is a single recommendation returned by the configured AI provider.
@@ -273,11 +60,25 @@ type GeminiRecommendation struct {
 	Title     string `json:"title"`
 	Year      int    `json:"year"`
 	MediaType string `json:"mediaType"` // "movie" or "series"
+	// Reason and BecauseOf explain a personalized recommendation: a short
+	// sentence and the watched title it follows from.
+	Reason    string `json:"reason,omitempty"`
+	BecauseOf string `json:"becauseOf,omitempty"`
+}
+
+func (c *aiClient) completeRecommendations(ctx context.Context, prompt string, temperature float64, maxTokens int, label string) ([]GeminiRecommendation, error) {
+	responseText, err := c.complete(ctx, LLMRequest{System: aiRecommendationSystem, Prompt: prompt, Temperature: temperature, MaxTokens: maxTokens, Label: label})
+	if err != nil {
+		return nil, err
+	}
+	return parseAIRecommendations(responseText, c.providerLabel(), label)
 }
 
-func (c *geminiClient) completeRecommendations(ctx context.Context, prompt string, temperature float64, maxTokens int, label string) ([]GeminiRecommendation, error) {
+// complete sends req to the configured provider and returns the response
+// text, spacing requests at least minInterval apart.
+func (c *aiClient) complete(ctx context.Context, req LLMRequest) (string, error) {
 	if !c.isConfigured() {
-		return nil, fmt.Errorf("%s api key not configured", strings.ToLower(c.providerLabel()))
+		return "", fmt.Errorf("AI provider %w", ErrNotConfigured)
 	}
 
 	c.throttleMu.Lock()
@@ -292,163 +93,7 @@ func (c *geminiClient) completeRecommendations(ctx context.Context, prompt strin
 	if wait > 0 {
 		time.Sleep(wait)
 	}
-
-	var responseText string
-	var err error
-	switch c.provider {
-	case aiProviderOpenAI, aiProviderOpenRouter, aiProviderNanoGPT, aiProviderLinkAPI:
-		responseText, err = c.completeOpenAICompatible(ctx, prompt, temperature, maxTokens, label)
-	case aiProviderAnthropic:
-		responseText, err = c.completeAnthropic(ctx, prompt, temperature, maxTokens, label)
-	default:
-		responseText, err = c.completeGemini(ctx, prompt, temperature, maxTokens, label)
-	}
-	if err != nil {
-		return nil, err
-	}
-	return parseAIRecommendations(responseText, c.providerLabel(), label)
-}
-
-func (c *geminiClient) completeGemini(ctx context.Context, prompt string, temperature float64, maxTokens int, label string) (string, error) {
-	endpoint := fmt.Sprintf("%s/models/%s:generateContent?key=%s", c.resolvedBaseURL(), c.modelName(), c.apiKey)
-	reqBody := geminiRequest{
-		SystemInstruction: noThinkSystem,
-		Contents: []geminiContent{
-			{Parts: []geminiPart{{Text: noThinkPrompt(prompt)}}},
-		},
-		GenerationConfig: &geminiGenerationConfig{
-			Temperature:     temperature,
-			MaxOutputTokens: maxTokens,
-		},
-	}
-
-	bodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", fmt.Errorf("marshal gemini request: %w", err)
-	}
-
-	var geminiResp geminiResponse
-	if err := c.doJSONWithRetry(ctx, http.MethodPost, endpoint, nil, bodyBytes, &geminiResp, "gemini", label); err != nil {
-		return "", err
-	}
-	if geminiResp.Error != nil {
-		return "", fmt.Errorf("gemini API error: %s", geminiResp.Error.Message)
-	}
-	return geminiResponseText(geminiResp)
-}
-
-func (c *geminiClient) completeOpenAICompatible(ctx context.Context, prompt string, temperature float64, maxTokens int, label string) (string, error) {
-	endpoint := c.resolvedBaseURL() + "/chat/completions"
-	reqBody := openAIChatRequest{
-		Model: c.modelName(),
-		Messages: []openAIChatMessage{
-			{Role: "system", Content: "You are a movie and TV show recommendation engine. Respond only with the requested JSON array."},
-			{Role: "user", Content: prompt},
-		},
-		Temperature: temperature,
-		MaxTokens:   maxTokens,
-	}
-	bodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", fmt.Errorf("marshal %s request: %w", c.providerLabel(), err)
-	}
-	headers := map[string]string{"Authorization": "Bearer " + c.apiKey}
-	if c.provider == aiProviderOpenRouter {
-		headers["HTTP-Referer"] = "https://github.com/godver3/mediastorm"
-		headers["X-OpenRouter-Title"] = "mediastorm"
-	}
-
-	var chatResp openAIChatResponse
-	if err := c.doJSONWithRetry(ctx, http.MethodPost, endpoint, headers, bodyBytes, &chatResp, strings.ToLower(c.providerLabel()), label); err != nil {
-		return "", err
-	}
-	if chatResp.Error != nil {
-		return "", fmt.Errorf("%s API error: %s", c.providerLabel(), chatResp.Error.Message)
-	}
-	if len(chatResp.Choices) == 0 {
-		return "", fmt.Errorf("%s returned empty response", c.providerLabel())
-	}
-	return chatResp.Choices[0].Message.Content, nil
-}
-
-func (c *geminiClient) completeAnthropic(ctx context.Context, prompt string, temperature float64, maxTokens int, label string) (string, error) {
-	endpoint := c.resolvedBaseURL() + "/messages"
-	reqBody := anthropicRequest{
-		Model:       c.modelName(),
-		MaxTokens:   maxTokens,
-		Temperature: temperature,
-		System:      "You are a movie and TV show recommendation engine. Respond only with the requested JSON array.",
-		Messages: []anthropicMessage{
-			{Role: "user", Content: prompt},
-		},
-	}
-	bodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return "", fmt.Errorf("marshal Anthropic request: %w", err)
-	}
-	headers := map[string]string{
-		"x-api-key":         c.apiKey,
-		"anthropic-version": "2023-06-01",
-	}
-
-	var msgResp anthropicResponse
-	if err := c.doJSONWithRetry(ctx, http.MethodPost, endpoint, headers, bodyBytes, &msgResp, "anthropic", label); err != nil {
-		return "", err
-	}
-	if msgResp.Error != nil {
-		return "", fmt.Errorf("Anthropic API error: %s", msgResp.Error.Message)
-	}
-	for _, part := range msgResp.Content {
-		if part.Text != "" {
-			return part.Text, nil
-		}
-	}
-	return "", errors.New("Anthropic returned empty response")
-}
-
-func (c *geminiClient) doJSONWithRetry(ctx context.Context, method, endpoint string, headers map[string]string, bodyBytes []byte, out interface{}, logPrefix, label string) error {
-	var lastErr error
-	backoff := 500 * time.Millisecond
-	for attempt := 0; attempt < 3; attempt++ {
-		req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(bodyBytes))
-		if err != nil {
-			return fmt.Errorf("create %s request: %w", logPrefix, err)
-		}
-		req.Header.Set("Content-Type", "application/json")
-		for k, v := range headers {
-			req.Header.Set(k, v)
-		}
-
-		resp, err := c.httpc.Do(req)
-		if err != nil {
-			lastErr = err
-			log.Printf("[%s] %s http error (attempt %d/3): %v", logPrefix, label, attempt+1, err)
-			time.Sleep(backoff)
-			backoff *= 2
-			continue
-		}
-
-		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
-			resp.Body.Close()
-			lastErr = fmt.Errorf("%s request failed: status %d", logPrefix, resp.StatusCode)
-			log.Printf("[%s] %s retryable status (attempt %d/3): %d", logPrefix, label, attempt+1, resp.StatusCode)
-			time.Sleep(backoff)
-			backoff *= 2
-			continue
-		}
-
-		defer resp.Body.Close()
-		if resp.StatusCode >= 400 {
-			body, _ := io.ReadAll(resp.Body)
-			return fmt.Errorf("%s API error %d: %s", c.providerLabel(), resp.StatusCode, string(body))
-		}
-
-		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
-			return fmt.Errorf("decode %s response: %w", logPrefix, err)
-		}
-		return nil
-	}
-	return fmt.Errorf("%s request failed after 3 attempts: %w", c.providerLabel(), lastErr)
+	return c.llm.Complete(ctx, req)
 }
 
 func parseAIRecommendations(responseText, providerLabel, label string) ([]GeminiRecommendation, error) {
@@ -467,9 +112,10 @@ func parseAIRecommendations(responseText, providerLabel, label string) ([]Gemini
 }
 
 // getRecommendations asks the configured AI provider for personalized recommendations based on watched titles.
-func (c *geminiClient) getRecommendations(ctx context.Context, watchedTitles []string, mediaTypes []string) ([]GeminiRecommendation, error) {
+// With explain set, each recommendation also carries a reason and the watched title it follows from.
+func (c *aiClient) getRecommendations(ctx context.Context, watchedTitles []string, mediaTypes []string, explain bool) ([]GeminiRecommendation, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("AI provider API key not configured")
+		return nil, fmt.Errorf("AI provider %w", ErrNotConfigured)
 	}
 
 	if len(watchedTitles) == 0 {
@@ -499,6 +145,19 @@ func (c *geminiClient) getRecommendations(ctx context.Context, watchedTitles []s
 	}
 	emphasis := emphases[time.Now().UnixNano()%int64(len(emphases))]
 
+	fields := `- "title": the exact title as it appears on TMDB
+- "year": the release year (integer)
+- "mediaType": either "movie" or "series"`
+	example := `[{"title": "Inception", "year": 2010, "mediaType": "movie"}, {"title": "Dark", "year": 2017, "mediaType": "series"}]`
+	maxTokens := 2048
+	if explain {
+		fields += `
+- "becauseOf": the title from the watched list above that this recommendation most follows from, spelled exactly as listed
+- "reason": one short sentence (at most 15 words) telling the user why they would enjoy it, e.g. "Another twisty time-travel mystery with a slow-burn family drama."`
+		example = `[{"title": "Inception", "year": 2010, "mediaType": "movie", "becauseOf": "Interstellar", "reason": "Nolan's layered, mind-bending storytelling on an even bigger stage."}, {"title": "Dark", "year": 2017, "mediaType": "series", "becauseOf": "Stranger Things", "reason": "A small town, missing kids and a far darker time-travel mystery."}]`
+		maxTokens = 3072
+	}
+
 	prompt := fmt.Sprintf(`You are a movie and TV show recommendation engine. Based on the following titles that a user has recently watched and enjoyed, recommend exactly 20 movies and TV shows they would likely enjoy.
 
 IMPORTANT: You MUST include a balanced mix — at least 8 movies and at least 8 TV shows in your 20 recommendations. Do not skew heavily toward one type.
@@ -513,20 +172,18 @@ Focus on:
 - %s
 
 Respond with ONLY a JSON array, no other text. Each object must have exactly these fields:
-- "title": the exact title as it appears on TMDB
-- "year": the release year (integer)
-- "mediaType": either "movie" or "series"
+%s
 
 	Example format:
-	[{"title": "Inception", "year": 2010, "mediaType": "movie"}, {"title": "Dark", "year": 2017, "mediaType": "series"}]`, titleList, emphasis)
+	%s`, titleList, emphasis, fields, example)
 
-	return c.completeRecommendations(ctx, prompt, 0.9, 2048, "recommendations")
+	return c.completeRecommendations(ctx, prompt, 0.9, maxTokens, "recommendations")
 }
 
 // getSimilarRecommendations asks the configured AI provider for recommendations similar to a specific title.
-func (c *geminiClient) getSimilarRecommendations(ctx context.Context, seedTitle string, mediaType string) ([]GeminiRecommendation, error) {
+func (c *aiClient) getSimilarRecommendations(ctx context.Context, seedTitle string, mediaType string) ([]GeminiRecommendation, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("AI provider API key not configured")
+		return nil, fmt.Errorf("AI provider %w", ErrNotConfigured)
 	}
 
 	prompt := fmt.Sprintf(`You are a movie and TV show recommendation engine. A user loved "%s" (%s). Recommend exactly 15 movies and TV shows they would enjoy based on this title.
@@ -549,9 +206,9 @@ Respond with ONLY a JSON array, no other text. Each object must have exactly the
 }
 
 // getCustomRecommendations asks the configured AI provider for recommendations based on a free-text user query.
-func (c *geminiClient) getCustomRecommendations(ctx context.Context, query string) ([]GeminiRecommendation, error) {
+func (c *aiClient) getCustomRecommendations(ctx context.Context, query string) ([]GeminiRecommendation, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("AI provider API key not configured")
+		return nil, fmt.Errorf("AI provider %w", ErrNotConfigured)
 	}
 
 	prompt := fmt.Sprintf(`You are a movie and TV show recommendation engine. A user has made the following request:
@@ -573,9 +230,9 @@ Respond with ONLY a JSON array, no other text. Each object must have exactly the
 
 // getSurpriseRecommendation asks the configured AI provider for a single random movie/show recommendation.
 // Uses high temperature and randomized prompt elements to avoid repetitive answers.
-func (c *geminiClient) getSurpriseRecommendation(ctx context.Context, preferredDecade, preferredMediaType string) ([]GeminiRecommendation, error) {
+func (c *aiClient) getSurpriseRecommendation(ctx context.Context, preferredDecade, preferredMediaType string) ([]GeminiRecommendation, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("AI provider API key not configured")
+		return nil, fmt.Errorf("AI provider %w", ErrNotConfigured)
 	}
 
 	// Randomized category seeds to push Gemma toward variety
@@ -625,3 +282,57 @@ Constraints:
 
 	return c.completeRecommendations(ctx, prompt, 1.5, 256, "surprise")
 }
+
+// aiSearchQuery is the structure the AI provider extracts from a natural
+// language search.
+type aiSearchQuery struct {
+	MediaType string   `json:"mediaType"`
+	Genres    []string `json:"genres"`
+	YearFrom  int      `json:"yearFrom"`
+	YearTo    int      `json:"yearTo"`
+	Cast      []string `json:"cast"`
+	Keywords  []string `json:"keywords"`
+	Sort      string   `json:"sort"`
+}
+
+// parseSearchQuery asks the configured AI provider to turn a spoken or typed
+// request into discover filters.
+func (c *aiClient) parseSearchQuery(ctx context.Context, query string) (aiSearchQuery, error) {
+	if !c.isConfigured() {
+		return aiSearchQuery{}, fmt.Errorf("AI provider %w", ErrNotConfigured)
+	}
+
+	prompt := fmt.Sprintf(`You turn movie and TV search requests into filters. The request is:
+
+"%s"
+
+Respond with ONLY a JSON object, no other text, with these fields (leave out any the request doesn't mention):
+- "mediaType": "movie" or "series"
+- "genres": genre names from: action, adventure, animation, comedy, crime, documentary, drama, family, fantasy, history, horror, music, mystery, romance, sci-fi, thriller, war, western, reality, kids
+- "yearFrom", "yearTo": the first and last release year (integers); a decade such as "the 90s" is 1990 to 1999
+- "cast": full names of actors the titles must feature
+- "keywords": up to three short TMDB-style subject keywords, e.g. "time travel"
+- "sort": "rating", "newest" or "oldest"
+
+Example: {"mediaType": "movie", "genres": ["sci-fi"], "yearFrom": 1990, "yearTo": 1999, "cast": ["Keanu Reeves"]}`, query)
+
+	responseText, err := c.complete(ctx, LLMRequest{
+		System:      "You turn movie and TV search requests into filters. Respond only with the requested JSON object.",
+		Prompt:      prompt,
+		Temperature: 0.1,
+		MaxTokens:   512,
+		Label:       "search query",
+	})
+	if err != nil {
+		return aiSearchQuery{}, err
+	}
+	cleaned := strings.TrimSpace(responseText)
+	cleaned = strings.TrimPrefix(cleaned, "```json")
+	cleaned = strings.TrimPrefix(cleaned, "```")
+	cleaned = strings.TrimSuffix(cleaned, "```")
+	var parsed aiSearchQuery
+	if err := json.Unmarshal([]byte(strings.TrimSpace(cleaned)), &parsed); err != nil {
+		return aiSearchQuery{}, fmt.Errorf("parse %s search query: %w (raw: %s)", c.providerLabel(), err, responseText[:min(200, len(responseText))])
+	}
+	return parsed, nil
+}
@@ -0,0 +1,315 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"novastream/models"
+)
+
+// Genre hub row IDs.
+const (
+	GenreHubRowTrending = "trending"
+	GenreHubRowTopRated = "top-rated"
+	GenreHubRowNew      = "new"
+)
+
+const (
+	// genreBrowseStatsFile lives in the cache root so clearing the metadata
+	// cache keeps the browse counts.
+	genreBrowseStatsFile = "genre_browse_stats.json"
+	// genreHubNewWindow is how far back the "new" row reaches.
+	genreHubNewWindow = 180 * 24 * time.Hour
+	// genreHubDefaultLimit is the number of items per row when the caller
+	// doesn't ask for a size.
+	genreHubDefaultLimit = 20
+	// genreHubWarmCount is how many of the most-browsed genres the cache
+	// manager pre-fetches.
+	genreHubWarmCount = 6
+)
+
+var genreHubRows = []struct {
+	id    string
+	title string
+}{
+	{GenreHubRowTrending, "Trending"},
+	{GenreHubRowTopRated, "Top Rated"},
+	{GenreHubRowNew, "New"},
+}
+
+// tmdbMovieToTVGenre and tmdbTVToMovieGenre map TMDB genres to their
+// closest counterpart for the other media type. Genres missing here only
+// exist for one of them.
+var (
+	tmdbMovieToTVGenre = map[int64]int64{
+		28: 10759, 12: 10759, 16: 16, 35: 35, 80: 80, 99: 99, 18: 18,
+		10751: 10751, 14: 10765, 878: 10765, 9648: 9648, 10752: 10768, 37: 37,
+	}
+	tmdbTVToMovieGenre = map[int64]int64{
+		10759: 28, 16: 16, 35: 35, 80: 80, 99: 99, 18: 18, 10751: 10751,
+		10762: 10751, 9648: 9648, 10765: 878, 10768: 10752, 37: 37,
+	}
+)
+
+// genreHubGenres returns the movie and series genre IDs a hub for genreID
+// covers, and the genre's display name.
+func genreHubGenres(mediaType string, genreID int64) (movieGenreID, seriesGenreID int64, name string) {
+	if mediaType == "movie" {
+		return genreID, tmdbMovieToTVGenre[genreID], tmdbMovieGenres[int(genreID)]
+	}
+	return tmdbTVToMovieGenre[genreID], genreID, tmdbTVGenres[int(genreID)]
+}
+
+// GenreHub returns the trending, top-rated and new rows for a genre, each
+// mixing movies and series. mediaType says which TMDB genre list genreID
+// comes from; the counterpart genre of the other type is looked up. Each
+// request counts towards the genre's browse total, and the most-browsed
+// genres are kept warm by the cache manager.
+func (s *Service) GenreHub(ctx context.Context, mediaType string, genreID int64, limit int, opts ShelfLoadOptions) (*models.GenreHub, error) {
+	if genreID <= 0 {
+		return nil, fmt.Errorf("genre id required")
+	}
+	if s.tmdb == nil || !s.tmdb.isConfigured() {
+		return nil, fmt.Errorf("tmdb client not configured")
+	}
+	normalizedType := strings.ToLower(strings.TrimSpace(mediaType))
+	if normalizedType != "movie" {
+		normalizedType = "series"
+	}
+	s.genreBrowse.record(normalizedType, genreID)
+	return s.genreHub(ctx, normalizedType, genreID, limit, opts)
+}
+
+func (s *Service) genreHub(ctx context.Context, mediaType string, genreID int64, limit int, opts ShelfLoadOptions) (*models.GenreHub, error) {
+	if limit <= 0 {
+		limit = genreHubDefaultLimit
+	}
+	movieGenreID, seriesGenreID, name := genreHubGenres(mediaType, genreID)
+	hub := &models.GenreHub{Name: name, MovieGenreID: movieGenreID, SeriesGenreID: seriesGenreID}
+
+	type rowResult struct {
+		movies, series []models.TrendingItem
+		err            error
+	}
+	results := make([]rowResult, len(genreHubRows))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	load := func(i int, rowID, rowType string, genreID int64) {
+		defer wg.Done()
+		items, _, err := s.discoverShelfWithOptions(ctx, rowType, limit, 0, opts,
+			fmt.Sprintf("genre hub row=%s genreId=%d", rowID, genreID),
+			[]string{"genre-hub", "v1", rowID, fmt.Sprintf("%d", genreID)},
+			func(normalizedType string, page int) ([]models.Title, int, error) {
+				return s.tmdb.discoverGenreHubRow(ctx, normalizedType, genreID, rowID, page)
+			})
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			log.Printf("[metadata] genre hub row=%s type=%s genreId=%d failed: %v", rowID, rowType, genreID, err)
+			if results[i].err == nil {
+				results[i].err = err
+			}
+			return
+		}
+		if rowType == "movie" {
+			results[i].movies = items
+		} else {
+			results[i].series = items
+		}
+	}
+	for i, row := range genreHubRows {
+		if movieGenreID > 0 {
+			wg.Add(1)
+			go load(i, row.id, "movie", movieGenreID)
+		}
+		if seriesGenreID > 0 {
+			wg.Add(1)
+			go load(i, row.id, "series", seriesGenreID)
+		}
+	}
+	wg.Wait()
+
+	var firstErr error
+	for i, row := range genreHubRows {
+		result := results[i]
+		if result.err != nil && firstErr == nil {
+			firstErr = result.err
+		}
+		hub.Rows = append(hub.Rows, models.GenreHubRow{
+			ID:    row.id,
+			Title: row.title,
+			Items: interleaveGenreHubItems(result.movies, result.series, limit),
+		})
+	}
+	for _, row := range hub.Rows {
+		if len(row.Items) > 0 {
+			return hub, nil
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return hub, nil
+}
+
+// interleaveGenreHubItems alternates movies and series, filling from
+// whichever list is longer once the other runs out, and re-ranks the result.
+func interleaveGenreHubItems(movies, series []models.TrendingItem, limit int) []models.TrendingItem {
+	items := make([]models.TrendingItem, 0, limit)
+	for i := 0; len(items) < limit && (i < len(movies) || i < len(series)); i++ {
+		if i < len(movies) {
+			items = append(items, movies[i])
+		}
+		if i < len(series) && len(items) < limit {
+			items = append(items, series[i])
+		}
+	}
+	for i := range items {
+		items[i].Rank = i + 1
+	}
+	return items
+}
+
+// warmGenreHubs pre-fetches the hubs of the most-browsed genres and persists
+// the browse counts.
+func (s *Service) warmGenreHubs(ctx context.Context) {
+	top := s.genreBrowse.top(genreHubWarmCount)
+	if len(top) > 0 {
+		log.Printf("[metadata] cache manager: warming %d genre hubs", len(top))
+	}
+	for _, key := range top {
+		if ctx.Err() != nil {
+			break
+		}
+		if _, err := s.genreHub(ctx, key.mediaType, key.genreID, genreHubDefaultLimit, ShelfLoadOptions{}); err != nil {
+			log.Printf("[metadata] cache manager: genre hub error type=%s genreId=%d: %v", key.mediaType, key.genreID, err)
+		}
+	}
+	if err := s.genreBrowse.save(); err != nil {
+		log.Printf("[metadata] WARNING: failed to save genre browse stats: %v", err)
+	}
+}
+
+type genreBrowseKey struct {
+	mediaType string
+	genreID   int64
+}
+
+func (k genreBrowseKey) String() string {
+	return k.mediaType + ":" + strconv.FormatInt(k.genreID, 10)
+}
+
+func parseGenreBrowseKey(value string) (genreBrowseKey, bool) {
+	mediaType, id, ok := strings.Cut(value, ":")
+	genreID, err := strconv.ParseInt(id, 10, 64)
+	if !ok || err != nil || genreID <= 0 || (mediaType != "movie" && mediaType != "series") {
+		return genreBrowseKey{}, false
+	}
+	return genreBrowseKey{mediaType: mediaType, genreID: genreID}, true
+}
+
+// genreBrowseStats counts genre hub visits, persisted as JSON and shared by
+// all language clones of a Service. A nil tracker ignores visits.
+type genreBrowseStats struct {
+	mu     sync.Mutex
+	path   string
+	counts map[genreBrowseKey]int
+	dirty  bool
+}
+
+func newGenreBrowseStats(path string) *genreBrowseStats {
+	stats := &genreBrowseStats{path: path, counts: make(map[genreBrowseKey]int)}
+	if path == "" {
+		return stats
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return stats
+	}
+	if err != nil {
+		log.Printf("[metadata] WARNING: failed to read genre browse stats: %v", err)
+		return stats
+	}
+	var counts map[string]int
+	if err := json.Unmarshal(data, &counts); err != nil {
+		log.Printf("[metadata] WARNING: failed to decode genre browse stats: %v", err)
+		return stats
+	}
+	for value, count := range counts {
+		if key, ok := parseGenreBrowseKey(value); ok && count > 0 {
+			stats.counts[key] = count
+		}
+	}
+	return stats
+}
+
+func (st *genreBrowseStats) record(mediaType string, genreID int64) {
+	if st == nil {
+		return
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	st.counts[genreBrowseKey{mediaType: mediaType, genreID: genreID}]++
+	st.dirty = true
+}
+
+// top returns up to n genres, most visited first.
+func (st *genreBrowseStats) top(n int) []genreBrowseKey {
+	if st == nil {
+		return nil
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	keys := make([]genreBrowseKey, 0, len(st.counts))
+	for key := range st.counts {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if st.counts[keys[i]] != st.counts[keys[j]] {
+			return st.counts[keys[i]] > st.counts[keys[j]]
+		}
+		return keys[i].String() < keys[j].String()
+	})
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+	return keys
+}
+
+// save writes the counts if they changed since the last save.
+func (st *genreBrowseStats) save() error {
+	if st == nil {
+		return nil
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if !st.dirty || st.path == "" {
+		return nil
+	}
+	counts := make(map[string]int, len(st.counts))
+	for key, count := range st.counts {
+		counts[key.String()] = count
+	}
+	data, err := json.MarshalIndent(counts, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode genre browse stats: %w", err)
+	}
+	tmp := st.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write genre browse stats: %w", err)
+	}
+	if err := os.Rename(tmp, st.path); err != nil {
+		return err
+	}
+	st.dirty = false
+	return nil
+}
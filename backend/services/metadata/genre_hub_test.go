@@ -0,0 +1,107 @@
+package metadata
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"novastream/models"
+)
+
+func TestGenreHubMixesMoviesAndSeries(t *testing.T) {
+	cache := newFileCache(t.TempDir(), 24)
+	var mu sync.Mutex
+	queries := make(map[string][]string)
+	svc := &Service{
+		client: &tvdbClient{language: "eng"},
+		cache:  cache,
+		tmdb: newTMDBClient("tmdb-key", "eng", &http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				body := `{"backdrops":[],"posters":[],"logos":[]}`
+				switch req.URL.Path {
+				case "/3/discover/movie":
+					body = `{"results":[{"id":603,"title":"The Matrix","release_date":"1999-03-31","popularity":80}],"total_results":1}`
+				case "/3/discover/tv":
+					body = `{"results":[{"id":1399,"name":"Foundation","first_air_date":"2021-09-23","popularity":60}],"total_results":1}`
+				}
+				if strings.HasPrefix(req.URL.Path, "/3/discover/") {
+					mu.Lock()
+					queries[req.URL.Path] = append(queries[req.URL.Path], req.URL.RawQuery)
+					mu.Unlock()
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Status:     "200 OK",
+					Body:       io.NopCloser(strings.NewReader(body)),
+					Header:     make(http.Header),
+				}, nil
+			}),
+		}, cache),
+		genreBrowse: newGenreBrowseStats(filepath.Join(t.TempDir(), genreBrowseStatsFile)),
+	}
+
+	hub, err := svc.GenreHub(context.Background(), "movie", 878, 10, ShelfLoadOptions{Lite: true})
+	if err != nil {
+		t.Fatalf("GenreHub: %v", err)
+	}
+	if hub.Name != "Sci-Fi" || hub.MovieGenreID != 878 || hub.SeriesGenreID != 10765 {
+		t.Fatalf("unexpected hub %+v", hub)
+	}
+	if len(hub.Rows) != 3 {
+		t.Fatalf("expected 3 rows, got %d", len(hub.Rows))
+	}
+	for _, row := range hub.Rows {
+		if len(row.Items) != 2 || row.Items[0].Title.MediaType != "movie" || row.Items[1].Title.MediaType != "series" || row.Items[1].Rank != 2 {
+			t.Fatalf("expected row %s to interleave a movie and a series, got %+v", row.ID, row.Items)
+		}
+	}
+
+	movieQueries := strings.Join(queries["/3/discover/movie"], "\n")
+	tvQueries := strings.Join(queries["/3/discover/tv"], "\n")
+	if len(queries["/3/discover/movie"]) != 3 || len(queries["/3/discover/tv"]) != 3 {
+		t.Fatalf("expected three discover requests per media type, got %v", queries)
+	}
+	for _, param := range []string{"with_genres=878", "vote_count.gte=300", "sort_by=vote_average.desc", "primary_release_date.lte=" + time.Now().UTC().Format("2006-01-02")} {
+		if !strings.Contains(movieQueries, param) {
+			t.Fatalf("expected %q in movie queries:\n%s", param, movieQueries)
+		}
+	}
+	for _, param := range []string{"with_genres=10765", "vote_count.gte=50", "first_air_date.gte="} {
+		if !strings.Contains(tvQueries, param) {
+			t.Fatalf("expected %q in tv queries:\n%s", param, tvQueries)
+		}
+	}
+
+	// Warm-ups don't count as visits.
+	svc.GenreHub(context.Background(), "series", 10759, 10, ShelfLoadOptions{Lite: true})
+	svc.GenreHub(context.Background(), "movie", 878, 10, ShelfLoadOptions{Lite: true})
+	svc.warmGenreHubs(context.Background())
+	top := svc.genreBrowse.top(5)
+	if len(top) != 2 || top[0] != (genreBrowseKey{mediaType: "movie", genreID: 878}) {
+		t.Fatalf("unexpected most-browsed genres %+v", top)
+	}
+
+	// Counts survive a restart.
+	reloaded := newGenreBrowseStats(svc.genreBrowse.path)
+	if got := reloaded.top(5); len(got) != 2 || got[0] != top[0] || reloaded.counts[top[0]] != 2 {
+		t.Fatalf("unexpected reloaded stats %+v", reloaded.counts)
+	}
+}
+
+func TestInterleaveGenreHubItemsFillsFromLongerList(t *testing.T) {
+	movies := []models.TrendingItem{{Title: models.Title{ID: "m1"}}, {Title: models.Title{ID: "m2"}}, {Title: models.Title{ID: "m3"}}}
+	series := []models.TrendingItem{{Title: models.Title{ID: "s1"}}}
+	items := interleaveGenreHubItems(movies, series, 3)
+	var ids []string
+	for _, item := range items {
+		ids = append(ids, item.Title.ID)
+	}
+	if strings.Join(ids, ",") != "m1,s1,m2" || items[2].Rank != 3 {
+		t.Fatalf("unexpected interleave %v", ids)
+	}
+}
@@ -0,0 +1,350 @@
+package metadata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"novastream/models"
+)
+
+// identityOverridesFile lives in the cache root rather than the metadata
+// cache directory, so clearing the cache keeps the overrides.
+const identityOverridesFile = "metadata_identity_overrides.json"
+
+// IdentityOverride pins a title to the TVDB and/or TMDB entry it really is,
+// for when automatic matching picks the wrong show or movie. TitleID is the
+// ID clients request the title by: "tmdb:tv:1396", "tvdb:movie:123", an IMDB
+// ID, or a list item ID like "mdblist:series:42". An ID left at zero is
+// derived from the other one.
+type IdentityOverride struct {
+	TitleID   string    `json:"titleId"`
+	MediaType string    `json:"mediaType"` // movie | series
+	TVDBID    int64     `json:"tvdbId,omitempty"`
+	TMDBID    int64     `json:"tmdbId,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// identityOverrideStore keeps the overrides in memory, persisted as JSON and
+// shared by all language clones of a Service. An empty path keeps them in
+// memory only.
+type identityOverrideStore struct {
+	mu        sync.RWMutex
+	path      string
+	overrides map[string]IdentityOverride // normalized title ID -> override
+}
+
+func newIdentityOverrideStore(path string) *identityOverrideStore {
+	store := &identityOverrideStore{path: path, overrides: make(map[string]IdentityOverride)}
+	if path == "" {
+		return store
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store
+	}
+	if err != nil {
+		log.Printf("[metadata] WARNING: failed to read identity overrides: %v", err)
+		return store
+	}
+	var overrides []IdentityOverride
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &overrides); err != nil {
+			log.Printf("[metadata] WARNING: failed to decode identity overrides: %v", err)
+			return store
+		}
+	}
+	for _, override := range overrides {
+		if key := normalizeIdentityTitleID(override.TitleID); key != "" {
+			override.TitleID = key
+			store.overrides[key] = override
+		}
+	}
+	if len(store.overrides) > 0 {
+		log.Printf("[metadata] loaded %d identity overrides", len(store.overrides))
+	}
+	return store
+}
+
+// listLocked returns the overrides sorted by title ID.
+func (st *identityOverrideStore) listLocked() []IdentityOverride {
+	list := make([]IdentityOverride, 0, len(st.overrides))
+	for _, override := range st.overrides {
+		list = append(list, override)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].TitleID < list[j].TitleID })
+	return list
+}
+
+func (st *identityOverrideStore) saveLocked() error {
+	if st.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(st.listLocked(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode identity overrides: %w", err)
+	}
+	tmp := st.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write identity overrides: %w", err)
+	}
+	return os.Rename(tmp, st.path)
+}
+
+// normalizeIdentityTitleID canonicalizes a title ID so the spellings clients
+// use ("TMDB:series:1", "imdb:tt0903747") find the same override.
+func normalizeIdentityTitleID(titleID string) string {
+	id := strings.ToLower(strings.TrimSpace(titleID))
+	id = strings.TrimPrefix(id, "imdb:")
+	switch {
+	case strings.HasPrefix(id, "tmdb:series:"):
+		id = "tmdb:tv:" + strings.TrimPrefix(id, "tmdb:series:")
+	case strings.HasPrefix(id, "tvdb:tv:"):
+		id = "tvdb:series:" + strings.TrimPrefix(id, "tvdb:tv:")
+	}
+	return id
+}
+
+// identityKeys lists the keys an override for a title may be stored under,
+// most specific first.
+func identityKeys(mediaType, titleID string, tvdbID, tmdbID int64, imdbID string) []string {
+	tvdbKind, tmdbKind := "series", "tv"
+	if mediaType == "movie" {
+		tvdbKind, tmdbKind = "movie", "movie"
+	}
+	var keys []string
+	if key := normalizeIdentityTitleID(titleID); key != "" {
+		keys = append(keys, key)
+	}
+	if tvdbID > 0 {
+		keys = append(keys, fmt.Sprintf("tvdb:%s:%d", tvdbKind, tvdbID))
+	}
+	if tmdbID > 0 {
+		keys = append(keys, fmt.Sprintf("tmdb:%s:%d", tmdbKind, tmdbID))
+	}
+	if key := normalizeIdentityTitleID(imdbID); key != "" {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// SetIdentityOverride pins a title to the given TVDB and/or TMDB IDs. Every
+// details and resolution path consults the overrides before matching, and the
+// cached TMDB→TVDB mappings involved are replaced, so the next request serves
+// the right title. Lists already enriched with the wrong match pick it up on
+// their next refresh.
+func (s *Service) SetIdentityOverride(override IdentityOverride) (IdentityOverride, error) {
+	if s.identityOverrides == nil {
+		return IdentityOverride{}, fmt.Errorf("identity overrides %w", ErrNotConfigured)
+	}
+	override.TitleID = normalizeIdentityTitleID(override.TitleID)
+	override.MediaType = strings.ToLower(strings.TrimSpace(override.MediaType))
+	if override.TitleID == "" {
+		return IdentityOverride{}, fmt.Errorf("title id required")
+	}
+	if override.MediaType != "movie" && override.MediaType != "series" {
+		return IdentityOverride{}, fmt.Errorf("media type must be movie or series")
+	}
+	if override.TVDBID < 0 || override.TMDBID < 0 || (override.TVDBID == 0 && override.TMDBID == 0) {
+		return IdentityOverride{}, fmt.Errorf("tvdb or tmdb id required")
+	}
+	override.UpdatedAt = time.Now().UTC()
+
+	store := s.identityOverrides
+	store.mu.Lock()
+	previous, hadPrevious := store.overrides[override.TitleID]
+	store.overrides[override.TitleID] = override
+	if err := store.saveLocked(); err != nil {
+		if hadPrevious {
+			store.overrides[override.TitleID] = previous
+		} else {
+			delete(store.overrides, override.TitleID)
+		}
+		store.mu.Unlock()
+		return IdentityOverride{}, err
+	}
+	store.mu.Unlock()
+
+	if hadPrevious {
+		s.dropIdentityMappings(previous)
+	}
+	s.dropIdentityMappings(override)
+	if tmdbID := identityMappingTMDBID(override); tmdbID > 0 && override.TVDBID > 0 && s.cache != nil {
+		_ = s.cache.set(tvdbResolveCacheKey(override.MediaType, tmdbID), override.TVDBID)
+	}
+	log.Printf("[metadata] identity override set %s (%s) → tvdb:%d tmdb:%d", override.TitleID, override.MediaType, override.TVDBID, override.TMDBID)
+	return override, nil
+}
+
+// RemoveIdentityOverride deletes the override for titleID, returning
+// ErrNotFound when there is none. The title is matched automatically again.
+func (s *Service) RemoveIdentityOverride(titleID string) error {
+	if s.identityOverrides == nil {
+		return fmt.Errorf("identity overrides %w", ErrNotConfigured)
+	}
+	key := normalizeIdentityTitleID(titleID)
+	store := s.identityOverrides
+	store.mu.Lock()
+	override, ok := store.overrides[key]
+	if !ok {
+		store.mu.Unlock()
+		return newKindError(ErrNotFound, "no identity override for %q", titleID)
+	}
+	delete(store.overrides, key)
+	if err := store.saveLocked(); err != nil {
+		store.overrides[key] = override
+		store.mu.Unlock()
+		return err
+	}
+	store.mu.Unlock()
+
+	s.dropIdentityMappings(override)
+	log.Printf("[metadata] identity override removed %s", key)
+	return nil
+}
+
+// IdentityOverrides lists the configured overrides sorted by title ID.
+func (s *Service) IdentityOverrides() []IdentityOverride {
+	if s.identityOverrides == nil {
+		return []IdentityOverride{}
+	}
+	s.identityOverrides.mu.RLock()
+	defer s.identityOverrides.mu.RUnlock()
+	return s.identityOverrides.listLocked()
+}
+
+// identityOverride returns the override matching any of a title's IDs.
+func (s *Service) identityOverride(mediaType, titleID string, tvdbID, tmdbID int64, imdbID string) (IdentityOverride, bool) {
+	if s.identityOverrides == nil {
+		return IdentityOverride{}, false
+	}
+	s.identityOverrides.mu.RLock()
+	defer s.identityOverrides.mu.RUnlock()
+	if len(s.identityOverrides.overrides) == 0 {
+		return IdentityOverride{}, false
+	}
+	for _, key := range identityKeys(mediaType, titleID, tvdbID, tmdbID, imdbID) {
+		if override, ok := s.identityOverrides.overrides[key]; ok && override.MediaType == mediaType {
+			return override, true
+		}
+	}
+	return IdentityOverride{}, false
+}
+
+// applySeriesIdentityOverride rewrites a series query to the IDs its
+// override pins. The TitleID is rewritten too so its own ID can't win over
+// the override; an ID the override leaves unset is derived again.
+func (s *Service) applySeriesIdentityOverride(req models.SeriesDetailsQuery) models.SeriesDetailsQuery {
+	override, ok := s.identityOverride("series", req.TitleID, req.TVDBID, req.TMDBID, req.IMDBID)
+	if !ok {
+		return req
+	}
+	metadataTracef("[metadata] identity override for series titleId=%q tvdbId=%d tmdbId=%d → tvdb:%d tmdb:%d",
+		req.TitleID, req.TVDBID, req.TMDBID, override.TVDBID, override.TMDBID)
+	req.TVDBID = override.TVDBID
+	req.TMDBID = identityMappingTMDBID(override)
+	if override.TVDBID > 0 {
+		req.TitleID = fmt.Sprintf("tvdb:series:%d", override.TVDBID)
+	} else {
+		req.TitleID = fmt.Sprintf("tmdb:tv:%d", req.TMDBID)
+	}
+	return req
+}
+
+// applyMovieIdentityOverride is applySeriesIdentityOverride for movies. A
+// TMDB-only override also drops the name, so the name search that likely
+// picked the wrong movie isn't repeated and TMDB serves the details.
+func (s *Service) applyMovieIdentityOverride(req models.MovieDetailsQuery) models.MovieDetailsQuery {
+	override, ok := s.identityOverride("movie", req.TitleID, req.TVDBID, req.TMDBID, req.IMDBID)
+	if !ok {
+		return req
+	}
+	metadataTracef("[metadata] identity override for movie titleId=%q tvdbId=%d tmdbId=%d → tvdb:%d tmdb:%d",
+		req.TitleID, req.TVDBID, req.TMDBID, override.TVDBID, override.TMDBID)
+	req.TVDBID = override.TVDBID
+	req.TMDBID = identityMappingTMDBID(override)
+	if override.TVDBID > 0 {
+		req.TitleID = fmt.Sprintf("tvdb:movie:%d", override.TVDBID)
+	} else {
+		req.TitleID = fmt.Sprintf("tmdb:movie:%d", req.TMDBID)
+		req.Name = ""
+	}
+	return req
+}
+
+// applyListItemIdentityOverride rewrites the IDs of a list item before
+// enrichment, which matches by its TVDB ID first.
+func (s *Service) applyListItemIdentityOverride(item mdblistItem, titleID string) mdblistItem {
+	mediaType := mdblistItemMediaType(item)
+	var tvdbID, tmdbID int64
+	if item.TVDBID != nil {
+		tvdbID = *item.TVDBID
+	}
+	if item.TMDBID != nil {
+		tmdbID = *item.TMDBID
+	}
+	override, ok := s.identityOverride(mediaType, titleID, tvdbID, tmdbID, item.IMDBID)
+	if !ok {
+		return item
+	}
+	item.TVDBID = nil
+	if override.TVDBID > 0 {
+		id := override.TVDBID
+		item.TVDBID = &id
+	}
+	if id := identityMappingTMDBID(override); id > 0 {
+		item.TMDBID = &id
+	}
+	return item
+}
+
+// identityMappingTMDBID is the TMDB ID an override resolves to: its own, or
+// the one its title ID names.
+func identityMappingTMDBID(override IdentityOverride) int64 {
+	if override.TMDBID > 0 {
+		return override.TMDBID
+	}
+	for _, prefix := range []string{"tmdb:tv:", "tmdb:movie:"} {
+		if rest, ok := strings.CutPrefix(override.TitleID, prefix); ok {
+			if id, err := strconv.ParseInt(rest, 10, 64); err == nil && id > 0 {
+				return id
+			}
+		}
+	}
+	return 0
+}
+
+// tvdbResolveCacheKey is the cache key of the TMDB→TVDB ID mapping that
+// matching stores for a title.
+func tvdbResolveCacheKey(mediaType string, tmdbID int64) string {
+	if mediaType == "movie" {
+		return cacheKey("tvdb", "resolve", "movie", "tmdb", fmt.Sprintf("%d", tmdbID))
+	}
+	return cacheKey("tvdb", "resolve", "tmdb", fmt.Sprintf("%d", tmdbID))
+}
+
+// dropIdentityMappings removes the cached TMDB→TVDB mappings an override
+// touches, which may hold the wrong match.
+func (s *Service) dropIdentityMappings(override IdentityOverride) {
+	if s.cache == nil {
+		return
+	}
+	seen := map[int64]bool{}
+	for _, tmdbID := range []int64{override.TMDBID, identityMappingTMDBID(override)} {
+		if tmdbID <= 0 || seen[tmdbID] {
+			continue
+		}
+		seen[tmdbID] = true
+		key := tvdbResolveCacheKey(override.MediaType, tmdbID)
+		if err := s.cache.delete(key); err != nil {
+			log.Printf("[metadata] identity override: failed to drop cached mapping %s: %v", key, err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"novastream/models"
+)
+
+func TestIdentityOverridesPersist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), identityOverridesFile)
+	cache := newFileCache(t.TempDir(), 24)
+	svc := &Service{cache: cache, identityOverrides: newIdentityOverrideStore(path)}
+
+	wrongKey := tvdbResolveCacheKey("series", 1396)
+	if err := cache.set(wrongKey, int64(999)); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	if _, err := svc.SetIdentityOverride(IdentityOverride{TitleID: "TMDB:series:1396", MediaType: "series"}); err == nil {
+		t.Fatal("expected an error without ids")
+	}
+	if _, err := svc.SetIdentityOverride(IdentityOverride{TitleID: "tt0903747", MediaType: "episode", TVDBID: 1}); err == nil {
+		t.Fatal("expected an error for an unknown media type")
+	}
+	override, err := svc.SetIdentityOverride(IdentityOverride{TitleID: "TMDB:series:1396", MediaType: "Series", TVDBID: 81189})
+	if err != nil {
+		t.Fatalf("SetIdentityOverride: %v", err)
+	}
+	if override.TitleID != "tmdb:tv:1396" || override.MediaType != "series" || override.UpdatedAt.IsZero() {
+		t.Fatalf("unexpected override %+v", override)
+	}
+	var resolved int64
+	if ok, _ := cache.get(wrongKey, &resolved); !ok || resolved != 81189 {
+		t.Fatalf("expected the cached mapping to be replaced, got %d", resolved)
+	}
+
+	reloaded := &Service{cache: cache, identityOverrides: newIdentityOverrideStore(path)}
+	list := reloaded.IdentityOverrides()
+	if len(list) != 1 || list[0].TitleID != "tmdb:tv:1396" || list[0].TVDBID != 81189 {
+		t.Fatalf("expected the override to persist, got %+v", list)
+	}
+
+	if err := reloaded.RemoveIdentityOverride("tmdb:tv:1396"); err != nil {
+		t.Fatalf("RemoveIdentityOverride: %v", err)
+	}
+	if ok, _ := cache.get(wrongKey, &resolved); ok {
+		t.Fatal("expected the mapping to be dropped with the override")
+	}
+	if err := reloaded.RemoveIdentityOverride("tmdb:tv:1396"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if list := newIdentityOverrideStore(path).listLocked(); len(list) != 0 {
+		t.Fatalf("expected the removal to persist, got %+v", list)
+	}
+}
+
+func TestApplyIdentityOverrides(t *testing.T) {
+	svc := &Service{identityOverrides: newIdentityOverrideStore("")}
+	for _, override := range []IdentityOverride{
+		{TitleID: "tt0903747", MediaType: "series", TVDBID: 81189},
+		{TitleID: "tmdb:tv:42", MediaType: "series", TVDBID: 7},
+		{TitleID: "tvdb:movie:100", MediaType: "movie", TMDBID: 603},
+		{TitleID: "mdblist:series:5", MediaType: "series", TMDBID: 1399},
+	} {
+		if _, err := svc.SetIdentityOverride(override); err != nil {
+			t.Fatalf("SetIdentityOverride(%+v): %v", override, err)
+		}
+	}
+
+	series := svc.applySeriesIdentityOverride(models.SeriesDetailsQuery{TitleID: "tvdb:series:1", TVDBID: 1, TMDBID: 55, IMDBID: "tt0903747"})
+	if series.TVDBID != 81189 || series.TMDBID != 0 || series.TitleID != "tvdb:series:81189" {
+		t.Fatalf("expected the IMDB override to pin the series, got %+v", series)
+	}
+	if id, err := svc.resolveSeriesTVDBID(context.Background(), models.SeriesDetailsQuery{TitleID: "tmdb:tv:42", Name: "Wrong Show"}); err != nil || id != 7 {
+		t.Fatalf("expected resolution to use the override, got %d (%v)", id, err)
+	}
+	untouched := models.SeriesDetailsQuery{TitleID: "tmdb:tv:43", TMDBID: 43}
+	if got := svc.applySeriesIdentityOverride(untouched); got != untouched {
+		t.Fatalf("expected no change without an override, got %+v", got)
+	}
+
+	movie := svc.applyMovieIdentityOverride(models.MovieDetailsQuery{TitleID: "tvdb:movie:100", Name: "The Matrix", TVDBID: 100})
+	if movie.TVDBID != 0 || movie.TMDBID != 603 || movie.TitleID != "tmdb:movie:603" || movie.Name != "" {
+		t.Fatalf("expected the TMDB-only override to skip TVDB matching, got %+v", movie)
+	}
+	// Overrides only apply to their media type.
+	if got := svc.applyMovieIdentityOverride(models.MovieDetailsQuery{IMDBID: "tt0903747"}); got.TVDBID != 0 || got.TMDBID != 0 {
+		t.Fatalf("expected a series override not to apply to movies, got %+v", got)
+	}
+
+	wrongTVDB := int64(3)
+	item := svc.applyListItemIdentityOverride(mdblistItem{ID: 5, MediaType: "show", TVDBID: &wrongTVDB}, "mdblist:series:5")
+	if item.TVDBID != nil || item.TMDBID == nil || *item.TMDBID != 1399 {
+		t.Fatalf("expected the list item to be re-pinned, got %+v", item)
+	}
+}
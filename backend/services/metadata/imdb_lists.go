@@ -0,0 +1,81 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"novastream/services/imdb"
+)
+
+// imdbResolveConcurrency bounds parallel IMDb -> TMDB ID lookups per list.
+const imdbResolveConcurrency = 8
+
+// imdbListSource reads public IMDb lists, watchlists and exported CSVs.
+type imdbListSource interface {
+	GetListItems(ctx context.Context, rawURL string, maxItems int) ([]imdb.ListItem, error)
+}
+
+// SetIMDbClient enables IMDb list, watchlist and CSV export URLs as custom
+// list sources alongside MDBList URLs.
+func (s *Service) SetIMDbClient(client imdbListSource) {
+	s.imdb = client
+}
+
+// fetchIMDbListItems reads an IMDb list and resolves each entry's tt ID to a
+// TMDB ID up front, so enrichment matches by ID rather than by title.
+// Entries whose type the source didn't report are tried as a movie first,
+// then as a series.
+func (s *Service) fetchIMDbListItems(ctx context.Context, listURL string) ([]mdblistItem, error) {
+	if s.imdb == nil {
+		return nil, fmt.Errorf("imdb lists %w", ErrNotConfigured)
+	}
+	entries, err := s.imdb.GetListItems(ctx, listURL, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]mdblistItem, len(entries))
+	sem := make(chan struct{}, imdbResolveConcurrency)
+	var wg sync.WaitGroup
+	for i, entry := range entries {
+		items[i] = mdblistItem{
+			Rank:        i + 1,
+			Title:       entry.Title,
+			IMDBID:      entry.IMDBID,
+			ReleaseYear: entry.Year,
+			MediaType:   entry.MediaType,
+		}
+		wg.Add(1)
+		go func(item *mdblistItem) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			s.resolveIMDbListItem(ctx, item)
+		}(&items[i])
+	}
+	wg.Wait()
+	return items, ctx.Err()
+}
+
+func (s *Service) resolveIMDbListItem(ctx context.Context, item *mdblistItem) {
+	if ctx.Err() != nil {
+		return
+	}
+	var tmdbID int64
+	switch item.MediaType {
+	case "show":
+		tmdbID = s.getTMDBIDForIMDBTV(ctx, item.IMDBID)
+	case "movie":
+		tmdbID = s.getTMDBIDForIMDB(ctx, item.IMDBID)
+	default:
+		if tmdbID = s.getTMDBIDForIMDB(ctx, item.IMDBID); tmdbID > 0 {
+			item.MediaType = "movie"
+		} else if tmdbID = s.getTMDBIDForIMDBTV(ctx, item.IMDBID); tmdbID > 0 {
+			item.MediaType = "show"
+		}
+	}
+	if tmdbID > 0 {
+		item.TMDBID = &tmdbID
+	}
+}
@@ -0,0 +1,58 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"novastream/services/imdb"
+)
+
+type fakeIMDbSource struct {
+	items []imdb.ListItem
+}
+
+func (f *fakeIMDbSource) GetListItems(context.Context, string, int) ([]imdb.ListItem, error) {
+	return f.items, nil
+}
+
+func TestFetchCustomListItemsResolvesIMDbIDs(t *testing.T) {
+	ids := newFileCache(t.TempDir(), 24)
+	// Seed the ID cache so no TMDB request is made; an unreported type that
+	// has no movie match falls through to the TV lookup.
+	_ = ids.set(cacheKey("id", "imdb-to-tmdb", "movie", "tt0111161"), int64(278))
+	_ = ids.set(cacheKey("id", "imdb-to-tmdb", "tv", "tt0903747"), int64(1396))
+	_ = ids.set(cacheKey("id", "imdb-to-tmdb", "movie", "tt0903747"), int64(0))
+
+	svc := &Service{client: &tvdbClient{language: "eng"}, idCache: ids}
+	svc.SetIMDbClient(&fakeIMDbSource{items: []imdb.ListItem{
+		{IMDBID: "tt0111161", Title: "The Shawshank Redemption", Year: 1994, MediaType: "movie"},
+		{IMDBID: "tt0903747"},
+	}})
+
+	items, err := svc.fetchCustomListItems(context.Background(), "https://www.imdb.com/list/ls012345678/")
+	if err != nil {
+		t.Fatalf("fetchCustomListItems: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	if items[0].IMDBID != "tt0111161" || items[0].TMDBID == nil || *items[0].TMDBID != 278 || items[0].Rank != 1 {
+		t.Fatalf("unexpected movie item: %+v", items[0])
+	}
+	if items[1].TMDBID == nil || *items[1].TMDBID != 1396 || mdblistItemMediaType(items[1]) != "series" {
+		t.Fatalf("unexpected series item: %+v", items[1])
+	}
+
+	if clone := svc.WithLanguage("fra"); clone.imdb == nil {
+		t.Fatal("expected language clone to keep the IMDb source")
+	}
+}
+
+func TestFetchCustomListItemsWithoutIMDbClient(t *testing.T) {
+	svc := &Service{client: &tvdbClient{language: "eng"}}
+	_, err := svc.fetchCustomListItems(context.Background(), "https://www.imdb.com/user/ur1234567/watchlist")
+	if !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("expected ErrNotConfigured, got %v", err)
+	}
+}
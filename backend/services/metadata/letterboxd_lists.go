@@ -0,0 +1,60 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+
+	"novastream/services/imdb"
+	"novastream/services/letterboxd"
+	"novastream/services/listshares"
+)
+
+// letterboxdListSource reads public Letterboxd lists and watchlists.
+type letterboxdListSource interface {
+	GetListItems(ctx context.Context, rawURL string, maxItems int) ([]letterboxd.ListItem, error)
+}
+
+// SetLetterboxdClient enables Letterboxd list and watchlist URLs as custom
+// list sources alongside MDBList URLs.
+func (s *Service) SetLetterboxdClient(client letterboxdListSource) {
+	s.letterboxd = client
+}
+
+// fetchCustomListItems fetches the raw items of a custom list URL. Letterboxd
+// entries carry only a title and year; they are matched to TVDB by the same
+// enrichment pipeline as MDBList items. IMDb and Trakt entries carry their
+// own IDs.
+func (s *Service) fetchCustomListItems(ctx context.Context, listURL string) ([]mdblistItem, error) {
+	if err := offlineError("custom list"); err != nil {
+		return nil, err
+	}
+	if imdb.IsListURL(listURL) {
+		return s.fetchIMDbListItems(ctx, listURL)
+	}
+	if s.trakt != nil && s.trakt.IsListURL(listURL) {
+		return s.fetchTraktListItems(ctx, listURL)
+	}
+	if listshares.IsListURL(listURL) {
+		return s.fetchSharedListItems(ctx, listURL)
+	}
+	if !letterboxd.IsListURL(listURL) {
+		return s.client.FetchMDBListCustom(listURL)
+	}
+	if s.letterboxd == nil {
+		return nil, fmt.Errorf("letterboxd lists %w", ErrNotConfigured)
+	}
+	entries, err := s.letterboxd.GetListItems(ctx, listURL, 0)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]mdblistItem, 0, len(entries))
+	for i, entry := range entries {
+		items = append(items, mdblistItem{
+			Rank:        i + 1,
+			Title:       entry.Title,
+			ReleaseYear: entry.Year,
+			MediaType:   entry.MediaType,
+		})
+	}
+	return items, nil
+}
@@ -0,0 +1,56 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"novastream/services/letterboxd"
+)
+
+type fakeLetterboxdSource struct {
+	items []letterboxd.ListItem
+	urls  []string
+}
+
+func (f *fakeLetterboxdSource) GetListItems(_ context.Context, rawURL string, _ int) ([]letterboxd.ListItem, error) {
+	f.urls = append(f.urls, rawURL)
+	return f.items, nil
+}
+
+func TestFetchCustomListItemsReadsLetterboxdLists(t *testing.T) {
+	source := &fakeLetterboxdSource{items: []letterboxd.ListItem{
+		{Title: "Paris, Texas", Year: 1984, MediaType: "movie", Slug: "paris-texas"},
+		{Title: "Stalker", Year: 1979, MediaType: "movie", Slug: "stalker"},
+	}}
+	svc := &Service{client: &tvdbClient{language: "eng"}}
+	svc.SetLetterboxdClient(source)
+
+	listURL := "https://letterboxd.com/someone/list/slow-cinema/"
+	items, err := svc.fetchCustomListItems(context.Background(), listURL)
+	if err != nil {
+		t.Fatalf("fetchCustomListItems: %v", err)
+	}
+	if len(source.urls) != 1 || source.urls[0] != listURL {
+		t.Fatalf("expected Letterboxd source to be queried once, got %v", source.urls)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	if items[1].Title != "Stalker" || items[1].ReleaseYear != 1979 || items[1].Rank != 2 || mdblistItemMediaType(items[1]) != "movie" {
+		t.Fatalf("unexpected item: %+v", items[1])
+	}
+
+	// Language clones keep the Letterboxd source.
+	if clone := svc.WithLanguage("fra"); clone.letterboxd == nil {
+		t.Fatal("expected language clone to keep the Letterboxd source")
+	}
+}
+
+func TestFetchCustomListItemsWithoutLetterboxdClient(t *testing.T) {
+	svc := &Service{client: &tvdbClient{language: "eng"}}
+	_, err := svc.fetchCustomListItems(context.Background(), "https://letterboxd.com/someone/watchlist/")
+	if !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("expected ErrNotConfigured, got %v", err)
+	}
+}
@@ -0,0 +1,282 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"novastream/models"
+)
+
+// List enrichment makes two TVDB calls per item with a TVDB ID: the extended
+// record and its translation. TVDB v4 ignores conditional request headers
+// (If-Modified-Since, If-None-Match), so calls are saved instead by asking
+// for translations in the extended request (meta=translations) and by
+// enriching each title once per warm cycle however many lists it is on.
+//
+// Across warm cycles and restarts, enriched titles are kept once per title,
+// keyed by TVDB and TMDB ID (enrichedTitleCacheKey), and trending, custom
+// and curated lists all read from and add to the same entries.
+
+// listItemExtendedMeta are the meta keys list enrichment caches extended
+// records under, per media type.
+var listItemExtendedMeta = map[string]string{"movie": "artwork", "series": "artworks"}
+
+func tvdbExtendedCacheKey(mediaType string, tvdbID int64, meta string) string {
+	return cacheKey("tvdb", mediaType, "extended", "v1", fmt.Sprintf("%d", tvdbID), meta)
+}
+
+func tvdbTranslationsCacheKey(mediaType string, tvdbID int64, lang string) string {
+	return cacheKey("tvdb", mediaType, "translations", "v1", fmt.Sprintf("%d", tvdbID), lang)
+}
+
+// cachedMovieWithTranslation returns a movie's extended record and its
+// translation in the client language for list enrichment. A cold movie
+// costs a single extended request with meta=translations, which fills both
+// caches; a movie without a translation in the language is cached with an
+// empty one so it isn't asked for again.
+func (s *Service) cachedMovieWithTranslation(tvdbID int64) (tvdbMovieExtendedData, *tvdbSeriesTranslation, error) {
+	meta := listItemExtendedMeta["movie"]
+	var ext tvdbMovieExtendedData
+	if ok, _ := s.cache.get(tvdbExtendedCacheKey("movie", tvdbID, meta), &ext); ok {
+		trans, _ := s.cachedMovieTranslations(tvdbID, s.client.language)
+		return ext, trans, nil
+	}
+	value, err := s.singleflightCachedFetch(context.Background(), tvdbExtendedCacheKey("movie", tvdbID, "translations"), func() (any, error) {
+		data, err := s.client.movieExtended(tvdbID, []string{"translations"})
+		if err != nil {
+			return nil, err
+		}
+		trans := data.Translations.forLanguage(s.client.language)
+		data.Translations = nil
+		_ = s.cache.set(tvdbExtendedCacheKey("movie", tvdbID, meta), data)
+		_ = s.cache.set(tvdbTranslationsCacheKey("movie", tvdbID, s.client.language), trans)
+		return movieWithTranslation{data, trans}, nil
+	})
+	if err != nil {
+		return tvdbMovieExtendedData{}, nil, err
+	}
+	result, _ := value.(movieWithTranslation)
+	return result.ext, &result.trans, nil
+}
+
+// cachedSeriesWithTranslation is cachedMovieWithTranslation for series.
+func (s *Service) cachedSeriesWithTranslation(tvdbID int64) (tvdbSeriesExtendedData, *tvdbSeriesTranslation, error) {
+	meta := listItemExtendedMeta["series"]
+	var ext tvdbSeriesExtendedData
+	if ok, _ := s.cache.get(tvdbExtendedCacheKey("series", tvdbID, meta), &ext); ok {
+		trans, _ := s.cachedSeriesTranslations(tvdbID, s.client.language)
+		return ext, trans, nil
+	}
+	value, err := s.singleflightCachedFetch(context.Background(), tvdbExtendedCacheKey("series", tvdbID, "translations"), func() (any, error) {
+		data, err := s.client.seriesExtended(tvdbID, []string{"translations"})
+		if err != nil {
+			return nil, err
+		}
+		trans := data.Translations.forLanguage(s.client.language)
+		data.Translations = nil
+		_ = s.cache.set(tvdbExtendedCacheKey("series", tvdbID, meta), data)
+		_ = s.cache.set(tvdbTranslationsCacheKey("series", tvdbID, s.client.language), trans)
+		return seriesWithTranslation{data, trans}, nil
+	})
+	if err != nil {
+		return tvdbSeriesExtendedData{}, nil, err
+	}
+	result, _ := value.(seriesWithTranslation)
+	return result.ext, &result.trans, nil
+}
+
+type movieWithTranslation struct {
+	ext   tvdbMovieExtendedData
+	trans tvdbSeriesTranslation
+}
+
+type seriesWithTranslation struct {
+	ext   tvdbSeriesExtendedData
+	trans tvdbSeriesTranslation
+}
+
+// listEnrichmentBatch coalesces the enrichment of titles that appear on
+// several lists within one warm cycle: the first list to reach a title
+// enriches it and the others wait for and copy the result.
+type listEnrichmentBatch struct {
+	mu    sync.Mutex
+	calls map[string]*listEnrichmentCall
+}
+
+type listEnrichmentCall struct {
+	done chan struct{}
+	item models.TrendingItem
+}
+
+type listEnrichmentBatchKey struct{}
+
+// withListEnrichmentBatch returns a context whose custom and curated list
+// enrichments share results for the same title.
+func withListEnrichmentBatch(ctx context.Context) context.Context {
+	return context.WithValue(ctx, listEnrichmentBatchKey{}, &listEnrichmentBatch{calls: make(map[string]*listEnrichmentCall)})
+}
+
+func listEnrichmentBatchFrom(ctx context.Context) *listEnrichmentBatch {
+	batch, _ := ctx.Value(listEnrichmentBatchKey{}).(*listEnrichmentBatch)
+	return batch
+}
+
+// enrich returns the batch's result for key, running fn if no list has
+// enriched the title yet. Results are copied so each list can change its
+// own items.
+func (b *listEnrichmentBatch) enrich(ctx context.Context, key string, fn func() models.TrendingItem) models.TrendingItem {
+	b.mu.Lock()
+	call, ok := b.calls[key]
+	if !ok {
+		call = &listEnrichmentCall{done: make(chan struct{})}
+		b.calls[key] = call
+	}
+	b.mu.Unlock()
+
+	if !ok {
+		call.item = fn()
+		close(call.done)
+		return call.item
+	}
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return fn()
+	}
+	return cloneTrendingItem(call.item)
+}
+
+// listItemEnrichmentKey identifies a list item's title for coalescing, or
+// returns "" when the item has nothing to identify it by.
+func listItemEnrichmentKey(mediaType string, item mdblistItem, liteMovieEnrichment bool) string {
+	var id string
+	switch {
+	case item.TVDBID != nil && *item.TVDBID > 0:
+		id = fmt.Sprintf("tvdb:%d", *item.TVDBID)
+	case strings.TrimSpace(item.IMDBID) != "":
+		id = "imdb:" + strings.TrimSpace(item.IMDBID)
+	case item.TMDBID != nil && *item.TMDBID > 0:
+		id = fmt.Sprintf("tmdb:%d", *item.TMDBID)
+	case strings.TrimSpace(item.Title) != "":
+		id = fmt.Sprintf("title:%s:%d", strings.ToLower(strings.TrimSpace(item.Title)), item.ReleaseYear)
+	default:
+		return ""
+	}
+	return fmt.Sprintf("%s:%s:%t", mediaType, id, liteMovieEnrichment)
+}
+
+func cloneTrendingItem(item models.TrendingItem) models.TrendingItem {
+	raw, err := json.Marshal(item)
+	if err != nil {
+		return item
+	}
+	var clone models.TrendingItem
+	if err := json.Unmarshal(raw, &clone); err != nil {
+		return item
+	}
+	return clone
+}
+
+// Enrichment depths of the shared per-title entries. A lite movie has its
+// TVDB record, translation and artwork; a full movie adds TMDB releases,
+// certification and hydrated overview and artwork. Series enrich the same
+// way for every list and are always full.
+const (
+	enrichedTitleDepthLite = "lite"
+	enrichedTitleDepthFull = "full"
+)
+
+func enrichedTitleDepth(mediaType string, liteMovieEnrichment bool) string {
+	if mediaType == "movie" && liteMovieEnrichment {
+		return enrichedTitleDepthLite
+	}
+	return enrichedTitleDepthFull
+}
+
+// enrichedTitleCacheKey is the shared entry for a title enriched to depth,
+// keyed by one of its provider IDs ("tvdb" or "tmdb").
+func enrichedTitleCacheKey(provider, mediaType string, id int64, depth, lang string) string {
+	return cacheKey(provider, mediaType, "enriched", "v1", strconv.FormatInt(id, 10), depth, lang)
+}
+
+// enrichedTitleKeys returns the keys a title's shared entry is stored under:
+// one per known TVDB and TMDB ID.
+func enrichedTitleKeys(mediaType string, tvdbID, tmdbID int64, depth, lang string) []string {
+	var keys []string
+	if tvdbID > 0 {
+		keys = append(keys, enrichedTitleCacheKey("tvdb", mediaType, tvdbID, depth, lang))
+	}
+	if tmdbID > 0 {
+		keys = append(keys, enrichedTitleCacheKey("tmdb", mediaType, tmdbID, depth, lang))
+	}
+	return keys
+}
+
+// cachedEnrichedTitle returns the shared enrichment of a list item's title at
+// depth, or a full one when a lite one will do. The title keeps the ID,
+// names and artwork of the enrichment; callers set rank and popularity.
+func (s *Service) cachedEnrichedTitle(mediaType string, item mdblistItem, depth string) (models.Title, bool) {
+	var tvdbID, tmdbID int64
+	if item.TVDBID != nil {
+		tvdbID = *item.TVDBID
+	}
+	if item.TMDBID != nil {
+		tmdbID = *item.TMDBID
+	}
+	depths := []string{depth}
+	if depth == enrichedTitleDepthLite {
+		depths = append(depths, enrichedTitleDepthFull)
+	}
+	for _, d := range depths {
+		for _, key := range enrichedTitleKeys(mediaType, tvdbID, tmdbID, d, s.client.language) {
+			var title models.Title
+			if ok, _ := s.cache.get(key, &title); ok && title.TVDBID > 0 {
+				return title, true
+			}
+		}
+	}
+	return models.Title{}, false
+}
+
+// storeEnrichedTitle shares an enriched title with every list that carries
+// it. Titles that didn't match on TVDB aren't stored, so the next list tries
+// again.
+func (s *Service) storeEnrichedTitle(title models.Title, depth string) {
+	if title.TVDBID <= 0 {
+		return
+	}
+	for _, key := range enrichedTitleKeys(title.MediaType, title.TVDBID, title.TMDBID, depth, s.client.language) {
+		_ = s.cache.set(key, title)
+	}
+}
+
+// enrichSharedListItem returns a list item from its title's shared entry,
+// enriching the title and sharing it when no list has yet.
+func (s *Service) enrichSharedListItem(ctx context.Context, item mdblistItem, mediaType string, liteMovieEnrichment bool) models.TrendingItem {
+	depth := enrichedTitleDepth(mediaType, liteMovieEnrichment)
+	if title, ok := s.cachedEnrichedTitle(mediaType, item, depth); ok {
+		s.resolveEnrichmentFailure(item)
+		return models.TrendingItem{Rank: item.Rank, Title: title}
+	}
+	enriched := s.enrichListItem(ctx, item, mediaType, liteMovieEnrichment)
+	s.storeEnrichedTitle(enriched.Title, depth)
+	return enriched
+}
+
+// enrichTrendingTitle fills a trending title from its shared entry, or runs
+// enrich and shares the result. Trending titles are lite.
+func (s *Service) enrichTrendingTitle(title *models.Title, item mdblistItem, enrich func()) {
+	depth := enrichedTitleDepth(title.MediaType, true)
+	if cached, ok := s.cachedEnrichedTitle(title.MediaType, item, depth); ok {
+		cached.Popularity = title.Popularity
+		*title = cached
+		s.resolveEnrichmentFailure(item)
+		return
+	}
+	enrich()
+	s.storeEnrichedTitle(*title, depth)
+}
@@ -0,0 +1,169 @@
+package metadata
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"novastream/models"
+)
+
+func TestEnrichCustomListItemFetchesTranslationsWithExtended(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	httpc := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			requests[req.URL.Path+"?"+req.URL.RawQuery]++
+			mu.Unlock()
+			body := `{}`
+			switch req.URL.Path {
+			case "/v4/login":
+				body = `{"data":{"token":"test-token"}}`
+			case "/v4/series/200/extended":
+				body = `{"data":{"id":200,"name":"Dark","overview":"English overview","status":{"name":"Ended"},
+					"translations":{
+						"nameTranslations":[{"language":"eng","name":"Dark"},{"language":"deu","name":"Dunkel"}],
+						"overviewTranslations":[{"language":"deu","overview":"Deutsche Beschreibung"}]}}}`
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		}),
+	}
+	svc := &Service{
+		client: newTVDBClient("test-tvdb-key", "deu", httpc, 24),
+		cache:  newFileCache(t.TempDir(), 24),
+	}
+	svc.client.limiter = nil
+
+	tvdbID := int64(200)
+	item := mdblistItem{ID: 1, Rank: 1, Title: "Dark", TVDBID: &tvdbID, MediaType: "show"}
+	for i := 0; i < 2; i++ {
+		enriched := svc.enrichCustomListItem(context.Background(), item, false)
+		if enriched.Title.Name != "Dunkel" || enriched.Title.Overview != "Deutsche Beschreibung" || enriched.Title.Status != "Ended" {
+			t.Fatalf("unexpected enriched title %+v", enriched.Title)
+		}
+	}
+
+	if got := requests["/v4/series/200/extended?meta=translations"]; got != 1 {
+		t.Fatalf("extended requests = %d, want 1 (all requests: %v)", got, requests)
+	}
+	for path := range requests {
+		if strings.Contains(path, "/translations/") {
+			t.Fatalf("unexpected separate translations request %s", path)
+		}
+	}
+	var trans tvdbSeriesTranslation
+	if ok, _ := svc.cache.get(tvdbTranslationsCacheKey("series", 200, "deu"), &trans); !ok || trans.Name != "Dunkel" {
+		t.Fatalf("translation cache = %+v (ok=%v), want Dunkel", trans, ok)
+	}
+}
+
+func TestListEnrichmentBatchCoalescesTitles(t *testing.T) {
+	batch := listEnrichmentBatchFrom(withListEnrichmentBatch(context.Background()))
+	var calls atomic.Int32
+	fn := func() models.TrendingItem {
+		calls.Add(1)
+		return models.TrendingItem{Rank: 3, Title: models.Title{Name: "Dune", Genres: []string{"Sci-Fi"}}}
+	}
+
+	var wg sync.WaitGroup
+	results := make([]models.TrendingItem, 4)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = batch.enrich(context.Background(), "movie:tvdb:1:false", fn)
+		}(i)
+	}
+	wg.Wait()
+	if calls.Load() != 1 {
+		t.Fatalf("enrichment ran %d times, want 1", calls.Load())
+	}
+	results[0].Title.Genres[0] = "Changed"
+	for _, result := range results[1:] {
+		if result.Title.Name != "Dune" || result.Title.Genres[0] != "Sci-Fi" {
+			t.Fatalf("coalesced results share state: %+v", results)
+		}
+	}
+
+	batch.enrich(context.Background(), "movie:tvdb:2:false", fn)
+	if calls.Load() != 2 {
+		t.Fatalf("distinct titles should be enriched separately")
+	}
+}
+
+func TestListItemEnrichmentKey(t *testing.T) {
+	tvdbID, tmdbID := int64(7), int64(9)
+	cases := []struct {
+		item mdblistItem
+		want string
+	}{
+		{mdblistItem{TVDBID: &tvdbID, IMDBID: "tt1"}, "movie:tvdb:7:true"},
+		{mdblistItem{IMDBID: "tt1", TMDBID: &tmdbID}, "movie:imdb:tt1:true"},
+		{mdblistItem{TMDBID: &tmdbID}, "movie:tmdb:9:true"},
+		{mdblistItem{Title: " Dune ", ReleaseYear: 2021}, "movie:title:dune:2021:true"},
+		{mdblistItem{}, ""},
+	}
+	for _, tc := range cases {
+		if got := listItemEnrichmentKey("movie", tc.item, true); got != tc.want {
+			t.Fatalf("listItemEnrichmentKey(%+v) = %q, want %q", tc.item, got, tc.want)
+		}
+	}
+}
+
+func TestEnrichedTitlesAreSharedAcrossLists(t *testing.T) {
+	var extended atomic.Int32
+	httpc := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body := `{}`
+			switch req.URL.Path {
+			case "/v4/login":
+				body = `{"data":{"token":"test-token"}}`
+			case "/v4/series/200/extended":
+				extended.Add(1)
+				body = `{"data":{"id":200,"name":"Dark","overview":"Time travel in Winden","status":{"name":"Ended"},
+					"genres":[{"name":"Drama"}],
+					"translations":{"nameTranslations":[{"language":"eng","name":"Dark"}]}}}`
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		}),
+	}
+	svc := &Service{
+		client: newTVDBClient("test-tvdb-key", "eng", httpc, 24),
+		cache:  newFileCache(t.TempDir(), 24),
+	}
+	svc.client.limiter = nil
+
+	tvdbID, tmdbID := int64(200), int64(70523)
+	custom := svc.enrichCustomListItem(context.Background(), mdblistItem{ID: 1, Rank: 4, Title: "Dark", TVDBID: &tvdbID, TMDBID: &tmdbID, MediaType: "show"}, false)
+	if custom.Title.Name != "Dark" || custom.Title.Popularity != 96 || len(custom.Title.Genres) != 1 {
+		t.Fatalf("unexpected custom list title %+v", custom.Title)
+	}
+
+	// Trending reuses the entry rather than enriching again.
+	trending := models.Title{ID: "mdblist:series:9", Name: "dark", MediaType: "series", Popularity: 99}
+	svc.enrichTrendingTitle(&trending, mdblistItem{ID: 9, Rank: 1, TVDBID: &tvdbID, MediaType: "show"}, func() {
+		t.Fatal("trending enriched a title another list already enriched")
+	})
+	if trending.Name != "Dark" || trending.TVDBID != 200 || trending.Popularity != 99 || trending.Status != "Ended" {
+		t.Fatalf("unexpected trending title %+v", trending)
+	}
+
+	// A list that only knows the TMDB ID finds the same entry, and a
+	// curated list's fast path keeps its own ID.
+	other := svc.enrichCustomListItem(context.Background(), mdblistItem{ID: 2, Rank: 10, Title: "Dark", TMDBID: &tmdbID, MediaType: "show"}, true)
+	if other.Title.TVDBID != 200 || other.Rank != 10 || other.Title.Popularity != 90 {
+		t.Fatalf("unexpected TMDB-keyed title %+v", other)
+	}
+	lite := svc.enrichLiteCustomListItem(context.Background(), mdblistItem{ID: 3, Rank: 2, Title: "Dark", TMDBID: &tmdbID, MediaType: "show"})
+	if lite.Title.ID != "tmdb:tv:70523" || lite.Title.Overview != "Time travel in Winden" {
+		t.Fatalf("unexpected lite title %+v", lite.Title)
+	}
+	if got := extended.Load(); got != 1 {
+		t.Fatalf("extended requests = %d, want 1", got)
+	}
+}
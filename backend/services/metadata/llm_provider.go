@@ -0,0 +1,480 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const geminiBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
+const ollamaBaseURL = "http://localhost:11434"
+
+const (
+	aiProviderGemini           = "gemini"
+	aiProviderOpenAI           = "openai"
+	aiProviderAnthropic        = "anthropic"
+	aiProviderOpenRouter       = "openrouter"
+	aiProviderNanoGPT          = "nanogpt"
+	aiProviderLinkAPI          = "linkapi"
+	aiProviderOllama           = "ollama"
+	aiProviderOpenAICompatible = "openai-compatible"
+)
+
+type AIConfig struct {
+	Provider string
+	APIKey   string
+	Model    string
+	BaseURL  string
+}
+
+// LLMProvider completes prompts for the AI features: recommendation rows,
+// similar and custom picks, surprise me and smart search. Each
+// implementation speaks one vendor's API; aiClient builds the prompts and
+// spaces out requests.
+type LLMProvider interface {
+	// Name labels the provider in logs and errors, e.g. "OpenAI".
+	Name() string
+	// Model is the model prompts are sent to. It is part of AI cache keys.
+	Model() string
+	Complete(ctx context.Context, req LLMRequest) (string, error)
+}
+
+// LLMRequest is a single prompt for an LLMProvider.
+type LLMRequest struct {
+	System      string // Instructions sent ahead of the prompt, where the API takes them
+	Prompt      string
+	Temperature float64
+	MaxTokens   int
+	Label       string // Names the feature in logs, e.g. "recommendations"
+}
+
+func normalizeMetadataAIProvider(provider, apiKey string) string {
+	switch strings.ToLower(strings.TrimSpace(provider)) {
+	case "", "none":
+		if strings.TrimSpace(apiKey) == "" {
+			return ""
+		}
+		return aiProviderGemini
+	case "gemini", "google", "google-gemini":
+		return aiProviderGemini
+	case "openai", "chatgpt", "gpt":
+		return aiProviderOpenAI
+	case "anthropic", "claude":
+		return aiProviderAnthropic
+	case "openrouter", "open-router":
+		return aiProviderOpenRouter
+	case "nanogpt", "nano-gpt", "nano_gpt":
+		return aiProviderNanoGPT
+	case "linkapi", "link-api", "link_api":
+		return aiProviderLinkAPI
+	case "ollama":
+		return aiProviderOllama
+	case "openai-compatible", "openai_compatible", "openaicompatible", "custom":
+		return aiProviderOpenAICompatible
+	default:
+		return strings.ToLower(strings.TrimSpace(provider))
+	}
+}
+
+// newLLMProvider returns the provider cfg selects, or nil when AI is off or
+// the provider is missing what it needs: hosted providers need an API key
+// and a custom OpenAI-compatible endpoint needs its base URL. Ollama runs
+// locally and needs neither. Unknown providers are treated as Gemini.
+func newLLMProvider(cfg AIConfig, httpc *http.Client) LLMProvider {
+	api := llmAPI{
+		apiKey:  strings.TrimSpace(cfg.APIKey),
+		model:   strings.TrimSpace(cfg.Model),
+		baseURL: strings.TrimRight(strings.TrimSpace(cfg.BaseURL), "/"),
+		httpc:   httpc,
+	}
+	switch provider := normalizeMetadataAIProvider(cfg.Provider, cfg.APIKey); provider {
+	case "":
+		return nil
+	case aiProviderOllama:
+		return &ollamaProvider{api.withDefaults("Ollama", ollamaBaseURL, "llama3.1")}
+	case aiProviderOpenAICompatible:
+		if api.baseURL == "" {
+			return nil
+		}
+		return &openAICompatibleProvider{llmAPI: api.withDefaults("OpenAI-compatible", "", "")}
+	default:
+		if api.apiKey == "" {
+			return nil
+		}
+		switch provider {
+		case aiProviderOpenAI:
+			return &openAICompatibleProvider{llmAPI: api.withDefaults("OpenAI", "https://api.openai.com/v1", "gpt-5.5")}
+		case aiProviderOpenRouter:
+			return &openAICompatibleProvider{
+				llmAPI: api.withDefaults("OpenRouter", "https://openrouter.ai/api/v1", "~openai/gpt-latest"),
+				headers: map[string]string{
+					"HTTP-Referer":       "https://github.com/godver3/mediastorm",
+					"X-OpenRouter-Title": "mediastorm",
+				},
+			}
+		case aiProviderNanoGPT:
+			return &openAICompatibleProvider{llmAPI: api.withDefaults("NanoGPT", "https://nano-gpt.com/api/v1", "gpt-4o-mini")}
+		case aiProviderLinkAPI:
+			return &openAICompatibleProvider{llmAPI: api.withDefaults("LinkAPI", "https://api.linkapi.org/v1", "gpt-4o-mini")}
+		case aiProviderAnthropic:
+			return &anthropicProvider{api.withDefaults("Anthropic", "https://api.anthropic.com/v1", "claude-sonnet-4-5")}
+		default:
+			return &geminiProvider{api.withDefaults("Gemini", geminiBaseURL, "gemma-4-26b-a4b-it")}
+		}
+	}
+}
+
+// llmAPI holds what every provider needs to reach its API.
+type llmAPI struct {
+	name    string
+	apiKey  string
+	model   string
+	baseURL string
+	httpc   *http.Client
+}
+
+func (a llmAPI) Name() string  { return a.name }
+func (a llmAPI) Model() string { return a.model }
+
+// withDefaults names the API and fills in the base URL and model when the
+// config leaves them blank.
+func (a llmAPI) withDefaults(name, baseURL, model string) llmAPI {
+	a.name = name
+	if a.baseURL == "" {
+		a.baseURL = baseURL
+	}
+	if a.model == "" {
+		a.model = model
+	}
+	return a
+}
+
+// postJSON posts body to endpoint and decodes the response into out,
+// retrying network errors, rate limits and server errors up to three times.
+func (a llmAPI) postJSON(ctx context.Context, endpoint string, headers map[string]string, body, out any, label string) error {
+	logPrefix := strings.ToLower(a.name)
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("marshal %s request: %w", a.name, err)
+	}
+	if err := offlineError(logPrefix); err != nil {
+		return err
+	}
+	var lastErr error
+	backoff := 500 * time.Millisecond
+	for attempt := 0; attempt < 3; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(bodyBytes))
+		if err != nil {
+			return fmt.Errorf("create %s request: %w", logPrefix, err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+
+		resp, err := a.httpc.Do(req)
+		if err != nil {
+			lastErr = err
+			log.Printf("[%s] %s http error (attempt %d/3): %v", logPrefix, label, attempt+1, err)
+			time.Sleep(backoff)
+			backoff *= 2
+			continue
+		}
+
+		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+			resp.Body.Close()
+			lastErr = upstreamError(resp.StatusCode, "%s request failed: status %d", logPrefix, resp.StatusCode)
+			log.Printf("[%s] %s retryable status (attempt %d/3): %d", logPrefix, label, attempt+1, resp.StatusCode)
+			time.Sleep(backoff)
+			backoff *= 2
+			continue
+		}
+
+		defer resp.Body.Close()
+		if resp.StatusCode >= 400 {
+			body, _ := io.ReadAll(resp.Body)
+			return upstreamError(resp.StatusCode, "%s API error %d: %s", a.name, resp.StatusCode, string(body))
+		}
+
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("decode %s response: %w", logPrefix, err)
+		}
+		return nil
+	}
+	return fmt.Errorf("%s request failed after 3 attempts: %w", a.name, lastErr)
+}
+
+// geminiProvider calls the Gemini generateContent API.
+type geminiProvider struct{ llmAPI }
+
+// geminiRequest is the request body for the Gemini generateContent API.
+type geminiRequest struct {
+	SystemInstruction *geminiContent          `json:"systemInstruction,omitempty"`
+	Contents          []geminiContent         `json:"contents"`
+	GenerationConfig  *geminiGenerationConfig `json:"generationConfig,omitempty"`
+}
+
+type geminiContent struct {
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+// noThinkSystem is the system instruction that suppresses Gemma 4 thinking tokens.
+var noThinkSystem = &geminiContent{Parts: []geminiPart{{Text: "You are a helpful assistant. no thought tokens. Respond directly without reasoning."}}}
+
+// noThinkPrompt wraps a prompt with the <thought off> prefix that, combined with
+// noThinkSystem, reliably suppresses the Gemma 4 thinking chain (~14s vs ~36s).
+func noThinkPrompt(prompt string) string {
+	return "<thought off> " + prompt
+}
+
+type geminiGenerationConfig struct {
+	Temperature      float64 `json:"temperature"`
+	MaxOutputTokens  int     `json:"maxOutputTokens"`
+	ResponseMIMEType string  `json:"responseMimeType,omitempty"`
+}
+
+// geminiResponse is the response from the Gemini generateContent API.
+type geminiResponse struct {
+	Candidates []struct {
+		Content struct {
+			Parts []struct {
+				Text    string `json:"text"`
+				Thought bool   `json:"thought"`
+			} `json:"parts"`
+		} `json:"content"`
+	} `json:"candidates"`
+	Error *struct {
+		Message string `json:"message"`
+		Code    int    `json:"code"`
+	} `json:"error,omitempty"`
+}
+
+// geminiResponseText returns the first non-thought part text from a response.
+// Gemma 4 thinking models split output into a thought part (reasoning chain)
+// and an answer part — we always want the answer.
+func geminiResponseText(resp geminiResponse) (string, error) {
+	if len(resp.Candidates) == 0 {
+		return "", errors.New("gemini returned empty response")
+	}
+	parts := resp.Candidates[0].Content.Parts
+	for _, p := range parts {
+		if !p.Thought {
+			return p.Text, nil
+		}
+	}
+	if len(parts) > 0 {
+		return parts[len(parts)-1].Text, nil
+	}
+	return "", errors.New("gemini returned empty response")
+}
+
+// Complete sends the prompt with the no-think system instruction in place
+// of req.System, since the default Gemma models otherwise spend most of
+// the request thinking.
+func (p *geminiProvider) Complete(ctx context.Context, req LLMRequest) (string, error) {
+	endpoint := fmt.Sprintf("%s/models/%s:generateContent?key=%s", p.baseURL, p.model, p.apiKey)
+	reqBody := geminiRequest{
+		SystemInstruction: noThinkSystem,
+		Contents: []geminiContent{
+			{Parts: []geminiPart{{Text: noThinkPrompt(req.Prompt)}}},
+		},
+		GenerationConfig: &geminiGenerationConfig{
+			Temperature:     req.Temperature,
+			MaxOutputTokens: req.MaxTokens,
+		},
+	}
+
+	var geminiResp geminiResponse
+	if err := p.postJSON(ctx, endpoint, nil, reqBody, &geminiResp, req.Label); err != nil {
+		return "", err
+	}
+	if geminiResp.Error != nil {
+		return "", fmt.Errorf("gemini API error: %s", geminiResp.Error.Message)
+	}
+	return geminiResponseText(geminiResp)
+}
+
+// openAICompatibleProvider calls an OpenAI-style chat completions API:
+// OpenAI itself, the OpenAI-compatible hosted services, or any self-hosted
+// server that speaks the protocol (LM Studio, vLLM, LocalAI, llama.cpp).
+type openAICompatibleProvider struct {
+	llmAPI
+	headers map[string]string
+}
+
+type openAIChatRequest struct {
+	Model       string              `json:"model"`
+	Messages    []openAIChatMessage `json:"messages"`
+	Temperature float64             `json:"temperature,omitempty"`
+	MaxTokens   int                 `json:"max_tokens,omitempty"`
+}
+
+type openAIChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIChatResponse struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error,omitempty"`
+}
+
+func (p *openAICompatibleProvider) Complete(ctx context.Context, req LLMRequest) (string, error) {
+	reqBody := openAIChatRequest{
+		Model:       p.model,
+		Messages:    llmChatMessages(req),
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+	}
+	headers := make(map[string]string, len(p.headers)+1)
+	if p.apiKey != "" {
+		headers["Authorization"] = "Bearer " + p.apiKey
+	}
+	for k, v := range p.headers {
+		headers[k] = v
+	}
+
+	var chatResp openAIChatResponse
+	if err := p.postJSON(ctx, p.baseURL+"/chat/completions", headers, reqBody, &chatResp, req.Label); err != nil {
+		return "", err
+	}
+	if chatResp.Error != nil {
+		return "", fmt.Errorf("%s API error: %s", p.name, chatResp.Error.Message)
+	}
+	if len(chatResp.Choices) == 0 {
+		return "", fmt.Errorf("%s returned empty response", p.name)
+	}
+	return chatResp.Choices[0].Message.Content, nil
+}
+
+func llmChatMessages(req LLMRequest) []openAIChatMessage {
+	messages := make([]openAIChatMessage, 0, 2)
+	if req.System != "" {
+		messages = append(messages, openAIChatMessage{Role: "system", Content: req.System})
+	}
+	return append(messages, openAIChatMessage{Role: "user", Content: req.Prompt})
+}
+
+// anthropicProvider calls the Anthropic Messages API.
+type anthropicProvider struct{ llmAPI }
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	MaxTokens   int                `json:"max_tokens"`
+	Temperature float64            `json:"temperature,omitempty"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	Error *struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error,omitempty"`
+}
+
+func (p *anthropicProvider) Complete(ctx context.Context, req LLMRequest) (string, error) {
+	reqBody := anthropicRequest{
+		Model:       p.model,
+		MaxTokens:   req.MaxTokens,
+		Temperature: req.Temperature,
+		System:      req.System,
+		Messages: []anthropicMessage{
+			{Role: "user", Content: req.Prompt},
+		},
+	}
+	headers := map[string]string{
+		"x-api-key":         p.apiKey,
+		"anthropic-version": "2023-06-01",
+	}
+
+	var msgResp anthropicResponse
+	if err := p.postJSON(ctx, p.baseURL+"/messages", headers, reqBody, &msgResp, req.Label); err != nil {
+		return "", err
+	}
+	if msgResp.Error != nil {
+		return "", fmt.Errorf("Anthropic API error: %s", msgResp.Error.Message)
+	}
+	for _, part := range msgResp.Content {
+		if part.Text != "" {
+			return part.Text, nil
+		}
+	}
+	return "", errors.New("Anthropic returned empty response")
+}
+
+// ollamaProvider calls a local Ollama server's chat API, so AI rows work
+// without a hosted provider account. An API key, if set, is sent as a
+// bearer token for servers behind an authenticating proxy.
+type ollamaProvider struct{ llmAPI }
+
+type ollamaChatRequest struct {
+	Model    string              `json:"model"`
+	Messages []openAIChatMessage `json:"messages"`
+	Stream   bool                `json:"stream"`
+	Options  ollamaOptions       `json:"options"`
+}
+
+type ollamaOptions struct {
+	Temperature float64 `json:"temperature"`
+	NumPredict  int     `json:"num_predict,omitempty"`
+}
+
+type ollamaChatResponse struct {
+	Message struct {
+		Content string `json:"content"`
+	} `json:"message"`
+	Error string `json:"error,omitempty"`
+}
+
+func (p *ollamaProvider) Complete(ctx context.Context, req LLMRequest) (string, error) {
+	reqBody := ollamaChatRequest{
+		Model:    p.model,
+		Messages: llmChatMessages(req),
+		Options:  ollamaOptions{Temperature: req.Temperature, NumPredict: req.MaxTokens},
+	}
+	var headers map[string]string
+	if p.apiKey != "" {
+		headers = map[string]string{"Authorization": "Bearer " + p.apiKey}
+	}
+
+	var chatResp ollamaChatResponse
+	if err := p.postJSON(ctx, p.baseURL+"/api/chat", headers, reqBody, &chatResp, req.Label); err != nil {
+		return "", err
+	}
+	if chatResp.Error != "" {
+		return "", fmt.Errorf("Ollama error: %s", chatResp.Error)
+	}
+	if strings.TrimSpace(chatResp.Message.Content) == "" {
+		return "", errors.New("Ollama returned empty response")
+	}
+	return chatResp.Message.Content, nil
+}
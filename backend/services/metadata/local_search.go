@@ -0,0 +1,408 @@
+package metadata
+
+import (
+	"encoding/json"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+
+	"novastream/models"
+)
+
+const (
+	// localSearchIndexMax bounds the titles held by the local search index.
+	// Titles beyond it are dropped until the next restart.
+	localSearchIndexMax = 50000
+	// localSearchDefaultLimit caps SearchLocal results when no limit is given.
+	localSearchDefaultLimit = 20
+	// localSearchExtraBoost ranks the caller's own titles (watchlist, history)
+	// above equally good matches from the shared index.
+	localSearchExtraBoost = 15
+)
+
+// localSearchNamespaces are the cache namespaces whose entries can hold
+// titles: search and shelf results, and movie and series details.
+var localSearchNamespaces = map[string]bool{
+	CacheNamespaceSearch:   true,
+	CacheNamespaceTrending: true,
+	CacheNamespaceDiscover: true,
+	CacheNamespaceSeries:   true,
+	CacheNamespaceMovies:   true,
+}
+
+// localSearchIndex is an in-memory inverted index from name tokens to the
+// titles the service has seen, shared by all language clones of a Service.
+// It is filled from the metadata cache once, in the background, and from
+// every remote search after that; it is never persisted.
+type localSearchIndex struct {
+	mu       sync.RWMutex
+	docs     map[string]models.Title
+	postings map[string]map[string]struct{}
+	warmOnce sync.Once
+}
+
+func newLocalSearchIndex() *localSearchIndex {
+	return &localSearchIndex{
+		docs:     make(map[string]models.Title),
+		postings: make(map[string]map[string]struct{}),
+	}
+}
+
+// add indexes titles by their name, original name and alternate titles. A
+// title already indexed is replaced, keeping the index to one entry per
+// title identity.
+func (idx *localSearchIndex) add(titles ...models.Title) {
+	if idx == nil {
+		return
+	}
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, title := range titles {
+		if strings.TrimSpace(title.Name) == "" || (title.MediaType != "movie" && title.MediaType != "series") {
+			continue
+		}
+		id := searchResultIdentity(title)
+		if id == "" {
+			continue
+		}
+		if _, exists := idx.docs[id]; !exists && len(idx.docs) >= localSearchIndexMax {
+			continue
+		}
+		idx.docs[id] = localSearchDoc(title)
+		for _, token := range localSearchTitleTokens(title) {
+			ids := idx.postings[token]
+			if ids == nil {
+				ids = make(map[string]struct{})
+				idx.postings[token] = ids
+			}
+			ids[id] = struct{}{}
+		}
+	}
+}
+
+// localSearchDoc trims a title to the fields search results use, so the
+// index doesn't pin cast, trailers and release lists in memory.
+func localSearchDoc(title models.Title) models.Title {
+	return models.Title{
+		ID:              title.ID,
+		Name:            title.Name,
+		OriginalName:    title.OriginalName,
+		AlternateTitles: title.AlternateTitles,
+		Overview:        title.Overview,
+		Year:            title.Year,
+		Language:        title.Language,
+		Poster:          title.Poster,
+		Backdrop:        title.Backdrop,
+		MediaType:       title.MediaType,
+		TVDBID:          title.TVDBID,
+		IMDBID:          title.IMDBID,
+		TMDBID:          title.TMDBID,
+		Popularity:      title.Popularity,
+		Network:         title.Network,
+		Certification:   title.Certification,
+		Genres:          title.Genres,
+		Adult:           title.Adult,
+	}
+}
+
+// search returns the indexed titles matching every query token, the last
+// one as a prefix so results keep up with search-as-you-type.
+func (idx *localSearchIndex) search(query []string, mediaType string) []models.SearchResult {
+	if idx == nil || len(query) == 0 {
+		return nil
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var candidates map[string]struct{}
+	for i, token := range query {
+		matches := make(map[string]struct{})
+		if i == len(query)-1 {
+			for indexed, ids := range idx.postings {
+				if strings.HasPrefix(indexed, token) {
+					for id := range ids {
+						matches[id] = struct{}{}
+					}
+				}
+			}
+		} else {
+			for id := range idx.postings[token] {
+				matches[id] = struct{}{}
+			}
+		}
+		if candidates == nil {
+			candidates = matches
+			continue
+		}
+		for id := range candidates {
+			if _, ok := matches[id]; !ok {
+				delete(candidates, id)
+			}
+		}
+	}
+
+	results := make([]models.SearchResult, 0, len(candidates))
+	for id := range candidates {
+		title := idx.docs[id]
+		if mediaType != "" && title.MediaType != mediaType {
+			continue
+		}
+		if score := localMatchScore(query, title); score > 0 {
+			results = append(results, models.SearchResult{Title: title, Score: score})
+		}
+	}
+	return results
+}
+
+func (idx *localSearchIndex) size() int {
+	if idx == nil {
+		return 0
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// warmLocalSearchIndex indexes the titles held in the metadata cache. Each
+// entry is decoded as a list of results or shelf items, as details with a
+// nested title, or as a bare title; entries of any other shape index nothing.
+func (s *Service) warmLocalSearchIndex() {
+	if s.searchIndex == nil || s.cache == nil {
+		return
+	}
+	keys, err := s.cache.keys()
+	if err != nil {
+		log.Printf("[metadata] local search index warm-up failed: %v", err)
+		return
+	}
+	for _, key := range keys {
+		if !localSearchNamespaces[cacheKeyNamespace(key)] {
+			continue
+		}
+		var raw json.RawMessage
+		if ok, _ := s.cache.get(key, &raw); !ok {
+			continue
+		}
+		s.searchIndex.add(cachedTitles(raw)...)
+	}
+	log.Printf("[metadata] local search index warmed titles=%d", s.searchIndex.size())
+}
+
+// cachedTitles extracts the titles from a cached value.
+func cachedTitles(raw json.RawMessage) []models.Title {
+	trimmed := strings.TrimSpace(string(raw))
+	if strings.HasPrefix(trimmed, "[") {
+		var items []struct {
+			Title models.Title `json:"title"`
+		}
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil
+		}
+		titles := make([]models.Title, 0, len(items))
+		for _, item := range items {
+			titles = append(titles, item.Title)
+		}
+		return titles
+	}
+	var details struct {
+		Title *models.Title `json:"title"`
+	}
+	if err := json.Unmarshal(raw, &details); err == nil && details.Title != nil {
+		return []models.Title{*details.Title}
+	}
+	var title models.Title
+	if err := json.Unmarshal(raw, &title); err != nil || title.ID == "" {
+		return nil
+	}
+	return []models.Title{title}
+}
+
+// SearchLocal matches query against the local index of titles the service
+// has already fetched, without calling any provider. extra titles (such as
+// the profile's watchlist and history) are matched too and ranked above
+// equally good matches from the index. When nothing matches as typed,
+// titles within a typo of the query are returned instead.
+// The first call starts indexing the cache in the background, so early
+// results may be incomplete. Results are sorted by score, best first.
+func (s *Service) SearchLocal(query, mediaType string, extra []models.Title, limit int) []models.SearchResult {
+	tokens := localSearchTokens(query)
+	if len(tokens) == 0 {
+		return []models.SearchResult{}
+	}
+	switch mediaType = strings.ToLower(strings.TrimSpace(mediaType)); mediaType {
+	case "movies":
+		mediaType = "movie"
+	case "", "all":
+		mediaType = ""
+	case "movie":
+	default:
+		mediaType = "series"
+	}
+	if limit <= 0 {
+		limit = localSearchDefaultLimit
+	}
+	if s.searchIndex != nil {
+		s.searchIndex.warmOnce.Do(func() { go s.warmLocalSearchIndex() })
+	}
+
+	var results []models.SearchResult
+	for _, title := range extra {
+		if mediaType != "" && title.MediaType != mediaType {
+			continue
+		}
+		if score := localMatchScore(tokens, title); score > 0 {
+			results = append(results, models.SearchResult{Title: title, Score: score + localSearchExtraBoost})
+		}
+	}
+	results = mergeSearchResults(append(results, s.searchIndex.search(tokens, mediaType)...))
+	if len(results) == 0 {
+		// Nothing matches as typed; fall back to titles within a typo.
+		q := parseSearchQuery(query)
+		for _, match := range s.searchIndex.fuzzySearch(q.name, mediaType) {
+			if sim := searchTitleSimilarity(q, match.Title); sim >= searchFuzzyMinSimilarity {
+				match.Score = int(math.Round(sim * 100))
+				results = append(results, match)
+			}
+		}
+	}
+	if !s.adultSearchAllowed() {
+		filtered := results[:0]
+		for _, result := range results {
+			if !result.Title.Adult {
+				filtered = append(filtered, result)
+			}
+		}
+		results = filtered
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Title.Popularity > results[j].Title.Popularity
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// localMatchScore rates how well a title's names match the query tokens,
+// from 100 for an exact name down to 50 for names containing every token;
+// 0 means no match. The last token may be a prefix.
+func localMatchScore(query []string, title models.Title) int {
+	best := 0
+	for i, name := range localSearchNames(title) {
+		tokens := localSearchTokens(name)
+		score := 0
+		switch {
+		case equalTokens(tokens, query):
+			score = 100
+		case hasTokenPrefix(tokens, query):
+			score = 80
+		case containsTokens(tokens, query):
+			score = 60
+		}
+		if score > 0 && i > 0 {
+			// Alternate names rank just below the same match on the name.
+			score -= 10
+		}
+		if score > best {
+			best = score
+		}
+	}
+	return best
+}
+
+func localSearchNames(title models.Title) []string {
+	names := []string{title.Name}
+	if title.OriginalName != "" {
+		names = append(names, title.OriginalName)
+	}
+	return append(names, title.AlternateTitles...)
+}
+
+func localSearchTitleTokens(title models.Title) []string {
+	var tokens []string
+	for _, name := range localSearchNames(title) {
+		tokens = append(tokens, localSearchTokens(name)...)
+	}
+	return tokens
+}
+
+// localSearchTokens lower-cases text, strips accents and splits it on
+// anything that isn't a letter or digit.
+func localSearchTokens(text string) []string {
+	var b strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(text)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return strings.FieldsFunc(b.String(), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func equalTokens(tokens, query []string) bool {
+	if len(tokens) != len(query) {
+		return false
+	}
+	for i := range tokens {
+		if tokens[i] != query[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// hasTokenPrefix reports whether tokens start with query, the last query
+// token matching as a prefix.
+func hasTokenPrefix(tokens, query []string) bool {
+	if len(tokens) < len(query) {
+		return false
+	}
+	last := len(query) - 1
+	for i := 0; i < last; i++ {
+		if tokens[i] != query[i] {
+			return false
+		}
+	}
+	return strings.HasPrefix(tokens[last], query[last])
+}
+
+// containsTokens reports whether every query token appears in tokens, the
+// last one as a prefix of some token.
+func containsTokens(tokens, query []string) bool {
+	last := len(query) - 1
+	for i, q := range query {
+		found := false
+		for _, token := range tokens {
+			if token == q || (i == last && strings.HasPrefix(token, q)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// indexSearchResults adds remote search results to the local index.
+func (s *Service) indexSearchResults(results []models.SearchResult) {
+	if s.searchIndex == nil {
+		return
+	}
+	titles := make([]models.Title, 0, len(results))
+	for _, result := range results {
+		titles = append(titles, result.Title)
+	}
+	s.searchIndex.add(titles...)
+}
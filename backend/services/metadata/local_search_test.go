@@ -0,0 +1,58 @@
+package metadata
+
+import (
+	"testing"
+
+	"novastream/models"
+)
+
+func TestSearchLocal(t *testing.T) {
+	svc := &Service{searchIndex: newLocalSearchIndex()}
+	svc.searchIndex.warmOnce.Do(func() {})
+	svc.searchIndex.add(
+		models.Title{ID: "tmdb:movie:194", Name: "Amélie", OriginalName: "Le Fabuleux Destin d'Amélie Poulain", MediaType: "movie", TMDBID: 194},
+		models.Title{ID: "tmdb:movie:603", Name: "The Matrix", MediaType: "movie", TMDBID: 603, Popularity: 90},
+		models.Title{ID: "tmdb:movie:604", Name: "The Matrix Reloaded", MediaType: "movie", TMDBID: 604, Popularity: 50},
+		models.Title{ID: "tvdb:series:81189", Name: "Breaking Bad", MediaType: "series", TVDBID: 81189},
+	)
+
+	results := svc.SearchLocal("the matr", "", nil, 0)
+	if len(results) != 2 || results[0].Title.TMDBID != 603 {
+		t.Fatalf("expected both Matrix films by popularity, got %+v", results)
+	}
+	if results := svc.SearchLocal("amelie", "movie", nil, 0); len(results) != 1 || results[0].Score != 100 {
+		t.Fatalf("expected an accent-insensitive exact match, got %+v", results)
+	}
+	if results := svc.SearchLocal("fabuleux", "", nil, 0); len(results) != 1 || results[0].Title.Name != "Amélie" {
+		t.Fatalf("expected an original title match, got %+v", results)
+	}
+	if results := svc.SearchLocal("matrix", "series", nil, 0); len(results) != 0 {
+		t.Fatalf("expected no series matches, got %+v", results)
+	}
+
+	extra := []models.Title{{ID: "tmdb:movie:604", Name: "The Matrix Reloaded", MediaType: "movie", TMDBID: 604}}
+	results = svc.SearchLocal("matrix", "", extra, 1)
+	if len(results) != 1 || results[0].Title.TMDBID != 604 {
+		t.Fatalf("expected the profile's own title first, got %+v", results)
+	}
+}
+
+func TestWarmLocalSearchIndex(t *testing.T) {
+	svc := &Service{cache: newFileCache(t.TempDir(), 24), searchIndex: newLocalSearchIndex()}
+	_ = svc.cache.set(cacheKey("metadata", "search", "v7", "movie", "heat"), []models.SearchResult{
+		{Title: models.Title{ID: "tmdb:movie:949", Name: "Heat", MediaType: "movie", TMDBID: 949}},
+	})
+	_ = svc.cache.set(cacheKey("tvdb", "series", "details", "81189"), models.SeriesDetails{
+		Title: models.Title{ID: "tvdb:series:81189", Name: "Breaking Bad", MediaType: "series", TVDBID: 81189},
+	})
+	_ = svc.cache.set(cacheKey("tmdb", "trailers", "949"), []models.Trailer{{Name: "Heat Trailer"}})
+
+	svc.warmLocalSearchIndex()
+	if got := svc.searchIndex.size(); got != 2 {
+		t.Fatalf("expected 2 indexed titles, got %d", got)
+	}
+	svc.searchIndex.warmOnce.Do(func() {})
+	if results := svc.SearchLocal("breaking", "series", nil, 0); len(results) != 1 {
+		t.Fatalf("expected the cached series, got %+v", results)
+	}
+}
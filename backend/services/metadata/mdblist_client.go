@@ -25,10 +25,7 @@ type mdblistClient struct {
 	cache    map[string]*mdblistCacheEntry
 	cacheTTL time.Duration
 
-	// Rate limiting
-	throttleMu  sync.Mutex
-	lastRequest time.Time
-	minInterval time.Duration
+	limiter *rateLimiter // shared per-provider limiter; nil disables limiting
 }
 
 type mdblistCacheEntry struct {
@@ -83,7 +80,7 @@ func newMDBListClient(apiKey string, enabledRatings []string, enabled bool, cach
 		enabled:        enabled,
 		cache:          make(map[string]*mdblistCacheEntry),
 		cacheTTL:       time.Duration(cacheTTLHours) * time.Hour,
-		minInterval:    1100 * time.Millisecond, // MDBList free tier: ~1 req/sec
+		limiter:        mdblistLimiter,
 	}
 }
 
@@ -143,32 +140,21 @@ func (c *mdblistClient) GetRatings(ctx context.Context, imdbID string, mediaType
 	// Fetch all ratings in a single API call using /imdb/{type}/{id} endpoint
 	url := fmt.Sprintf("https://api.mdblist.com/imdb/%s/%s?apikey=%s", mediaType, imdbID, c.apiKey)
 
+	if err := offlineError("mdblist"); err != nil {
+		return nil, err
+	}
 	var result mdblistMediaResponse
 	var lastErr error
 	backoff := 2 * time.Second
 
 	// Retry loop with exponential backoff
 	for attempt := 0; attempt < 3; attempt++ {
-		// Rate limiting — compute wait outside the lock to avoid blocking other goroutines
-		c.throttleMu.Lock()
-		wait := c.minInterval - time.Since(c.lastRequest)
-		if wait > 0 {
-			c.lastRequest = time.Now().Add(wait) // reserve our slot
-		} else {
-			c.lastRequest = time.Now()
-			wait = 0
-		}
-		c.throttleMu.Unlock()
-		if wait > 0 {
-			time.Sleep(wait)
-		}
-
 		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 		if err != nil {
 			return nil, fmt.Errorf("create request: %w", err)
 		}
 
-		resp, err := c.httpClient.Do(req)
+		resp, err := c.limiter.do(c.httpClient, req)
 		if err != nil {
 			lastErr = fmt.Errorf("http request: %w", err)
 			log.Printf("[mdblist] http request error (attempt %d/3): %v", attempt+1, err)
@@ -185,7 +171,7 @@ func (c *mdblistClient) GetRatings(ctx context.Context, imdbID string, mediaType
 		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
 			resp.Body.Close()
 			log.Printf("[mdblist] rate limited or server error (attempt %d/3): status %d", attempt+1, resp.StatusCode)
-			lastErr = fmt.Errorf("status %d", resp.StatusCode)
+			lastErr = upstreamError(resp.StatusCode, "status %d", resp.StatusCode)
 			// Context-aware sleep: bail immediately if context expires during backoff
 			select {
 			case <-ctx.Done():
@@ -199,7 +185,7 @@ func (c *mdblistClient) GetRatings(ctx context.Context, imdbID string, mediaType
 		if resp.StatusCode != http.StatusOK {
 			resp.Body.Close()
 			log.Printf("[mdblist] unexpected status %d for %s", resp.StatusCode, url)
-			return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+			return nil, upstreamError(resp.StatusCode, "unexpected status: %d", resp.StatusCode)
 		}
 
 		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
@@ -288,31 +274,20 @@ func (c *mdblistClient) GetAllRatings(ctx context.Context, imdbID string, mediaT
 	// Fetch all ratings in a single API call
 	url := fmt.Sprintf("https://api.mdblist.com/imdb/%s/%s?apikey=%s", mediaType, imdbID, c.apiKey)
 
+	if err := offlineError("mdblist"); err != nil {
+		return nil, err
+	}
 	var result mdblistMediaResponse
 	var lastErr error
 	backoff := 2 * time.Second
 
 	for attempt := 0; attempt < 3; attempt++ {
-		// Rate limiting — compute wait outside the lock to avoid blocking other goroutines
-		c.throttleMu.Lock()
-		wait := c.minInterval - time.Since(c.lastRequest)
-		if wait > 0 {
-			c.lastRequest = time.Now().Add(wait) // reserve our slot
-		} else {
-			c.lastRequest = time.Now()
-			wait = 0
-		}
-		c.throttleMu.Unlock()
-		if wait > 0 {
-			time.Sleep(wait)
-		}
-
 		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 		if err != nil {
 			return nil, fmt.Errorf("create request: %w", err)
 		}
 
-		resp, err := c.httpClient.Do(req)
+		resp, err := c.limiter.do(c.httpClient, req)
 		if err != nil {
 			lastErr = fmt.Errorf("http request: %w", err)
 			log.Printf("[mdblist] http request error (attempt %d/3): %v", attempt+1, err)
@@ -327,7 +302,7 @@ func (c *mdblistClient) GetAllRatings(ctx context.Context, imdbID string, mediaT
 
 		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
 			resp.Body.Close()
-			lastErr = fmt.Errorf("status %d", resp.StatusCode)
+			lastErr = upstreamError(resp.StatusCode, "status %d", resp.StatusCode)
 			select {
 			case <-ctx.Done():
 				return nil, fmt.Errorf("context cancelled during retry: %w", ctx.Err())
@@ -339,7 +314,7 @@ func (c *mdblistClient) GetAllRatings(ctx context.Context, imdbID string, mediaT
 
 		if resp.StatusCode != http.StatusOK {
 			resp.Body.Close()
-			return nil, fmt.Errorf("unexpected status: %d", resp.StatusCode)
+			return nil, upstreamError(resp.StatusCode, "unexpected status: %d", resp.StatusCode)
 		}
 
 		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
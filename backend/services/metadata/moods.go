@@ -0,0 +1,135 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"novastream/models"
+)
+
+// keywordSearchCacheTTL is how long keyword lookups are cached. TMDB keyword
+// IDs never change, so lookups are kept far longer than title metadata.
+const keywordSearchCacheTTL = 30 * 24 * time.Hour
+
+// mood maps a discover preset onto TMDB keyword names and genres. Keywords
+// are looked up by name, so the table doesn't hard-code TMDB IDs; titles
+// match any of the keywords and all of the genres for their media type.
+type mood struct {
+	id           string
+	name         string
+	description  string
+	keywords     []string
+	movieGenres  []int64
+	seriesGenres []int64
+	minVotes     int
+}
+
+var moods = []mood{
+	{id: "feel-good", name: "Feel-Good", description: "Light, uplifting comedies", keywords: []string{"feel-good"}, movieGenres: []int64{35}, seriesGenres: []int64{35}},
+	{id: "slow-burn", name: "Slow Burn", description: "Tension that builds patiently", keywords: []string{"slow burn"}, minVotes: 50},
+	{id: "heist", name: "Heist", description: "Crews, plans and big scores", keywords: []string{"heist", "bank robbery", "con artist"}, movieGenres: []int64{80}, seriesGenres: []int64{80}},
+	{id: "mind-bending", name: "Mind-Bending", description: "Twists, loops and unreliable realities", keywords: []string{"plot twist", "time loop", "alternate reality"}},
+	{id: "coming-of-age", name: "Coming of Age", description: "Growing up and finding yourself", keywords: []string{"coming of age"}},
+	{id: "dystopian", name: "Dystopian", description: "Bleak futures and broken societies", keywords: []string{"dystopia", "post-apocalyptic future"}, movieGenres: []int64{878}, seriesGenres: []int64{10765}},
+	{id: "road-trip", name: "Road Trip", description: "Journeys where the road is the story", keywords: []string{"road trip"}},
+	{id: "true-story", name: "True Stories", description: "Based on real events", keywords: []string{"based on true story"}},
+	{id: "revenge", name: "Revenge", description: "Scores settled the hard way", keywords: []string{"revenge"}, movieGenres: []int64{53}},
+	{id: "whodunit", name: "Whodunit", description: "Clues, suspects and a final reveal", keywords: []string{"whodunit", "murder mystery"}, movieGenres: []int64{9648}, seriesGenres: []int64{9648}},
+	{id: "date-night", name: "Date Night", description: "Romantic comedies for two", keywords: []string{"romantic comedy"}, movieGenres: []int64{10749}, seriesGenres: []int64{35}},
+	{id: "spooky", name: "Spooky", description: "Haunted houses and things that go bump", keywords: []string{"haunted house", "ghost"}, movieGenres: []int64{27}},
+}
+
+// Moods lists the curated discover moods in display order.
+func (s *Service) Moods() []models.Mood {
+	out := make([]models.Mood, 0, len(moods))
+	for _, m := range moods {
+		out = append(out, models.Mood{ID: m.id, Name: m.name, Description: m.description})
+	}
+	return out
+}
+
+func findMood(id string) (mood, bool) {
+	id = strings.ToLower(strings.TrimSpace(id))
+	for _, m := range moods {
+		if m.id == id {
+			return m, true
+		}
+	}
+	return mood{}, false
+}
+
+// SearchKeywords looks up TMDB keywords by name, for building keyword
+// filters. Lookups are cached for keywordSearchCacheTTL.
+func (s *Service) SearchKeywords(ctx context.Context, query string) ([]models.Keyword, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return []models.Keyword{}, nil
+	}
+	if s.tmdb == nil || !s.tmdb.isConfigured() {
+		return nil, errTMDBNotConfigured
+	}
+	key := cacheKey("tmdb", "keyword", "search", "v1", query)
+	var cached []models.Keyword
+	if ok, _ := s.cache.getWithMaxAge(key, &cached, keywordSearchCacheTTL); ok {
+		return cached, nil
+	}
+	keywords, err := s.tmdb.searchKeywords(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.set(key, keywords); err != nil {
+		log.Printf("[metadata] failed to cache keyword search %q: %v", query, err)
+	}
+	return keywords, nil
+}
+
+// moodFilter resolves a mood to the discover filter for mediaType. Keywords
+// TMDB doesn't know are skipped; a mood with neither keywords nor genres
+// left fails rather than returning unfiltered popular titles.
+func (s *Service) moodFilter(ctx context.Context, mediaType string, m mood) (models.DiscoverFilter, error) {
+	filter := models.DiscoverFilter{MediaType: mediaType, MinVotes: m.minVotes}
+	if mediaType == "movie" {
+		filter.GenreIDs = m.movieGenres
+	} else {
+		filter.GenreIDs = m.seriesGenres
+	}
+	for _, name := range m.keywords {
+		keywords, err := s.SearchKeywords(ctx, name)
+		if err != nil {
+			return models.DiscoverFilter{}, err
+		}
+		// Search is fuzzy, so only an exact name match counts.
+		for _, keyword := range keywords {
+			if strings.EqualFold(keyword.Name, name) {
+				filter.KeywordIDs = append(filter.KeywordIDs, keyword.ID)
+				break
+			}
+		}
+	}
+	if len(filter.KeywordIDs) == 0 && len(filter.GenreIDs) == 0 {
+		return models.DiscoverFilter{}, fmt.Errorf("no TMDB keywords found for mood %q", m.id)
+	}
+	return filter, nil
+}
+
+// DiscoverByMood returns TMDB discover results for a curated mood such as
+// "feel-good" or "heist", paged, cached and enriched like the other discover
+// shelves.
+func (s *Service) DiscoverByMood(ctx context.Context, mediaType, moodID string, limit, offset int, opts ShelfLoadOptions) ([]models.TrendingItem, int, error) {
+	m, ok := findMood(moodID)
+	if !ok {
+		return nil, 0, newKindError(ErrNotFound, "unknown mood %q", moodID)
+	}
+	normalizedType := strings.ToLower(strings.TrimSpace(mediaType))
+	if normalizedType != "movie" {
+		normalizedType = "series"
+	}
+	filter, err := s.moodFilter(ctx, normalizedType, m)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.DiscoverWithFilter(ctx, filter, limit, offset, opts)
+}
@@ -0,0 +1,152 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"novastream/models"
+)
+
+const (
+	// multiSearchDefaultLimit caps MultiSearch results when no limit is given.
+	multiSearchDefaultLimit = 30
+	// multiSearchMaxLimit is the most results MultiSearch returns.
+	multiSearchMaxLimit = 100
+)
+
+// MultiSearch searches movies, series and people concurrently and returns
+// one list, best first. Titles keep the score Search ranks them by; people
+// are scored on the same scale from name similarity and popularity. Equal
+// scores keep the lists interleaved, so no type crowds out the others. An
+// error is returned only when every search fails.
+func (s *Service) MultiSearch(ctx context.Context, query string, limit int) ([]models.MultiSearchResult, error) {
+	q := strings.TrimSpace(query)
+	if q == "" {
+		return []models.MultiSearchResult{}, nil
+	}
+	if limit <= 0 {
+		limit = multiSearchDefaultLimit
+	}
+	if limit > multiSearchMaxLimit {
+		limit = multiSearchMaxLimit
+	}
+
+	var (
+		wg                             sync.WaitGroup
+		movies, series                 []models.SearchResult
+		people                         []models.MultiSearchResult
+		movieErr, seriesErr, personErr error
+	)
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		movies, movieErr = s.Search(ctx, q, "movie")
+	}()
+	go func() {
+		defer wg.Done()
+		series, seriesErr = s.Search(ctx, q, "series")
+	}()
+	go func() {
+		defer wg.Done()
+		people, personErr = s.searchPeople(ctx, q)
+	}()
+	wg.Wait()
+
+	if movieErr != nil && seriesErr != nil && personErr != nil {
+		return nil, errors.Join(movieErr, seriesErr, personErr)
+	}
+	for _, err := range []error{movieErr, seriesErr, personErr} {
+		if err != nil {
+			log.Printf("[metadata] multi-search partial failure query=%q err=%v", q, err)
+		}
+	}
+
+	results := interleaveMultiSearch(
+		titleMultiSearchResults(models.MultiSearchTypeMovie, movies),
+		titleMultiSearchResults(models.MultiSearchTypeSeries, series),
+		people,
+	)
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// searchPeople searches TMDB for people and scores them for MultiSearch.
+func (s *Service) searchPeople(ctx context.Context, query string) ([]models.MultiSearchResult, error) {
+	if s.demo || s.tmdb == nil || !s.tmdb.isConfigured() {
+		return nil, nil
+	}
+	allowAdult := s.adultSearchAllowed()
+	adultPolicy := "adult-blocked"
+	if allowAdult {
+		adultPolicy = "adult-allowed"
+	}
+	key := cacheKey("metadata", "search", "person", "v1", query, s.tmdb.language, adultPolicy)
+	var people []tmdbPersonResult
+	if ok, _ := s.cache.get(key, &people); !ok {
+		var err error
+		if people, err = s.tmdb.searchPeople(ctx, query, allowAdult); err != nil {
+			return nil, err
+		}
+		if err := s.cache.set(key, people); err != nil {
+			log.Printf("[metadata] failed to cache person search: %v", err)
+		}
+	}
+
+	q := parseSearchQuery(query)
+	results := make([]models.MultiSearchResult, 0, len(people))
+	for _, p := range people {
+		person := p.Person
+		results = append(results, models.MultiSearchResult{
+			Type:   models.MultiSearchTypePerson,
+			Person: &person,
+			Score:  personSearchScore(q, p),
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results, nil
+}
+
+// personSearchScore puts people on the scale of searchRankScoreFor: name
+// similarity up to 60 plus up to 22 for popularity, standing in for the
+// popularity and vote count titles are scored on.
+func personSearchScore(q searchQuery, p tmdbPersonResult) int {
+	score := searchNameSimilarity(q.tokens, localSearchTokens(p.Person.Name)) * 60
+	if p.Popularity > 0 {
+		score += math.Min(22, 5*math.Log10(1+p.Popularity))
+	}
+	return int(math.Round(score))
+}
+
+func titleMultiSearchResults(kind string, results []models.SearchResult) []models.MultiSearchResult {
+	out := make([]models.MultiSearchResult, 0, len(results))
+	for _, result := range results {
+		title := result.Title
+		out = append(out, models.MultiSearchResult{Type: kind, Title: &title, Score: result.Score})
+	}
+	return out
+}
+
+// interleaveMultiSearch takes one result from each list in turn.
+func interleaveMultiSearch(lists ...[]models.MultiSearchResult) []models.MultiSearchResult {
+	total := 0
+	for _, list := range lists {
+		total += len(list)
+	}
+	out := make([]models.MultiSearchResult, 0, total)
+	for i := 0; len(out) < total; i++ {
+		for _, list := range lists {
+			if i < len(list) {
+				out = append(out, list[i])
+			}
+		}
+	}
+	return out
+}
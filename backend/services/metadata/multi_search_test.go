@@ -0,0 +1,78 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"novastream/models"
+)
+
+func TestMultiSearchInterleavesTypesByScore(t *testing.T) {
+	httpc := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body := `{"data":[]}`
+			switch req.URL.Path {
+			case "/v4/login":
+				body = `{"data":{"token":"test-token"}}`
+			case "/3/search/movie":
+				body = `{"results":[
+					{"id":603,"title":"The Matrix","release_date":"1999-03-31","popularity":60,"vote_average":8.2,"vote_count":25000},
+					{"id":604,"title":"The Matrix Reloaded","release_date":"2003-05-15","popularity":40,"vote_average":7.0,"vote_count":10000}
+				]}`
+			case "/3/search/tv":
+				body = `{"results":[{"id":9001,"name":"Matrix","first_air_date":"1993-03-01","popularity":3,"vote_count":20}]}`
+			case "/3/search/person":
+				body = `{"results":[
+					{"id":1,"name":"Matrix Smith","known_for_department":"Acting","popularity":2},
+					{"id":2,"name":"Someone Else","popularity":90}
+				]}`
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(body)), Header: make(http.Header)}, nil
+		}),
+	}
+	cacheDir := t.TempDir()
+	svc := &Service{
+		client: newTVDBClient("test-tvdb-key", "eng", httpc, 24),
+		tmdb:   newTMDBClient("test-tmdb-key", "eng", httpc, newFileCache(cacheDir, 24)),
+		cache:  newFileCache(cacheDir, 24),
+	}
+	svc.client.limiter = nil
+
+	results, err := svc.MultiSearch(context.Background(), "the matrix", 0)
+	if err != nil {
+		t.Fatalf("MultiSearch failed: %v", err)
+	}
+	types := make(map[string]int)
+	for i, result := range results {
+		types[result.Type]++
+		if i > 0 && result.Score > results[i-1].Score {
+			t.Fatalf("results not sorted by score: %+v", results)
+		}
+		if (result.Type == models.MultiSearchTypePerson) != (result.Person != nil) || (result.Title == nil) == (result.Person == nil) {
+			t.Fatalf("result %d has mismatched payload: %+v", i, result)
+		}
+	}
+	if types[models.MultiSearchTypeMovie] != 2 || types[models.MultiSearchTypeSeries] == 0 || types[models.MultiSearchTypePerson] != 2 {
+		t.Fatalf("expected movies, series and people, got %v", types)
+	}
+	if results[0].Title == nil || results[0].Title.TMDBID != 603 {
+		t.Fatalf("expected The Matrix first, got %+v", results[0])
+	}
+
+	limited, err := svc.MultiSearch(context.Background(), "the matrix", 2)
+	if err != nil || len(limited) != 2 {
+		t.Fatalf("expected 2 limited results, got %d (err=%v)", len(limited), err)
+	}
+}
+
+func TestInterleaveMultiSearch(t *testing.T) {
+	a := []models.MultiSearchResult{{Type: "movie", Score: 1}, {Type: "movie", Score: 2}}
+	b := []models.MultiSearchResult{{Type: "person", Score: 3}}
+	got := interleaveMultiSearch(a, nil, b)
+	if len(got) != 3 || got[0].Score != 1 || got[1].Score != 3 || got[2].Score != 2 {
+		t.Fatalf("unexpected interleave %+v", got)
+	}
+}
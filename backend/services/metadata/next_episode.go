@@ -0,0 +1,156 @@
+package metadata
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"novastream/models"
+)
+
+const (
+	// dailySeriesMinEpisodes is how many dated episodes a series needs before
+	// its air-date spacing is trusted to mark it as a daily show.
+	dailySeriesMinEpisodes = 10
+	// dailySeriesMaxGap is the largest typical gap between episodes of a
+	// daily show; weekday-only shows skip the weekend.
+	dailySeriesMaxGap = 3 * 24 * time.Hour
+)
+
+// NextEpisode resolves the episode to watch after lastSeason/lastEpisode from
+// the cached series details. Pass lastEpisode 0 to get the first episode.
+// Specials only lead to further specials, season gaps are skipped, and daily
+// shows follow air-date order rather than episode numbers. The result reports
+// whether the episode has aired. Returns nil when nothing follows.
+func (s *Service) NextEpisode(ctx context.Context, req models.SeriesDetailsQuery, lastSeason, lastEpisode int) (*models.ResolvedNextEpisode, error) {
+	details, err := s.seriesDetails(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resolveNextEpisode(details, lastSeason, lastEpisode, time.Now()), nil
+}
+
+type orderedEpisode struct {
+	models.SeriesEpisode
+	airAt time.Time
+}
+
+func resolveNextEpisode(details *models.SeriesDetails, lastSeason, lastEpisode int, now time.Time) *models.ResolvedNextEpisode {
+	if details == nil {
+		return nil
+	}
+
+	// Specials stay among specials; everything else walks the regular seasons.
+	specials := lastSeason == 0 && lastEpisode > 0
+	var episodes []orderedEpisode
+	for _, season := range details.Seasons {
+		if (season.Number == 0) != specials || season.Number < 0 {
+			continue
+		}
+		for _, ep := range season.Episodes {
+			if ep.EpisodeNumber <= 0 {
+				continue
+			}
+			if ep.SeasonNumber == 0 && season.Number != 0 {
+				ep.SeasonNumber = season.Number
+			}
+			episodes = append(episodes, orderedEpisode{
+				SeriesEpisode: ep,
+				airAt:         episodeAirTime(&ep, details.Title.AirsTime, details.Title.AirsTimezone),
+			})
+		}
+	}
+	if len(episodes) == 0 {
+		return nil
+	}
+
+	byNumber := func(a, b orderedEpisode) bool {
+		if a.SeasonNumber != b.SeasonNumber {
+			return a.SeasonNumber < b.SeasonNumber
+		}
+		return a.EpisodeNumber < b.EpisodeNumber
+	}
+	sort.SliceStable(episodes, func(i, j int) bool { return byNumber(episodes[i], episodes[j]) })
+	if !specials && isDailySeries(episodes) {
+		// Daily shows are often numbered out of broadcast order (or renumbered
+		// per year), so the air date decides what comes next.
+		sort.SliceStable(episodes, func(i, j int) bool {
+			a, b := episodes[i], episodes[j]
+			if a.airAt.IsZero() || b.airAt.IsZero() || a.airAt.Equal(b.airAt) {
+				if a.airAt.IsZero() != b.airAt.IsZero() {
+					return b.airAt.IsZero()
+				}
+				return byNumber(a, b)
+			}
+			return a.airAt.Before(b.airAt)
+		})
+	}
+
+	next := -1
+	if lastEpisode <= 0 {
+		next = 0
+	} else {
+		for i, ep := range episodes {
+			if ep.SeasonNumber == lastSeason && ep.EpisodeNumber == lastEpisode {
+				next = i + 1
+				break
+			}
+		}
+		if next < 0 {
+			// The last episode is not in the metadata (e.g. it was removed or
+			// renumbered); continue with the first one numbered after it.
+			last := orderedEpisode{SeriesEpisode: models.SeriesEpisode{SeasonNumber: lastSeason, EpisodeNumber: lastEpisode}}
+			for i, ep := range episodes {
+				if byNumber(last, ep) {
+					next = i
+					break
+				}
+			}
+		}
+	}
+	if next < 0 || next >= len(episodes) {
+		return nil
+	}
+
+	ep := episodes[next]
+	resolved := &models.ResolvedNextEpisode{
+		Episode:   ep.SeriesEpisode,
+		NewSeason: lastEpisode > 0 && ep.SeasonNumber != lastSeason,
+	}
+	if !ep.airAt.IsZero() {
+		resolved.AirsAt = ep.airAt.UTC().Format(time.RFC3339)
+		resolved.Available = !ep.airAt.After(now)
+		if !resolved.Available {
+			resolved.CountdownSeconds = int64(ep.airAt.Sub(now) / time.Second)
+		}
+	}
+	return resolved
+}
+
+// isDailySeries reports whether episodes air on consecutive days, judged by
+// the median gap between distinct air dates. Same-day drops (whole seasons
+// released at once) do not count as daily.
+func isDailySeries(episodes []orderedEpisode) bool {
+	dates := make([]time.Time, 0, len(episodes))
+	for _, ep := range episodes {
+		if !ep.airAt.IsZero() {
+			dates = append(dates, ep.airAt)
+		}
+	}
+	if len(dates) < dailySeriesMinEpisodes {
+		return false
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+
+	var gaps []time.Duration
+	for i := 1; i < len(dates); i++ {
+		if gap := dates[i].Sub(dates[i-1]); gap > 0 {
+			gaps = append(gaps, gap)
+		}
+	}
+	if len(gaps) < len(dates)/2 {
+		return false
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+	return gaps[len(gaps)/2] <= dailySeriesMaxGap
+}
@@ -0,0 +1,91 @@
+package metadata
+
+import (
+	"testing"
+	"time"
+
+	"novastream/models"
+)
+
+func TestResolveNextEpisode(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	details := &models.SeriesDetails{
+		Seasons: []models.SeriesSeason{
+			{Number: 0, Episodes: []models.SeriesEpisode{
+				{SeasonNumber: 0, EpisodeNumber: 1, AiredDate: "2025-12-25"},
+				{SeasonNumber: 0, EpisodeNumber: 2, AiredDate: "2026-01-01"},
+			}},
+			{Number: 1, Episodes: []models.SeriesEpisode{
+				{SeasonNumber: 1, EpisodeNumber: 1, AiredDate: "2025-01-01"},
+				{SeasonNumber: 1, EpisodeNumber: 2, AiredDate: "2025-01-08"},
+			}},
+			// Season 2 is missing from the metadata.
+			{Number: 3, Episodes: []models.SeriesEpisode{
+				{SeasonNumber: 3, EpisodeNumber: 1, AiredDate: "2026-03-01"},
+				{SeasonNumber: 3, EpisodeNumber: 2, AiredDateTimeUTC: "2026-03-17T01:00:00Z"},
+			}},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		season, ep    int
+		wantSeason    int
+		wantEpisode   int
+		wantAvailable bool
+		wantNewSeason bool
+		wantNil       bool
+	}{
+		{name: "not started", season: 0, ep: 0, wantSeason: 1, wantEpisode: 1, wantAvailable: true},
+		{name: "same season", season: 1, ep: 1, wantSeason: 1, wantEpisode: 2, wantAvailable: true},
+		{name: "skips season gap", season: 1, ep: 2, wantSeason: 3, wantEpisode: 1, wantAvailable: true, wantNewSeason: true},
+		{name: "upcoming", season: 3, ep: 1, wantSeason: 3, wantEpisode: 2},
+		{name: "specials stay specials", season: 0, ep: 1, wantSeason: 0, wantEpisode: 2, wantAvailable: true},
+		{name: "last special", season: 0, ep: 2, wantNil: true},
+		{name: "finale", season: 3, ep: 2, wantNil: true},
+		{name: "unknown episode continues after it", season: 2, ep: 5, wantSeason: 3, wantEpisode: 1, wantAvailable: true, wantNewSeason: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolveNextEpisode(details, tt.season, tt.ep, now)
+			if tt.wantNil {
+				if got != nil {
+					t.Fatalf("expected no next episode, got %+v", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("expected a next episode")
+			}
+			if got.Episode.SeasonNumber != tt.wantSeason || got.Episode.EpisodeNumber != tt.wantEpisode {
+				t.Fatalf("next = S%02dE%02d, want S%02dE%02d", got.Episode.SeasonNumber, got.Episode.EpisodeNumber, tt.wantSeason, tt.wantEpisode)
+			}
+			if got.Available != tt.wantAvailable || got.NewSeason != tt.wantNewSeason {
+				t.Fatalf("available=%v newSeason=%v, want %v/%v", got.Available, got.NewSeason, tt.wantAvailable, tt.wantNewSeason)
+			}
+			if !got.Available && got.CountdownSeconds <= 0 {
+				t.Fatalf("expected a countdown for an upcoming episode, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestResolveNextEpisodeDailyShowFollowsAirDates(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	start := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	var episodes []models.SeriesEpisode
+	// Numbered in reverse broadcast order, as some daily shows are listed.
+	for i := 0; i < 12; i++ {
+		episodes = append(episodes, models.SeriesEpisode{
+			SeasonNumber:  2026,
+			EpisodeNumber: 100 - i,
+			AiredDate:     start.AddDate(0, 0, i).Format("2006-01-02"),
+		})
+	}
+	details := &models.SeriesDetails{Seasons: []models.SeriesSeason{{Number: 2026, Episodes: episodes}}}
+
+	got := resolveNextEpisode(details, 2026, 100, now)
+	if got == nil || got.Episode.EpisodeNumber != 99 {
+		t.Fatalf("expected the episode aired the next day (E99), got %+v", got)
+	}
+}
@@ -0,0 +1,42 @@
+package metadata
+
+import (
+	"log"
+	"sync/atomic"
+)
+
+// offlineMode freezes the metadata caches and cuts off every upstream API.
+// Like the rate limiters it is package level, so the clients rebuilt for
+// each language clone and key reload see the same switch.
+var offlineMode atomic.Bool
+
+// SetOffline turns offline mode on or off. While offline, responses are
+// served from the caches only: cached entries don't expire and aren't
+// replaced, cache misses fail fast with ErrOffline instead of reaching TMDB,
+// TVDB, MDBList and the other providers, and the background cache manager and
+// workers pause. Meant for metered connections and provider outages.
+func (s *Service) SetOffline(offline bool) {
+	if offlineMode.Swap(offline) == offline {
+		return
+	}
+	if offline {
+		log.Println("[metadata] offline mode enabled: serving from cache only, upstream requests and background refreshes paused")
+	} else {
+		log.Println("[metadata] offline mode disabled: upstream requests resumed")
+	}
+}
+
+// Offline reports whether offline mode is on.
+func (s *Service) Offline() bool {
+	return offlineMode.Load()
+}
+
+// offlineError returns the error a request to provider fails with while
+// offline mode is on, or nil when upstream requests are allowed. Callers
+// check it before their first attempt so retry loops don't back off on it.
+func offlineError(provider string) error {
+	if !offlineMode.Load() {
+		return nil
+	}
+	return newKindError(ErrOffline, "%s request skipped: offline mode is on and the data is not cached", provider)
+}
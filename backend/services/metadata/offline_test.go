@@ -0,0 +1,80 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func setOfflineForTest(t *testing.T, svc *Service) {
+	t.Helper()
+	svc.SetOffline(true)
+	t.Cleanup(func() { svc.SetOffline(false) })
+}
+
+func TestOfflineModeFreezesCache(t *testing.T) {
+	dir := t.TempDir()
+	cache := newFileCache(dir, 24)
+	svc := &Service{cache: cache}
+	if err := cache.set("stale", "old value"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "stale.json"), past, past); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	setOfflineForTest(t, svc)
+	var value string
+	if ok, _ := cache.getWithMaxAge("stale", &value, time.Minute); !ok || value != "old value" {
+		t.Fatalf("expected the expired entry to be served offline, got %v %q", ok, value)
+	}
+	if err := cache.set("fresh", "new value"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if ok, _ := cache.get("fresh", &value); ok {
+		t.Fatal("expected writes to be dropped while offline")
+	}
+
+	svc.SetOffline(false)
+	if ok, _ := cache.getWithMaxAge("stale", &value, time.Minute); ok {
+		t.Fatal("expected the entry to expire once back online")
+	}
+}
+
+func TestOfflineModeBlocksUpstreamRequests(t *testing.T) {
+	requests := 0
+	httpc := &http.Client{Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return nil, errors.New("unexpected request")
+	})}
+	svc := &Service{
+		tmdb:  newTMDBClient("tmdb-key", "en-US", httpc, nil),
+		cache: newFileCache(t.TempDir(), 24),
+	}
+	setOfflineForTest(t, svc)
+
+	start := time.Now()
+	if _, err := svc.cachedFetchTMDBImageList(context.Background(), "movie", 603); !errors.Is(err, ErrOffline) {
+		t.Fatalf("expected ErrOffline, got %v", err)
+	}
+	if _, err := svc.tmdb.findMovieByIMDBID(context.Background(), "tt0133093"); !errors.Is(err, ErrOffline) {
+		t.Fatalf("expected ErrOffline, got %v", err)
+	}
+	if _, err := svc.fetchCustomListItems(context.Background(), "https://mdblist.com/lists/user/list"); !errors.Is(err, ErrOffline) {
+		t.Fatalf("expected ErrOffline for custom lists, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected offline misses to fail fast, took %s", elapsed)
+	}
+	if requests != 0 {
+		t.Fatalf("expected no upstream requests, got %d", requests)
+	}
+	if status := svc.GetCacheManagerStatus(); !status.Offline {
+		t.Fatal("expected the cache manager status to report offline mode")
+	}
+}
@@ -0,0 +1,295 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"novastream/models"
+)
+
+// metadataProviderTimeout bounds each call to a registered provider, so a
+// slow community provider can't hold up search or details.
+const metadataProviderTimeout = 10 * time.Second
+
+// reservedProviderNames are the ID prefixes and sources the core service
+// already uses; providers can't register under them.
+var reservedProviderNames = map[string]bool{
+	"tvdb": true, "tmdb": true, "imdb": true, "mdblist": true, "trakt": true,
+	"anilist": true, "fanart": true, "local": true, "name": true,
+}
+
+// MetadataProvider is a community metadata source, such as Douban or
+// Kinopoisk, plugged into search and details without changes to the core
+// service. Titles a provider returns have IDs of the form
+// "<name>:<mediaType>:<id>"; the id part is what the provider receives back
+// in Details, Artwork and ExternalIDs. mediaType is "movie" or "series".
+type MetadataProvider interface {
+	// Name identifies the provider in title IDs, e.g. "douban".
+	Name() string
+	// Search returns the provider's titles matching query.
+	Search(ctx context.Context, query, mediaType string) ([]models.Title, error)
+	// Details returns the provider's full record for a title.
+	Details(ctx context.Context, mediaType, id string) (*models.Title, error)
+	// Artwork returns the title's posters, backdrops and logos, best first.
+	Artwork(ctx context.Context, mediaType, id string) ([]models.Image, error)
+	// ExternalIDs maps the title to IMDB, TMDB and TVDB IDs where known.
+	ExternalIDs(ctx context.Context, mediaType, id string) (ProviderIDs, error)
+}
+
+// ProviderIDs are the core IDs a provider title maps to. A title with any of
+// them is served by the core TVDB/TMDB pipeline.
+type ProviderIDs struct {
+	IMDBID string
+	TMDBID int64
+	TVDBID int64
+}
+
+func (ids ProviderIDs) empty() bool {
+	return strings.TrimSpace(ids.IMDBID) == "" && ids.TMDBID <= 0 && ids.TVDBID <= 0
+}
+
+var (
+	providerModulesMu sync.Mutex
+	providerModules   []func() MetadataProvider
+)
+
+// RegisterProviderModule adds a compile-time provider module. Modules call
+// it from an init function and are enabled by importing their package; every
+// Service created afterwards registers an instance from factory.
+func RegisterProviderModule(factory func() MetadataProvider) {
+	if factory == nil {
+		return
+	}
+	providerModulesMu.Lock()
+	defer providerModulesMu.Unlock()
+	providerModules = append(providerModules, factory)
+}
+
+// registerProviderModules registers an instance of every provider module.
+func (s *Service) registerProviderModules() {
+	providerModulesMu.Lock()
+	factories := append([]func() MetadataProvider(nil), providerModules...)
+	providerModulesMu.Unlock()
+	for _, factory := range factories {
+		if err := s.RegisterMetadataProvider(factory()); err != nil {
+			log.Printf("[metadata] provider module skipped: %v", err)
+		}
+	}
+}
+
+// metadataProviderRegistry is shared between a Service and its WithLanguage
+// clones.
+type metadataProviderRegistry struct {
+	mu        sync.RWMutex
+	providers map[string]MetadataProvider
+	external  []string // names registered by SetExternalProviders
+}
+
+func newMetadataProviderRegistry() *metadataProviderRegistry {
+	return &metadataProviderRegistry{providers: make(map[string]MetadataProvider)}
+}
+
+// RegisterMetadataProvider adds a provider to search and details. Names must
+// be a single word that isn't already used by a built-in source; registering
+// a provider with an existing name replaces it.
+func (s *Service) RegisterMetadataProvider(p MetadataProvider) error {
+	if p == nil || s.metadataProviders == nil {
+		return nil
+	}
+	name := strings.ToLower(strings.TrimSpace(p.Name()))
+	if name == "" || strings.ContainsAny(name, ": /") {
+		return fmt.Errorf("invalid metadata provider name %q", p.Name())
+	}
+	if reservedProviderNames[name] {
+		return fmt.Errorf("metadata provider name %q is reserved", name)
+	}
+	s.metadataProviders.mu.Lock()
+	s.metadataProviders.providers[name] = p
+	s.metadataProviders.mu.Unlock()
+	log.Printf("[metadata] registered metadata provider %q", name)
+	return nil
+}
+
+// UnregisterMetadataProvider removes a provider by name.
+func (s *Service) UnregisterMetadataProvider(name string) {
+	if s.metadataProviders == nil {
+		return
+	}
+	s.metadataProviders.mu.Lock()
+	delete(s.metadataProviders.providers, strings.ToLower(strings.TrimSpace(name)))
+	s.metadataProviders.mu.Unlock()
+}
+
+// MetadataProviders lists the names of the registered providers.
+func (s *Service) MetadataProviders() []string {
+	if s.metadataProviders == nil {
+		return nil
+	}
+	s.metadataProviders.mu.RLock()
+	defer s.metadataProviders.mu.RUnlock()
+	names := make([]string, 0, len(s.metadataProviders.providers))
+	for name := range s.metadataProviders.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *Service) metadataProvider(name string) MetadataProvider {
+	if s.metadataProviders == nil {
+		return nil
+	}
+	s.metadataProviders.mu.RLock()
+	defer s.metadataProviders.mu.RUnlock()
+	return s.metadataProviders.providers[name]
+}
+
+// parseProviderTitleID splits a provider title ID into the provider, media
+// type and provider-side ID. ok is false for IDs of unregistered providers.
+func (s *Service) parseProviderTitleID(titleID string) (provider MetadataProvider, mediaType, id string, ok bool) {
+	parts := strings.SplitN(strings.TrimSpace(titleID), ":", 3)
+	if len(parts) != 3 || parts[2] == "" {
+		return nil, "", "", false
+	}
+	provider = s.metadataProvider(strings.ToLower(parts[0]))
+	if provider == nil {
+		return nil, "", "", false
+	}
+	mediaType = "series"
+	if parts[1] == "movie" {
+		mediaType = "movie"
+	}
+	return provider, mediaType, parts[2], true
+}
+
+// searchMetadataProviders searches every registered provider in parallel.
+// Provider errors are logged and skipped.
+func (s *Service) searchMetadataProviders(ctx context.Context, query, mediaType string) []models.SearchResult {
+	if s.metadataProviders == nil {
+		return nil
+	}
+	s.metadataProviders.mu.RLock()
+	providers := make(map[string]MetadataProvider, len(s.metadataProviders.providers))
+	for name, p := range s.metadataProviders.providers {
+		providers[name] = p
+	}
+	s.metadataProviders.mu.RUnlock()
+	if len(providers) == 0 {
+		return nil
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results []models.SearchResult
+	)
+	for name, p := range providers {
+		wg.Add(1)
+		go func(name string, p MetadataProvider) {
+			defer wg.Done()
+			callCtx, cancel := context.WithTimeout(ctx, metadataProviderTimeout)
+			defer cancel()
+			titles, err := p.Search(callCtx, query, mediaType)
+			if err != nil {
+				log.Printf("[metadata] provider %q search failed query=%q type=%s err=%v", name, query, mediaType, err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			for _, title := range titles {
+				if title = normalizeProviderTitle(name, mediaType, title); title.Name != "" {
+					results = append(results, models.SearchResult{Title: title})
+				}
+			}
+		}(name, p)
+	}
+	wg.Wait()
+	return results
+}
+
+// normalizeProviderTitle namespaces a provider title's ID and media type.
+// Provider IDs given without the "<name>:<mediaType>:" prefix get it added.
+func normalizeProviderTitle(name, mediaType string, title models.Title) models.Title {
+	title.Name = strings.TrimSpace(title.Name)
+	if title.MediaType != "movie" && title.MediaType != "series" {
+		title.MediaType = mediaType
+	}
+	id := strings.TrimSpace(title.ID)
+	prefix := name + ":" + title.MediaType + ":"
+	if id == "" {
+		title.Name = ""
+		return title
+	}
+	if !strings.HasPrefix(id, prefix) {
+		id = prefix + id
+	}
+	title.ID = id
+	return title
+}
+
+// providerDetails resolves a title ID from a registered provider. When the
+// provider maps the title to core IDs, they are returned for the core
+// pipeline to serve; otherwise the provider's own details are returned, with
+// artwork filled in from its Artwork. ok is false for other title IDs.
+func (s *Service) providerDetails(ctx context.Context, titleID string) (ids ProviderIDs, title *models.Title, ok bool, err error) {
+	provider, mediaType, id, ok := s.parseProviderTitleID(titleID)
+	if !ok {
+		return ProviderIDs{}, nil, false, nil
+	}
+	callCtx, cancel := context.WithTimeout(ctx, metadataProviderTimeout)
+	defer cancel()
+
+	if ids, err := provider.ExternalIDs(callCtx, mediaType, id); err == nil && !ids.empty() {
+		return ids, nil, true, nil
+	}
+	title, err = provider.Details(callCtx, mediaType, id)
+	if err != nil {
+		return ProviderIDs{}, nil, true, fmt.Errorf("%s details: %w", provider.Name(), err)
+	}
+	if title == nil {
+		return ProviderIDs{}, nil, true, newKindError(ErrNotFound, "%s title not found", provider.Name())
+	}
+	normalized := normalizeProviderTitle(strings.ToLower(provider.Name()), mediaType, *title)
+	normalized.ID = strings.TrimSpace(titleID)
+	if normalized.Poster == nil || normalized.Backdrop == nil {
+		if images, err := provider.Artwork(callCtx, mediaType, id); err == nil {
+			applyProviderArtwork(&normalized, images)
+		}
+	}
+	return ProviderIDs{}, &normalized, true, nil
+}
+
+// providerSeriesQuery swaps a provider title ID for the core IDs it maps to.
+func providerSeriesQuery(req models.SeriesDetailsQuery, ids ProviderIDs) models.SeriesDetailsQuery {
+	return models.SeriesDetailsQuery{Name: req.Name, Year: req.Year, TVDBID: ids.TVDBID, TMDBID: ids.TMDBID, IMDBID: ids.IMDBID}
+}
+
+// applyProviderArtwork fills a title's missing poster, backdrop and logo
+// from a provider's artwork, taking the first of each type.
+func applyProviderArtwork(title *models.Title, images []models.Image) {
+	for _, img := range images {
+		if strings.TrimSpace(img.URL) == "" {
+			continue
+		}
+		img := img
+		switch img.Type {
+		case "poster":
+			if title.Poster == nil {
+				title.Poster = &img
+			}
+		case "backdrop":
+			if title.Backdrop == nil {
+				title.Backdrop = &img
+			}
+		case "logo":
+			if title.Logo == nil {
+				title.Logo = &img
+			}
+		}
+	}
+}
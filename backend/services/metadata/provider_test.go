@@ -0,0 +1,86 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"novastream/models"
+)
+
+type stubMetadataProvider struct {
+	name string
+	ids  ProviderIDs
+}
+
+func (p stubMetadataProvider) Name() string { return p.name }
+
+func (p stubMetadataProvider) Search(_ context.Context, query, mediaType string) ([]models.Title, error) {
+	if query == "fail" {
+		return nil, errors.New("unavailable")
+	}
+	return []models.Title{{ID: "1291843", Name: "Let the Bullets Fly", Year: 2010}, {Name: "no id"}}, nil
+}
+
+func (p stubMetadataProvider) Details(_ context.Context, mediaType, id string) (*models.Title, error) {
+	return &models.Title{ID: id, Name: "Let the Bullets Fly", MediaType: mediaType}, nil
+}
+
+func (p stubMetadataProvider) Artwork(context.Context, string, string) ([]models.Image, error) {
+	return []models.Image{{URL: "https://img.test/poster.jpg", Type: "poster"}, {URL: "https://img.test/other.jpg", Type: "poster"}}, nil
+}
+
+func (p stubMetadataProvider) ExternalIDs(context.Context, string, string) (ProviderIDs, error) {
+	return p.ids, nil
+}
+
+func TestRegisterMetadataProvider(t *testing.T) {
+	svc := &Service{metadataProviders: newMetadataProviderRegistry()}
+	if err := svc.RegisterMetadataProvider(stubMetadataProvider{name: "Douban"}); err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if err := svc.RegisterMetadataProvider(stubMetadataProvider{name: "tmdb"}); err == nil {
+		t.Fatal("expected a built-in name to be refused")
+	}
+	if err := svc.RegisterMetadataProvider(stubMetadataProvider{name: "a:b"}); err == nil {
+		t.Fatal("expected a name with a colon to be refused")
+	}
+	if got := svc.MetadataProviders(); !reflect.DeepEqual(got, []string{"douban"}) {
+		t.Fatalf("MetadataProviders() = %v", got)
+	}
+	svc.UnregisterMetadataProvider("douban")
+	if got := svc.MetadataProviders(); len(got) != 0 {
+		t.Fatalf("expected no providers, got %v", got)
+	}
+}
+
+func TestMetadataProviderSearchAndDetails(t *testing.T) {
+	svc := &Service{metadataProviders: newMetadataProviderRegistry()}
+	_ = svc.RegisterMetadataProvider(stubMetadataProvider{name: "douban"})
+
+	results := svc.searchMetadataProviders(context.Background(), "bullets", "movie")
+	if len(results) != 1 || results[0].Title.ID != "douban:movie:1291843" || results[0].Title.MediaType != "movie" {
+		t.Fatalf("unexpected provider results %+v", results)
+	}
+	if results := svc.searchMetadataProviders(context.Background(), "fail", "movie"); len(results) != 0 {
+		t.Fatalf("expected a failing provider to be skipped, got %+v", results)
+	}
+
+	title, err := svc.MovieDetails(context.Background(), models.MovieDetailsQuery{TitleID: "douban:movie:1291843"})
+	if err != nil {
+		t.Fatalf("MovieDetails: %v", err)
+	}
+	if title.ID != "douban:movie:1291843" || title.Poster == nil || title.Poster.URL != "https://img.test/poster.jpg" {
+		t.Fatalf("unexpected provider details %+v", title)
+	}
+
+	if _, _, ok, _ := svc.providerDetails(context.Background(), "tmdb:movie:603"); ok {
+		t.Fatal("expected core IDs to be left to the core pipeline")
+	}
+	_ = svc.RegisterMetadataProvider(stubMetadataProvider{name: "douban", ids: ProviderIDs{TMDBID: 38365}})
+	ids, title, ok, err := svc.providerDetails(context.Background(), "douban:movie:1291843")
+	if !ok || err != nil || title != nil || ids.TMDBID != 38365 {
+		t.Fatalf("expected mapped IDs, got ids=%+v title=%+v ok=%v err=%v", ids, title, ok, err)
+	}
+}
@@ -0,0 +1,236 @@
+package metadata
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Built-in limits, used when the settings leave a value at zero.
+const (
+	defaultTMDBQPS    = 200 // TMDB is generous; retries back off on 429/5xx.
+	defaultTVDBQPS    = 100
+	defaultMDBListQPS = 0.9 // MDBList free tier: ~1 req/sec
+
+	defaultEnrichLimit = 200 // cap TMDB API calls per batch; remaining items enriched progressively
+)
+
+// Shared per-provider limiters. Clients are rebuilt for every language clone
+// and API key reload, so the limiters live at package level to keep a single
+// budget per upstream API.
+var (
+	tmdbLimiter    = newRateLimiter("tmdb", defaultTMDBQPS, 0)
+	tvdbLimiter    = newRateLimiter("tvdb", defaultTVDBQPS, 0)
+	mdblistLimiter = newRateLimiter("mdblist", defaultMDBListQPS, 0)
+)
+
+// ProviderRateLimit configures one upstream API. Zero values select the
+// built-in defaults; a Concurrency of zero means unlimited.
+type ProviderRateLimit struct {
+	QPS         float64
+	Concurrency int
+}
+
+// RateLimitConfig configures upstream request limits and enrichment fan-out.
+type RateLimitConfig struct {
+	TMDB    ProviderRateLimit
+	TVDB    ProviderRateLimit
+	MDBList ProviderRateLimit
+	// EnrichConcurrency overrides the per-batch worker counts used by list
+	// enrichment. Zero keeps each call site's default.
+	EnrichConcurrency int
+	// EnrichLimit caps TMDB lookups per trending enrichment batch.
+	EnrichLimit int
+}
+
+// RateLimitStats reports a provider limiter's settings and activity.
+type RateLimitStats struct {
+	QPS         float64 `json:"qps"`
+	Concurrency int     `json:"concurrency"` // 0 = unlimited
+	InFlight    int32   `json:"inFlight"`
+	Requests    int64   `json:"requests"`
+	Throttled   int64   `json:"throttled"` // upstream 429 responses
+}
+
+// rateLimiter spaces requests to a target QPS and optionally bounds how many
+// are in flight at once. A nil *rateLimiter does no limiting.
+type rateLimiter struct {
+	name string
+
+	mu          sync.Mutex
+	qps         float64
+	interval    time.Duration
+	next        time.Time
+	concurrency int
+	slots       chan struct{}
+
+	inFlight  atomic.Int32
+	requests  atomic.Int64
+	throttled atomic.Int64
+}
+
+func newRateLimiter(name string, qps float64, concurrency int) *rateLimiter {
+	l := &rateLimiter{name: name}
+	l.setLimits(qps, concurrency)
+	return l
+}
+
+// setLimits updates the limiter. Requests already holding a concurrency slot
+// release it to the previous pool, so changes take effect for new requests.
+func (l *rateLimiter) setLimits(qps float64, concurrency int) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.qps = qps
+	l.interval = 0
+	if qps > 0 {
+		l.interval = time.Duration(float64(time.Second) / qps)
+	}
+	if concurrency < 0 {
+		concurrency = 0
+	}
+	if concurrency != l.concurrency || (concurrency > 0 && l.slots == nil) {
+		l.concurrency = concurrency
+		l.slots = nil
+		if concurrency > 0 {
+			l.slots = make(chan struct{}, concurrency)
+		}
+	}
+}
+
+// acquire blocks until the request may start and returns a function that
+// frees its concurrency slot.
+func (l *rateLimiter) acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	l.mu.Lock()
+	slots := l.slots
+	l.mu.Unlock()
+	if slots != nil {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	// Reserve the next send slot under the lock, then sleep outside it so
+	// other goroutines can queue behind us.
+	l.mu.Lock()
+	now := time.Now()
+	wait := l.next.Sub(now)
+	if wait < 0 {
+		wait = 0
+	}
+	l.next = now.Add(wait + l.interval)
+	l.mu.Unlock()
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+
+	l.inFlight.Add(1)
+	l.requests.Add(1)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.inFlight.Add(-1)
+			if slots != nil {
+				<-slots
+			}
+		})
+	}, nil
+}
+
+// do sends req once the limiter allows it. The concurrency slot is held until
+// the response body is closed. Nothing is sent in offline mode.
+func (l *rateLimiter) do(httpc *http.Client, req *http.Request) (*http.Response, error) {
+	if err := offlineError(req.URL.Host); err != nil {
+		return nil, err
+	}
+	release, err := l.acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpc.Do(req)
+	if err != nil {
+		release()
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusTooManyRequests && l != nil {
+		l.throttled.Add(1)
+	}
+	resp.Body = &limitedBody{ReadCloser: resp.Body, release: release}
+	return resp, nil
+}
+
+func (l *rateLimiter) stats() RateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return RateLimitStats{
+		QPS:         l.qps,
+		Concurrency: l.concurrency,
+		InFlight:    l.inFlight.Load(),
+		Requests:    l.requests.Load(),
+		Throttled:   l.throttled.Load(),
+	}
+}
+
+type limitedBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *limitedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
+
+// SetRateLimits applies settings-driven upstream limits and enrichment
+// fan-out. Zero values restore the built-in defaults.
+func (s *Service) SetRateLimits(cfg RateLimitConfig) {
+	tmdbLimiter.setLimits(rateLimitQPS(cfg.TMDB.QPS, defaultTMDBQPS), cfg.TMDB.Concurrency)
+	tvdbLimiter.setLimits(rateLimitQPS(cfg.TVDB.QPS, defaultTVDBQPS), cfg.TVDB.Concurrency)
+	mdblistLimiter.setLimits(rateLimitQPS(cfg.MDBList.QPS, defaultMDBListQPS), cfg.MDBList.Concurrency)
+	s.enrichConcurrency.Store(int32(max(cfg.EnrichConcurrency, 0)))
+	s.enrichLimit.Store(int32(max(cfg.EnrichLimit, 0)))
+}
+
+// RateLimitStats returns the current state of each upstream limiter.
+func (s *Service) RateLimitStats() map[string]RateLimitStats {
+	return map[string]RateLimitStats{
+		tmdbLimiter.name:    tmdbLimiter.stats(),
+		tvdbLimiter.name:    tvdbLimiter.stats(),
+		mdblistLimiter.name: mdblistLimiter.stats(),
+	}
+}
+
+func rateLimitQPS(qps, fallback float64) float64 {
+	if qps > 0 {
+		return qps
+	}
+	return fallback
+}
+
+// enrichWorkers returns the configured enrichment concurrency, or def when
+// the settings leave it unset.
+func (s *Service) enrichWorkers(def int) int {
+	if n := int(s.enrichConcurrency.Load()); n > 0 {
+		return n
+	}
+	return def
+}
+
+// enrichBatchLimit returns the per-batch TMDB lookup cap for trending enrichment.
+func (s *Service) enrichBatchLimit() int {
+	if n := int(s.enrichLimit.Load()); n > 0 {
+		return n
+	}
+	return defaultEnrichLimit
+}
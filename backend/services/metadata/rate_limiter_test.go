@@ -0,0 +1,97 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBoundsConcurrency(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		inFlight    int
+		maxInFlight int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(20 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		if r.URL.Path == "/limited" {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+
+	limiter := newRateLimiter("test", 0, 2)
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			resp, err := limiter.do(server.Client(), req)
+			if err != nil {
+				t.Errorf("do: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 2 {
+		t.Fatalf("expected at most 2 concurrent requests, saw %d", maxInFlight)
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/limited", nil)
+	resp, err := limiter.do(server.Client(), req)
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	resp.Body.Close()
+
+	stats := limiter.stats()
+	if stats.Requests != 7 || stats.Throttled != 1 || stats.InFlight != 0 || stats.Concurrency != 2 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestRateLimiterSpacesRequests(t *testing.T) {
+	limiter := newRateLimiter("test", 50, 0) // 20ms apart
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		release, err := limiter.acquire(context.Background())
+		if err != nil {
+			t.Fatalf("acquire: %v", err)
+		}
+		release()
+	}
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Fatalf("expected requests to be spaced ~20ms apart, took %v", elapsed)
+	}
+}
+
+func TestRateLimiterAcquireHonorsContext(t *testing.T) {
+	limiter := newRateLimiter("test", 0, 1)
+	release, err := limiter.acquire(context.Background())
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := limiter.acquire(ctx); err == nil {
+		t.Fatal("expected acquire to fail once the context is done")
+	}
+}
@@ -0,0 +1,79 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RawProviderPayloads holds the unparsed TVDB and TMDB responses behind a
+// title's details, for extension developers and bug reports. Payloads are
+// fetched fresh rather than read from the cache, which only keeps the parsed
+// fields.
+type RawProviderPayloads struct {
+	FetchedAt time.Time                  `json:"fetchedAt"`
+	Requests  map[string]string          `json:"requests"` // provider -> request URL, API keys removed
+	Payloads  map[string]json.RawMessage `json:"payloads"` // provider -> response body
+	Errors    map[string]string          `json:"errors,omitempty"`
+}
+
+// RawProviderPayloads fetches the TVDB extended record and TMDB details
+// record that the details path assembles a title from. A provider is skipped
+// when its ID is zero or its client is not configured; failures are reported
+// per provider so one outage doesn't hide the other payload.
+func (s *Service) RawProviderPayloads(ctx context.Context, mediaType string, tvdbID, tmdbID int64) *RawProviderPayloads {
+	movie := strings.EqualFold(strings.TrimSpace(mediaType), "movie")
+	raw := &RawProviderPayloads{
+		FetchedAt: time.Now().UTC(),
+		Requests:  make(map[string]string),
+		Payloads:  make(map[string]json.RawMessage),
+	}
+	fail := func(provider string, err error) {
+		if raw.Errors == nil {
+			raw.Errors = make(map[string]string)
+		}
+		raw.Errors[provider] = err.Error()
+	}
+
+	if tvdbID > 0 && s.client != nil {
+		// Same meta the details path requests, so episodes and artwork are included.
+		endpoint, meta := fmt.Sprintf("https://api4.thetvdb.com/v4/series/%d/extended", tvdbID), "episodes,seasons,artworks"
+		if movie {
+			endpoint, meta = fmt.Sprintf("https://api4.thetvdb.com/v4/movies/%d/extended", tvdbID), "artwork"
+		}
+		params := url.Values{"meta": {meta}}
+		raw.Requests["tvdb"] = endpoint + "?" + params.Encode()
+		var payload json.RawMessage
+		if err := s.client.doGET(endpoint, params, &payload); err != nil {
+			fail("tvdb", err)
+		} else {
+			raw.Payloads["tvdb"] = payload
+		}
+	}
+
+	if tmdbID > 0 && s.tmdb != nil && s.tmdb.isConfigured() {
+		params := url.Values{}
+		endpoint := fmt.Sprintf("%s/tv/%d", tmdbBaseURL, tmdbID)
+		if movie {
+			endpoint = fmt.Sprintf("%s/movie/%d", tmdbBaseURL, tmdbID)
+			params.Set("language", "en-US")
+		} else {
+			params.Set("append_to_response", "external_ids")
+		}
+		if lang := strings.TrimSpace(s.tmdb.language); lang != "" {
+			params.Set("language", normalizeLanguage(lang))
+		}
+		raw.Requests["tmdb"] = endpoint + "?" + params.Encode()
+		params.Set("api_key", s.tmdb.apiKey)
+		var payload json.RawMessage
+		if err := s.tmdb.doGET(ctx, endpoint+"?"+params.Encode(), &payload); err != nil {
+			fail("tmdb", err)
+		} else {
+			raw.Payloads["tmdb"] = payload
+		}
+	}
+	return raw
+}
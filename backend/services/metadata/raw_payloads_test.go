@@ -0,0 +1,48 @@
+package metadata
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRawProviderPayloads(t *testing.T) {
+	httpc := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body := ""
+			switch {
+			case req.URL.Path == "/v4/login":
+				body = `{"data":{"token":"tvdb-token"}}`
+			case req.URL.Path == "/v4/series/81189/extended":
+				if got := req.URL.Query().Get("meta"); got != "episodes,seasons,artworks" {
+					t.Fatalf("unexpected tvdb meta %q", got)
+				}
+				body = `{"status":"success","data":{"id":81189,"name":"Breaking Bad","unmappedField":true}}`
+			case req.URL.Path == "/3/tv/1396":
+				return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: io.NopCloser(strings.NewReader(`{}`)), Header: make(http.Header)}, nil
+			default:
+				t.Fatalf("unhandled request: %s", req.URL.String())
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		}),
+	}
+	svc := &Service{
+		client: newTVDBClient("tvdb-key", "en", httpc, 24),
+		tmdb:   newTMDBClient("tmdb-key", "en", httpc, newFileCache(t.TempDir(), 24)),
+	}
+	svc.client.limiter = nil
+	svc.tmdb.limiter = nil
+
+	raw := svc.RawProviderPayloads(context.Background(), "series", 81189, 1396)
+	if !strings.Contains(string(raw.Payloads["tvdb"]), `"unmappedField":true`) {
+		t.Fatalf("expected the full tvdb response, got %s", raw.Payloads["tvdb"])
+	}
+	if _, ok := raw.Payloads["tmdb"]; ok || raw.Errors["tmdb"] == "" {
+		t.Fatalf("expected a tmdb error, got payloads=%v errors=%v", raw.Payloads, raw.Errors)
+	}
+	if strings.Contains(raw.Requests["tmdb"], "tmdb-key") || !strings.Contains(raw.Requests["tmdb"], "append_to_response=external_ids") {
+		t.Fatalf("unexpected tmdb request %q", raw.Requests["tmdb"])
+	}
+}
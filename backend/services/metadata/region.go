@@ -0,0 +1,60 @@
+package metadata
+
+import (
+	"strconv"
+	"strings"
+
+	"novastream/models"
+)
+
+// WithRegion returns a request-scoped metadata service that prefers region's
+// content ratings and release dates (ISO 3166-1, e.g. "GB"), falling back to
+// US data like the shared service. It shares s's caches and language.
+func (s *Service) WithRegion(region string) *Service {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if region == "" || region == s.certificationCountry() {
+		return s
+	}
+	language := ""
+	if s.client != nil {
+		language = s.client.language
+	}
+	local := s.localCopy(language)
+	local.certCountry = region
+	return local
+}
+
+// applyRegion re-picks title's certification and primary releases for this
+// service's region. Cached details keep whichever region they were built
+// for, so the per-country data cached alongside them is consulted instead
+// of refetching. Titles without cached region data are left alone.
+func (s *Service) applyRegion(title *models.Title) {
+	if title == nil || title.TMDBID <= 0 {
+		return
+	}
+	id := strconv.FormatInt(title.TMDBID, 10)
+
+	if strings.EqualFold(title.MediaType, "movie") {
+		var cached cachedReleasesWithCert
+		if ok, _ := s.cache.get(cacheKey("tmdb", "movie", "releases", "v2", id), &cached); !ok {
+			return
+		}
+		if cert, country := s.pickCertification(cached.certifications()); cert != "" {
+			title.Certification, title.CertificationCountry = cert, country
+		}
+		if len(title.Releases) > 0 {
+			// Releases may share a backing array with other copies of the title.
+			title.Releases = append([]models.Release(nil), title.Releases...)
+			s.ensureMovieReleasePointers(title)
+		}
+		return
+	}
+
+	var ratings map[string]string
+	if ok, _ := s.cache.get(cacheKey("tmdb", "tv", "content_rating", "v2", id), &ratings); !ok {
+		return
+	}
+	if cert, country := s.pickCertification(ratings); cert != "" {
+		title.Certification, title.CertificationCountry = cert, country
+	}
+}
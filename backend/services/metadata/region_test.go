@@ -0,0 +1,89 @@
+package metadata
+
+import (
+	"net/http"
+	"testing"
+
+	"novastream/models"
+)
+
+func TestEnsureMovieReleasePointersPrefersRegion(t *testing.T) {
+	releases := []models.Release{
+		{Type: "theatrical", Date: "2026-01-02", Country: "FR"},
+		{Type: "theatrical", Date: "2026-01-10", Country: "US"},
+		{Type: "theatrical", Date: "2026-02-01", Country: "GB"},
+		{Type: "digital", Date: "2026-03-01", Country: "US"},
+		{Type: "digital", Date: "2026-02-20", Country: "FR"},
+	}
+	pointers := func(country string) (string, string) {
+		svc := &Service{}
+		svc.SetCertificationCountry(country)
+		title := models.Title{Releases: append([]models.Release(nil), releases...)}
+		svc.ensureMovieReleasePointers(&title)
+		if title.Theatrical == nil || title.HomeRelease == nil {
+			t.Fatalf("%q: missing release pointers", country)
+		}
+		return title.Theatrical.Country, title.HomeRelease.Country
+	}
+
+	// No region: earliest anywhere.
+	if theatrical, home := pointers(""); theatrical != "FR" || home != "FR" {
+		t.Fatalf("no region = %s/%s, want FR/FR", theatrical, home)
+	}
+	// GB has no digital release, so home falls back to US.
+	if theatrical, home := pointers("GB"); theatrical != "GB" || home != "US" {
+		t.Fatalf("GB = %s/%s, want GB/US", theatrical, home)
+	}
+	// No JP releases at all: US for both.
+	if theatrical, home := pointers("JP"); theatrical != "US" || home != "US" {
+		t.Fatalf("JP = %s/%s, want US/US", theatrical, home)
+	}
+}
+
+func TestWithRegionReappliesCachedCertifications(t *testing.T) {
+	svc := &Service{
+		cache: newFileCache(t.TempDir(), 24),
+		tmdb:  newTMDBClient("tmdb-key", "en", &http.Client{}, nil),
+	}
+	_ = svc.cache.set(cacheKey("tmdb", "movie", "releases", "v2", "1"), cachedReleasesWithCert{
+		Releases:       []models.Release{{Type: "theatrical", Date: "2026-01-10", Country: "US"}, {Type: "theatrical", Date: "2026-02-01", Country: "GB"}},
+		Certification:  "PG-13",
+		Certifications: map[string]string{"US": "PG-13", "GB": "12A"},
+	})
+	_ = svc.cache.set(cacheKey("tmdb", "tv", "content_rating", "v2", "2"), map[string]string{"US": "TV-MA", "DE": "16"})
+
+	if svc.WithRegion(" ") != svc {
+		t.Fatal("empty region should keep the shared service")
+	}
+
+	// Details cached with the US rating and release pointers.
+	movie := models.Title{MediaType: "movie", TMDBID: 1, Certification: "PG-13", CertificationCountry: "US",
+		Releases: []models.Release{{Type: "theatrical", Date: "2026-01-10", Country: "US"}, {Type: "theatrical", Date: "2026-02-01", Country: "GB"}}}
+	svc.ensureMovieReleasePointers(&movie)
+	cachedReleases := movie.Releases
+
+	gb := svc.WithRegion("gb")
+	gb.applyRegion(&movie)
+	if movie.Certification != "12A" || movie.CertificationCountry != "GB" {
+		t.Fatalf("movie certification = %q/%q, want 12A/GB", movie.Certification, movie.CertificationCountry)
+	}
+	if movie.Theatrical == nil || movie.Theatrical.Country != "GB" {
+		t.Fatalf("theatrical = %+v, want GB release", movie.Theatrical)
+	}
+	if !cachedReleases[0].Primary || cachedReleases[1].Primary {
+		t.Fatal("applyRegion modified the original releases")
+	}
+
+	series := models.Title{MediaType: "series", TMDBID: 2, Certification: "TV-MA", CertificationCountry: "US"}
+	svc.WithRegion("DE").applyRegion(&series)
+	if series.Certification != "16" || series.CertificationCountry != "DE" {
+		t.Fatalf("series certification = %q/%q, want 16/DE", series.Certification, series.CertificationCountry)
+	}
+
+	// Countries without a rating keep the US one.
+	series = models.Title{MediaType: "series", TMDBID: 2}
+	svc.WithRegion("JP").applyRegion(&series)
+	if series.Certification != "TV-MA" || series.CertificationCountry != "US" {
+		t.Fatalf("series certification = %q/%q, want US fallback", series.Certification, series.CertificationCountry)
+	}
+}
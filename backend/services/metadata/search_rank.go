@@ -0,0 +1,302 @@
+package metadata
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"novastream/models"
+)
+
+const (
+	// searchFuzzyFallbackSimilarity is the best name similarity below which a
+	// remote search is treated as having missed, and typo-tolerant matches
+	// from the local index are added.
+	searchFuzzyFallbackSimilarity = 0.8
+	// searchFuzzyMinSimilarity is the lowest similarity a typo-tolerant
+	// match from the local index needs to be returned.
+	searchFuzzyMinSimilarity = 0.6
+)
+
+// searchQuery is a search string split into name tokens, with the year it
+// ends in, if any ("dune 2021").
+type searchQuery struct {
+	tokens []string
+	name   []string // tokens without the year
+	year   int
+}
+
+func parseSearchQuery(query string) searchQuery {
+	q := searchQuery{tokens: localSearchTokens(query)}
+	q.name = q.tokens
+	if n := len(q.tokens); n > 1 {
+		if year, err := strconv.Atoi(q.tokens[n-1]); err == nil && len(q.tokens[n-1]) == 4 && year >= 1900 && year <= time.Now().Year()+2 {
+			q.year = year
+			q.name = q.tokens[:n-1]
+		}
+	}
+	return q
+}
+
+// rankSearchResults scores results by how well their names, original names
+// and alternate titles (aliases and translations) match the query, allowing
+// for typos, then by popularity and by how close their year is to a year
+// given in the query. Results are returned best first; ties keep the
+// providers' order.
+func rankSearchResults(query string, results []models.SearchResult) []models.SearchResult {
+	q := parseSearchQuery(query)
+	if len(q.tokens) == 0 || len(results) == 0 {
+		return results
+	}
+	for i := range results {
+		results[i].Score = searchRankScoreFor(q, results[i].Title)
+	}
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	return results
+}
+
+// searchRankScoreFor combines name similarity (up to 60), popularity (up to
+// 10), vote count (up to 12) and year proximity (-6 to +10). Popularity and
+// votes weigh enough that a well-known title containing the query can
+// outrank an obscure one named exactly like it.
+func searchRankScoreFor(q searchQuery, title models.Title) int {
+	score := searchTitleSimilarity(q, title) * 60
+	if title.Popularity > 0 {
+		score += math.Min(10, 2*math.Log10(1+title.Popularity))
+	}
+	if title.VoteCount > 0 {
+		score += math.Min(12, 2.5*math.Log10(1+float64(title.VoteCount)))
+	}
+	if q.year > 0 && title.Year > 0 {
+		switch diff := title.Year - q.year; {
+		case diff == 0:
+			score += 10
+		case diff == 1 || diff == -1:
+			score += 6
+		case diff == 2 || diff == -2:
+			score += 2
+		default:
+			score -= 6
+		}
+	}
+	return int(math.Round(score))
+}
+
+// searchTitleSimilarity returns the best similarity, from 0 to 1, between the
+// query and any of the title's names. Alternate names count for slightly
+// less than the name itself.
+func searchTitleSimilarity(q searchQuery, title models.Title) float64 {
+	best := 0.0
+	for i, name := range localSearchNames(title) {
+		tokens := localSearchTokens(name)
+		sim := searchNameSimilarity(q.tokens, tokens)
+		if q.year > 0 {
+			sim = math.Max(sim, searchNameSimilarity(q.name, tokens))
+		}
+		if i > 0 {
+			sim *= 0.95
+		}
+		best = math.Max(best, sim)
+	}
+	return best
+}
+
+// searchNameSimilarity rates a name against the query: 1 for the same
+// words, 0.95 when the name starts with them, 0.9 when it contains them all,
+// and up to 0.75 for near misses by edit distance, shared trigrams or words
+// within a typo of each other.
+func searchNameSimilarity(query, name []string) float64 {
+	if len(query) == 0 || len(name) == 0 {
+		return 0
+	}
+	switch {
+	case equalTokens(name, query):
+		return 1
+	case hasTokenPrefix(name, query):
+		return 0.95
+	case containsTokens(name, query):
+		return 0.9
+	}
+	a, b := strings.Join(query, " "), strings.Join(name, " ")
+	fuzzy := math.Max(editSimilarity(a, b), trigramSimilarity(a, b))
+	fuzzy = math.Max(fuzzy, fuzzyTokenCoverage(query, name))
+	return 0.75 * fuzzy
+}
+
+// fuzzyTokenCoverage is the share of query words that match a word of the
+// name within typoTolerance edits, scaled down when the name has many more
+// words than the query.
+func fuzzyTokenCoverage(query, name []string) float64 {
+	matched := 0
+	for _, q := range query {
+		for _, token := range name {
+			if withinTypo(q, token) {
+				matched++
+				break
+			}
+		}
+	}
+	coverage := float64(matched) / float64(len(query))
+	if len(name) > len(query) {
+		coverage *= float64(len(query)) / float64(len(name))
+	}
+	return coverage
+}
+
+// typoTolerance is the number of edits a word of this length may be off by:
+// none for short words, one up to seven letters, two beyond.
+func typoTolerance(word string) int {
+	switch n := len([]rune(word)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func withinTypo(query, token string) bool {
+	if query == token {
+		return true
+	}
+	maxEdits := typoTolerance(query)
+	if maxEdits == 0 {
+		return false
+	}
+	qr, tr := []rune(query), []rune(token)
+	if d := len(qr) - len(tr); d > maxEdits || -d > maxEdits {
+		return false
+	}
+	return editDistance(qr, tr) <= maxEdits
+}
+
+// editSimilarity is 1 minus the edit distance over the longer length.
+func editSimilarity(a, b string) float64 {
+	ar, br := []rune(a), []rune(b)
+	longest := len(ar)
+	if len(br) > longest {
+		longest = len(br)
+	}
+	if longest == 0 {
+		return 0
+	}
+	return 1 - float64(editDistance(ar, br))/float64(longest)
+}
+
+// editDistance returns the number of single-rune insertions, deletions,
+// substitutions and adjacent transpositions turning a into b (the optimal
+// string alignment distance), so "matirx" is one typo from "matrix".
+func editDistance(a, b []rune) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d := min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				d = min(d, rows[i-2][j-2]+1)
+			}
+			rows[i][j] = d
+		}
+	}
+	return rows[len(a)][len(b)]
+}
+
+// trigramSimilarity is the Jaccard similarity of the padded trigram sets of
+// a and b.
+func trigramSimilarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	shared := 0
+	for gram := range ta {
+		if _, ok := tb[gram]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+func trigrams(s string) map[string]struct{} {
+	runes := []rune("  " + s + " ")
+	grams := make(map[string]struct{}, len(runes))
+	for i := 0; i+3 <= len(runes); i++ {
+		grams[string(runes[i:i+3])] = struct{}{}
+	}
+	return grams
+}
+
+// fuzzySearch returns the indexed titles with a word within a typo of each
+// query word, for when an exact search finds nothing.
+func (idx *localSearchIndex) fuzzySearch(query []string, mediaType string) []models.SearchResult {
+	if idx == nil || len(query) == 0 {
+		return nil
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var candidates map[string]struct{}
+	for _, q := range query {
+		matches := make(map[string]struct{})
+		for indexed, ids := range idx.postings {
+			if withinTypo(q, indexed) {
+				for id := range ids {
+					matches[id] = struct{}{}
+				}
+			}
+		}
+		if candidates == nil {
+			candidates = matches
+			continue
+		}
+		for id := range candidates {
+			if _, ok := matches[id]; !ok {
+				delete(candidates, id)
+			}
+		}
+	}
+
+	results := make([]models.SearchResult, 0, len(candidates))
+	for id := range candidates {
+		title := idx.docs[id]
+		if mediaType != "" && title.MediaType != mediaType {
+			continue
+		}
+		results = append(results, models.SearchResult{Title: title})
+	}
+	return results
+}
+
+// addFuzzyMatches adds typo-tolerant matches from the local index when none
+// of results matches the query well, so a misspelt search still finds
+// titles the server has seen before.
+func (s *Service) addFuzzyMatches(query, mediaType string, results []models.SearchResult) []models.SearchResult {
+	if s.searchIndex == nil {
+		return results
+	}
+	q := parseSearchQuery(query)
+	for _, result := range results {
+		if searchTitleSimilarity(q, result.Title) >= searchFuzzyFallbackSimilarity {
+			return results
+		}
+	}
+	for _, match := range s.searchIndex.fuzzySearch(q.name, mediaType) {
+		if searchTitleSimilarity(q, match.Title) >= searchFuzzyMinSimilarity {
+			results = append(results, match)
+		}
+	}
+	return results
+}
@@ -0,0 +1,62 @@
+package metadata
+
+import (
+	"testing"
+
+	"novastream/models"
+)
+
+func TestRankSearchResultsToleratesTyposAndAliases(t *testing.T) {
+	results := []models.SearchResult{
+		{Title: models.Title{ID: "a", Name: "The Office Christmas Party", MediaType: "movie", Popularity: 40}},
+		{Title: models.Title{ID: "b", Name: "Breaking Bad", MediaType: "series", Popularity: 300}},
+		{Title: models.Title{ID: "c", Name: "La Casa de Papel", AlternateTitles: []string{"Money Heist"}, MediaType: "series", Popularity: 150}},
+	}
+
+	ranked := rankSearchResults("braking bad", append([]models.SearchResult(nil), results...))
+	if ranked[0].Title.ID != "b" {
+		t.Fatalf("expected a typo to still rank Breaking Bad first, got %+v", ranked)
+	}
+	ranked = rankSearchResults("money heist", append([]models.SearchResult(nil), results...))
+	if ranked[0].Title.ID != "c" {
+		t.Fatalf("expected the alias to rank La Casa de Papel first, got %+v", ranked)
+	}
+}
+
+func TestRankSearchResultsPrefersQueryYear(t *testing.T) {
+	ranked := rankSearchResults("dune 2021", []models.SearchResult{
+		{Title: models.Title{ID: "1984", Name: "Dune", Year: 1984, MediaType: "movie", Popularity: 30}},
+		{Title: models.Title{ID: "2021", Name: "Dune", Year: 2021, MediaType: "movie", Popularity: 30}},
+	})
+	if ranked[0].Title.ID != "2021" {
+		t.Fatalf("expected the 2021 film first, got %+v", ranked)
+	}
+	if q := parseSearchQuery("blade runner 2049"); q.year != 0 {
+		t.Fatalf("expected a future number not to be read as a year, got %d", q.year)
+	}
+}
+
+func TestSearchLocalFallsBackToTypos(t *testing.T) {
+	svc := &Service{searchIndex: newLocalSearchIndex()}
+	svc.searchIndex.warmOnce.Do(func() {})
+	svc.searchIndex.add(
+		models.Title{ID: "tmdb:movie:603", Name: "The Matrix", MediaType: "movie", TMDBID: 603},
+		models.Title{ID: "tmdb:tv:1396", Name: "Breaking Bad", MediaType: "series", TMDBID: 1396},
+	)
+	results := svc.SearchLocal("brekaing bad", "", nil, 0)
+	if len(results) != 1 || results[0].Title.Name != "Breaking Bad" {
+		t.Fatalf("expected a typo match, got %+v", results)
+	}
+	if results := svc.SearchLocal("zzz", "", nil, 0); len(results) != 0 {
+		t.Fatalf("expected no match, got %+v", results)
+	}
+
+	added := svc.addFuzzyMatches("the matirx", "movie", nil)
+	if len(added) != 1 || added[0].Title.TMDBID != 603 {
+		t.Fatalf("expected the index to fill in a missed remote search, got %+v", added)
+	}
+	strong := []models.SearchResult{{Title: models.Title{Name: "The Matrix", MediaType: "movie"}}}
+	if got := svc.addFuzzyMatches("the matrix", "movie", strong); len(got) != 1 {
+		t.Fatalf("expected no fallback after a good match, got %+v", got)
+	}
+}
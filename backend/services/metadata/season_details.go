@@ -0,0 +1,111 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"novastream/models"
+)
+
+// SeasonDetails returns one season of a series with its episodes filled in
+// from TMDB: stills, overviews in the service language, TMDB ratings and
+// guest stars. Clients fetch it for the season being viewed instead of
+// paying for every season of a long-running show up front.
+func (s *Service) SeasonDetails(ctx context.Context, req models.SeriesDetailsQuery, seasonNumber int) (*models.SeriesSeason, error) {
+	details, err := s.seriesDetails(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	var season *models.SeriesSeason
+	for i := range details.Seasons {
+		if details.Seasons[i].Number == seasonNumber {
+			copied := details.Seasons[i]
+			copied.Episodes = append([]models.SeriesEpisode(nil), copied.Episodes...)
+			season = &copied
+			break
+		}
+	}
+	if season == nil {
+		return nil, newKindError(ErrNotFound, "season %d not found for series %q", seasonNumber, details.Title.Name)
+	}
+
+	tmdbID := details.Title.TMDBID
+	if tmdbID == 0 {
+		tmdbID = req.TMDBID
+	}
+	if tmdbID > 0 && s.tmdb != nil && s.tmdb.isConfigured() {
+		if tmdbSeason, err := s.cachedFetchTMDBSeason(ctx, tmdbID, seasonNumber); err == nil && tmdbSeason != nil {
+			mergeTMDBSeasonEpisodes(season, tmdbSeason, !strings.HasPrefix(normalizeLanguage(s.tmdb.language), "en-"))
+		} else if err != nil {
+			log.Printf("[metadata] season details TMDB fetch failed tmdbId=%d season=%d: %v", tmdbID, seasonNumber, err)
+		}
+	}
+	return season, nil
+}
+
+// cachedFetchTMDBSeason fetches a TMDB season with its episodes with file
+// caching, keyed by language since overviews are localized. Series details
+// read episode stills and ratings through the same entries.
+func (s *Service) cachedFetchTMDBSeason(ctx context.Context, tmdbID int64, seasonNumber int) (*models.SeriesSeason, error) {
+	key := cacheKey("tmdb", "season", "v2", s.tmdb.language, fmt.Sprintf("%d", tmdbID), fmt.Sprintf("%d", seasonNumber))
+	var cached models.SeriesSeason
+	if ok, _ := s.cache.get(key, &cached); ok {
+		return &cached, nil
+	}
+	value, err := s.singleflightCachedFetch(ctx, key, func() (any, error) {
+		var cached models.SeriesSeason
+		if ok, _ := s.cache.get(key, &cached); ok {
+			return &cached, nil
+		}
+		result, err := s.tmdb.seriesSeasonDetails(ctx, tmdbID, tmdbSeasonSummary{Number: seasonNumber})
+		if err != nil {
+			return nil, err
+		}
+		_ = s.cache.set(key, result)
+		return &result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result, _ := value.(*models.SeriesSeason)
+	return result, nil
+}
+
+// mergeTMDBSeasonEpisodes copies TMDB's per-episode extras onto season's
+// episodes, matched by episode number. TMDB overviews replace the existing
+// ones when localized is set (the request language isn't English) and
+// otherwise only fill gaps.
+func mergeTMDBSeasonEpisodes(season *models.SeriesSeason, tmdbSeason *models.SeriesSeason, localized bool) {
+	byNumber := make(map[int]models.SeriesEpisode, len(tmdbSeason.Episodes))
+	for _, episode := range tmdbSeason.Episodes {
+		if episode.EpisodeNumber > 0 {
+			byNumber[episode.EpisodeNumber] = episode
+		}
+	}
+	for i := range season.Episodes {
+		episode := &season.Episodes[i]
+		tmdbEpisode, ok := byNumber[episode.EpisodeNumber]
+		if !ok {
+			continue
+		}
+		if tmdbEpisode.Image != nil && strings.TrimSpace(tmdbEpisode.Image.URL) != "" {
+			image := *tmdbEpisode.Image
+			episode.Image = &image
+		}
+		if overview := strings.TrimSpace(tmdbEpisode.Overview); overview != "" && (localized || strings.TrimSpace(episode.Overview) == "") {
+			episode.Overview = overview
+		}
+		if len(tmdbEpisode.Ratings) > 0 {
+			episode.Ratings = tmdbEpisode.Ratings
+		}
+		if len(tmdbEpisode.GuestStars) > 0 {
+			episode.GuestStars = tmdbEpisode.GuestStars
+		}
+		if episode.Runtime == 0 {
+			episode.Runtime = tmdbEpisode.Runtime
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package metadata
+
+import (
+	"testing"
+
+	"novastream/models"
+)
+
+func TestMergeTMDBSeasonEpisodes(t *testing.T) {
+	tvdbSeason := func() *models.SeriesSeason {
+		return &models.SeriesSeason{Number: 1, Episodes: []models.SeriesEpisode{
+			{EpisodeNumber: 1, Overview: "Mark leads a team of office workers.", Image: &models.Image{URL: "https://artworks.thetvdb.com/e1.jpg"}},
+			{EpisodeNumber: 2},
+			{EpisodeNumber: 3, Overview: "Untouched."},
+		}}
+	}
+	tmdbSeason := &models.SeriesSeason{Number: 1, Episodes: []models.SeriesEpisode{
+		{EpisodeNumber: 1, Overview: "Mark dirige une équipe.", Image: &models.Image{URL: "https://image.tmdb.org/t/p/original/e1.jpg"},
+			Ratings: []models.Rating{{Source: "tmdb", Value: 8.1, Max: 10}}, GuestStars: []models.CastMember{{ID: 30, Name: "Yul Vazquez"}}},
+		{EpisodeNumber: 2, Overview: "Helly tente de démissionner.", Runtime: 53},
+	}}
+
+	season := tvdbSeason()
+	mergeTMDBSeasonEpisodes(season, tmdbSeason, false)
+	first, second := season.Episodes[0], season.Episodes[1]
+	if first.Overview != "Mark leads a team of office workers." {
+		t.Fatalf("expected existing overview to be kept without localization, got %q", first.Overview)
+	}
+	if first.Image.URL != "https://image.tmdb.org/t/p/original/e1.jpg" || len(first.Ratings) != 1 || len(first.GuestStars) != 1 {
+		t.Fatalf("unexpected first episode %+v", first)
+	}
+	if second.Overview != "Helly tente de démissionner." || second.Runtime != 53 {
+		t.Fatalf("expected gaps to be filled, got %+v", second)
+	}
+	if season.Episodes[2].Overview != "Untouched." {
+		t.Fatalf("unmatched episode changed: %+v", season.Episodes[2])
+	}
+
+	season = tvdbSeason()
+	mergeTMDBSeasonEpisodes(season, tmdbSeason, true)
+	if season.Episodes[0].Overview != "Mark dirige une équipe." {
+		t.Fatalf("expected localized overview, got %q", season.Episodes[0].Overview)
+	}
+}
@@ -31,14 +31,28 @@ import (
 type Service struct {
 	client  *tvdbClient
 	tmdb    *tmdbClient
-	ai      *geminiClient
+	ai      *aiClient
 	mdblist *mdblistClient
-	cache   *fileCache
+	cache   cacheStore
+	// Optional Fanart.tv client, artwork provider preference order, and
+	// poster/backdrop selection preferences
+	artworkMu       sync.RWMutex
+	fanart          *fanartClient
+	artworkPriority []string
+	artworkPrefs    ArtworkPreferences
+	// AniList lookups for anime series (ID mapping is shared across languages)
+	anilist *anilistClient
+	// Pluggable trending sources beyond the built-in MDBList lists
+	trendingProviders *trendingProviderRegistry
+	// Community metadata providers searched alongside TVDB and TMDB
+	metadataProviders *metadataProviderRegistry
 	// Separate cache for stable ID mappings (TMDB↔IMDB) with 7x longer TTL
-	idCache *fileCache
+	idCache cacheStore
 	// Separate cache for MDBList ratings — long TTL, persists across restarts
-	ratingsCache *fileCache
-	demo         bool
+	ratingsCache cacheStore
+	// Backing database when the SQLite cache backend is selected (nil for file)
+	cacheDB *sqliteCacheDB
+	demo    bool
 
 	// Cache TTL in hours (stored for reuse when updating clients)
 	ttlHours int
@@ -49,6 +63,11 @@ type Service struct {
 
 	// Trailer prequeue manager for 1080p YouTube trailers
 	trailerPrequeue *TrailerPrequeueManager
+	// Automatic trailer prequeue policy for hero and trending titles
+	trailerPolicyMu      sync.RWMutex
+	trailerPolicy        TrailerPrequeuePolicy
+	trailerPolicyIdle    func() bool
+	trailerPolicyRunning atomic.Bool
 
 	// Cache directory (used to locate yt-dlp cookies file)
 	cacheDir string
@@ -58,6 +77,10 @@ type Service struct {
 
 	allowAdultSearch atomic.Bool
 
+	certCountryMu sync.RWMutex
+	certCountry   string // preferred certification country (ISO 3166-1); US is the fallback
+	watchCountry  string // watch-provider country (ISO 3166-1); empty follows certCountry
+
 	// Background cache manager
 	cacheStopCh          chan struct{}
 	cacheStatusMu        sync.RWMutex
@@ -69,23 +92,71 @@ type Service struct {
 	topTenRefreshMu      sync.Mutex
 	topTenInFlight       sync.Map
 	topTenSourceInFlight sync.Map
-	customListInfoFn     func() []CustomListInfo // returns configured custom MDBList URLs with display names
-	ratingItemsFn        func() []RatingItem     // returns all items that need ratings (watchlist, continue watching, user lists)
+	customListInfoFn     func() []CustomListInfo        // returns configured custom MDBList URLs with display names
+	ratingItemsFn        func() []RatingItem            // returns all items that need ratings (watchlist, continue watching, user lists)
+	warmItemsFn          func() []WarmItem              // returns profile titles (watchlist, continue watching) to pre-enrich
+	discoverFiltersFn    func() []models.DiscoverFilter // returns profiles' saved discover filters to pre-fetch
+	deferRefreshFn       func(time.Time) bool           // reports whether periodic refreshes should wait for the maintenance window
 
 	// Progress tracking for long-running enrichment operations
 	progressMu    sync.RWMutex
 	progressTasks map[string]*ProgressTask
 
+	// Settings-driven enrichment fan-out; zero keeps the built-in defaults.
+	enrichConcurrency atomic.Int32
+	enrichLimit       atomic.Int32
+
+	// Progress subscribers are fed by a single broadcaster goroutine that is
+	// started by the first SubscribeProgress call.
+	progressSubsMu  sync.Mutex
+	progressSubs    map[chan ProgressSnapshot]struct{}
+	progressChanged chan struct{}
+
 	// Guards against concurrent background re-enrichment of the same trending list.
 	// At most one enrichment goroutine per media type runs at a time.
 	trendingEnrichInProgress sync.Map
 	cachedFetchInFlight      sync.Map
+
+	// Last good trending lists, served while a replacement is built. Shared
+	// across language clones.
+	trendingSnapshots *trendingSnapshots
+
+	// Items that failed TVDB enrichment, shared across language clones.
+	enrichFailures *enrichmentFailureTracker
+
+	// Manual title → TVDB/TMDB pins that matching consults first, shared
+	// across language clones.
+	identityOverrides *identityOverrideStore
+	// Manual title field values applied over provider data, shared across
+	// language clones.
+	titleEdits *titleEditStore
+	// Genre hub visit counts that pick the hubs to keep warm, shared across
+	// language clones.
+	genreBrowse *genreBrowseStats
+	// Titles seen so far, for instant local search; shared across language
+	// clones.
+	searchIndex *localSearchIndex
+
+	// Reads Letterboxd URLs used as custom lists; optional.
+	letterboxd letterboxdListSource
+
+	// Reads IMDb list, watchlist and CSV URLs used as custom lists; optional.
+	imdb imdbListSource
+
+	// Reads public Trakt list URLs used as custom lists; optional.
+	trakt traktListSource
+
+	// Reads other servers' share links used as custom lists; optional.
+	sharedLists sharedListSource
+
+	// Computes poster and backdrop blurhashes and dominant colors; optional.
+	placeholders artworkPlaceholderSource
 }
 
 // CacheManagerStatus holds the current state of the background cache manager.
 type CacheManagerStatus struct {
 	Running           bool      `json:"running"`
-	Status            string    `json:"status"` // "idle", "warming", "refreshing"
+	Status            string    `json:"status"` // "idle", "warming", "refreshing", "paused"
 	LastRefreshAt     time.Time `json:"lastRefreshAt"`
 	LastRefreshMs     int64     `json:"lastRefreshMs"`
 	NextRefreshAt     time.Time `json:"nextRefreshAt"`
@@ -94,6 +165,10 @@ type CacheManagerStatus struct {
 	SeriesCached      int       `json:"seriesCached"`
 	CustomListsCached int       `json:"customListsCached"`
 	LastError         string    `json:"lastError,omitempty"`
+	// RateLimits reports each upstream API limiter, keyed by provider.
+	RateLimits map[string]RateLimitStats `json:"rateLimits,omitempty"`
+	// Offline is set while offline mode pauses refreshes and upstream requests.
+	Offline bool `json:"offline"`
 }
 
 type TopTenWorkerStatus struct {
@@ -119,12 +194,42 @@ func formatInterval(d time.Duration) string {
 
 // ProgressTask tracks the progress of a long-running metadata operation.
 type ProgressTask struct {
-	ID        string `json:"id"`        // "trending-movie", "trending-series", "custom-list:<url>"
-	Label     string `json:"label"`     // "Trending Movies", "My Custom List"
-	Phase     string `json:"phase"`     // "fetching", "enriching", "enriching-releases"
-	Current   int32  `json:"current"`   // items processed (updated atomically)
-	Total     int32  `json:"total"`     // total items (updated atomically)
-	StartedAt int64  `json:"startedAt"` // unix ms
+	ID        string `json:"id"`                  // "trending-movie", "trending-series", "custom-list:<url>"
+	Label     string `json:"label"`               // "Trending Movies", "My Custom List"
+	Phase     string `json:"phase"`               // "fetching", "enriching", "enriching-releases"
+	Current   int32  `json:"current"`             // items processed (updated atomically)
+	Total     int32  `json:"total"`               // total items (updated atomically)
+	StartedAt int64  `json:"startedAt"`           // unix ms
+	Cancelled bool   `json:"cancelled,omitempty"` // cancel requested; the task is winding down
+
+	// Computed for snapshots from the current phase, since Current restarts
+	// with every phase.
+	PhaseStartedAt int64   `json:"phaseStartedAt"`       // unix ms
+	Rate           float64 `json:"rate,omitempty"`       // items per second
+	ETASeconds     int64   `json:"etaSeconds,omitempty"` // estimated seconds left in the phase
+
+	cancel    context.CancelFunc
+	cancelled int32 // set atomically by CancelProgressTask
+}
+
+// progressETAMinElapsed is how long a phase must run before a rate and ETA
+// are reported; the first few items are too noisy to extrapolate from.
+const progressETAMinElapsed = 2 * time.Second
+
+// progressRate returns the items/sec rate and the estimated seconds left for
+// a phase that started at phaseStartedAt (unix ms). Both are zero until the
+// phase has run long enough and processed at least one item.
+func progressRate(current, total int32, phaseStartedAt int64, now time.Time) (float64, int64) {
+	elapsed := now.Sub(time.UnixMilli(phaseStartedAt))
+	if current <= 0 || elapsed < progressETAMinElapsed {
+		return 0, 0
+	}
+	rate := float64(current) / elapsed.Seconds()
+	var eta int64
+	if total > current {
+		eta = int64(math.Ceil(float64(total-current) / rate))
+	}
+	return math.Round(rate*10) / 10, eta
 }
 
 // ProgressSnapshot is the response payload for the progress endpoint.
@@ -160,6 +265,17 @@ type RatingItem struct {
 	MediaType string // "movie" or "series"
 }
 
+// WarmItem identifies a title from a profile's watchlist or continue-watching
+// row whose details should be cached before anyone opens the shelf.
+type WarmItem struct {
+	MediaType string // "movie" or "series"
+	Name      string
+	Year      int
+	IMDBID    string
+	TMDBID    int64
+	TVDBID    int64
+}
+
 // MDBListConfig holds configuration for the MDBList client
 type MDBListConfig struct {
 	APIKey         string
@@ -190,20 +306,30 @@ func NewService(tvdbAPIKey, tmdbAPIKey, language, cacheDir string, ttlHours int,
 	}
 
 	svc := &Service{
-		client:           newTVDBClient(tvdbAPIKey, language, &http.Client{}, ttlHours),
-		tmdb:             newTMDBClient(tmdbAPIKey, language, &http.Client{}, newFileCache(metadataCacheDir, ttlHours)),
-		ai:               newAIClient(aiConfig, &http.Client{}, newFileCache(metadataCacheDir, ttlHours)),
-		mdblist:          newMDBListClient(mdblistCfg.APIKey, mdblistCfg.EnabledRatings, mdblistCfg.Enabled, ttlHours),
-		cache:            newFileCache(metadataCacheDir, ttlHours),
-		idCache:          newFileCache(idCacheDir, ttlHours*stableIDCacheTTLMultiplier),
-		ratingsCache:     newFileCache(ratingsCacheDir, ttlHours*stableIDCacheTTLMultiplier),
-		demo:             demo,
-		ttlHours:         ttlHours,
-		inflightRequests: make(map[string]*inflightRequest),
-		trailerPrequeue:  trailerMgr,
-		progressTasks:    make(map[string]*ProgressTask),
-		cacheDir:         cacheDir,
-	}
+		client:            newTVDBClient(tvdbAPIKey, language, &http.Client{}, ttlHours),
+		tmdb:              newTMDBClient(tmdbAPIKey, language, &http.Client{}, newFileCache(metadataCacheDir, ttlHours)),
+		ai:                newAIClient(aiConfig, &http.Client{}, newFileCache(metadataCacheDir, ttlHours)),
+		mdblist:           newMDBListClient(mdblistCfg.APIKey, mdblistCfg.EnabledRatings, mdblistCfg.Enabled, ttlHours),
+		cache:             newFileCache(metadataCacheDir, ttlHours),
+		idCache:           newFileCache(idCacheDir, ttlHours*stableIDCacheTTLMultiplier),
+		ratingsCache:      newFileCache(ratingsCacheDir, ttlHours*stableIDCacheTTLMultiplier),
+		demo:              demo,
+		ttlHours:          ttlHours,
+		inflightRequests:  make(map[string]*inflightRequest),
+		trailerPrequeue:   trailerMgr,
+		progressTasks:     make(map[string]*ProgressTask),
+		cacheDir:          cacheDir,
+		trendingProviders: newTrendingProviderRegistry(),
+		anilist:           newAniListClient(&http.Client{Timeout: 30 * time.Second}),
+		enrichFailures:    newEnrichmentFailureTracker(),
+		trendingSnapshots: newTrendingSnapshots(),
+		identityOverrides: newIdentityOverrideStore(filepath.Join(cacheDir, identityOverridesFile)),
+		titleEdits:        newTitleEditStore(filepath.Join(cacheDir, titleEditsFile)),
+		genreBrowse:       newGenreBrowseStats(filepath.Join(cacheDir, genreBrowseStatsFile)),
+		searchIndex:       newLocalSearchIndex(),
+		metadataProviders: newMetadataProviderRegistry(),
+	}
+	svc.registerProviderModules()
 	return svc
 }
 
@@ -214,7 +340,12 @@ func (s *Service) WithLanguage(language string) *Service {
 	if language == "" || (s.client != nil && strings.EqualFold(normalizeTVDBLanguage(language), s.client.language)) {
 		return s
 	}
+	return s.localCopy(language)
+}
 
+// localCopy returns a service sharing s's caches and configuration that
+// fetches in language.
+func (s *Service) localCopy(language string) *Service {
 	tvdbAPIKey := ""
 	if s.client != nil {
 		tvdbAPIKey = s.client.apiKey
@@ -232,6 +363,7 @@ func (s *Service) WithLanguage(language string) *Service {
 		cache:               s.cache,
 		idCache:             s.idCache,
 		ratingsCache:        s.ratingsCache,
+		cacheDB:             s.cacheDB,
 		demo:                s.demo,
 		ttlHours:            s.ttlHours,
 		inflightRequests:    make(map[string]*inflightRequest),
@@ -240,11 +372,40 @@ func (s *Service) WithLanguage(language string) *Service {
 		cacheDir:            s.cacheDir,
 		customListInfoFn:    s.customListInfoFn,
 		ratingItemsFn:       s.ratingItemsFn,
+		warmItemsFn:         s.warmItemsFn,
+		discoverFiltersFn:   s.discoverFiltersFn,
 		topTenInterval:      s.topTenInterval,
 		topTenInFlight:      sync.Map{},
 		cachedFetchInFlight: sync.Map{},
+		trendingProviders:   s.trendingProviders,
+		anilist:             s.anilist,
+		enrichFailures:      s.enrichFailures,
+		trendingSnapshots:   s.trendingSnapshots,
+		identityOverrides:   s.identityOverrides,
+		titleEdits:          s.titleEdits,
+		genreBrowse:         s.genreBrowse,
+		searchIndex:         s.searchIndex,
+		metadataProviders:   s.metadataProviders,
+		letterboxd:          s.letterboxd,
+		imdb:                s.imdb,
+		trakt:               s.trakt,
+		sharedLists:         s.sharedLists,
+		placeholders:        s.placeholders,
 	}
 	local.allowAdultSearch.Store(s.allowAdultSearch.Load())
+	local.certCountry = s.certificationCountry()
+	s.certCountryMu.RLock()
+	local.watchCountry = s.watchCountry
+	s.certCountryMu.RUnlock()
+	local.enrichConcurrency.Store(s.enrichConcurrency.Load())
+	local.enrichLimit.Store(s.enrichLimit.Load())
+
+	fanart, priority := s.artworkProviders()
+	if fanart != nil {
+		local.fanart = newFanartClient(fanart.apiKey, language, fanart.httpc)
+	}
+	local.artworkPriority = priority
+	local.artworkPrefs = s.artworkPreferences()
 
 	s.ytdlpProxyMu.RLock()
 	local.ytdlpProxy = s.ytdlpProxy
@@ -264,6 +425,23 @@ func (s *Service) SetYTDLPProxyURL(proxyURL string) {
 	}
 }
 
+// SetTrailerDiskSpaceCheck sets a function that is consulted before each
+// trailer download; an error fails the download instead of writing to disk.
+func (s *Service) SetTrailerDiskSpaceCheck(fn func() error) {
+	if s.trailerPrequeue != nil {
+		s.trailerPrequeue.SetDiskSpaceCheck(fn)
+	}
+}
+
+// TrailerDir returns the directory trailers are downloaded to, or "" when
+// trailer downloads are unavailable.
+func (s *Service) TrailerDir() string {
+	if s.trailerPrequeue == nil {
+		return ""
+	}
+	return s.trailerPrequeue.tempDir
+}
+
 func (s *Service) ytdlpProxyURL() string {
 	s.ytdlpProxyMu.RLock()
 	defer s.ytdlpProxyMu.RUnlock()
@@ -279,15 +457,79 @@ func (s *Service) adultSearchAllowed() bool {
 	return s.allowAdultSearch.Load()
 }
 
-// startProgressTask registers a new progress task and returns a cleanup function
-// that removes it when the operation completes.
-func (s *Service) startProgressTask(id, label, phase string, total int) func() {
+// SetCertificationCountry sets the country whose content ratings are preferred
+// (ISO 3166-1, e.g. "DE" or "GB"). Titles without a rating for that country
+// fall back to the US rating. Empty selects US.
+func (s *Service) SetCertificationCountry(country string) {
+	s.certCountryMu.Lock()
+	s.certCountry = strings.ToUpper(strings.TrimSpace(country))
+	s.certCountryMu.Unlock()
+}
+
+func (s *Service) certificationCountry() string {
+	s.certCountryMu.RLock()
+	defer s.certCountryMu.RUnlock()
+	return s.certCountry
+}
+
+// SetWatchProviderCountry sets the country whose streaming catalogs are
+// attached to movie and series details (ISO 3166-1). Empty follows the
+// certification country.
+func (s *Service) SetWatchProviderCountry(country string) {
+	s.certCountryMu.Lock()
+	s.watchCountry = strings.ToUpper(strings.TrimSpace(country))
+	s.certCountryMu.Unlock()
+}
+
+// WatchProviderRegion returns the server's default watch provider region:
+// the watch provider country, then the certification country, then US.
+func (s *Service) WatchProviderRegion() string {
+	return s.watchProviderCountry()
+}
+
+func (s *Service) watchProviderCountry() string {
+	s.certCountryMu.RLock()
+	country := s.watchCountry
+	if country == "" {
+		country = s.certCountry
+	}
+	s.certCountryMu.RUnlock()
+	return normalizeWatchProviderRegion(country)
+}
+
+// pickCertification selects the preferred country's rating from certs, falling
+// back to the US rating. It returns the rating and its country.
+func (s *Service) pickCertification(certs map[string]string) (string, string) {
+	if country := s.certificationCountry(); country != "" {
+		if cert := strings.TrimSpace(certs[country]); cert != "" {
+			return cert, country
+		}
+	}
+	if cert := strings.TrimSpace(certs["US"]); cert != "" {
+		return cert, "US"
+	}
+	return "", ""
+}
+
+// setTitleCertification applies the preferred rating from certs to title.
+func (s *Service) setTitleCertification(title *models.Title, certs map[string]string) {
+	title.Certification, title.CertificationCountry = s.pickCertification(certs)
+}
+
+// startProgressTask registers a new progress task and returns a context for
+// its work, cancelled by CancelProgressTask, and a cleanup function that
+// removes the task when the operation completes.
+func (s *Service) startProgressTask(ctx context.Context, id, label, phase string, total int) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	now := time.Now().UnixMilli()
 	task := &ProgressTask{
-		ID:        id,
-		Label:     label,
-		Phase:     phase,
-		Total:     int32(total),
-		StartedAt: time.Now().UnixMilli(),
+		ID:             id,
+		Label:          label,
+		Phase:          phase,
+		Total:          int32(total),
+		StartedAt:      now,
+		PhaseStartedAt: now,
+		cancel:         cancel,
 	}
 	s.progressMu.Lock()
 	if s.progressTasks == nil {
@@ -295,13 +537,38 @@ func (s *Service) startProgressTask(id, label, phase string, total int) func() {
 	}
 	s.progressTasks[id] = task
 	s.progressMu.Unlock()
-	return func() {
+	s.notifyProgress()
+	return ctx, func() {
+		cancel()
 		s.progressMu.Lock()
-		delete(s.progressTasks, id)
+		// A newer run of the same operation may have replaced this task.
+		if s.progressTasks[id] == task {
+			delete(s.progressTasks, id)
+		}
 		s.progressMu.Unlock()
+		s.notifyProgress()
 	}
 }
 
+// CancelProgressTask cancels the running task with the given ID. Its worker
+// pools stop picking up new items and partial results are not cached; the
+// task stays listed, marked cancelled, until it has wound down. Returns
+// false when no such task is running.
+func (s *Service) CancelProgressTask(id string) bool {
+	s.progressMu.RLock()
+	task, ok := s.progressTasks[id]
+	s.progressMu.RUnlock()
+	if !ok {
+		return false
+	}
+	if atomic.CompareAndSwapInt32(&task.cancelled, 0, 1) {
+		log.Printf("[metadata] cancelling progress task %q", id)
+		task.cancel()
+		s.notifyProgress()
+	}
+	return true
+}
+
 // updateProgressPhase changes the phase and resets the counter for a task.
 func (s *Service) updateProgressPhase(id, phase string, total int) {
 	s.progressMu.RLock()
@@ -312,8 +579,12 @@ func (s *Service) updateProgressPhase(id, phase string, total int) {
 	}
 	atomic.StoreInt32(&task.Current, 0)
 	atomic.StoreInt32(&task.Total, int32(total))
+	s.progressMu.Lock()
+	task.PhaseStartedAt = time.Now().UnixMilli()
+	s.progressMu.Unlock()
 	// Phase is only written from the orchestrating goroutine, so no race.
 	task.Phase = phase
+	s.notifyProgress()
 }
 
 // incrementProgress atomically increments the Current counter for a task.
@@ -325,22 +596,28 @@ func (s *Service) incrementProgress(id string) {
 		return
 	}
 	atomic.AddInt32(&task.Current, 1)
+	s.notifyProgress()
 }
 
 // GetProgressSnapshot returns a copy of all active progress tasks.
 func (s *Service) GetProgressSnapshot() ProgressSnapshot {
 	s.progressMu.RLock()
 	defer s.progressMu.RUnlock()
+	now := time.Now()
 	tasks := make([]ProgressTask, 0, len(s.progressTasks))
 	for _, t := range s.progressTasks {
-		tasks = append(tasks, ProgressTask{
-			ID:        t.ID,
-			Label:     t.Label,
-			Phase:     t.Phase,
-			Current:   atomic.LoadInt32(&t.Current),
-			Total:     atomic.LoadInt32(&t.Total),
-			StartedAt: t.StartedAt,
-		})
+		task := ProgressTask{
+			ID:             t.ID,
+			Label:          t.Label,
+			Phase:          t.Phase,
+			Current:        atomic.LoadInt32(&t.Current),
+			Total:          atomic.LoadInt32(&t.Total),
+			StartedAt:      t.StartedAt,
+			Cancelled:      atomic.LoadInt32(&t.cancelled) == 1,
+			PhaseStartedAt: t.PhaseStartedAt,
+		}
+		task.Rate, task.ETASeconds = progressRate(task.Current, task.Total, task.PhaseStartedAt, now)
+		tasks = append(tasks, task)
 	}
 	return ProgressSnapshot{
 		Tasks:       tasks,
@@ -348,6 +625,73 @@ func (s *Service) GetProgressSnapshot() ProgressSnapshot {
 	}
 }
 
+// progressBroadcastInterval caps how often subscribers receive snapshots;
+// enrichment increments progress far faster than a UI can render it.
+const progressBroadcastInterval = 250 * time.Millisecond
+
+// SubscribeProgress returns a channel of progress snapshots and a function
+// that ends the subscription. The current snapshot is delivered immediately;
+// later updates are coalesced so a slow reader only sees the latest one.
+func (s *Service) SubscribeProgress() (<-chan ProgressSnapshot, func()) {
+	ch := make(chan ProgressSnapshot, 1)
+	ch <- s.GetProgressSnapshot()
+
+	s.progressSubsMu.Lock()
+	if s.progressSubs == nil {
+		s.progressSubs = make(map[chan ProgressSnapshot]struct{})
+	}
+	if s.progressChanged == nil {
+		s.progressChanged = make(chan struct{}, 1)
+		go s.broadcastProgress(s.progressChanged)
+	}
+	s.progressSubs[ch] = struct{}{}
+	s.progressSubsMu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.progressSubsMu.Lock()
+			delete(s.progressSubs, ch)
+			close(ch)
+			s.progressSubsMu.Unlock()
+		})
+	}
+}
+
+// notifyProgress wakes the broadcaster, if any subscriber has ever started it.
+func (s *Service) notifyProgress() {
+	s.progressSubsMu.Lock()
+	changed := s.progressChanged
+	s.progressSubsMu.Unlock()
+	if changed == nil {
+		return
+	}
+	select {
+	case changed <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Service) broadcastProgress(changed <-chan struct{}) {
+	for range changed {
+		snapshot := s.GetProgressSnapshot()
+		s.progressSubsMu.Lock()
+		for ch := range s.progressSubs {
+			// Replace any snapshot the subscriber hasn't read yet.
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- snapshot:
+			default:
+			}
+		}
+		s.progressSubsMu.Unlock()
+		time.Sleep(progressBroadcastInterval)
+	}
+}
+
 // StartBackgroundCacheManager warms the trending cache on startup and refreshes
 // it periodically. This ensures the first user request is served from cache
 // rather than blocking on hundreds of external API calls.
@@ -366,19 +710,29 @@ func (s *Service) StartBackgroundCacheManager(refreshInterval time.Duration) {
 	s.cacheStatusMu.Unlock()
 
 	go func() {
+		s.purgeLegacyCacheEntries()
+
 		// Initial warm-up
-		log.Println("[metadata] background cache manager: warming trending caches...")
-		start := time.Now()
-		s.warmTrendingCache()
-		elapsed := time.Since(start)
-		log.Printf("[metadata] background cache manager: warm-up complete (%s)", elapsed.Round(time.Millisecond))
+		if s.Offline() {
+			log.Println("[metadata] background cache manager: offline mode, skipping warm-up")
+			s.cacheStatusMu.Lock()
+			s.cacheStatus.Status = "paused"
+			s.cacheStatus.NextRefreshAt = time.Now().Add(refreshInterval)
+			s.cacheStatusMu.Unlock()
+		} else {
+			log.Println("[metadata] background cache manager: warming trending caches...")
+			start := time.Now()
+			s.warmTrendingCache(context.Background())
+			elapsed := time.Since(start)
+			log.Printf("[metadata] background cache manager: warm-up complete (%s)", elapsed.Round(time.Millisecond))
 
-		s.cacheStatusMu.Lock()
-		s.cacheStatus.Status = "idle"
-		s.cacheStatus.LastRefreshAt = time.Now()
-		s.cacheStatus.LastRefreshMs = elapsed.Milliseconds()
-		s.cacheStatus.NextRefreshAt = time.Now().Add(refreshInterval)
-		s.cacheStatusMu.Unlock()
+			s.cacheStatusMu.Lock()
+			s.cacheStatus.Status = "idle"
+			s.cacheStatus.LastRefreshAt = time.Now()
+			s.cacheStatus.LastRefreshMs = elapsed.Milliseconds()
+			s.cacheStatus.NextRefreshAt = time.Now().Add(refreshInterval)
+			s.cacheStatusMu.Unlock()
+		}
 
 		ticker := time.NewTicker(refreshInterval)
 		defer ticker.Stop()
@@ -386,13 +740,29 @@ func (s *Service) StartBackgroundCacheManager(refreshInterval time.Duration) {
 		for {
 			select {
 			case <-ticker.C:
+				if s.Offline() {
+					log.Println("[metadata] background cache manager: offline mode, pausing refresh")
+					s.cacheStatusMu.Lock()
+					s.cacheStatus.Status = "paused"
+					s.cacheStatus.NextRefreshAt = time.Now().Add(refreshInterval)
+					s.cacheStatusMu.Unlock()
+					continue
+				}
+				if s.shouldDeferCacheRefresh(time.Now()) {
+					log.Println("[metadata] background cache manager: outside maintenance window, deferring refresh")
+					s.cacheStatusMu.Lock()
+					s.cacheStatus.NextRefreshAt = time.Now().Add(refreshInterval)
+					s.cacheStatusMu.Unlock()
+					continue
+				}
+
 				log.Println("[metadata] background cache manager: refreshing trending caches...")
 				s.cacheStatusMu.Lock()
 				s.cacheStatus.Status = "refreshing"
 				s.cacheStatusMu.Unlock()
 
 				start := time.Now()
-				s.warmTrendingCache()
+				s.warmTrendingCache(context.Background())
 				elapsed := time.Since(start)
 				log.Printf("[metadata] background cache manager: refresh complete (%s)", elapsed.Round(time.Millisecond))
 
@@ -441,6 +811,42 @@ func (s *Service) SetRatingItemsProvider(fn func() []RatingItem) {
 	s.ratingItemsFn = fn
 }
 
+// SetWarmItemsProvider sets a function that returns the titles (across all
+// profiles) on watchlist and continue-watching shelves. Called before each
+// warming cycle so newly added titles are picked up.
+func (s *Service) SetWarmItemsProvider(fn func() []WarmItem) {
+	s.warmItemsFn = fn
+}
+
+// SetDiscoverFiltersProvider sets a function that returns the saved discover
+// filters of all profiles. Called before each warming cycle so the first page
+// of newly saved filters is fetched ahead of the browse screens.
+func (s *Service) SetDiscoverFiltersProvider(fn func() []models.DiscoverFilter) {
+	s.discoverFiltersFn = fn
+}
+
+// SetMaintenanceWindowCheck sets a function that reports whether heavy
+// background work should be held back at a given time. Periodic trending
+// refreshes wait while it returns true, unless the cache is more than
+// maxDeferredCacheAge old; startup warm-up and manual refreshes always run.
+func (s *Service) SetMaintenanceWindowCheck(fn func(time.Time) bool) {
+	s.deferRefreshFn = fn
+}
+
+// maxDeferredCacheAge bounds how stale the trending cache may get while
+// periodic refreshes wait for the maintenance window.
+const maxDeferredCacheAge = 24 * time.Hour
+
+func (s *Service) shouldDeferCacheRefresh(now time.Time) bool {
+	if s.deferRefreshFn == nil || !s.deferRefreshFn(now) {
+		return false
+	}
+	s.cacheStatusMu.RLock()
+	lastRefresh := s.cacheStatus.LastRefreshAt
+	s.cacheStatusMu.RUnlock()
+	return now.Sub(lastRefresh) < maxDeferredCacheAge
+}
+
 // SetCustomListURLsProvider is a convenience wrapper that accepts a URL-only provider.
 // Deprecated: use SetCustomListInfoProvider to include display names for progress tracking.
 func (s *Service) SetCustomListURLsProvider(fn func() []string) {
@@ -459,17 +865,17 @@ func (s *Service) GetCacheManagerStatus() CacheManagerStatus {
 	s.cacheStatusMu.RLock()
 	defer s.cacheStatusMu.RUnlock()
 	status := s.cacheStatus
-	// Count cached items using the same v6+language key that Trending() reads.
+	// Count cached items using the same keys that Trending() reads.
 	lang := ""
 	if s.client != nil {
 		lang = s.client.language
 	}
-	movieKey := cacheKey("mdblist", "trending", "movie", "v7", lang)
+	movieKey := trendingCacheKey("movie", "full", lang)
 	var movies []models.TrendingItem
 	if ok, _ := s.cache.get(movieKey, &movies); ok {
 		status.MoviesCached = len(movies)
 	}
-	seriesKey := cacheKey("mdblist", "trending", "series", "v7", lang)
+	seriesKey := trendingCacheKey("series", "full", lang)
 	var series []models.TrendingItem
 	if ok, _ := s.cache.get(seriesKey, &series); ok {
 		status.SeriesCached = len(series)
@@ -490,11 +896,22 @@ func (s *Service) GetCacheManagerStatus() CacheManagerStatus {
 		}
 		status.CustomListsCached = cached
 	}
+	status.RateLimits = s.RateLimitStats()
+	status.Offline = s.Offline()
 	return status
 }
 
+// manualRefreshTaskID is the progress task ID of a manual cache refresh,
+// which can be cancelled like any other task.
+const manualRefreshTaskID = "cache-refresh"
+
 // RefreshTrendingCache forces an immediate refresh of the trending cache.
+// It does nothing in offline mode.
 func (s *Service) RefreshTrendingCache() {
+	if s.Offline() {
+		log.Println("[metadata] manual cache refresh skipped: offline mode")
+		return
+	}
 	go func() {
 		s.cacheStatusMu.Lock()
 		s.cacheStatus.Status = "refreshing"
@@ -502,20 +919,20 @@ func (s *Service) RefreshTrendingCache() {
 
 		log.Println("[metadata] manual cache refresh triggered")
 		start := time.Now()
+		ctx, cleanup := s.startProgressTask(context.Background(), manualRefreshTaskID, "Metadata Refresh", "refreshing", 0)
+		defer cleanup()
 
-		// Invalidate trending cache entries by overwriting with empty slices,
-		// then re-warm to fetch fresh data from MDBList + TMDB/TVDB.
-		// Use the same v6+language key that Trending() reads.
-		lang := ""
-		if s.client != nil {
-			lang = s.client.language
-		}
-		_ = s.cache.set(cacheKey("mdblist", "trending", "movie", "v7", lang), []models.TrendingItem{})
-		_ = s.cache.set(cacheKey("mdblist", "trending", "series", "v7", lang), []models.TrendingItem{})
-
-		s.warmTrendingCache()
+		// Rebuild the trending lists behind the cached ones so readers keep
+		// getting the old lists until each replacement is fully enriched,
+		// then re-warm custom lists and ratings.
+		s.refreshTrendingLists(ctx)
+		s.warmTrendingCache(ctx)
 		elapsed := time.Since(start)
-		log.Printf("[metadata] manual cache refresh complete (%s)", elapsed.Round(time.Millisecond))
+		if ctx.Err() != nil {
+			log.Printf("[metadata] manual cache refresh cancelled (%s)", elapsed.Round(time.Millisecond))
+		} else {
+			log.Printf("[metadata] manual cache refresh complete (%s)", elapsed.Round(time.Millisecond))
+		}
 
 		s.cacheStatusMu.Lock()
 		s.cacheStatus.Status = "idle"
@@ -573,6 +990,9 @@ func (s *Service) StopBackgroundTopTenWorker() {
 }
 
 func (s *Service) runTopTenRefresh() error {
+	if err := offlineError("top ten"); err != nil {
+		return err
+	}
 	s.topTenRefreshMu.Lock()
 	defer s.topTenRefreshMu.Unlock()
 
@@ -584,6 +1004,13 @@ func (s *Service) runTopTenRefresh() error {
 }
 
 func (s *Service) finishTopTenRefresh(start time.Time, err error) {
+	if errors.Is(err, ErrOffline) {
+		s.topTenStatusMu.Lock()
+		s.topTenStatus.Status = "paused"
+		s.topTenStatus.NextRefreshAt = time.Now().Add(s.topTenInterval)
+		s.topTenStatusMu.Unlock()
+		return
+	}
 	elapsed := time.Since(start)
 	status := "idle"
 	lastErr := ""
@@ -766,8 +1193,10 @@ func (s *Service) refreshTopTenCache(ctx context.Context, mediaType string, cust
 
 // warmTrendingCache pre-fetches and enriches trending data and custom MDBList lists.
 // All fetches run concurrently to minimize total warm-up time when MDBList is slow.
-func (s *Service) warmTrendingCache() {
-	ctx := context.Background()
+// Cancelling ctx stops it picking up further lists. Titles on several lists
+// are enriched once per cycle.
+func (s *Service) warmTrendingCache(ctx context.Context) {
+	ctx = withListEnrichmentBatch(ctx)
 	var mu sync.Mutex
 	var lastErr string
 
@@ -812,7 +1241,11 @@ func (s *Service) warmTrendingCache() {
 				wg.Add(1)
 				go func(info CustomListInfo) {
 					defer wg.Done()
-					sem <- struct{}{}
+					select {
+					case sem <- struct{}{}:
+					case <-ctx.Done():
+						return
+					}
 					defer func() { <-sem }()
 					opts := CustomListOptions{Limit: 0, Offset: 0, Label: info.Name}
 					if _, _, _, err := s.GetCustomList(ctx, info.URL, opts); err != nil {
@@ -823,17 +1256,158 @@ func (s *Service) warmTrendingCache() {
 		}
 	}
 
+	// Warm the first page of saved discover filters (concurrently, capped at 3 workers)
+	if s.discoverFiltersFn != nil {
+		filters := uniqueDiscoverFilters(s.discoverFiltersFn())
+		if len(filters) > 0 {
+			log.Printf("[metadata] cache manager: warming %d saved discover filters", len(filters))
+			sem := make(chan struct{}, 3)
+			for _, filter := range filters {
+				wg.Add(1)
+				go func(filter models.DiscoverFilter) {
+					defer wg.Done()
+					select {
+					case sem <- struct{}{}:
+					case <-ctx.Done():
+						return
+					}
+					defer func() { <-sem }()
+					if _, _, err := s.DiscoverWithFilter(ctx, filter, 0, 0, ShelfLoadOptions{}); err != nil {
+						log.Printf("[metadata] cache manager: saved filter error type=%s: %v", filter.MediaType, err)
+					}
+				}(filter)
+			}
+		}
+	}
+
+	// Warm the hubs of the most-browsed genres
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.warmGenreHubs(ctx)
+	}()
+
 	wg.Wait()
 
-	// Warm MDBList ratings (disk-persisted) for all cached items so that
-	// sort-by-rating works immediately without per-request API calls.
-	s.warmRatingsForCachedItems(ctx)
+	if ctx.Err() == nil {
+		// Pre-enrich watchlist and continue-watching titles so those shelves can
+		// be served from cache right after a restart.
+		if err := s.warmProfileItems(ctx); err != nil {
+			appendErr("profile items: " + err.Error())
+		}
+
+		// Warm MDBList ratings (disk-persisted) for all cached items so that
+		// sort-by-rating works immediately without per-request API calls.
+		s.warmRatingsForCachedItems(ctx)
+	}
 
 	s.cacheStatusMu.Lock()
 	s.cacheStatus.LastError = lastErr
 	s.cacheStatusMu.Unlock()
 }
 
+// uniqueDiscoverFilters drops filters that several profiles saved with the
+// same criteria; they share cache entries.
+func uniqueDiscoverFilters(filters []models.DiscoverFilter) []models.DiscoverFilter {
+	seen := make(map[string]bool, len(filters))
+	out := make([]models.DiscoverFilter, 0, len(filters))
+	for _, filter := range filters {
+		encoded, err := json.Marshal(filter)
+		if err != nil || seen[string(encoded)] {
+			continue
+		}
+		seen[string(encoded)] = true
+		out = append(out, filter)
+	}
+	return out
+}
+
+// warmProfileItemsWorkers caps concurrent detail fetches while warming
+// profile titles; most are cache hits after the first warm-up.
+const warmProfileItemsWorkers = 4
+
+// warmProfileItems fetches movie and series details for every title returned
+// by the warm items provider. Details land in the same cache entries the
+// watchlist and continue-watching handlers read artwork and overviews from.
+// TVDB aliases are pre-fetched too, since these are the titles most likely to
+// be played and playback resolution would otherwise fetch them on click.
+func (s *Service) warmProfileItems(ctx context.Context) error {
+	if s.warmItemsFn == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var items []WarmItem
+	for _, item := range s.warmItemsFn() {
+		var key string
+		switch {
+		case item.TVDBID > 0:
+			key = fmt.Sprintf("%s:tvdb:%d", item.MediaType, item.TVDBID)
+		case item.TMDBID > 0:
+			key = fmt.Sprintf("%s:tmdb:%d", item.MediaType, item.TMDBID)
+		case item.IMDBID != "":
+			key = item.MediaType + ":imdb:" + item.IMDBID
+		default:
+			continue // name-only titles are too ambiguous to warm
+		}
+		if (item.MediaType != "movie" && item.MediaType != "series") || seen[key] {
+			continue
+		}
+		seen[key] = true
+		items = append(items, item)
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	log.Printf("[metadata] cache manager: warming %d watchlist/continue-watching titles", len(items))
+	start := time.Now()
+	var failed atomic.Int32
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, warmProfileItemsWorkers)
+	for _, item := range items {
+		wg.Add(1)
+		go func(item WarmItem) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			var err error
+			tvdbID := item.TVDBID
+			if item.MediaType == "movie" {
+				var title *models.Title
+				title, err = s.MovieDetails(ctx, models.MovieDetailsQuery{
+					Name: item.Name, Year: item.Year, IMDBID: item.IMDBID, TMDBID: item.TMDBID, TVDBID: item.TVDBID,
+				})
+				if title != nil && title.TVDBID > 0 {
+					tvdbID = title.TVDBID
+				}
+			} else {
+				var details *models.SeriesDetails
+				details, err = s.SeriesDetails(ctx, models.SeriesDetailsQuery{
+					Name: item.Name, Year: item.Year, IMDBID: item.IMDBID, TMDBID: item.TMDBID, TVDBID: item.TVDBID,
+				})
+				if details != nil && details.Title.TVDBID > 0 {
+					tvdbID = details.Title.TVDBID
+				}
+			}
+			if err != nil {
+				failed.Add(1)
+				metadataTracef("[metadata] cache manager: warm %s %q failed: %v", item.MediaType, item.Name, err)
+			}
+			if tvdbID > 0 && ctx.Err() == nil {
+				s.prefetchAliases(item.MediaType, tvdbID)
+			}
+		}(item)
+	}
+	wg.Wait()
+
+	log.Printf("[metadata] cache manager: warmed %d profile titles (%d failed) in %s",
+		len(items)-int(failed.Load()), failed.Load(), time.Since(start).Round(time.Millisecond))
+	if n := failed.Load(); n > 0 {
+		return fmt.Errorf("%d of %d titles failed", n, len(items))
+	}
+	return nil
+}
+
 // warmRatingsForCachedItems fetches MDBList ratings for all items in the metadata cache
 // (trending lists, custom lists, etc.) that don't already have ratings on disk.
 func (s *Service) warmRatingsForCachedItems(ctx context.Context) {
@@ -866,18 +1440,13 @@ func (s *Service) warmRatingsForCachedItems(ctx context.Context) {
 		}
 	}
 
-	// Scan ALL cached list files in the metadata cache directory.
-	// Any file that deserialises as []TrendingItem with IMDB IDs is included.
+	// Scan ALL cached lists in the metadata cache.
+	// Any entry that deserialises as []TrendingItem with IMDB IDs is included.
 	// This covers trending, custom MDBList lists, streaming service lists,
 	// genre discovery, AI recommendations — anything the user has ever browsed.
-	entries, err := os.ReadDir(s.cache.dir)
+	keys, err := s.cache.keys()
 	if err == nil {
-		for _, entry := range entries {
-			if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
-				continue
-			}
-			// Strip the .json extension to get the cache key
-			key := strings.TrimSuffix(entry.Name(), ".json")
+		for _, key := range keys {
 			var items []models.TrendingItem
 			if ok, _ := s.cache.get(key, &items); ok && len(items) > 0 {
 				addItems(items)
@@ -1100,7 +1669,7 @@ func (s *Service) GetCachedArtworkURLs(mediaType string, tmdbID int64, tvdbID in
 	if mediaType == "movie" {
 		// Try TMDB-only cache first (used when no TVDB ID was available)
 		if tmdbID > 0 {
-			cacheID := cacheKey("tmdb", "movie", "details", "v3", s.client.language, strconv.FormatInt(tmdbID, 10))
+			cacheID := cacheKey("tmdb", "movie", "details", "v4", s.client.language, strconv.FormatInt(tmdbID, 10))
 			var cached models.Title
 			if ok, _ := s.cache.get(cacheID, &cached); ok {
 				mergeTitle(cached)
@@ -1122,7 +1691,7 @@ func (s *Service) GetCachedArtworkURLs(mediaType string, tmdbID int64, tvdbID in
 			}
 		}
 		if movieTVDBID > 0 {
-			cacheID := cacheKey("tvdb", "movie", "details", "v5", s.client.language, strconv.FormatInt(movieTVDBID, 10))
+			cacheID := cacheKey("tvdb", "movie", "details", "v6", s.client.language, strconv.FormatInt(movieTVDBID, 10))
 			var cached models.Title
 			if ok, _ := s.cache.get(cacheID, &cached); ok {
 				mergeTitle(cached)
@@ -1138,7 +1707,7 @@ func (s *Service) GetCachedArtworkURLs(mediaType string, tmdbID int64, tvdbID in
 			}
 		}
 		if seriesTVDBID > 0 {
-			cacheID := cacheKey("tvdb", "series", "details", "v10", s.client.language, strconv.FormatInt(seriesTVDBID, 10))
+			cacheID := cacheKey("tvdb", "series", "details", "v12", s.client.language, strconv.FormatInt(seriesTVDBID, 10))
 			var cached models.SeriesDetails
 			if ok, _ := s.cache.get(cacheID, &cached); ok {
 				mergeTitle(cached.Title)
@@ -1172,7 +1741,7 @@ func (s *Service) GetCachedOverview(mediaType string, tmdbID int64, tvdbID int64
 	overview := ""
 	if mediaType == "movie" {
 		if tmdbID > 0 {
-			cacheID := cacheKey("tmdb", "movie", "details", "v3", s.client.language, strconv.FormatInt(tmdbID, 10))
+			cacheID := cacheKey("tmdb", "movie", "details", "v4", s.client.language, strconv.FormatInt(tmdbID, 10))
 			var cached models.Title
 			if ok, _ := s.cache.get(cacheID, &cached); ok {
 				overview = mergeOverview(overview, cached.Overview)
@@ -1188,14 +1757,14 @@ func (s *Service) GetCachedOverview(mediaType string, tmdbID int64, tvdbID int64
 			}
 		}
 		if movieTVDBID > 0 {
-			cacheID := cacheKey("tvdb", "movie", "details", "v5", s.client.language, strconv.FormatInt(movieTVDBID, 10))
+			cacheID := cacheKey("tvdb", "movie", "details", "v6", s.client.language, strconv.FormatInt(movieTVDBID, 10))
 			var cached models.Title
 			if ok, _ := s.cache.get(cacheID, &cached); ok {
 				overview = mergeOverview(overview, cached.Overview)
 			}
 		}
 	} else if tvdbID > 0 {
-		cacheID := cacheKey("tvdb", "series", "details", "v10", s.client.language, strconv.FormatInt(tvdbID, 10))
+		cacheID := cacheKey("tvdb", "series", "details", "v12", s.client.language, strconv.FormatInt(tvdbID, 10))
 		var cached models.SeriesDetails
 		if ok, _ := s.cache.get(cacheID, &cached); ok {
 			overview = mergeOverview(overview, cached.Title.Overview)
@@ -1342,9 +1911,13 @@ func (s *Service) getTMDBIDForIMDBTV(ctx context.Context, imdbID string) int64 {
 	return tmdbID
 }
 
+// cacheKey builds a cache key from its parts. The key starts with the
+// entry's cache namespace and the short parts (IDs, languages, versions) so
+// entries can be listed and invalidated by namespace or title; the hash of
+// the full parts keeps it unique.
 func cacheKey(parts ...string) string {
 	h := sha1.Sum([]byte(strings.Join(parts, ":")))
-	return hex.EncodeToString(h[:])
+	return cacheKeyLabel(parts) + "_" + hex.EncodeToString(h[:])
 }
 
 // ShelfLoadOptions configures fast shelf rendering for list-style endpoints.
@@ -1386,22 +1959,7 @@ func (s *Service) TrendingWithOptions(ctx context.Context, mediaType string, opt
 		return items, nil
 	}
 
-	label := "series"
-	fetcher := s.getTrendingSeries
-	progressID := "trending-series"
-	progressLabel := "Trending TV"
-	if normalized == "movie" {
-		label = "movie"
-		fetcher = s.getRecentMovies
-		progressID = "trending-movie"
-		progressLabel = "Trending Movies"
-	}
-
-	cacheMode := "full"
-	if opts.Lite {
-		cacheMode = "fast"
-	}
-	key := cacheKey("mdblist", "trending", label, "v8", cacheMode, s.client.language)
+	key := s.trendingKey(normalized, opts)
 	// Use a detached context for enrichment so work completes even if the
 	// HTTP client disconnects — results are cached for future requests.
 	enrichCtx := context.Background()
@@ -1409,6 +1967,7 @@ func (s *Service) TrendingWithOptions(ctx context.Context, mediaType string, opt
 
 	var cached []models.TrendingItem
 	if ok, _ := s.cache.get(key, &cached); ok && len(cached) > 0 {
+		s.trendingSnapshots.put(key, cached, false)
 		// If any items are missing certifications, kick off enrichment in the
 		// background so the caller receives the cached list immediately rather
 		// than waiting for TMDB API calls. The updated list is written back to
@@ -1436,7 +1995,7 @@ func (s *Service) TrendingWithOptions(ctx context.Context, mediaType string, opt
 					} else {
 						s.enrichTrendingTVContentRatings(enrichCtx, toEnrich)
 					}
-					_ = s.cache.set(enrichKey, toEnrich)
+					s.storeTrending(enrichKey, toEnrich)
 				}()
 			}
 		}
@@ -1444,7 +2003,7 @@ func (s *Service) TrendingWithOptions(ctx context.Context, mediaType string, opt
 			genresUpdated := s.enrichLiteMissingGenres(ctx, cached)
 			s.enrichShelfArtwork(ctx, cached, artworkLimit)
 			if genresUpdated || artworkLimit > customListLiteArtworkLimit {
-				_ = s.cache.set(key, cached)
+				s.storeTrending(key, cached)
 			}
 		} else if opts.ArtworkLimit > 0 {
 			s.enrichShelfArtwork(ctx, cached, artworkLimit)
@@ -1452,8 +2011,57 @@ func (s *Service) TrendingWithOptions(ctx context.Context, mediaType string, opt
 		return cached, nil
 	}
 
-	// Cold cache — register progress task for the full fetch+enrich pipeline
-	cleanup := s.startProgressTask(progressID, progressLabel, "fetching", 0)
+	// The cached list expired: keep serving the last good one while a
+	// replacement is built in the background.
+	if stale, ok := s.trendingSnapshots.get(key); ok {
+		go func() {
+			_ = s.revalidateTrending(key, func() ([]models.TrendingItem, error) {
+				return s.buildTrending(enrichCtx, normalized, opts)
+			})
+		}()
+		if opts.ArtworkLimit > 0 {
+			s.enrichShelfArtwork(ctx, stale, artworkLimit)
+		}
+		return stale, nil
+	}
+
+	items, err := s.buildTrending(enrichCtx, normalized, opts)
+	if err != nil {
+		return nil, err
+	}
+	s.storeTrending(key, items)
+	return items, nil
+}
+
+// trendingKey returns the cache key TrendingWithOptions uses for a
+// normalized media type ("movie" or "tv").
+func (s *Service) trendingKey(normalized string, opts ShelfLoadOptions) string {
+	label := "series"
+	if normalized == "movie" {
+		label = "movie"
+	}
+	cacheMode := "full"
+	if opts.Lite {
+		cacheMode = "fast"
+	}
+	return trendingCacheKey(label, cacheMode, s.client.language)
+}
+
+// buildTrending fetches and enriches a trending list from scratch without
+// reading or writing the cache.
+func (s *Service) buildTrending(ctx context.Context, normalized string, opts ShelfLoadOptions) ([]models.TrendingItem, error) {
+	fetcher := s.getTrendingSeries
+	progressID := "trending-series"
+	progressLabel := "Trending TV"
+	if normalized == "movie" {
+		fetcher = s.getRecentMovies
+		progressID = "trending-movie"
+		progressLabel = "Trending Movies"
+	}
+	artworkLimit := shelfLoadArtworkLimit(opts)
+
+	// Register a progress task for the full fetch+enrich pipeline
+	ctx, cleanup := s.startProgressTask(ctx, progressID, progressLabel, "fetching", 0)
 	defer cleanup()
 
 	var items []models.TrendingItem
@@ -1474,14 +2082,15 @@ func (s *Service) TrendingWithOptions(ctx context.Context, mediaType string, opt
 		s.enrichShelfArtwork(ctx, items, artworkLimit)
 	} else if normalized == "movie" {
 		// Enrich movies with release data (theatrical/home release)
-		s.enrichTrendingMovieReleases(enrichCtx, items)
+		s.enrichTrendingMovieReleases(ctx, items)
 	} else {
 		// Enrich TV shows with content ratings
-		s.enrichTrendingTVContentRatings(enrichCtx, items)
-		s.enrichShelfArtwork(enrichCtx, items, artworkLimit)
+		s.enrichTrendingTVContentRatings(ctx, items)
+		s.enrichShelfArtwork(ctx, items, artworkLimit)
 	}
-	if len(items) > 0 {
-		_ = s.cache.set(key, items)
+	// A cancelled build is partially enriched; keep the previous list.
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 	return items, nil
 }
@@ -1540,8 +2149,8 @@ func (s *Service) enrichDemoArtwork(ctx context.Context, items []models.Trending
 // enrichTrendingMovieReleases adds release data (theatrical/home release) and runtime to trending movie items.
 // This runs concurrently for performance. Release data is cached by enrichMovieReleases.
 func (s *Service) enrichTrendingMovieReleases(ctx context.Context, items []models.TrendingItem) {
-	const maxConcurrent = 5
-	const enrichLimit = 200 // cap TMDB API calls per batch; remaining items enriched progressively
+	maxConcurrent := s.enrichWorkers(5)
+	enrichLimit := s.enrichBatchLimit()
 	sem := make(chan struct{}, maxConcurrent)
 	var wg sync.WaitGroup
 	var enrichedCount int32
@@ -1653,8 +2262,8 @@ func (s *Service) enrichTrendingMovieReleases(ctx context.Context, items []model
 
 // enrichTrendingTVContentRatings fetches TV content ratings for trending TV shows
 func (s *Service) enrichTrendingTVContentRatings(ctx context.Context, items []models.TrendingItem) {
-	const maxConcurrent = 5
-	const enrichLimit = 200 // cap TMDB API calls per batch; remaining items enriched progressively
+	maxConcurrent := s.enrichWorkers(5)
+	enrichLimit := s.enrichBatchLimit()
 	sem := make(chan struct{}, maxConcurrent)
 	var wg sync.WaitGroup
 	var enrichedCount int32
@@ -1782,6 +2391,11 @@ func (s *Service) searchDemo(ctx context.Context, query string, mediaType string
 	return results
 }
 
+// trendingCacheKey returns the cache key for an enriched trending list.
+func trendingCacheKey(label, cacheMode, language string) string {
+	return cacheKey("mdblist", "trending", label, "v8", cacheMode, language)
+}
+
 // getRecentMovies uses MDBList to get top movies of the week, enriched with TVDB data
 func (s *Service) getRecentMovies() ([]models.TrendingItem, error) {
 	// Fetch top movies from MDBList
@@ -1820,7 +2434,7 @@ func (s *Service) getRecentMovies() ([]models.TrendingItem, error) {
 
 	// Enrich with TVDB data concurrently (10 parallel workers)
 	s.updateProgressPhase("trending-movie", "enriching", len(items))
-	const maxConcurrent = 10
+	maxConcurrent := s.enrichWorkers(10)
 	sem := make(chan struct{}, maxConcurrent)
 	var wg sync.WaitGroup
 
@@ -1830,7 +2444,11 @@ func (s *Service) getRecentMovies() ([]models.TrendingItem, error) {
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
-			s.enrichMovieTVDB(&items[idx].Title, mdblistMovies[idx])
+			movie := mdblistMovies[idx]
+			item := mdblistItem{ID: movie.ID, Rank: movie.Rank, Title: movie.Title, TVDBID: movie.TVDBID, IMDBID: movie.IMDBID, MediaType: "movie", ReleaseYear: movie.ReleaseYear}
+			s.enrichTrendingTitle(&items[idx].Title, item, func() {
+				s.enrichMovieTVDB(&items[idx].Title, movie)
+			})
 			s.incrementProgress("trending-movie")
 		}(i)
 	}
@@ -1867,6 +2485,7 @@ func (s *Service) getRecentMoviesLite(ctx context.Context) ([]models.TrendingIte
 // enrichMovieTVDB enriches a single movie Title with TVDB artwork and metadata.
 func (s *Service) enrichMovieTVDB(title *models.Title, movie mdblistMovie) {
 	var found bool
+	var failReason string
 	var searchResult *tvdbSearchResult
 
 	if movie.TVDBID != nil && *movie.TVDBID > 0 {
@@ -1896,6 +2515,8 @@ func (s *Service) enrichMovieTVDB(title *models.Title, movie mdblistMovie) {
 				}
 			}
 			found = true
+		} else {
+			failReason = fmt.Sprintf("tvdb movie %d lookup failed: %v", *movie.TVDBID, err)
 		}
 	} else {
 		remoteID := fmt.Sprintf("%d", movie.ID)
@@ -1906,10 +2527,29 @@ func (s *Service) enrichMovieTVDB(title *models.Title, movie mdblistMovie) {
 			if searchResults, err := s.searchTVDBMovie(movie.Title, movie.ReleaseYear, ""); err == nil && len(searchResults) > 0 {
 				searchResult = &searchResults[0]
 				found = true
+			} else if err != nil {
+				failReason = fmt.Sprintf("tvdb search failed: %v", err)
+			} else {
+				failReason = "tvdb search returned no results"
 			}
 		}
 	}
 
+	item := mdblistItem{
+		ID:          movie.ID,
+		Rank:        movie.Rank,
+		Title:       movie.Title,
+		TVDBID:      movie.TVDBID,
+		IMDBID:      movie.IMDBID,
+		MediaType:   "movie",
+		ReleaseYear: movie.ReleaseYear,
+	}
+	if found {
+		s.resolveEnrichmentFailure(item)
+	} else {
+		s.recordEnrichmentFailure(item, EnrichmentSourceTrending, failReason, trendingCacheKey("movie", "full", s.client.language))
+	}
+
 	if found && searchResult != nil {
 		if searchResult.TVDBID != "" {
 			if tvdbID, err := strconv.ParseInt(searchResult.TVDBID, 10, 64); err == nil {
@@ -2018,7 +2658,7 @@ func (s *Service) getMovieDetailsFromTMDB(ctx context.Context, req models.MovieD
 	log.Printf("[metadata] fetching movie details from TMDB tmdbId=%d name=%q", req.TMDBID, req.Name)
 
 	// Check cache with TMDB key
-	cacheID := cacheKey("tmdb", "movie", "details", "v3", s.client.language, strconv.FormatInt(req.TMDBID, 10))
+	cacheID := cacheKey("tmdb", "movie", "details", "v4", s.client.language, strconv.FormatInt(req.TMDBID, 10))
 	var cached models.Title
 	if ok, _ := s.cache.get(cacheID, &cached); ok && cached.ID != "" {
 		metadataTracef("[metadata] movie details cache hit (TMDB) tmdbId=%d lang=%s", req.TMDBID, s.client.language)
@@ -2293,7 +2933,7 @@ func (s *Service) getTrendingSeries() ([]models.TrendingItem, error) {
 
 	// Enrich with TVDB data concurrently (10 parallel workers)
 	s.updateProgressPhase("trending-series", "enriching", len(items))
-	const maxConcurrent = 10
+	maxConcurrent := s.enrichWorkers(10)
 	sem := make(chan struct{}, maxConcurrent)
 	var wg sync.WaitGroup
 
@@ -2303,7 +2943,11 @@ func (s *Service) getTrendingSeries() ([]models.TrendingItem, error) {
 			defer wg.Done()
 			sem <- struct{}{}
 			defer func() { <-sem }()
-			s.enrichSeriesTVDB(&items[idx].Title, mdblistTVShows[idx])
+			show := mdblistTVShows[idx]
+			item := mdblistItem{ID: show.ID, Rank: show.Rank, Title: show.Title, TVDBID: show.TVDBID, IMDBID: show.IMDBID, MediaType: "show", ReleaseYear: show.ReleaseYear}
+			s.enrichTrendingTitle(&items[idx].Title, item, func() {
+				s.enrichSeriesTVDB(&items[idx].Title, show)
+			})
 			s.incrementProgress("trending-series")
 		}(i)
 	}
@@ -2482,7 +3126,7 @@ func (s *Service) Search(ctx context.Context, query string, mediaType string) ([
 	if mediaType == "" || mediaType == "all" {
 		movieResults, movieErr := s.Search(ctx, q, "movie")
 		seriesResults, seriesErr := s.Search(ctx, q, "series")
-		results := mergeSearchResults(append(movieResults, seriesResults...))
+		results := rankSearchResults(q, mergeSearchResults(append(movieResults, seriesResults...)))
 		if len(results) > 0 || movieErr == nil || seriesErr == nil {
 			return results, nil
 		}
@@ -2497,7 +3141,7 @@ func (s *Service) Search(ctx context.Context, query string, mediaType string) ([
 	if allowAdultSearch {
 		adultPolicy = "adult-allowed"
 	}
-	key := cacheKey("metadata", "search", "v6", mediaType, q, s.client.language, adultPolicy)
+	key := cacheKey("metadata", "search", "v7", mediaType, q, s.client.language, adultPolicy)
 	var cached []models.SearchResult
 	if ok, _ := s.cache.get(key, &cached); ok {
 		valid := false
@@ -2508,6 +3152,7 @@ func (s *Service) Search(ctx context.Context, query string, mediaType string) ([
 			}
 		}
 		if valid {
+			s.indexSearchResults(cached)
 			return cached, nil
 		}
 	}
@@ -2522,6 +3167,7 @@ func (s *Service) Search(ctx context.Context, query string, mediaType string) ([
 			Overview        string            `json:"overview"`
 			Overviews       map[string]string `json:"overviews"`
 			Translations    map[string]string `json:"translations"`
+			Aliases         []string          `json:"aliases"`
 			PrimaryLanguage string            `json:"primary_language"`
 			Year            string            `json:"year"`
 			FirstAirTime    string            `json:"first_air_time"`
@@ -2652,9 +3298,14 @@ func (s *Service) Search(ctx context.Context, query string, mediaType string) ([
 					addAlias(d.Translations[lang])
 				}
 			}
+			for _, alias := range d.Aliases {
+				addAlias(alias)
+			}
 			// Note: Skip fetching aliases here for faster search response.
-			// Aliases are already included from translations above.
-			// Full alias fetch happens during playback resolution when needed.
+			// The aliases and translations in the search record are included above.
+			// Full alias fetch happens during playback resolution when needed;
+			// the cache manager pre-fetches aliases for watchlist and
+			// continue-watching titles so those resolve without the wait.
 			if len(alternateTitles) > 0 {
 				title.AlternateTitles = alternateTitles
 			}
@@ -2712,8 +3363,9 @@ func (s *Service) Search(ctx context.Context, query string, mediaType string) ([
 			log.Printf("[metadata] TMDB search failed query=%q type=%s err=%v", q, mediaType, err)
 		}
 	}
+	results = append(results, s.searchMetadataProviders(ctx, q, mediaType)...)
 
-	results = mergeSearchResults(results)
+	results = mergeSearchResults(s.addFuzzyMatches(q, mediaType, results))
 	if !allowAdultSearch {
 		results = filterAdultSearchResults(results)
 	}
@@ -2721,7 +3373,9 @@ func (s *Service) Search(ctx context.Context, query string, mediaType string) ([
 		return nil, tvdbErr
 	}
 	s.enrichSearchResults(ctx, results)
+	results = rankSearchResults(q, results)
 	_ = s.cache.set(key, results)
+	s.indexSearchResults(results)
 	return results, nil
 }
 
@@ -2942,6 +3596,12 @@ func (s *Service) FetchAliasesWithLanguage(mediaType string, tvdbID int64) []mod
 	return s.fetchTVDBAliasesWithLanguage(mediaType, tvdbID)
 }
 
+// prefetchAliases warms both alias caches playback resolution reads from.
+func (s *Service) prefetchAliases(mediaType string, tvdbID int64) {
+	s.fetchTVDBAliases(mediaType, tvdbID)
+	s.fetchTVDBAliasesWithLanguage(mediaType, tvdbID)
+}
+
 func (s *Service) fetchTVDBAliasesWithLanguage(mediaType string, tvdbID int64) []models.LanguageAlias {
 	if s.client == nil || s.cache == nil || tvdbID <= 0 {
 		return nil
@@ -3025,6 +3685,8 @@ func (s *Service) fetchTVDBAliases(mediaType string, tvdbID int64) []string {
 }
 
 func (s *Service) resolveSeriesTVDBID(ctx context.Context, req models.SeriesDetailsQuery) (int64, error) {
+	req = s.applySeriesIdentityOverride(req)
+
 	// Fast path: if we already have the TVDB ID, return it
 	if req.TVDBID > 0 {
 		return req.TVDBID, nil
@@ -3310,13 +3972,16 @@ func normalizeTVDBImageURL(value string) string {
 	return tvdbArtworkBaseURL + "/" + strings.TrimPrefix(trimmed, "/")
 }
 
+// applyTVDBArtworks fills in a missing poster and backdrop, plus alternate
+// backdrops, from TVDB artwork. Artwork is taken in rankTVDBArtworks order so
+// the same title always gets the same images.
 func applyTVDBArtworks(title *models.Title, arts []tvdbArtwork) bool {
 	if title == nil {
 		return false
 	}
 	updated := false
 	const maxBackdrops = 5
-	for _, art := range arts {
+	for _, art := range rankTVDBArtworks(arts) {
 		normalized := normalizeTVDBImageURL(art.Image)
 		if normalized == "" {
 			continue
@@ -3342,6 +4007,19 @@ func applyTVDBArtworks(title *models.Title, arts []tvdbArtwork) bool {
 	return updated
 }
 
+// rankTVDBArtworks returns arts ordered best first by TVDB's community score,
+// falling back to artwork ID so ties don't depend on the API's ordering.
+func rankTVDBArtworks(arts []tvdbArtwork) []tvdbArtwork {
+	ranked := append([]tvdbArtwork(nil), arts...)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].ID < ranked[j].ID
+	})
+	return ranked
+}
+
 func artworkLooksLikePoster(art tvdbArtwork) bool {
 	lt := strings.ToLower(art.Type.String())
 	switch {
@@ -3411,10 +4089,37 @@ func (s *Service) tmdbSeriesDetailsFallback(ctx context.Context, req models.Seri
 	return details, nil
 }
 
+// SeriesDetails returns full series metadata including seasons and episodes.
+// The nextEpisode countdown is computed on every call so cached details never
+// serve a stale value.
 func (s *Service) SeriesDetails(ctx context.Context, req models.SeriesDetailsQuery) (*models.SeriesDetails, error) {
+	if ids, title, ok, err := s.providerDetails(ctx, req.TitleID); ok {
+		if err != nil {
+			return nil, err
+		}
+		if title != nil {
+			// Provider-only series have no season data.
+			return &models.SeriesDetails{Title: *title}, nil
+		}
+		req = providerSeriesQuery(req, ids)
+	}
+	details, err := s.seriesDetails(ctx, req)
+	if err != nil || details == nil {
+		return details, err
+	}
+	details.NextEpisode = computeNextEpisode(details, time.Now())
+	s.applyRegion(&details.Title)
+	s.applyWatchProviders(ctx, &details.Title)
+	s.applyArtworkPreferences(ctx, &details.Title)
+	s.applyTitleEdits(&details.Title)
+	return details, nil
+}
+
+func (s *Service) seriesDetails(ctx context.Context, req models.SeriesDetailsQuery) (*models.SeriesDetails, error) {
 	if s.client == nil {
 		return nil, fmt.Errorf("tvdb client not configured")
 	}
+	req = s.applySeriesIdentityOverride(req)
 
 	metadataTracef("[metadata] series details request titleId=%q name=%q year=%d tvdbId=%d",
 
@@ -3442,13 +4147,13 @@ func (s *Service) SeriesDetails(ctx context.Context, req models.SeriesDetailsQue
 		log.Printf("[metadata] series details resolve missing tvdbId titleId=%q name=%q year=%d",
 
 			strings.TrimSpace(req.TitleID), strings.TrimSpace(req.Name), req.Year)
-		if fallback, fallbackErr := s.tmdbSeriesDetailsFallback(ctx, req, fmt.Errorf("unable to resolve tvdb id for series")); fallbackErr == nil && fallback != nil {
+		if fallback, fallbackErr := s.tmdbSeriesDetailsFallback(ctx, req, newKindError(ErrNotFound, "unable to resolve tvdb id for series")); fallbackErr == nil && fallback != nil {
 			return fallback, nil
 		}
-		return nil, fmt.Errorf("unable to resolve tvdb id for series")
+		return nil, newKindError(ErrNotFound, "unable to resolve tvdb id for series")
 	}
 
-	cacheID := cacheKey("tvdb", "series", "details", "v10", s.client.language, strconv.FormatInt(tvdbID, 10))
+	cacheID := cacheKey("tvdb", "series", "details", "v12", s.client.language, strconv.FormatInt(tvdbID, 10))
 	var cached models.SeriesDetails
 	if ok, _ := s.cache.get(cacheID, &cached); ok && len(cached.Seasons) > 0 {
 		metadataTracef("[metadata] series details cache hit tvdbId=%d lang=%s seasons=%d hasPoster=%v hasBackdrop=%v",
@@ -3496,6 +4201,7 @@ func (s *Service) SeriesDetails(ctx context.Context, req models.SeriesDetailsQue
 			enrichGenres       []string
 			enrichCertOK       bool
 			enrichCert         string
+			enrichCertCountry  string
 			seasonTranslations map[int64]struct {
 				name     string
 				overview string
@@ -3544,6 +4250,7 @@ func (s *Service) SeriesDetails(ctx context.Context, req models.SeriesDetailsQue
 				if s.enrichTVContentRating(ctx, &titleCopy, cachedTMDBID) {
 					enrichCertOK = true
 					enrichCert = titleCopy.Certification
+					enrichCertCountry = titleCopy.CertificationCountry
 				}
 			}()
 		}
@@ -3572,7 +4279,7 @@ func (s *Service) SeriesDetails(ctx context.Context, req models.SeriesDetailsQue
 					wg.Add(1)
 					go func(seasonID int64) {
 						defer wg.Done()
-						translation, err := s.client.seasonTranslations(seasonID, s.client.language)
+						translation, err := s.cachedSeasonTranslations(seasonID, s.client.language)
 						if err != nil || translation == nil {
 							return
 						}
@@ -3618,6 +4325,7 @@ func (s *Service) SeriesDetails(ctx context.Context, req models.SeriesDetailsQue
 		}
 		if enrichCertOK {
 			cached.Title.Certification = enrichCert
+			cached.Title.CertificationCountry = enrichCertCountry
 			log.Printf("[metadata] content rating added to cached series tmdbId=%d rating=%s", cachedTMDBID, enrichCert)
 			cacheUpdated = true
 		}
@@ -3699,7 +4407,7 @@ func (s *Service) SeriesDetails(ctx context.Context, req models.SeriesDetailsQue
 		if strings.Contains(err.Error(), "404 Not Found") {
 			if altID := s.tryFallbackSeriesTVDBID(ctx, req, tvdbID); altID > 0 {
 				tvdbID = altID
-				cacheID = cacheKey("tvdb", "series", "details", "v10", s.client.language, strconv.FormatInt(tvdbID, 10))
+				cacheID = cacheKey("tvdb", "series", "details", "v12", s.client.language, strconv.FormatInt(tvdbID, 10))
 				base, err = s.getTVDBSeriesDetails(tvdbID)
 			}
 		}
@@ -3762,7 +4470,7 @@ func (s *Service) SeriesDetails(ctx context.Context, req models.SeriesDetailsQue
 			wg.Add(1)
 			go func(seasonID int64) {
 				defer wg.Done()
-				if translation, err := s.client.seasonTranslations(seasonID, s.client.language); err == nil && translation != nil {
+				if translation, err := s.cachedSeasonTranslations(seasonID, s.client.language); err == nil && translation != nil {
 					mu.Lock()
 					seasonTrans[seasonID] = translationResult{
 						name:     strings.TrimSpace(translation.Name),
@@ -4068,7 +4776,7 @@ func (s *Service) SeriesDetails(ctx context.Context, req models.SeriesDetailsQue
 		log.Printf("[metadata] using request TMDB ID for series enrichment tvdbId=%d tmdbId=%d", tvdbID, req.TMDBID)
 		details.Title = seriesTitle
 	}
-	if tmdbIDForEnrichment > 0 && s.preferTMDBEpisodeImages(ctx, &details, tmdbIDForEnrichment) {
+	if tmdbIDForEnrichment > 0 && s.preferTMDBEpisodeImages(ctx, &details, tmdbIDForEnrichment, true) {
 		log.Printf("[metadata] applied TMDB episode stills tvdbId=%d tmdbId=%d", tvdbID, tmdbIDForEnrichment)
 	}
 
@@ -4129,6 +4837,11 @@ func (s *Service) SeriesDetails(ctx context.Context, req models.SeriesDetailsQue
 		}
 	}
 
+	// Layer Fanart.tv clearlogo/banner on top according to the artwork provider priority
+	if s.applyFanartArtwork(ctx, &seriesTitle) {
+		details.Title = seriesTitle
+	}
+
 	// Fetch genres from TMDB if configured
 	if tmdbIDForEnrichment > 0 && s.tmdb != nil && s.tmdb.isConfigured() {
 		if genres, err := s.tmdb.fetchSeriesGenres(ctx, tmdbIDForEnrichment); err == nil && len(genres) > 0 {
@@ -4161,6 +4874,7 @@ func (s *Service) SeriesDetails(ctx context.Context, req models.SeriesDetailsQue
 		}
 	}
 
+	s.backfillAbsoluteEpisodeNumbers(ctx, &details, tmdbIDForEnrichment)
 	populateAiredDateTimeUTC(&details)
 
 	// If we fell back to a parent series (e.g. "Company Retreat" → "Jury Duty"),
@@ -4178,6 +4892,7 @@ func (s *Service) SeriesDetails(ctx context.Context, req models.SeriesDetailsQue
 		}
 	}
 
+	s.applyArtworkPlaceholders(ctx, &details.Title)
 	_ = s.cache.set(cacheID, details)
 
 	metadataTracef("[metadata] series details complete tvdbId=%d seasons=%d", tvdbID, len(seasons))
@@ -4185,7 +4900,12 @@ func (s *Service) SeriesDetails(ctx context.Context, req models.SeriesDetailsQue
 	return &details, nil
 }
 
-func (s *Service) preferTMDBEpisodeImages(ctx context.Context, details *models.SeriesDetails, tmdbID int64) bool {
+// preferTMDBEpisodeImages swaps in TMDB episode stills where TMDB has them
+// and attaches each episode's TMDB rating. With guestStars set it also copies
+// each episode's TMDB guest cast, which comes back in the same season
+// requests. Seasons are fetched through the per-season cache shared with
+// SeasonDetails.
+func (s *Service) preferTMDBEpisodeImages(ctx context.Context, details *models.SeriesDetails, tmdbID int64, guestStars bool) bool {
 	if details == nil || tmdbID <= 0 || s.tmdb == nil || !s.tmdb.isConfigured() || len(details.Seasons) == 0 {
 		return false
 	}
@@ -4193,6 +4913,8 @@ func (s *Service) preferTMDBEpisodeImages(ctx context.Context, details *models.S
 	type seasonImageResult struct {
 		seasonNumber int
 		images       map[int]models.Image
+		guestStars   map[int][]models.CastMember
+		ratings      map[int][]models.Rating
 		err          error
 	}
 
@@ -4206,8 +4928,6 @@ func (s *Service) preferTMDBEpisodeImages(ctx context.Context, details *models.S
 			continue
 		}
 		seasonNumber := season.Number
-		seasonName := season.Name
-		episodeCount := season.EpisodeCount
 		seasonCount++
 		wg.Add(1)
 		go func() {
@@ -4215,24 +4935,34 @@ func (s *Service) preferTMDBEpisodeImages(ctx context.Context, details *models.S
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			tmdbSeason, err := s.tmdb.seriesSeasonDetails(ctx, tmdbID, tmdbSeasonSummary{
-				Number:       seasonNumber,
-				Name:         seasonName,
-				EpisodeCount: episodeCount,
-			})
+			tmdbSeason, err := s.cachedFetchTMDBSeason(ctx, tmdbID, seasonNumber)
+			if err == nil && tmdbSeason == nil {
+				err = fmt.Errorf("empty season response")
+			}
 			if err != nil {
 				results <- seasonImageResult{seasonNumber: seasonNumber, err: err}
 				return
 			}
 
 			images := make(map[int]models.Image)
+			guests := make(map[int][]models.CastMember)
+			ratings := make(map[int][]models.Rating)
 			for _, episode := range tmdbSeason.Episodes {
-				if episode.EpisodeNumber <= 0 || episode.Image == nil || strings.TrimSpace(episode.Image.URL) == "" {
+				if episode.EpisodeNumber <= 0 {
+					continue
+				}
+				if len(episode.Ratings) > 0 {
+					ratings[episode.EpisodeNumber] = episode.Ratings
+				}
+				if guestStars && len(episode.GuestStars) > 0 {
+					guests[episode.EpisodeNumber] = episode.GuestStars
+				}
+				if episode.Image == nil || strings.TrimSpace(episode.Image.URL) == "" {
 					continue
 				}
 				images[episode.EpisodeNumber] = *episode.Image
 			}
-			results <- seasonImageResult{seasonNumber: seasonNumber, images: images}
+			results <- seasonImageResult{seasonNumber: seasonNumber, images: images, guestStars: guests, ratings: ratings}
 		}()
 	}
 
@@ -4246,6 +4976,8 @@ func (s *Service) preferTMDBEpisodeImages(ctx context.Context, details *models.S
 	}()
 
 	imagesBySeason := make(map[int]map[int]models.Image)
+	guestsBySeason := make(map[int]map[int][]models.CastMember)
+	ratingsBySeason := make(map[int]map[int][]models.Rating)
 	for result := range results {
 		if result.err != nil {
 			log.Printf("[metadata] TMDB episode image fetch failed tmdbId=%d season=%d err=%v", tmdbID, result.seasonNumber, result.err)
@@ -4254,19 +4986,32 @@ func (s *Service) preferTMDBEpisodeImages(ctx context.Context, details *models.S
 		if len(result.images) > 0 {
 			imagesBySeason[result.seasonNumber] = result.images
 		}
+		if len(result.guestStars) > 0 {
+			guestsBySeason[result.seasonNumber] = result.guestStars
+		}
+		if len(result.ratings) > 0 {
+			ratingsBySeason[result.seasonNumber] = result.ratings
+		}
 	}
-	if len(imagesBySeason) == 0 {
+	if len(imagesBySeason) == 0 && len(guestsBySeason) == 0 && len(ratingsBySeason) == 0 {
 		return false
 	}
 
 	changed := 0
 	for i := range details.Seasons {
 		seasonImages := imagesBySeason[details.Seasons[i].Number]
-		if len(seasonImages) == 0 {
-			continue
-		}
+		seasonGuests := guestsBySeason[details.Seasons[i].Number]
+		seasonRatings := ratingsBySeason[details.Seasons[i].Number]
 		for j := range details.Seasons[i].Episodes {
 			episode := &details.Seasons[i].Episodes[j]
+			if guests := seasonGuests[episode.EpisodeNumber]; len(guests) > 0 {
+				episode.GuestStars = guests
+				changed++
+			}
+			if ratings := seasonRatings[episode.EpisodeNumber]; len(ratings) > 0 {
+				episode.Ratings = ratings
+				changed++
+			}
 			tmdbImage, ok := seasonImages[episode.EpisodeNumber]
 			if !ok || strings.TrimSpace(tmdbImage.URL) == "" {
 				continue
@@ -4281,7 +5026,7 @@ func (s *Service) preferTMDBEpisodeImages(ctx context.Context, details *models.S
 	}
 
 	if changed > 0 {
-		log.Printf("[metadata] preferred TMDB episode stills tmdbId=%d changed=%d", tmdbID, changed)
+		log.Printf("[metadata] preferred TMDB episode stills, ratings and guest stars tmdbId=%d changed=%d", tmdbID, changed)
 	}
 	return changed > 0
 }
@@ -4308,13 +5053,23 @@ func populateAiredDateTimeUTC(details *models.SeriesDetails) {
 // SeriesDetailsLite is a lightweight variant of SeriesDetails optimised for
 // continue-watching and other contexts that only need poster, backdrop, overview,
 // IDs, year and a basic episode list (season/episode numbers + air dates).
-// It skips: getTVDBSeriesDetails, season translations, localized episode names,
+// It skips: getTVDBSeriesDetails, season translation fetches (translations
+// already cached by SeriesDetails are applied), localized episode names,
 // MDBList ratings, and non-artwork TMDB enrichment (credits, genres, content rating).
 // It uses a dedicated lite cache key so it can't overwrite the richer full-details cache.
 func (s *Service) SeriesDetailsLite(ctx context.Context, req models.SeriesDetailsQuery) (*models.SeriesDetails, error) {
+	details, err := s.seriesDetailsLite(ctx, req)
+	if details != nil {
+		s.applyTitleEdits(&details.Title)
+	}
+	return details, err
+}
+
+func (s *Service) seriesDetailsLite(ctx context.Context, req models.SeriesDetailsQuery) (*models.SeriesDetails, error) {
 	if s.client == nil {
 		return nil, fmt.Errorf("tvdb client not configured")
 	}
+	req = s.applySeriesIdentityOverride(req)
 
 	metadataTracef("[metadata] series details lite request titleId=%q name=%q year=%d tvdbId=%d",
 		strings.TrimSpace(req.TitleID), strings.TrimSpace(req.Name), req.Year, req.TVDBID)
@@ -4324,17 +5079,17 @@ func (s *Service) SeriesDetailsLite(ctx context.Context, req models.SeriesDetail
 		return nil, err
 	}
 	if tvdbID <= 0 {
-		return nil, fmt.Errorf("unable to resolve tvdb id for series")
+		return nil, newKindError(ErrNotFound, "unable to resolve tvdb id for series")
 	}
 
-	fullCacheID := cacheKey("tvdb", "series", "details", "v10", s.client.language, strconv.FormatInt(tvdbID, 10))
+	fullCacheID := cacheKey("tvdb", "series", "details", "v12", s.client.language, strconv.FormatInt(tvdbID, 10))
 	var fullCached models.SeriesDetails
 	if ok, _ := s.cache.get(fullCacheID, &fullCached); ok && len(fullCached.Seasons) > 0 {
 		log.Printf("[metadata] series details lite full-cache hit tvdbId=%d seasons=%d", tvdbID, len(fullCached.Seasons))
 		return &fullCached, nil
 	}
 
-	cacheID := cacheKey("tvdb", "series", "details", "v10-lite", s.client.language, strconv.FormatInt(tvdbID, 10))
+	cacheID := cacheKey("tvdb", "series", "details", "v11-lite", s.client.language, strconv.FormatInt(tvdbID, 10))
 	var cached models.SeriesDetails
 	if ok, _ := s.cache.get(cacheID, &cached); ok && len(cached.Seasons) > 0 {
 		log.Printf("[metadata] series details lite cache hit tvdbId=%d seasons=%d", tvdbID, len(cached.Seasons))
@@ -4379,7 +5134,7 @@ func (s *Service) SeriesDetailsLite(ctx context.Context, req models.SeriesDetail
 		if strings.Contains(extResult.err.Error(), "404 Not Found") {
 			if altID := s.tryFallbackSeriesTVDBID(ctx, req, tvdbID); altID > 0 {
 				tvdbID = altID
-				cacheID = cacheKey("tvdb", "series", "details", "v10", s.client.language, strconv.FormatInt(tvdbID, 10))
+				cacheID = cacheKey("tvdb", "series", "details", "v12", s.client.language, strconv.FormatInt(tvdbID, 10))
 				// Drain the translation channel from the failed ID
 				<-transChan
 				// Re-fetch with the correct ID
@@ -4531,6 +5286,14 @@ func (s *Service) SeriesDetailsLite(ctx context.Context, req models.SeriesDetail
 		if season.ID > 0 {
 			target.ID = fmt.Sprintf("tvdb:season:%d", season.ID)
 			target.TVDBID = season.ID
+			if trans := s.peekSeasonTranslations(season.ID, s.client.language); trans != nil {
+				if name := strings.TrimSpace(trans.Name); name != "" {
+					target.Name = name
+				}
+				if overview := strings.TrimSpace(trans.Overview); overview != "" {
+					target.Overview = overview
+				}
+			}
 		}
 		if season.Type.Name != "" {
 			target.Type = season.Type.Name
@@ -4628,7 +5391,7 @@ func (s *Service) SeriesDetailsLite(ctx context.Context, req models.SeriesDetail
 		seriesTitle.TMDBID = req.TMDBID
 		details.Title = seriesTitle
 	}
-	if tmdbIDForEnrichment > 0 && s.preferTMDBEpisodeImages(ctx, &details, tmdbIDForEnrichment) {
+	if tmdbIDForEnrichment > 0 && s.preferTMDBEpisodeImages(ctx, &details, tmdbIDForEnrichment, false) {
 		log.Printf("[metadata] lite: applied TMDB episode stills tvdbId=%d tmdbId=%d", tvdbID, tmdbIDForEnrichment)
 	}
 	if tmdbIDForEnrichment > 0 && s.tmdb != nil && s.tmdb.isConfigured() {
@@ -4661,6 +5424,8 @@ func (s *Service) SeriesDetailsLite(ctx context.Context, req models.SeriesDetail
 		}
 	}
 
+	// Lite skips the TMDB episode group lookup; gaps are only counted.
+	s.backfillAbsoluteEpisodeNumbers(ctx, &details, 0)
 	populateAiredDateTimeUTC(&details)
 
 	_ = s.cache.set(cacheID, details)
@@ -4701,10 +5466,11 @@ func (s *Service) BatchSeriesDetails(ctx context.Context, queries []models.Serie
 			continue
 		}
 
-		cacheID := cacheKey("tvdb", "series", "details", "v10", s.client.language, strconv.FormatInt(tvdbID, 10))
+		cacheID := cacheKey("tvdb", "series", "details", "v12", s.client.language, strconv.FormatInt(tvdbID, 10))
 		var cached models.SeriesDetails
 		if ok, _ := s.cache.get(cacheID, &cached); ok && len(cached.Seasons) > 0 {
 			log.Printf("[metadata] batch series cache hit index=%d tvdbId=%d name=%q", i, tvdbID, query.Name)
+			cached.NextEpisode = computeNextEpisode(&cached, time.Now())
 			results[i].Details = &cached
 		} else {
 			// Need to fetch this one
@@ -4722,7 +5488,7 @@ func (s *Service) BatchSeriesDetails(ctx context.Context, queries []models.Serie
 		len(queries)-len(tasksToFetch), len(tasksToFetch), len(queries))
 
 	// Second pass: fetch uncached items concurrently with controlled parallelism
-	const maxConcurrent = 5
+	maxConcurrent := s.enrichWorkers(5)
 	sem := make(chan struct{}, maxConcurrent)
 	var wg sync.WaitGroup
 
@@ -4748,6 +5514,11 @@ func (s *Service) BatchSeriesDetails(ctx context.Context, queries []models.Serie
 	}
 
 	wg.Wait()
+	for i := range results {
+		if results[i].Details != nil {
+			s.applyTitleEdits(&results[i].Details.Title)
+		}
+	}
 	log.Printf("[metadata] batch series complete total=%d", len(queries))
 	return results
 }
@@ -4778,6 +5549,7 @@ func extractTitleFields(full *models.Title, fields []string) models.Title {
 			out.AirsTimezone = full.AirsTimezone
 		case "certification":
 			out.Certification = full.Certification
+			out.CertificationCountry = full.CertificationCountry
 		case "language":
 			out.Language = full.Language
 		case "popularity":
@@ -4850,7 +5622,7 @@ func (s *Service) BatchSeriesTitleFields(ctx context.Context, queries []models.S
 		}
 
 		// Check the full SeriesDetails cache
-		cacheID := cacheKey("tvdb", "series", "details", "v10", s.client.language, strconv.FormatInt(tvdbID, 10))
+		cacheID := cacheKey("tvdb", "series", "details", "v12", s.client.language, strconv.FormatInt(tvdbID, 10))
 		var cached models.SeriesDetails
 		if ok, _ := s.cache.get(cacheID, &cached); ok {
 			extracted := extractTitleFields(&cached.Title, fields)
@@ -4884,7 +5656,7 @@ func (s *Service) BatchSeriesTitleFields(ctx context.Context, queries []models.S
 	// Second pass: fetch uncached items. SeriesDetailsLite is used when TMDB
 	// image fields are requested so the response can include text posters/logos
 	// without returning full season/episode payloads.
-	const maxConcurrent = 5
+	maxConcurrent := s.enrichWorkers(5)
 	sem := make(chan struct{}, maxConcurrent)
 	var wg sync.WaitGroup
 
@@ -4917,6 +5689,11 @@ func (s *Service) BatchSeriesTitleFields(ctx context.Context, queries []models.S
 	}
 
 	wg.Wait()
+	for i := range results {
+		if results[i].Details != nil {
+			s.applyTitleEdits(&results[i].Details.Title)
+		}
+	}
 	log.Printf("[metadata] batch series fields complete total=%d", len(queries))
 	return results
 }
@@ -5024,9 +5801,22 @@ func (s *Service) BatchMovieReleases(ctx context.Context, queries []models.Batch
 // SeriesInfo fetches lightweight series metadata (poster, backdrop, external IDs) without episodes.
 // This is useful for continue watching where we only need series-level metadata.
 func (s *Service) SeriesInfo(ctx context.Context, req models.SeriesDetailsQuery) (*models.Title, error) {
+	if ids, title, ok, err := s.providerDetails(ctx, req.TitleID); ok {
+		if err != nil || title != nil {
+			return title, err
+		}
+		req = providerSeriesQuery(req, ids)
+	}
+	title, err := s.seriesInfo(ctx, req)
+	s.applyTitleEdits(title)
+	return title, err
+}
+
+func (s *Service) seriesInfo(ctx context.Context, req models.SeriesDetailsQuery) (*models.Title, error) {
 	if s.client == nil {
 		return nil, fmt.Errorf("tvdb client not configured")
 	}
+	req = s.applySeriesIdentityOverride(req)
 
 	log.Printf("[metadata] series info request (lightweight) titleId=%q name=%q year=%d tvdbId=%d",
 		strings.TrimSpace(req.TitleID), strings.TrimSpace(req.Name), req.Year, req.TVDBID)
@@ -5040,7 +5830,7 @@ func (s *Service) SeriesInfo(ctx context.Context, req models.SeriesDetailsQuery)
 	if tvdbID <= 0 {
 		log.Printf("[metadata] series info resolve missing tvdbId titleId=%q name=%q year=%d",
 			strings.TrimSpace(req.TitleID), strings.TrimSpace(req.Name), req.Year)
-		return nil, fmt.Errorf("unable to resolve tvdb id for series")
+		return nil, newKindError(ErrNotFound, "unable to resolve tvdb id for series")
 	}
 
 	// Check cache first
@@ -5049,6 +5839,7 @@ func (s *Service) SeriesInfo(ctx context.Context, req models.SeriesDetailsQuery)
 	if ok, _ := s.cache.get(cacheID, &cached); ok {
 		log.Printf("[metadata] series info cache hit tvdbId=%d lang=%s hasPoster=%v hasBackdrop=%v",
 			tvdbID, s.client.language, cached.Poster != nil, cached.Backdrop != nil)
+		cached.NextEpisode = s.cachedNextEpisode(tvdbID)
 		return &cached, nil
 	}
 
@@ -5161,27 +5952,71 @@ func (s *Service) SeriesInfo(ctx context.Context, req models.SeriesDetailsQuery)
 	// Cache the result
 	_ = s.cache.set(cacheID, seriesTitle)
 
+	seriesTitle.NextEpisode = s.cachedNextEpisode(tvdbID)
 	return &seriesTitle, nil
 }
 
+// cachedNextEpisode derives the next-episode countdown for SeriesInfo from the
+// cached full series details. SeriesInfo never fetches episodes itself, so the
+// field is only populated once SeriesDetails has been loaded for the series.
+func (s *Service) cachedNextEpisode(tvdbID int64) *models.NextEpisode {
+	if tvdbID <= 0 || s.client == nil {
+		return nil
+	}
+	var details models.SeriesDetails
+	cacheID := cacheKey("tvdb", "series", "details", "v12", s.client.language, strconv.FormatInt(tvdbID, 10))
+	if ok, _ := s.cache.get(cacheID, &details); !ok || len(details.Seasons) == 0 {
+		return nil
+	}
+	return computeNextEpisode(&details, time.Now())
+}
+
 // MovieInfo fetches lightweight movie metadata (poster, backdrop, external IDs) without ratings.
 // This is useful for continue watching where we only need basic movie info.
 func (s *Service) MovieInfo(ctx context.Context, req models.MovieDetailsQuery) (*models.Title, error) {
 	// Use MovieDetails but skip ratings by calling the internal implementation
-	return s.movieDetailsInternal(ctx, req, false)
+	title, err := s.movieDetailsInternal(ctx, req, false)
+	s.applyRegion(title)
+	s.applyTitleEdits(title)
+	return title, err
 }
 
 // MovieDetails fetches metadata for a movie including poster, backdrop, and ratings.
 func (s *Service) MovieDetails(ctx context.Context, req models.MovieDetailsQuery) (*models.Title, error) {
-	return s.movieDetailsInternal(ctx, req, true)
+	if ids, title, ok, err := s.providerDetails(ctx, req.TitleID); ok {
+		if err != nil || title != nil {
+			return title, err
+		}
+		req = models.MovieDetailsQuery{Name: req.Name, Year: req.Year, IMDBID: ids.IMDBID, TMDBID: ids.TMDBID, TVDBID: ids.TVDBID}
+	}
+	title, err := s.movieDetailsInternal(ctx, req, true)
+	s.applyRegion(title)
+	s.applyWatchProviders(ctx, title)
+	s.applyArtworkPreferences(ctx, title)
+	s.applyTitleEdits(title)
+	return title, err
 }
 
 // CollectionDetails fetches details for a movie collection from TMDB.
+// Results are cached since the collection shelf looks up every collection the
+// profile has started.
 func (s *Service) CollectionDetails(ctx context.Context, collectionID int64) (*models.CollectionDetails, error) {
 	if s.tmdb == nil || !s.tmdb.isConfigured() {
 		return nil, fmt.Errorf("tmdb client not configured")
 	}
-	return s.tmdb.fetchCollectionDetails(ctx, collectionID)
+
+	cacheID := cacheKey("tmdb", "collection", "v1", fmt.Sprintf("%d", collectionID), strings.TrimSpace(s.tmdb.language))
+	var cached models.CollectionDetails
+	if ok, _ := s.cache.get(cacheID, &cached); ok && cached.ID > 0 {
+		return &cached, nil
+	}
+
+	details, err := s.tmdb.fetchCollectionDetails(ctx, collectionID)
+	if err != nil {
+		return nil, err
+	}
+	_ = s.cache.set(cacheID, details)
+	return details, nil
 }
 
 // Similar fetches similar movies or TV shows from TMDB.
@@ -5315,8 +6150,44 @@ func (s *Service) DiscoverByDecadeWithOptions(ctx context.Context, mediaType str
 		})
 }
 
+// DiscoverWithFilter returns TMDB discover results matching a saved discover
+// filter. The filter's media type overrides the one passed to the shelf
+// loader, so a saved "90s anime movies" set never returns series. Watch
+// provider filters without a region use the watch provider country.
+func (s *Service) DiscoverWithFilter(ctx context.Context, filter models.DiscoverFilter, limit, offset int, opts ShelfLoadOptions) ([]models.TrendingItem, int, error) {
+	filter.GenreIDs = append([]int64(nil), filter.GenreIDs...)
+	sort.Slice(filter.GenreIDs, func(i, j int) bool { return filter.GenreIDs[i] < filter.GenreIDs[j] })
+	filter.KeywordIDs = append([]int64(nil), filter.KeywordIDs...)
+	sort.Slice(filter.KeywordIDs, func(i, j int) bool { return filter.KeywordIDs[i] < filter.KeywordIDs[j] })
+	filter.CastIDs = append([]int64(nil), filter.CastIDs...)
+	sort.Slice(filter.CastIDs, func(i, j int) bool { return filter.CastIDs[i] < filter.CastIDs[j] })
+	filter.WatchProviderIDs = append([]int64(nil), filter.WatchProviderIDs...)
+	sort.Slice(filter.WatchProviderIDs, func(i, j int) bool { return filter.WatchProviderIDs[i] < filter.WatchProviderIDs[j] })
+	if len(filter.WatchProviderIDs) > 0 {
+		if strings.TrimSpace(filter.WatchRegion) == "" {
+			filter.WatchRegion = s.watchProviderCountry()
+		}
+		filter.WatchRegion = normalizeWatchProviderRegion(filter.WatchRegion)
+	} else {
+		filter.WatchRegion = ""
+	}
+	if filter.YearFrom > 0 && filter.YearTo > 0 && filter.YearFrom > filter.YearTo {
+		return nil, 0, fmt.Errorf("invalid year range")
+	}
+	encoded, err := json.Marshal(filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("encode discover filter: %w", err)
+	}
+	return s.discoverShelfWithOptions(ctx, filter.MediaType, limit, offset, opts,
+		"filter",
+		[]string{"filter", "v1", string(encoded)},
+		func(normalizedType string, page int) ([]models.Title, int, error) {
+			return s.tmdb.discoverByFilter(ctx, normalizedType, filter, page)
+		})
+}
+
 // discoverShelfWithOptions implements the shared paging/caching logic for the
-// TMDB discover-backed shelves (genre, decade).
+// TMDB discover-backed shelves (genre, decade, saved filters).
 func (s *Service) discoverShelfWithOptions(ctx context.Context, mediaType string, limit, offset int, opts ShelfLoadOptions, logLabel string, cacheKeyParts []string, fetch func(normalizedType string, page int) ([]models.Title, int, error)) ([]models.TrendingItem, int, error) {
 	start := time.Now()
 	if s.tmdb == nil || !s.tmdb.isConfigured() {
@@ -5453,9 +6324,48 @@ func discoverTitleDedupeKey(mediaType string, title models.Title) string {
 	return fmt.Sprintf("title:%s:%s:%d", mediaType, name, title.Year)
 }
 
+// aiTitleRef is the cached TMDB match for an AI suggestion.
+type aiTitleRef struct {
+	TMDBID    int64  `json:"tmdbId"`
+	MediaType string `json:"mediaType"`
+}
+
+// resolveAITitle resolves an AI suggestion (title, year, type) to a TMDB title.
+// The TMDB ID match is kept in the long-lived ID cache so common suggestions
+// skip the search on later calls; the title itself is then loaded by ID, which
+// for movies is usually already cached from other shelves.
+func (s *Service) resolveAITitle(ctx context.Context, name string, year int, apiType string) (*models.Title, error) {
+	idKey := cacheKey("id", "ai-title-to-tmdb", apiType, strings.ToLower(strings.TrimSpace(name)), strconv.Itoa(year))
+	var ref aiTitleRef
+	if ok, _ := s.idCache.get(idKey, &ref); ok && ref.TMDBID > 0 {
+		var title *models.Title
+		var err error
+		if ref.MediaType == "series" {
+			title, err = s.tmdb.seriesDetails(ctx, ref.TMDBID)
+		} else {
+			title, err = s.tmdb.movieDetails(ctx, ref.TMDBID)
+		}
+		if err == nil && title != nil {
+			return title, nil
+		}
+		log.Printf("[metadata] AI rec %q: cached tmdb id %d unusable, searching again: %v", name, ref.TMDBID, err)
+	}
+
+	title, err := s.tmdb.searchByTitle(ctx, name, year, apiType)
+	if err != nil || title == nil {
+		return title, err
+	}
+	ref = aiTitleRef{TMDBID: title.TMDBID, MediaType: title.MediaType}
+	if err := s.idCache.set(idKey, ref); err != nil {
+		log.Printf("[metadata] failed to cache AI title resolution: %v", err)
+	}
+	return title, nil
+}
+
 // GetAIRecommendations generates personalized recommendations using the configured AI provider
 // based on the user's watched titles. Results are cached for 24 hours per user.
-func (s *Service) GetAIRecommendations(ctx context.Context, watchedTitles []string, mediaTypes []string, userID string) ([]models.TrendingItem, error) {
+// Reasons and "because you watched" titles are only generated when explain is set.
+func (s *Service) GetAIRecommendations(ctx context.Context, watchedTitles []string, mediaTypes []string, userID string, explain bool) ([]models.TrendingItem, error) {
 	if s.ai == nil || !s.ai.isConfigured() {
 		return nil, fmt.Errorf("AI provider API key not configured")
 	}
@@ -5467,7 +6377,7 @@ func (s *Service) GetAIRecommendations(ctx context.Context, watchedTitles []stri
 	providerLabel := s.ai.providerLabel()
 
 	// Get fresh recommendations from the configured AI provider (no cache — each request should feel unique)
-	recs, err := s.ai.getRecommendations(ctx, watchedTitles, mediaTypes)
+	recs, err := s.ai.getRecommendations(ctx, watchedTitles, mediaTypes, explain)
 	if err != nil {
 		return nil, fmt.Errorf("%s recommendations: %w", providerLabel, err)
 	}
@@ -5490,7 +6400,7 @@ func (s *Service) GetAIRecommendations(ctx context.Context, watchedTitles []stri
 			apiType = "tv"
 		}
 
-		title, err := s.tmdb.searchByTitle(ctx, rec.Title, rec.Year, apiType)
+		title, err := s.resolveAITitle(ctx, rec.Title, rec.Year, apiType)
 		if err != nil {
 			log.Printf("[metadata] AI rec %q: tmdb search failed: %v", rec.Title, err)
 			continue
@@ -5512,8 +6422,10 @@ func (s *Service) GetAIRecommendations(ctx context.Context, watchedTitles []stri
 
 		rank++
 		items = append(items, models.TrendingItem{
-			Rank:  rank,
-			Title: *title,
+			Rank:              rank,
+			Title:             *title,
+			Reason:            aiRecommendationReason(rec.Reason),
+			BecauseYouWatched: matchWatchedTitle(rec.BecauseOf, watchedTitles),
 		})
 	}
 
@@ -5525,6 +6437,40 @@ func (s *Service) GetAIRecommendations(ctx context.Context, watchedTitles []stri
 	return items, nil
 }
 
+// aiRecommendationReasonMax caps the length of a recommendation's reason,
+// in runes, in case the model ignores the prompt's word limit.
+const aiRecommendationReasonMax = 160
+
+// aiRecommendationReason tidies the reason the AI gave for a recommendation
+// for display on a shelf.
+func aiRecommendationReason(reason string) string {
+	reason = strings.Join(strings.Fields(reason), " ")
+	if runes := []rune(reason); len(runes) > aiRecommendationReasonMax {
+		cut := string(runes[:aiRecommendationReasonMax])
+		if i := strings.LastIndex(cut, " "); i > 0 {
+			cut = cut[:i]
+		}
+		reason = strings.TrimRight(cut, " ,;:.-") + "…"
+	}
+	return reason
+}
+
+// matchWatchedTitle returns the watched title the AI named as the source of
+// a recommendation, as the user's history spells it, or "" when the AI
+// named something the user hasn't watched.
+func matchWatchedTitle(name string, watchedTitles []string) string {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ""
+	}
+	for _, watched := range watchedTitles {
+		if strings.EqualFold(strings.TrimSpace(watched), name) {
+			return watched
+		}
+	}
+	return ""
+}
+
 // GetAISimilar generates recommendations similar to a specific title using the configured AI provider.
 func (s *Service) GetAISimilar(ctx context.Context, seedTitle string, mediaType string) ([]models.TrendingItem, error) {
 	if s.ai == nil || !s.ai.isConfigured() {
@@ -5557,7 +6503,7 @@ func (s *Service) GetAISimilar(ctx context.Context, seedTitle string, mediaType
 			apiType = "tv"
 		}
 
-		title, err := s.tmdb.searchByTitle(ctx, rec.Title, rec.Year, apiType)
+		title, err := s.resolveAITitle(ctx, rec.Title, rec.Year, apiType)
 		if err != nil || title == nil {
 			continue
 		}
@@ -5616,7 +6562,7 @@ func (s *Service) GetAICustomRecommendations(ctx context.Context, query string)
 			apiType = "tv"
 		}
 
-		title, err := s.tmdb.searchByTitle(ctx, rec.Title, rec.Year, apiType)
+		title, err := s.resolveAITitle(ctx, rec.Title, rec.Year, apiType)
 		if err != nil || title == nil {
 			continue
 		}
@@ -5662,7 +6608,7 @@ func (s *Service) GetAISurprise(ctx context.Context, decade, mediaType string) (
 		if apiType == "series" {
 			apiType = "tv"
 		}
-		title, err := s.tmdb.searchByTitle(ctx, rec.Title, rec.Year, apiType)
+		title, err := s.resolveAITitle(ctx, rec.Title, rec.Year, apiType)
 		if err != nil || title == nil {
 			continue
 		}
@@ -5780,6 +6726,7 @@ func (s *Service) movieDetailsInternal(ctx context.Context, req models.MovieDeta
 	if s.client == nil {
 		return nil, fmt.Errorf("tvdb client not configured")
 	}
+	req = s.applyMovieIdentityOverride(req)
 
 	metadataTracef("[metadata] movie details request titleId=%q name=%q year=%d tvdbId=%d tmdbId=%d imdbId=%s",
 		strings.TrimSpace(req.TitleID), strings.TrimSpace(req.Name), req.Year, req.TVDBID, req.TMDBID, strings.TrimSpace(req.IMDBID))
@@ -5857,11 +6804,11 @@ func (s *Service) movieDetailsInternal(ctx context.Context, req models.MovieDeta
 			return s.getMovieDetailsFromTMDB(ctx, req)
 		}
 
-		return nil, fmt.Errorf("unable to resolve tvdb id for movie and no tmdb fallback available")
+		return nil, newKindError(ErrNotFound, "unable to resolve tvdb id for movie and no tmdb fallback available")
 	}
 
 	// Check cache.
-	cacheID := cacheKey("tvdb", "movie", "details", "v5", s.client.language, strconv.FormatInt(tvdbID, 10))
+	cacheID := cacheKey("tvdb", "movie", "details", "v6", s.client.language, strconv.FormatInt(tvdbID, 10))
 	var cached models.Title
 	if ok, _ := s.cache.get(cacheID, &cached); ok && cached.ID != "" {
 		metadataTracef("[metadata] movie details cache hit tvdbId=%d lang=%s", tvdbID, s.client.language)
@@ -6145,6 +7092,10 @@ func (s *Service) movieDetailsInternal(ctx context.Context, req models.MovieDeta
 
 	enrichWg.Wait()
 
+	// 5. Fanart.tv clearlogo/banner/disc art, applied after TMDB so priority can compare logos
+	s.applyFanartArtwork(ctx, &movieTitle)
+	s.applyArtworkPlaceholders(ctx, &movieTitle)
+
 	// Cache the result
 	_ = s.cache.set(cacheID, movieTitle)
 
@@ -6242,7 +7193,17 @@ func (s *Service) maybeHydrateOverviewFromTMDB(ctx context.Context, title *model
 // cachedReleasesWithCert is the cached structure for movie releases including certification
 type cachedReleasesWithCert struct {
 	Releases      []models.Release `json:"releases"`
-	Certification string           `json:"certification"`
+	Certification string           `json:"certification"` // US rating
+	// Certifications holds every country's rating. Entries written before it
+	// existed only carry the US Certification.
+	Certifications map[string]string `json:"certifications,omitempty"`
+}
+
+func (c cachedReleasesWithCert) certifications() map[string]string {
+	if len(c.Certifications) > 0 {
+		return c.Certifications
+	}
+	return map[string]string{"US": c.Certification}
 }
 
 func (s *Service) enrichMovieReleases(ctx context.Context, title *models.Title, tmdbID int64) bool {
@@ -6259,7 +7220,7 @@ func (s *Service) enrichMovieReleases(ctx context.Context, title *models.Title,
 			return false
 		}
 		title.Releases = append([]models.Release(nil), cached.Releases...)
-		title.Certification = cached.Certification
+		s.setTitleCertification(title, cached.certifications())
 		s.ensureMovieReleasePointers(title)
 		return true
 	}
@@ -6276,11 +7237,12 @@ func (s *Service) enrichMovieReleases(ctx context.Context, title *models.Title,
 	}
 
 	title.Releases = append([]models.Release(nil), result.Releases...)
-	title.Certification = result.Certification
+	s.setTitleCertification(title, result.Certifications)
 	s.ensureMovieReleasePointers(title)
 	_ = s.cache.set(cacheID, cachedReleasesWithCert{
-		Releases:      title.Releases,
-		Certification: title.Certification,
+		Releases:       title.Releases,
+		Certification:  result.Certifications["US"],
+		Certifications: result.Certifications,
 	})
 
 	return true
@@ -6295,23 +7257,23 @@ func (s *Service) enrichTVContentRating(ctx context.Context, title *models.Title
 		return false // Already has a rating
 	}
 
-	cacheID := cacheKey("tmdb", "tv", "content_rating", "v1", strconv.FormatInt(tmdbID, 10))
-	var cached string
+	cacheID := cacheKey("tmdb", "tv", "content_rating", "v2", strconv.FormatInt(tmdbID, 10))
+	var cached map[string]string
 	if ok, _ := s.cache.get(cacheID, &cached); ok {
-		title.Certification = cached
-		return cached != ""
+		s.setTitleCertification(title, cached)
+		return title.Certification != ""
 	}
 
-	rating, err := s.tmdb.fetchTVContentRating(ctx, tmdbID)
+	ratings, err := s.tmdb.fetchTVContentRatings(ctx, tmdbID)
 	if err != nil {
 		log.Printf("[metadata] WARN: tmdb tv content rating fetch failed tmdbId=%d err=%v", tmdbID, err)
 		return false
 	}
 
-	title.Certification = rating
-	_ = s.cache.set(cacheID, rating)
+	s.setTitleCertification(title, ratings)
+	_ = s.cache.set(cacheID, ratings)
 
-	return rating != ""
+	return title.Certification != ""
 }
 
 // enrichTitleCertification populates a single title's certification (content
@@ -6348,7 +7310,7 @@ func (s *Service) EnrichSearchCertifications(ctx context.Context, results []mode
 		return
 	}
 
-	const maxConcurrent = 5
+	maxConcurrent := s.enrichWorkers(5)
 	sem := make(chan struct{}, maxConcurrent)
 	var wg sync.WaitGroup
 
@@ -6376,7 +7338,7 @@ func (s *Service) EnrichTrendingCertifications(ctx context.Context, items []mode
 		return
 	}
 
-	const maxConcurrent = 5
+	maxConcurrent := s.enrichWorkers(5)
 	sem := make(chan struct{}, maxConcurrent)
 	var wg sync.WaitGroup
 
@@ -6402,7 +7364,7 @@ func (s *Service) EnrichTitleCertifications(ctx context.Context, titles []models
 		return
 	}
 
-	const maxConcurrent = 5
+	maxConcurrent := s.enrichWorkers(5)
 	sem := make(chan struct{}, maxConcurrent)
 	var wg sync.WaitGroup
 
@@ -6421,6 +7383,10 @@ func (s *Service) EnrichTitleCertifications(ctx context.Context, titles []models
 	wg.Wait()
 }
 
+// ensureMovieReleasePointers marks the primary theatrical and home releases.
+// When a certification country is configured, its releases are preferred,
+// then US releases, then the earliest anywhere; otherwise the earliest
+// release worldwide is used.
 func (s *Service) ensureMovieReleasePointers(title *models.Title) {
 	if title == nil {
 		return
@@ -6432,6 +7398,46 @@ func (s *Service) ensureMovieReleasePointers(title *models.Title) {
 		return
 	}
 
+	for i := range title.Releases {
+		title.Releases[i].Primary = false
+	}
+
+	var tiers []string
+	if country := s.certificationCountry(); country != "" {
+		tiers = append(tiers, country)
+		if country != "US" {
+			tiers = append(tiers, "US")
+		}
+	}
+	tiers = append(tiers, "") // any country
+
+	bestTheatricalIdx, bestHomeIdx := -1, -1
+	for _, country := range tiers {
+		theatricalIdx, homeIdx := bestReleaseIndexes(title.Releases, country)
+		if bestTheatricalIdx == -1 {
+			bestTheatricalIdx = theatricalIdx
+		}
+		if bestHomeIdx == -1 {
+			bestHomeIdx = homeIdx
+		}
+	}
+
+	title.Theatrical = nil
+	title.HomeRelease = nil
+
+	if bestTheatricalIdx >= 0 {
+		title.Releases[bestTheatricalIdx].Primary = true
+		title.Theatrical = &title.Releases[bestTheatricalIdx]
+	}
+	if bestHomeIdx >= 0 {
+		title.Releases[bestHomeIdx].Primary = true
+		title.HomeRelease = &title.Releases[bestHomeIdx]
+	}
+}
+
+// bestReleaseIndexes returns the indexes of the best theatrical and home
+// releases in country (any country when empty), or -1 when there is none.
+func bestReleaseIndexes(releases []models.Release, country string) (int, int) {
 	var (
 		bestTheatricalIdx = -1
 		bestTheatricalTS  time.Time
@@ -6442,9 +7448,11 @@ func (s *Service) ensureMovieReleasePointers(title *models.Title) {
 		bestHomePri = math.MaxInt32
 	)
 
-	for i := range title.Releases {
-		release := &title.Releases[i]
-		release.Primary = false
+	for i := range releases {
+		release := &releases[i]
+		if country != "" && !strings.EqualFold(strings.TrimSpace(release.Country), country) {
+			continue
+		}
 		releaseType := strings.ToLower(strings.TrimSpace(release.Type))
 		ts, ok := parseReleaseTime(release.Date)
 		if !ok {
@@ -6468,18 +7476,7 @@ func (s *Service) ensureMovieReleasePointers(title *models.Title) {
 			}
 		}
 	}
-
-	title.Theatrical = nil
-	title.HomeRelease = nil
-
-	if bestTheatricalIdx >= 0 {
-		title.Releases[bestTheatricalIdx].Primary = true
-		title.Theatrical = &title.Releases[bestTheatricalIdx]
-	}
-	if bestHomeIdx >= 0 {
-		title.Releases[bestHomeIdx].Primary = true
-		title.HomeRelease = &title.Releases[bestHomeIdx]
-	}
+	return bestTheatricalIdx, bestHomeIdx
 }
 
 func parseReleaseTime(value string) (time.Time, bool) {
@@ -7075,7 +8072,7 @@ func (s *Service) singleflightCachedFetch(ctx context.Context, key string, fetch
 // cachedMovieExtended fetches TVDB movie extended data with file caching.
 func (s *Service) cachedMovieExtended(tvdbID int64, meta []string) (tvdbMovieExtendedData, error) {
 	metaKey := strings.Join(meta, ",")
-	cacheID := cacheKey("tvdb", "movie", "extended", "v1", fmt.Sprintf("%d", tvdbID), metaKey)
+	cacheID := tvdbExtendedCacheKey("movie", tvdbID, metaKey)
 	var cached tvdbMovieExtendedData
 	if ok, _ := s.cache.get(cacheID, &cached); ok {
 		return cached, nil
@@ -7102,7 +8099,7 @@ func (s *Service) cachedMovieExtended(tvdbID int64, meta []string) (tvdbMovieExt
 // cachedSeriesExtended fetches TVDB series extended data with file caching.
 func (s *Service) cachedSeriesExtended(tvdbID int64, meta []string) (tvdbSeriesExtendedData, error) {
 	metaKey := strings.Join(meta, ",")
-	cacheID := cacheKey("tvdb", "series", "extended", "v1", fmt.Sprintf("%d", tvdbID), metaKey)
+	cacheID := tvdbExtendedCacheKey("series", tvdbID, metaKey)
 	var cached tvdbSeriesExtendedData
 	if ok, _ := s.cache.get(cacheID, &cached); ok {
 		return cached, nil
@@ -7129,9 +8126,9 @@ func (s *Service) cachedSeriesExtended(tvdbID int64, meta []string) (tvdbSeriesE
 // cachedFetchCredits fetches TMDB cast credits with file caching.
 func (s *Service) cachedFetchCredits(ctx context.Context, mediaType string, tmdbID int64) (*models.Credits, error) {
 	if s.tmdb == nil || !s.tmdb.isConfigured() {
-		return nil, errors.New("tmdb api key not configured")
+		return nil, errTMDBNotConfigured
 	}
-	key := cacheKey("tmdb", "credits", "v1", mediaType, fmt.Sprintf("%d", tmdbID))
+	key := cacheKey("tmdb", "credits", "v2", mediaType, fmt.Sprintf("%d", tmdbID))
 	var cached models.Credits
 	if ok, _ := s.cache.get(key, &cached); ok {
 		return &cached, nil
@@ -7161,7 +8158,7 @@ func (s *Service) cachedFetchCredits(ctx context.Context, mediaType string, tmdb
 // The cached result includes the IsDark flag computed at fetch time.
 func (s *Service) cachedFetchImages(ctx context.Context, mediaType string, tmdbID int64) (*tmdbImagesResult, error) {
 	if s.tmdb == nil || !s.tmdb.isConfigured() {
-		return nil, errors.New("tmdb api key not configured")
+		return nil, errTMDBNotConfigured
 	}
 	key := cacheKey("tmdb", "images", "v6", s.client.language, mediaType, fmt.Sprintf("%d", tmdbID))
 	var cached tmdbImagesResult
@@ -7233,6 +8230,7 @@ func (s *Service) applyCachedTMDBImages(ctx context.Context, title *models.Title
 		title.Backdrops = merged
 		updated = true
 	}
+	s.applyArtworkPlaceholders(ctx, title)
 	return updated
 }
 
@@ -7244,7 +8242,7 @@ func (s *Service) enrichShelfArtwork(ctx context.Context, items []models.Trendin
 		limit = len(items)
 	}
 
-	const maxConcurrent = 5
+	maxConcurrent := s.enrichWorkers(5)
 	sem := make(chan struct{}, maxConcurrent)
 	var wg sync.WaitGroup
 	for i := 0; i < limit; i++ {
@@ -7369,7 +8367,7 @@ func comparableArtworkURL(imageURL string) string {
 
 // cachedMovieTranslations fetches TVDB movie translations with file caching.
 func (s *Service) cachedMovieTranslations(tvdbID int64, lang string) (*tvdbSeriesTranslation, error) {
-	cacheID := cacheKey("tvdb", "movie", "translations", "v1", fmt.Sprintf("%d", tvdbID), lang)
+	cacheID := tvdbTranslationsCacheKey("movie", tvdbID, lang)
 	var cached tvdbSeriesTranslation
 	if ok, _ := s.cache.get(cacheID, &cached); ok {
 		return &cached, nil
@@ -7397,7 +8395,7 @@ func (s *Service) cachedMovieTranslations(tvdbID int64, lang string) (*tvdbSerie
 
 // cachedSeriesTranslations fetches TVDB series translations with file caching.
 func (s *Service) cachedSeriesTranslations(tvdbID int64, lang string) (*tvdbSeriesTranslation, error) {
-	cacheID := cacheKey("tvdb", "series", "translations", "v1", fmt.Sprintf("%d", tvdbID), lang)
+	cacheID := tvdbTranslationsCacheKey("series", tvdbID, lang)
 	var cached tvdbSeriesTranslation
 	if ok, _ := s.cache.get(cacheID, &cached); ok {
 		return &cached, nil
@@ -7423,6 +8421,56 @@ func (s *Service) cachedSeriesTranslations(tvdbID int64, lang string) (*tvdbSeri
 	return result, nil
 }
 
+// seasonTranslationCacheTTL is how long season translations are kept. Season
+// names and overviews are rarely edited after a season airs, so they outlive
+// the regular metadata TTL.
+const seasonTranslationCacheTTL = 30 * 24 * time.Hour
+
+func seasonTranslationCacheKey(seasonID int64, lang string) string {
+	return cacheKey("tvdb", "season", "translations", "v1", fmt.Sprintf("%d", seasonID), lang)
+}
+
+// cachedSeasonTranslations fetches a TVDB season translation with long-lived
+// caching. A season without a translation in lang is cached as empty so it
+// isn't requested again on every cold series fetch.
+func (s *Service) cachedSeasonTranslations(seasonID int64, lang string) (*tvdbSeriesTranslation, error) {
+	if cached := s.peekSeasonTranslations(seasonID, lang); cached != nil {
+		return cached, nil
+	}
+	cacheID := seasonTranslationCacheKey(seasonID, lang)
+	value, err := s.singleflightCachedFetch(context.Background(), cacheID, func() (any, error) {
+		if cached := s.peekSeasonTranslations(seasonID, lang); cached != nil {
+			return cached, nil
+		}
+		result, err := s.client.seasonTranslations(seasonID, lang)
+		if errors.Is(err, ErrNotFound) {
+			result, err = &tvdbSeriesTranslation{}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if result != nil {
+			_ = s.cache.set(cacheID, *result)
+		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result, _ := value.(*tvdbSeriesTranslation)
+	return result, nil
+}
+
+// peekSeasonTranslations returns a cached season translation without
+// fetching, or nil when none is cached.
+func (s *Service) peekSeasonTranslations(seasonID int64, lang string) *tvdbSeriesTranslation {
+	var cached tvdbSeriesTranslation
+	if ok, _ := s.cache.getWithMaxAge(seasonTranslationCacheKey(seasonID, lang), &cached, seasonTranslationCacheTTL); ok {
+		return &cached
+	}
+	return nil
+}
+
 func (s *Service) cachedSeriesEpisodesBySeasonType(tvdbID int64, seasonType, lang string) ([]tvdbEpisode, error) {
 	seasonType = strings.TrimSpace(seasonType)
 	if seasonType == "" {
@@ -7494,6 +8542,13 @@ func buildLiteCustomListItem(item mdblistItem) models.TrendingItem {
 func (s *Service) enrichLiteCustomListItem(ctx context.Context, item mdblistItem) models.TrendingItem {
 	result := buildLiteCustomListItem(item)
 	title := &result.Title
+	// A title another list already enriched is better than the fast path;
+	// it keeps the lite item's ID.
+	if cached, ok := s.cachedEnrichedTitle(title.MediaType, item, enrichedTitleDepthLite); ok {
+		cached.ID, cached.Popularity = title.ID, title.Popularity
+		result.Title = cached
+		return result
+	}
 	if item.TVDBID == nil || *item.TVDBID <= 0 {
 		s.applyTMDBGenreFallback(ctx, title)
 		return result
@@ -7719,7 +8774,7 @@ func filterWatchedMDBListItems(items []mdblistItem, userID string, historySvc Hi
 // before full enrichment. For movies it checks TMDB release data; for series it checks
 // TVDB status.
 func (s *Service) preFilterUnreleased(ctx context.Context, items []mdblistItem) []mdblistItem {
-	const maxConcurrent = 10
+	maxConcurrent := s.enrichWorkers(10)
 	sem := make(chan struct{}, maxConcurrent)
 	var wg sync.WaitGroup
 
@@ -7798,10 +8853,33 @@ func applyTVDBMovieExtendedMetadata(title *models.Title, ext tvdbMovieExtendedDa
 }
 
 // enrichCustomListItem enriches a single mdblistItem into a full TrendingItem.
-// Within each item, TVDB extended + translations calls are parallelized.
+// A title already enriched for another list is reused from its shared
+// entry, or, within a warm cycle, copied from that list rather than fetched
+// again.
 func (s *Service) enrichCustomListItem(ctx context.Context, item mdblistItem, liteMovieEnrichment bool) models.TrendingItem {
 	mediaType := mdblistItemMediaType(item)
+	item = s.applyListItemIdentityOverride(item, fmt.Sprintf("mdblist:%s:%d", mediaType, item.ID))
 
+	enrich := func() models.TrendingItem {
+		return s.enrichSharedListItem(ctx, item, mediaType, liteMovieEnrichment)
+	}
+	var enriched models.TrendingItem
+	batch := listEnrichmentBatchFrom(ctx)
+	if key := listItemEnrichmentKey(mediaType, item, liteMovieEnrichment); batch != nil && key != "" {
+		enriched = batch.enrich(ctx, key, enrich)
+	} else {
+		enriched = enrich()
+	}
+	// Rank and popularity are the item's place on this list.
+	enriched.Rank = item.Rank
+	enriched.Title.Popularity = float64(100 - item.Rank)
+	return enriched
+}
+
+// enrichListItem does the TVDB and TMDB lookups for enrichCustomListItem.
+// Items with a TVDB ID fetch their extended record and translation in one
+// request.
+func (s *Service) enrichListItem(ctx context.Context, item mdblistItem, mediaType string, liteMovieEnrichment bool) models.TrendingItem {
 	title := models.Title{
 		ID:         fmt.Sprintf("mdblist:%s:%d", mediaType, item.ID),
 		Name:       item.Title,
@@ -7819,30 +8897,17 @@ func (s *Service) enrichCustomListItem(ctx context.Context, item mdblistItem, li
 	}
 
 	var found bool
+	var failReason string
 	gotTranslatedOverview := false
 
 	// Try TVDB ID from MDBList first
 	if item.TVDBID != nil && *item.TVDBID > 0 {
 		tvdbID := *item.TVDBID
 		if mediaType == "movie" {
-			// Parallel: extended (includes name/overview/artwork) + translations
-			var ext tvdbMovieExtendedData
-			var extErr error
-			var trans *tvdbSeriesTranslation
-
-			var innerWg sync.WaitGroup
-			innerWg.Add(2)
-			go func() {
-				defer innerWg.Done()
-				ext, extErr = s.cachedMovieExtended(tvdbID, []string{"artwork"})
-			}()
-			go func() {
-				defer innerWg.Done()
-				trans, _ = s.cachedMovieTranslations(tvdbID, s.client.language)
-			}()
-			innerWg.Wait()
-
-			if extErr == nil {
+			ext, trans, extErr := s.cachedMovieWithTranslation(tvdbID)
+			if extErr != nil {
+				failReason = fmt.Sprintf("tvdb movie %d lookup failed: %v", tvdbID, extErr)
+			} else {
 				title.TVDBID = tvdbID
 				title.ID = fmt.Sprintf("tvdb:movie:%d", tvdbID)
 				title.Name = ext.Name
@@ -7861,30 +8926,19 @@ func (s *Service) enrichCustomListItem(ctx context.Context, item mdblistItem, li
 				}
 			}
 		} else {
-			// Parallel: extended (includes name/overview/artwork/status) + translations
-			var ext tvdbSeriesExtendedData
-			var extErr error
-			var trans *tvdbSeriesTranslation
-
-			var innerWg sync.WaitGroup
-			innerWg.Add(2)
-			go func() {
-				defer innerWg.Done()
-				ext, extErr = s.cachedSeriesExtended(tvdbID, []string{"artworks"})
-			}()
-			go func() {
-				defer innerWg.Done()
-				trans, _ = s.cachedSeriesTranslations(tvdbID, s.client.language)
-			}()
-			innerWg.Wait()
-
-			if extErr == nil {
+			ext, trans, extErr := s.cachedSeriesWithTranslation(tvdbID)
+			if extErr != nil {
+				failReason = fmt.Sprintf("tvdb series %d lookup failed: %v", tvdbID, extErr)
+			} else {
 				title.TVDBID = tvdbID
 				title.ID = fmt.Sprintf("tvdb:series:%d", tvdbID)
 				title.Overview = ext.Overview
 				title.Status = ext.Status.Name
 				found = true
 				applyTVDBArtworks(&title, ext.Artworks)
+				if genres := tvdbGenreNames(ext.Genres); len(genres) > 0 {
+					title.Genres = genres
+				}
 
 				if trans != nil {
 					if trans.Name != "" {
@@ -7905,13 +8959,16 @@ func (s *Service) enrichCustomListItem(ctx context.Context, item mdblistItem, li
 			searchResults, err := s.searchTVDBMovie(item.Title, item.ReleaseYear, remoteID)
 			if err != nil {
 				log.Printf("[metadata] custom list movie tvdb search error title=%q year=%d imdbId=%q err=%v", item.Title, item.ReleaseYear, item.IMDBID, err)
+				failReason = fmt.Sprintf("tvdb search failed: %v", err)
 			} else if len(searchResults) == 0 {
+				failReason = "tvdb search returned no results"
 				log.Printf("[metadata] custom list movie tvdb search returned 0 results title=%q year=%d imdbId=%q", item.Title, item.ReleaseYear, item.IMDBID)
 				if item.ReleaseYear > 0 {
 					log.Printf("[metadata] custom list movie tvdb search retrying without year title=%q imdbId=%q", item.Title, item.IMDBID)
 					searchResults, err = s.searchTVDBMovie(item.Title, 0, remoteID)
 					if err != nil {
 						log.Printf("[metadata] custom list movie tvdb search (no year) error title=%q imdbId=%q err=%v", item.Title, item.IMDBID, err)
+						failReason = fmt.Sprintf("tvdb search failed: %v", err)
 					} else if len(searchResults) > 0 {
 						log.Printf("[metadata] custom list movie tvdb search (no year) found %d results title=%q imdbId=%q", len(searchResults), item.Title, item.IMDBID)
 					}
@@ -7921,15 +8978,18 @@ func (s *Service) enrichCustomListItem(ctx context.Context, item mdblistItem, li
 				result := searchResults[0]
 				if result.TVDBID == "" {
 					log.Printf("[metadata] custom list movie tvdb search result has no tvdb_id title=%q year=%d imdbId=%q firstResultName=%q", item.Title, item.ReleaseYear, item.IMDBID, result.Name)
+					failReason = fmt.Sprintf("tvdb search result %q has no tvdb id", result.Name)
 				} else if tvdbID, err := strconv.ParseInt(result.TVDBID, 10, 64); err != nil {
 					log.Printf("[metadata] custom list movie tvdb search result has invalid tvdb_id title=%q year=%d tvdbId=%q err=%v", item.Title, item.ReleaseYear, result.TVDBID, err)
+					failReason = fmt.Sprintf("tvdb search result has invalid tvdb id %q", result.TVDBID)
 				} else {
 					title.TVDBID = tvdbID
 					title.ID = fmt.Sprintf("tvdb:movie:%d", tvdbID)
 					if img := newTVDBImage(result.ImageURL, "poster", 0, 0); img != nil {
 						title.Poster = img
 					}
-					if ext, err := s.cachedMovieExtended(tvdbID, []string{"artwork"}); err == nil {
+					ext, trans, extErr := s.cachedMovieWithTranslation(tvdbID)
+					if extErr == nil {
 						applyTVDBMovieExtendedMetadata(&title, ext)
 						applyTVDBRemoteIDs(&title, ext.RemoteIDs)
 					}
@@ -7947,8 +9007,8 @@ func (s *Service) enrichCustomListItem(ctx context.Context, item mdblistItem, li
 						title.Overview = result.Overviews[lang3]
 						gotTranslatedOverview = true
 					}
-					// Fetch translations for the user's language (authoritative)
-					if trans, err := s.cachedMovieTranslations(tvdbID, s.client.language); err == nil && trans != nil {
+					// Translations for the user's language are authoritative
+					if trans != nil {
 						if trans.Name != "" {
 							title.Name = trans.Name
 						}
@@ -7964,13 +9024,16 @@ func (s *Service) enrichCustomListItem(ctx context.Context, item mdblistItem, li
 			searchResults, err := s.searchTVDBSeries(item.Title, item.ReleaseYear, remoteID)
 			if err != nil {
 				log.Printf("[metadata] custom list series tvdb search error title=%q year=%d imdbId=%q err=%v", item.Title, item.ReleaseYear, item.IMDBID, err)
+				failReason = fmt.Sprintf("tvdb search failed: %v", err)
 			} else if len(searchResults) == 0 {
+				failReason = "tvdb search returned no results"
 				log.Printf("[metadata] custom list series tvdb search returned 0 results title=%q year=%d imdbId=%q", item.Title, item.ReleaseYear, item.IMDBID)
 				if item.ReleaseYear > 0 {
 					log.Printf("[metadata] custom list series tvdb search retrying without year title=%q imdbId=%q", item.Title, item.IMDBID)
 					searchResults, err = s.searchTVDBSeries(item.Title, 0, remoteID)
 					if err != nil {
 						log.Printf("[metadata] custom list series tvdb search (no year) error title=%q imdbId=%q err=%v", item.Title, item.IMDBID, err)
+						failReason = fmt.Sprintf("tvdb search failed: %v", err)
 					} else if len(searchResults) > 0 {
 						log.Printf("[metadata] custom list series tvdb search (no year) found %d results title=%q imdbId=%q", len(searchResults), item.Title, item.IMDBID)
 					}
@@ -7980,15 +9043,18 @@ func (s *Service) enrichCustomListItem(ctx context.Context, item mdblistItem, li
 				result := searchResults[0]
 				if result.TVDBID == "" {
 					log.Printf("[metadata] custom list series tvdb search result has no tvdb_id title=%q year=%d imdbId=%q firstResultName=%q", item.Title, item.ReleaseYear, item.IMDBID, result.Name)
+					failReason = fmt.Sprintf("tvdb search result %q has no tvdb id", result.Name)
 				} else if tvdbID, err := strconv.ParseInt(result.TVDBID, 10, 64); err != nil {
 					log.Printf("[metadata] custom list series tvdb search result has invalid tvdb_id title=%q year=%d tvdbId=%q err=%v", item.Title, item.ReleaseYear, result.TVDBID, err)
+					failReason = fmt.Sprintf("tvdb search result has invalid tvdb id %q", result.TVDBID)
 				} else {
 					title.TVDBID = tvdbID
 					title.ID = fmt.Sprintf("tvdb:series:%d", tvdbID)
 					if img := newTVDBImage(result.ImageURL, "poster", 0, 0); img != nil {
 						title.Poster = img
 					}
-					if ext, err := s.cachedSeriesExtended(tvdbID, []string{"artworks"}); err == nil {
+					ext, trans, extErr := s.cachedSeriesWithTranslation(tvdbID)
+					if extErr == nil {
 						applyTVDBArtworks(&title, ext.Artworks)
 						applyTVDBRemoteIDs(&title, ext.RemoteIDs)
 						if title.Status == "" {
@@ -8008,8 +9074,8 @@ func (s *Service) enrichCustomListItem(ctx context.Context, item mdblistItem, li
 					if result.Overviews != nil && lang3 != "" && lang3 != "eng" && result.Overviews[lang3] != "" {
 						title.Overview = result.Overviews[lang3]
 					}
-					// Fetch translations for the user's language (authoritative)
-					if trans, err := s.cachedSeriesTranslations(tvdbID, s.client.language); err == nil && trans != nil {
+					// Translations for the user's language are authoritative
+					if trans != nil {
 						if trans.Name != "" {
 							title.Name = trans.Name
 						}
@@ -8025,6 +9091,12 @@ func (s *Service) enrichCustomListItem(ctx context.Context, item mdblistItem, li
 
 	if !found {
 		log.Printf("[metadata] no tvdb match for custom list item title=%q year=%d type=%s imdbId=%q", item.Title, item.ReleaseYear, mediaType, item.IMDBID)
+		if failReason == "" {
+			failReason = "no tvdb match"
+		}
+		s.recordEnrichmentFailure(item, EnrichmentSourceCustomList, failReason)
+	} else {
+		s.resolveEnrichmentFailure(item)
 	}
 
 	// Enrich movies with release data from TMDB (parallel where possible)
@@ -8081,6 +9153,7 @@ func (s *Service) enrichCustomListItem(ctx context.Context, item mdblistItem, li
 			if relOK {
 				title.Releases = relTitle.Releases
 				title.Certification = relTitle.Certification
+				title.CertificationCountry = relTitle.CertificationCountry
 				title.HomeRelease = relTitle.HomeRelease
 				title.Theatrical = relTitle.Theatrical
 			}
@@ -8128,7 +9201,7 @@ func (s *Service) enrichCustomListItem(ctx context.Context, item mdblistItem, li
 	}
 }
 
-// GetCustomList fetches items from a custom MDBList URL and returns them as TrendingItems.
+// GetCustomList fetches items from a custom MDBList or Letterboxd URL and returns them as TrendingItems.
 // Pre-filters watched/unreleased items before enrichment so only displayed items incur full
 // TVDB lookups. Returns (items, filteredTotal, unfilteredTotal, error).
 func (s *Service) GetCustomList(ctx context.Context, listURL string, opts CustomListOptions) ([]models.TrendingItem, int, int, error) {
@@ -8188,18 +9261,19 @@ func (s *Service) GetCustomList(ctx context.Context, listURL string, opts Custom
 				}
 			}
 		}
-		cleanup := s.startProgressTask(progressID, progressLabel, "fetching", 0)
+		var cleanup func()
+		ctx, cleanup = s.startProgressTask(ctx, progressID, progressLabel, "fetching", 0)
 		defer cleanup()
 	}
 
-	// Fetch raw items from MDBList API
-	rawItems, err := s.client.FetchMDBListCustom(listURL)
+	// Fetch raw items from MDBList, Letterboxd, IMDb, Trakt or a shared list
+	rawItems, err := s.fetchCustomListItems(ctx, listURL)
 	if err != nil {
-		return nil, 0, 0, fmt.Errorf("failed to fetch custom MDBList: %w", err)
+		return nil, 0, 0, fmt.Errorf("failed to fetch custom list: %w", err)
 	}
 
 	unfilteredTotal := len(rawItems)
-	log.Printf("[metadata] fetched %d items from custom MDBList: %s", unfilteredTotal, listURL)
+	log.Printf("[metadata] fetched %d items from custom list: %s", unfilteredTotal, listURL)
 
 	remaining := rawItems
 
@@ -8252,7 +9326,11 @@ func (s *Service) GetCustomList(ctx context.Context, listURL string, opts Custom
 		wg.Add(1)
 		go func(idx int, it mdblistItem) {
 			defer wg.Done()
-			sem <- struct{}{}
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
 			defer func() { <-sem }()
 			if opts.Lite {
 				results[idx] = s.enrichLiteCustomListItem(ctx, it)
@@ -8265,12 +9343,17 @@ func (s *Service) GetCustomList(ctx context.Context, listURL string, opts Custom
 		}(i, item)
 	}
 	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		log.Printf("[metadata] custom list enrichment stopped for %s: %v", listURL, err)
+		return nil, 0, 0, err
+	}
 	s.enrichShelfArtwork(ctx, results, customListArtworkLimit(opts))
 
 	// Only cache full-list results when no filtering was applied
 	if !opts.HideWatched && !opts.HideUnreleased && opts.Offset == 0 &&
 		(opts.Limit == 0 || opts.Limit >= unfilteredTotal) && len(results) > 0 {
 		_ = s.cache.set(cacheID, results)
+		s.enrichFailures.attachCache(cacheID, s.client.language, itemsToEnrich)
 		log.Printf("[metadata] cached %d enriched items for custom list: %s", len(results), listURL)
 	}
 
@@ -8364,7 +9447,7 @@ func (s *Service) GetCuratedList(ctx context.Context, items []CuratedItem, label
 
 	// Register progress tracking
 	progressID := "curated-list:" + label
-	cleanup := s.startProgressTask(progressID, label, "enriching", len(mdbItems))
+	ctx, cleanup := s.startProgressTask(ctx, progressID, label, "enriching", len(mdbItems))
 	defer cleanup()
 
 	sem := make(chan struct{}, foregroundCustomListEnrichConcurrency)
@@ -8375,18 +9458,27 @@ func (s *Service) GetCuratedList(ctx context.Context, items []CuratedItem, label
 		wg.Add(1)
 		go func(idx int, it mdblistItem) {
 			defer wg.Done()
-			sem <- struct{}{}
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
 			defer func() { <-sem }()
 			results[idx] = s.enrichCustomListItem(ctx, it, false)
 			s.incrementProgress(progressID)
 		}(i, item)
 	}
 	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		log.Printf("[metadata] curated list enrichment stopped for %q: %v", label, err)
+		return nil, err
+	}
 	s.enrichShelfArtwork(ctx, results, customListShelfArtworkLimit)
 
 	// Cache results
 	if len(results) > 0 {
 		_ = s.cache.set(cacheID, results)
+		s.enrichFailures.attachCache(cacheID, s.client.language, mdbItems)
 		log.Printf("[metadata] cached %d enriched items for curated list %q", len(results), label)
 	}
 
@@ -8413,6 +9505,9 @@ func (s *Service) ExtractTrailerStreamURL(ctx context.Context, videoURL string)
 		log.Printf("[metadata] trailer stream cache hit for %s", videoURL)
 		return cached, nil
 	}
+	if err := offlineError("youtube"); err != nil {
+		return "", err
+	}
 
 	// Try to find yt-dlp binary
 	ytdlpPath := "/usr/local/bin/yt-dlp"
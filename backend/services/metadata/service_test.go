@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"sync"
@@ -196,7 +198,7 @@ func TestGetCachedArtworkURLsResolvesSeriesTMDBToTVDBCache(t *testing.T) {
 	if err := cache.set(cacheKey("tvdb", "resolve", "tmdb", "71712"), int64(328634)); err != nil {
 		t.Fatalf("set resolve cache: %v", err)
 	}
-	if err := cache.set(cacheKey("tvdb", "series", "details", "v10", "eng", "328634"), models.SeriesDetails{
+	if err := cache.set(cacheKey("tvdb", "series", "details", "v12", "eng", "328634"), models.SeriesDetails{
 		Title: models.Title{
 			TextPoster:   &models.Image{URL: "https://example.test/text-poster.jpg", Type: "poster"},
 			TextBackdrop: &models.Image{URL: "https://example.test/text-backdrop.jpg", Type: "backdrop"},
@@ -255,7 +257,7 @@ func TestGetCachedArtworkURLsResolvesMovieTMDBToTVDBCache(t *testing.T) {
 	if err := cache.set(cacheKey("tvdb", "resolve", "movie", "tmdb", "752"), int64(528)); err != nil {
 		t.Fatalf("set resolve cache: %v", err)
 	}
-	if err := cache.set(cacheKey("tvdb", "movie", "details", "v5", "eng", "528"), models.Title{
+	if err := cache.set(cacheKey("tvdb", "movie", "details", "v6", "eng", "528"), models.Title{
 		TextBackdrop: &models.Image{URL: "https://example.test/movie-text-backdrop.jpg", Type: "backdrop"},
 		Backdrops: []models.Image{
 			{URL: "https://example.test/movie-alt-1.jpg", Type: "backdrop"},
@@ -312,9 +314,15 @@ func TestGetCustomListFetchesTranslations(t *testing.T) {
 				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBuffer(body)), Header: make(http.Header)}, nil
 			}
 
-			// Handle TVDB series extended (includes name/overview/artwork/status) — primary call
+			// Handle TVDB series extended (includes name/overview/artwork/status) — primary call,
+			// with translations in every language when asked for meta=translations
 			if strings.HasPrefix(path, "/v4/series/12345/extended") {
-				body := bytes.NewBufferString(`{"data":{"id":12345,"name":"テストアニメ","overview":"これは日本語の概要です","artworks":[]}}`)
+				translations := ""
+				if req.URL.Query().Get("meta") == "translations" {
+					translationsFetched = append(translationsFetched, "eng")
+					translations = `,"translations":{"nameTranslations":[{"language":"eng","name":"Test Anime English"}],"overviewTranslations":[{"language":"eng","overview":"This is the English overview"}]}`
+				}
+				body := bytes.NewBufferString(`{"data":{"id":12345,"name":"テストアニメ","overview":"これは日本語の概要です","artworks":[]` + translations + `}}`)
 				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(body), Header: make(http.Header)}, nil
 			}
 
@@ -336,7 +344,7 @@ func TestGetCustomListFetchesTranslations(t *testing.T) {
 		client: newTVDBClient("test-api-key", "eng", httpc, 24),
 		cache:  newFileCache(t.TempDir(), 24),
 	}
-	service.client.minInterval = 0
+	service.client.limiter = nil
 
 	// Call GetCustomList
 	items, filteredTotal, unfilteredTotal, err := service.GetCustomList(context.Background(), "https://mdblist.com/lists/test/anime/json", CustomListOptions{Limit: 10})
@@ -421,9 +429,15 @@ func TestGetCustomListMovieTranslations(t *testing.T) {
 				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBuffer(body)), Header: make(http.Header)}, nil
 			}
 
-			// Handle TVDB movie extended (includes name/overview/artwork) — now the primary call
+			// Handle TVDB movie extended (includes name/overview/artwork) — now the primary call,
+			// with translations in every language when asked for meta=translations
 			if strings.HasPrefix(path, "/v4/movies/67890/extended") {
-				body := bytes.NewBufferString(`{"data":{"id":67890,"name":"テスト映画","overview":"これは日本語の映画概要です","artworks":[]}}`)
+				translations := ""
+				if req.URL.Query().Get("meta") == "translations" {
+					translationsFetched = append(translationsFetched, "eng")
+					translations = `,"translations":{"nameTranslations":[{"language":"eng","name":"Test Movie English"}],"overviewTranslations":[{"language":"eng","overview":"This is the English movie overview"}]}`
+				}
+				body := bytes.NewBufferString(`{"data":{"id":67890,"name":"テスト映画","overview":"これは日本語の映画概要です","artworks":[]` + translations + `}}`)
 				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(body), Header: make(http.Header)}, nil
 			}
 
@@ -447,7 +461,7 @@ func TestGetCustomListMovieTranslations(t *testing.T) {
 		cache:   newFileCache(tempDir, 24),
 		idCache: newFileCache(tempDir, 24*7),
 	}
-	service.client.minInterval = 0
+	service.client.limiter = nil
 
 	// Call GetCustomList
 	items, filteredTotal, _, err := service.GetCustomList(context.Background(), "https://mdblist.com/lists/test/movies/json", CustomListOptions{Limit: 10})
@@ -572,7 +586,7 @@ func TestSeriesDetailsUpgradesCachedLiteSeasonNames(t *testing.T) {
 		cache:   newFileCache(tempDir, 24),
 		idCache: newFileCache(tempDir, 24*7),
 	}
-	service.client.minInterval = 0
+	service.client.limiter = nil
 
 	query := models.SeriesDetailsQuery{
 		TVDBID: 12345,
@@ -682,7 +696,7 @@ func TestSearchWithoutMediaTypeIncludesMoviesAndSeries(t *testing.T) {
 		client: newTVDBClient("test-api-key", "eng", httpc, 24),
 		cache:  newFileCache(t.TempDir(), 24),
 	}
-	svc.client.minInterval = 0
+	svc.client.limiter = nil
 
 	results, err := svc.Search(context.Background(), "heat", "")
 	if err != nil {
@@ -736,7 +750,7 @@ func TestSearchBlocksAdultResultsByDefaultAndAllowsWhenEnabled(t *testing.T) {
 		tmdb:   newTMDBClient("test-tmdb-key", "eng", httpc, newFileCache(cacheDir, 24)),
 		cache:  newFileCache(cacheDir, 24),
 	}
-	svc.client.minInterval = 0
+	svc.client.limiter = nil
 
 	results, err := svc.Search(context.Background(), "movie", "movie")
 	if err != nil {
@@ -803,7 +817,7 @@ func TestSearchEnrichesResultsWithRequestedLanguage(t *testing.T) {
 	}
 	cacheDir := t.TempDir()
 	svc := &Service{
-		client: &tvdbClient{apiKey: "test-tvdb-key", language: "fra", httpc: httpc, minInterval: 0, translationCacheTTL: 24 * time.Hour},
+		client: &tvdbClient{apiKey: "test-tvdb-key", language: "fra", httpc: httpc, translationCacheTTL: 24 * time.Hour},
 		tmdb:   newTMDBClient("test-tmdb-key", "fra", httpc, newFileCache(cacheDir, 24)),
 		cache:  newFileCache(cacheDir, 24),
 	}
@@ -856,7 +870,7 @@ func TestSearchLocalizesTMDBOnlyMovieTitleAndOverview(t *testing.T) {
 	}
 	cacheDir := t.TempDir()
 	svc := &Service{
-		client: &tvdbClient{apiKey: "test-tvdb-key", language: "ita", httpc: httpc, minInterval: 0, translationCacheTTL: 24 * time.Hour},
+		client: &tvdbClient{apiKey: "test-tvdb-key", language: "ita", httpc: httpc, translationCacheTTL: 24 * time.Hour},
 		tmdb:   newTMDBClient("test-tmdb-key", "ita", httpc, newFileCache(cacheDir, 24)),
 		cache:  newFileCache(cacheDir, 24),
 	}
@@ -901,7 +915,7 @@ func TestSearchTMDBVoteCountBoostsCanonicalMatch(t *testing.T) {
 		tmdb:   newTMDBClient("test-tmdb-key", "eng", httpc, newFileCache(cacheDir, 24)),
 		cache:  newFileCache(cacheDir, 24),
 	}
-	svc.client.minInterval = 0
+	svc.client.limiter = nil
 
 	results, err := svc.Search(context.Background(), "fellowship of the ring", "movie")
 	if err != nil {
@@ -925,11 +939,13 @@ func TestSearchTMDBVoteCountBoostsCanonicalMatch(t *testing.T) {
 }
 
 func TestPreferTMDBEpisodeImagesOverridesTVDBStills(t *testing.T) {
+	seasonRequests := 0
 	httpc := &http.Client{
 		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
 			if req.URL.Path == "/3/tv/42/season/1" {
+				seasonRequests++
 				body := bytes.NewBufferString(`{"id":1001,"name":"Season 1","season_number":1,"episodes":[
-					{"id":5001,"name":"Pilot","season_number":1,"episode_number":1,"still_path":"/tmdb-pilot.jpg"},
+					{"id":5001,"name":"Pilot","season_number":1,"episode_number":1,"still_path":"/tmdb-pilot.jpg","vote_average":8.4,"vote_count":120},
 					{"id":5002,"name":"Second","season_number":1,"episode_number":2}
 				]}`)
 				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(body), Header: make(http.Header)}, nil
@@ -940,9 +956,10 @@ func TestPreferTMDBEpisodeImagesOverridesTVDBStills(t *testing.T) {
 	}
 
 	service := &Service{
-		tmdb: newTMDBClient("tmdb-key", "eng", httpc, newFileCache(t.TempDir(), 24)),
+		tmdb:  newTMDBClient("tmdb-key", "eng", httpc, newFileCache(t.TempDir(), 24)),
+		cache: newFileCache(t.TempDir(), 24),
 	}
-	service.tmdb.minInterval = 0
+	service.tmdb.limiter = nil
 
 	details := models.SeriesDetails{
 		Title: models.Title{TMDBID: 42, MediaType: "series"},
@@ -969,7 +986,7 @@ func TestPreferTMDBEpisodeImagesOverridesTVDBStills(t *testing.T) {
 		},
 	}
 
-	if !service.preferTMDBEpisodeImages(context.Background(), &details, 42) {
+	if !service.preferTMDBEpisodeImages(context.Background(), &details, 42, false) {
 		t.Fatal("expected TMDB episode image enrichment to change the details")
 	}
 
@@ -989,6 +1006,24 @@ func TestPreferTMDBEpisodeImagesOverridesTVDBStills(t *testing.T) {
 	if gotSecondImage == nil || gotSecondImage.URL != "https://artworks.thetvdb.com/banners/tvdb-second.jpg" {
 		t.Fatalf("expected second episode to keep TVDB image when TMDB still is missing, got %#v", gotSecondImage)
 	}
+
+	wantRating := models.Rating{Source: "tmdb", Value: 8.4, Max: 10, Votes: 120}
+	if ratings := details.Seasons[0].Episodes[0].Ratings; len(ratings) != 1 || ratings[0] != wantRating {
+		t.Fatalf("expected pilot rating %+v, got %+v", wantRating, ratings)
+	}
+	if ratings := details.Seasons[0].Episodes[1].Ratings; len(ratings) != 0 {
+		t.Fatalf("expected unrated second episode, got %+v", ratings)
+	}
+
+	// The season is cached, so a rebuild doesn't refetch it.
+	details.Seasons[0].Episodes[0].Ratings = nil
+	service.preferTMDBEpisodeImages(context.Background(), &details, 42, false)
+	if seasonRequests != 1 {
+		t.Fatalf("expected 1 season request, got %d", seasonRequests)
+	}
+	if len(details.Seasons[0].Episodes[0].Ratings) != 1 {
+		t.Fatal("expected cached season to restore the pilot rating")
+	}
 }
 
 // TestGetCustomListNoTranslationWhenUnavailable verifies that when translation is not available,
@@ -1042,7 +1077,7 @@ func TestGetCustomListNoTranslationWhenUnavailable(t *testing.T) {
 		client: newTVDBClient("test-api-key", "eng", httpc, 24),
 		cache:  newFileCache(t.TempDir(), 24),
 	}
-	service.client.minInterval = 0
+	service.client.limiter = nil
 
 	// Call GetCustomList
 	items, _, _, err := service.GetCustomList(context.Background(), "https://mdblist.com/lists/test/obscure/json", CustomListOptions{Limit: 10})
@@ -1077,7 +1112,7 @@ func TestProgressLifecycle(t *testing.T) {
 	}
 
 	// Start a task
-	cleanup := svc.startProgressTask("test-task", "Test Task", "fetching", 0)
+	_, cleanup := svc.startProgressTask(context.Background(), "test-task", "Test Task", "fetching", 0)
 
 	snap = svc.GetProgressSnapshot()
 	if snap.ActiveCount != 1 {
@@ -1119,6 +1154,111 @@ func TestProgressIncrementNoTask(t *testing.T) {
 	svc.updateProgressPhase("nonexistent", "test", 10)
 }
 
+// TestSubscribeProgress verifies subscribers get the current snapshot and then
+// the latest state after changes.
+func TestSubscribeProgress(t *testing.T) {
+	svc := &Service{progressTasks: make(map[string]*ProgressTask)}
+	updates, unsubscribe := svc.SubscribeProgress()
+
+	if snap := <-updates; snap.ActiveCount != 0 {
+		t.Fatalf("expected empty initial snapshot, got %+v", snap)
+	}
+
+	_, cleanup := svc.startProgressTask(context.Background(), "test-task", "Test Task", "fetching", 3)
+	waitForSnapshot := func(want int) {
+		t.Helper()
+		deadline := time.After(2 * time.Second)
+		for {
+			select {
+			case snap := <-updates:
+				if snap.ActiveCount == want {
+					return
+				}
+			case <-deadline:
+				t.Fatalf("timed out waiting for %d active tasks", want)
+			}
+		}
+	}
+	waitForSnapshot(1)
+	cleanup()
+	waitForSnapshot(0)
+
+	unsubscribe()
+	unsubscribe()
+	for range updates {
+		// Drain any buffered snapshot; the loop ends once the channel is closed.
+	}
+	// Notifications after the last subscriber leaves must not block.
+	_, cleanup = svc.startProgressTask(context.Background(), "other", "Other", "fetching", 0)
+	cleanup()
+}
+
+// TestProgressRate verifies the rate and ETA derived from phase progress.
+func TestProgressRate(t *testing.T) {
+	now := time.UnixMilli(1_700_000_060_000)
+	phaseStart := now.Add(-60 * time.Second).UnixMilli()
+
+	rate, eta := progressRate(120, 200, phaseStart, now)
+	if rate != 2 || eta != 40 {
+		t.Fatalf("expected 2 items/s and 40s left, got %v and %d", rate, eta)
+	}
+	if rate, eta := progressRate(200, 200, phaseStart, now); rate == 0 || eta != 0 {
+		t.Fatalf("expected a rate and no ETA once done, got %v and %d", rate, eta)
+	}
+	if rate, eta := progressRate(0, 200, phaseStart, now); rate != 0 || eta != 0 {
+		t.Fatalf("expected nothing before the first item, got %v and %d", rate, eta)
+	}
+	justStarted := now.Add(-500 * time.Millisecond).UnixMilli()
+	if rate, eta := progressRate(5, 200, justStarted, now); rate != 0 || eta != 0 {
+		t.Fatalf("expected nothing for a phase that just started, got %v and %d", rate, eta)
+	}
+}
+
+// TestCancelProgressTask verifies cancelling a task cancels its context and
+// marks it cancelled until its cleanup runs.
+func TestCancelProgressTask(t *testing.T) {
+	svc := &Service{progressTasks: make(map[string]*ProgressTask)}
+	if svc.CancelProgressTask("missing") {
+		t.Fatal("expected false for a task that is not running")
+	}
+
+	ctx, cleanup := svc.startProgressTask(context.Background(), "test-task", "Test Task", "enriching", 10)
+	if !svc.CancelProgressTask("test-task") {
+		t.Fatal("expected running task to be cancelled")
+	}
+	select {
+	case <-ctx.Done():
+	default:
+		t.Fatal("expected task context to be cancelled")
+	}
+	// Cancelling again is a no-op that still reports the task as running.
+	if !svc.CancelProgressTask("test-task") {
+		t.Fatal("expected repeated cancel to report the task")
+	}
+	snap := svc.GetProgressSnapshot()
+	if snap.ActiveCount != 1 || !snap.Tasks[0].Cancelled {
+		t.Fatalf("expected cancelled task in snapshot, got %+v", snap.Tasks)
+	}
+
+	cleanup()
+	if snap := svc.GetProgressSnapshot(); snap.ActiveCount != 0 {
+		t.Fatalf("expected task removed after cleanup, got %+v", snap.Tasks)
+	}
+}
+
+// TestProgressCleanupKeepsNewerTask verifies a finished run does not remove a
+// newer task registered under the same ID.
+func TestProgressCleanupKeepsNewerTask(t *testing.T) {
+	svc := &Service{progressTasks: make(map[string]*ProgressTask)}
+	_, first := svc.startProgressTask(context.Background(), "refresh", "Refresh", "refreshing", 0)
+	_, second := svc.startProgressTask(context.Background(), "refresh", "Refresh", "refreshing", 0)
+	first()
+	if snap := svc.GetProgressSnapshot(); snap.ActiveCount != 1 {
+		t.Fatalf("expected newer task to remain, got %+v", snap.Tasks)
+	}
+	second()
+}
+
 func TestGetCustomListSuppressProgress(t *testing.T) {
 	var (
 		svc                *Service
@@ -1151,7 +1291,7 @@ func TestGetCustomListSuppressProgress(t *testing.T) {
 		cache:         newFileCache(t.TempDir(), 24),
 		progressTasks: make(map[string]*ProgressTask),
 	}
-	svc.client.minInterval = 0
+	svc.client.limiter = nil
 
 	suppressExpected = false
 	if _, _, _, err := svc.GetCustomList(context.Background(), "https://mdblist.com/lists/test/progress/json", CustomListOptions{}); err != nil {
@@ -1390,6 +1530,109 @@ func TestDiscoverByDecadeWithOptions(t *testing.T) {
 	}
 }
 
+func TestDiscoverWithFilter(t *testing.T) {
+	cache := newFileCache(t.TempDir(), 24)
+	var capturedQuery string
+	requests := 0
+	svc := &Service{
+		client: &tvdbClient{language: "eng"},
+		cache:  cache,
+		tmdb: newTMDBClient("tmdb-key", "eng", &http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				if req.URL.Path != "/3/discover/movie" {
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Status:     "200 OK",
+						Body:       io.NopCloser(strings.NewReader(`{"backdrops":[],"posters":[],"logos":[]}`)),
+						Header:     make(http.Header),
+					}, nil
+				}
+				requests++
+				capturedQuery = req.URL.RawQuery
+				body := `{"results":[{"id":129,"title":"Spirited Away","release_date":"2001-07-20","popularity":80}],"total_results":1}`
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Status:     "200 OK",
+					Body:       io.NopCloser(strings.NewReader(body)),
+					Header:     make(http.Header),
+				}, nil
+			}),
+		}, cache),
+	}
+
+	filter := models.DiscoverFilter{
+		MediaType:        "movie",
+		GenreIDs:         []int64{16, 14},
+		YearFrom:         1990,
+		YearTo:           2005,
+		RuntimeMax:       130,
+		OriginalLanguage: "ja",
+		Sort:             "rating",
+	}
+	items, total, err := svc.DiscoverWithFilter(context.Background(), filter, 20, 0, ShelfLoadOptions{Lite: true})
+	if err != nil {
+		t.Fatalf("DiscoverWithFilter: %v", err)
+	}
+	if total != 1 || len(items) != 1 || items[0].Title.Name != "Spirited Away" {
+		t.Fatalf("unexpected results total=%d items=%+v", total, items)
+	}
+	for _, param := range []string{
+		"with_genres=14,16",
+		"primary_release_date.gte=1990-01-01",
+		"primary_release_date.lte=2005-12-31",
+		"with_runtime.lte=130",
+		"vote_count.gte=100",
+		"with_original_language=ja",
+		"sort_by=vote_average.desc",
+	} {
+		if !strings.Contains(capturedQuery, param) {
+			t.Fatalf("expected %q in query: %s", param, capturedQuery)
+		}
+	}
+	// The filter's language and sort replace the client defaults.
+	if strings.Contains(capturedQuery, "with_original_language=en") || strings.Contains(capturedQuery, "popularity.desc") {
+		t.Fatalf("expected the default language filter and sort to be dropped: %s", capturedQuery)
+	}
+
+	// Genre order doesn't matter for caching, so warm-up pages serve later requests.
+	filter.GenreIDs = []int64{14, 16}
+	if _, _, err := svc.DiscoverWithFilter(context.Background(), filter, 20, 0, ShelfLoadOptions{Lite: true}); err != nil {
+		t.Fatalf("DiscoverWithFilter (cached): %v", err)
+	}
+	if requests != 1 {
+		t.Fatalf("expected the second call to hit the cache, got %d discover requests", requests)
+	}
+
+	if _, _, err := svc.DiscoverWithFilter(context.Background(), models.DiscoverFilter{MediaType: "movie", YearFrom: 2000, YearTo: 1990}, 20, 0, ShelfLoadOptions{}); err == nil {
+		t.Fatal("expected an error for an inverted year range")
+	}
+}
+
+func TestTMDBDiscoverFilterQueryWatchProviders(t *testing.T) {
+	query := tmdbDiscoverFilterQuery("series", models.DiscoverFilter{
+		MediaType:        "series",
+		YearFrom:         1990,
+		YearTo:           1999,
+		MinRating:        7.5,
+		WatchProviderIDs: []int64{8, 337},
+		WatchRegion:      "gb",
+	})
+	for _, param := range []string{
+		"first_air_date.gte=1990-01-01",
+		"first_air_date.lte=1999-12-31",
+		"vote_average.gte=7.5",
+		"with_watch_providers=8%7C337",
+		"watch_region=GB",
+	} {
+		if !strings.Contains(query, param) {
+			t.Fatalf("expected %q in query: %s", param, query)
+		}
+	}
+	if query := tmdbDiscoverFilterQuery("movie", models.DiscoverFilter{MediaType: "movie", WatchRegion: "GB"}); strings.Contains(query, "watch_region") {
+		t.Fatalf("expected no watch region without providers: %s", query)
+	}
+}
+
 func TestDiscoverByGenreWithOptionsFiltersOriginalLanguage(t *testing.T) {
 	cache := newFileCache(t.TempDir(), 24)
 	var capturedQuery string
@@ -2023,7 +2266,7 @@ func TestMovieDetailsCacheHitHydratesRatingsFromRatingsCache(t *testing.T) {
 		mdblist:      newMDBListClient("test-key", []string{"tomatoes", "audience"}, true, 24),
 	}
 
-	cacheID := cacheKey("tvdb", "movie", "details", "v5", "eng", "369859")
+	cacheID := cacheKey("tvdb", "movie", "details", "v6", "eng", "369859")
 	if err := svc.cache.set(cacheID, models.Title{
 		ID:            "tvdb:movie:369859",
 		Name:          "Primate",
@@ -2067,7 +2310,7 @@ func TestSeriesDetailsCacheHitHydratesRatingsFromRatingsCache(t *testing.T) {
 		mdblist:      newMDBListClient("test-key", []string{"tomatoes", "audience"}, true, 24),
 	}
 
-	cacheID := cacheKey("tvdb", "series", "details", "v10", "eng", "75805")
+	cacheID := cacheKey("tvdb", "series", "details", "v12", "eng", "75805")
 	if err := svc.cache.set(cacheID, models.SeriesDetails{
 		Title: models.Title{
 			ID:        "tvdb:series:75805",
@@ -2160,3 +2403,186 @@ func TestGetTopTenListSourceUsesSourceCache(t *testing.T) {
 		t.Fatalf("unexpected cached items: %#v", items)
 	}
 }
+
+func TestResolveAITitleCachesTMDBMatch(t *testing.T) {
+	var searches, details int
+	httpc := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			var body string
+			switch req.URL.Path {
+			case "/3/search/movie":
+				searches++
+				body = `{"results":[{"id":603,"title":"The Matrix","release_date":"1999-03-30","poster_path":"/p.jpg"}]}`
+			case "/3/movie/603":
+				details++
+				body = `{"id":603,"title":"The Matrix","release_date":"1999-03-30","poster_path":"/p.jpg"}`
+			default:
+				return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: io.NopCloser(strings.NewReader(`{}`)), Header: make(http.Header)}, nil
+			}
+			return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+		}),
+	}
+	cacheDir := t.TempDir()
+	svc := &Service{
+		tmdb:    newTMDBClient("tmdb-key", "eng", httpc, newFileCache(filepath.Join(cacheDir, "tmdb"), 24)),
+		cache:   newFileCache(cacheDir, 24),
+		idCache: newFileCache(filepath.Join(cacheDir, "ids"), 24),
+	}
+
+	first, err := svc.resolveAITitle(context.Background(), "The Matrix", 1999, "movie")
+	if err != nil || first == nil || first.TMDBID != 603 {
+		t.Fatalf("first resolve = %+v, err=%v", first, err)
+	}
+	second, err := svc.resolveAITitle(context.Background(), " the matrix ", 1999, "movie")
+	if err != nil || second == nil || second.TMDBID != 603 || second.Name != "The Matrix" {
+		t.Fatalf("second resolve = %+v, err=%v", second, err)
+	}
+	if searches != 1 {
+		t.Fatalf("expected one TMDB search, got %d", searches)
+	}
+	if details != 1 {
+		t.Fatalf("expected cached match to load movie details by id once, got %d", details)
+	}
+}
+
+func TestCachedSeasonTranslationsCachesHitsAndMisses(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		fetched = map[string]int{}
+	)
+	httpc := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			path := req.URL.Path
+			if path == "/v4/login" {
+				body := bytes.NewBufferString(`{"data":{"token":"test-token"}}`)
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(body), Header: make(http.Header)}, nil
+			}
+			fetched[path]++
+			if path == "/v4/seasons/100/translations/deu" {
+				body := bytes.NewBufferString(`{"data":{"language":"deu","name":"Staffel Eins","overview":"Übersicht"}}`)
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(body), Header: make(http.Header)}, nil
+			}
+			return &http.Response{StatusCode: http.StatusNotFound, Status: "404 Not Found", Body: io.NopCloser(bytes.NewBufferString(`{}`)), Header: make(http.Header)}, nil
+		}),
+	}
+
+	svc := &Service{
+		client: newTVDBClient("test-api-key", "deu", httpc, 24),
+		cache:  newFileCache(t.TempDir(), 24),
+	}
+	svc.client.limiter = nil
+
+	if svc.peekSeasonTranslations(100, "deu") != nil {
+		t.Fatal("expected no cached translation before the first fetch")
+	}
+	for i := 0; i < 2; i++ {
+		got, err := svc.cachedSeasonTranslations(100, "deu")
+		if err != nil || got == nil || got.Name != "Staffel Eins" {
+			t.Fatalf("cachedSeasonTranslations(100) = %+v, %v", got, err)
+		}
+		missing, err := svc.cachedSeasonTranslations(200, "deu")
+		if err != nil || missing == nil || missing.Name != "" {
+			t.Fatalf("cachedSeasonTranslations(200) = %+v, %v; want empty translation", missing, err)
+		}
+	}
+	if peek := svc.peekSeasonTranslations(100, "deu"); peek == nil || peek.Overview != "Übersicht" {
+		t.Fatalf("peekSeasonTranslations(100) = %+v", peek)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if fetched["/v4/seasons/100/translations/deu"] != 1 || fetched["/v4/seasons/200/translations/deu"] != 1 {
+		t.Fatalf("expected one upstream request per season, got %v", fetched)
+	}
+}
+
+func TestEnrichMovieReleasesPrefersCertificationCountry(t *testing.T) {
+	svc := &Service{
+		cache: newFileCache(t.TempDir(), 24),
+		tmdb:  newTMDBClient("tmdb-key", "en", &http.Client{}, nil),
+	}
+	releases := []models.Release{{Type: "theatrical", Date: "2026-01-10", Country: "US", Released: true}}
+	_ = svc.cache.set(cacheKey("tmdb", "movie", "releases", "v2", "1"), cachedReleasesWithCert{
+		Releases:       releases,
+		Certification:  "PG-13",
+		Certifications: map[string]string{"US": "PG-13", "DE": "12"},
+	})
+	// Entries cached before per-country ratings only carry the US rating.
+	_ = svc.cache.set(cacheKey("tmdb", "movie", "releases", "v2", "2"), cachedReleasesWithCert{
+		Releases:      releases,
+		Certification: "R",
+	})
+
+	svc.SetCertificationCountry("de")
+	var title models.Title
+	svc.enrichMovieReleases(context.Background(), &title, 1)
+	if title.Certification != "12" || title.CertificationCountry != "DE" {
+		t.Fatalf("certification = %q/%q, want 12/DE", title.Certification, title.CertificationCountry)
+	}
+
+	title = models.Title{}
+	svc.enrichMovieReleases(context.Background(), &title, 2)
+	if title.Certification != "R" || title.CertificationCountry != "US" {
+		t.Fatalf("certification = %q/%q, want US fallback R", title.Certification, title.CertificationCountry)
+	}
+}
+
+func TestDiscoverByMoodResolvesKeywords(t *testing.T) {
+	cache := newFileCache(t.TempDir(), 24)
+	var discoverQuery string
+	keywordSearches := 0
+	svc := &Service{
+		client: &tvdbClient{language: "eng"},
+		cache:  cache,
+		tmdb: newTMDBClient("tmdb-key", "eng", &http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				body := `{}`
+				status := http.StatusOK
+				switch {
+				case req.URL.Path == "/3/search/keyword":
+					keywordSearches++
+					switch req.URL.Query().Get("query") {
+					case "heist":
+						body = `{"results":[{"id":10051,"name":"heist"},{"id":99,"name":"heist movie"}]}`
+					case "bank robbery":
+						body = `{"results":[{"id":9748,"name":"Bank Robbery"}]}`
+					default:
+						body = `{"results":[]}`
+					}
+				case req.URL.Path == "/3/discover/movie":
+					discoverQuery = req.URL.RawQuery
+					body = `{"results":[{"id":161,"title":"Ocean's Eleven","release_date":"2001-12-07","popularity":50}],"total_results":1}`
+				case strings.HasSuffix(req.URL.Path, "/images"):
+					body = `{"backdrops":[],"posters":[],"logos":[]}`
+				default:
+					status = http.StatusNotFound
+				}
+				return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body)), Header: make(http.Header)}, nil
+			}),
+		}, cache),
+	}
+	svc.tmdb.limiter = nil
+
+	items, _, err := svc.DiscoverByMood(context.Background(), "movie", "Heist", 20, 0, ShelfLoadOptions{Lite: true})
+	if err != nil {
+		t.Fatalf("DiscoverByMood: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected 1 item, got %d", len(items))
+	}
+	if !strings.Contains(discoverQuery, "with_keywords=10051%7C9748") && !strings.Contains(discoverQuery, "with_keywords=9748%7C10051") {
+		t.Fatalf("expected exact keyword matches in query: %s", discoverQuery)
+	}
+	if !strings.Contains(discoverQuery, "with_genres=80") {
+		t.Fatalf("expected the mood genre in query: %s", discoverQuery)
+	}
+
+	if _, err := svc.SearchKeywords(context.Background(), "Heist"); err != nil || keywordSearches != 3 {
+		t.Fatalf("expected cached keyword search, got err=%v searches=%d", err, keywordSearches)
+	}
+	if _, _, err := svc.DiscoverByMood(context.Background(), "movie", "cozy", 20, 0, ShelfLoadOptions{}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown mood, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+
+	"novastream/services/listshares"
+)
+
+// sharedListSource reads lists other servers publish through their public
+// share links.
+type sharedListSource interface {
+	GetListItems(ctx context.Context, rawURL string) ([]listshares.ListItem, error)
+}
+
+// SetSharedListClient enables other servers' share links
+// (.../shared/lists/{token}) as custom list sources alongside MDBList URLs.
+// They refresh with the rest of the custom lists.
+func (s *Service) SetSharedListClient(client sharedListSource) {
+	s.sharedLists = client
+}
+
+// fetchSharedListItems reads a list shared by another server. Entries carry
+// whatever IDs the other side knew, so enrichment matches by ID where it can.
+func (s *Service) fetchSharedListItems(ctx context.Context, listURL string) ([]mdblistItem, error) {
+	if s.sharedLists == nil {
+		return nil, fmt.Errorf("shared lists %w", ErrNotConfigured)
+	}
+	entries, err := s.sharedLists.GetListItems(ctx, listURL)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]mdblistItem, 0, len(entries))
+	for i, entry := range entries {
+		mediaType := "movie"
+		if entry.MediaType == "series" {
+			mediaType = "show"
+		}
+		item := mdblistItem{
+			Rank:        i + 1,
+			Title:       entry.Title,
+			IMDBID:      entry.IMDBID,
+			ReleaseYear: entry.Year,
+			MediaType:   mediaType,
+		}
+		if entry.TMDBID > 0 {
+			tmdbID := entry.TMDBID
+			item.TMDBID = &tmdbID
+		}
+		if entry.TVDBID > 0 {
+			tvdbID := entry.TVDBID
+			item.TVDBID = &tvdbID
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
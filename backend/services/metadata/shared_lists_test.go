@@ -0,0 +1,42 @@
+package metadata
+
+import (
+	"context"
+	"testing"
+
+	"novastream/services/listshares"
+)
+
+type fakeSharedListSource struct {
+	items []listshares.ListItem
+}
+
+func (f *fakeSharedListSource) GetListItems(context.Context, string) ([]listshares.ListItem, error) {
+	return f.items, nil
+}
+
+func TestFetchCustomListItemsReadsSharedLists(t *testing.T) {
+	svc := &Service{client: &tvdbClient{language: "eng"}}
+	svc.SetSharedListClient(&fakeSharedListSource{items: []listshares.ListItem{
+		{Title: "The Matrix", Year: 1999, IMDBID: "tt0133093", TMDBID: 603, MediaType: "movie"},
+		{Title: "Severance", Year: 2022, TVDBID: 371980, MediaType: "series"},
+	}})
+
+	items, err := svc.fetchCustomListItems(context.Background(), "https://friend.example/shared/lists/tok123")
+	if err != nil {
+		t.Fatalf("fetchCustomListItems: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	if items[0].TMDBID == nil || *items[0].TMDBID != 603 || items[0].Rank != 1 {
+		t.Fatalf("unexpected movie item: %+v", items[0])
+	}
+	if items[1].TVDBID == nil || *items[1].TVDBID != 371980 || mdblistItemMediaType(items[1]) != "series" {
+		t.Fatalf("unexpected series item: %+v", items[1])
+	}
+
+	if clone := svc.WithLanguage("fra"); clone.sharedLists == nil {
+		t.Fatal("expected language clone to keep the shared list source")
+	}
+}
@@ -0,0 +1,591 @@
+package metadata
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"novastream/models"
+)
+
+const (
+	// smartSearchDefaultLimit caps SmartSearch results when no limit is given.
+	smartSearchDefaultLimit = 20
+	// smartSearchMaxLimit is the most results SmartSearch returns.
+	smartSearchMaxLimit = 50
+	// smartSearchAICacheTTL is how long AI parses of a query are kept.
+	smartSearchAICacheTTL = 7 * 24 * time.Hour
+)
+
+// smartSearchGenre maps spoken genre words to TMDB genre IDs. A zero ID
+// means TMDB has no such genre for that media type.
+type smartSearchGenre struct {
+	name   string
+	words  []string
+	movie  int64
+	series int64
+}
+
+var smartSearchGenres = []smartSearchGenre{
+	{"Action", []string{"action"}, 28, 10759},
+	{"Adventure", []string{"adventure", "adventures"}, 12, 10759},
+	{"Animation", []string{"animation", "animated", "cartoon", "cartoons", "anime"}, 16, 16},
+	{"Comedy", []string{"comedy", "comedies", "funny", "sitcom", "sitcoms"}, 35, 35},
+	{"Crime", []string{"crime", "gangster"}, 80, 80},
+	{"Documentary", []string{"documentary", "documentaries", "docuseries"}, 99, 99},
+	{"Drama", []string{"drama", "dramas"}, 18, 18},
+	{"Family", []string{"family"}, 10751, 10751},
+	{"Fantasy", []string{"fantasy"}, 14, 10765},
+	{"History", []string{"history", "historical"}, 36, 0},
+	{"Horror", []string{"horror", "scary"}, 27, 0},
+	{"Music", []string{"music", "musical", "musicals"}, 10402, 0},
+	{"Mystery", []string{"mystery", "mysteries", "whodunit"}, 9648, 9648},
+	{"Romance", []string{"romance", "romantic", "romcom", "romcoms"}, 10749, 0},
+	{"Sci-Fi", []string{"sci-fi", "scifi", "sci fi", "science fiction"}, 878, 10765},
+	{"Thriller", []string{"thriller", "thrillers", "suspense"}, 53, 0},
+	{"War", []string{"war"}, 10752, 10768},
+	{"Western", []string{"western", "westerns"}, 37, 37},
+	{"Reality", []string{"reality"}, 0, 10764},
+	{"Kids", []string{"kids", "children's"}, 10751, 10762},
+}
+
+var (
+	smartSearchGenreWords = func() map[string]int {
+		words := make(map[string]int)
+		for i, g := range smartSearchGenres {
+			words[strings.ToLower(g.name)] = i
+			for _, w := range g.words {
+				words[w] = i
+			}
+		}
+		return words
+	}()
+	smartSearchMediaWords = map[string]string{
+		"movie": "movie", "movies": "movie", "film": "movie", "films": "movie", "flick": "movie", "flicks": "movie",
+		"show": "series", "shows": "series", "series": "series", "tv": "series", "sitcom": "series", "sitcoms": "series",
+	}
+	smartSearchSortWords = map[string]string{
+		"best": "rating", "top": "rating", "top-rated": "rating", "highest": "rating", "highest-rated": "rating",
+		"greatest": "rating", "acclaimed": "rating", "classic": "rating", "classics": "rating",
+		"new": "newest", "newest": "newest", "latest": "newest", "recent": "newest",
+		"oldest": "oldest",
+	}
+	smartSearchDecadeWords = map[string]int{
+		"fifties": 1950, "sixties": 1960, "seventies": 1970, "eighties": 1980, "nineties": 1990,
+	}
+	smartSearchCastCues = map[string]bool{"with": true, "starring": true, "featuring": true, "feat": true}
+	smartSearchFiller   = map[string]bool{
+		"a": true, "an": true, "the": true, "some": true, "any": true, "me": true, "i": true, "we": true,
+		"want": true, "wanna": true, "to": true, "watch": true, "find": true, "search": true, "for": true,
+		"play": true, "please": true, "give": true, "recommend": true, "good": true, "great": true,
+		"from": true, "in": true, "of": true, "made": true, "released": true, "that": true, "are": true,
+		"is": true, "rated": true, "critically": true, "actor": true, "actress": true, "and": true,
+		"something": true, "like": true, "era": true, "year": true, "years": true, "decade": true,
+	}
+	smartSearchShortDecade = regexp.MustCompile(`^'?(\d)0'?s$`)
+	smartSearchLongDecade  = regexp.MustCompile(`^(1[89]\d0|20\d0)'?s$`)
+)
+
+// smartQuery is the structure read from a natural language search.
+type smartQuery struct {
+	mediaType  string // "movie", "series" or "" for both
+	genres     []int  // indexes into smartSearchGenres
+	yearFrom   int
+	yearTo     int
+	cast       []string
+	keywords   []string
+	sort       string
+	unmatched  []string
+	aiAssisted bool
+}
+
+func (q smartQuery) structured() bool {
+	return len(q.genres) > 0 || q.yearFrom > 0 || q.yearTo > 0 || len(q.cast) > 0 || len(q.keywords) > 0 || q.sort != ""
+}
+
+func (q *smartQuery) addGenre(idx int) {
+	for _, existing := range q.genres {
+		if existing == idx {
+			return
+		}
+	}
+	q.genres = append(q.genres, idx)
+}
+
+// smartSearchTokens lowercases a query and splits it into words, keeping
+// apostrophes and hyphens inside words ("90's", "sci-fi").
+func smartSearchTokens(query string) []string {
+	query = strings.ReplaceAll(strings.ToLower(query), "’", "'")
+	fields := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '\'' && r != '-'
+	})
+	tokens := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if field = strings.Trim(field, "-"); field != "" && field != "'" {
+			tokens = append(tokens, field)
+		}
+	}
+	return tokens
+}
+
+// smartSearchDecade returns the first year of the decade a word names, such
+// as "90s", "'90s", "1990s" or "nineties". Two-digit decades up to the
+// current one are read as this century.
+func smartSearchDecade(word string) int {
+	if decade, ok := smartSearchDecadeWords[word]; ok {
+		return decade
+	}
+	if m := smartSearchLongDecade.FindStringSubmatch(word); m != nil {
+		decade, _ := strconv.Atoi(m[1])
+		return decade
+	}
+	if m := smartSearchShortDecade.FindStringSubmatch(word); m != nil {
+		tens, _ := strconv.Atoi(m[1])
+		if decade := 2000 + tens*10; decade <= time.Now().Year() {
+			return decade
+		}
+		return 1900 + tens*10
+	}
+	return 0
+}
+
+func smartSearchYear(word string) int {
+	if len(word) != 4 {
+		return 0
+	}
+	year, err := strconv.Atoi(word)
+	if err != nil || year < 1870 || year > time.Now().Year()+5 {
+		return 0
+	}
+	return year
+}
+
+// parseSmartQuery reads media type, genres, decade or year, cast, sort
+// order and leftover words from a query such as "90s sci-fi movies with
+// Keanu Reeves".
+func parseSmartQuery(query string) smartQuery {
+	var q smartQuery
+	tokens := smartSearchTokens(query)
+	recognized := func(i int) bool {
+		tok := tokens[i]
+		if i+1 < len(tokens) {
+			if _, ok := smartSearchGenreWords[tok+" "+tokens[i+1]]; ok {
+				return true
+			}
+		}
+		_, genre := smartSearchGenreWords[tok]
+		_, media := smartSearchMediaWords[tok]
+		_, sortWord := smartSearchSortWords[tok]
+		return genre || media || sortWord || smartSearchCastCues[tok] || smartSearchDecade(tok) > 0 || smartSearchYear(tok) > 0 ||
+			tok == "from" || tok == "in" || tok == "before" || tok == "after" || tok == "since"
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		prev := ""
+		if i > 0 {
+			prev = tokens[i-1]
+		}
+		if i+1 < len(tokens) {
+			pair := tok + " " + tokens[i+1]
+			if pair == "show me" {
+				i++
+				continue
+			}
+			if idx, ok := smartSearchGenreWords[pair]; ok {
+				q.addGenre(idx)
+				i++
+				continue
+			}
+		}
+		if idx, ok := smartSearchGenreWords[tok]; ok {
+			q.addGenre(idx)
+			continue
+		}
+		if media, ok := smartSearchMediaWords[tok]; ok {
+			if q.mediaType == "" {
+				q.mediaType = media
+			}
+			continue
+		}
+		if order, ok := smartSearchSortWords[tok]; ok {
+			q.sort = order
+			continue
+		}
+		if decade := smartSearchDecade(tok); decade > 0 {
+			q.yearFrom, q.yearTo = decade, decade+9
+			continue
+		}
+		if year := smartSearchYear(tok); year > 0 {
+			switch prev {
+			case "after":
+				q.yearFrom, q.yearTo = year+1, 0
+			case "since":
+				q.yearFrom, q.yearTo = year, 0
+			case "before":
+				q.yearFrom, q.yearTo = 0, year-1
+			default:
+				q.yearFrom, q.yearTo = year, year
+			}
+			continue
+		}
+		if smartSearchCastCues[tok] {
+			// The name runs to the next recognized word; "and" separates names.
+			var name []string
+			j := i + 1
+			for ; j < len(tokens) && !recognized(j); j++ {
+				if tokens[j] == "and" {
+					if len(name) > 0 {
+						q.cast = append(q.cast, strings.Join(name, " "))
+					}
+					name = nil
+					continue
+				}
+				name = append(name, tokens[j])
+			}
+			if len(name) > 0 {
+				q.cast = append(q.cast, strings.Join(name, " "))
+			}
+			i = j - 1
+			continue
+		}
+		if smartSearchFiller[tok] || tok == "before" || tok == "after" || tok == "since" {
+			continue
+		}
+		q.unmatched = append(q.unmatched, tok)
+	}
+	return q
+}
+
+// aiSmartQuery asks the AI provider to parse a query the rules couldn't
+// fully read. Parses are cached for smartSearchAICacheTTL.
+func (s *Service) aiSmartQuery(ctx context.Context, query string) (smartQuery, error) {
+	key := cacheKey("ai", "smart-search", "v1", strings.ToLower(query))
+	var parsed aiSearchQuery
+	if ok, _ := s.cache.getWithMaxAge(key, &parsed, smartSearchAICacheTTL); !ok {
+		var err error
+		if parsed, err = s.ai.parseSearchQuery(ctx, query); err != nil {
+			return smartQuery{}, err
+		}
+		if err := s.cache.set(key, parsed); err != nil {
+			log.Printf("[metadata] failed to cache smart search parse: %v", err)
+		}
+	}
+
+	q := smartQuery{aiAssisted: true}
+	switch strings.ToLower(strings.TrimSpace(parsed.MediaType)) {
+	case "movie", "movies":
+		q.mediaType = "movie"
+	case "series", "tv", "show":
+		q.mediaType = "series"
+	}
+	for _, genre := range parsed.Genres {
+		if idx, ok := smartSearchGenreWords[strings.ToLower(strings.TrimSpace(genre))]; ok {
+			q.addGenre(idx)
+		}
+	}
+	if smartSearchYear(strconv.Itoa(parsed.YearFrom)) > 0 {
+		q.yearFrom = parsed.YearFrom
+	}
+	if smartSearchYear(strconv.Itoa(parsed.YearTo)) > 0 && parsed.YearTo >= q.yearFrom {
+		q.yearTo = parsed.YearTo
+	}
+	for _, name := range parsed.Cast {
+		if name = strings.TrimSpace(name); name != "" {
+			q.cast = append(q.cast, name)
+		}
+	}
+	for _, keyword := range parsed.Keywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" && len(q.keywords) < 3 {
+			q.keywords = append(q.keywords, keyword)
+		}
+	}
+	switch parsed.Sort {
+	case "rating", "newest", "oldest":
+		q.sort = parsed.Sort
+	}
+	return q, nil
+}
+
+// SmartSearch answers a natural language query such as "90s sci-fi movies
+// with Keanu Reeves" by reading it into discover filters (genre, decade or
+// year, cast, keywords and sort order) and running them. Queries the rules
+// can't fully read are handed to the AI provider when one is configured.
+// Cast is resolved to TMDB people and leftover words to TMDB keywords;
+// queries that still carry no filters fall back to a title search.
+func (s *Service) SmartSearch(ctx context.Context, query string, limit int) (*models.SmartSearchResult, error) {
+	query = strings.TrimSpace(query)
+	result := &models.SmartSearchResult{Query: query, Source: models.SmartSearchSourceSearch, Items: []models.TrendingItem{}}
+	if query == "" {
+		return result, nil
+	}
+	if limit <= 0 {
+		limit = smartSearchDefaultLimit
+	}
+	if limit > smartSearchMaxLimit {
+		limit = smartSearchMaxLimit
+	}
+
+	q := parseSmartQuery(query)
+	if (!q.structured() || len(q.unmatched) > 0) && s.ai.isConfigured() {
+		aiQuery, err := s.aiSmartQuery(ctx, query)
+		if err != nil {
+			log.Printf("[metadata] smart search AI parse failed query=%q: %v", query, err)
+		} else if aiQuery.structured() {
+			q = aiQuery
+		}
+	}
+
+	castIDs, keywordIDs := s.resolveSmartQuery(ctx, q, &result.Parsed)
+	structured := len(q.genres) > 0 || q.yearFrom > 0 || q.yearTo > 0 || len(castIDs) > 0 || len(keywordIDs) > 0 || q.sort != ""
+	if !structured {
+		results, err := s.Search(ctx, query, q.mediaType)
+		if err != nil {
+			return nil, err
+		}
+		for i, r := range results {
+			if i == limit {
+				break
+			}
+			result.Items = append(result.Items, models.TrendingItem{Rank: i + 1, Title: r.Title})
+		}
+		return result, nil
+	}
+
+	if len(castIDs) > 0 && q.mediaType == "series" {
+		// TMDB's discover can't filter series by cast.
+		items, err := s.smartSearchFilmography(ctx, q, castIDs, limit)
+		if err != nil {
+			return nil, err
+		}
+		result.Source = models.SmartSearchSourceFilmography
+		result.Items = items
+		return result, nil
+	}
+
+	mediaTypes := []string{"movie", "series"}
+	switch {
+	case q.mediaType != "":
+		mediaTypes = []string{q.mediaType}
+	case len(castIDs) > 0:
+		mediaTypes = []string{"movie"}
+	}
+	for _, mediaType := range mediaTypes {
+		if filter, ok := smartSearchFilter(q, mediaType, castIDs, keywordIDs); ok {
+			result.Filters = append(result.Filters, filter)
+		}
+	}
+	result.Source = models.SmartSearchSourceDiscover
+
+	lists := make([][]models.TrendingItem, len(result.Filters))
+	errs := make([]error, len(result.Filters))
+	var wg sync.WaitGroup
+	for i, filter := range result.Filters {
+		wg.Add(1)
+		go func(i int, filter models.DiscoverFilter) {
+			defer wg.Done()
+			lists[i], _, errs[i] = s.DiscoverWithFilter(ctx, filter, limit, 0, ShelfLoadOptions{})
+		}(i, filter)
+	}
+	wg.Wait()
+
+	var movies, series []models.TrendingItem
+	failed := 0
+	for i, filter := range result.Filters {
+		if errs[i] != nil {
+			log.Printf("[metadata] smart search discover failed query=%q type=%s: %v", query, filter.MediaType, errs[i])
+			failed++
+			continue
+		}
+		if filter.MediaType == "movie" {
+			movies = lists[i]
+		} else {
+			series = lists[i]
+		}
+	}
+	if failed > 0 && failed == len(result.Filters) {
+		return nil, errs[0]
+	}
+	result.Items = interleaveGenreHubItems(movies, series, limit)
+	return result, nil
+}
+
+// resolveSmartQuery looks up the query's cast as TMDB people and its
+// keywords, and leftover words, as TMDB keywords, recording what it found
+// in parsed. Cast names that match no one are tried as keywords, so "with
+// aliens" still finds alien films.
+func (s *Service) resolveSmartQuery(ctx context.Context, q smartQuery, parsed *models.SmartSearchParse) (castIDs, keywordIDs []int64) {
+	parsed.MediaType = q.mediaType
+	parsed.YearFrom, parsed.YearTo = q.yearFrom, q.yearTo
+	parsed.Sort = q.sort
+	parsed.AIAssisted = q.aiAssisted
+	for _, idx := range q.genres {
+		parsed.Genres = append(parsed.Genres, smartSearchGenres[idx].name)
+	}
+
+	keywords := append([]string(nil), q.keywords...)
+	for _, name := range q.cast {
+		person, ok := s.smartSearchPerson(ctx, name)
+		if !ok {
+			keywords = append(keywords, name)
+			continue
+		}
+		castIDs = append(castIDs, person.ID)
+		parsed.Cast = append(parsed.Cast, person)
+	}
+	if len(q.unmatched) > 0 {
+		keywords = append(keywords, strings.Join(q.unmatched, " "))
+	}
+
+	var unmatched []string
+	for _, phrase := range keywords {
+		keyword, ok := s.smartSearchKeyword(ctx, phrase)
+		if !ok {
+			unmatched = append(unmatched, phrase)
+			continue
+		}
+		keywordIDs = append(keywordIDs, keyword.ID)
+		parsed.Keywords = append(parsed.Keywords, keyword)
+	}
+	parsed.Unmatched = strings.Join(unmatched, " ")
+	return castIDs, keywordIDs
+}
+
+// smartSearchPerson returns the best-known person named like name.
+func (s *Service) smartSearchPerson(ctx context.Context, name string) (models.Person, bool) {
+	people, err := s.searchPeople(ctx, name)
+	if err != nil {
+		log.Printf("[metadata] smart search person lookup failed name=%q: %v", name, err)
+		return models.Person{}, false
+	}
+	tokens := localSearchTokens(name)
+	for _, p := range people {
+		if searchNameSimilarity(tokens, localSearchTokens(p.Person.Name)) >= 0.9 {
+			return *p.Person, true
+		}
+	}
+	return models.Person{}, false
+}
+
+// smartSearchKeyword returns the TMDB keyword named phrase, also trying it
+// without a plural "s".
+func (s *Service) smartSearchKeyword(ctx context.Context, phrase string) (models.Keyword, bool) {
+	keywords, err := s.SearchKeywords(ctx, phrase)
+	if err != nil {
+		return models.Keyword{}, false
+	}
+	singular := strings.TrimSuffix(phrase, "s")
+	for _, keyword := range keywords {
+		if strings.EqualFold(keyword.Name, phrase) || strings.EqualFold(keyword.Name, singular) {
+			return keyword, true
+		}
+	}
+	return models.Keyword{}, false
+}
+
+// smartSearchFilter builds the discover filter for mediaType. ok is false
+// when a requested genre doesn't exist for the media type.
+func smartSearchFilter(q smartQuery, mediaType string, castIDs, keywordIDs []int64) (models.DiscoverFilter, bool) {
+	filter := models.DiscoverFilter{
+		MediaType:  mediaType,
+		YearFrom:   q.yearFrom,
+		YearTo:     q.yearTo,
+		KeywordIDs: keywordIDs,
+		Sort:       q.sort,
+	}
+	if mediaType == "movie" {
+		filter.CastIDs = castIDs
+	}
+	for _, idx := range q.genres {
+		id := smartSearchGenres[idx].movie
+		if mediaType == "series" {
+			id = smartSearchGenres[idx].series
+		}
+		if id == 0 {
+			return models.DiscoverFilter{}, false
+		}
+		filter.GenreIDs = append(filter.GenreIDs, id)
+	}
+	return filter, true
+}
+
+// smartSearchFilmography returns the series all of castIDs appeared in that
+// match the query's genres and years.
+func (s *Service) smartSearchFilmography(ctx context.Context, q smartQuery, castIDs []int64, limit int) ([]models.TrendingItem, error) {
+	var titles []models.Title
+	for i, id := range castIDs {
+		details, err := s.PersonDetails(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			titles = details.Filmography
+			continue
+		}
+		credited := make(map[string]bool, len(details.Filmography))
+		for _, title := range details.Filmography {
+			credited[title.ID] = true
+		}
+		kept := titles[:0:0]
+		for _, title := range titles {
+			if credited[title.ID] {
+				kept = append(kept, title)
+			}
+		}
+		titles = kept
+	}
+
+	var genres []string
+	for _, idx := range q.genres {
+		genres = append(genres, tmdbTVGenres[int(smartSearchGenres[idx].series)])
+	}
+	var matches []models.Title
+	for _, title := range titles {
+		if title.MediaType != "series" ||
+			(q.yearFrom > 0 && title.Year < q.yearFrom) || (q.yearTo > 0 && (title.Year == 0 || title.Year > q.yearTo)) ||
+			!hasAllGenres(title.Genres, genres) {
+			continue
+		}
+		matches = append(matches, title)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		switch q.sort {
+		case "newest":
+			return matches[i].Year > matches[j].Year
+		case "oldest":
+			return matches[i].Year < matches[j].Year
+		}
+		return matches[i].Popularity > matches[j].Popularity
+	})
+
+	items := make([]models.TrendingItem, 0, min(limit, len(matches)))
+	for i, title := range matches {
+		if i == limit {
+			break
+		}
+		items = append(items, models.TrendingItem{Rank: i + 1, Title: title})
+	}
+	return items, nil
+}
+
+func hasAllGenres(have, want []string) bool {
+	for _, w := range want {
+		found := false
+		for _, h := range have {
+			if strings.EqualFold(h, w) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"novastream/models"
+)
+
+func TestParseSmartQuery(t *testing.T) {
+	genres := func(names ...string) []int {
+		var out []int
+		for _, name := range names {
+			out = append(out, smartSearchGenreWords[name])
+		}
+		return out
+	}
+	cases := []struct {
+		query string
+		want  smartQuery
+	}{
+		{"90s sci-fi movies with Keanu Reeves", smartQuery{mediaType: "movie", genres: genres("sci-fi"), yearFrom: 1990, yearTo: 1999, cast: []string{"keanu reeves"}}},
+		{"Show me science fiction shows from the 1980s", smartQuery{mediaType: "series", genres: genres("sci-fi"), yearFrom: 1980, yearTo: 1989}},
+		{"best horror films since 2015", smartQuery{mediaType: "movie", genres: genres("horror"), yearFrom: 2015, sort: "rating"}},
+		{"comedies starring Tom Hanks and Meg Ryan before 2000", smartQuery{genres: genres("comedy"), yearTo: 1999, cast: []string{"tom hanks", "meg ryan"}}},
+		{"newest heist thrillers", smartQuery{genres: genres("thriller"), sort: "newest", unmatched: []string{"heist"}}},
+		{"the matrix", smartQuery{unmatched: []string{"matrix"}}},
+	}
+	for _, tc := range cases {
+		if got := parseSmartQuery(tc.query); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("parseSmartQuery(%q) = %+v, want %+v", tc.query, got, tc.want)
+		}
+	}
+}
+
+func TestSmartSearchDecade(t *testing.T) {
+	cases := map[string]int{"90s": 1990, "'90s": 1990, "1990s": 1990, "nineties": 1990, "10s": 2010, "1880s": 1880, "90": 0, "sci-fi": 0}
+	for word, want := range cases {
+		if got := smartSearchDecade(word); got != want {
+			t.Errorf("smartSearchDecade(%q) = %d, want %d", word, got, want)
+		}
+	}
+}
+
+func TestSmartSearchRunsDiscoverWithResolvedCast(t *testing.T) {
+	var mu sync.Mutex
+	var discover []string
+	httpc := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body := `{"results":[]}`
+			switch req.URL.Path {
+			case "/3/search/person":
+				body = `{"results":[{"id":6384,"name":"Keanu Reeves","known_for_department":"Acting","popularity":50}]}`
+			case "/3/discover/movie":
+				mu.Lock()
+				discover = append(discover, req.URL.RawQuery)
+				mu.Unlock()
+				body = `{"total_results":1,"results":[{"id":603,"title":"The Matrix","release_date":"1999-03-31","popularity":60,"genre_ids":[878]}]}`
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(body)), Header: make(http.Header)}, nil
+		}),
+	}
+	cacheDir := t.TempDir()
+	svc := &Service{
+		client: newTVDBClient("test-tvdb-key", "eng", httpc, 24),
+		tmdb:   newTMDBClient("test-tmdb-key", "eng", httpc, newFileCache(cacheDir, 24)),
+		cache:  newFileCache(cacheDir, 24),
+	}
+	svc.client.limiter = nil
+
+	result, err := svc.SmartSearch(context.Background(), "90s sci-fi movies with Keanu Reeves", 0)
+	if err != nil {
+		t.Fatalf("SmartSearch failed: %v", err)
+	}
+	if result.Source != models.SmartSearchSourceDiscover || len(result.Filters) != 1 {
+		t.Fatalf("expected one discover filter, got %+v", result)
+	}
+	want := models.DiscoverFilter{MediaType: "movie", GenreIDs: []int64{878}, CastIDs: []int64{6384}, YearFrom: 1990, YearTo: 1999}
+	if !reflect.DeepEqual(result.Filters[0], want) {
+		t.Fatalf("filter = %+v, want %+v", result.Filters[0], want)
+	}
+	if len(result.Parsed.Cast) != 1 || result.Parsed.Cast[0].Name != "Keanu Reeves" || result.Parsed.Unmatched != "" {
+		t.Fatalf("unexpected parse %+v", result.Parsed)
+	}
+	if len(result.Items) != 1 || result.Items[0].Title.Name != "The Matrix" {
+		t.Fatalf("unexpected items %+v", result.Items)
+	}
+	if len(discover) != 1 || !bytes.Contains([]byte(discover[0]), []byte("with_cast=6384")) {
+		t.Fatalf("discover requests %v, want with_cast=6384", discover)
+	}
+}
+
+func TestSmartSearchFallsBackToTitleSearch(t *testing.T) {
+	httpc := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			body := `{"results":[]}`
+			switch req.URL.Path {
+			case "/v4/login":
+				body = `{"data":{"token":"test-token"}}`
+			case "/3/search/movie":
+				body = `{"results":[{"id":603,"title":"The Matrix","release_date":"1999-03-31","popularity":60}]}`
+			case "/3/search/tv", "/v4/search":
+				body = `{"results":[],"data":[]}`
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewBufferString(body)), Header: make(http.Header)}, nil
+		}),
+	}
+	cacheDir := t.TempDir()
+	svc := &Service{
+		client: newTVDBClient("test-tvdb-key", "eng", httpc, 24),
+		tmdb:   newTMDBClient("test-tmdb-key", "eng", httpc, newFileCache(cacheDir, 24)),
+		cache:  newFileCache(cacheDir, 24),
+	}
+	svc.client.limiter = nil
+
+	result, err := svc.SmartSearch(context.Background(), "the matrix movie", 5)
+	if err != nil {
+		t.Fatalf("SmartSearch failed: %v", err)
+	}
+	if result.Source != models.SmartSearchSourceSearch || len(result.Filters) != 0 || result.Parsed.Unmatched != "matrix" {
+		t.Fatalf("expected title search fallback, got %+v", result)
+	}
+	if len(result.Items) == 0 || result.Items[0].Title.Name != "The Matrix" {
+		t.Fatalf("unexpected items %+v", result.Items)
+	}
+}
@@ -0,0 +1,398 @@
+package metadata
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const (
+	// CacheBackendFile stores each cache entry as a JSON file (the default).
+	CacheBackendFile = "file"
+	// CacheBackendSQLite stores all cache entries in a single SQLite database.
+	CacheBackendSQLite = "sqlite"
+)
+
+// sqliteCacheEvictionInterval is how many writes may happen between size checks.
+// Summing entry sizes on every write would dominate the cost of a set.
+const sqliteCacheEvictionInterval = 128
+
+// sqliteCacheEvictionTarget is the fraction of the size limit eviction shrinks
+// the cache to, so a full cache doesn't evict on every check.
+const sqliteCacheEvictionTarget = 0.9
+
+// sqliteCacheAccessResolution limits how often reads refresh an entry's
+// last-access time, keeping hot keys from turning every get into a write.
+const sqliteCacheAccessResolution = time.Hour
+
+// sqliteCacheMigrationBatch is how many files are imported per transaction.
+const sqliteCacheMigrationBatch = 500
+
+const sqliteCacheSchema = `
+CREATE TABLE IF NOT EXISTS metadata_cache (
+	namespace   TEXT    NOT NULL,
+	key         TEXT    NOT NULL,
+	value       BLOB    NOT NULL,
+	size        INTEGER NOT NULL,
+	updated_at  INTEGER NOT NULL,
+	accessed_at INTEGER NOT NULL,
+	PRIMARY KEY (namespace, key)
+);
+CREATE INDEX IF NOT EXISTS idx_metadata_cache_accessed ON metadata_cache(accessed_at);
+`
+
+// sqliteCacheDB is a SQLite database shared by several cache namespaces. The
+// size limit applies to the database as a whole; when it is exceeded the least
+// recently accessed entries are evicted regardless of namespace.
+type sqliteCacheDB struct {
+	db       *sql.DB
+	path     string
+	maxBytes int64
+
+	mu     sync.Mutex
+	writes int
+}
+
+func openSQLiteCacheDB(path string, maxBytes int64) (*sqliteCacheDB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create cache directory: %w", err)
+	}
+	connString := fmt.Sprintf("%s?_journal_mode=WAL&_synchronous=NORMAL&_busy_timeout=30000", path)
+	db, err := sql.Open("sqlite3", connString)
+	if err != nil {
+		return nil, fmt.Errorf("open metadata cache database: %w", err)
+	}
+	db.SetMaxOpenConns(4)
+	db.SetMaxIdleConns(2)
+	if _, err := db.Exec(sqliteCacheSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("initialize metadata cache schema: %w", err)
+	}
+	c := &sqliteCacheDB{db: db, path: path, maxBytes: maxBytes}
+	c.evictIfNeeded()
+	return c, nil
+}
+
+// namespace returns a cacheStore view over one namespace of the database.
+func (c *sqliteCacheDB) namespace(name string, ttlHours int) *sqliteCache {
+	return &sqliteCache{db: c, namespace: name, ttl: time.Duration(ttlHours) * time.Hour}
+}
+
+func (c *sqliteCacheDB) close() error {
+	return c.db.Close()
+}
+
+// noteWrite counts a write and runs eviction every sqliteCacheEvictionInterval writes.
+func (c *sqliteCacheDB) noteWrite() {
+	if c.maxBytes <= 0 {
+		return
+	}
+	c.mu.Lock()
+	c.writes++
+	due := c.writes >= sqliteCacheEvictionInterval
+	if due {
+		c.writes = 0
+	}
+	c.mu.Unlock()
+	if due {
+		c.evictIfNeeded()
+	}
+}
+
+// evictIfNeeded removes least recently accessed entries until the database is
+// back under sqliteCacheEvictionTarget of its size limit.
+func (c *sqliteCacheDB) evictIfNeeded() {
+	if c.maxBytes <= 0 {
+		return
+	}
+	var total int64
+	if err := c.db.QueryRow(`SELECT COALESCE(SUM(size), 0) FROM metadata_cache`).Scan(&total); err != nil {
+		log.Printf("[metadata] sqlite cache: size check failed: %v", err)
+		return
+	}
+	if total <= c.maxBytes {
+		return
+	}
+
+	target := int64(float64(c.maxBytes) * sqliteCacheEvictionTarget)
+	rows, err := c.db.Query(`SELECT namespace, key, size FROM metadata_cache ORDER BY accessed_at ASC`)
+	if err != nil {
+		log.Printf("[metadata] sqlite cache: eviction scan failed: %v", err)
+		return
+	}
+	type victim struct{ namespace, key string }
+	var victims []victim
+	remaining := total
+	for rows.Next() && remaining > target {
+		var v victim
+		var size int64
+		if err := rows.Scan(&v.namespace, &v.key, &size); err != nil {
+			break
+		}
+		victims = append(victims, v)
+		remaining -= size
+	}
+	rows.Close()
+
+	tx, err := c.db.Begin()
+	if err != nil {
+		log.Printf("[metadata] sqlite cache: eviction failed: %v", err)
+		return
+	}
+	stmt, err := tx.Prepare(`DELETE FROM metadata_cache WHERE namespace = ? AND key = ?`)
+	if err != nil {
+		tx.Rollback()
+		log.Printf("[metadata] sqlite cache: eviction failed: %v", err)
+		return
+	}
+	for _, v := range victims {
+		if _, err := stmt.Exec(v.namespace, v.key); err != nil {
+			stmt.Close()
+			tx.Rollback()
+			log.Printf("[metadata] sqlite cache: eviction failed: %v", err)
+			return
+		}
+	}
+	stmt.Close()
+	if err := tx.Commit(); err != nil {
+		log.Printf("[metadata] sqlite cache: eviction failed: %v", err)
+		return
+	}
+	log.Printf("[metadata] sqlite cache: evicted %d entries (%d -> %d bytes, limit %d)", len(victims), total, remaining, c.maxBytes)
+}
+
+// sqliteCache is one namespace ("metadata", "ids", "ratings") of a sqliteCacheDB.
+type sqliteCache struct {
+	db        *sqliteCacheDB
+	namespace string
+	ttl       time.Duration
+}
+
+var _ cacheStore = (*sqliteCache)(nil)
+
+func (c *sqliteCache) get(key string, v any) (bool, error) {
+	return c.getWithMaxAge(key, v, 0)
+}
+
+// getWithMaxAge mirrors fileCache.getWithMaxAge: maxAge of 0 uses the
+// namespace's jittered TTL, and expired entries are removed on read.
+func (c *sqliteCache) getWithMaxAge(key string, v any, maxAge time.Duration) (bool, error) {
+	if key == "" {
+		return false, errors.New("empty key")
+	}
+	var value []byte
+	var updatedAt, accessedAt int64
+	err := c.db.db.QueryRow(
+		`SELECT value, updated_at, accessed_at FROM metadata_cache WHERE namespace = ? AND key = ?`,
+		c.namespace, key,
+	).Scan(&value, &updatedAt, &accessedAt)
+	if err != nil {
+		return false, nil
+	}
+
+	ttl := jitteredCacheTTL(c.ttl, key)
+	if maxAge > 0 {
+		ttl = maxAge
+	}
+	now := time.Now()
+	if now.Sub(time.UnixMilli(updatedAt)) > ttl && !offlineMode.Load() {
+		_, _ = c.db.db.Exec(`DELETE FROM metadata_cache WHERE namespace = ? AND key = ?`, c.namespace, key)
+		return false, nil
+	}
+	if err := json.Unmarshal(value, v); err != nil {
+		return false, nil
+	}
+	if now.Sub(time.UnixMilli(accessedAt)) > sqliteCacheAccessResolution {
+		_, _ = c.db.db.Exec(
+			`UPDATE metadata_cache SET accessed_at = ? WHERE namespace = ? AND key = ?`,
+			now.UnixMilli(), c.namespace, key,
+		)
+	}
+	return true, nil
+}
+
+func (c *sqliteCache) set(key string, v any) error {
+	if key == "" {
+		return errors.New("empty key")
+	}
+	if offlineMode.Load() {
+		return nil // frozen, see fileCache.set
+	}
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UnixMilli()
+	_, err = c.db.db.Exec(
+		`INSERT INTO metadata_cache (namespace, key, value, size, updated_at, accessed_at)
+		 VALUES (?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(namespace, key) DO UPDATE SET
+		   value = excluded.value, size = excluded.size,
+		   updated_at = excluded.updated_at, accessed_at = excluded.accessed_at`,
+		c.namespace, key, value, len(value), now, now,
+	)
+	if err != nil {
+		return err
+	}
+	c.db.noteWrite()
+	return nil
+}
+
+func (c *sqliteCache) clear() error {
+	_, err := c.db.db.Exec(`DELETE FROM metadata_cache WHERE namespace = ?`, c.namespace)
+	return err
+}
+
+func (c *sqliteCache) keys() ([]string, error) {
+	rows, err := c.db.db.Query(`SELECT key FROM metadata_cache WHERE namespace = ?`, c.namespace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (c *sqliteCache) stats() (int, int64, error) {
+	var count int
+	var size int64
+	err := c.db.db.QueryRow(
+		`SELECT COUNT(*), COALESCE(SUM(size), 0) FROM metadata_cache WHERE namespace = ?`,
+		c.namespace,
+	).Scan(&count, &size)
+	return count, size, err
+}
+
+func (c *sqliteCache) entries() ([]cacheEntry, error) {
+	rows, err := c.db.db.Query(`SELECT key, size, updated_at FROM metadata_cache WHERE namespace = ?`, c.namespace)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var entries []cacheEntry
+	for rows.Next() {
+		var entry cacheEntry
+		var updatedAt int64
+		if err := rows.Scan(&entry.key, &entry.sizeBytes, &updatedAt); err != nil {
+			return nil, err
+		}
+		entry.updatedAt = time.UnixMilli(updatedAt)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func (c *sqliteCache) delete(key string) error {
+	if key == "" {
+		return errors.New("empty key")
+	}
+	_, err := c.db.db.Exec(`DELETE FROM metadata_cache WHERE namespace = ? AND key = ?`, c.namespace, key)
+	return err
+}
+
+// migrateFromDir imports the .json files of a file cache directory into the
+// namespace, keeping each file's modification time as the entry's age so TTLs
+// carry over. Imported files are removed; entries already in the database win
+// over files. Returns the number of entries imported.
+func (c *sqliteCache) migrateFromDir(dir string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	var imported int
+	var batch []string
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		tx, err := c.db.db.Begin()
+		if err != nil {
+			return err
+		}
+		stmt, err := tx.Prepare(
+			`INSERT OR IGNORE INTO metadata_cache (namespace, key, value, size, updated_at, accessed_at)
+			 VALUES (?, ?, ?, ?, ?, ?)`,
+		)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		var done []string
+		for _, path := range batch {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			raw, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			// Re-encode compactly; the file cache writes indented JSON.
+			var compact bytes.Buffer
+			if err := json.Compact(&compact, raw); err != nil {
+				done = append(done, path)
+				continue
+			}
+			value := compact.Bytes()
+			key := strings.TrimSuffix(filepath.Base(path), ".json")
+			modified := info.ModTime().UnixMilli()
+			res, err := stmt.Exec(c.namespace, key, value, len(value), modified, modified)
+			if err != nil {
+				stmt.Close()
+				tx.Rollback()
+				return err
+			}
+			done = append(done, path)
+			if n, _ := res.RowsAffected(); n > 0 {
+				imported++
+			}
+		}
+		stmt.Close()
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		for _, path := range done {
+			_ = os.Remove(path)
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		batch = append(batch, filepath.Join(dir, entry.Name()))
+		if len(batch) >= sqliteCacheMigrationBatch {
+			if err := flush(); err != nil {
+				return imported, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return imported, err
+	}
+	c.db.evictIfNeeded()
+	return imported, nil
+}
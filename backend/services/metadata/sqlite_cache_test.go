@@ -0,0 +1,138 @@
+package metadata
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestSQLiteCacheDB(t *testing.T, maxBytes int64) *sqliteCacheDB {
+	t.Helper()
+	db, err := openSQLiteCacheDB(filepath.Join(t.TempDir(), sqliteCacheFile), maxBytes)
+	if err != nil {
+		t.Fatalf("openSQLiteCacheDB: %v", err)
+	}
+	t.Cleanup(func() { db.close() })
+	return db
+}
+
+func TestSQLiteCache_RoundTripAndNamespaces(t *testing.T) {
+	db := newTestSQLiteCacheDB(t, 0)
+	meta := db.namespace("metadata", 24)
+	ids := db.namespace("ids", 24)
+
+	if err := meta.set("title-1", map[string]string{"name": "Dune"}); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if err := ids.set("title-1", "tt1160419"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	var got map[string]string
+	if ok, _ := meta.get("title-1", &got); !ok || got["name"] != "Dune" {
+		t.Fatalf("expected cached metadata, got ok=%v value=%v", ok, got)
+	}
+	var imdb string
+	if ok, _ := ids.get("title-1", &imdb); !ok || imdb != "tt1160419" {
+		t.Fatalf("expected namespaced id entry, got ok=%v value=%q", ok, imdb)
+	}
+
+	if err := meta.clear(); err != nil {
+		t.Fatalf("clear: %v", err)
+	}
+	if ok, _ := meta.get("title-1", &got); ok {
+		t.Fatal("expected metadata namespace to be cleared")
+	}
+	if keys, _ := ids.keys(); len(keys) != 1 {
+		t.Fatalf("expected clear to leave other namespaces alone, got keys %v", keys)
+	}
+}
+
+func TestSQLiteCache_MaxAgeExpiresEntries(t *testing.T) {
+	db := newTestSQLiteCacheDB(t, 0)
+	meta := db.namespace("metadata", 24)
+	if err := meta.set("stale", "value"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	old := time.Now().Add(-2 * time.Hour).UnixMilli()
+	if _, err := db.db.Exec(`UPDATE metadata_cache SET updated_at = ?`, old); err != nil {
+		t.Fatalf("backdate: %v", err)
+	}
+
+	var v string
+	if ok, _ := meta.getWithMaxAge("stale", &v, time.Hour); ok {
+		t.Fatal("expected entry older than maxAge to miss")
+	}
+	if entries, _, _ := meta.stats(); entries != 0 {
+		t.Fatalf("expected expired entry to be removed, got %d entries", entries)
+	}
+}
+
+func TestSQLiteCache_EvictsLeastRecentlyAccessed(t *testing.T) {
+	payload := strings.Repeat("x", 1000)
+	db := newTestSQLiteCacheDB(t, 3000)
+	meta := db.namespace("metadata", 24)
+	for i, key := range []string{"a", "b", "c", "d"} {
+		if err := meta.set(key, payload); err != nil {
+			t.Fatalf("set %s: %v", key, err)
+		}
+		if _, err := db.db.Exec(`UPDATE metadata_cache SET accessed_at = ? WHERE key = ?`, int64(i+1), key); err != nil {
+			t.Fatalf("set access time: %v", err)
+		}
+	}
+
+	db.evictIfNeeded()
+
+	keys, _ := meta.keys()
+	sort.Strings(keys)
+	if strings.Join(keys, ",") != "c,d" {
+		t.Fatalf("expected oldest entries to be evicted down to 90%% of the limit, got %v", keys)
+	}
+}
+
+func TestSQLiteCache_MigrateFromDir(t *testing.T) {
+	dir := t.TempDir()
+	files := newFileCache(dir, 24)
+	if err := files.set("list-1", []string{"a", "b"}); err != nil {
+		t.Fatalf("file set: %v", err)
+	}
+	if err := files.set("kept", "from-file"); err != nil {
+		t.Fatalf("file set: %v", err)
+	}
+	modified := time.Now().Add(-3 * time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "list-1.json"), modified, modified); err != nil {
+		t.Fatalf("chtimes: %v", err)
+	}
+
+	db := newTestSQLiteCacheDB(t, 0)
+	meta := db.namespace("metadata", 24)
+	if err := meta.set("kept", "from-db"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+
+	imported, err := meta.migrateFromDir(dir)
+	if err != nil {
+		t.Fatalf("migrateFromDir: %v", err)
+	}
+	if imported != 1 {
+		t.Fatalf("expected 1 new entry imported, got %d", imported)
+	}
+	if remaining, _ := files.keys(); len(remaining) != 0 {
+		t.Fatalf("expected migrated files to be removed, got %v", remaining)
+	}
+
+	var list []string
+	if ok, _ := meta.get("list-1", &list); !ok || len(list) != 2 {
+		t.Fatalf("expected migrated list, got ok=%v value=%v", ok, list)
+	}
+	if ok, _ := meta.getWithMaxAge("list-1", &list, time.Hour); ok {
+		t.Fatal("expected migrated entry to keep the file's age")
+	}
+	var kept string
+	if ok, _ := meta.get("kept", &kept); !ok || kept != "from-db" {
+		t.Fatalf("expected existing database entry to win over file, got %q", kept)
+	}
+}
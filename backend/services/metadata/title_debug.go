@@ -0,0 +1,266 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"novastream/models"
+)
+
+// FieldProvenance names the provider that supplied one field of a title.
+// Providers are tvdb, tmdb, fanart and mdblist, or the image host for
+// artwork from elsewhere; "missing" means no provider had the field.
+type FieldProvenance struct {
+	Field    string `json:"field"`
+	Provider string `json:"provider"`
+	Value    string `json:"value,omitempty"`
+	Note     string `json:"note,omitempty"`
+}
+
+// TitleDebugReport explains where a title's metadata came from, for tracking
+// down wrong artwork, names or ratings.
+type TitleDebugReport struct {
+	Query              string              `json:"query"`
+	MediaType          string              `json:"mediaType"`
+	GeneratedAt        time.Time           `json:"generatedAt"`
+	Title              *models.Title       `json:"title,omitempty"`
+	Fields             []FieldProvenance   `json:"fields"`
+	CacheEntries       []CacheEntryInfo    `json:"cacheEntries"`
+	EnrichmentFailures []EnrichmentFailure `json:"enrichmentFailures,omitempty"`
+	Errors             []string            `json:"errors,omitempty"`
+}
+
+// DebugTitle loads a title through the normal details path and reports which
+// provider supplied each field, the cache entries keyed by its IDs with their
+// ages, and any enrichment failures recorded for it. The ID is an IMDB ID or
+// a qualified "tvdb:81189" / "tmdb:1396"; mediaType is "movie" or "series".
+func (s *Service) DebugTitle(ctx context.Context, id, mediaType string) (*TitleDebugReport, error) {
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	if mediaType != "movie" {
+		mediaType = "series"
+	}
+	var imdbID string
+	var tvdbID, tmdbID int64
+	provider, value, qualified := strings.Cut(strings.TrimSpace(id), ":")
+	if !qualified {
+		provider, value = "imdb", provider
+	}
+	switch strings.ToLower(provider) {
+	case "imdb":
+		if !strings.HasPrefix(value, "tt") {
+			return nil, fmt.Errorf("invalid title id %q: use an IMDB ID or tvdb:<id> / tmdb:<id>", id)
+		}
+		imdbID = value
+	case "tvdb", "tmdb":
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid title id %q", id)
+		}
+		if strings.EqualFold(provider, "tvdb") {
+			tvdbID = n
+		} else {
+			tmdbID = n
+		}
+	default:
+		return nil, fmt.Errorf("invalid title id %q: unknown provider %q", id, provider)
+	}
+
+	report := &TitleDebugReport{
+		Query:       strings.TrimSpace(id),
+		MediaType:   mediaType,
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	var title *models.Title
+	var err error
+	if mediaType == "movie" {
+		title, err = s.MovieDetails(ctx, models.MovieDetailsQuery{IMDBID: imdbID, TVDBID: tvdbID, TMDBID: tmdbID})
+	} else {
+		var details *models.SeriesDetails
+		details, err = s.SeriesDetails(ctx, models.SeriesDetailsQuery{IMDBID: imdbID, TVDBID: tvdbID, TMDBID: tmdbID})
+		if details != nil {
+			title = &details.Title
+		}
+	}
+	if err != nil {
+		report.Errors = append(report.Errors, "details: "+err.Error())
+	}
+	if title != nil {
+		report.Title = title
+		report.Fields = titleProvenance(title)
+		if imdbID == "" {
+			imdbID = title.IMDBID
+		}
+		if tvdbID == 0 {
+			tvdbID = title.TVDBID
+		}
+		if tmdbID == 0 {
+			tmdbID = title.TMDBID
+		}
+	}
+	if report.Fields == nil {
+		report.Fields = []FieldProvenance{}
+	}
+
+	var matchers []func(string) bool
+	for _, key := range titleIDKeys(imdbID, tvdbID, tmdbID) {
+		if match, err := titleCacheKeyMatcher(key); err == nil {
+			matchers = append(matchers, match)
+		}
+	}
+	entries, err := s.matchingCacheEntries(func(key string) bool {
+		for _, match := range matchers {
+			if match(key) {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		report.Errors = append(report.Errors, "cache: "+err.Error())
+	}
+	if entries == nil {
+		entries = []CacheEntryInfo{}
+	}
+	report.CacheEntries = entries
+
+	for _, failure := range s.enrichFailures.list() {
+		if (imdbID != "" && failure.IMDBID == imdbID) ||
+			(tvdbID > 0 && failure.TVDBID == tvdbID) ||
+			(tmdbID > 0 && failure.TMDBID == tmdbID) {
+			report.EnrichmentFailures = append(report.EnrichmentFailures, failure)
+		}
+	}
+	return report, nil
+}
+
+// titleIDKeys returns the title's IDs in the qualified form the cache
+// matcher understands.
+func titleIDKeys(imdbID string, tvdbID, tmdbID int64) []string {
+	var keys []string
+	if imdbID != "" {
+		keys = append(keys, imdbID)
+	}
+	if tvdbID > 0 {
+		keys = append(keys, "tvdb:"+strconv.FormatInt(tvdbID, 10))
+	}
+	if tmdbID > 0 {
+		keys = append(keys, "tmdb:"+strconv.FormatInt(tmdbID, 10))
+	}
+	return keys
+}
+
+// titleProvenance attributes each field of a title to its provider. Details
+// come from TVDB when the title has a TVDB ID and from TMDB otherwise; TMDB
+// fills certifications, releases and genres, MDBList the ratings, and images
+// are attributed by the host they are served from.
+func titleProvenance(title *models.Title) []FieldProvenance {
+	base := "tmdb"
+	if title.TVDBID > 0 {
+		base = "tvdb"
+	}
+
+	var fields []FieldProvenance
+	nameNote := ""
+	if title.OriginalName != "" && title.OriginalName != title.Name {
+		nameNote = "translated from " + title.OriginalName
+	}
+	fields = append(fields, FieldProvenance{Field: "name", Provider: base, Value: title.Name, Note: nameNote})
+	if title.Overview != "" {
+		fields = append(fields, FieldProvenance{Field: "overview", Provider: base})
+	} else {
+		fields = append(fields, FieldProvenance{Field: "overview", Provider: "missing"})
+	}
+
+	images := []struct {
+		field string
+		img   *models.Image
+	}{
+		{"poster", title.Poster},
+		{"textPoster", title.TextPoster},
+		{"backdrop", title.Backdrop},
+		{"textBackdrop", title.TextBackdrop},
+		{"logo", title.Logo},
+		{"banner", title.Banner},
+		{"discArt", title.DiscArt},
+	}
+	for _, image := range images {
+		if image.img == nil || image.img.URL == "" {
+			if image.field == "poster" || image.field == "backdrop" {
+				fields = append(fields, FieldProvenance{Field: image.field, Provider: "missing"})
+			}
+			continue
+		}
+		note := ""
+		if image.img.Language != "" {
+			note = "language " + image.img.Language
+			if image.img.IsFallbackLanguage {
+				note += " (fallback)"
+			}
+		}
+		if image.img.IsTextless {
+			if note != "" {
+				note += ", "
+			}
+			note += "textless"
+		}
+		fields = append(fields, FieldProvenance{Field: image.field, Provider: imageProvider(image.img.URL), Value: image.img.URL, Note: note})
+	}
+
+	certNote := ""
+	if title.CertificationCountry != "" {
+		certNote = "country " + title.CertificationCountry
+	}
+	if title.Certification != "" {
+		fields = append(fields, FieldProvenance{Field: "certification", Provider: "tmdb", Value: title.Certification, Note: certNote})
+	} else {
+		fields = append(fields, FieldProvenance{Field: "certification", Provider: "missing"})
+	}
+	if len(title.Genres) > 0 {
+		fields = append(fields, FieldProvenance{Field: "genres", Provider: "tmdb", Value: strings.Join(title.Genres, ", ")})
+	}
+	for _, rel := range title.Releases {
+		provider := rel.Source
+		if provider == "" {
+			provider = "tmdb"
+		}
+		note := rel.Country
+		if rel.Primary {
+			note = strings.TrimSpace(note + " primary")
+		}
+		fields = append(fields, FieldProvenance{Field: "release." + rel.Type, Provider: provider, Value: rel.Date, Note: note})
+	}
+	for _, rating := range title.Ratings {
+		fields = append(fields, FieldProvenance{
+			Field:    "rating." + rating.Source,
+			Provider: "mdblist",
+			Value:    strconv.FormatFloat(rating.Value, 'f', -1, 64) + "/" + strconv.FormatFloat(rating.Max, 'f', -1, 64),
+		})
+	}
+	if title.PrimaryTrailer != nil {
+		fields = append(fields, FieldProvenance{Field: "trailer", Provider: title.PrimaryTrailer.Source, Value: title.PrimaryTrailer.URL})
+	}
+	return fields
+}
+
+// imageProvider attributes an artwork URL to a provider by its host.
+func imageProvider(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "unknown"
+	}
+	host := strings.ToLower(u.Hostname())
+	switch {
+	case strings.HasSuffix(host, "thetvdb.com"):
+		return "tvdb"
+	case strings.HasSuffix(host, "tmdb.org"):
+		return "tmdb"
+	case strings.HasSuffix(host, "fanart.tv"):
+		return "fanart"
+	}
+	return host
+}
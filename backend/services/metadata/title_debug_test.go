@@ -0,0 +1,89 @@
+package metadata
+
+import (
+	"context"
+	"testing"
+
+	"novastream/models"
+)
+
+func TestTitleProvenanceAttributesFields(t *testing.T) {
+	title := &models.Title{
+		Name:          "Breaking Bad",
+		TVDBID:        81189,
+		Overview:      "A chemistry teacher...",
+		Poster:        &models.Image{URL: "https://artworks.thetvdb.com/banners/posters/81189-1.jpg", Language: "eng"},
+		Backdrop:      &models.Image{URL: "https://image.tmdb.org/t/p/original/x.jpg", IsTextless: true},
+		Logo:          &models.Image{URL: "https://assets.fanart.tv/fanart/tv/81189/hdtvlogo/x.png"},
+		Certification: "TV-MA",
+		Releases:      []models.Release{{Type: "premiere", Date: "2008-01-20", Country: "US", Source: "tmdb"}},
+		Ratings:       []models.Rating{{Source: "imdb", Value: 9.5, Max: 10}},
+	}
+
+	byField := make(map[string]FieldProvenance)
+	for _, field := range titleProvenance(title) {
+		byField[field.Field] = field
+	}
+	want := map[string]string{
+		"name":             "tvdb",
+		"overview":         "tvdb",
+		"poster":           "tvdb",
+		"backdrop":         "tmdb",
+		"logo":             "fanart",
+		"certification":    "tmdb",
+		"release.premiere": "tmdb",
+		"rating.imdb":      "mdblist",
+	}
+	for field, provider := range want {
+		if got := byField[field].Provider; got != provider {
+			t.Errorf("%s provider = %q, want %q", field, got, provider)
+		}
+	}
+	if byField["backdrop"].Note != "textless" || byField["rating.imdb"].Value != "9.5/10" {
+		t.Errorf("unexpected details: backdrop=%+v rating=%+v", byField["backdrop"], byField["rating.imdb"])
+	}
+}
+
+func TestTitleIDKeysMatchCacheEntries(t *testing.T) {
+	svc := newInspectTestService(t)
+	seriesKey := cacheKey("tvdb", "series", "details", "v12", "eng", "81189")
+	ratingsKey := cacheKey("ratings", "all", "show", "tt0903747")
+	otherKey := cacheKey("tvdb", "series", "details", "v12", "eng", "75805")
+	for _, key := range []string{seriesKey, otherKey} {
+		if err := svc.cache.set(key, "v"); err != nil {
+			t.Fatalf("set: %v", err)
+		}
+	}
+	if err := svc.ratingsCache.set(ratingsKey, "v"); err != nil {
+		t.Fatalf("set ratings: %v", err)
+	}
+
+	var matchers []func(string) bool
+	for _, key := range titleIDKeys("tt0903747", 81189, 0) {
+		match, err := titleCacheKeyMatcher(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		matchers = append(matchers, match)
+	}
+	entries, err := svc.matchingCacheEntries(func(key string) bool {
+		for _, match := range matchers {
+			if match(key) {
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil || len(entries) != 2 {
+		t.Fatalf("expected the series and ratings entries, got %+v err=%v", entries, err)
+	}
+}
+
+func TestDebugTitleRejectsAmbiguousIDs(t *testing.T) {
+	svc := &Service{}
+	for _, id := range []string{"81189", "trakt:1", "tvdb:abc"} {
+		if _, err := svc.DebugTitle(context.Background(), id, "series"); err == nil {
+			t.Errorf("expected %q to be rejected", id)
+		}
+	}
+}
@@ -0,0 +1,354 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"novastream/models"
+)
+
+// titleEditsFile lives in the cache root next to the identity overrides, so
+// clearing or refreshing the metadata cache keeps the edits.
+const titleEditsFile = "metadata_title_edits.json"
+
+// Title fields that can be edited or locked.
+const (
+	TitleFieldName      = "name"
+	TitleFieldOverview  = "overview"
+	TitleFieldPosterURL = "posterUrl"
+	TitleFieldIsDaily   = "isDaily"
+)
+
+// TitleEdits holds manual values for a title's fields. A nil field follows
+// the providers; a set one replaces what they return, on every refresh.
+// TitleID takes the forms IdentityOverride accepts.
+type TitleEdits struct {
+	TitleID   string    `json:"titleId"`
+	MediaType string    `json:"mediaType"` // movie | series
+	Name      *string   `json:"name,omitempty"`
+	Overview  *string   `json:"overview,omitempty"`
+	PosterURL *string   `json:"posterUrl,omitempty"`
+	IsDaily   *bool     `json:"isDaily,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (e TitleEdits) empty() bool {
+	return e.Name == nil && e.Overview == nil && e.PosterURL == nil && e.IsDaily == nil
+}
+
+// apply writes the edited fields onto title.
+func (e TitleEdits) apply(title *models.Title) {
+	if e.Name != nil {
+		title.Name = *e.Name
+	}
+	if e.Overview != nil {
+		title.Overview = *e.Overview
+	}
+	if e.PosterURL != nil {
+		if *e.PosterURL == "" {
+			title.Poster = nil
+		} else if title.Poster == nil || title.Poster.URL != *e.PosterURL {
+			title.Poster = &models.Image{URL: *e.PosterURL, Type: "poster"}
+		}
+	}
+	if e.IsDaily != nil {
+		title.IsDaily = *e.IsDaily
+	}
+}
+
+// titleEditStore keeps the edits in memory, persisted as JSON and shared by
+// all language clones of a Service. An empty path keeps them in memory only.
+type titleEditStore struct {
+	mu    sync.RWMutex
+	path  string
+	edits map[string]TitleEdits // normalized title ID -> edits
+}
+
+func newTitleEditStore(path string) *titleEditStore {
+	store := &titleEditStore{path: path, edits: make(map[string]TitleEdits)}
+	if path == "" {
+		return store
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store
+	}
+	if err != nil {
+		log.Printf("[metadata] WARNING: failed to read title edits: %v", err)
+		return store
+	}
+	var edits []TitleEdits
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &edits); err != nil {
+			log.Printf("[metadata] WARNING: failed to decode title edits: %v", err)
+			return store
+		}
+	}
+	for _, edit := range edits {
+		if key := normalizeIdentityTitleID(edit.TitleID); key != "" && !edit.empty() {
+			edit.TitleID = key
+			store.edits[key] = edit
+		}
+	}
+	return store
+}
+
+// listLocked returns the edits sorted by title ID.
+func (st *titleEditStore) listLocked() []TitleEdits {
+	list := make([]TitleEdits, 0, len(st.edits))
+	for _, edit := range st.edits {
+		list = append(list, edit)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].TitleID < list[j].TitleID })
+	return list
+}
+
+func (st *titleEditStore) saveLocked() error {
+	if st.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(st.listLocked(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode title edits: %w", err)
+	}
+	tmp := st.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("write title edits: %w", err)
+	}
+	return os.Rename(tmp, st.path)
+}
+
+// put stores edits for their title, or removes the entry when no field is
+// set, and persists the change.
+func (st *titleEditStore) put(edits TitleEdits) error {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	previous, hadPrevious := st.edits[edits.TitleID]
+	if edits.empty() {
+		delete(st.edits, edits.TitleID)
+	} else {
+		st.edits[edits.TitleID] = edits
+	}
+	if err := st.saveLocked(); err != nil {
+		if hadPrevious {
+			st.edits[edits.TitleID] = previous
+		} else {
+			delete(st.edits, edits.TitleID)
+		}
+		return err
+	}
+	return nil
+}
+
+// normalizeTitleEdits validates edits and canonicalizes their title ID and
+// media type.
+func normalizeTitleEdits(edits TitleEdits) (TitleEdits, error) {
+	edits.TitleID = normalizeIdentityTitleID(edits.TitleID)
+	edits.MediaType = strings.ToLower(strings.TrimSpace(edits.MediaType))
+	if edits.TitleID == "" {
+		return TitleEdits{}, fmt.Errorf("title id required")
+	}
+	if edits.MediaType != "movie" && edits.MediaType != "series" {
+		return TitleEdits{}, fmt.Errorf("media type must be movie or series")
+	}
+	if edits.Name != nil && strings.TrimSpace(*edits.Name) == "" {
+		return TitleEdits{}, fmt.Errorf("name cannot be empty")
+	}
+	if edits.PosterURL != nil {
+		url := strings.TrimSpace(*edits.PosterURL)
+		if url != "" && !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			return TitleEdits{}, fmt.Errorf("poster url must be http or https")
+		}
+		edits.PosterURL = &url
+	}
+	return edits, nil
+}
+
+// SetTitleEdits replaces the manual field values of a title. Fields left nil
+// follow the providers again; with none set the title's edits are removed.
+// Edits apply to every details response, cached or fresh.
+func (s *Service) SetTitleEdits(edits TitleEdits) (TitleEdits, error) {
+	if s.titleEdits == nil {
+		return TitleEdits{}, fmt.Errorf("title edits %w", ErrNotConfigured)
+	}
+	edits, err := normalizeTitleEdits(edits)
+	if err != nil {
+		return TitleEdits{}, err
+	}
+	edits.UpdatedAt = time.Now().UTC()
+	if err := s.titleEdits.put(edits); err != nil {
+		return TitleEdits{}, err
+	}
+	log.Printf("[metadata] title edits set %s (%s) fields=%v", edits.TitleID, edits.MediaType, editedTitleFields(edits))
+	return edits, nil
+}
+
+// LockTitleFields pins fields of a title to the values it has now, so later
+// provider refreshes can't change them. Fields that already carry an edit
+// keep it.
+func (s *Service) LockTitleFields(ctx context.Context, mediaType, titleID string, fields []string) (TitleEdits, error) {
+	if s.titleEdits == nil {
+		return TitleEdits{}, fmt.Errorf("title edits %w", ErrNotConfigured)
+	}
+	edits, err := normalizeTitleEdits(TitleEdits{TitleID: titleID, MediaType: mediaType})
+	if err != nil {
+		return TitleEdits{}, err
+	}
+	if len(fields) == 0 {
+		return TitleEdits{}, fmt.Errorf("at least one field required")
+	}
+	for _, field := range fields {
+		switch field {
+		case TitleFieldName, TitleFieldOverview, TitleFieldPosterURL, TitleFieldIsDaily:
+		default:
+			return TitleEdits{}, fmt.Errorf("unknown title field %q", field)
+		}
+	}
+
+	title, err := s.currentTitle(ctx, edits.MediaType, edits.TitleID)
+	if err != nil {
+		return TitleEdits{}, err
+	}
+	s.titleEdits.mu.RLock()
+	if existing, ok := s.titleEdits.edits[edits.TitleID]; ok && existing.MediaType == edits.MediaType {
+		edits = existing
+	}
+	s.titleEdits.mu.RUnlock()
+	// Copy the values: the title may be shared with other requests.
+	name, overview, isDaily := title.Name, title.Overview, title.IsDaily
+	posterURL := ""
+	if title.Poster != nil {
+		posterURL = title.Poster.URL
+	}
+	for _, field := range fields {
+		switch {
+		case field == TitleFieldName && edits.Name == nil:
+			edits.Name = &name
+		case field == TitleFieldOverview && edits.Overview == nil:
+			edits.Overview = &overview
+		case field == TitleFieldPosterURL && edits.PosterURL == nil:
+			edits.PosterURL = &posterURL
+		case field == TitleFieldIsDaily && edits.IsDaily == nil:
+			edits.IsDaily = &isDaily
+		}
+	}
+	return s.SetTitleEdits(edits)
+}
+
+// currentTitle fetches a title's details by its title ID.
+func (s *Service) currentTitle(ctx context.Context, mediaType, titleID string) (*models.Title, error) {
+	tvdbID, tmdbID, imdbID := idsFromTitleID(titleID)
+	if mediaType == "movie" {
+		return s.MovieInfo(ctx, models.MovieDetailsQuery{TitleID: titleID, TVDBID: tvdbID, TMDBID: tmdbID, IMDBID: imdbID})
+	}
+	return s.SeriesInfo(ctx, models.SeriesDetailsQuery{TitleID: titleID, TVDBID: tvdbID, TMDBID: tmdbID, IMDBID: imdbID})
+}
+
+// idsFromTitleID extracts the provider IDs a normalized title ID names.
+func idsFromTitleID(titleID string) (tvdbID, tmdbID int64, imdbID string) {
+	if strings.HasPrefix(titleID, "tt") {
+		return 0, 0, titleID
+	}
+	parts := strings.Split(titleID, ":")
+	id, err := strconv.ParseInt(parts[len(parts)-1], 10, 64)
+	if err != nil || len(parts) < 2 {
+		return 0, 0, ""
+	}
+	switch parts[0] {
+	case "tvdb":
+		return id, 0, ""
+	case "tmdb":
+		return 0, id, ""
+	}
+	return 0, 0, ""
+}
+
+// RemoveTitleEdits drops every edit of titleID, returning ErrNotFound when
+// there are none.
+func (s *Service) RemoveTitleEdits(titleID string) error {
+	if s.titleEdits == nil {
+		return fmt.Errorf("title edits %w", ErrNotConfigured)
+	}
+	key := normalizeIdentityTitleID(titleID)
+	s.titleEdits.mu.RLock()
+	edits, ok := s.titleEdits.edits[key]
+	s.titleEdits.mu.RUnlock()
+	if !ok {
+		return newKindError(ErrNotFound, "no title edits for %q", titleID)
+	}
+	if err := s.titleEdits.put(TitleEdits{TitleID: key, MediaType: edits.MediaType}); err != nil {
+		return err
+	}
+	log.Printf("[metadata] title edits removed %s", key)
+	return nil
+}
+
+// AllTitleEdits lists the edited titles sorted by title ID.
+func (s *Service) AllTitleEdits() []TitleEdits {
+	if s.titleEdits == nil {
+		return []TitleEdits{}
+	}
+	s.titleEdits.mu.RLock()
+	defer s.titleEdits.mu.RUnlock()
+	return s.titleEdits.listLocked()
+}
+
+// titleEditsFor returns the edits matching any of a title's IDs.
+func (s *Service) titleEditsFor(mediaType, titleID string, tvdbID, tmdbID int64, imdbID string) (TitleEdits, bool) {
+	if s.titleEdits == nil {
+		return TitleEdits{}, false
+	}
+	s.titleEdits.mu.RLock()
+	defer s.titleEdits.mu.RUnlock()
+	if len(s.titleEdits.edits) == 0 {
+		return TitleEdits{}, false
+	}
+	for _, key := range identityKeys(mediaType, titleID, tvdbID, tmdbID, imdbID) {
+		if edits, ok := s.titleEdits.edits[key]; ok && edits.MediaType == mediaType {
+			return edits, true
+		}
+	}
+	return TitleEdits{}, false
+}
+
+// applyTitleEdits writes a title's manual field values over the provider
+// data. Like applyRegion it runs per request, after the cache, so refreshes
+// never clobber the edits.
+func (s *Service) applyTitleEdits(title *models.Title) {
+	if title == nil {
+		return
+	}
+	mediaType := "series"
+	if strings.EqualFold(title.MediaType, "movie") {
+		mediaType = "movie"
+	}
+	if edits, ok := s.titleEditsFor(mediaType, title.ID, title.TVDBID, title.TMDBID, title.IMDBID); ok {
+		edits.apply(title)
+	}
+}
+
+func editedTitleFields(edits TitleEdits) []string {
+	var fields []string
+	if edits.Name != nil {
+		fields = append(fields, TitleFieldName)
+	}
+	if edits.Overview != nil {
+		fields = append(fields, TitleFieldOverview)
+	}
+	if edits.PosterURL != nil {
+		fields = append(fields, TitleFieldPosterURL)
+	}
+	if edits.IsDaily != nil {
+		fields = append(fields, TitleFieldIsDaily)
+	}
+	return fields
+}
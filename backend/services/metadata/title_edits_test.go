@@ -0,0 +1,79 @@
+package metadata
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"novastream/models"
+)
+
+func TestTitleEditsPersistAndApply(t *testing.T) {
+	path := filepath.Join(t.TempDir(), titleEditsFile)
+	svc := &Service{titleEdits: newTitleEditStore(path)}
+	name, poster, daily := "The Daily Show", " https://example.com/poster.jpg ", true
+	badPoster, blank := "file:///etc/passwd", " "
+
+	if _, err := svc.SetTitleEdits(TitleEdits{TitleID: "tvdb:series:71256", MediaType: "series", PosterURL: &badPoster}); err == nil {
+		t.Fatal("expected a non-http poster url to be rejected")
+	}
+	if _, err := svc.SetTitleEdits(TitleEdits{TitleID: "tvdb:series:71256", MediaType: "series", Name: &blank}); err == nil {
+		t.Fatal("expected an empty name to be rejected")
+	}
+	edits, err := svc.SetTitleEdits(TitleEdits{TitleID: "TVDB:tv:71256", MediaType: "Series", Name: &name, PosterURL: &poster, IsDaily: &daily})
+	if err != nil {
+		t.Fatalf("SetTitleEdits: %v", err)
+	}
+	if edits.TitleID != "tvdb:series:71256" || *edits.PosterURL != "https://example.com/poster.jpg" {
+		t.Fatalf("unexpected normalized edits %+v", edits)
+	}
+
+	reloaded := &Service{titleEdits: newTitleEditStore(path)}
+	title := &models.Title{
+		ID:        "tvdb:series:71256",
+		MediaType: "series",
+		TVDBID:    71256,
+		Name:      "Daily Show",
+		Overview:  "From the provider",
+		Poster:    &models.Image{URL: "https://artworks.thetvdb.com/poster.jpg", Type: "poster"},
+	}
+	reloaded.applyTitleEdits(title)
+	if title.Name != name || !title.IsDaily || title.Poster == nil || title.Poster.URL != "https://example.com/poster.jpg" {
+		t.Fatalf("expected the persisted edits to apply, got %+v", title)
+	}
+	if title.Overview != "From the provider" {
+		t.Fatalf("expected unedited fields to follow the provider, got %q", title.Overview)
+	}
+
+	// Edits only apply to their media type.
+	movie := &models.Title{MediaType: "movie", TVDBID: 71256, Name: "A Movie"}
+	reloaded.applyTitleEdits(movie)
+	if movie.Name != "A Movie" {
+		t.Fatalf("expected series edits not to apply to movies, got %q", movie.Name)
+	}
+
+	if err := reloaded.RemoveTitleEdits("tvdb:series:71256"); err != nil {
+		t.Fatalf("RemoveTitleEdits: %v", err)
+	}
+	if err := reloaded.RemoveTitleEdits("tvdb:series:71256"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if list := newTitleEditStore(path).listLocked(); len(list) != 0 {
+		t.Fatalf("expected the removal to persist, got %+v", list)
+	}
+}
+
+func TestIDsFromTitleID(t *testing.T) {
+	cases := map[string][3]any{
+		"tvdb:series:81189": {int64(81189), int64(0), ""},
+		"tmdb:movie:603":    {int64(0), int64(603), ""},
+		"tt0903747":         {int64(0), int64(0), "tt0903747"},
+		"mdblist:series:5":  {int64(0), int64(0), ""},
+	}
+	for titleID, want := range cases {
+		tvdbID, tmdbID, imdbID := idsFromTitleID(titleID)
+		if tvdbID != want[0] || tmdbID != want[1] || imdbID != want[2] {
+			t.Fatalf("idsFromTitleID(%q) = %d, %d, %q", titleID, tvdbID, tmdbID, imdbID)
+		}
+	}
+}
@@ -91,57 +91,43 @@ type tmdbClient struct {
 	apiKey   string
 	language string
 	httpc    *http.Client
-	cache    *fileCache // Optional cache for expensive lookups
+	cache    cacheStore // Optional cache for expensive lookups
 
-	// Rate limiting
-	throttleMu  sync.Mutex
-	lastRequest time.Time
-	minInterval time.Duration
+	limiter *rateLimiter // shared per-provider limiter; nil disables limiting
 
 	// In-flight singleflight map for movieDetails — holds only requests
 	// currently being fetched (bounded), not a process-lifetime cache.
 	movieCache sync.Map
 }
 
-func newTMDBClient(apiKey, language string, httpc *http.Client, cache *fileCache) *tmdbClient {
+func newTMDBClient(apiKey, language string, httpc *http.Client, cache cacheStore) *tmdbClient {
 	if httpc == nil {
 		httpc = &http.Client{Timeout: 15 * time.Second}
 	}
 	return &tmdbClient{
-		apiKey:      strings.TrimSpace(apiKey),
-		language:    language,
-		httpc:       httpc,
-		cache:       cache,
-		minInterval: 5 * time.Millisecond, // TMDB has generous rate limits; retries back off on 429/5xx.
+		apiKey:   strings.TrimSpace(apiKey),
+		language: language,
+		httpc:    httpc,
+		cache:    cache,
+		limiter:  tmdbLimiter,
 	}
 }
 
 // doGET performs an HTTP GET with rate limiting and retry with exponential backoff
 func (c *tmdbClient) doGET(ctx context.Context, endpoint string, v any) error {
+	if err := offlineError("tmdb"); err != nil {
+		return err
+	}
 	var lastErr error
 	backoff := 300 * time.Millisecond
 
 	for attempt := 0; attempt < 3; attempt++ {
-		// Rate limiting — compute wait outside the lock to avoid blocking other goroutines
-		c.throttleMu.Lock()
-		wait := c.minInterval - time.Since(c.lastRequest)
-		if wait > 0 {
-			c.lastRequest = time.Now().Add(wait) // reserve slot
-		} else {
-			c.lastRequest = time.Now()
-			wait = 0
-		}
-		c.throttleMu.Unlock()
-		if wait > 0 {
-			time.Sleep(wait)
-		}
-
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 		if err != nil {
 			return err
 		}
 
-		resp, err := c.httpc.Do(req)
+		resp, err := c.limiter.do(c.httpc, req)
 		if err != nil {
 			lastErr = err
 			log.Printf("[tmdb] http error (attempt %d/3): %v", attempt+1, err)
@@ -154,7 +140,7 @@ func (c *tmdbClient) doGET(ctx context.Context, endpoint string, v any) error {
 		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
 			resp.Body.Close()
 			log.Printf("[tmdb] rate limited or server error (attempt %d/3): status %d", attempt+1, resp.StatusCode)
-			lastErr = fmt.Errorf("tmdb request failed: %s", resp.Status)
+			lastErr = upstreamError(resp.StatusCode, "tmdb request failed: %s", resp.Status)
 			if ra := resp.Header.Get("Retry-After"); ra != "" {
 				if secs, err := strconv.Atoi(ra); err == nil {
 					time.Sleep(time.Duration(secs) * time.Second)
@@ -168,7 +154,7 @@ func (c *tmdbClient) doGET(ctx context.Context, endpoint string, v any) error {
 
 		if resp.StatusCode >= 400 {
 			resp.Body.Close()
-			return fmt.Errorf("tmdb request failed: %s", resp.Status)
+			return upstreamError(resp.StatusCode, "tmdb request failed: %s", resp.Status)
 		}
 
 		err = json.NewDecoder(resp.Body).Decode(v)
@@ -216,13 +202,24 @@ type tmdbReleaseDatesResponse struct {
 }
 
 type tmdbCreditsResponse struct {
-	Cast []struct {
+	Cast []tmdbCastEntry `json:"cast"`
+	Crew []struct {
 		ID          int64  `json:"id"`
 		Name        string `json:"name"`
-		Character   string `json:"character"`
-		Order       int    `json:"order"`
+		Job         string `json:"job"`
+		Department  string `json:"department"`
 		ProfilePath string `json:"profile_path"`
-	} `json:"cast"`
+	} `json:"crew"`
+}
+
+// tmdbCastEntry is a cast credit as returned by /credits and as an episode's
+// guest_stars.
+type tmdbCastEntry struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Character   string `json:"character"`
+	Order       int    `json:"order"`
+	ProfilePath string `json:"profile_path"`
 }
 
 // tmdbAggregateCreditsResponse is for TV shows using /aggregate_credits endpoint
@@ -237,6 +234,17 @@ type tmdbAggregateCreditsResponse struct {
 			EpisodeCount int    `json:"episode_count"`
 		} `json:"roles"`
 	} `json:"cast"`
+	Crew []struct {
+		ID                int64  `json:"id"`
+		Name              string `json:"name"`
+		Department        string `json:"department"`
+		ProfilePath       string `json:"profile_path"`
+		TotalEpisodeCount int    `json:"total_episode_count"`
+		Jobs              []struct {
+			Job          string `json:"job"`
+			EpisodeCount int    `json:"episode_count"`
+		} `json:"jobs"`
+	} `json:"crew"`
 }
 
 type tmdbReleaseCountry struct {
@@ -325,6 +333,7 @@ type tmdbImageItem struct {
 	Height      int     `json:"height"`
 	Width       int     `json:"width"`
 	VoteAverage float64 `json:"vote_average"`
+	VoteCount   int     `json:"vote_count"`
 	ISO6391     string  `json:"iso_639_1"`
 }
 
@@ -345,11 +354,11 @@ type tmdbImagesResult struct {
 	Backdrops        []models.Image
 }
 
-// fetchImages retrieves logo and textless poster for a movie or TV show from TMDB
-// Uses a single API call to get both, improving efficiency
-func (c *tmdbClient) fetchImages(ctx context.Context, mediaType string, tmdbID int64) (*tmdbImagesResult, error) {
+// fetchImageList retrieves every poster, backdrop and logo TMDB has for a
+// movie or TV show, in all languages.
+func (c *tmdbClient) fetchImageList(ctx context.Context, mediaType string, tmdbID int64) (*tmdbImagesResponse, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("tmdb api key not configured")
+		return nil, errTMDBNotConfigured
 	}
 
 	// Map "series" to "tv" for TMDB API
@@ -362,15 +371,26 @@ func (c *tmdbClient) fetchImages(ctx context.Context, mediaType string, tmdbID i
 	if err != nil {
 		return nil, err
 	}
-	// Don't filter logos server-side — TMDB's include_image_language returns 0 results
-	// for many shows. Fetch all logos then filter client-side by language preference.
-	preferredLang := c.logoLanguage()
 	endpoint = endpoint + "?api_key=" + c.apiKey
 
 	var payload tmdbImagesResponse
 	if err := c.doGET(ctx, endpoint, &payload); err != nil {
 		return nil, fmt.Errorf("tmdb images for %s/%d failed: %w", apiMediaType, tmdbID, err)
 	}
+	return &payload, nil
+}
+
+// fetchImages retrieves logo and textless poster for a movie or TV show from TMDB
+// Uses a single API call to get both, improving efficiency
+func (c *tmdbClient) fetchImages(ctx context.Context, mediaType string, tmdbID int64) (*tmdbImagesResult, error) {
+	// Don't filter logos server-side — TMDB's include_image_language returns 0 results
+	// for many shows. Fetch all logos then filter client-side by language preference.
+	preferredLang := c.logoLanguage()
+	images, err := c.fetchImageList(ctx, mediaType, tmdbID)
+	if err != nil {
+		return nil, err
+	}
+	payload := *images
 
 	result := &tmdbImagesResult{}
 
@@ -555,6 +575,9 @@ func (c *tmdbClient) isWhiteOnlySVGURL(ctx context.Context, imageURL string) boo
 		return false
 	}
 
+	if offlineError("tmdb") != nil {
+		return false
+	}
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
 	if err != nil {
 		log.Printf("[metadata] logo svg color: failed to create request: %v", err)
@@ -716,6 +739,9 @@ func (c *tmdbClient) fetchBackdropVisualSignature(ctx context.Context, imageURL
 		analysisURL = strings.Replace(imageURL, "/w780/", "/"+tmdbBackdropAnalysisSize+"/", 1)
 	}
 
+	if err := offlineError("tmdb"); err != nil {
+		return nil, err
+	}
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, analysisURL, nil)
 	if err != nil {
 		return nil, err
@@ -1006,6 +1032,10 @@ func (c *tmdbClient) isImageDark(ctx context.Context, imageURL string) bool {
 	// Use w92 thumbnail for analysis — tiny and fast to download
 	analysisURL := strings.Replace(imageURL, "/w500/", "/w92/", 1)
 
+	if offlineError("tmdb") != nil {
+		return false
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, analysisURL, nil)
 	if err != nil {
 		log.Printf("[metadata] logo brightness: failed to create request: %v", err)
@@ -1082,7 +1112,7 @@ func (c *tmdbClient) isImageDark(ctx context.Context, imageURL string) bool {
 // fetchSeriesGenres retrieves genres for a TV series from TMDB
 func (c *tmdbClient) fetchSeriesGenres(ctx context.Context, tmdbID int64) ([]string, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("tmdb api key not configured")
+		return nil, errTMDBNotConfigured
 	}
 
 	endpoint, err := url.JoinPath(tmdbBaseURL, "tv", fmt.Sprintf("%d", tmdbID))
@@ -1112,7 +1142,7 @@ func (c *tmdbClient) fetchSeriesGenres(ctx context.Context, tmdbID int64) ([]str
 
 func (c *tmdbClient) seriesDetails(ctx context.Context, tmdbID int64) (*models.Title, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("tmdb api key not configured")
+		return nil, errTMDBNotConfigured
 	}
 	if tmdbID <= 0 {
 		return nil, errors.New("tmdb id required")
@@ -1261,7 +1291,7 @@ func (s tmdbSeasonSummary) toModel(tmdbID int64) models.SeriesSeason {
 
 func (c *tmdbClient) seriesSeasonSummaries(ctx context.Context, tmdbID int64) ([]tmdbSeasonSummary, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("tmdb api key not configured")
+		return nil, errTMDBNotConfigured
 	}
 	endpoint, err := url.JoinPath(tmdbBaseURL, "tv", fmt.Sprintf("%d", tmdbID))
 	if err != nil {
@@ -1283,7 +1313,7 @@ func (c *tmdbClient) seriesSeasonSummaries(ctx context.Context, tmdbID int64) ([
 
 func (c *tmdbClient) seriesSeasonDetails(ctx context.Context, tmdbID int64, summary tmdbSeasonSummary) (models.SeriesSeason, error) {
 	if !c.isConfigured() {
-		return models.SeriesSeason{}, errors.New("tmdb api key not configured")
+		return models.SeriesSeason{}, errTMDBNotConfigured
 	}
 	endpoint, err := url.JoinPath(tmdbBaseURL, "tv", fmt.Sprintf("%d", tmdbID), "season", fmt.Sprintf("%d", summary.Number))
 	if err != nil {
@@ -1302,14 +1332,17 @@ func (c *tmdbClient) seriesSeasonDetails(ctx context.Context, tmdbID int64, summ
 		AirDate    string `json:"air_date"`
 		PosterPath string `json:"poster_path"`
 		Episodes   []struct {
-			ID            int64  `json:"id"`
-			Name          string `json:"name"`
-			Overview      string `json:"overview"`
-			SeasonNumber  int    `json:"season_number"`
-			EpisodeNumber int    `json:"episode_number"`
-			AirDate       string `json:"air_date"`
-			Runtime       int    `json:"runtime"`
-			StillPath     string `json:"still_path"`
+			ID            int64           `json:"id"`
+			Name          string          `json:"name"`
+			Overview      string          `json:"overview"`
+			SeasonNumber  int             `json:"season_number"`
+			EpisodeNumber int             `json:"episode_number"`
+			AirDate       string          `json:"air_date"`
+			Runtime       int             `json:"runtime"`
+			StillPath     string          `json:"still_path"`
+			VoteAverage   float64         `json:"vote_average"`
+			VoteCount     int             `json:"vote_count"`
+			GuestStars    []tmdbCastEntry `json:"guest_stars"`
 		} `json:"episodes"`
 	}
 	if err := c.doGET(ctx, endpoint, &payload); err != nil {
@@ -1363,6 +1396,12 @@ func (c *tmdbClient) seriesSeasonDetails(ctx context.Context, tmdbID int64, summ
 		if still := buildTMDBImage(ep.StillPath, tmdbStillSize, "still"); still != nil {
 			episode.Image = still
 		}
+		if ep.VoteCount > 0 {
+			episode.Ratings = []models.Rating{{Source: "tmdb", Value: ep.VoteAverage, Max: 10, Votes: ep.VoteCount}}
+		}
+		for _, guest := range ep.GuestStars {
+			episode.GuestStars = append(episode.GuestStars, guest.toModel())
+		}
 		episodes = append(episodes, episode)
 	}
 	sort.Slice(episodes, func(i, j int) bool {
@@ -1380,7 +1419,7 @@ func (c *tmdbClient) seriesSeasonDetails(ctx context.Context, tmdbID int64, summ
 
 func (c *tmdbClient) searchTitles(ctx context.Context, query, mediaType string, limit int, includeAdult bool) ([]models.SearchResult, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("tmdb api key not configured")
+		return nil, errTMDBNotConfigured
 	}
 	query = strings.TrimSpace(query)
 	if query == "" {
@@ -1646,7 +1685,7 @@ func calculateRoleImportance(popularity, voteAverage float64, billingOrder, epis
 
 func (c *tmdbClient) fetchTrailers(ctx context.Context, mediaType string, tmdbID int64) ([]models.Trailer, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("tmdb api key not configured")
+		return nil, errTMDBNotConfigured
 	}
 
 	apiMediaType := strings.ToLower(strings.TrimSpace(mediaType))
@@ -1671,14 +1710,14 @@ func (c *tmdbClient) fetchTrailers(ctx context.Context, mediaType string, tmdbID
 	}
 	req.URL.RawQuery = q.Encode()
 
-	resp, err := c.httpc.Do(req)
+	resp, err := c.limiter.do(c.httpc, req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("tmdb videos %s/%d failed: %s", apiMediaType, tmdbID, resp.Status)
+		return nil, upstreamError(resp.StatusCode, "tmdb videos %s/%d failed: %s", apiMediaType, tmdbID, resp.Status)
 	}
 
 	var payload tmdbVideosResponse
@@ -1732,7 +1771,7 @@ func (c *tmdbClient) fetchTrailers(ctx context.Context, mediaType string, tmdbID
 // fetchSeasonTrailers fetches trailers for a specific season of a TV show from TMDB
 func (c *tmdbClient) fetchSeasonTrailers(ctx context.Context, tmdbID int64, seasonNumber int) ([]models.Trailer, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("tmdb api key not configured")
+		return nil, errTMDBNotConfigured
 	}
 
 	// TMDB API: /tv/{series_id}/season/{season_number}/videos
@@ -1753,14 +1792,14 @@ func (c *tmdbClient) fetchSeasonTrailers(ctx context.Context, tmdbID int64, seas
 	}
 	req.URL.RawQuery = q.Encode()
 
-	resp, err := c.httpc.Do(req)
+	resp, err := c.limiter.do(c.httpc, req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("tmdb season videos tv/%d/season/%d failed: %s", tmdbID, seasonNumber, resp.Status)
+		return nil, upstreamError(resp.StatusCode, "tmdb season videos tv/%d/season/%d failed: %s", tmdbID, seasonNumber, resp.Status)
 	}
 
 	var payload tmdbVideosResponse
@@ -1818,7 +1857,7 @@ func (c *tmdbClient) fetchSeasonTrailers(ctx context.Context, tmdbID int64, seas
 // for the same TMDB ID share one HTTP request.
 func (c *tmdbClient) movieDetails(ctx context.Context, tmdbID int64) (*models.Title, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("tmdb api key not configured")
+		return nil, errTMDBNotConfigured
 	}
 
 	// Persistent file cache — survives restarts and is scoped per language,
@@ -1874,14 +1913,14 @@ func (c *tmdbClient) movieDetailsFetch(ctx context.Context, tmdbID int64) (*mode
 	}
 	req.URL.RawQuery = q.Encode()
 
-	resp, err := c.httpc.Do(req)
+	resp, err := c.limiter.do(c.httpc, req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("tmdb movie details failed: %s", resp.Status)
+		return nil, upstreamError(resp.StatusCode, "tmdb movie details failed: %s", resp.Status)
 	}
 
 	var movie struct {
@@ -1962,7 +2001,7 @@ func (c *tmdbClient) movieDetailsFetch(ctx context.Context, tmdbID int64) (*mode
 // including all movies in the collection
 func (c *tmdbClient) fetchCollectionDetails(ctx context.Context, collectionID int64) (*models.CollectionDetails, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("tmdb api key not configured")
+		return nil, errTMDBNotConfigured
 	}
 
 	endpoint, err := url.JoinPath(tmdbBaseURL, "collection", fmt.Sprintf("%d", collectionID))
@@ -1984,14 +2023,14 @@ func (c *tmdbClient) fetchCollectionDetails(ctx context.Context, collectionID in
 	}
 	req.URL.RawQuery = q.Encode()
 
-	resp, err := c.httpc.Do(req)
+	resp, err := c.limiter.do(c.httpc, req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("tmdb collection details failed: %s", resp.Status)
+		return nil, upstreamError(resp.StatusCode, "tmdb collection details failed: %s", resp.Status)
 	}
 
 	var collection struct {
@@ -2057,11 +2096,11 @@ func (c *tmdbClient) fetchCollectionDetails(ctx context.Context, collectionID in
 	return details, nil
 }
 
-// fetchCredits retrieves cast information from TMDB for movies or TV shows
-// Returns top 8 billed cast members with profile images
+// fetchCredits retrieves cast and crew information from TMDB for movies or TV shows
+// Returns top 8 billed cast members with profile images, plus key crew
 func (c *tmdbClient) fetchCredits(ctx context.Context, mediaType string, tmdbID int64) (*models.Credits, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("tmdb api key not configured")
+		return nil, errTMDBNotConfigured
 	}
 
 	// Map "series" to "tv" for TMDB API
@@ -2101,47 +2140,53 @@ func (c *tmdbClient) fetchMovieCredits(ctx context.Context, tmdbID int64) (*mode
 
 	cast := make([]models.CastMember, 0, maxCast)
 	for i := 0; i < maxCast; i++ {
-		cm := payload.Cast[i]
-		member := models.CastMember{
-			ID:        cm.ID,
-			Name:      strings.TrimSpace(cm.Name),
-			Character: strings.TrimSpace(cm.Character),
-			Order:     cm.Order,
-		}
-		if cm.ProfilePath != "" {
-			member.ProfilePath = cm.ProfilePath
-			member.ProfileURL = fmt.Sprintf("%s/%s%s", tmdbImageBaseURL, tmdbProfileSize, cm.ProfilePath)
-		}
-		cast = append(cast, member)
+		cast = append(cast, payload.Cast[i].toModel())
+	}
+
+	crew := make([]models.CrewMember, 0, len(payload.Crew))
+	for _, cm := range payload.Crew {
+		crew = append(crew, newCrewMember(cm.ID, cm.Name, cm.Job, cm.Department, cm.ProfilePath))
 	}
 
-	return &models.Credits{Cast: cast}, nil
+	credits := &models.Credits{Cast: cast}
+	applyCrew(credits, crew)
+	return credits, nil
 }
 
 func (c *tmdbClient) fetchTVCredits(ctx context.Context, tmdbID int64) (*models.Credits, error) {
-	endpoint, err := url.JoinPath(tmdbBaseURL, "tv", fmt.Sprintf("%d", tmdbID), "aggregate_credits")
+	// aggregate_credits is appended to the show itself so created_by comes
+	// back in the same request.
+	endpoint, err := url.JoinPath(tmdbBaseURL, "tv", fmt.Sprintf("%d", tmdbID))
 	if err != nil {
 		return nil, err
 	}
-	endpoint = endpoint + "?api_key=" + c.apiKey
+	endpoint = endpoint + "?api_key=" + c.apiKey + "&append_to_response=aggregate_credits"
 	if lang := strings.TrimSpace(c.language); lang != "" {
 		endpoint = endpoint + "&language=" + normalizeLanguage(lang)
 	}
 
-	var payload tmdbAggregateCreditsResponse
+	var payload struct {
+		CreatedBy []struct {
+			ID          int64  `json:"id"`
+			Name        string `json:"name"`
+			ProfilePath string `json:"profile_path"`
+		} `json:"created_by"`
+		AggregateCredits tmdbAggregateCreditsResponse `json:"aggregate_credits"`
+	}
 	if err := c.doGET(ctx, endpoint, &payload); err != nil {
 		return nil, fmt.Errorf("tmdb aggregate_credits for tv/%d failed: %w", tmdbID, err)
 	}
+	aggregate := payload.AggregateCredits
 
 	// Limit to top 8 cast members by order
 	maxCast := 8
-	if len(payload.Cast) < maxCast {
-		maxCast = len(payload.Cast)
+	if len(aggregate.Cast) < maxCast {
+		maxCast = len(aggregate.Cast)
 	}
 
 	cast := make([]models.CastMember, 0, maxCast)
 	for i := 0; i < maxCast; i++ {
-		cm := payload.Cast[i]
+		cm := aggregate.Cast[i]
 		// Get primary character from roles (first one with most episodes)
 		character := ""
 		if len(cm.Roles) > 0 {
@@ -2160,13 +2205,96 @@ func (c *tmdbClient) fetchTVCredits(ctx context.Context, tmdbID int64) (*models.
 		cast = append(cast, member)
 	}
 
-	return &models.Credits{Cast: cast}, nil
+	// Series crew rotates per episode; rank people by how many episodes they
+	// worked on and credit each with their most frequent job.
+	crewEntries := aggregate.Crew
+	sort.SliceStable(crewEntries, func(i, j int) bool {
+		return crewEntries[i].TotalEpisodeCount > crewEntries[j].TotalEpisodeCount
+	})
+	crew := make([]models.CrewMember, 0, len(crewEntries))
+	for _, cm := range crewEntries {
+		for _, job := range cm.Jobs {
+			crew = append(crew, newCrewMember(cm.ID, cm.Name, job.Job, cm.Department, cm.ProfilePath))
+		}
+	}
+
+	credits := &models.Credits{Cast: cast}
+	applyCrew(credits, crew)
+	for _, creator := range payload.CreatedBy {
+		credits.Creators = append(credits.Creators, newCrewMember(creator.ID, creator.Name, "Creator", "Writing", creator.ProfilePath))
+	}
+	return credits, nil
+}
+
+func (e tmdbCastEntry) toModel() models.CastMember {
+	member := models.CastMember{
+		ID:        e.ID,
+		Name:      strings.TrimSpace(e.Name),
+		Character: strings.TrimSpace(e.Character),
+		Order:     e.Order,
+	}
+	if e.ProfilePath != "" {
+		member.ProfilePath = e.ProfilePath
+		member.ProfileURL = fmt.Sprintf("%s/%s%s", tmdbImageBaseURL, tmdbProfileSize, e.ProfilePath)
+	}
+	return member
+}
+
+func newCrewMember(id int64, name, job, department, profilePath string) models.CrewMember {
+	member := models.CrewMember{
+		ID:         id,
+		Name:       strings.TrimSpace(name),
+		Job:        strings.TrimSpace(job),
+		Department: strings.TrimSpace(department),
+	}
+	if profilePath != "" {
+		member.ProfilePath = profilePath
+		member.ProfileURL = fmt.Sprintf("%s/%s%s", tmdbImageBaseURL, tmdbProfileSize, profilePath)
+	}
+	return member
+}
+
+// maxCrewPerDepartment caps how many crew credits each department
+// contributes, mirroring the top-8 cast limit.
+const maxCrewPerDepartment = 8
+
+// applyCrew fills in credits' crew from TMDB's crew list, which is assumed to
+// be in billing order. Each person appears at most once per job, and once in
+// Directors and Writers however many writing jobs they hold.
+func applyCrew(credits *models.Credits, crew []models.CrewMember) {
+	perDepartment := make(map[string]int)
+	seenJob := make(map[string]bool)
+	seenDirector := make(map[int64]bool)
+	seenWriter := make(map[int64]bool)
+	for _, member := range crew {
+		if member.Name == "" || member.Job == "" {
+			continue
+		}
+		jobKey := fmt.Sprintf("%d:%s", member.ID, member.Job)
+		if seenJob[jobKey] {
+			continue
+		}
+		seenJob[jobKey] = true
+
+		if member.Job == "Director" && !seenDirector[member.ID] && len(credits.Directors) < maxCrewPerDepartment {
+			seenDirector[member.ID] = true
+			credits.Directors = append(credits.Directors, member)
+		}
+		if member.Department == "Writing" && !seenWriter[member.ID] && len(credits.Writers) < maxCrewPerDepartment {
+			seenWriter[member.ID] = true
+			credits.Writers = append(credits.Writers, member)
+		}
+		if perDepartment[member.Department] < maxCrewPerDepartment {
+			perDepartment[member.Department]++
+			credits.Crew = append(credits.Crew, member)
+		}
+	}
 }
 
 // fetchTVShowTotalEpisodes fetches the total number of episodes for a TV show (cached)
 func (c *tmdbClient) fetchTVShowTotalEpisodes(ctx context.Context, tmdbID int64) (int, error) {
 	if !c.isConfigured() {
-		return 0, errors.New("tmdb api key not configured")
+		return 0, errTMDBNotConfigured
 	}
 
 	// Check cache first
@@ -2199,10 +2327,107 @@ func (c *tmdbClient) fetchTVShowTotalEpisodes(ctx context.Context, tmdbID int64)
 	return payload.NumberOfEpisodes, nil
 }
 
-// movieReleaseDatesResult contains releases and the US certification
+// tmdbEpisodeGroupTypeAbsolute is TMDB's episode group type for absolute ordering.
+const tmdbEpisodeGroupTypeAbsolute = 2
+
+// tmdbAbsoluteEpisode is one regular episode from a TMDB absolute episode group.
+type tmdbAbsoluteEpisode struct {
+	Season   int    `json:"season"`
+	Episode  int    `json:"episode"`
+	Absolute int    `json:"absolute"`
+	AirDate  string `json:"airDate,omitempty"`
+}
+
+// fetchAbsoluteEpisodeOrder returns the series' episodes in the order of its
+// largest TMDB "Absolute" episode group. Specials are skipped and don't count
+// towards the numbering. Series without such a group return nil.
+func (c *tmdbClient) fetchAbsoluteEpisodeOrder(ctx context.Context, tmdbID int64) ([]tmdbAbsoluteEpisode, error) {
+	if !c.isConfigured() {
+		return nil, errTMDBNotConfigured
+	}
+
+	cacheKey := fmt.Sprintf("tv:%d:absolute_order", tmdbID)
+	if c.cache != nil {
+		var cached []tmdbAbsoluteEpisode
+		if ok, _ := c.cache.get(cacheKey, &cached); ok {
+			return cached, nil
+		}
+	}
+
+	endpoint, err := url.JoinPath(tmdbBaseURL, "tv", fmt.Sprintf("%d", tmdbID), "episode_groups")
+	if err != nil {
+		return nil, err
+	}
+	var groups struct {
+		Results []struct {
+			ID           string `json:"id"`
+			Type         int    `json:"type"`
+			EpisodeCount int    `json:"episode_count"`
+		} `json:"results"`
+	}
+	if err := c.doGET(ctx, endpoint+"?api_key="+c.apiKey, &groups); err != nil {
+		return nil, fmt.Errorf("tmdb tv/%d episode groups failed: %w", tmdbID, err)
+	}
+
+	groupID := ""
+	best := 0
+	for _, group := range groups.Results {
+		if group.Type == tmdbEpisodeGroupTypeAbsolute && group.EpisodeCount > best {
+			groupID, best = group.ID, group.EpisodeCount
+		}
+	}
+
+	var order []tmdbAbsoluteEpisode
+	if groupID != "" {
+		endpoint, err := url.JoinPath(tmdbBaseURL, "tv", "episode_group", groupID)
+		if err != nil {
+			return nil, err
+		}
+		var payload struct {
+			Groups []struct {
+				Order    int `json:"order"`
+				Episodes []struct {
+					SeasonNumber  int    `json:"season_number"`
+					EpisodeNumber int    `json:"episode_number"`
+					Order         int    `json:"order"`
+					AirDate       string `json:"air_date"`
+				} `json:"episodes"`
+			} `json:"groups"`
+		}
+		if err := c.doGET(ctx, endpoint+"?api_key="+c.apiKey, &payload); err != nil {
+			return nil, fmt.Errorf("tmdb episode group %s failed: %w", groupID, err)
+		}
+		sort.SliceStable(payload.Groups, func(i, j int) bool { return payload.Groups[i].Order < payload.Groups[j].Order })
+		absolute := 0
+		for _, group := range payload.Groups {
+			episodes := group.Episodes
+			sort.SliceStable(episodes, func(i, j int) bool { return episodes[i].Order < episodes[j].Order })
+			for _, ep := range episodes {
+				if ep.SeasonNumber <= 0 {
+					continue
+				}
+				absolute++
+				order = append(order, tmdbAbsoluteEpisode{
+					Season:   ep.SeasonNumber,
+					Episode:  ep.EpisodeNumber,
+					Absolute: absolute,
+					AirDate:  strings.TrimSpace(ep.AirDate),
+				})
+			}
+		}
+	}
+
+	// Cache misses too, so series without an absolute group aren't re-queried.
+	if c.cache != nil {
+		_ = c.cache.set(cacheKey, order)
+	}
+	return order, nil
+}
+
+// movieReleaseDatesResult contains releases and the certification per country
 type movieReleaseDatesResult struct {
-	Releases      []models.Release
-	Certification string // US MPAA rating (G, PG, PG-13, R, NC-17)
+	Releases       []models.Release
+	Certifications map[string]string // ISO 3166-1 country -> rating (e.g. "US" -> "PG-13", "DE" -> "12")
 }
 
 func (c *tmdbClient) movieReleaseDates(ctx context.Context, tmdbID int64) ([]models.Release, error) {
@@ -2215,7 +2440,7 @@ func (c *tmdbClient) movieReleaseDates(ctx context.Context, tmdbID int64) ([]mod
 
 func (c *tmdbClient) movieReleaseDatesWithCert(ctx context.Context, tmdbID int64) (*movieReleaseDatesResult, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("tmdb api key not configured")
+		return nil, errTMDBNotConfigured
 	}
 
 	endpoint, err := url.JoinPath(tmdbBaseURL, "movie", fmt.Sprintf("%d", tmdbID), "release_dates")
@@ -2232,14 +2457,14 @@ func (c *tmdbClient) movieReleaseDatesWithCert(ctx context.Context, tmdbID int64
 	q.Set("api_key", c.apiKey)
 	req.URL.RawQuery = q.Encode()
 
-	resp, err := c.httpc.Do(req)
+	resp, err := c.limiter.do(c.httpc, req)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("tmdb movie release dates failed: %s", resp.Status)
+		return nil, upstreamError(resp.StatusCode, "tmdb movie release dates failed: %s", resp.Status)
 	}
 
 	var payload tmdbReleaseDatesResponse
@@ -2249,17 +2474,18 @@ func (c *tmdbClient) movieReleaseDatesWithCert(ctx context.Context, tmdbID int64
 
 	now := time.Now()
 	releases := make([]models.Release, 0, 8)
-	var usCertification string
+	certifications := make(map[string]string)
 
 	for _, country := range payload.Results {
 		countryCode := strings.TrimSpace(country.ISO31661)
 
-		// Extract US certification (prefer theatrical releases for rating)
-		if countryCode == "US" && usCertification == "" {
+		// Take the first certification listed for each country (theatrical
+		// releases come first, so they win).
+		if countryCode != "" && certifications[countryCode] == "" {
 			for _, entry := range country.ReleaseDates {
 				cert := strings.TrimSpace(entry.Certification)
 				if cert != "" {
-					usCertification = cert
+					certifications[countryCode] = cert
 					break
 				}
 			}
@@ -2295,20 +2521,21 @@ func (c *tmdbClient) movieReleaseDatesWithCert(ctx context.Context, tmdbID int64
 	}
 
 	return &movieReleaseDatesResult{
-		Releases:      releases,
-		Certification: usCertification,
+		Releases:       releases,
+		Certifications: certifications,
 	}, nil
 }
 
-// fetchTVContentRating fetches the US TV content rating for a TV show
-func (c *tmdbClient) fetchTVContentRating(ctx context.Context, tmdbID int64) (string, error) {
+// fetchTVContentRatings fetches a TV show's content rating per country
+// (ISO 3166-1 country -> rating).
+func (c *tmdbClient) fetchTVContentRatings(ctx context.Context, tmdbID int64) (map[string]string, error) {
 	if !c.isConfigured() {
-		return "", errors.New("tmdb api key not configured")
+		return nil, errTMDBNotConfigured
 	}
 
 	endpoint, err := url.JoinPath(tmdbBaseURL, "tv", fmt.Sprintf("%d", tmdbID), "content_ratings")
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	endpoint = endpoint + "?api_key=" + c.apiKey
 
@@ -2320,22 +2547,22 @@ func (c *tmdbClient) fetchTVContentRating(ctx context.Context, tmdbID int64) (st
 	}
 
 	if err := c.doGET(ctx, endpoint, &payload); err != nil {
-		return "", fmt.Errorf("tmdb tv/%d content_ratings failed: %w", tmdbID, err)
+		return nil, fmt.Errorf("tmdb tv/%d content_ratings failed: %w", tmdbID, err)
 	}
 
-	// Find US rating
+	ratings := make(map[string]string, len(payload.Results))
 	for _, r := range payload.Results {
-		if r.ISO31661 == "US" {
-			return strings.TrimSpace(r.Rating), nil
+		country := strings.TrimSpace(r.ISO31661)
+		if rating := strings.TrimSpace(r.Rating); country != "" && rating != "" {
+			ratings[country] = rating
 		}
 	}
-
-	return "", nil
+	return ratings, nil
 }
 
 func (c *tmdbClient) fetchExternalID(ctx context.Context, mediaType string, tmdbID int64) (string, error) {
 	if !c.isConfigured() {
-		return "", errors.New("tmdb api key not configured")
+		return "", errTMDBNotConfigured
 	}
 
 	// Map "series" to "tv" for TMDB API
@@ -2354,27 +2581,17 @@ func (c *tmdbClient) fetchExternalID(ctx context.Context, mediaType string, tmdb
 	var lastErr error
 	backoff := 300 * time.Millisecond
 
-	for attempt := 0; attempt < 3; attempt++ {
-		// Rate limiting — compute wait outside the lock to avoid blocking other goroutines
-		c.throttleMu.Lock()
-		wait := c.minInterval - time.Since(c.lastRequest)
-		if wait > 0 {
-			c.lastRequest = time.Now().Add(wait)
-		} else {
-			c.lastRequest = time.Now()
-			wait = 0
-		}
-		c.throttleMu.Unlock()
-		if wait > 0 {
-			time.Sleep(wait)
-		}
+	if err := offlineError("tmdb"); err != nil {
+		return "", err
+	}
 
+	for attempt := 0; attempt < 3; attempt++ {
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 		if err != nil {
 			return "", err
 		}
 
-		resp, err := c.httpc.Do(req)
+		resp, err := c.limiter.do(c.httpc, req)
 		if err != nil {
 			lastErr = err
 			log.Printf("[tmdb] fetchExternalID http error (attempt %d/3): %v", attempt+1, err)
@@ -2387,7 +2604,7 @@ func (c *tmdbClient) fetchExternalID(ctx context.Context, mediaType string, tmdb
 		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
 			resp.Body.Close()
 			log.Printf("[tmdb] fetchExternalID rate limited (attempt %d/3): status %d", attempt+1, resp.StatusCode)
-			lastErr = fmt.Errorf("tmdb external_ids for %s/%d failed: %s", apiMediaType, tmdbID, resp.Status)
+			lastErr = upstreamError(resp.StatusCode, "tmdb external_ids for %s/%d failed: %s", apiMediaType, tmdbID, resp.Status)
 			if ra := resp.Header.Get("Retry-After"); ra != "" {
 				if secs, err := strconv.Atoi(ra); err == nil {
 					time.Sleep(time.Duration(secs) * time.Second)
@@ -2401,7 +2618,7 @@ func (c *tmdbClient) fetchExternalID(ctx context.Context, mediaType string, tmdb
 
 		if resp.StatusCode >= 400 {
 			resp.Body.Close()
-			return "", fmt.Errorf("tmdb external_ids for %s/%d failed: %s", apiMediaType, tmdbID, resp.Status)
+			return "", upstreamError(resp.StatusCode, "tmdb external_ids for %s/%d failed: %s", apiMediaType, tmdbID, resp.Status)
 		}
 
 		err = json.NewDecoder(resp.Body).Decode(&payload)
@@ -2418,7 +2635,7 @@ func (c *tmdbClient) fetchExternalID(ctx context.Context, mediaType string, tmdb
 // findMovieByIMDBID looks up a movie's TMDB ID using its IMDB ID
 func (c *tmdbClient) findMovieByIMDBID(ctx context.Context, imdbID string) (int64, error) {
 	if !c.isConfigured() {
-		return 0, errors.New("tmdb api key not configured")
+		return 0, errTMDBNotConfigured
 	}
 	if imdbID == "" {
 		return 0, errors.New("imdb id required")
@@ -2434,32 +2651,22 @@ func (c *tmdbClient) findMovieByIMDBID(ctx context.Context, imdbID string) (int6
 	var lastErr error
 	backoff := 300 * time.Millisecond
 
+	if err := offlineError("tmdb"); err != nil {
+		return 0, err
+	}
+
 	for attempt := 0; attempt < 3; attempt++ {
 		// Don't retry if context is already canceled
 		if ctx.Err() != nil {
 			return 0, ctx.Err()
 		}
 
-		// Rate limiting — compute wait outside the lock to avoid blocking other goroutines
-		c.throttleMu.Lock()
-		wait := c.minInterval - time.Since(c.lastRequest)
-		if wait > 0 {
-			c.lastRequest = time.Now().Add(wait)
-		} else {
-			c.lastRequest = time.Now()
-			wait = 0
-		}
-		c.throttleMu.Unlock()
-		if wait > 0 {
-			time.Sleep(wait)
-		}
-
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 		if err != nil {
 			return 0, err
 		}
 
-		resp, err := c.httpc.Do(req)
+		resp, err := c.limiter.do(c.httpc, req)
 		if err != nil {
 			if ctx.Err() != nil {
 				return 0, ctx.Err() // context canceled — don't retry
@@ -2474,7 +2681,7 @@ func (c *tmdbClient) findMovieByIMDBID(ctx context.Context, imdbID string) (int6
 		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
 			resp.Body.Close()
 			log.Printf("[tmdb] findMovieByIMDBID rate limited (attempt %d/3): status %d", attempt+1, resp.StatusCode)
-			lastErr = fmt.Errorf("tmdb find %s failed: %s", imdbID, resp.Status)
+			lastErr = upstreamError(resp.StatusCode, "tmdb find %s failed: %s", imdbID, resp.Status)
 			if ra := resp.Header.Get("Retry-After"); ra != "" {
 				if secs, err := strconv.Atoi(ra); err == nil {
 					time.Sleep(time.Duration(secs) * time.Second)
@@ -2488,7 +2695,7 @@ func (c *tmdbClient) findMovieByIMDBID(ctx context.Context, imdbID string) (int6
 
 		if resp.StatusCode >= 400 {
 			resp.Body.Close()
-			return 0, fmt.Errorf("tmdb find %s failed: %s", imdbID, resp.Status)
+			return 0, upstreamError(resp.StatusCode, "tmdb find %s failed: %s", imdbID, resp.Status)
 		}
 
 		var result struct {
@@ -2514,7 +2721,7 @@ func (c *tmdbClient) findMovieByIMDBID(ctx context.Context, imdbID string) (int6
 // findTVByIMDBID looks up a TV show's TMDB ID using its IMDB ID
 func (c *tmdbClient) findTVByIMDBID(ctx context.Context, imdbID string) (int64, error) {
 	if !c.isConfigured() {
-		return 0, errors.New("tmdb api key not configured")
+		return 0, errTMDBNotConfigured
 	}
 	if imdbID == "" {
 		return 0, errors.New("imdb id required")
@@ -2528,31 +2735,21 @@ func (c *tmdbClient) findTVByIMDBID(ctx context.Context, imdbID string) (int64,
 	var lastErr error
 	backoff := 300 * time.Millisecond
 
+	if err := offlineError("tmdb"); err != nil {
+		return 0, err
+	}
+
 	for attempt := 0; attempt < 3; attempt++ {
 		if ctx.Err() != nil {
 			return 0, ctx.Err()
 		}
 
-		// Rate limiting — compute wait outside the lock to avoid blocking other goroutines
-		c.throttleMu.Lock()
-		wait := c.minInterval - time.Since(c.lastRequest)
-		if wait > 0 {
-			c.lastRequest = time.Now().Add(wait)
-		} else {
-			c.lastRequest = time.Now()
-			wait = 0
-		}
-		c.throttleMu.Unlock()
-		if wait > 0 {
-			time.Sleep(wait)
-		}
-
 		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 		if err != nil {
 			return 0, err
 		}
 
-		resp, err := c.httpc.Do(req)
+		resp, err := c.limiter.do(c.httpc, req)
 		if err != nil {
 			if ctx.Err() != nil {
 				return 0, ctx.Err()
@@ -2567,7 +2764,7 @@ func (c *tmdbClient) findTVByIMDBID(ctx context.Context, imdbID string) (int64,
 		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
 			resp.Body.Close()
 			log.Printf("[tmdb] findTVByIMDBID rate limited (attempt %d/3): status %d", attempt+1, resp.StatusCode)
-			lastErr = fmt.Errorf("tmdb find TV %s failed: %s", imdbID, resp.Status)
+			lastErr = upstreamError(resp.StatusCode, "tmdb find TV %s failed: %s", imdbID, resp.Status)
 			if ra := resp.Header.Get("Retry-After"); ra != "" {
 				if secs, err := strconv.Atoi(ra); err == nil {
 					time.Sleep(time.Duration(secs) * time.Second)
@@ -2581,7 +2778,7 @@ func (c *tmdbClient) findTVByIMDBID(ctx context.Context, imdbID string) (int64,
 
 		if resp.StatusCode >= 400 {
 			resp.Body.Close()
-			return 0, fmt.Errorf("tmdb find TV %s failed: %s", imdbID, resp.Status)
+			return 0, upstreamError(resp.StatusCode, "tmdb find TV %s failed: %s", imdbID, resp.Status)
 		}
 
 		var result struct {
@@ -2643,7 +2840,7 @@ type tmdbSimilarResponse struct {
 // fetchPersonDetails retrieves detailed information about a person from TMDB
 func (c *tmdbClient) fetchPersonDetails(ctx context.Context, personID int64) (*models.Person, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("tmdb api key not configured")
+		return nil, errTMDBNotConfigured
 	}
 
 	endpoint, err := url.JoinPath(tmdbBaseURL, "person", fmt.Sprintf("%d", personID))
@@ -2688,7 +2885,7 @@ func (c *tmdbClient) fetchPersonDetails(ctx context.Context, personID int64) (*m
 // fetchPersonCombinedCredits retrieves all movie and TV credits for a person from TMDB
 func (c *tmdbClient) fetchPersonCombinedCredits(ctx context.Context, personID int64) ([]models.Title, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("tmdb api key not configured")
+		return nil, errTMDBNotConfigured
 	}
 
 	endpoint, err := url.JoinPath(tmdbBaseURL, "person", fmt.Sprintf("%d", personID), "combined_credits")
@@ -2892,6 +3089,7 @@ func (c *tmdbClient) fetchPersonCombinedCredits(ctx context.Context, personID in
 			MediaType: mediaType,
 			TMDBID:    credit.ID,
 			Language:  credit.OriginalLanguage,
+			Genres:    resolveGenreIDs(credit.GenreIDs, credit.MediaType),
 		}
 		if year := parseTMDBYear(credit.ReleaseDate, credit.FirstAirDate); year != 0 {
 			title.Year = year
@@ -2954,7 +3152,7 @@ type titleSeedInfo struct {
 // year for a title in parallel. Used by the custom recommendations engine.
 func (c *tmdbClient) fetchTitleSeedInfo(ctx context.Context, mediaType string, tmdbID int64) (titleSeedInfo, error) {
 	if !c.isConfigured() {
-		return titleSeedInfo{}, errors.New("tmdb api key not configured")
+		return titleSeedInfo{}, errTMDBNotConfigured
 	}
 
 	apiMediaType := strings.ToLower(strings.TrimSpace(mediaType))
@@ -3060,7 +3258,7 @@ type discoverSimilarOpts struct {
 // to find content similar to a seed title. Returns up to 20 titles.
 func (c *tmdbClient) discoverSimilar(ctx context.Context, mediaType string, genreIDs []int64, excludeTMDBID int64, opts discoverSimilarOpts) ([]models.Title, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("tmdb api key not configured")
+		return nil, errTMDBNotConfigured
 	}
 
 	apiMediaType := strings.ToLower(strings.TrimSpace(mediaType))
@@ -3171,7 +3369,7 @@ func (c *tmdbClient) discoverSimilar(ctx context.Context, mediaType string, genr
 // Returns up to 20 similar titles
 func (c *tmdbClient) fetchSimilar(ctx context.Context, mediaType string, tmdbID int64) ([]models.Title, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("tmdb api key not configured")
+		return nil, errTMDBNotConfigured
 	}
 
 	// Map "series" to "tv" for TMDB API
@@ -3236,7 +3434,7 @@ func (c *tmdbClient) fetchSimilar(ctx context.Context, mediaType string, tmdbID
 // Returns the best matching Title or nil if no match found.
 func (c *tmdbClient) searchByTitle(ctx context.Context, title string, year int, mediaType string) (*models.Title, error) {
 	if !c.isConfigured() {
-		return nil, errors.New("tmdb api key not configured")
+		return nil, errTMDBNotConfigured
 	}
 
 	apiMediaType := strings.ToLower(strings.TrimSpace(mediaType))
@@ -3333,12 +3531,205 @@ func (c *tmdbClient) discoverByDecade(ctx context.Context, mediaType string, dec
 	return c.discoverTitles(ctx, mediaType, filter, fmt.Sprintf("decade decade=%d", decadeStart), page)
 }
 
+// discoverByFilter fetches movies or TV shows matching a saved discover filter.
+func (c *tmdbClient) discoverByFilter(ctx context.Context, mediaType string, filter models.DiscoverFilter, page int) ([]models.Title, int, error) {
+	return c.discoverTitles(ctx, mediaType, tmdbDiscoverFilterQuery(mediaType, filter), "filter", page)
+}
+
+// searchKeywords looks up TMDB keywords by name. Results are in TMDB's
+// relevance order.
+func (c *tmdbClient) searchKeywords(ctx context.Context, query string) ([]models.Keyword, error) {
+	if !c.isConfigured() {
+		return nil, errTMDBNotConfigured
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return []models.Keyword{}, nil
+	}
+	endpoint := fmt.Sprintf("%s/search/keyword?api_key=%s&query=%s&page=1", tmdbBaseURL, c.apiKey, url.QueryEscape(query))
+	var payload struct {
+		Results []struct {
+			ID   int64  `json:"id"`
+			Name string `json:"name"`
+		} `json:"results"`
+	}
+	if err := c.doGET(ctx, endpoint, &payload); err != nil {
+		return nil, fmt.Errorf("tmdb keyword search for %q failed: %w", query, err)
+	}
+	keywords := make([]models.Keyword, 0, len(payload.Results))
+	for _, result := range payload.Results {
+		if result.ID > 0 && strings.TrimSpace(result.Name) != "" {
+			keywords = append(keywords, models.Keyword{ID: result.ID, Name: result.Name})
+		}
+	}
+	return keywords, nil
+}
+
+// tmdbPersonResult is a person from TMDB's person search with the
+// popularity used to rank them.
+type tmdbPersonResult struct {
+	Person     models.Person `json:"person"`
+	Popularity float64       `json:"popularity"`
+}
+
+// searchPeople searches TMDB for people by name.
+func (c *tmdbClient) searchPeople(ctx context.Context, query string, includeAdult bool) ([]tmdbPersonResult, error) {
+	if !c.isConfigured() {
+		return nil, errTMDBNotConfigured
+	}
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return []tmdbPersonResult{}, nil
+	}
+	endpoint := fmt.Sprintf("%s/search/person?api_key=%s&query=%s&include_adult=%t&page=1",
+		tmdbBaseURL, c.apiKey, url.QueryEscape(query), includeAdult)
+	if lang := strings.TrimSpace(c.language); lang != "" {
+		endpoint += "&language=" + normalizeLanguage(lang)
+	}
+	var payload struct {
+		Results []struct {
+			ID                 int64   `json:"id"`
+			Name               string  `json:"name"`
+			ProfilePath        string  `json:"profile_path"`
+			KnownForDepartment string  `json:"known_for_department"`
+			Popularity         float64 `json:"popularity"`
+			Adult              bool    `json:"adult"`
+		} `json:"results"`
+	}
+	if err := c.doGET(ctx, endpoint, &payload); err != nil {
+		return nil, fmt.Errorf("tmdb person search for %q failed: %w", query, err)
+	}
+	people := make([]tmdbPersonResult, 0, len(payload.Results))
+	for _, result := range payload.Results {
+		name := strings.TrimSpace(result.Name)
+		if result.ID <= 0 || name == "" || (result.Adult && !includeAdult) {
+			continue
+		}
+		person := models.Person{ID: result.ID, Name: name, KnownFor: strings.TrimSpace(result.KnownForDepartment)}
+		if result.ProfilePath != "" {
+			person.ProfileURL = fmt.Sprintf("%s/%s%s", tmdbImageBaseURL, tmdbPosterSize, result.ProfilePath)
+		}
+		people = append(people, tmdbPersonResult{Person: person, Popularity: result.Popularity})
+	}
+	return people, nil
+}
+
+// discoverGenreHubRow fetches one genre hub row (see the GenreHubRow*
+// constants) for movies or TV shows.
+func (c *tmdbClient) discoverGenreHubRow(ctx context.Context, mediaType string, genreID int64, row string, page int) ([]models.Title, int, error) {
+	query := tmdbGenreHubQuery(mediaType, genreID, row, time.Now().UTC())
+	return c.discoverTitles(ctx, mediaType, query, fmt.Sprintf("genre hub row=%s genreId=%d", row, genreID), page)
+}
+
+// tmdbGenreHubQuery builds the discover params for a genre hub row. Top rated
+// uses the same vote-count floors as the decade shelves; new is the most
+// popular of the genre's releases from the last genreHubNewWindow.
+func tmdbGenreHubQuery(mediaType string, genreID int64, row string, now time.Time) string {
+	dateField, minVotes := "primary_release_date", 300
+	if strings.ToLower(strings.TrimSpace(mediaType)) != "movie" {
+		dateField, minVotes = "first_air_date", 50
+	}
+	query := fmt.Sprintf("&with_genres=%d", genreID)
+	switch row {
+	case GenreHubRowTopRated:
+		query += fmt.Sprintf("&vote_count.gte=%d&sort_by=vote_average.desc", minVotes)
+	case GenreHubRowNew:
+		query += fmt.Sprintf("&%s.gte=%s&%s.lte=%s",
+			dateField, now.Add(-genreHubNewWindow).Format("2006-01-02"),
+			dateField, now.Format("2006-01-02"))
+	}
+	return query
+}
+
+// tmdbDiscoverFilterQuery converts a discover filter to TMDB discover params.
+// Rating sorts get a vote-count floor unless the filter sets one, so titles
+// with a handful of perfect votes don't fill the first pages.
+func tmdbDiscoverFilterQuery(mediaType string, filter models.DiscoverFilter) string {
+	dateField, titleField := "primary_release_date", "title"
+	if strings.ToLower(strings.TrimSpace(mediaType)) != "movie" {
+		dateField, titleField = "first_air_date", "name"
+	}
+
+	var query strings.Builder
+	if len(filter.GenreIDs) > 0 {
+		ids := make([]string, 0, len(filter.GenreIDs))
+		for _, id := range filter.GenreIDs {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+		query.WriteString("&with_genres=" + strings.Join(ids, ","))
+	}
+	if len(filter.KeywordIDs) > 0 {
+		ids := make([]string, 0, len(filter.KeywordIDs))
+		for _, id := range filter.KeywordIDs {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+		query.WriteString("&with_keywords=" + url.QueryEscape(strings.Join(ids, "|")))
+	}
+	if len(filter.CastIDs) > 0 {
+		ids := make([]string, 0, len(filter.CastIDs))
+		for _, id := range filter.CastIDs {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+		query.WriteString("&with_cast=" + strings.Join(ids, ","))
+	}
+	if filter.YearFrom > 0 {
+		fmt.Fprintf(&query, "&%s.gte=%d-01-01", dateField, filter.YearFrom)
+	}
+	if filter.YearTo > 0 {
+		fmt.Fprintf(&query, "&%s.lte=%d-12-31", dateField, filter.YearTo)
+	}
+	if filter.RuntimeMin > 0 {
+		fmt.Fprintf(&query, "&with_runtime.gte=%d", filter.RuntimeMin)
+	}
+	if filter.RuntimeMax > 0 {
+		fmt.Fprintf(&query, "&with_runtime.lte=%d", filter.RuntimeMax)
+	}
+	if filter.MinRating > 0 {
+		query.WriteString("&vote_average.gte=" + strconv.FormatFloat(filter.MinRating, 'f', -1, 64))
+	}
+	minVotes := filter.MinVotes
+	if minVotes <= 0 && filter.Sort == "rating" {
+		minVotes = 100
+	}
+	if minVotes > 0 {
+		fmt.Fprintf(&query, "&vote_count.gte=%d", minVotes)
+	}
+	if lang := strings.ToLower(strings.TrimSpace(filter.OriginalLanguage)); lang != "" {
+		query.WriteString("&with_original_language=" + url.QueryEscape(lang))
+	}
+	if len(filter.WatchProviderIDs) > 0 {
+		ids := make([]string, 0, len(filter.WatchProviderIDs))
+		for _, id := range filter.WatchProviderIDs {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+		// "|" makes TMDB match any of the providers rather than all of them.
+		query.WriteString("&with_watch_providers=" + url.QueryEscape(strings.Join(ids, "|")))
+		query.WriteString("&watch_region=" + url.QueryEscape(normalizeWatchProviderRegion(filter.WatchRegion)))
+	}
+
+	sortBy := "popularity.desc"
+	switch filter.Sort {
+	case "rating":
+		sortBy = "vote_average.desc"
+	case "newest":
+		sortBy = dateField + ".desc"
+	case "oldest":
+		sortBy = dateField + ".asc"
+	case "title":
+		sortBy = titleField + ".asc"
+	}
+	query.WriteString("&sort_by=" + sortBy)
+	return query.String()
+}
+
 // discoverTitles runs a TMDB discover query with the given extra filter params
-// (already URL-encoded, starting with "&") sorted by popularity.
+// (already URL-encoded, starting with "&"). Results are sorted by popularity
+// and limited to the client language's original language unless the filter
+// params set sort_by or with_original_language themselves.
 func (c *tmdbClient) discoverTitles(ctx context.Context, mediaType, filterQuery, logLabel string, page int) ([]models.Title, int, error) {
 	start := time.Now()
 	if !c.isConfigured() {
-		return nil, 0, errors.New("tmdb api key not configured")
+		return nil, 0, errTMDBNotConfigured
 	}
 
 	// Map "series" to "tv" for TMDB API
@@ -3347,11 +3738,14 @@ func (c *tmdbClient) discoverTitles(ctx context.Context, mediaType, filterQuery,
 		apiMediaType = "tv"
 	}
 
-	endpoint := fmt.Sprintf("%s/discover/%s?api_key=%s%s&sort_by=popularity.desc&page=%d",
+	if !strings.Contains(filterQuery, "&sort_by=") {
+		filterQuery += "&sort_by=popularity.desc"
+	}
+	endpoint := fmt.Sprintf("%s/discover/%s?api_key=%s%s&page=%d",
 		tmdbBaseURL, apiMediaType, c.apiKey, filterQuery, page)
 	if lang := strings.TrimSpace(c.language); lang != "" {
 		endpoint = endpoint + "&language=" + normalizeLanguage(lang)
-		if originalLanguage := tmdbOriginalLanguageFilter(lang); originalLanguage != "" {
+		if originalLanguage := tmdbOriginalLanguageFilter(lang); originalLanguage != "" && !strings.Contains(filterQuery, "&with_original_language=") {
 			endpoint = endpoint + "&with_original_language=" + url.QueryEscape(originalLanguage)
 		}
 	}
@@ -513,3 +513,90 @@ func TestLogoLanguage(t *testing.T) {
 		})
 	}
 }
+
+func TestFetchMovieCredits_Crew(t *testing.T) {
+	rt := &countingRoundTripper{body: `{
+		"cast":[{"id":1,"name":"Keanu Reeves","character":"Neo","order":0}],
+		"crew":[
+			{"id":10,"name":"Lana Wachowski","job":"Director","department":"Directing"},
+			{"id":10,"name":"Lana Wachowski","job":"Writer","department":"Writing"},
+			{"id":10,"name":"Lana Wachowski","job":"Writer","department":"Writing"},
+			{"id":11,"name":"Lilly Wachowski","job":"Director","department":"Directing"},
+			{"id":12,"name":"Don Davis","job":"Original Music Composer","department":"Sound","profile_path":"/dd.jpg"}
+		]}`}
+	c := newTMDBClient("test-key", "en", &http.Client{Transport: rt}, nil)
+
+	credits, err := c.fetchCredits(context.Background(), "movie", 603)
+	if err != nil {
+		t.Fatalf("fetchCredits: %v", err)
+	}
+	if len(credits.Cast) != 1 || credits.Cast[0].Character != "Neo" {
+		t.Fatalf("unexpected cast %+v", credits.Cast)
+	}
+	if len(credits.Directors) != 2 || credits.Directors[1].Name != "Lilly Wachowski" {
+		t.Fatalf("unexpected directors %+v", credits.Directors)
+	}
+	if len(credits.Writers) != 1 || credits.Writers[0].Job != "Writer" {
+		t.Fatalf("expected duplicate writing credits to collapse, got %+v", credits.Writers)
+	}
+	if len(credits.Crew) != 4 {
+		t.Fatalf("expected 4 crew credits, got %+v", credits.Crew)
+	}
+	if composer := credits.Crew[3]; composer.Department != "Sound" || composer.ProfileURL == "" {
+		t.Fatalf("unexpected composer credit %+v", composer)
+	}
+}
+
+func TestFetchTVCredits_CreatorsAndCrew(t *testing.T) {
+	var gotPath, gotAppend string
+	rt := roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		gotPath = req.URL.Path
+		gotAppend = req.URL.Query().Get("append_to_response")
+		return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: io.NopCloser(strings.NewReader(`{
+			"created_by":[{"id":20,"name":"Dan Erickson"}],
+			"aggregate_credits":{
+				"cast":[{"id":1,"name":"Adam Scott","order":0,"roles":[{"character":"Mark Scout","episode_count":19}]}],
+				"crew":[
+					{"id":21,"name":"Aoife McArdle","department":"Directing","total_episode_count":3,"jobs":[{"job":"Director","episode_count":3}]},
+					{"id":22,"name":"Ben Stiller","department":"Directing","total_episode_count":11,"jobs":[{"job":"Director","episode_count":11}]}
+				]}}`))}, nil
+	})
+	c := newTMDBClient("test-key", "en", &http.Client{Transport: rt}, nil)
+
+	credits, err := c.fetchCredits(context.Background(), "series", 95396)
+	if err != nil {
+		t.Fatalf("fetchCredits: %v", err)
+	}
+	if gotPath != "/3/tv/95396" || gotAppend != "aggregate_credits" {
+		t.Fatalf("unexpected request %s append=%s", gotPath, gotAppend)
+	}
+	if len(credits.Cast) != 1 || credits.Cast[0].Character != "Mark Scout" {
+		t.Fatalf("unexpected cast %+v", credits.Cast)
+	}
+	if len(credits.Creators) != 1 || credits.Creators[0].Name != "Dan Erickson" || credits.Creators[0].Job != "Creator" {
+		t.Fatalf("unexpected creators %+v", credits.Creators)
+	}
+	// Directors are ranked by how many episodes they directed.
+	if len(credits.Directors) != 2 || credits.Directors[0].Name != "Ben Stiller" {
+		t.Fatalf("unexpected directors %+v", credits.Directors)
+	}
+}
+
+func TestSeriesSeasonDetails_GuestStars(t *testing.T) {
+	rt := &countingRoundTripper{body: `{"id":5,"season_number":1,"episodes":[
+		{"id":100,"name":"Good News About Hell","season_number":1,"episode_number":1,
+		 "guest_stars":[{"id":30,"name":"Yul Vazquez","character":"Peter Kilmer","order":0,"profile_path":"/yv.jpg"}]}]}`}
+	c := newTMDBClient("test-key", "en", &http.Client{Transport: rt}, nil)
+
+	season, err := c.seriesSeasonDetails(context.Background(), 95396, tmdbSeasonSummary{Number: 1})
+	if err != nil {
+		t.Fatalf("seriesSeasonDetails: %v", err)
+	}
+	if len(season.Episodes) != 1 {
+		t.Fatalf("expected 1 episode, got %d", len(season.Episodes))
+	}
+	guests := season.Episodes[0].GuestStars
+	if len(guests) != 1 || guests[0].Character != "Peter Kilmer" || guests[0].ProfileURL == "" {
+		t.Fatalf("unexpected guest stars %+v", guests)
+	}
+}
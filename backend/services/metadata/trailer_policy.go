@@ -0,0 +1,243 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"novastream/models"
+)
+
+// defaultTrailerPolicyMaxDiskMB is the trailer disk quota used when none is configured.
+const defaultTrailerPolicyMaxDiskMB = 1024
+
+// trailerPolicyPinDuration keeps policy trailers around between runs, so
+// trailers fetched during a night-only window are still there the next evening.
+const trailerPolicyPinDuration = 24 * time.Hour
+
+// TrailerPrequeuePolicy controls automatic trailer downloads for the titles a
+// home screen opens on: the hero/billboard (Top 10 Today) and the start of the
+// trending rows.
+type TrailerPrequeuePolicy struct {
+	Enabled          bool
+	HeroItems        int    // Top 10 titles to prequeue
+	TrendingRowItems int    // Titles from the start of the trending movie and series rows
+	MaxDiskMB        int    // Quota for all downloaded trailers; 0 uses the default
+	WindowStart      string // "HH:MM" server local time; empty start and end allow any time
+	WindowEnd        string
+	PreviewClips     bool // Also cut a short muted preview clip from each trailer
+}
+
+// SetTrailerPrequeuePolicy replaces the policy used by the trailer policy worker.
+func (s *Service) SetTrailerPrequeuePolicy(policy TrailerPrequeuePolicy) {
+	s.trailerPolicyMu.Lock()
+	s.trailerPolicy = policy
+	s.trailerPolicyMu.Unlock()
+}
+
+// SetTrailerPolicyIdleCheck sets the function the policy worker uses to decide
+// whether the server is idle (e.g. no active playback). Without one the server
+// is always treated as idle.
+func (s *Service) SetTrailerPolicyIdleCheck(idle func() bool) {
+	s.trailerPolicyMu.Lock()
+	s.trailerPolicyIdle = idle
+	s.trailerPolicyMu.Unlock()
+}
+
+func (s *Service) trailerPolicyState() (TrailerPrequeuePolicy, func() bool) {
+	s.trailerPolicyMu.RLock()
+	defer s.trailerPolicyMu.RUnlock()
+	return s.trailerPolicy, s.trailerPolicyIdle
+}
+
+// StartTrailerPrequeuePolicyWorker periodically downloads trailers for hero and
+// trending titles while the policy is enabled, the server is idle, and the
+// current time is inside the policy's download window.
+func (s *Service) StartTrailerPrequeuePolicyWorker(interval time.Duration) {
+	if s.demo || s.trailerPrequeue == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			s.runTrailerPrequeuePolicy(context.Background())
+		}
+	}()
+}
+
+// runTrailerPrequeuePolicy performs one policy pass. Downloads run one at a
+// time and the pass stops as soon as the window closes, playback starts, or the
+// disk quota is reached.
+func (s *Service) runTrailerPrequeuePolicy(ctx context.Context) {
+	if !s.trailerPolicyRunning.CompareAndSwap(false, true) {
+		return
+	}
+	defer s.trailerPolicyRunning.Store(false)
+
+	policy, idle := s.trailerPolicyState()
+	canDownload := func() (bool, string) {
+		if !policy.Enabled {
+			return false, "disabled"
+		}
+		if offlineMode.Load() {
+			return false, "offline mode"
+		}
+		if ok, err := inTrailerPolicyWindow(time.Now(), policy.WindowStart, policy.WindowEnd); !ok {
+			if err != nil {
+				return false, err.Error()
+			}
+			return false, "outside download window"
+		}
+		if idle != nil && !idle() {
+			return false, "server busy"
+		}
+		return true, ""
+	}
+	if ok, _ := canDownload(); !ok {
+		return
+	}
+
+	maxDiskMB := policy.MaxDiskMB
+	if maxDiskMB <= 0 {
+		maxDiskMB = defaultTrailerPolicyMaxDiskMB
+	}
+	quota := int64(maxDiskMB) * 1024 * 1024
+
+	candidates := s.trailerPolicyCandidates(ctx, policy)
+	var downloaded, pinned, previews int
+	for _, title := range candidates {
+		if ctx.Err() != nil {
+			return
+		}
+		if ok, reason := canDownload(); !ok {
+			log.Printf("[trailer-policy] stopping early: %s", reason)
+			break
+		}
+		if usage := s.trailerPrequeue.DiskUsage(); usage >= quota {
+			log.Printf("[trailer-policy] stopping early: trailer disk quota reached (%d/%d MB)", usage/(1024*1024), maxDiskMB)
+			break
+		}
+
+		videoURL := s.trailerPolicyVideoURL(ctx, title)
+		if videoURL == "" {
+			continue
+		}
+		wasQueued := false
+		if _, ok := s.trailerPrequeue.GetStatus(s.trailerPrequeue.generateID(videoURL)); ok {
+			wasQueued = true
+		}
+		id, status := s.trailerPrequeue.PrequeuePinned(videoURL, time.Now().Add(trailerPolicyPinDuration))
+		switch {
+		case status == TrailerStatusFailed:
+			log.Printf("[trailer-policy] download failed for %q", title.Name)
+		case wasQueued:
+			pinned++
+		default:
+			downloaded++
+		}
+		if policy.PreviewClips && status == TrailerStatusReady {
+			if s.trailerPrequeue.PreparePreview(id, title) == TrailerStatusReady {
+				previews++
+			}
+		}
+	}
+	log.Printf("[trailer-policy] pass complete: %d candidates, %d downloaded, %d already cached, %d preview clips", len(candidates), downloaded, pinned, previews)
+}
+
+// trailerPolicyCandidates returns hero titles followed by the first titles of
+// the trending movie and series rows, without duplicates.
+func (s *Service) trailerPolicyCandidates(ctx context.Context, policy TrailerPrequeuePolicy) []models.Title {
+	var candidates []models.Title
+	seen := make(map[string]bool)
+	add := func(items []models.TrendingItem, limit int) {
+		for i := 0; i < len(items) && i < limit; i++ {
+			title := items[i].Title
+			key := title.MediaType + ":" + title.ID
+			if title.ID == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			candidates = append(candidates, title)
+		}
+	}
+
+	if policy.HeroItems > 0 {
+		items, err := s.GetTopTen(ctx, "all", nil)
+		if err != nil {
+			log.Printf("[trailer-policy] top ten unavailable: %v", err)
+		}
+		add(items, policy.HeroItems)
+	}
+	if policy.TrendingRowItems > 0 {
+		for _, mediaType := range []string{"movie", "series"} {
+			items, err := s.Trending(ctx, mediaType)
+			if err != nil {
+				log.Printf("[trailer-policy] trending %s unavailable: %v", mediaType, err)
+				continue
+			}
+			add(items, policy.TrendingRowItems)
+		}
+	}
+	return candidates
+}
+
+// trailerPolicyVideoURL returns the primary YouTube trailer for a title, or ""
+// when it has none the prequeue manager can download.
+func (s *Service) trailerPolicyVideoURL(ctx context.Context, title models.Title) string {
+	resp, err := s.Trailers(ctx, models.TrailerQuery{
+		MediaType: title.MediaType,
+		TitleID:   title.ID,
+		Name:      title.Name,
+		Year:      title.Year,
+		IMDBID:    title.IMDBID,
+		TMDBID:    title.TMDBID,
+		TVDBID:    title.TVDBID,
+	})
+	if err != nil || resp == nil || resp.PrimaryTrailer == nil {
+		return ""
+	}
+	videoURL := strings.TrimSpace(resp.PrimaryTrailer.URL)
+	parsed, err := url.Parse(videoURL)
+	if err != nil || extractYouTubeID(parsed) == "" {
+		return ""
+	}
+	return videoURL
+}
+
+// inTrailerPolicyWindow reports whether now falls inside the daily [start, end)
+// window given as "HH:MM". Windows may wrap midnight (e.g. 23:00–06:00). Empty
+// start and end allow any time.
+func inTrailerPolicyWindow(now time.Time, start, end string) (bool, error) {
+	start, end = strings.TrimSpace(start), strings.TrimSpace(end)
+	if start == "" && end == "" {
+		return true, nil
+	}
+	startMin, err := parseTrailerPolicyClock(start)
+	if err != nil {
+		return false, err
+	}
+	endMin, err := parseTrailerPolicyClock(end)
+	if err != nil {
+		return false, err
+	}
+	current := now.Hour()*60 + now.Minute()
+	if startMin == endMin {
+		return true, nil
+	}
+	if startMin < endMin {
+		return current >= startMin && current < endMin, nil
+	}
+	return current >= startMin || current < endMin, nil
+}
+
+func parseTrailerPolicyClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid trailer window time %q (want HH:MM)", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
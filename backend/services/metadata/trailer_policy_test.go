@@ -0,0 +1,77 @@
+package metadata
+
+import (
+	"testing"
+	"time"
+)
+
+func TestInTrailerPolicyWindow(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 5, 1, hour, minute, 0, 0, time.Local)
+	}
+	tests := []struct {
+		name       string
+		now        time.Time
+		start, end string
+		want       bool
+		wantErr    bool
+	}{
+		{name: "no window", now: at(12, 0), want: true},
+		{name: "inside daytime window", now: at(10, 30), start: "09:00", end: "17:00", want: true},
+		{name: "end is exclusive", now: at(17, 0), start: "09:00", end: "17:00", want: false},
+		{name: "before daytime window", now: at(8, 59), start: "09:00", end: "17:00", want: false},
+		{name: "wrapping window late evening", now: at(23, 30), start: "23:00", end: "06:00", want: true},
+		{name: "wrapping window early morning", now: at(5, 59), start: "23:00", end: "06:00", want: true},
+		{name: "outside wrapping window", now: at(12, 0), start: "23:00", end: "06:00", want: false},
+		{name: "equal start and end", now: at(3, 0), start: "02:00", end: "02:00", want: true},
+		{name: "missing end", now: at(3, 0), start: "02:00", wantErr: true},
+		{name: "invalid time", now: at(3, 0), start: "25:00", end: "06:00", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := inTrailerPolicyWindow(tt.now, tt.start, tt.end)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrailerPrequeueCleanupKeepsPinnedItems(t *testing.T) {
+	mgr, err := NewTrailerPrequeueManager(t.TempDir(), t.TempDir())
+	if err != nil {
+		t.Fatalf("NewTrailerPrequeueManager: %v", err)
+	}
+	readyAt := time.Now().Add(-2 * unusedTimeout)
+	mgr.items["pinned"] = &TrailerPrequeueItem{
+		ID: "pinned", Status: TrailerStatusReady, ReadyAt: &readyAt, FileSize: 300,
+		Pinned: true, pinnedUntil: time.Now().Add(time.Hour),
+	}
+	mgr.items["expired-pin"] = &TrailerPrequeueItem{
+		ID: "expired-pin", Status: TrailerStatusReady, ReadyAt: &readyAt, FileSize: 200,
+		Pinned: true, pinnedUntil: time.Now().Add(-time.Minute),
+	}
+	mgr.items["unpinned"] = &TrailerPrequeueItem{
+		ID: "unpinned", Status: TrailerStatusReady, ReadyAt: &readyAt, FileSize: 100,
+	}
+
+	if got := mgr.DiskUsage(); got != 600 {
+		t.Fatalf("DiskUsage before cleanup = %d, want 600", got)
+	}
+	mgr.cleanup()
+
+	if _, ok := mgr.items["pinned"]; !ok {
+		t.Fatal("pinned trailer was removed before its pin expired")
+	}
+	for _, id := range []string{"expired-pin", "unpinned"} {
+		if _, ok := mgr.items[id]; ok {
+			t.Fatalf("%s trailer should have been removed", id)
+		}
+	}
+	if got := mgr.DiskUsage(); got != 300 {
+		t.Fatalf("DiskUsage after cleanup = %d, want 300", got)
+	}
+}
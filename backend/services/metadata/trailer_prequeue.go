@@ -16,6 +16,7 @@ import (
 	"time"
 
 	"novastream/internal/ytdlp"
+	"novastream/models"
 )
 
 // TrailerStatus represents the current state of a prequeued trailer download
@@ -39,6 +40,16 @@ type TrailerPrequeueItem struct {
 	ReadyAt        *time.Time    `json:"readyAt,omitempty"`
 	LastAccessedAt *time.Time    `json:"-"` // Track when trailer was last served
 	FileSize       int64         `json:"fileSize,omitempty"`
+	// Pinned trailers were downloaded by the prequeue policy and are kept until
+	// pinnedUntil regardless of access, so home-screen trailers start instantly.
+	Pinned      bool      `json:"pinned,omitempty"`
+	pinnedUntil time.Time `json:"-"`
+	// Short muted preview clip cut from the trailer for autoplay on focus.
+	PreviewStatus TrailerStatus `json:"previewStatus,omitempty"`
+	PreviewPath   string        `json:"-"`
+	PreviewSize   int64         `json:"previewSize,omitempty"`
+	PreviewError  string        `json:"previewError,omitempty"`
+	previewTitle  models.Title  // Title the preview was prepared for
 }
 
 // TrailerPrequeueManager manages trailer downloads and temporary file storage
@@ -52,6 +63,7 @@ type TrailerPrequeueManager struct {
 	cleanupActive bool          // Whether cleanup goroutine is running
 	proxyMu       sync.RWMutex
 	ytdlpProxyURL string
+	spaceCheck    func() error // guarded by mu; an error fails downloads up front
 }
 
 // NewTrailerPrequeueManager creates a new prequeue manager
@@ -79,6 +91,13 @@ func (m *TrailerPrequeueManager) SetYTDLPProxyURL(proxyURL string) {
 	m.proxyMu.Unlock()
 }
 
+// SetDiskSpaceCheck sets a function that is consulted before each download.
+func (m *TrailerPrequeueManager) SetDiskSpaceCheck(fn func() error) {
+	m.mu.Lock()
+	m.spaceCheck = fn
+	m.mu.Unlock()
+}
+
 func (m *TrailerPrequeueManager) getYTDLPProxyURL() string {
 	m.proxyMu.RLock()
 	defer m.proxyMu.RUnlock()
@@ -133,6 +152,68 @@ func (m *TrailerPrequeueManager) Prequeue(videoURL string) string {
 	return id
 }
 
+// PrequeuePinned downloads a trailer and keeps it until the given time. Unlike
+// Prequeue it blocks until the download finishes, so callers can bound how many
+// downloads run at once. Already queued or ready trailers are pinned in place
+// without downloading again.
+func (m *TrailerPrequeueManager) PrequeuePinned(videoURL string, until time.Time) (string, TrailerStatus) {
+	id := m.generateID(videoURL)
+
+	m.mu.Lock()
+	if existing, ok := m.items[id]; ok && existing.Status != TrailerStatusFailed {
+		existing.Pinned = true
+		if until.After(existing.pinnedUntil) {
+			existing.pinnedUntil = until
+		}
+		status := existing.Status
+		m.mu.Unlock()
+		return id, status
+	}
+
+	m.items[id] = &TrailerPrequeueItem{
+		ID:          id,
+		VideoURL:    videoURL,
+		Status:      TrailerStatusPending,
+		CreatedAt:   time.Now(),
+		Pinned:      true,
+		pinnedUntil: until,
+	}
+	if !m.cleanupActive {
+		m.cleanupActive = true
+		m.cleanupC = make(chan struct{})
+		go m.cleanupLoop()
+		log.Printf("[trailer-prequeue] started cleanup goroutine")
+	}
+	m.mu.Unlock()
+
+	log.Printf("[trailer-prequeue] policy download: %s for %s", id, videoURL)
+	m.downloadTrailer(id, videoURL)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if item, ok := m.items[id]; ok {
+		return id, item.Status
+	}
+	return id, TrailerStatusFailed
+}
+
+// DiskUsage returns the total size in bytes of downloaded trailers and their
+// preview clips.
+func (m *TrailerPrequeueManager) DiskUsage() int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var total int64
+	for _, item := range m.items {
+		if item.Status == TrailerStatusReady {
+			total += item.FileSize
+		}
+		if item.PreviewStatus == TrailerStatusReady {
+			total += item.PreviewSize
+		}
+	}
+	return total
+}
+
 // GetStatus returns the current status of a prequeued trailer
 func (m *TrailerPrequeueManager) GetStatus(id string) (*TrailerPrequeueItem, bool) {
 	m.mu.RLock()
@@ -197,8 +278,21 @@ func (m *TrailerPrequeueManager) downloadTrailer(id, videoURL string) {
 		return
 	}
 	item.Status = TrailerStatusDownloading
+	spaceCheck := m.spaceCheck
 	m.mu.Unlock()
 
+	if spaceCheck != nil {
+		if err := spaceCheck(); err != nil {
+			m.setFailed(id, err.Error())
+			return
+		}
+	}
+
+	if err := offlineError("youtube"); err != nil {
+		m.setFailed(id, err.Error())
+		return
+	}
+
 	log.Printf("[trailer-prequeue] starting download: %s", id)
 
 	// Find yt-dlp
@@ -422,6 +516,7 @@ func (m *TrailerPrequeueManager) cleanupLoop() {
 }
 
 // cleanup removes trailer files based on access patterns:
+// - Pinned ready trailers are kept until their pin expires
 // - Ready trailers not accessed within 1 minute are deleted
 // - Accessed trailers are deleted 2 minutes after last access
 // - Failed/pending trailers older than maxAge are deleted
@@ -439,6 +534,9 @@ func (m *TrailerPrequeueManager) cleanup() bool {
 
 		switch item.Status {
 		case TrailerStatusReady:
+			if item.Pinned && now.Before(item.pinnedUntil) {
+				continue
+			}
 			if item.LastAccessedAt != nil {
 				// Was accessed - delete after postAccessTimeout
 				if now.Sub(*item.LastAccessedAt) > postAccessTimeout {
@@ -468,10 +566,13 @@ func (m *TrailerPrequeueManager) cleanup() bool {
 
 	for _, id := range toDelete {
 		item := m.items[id]
-		// Delete file if it exists
-		if item.FilePath != "" {
-			if err := os.Remove(item.FilePath); err != nil && !os.IsNotExist(err) {
-				log.Printf("[trailer-prequeue] failed to delete file %s: %v", item.FilePath, err)
+		// Delete files if they exist
+		for _, path := range []string{item.FilePath, item.PreviewPath} {
+			if path == "" {
+				continue
+			}
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				log.Printf("[trailer-prequeue] failed to delete file %s: %v", path, err)
 			}
 		}
 		delete(m.items, id)
@@ -0,0 +1,216 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"novastream/models"
+)
+
+// Preview clips are the opening seconds of a downloaded trailer, muted and
+// scaled down so clients can autoplay them when a hero or trending title is
+// focused.
+const (
+	previewClipSeconds = 30
+	previewClipHeight  = 360
+	previewClipCRF     = 28
+)
+
+// TrailerPreview is a ready preview clip and the title it belongs to. Clients
+// fetch the clip with the trailer preview serve endpoint using ID.
+type TrailerPreview struct {
+	ID        string `json:"id"`
+	TitleID   string `json:"titleId"`
+	MediaType string `json:"mediaType"`
+	Name      string `json:"name"`
+	TMDBID    int64  `json:"tmdbId,omitempty"`
+	TVDBID    int64  `json:"tvdbId,omitempty"`
+	IMDBID    string `json:"imdbId,omitempty"`
+	FileSize  int64  `json:"fileSize"`
+}
+
+// PreparePreview cuts the preview clip of a ready trailer for title, blocking
+// until ffmpeg finishes. A clip that is already ready or being cut is left
+// alone.
+func (m *TrailerPrequeueManager) PreparePreview(id string, title models.Title) TrailerStatus {
+	m.mu.Lock()
+	item, ok := m.items[id]
+	if !ok || item.Status != TrailerStatusReady {
+		m.mu.Unlock()
+		return TrailerStatusFailed
+	}
+	item.previewTitle = title
+	if item.PreviewStatus == TrailerStatusReady || item.PreviewStatus == TrailerStatusDownloading {
+		status := item.PreviewStatus
+		m.mu.Unlock()
+		return status
+	}
+	item.PreviewStatus = TrailerStatusDownloading
+	item.PreviewError = ""
+	source := item.FilePath
+	m.mu.Unlock()
+
+	previewPath := strings.TrimSuffix(source, filepath.Ext(source)) + ".preview.mp4"
+	err := cutPreviewClip(source, previewPath)
+	var size int64
+	if err == nil {
+		stat, statErr := os.Stat(previewPath)
+		if statErr != nil {
+			err = fmt.Errorf("preview clip not written: %w", statErr)
+		} else {
+			size = stat.Size()
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	item, ok = m.items[id]
+	if !ok {
+		// The trailer was cleaned up while the clip was being cut.
+		_ = os.Remove(previewPath)
+		return TrailerStatusFailed
+	}
+	if err != nil {
+		log.Printf("[trailer-prequeue] preview clip failed for %s: %v", id, err)
+		item.PreviewStatus = TrailerStatusFailed
+		item.PreviewError = err.Error()
+		return TrailerStatusFailed
+	}
+	item.PreviewStatus = TrailerStatusReady
+	item.PreviewPath = previewPath
+	item.PreviewSize = size
+	log.Printf("[trailer-prequeue] preview clip ready: %s (size: %d bytes)", id, size)
+	return TrailerStatusReady
+}
+
+// Previews lists the ready preview clips, ordered by title ID.
+func (m *TrailerPrequeueManager) Previews() []TrailerPreview {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var previews []TrailerPreview
+	for id, item := range m.items {
+		if item.PreviewStatus != TrailerStatusReady || item.previewTitle.ID == "" {
+			continue
+		}
+		title := item.previewTitle
+		previews = append(previews, TrailerPreview{
+			ID:        id,
+			TitleID:   title.ID,
+			MediaType: title.MediaType,
+			Name:      title.Name,
+			TMDBID:    title.TMDBID,
+			TVDBID:    title.TVDBID,
+			IMDBID:    title.IMDBID,
+			FileSize:  item.PreviewSize,
+		})
+	}
+	sort.Slice(previews, func(i, j int) bool { return previews[i].TitleID < previews[j].TitleID })
+	return previews
+}
+
+// ServePreview serves the preview clip of a trailer with range request
+// support. Serving counts as an access of the trailer.
+func (m *TrailerPrequeueManager) ServePreview(id string, w http.ResponseWriter, r *http.Request) error {
+	m.mu.Lock()
+	item, ok := m.items[id]
+	var status TrailerStatus
+	var path string
+	if ok {
+		now := time.Now()
+		item.LastAccessedAt = &now
+		status, path = item.PreviewStatus, item.PreviewPath
+	}
+	m.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("trailer not found: %s", id)
+	}
+	if status != TrailerStatusReady {
+		return fmt.Errorf("preview clip not ready (status: %s)", status)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open preview clip: %w", err)
+	}
+	defer file.Close()
+	stat, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat preview clip: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "video/mp4")
+	http.ServeContent(w, r, path, stat.ModTime(), file)
+	return nil
+}
+
+// cutPreviewClip encodes the preview clip of the trailer at src into dst.
+func cutPreviewClip(src, dst string) error {
+	ffmpegPath := "ffmpeg"
+	if _, err := exec.LookPath(ffmpegPath); err != nil {
+		return fmt.Errorf("ffmpeg not found")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, ffmpegPath, previewClipArgs(src, dst)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		_ = os.Remove(dst)
+		errMsg := strings.TrimSpace(stderr.String())
+		if errMsg == "" {
+			errMsg = err.Error()
+		}
+		return fmt.Errorf("ffmpeg preview encode failed: %s", errMsg)
+	}
+	return nil
+}
+
+// previewClipArgs keeps the first previewClipSeconds of video only, scaled
+// down to previewClipHeight (never up) as H.264 that starts playing before
+// it has fully downloaded.
+func previewClipArgs(src, dst string) []string {
+	return []string{
+		"-y",
+		"-i", src,
+		"-t", strconv.Itoa(previewClipSeconds),
+		"-map", "0:v:0",
+		"-an",
+		"-vf", fmt.Sprintf("scale=-2:'min(%d,ih)'", previewClipHeight),
+		"-c:v", "libx264",
+		"-preset", "veryfast",
+		"-crf", strconv.Itoa(previewClipCRF),
+		"-pix_fmt", "yuv420p",
+		"-movflags", "+faststart",
+		dst,
+	}
+}
+
+// TrailerPreviews lists the preview clips prepared for hero and trending
+// titles.
+func (s *Service) TrailerPreviews() []TrailerPreview {
+	if s.trailerPrequeue == nil {
+		return nil
+	}
+	return s.trailerPrequeue.Previews()
+}
+
+// ServeTrailerPreview serves the preview clip of a prequeued trailer.
+func (s *Service) ServeTrailerPreview(id string, w http.ResponseWriter, r *http.Request) error {
+	if s.trailerPrequeue == nil {
+		return fmt.Errorf("trailer prequeue manager not initialized")
+	}
+	return s.trailerPrequeue.ServePreview(id, w, r)
+}
@@ -0,0 +1,83 @@
+package metadata
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"novastream/models"
+)
+
+func TestPreviewClipArgsKeepMutedOpeningSeconds(t *testing.T) {
+	args := strings.Join(previewClipArgs("in.mp4", "out.mp4"), " ")
+	for _, want := range []string{"-t 30", "-an", "scale=-2:'min(360,ih)'", "-c:v libx264", "-movflags +faststart"} {
+		if !strings.Contains(args, want) {
+			t.Errorf("expected %q in args %q", want, args)
+		}
+	}
+	if !strings.HasSuffix(args, " out.mp4") {
+		t.Errorf("expected output path last, got %q", args)
+	}
+}
+
+func TestTrailerPreviewsListsReadyClips(t *testing.T) {
+	dir := t.TempDir()
+	mgr, err := NewTrailerPrequeueManager(dir, dir)
+	if err != nil {
+		t.Fatalf("NewTrailerPrequeueManager: %v", err)
+	}
+	previewPath := filepath.Join(dir, "a.preview.mp4")
+	if err := os.WriteFile(previewPath, []byte("clip"), 0o644); err != nil {
+		t.Fatalf("write clip: %v", err)
+	}
+	mgr.items["a"] = &TrailerPrequeueItem{
+		ID: "a", Status: TrailerStatusReady, FileSize: 100, CreatedAt: time.Now(),
+		PreviewStatus: TrailerStatusReady, PreviewPath: previewPath, PreviewSize: 4,
+		previewTitle: models.Title{ID: "tmdb:movie:1", MediaType: "movie", Name: "Dune", TMDBID: 1},
+	}
+	mgr.items["b"] = &TrailerPrequeueItem{ID: "b", Status: TrailerStatusReady, FileSize: 50, PreviewStatus: TrailerStatusFailed}
+
+	previews := mgr.Previews()
+	if len(previews) != 1 || previews[0].ID != "a" || previews[0].TitleID != "tmdb:movie:1" || previews[0].FileSize != 4 {
+		t.Fatalf("expected the ready clip alone, got %+v", previews)
+	}
+	if usage := mgr.DiskUsage(); usage != 154 {
+		t.Fatalf("expected disk usage to include the preview clip, got %d", usage)
+	}
+
+	rec := httptest.NewRecorder()
+	if err := mgr.ServePreview("a", rec, httptest.NewRequest(http.MethodGet, "/", nil)); err != nil {
+		t.Fatalf("ServePreview: %v", err)
+	}
+	if rec.Body.String() != "clip" || rec.Header().Get("Content-Type") != "video/mp4" {
+		t.Fatalf("unexpected response %q (%s)", rec.Body.String(), rec.Header().Get("Content-Type"))
+	}
+	if mgr.items["a"].LastAccessedAt == nil {
+		t.Fatal("expected serving a preview to count as an access")
+	}
+	if err := mgr.ServePreview("b", httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil)); err == nil {
+		t.Fatal("expected an error for a trailer without a ready clip")
+	}
+}
+
+func TestPreparePreviewRequiresReadyTrailer(t *testing.T) {
+	dir := t.TempDir()
+	mgr, err := NewTrailerPrequeueManager(dir, dir)
+	if err != nil {
+		t.Fatalf("NewTrailerPrequeueManager: %v", err)
+	}
+	mgr.items["pending"] = &TrailerPrequeueItem{ID: "pending", Status: TrailerStatusDownloading}
+	if status := mgr.PreparePreview("pending", models.Title{ID: "x"}); status != TrailerStatusFailed {
+		t.Fatalf("expected a trailer still downloading to be refused, got %s", status)
+	}
+	if status := mgr.PreparePreview("missing", models.Title{ID: "x"}); status != TrailerStatusFailed {
+		t.Fatalf("expected an unknown trailer to be refused, got %s", status)
+	}
+	if mgr.items["pending"].PreviewStatus != "" {
+		t.Fatal("expected no preview state on a refused trailer")
+	}
+}
@@ -0,0 +1,52 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+)
+
+// traktListSource reads public Trakt user lists. It is implemented by the
+// trakt package, which itself depends on this one.
+type traktListSource interface {
+	IsListURL(rawURL string) bool
+	GetListItems(ctx context.Context, rawURL string) ([]CuratedItem, error)
+}
+
+// SetTraktListSource enables public Trakt list URLs as custom list sources
+// alongside MDBList URLs.
+func (s *Service) SetTraktListSource(source traktListSource) {
+	s.trakt = source
+}
+
+// fetchTraktListItems reads a public Trakt list. Trakt reports TMDB, TVDB
+// and IMDb IDs for every entry, so no ID resolution is needed before
+// enrichment.
+func (s *Service) fetchTraktListItems(ctx context.Context, listURL string) ([]mdblistItem, error) {
+	if s.trakt == nil {
+		return nil, fmt.Errorf("trakt lists %w", ErrNotConfigured)
+	}
+	entries, err := s.trakt.GetListItems(ctx, listURL)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]mdblistItem, 0, len(entries))
+	for i, entry := range entries {
+		item := mdblistItem{
+			Rank:        i + 1,
+			Title:       entry.Title,
+			IMDBID:      entry.IMDBID,
+			ReleaseYear: entry.Year,
+			MediaType:   entry.MediaType,
+		}
+		if entry.TMDBID > 0 {
+			tmdbID := entry.TMDBID
+			item.TMDBID = &tmdbID
+		}
+		if entry.TVDBID > 0 {
+			tvdbID := entry.TVDBID
+			item.TVDBID = &tvdbID
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,45 @@
+package metadata
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type fakeTraktListSource struct {
+	items []CuratedItem
+}
+
+func (f *fakeTraktListSource) IsListURL(rawURL string) bool {
+	return strings.Contains(rawURL, "trakt.tv/users/")
+}
+
+func (f *fakeTraktListSource) GetListItems(context.Context, string) ([]CuratedItem, error) {
+	return f.items, nil
+}
+
+func TestFetchCustomListItemsReadsTraktLists(t *testing.T) {
+	svc := &Service{client: &tvdbClient{language: "eng"}}
+	svc.SetTraktListSource(&fakeTraktListSource{items: []CuratedItem{
+		{Title: "Heat", Year: 1995, IMDBID: "tt0113277", TMDBID: 949, MediaType: "movie"},
+		{Title: "The Wire", Year: 2002, IMDBID: "tt0306414", TMDBID: 1438, TVDBID: 79126, MediaType: "show"},
+	}})
+
+	items, err := svc.fetchCustomListItems(context.Background(), "https://trakt.tv/users/someone/lists/crime")
+	if err != nil {
+		t.Fatalf("fetchCustomListItems: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(items))
+	}
+	if items[0].TMDBID == nil || *items[0].TMDBID != 949 || items[0].TVDBID != nil || items[0].Rank != 1 {
+		t.Fatalf("unexpected movie item: %+v", items[0])
+	}
+	if items[1].TVDBID == nil || *items[1].TVDBID != 79126 || mdblistItemMediaType(items[1]) != "series" {
+		t.Fatalf("unexpected series item: %+v", items[1])
+	}
+
+	if clone := svc.WithLanguage("fra"); clone.trakt == nil {
+		t.Fatal("expected language clone to keep the Trakt list source")
+	}
+}
@@ -0,0 +1,192 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"novastream/models"
+)
+
+// TrendingSourceMDBList is the built-in trending source backed by the curated
+// MDBList lists. It is used whenever no other source is requested.
+const TrendingSourceMDBList = "mdblist"
+
+// providerTrendingCacheTTL bounds how long a provider's list is served from cache.
+// Provider charts (e.g. Trakt trending) move faster than the MDBList curated lists.
+const providerTrendingCacheTTL = 6 * time.Hour
+
+// TrendingProvider supplies ranked titles for trending-style shelves. The
+// metadata service runs the returned items through the same enrichment pipeline
+// as MDBList lists (TVDB artwork, TMDB certifications and release data).
+type TrendingProvider interface {
+	// Name identifies the provider in source strings, e.g. "trakt".
+	Name() string
+	// Lists returns the list kinds the provider serves, e.g. "trending", "popular".
+	Lists() []string
+	// FetchTrending returns ranked items for mediaType ("movie" or "series") and list.
+	FetchTrending(ctx context.Context, mediaType, list string) ([]CuratedItem, error)
+}
+
+// trendingProviderRegistry is shared between a Service and its WithLanguage clones.
+type trendingProviderRegistry struct {
+	mu        sync.RWMutex
+	providers map[string]TrendingProvider
+}
+
+func newTrendingProviderRegistry() *trendingProviderRegistry {
+	return &trendingProviderRegistry{providers: make(map[string]TrendingProvider)}
+}
+
+// RegisterTrendingProvider makes a provider selectable as a trending source.
+// Registering a provider with an existing name replaces it.
+func (s *Service) RegisterTrendingProvider(p TrendingProvider) {
+	if p == nil || s.trendingProviders == nil {
+		return
+	}
+	name := strings.ToLower(strings.TrimSpace(p.Name()))
+	if name == "" || name == TrendingSourceMDBList {
+		return
+	}
+	s.trendingProviders.mu.Lock()
+	s.trendingProviders.providers[name] = p
+	s.trendingProviders.mu.Unlock()
+	log.Printf("[metadata] registered trending provider %q (lists=%v)", name, p.Lists())
+}
+
+// TrendingSources lists every selectable trending source as "provider:list",
+// with the built-in MDBList source first.
+func (s *Service) TrendingSources() []string {
+	sources := []string{TrendingSourceMDBList}
+	if s.trendingProviders == nil {
+		return sources
+	}
+	s.trendingProviders.mu.RLock()
+	defer s.trendingProviders.mu.RUnlock()
+	var extra []string
+	for name, p := range s.trendingProviders.providers {
+		for _, list := range p.Lists() {
+			extra = append(extra, name+":"+list)
+		}
+	}
+	sort.Strings(extra)
+	return append(sources, extra...)
+}
+
+// parseTrendingSource splits "provider:list" into its parts. A bare provider
+// name selects that provider's first list.
+func parseTrendingSource(source string) (provider, list string) {
+	source = strings.ToLower(strings.TrimSpace(source))
+	provider, list, _ = strings.Cut(source, ":")
+	return strings.TrimSpace(provider), strings.TrimSpace(list)
+}
+
+// TrendingFromSource returns trending titles from the named source. An empty
+// source or "mdblist" falls through to TrendingWithOptions.
+func (s *Service) TrendingFromSource(ctx context.Context, mediaType, source string, opts ShelfLoadOptions) ([]models.TrendingItem, error) {
+	providerName, list := parseTrendingSource(source)
+	if providerName == "" || providerName == TrendingSourceMDBList {
+		return s.TrendingWithOptions(ctx, mediaType, opts)
+	}
+
+	var provider TrendingProvider
+	if s.trendingProviders != nil {
+		s.trendingProviders.mu.RLock()
+		provider = s.trendingProviders.providers[providerName]
+		s.trendingProviders.mu.RUnlock()
+	}
+	if provider == nil {
+		return nil, newKindError(ErrUnknownTrendingSource, "unknown trending source %q", source)
+	}
+	lists := provider.Lists()
+	if list == "" && len(lists) > 0 {
+		list = lists[0]
+	}
+	supported := false
+	for _, l := range lists {
+		if l == list {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return nil, newKindError(ErrUnknownTrendingSource, "trending source %q does not support list %q", providerName, list)
+	}
+
+	normalized := "series"
+	switch strings.ToLower(strings.TrimSpace(mediaType)) {
+	case "movie", "movies", "film", "films":
+		normalized = "movie"
+	}
+
+	key := cacheKey("trending", providerName, list, normalized, "v1", s.client.language)
+	var cached []models.TrendingItem
+	if ok, _ := s.cache.getWithMaxAge(key, &cached, providerTrendingCacheTTL); ok && len(cached) > 0 {
+		if opts.Lite || opts.ArtworkLimit > 0 {
+			s.enrichShelfArtwork(ctx, cached, shelfLoadArtworkLimit(opts))
+		}
+		return cached, nil
+	}
+
+	curated, err := provider.FetchTrending(ctx, normalized, list)
+	if err != nil {
+		return nil, fmt.Errorf("%s %s %s: %w", providerName, list, normalized, err)
+	}
+	for i := range curated {
+		if curated[i].MediaType == "" {
+			curated[i].MediaType = normalized
+		}
+	}
+
+	label := fmt.Sprintf("%s %s (%s)", providerName, list, normalized)
+	items, err := s.GetCuratedList(ctx, curated, label)
+	if err != nil {
+		return nil, err
+	}
+
+	// Same certification/release enrichment the MDBList trending lists get.
+	// A detached context lets the work finish and populate the cache even if
+	// the client disconnects.
+	enrichCtx := context.Background()
+	if normalized == "movie" {
+		s.enrichTrendingMovieReleases(enrichCtx, items)
+	} else {
+		s.enrichTrendingTVContentRatings(enrichCtx, items)
+	}
+	if len(items) > 0 {
+		_ = s.cache.set(key, items)
+	}
+	return items, nil
+}
+
+// TrendingPage returns one page of trending titles from the given source and
+// the total number of titles in the list, mirroring GetCustomList's
+// offset/limit handling. A limit of 0 returns everything after offset. Pages
+// past the first get their own shelf artwork pass, since TrendingWithOptions
+// only enriches artwork for the head of the list.
+func (s *Service) TrendingPage(ctx context.Context, mediaType, source string, limit, offset int, opts ShelfLoadOptions) ([]models.TrendingItem, int, error) {
+	items, err := s.TrendingFromSource(ctx, mediaType, source, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := len(items)
+	if offset >= total {
+		return []models.TrendingItem{}, total, nil
+	}
+	if offset > 0 {
+		items = items[offset:]
+	}
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	page := make([]models.TrendingItem, len(items))
+	copy(page, items)
+	if offset > 0 {
+		s.enrichShelfArtwork(ctx, page, shelfLoadArtworkLimit(opts))
+	}
+	return page, total, nil
+}
@@ -0,0 +1,50 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type stubTrendingProvider struct{}
+
+func (stubTrendingProvider) Name() string    { return "Stub" }
+func (stubTrendingProvider) Lists() []string { return []string{"trending", "popular"} }
+func (stubTrendingProvider) FetchTrending(context.Context, string, string) ([]CuratedItem, error) {
+	return nil, nil
+}
+
+func TestParseTrendingSource(t *testing.T) {
+	tests := []struct {
+		in, provider, list string
+	}{
+		{"", "", ""},
+		{"mdblist", "mdblist", ""},
+		{"Trakt:Popular", "trakt", "popular"},
+		{" trakt ", "trakt", ""},
+	}
+	for _, tt := range tests {
+		provider, list := parseTrendingSource(tt.in)
+		if provider != tt.provider || list != tt.list {
+			t.Errorf("parseTrendingSource(%q) = (%q, %q), want (%q, %q)", tt.in, provider, list, tt.provider, tt.list)
+		}
+	}
+}
+
+func TestTrendingSourcesAndUnknownSource(t *testing.T) {
+	svc := &Service{trendingProviders: newTrendingProviderRegistry()}
+	svc.RegisterTrendingProvider(stubTrendingProvider{})
+
+	want := []string{"mdblist", "stub:popular", "stub:trending"}
+	if got := svc.TrendingSources(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("TrendingSources() = %v, want %v", got, want)
+	}
+
+	if _, err := svc.TrendingFromSource(context.Background(), "movie", "other:trending", ShelfLoadOptions{}); !errors.Is(err, ErrUnknownTrendingSource) {
+		t.Fatalf("expected ErrUnknownTrendingSource for unregistered provider, got %v", err)
+	}
+	if _, err := svc.TrendingFromSource(context.Background(), "movie", "stub:anticipated", ShelfLoadOptions{}); !errors.Is(err, ErrUnknownTrendingSource) {
+		t.Fatalf("expected ErrUnknownTrendingSource for unsupported list, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package metadata
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"novastream/models"
+)
+
+// trendingSnapshots keeps the last good enriched copy of each trending list
+// so it can be served while a replacement is built, after the on-disk copy
+// has expired or while a refresh is running. Shared across language clones.
+type trendingSnapshots struct {
+	mu       sync.Mutex
+	lists    map[string][]models.TrendingItem
+	building map[string]bool
+}
+
+func newTrendingSnapshots() *trendingSnapshots {
+	return &trendingSnapshots{
+		lists:    make(map[string][]models.TrendingItem),
+		building: make(map[string]bool),
+	}
+}
+
+// get returns a copy of the last good list for key.
+func (t *trendingSnapshots) get(key string) ([]models.TrendingItem, bool) {
+	if t == nil {
+		return nil, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	items, ok := t.lists[key]
+	if !ok {
+		return nil, false
+	}
+	return copyTrendingItems(items), true
+}
+
+// put records items as the last good list for key. Empty lists are ignored
+// so a failed fetch never replaces a good one.
+func (t *trendingSnapshots) put(key string, items []models.TrendingItem, replace bool) {
+	if t == nil || len(items) == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.lists[key]; ok && !replace {
+		return
+	}
+	t.lists[key] = copyTrendingItems(items)
+}
+
+// begin claims the right to rebuild key; false means a rebuild is already
+// running.
+func (t *trendingSnapshots) begin(key string) bool {
+	if t == nil {
+		return true
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.building[key] {
+		return false
+	}
+	t.building[key] = true
+	return true
+}
+
+func (t *trendingSnapshots) done(key string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.building, key)
+}
+
+// storeTrending swaps in a fully built trending list. The file cache write is
+// an atomic rename, so readers see either the old list or the new one.
+func (s *Service) storeTrending(key string, items []models.TrendingItem) {
+	if len(items) == 0 {
+		return
+	}
+	_ = s.cache.set(key, items)
+	s.trendingSnapshots.put(key, items, true)
+}
+
+// revalidateTrending rebuilds the list cached under key and swaps it in once
+// it is complete. Until then, and if the build fails or comes back empty,
+// readers keep getting the previous list. Only one rebuild per key runs at a
+// time; a concurrent call returns immediately.
+func (s *Service) revalidateTrending(key string, build func() ([]models.TrendingItem, error)) error {
+	if !s.trendingSnapshots.begin(key) {
+		return nil
+	}
+	defer s.trendingSnapshots.done(key)
+
+	items, err := build()
+	if err != nil {
+		log.Printf("[metadata] trending refresh failed for %s, keeping previous list: %v", key, err)
+		return err
+	}
+	if len(items) == 0 {
+		log.Printf("[metadata] trending refresh returned no items for %s, keeping previous list", key)
+		return nil
+	}
+	s.storeTrending(key, items)
+	return nil
+}
+
+// refreshTrendingLists rebuilds the full trending lists, and the fast ones
+// that have been served, behind the cached copies.
+func (s *Service) refreshTrendingLists(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, mediaType := range []string{"movie", "tv"} {
+		for _, opts := range []ShelfLoadOptions{{}, {Lite: true}} {
+			key := s.trendingKey(mediaType, opts)
+			if opts.Lite {
+				if _, ok := s.trendingSnapshots.get(key); !ok {
+					continue
+				}
+			}
+			wg.Add(1)
+			go func(mediaType string, opts ShelfLoadOptions) {
+				defer wg.Done()
+				_ = s.revalidateTrending(key, func() ([]models.TrendingItem, error) {
+					return s.buildTrending(ctx, mediaType, opts)
+				})
+			}(mediaType, opts)
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,103 @@
+package metadata
+
+import (
+	"errors"
+	"testing"
+
+	"novastream/models"
+)
+
+func trendingList(names ...string) []models.TrendingItem {
+	items := make([]models.TrendingItem, len(names))
+	for i, name := range names {
+		items[i] = models.TrendingItem{Rank: i + 1, Title: models.Title{Name: name}}
+	}
+	return items
+}
+
+func cachedTrendingNames(t *testing.T, svc *Service, key string) []string {
+	t.Helper()
+	var items []models.TrendingItem
+	if ok, err := svc.cache.get(key, &items); !ok || err != nil {
+		t.Fatalf("expected %s to be cached (err=%v)", key, err)
+	}
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Title.Name
+	}
+	return names
+}
+
+func TestRevalidateTrendingKeepsPreviousListUntilSwap(t *testing.T) {
+	svc := &Service{
+		client:            &tvdbClient{language: "eng"},
+		cache:             newFileCache(t.TempDir(), 24),
+		trendingSnapshots: newTrendingSnapshots(),
+	}
+	key := svc.trendingKey("movie", ShelfLoadOptions{})
+	svc.storeTrending(key, trendingList("Old A", "Old B"))
+
+	if err := svc.revalidateTrending(key, func() ([]models.TrendingItem, error) {
+		return nil, errors.New("mdblist unavailable")
+	}); err == nil {
+		t.Fatal("expected the build error to be returned")
+	}
+	_ = svc.revalidateTrending(key, func() ([]models.TrendingItem, error) { return nil, nil })
+	if got := cachedTrendingNames(t, svc, key); len(got) != 2 || got[0] != "Old A" {
+		t.Fatalf("failed or empty rebuilds replaced the list: %v", got)
+	}
+
+	// While a rebuild is in flight, readers still see the old list and a
+	// second rebuild of the same key is skipped.
+	started := make(chan struct{})
+	release := make(chan struct{})
+	finished := make(chan error)
+	go func() {
+		finished <- svc.revalidateTrending(key, func() ([]models.TrendingItem, error) {
+			close(started)
+			<-release
+			return trendingList("New A"), nil
+		})
+	}()
+	<-started
+	if got := cachedTrendingNames(t, svc, key); got[0] != "Old A" {
+		t.Fatalf("list changed before the rebuild finished: %v", got)
+	}
+	if stale, ok := svc.trendingSnapshots.get(key); !ok || stale[0].Title.Name != "Old A" {
+		t.Fatalf("expected the old snapshot mid-rebuild, got %v", stale)
+	}
+	if err := svc.revalidateTrending(key, func() ([]models.TrendingItem, error) {
+		t.Error("concurrent rebuild should have been skipped")
+		return nil, nil
+	}); err != nil {
+		t.Fatalf("skipped rebuild returned %v", err)
+	}
+	close(release)
+	if err := <-finished; err != nil {
+		t.Fatalf("revalidateTrending: %v", err)
+	}
+
+	if got := cachedTrendingNames(t, svc, key); len(got) != 1 || got[0] != "New A" {
+		t.Fatalf("expected the rebuilt list to be swapped in, got %v", got)
+	}
+	if stale, ok := svc.trendingSnapshots.get(key); !ok || stale[0].Title.Name != "New A" {
+		t.Fatalf("expected the snapshot to follow the swap, got %v", stale)
+	}
+}
+
+func TestTrendingSnapshotsCopyOnReadAndWrite(t *testing.T) {
+	snapshots := newTrendingSnapshots()
+	items := trendingList("A")
+	snapshots.put("k", items, false)
+	items[0].Title.Name = "mutated"
+	snapshots.put("k", trendingList("B"), false)
+
+	got, _ := snapshots.get("k")
+	if got[0].Title.Name != "A" {
+		t.Fatalf("expected the first snapshot to be kept and isolated, got %q", got[0].Title.Name)
+	}
+	got[0].Title.Name = "mutated"
+	if again, _ := snapshots.get("k"); again[0].Title.Name != "A" {
+		t.Fatal("callers must not be able to modify the stored snapshot")
+	}
+}
@@ -26,9 +26,7 @@ type tvdbClient struct {
 	token       string
 	tokenExpiry time.Time
 
-	throttleMu  sync.Mutex
-	lastRequest time.Time
-	minInterval time.Duration
+	limiter *rateLimiter // shared per-provider limiter; nil disables limiting
 
 	episodeTranslationCache sync.Map
 	translationCacheTTL     time.Duration
@@ -53,7 +51,7 @@ func newTVDBClient(apiKey, language string, httpc *http.Client, cacheTTLHours in
 		apiKey:              apiKey,
 		language:            language,
 		httpc:               httpc,
-		minInterval:         10 * time.Millisecond,
+		limiter:             tvdbLimiter,
 		translationCacheTTL: time.Duration(cacheTTLHours) * time.Hour,
 	}
 }
@@ -132,13 +130,13 @@ func (c *tvdbClient) ensureToken() (string, error) {
 	buf, _ := json.Marshal(body)
 	req, _ := http.NewRequest(http.MethodPost, "https://api4.thetvdb.com/v4/login", bytes.NewReader(buf))
 	req.Header.Set("Content-Type", "application/json")
-	resp, err := c.httpc.Do(req)
+	resp, err := c.limiter.do(c.httpc, req)
 	if err != nil {
 		return "", err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode >= 300 {
-		return "", fmt.Errorf("tvdb login failed: %s", resp.Status)
+		return "", upstreamError(resp.StatusCode, "tvdb login failed: %s", resp.Status)
 	}
 	var data struct {
 		Data struct {
@@ -161,6 +159,9 @@ func (c *tvdbClient) doGET(u string, q url.Values, v any) error {
 			u = u + "?" + q.Encode()
 		}
 	}
+	if err := offlineError("tvdb"); err != nil {
+		return err
+	}
 	var lastErr error
 	backoff := 300 * time.Millisecond
 	for attempt := 0; attempt < 3; attempt++ {
@@ -171,27 +172,13 @@ func (c *tvdbClient) doGET(u string, q url.Values, v any) error {
 			backoff *= 2
 			continue
 		}
-		// Rate limiting — compute wait outside the lock to avoid blocking other goroutines
-		c.throttleMu.Lock()
-		wait := c.minInterval - time.Since(c.lastRequest)
-		if wait > 0 {
-			c.lastRequest = time.Now().Add(wait)
-		} else {
-			c.lastRequest = time.Now()
-			wait = 0
-		}
-		c.throttleMu.Unlock()
-		if wait > 0 {
-			time.Sleep(wait)
-		}
-
 		req, _ := http.NewRequest(http.MethodGet, u, nil)
 		req.Header.Set("Authorization", "Bearer "+token)
 		if c.language != "" {
 			req.Header.Set("Accept-Language", c.language)
 		}
 		metadataTracef("[tvdb] GET %s acceptLanguage=%q", u, req.Header.Get("Accept-Language"))
-		resp, err := c.httpc.Do(req)
+		resp, err := c.limiter.do(c.httpc, req)
 		if err != nil {
 			lastErr = err
 			time.Sleep(backoff)
@@ -201,6 +188,10 @@ func (c *tvdbClient) doGET(u string, q url.Values, v any) error {
 		defer resp.Body.Close()
 		if resp.StatusCode >= 300 {
 			if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+				body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+				// Close before backing off so the limiter slot is free for the retry.
+				resp.Body.Close()
+				lastErr = upstreamError(resp.StatusCode, "tvdb get %s failed: %s: %s", u, resp.Status, strings.TrimSpace(string(body)))
 				if ra := resp.Header.Get("Retry-After"); ra != "" {
 					if secs, err := strconv.Atoi(ra); err == nil {
 						time.Sleep(time.Duration(secs) * time.Second)
@@ -209,12 +200,10 @@ func (c *tvdbClient) doGET(u string, q url.Values, v any) error {
 					time.Sleep(backoff)
 					backoff *= 2
 				}
-				body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
-				lastErr = fmt.Errorf("tvdb get %s failed: %s: %s", u, resp.Status, strings.TrimSpace(string(body)))
 				continue
 			}
 			body, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
-			return fmt.Errorf("tvdb get %s failed: %s: %s", u, resp.Status, strings.TrimSpace(string(body)))
+			return upstreamError(resp.StatusCode, "tvdb get %s failed: %s: %s", u, resp.Status, strings.TrimSpace(string(body)))
 		}
 		return json.NewDecoder(resp.Body).Decode(v)
 	}
@@ -361,6 +350,7 @@ type tvdbArtwork struct {
 	Type      tvdbArtworkType `json:"type"`
 	Width     int             `json:"width"`
 	Height    int             `json:"height"`
+	Score     float64         `json:"score"`
 }
 
 func (c *tvdbClient) movieArtworks(id int64) ([]tvdbArtwork, error) {
@@ -529,6 +519,35 @@ type tvdbSeriesTranslation struct {
 	IsPrimary bool   `json:"isPrimary"`
 }
 
+// tvdbExtendedTranslations lists a record's name and overview translations
+// in every language, as returned by extended requests with meta=translations.
+type tvdbExtendedTranslations struct {
+	NameTranslations     []tvdbSeriesTranslation `json:"nameTranslations"`
+	OverviewTranslations []tvdbSeriesTranslation `json:"overviewTranslations"`
+}
+
+// forLanguage returns the name and overview translations in lang. Both are
+// empty when the record has no translation in lang.
+func (t *tvdbExtendedTranslations) forLanguage(lang string) tvdbSeriesTranslation {
+	tr := tvdbSeriesTranslation{Language: lang}
+	if t == nil {
+		return tr
+	}
+	for _, name := range t.NameTranslations {
+		if name.Language == lang {
+			tr.Name, tr.IsPrimary = name.Name, name.IsPrimary
+			break
+		}
+	}
+	for _, overview := range t.OverviewTranslations {
+		if overview.Language == lang {
+			tr.Overview = overview.Overview
+			break
+		}
+	}
+	return tr
+}
+
 type tvdbOriginalNetwork struct {
 	Name    string `json:"name"`
 	Country string `json:"country"`
@@ -582,6 +601,8 @@ type tvdbSeriesExtendedData struct {
 	Type            string    `json:"type"`
 	Tags            []tvdbTag `json:"tags"`
 	OriginalCountry string    `json:"originalCountry"`
+	// Translations is only returned for requests with meta=translations.
+	Translations *tvdbExtendedTranslations `json:"translations,omitempty"`
 }
 
 type tvdbMovieExtendedData struct {
@@ -599,6 +620,8 @@ type tvdbMovieExtendedData struct {
 	} `json:"remoteIds"`
 	Tags            []tvdbTag `json:"tags"`
 	OriginalCountry string    `json:"originalCountry"`
+	// Translations is only returned for requests with meta=translations.
+	Translations *tvdbExtendedTranslations `json:"translations,omitempty"`
 }
 
 // tvdbTag represents a tag from TVDB's extended data.
@@ -663,6 +686,9 @@ func (c *tvdbClient) fetchMDBListTVShows() ([]mdblistTVShow, error) {
 // fetchMDBListJSON fetches and decodes JSON from an MDBList URL with a 15-second
 // timeout and one retry on server errors (500+/524 Cloudflare timeouts).
 func (c *tvdbClient) fetchMDBListJSON(url string, dest any) error {
+	if err := offlineError("mdblist"); err != nil {
+		return err
+	}
 	backoff := 500 * time.Millisecond
 	var lastErr error
 
@@ -689,7 +715,7 @@ func (c *tvdbClient) fetchMDBListJSON(url string, dest any) error {
 		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
 			resp.Body.Close()
 			cancel()
-			lastErr = fmt.Errorf("mdblist request failed: %s", resp.Status)
+			lastErr = upstreamError(resp.StatusCode, "mdblist request failed: %s", resp.Status)
 			log.Printf("[mdblist] server error (attempt %d/2) url=%s: %s", attempt+1, url, resp.Status)
 			continue
 		}
@@ -697,7 +723,7 @@ func (c *tvdbClient) fetchMDBListJSON(url string, dest any) error {
 		if resp.StatusCode >= 300 {
 			resp.Body.Close()
 			cancel()
-			return fmt.Errorf("mdblist request failed: %s", resp.Status)
+			return upstreamError(resp.StatusCode, "mdblist request failed: %s", resp.Status)
 		}
 
 		err = json.NewDecoder(resp.Body).Decode(dest)
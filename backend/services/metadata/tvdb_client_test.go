@@ -40,7 +40,7 @@ func TestTVDBClientSetsAcceptLanguageHeader(t *testing.T) {
 	}
 
 	client := newTVDBClient("apikey", "en", httpc, 24)
-	client.minInterval = 0
+	client.limiter = nil
 
 	var dest map[string]any
 	if err := client.doGET("https://api4.thetvdb.com/v4/test", nil, &dest); err != nil {
@@ -82,7 +82,7 @@ func TestTVDBClientEpisodeTranslationCaching(t *testing.T) {
 	}
 
 	client := newTVDBClient("apikey", "en", httpc, 24)
-	client.minInterval = 0
+	client.limiter = nil
 
 	translation, err := client.episodeTranslation(123, "eng")
 	if err != nil {
@@ -145,7 +145,7 @@ func TestTVDBClientSeriesEpisodesBySeasonType(t *testing.T) {
 	}
 
 	client := newTVDBClient("apikey", "en", httpc, 24)
-	client.minInterval = 0
+	client.limiter = nil
 
 	episodes, err := client.seriesEpisodesBySeasonType(42, "official", "en")
 	if err != nil {
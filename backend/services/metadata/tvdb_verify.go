@@ -0,0 +1,53 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ReidentifySeries checks that a stored TVDB series ID still resolves. When
+// TVDB answers 404 (the series was merged into another or deleted), it maps
+// the title back to TVDB through its TMDB ID, or the TMDB ID found for its
+// IMDB ID, and returns the replacement. gone reports that the old ID is dead;
+// newID is 0 when no replacement could be found. Titles are never matched by
+// name, so a dead ID without IMDB or TMDB IDs stays unresolved.
+func (s *Service) ReidentifySeries(ctx context.Context, tvdbID int64, imdbID string, tmdbID int64) (newID int64, gone bool, err error) {
+	if s.client == nil {
+		return 0, false, fmt.Errorf("tvdb client %w", ErrNotConfigured)
+	}
+	if tvdbID <= 0 {
+		return 0, false, fmt.Errorf("invalid tvdb id %d", tvdbID)
+	}
+
+	series, err := s.getTVDBSeriesDetails(tvdbID)
+	switch {
+	case err == nil && series.ID > 0 && series.ID != tvdbID:
+		// TVDB served the series it was merged into.
+		return series.ID, true, nil
+	case err == nil:
+		return 0, false, nil
+	case !errors.Is(err, ErrNotFound):
+		return 0, false, err
+	}
+
+	if s.tmdb == nil || !s.tmdb.isConfigured() {
+		return 0, true, nil
+	}
+	if tmdbID <= 0 && imdbID != "" {
+		if tmdbID, err = s.tmdb.findTVByIMDBID(ctx, imdbID); err != nil {
+			return 0, true, fmt.Errorf("find tmdb id for %s: %w", imdbID, err)
+		}
+	}
+	if tmdbID <= 0 {
+		return 0, true, nil
+	}
+	title, err := s.tmdb.seriesDetails(ctx, tmdbID)
+	if err != nil {
+		return 0, true, fmt.Errorf("tmdb series %d: %w", tmdbID, err)
+	}
+	if title == nil || title.TVDBID <= 0 || title.TVDBID == tvdbID {
+		return 0, true, nil
+	}
+	return title.TVDBID, true, nil
+}
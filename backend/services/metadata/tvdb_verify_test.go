@@ -0,0 +1,56 @@
+package metadata
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestReidentifySeriesResolvesDeadIDThroughTMDB(t *testing.T) {
+	httpc := &http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			status, body := http.StatusOK, `{}`
+			switch req.URL.Path {
+			case "/v4/login":
+				body = `{"data":{"token":"abc"}}`
+			case "/v4/series/111":
+				status = http.StatusNotFound
+			case "/v4/series/333":
+				body = `{"data":{"id":333,"name":"Still Here"}}`
+			case "/3/find/tt0000111":
+				body = `{"tv_results":[{"id":500}]}`
+			case "/3/tv/500":
+				body = `{"id":500,"name":"Example Show","external_ids":{"imdb_id":"tt0000111","tvdb_id":222}}`
+			default:
+				status = http.StatusNotFound
+			}
+			return &http.Response{StatusCode: status, Body: io.NopCloser(bytes.NewBufferString(body)), Header: make(http.Header)}, nil
+		}),
+	}
+	svc := &Service{
+		client: newTVDBClient("apikey", "en", httpc, 24),
+		tmdb:   newTMDBClient("tmdb-key", "en", httpc, newFileCache(t.TempDir(), 24)),
+	}
+	svc.client.limiter = nil
+	svc.tmdb.limiter = nil
+
+	newID, gone, err := svc.ReidentifySeries(context.Background(), 333, "tt0000333", 0)
+	if err != nil || gone || newID != 0 {
+		t.Fatalf("live id: got newID=%d gone=%v err=%v, want 0 false nil", newID, gone, err)
+	}
+
+	newID, gone, err = svc.ReidentifySeries(context.Background(), 111, "tt0000111", 0)
+	if err != nil {
+		t.Fatalf("ReidentifySeries() error = %v", err)
+	}
+	if !gone || newID != 222 {
+		t.Fatalf("dead id: got newID=%d gone=%v, want 222 true", newID, gone)
+	}
+
+	newID, gone, err = svc.ReidentifySeries(context.Background(), 111, "", 0)
+	if err != nil || !gone || newID != 0 {
+		t.Fatalf("dead id without mappings: got newID=%d gone=%v err=%v, want 0 true nil", newID, gone, err)
+	}
+}
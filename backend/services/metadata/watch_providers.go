@@ -0,0 +1,247 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"novastream/models"
+)
+
+// watchProvidersCacheTTL keeps provider availability fresh enough for
+// "now streaming on" notifications; catalogs shift more often than artwork.
+const watchProvidersCacheTTL = 12 * time.Hour
+
+// watchProviderTypeOrder ranks availability types so subscription services
+// are listed before free, ad-supported, rental, and purchase options.
+var watchProviderTypeOrder = []string{"flatrate", "free", "ads", "rent", "buy"}
+
+type tmdbWatchProviderItem struct {
+	ProviderID      int    `json:"provider_id"`
+	ProviderName    string `json:"provider_name"`
+	LogoPath        string `json:"logo_path"`
+	DisplayPriority int    `json:"display_priority"`
+}
+
+type tmdbWatchProviderRegion struct {
+	Link     string                  `json:"link"`
+	Flatrate []tmdbWatchProviderItem `json:"flatrate"`
+	Free     []tmdbWatchProviderItem `json:"free"`
+	Ads      []tmdbWatchProviderItem `json:"ads"`
+	Rent     []tmdbWatchProviderItem `json:"rent"`
+	Buy      []tmdbWatchProviderItem `json:"buy"`
+}
+
+type tmdbWatchProvidersResponse struct {
+	Results map[string]tmdbWatchProviderRegion `json:"results"`
+}
+
+// fetchWatchProviders retrieves watch providers for a series season, or for the
+// whole series when seasonNumber <= 0. Returns nil when the region has no data.
+func (c *tmdbClient) fetchWatchProviders(ctx context.Context, tmdbID int64, seasonNumber int, region string) (*models.WatchProviderAvailability, error) {
+	if tmdbID <= 0 {
+		return nil, errors.New("tmdb id required")
+	}
+	parts := []string{"tv", fmt.Sprintf("%d", tmdbID)}
+	if seasonNumber > 0 {
+		parts = append(parts, "season", fmt.Sprintf("%d", seasonNumber))
+	}
+	results, err := c.fetchWatchProviderRegions(ctx, parts...)
+	if err != nil {
+		return nil, fmt.Errorf("tmdb watch providers for tv/%d season %d failed: %w", tmdbID, seasonNumber, err)
+	}
+
+	regional, ok := results[region]
+	if !ok {
+		return nil, nil
+	}
+	return buildWatchProviderAvailability(region, regional), nil
+}
+
+// fetchWatchProviderRegions retrieves every region's watch providers for the
+// TMDB resource at path (e.g. "movie", "603").
+func (c *tmdbClient) fetchWatchProviderRegions(ctx context.Context, path ...string) (map[string]tmdbWatchProviderRegion, error) {
+	if !c.isConfigured() {
+		return nil, errTMDBNotConfigured
+	}
+	endpoint, err := url.JoinPath(tmdbBaseURL, append(path, "watch", "providers")...)
+	if err != nil {
+		return nil, err
+	}
+	endpoint = endpoint + "?api_key=" + c.apiKey
+
+	var payload tmdbWatchProvidersResponse
+	if err := c.doGET(ctx, endpoint, &payload); err != nil {
+		return nil, err
+	}
+	if payload.Results == nil {
+		payload.Results = map[string]tmdbWatchProviderRegion{}
+	}
+	return payload.Results, nil
+}
+
+// buildWatchProviderAvailability flattens TMDB's per-type lists into a single
+// ordered list, keeping the first (best) type for providers listed more than once.
+func buildWatchProviderAvailability(region string, regional tmdbWatchProviderRegion) *models.WatchProviderAvailability {
+	byType := map[string][]tmdbWatchProviderItem{
+		"flatrate": regional.Flatrate,
+		"free":     regional.Free,
+		"ads":      regional.Ads,
+		"rent":     regional.Rent,
+		"buy":      regional.Buy,
+	}
+	availability := &models.WatchProviderAvailability{
+		Region:    region,
+		Link:      regional.Link,
+		Providers: []models.WatchProvider{},
+	}
+	seen := make(map[int]bool)
+	for _, providerType := range watchProviderTypeOrder {
+		for _, item := range sortWatchProviderItems(byType[providerType]) {
+			if item.ProviderID == 0 || seen[item.ProviderID] {
+				continue
+			}
+			seen[item.ProviderID] = true
+			provider := models.WatchProvider{
+				ID:   item.ProviderID,
+				Name: item.ProviderName,
+				Type: providerType,
+			}
+			if item.LogoPath != "" {
+				provider.LogoURL = fmt.Sprintf("%s/%s%s", tmdbImageBaseURL, "w92", item.LogoPath)
+			}
+			availability.Providers = append(availability.Providers, provider)
+		}
+	}
+	return availability
+}
+
+func sortWatchProviderItems(items []tmdbWatchProviderItem) []tmdbWatchProviderItem {
+	sorted := append([]tmdbWatchProviderItem(nil), items...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].DisplayPriority < sorted[j].DisplayPriority
+	})
+	return sorted
+}
+
+// normalizeWatchProviderRegion returns an ISO 3166-1 alpha-2 region code, defaulting to US.
+func normalizeWatchProviderRegion(region string) string {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if len(region) != 2 {
+		return "US"
+	}
+	return region
+}
+
+// EpisodeWatchProviders returns where an episode of the given season can be
+// streamed in region. TMDB tracks availability per season, so the season is
+// checked first with the series-level list as a fallback. Results are cached
+// for watchProvidersCacheTTL, including empty results.
+func (s *Service) EpisodeWatchProviders(ctx context.Context, tmdbID int64, seasonNumber int, region string) (*models.WatchProviderAvailability, error) {
+	if s.tmdb == nil || !s.tmdb.isConfigured() {
+		return nil, errTMDBNotConfigured
+	}
+	if tmdbID <= 0 {
+		return nil, errors.New("tmdb id required")
+	}
+	region = normalizeWatchProviderRegion(region)
+
+	key := cacheKey("tmdb", "watchproviders", "v1", region, fmt.Sprintf("%d", tmdbID), fmt.Sprintf("%d", seasonNumber))
+	var cached models.WatchProviderAvailability
+	if ok, _ := s.cache.getWithMaxAge(key, &cached, watchProvidersCacheTTL); ok {
+		if len(cached.Providers) == 0 {
+			return nil, nil
+		}
+		return &cached, nil
+	}
+
+	value, err := s.singleflightCachedFetch(ctx, key, func() (any, error) {
+		availability, err := s.tmdb.fetchWatchProviders(ctx, tmdbID, seasonNumber, region)
+		if err != nil {
+			return nil, err
+		}
+		if (availability == nil || len(availability.Providers) == 0) && seasonNumber > 0 {
+			availability, err = s.tmdb.fetchWatchProviders(ctx, tmdbID, 0, region)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if availability == nil {
+			availability = &models.WatchProviderAvailability{Region: region, Providers: []models.WatchProvider{}}
+		}
+		_ = s.cache.set(key, availability)
+		return availability, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	availability, _ := value.(*models.WatchProviderAvailability)
+	if availability == nil || len(availability.Providers) == 0 {
+		return nil, nil
+	}
+	return availability, nil
+}
+
+// TitleWatchProviders returns where a movie or series can be streamed in
+// region. TMDB reports every region at once, so all of them are cached
+// together for the standard metadata TTL and switching countries is free.
+func (s *Service) TitleWatchProviders(ctx context.Context, mediaType string, tmdbID int64, region string) (*models.WatchProviderAvailability, error) {
+	if s.tmdb == nil || !s.tmdb.isConfigured() {
+		return nil, errTMDBNotConfigured
+	}
+	if tmdbID <= 0 {
+		return nil, errors.New("tmdb id required")
+	}
+	kind := "tv"
+	if strings.EqualFold(mediaType, "movie") {
+		kind = "movie"
+	}
+	region = normalizeWatchProviderRegion(region)
+
+	key := cacheKey("tmdb", kind, "watchproviders", "v1", fmt.Sprintf("%d", tmdbID))
+	var results map[string]tmdbWatchProviderRegion
+	if ok, _ := s.cache.get(key, &results); !ok {
+		value, err := s.singleflightCachedFetch(ctx, key, func() (any, error) {
+			fetched, err := s.tmdb.fetchWatchProviderRegions(ctx, kind, fmt.Sprintf("%d", tmdbID))
+			if err != nil {
+				return nil, fmt.Errorf("tmdb watch providers for %s/%d failed: %w", kind, tmdbID, err)
+			}
+			_ = s.cache.set(key, fetched)
+			return fetched, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		results, _ = value.(map[string]tmdbWatchProviderRegion)
+	}
+
+	regional, ok := results[region]
+	if !ok {
+		return nil, nil
+	}
+	availability := buildWatchProviderAvailability(region, regional)
+	if len(availability.Providers) == 0 {
+		return nil, nil
+	}
+	return availability, nil
+}
+
+// applyWatchProviders attaches the title's streaming services in this
+// service's watch-provider country. Lookup failures leave the title alone;
+// providers are a nice-to-have on details pages.
+func (s *Service) applyWatchProviders(ctx context.Context, title *models.Title) {
+	if title == nil || title.TMDBID <= 0 || s.tmdb == nil || !s.tmdb.isConfigured() {
+		return
+	}
+	availability, err := s.TitleWatchProviders(ctx, title.MediaType, title.TMDBID, s.watchProviderCountry())
+	if err != nil {
+		log.Printf("[metadata] watch providers for %s tmdb:%d: %v", title.MediaType, title.TMDBID, err)
+		return
+	}
+	title.WatchProviders = availability
+}
@@ -0,0 +1,105 @@
+package metadata
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"novastream/models"
+)
+
+func TestBuildWatchProviderAvailability(t *testing.T) {
+	regional := tmdbWatchProviderRegion{
+		Link: "https://www.themoviedb.org/tv/1399/watch?locale=US",
+		Flatrate: []tmdbWatchProviderItem{
+			{ProviderID: 384, ProviderName: "Max", LogoPath: "/max.jpg", DisplayPriority: 5},
+			{ProviderID: 15, ProviderName: "Hulu", LogoPath: "/hulu.jpg", DisplayPriority: 1},
+		},
+		Buy: []tmdbWatchProviderItem{
+			{ProviderID: 2, ProviderName: "Apple TV", DisplayPriority: 3},
+			{ProviderID: 15, ProviderName: "Hulu", DisplayPriority: 1},
+		},
+	}
+
+	got := buildWatchProviderAvailability("US", regional)
+	if got.Region != "US" || got.Link != regional.Link {
+		t.Fatalf("unexpected region/link: %+v", got)
+	}
+	want := []struct {
+		name, kind string
+	}{{"Hulu", "flatrate"}, {"Max", "flatrate"}, {"Apple TV", "buy"}}
+	if len(got.Providers) != len(want) {
+		t.Fatalf("expected %d providers, got %+v", len(want), got.Providers)
+	}
+	for i, w := range want {
+		if got.Providers[i].Name != w.name || got.Providers[i].Type != w.kind {
+			t.Fatalf("provider %d = %+v, want %s/%s", i, got.Providers[i], w.name, w.kind)
+		}
+	}
+	if got.Providers[0].LogoURL != "https://image.tmdb.org/t/p/w92/hulu.jpg" {
+		t.Fatalf("unexpected logo url %q", got.Providers[0].LogoURL)
+	}
+}
+
+func TestEpisodeWatchProviders_CachesRegionalResult(t *testing.T) {
+	rt := &countingRoundTripper{body: `{"id":1399,"results":{"US":{"link":"https://example.test","flatrate":[{"provider_id":15,"provider_name":"Hulu","display_priority":1}]}}}`}
+	cache := newFileCache(t.TempDir(), 24)
+	svc := &Service{
+		tmdb:  newTMDBClient("test-key", "en", &http.Client{Transport: rt}, cache),
+		cache: cache,
+	}
+
+	for i := 0; i < 2; i++ {
+		got, err := svc.EpisodeWatchProviders(context.Background(), 1399, 2, "us")
+		if err != nil {
+			t.Fatalf("EpisodeWatchProviders: %v", err)
+		}
+		if got == nil || len(got.Providers) != 1 || got.Providers[0].Name != "Hulu" {
+			t.Fatalf("expected Hulu, got %+v", got)
+		}
+	}
+	if rt.callCount() != 1 {
+		t.Fatalf("expected a single TMDB request, got %d", rt.callCount())
+	}
+
+	got, err := svc.EpisodeWatchProviders(context.Background(), 1399, 2, "GB")
+	if err != nil {
+		t.Fatalf("EpisodeWatchProviders GB: %v", err)
+	}
+	if got != nil {
+		t.Fatalf("expected no providers for GB, got %+v", got)
+	}
+}
+
+func TestTitleWatchProviders_CachesAllRegions(t *testing.T) {
+	rt := &countingRoundTripper{body: `{"id":603,"results":{"US":{"flatrate":[{"provider_id":8,"provider_name":"Netflix","display_priority":1}]},"GB":{"rent":[{"provider_id":2,"provider_name":"Apple TV","display_priority":1}]}}}`}
+	cache := newFileCache(t.TempDir(), 24)
+	svc := &Service{
+		tmdb:  newTMDBClient("test-key", "en", &http.Client{Transport: rt}, cache),
+		cache: cache,
+	}
+
+	title := &models.Title{MediaType: "movie", TMDBID: 603}
+	svc.applyWatchProviders(context.Background(), title)
+	if title.WatchProviders == nil || title.WatchProviders.Region != "US" || title.WatchProviders.Providers[0].Name != "Netflix" {
+		t.Fatalf("expected US Netflix availability, got %+v", title.WatchProviders)
+	}
+
+	// The watch-provider country follows the certification country unless set.
+	svc.SetCertificationCountry("GB")
+	title = &models.Title{MediaType: "movie", TMDBID: 603}
+	svc.applyWatchProviders(context.Background(), title)
+	if title.WatchProviders == nil || title.WatchProviders.Region != "GB" || title.WatchProviders.Providers[0].Type != "rent" {
+		t.Fatalf("expected GB rental availability, got %+v", title.WatchProviders)
+	}
+
+	svc.SetWatchProviderCountry("DE")
+	title = &models.Title{MediaType: "movie", TMDBID: 603}
+	svc.applyWatchProviders(context.Background(), title)
+	if title.WatchProviders != nil {
+		t.Fatalf("expected no providers for DE, got %+v", title.WatchProviders)
+	}
+	if rt.callCount() != 1 {
+		t.Fatalf("expected a single TMDB request, got %d", rt.callCount())
+	}
+}
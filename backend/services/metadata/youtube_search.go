@@ -98,6 +98,10 @@ func (s *Service) SearchYouTubeVideos(ctx context.Context, query string, limit i
 		}
 	}
 
+	if err := offlineError("youtube"); err != nil {
+		return nil, err
+	}
+
 	searchCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
 	defer cancel()
 
@@ -0,0 +1,115 @@
+package notifications
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"novastream/utils/locale"
+)
+
+//go:embed templates/*
+var digestTemplates embed.FS
+
+// Digest is the content of a profile's weekly email digest. Times should be
+// in the location the reader expects; relative days are counted from
+// GeneratedAt.
+type Digest struct {
+	ProfileName string
+	Locale      locale.Locale
+	GeneratedAt time.Time
+	Upcoming    []DigestEpisode
+	NewMovies   []DigestMovie
+	Health      DigestHealth
+}
+
+// DigestEpisode is an episode airing in the coming week.
+type DigestEpisode struct {
+	SeriesTitle  string
+	Season       int
+	Episode      int
+	EpisodeTitle string
+	Network      string
+	StreamingOn  []string // streaming services carrying the season
+	AirsAt       time.Time
+}
+
+// DigestMovie is a watchlist movie that became available to stream or buy
+// in the past week.
+type DigestMovie struct {
+	Title       string
+	Year        int
+	ReleaseType string // "digital" or "physical"
+	ReleasedAt  time.Time
+}
+
+// DigestHealth summarizes the server's state.
+type DigestHealth struct {
+	RunningSince    time.Time
+	UsenetProviders int
+	DebridProviders int
+	TaskCount       int
+	FailingTasks    []DigestTaskFailure
+}
+
+// DigestTaskFailure is a scheduled task whose last run failed.
+type DigestTaskFailure struct {
+	Name     string
+	Error    string
+	FailedAt time.Time
+}
+
+// RenderDigest renders the digest as an email to the given recipients.
+func RenderDigest(d Digest, to []string) (Email, error) {
+	l := d.Locale
+	funcs := map[string]any{
+		"t": func(key string, args ...any) string { return translate(l, key, args...) },
+		"number": func(n int) string {
+			return l.FormatNumber(int64(n))
+		},
+		"date":     l.FormatDate,
+		"datetime": l.FormatDateTime,
+		"day": func(t time.Time) string {
+			return l.FormatRelativeDay(t, d.GeneratedAt)
+		},
+		"episode": func(season, episode int) string {
+			return fmt.Sprintf("S%02dE%02d", season, episode)
+		},
+		"streaming": func(services []string) string {
+			return translate(l, "digest.streamingOn", strings.Join(services, ", "))
+		},
+		"release": func(releaseType string) string {
+			if label := translate(l, "digest.release."+releaseType); !strings.HasPrefix(label, "digest.") {
+				return label
+			}
+			return releaseType
+		},
+	}
+
+	textTmpl, err := texttemplate.New("digest.txt.tmpl").Funcs(funcs).ParseFS(digestTemplates, "templates/digest.txt.tmpl")
+	if err != nil {
+		return Email{}, fmt.Errorf("parse digest text template: %w", err)
+	}
+	htmlTmpl, err := htmltemplate.New("digest.html.tmpl").Funcs(funcs).ParseFS(digestTemplates, "templates/digest.html.tmpl")
+	if err != nil {
+		return Email{}, fmt.Errorf("parse digest html template: %w", err)
+	}
+
+	var text, html bytes.Buffer
+	if err := textTmpl.Execute(&text, d); err != nil {
+		return Email{}, fmt.Errorf("render digest text: %w", err)
+	}
+	if err := htmlTmpl.Execute(&html, d); err != nil {
+		return Email{}, fmt.Errorf("render digest html: %w", err)
+	}
+	return Email{
+		To:      to,
+		Subject: translate(l, "digest.subject", l.FormatDate(d.GeneratedAt)),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
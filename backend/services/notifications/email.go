@@ -0,0 +1,273 @@
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"novastream/config"
+)
+
+// ErrSMTPNotConfigured is returned when an email is sent before a mail
+// server has been configured.
+var ErrSMTPNotConfigured = errors.New("smtp server not configured")
+
+// SettingsLoader provides the current settings; *config.Manager satisfies it.
+type SettingsLoader interface {
+	Load() (config.Settings, error)
+}
+
+// Email is a message with a plain text body and an optional HTML
+// alternative.
+type Email struct {
+	To      []string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// mailTransport delivers a composed message through the SMTP server.
+type mailTransport func(ctx context.Context, smtpSettings config.SMTPSettings, from string, to []string, msg []byte) error
+
+// ParseRecipients parses a comma-separated list of email addresses, with or
+// without a "mailto:" prefix.
+func ParseRecipients(list string) ([]string, error) {
+	list = strings.TrimSpace(list)
+	list = strings.TrimPrefix(list, "mailto:")
+	if list == "" {
+		return nil, errors.New("no email recipients")
+	}
+	addresses, err := mail.ParseAddressList(list)
+	if err != nil {
+		return nil, fmt.Errorf("invalid email recipients: %w", err)
+	}
+	recipients := make([]string, 0, len(addresses))
+	for _, addr := range addresses {
+		recipients = append(recipients, addr.Address)
+	}
+	return recipients, nil
+}
+
+// ValidateSMTP checks that the mail server settings are usable.
+func ValidateSMTP(smtpSettings config.SMTPSettings) error {
+	if strings.TrimSpace(smtpSettings.Host) == "" {
+		return ErrSMTPNotConfigured
+	}
+	switch smtpSettings.Security {
+	case "", config.SMTPSecurityStartTLS, config.SMTPSecurityTLS, config.SMTPSecurityNone:
+	default:
+		return fmt.Errorf("unsupported smtp security %q", smtpSettings.Security)
+	}
+	if _, err := mail.ParseAddress(strings.TrimSpace(smtpSettings.From)); err != nil {
+		return fmt.Errorf("invalid smtp sender address: %w", err)
+	}
+	return nil
+}
+
+// SetSettingsSource lets the sender read the mail server settings when an
+// email is sent, so changes apply without a restart.
+func (s *Sender) SetSettingsSource(source SettingsLoader) {
+	s.settings = source
+}
+
+// SetMailTransportForTest replaces SMTP delivery.
+func (s *Sender) SetMailTransportForTest(transport func(ctx context.Context, smtpSettings config.SMTPSettings, from string, to []string, msg []byte) error) {
+	if transport != nil {
+		s.mailTransport = transport
+	}
+}
+
+// SendEmail delivers an email through the configured mail server.
+func (s *Sender) SendEmail(ctx context.Context, email Email) error {
+	if s.settings == nil {
+		return ErrSMTPNotConfigured
+	}
+	settings, err := s.settings.Load()
+	if err != nil {
+		return fmt.Errorf("load smtp settings: %w", err)
+	}
+	smtpSettings := settings.SMTP
+	if err := ValidateSMTP(smtpSettings); err != nil {
+		return err
+	}
+	if len(email.To) == 0 {
+		return errors.New("no email recipients")
+	}
+	from, _ := mail.ParseAddress(strings.TrimSpace(smtpSettings.From))
+	msg, err := composeEmail(from, email, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := s.mailTransport(ctx, smtpSettings, from.Address, email.To, msg); err != nil {
+		return fmt.Errorf("email notification: %w", err)
+	}
+	return nil
+}
+
+// composeEmail renders the RFC 5322 message, as multipart/alternative when
+// the email has an HTML body.
+func composeEmail(from *mail.Address, email Email, date time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	header := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+	header("From", from.String())
+	header("To", strings.Join(email.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", email.Subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("Message-ID", messageID(from.Address))
+	header("MIME-Version", "1.0")
+	header("User-Agent", userAgent)
+
+	if email.HTML == "" {
+		header("Content-Type", `text/plain; charset="utf-8"`)
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(&buf, email.Text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	header("Content-Type", `multipart/alternative; boundary="`+parts.Boundary()+`"`)
+	buf.WriteString("\r\n")
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain", email.Text},
+		{"text/html", email.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType + `; charset="utf-8"`},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeQuotedPrintable(w, part.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	buf.Write(body.Bytes())
+	return buf.Bytes(), nil
+}
+
+func writeQuotedPrintable(w interface{ Write([]byte) (int, error) }, content string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(strings.ReplaceAll(content, "\n", "\r\n"))); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func messageID(from string) string {
+	domain := "mediastorm.local"
+	if _, host, ok := strings.Cut(from, "@"); ok && host != "" {
+		domain = host
+	}
+	var b [12]byte
+	_, _ = rand.Read(b[:])
+	return "<" + hex.EncodeToString(b[:]) + "@" + domain + ">"
+}
+
+// sendSMTP delivers msg over SMTP. STARTTLS is required unless security is
+// "none"; "tls" connects with implicit TLS (usually port 465).
+func sendSMTP(ctx context.Context, smtpSettings config.SMTPSettings, from string, to []string, msg []byte) error {
+	host := strings.TrimSpace(smtpSettings.Host)
+	port := smtpSettings.Port
+	if port <= 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	tlsConfig := &tls.Config{ServerName: host}
+
+	dialer := &net.Dialer{Timeout: 15 * time.Second}
+	var conn net.Conn
+	var err error
+	if smtpSettings.Security == config.SMTPSecurityTLS {
+		conn, err = (&tls.Dialer{NetDialer: dialer, Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("connect to %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if smtpSettings.Security == "" || smtpSettings.Security == config.SMTPSecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("starttls: %w", err)
+		}
+	}
+	if smtpSettings.Username != "" {
+		if ok, _ := client.Extension("AUTH"); ok {
+			auth := smtp.PlainAuth("", smtpSettings.Username, smtpSettings.Password, host)
+			if err := client.Auth(auth); err != nil {
+				return fmt.Errorf("smtp auth: %w", err)
+			}
+		}
+	}
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("recipient %s: %w", rcpt, err)
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// eventEmail is the email sent to an email notification target.
+func eventEmail(target config.TaskNotification, e Event) (Email, error) {
+	to, err := ParseRecipients(target.URL)
+	if err != nil {
+		return Email{}, err
+	}
+	text := e.Summary() + "\n\n" +
+		translate(e.Locale, "field.type") + ": " + e.TaskType + "\n" +
+		translate(e.Locale, "field.items") + ": " + e.Locale.FormatNumber(int64(e.Count)) + "\n"
+	if !e.FinishedAt.IsZero() {
+		text += e.Locale.FormatDateTime(e.FinishedAt) + " UTC\n"
+	}
+	return Email{To: to, Subject: e.Title(), Text: text}, nil
+}
@@ -0,0 +1,171 @@
+package notifications
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"novastream/config"
+	"novastream/utils/locale"
+)
+
+type staticSettings config.Settings
+
+func (s staticSettings) Load() (config.Settings, error) { return config.Settings(s), nil }
+
+type sentMail struct {
+	from string
+	to   []string
+	msg  []byte
+}
+
+func newMailSender(smtpSettings config.SMTPSettings) (*Sender, *[]sentMail) {
+	var sent []sentMail
+	sender := NewSender()
+	sender.SetSettingsSource(staticSettings(config.Settings{SMTP: smtpSettings}))
+	sender.SetMailTransportForTest(func(_ context.Context, _ config.SMTPSettings, from string, to []string, msg []byte) error {
+		sent = append(sent, sentMail{from: from, to: to, msg: msg})
+		return nil
+	})
+	return sender, &sent
+}
+
+func TestParseRecipients(t *testing.T) {
+	got, err := ParseRecipients("mailto:Alice <alice@example.com>, bob@example.com")
+	if err != nil || strings.Join(got, ",") != "alice@example.com,bob@example.com" {
+		t.Fatalf("unexpected recipients %v err=%v", got, err)
+	}
+	for _, bad := range []string{"", "mailto:", "not an address"} {
+		if _, err := ParseRecipients(bad); err == nil {
+			t.Errorf("expected %q to be rejected", bad)
+		}
+	}
+}
+
+func TestSendEmailTarget(t *testing.T) {
+	sender, sent := newMailSender(config.SMTPSettings{Host: "smtp.example.com", From: "mediastorm <media@example.com>"})
+	target := config.TaskNotification{Type: config.TaskNotificationTypeEmail, URL: "mailto:admin@example.com", OnFailure: true}
+
+	if err := sender.Send(context.Background(), target, failedEvent()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(*sent) != 1 || (*sent)[0].from != "media@example.com" || strings.Join((*sent)[0].to, ",") != "admin@example.com" {
+		t.Fatalf("unexpected delivery %+v", *sent)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(string((*sent)[0].msg)))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	if subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject")); subject != `Task "Nightly Trakt" failed` {
+		t.Fatalf("unexpected subject %q", subject)
+	}
+	body, _ := io.ReadAll(msg.Body)
+	if !strings.Contains(string(body), "Error: trakt unavailable") {
+		t.Fatalf("expected the summary in the body, got %q", body)
+	}
+}
+
+func TestSendEmailRequiresSMTP(t *testing.T) {
+	sender, _ := newMailSender(config.SMTPSettings{})
+	err := sender.SendEmail(context.Background(), Email{To: []string{"a@example.com"}, Subject: "x", Text: "y"})
+	if err != ErrSMTPNotConfigured {
+		t.Fatalf("expected ErrSMTPNotConfigured, got %v", err)
+	}
+	if err := ValidateSMTP(config.SMTPSettings{Host: "smtp.example.com", From: "media@example.com", Security: "ssl"}); err == nil {
+		t.Fatal("expected an unknown security mode to be rejected")
+	}
+}
+
+func testDigest(l locale.Locale) Digest {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC) // a Monday
+	return Digest{
+		ProfileName: "Sam",
+		Locale:      l,
+		GeneratedAt: now,
+		Upcoming: []DigestEpisode{
+			{SeriesTitle: "Severance", Season: 2, Episode: 8, EpisodeTitle: "Sweet Vitriol", Network: "Apple TV+", StreamingOn: []string{"Apple TV+", "Hulu"}, AirsAt: now.Add(3 * time.Hour)},
+			{SeriesTitle: "The <Bear>", Season: 4, Episode: 1, AirsAt: now.AddDate(0, 0, 3)},
+		},
+		NewMovies: []DigestMovie{
+			{Title: "Dune: Part Two", Year: 2024, ReleaseType: "digital", ReleasedAt: now.AddDate(0, 0, -1)},
+		},
+		Health: DigestHealth{
+			UsenetProviders: 2,
+			TaskCount:       5,
+			FailingTasks:    []DigestTaskFailure{{Name: "Nightly Trakt", Error: "trakt unavailable", FailedAt: now.Add(-time.Hour)}},
+		},
+	}
+}
+
+func TestRenderDigest(t *testing.T) {
+	email, err := RenderDigest(testDigest(locale.Resolve(locale.Settings{Language: "en"})), []string{"sam@example.com"})
+	if err != nil {
+		t.Fatalf("RenderDigest: %v", err)
+	}
+	if email.Subject != "Your mediastorm week of 03/02/2026" {
+		t.Fatalf("unexpected subject %q", email.Subject)
+	}
+	for _, want := range []string{
+		"Hi Sam,",
+		`- today: Severance S02E08 "Sweet Vitriol" (Apple TV+), now on Apple TV+, Hulu`,
+		"- on Thursday: The <Bear> S04E01\n",
+		"- Dune: Part Two (2024): digital, yesterday",
+		"2 Usenet and 0 debrid providers enabled",
+		"1 of 5 scheduled tasks failed",
+		"- Nightly Trakt (03/02/2026 8:00 AM): trakt unavailable",
+	} {
+		if !strings.Contains(email.Text, want) {
+			t.Errorf("expected %q in text:\n%s", want, email.Text)
+		}
+	}
+	if !strings.Contains(email.HTML, "The &lt;Bear&gt;") || strings.Contains(email.HTML, "<Bear>") {
+		t.Error("expected titles to be escaped in the HTML body")
+	}
+
+	empty := Digest{ProfileName: "Sam", Locale: locale.Resolve(locale.Settings{Language: "fr"}), GeneratedAt: time.Now()}
+	email, err = RenderDigest(empty, nil)
+	if err != nil {
+		t.Fatalf("RenderDigest: %v", err)
+	}
+	for _, want := range []string{"Bonjour Sam", "Aucun épisode", "Aucun film", "Les 0 tâches planifiées ont réussi"} {
+		if !strings.Contains(email.Text, want) {
+			t.Errorf("expected %q in French text:\n%s", want, email.Text)
+		}
+	}
+}
+
+func TestComposeEmailAlternatives(t *testing.T) {
+	from := &mail.Address{Name: "mediastorm", Address: "media@example.com"}
+	raw, err := composeEmail(from, Email{To: []string{"a@example.com"}, Subject: "Résumé", Text: "plain", HTML: "<p>html</p>"}, time.Now())
+	if err != nil {
+		t.Fatalf("composeEmail: %v", err)
+	}
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatalf("parse message: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("unexpected content type %q err=%v", msg.Header.Get("Content-Type"), err)
+	}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	var types []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("next part: %v", err)
+		}
+		types = append(types, strings.Split(part.Header.Get("Content-Type"), ";")[0])
+	}
+	if strings.Join(types, ",") != "text/plain,text/html" {
+		t.Fatalf("unexpected parts %v", types)
+	}
+}
@@ -0,0 +1,289 @@
+package notifications
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"novastream/utils/locale"
+)
+
+// messages is the translation bundle for notification text, keyed by message
+// and then language. English is the fallback for missing translations.
+var messages = map[string]map[string]string{
+	"task.succeeded": {
+		locale.English: "Task %q succeeded",
+		locale.French:  "La tâche « %s » a réussi",
+	},
+	"task.failed": {
+		locale.English: "Task %q failed",
+		locale.French:  "La tâche « %s » a échoué",
+	},
+	"summary.error": {
+		locale.English: "Error: %s",
+		locale.French:  "Erreur : %s",
+	},
+	"summary.dryRun": {
+		locale.English: "Dry run: %s to add, %s to remove",
+		locale.French:  "Simulation : %s à ajouter, %s à supprimer",
+	},
+	"summary.processed": {
+		locale.English: "Processed %s items",
+		locale.French:  "%s éléments traités",
+	},
+	"field.type": {
+		locale.English: "Type",
+		locale.French:  "Type",
+	},
+	"field.items": {
+		locale.English: "Items",
+		locale.French:  "Éléments",
+	},
+	"field.dryRun": {
+		locale.English: "Dry run",
+		locale.French:  "Simulation",
+	},
+	"field.dryRunValue": {
+		locale.English: "%s to add, %s to remove",
+		locale.French:  "%s à ajouter, %s à supprimer",
+	},
+	"test.name": {
+		locale.English: "Test notification",
+		locale.French:  "Notification de test",
+	},
+	"test.message": {
+		locale.English: "Notifications from mediastorm are working",
+		locale.French:  "Les notifications de mediastorm fonctionnent",
+	},
+	"disk.low": {
+		locale.English: "Low disk space on the %s volume",
+		locale.French:  "Espace disque faible sur le volume %s",
+	},
+	"disk.lowMessage": {
+		locale.English: "%s free on %s, below the %s threshold. Prequeue, recordings, trailer downloads and backups are paused until space is freed.",
+		locale.French:  "%s libres sur %s, sous le seuil de %s. La préparation, les enregistrements, les bandes-annonces et les sauvegardes sont suspendus jusqu'à ce que de l'espace soit libéré.",
+	},
+	"disk.recovered": {
+		locale.English: "Disk space recovered on the %s volume",
+		locale.French:  "Espace disque rétabli sur le volume %s",
+	},
+	"disk.recoveredMessage": {
+		locale.English: "%s free on %s. Paused work has resumed.",
+		locale.French:  "%s libres sur %s. Les tâches suspendues ont repris.",
+	},
+	"leaving.headline": {
+		locale.English: "Watchlist titles leaving soon for %s",
+		locale.French:  "Titres de la liste bientôt retirés pour %s",
+	},
+	"leaving.line": {
+		locale.English: "%s leaves %s on %s",
+		locale.French:  "%s quitte %s le %s",
+	},
+	"watchtime.warning": {
+		locale.English: "%s is nearing this week's watch time goal",
+		locale.French:  "%s approche de son objectif de temps de visionnage de la semaine",
+	},
+	"watchtime.reached": {
+		locale.English: "%s reached this week's watch time goal",
+		locale.French:  "%s a atteint son objectif de temps de visionnage de la semaine",
+	},
+	"watchtime.usage": {
+		locale.English: "%s of %s watched this week.",
+		locale.French:  "%s sur %s regardées cette semaine.",
+	},
+	"watchtime.blocked": {
+		locale.English: "Playback is paused until Monday.",
+		locale.French:  "La lecture est suspendue jusqu'à lundi.",
+	},
+	"digest.subject": {
+		locale.English: "Your mediastorm week of %s",
+		locale.French:  "Votre semaine mediastorm du %s",
+	},
+	"digest.greeting": {
+		locale.English: "Hi %s, here is your week on mediastorm.",
+		locale.French:  "Bonjour %s, voici votre semaine sur mediastorm.",
+	},
+	"digest.upcoming": {
+		locale.English: "Upcoming episodes",
+		locale.French:  "Prochains épisodes",
+	},
+	"digest.upcomingEmpty": {
+		locale.English: "No episodes from your watchlist or shows in progress air this week.",
+		locale.French:  "Aucun épisode de votre liste ou de vos séries en cours ne sort cette semaine.",
+	},
+	"digest.streamingOn": {
+		locale.English: "now on %s",
+		locale.French:  "disponible sur %s",
+	},
+	"digest.newMovies": {
+		locale.English: "Newly available from your watchlist",
+		locale.French:  "Nouveautés disponibles de votre liste",
+	},
+	"digest.newMoviesEmpty": {
+		locale.English: "None of the movies on your watchlist came out this week.",
+		locale.French:  "Aucun film de votre liste n'est sorti cette semaine.",
+	},
+	"digest.release.digital": {
+		locale.English: "digital",
+		locale.French:  "numérique",
+	},
+	"digest.release.physical": {
+		locale.English: "disc",
+		locale.French:  "disque",
+	},
+	"digest.health": {
+		locale.English: "Server health",
+		locale.French:  "État du serveur",
+	},
+	"digest.runningSince": {
+		locale.English: "Running since %s",
+		locale.French:  "En service depuis le %s",
+	},
+	"digest.providers": {
+		locale.English: "%s Usenet and %s debrid providers enabled",
+		locale.French:  "%s fournisseurs Usenet et %s fournisseurs debrid actifs",
+	},
+	"digest.tasksOK": {
+		locale.English: "All %s scheduled tasks succeeded on their last run.",
+		locale.French:  "Les %s tâches planifiées ont réussi lors de leur dernière exécution.",
+	},
+	"digest.tasksFailing": {
+		locale.English: "%s of %s scheduled tasks failed on their last run:",
+		locale.French:  "%s tâches planifiées sur %s ont échoué lors de leur dernière exécution :",
+	},
+	"digest.footer": {
+		locale.English: "Sent by mediastorm. Edit or disable this digest under Tools > Scheduled Tasks.",
+		locale.French:  "Envoyé par mediastorm. Modifiez ou désactivez ce résumé dans Outils > Tâches planifiées.",
+	},
+}
+
+// translate formats the message key in the locale's language.
+func translate(l locale.Locale, key string, args ...any) string {
+	variants, ok := messages[key]
+	if !ok {
+		return key
+	}
+	format, ok := variants[l.Language]
+	if !ok {
+		format = variants[locale.English]
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// TestEvent is the event sent when an admin tests a notification target.
+func TestEvent(l locale.Locale, finishedAt time.Time) Event {
+	return Event{
+		TaskID:     "test",
+		TaskName:   translate(l, "test.name"),
+		TaskType:   "test",
+		Success:    true,
+		Message:    translate(l, "test.message"),
+		FinishedAt: finishedAt,
+		Locale:     l,
+	}
+}
+
+// DiskSpaceEventType is the TaskType of disk space events.
+const DiskSpaceEventType = "storage"
+
+// DiskSpaceEvent is the event sent when a monitored volume drops below the
+// free space threshold (low) or climbs back above it.
+func DiskSpaceEvent(l locale.Locale, volume, path string, freeBytes, thresholdBytes uint64, low bool, at time.Time) Event {
+	e := Event{
+		TaskID:     DiskSpaceEventType,
+		TaskName:   volume,
+		TaskType:   DiskSpaceEventType,
+		Success:    !low,
+		FinishedAt: at,
+		Locale:     l,
+	}
+	if low {
+		e.Headline = translate(l, "disk.low", volume)
+		e.Message = translate(l, "disk.lowMessage", formatBytes(l, freeBytes), path, formatBytes(l, thresholdBytes))
+	} else {
+		e.Headline = translate(l, "disk.recovered", volume)
+		e.Message = translate(l, "disk.recoveredMessage", formatBytes(l, freeBytes), path)
+	}
+	return e
+}
+
+// LeavingSoonEventType is the TaskType of leaving soon alerts.
+const LeavingSoonEventType = "leaving_soon"
+
+// LeavingSoonTitle is a watchlist title due to leave a streaming service.
+type LeavingSoonTitle struct {
+	Title    string
+	Service  string
+	LeavesAt time.Time
+}
+
+// LeavingSoonEvent is the alert listing a profile's watchlist titles about to
+// leave its streaming services, one line per title.
+func LeavingSoonEvent(l locale.Locale, profileName string, titles []LeavingSoonTitle, at time.Time) Event {
+	lines := make([]string, 0, len(titles))
+	for _, title := range titles {
+		lines = append(lines, translate(l, "leaving.line", title.Title, title.Service, l.FormatDate(title.LeavesAt)))
+	}
+	return Event{
+		TaskID:     LeavingSoonEventType,
+		TaskName:   profileName,
+		TaskType:   LeavingSoonEventType,
+		Success:    true,
+		Count:      len(titles),
+		Headline:   translate(l, "leaving.headline", profileName),
+		Message:    strings.Join(lines, "\n"),
+		FinishedAt: at,
+		Locale:     l,
+	}
+}
+
+// WatchTimeEventType is the TaskType of watch time warnings.
+const WatchTimeEventType = "watch_time"
+
+// WatchTimeEvent is the warning sent when a profile passes its weekly watch
+// time warning threshold (reached false) or uses up its goal (reached true).
+// blocked adds that playback is paused by a hard limit.
+func WatchTimeEvent(l locale.Locale, profileName string, watchedMinutes, goalMinutes int, reached, blocked bool, at time.Time) Event {
+	headline := translate(l, "watchtime.warning", profileName)
+	if reached {
+		headline = translate(l, "watchtime.reached", profileName)
+	}
+	message := translate(l, "watchtime.usage", formatMinutes(l, watchedMinutes), formatMinutes(l, goalMinutes))
+	if blocked {
+		message += " " + translate(l, "watchtime.blocked")
+	}
+	return Event{
+		TaskID:     WatchTimeEventType,
+		TaskName:   profileName,
+		TaskType:   WatchTimeEventType,
+		Success:    true,
+		Count:      watchedMinutes,
+		Headline:   headline,
+		Message:    message,
+		FinishedAt: at,
+		Locale:     l,
+	}
+}
+
+// formatMinutes renders a duration in minutes as hours and minutes.
+func formatMinutes(l locale.Locale, minutes int) string {
+	if minutes < 60 {
+		return l.FormatNumber(int64(minutes)) + " min"
+	}
+	if minutes%60 == 0 {
+		return l.FormatNumber(int64(minutes/60)) + " h"
+	}
+	return fmt.Sprintf("%s h %02d min", l.FormatNumber(int64(minutes/60)), minutes%60)
+}
+
+// formatBytes renders a size in whole gigabytes, or megabytes below 1 GB.
+func formatBytes(l locale.Locale, n uint64) string {
+	const mb, gb = 1 << 20, 1 << 30
+	if n < gb {
+		return l.FormatNumber(int64(n/mb)) + " MB"
+	}
+	return l.FormatNumber(int64(n/gb)) + " GB"
+}
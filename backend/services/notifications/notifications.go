@@ -0,0 +1,286 @@
+// Package notifications delivers scheduled task outcomes to Discord, Gotify,
+// ntfy, generic webhooks and email, and renders the weekly email digest.
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"novastream/config"
+	"novastream/utils/locale"
+)
+
+const userAgent = "mediastorm/1.0"
+
+// Event describes a finished scheduled task run.
+type Event struct {
+	TaskID     string
+	TaskName   string
+	TaskType   string
+	Success    bool
+	Error      string
+	Count      int
+	DryRun     bool
+	ToAdd      int
+	ToRemove   int
+	Message    string
+	FinishedAt time.Time
+	// Headline replaces the task succeeded/failed title for events that are
+	// not task runs, such as disk space alerts.
+	Headline string
+	// Locale is the language and number format of the rendered text. The
+	// zero value renders English.
+	Locale locale.Locale
+}
+
+// Title is a one-line headline for the event.
+func (e Event) Title() string {
+	if e.Headline != "" {
+		return e.Headline
+	}
+	name := e.TaskName
+	if name == "" {
+		name = e.TaskType
+	}
+	if e.Success {
+		return translate(e.Locale, "task.succeeded", name)
+	}
+	return translate(e.Locale, "task.failed", name)
+}
+
+// Summary describes the run's result in a sentence.
+func (e Event) Summary() string {
+	switch {
+	case e.Headline != "" && e.Message != "":
+		return e.Message
+	case !e.Success:
+		return translate(e.Locale, "summary.error", e.Error)
+	case e.DryRun:
+		return translate(e.Locale, "summary.dryRun", e.Locale.FormatNumber(int64(e.ToAdd)), e.Locale.FormatNumber(int64(e.ToRemove)))
+	case e.Message != "":
+		return e.Message
+	default:
+		return translate(e.Locale, "summary.processed", e.Locale.FormatNumber(int64(e.Count)))
+	}
+}
+
+// Wants reports whether the target is configured for the event's outcome.
+func Wants(target config.TaskNotification, e Event) bool {
+	if e.Success {
+		return target.OnSuccess
+	}
+	return target.OnFailure
+}
+
+// Validate checks that a notification target is usable.
+func Validate(target config.TaskNotification) error {
+	switch target.Type {
+	case config.TaskNotificationTypeDiscord, config.TaskNotificationTypeNtfy, config.TaskNotificationTypeWebhook:
+	case config.TaskNotificationTypeEmail:
+		_, err := ParseRecipients(target.URL)
+		return err
+	case config.TaskNotificationTypeGotify:
+		if strings.TrimSpace(target.Token) == "" {
+			return errors.New("gotify notifications require an application token")
+		}
+	default:
+		return fmt.Errorf("unsupported notification type %q", target.Type)
+	}
+	u, err := url.Parse(strings.TrimSpace(target.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s notification URL must be an http(s) URL", target.Type)
+	}
+	return nil
+}
+
+// Sender posts events to notification services.
+type Sender struct {
+	httpClient    *http.Client
+	settings      SettingsLoader
+	mailTransport mailTransport
+}
+
+// NewSender creates a Sender. Email targets also need SetSettingsSource.
+func NewSender() *Sender {
+	return &Sender{
+		httpClient:    &http.Client{Timeout: 15 * time.Second},
+		mailTransport: sendSMTP,
+	}
+}
+
+// SetHTTPClientForTest overrides the HTTP client.
+func (s *Sender) SetHTTPClientForTest(httpClient *http.Client) {
+	if httpClient != nil {
+		s.httpClient = httpClient
+	}
+}
+
+// Send delivers the event to a single target.
+func (s *Sender) Send(ctx context.Context, target config.TaskNotification, e Event) error {
+	if err := Validate(target); err != nil {
+		return err
+	}
+	if target.Type == config.TaskNotificationTypeEmail {
+		email, err := eventEmail(target, e)
+		if err != nil {
+			return err
+		}
+		return s.SendEmail(ctx, email)
+	}
+	req, err := buildRequest(ctx, target, e)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s notification: %w", target.Type, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s notification: status %d: %s", target.Type, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+func buildRequest(ctx context.Context, target config.TaskNotification, e Event) (*http.Request, error) {
+	endpoint := strings.TrimSpace(target.URL)
+	token := strings.TrimSpace(target.Token)
+
+	switch target.Type {
+	case config.TaskNotificationTypeDiscord:
+		return jsonRequest(ctx, endpoint, discordPayload(e))
+
+	case config.TaskNotificationTypeGotify:
+		priority := 4
+		if !e.Success {
+			priority = 8
+		}
+		req, err := jsonRequest(ctx, strings.TrimRight(endpoint, "/")+"/message", map[string]interface{}{
+			"title":    e.Title(),
+			"message":  e.Summary(),
+			"priority": priority,
+		})
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("X-Gotify-Key", token)
+		return req, nil
+
+	case config.TaskNotificationTypeNtfy:
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(e.Summary()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Title", e.Title())
+		if e.Success {
+			req.Header.Set("Priority", "default")
+			req.Header.Set("Tags", "white_check_mark")
+		} else {
+			req.Header.Set("Priority", "high")
+			req.Header.Set("Tags", "warning")
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return req, nil
+
+	default: // webhook
+		req, err := jsonRequest(ctx, endpoint, webhookPayload(e))
+		if err != nil {
+			return nil, err
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return req, nil
+	}
+}
+
+func jsonRequest(ctx context.Context, endpoint string, payload interface{}) (*http.Request, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+const (
+	discordColorSuccess = 0x2ecc71
+	discordColorFailure = 0xe74c3c
+)
+
+func discordPayload(e Event) map[string]interface{} {
+	color := discordColorSuccess
+	if !e.Success {
+		color = discordColorFailure
+	}
+	fields := []map[string]interface{}{
+		{"name": translate(e.Locale, "field.type"), "value": e.TaskType, "inline": true},
+		{"name": translate(e.Locale, "field.items"), "value": e.Locale.FormatNumber(int64(e.Count)), "inline": true},
+	}
+	if e.DryRun {
+		value := translate(e.Locale, "field.dryRunValue", e.Locale.FormatNumber(int64(e.ToAdd)), e.Locale.FormatNumber(int64(e.ToRemove)))
+		fields = append(fields, map[string]interface{}{"name": translate(e.Locale, "field.dryRun"), "value": value, "inline": true})
+	}
+	return map[string]interface{}{
+		"username": "mediastorm",
+		"embeds": []map[string]interface{}{{
+			"title":       e.Title(),
+			"description": e.Summary(),
+			"color":       color,
+			"fields":      fields,
+			"timestamp":   e.FinishedAt.UTC().Format(time.RFC3339),
+		}},
+	}
+}
+
+// webhookPayload is the JSON body posted to generic webhooks.
+func webhookPayload(e Event) map[string]interface{} {
+	event := "task.succeeded"
+	if !e.Success {
+		event = "task.failed"
+	}
+	if e.TaskType == DiskSpaceEventType {
+		event = "storage.recovered"
+		if !e.Success {
+			event = "storage.low"
+		}
+	}
+	payload := map[string]interface{}{
+		"event": event,
+		"task": map[string]interface{}{
+			"id":   e.TaskID,
+			"name": e.TaskName,
+			"type": e.TaskType,
+		},
+		"result": map[string]interface{}{
+			"count":    e.Count,
+			"dryRun":   e.DryRun,
+			"toAdd":    e.ToAdd,
+			"toRemove": e.ToRemove,
+			"message":  e.Message,
+		},
+		"summary":    e.Summary(),
+		"finishedAt": e.FinishedAt.UTC().Format(time.RFC3339),
+	}
+	if e.Error != "" {
+		payload["error"] = e.Error
+	}
+	return payload
+}
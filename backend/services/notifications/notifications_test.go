@@ -0,0 +1,160 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"novastream/config"
+	"novastream/utils/locale"
+)
+
+type capturedRequest struct {
+	path   string
+	header http.Header
+	body   []byte
+}
+
+func newCaptureServer(t *testing.T, status int) (*httptest.Server, *capturedRequest) {
+	t.Helper()
+	captured := &capturedRequest{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		captured.path = r.URL.Path
+		captured.header = r.Header.Clone()
+		captured.body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, captured
+}
+
+func failedEvent() Event {
+	return Event{
+		TaskID:     "task-1",
+		TaskName:   "Nightly Trakt",
+		TaskType:   "trakt_list_sync",
+		Error:      "trakt unavailable",
+		FinishedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+}
+
+func TestSendDiscordEmbed(t *testing.T) {
+	srv, captured := newCaptureServer(t, http.StatusNoContent)
+	target := config.TaskNotification{Type: config.TaskNotificationTypeDiscord, URL: srv.URL + "/api/webhooks/1/abc"}
+
+	if err := NewSender().Send(context.Background(), target, failedEvent()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	var payload struct {
+		Embeds []struct {
+			Title       string `json:"title"`
+			Description string `json:"description"`
+			Color       int    `json:"color"`
+		} `json:"embeds"`
+	}
+	if err := json.Unmarshal(captured.body, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if len(payload.Embeds) != 1 {
+		t.Fatalf("expected one embed, got %d", len(payload.Embeds))
+	}
+	embed := payload.Embeds[0]
+	if embed.Title != `Task "Nightly Trakt" failed` || embed.Description != "Error: trakt unavailable" || embed.Color != discordColorFailure {
+		t.Fatalf("unexpected embed: %+v", embed)
+	}
+}
+
+func TestSendGotifyUsesMessageEndpointAndToken(t *testing.T) {
+	srv, captured := newCaptureServer(t, http.StatusOK)
+	target := config.TaskNotification{Type: config.TaskNotificationTypeGotify, URL: srv.URL + "/", Token: "app-token"}
+	event := Event{TaskName: "Backup", TaskType: "backup", Success: true, Message: "Backup created"}
+
+	if err := NewSender().Send(context.Background(), target, event); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if captured.path != "/message" {
+		t.Fatalf("expected /message, got %q", captured.path)
+	}
+	if got := captured.header.Get("X-Gotify-Key"); got != "app-token" {
+		t.Fatalf("expected gotify token header, got %q", got)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(captured.body, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload["message"] != "Backup created" || payload["priority"] != float64(4) {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+}
+
+func TestSendNtfyHeaders(t *testing.T) {
+	srv, captured := newCaptureServer(t, http.StatusOK)
+	target := config.TaskNotification{Type: config.TaskNotificationTypeNtfy, URL: srv.URL + "/mediastorm", Token: "tk_secret"}
+
+	if err := NewSender().Send(context.Background(), target, failedEvent()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if captured.path != "/mediastorm" || string(captured.body) != "Error: trakt unavailable" {
+		t.Fatalf("unexpected request: path=%q body=%q", captured.path, captured.body)
+	}
+	if captured.header.Get("Priority") != "high" || captured.header.Get("Authorization") != "Bearer tk_secret" {
+		t.Fatalf("unexpected headers: %v", captured.header)
+	}
+}
+
+func TestSendWebhookReportsErrorStatus(t *testing.T) {
+	srv, captured := newCaptureServer(t, http.StatusBadGateway)
+	target := config.TaskNotification{Type: config.TaskNotificationTypeWebhook, URL: srv.URL}
+
+	err := NewSender().Send(context.Background(), target, failedEvent())
+	if err == nil || !strings.Contains(err.Error(), "status 502") {
+		t.Fatalf("expected status error, got %v", err)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(captured.body, &payload); err != nil {
+		t.Fatalf("decode payload: %v", err)
+	}
+	if payload["event"] != "task.failed" || payload["error"] != "trakt unavailable" {
+		t.Fatalf("unexpected payload: %v", payload)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  config.TaskNotification
+		wantErr bool
+	}{
+		{"discord", config.TaskNotification{Type: config.TaskNotificationTypeDiscord, URL: "https://discord.com/api/webhooks/1/x"}, false},
+		{"gotify without token", config.TaskNotification{Type: config.TaskNotificationTypeGotify, URL: "https://gotify.local"}, true},
+		{"unknown type", config.TaskNotification{Type: "email", URL: "https://example.com"}, true},
+		{"non-http url", config.TaskNotification{Type: config.TaskNotificationTypeWebhook, URL: "ftp://example.com"}, true},
+	}
+	for _, tt := range tests {
+		if err := Validate(tt.target); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEventTextFollowsLocale(t *testing.T) {
+	e := failedEvent()
+	e.Locale = locale.Resolve(locale.Settings{Language: "fr"})
+	if got := e.Title(); got != "La tâche « Nightly Trakt » a échoué" {
+		t.Fatalf("unexpected French title %q", got)
+	}
+
+	e = Event{TaskName: "Sync", Success: true, Count: 12345, Locale: locale.Resolve(locale.Settings{Language: "en"})}
+	if got := e.Summary(); got != "Processed 12,345 items" {
+		t.Fatalf("unexpected English summary %q", got)
+	}
+	e.Locale = locale.Resolve(locale.Settings{Language: "fr"})
+	if got := e.Summary(); got != "12\u202f345 éléments traités" {
+		t.Fatalf("unexpected French summary %q", got)
+	}
+}
@@ -0,0 +1,284 @@
+// Package overseerr talks to an Overseerr or Jellyseerr instance so titles
+// can be requested through an existing request-management stack instead of
+// mediastorm's own arr integration. Both servers expose the same v1 API.
+package overseerr
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"novastream/models"
+)
+
+// availabilityTTL is how long an availability lookup is reused. Title
+// responses merge availability in, so lookups happen on every details view.
+const availabilityTTL = 5 * time.Minute
+
+// ErrNotConfigured is returned when no Overseerr URL or API key is set.
+var ErrNotConfigured = errors.New("overseerr not configured")
+
+// Media status values reported by Overseerr for a title.
+const (
+	mediaStatusUnknown            = 1
+	mediaStatusPending            = 2
+	mediaStatusProcessing         = 3
+	mediaStatusPartiallyAvailable = 4
+	mediaStatusAvailable          = 5
+	mediaStatusBlacklisted        = 6 // Jellyseerr only
+	mediaStatusDeleted            = 7
+)
+
+// Request status values for an individual request.
+const (
+	requestStatusApproved = 2
+	requestStatusDeclined = 3
+)
+
+// Client is an Overseerr/Jellyseerr API client. The URL and key can be
+// changed at runtime when settings are saved.
+type Client struct {
+	mu         sync.RWMutex
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+
+	cacheMu sync.Mutex
+	cache   map[string]cachedAvailability
+}
+
+type cachedAvailability struct {
+	availability models.RequestAvailability
+	expires      time.Time
+}
+
+// NewClient creates a client for the instance at baseURL, e.g.
+// "http://overseerr:5055". An empty URL or key leaves it unconfigured.
+func NewClient(baseURL, apiKey string) *Client {
+	c := &Client{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		cache:      make(map[string]cachedAvailability),
+	}
+	c.Configure(baseURL, apiKey)
+	return c
+}
+
+// Configure updates the instance URL and API key (e.g., when settings
+// change) and drops cached lookups.
+func (c *Client) Configure(baseURL, apiKey string) {
+	c.mu.Lock()
+	c.baseURL = strings.TrimRight(strings.TrimSpace(baseURL), "/")
+	c.apiKey = strings.TrimSpace(apiKey)
+	c.mu.Unlock()
+
+	c.cacheMu.Lock()
+	c.cache = make(map[string]cachedAvailability)
+	c.cacheMu.Unlock()
+}
+
+// SetHTTPClientForTest overrides the HTTP client.
+func (c *Client) SetHTTPClientForTest(httpClient *http.Client) {
+	if httpClient != nil {
+		c.httpClient = httpClient
+	}
+}
+
+// IsConfigured reports whether a URL and API key are set.
+func (c *Client) IsConfigured() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.baseURL != "" && c.apiKey != ""
+}
+
+type mediaInfo struct {
+	Status   int `json:"status"`
+	Requests []struct {
+		ID     int `json:"id"`
+		Status int `json:"status"`
+	} `json:"requests"`
+}
+
+// Availability returns the request state of a title by its TMDB ID.
+// mediaType is "movie" or "series".
+func (c *Client) Availability(ctx context.Context, mediaType string, tmdbID int64) (*models.RequestAvailability, error) {
+	kind, err := apiMediaType(mediaType)
+	if err != nil {
+		return nil, err
+	}
+	if tmdbID <= 0 {
+		return nil, errors.New("tmdb id required")
+	}
+	key := fmt.Sprintf("%s:%d", kind, tmdbID)
+	c.cacheMu.Lock()
+	if cached, ok := c.cache[key]; ok && time.Now().Before(cached.expires) {
+		c.cacheMu.Unlock()
+		availability := cached.availability
+		return &availability, nil
+	}
+	c.cacheMu.Unlock()
+
+	var resp struct {
+		MediaInfo *mediaInfo `json:"mediaInfo"`
+	}
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/%s/%d", kind, tmdbID), nil, &resp); err != nil {
+		return nil, err
+	}
+	availability := availabilityFromMedia(resp.MediaInfo)
+
+	c.cacheMu.Lock()
+	c.cache[key] = cachedAvailability{availability: availability, expires: time.Now().Add(availabilityTTL)}
+	c.cacheMu.Unlock()
+	return &availability, nil
+}
+
+// Request asks Overseerr for a title by its TMDB ID. For series, seasons
+// lists the seasons wanted; empty requests all of them. The request is made
+// as the API key's owner and follows Overseerr's own approval rules.
+func (c *Client) Request(ctx context.Context, mediaType string, tmdbID int64, seasons []int) (*models.RequestAvailability, error) {
+	kind, err := apiMediaType(mediaType)
+	if err != nil {
+		return nil, err
+	}
+	if tmdbID <= 0 {
+		return nil, errors.New("tmdb id required")
+	}
+	body := map[string]any{"mediaType": kind, "mediaId": tmdbID}
+	if kind == "tv" {
+		if len(seasons) > 0 {
+			body["seasons"] = seasons
+		} else {
+			body["seasons"] = "all"
+		}
+	}
+
+	var resp struct {
+		ID     int        `json:"id"`
+		Status int        `json:"status"`
+		Media  *mediaInfo `json:"media"`
+	}
+	if err := c.do(ctx, http.MethodPost, "/api/v1/request", body, &resp); err != nil {
+		return nil, err
+	}
+	availability := availabilityFromMedia(resp.Media)
+	availability.RequestID = resp.ID
+	if availability.Status == models.RequestStatusNotRequested {
+		availability.Status = requestStatus(resp.Status)
+	}
+
+	c.cacheMu.Lock()
+	delete(c.cache, fmt.Sprintf("%s:%d", kind, tmdbID))
+	c.cacheMu.Unlock()
+	return &availability, nil
+}
+
+// availabilityFromMedia maps Overseerr's media info onto a request state.
+// Media Overseerr has never seen has no media info at all.
+func availabilityFromMedia(info *mediaInfo) models.RequestAvailability {
+	availability := models.RequestAvailability{Source: "overseerr", Status: models.RequestStatusNotRequested}
+	if info == nil {
+		return availability
+	}
+	switch info.Status {
+	case mediaStatusAvailable:
+		availability.Status = models.RequestStatusAvailable
+	case mediaStatusPartiallyAvailable:
+		availability.Status = models.RequestStatusPartiallyAvailable
+	case mediaStatusProcessing:
+		availability.Status = models.RequestStatusProcessing
+	case mediaStatusPending:
+		availability.Status = models.RequestStatusPending
+	case mediaStatusBlacklisted:
+		availability.Status = models.RequestStatusDeclined
+	}
+	// Unknown or deleted media may still carry a request of its own.
+	if (info.Status == mediaStatusUnknown || info.Status == mediaStatusDeleted || info.Status == 0) && len(info.Requests) > 0 {
+		latest := info.Requests[len(info.Requests)-1]
+		availability.Status = requestStatus(latest.Status)
+	}
+	if len(info.Requests) > 0 {
+		availability.RequestID = info.Requests[len(info.Requests)-1].ID
+	}
+	return availability
+}
+
+func requestStatus(status int) string {
+	switch status {
+	case requestStatusApproved:
+		return models.RequestStatusProcessing
+	case requestStatusDeclined:
+		return models.RequestStatusDeclined
+	}
+	return models.RequestStatusPending
+}
+
+func apiMediaType(mediaType string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(mediaType)) {
+	case "movie":
+		return "movie", nil
+	case "series", "tv", "show":
+		return "tv", nil
+	}
+	return "", fmt.Errorf("unsupported media type %q", mediaType)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	c.mu.RLock()
+	baseURL, apiKey := c.baseURL, c.apiKey
+	c.mu.RUnlock()
+	if baseURL == "" || apiKey == "" {
+		return ErrNotConfigured
+	}
+
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("X-Api-Key", apiKey)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("overseerr api request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(msg))}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// APIError is a non-2xx response from Overseerr.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("overseerr api returned %d: %s", e.StatusCode, e.Message)
+}
@@ -0,0 +1,74 @@
+package overseerr
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"novastream/models"
+)
+
+func TestClientAvailabilityAndRequest(t *testing.T) {
+	var lookups int
+	var requested map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/movie/603":
+			lookups++
+			w.Write([]byte(`{"id":603,"mediaInfo":{"status":5,"requests":[{"id":3,"status":2}]}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/tv/1396":
+			w.Write([]byte(`{"id":1396}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/request":
+			json.NewDecoder(r.Body).Decode(&requested)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":9,"status":1,"media":{"status":2}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	if NewClient("", "").IsConfigured() {
+		t.Fatalf("expected client without URL to be unconfigured")
+	}
+	client := NewClient(server.URL+"/", "key")
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		got, err := client.Availability(ctx, "movie", 603)
+		if err != nil {
+			t.Fatalf("Availability() error = %v", err)
+		}
+		if got.Status != models.RequestStatusAvailable || got.RequestID != 3 {
+			t.Fatalf("unexpected movie availability %+v", got)
+		}
+	}
+	if lookups != 1 {
+		t.Fatalf("expected the second lookup to be cached, got %d requests", lookups)
+	}
+
+	got, err := client.Availability(ctx, "series", 1396)
+	if err != nil {
+		t.Fatalf("Availability() error = %v", err)
+	}
+	if got.Status != models.RequestStatusNotRequested {
+		t.Fatalf("expected unseen series to be not requested, got %+v", got)
+	}
+
+	got, err = client.Request(ctx, "series", 1396, nil)
+	if err != nil {
+		t.Fatalf("Request() error = %v", err)
+	}
+	if got.Status != models.RequestStatusPending || got.RequestID != 9 {
+		t.Fatalf("unexpected request result %+v", got)
+	}
+	if requested["mediaType"] != "tv" || requested["mediaId"] != float64(1396) || requested["seasons"] != "all" {
+		t.Fatalf("unexpected request body %v", requested)
+	}
+}
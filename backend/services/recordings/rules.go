@@ -0,0 +1,489 @@
+package recordings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"novastream/internal/datastore"
+	"novastream/models"
+)
+
+var (
+	ErrRulesUnavailable   = errors.New("recording rules are not configured")
+	ErrRuleNotFound       = errors.New("recording rule not found")
+	ErrRuleQueryRequired  = errors.New("rule query is required")
+	ErrInvalidRuleMatch   = errors.New("rule match must be \"series\" or \"keyword\"")
+	ErrRuleChannelUnknown = errors.New("rule channel is not in the profile's lineup")
+)
+
+// RuleChannel is a channel a profile can record from.
+type RuleChannel struct {
+	ID        string
+	TvgID     string
+	Name      string
+	SourceURL string
+}
+
+// RuleGuide supplies what rule evaluation needs from Live TV.
+type RuleGuide interface {
+	// Schedule returns the current guide data; it must not be modified.
+	Schedule() *models.EPGSchedule
+	// ProfileChannels returns the channels profileID can record and the
+	// offset its guide times are shifted by.
+	ProfileChannels(ctx context.Context, profileID string) ([]RuleChannel, time.Duration, error)
+	// MaxConcurrentRecordings bounds overlapping recordings (0 = unlimited).
+	MaxConcurrentRecordings() int
+}
+
+// RuleConflict is a matched airing that was not scheduled because the
+// concurrent recording limit was reached.
+type RuleConflict struct {
+	RuleID      string    `json:"ruleId"`
+	UserID      string    `json:"userId"`
+	Title       string    `json:"title"`
+	ChannelName string    `json:"channelName"`
+	StartAt     time.Time `json:"startAt"`
+	EndAt       time.Time `json:"endAt"`
+}
+
+// RuleEvaluation summarizes one pass over the recording rules.
+type RuleEvaluation struct {
+	Rules     int            `json:"rules"`
+	Matched   int            `json:"matched"`
+	Scheduled int            `json:"scheduled"`
+	Conflicts []RuleConflict `json:"conflicts"`
+}
+
+// EnableRules turns on recurring recording rules, stored in repo and
+// evaluated against guide.
+func (s *Service) EnableRules(repo datastore.RecordingRuleRepository, guide RuleGuide) {
+	s.rules = repo
+	s.guide = guide
+}
+
+func (s *Service) ListRules(userID string) ([]models.RecordingRule, error) {
+	if s.rules == nil {
+		return nil, ErrRulesUnavailable
+	}
+	return s.rules.List(context.Background(), userID)
+}
+
+func (s *Service) GetRule(id string) (*models.RecordingRule, error) {
+	if s.rules == nil {
+		return nil, ErrRulesUnavailable
+	}
+	rule, err := s.rules.Get(context.Background(), strings.TrimSpace(id))
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, ErrRuleNotFound
+	}
+	return rule, nil
+}
+
+func (s *Service) CreateRule(req models.RecordingRuleRequest) (models.RecordingRule, error) {
+	if s.rules == nil {
+		return models.RecordingRule{}, ErrRulesUnavailable
+	}
+	userID := strings.TrimSpace(req.ProfileID)
+	if userID == "" {
+		return models.RecordingRule{}, ErrProfileRequired
+	}
+	now := time.Now().UTC()
+	rule := models.RecordingRule{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Match:     models.RecordingRuleMatchSeries,
+		Enabled:   true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := applyRuleRequest(&rule, req); err != nil {
+		return models.RecordingRule{}, err
+	}
+	if err := s.rules.Create(context.Background(), &rule); err != nil {
+		return models.RecordingRule{}, err
+	}
+	s.evaluateRulesSoon("rule created")
+	return rule, nil
+}
+
+func (s *Service) UpdateRule(id string, req models.RecordingRuleRequest) (models.RecordingRule, error) {
+	rule, err := s.GetRule(id)
+	if err != nil {
+		return models.RecordingRule{}, err
+	}
+	if err := applyRuleRequest(rule, req); err != nil {
+		return models.RecordingRule{}, err
+	}
+	rule.UpdatedAt = time.Now().UTC()
+	if err := s.rules.Update(context.Background(), rule); err != nil {
+		return models.RecordingRule{}, err
+	}
+	s.evaluateRulesSoon("rule updated")
+	return *rule, nil
+}
+
+// DeleteRule removes a rule. Recordings it already scheduled are kept.
+func (s *Service) DeleteRule(id string) error {
+	rule, err := s.GetRule(id)
+	if err != nil {
+		return err
+	}
+	return s.rules.Delete(context.Background(), rule.ID)
+}
+
+func applyRuleRequest(rule *models.RecordingRule, req models.RecordingRuleRequest) error {
+	if req.Match != nil {
+		rule.Match = models.RecordingRuleMatch(strings.ToLower(strings.TrimSpace(string(*req.Match))))
+	}
+	if req.Query != nil {
+		rule.Query = strings.TrimSpace(*req.Query)
+	}
+	if req.ChannelID != nil {
+		rule.ChannelID = strings.TrimSpace(*req.ChannelID)
+	}
+	if req.TvgID != nil {
+		rule.TvgID = strings.TrimSpace(*req.TvgID)
+	}
+	if req.ChannelName != nil {
+		rule.ChannelName = strings.TrimSpace(*req.ChannelName)
+	}
+	if rule.ChannelID == "" {
+		rule.TvgID, rule.ChannelName = "", ""
+	}
+	if req.NewOnly != nil {
+		rule.NewOnly = *req.NewOnly
+	}
+	if req.Priority != nil {
+		rule.Priority = *req.Priority
+	}
+	if req.PaddingBeforeSeconds != nil {
+		rule.PaddingBeforeSeconds = max(0, *req.PaddingBeforeSeconds)
+	}
+	if req.PaddingAfterSeconds != nil {
+		rule.PaddingAfterSeconds = max(0, *req.PaddingAfterSeconds)
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+
+	switch rule.Match {
+	case models.RecordingRuleMatchSeries, models.RecordingRuleMatchKeyword:
+	default:
+		return ErrInvalidRuleMatch
+	}
+	if normalizeRuleText(rule.Query) == "" {
+		return ErrRuleQueryRequired
+	}
+	return nil
+}
+
+// evaluateRulesSoon re-evaluates rules after a change, in the background so
+// the request does not wait on playlist fetches.
+func (s *Service) evaluateRulesSoon(reason string) {
+	if s.guide == nil {
+		return
+	}
+	go s.RunRuleEvaluation(reason)
+}
+
+// RunRuleEvaluation evaluates the rules and logs the outcome.
+func (s *Service) RunRuleEvaluation(reason string) {
+	result, err := s.EvaluateRules(context.Background())
+	if err != nil {
+		log.Printf("[recordings] rule evaluation (%s) failed: %v", reason, err)
+		return
+	}
+	if result.Scheduled > 0 || len(result.Conflicts) > 0 {
+		log.Printf("[recordings] rule evaluation (%s): rules=%d matched=%d scheduled=%d conflicts=%d",
+			reason, result.Rules, result.Matched, result.Scheduled, len(result.Conflicts))
+	}
+}
+
+// ruleCandidate is an upcoming airing matched by a rule.
+type ruleCandidate struct {
+	rule    models.RecordingRule
+	channel RuleChannel
+	program models.EPGProgram
+	startAt time.Time // guide start with the profile's offset applied
+	endAt   time.Time
+}
+
+func (c ruleCandidate) window() (time.Time, time.Time) {
+	return c.startAt.Add(-time.Duration(c.rule.PaddingBeforeSeconds) * time.Second),
+		c.endAt.Add(time.Duration(c.rule.PaddingAfterSeconds) * time.Second)
+}
+
+// EvaluateRules schedules every upcoming airing matched by an enabled rule.
+// Airings already scheduled (or cancelled) are left alone. When the
+// concurrent recording limit is reached, higher priority rules win and the
+// airings that do not fit are reported as conflicts.
+func (s *Service) EvaluateRules(ctx context.Context) (RuleEvaluation, error) {
+	result := RuleEvaluation{Conflicts: []RuleConflict{}}
+	if s.rules == nil || s.guide == nil {
+		return result, ErrRulesUnavailable
+	}
+	s.ruleMu.Lock()
+	defer s.ruleMu.Unlock()
+
+	rules, err := s.rules.List(ctx, "")
+	if err != nil {
+		return result, err
+	}
+	byUser := make(map[string][]models.RecordingRule)
+	var users []string
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		result.Rules++
+		if _, ok := byUser[rule.UserID]; !ok {
+			users = append(users, rule.UserID)
+		}
+		byUser[rule.UserID] = append(byUser[rule.UserID], rule)
+	}
+	schedule := s.guide.Schedule()
+	if len(users) == 0 || schedule == nil || len(schedule.Programs) == 0 {
+		return result, nil
+	}
+
+	existing, err := s.repo.List(ctx, models.RecordingListFilter{IncludeAll: true})
+	if err != nil {
+		return result, err
+	}
+	book := newRuleBook(existing)
+
+	now := time.Now().UTC()
+	var candidates []ruleCandidate
+	for _, userID := range users {
+		channels, offset, err := s.guide.ProfileChannels(ctx, userID)
+		if err != nil {
+			log.Printf("[recordings] resolve channels for rules of profile %s failed: %v", userID, err)
+			continue
+		}
+		for _, rule := range byUser[userID] {
+			matched, err := matchRule(rule, schedule, channels, offset, now)
+			if err != nil {
+				log.Printf("[recordings] rule %s (%q): %v", rule.ID, rule.Query, err)
+				continue
+			}
+			candidates = append(candidates, matched...)
+		}
+	}
+	result.Matched = len(candidates)
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.rule.Priority != b.rule.Priority {
+			return a.rule.Priority > b.rule.Priority
+		}
+		if !a.startAt.Equal(b.startAt) {
+			return a.startAt.Before(b.startAt)
+		}
+		return a.rule.CreatedAt.Before(b.rule.CreatedAt)
+	})
+
+	limit := s.guide.MaxConcurrentRecordings()
+	for _, c := range candidates {
+		if book.has(c) {
+			continue
+		}
+		from, to := c.window()
+		if limit > 0 && book.peakOverlap(from, to) >= limit {
+			result.Conflicts = append(result.Conflicts, RuleConflict{
+				RuleID:      c.rule.ID,
+				UserID:      c.rule.UserID,
+				Title:       c.program.Title,
+				ChannelName: c.channel.Name,
+				StartAt:     c.startAt,
+				EndAt:       c.endAt,
+			})
+			continue
+		}
+		recording, err := s.insertRecording(models.Recording{
+			ID:                   uuid.NewString(),
+			UserID:               c.rule.UserID,
+			Type:                 models.RecordingTypeEPG,
+			Status:               models.RecordingStatusPending,
+			ChannelID:            c.channel.ID,
+			TvgID:                c.channel.TvgID,
+			ChannelName:          c.channel.Name,
+			Title:                c.program.Title,
+			Description:          strings.TrimSpace(c.program.Description),
+			SourceURL:            c.channel.SourceURL,
+			StartAt:              c.startAt,
+			EndAt:                c.endAt,
+			PaddingBeforeSeconds: c.rule.PaddingBeforeSeconds,
+			PaddingAfterSeconds:  c.rule.PaddingAfterSeconds,
+			RuleID:               c.rule.ID,
+			Episode:              c.program.Episode,
+			CreatedAt:            now,
+			UpdatedAt:            now,
+		})
+		if err != nil {
+			return result, fmt.Errorf("schedule %q for rule %s: %w", c.program.Title, c.rule.ID, err)
+		}
+		book.add(recording)
+		result.Scheduled++
+	}
+	return result, nil
+}
+
+// matchRule returns the upcoming airings of schedule that rule matches on the
+// profile's channels.
+func matchRule(rule models.RecordingRule, schedule *models.EPGSchedule, channels []RuleChannel, offset time.Duration, now time.Time) ([]ruleCandidate, error) {
+	// Guide channel -> the first playlist channel carrying it.
+	byGuideID := make(map[string]RuleChannel)
+	for _, ch := range channels {
+		key := strings.ToLower(strings.TrimSpace(ch.TvgID))
+		if key == "" || strings.TrimSpace(ch.SourceURL) == "" {
+			continue
+		}
+		if rule.ChannelID != "" && ch.ID != rule.ChannelID {
+			continue
+		}
+		if _, ok := byGuideID[key]; !ok {
+			byGuideID[key] = ch
+		}
+	}
+	if rule.ChannelID != "" && len(byGuideID) == 0 {
+		return nil, ErrRuleChannelUnknown
+	}
+
+	query := normalizeRuleText(rule.Query)
+	var out []ruleCandidate
+	for guideID, ch := range byGuideID {
+		for _, prog := range schedule.Programs[guideID] {
+			startAt := prog.Start.Add(offset).UTC()
+			if !startAt.After(now) || !prog.Stop.After(prog.Start) {
+				continue
+			}
+			if rule.NewOnly && prog.PreviouslyShown {
+				continue
+			}
+			if !ruleMatchesProgram(rule.Match, query, prog) {
+				continue
+			}
+			out = append(out, ruleCandidate{
+				rule:    rule,
+				channel: ch,
+				program: prog,
+				startAt: startAt,
+				endAt:   prog.Stop.Add(offset).UTC(),
+			})
+		}
+	}
+	return out, nil
+}
+
+func ruleMatchesProgram(match models.RecordingRuleMatch, query string, prog models.EPGProgram) bool {
+	title := normalizeRuleText(prog.Title)
+	switch match {
+	case models.RecordingRuleMatchSeries:
+		return title == query
+	case models.RecordingRuleMatchKeyword:
+		needle := " " + query + " "
+		return strings.Contains(" "+title+" ", needle) ||
+			strings.Contains(" "+normalizeRuleText(prog.Description)+" ", needle)
+	}
+	return false
+}
+
+// normalizeRuleText lowercases text and reduces it to space-separated words.
+func normalizeRuleText(text string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// ruleBook tracks recordings during an evaluation: which airings and
+// episodes are taken and which time windows are occupied.
+type ruleBook struct {
+	airings  map[string]bool
+	episodes map[string]bool
+	windows  [][2]time.Time
+}
+
+func newRuleBook(existing []models.Recording) *ruleBook {
+	b := &ruleBook{airings: make(map[string]bool), episodes: make(map[string]bool)}
+	for _, recording := range existing {
+		b.add(recording)
+	}
+	return b
+}
+
+func airingKey(userID, channelID string, startAt time.Time) string {
+	return fmt.Sprintf("%s|%s|%d", userID, channelID, startAt.Unix())
+}
+
+func episodeKey(userID, title, episode string) string {
+	return userID + "|" + normalizeRuleText(title) + "|" + strings.ToLower(strings.TrimSpace(episode))
+}
+
+func (b *ruleBook) add(recording models.Recording) {
+	// Any recording, even a cancelled one, claims its airing so rules do not
+	// reschedule something the user removed.
+	b.airings[airingKey(recording.UserID, recording.ChannelID, recording.StartAt)] = true
+	switch recording.Status {
+	case models.RecordingStatusFailed, models.RecordingStatusCancelled:
+		return
+	}
+	if recording.Episode != "" {
+		b.episodes[episodeKey(recording.UserID, recording.Title, recording.Episode)] = true
+	}
+	if recording.Status != models.RecordingStatusCompleted {
+		b.windows = append(b.windows, [2]time.Time{
+			recording.StartAt.Add(-time.Duration(recording.PaddingBeforeSeconds) * time.Second),
+			recording.EndAt.Add(time.Duration(recording.PaddingAfterSeconds) * time.Second),
+		})
+	}
+}
+
+func (b *ruleBook) has(c ruleCandidate) bool {
+	if b.airings[airingKey(c.rule.UserID, c.channel.ID, c.startAt)] {
+		return true
+	}
+	return c.rule.NewOnly && c.program.Episode != "" &&
+		b.episodes[episodeKey(c.rule.UserID, c.program.Title, c.program.Episode)]
+}
+
+// peakOverlap returns the most recordings active at once within [from, to).
+func (b *ruleBook) peakOverlap(from, to time.Time) int {
+	type edge struct {
+		at    time.Time
+		delta int
+	}
+	var edges []edge
+	for _, w := range b.windows {
+		if !w[0].Before(to) || !w[1].After(from) {
+			continue
+		}
+		edges = append(edges, edge{w[0], 1}, edge{w[1], -1})
+	}
+	// Ends sort before starts at the same instant: back-to-back recordings
+	// do not overlap.
+	sort.Slice(edges, func(i, j int) bool {
+		if !edges[i].at.Equal(edges[j].at) {
+			return edges[i].at.Before(edges[j].at)
+		}
+		return edges[i].delta < edges[j].delta
+	})
+	peak, active := 0, 0
+	for _, e := range edges {
+		active += e.delta
+		if active > peak {
+			peak = active
+		}
+	}
+	return peak
+}
@@ -0,0 +1,217 @@
+package recordings
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"novastream/models"
+)
+
+type fakeRuleRepo struct {
+	mu    sync.Mutex
+	rules map[string]models.RecordingRule
+}
+
+func newFakeRuleRepo(rules ...models.RecordingRule) *fakeRuleRepo {
+	repo := &fakeRuleRepo{rules: make(map[string]models.RecordingRule)}
+	for _, rule := range rules {
+		repo.rules[rule.ID] = rule
+	}
+	return repo
+}
+
+func (r *fakeRuleRepo) Get(_ context.Context, id string) (*models.RecordingRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rule, ok := r.rules[id]
+	if !ok {
+		return nil, nil
+	}
+	return &rule, nil
+}
+
+func (r *fakeRuleRepo) List(_ context.Context, userID string) ([]models.RecordingRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []models.RecordingRule
+	for _, rule := range r.rules {
+		if userID == "" || rule.UserID == userID {
+			out = append(out, rule)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (r *fakeRuleRepo) Create(_ context.Context, rule *models.RecordingRule) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules[rule.ID] = *rule
+	return nil
+}
+
+func (r *fakeRuleRepo) Update(ctx context.Context, rule *models.RecordingRule) error {
+	return r.Create(ctx, rule)
+}
+
+func (r *fakeRuleRepo) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.rules, id)
+	return nil
+}
+
+type fakeRuleGuide struct {
+	schedule *models.EPGSchedule
+	channels []RuleChannel
+	offset   time.Duration
+	limit    int
+}
+
+func (g *fakeRuleGuide) Schedule() *models.EPGSchedule { return g.schedule }
+
+func (g *fakeRuleGuide) ProfileChannels(context.Context, string) ([]RuleChannel, time.Duration, error) {
+	return g.channels, g.offset, nil
+}
+
+func (g *fakeRuleGuide) MaxConcurrentRecordings() int { return g.limit }
+
+func testRule(id string, match models.RecordingRuleMatch, query string) models.RecordingRule {
+	return models.RecordingRule{ID: id, UserID: "default", Match: match, Query: query, Enabled: true}
+}
+
+func recordingsByTitle(t *testing.T, repo *fakeRecordingRepo) map[string][]models.Recording {
+	t.Helper()
+	all, _ := repo.List(context.Background(), models.RecordingListFilter{})
+	out := make(map[string][]models.Recording)
+	for _, recording := range all {
+		out[recording.Title] = append(out[recording.Title], recording)
+	}
+	return out
+}
+
+func TestEvaluateRulesSchedulesMatchesOnce(t *testing.T) {
+	base := time.Now().UTC().Truncate(time.Hour).Add(2 * time.Hour)
+	guide := &fakeRuleGuide{
+		schedule: &models.EPGSchedule{Programs: map[string][]models.EPGProgram{
+			"bbc1.uk": {
+				{ChannelID: "bbc1.uk", Title: "Doctor Who", Episode: "S01E01", Start: base, Stop: base.Add(time.Hour)},
+				{ChannelID: "bbc1.uk", Title: "Doctor Who", Episode: "S01E01", Start: base.Add(24 * time.Hour), Stop: base.Add(25 * time.Hour), PreviouslyShown: true},
+				{ChannelID: "bbc1.uk", Title: "Doctor Who Confidential", Start: base.Add(time.Hour), Stop: base.Add(2 * time.Hour)},
+				{ChannelID: "bbc1.uk", Title: "Doctor Who", Episode: "S01E00", Start: base.Add(-4 * time.Hour), Stop: base.Add(-3 * time.Hour)},
+			},
+			"sky.f1": {
+				{ChannelID: "sky.f1", Title: "F1: Monaco Grand Prix", Start: base.Add(3 * time.Hour), Stop: base.Add(5 * time.Hour)},
+				{ChannelID: "sky.f1", Title: "Grand Prixview", Start: base.Add(6 * time.Hour), Stop: base.Add(7 * time.Hour)},
+			},
+		}},
+		channels: []RuleChannel{
+			{ID: "c1", TvgID: "BBC1.uk", Name: "BBC One", SourceURL: "http://example.invalid/1.ts"},
+			{ID: "c2", TvgID: "sky.f1", Name: "Sky F1", SourceURL: "http://example.invalid/2.ts"},
+		},
+		offset: 30 * time.Minute,
+	}
+	series := testRule("r1", models.RecordingRuleMatchSeries, "doctor who")
+	series.NewOnly = true
+	series.PaddingAfterSeconds = 300
+	repo := newFakeRecordingRepo()
+	svc := newTestService(repo, "", t.TempDir())
+	svc.EnableRules(newFakeRuleRepo(series, testRule("r2", models.RecordingRuleMatchKeyword, "Grand Prix")), guide)
+
+	result, err := svc.EvaluateRules(context.Background())
+	if err != nil {
+		t.Fatalf("EvaluateRules() error = %v", err)
+	}
+	if result.Rules != 2 || result.Scheduled != 2 || len(result.Conflicts) != 0 {
+		t.Fatalf("result = %+v, want 2 rules and 2 scheduled", result)
+	}
+	byTitle := recordingsByTitle(t, repo)
+	who := byTitle["Doctor Who"]
+	if len(who) != 1 {
+		t.Fatalf("Doctor Who recordings = %d, want 1 (rerun and past airing skipped)", len(who))
+	}
+	if got := who[0]; got.RuleID != "r1" || got.ChannelID != "c1" || got.Episode != "S01E01" ||
+		!got.StartAt.Equal(base.Add(30*time.Minute)) || got.PaddingAfterSeconds != 300 {
+		t.Fatalf("unexpected recording %+v", got)
+	}
+	if len(byTitle["F1: Monaco Grand Prix"]) != 1 || len(byTitle["Grand Prixview"]) != 0 {
+		t.Fatalf("keyword rule should match whole words only, got %v", byTitle)
+	}
+
+	// A second pass, e.g. after the next guide refresh, adds nothing new.
+	result, err = svc.EvaluateRules(context.Background())
+	if err != nil || result.Scheduled != 0 {
+		t.Fatalf("second EvaluateRules() = %+v, %v; want nothing scheduled", result, err)
+	}
+}
+
+func TestEvaluateRulesResolvesConflictsByPriority(t *testing.T) {
+	base := time.Now().UTC().Truncate(time.Hour).Add(2 * time.Hour)
+	guide := &fakeRuleGuide{
+		schedule: &models.EPGSchedule{Programs: map[string][]models.EPGProgram{
+			"a": {{ChannelID: "a", Title: "News", Start: base, Stop: base.Add(time.Hour)}},
+			"b": {{ChannelID: "b", Title: "Match of the Day", Start: base.Add(30 * time.Minute), Stop: base.Add(90 * time.Minute)}},
+			"c": {{ChannelID: "c", Title: "News", Start: base.Add(time.Hour), Stop: base.Add(2 * time.Hour)}},
+		}},
+		channels: []RuleChannel{
+			{ID: "a", TvgID: "a", Name: "A", SourceURL: "http://example.invalid/a.ts"},
+			{ID: "b", TvgID: "b", Name: "B", SourceURL: "http://example.invalid/b.ts"},
+			{ID: "c", TvgID: "c", Name: "C", SourceURL: "http://example.invalid/c.ts"},
+		},
+		limit: 1,
+	}
+	news := testRule("news", models.RecordingRuleMatchSeries, "News")
+	football := testRule("football", models.RecordingRuleMatchSeries, "Match of the Day")
+	football.Priority = 10
+	repo := newFakeRecordingRepo()
+	svc := newTestService(repo, "", t.TempDir())
+	svc.EnableRules(newFakeRuleRepo(news, football), guide)
+
+	result, err := svc.EvaluateRules(context.Background())
+	if err != nil {
+		t.Fatalf("EvaluateRules() error = %v", err)
+	}
+	if result.Scheduled != 1 || len(result.Conflicts) != 2 {
+		t.Fatalf("result = %+v, want 1 scheduled and 2 conflicts", result)
+	}
+	if byTitle := recordingsByTitle(t, repo); len(byTitle["Match of the Day"]) != 1 {
+		t.Fatalf("higher priority rule should win, got %v", byTitle)
+	}
+
+	// Back-to-back airings do not conflict.
+	guide.limit = 2
+	result, err = svc.EvaluateRules(context.Background())
+	if err != nil || result.Scheduled != 2 || len(result.Conflicts) != 0 {
+		t.Fatalf("EvaluateRules() with limit 2 = %+v, %v; want both news airings", result, err)
+	}
+}
+
+func TestCreateRuleValidation(t *testing.T) {
+	svc := newTestService(newFakeRecordingRepo(), "", t.TempDir())
+	if _, err := svc.CreateRule(models.RecordingRuleRequest{ProfileID: "default"}); !errors.Is(err, ErrRulesUnavailable) {
+		t.Fatalf("CreateRule() without rules error = %v, want ErrRulesUnavailable", err)
+	}
+	svc.EnableRules(newFakeRuleRepo(), nil)
+
+	query := "  "
+	if _, err := svc.CreateRule(models.RecordingRuleRequest{ProfileID: "default", Query: &query}); !errors.Is(err, ErrRuleQueryRequired) {
+		t.Fatalf("CreateRule() blank query error = %v, want ErrRuleQueryRequired", err)
+	}
+	query = "Grand Prix"
+	match := models.RecordingRuleMatch("regex")
+	if _, err := svc.CreateRule(models.RecordingRuleRequest{ProfileID: "default", Query: &query, Match: &match}); !errors.Is(err, ErrInvalidRuleMatch) {
+		t.Fatalf("CreateRule() bad match error = %v, want ErrInvalidRuleMatch", err)
+	}
+	before := -60
+	rule, err := svc.CreateRule(models.RecordingRuleRequest{ProfileID: "default", Query: &query, PaddingBeforeSeconds: &before})
+	if err != nil {
+		t.Fatalf("CreateRule() error = %v", err)
+	}
+	if rule.Match != models.RecordingRuleMatchSeries || !rule.Enabled || rule.PaddingBeforeSeconds != 0 {
+		t.Fatalf("unexpected rule defaults %+v", rule)
+	}
+}
@@ -46,12 +46,18 @@ type Service struct {
 	ffmpegPath string
 	outputDir  string
 
+	rules  datastore.RecordingRuleRepository
+	guide  RuleGuide
+	ruleMu sync.Mutex // serializes rule evaluation
+
 	mu      sync.Mutex
 	ctx     context.Context
 	cancel  context.CancelFunc
 	running bool
 	wg      sync.WaitGroup
 	active  map[string]context.CancelFunc
+
+	spaceCheck func() error
 }
 
 func NewService(repo datastore.RecordingRepository, ffmpegPath, outputDir string) *Service {
@@ -66,6 +72,21 @@ func NewService(repo datastore.RecordingRepository, ffmpegPath, outputDir string
 	}
 }
 
+// SetDiskSpaceCheck sets a function that is consulted before a recording
+// starts. While it returns an error due recordings stay pending and are
+// retried on the next poll; recordings already running are not stopped.
+func (s *Service) SetDiskSpaceCheck(fn func() error) {
+	s.spaceCheck = fn
+}
+
+// diskSpaceError reports why recordings can't start now, if they can't.
+func (s *Service) diskSpaceError() error {
+	if s.spaceCheck == nil {
+		return nil
+	}
+	return s.spaceCheck()
+}
+
 func (s *Service) Start(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -82,6 +103,13 @@ func (s *Service) Start(ctx context.Context) error {
 	s.running = true
 	s.wg.Add(1)
 	go s.loop()
+	if s.rules != nil && s.guide != nil {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.RunRuleEvaluation("startup")
+		}()
+	}
 	return nil
 }
 
@@ -213,6 +241,12 @@ func (s *Service) createRecording(kind models.RecordingType, userID, channelID,
 	if recording.Title == "" {
 		recording.Title = recording.ChannelName
 	}
+	return s.insertRecording(recording)
+}
+
+// insertRecording stores a validated recording, starting it right away when
+// its window is already open.
+func (s *Service) insertRecording(recording models.Recording) (models.Recording, error) {
 	if err := s.repo.Create(context.Background(), &recording); err != nil {
 		return models.Recording{}, err
 	}
@@ -234,6 +268,10 @@ func (s *Service) maybeStartRecordingImmediately(recording models.Recording) {
 	if startAt.After(time.Now().UTC()) {
 		return
 	}
+	if err := s.diskSpaceError(); err != nil {
+		log.Printf("[recordings] deferring %s: %v", recording.ID, err)
+		return
+	}
 
 	s.wg.Add(1)
 	go func() {
@@ -303,6 +341,12 @@ func (s *Service) runDueRecordings() {
 		log.Printf("[recordings] list due recordings failed: %v", err)
 		return
 	}
+	if len(due) > 0 {
+		if err := s.diskSpaceError(); err != nil {
+			log.Printf("[recordings] deferring %d due recording(s): %v", len(due), err)
+			return
+		}
+	}
 	for _, recording := range due {
 		s.mu.Lock()
 		_, active := s.active[recording.ID]
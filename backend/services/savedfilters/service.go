@@ -0,0 +1,460 @@
+// Package savedfilters manages profiles' named discover filter sets, such as
+// "90s anime movies" or "short comedies". Saved filters are listed as browse
+// entries and can back home shelves.
+package savedfilters
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"novastream/internal/datastore"
+	"novastream/models"
+)
+
+var (
+	ErrStorageDirRequired = errors.New("storage directory not provided")
+	ErrUserIDRequired     = errors.New("user id is required")
+	ErrNameRequired       = errors.New("filter name is required")
+	ErrInvalidFilter      = errors.New("invalid discover filter")
+	ErrFilterNotFound     = errors.New("saved filter not found")
+)
+
+// maxNameLength caps saved filter names, which are shown as shelf titles.
+const maxNameLength = 80
+
+// Sort modes accepted in a discover filter.
+var validSorts = map[string]bool{
+	"":           true,
+	"popularity": true,
+	"rating":     true,
+	"newest":     true,
+	"oldest":     true,
+	"title":      true,
+}
+
+// Service stores saved discover filters.
+type Service struct {
+	mu      sync.RWMutex
+	path    string
+	store   *datastore.DataStore
+	filters map[string]models.SavedFilter // id -> filter
+}
+
+// useDB returns true when the service is backed by PostgreSQL.
+func (s *Service) useDB() bool { return s.store != nil }
+
+// NewServiceWithStore creates a saved filters service backed by PostgreSQL.
+func NewServiceWithStore(store *datastore.DataStore) (*Service, error) {
+	svc := &Service{
+		store:   store,
+		filters: make(map[string]models.SavedFilter),
+	}
+	if err := svc.load(); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+// NewService creates a saved filters service storing data inside the provided directory.
+func NewService(storageDir string) (*Service, error) {
+	if strings.TrimSpace(storageDir) == "" {
+		return nil, ErrStorageDirRequired
+	}
+	if err := os.MkdirAll(storageDir, 0o755); err != nil {
+		return nil, fmt.Errorf("create saved filters dir: %w", err)
+	}
+
+	svc := &Service{
+		path:    filepath.Join(storageDir, "saved_filters.json"),
+		filters: make(map[string]models.SavedFilter),
+	}
+	if err := svc.load(); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+// NormalizeFilter validates a discover filter and puts it in canonical form:
+// lower-cased media type, language and sort, upper-cased watch region, and
+// sorted, de-duplicated genres, keywords and watch providers. Filters that
+// differ only in form then share cache entries.
+func NormalizeFilter(filter models.DiscoverFilter) (models.DiscoverFilter, error) {
+	filter.MediaType = strings.ToLower(strings.TrimSpace(filter.MediaType))
+	switch filter.MediaType {
+	case "movie", "series":
+	case "tv", "show":
+		filter.MediaType = "series"
+	default:
+		return models.DiscoverFilter{}, fmt.Errorf("%w: media type must be movie or series", ErrInvalidFilter)
+	}
+
+	genres := make([]int64, 0, len(filter.GenreIDs))
+	seen := make(map[int64]bool, len(filter.GenreIDs))
+	for _, id := range filter.GenreIDs {
+		if id <= 0 {
+			return models.DiscoverFilter{}, fmt.Errorf("%w: invalid genre id %d", ErrInvalidFilter, id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			genres = append(genres, id)
+		}
+	}
+	sort.Slice(genres, func(i, j int) bool { return genres[i] < genres[j] })
+	filter.GenreIDs = nil
+	if len(genres) > 0 {
+		filter.GenreIDs = genres
+	}
+
+	keywords := make([]int64, 0, len(filter.KeywordIDs))
+	seenKeywords := make(map[int64]bool, len(filter.KeywordIDs))
+	for _, id := range filter.KeywordIDs {
+		if id <= 0 {
+			return models.DiscoverFilter{}, fmt.Errorf("%w: invalid keyword id %d", ErrInvalidFilter, id)
+		}
+		if !seenKeywords[id] {
+			seenKeywords[id] = true
+			keywords = append(keywords, id)
+		}
+	}
+	sort.Slice(keywords, func(i, j int) bool { return keywords[i] < keywords[j] })
+	filter.KeywordIDs = nil
+	if len(keywords) > 0 {
+		filter.KeywordIDs = keywords
+	}
+
+	cast := make([]int64, 0, len(filter.CastIDs))
+	seenCast := make(map[int64]bool, len(filter.CastIDs))
+	for _, id := range filter.CastIDs {
+		if id <= 0 {
+			return models.DiscoverFilter{}, fmt.Errorf("%w: invalid cast id %d", ErrInvalidFilter, id)
+		}
+		if !seenCast[id] {
+			seenCast[id] = true
+			cast = append(cast, id)
+		}
+	}
+	sort.Slice(cast, func(i, j int) bool { return cast[i] < cast[j] })
+	filter.CastIDs = nil
+	if len(cast) > 0 {
+		if filter.MediaType != "movie" {
+			return models.DiscoverFilter{}, fmt.Errorf("%w: cast filters only apply to movies", ErrInvalidFilter)
+		}
+		filter.CastIDs = cast
+	}
+
+	maxYear := time.Now().Year() + 5
+	switch {
+	case filter.YearFrom < 0 || filter.YearTo < 0,
+		filter.YearFrom > 0 && (filter.YearFrom < 1870 || filter.YearFrom > maxYear),
+		filter.YearTo > 0 && (filter.YearTo < 1870 || filter.YearTo > maxYear),
+		filter.YearFrom > 0 && filter.YearTo > 0 && filter.YearFrom > filter.YearTo:
+		return models.DiscoverFilter{}, fmt.Errorf("%w: invalid year range", ErrInvalidFilter)
+	}
+	if filter.RuntimeMin < 0 || filter.RuntimeMax < 0 ||
+		(filter.RuntimeMax > 0 && filter.RuntimeMin > filter.RuntimeMax) {
+		return models.DiscoverFilter{}, fmt.Errorf("%w: invalid runtime range", ErrInvalidFilter)
+	}
+	if filter.MinRating < 0 || filter.MinRating > 10 {
+		return models.DiscoverFilter{}, fmt.Errorf("%w: minimum rating must be between 0 and 10", ErrInvalidFilter)
+	}
+	if filter.MinVotes < 0 {
+		return models.DiscoverFilter{}, fmt.Errorf("%w: minimum votes must not be negative", ErrInvalidFilter)
+	}
+
+	filter.OriginalLanguage = strings.ToLower(strings.TrimSpace(filter.OriginalLanguage))
+	if filter.OriginalLanguage != "" && len(filter.OriginalLanguage) != 2 {
+		return models.DiscoverFilter{}, fmt.Errorf("%w: original language must be a two-letter code", ErrInvalidFilter)
+	}
+
+	providers := make([]int64, 0, len(filter.WatchProviderIDs))
+	seenProviders := make(map[int64]bool, len(filter.WatchProviderIDs))
+	for _, id := range filter.WatchProviderIDs {
+		if id <= 0 {
+			return models.DiscoverFilter{}, fmt.Errorf("%w: invalid watch provider id %d", ErrInvalidFilter, id)
+		}
+		if !seenProviders[id] {
+			seenProviders[id] = true
+			providers = append(providers, id)
+		}
+	}
+	sort.Slice(providers, func(i, j int) bool { return providers[i] < providers[j] })
+	filter.WatchProviderIDs = nil
+	if len(providers) > 0 {
+		filter.WatchProviderIDs = providers
+	}
+	// A region only means something alongside providers.
+	filter.WatchRegion = strings.ToUpper(strings.TrimSpace(filter.WatchRegion))
+	if len(providers) == 0 {
+		filter.WatchRegion = ""
+	} else if filter.WatchRegion != "" && len(filter.WatchRegion) != 2 {
+		return models.DiscoverFilter{}, fmt.Errorf("%w: watch region must be a two-letter country code", ErrInvalidFilter)
+	}
+
+	filter.Sort = strings.ToLower(strings.TrimSpace(filter.Sort))
+	if !validSorts[filter.Sort] {
+		return models.DiscoverFilter{}, fmt.Errorf("%w: unknown sort %q", ErrInvalidFilter, filter.Sort)
+	}
+	if filter.Sort == "popularity" {
+		filter.Sort = ""
+	}
+	return filter, nil
+}
+
+func normalizeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", ErrNameRequired
+	}
+	if len([]rune(name)) > maxNameLength {
+		return "", fmt.Errorf("%w: name must be at most %d characters", ErrInvalidFilter, maxNameLength)
+	}
+	return name, nil
+}
+
+// Create saves a new named filter for userID.
+func (s *Service) Create(userID, name string, filter models.DiscoverFilter) (models.SavedFilter, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return models.SavedFilter{}, ErrUserIDRequired
+	}
+	name, err := normalizeName(name)
+	if err != nil {
+		return models.SavedFilter{}, err
+	}
+	filter, err = NormalizeFilter(filter)
+	if err != nil {
+		return models.SavedFilter{}, err
+	}
+
+	now := time.Now().UTC()
+	saved := models.SavedFilter{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Name:      name,
+		Filter:    filter,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.filters[saved.ID] = saved
+	if err := s.saveLocked(); err != nil {
+		delete(s.filters, saved.ID)
+		return models.SavedFilter{}, err
+	}
+	return saved, nil
+}
+
+// Update renames one of userID's saved filters and replaces its criteria.
+func (s *Service) Update(userID, id, name string, filter models.DiscoverFilter) (models.SavedFilter, error) {
+	name, err := normalizeName(name)
+	if err != nil {
+		return models.SavedFilter{}, err
+	}
+	filter, err = NormalizeFilter(filter)
+	if err != nil {
+		return models.SavedFilter{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, ok := s.filters[strings.TrimSpace(id)]
+	if !ok || previous.UserID != strings.TrimSpace(userID) {
+		return models.SavedFilter{}, ErrFilterNotFound
+	}
+	updated := previous
+	updated.Name = name
+	updated.Filter = filter
+	updated.UpdatedAt = time.Now().UTC()
+	s.filters[updated.ID] = updated
+	if err := s.saveLocked(); err != nil {
+		s.filters[previous.ID] = previous
+		return models.SavedFilter{}, err
+	}
+	return updated, nil
+}
+
+// Get returns one of userID's saved filters.
+func (s *Service) Get(userID, id string) (models.SavedFilter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	saved, ok := s.filters[strings.TrimSpace(id)]
+	if !ok || saved.UserID != strings.TrimSpace(userID) {
+		return models.SavedFilter{}, ErrFilterNotFound
+	}
+	return saved, nil
+}
+
+// ListByUser returns userID's saved filters in the order they were created.
+func (s *Service) ListByUser(userID string) []models.SavedFilter {
+	userID = strings.TrimSpace(userID)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	filters := make([]models.SavedFilter, 0)
+	for _, saved := range s.filters {
+		if saved.UserID == userID {
+			filters = append(filters, saved)
+		}
+	}
+	sort.Slice(filters, func(i, j int) bool {
+		return filters[i].CreatedAt.Before(filters[j].CreatedAt)
+	})
+	return filters
+}
+
+// Delete removes one of userID's saved filters.
+func (s *Service) Delete(userID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved, ok := s.filters[strings.TrimSpace(id)]
+	if !ok || saved.UserID != strings.TrimSpace(userID) {
+		return ErrFilterNotFound
+	}
+	delete(s.filters, saved.ID)
+	if err := s.saveLocked(); err != nil {
+		s.filters[saved.ID] = saved
+		return err
+	}
+	return nil
+}
+
+// AllFilters returns the criteria of every profile's saved filters, for the
+// metadata cache manager to warm.
+func (s *Service) AllFilters() []models.DiscoverFilter {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	filters := make([]models.DiscoverFilter, 0, len(s.filters))
+	for _, saved := range s.filters {
+		filters = append(filters, saved.Filter)
+	}
+	return filters
+}
+
+func (s *Service) load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.useDB() {
+		list, err := s.store.SavedFilters().List(context.Background())
+		if err != nil {
+			return fmt.Errorf("load saved filters from db: %w", err)
+		}
+		s.filters = make(map[string]models.SavedFilter, len(list))
+		for _, saved := range list {
+			s.filters[saved.ID] = saved
+		}
+		return nil
+	}
+
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("open saved filters file: %w", err)
+	}
+	defer file.Close()
+
+	var stored []models.SavedFilter
+	if err := json.NewDecoder(file).Decode(&stored); err != nil {
+		return fmt.Errorf("decode saved filters: %w", err)
+	}
+
+	s.filters = make(map[string]models.SavedFilter, len(stored))
+	for _, saved := range stored {
+		if strings.TrimSpace(saved.ID) == "" || strings.TrimSpace(saved.UserID) == "" {
+			continue
+		}
+		s.filters[saved.ID] = saved
+	}
+	return nil
+}
+
+func (s *Service) saveLocked() error {
+	if s.useDB() {
+		return s.syncToDB()
+	}
+
+	filters := make([]models.SavedFilter, 0, len(s.filters))
+	for _, saved := range s.filters {
+		filters = append(filters, saved)
+	}
+	sort.Slice(filters, func(i, j int) bool {
+		return filters[i].CreatedAt.Before(filters[j].CreatedAt)
+	})
+
+	tmp := s.path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("create saved filters temp file: %w", err)
+	}
+
+	enc := json.NewEncoder(file)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(filters); err != nil {
+		file.Close()
+		_ = os.Remove(tmp)
+		return fmt.Errorf("encode saved filters: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		_ = os.Remove(tmp)
+		return fmt.Errorf("sync saved filters: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("close saved filters temp file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("replace saved filters file: %w", err)
+	}
+	return nil
+}
+
+// syncToDB writes the full in-memory filter state to PostgreSQL.
+func (s *Service) syncToDB() error {
+	ctx := context.Background()
+	return s.store.WithTx(ctx, func(tx *datastore.Tx) error {
+		existing, err := tx.SavedFilters().List(ctx)
+		if err != nil {
+			return err
+		}
+		dbFilters := make(map[string]models.SavedFilter, len(existing))
+		for _, saved := range existing {
+			dbFilters[saved.ID] = saved
+		}
+		for _, saved := range s.filters {
+			saved := saved
+			if current, ok := dbFilters[saved.ID]; !ok || !current.UpdatedAt.Equal(saved.UpdatedAt) {
+				if err := tx.SavedFilters().Upsert(ctx, &saved); err != nil {
+					return err
+				}
+			}
+			delete(dbFilters, saved.ID)
+		}
+		for id := range dbFilters {
+			if err := tx.SavedFilters().Delete(ctx, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,102 @@
+package savedfilters
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"novastream/models"
+)
+
+func TestCreateUpdateAndReload(t *testing.T) {
+	dir := t.TempDir()
+	svc, err := NewService(dir)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	saved, err := svc.Create("user-1", "  90s anime movies ", models.DiscoverFilter{
+		MediaType:        "Movie",
+		GenreIDs:         []int64{16, 16},
+		YearFrom:         1990,
+		YearTo:           1999,
+		OriginalLanguage: "JA",
+		Sort:             "Popularity",
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	want := models.DiscoverFilter{MediaType: "movie", GenreIDs: []int64{16}, YearFrom: 1990, YearTo: 1999, OriginalLanguage: "ja"}
+	if saved.Name != "90s anime movies" || !reflect.DeepEqual(saved.Filter, want) {
+		t.Fatalf("unexpected saved filter %+v", saved)
+	}
+
+	updated, err := svc.Update("user-1", saved.ID, "Short comedies", models.DiscoverFilter{MediaType: "tv", GenreIDs: []int64{35}, RuntimeMax: 30})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if updated.Filter.MediaType != "series" || updated.CreatedAt != saved.CreatedAt {
+		t.Fatalf("unexpected update %+v", updated)
+	}
+	if _, err := svc.Update("user-2", saved.ID, "Mine now", want); !errors.Is(err, ErrFilterNotFound) {
+		t.Fatalf("expected ErrFilterNotFound for another profile, got %v", err)
+	}
+
+	// Filters survive a restart.
+	reloaded, err := NewService(dir)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	got, err := reloaded.Get("user-1", saved.ID)
+	if err != nil || got.Name != "Short comedies" || got.Filter.RuntimeMax != 30 {
+		t.Fatalf("Get = %+v, %v", got, err)
+	}
+	if filters := reloaded.ListByUser("user-2"); len(filters) != 0 {
+		t.Fatalf("expected no filters for another profile, got %d", len(filters))
+	}
+	if all := reloaded.AllFilters(); len(all) != 1 || all[0].RuntimeMax != 30 {
+		t.Fatalf("unexpected AllFilters %+v", all)
+	}
+
+	if err := reloaded.Delete("user-2", saved.ID); !errors.Is(err, ErrFilterNotFound) {
+		t.Fatalf("expected ErrFilterNotFound, got %v", err)
+	}
+	if err := reloaded.Delete("user-1", saved.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if filters := reloaded.ListByUser("user-1"); len(filters) != 0 {
+		t.Fatalf("expected the filter to be deleted, got %d", len(filters))
+	}
+}
+
+func TestCreateValidatesFilter(t *testing.T) {
+	svc, err := NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	movie := models.DiscoverFilter{MediaType: "movie"}
+	if _, err := svc.Create("", "Name", movie); !errors.Is(err, ErrUserIDRequired) {
+		t.Fatalf("expected ErrUserIDRequired, got %v", err)
+	}
+	if _, err := svc.Create("user-1", " ", movie); !errors.Is(err, ErrNameRequired) {
+		t.Fatalf("expected ErrNameRequired, got %v", err)
+	}
+	for _, filter := range []models.DiscoverFilter{
+		{MediaType: "episode"},
+		{MediaType: "movie", GenreIDs: []int64{0}},
+		{MediaType: "movie", YearFrom: 2000, YearTo: 1990},
+		{MediaType: "movie", RuntimeMin: 120, RuntimeMax: 90},
+		{MediaType: "movie", MinRating: 11},
+		{MediaType: "movie", OriginalLanguage: "japanese"},
+		{MediaType: "movie", Sort: "random"},
+		{MediaType: "movie", KeywordIDs: []int64{0}},
+		{MediaType: "movie", WatchProviderIDs: []int64{-8}},
+		{MediaType: "movie", WatchProviderIDs: []int64{8}, WatchRegion: "USA"},
+		{MediaType: "movie", CastIDs: []int64{0}},
+		{MediaType: "series", CastIDs: []int64{6384}},
+	} {
+		if _, err := svc.Create("user-1", "Name", filter); !errors.Is(err, ErrInvalidFilter) {
+			t.Fatalf("expected ErrInvalidFilter for %+v, got %v", filter, err)
+		}
+	}
+}
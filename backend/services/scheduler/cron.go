@@ -0,0 +1,239 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // per-task timezones must resolve in minimal containers
+
+	"novastream/config"
+)
+
+// cronMacros maps the supported shorthand expressions to their five-field form.
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronDayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// cronSearchYears bounds the search for the next match, so expressions that
+// can never fire (e.g. "0 0 31 2 *") terminate.
+const cronSearchYears = 5
+
+// CronSchedule is a parsed five-field cron expression
+// (minute hour day-of-month month day-of-week).
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64 // bit sets of allowed values
+	domRestricted, dowRestricted  bool
+}
+
+// ParseCronSchedule parses a standard five-field cron expression. Fields
+// accept *, numbers, ranges (1-5), steps (*/15, 0-30/10), comma-separated
+// lists, and three-letter month and weekday names; day-of-week 7 is Sunday.
+// The @hourly, @daily, @weekly, @monthly and @yearly macros are also accepted.
+func ParseCronSchedule(expr string) (*CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if macro, ok := cronMacros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron expression %q must have 5 fields (minute hour day month weekday)", expr)
+	}
+
+	var sched CronSchedule
+	var err error
+	if sched.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("cron minute: %w", err)
+	}
+	if sched.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("cron hour: %w", err)
+	}
+	if sched.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("cron day of month: %w", err)
+	}
+	if sched.month, err = parseCronField(fields[3], 1, 12, cronMonthNames); err != nil {
+		return nil, fmt.Errorf("cron month: %w", err)
+	}
+	if sched.dow, err = parseCronField(fields[4], 0, 7, cronDayNames); err != nil {
+		return nil, fmt.Errorf("cron day of week: %w", err)
+	}
+	if sched.dow&(1<<7) != 0 {
+		sched.dow |= 1 << 0
+	}
+	sched.domRestricted = !strings.HasPrefix(fields[2], "*")
+	sched.dowRestricted = !strings.HasPrefix(fields[4], "*")
+	return &sched, nil
+}
+
+func parseCronField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+			step = n
+		}
+
+		lo, hi := min, max
+		switch {
+		case rangePart == "*":
+		case strings.Contains(rangePart, "-"):
+			loStr, hiStr, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = parseCronValue(loStr, min, max, names); err != nil {
+				return 0, err
+			}
+			if hi, err = parseCronValue(hiStr, min, max, names); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			v, err := parseCronValue(rangePart, min, max, names)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			if hasStep {
+				hi = max // "5/15" means every 15 starting at 5
+			} else {
+				hi = v
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func parseCronValue(s string, min, max int, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("value %d out of range %d-%d", v, min, max)
+	}
+	return v, nil
+}
+
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := c.dom&(1<<uint(t.Day())) != 0
+	dowMatch := c.dow&(1<<uint(t.Weekday())) != 0
+	// As in standard cron, when both day fields are restricted either may match.
+	if c.domRestricted && c.dowRestricted {
+		return domMatch || dowMatch
+	}
+	return domMatch && dowMatch
+}
+
+// Next returns the first matching minute strictly after t, evaluated in t's
+// location. It returns the zero time if nothing matches within five years.
+func (c *CronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(cronSearchYears, 0, 0)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			next := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			if !next.After(t) {
+				// Repeated hour at a DST fall-back; step past it.
+				next = t.Truncate(time.Hour).Add(time.Hour)
+			}
+			t = next
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// taskLocation resolves a task's timezone, defaulting to the server's local
+// time when unset.
+func taskLocation(tz string) (*time.Location, error) {
+	tz = strings.TrimSpace(tz)
+	if tz == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", tz)
+	}
+	return loc, nil
+}
+
+// ValidateTaskSchedule checks a task's cron expression and timezone.
+func ValidateTaskSchedule(task config.ScheduledTask) error {
+	if _, err := taskLocation(task.Timezone); err != nil {
+		return err
+	}
+	if task.Frequency != config.ScheduledTaskFrequencyCron {
+		return nil
+	}
+	if strings.TrimSpace(task.CronExpression) == "" {
+		return fmt.Errorf("cron expression is required for cron frequency")
+	}
+	_, err := ParseCronSchedule(task.CronExpression)
+	return err
+}
+
+// NextCronRun returns when a cron task is next due after its last run, or
+// after its creation if it has never run. ok is false for tasks that are not
+// cron-scheduled or whose schedule or timezone is invalid.
+func NextCronRun(task config.ScheduledTask) (next time.Time, ok bool) {
+	if task.Frequency != config.ScheduledTaskFrequencyCron {
+		return time.Time{}, false
+	}
+	sched, err := ParseCronSchedule(task.CronExpression)
+	if err != nil {
+		return time.Time{}, false
+	}
+	loc, err := taskLocation(task.Timezone)
+	if err != nil {
+		return time.Time{}, false
+	}
+	ref := task.CreatedAt
+	if task.LastRunAt != nil {
+		ref = *task.LastRunAt
+	}
+	next = sched.Next(ref.In(loc))
+	return next, !next.IsZero()
+}
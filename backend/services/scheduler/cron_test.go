@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"novastream/config"
+)
+
+func TestCronScheduleNext(t *testing.T) {
+	utc := time.UTC
+	tests := []struct {
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"0 3 * * *", time.Date(2026, 3, 10, 2, 59, 30, 0, utc), time.Date(2026, 3, 10, 3, 0, 0, 0, utc)},
+		{"0 3 * * *", time.Date(2026, 3, 10, 3, 0, 0, 0, utc), time.Date(2026, 3, 11, 3, 0, 0, 0, utc)},
+		{"*/15 * * * *", time.Date(2026, 3, 10, 10, 7, 0, 0, utc), time.Date(2026, 3, 10, 10, 15, 0, 0, utc)},
+		{"30 4 * * mon-fri", time.Date(2026, 3, 13, 5, 0, 0, 0, utc), time.Date(2026, 3, 16, 4, 30, 0, 0, utc)},
+		{"0 0 1 jan *", time.Date(2026, 3, 10, 0, 0, 0, 0, utc), time.Date(2027, 1, 1, 0, 0, 0, 0, utc)},
+		{"0 12 * * 7", time.Date(2026, 3, 10, 0, 0, 0, 0, utc), time.Date(2026, 3, 15, 12, 0, 0, 0, utc)},
+		// Both day fields restricted: either may match.
+		{"0 0 15 * fri", time.Date(2026, 3, 10, 0, 0, 0, 0, utc), time.Date(2026, 3, 13, 0, 0, 0, 0, utc)},
+		{"@weekly", time.Date(2026, 3, 10, 0, 0, 0, 0, utc), time.Date(2026, 3, 15, 0, 0, 0, 0, utc)},
+	}
+	for _, tt := range tests {
+		sched, err := ParseCronSchedule(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCronSchedule(%q): %v", tt.expr, err)
+		}
+		if got := sched.Next(tt.after); !got.Equal(tt.want) {
+			t.Errorf("%q after %v = %v, want %v", tt.expr, tt.after, got, tt.want)
+		}
+	}
+
+	sched, _ := ParseCronSchedule("0 0 31 2 *")
+	if got := sched.Next(time.Date(2026, 1, 1, 0, 0, 0, 0, utc)); !got.IsZero() {
+		t.Errorf("impossible schedule returned %v", got)
+	}
+}
+
+func TestParseCronScheduleRejectsInvalid(t *testing.T) {
+	for _, expr := range []string{"", "0 3 * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "0 3 * * funday"} {
+		if _, err := ParseCronSchedule(expr); err == nil {
+			t.Errorf("ParseCronSchedule(%q) succeeded, want error", expr)
+		}
+	}
+}
+
+func TestShouldRunCronTaskUsesTimezone(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	s := &Service{taskRunning: map[string]bool{}}
+
+	// Last ran two days ago at 03:00 New York time, so a later slot was missed.
+	lastRun := time.Now().In(loc).AddDate(0, 0, -2)
+	lastRun = time.Date(lastRun.Year(), lastRun.Month(), lastRun.Day(), 3, 0, 0, 0, loc)
+	task := config.ScheduledTask{
+		ID:             "nightly",
+		Frequency:      config.ScheduledTaskFrequencyCron,
+		CronExpression: "0 3 * * *",
+		Timezone:       "America/New_York",
+		LastRunAt:      &lastRun,
+	}
+	if !s.shouldRun(task) {
+		t.Fatal("expected missed cron slot to be due")
+	}
+
+	justRan := time.Now()
+	task.LastRunAt = &justRan
+	if s.shouldRun(task) {
+		t.Fatal("expected cron task not to run again before its next slot")
+	}
+
+	next, ok := NextCronRun(task)
+	if !ok || next.In(loc).Hour() != 3 || next.In(loc).Minute() != 0 {
+		t.Fatalf("NextCronRun = %v (ok=%v), want 03:00 New York time", next, ok)
+	}
+
+	task.Timezone = "Mars/Olympus_Mons"
+	if err := ValidateTaskSchedule(task); err == nil {
+		t.Fatal("expected unknown timezone to be rejected")
+	}
+	if s.shouldRun(task) {
+		t.Fatal("expected task with invalid timezone not to run")
+	}
+}
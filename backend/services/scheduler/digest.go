@@ -0,0 +1,311 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"novastream/config"
+	"novastream/models"
+	"novastream/services/notifications"
+)
+
+const (
+	digestWindow      = 7 * 24 * time.Hour
+	digestSendTimeout = time.Minute
+
+	// digestWatchProviderLimit caps the seasons looked up per digest.
+	digestWatchProviderLimit   = 40
+	digestWatchProviderTimeout = 30 * time.Second
+)
+
+// startedAt is when the server started, reported in the digest's health
+// summary.
+var startedAt = time.Now()
+
+// digestCalendar provides a profile's calendar, which already merges the
+// watchlist and shows in progress.
+type digestCalendar interface {
+	ItemsBetween(userID string, from, to time.Time) []models.CalendarItem
+}
+
+// digestWatchProviders resolves where a series season streams, so upcoming
+// episodes can say "now on Hulu".
+type digestWatchProviders interface {
+	EpisodeWatchProviders(ctx context.Context, tmdbID int64, seasonNumber int, region string) (*models.WatchProviderAvailability, error)
+	WatchProviderRegion() string
+}
+
+type digestMailer interface {
+	SendEmail(ctx context.Context, email notifications.Email) error
+}
+
+// SetCalendarService sets the calendar the email digest reads upcoming
+// episodes and new releases from.
+func (s *Service) SetCalendarService(cal digestCalendar) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calendar = cal
+}
+
+// SetWatchProviderService lets the digest list the streaming services
+// upcoming episodes are on.
+func (s *Service) SetWatchProviderService(svc digestWatchProviders) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watchProviders = svc
+}
+
+// executeEmailDigest mails a profile's weekly digest: episodes airing in the
+// next week, watchlist movies released for home viewing in the past week,
+// and a server health summary.
+func (s *Service) executeEmailDigest(task config.ScheduledTask) (SyncResult, error) {
+	s.mu.RLock()
+	cal := s.calendar
+	mailer := s.mailer
+	s.mu.RUnlock()
+	if cal == nil {
+		return SyncResult{}, fmt.Errorf("calendar service %w", ErrNotConfigured)
+	}
+	if mailer == nil {
+		return SyncResult{}, fmt.Errorf("email %w", ErrNotConfigured)
+	}
+	profileID, err := s.resolveTaskProfileID(task)
+	if err != nil {
+		return SyncResult{}, err
+	}
+	to, err := notifications.ParseRecipients(task.Config["to"])
+	if err != nil {
+		return SyncResult{}, err
+	}
+	settings, err := s.configManager.Load()
+	if err != nil {
+		return SyncResult{}, fmt.Errorf("load settings: %w", err)
+	}
+
+	loc := time.Local
+	if task.Timezone != "" {
+		if tz, err := time.LoadLocation(task.Timezone); err == nil {
+			loc = tz
+		}
+	}
+	now := time.Now().In(loc)
+	upcoming := cal.ItemsBetween(profileID, now, now.Add(digestWindow))
+	s.enrichDigestWatchProviders(profileID, upcoming)
+	digest := notifications.Digest{
+		ProfileName: s.profileName(profileID),
+		Locale:      s.taskLocale(task),
+		GeneratedAt: now,
+		Upcoming:    digestUpcoming(upcoming, loc),
+		NewMovies:   digestNewMovies(cal.ItemsBetween(profileID, now.Add(-digestWindow), now), loc),
+		Health:      digestHealth(settings, task.ID, loc),
+	}
+
+	email, err := notifications.RenderDigest(digest, to)
+	if err != nil {
+		return SyncResult{}, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), digestSendTimeout)
+	defer cancel()
+	if err := mailer.SendEmail(ctx, email); err != nil {
+		if errors.Is(err, notifications.ErrSMTPNotConfigured) {
+			return SyncResult{}, fmt.Errorf("smtp server %w", ErrNotConfigured)
+		}
+		return SyncResult{}, upstreamUnavailable(err)
+	}
+
+	return SyncResult{
+		Count:   len(digest.Upcoming) + len(digest.NewMovies),
+		Message: fmt.Sprintf("Sent digest to %s (%d upcoming episodes, %d new movies)", strings.Join(to, ", "), len(digest.Upcoming), len(digest.NewMovies)),
+	}, nil
+}
+
+func (s *Service) profileName(profileID string) string {
+	s.mu.RLock()
+	usersService := s.usersService
+	s.mu.RUnlock()
+	if usersService != nil {
+		for _, user := range usersService.ListAll() {
+			if user.ID == profileID && user.Name != "" {
+				return user.Name
+			}
+		}
+	}
+	return profileID
+}
+
+// digestUpcoming keeps the episodes from the watchlist and shows in
+// progress; trending and list sources are recommendations, not the
+// profile's own shows.
+func digestUpcoming(items []models.CalendarItem, loc *time.Location) []notifications.DigestEpisode {
+	var episodes []notifications.DigestEpisode
+	for _, item := range items {
+		if item.MediaType != "series" || !isOwnCalendarSource(item.Source) {
+			continue
+		}
+		episodes = append(episodes, notifications.DigestEpisode{
+			SeriesTitle:  item.Title,
+			Season:       item.SeasonNumber,
+			Episode:      item.EpisodeNumber,
+			EpisodeTitle: item.EpisodeTitle,
+			Network:      item.Network,
+			StreamingOn:  streamingProviderNames(item.WatchProviders),
+			AirsAt:       calendarItemTime(item, loc),
+		})
+	}
+	sort.SliceStable(episodes, func(i, j int) bool { return episodes[i].AirsAt.Before(episodes[j].AirsAt) })
+	return episodes
+}
+
+// enrichDigestWatchProviders attaches watch providers to the profile's own
+// upcoming episodes, in the profile's region or else the server's. Lookups
+// are deduped per series season; failures only leave the episode bare.
+func (s *Service) enrichDigestWatchProviders(profileID string, items []models.CalendarItem) {
+	s.mu.RLock()
+	providers := s.watchProviders
+	userSettings := s.userSettings
+	s.mu.RUnlock()
+	if providers == nil {
+		return
+	}
+	region := ""
+	if userSettings != nil {
+		if settings, err := userSettings.Get(profileID); err == nil && settings != nil {
+			region = strings.ToUpper(strings.TrimSpace(settings.Metadata.CertificationCountry))
+		}
+	}
+	if region == "" {
+		region = providers.WatchProviderRegion()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), digestWatchProviderTimeout)
+	defer cancel()
+
+	type seasonKey struct {
+		tmdbID int64
+		season int
+	}
+	lookups := make(map[seasonKey]*models.WatchProviderAvailability)
+	for i, item := range items {
+		if item.MediaType != "series" || item.SeasonNumber <= 0 || !isOwnCalendarSource(item.Source) {
+			continue
+		}
+		tmdbID, err := strconv.ParseInt(item.ExternalIDs["tmdb"], 10, 64)
+		if err != nil || tmdbID <= 0 {
+			continue
+		}
+		key := seasonKey{tmdbID: tmdbID, season: item.SeasonNumber}
+		availability, ok := lookups[key]
+		if !ok {
+			if len(lookups) >= digestWatchProviderLimit {
+				continue
+			}
+			availability, err = providers.EpisodeWatchProviders(ctx, tmdbID, item.SeasonNumber, region)
+			if err != nil {
+				log.Printf("[scheduler] digest watch providers lookup failed tmdbId=%d season=%d: %v", tmdbID, item.SeasonNumber, err)
+			}
+			lookups[key] = availability
+		}
+		items[i].WatchProviders = availability
+	}
+}
+
+// streamingProviderNames lists the services that stream a title without a
+// purchase, in the order TMDB returned them.
+func streamingProviderNames(availability *models.WatchProviderAvailability) []string {
+	if availability == nil {
+		return nil
+	}
+	var names []string
+	seen := make(map[string]bool)
+	for _, provider := range availability.Providers {
+		switch provider.Type {
+		case "flatrate", "free", "ads":
+		default:
+			continue
+		}
+		if provider.Name == "" || seen[provider.Name] {
+			continue
+		}
+		seen[provider.Name] = true
+		names = append(names, provider.Name)
+	}
+	return names
+}
+
+// digestNewMovies keeps the watchlist movies whose digital or disc release
+// fell in the window; theatrical releases aren't watchable at home yet.
+func digestNewMovies(items []models.CalendarItem, loc *time.Location) []notifications.DigestMovie {
+	var movies []notifications.DigestMovie
+	for _, item := range items {
+		if item.MediaType != "movie" || item.Source != "watchlist" {
+			continue
+		}
+		if item.ReleaseType != "digital" && item.ReleaseType != "physical" {
+			continue
+		}
+		movies = append(movies, notifications.DigestMovie{
+			Title:       item.Title,
+			Year:        item.Year,
+			ReleaseType: item.ReleaseType,
+			ReleasedAt:  calendarItemTime(item, loc),
+		})
+	}
+	sort.SliceStable(movies, func(i, j int) bool { return movies[i].ReleasedAt.Before(movies[j].ReleasedAt) })
+	return movies
+}
+
+func isOwnCalendarSource(source string) bool {
+	return source == "watchlist" || source == "history"
+}
+
+// calendarItemTime is the item's air time in loc. Items without an air time
+// are dated at local midnight so they fall on their calendar day.
+func calendarItemTime(item models.CalendarItem, loc *time.Location) time.Time {
+	if item.AirTime == "" || item.AirTimezone == "" {
+		if day, err := time.ParseInLocation("2006-01-02", item.AirDate, loc); err == nil {
+			return day
+		}
+	}
+	at, _ := time.Parse("2006-01-02 15:04", item.AirDate+" "+item.AirTime)
+	if tz, err := time.LoadLocation(item.AirTimezone); err == nil {
+		at = time.Date(at.Year(), at.Month(), at.Day(), at.Hour(), at.Minute(), 0, 0, tz)
+	}
+	return at.In(loc)
+}
+
+// digestHealth summarizes enabled providers and the scheduled tasks whose
+// last run failed, other than the digest task itself.
+func digestHealth(settings config.Settings, digestTaskID string, loc *time.Location) notifications.DigestHealth {
+	health := notifications.DigestHealth{RunningSince: startedAt.In(loc)}
+	for _, provider := range settings.Usenet {
+		if provider.Enabled {
+			health.UsenetProviders++
+		}
+	}
+	for _, provider := range settings.Streaming.DebridProviders {
+		if provider.Enabled {
+			health.DebridProviders++
+		}
+	}
+	for _, task := range settings.ScheduledTasks.Tasks {
+		if !task.Enabled || task.ID == digestTaskID {
+			continue
+		}
+		health.TaskCount++
+		if task.LastStatus != config.ScheduledTaskStatusError {
+			continue
+		}
+		failure := notifications.DigestTaskFailure{Name: task.Name, Error: task.LastError}
+		if task.LastRunAt != nil {
+			failure.FailedAt = task.LastRunAt.In(loc)
+		}
+		health.FailingTasks = append(health.FailingTasks, failure)
+	}
+	return health
+}
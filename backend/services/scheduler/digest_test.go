@@ -0,0 +1,132 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"novastream/config"
+	"novastream/models"
+	"novastream/services/notifications"
+)
+
+type fakeDigestCalendar struct {
+	items []models.CalendarItem
+}
+
+func (f *fakeDigestCalendar) ItemsBetween(_ string, from, to time.Time) []models.CalendarItem {
+	var items []models.CalendarItem
+	for _, item := range f.items {
+		at := calendarItemTime(item, time.UTC)
+		if !at.Before(from) && at.Before(to) {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+type fakeDigestWatchProviders struct {
+	regions []string
+}
+
+func (f *fakeDigestWatchProviders) EpisodeWatchProviders(_ context.Context, tmdbID int64, _ int, region string) (*models.WatchProviderAvailability, error) {
+	f.regions = append(f.regions, region)
+	if tmdbID != 95396 {
+		return nil, errors.New("not found")
+	}
+	return &models.WatchProviderAvailability{Region: region, Providers: []models.WatchProvider{
+		{Name: "Apple TV+", Type: "flatrate"},
+		{Name: "Amazon Video", Type: "buy"},
+	}}, nil
+}
+
+func (f *fakeDigestWatchProviders) WatchProviderRegion() string { return "US" }
+
+type fakeDigestMailer struct {
+	sent []notifications.Email
+	err  error
+}
+
+func (f *fakeDigestMailer) SendEmail(_ context.Context, email notifications.Email) error {
+	f.sent = append(f.sent, email)
+	return f.err
+}
+
+func TestExecuteEmailDigest(t *testing.T) {
+	mgr := config.NewManager(t.TempDir() + "/settings.json")
+	settings := config.DefaultSettings()
+	lastRun := time.Now().Add(-time.Hour)
+	settings.Usenet = []config.UsenetSettings{{Name: "news", Enabled: true}}
+	settings.ScheduledTasks.Tasks = []config.ScheduledTask{
+		{ID: "digest", Name: "Digest", Enabled: true, LastStatus: config.ScheduledTaskStatusError, LastError: "previous send failed"},
+		{ID: "trakt", Name: "Nightly Trakt", Enabled: true, LastStatus: config.ScheduledTaskStatusError, LastError: "trakt unavailable", LastRunAt: &lastRun},
+		{ID: "backup", Name: "Backup", Enabled: true, LastStatus: config.ScheduledTaskStatusSuccess},
+		{ID: "off", Name: "Disabled", LastStatus: config.ScheduledTaskStatusError},
+	}
+	if err := mgr.Save(settings); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	day := func(offset int) string { return time.Now().UTC().AddDate(0, 0, offset).Format("2006-01-02") }
+	mailer := &fakeDigestMailer{}
+	svc := NewService(mgr, nil, nil, nil)
+	svc.mailer = mailer
+	svc.SetUsersService(&fakeSchedulerUsersProvider{users: map[string]models.User{"prof-1": {ID: "prof-1", Name: "Sam"}}})
+	svc.SetCalendarService(&fakeDigestCalendar{items: []models.CalendarItem{
+		{Title: "Severance", MediaType: "series", SeasonNumber: 2, EpisodeNumber: 8, AirDate: day(2), Source: "watchlist", ExternalIDs: map[string]string{"tmdb": "95396"}},
+		{Title: "Severance", MediaType: "series", SeasonNumber: 2, EpisodeNumber: 9, AirDate: day(3), Source: "watchlist", ExternalIDs: map[string]string{"tmdb": "95396"}},
+		{Title: "The Bear", MediaType: "series", SeasonNumber: 4, EpisodeNumber: 1, AirDate: day(1), Source: "history"},
+		{Title: "Trending Show", MediaType: "series", SeasonNumber: 1, AirDate: day(1), Source: "trending", ExternalIDs: map[string]string{"tmdb": "1"}},
+		{Title: "Next Month", MediaType: "series", AirDate: day(30), Source: "watchlist"},
+		{Title: "Dune: Part Two", MediaType: "movie", ReleaseType: "digital", AirDate: day(-2), Source: "watchlist"},
+		{Title: "In Theaters", MediaType: "movie", ReleaseType: "theatrical", AirDate: day(-2), Source: "watchlist"},
+		{Title: "Old Release", MediaType: "movie", ReleaseType: "physical", AirDate: day(-20), Source: "watchlist"},
+	}})
+	providers := &fakeDigestWatchProviders{}
+	svc.SetWatchProviderService(providers)
+
+	task := config.ScheduledTask{
+		ID:       "digest",
+		Type:     config.ScheduledTaskTypeEmailDigest,
+		Timezone: "UTC",
+		Config:   map[string]string{"profileId": "prof-1", "to": "sam@example.com"},
+	}
+	result, err := svc.executeEmailDigest(task)
+	if err != nil {
+		t.Fatalf("executeEmailDigest() error = %v", err)
+	}
+	if result.Count != 4 {
+		t.Fatalf("expected 3 episodes and 1 movie, got %d (%s)", result.Count, result.Message)
+	}
+	if strings.Join(providers.regions, ",") != "US" {
+		t.Fatalf("expected one US lookup for the profile's season, got %v", providers.regions)
+	}
+	if len(mailer.sent) != 1 || strings.Join(mailer.sent[0].To, ",") != "sam@example.com" {
+		t.Fatalf("unexpected emails %+v", mailer.sent)
+	}
+	text := mailer.sent[0].Text
+	for _, want := range []string{"Hi Sam", "The Bear S04E01", "Severance S02E08, now on Apple TV+", "Dune: Part Two: digital", "1 of 2 scheduled tasks failed", "Nightly Trakt"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in digest:\n%s", want, text)
+		}
+	}
+	if strings.Index(text, "The Bear") > strings.Index(text, "Severance") {
+		t.Error("expected episodes in air date order")
+	}
+	for _, unwanted := range []string{"Amazon Video", "Trending Show", "Next Month", "In Theaters", "Old Release", "previous send failed"} {
+		if strings.Contains(text, unwanted) {
+			t.Errorf("unexpected %q in digest:\n%s", unwanted, text)
+		}
+	}
+
+	mailer.err = notifications.ErrSMTPNotConfigured
+	if _, err := svc.executeEmailDigest(task); !errors.Is(err, ErrNotConfigured) {
+		t.Fatalf("expected ErrNotConfigured without an smtp server, got %v", err)
+	}
+	task.Config["to"] = ""
+	if _, err := svc.executeEmailDigest(task); err == nil {
+		t.Fatal("expected an error without recipients")
+	}
+}
@@ -0,0 +1,425 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"novastream/config"
+	"novastream/models"
+	"novastream/services/history"
+	"novastream/services/jellyfin"
+)
+
+// jellyfinTaskAccount resolves the client, authenticated account and target
+// profile for a Jellyfin task.
+func (s *Service) jellyfinTaskAccount(task config.ScheduledTask) (*jellyfin.Client, *config.JellyfinAccount, string, error) {
+	s.mu.RLock()
+	jfClient := s.jellyfinClient
+	s.mu.RUnlock()
+
+	if jfClient == nil {
+		return nil, nil, "", fmt.Errorf("jellyfin client %w", ErrNotConfigured)
+	}
+
+	jellyfinAccountID := task.Config["jellyfinAccountId"]
+	profileID, err := s.resolveTaskProfileID(task)
+
+	if jellyfinAccountID == "" || profileID == "" {
+		return nil, nil, "", errors.New("missing jellyfinAccountId or profileId in task config")
+	}
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	// Load settings to get Jellyfin account
+	settings, err := s.configManager.Load()
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("load settings: %w", err)
+	}
+
+	jfAccount := settings.Jellyfin.GetAccountByID(jellyfinAccountID)
+	if jfAccount == nil {
+		return nil, nil, "", fmt.Errorf("jellyfin account %w", ErrNotFound)
+	}
+	if jfAccount.Token == "" {
+		return nil, nil, "", errors.New("jellyfin account not authenticated")
+	}
+	return jfClient, jfAccount, profileID, nil
+}
+
+// executeJellyfinSync syncs favorites (watchlist) and watched state between a
+// Jellyfin user and a local profile. Config:
+//   - syncDirection: source_to_target (import), target_to_source (export) or bidirectional
+//   - syncScope: all (default), watchlist or history
+//   - deleteBehavior: additive (default), delete or mirror
+//   - dryRun: "true" to report changes without applying them
+func (s *Service) executeJellyfinSync(task config.ScheduledTask) (SyncResult, error) {
+	jfClient, jfAccount, profileID, err := s.jellyfinTaskAccount(task)
+	if err != nil {
+		return SyncResult{}, err
+	}
+
+	syncDirection := task.Config["syncDirection"]
+	if syncDirection == "" {
+		syncDirection = "source_to_target"
+	}
+	switch syncDirection {
+	case "source_to_target", "target_to_source", "bidirectional":
+	default:
+		return SyncResult{}, fmt.Errorf("unknown sync direction: %s", syncDirection)
+	}
+	deleteBehavior := task.Config["deleteBehavior"]
+	if deleteBehavior == "" {
+		deleteBehavior = "additive"
+	}
+	dryRun := task.Config["dryRun"] == "true"
+
+	syncWatchlist, syncHistory := true, true
+	switch task.Config["syncScope"] {
+	case "", "all":
+	case "watchlist":
+		syncHistory = false
+	case "history":
+		syncWatchlist = false
+	default:
+		return SyncResult{}, fmt.Errorf("unknown sync scope: %s", task.Config["syncScope"])
+	}
+
+	s.mu.RLock()
+	historySvc := s.historyService
+	s.mu.RUnlock()
+	if syncHistory && historySvc == nil {
+		return SyncResult{}, fmt.Errorf("history service %w", ErrNotConfigured)
+	}
+
+	if dryRun {
+		log.Printf("[scheduler] DRY RUN mode enabled for Jellyfin sync")
+	}
+
+	// Exports need the Jellyfin library to resolve local items to Jellyfin
+	// item IDs and to read the user's current favorite/played state.
+	var library *jellyfinLibrary
+	if syncDirection != "source_to_target" {
+		itemTypes := []string{"Movie", "Series"}
+		if syncHistory {
+			itemTypes = append(itemTypes, "Episode")
+		}
+		items, err := jfClient.GetLibraryItems(jfAccount.ServerURL, jfAccount.Token, jfAccount.UserID, itemTypes...)
+		if err != nil {
+			return SyncResult{}, fmt.Errorf("fetch jellyfin library: %w", upstreamUnavailable(err))
+		}
+		library = newJellyfinLibrary(items)
+		log.Printf("[scheduler] Indexed %d Jellyfin library items", len(items))
+	}
+
+	syncSource := fmt.Sprintf("jellyfin:%s:%s", jfAccount.ID, task.ID)
+	result := SyncResult{DryRun: dryRun}
+	add := func(part SyncResult) {
+		result.Count += part.Count
+		result.ToAdd = append(result.ToAdd, part.ToAdd...)
+		result.ToRemove = append(result.ToRemove, part.ToRemove...)
+	}
+
+	if syncWatchlist {
+		if syncDirection != "target_to_source" {
+			// Bidirectional sync is a union; deletions can't be attributed to
+			// either side without tracking what was previously synced.
+			importBehavior := deleteBehavior
+			if syncDirection == "bidirectional" {
+				importBehavior = "additive"
+			}
+			part, err := s.syncJellyfinFavoritesToLocal(jfClient, jfAccount, profileID, syncSource, importBehavior, dryRun)
+			if err != nil {
+				return result, err
+			}
+			add(part)
+		}
+		if syncDirection != "source_to_target" {
+			exportBehavior := deleteBehavior
+			if syncDirection == "bidirectional" {
+				exportBehavior = "additive"
+			}
+			part, err := s.syncLocalToJellyfinFavorites(jfClient, jfAccount, library, profileID, exportBehavior, dryRun)
+			if err != nil {
+				return result, err
+			}
+			add(part)
+		}
+		if syncDirection == "bidirectional" && deleteBehavior != "additive" {
+			log.Printf("[scheduler] Note: delete/mirror behavior in bidirectional Jellyfin sync only adds favorites (deletion tracking not yet implemented)")
+		}
+	}
+
+	if syncHistory {
+		// Export before importing so a newer local unwatch is applied to
+		// Jellyfin before its played items are pulled back in.
+		if syncDirection != "source_to_target" {
+			part, err := s.syncLocalHistoryToJellyfin(historySvc, jfClient, jfAccount, library, profileID, deleteBehavior, dryRun)
+			if err != nil {
+				return result, err
+			}
+			add(part)
+		}
+		if syncDirection != "target_to_source" {
+			part, err := s.importJellyfinHistory(historySvc, jfClient, jfAccount, profileID, dryRun)
+			if err != nil {
+				return result, err
+			}
+			add(part)
+		}
+	}
+
+	return result, nil
+}
+
+// syncLocalToJellyfinFavorites favorites local watchlist items in Jellyfin.
+// Items that aren't in the Jellyfin library are skipped. In mirror mode,
+// Jellyfin favorites missing from the local watchlist are unfavorited.
+func (s *Service) syncLocalToJellyfinFavorites(jfClient *jellyfin.Client, jfAccount *config.JellyfinAccount, library *jellyfinLibrary, profileID, deleteBehavior string, dryRun bool) (SyncResult, error) {
+	result := SyncResult{DryRun: dryRun}
+
+	localItems, err := s.watchlistService.List(profileID)
+	if err != nil {
+		return result, fmt.Errorf("list local items: %w", err)
+	}
+
+	localJellyfinIDs := make(map[string]bool)
+	exported := 0
+	notInLibrary := 0
+
+	for _, localItem := range localItems {
+		jfItem := library.lookup(localItem.MediaType, localItem.ID, localItem.ExternalIDs)
+		if jfItem == nil {
+			notInLibrary++
+			continue
+		}
+		localJellyfinIDs[jfItem.ID] = true
+
+		if jfItem.UserData != nil && jfItem.UserData.IsFavorite {
+			continue // Already a favorite
+		}
+
+		if dryRun {
+			log.Printf("[scheduler] DRY RUN: Would favorite in Jellyfin: %s", localItem.Name)
+			result.ToAdd = append(result.ToAdd, config.DryRunItem{
+				Name:      localItem.Name,
+				MediaType: localItem.MediaType,
+				ID:        localItem.ID,
+			})
+			exported++
+			continue
+		}
+
+		if err := jfClient.SetFavorite(jfAccount.ServerURL, jfAccount.Token, jfAccount.UserID, jfItem.ID, true); err != nil {
+			log.Printf("[scheduler] Failed to favorite %s in Jellyfin: %v", localItem.Name, err)
+			continue
+		}
+		exported++
+	}
+
+	if notInLibrary > 0 {
+		log.Printf("[scheduler] Skipped %d watchlist items not found in the Jellyfin library", notInLibrary)
+	}
+
+	// For "delete" mode we can't tell which Jellyfin favorites were synced from
+	// local, so only "mirror" removes favorites.
+	if deleteBehavior == "mirror" {
+		removed := 0
+		for _, jfItem := range library.favorites {
+			if localJellyfinIDs[jfItem.ID] {
+				continue
+			}
+
+			if dryRun {
+				log.Printf("[scheduler] DRY RUN: Would unfavorite in Jellyfin: %s", jfItem.Name)
+				result.ToRemove = append(result.ToRemove, config.DryRunItem{
+					Name:      jfItem.Name,
+					MediaType: jellyfin.NormalizeMediaType(jfItem.Type),
+					ID:        jfItem.ID,
+				})
+				removed++
+				continue
+			}
+
+			if err := jfClient.SetFavorite(jfAccount.ServerURL, jfAccount.Token, jfAccount.UserID, jfItem.ID, false); err != nil {
+				log.Printf("[scheduler] Failed to unfavorite %s in Jellyfin: %v", jfItem.Name, err)
+				continue
+			}
+			removed++
+		}
+		if removed > 0 {
+			log.Printf("[scheduler] Removed %d Jellyfin favorites not in the local watchlist", removed)
+		}
+	}
+
+	log.Printf("[scheduler] Exported %d watchlist items to Jellyfin favorites", exported)
+	result.Count = exported
+	return result, nil
+}
+
+// syncLocalHistoryToJellyfin marks locally watched movies and episodes as
+// played in Jellyfin. Unless deleteBehavior is additive, local unwatches that
+// are newer than Jellyfin's last play are exported as well.
+func (s *Service) syncLocalHistoryToJellyfin(historySvc *history.Service, jfClient *jellyfin.Client, jfAccount *config.JellyfinAccount, library *jellyfinLibrary, profileID, deleteBehavior string, dryRun bool) (SyncResult, error) {
+	result := SyncResult{DryRun: dryRun}
+
+	items, err := historySvc.ListWatchHistory(profileID)
+	if err != nil {
+		return result, fmt.Errorf("list local history: %w", err)
+	}
+
+	exported := 0
+	removed := 0
+	for _, item := range items {
+		if item.MediaType != "movie" && item.MediaType != "episode" {
+			continue
+		}
+		jfItem := library.lookupHistoryItem(item)
+		if jfItem == nil {
+			continue
+		}
+		played := jfItem.UserData != nil && jfItem.UserData.Played
+
+		name := item.Name
+		if item.MediaType == "episode" {
+			name = fmt.Sprintf("%s S%02dE%02d", item.SeriesName, item.SeasonNumber, item.EpisodeNumber)
+		}
+
+		if item.Watched {
+			if played {
+				continue
+			}
+			if dryRun {
+				result.ToAdd = append(result.ToAdd, config.DryRunItem{
+					Name:      name,
+					MediaType: item.MediaType,
+					ID:        item.ItemID,
+				})
+				exported++
+				continue
+			}
+			if err := jfClient.SetPlayed(jfAccount.ServerURL, jfAccount.Token, jfAccount.UserID, jfItem.ID, true, item.WatchedAt); err != nil {
+				log.Printf("[scheduler] Failed to mark %s played in Jellyfin: %v", name, err)
+				continue
+			}
+			exported++
+			continue
+		}
+
+		if !played || deleteBehavior == "additive" {
+			continue
+		}
+		// A later play in Jellyfin wins over an older local unwatch.
+		if lastPlayed := jfItem.UserData.LastPlayedDate; lastPlayed != nil && !item.UpdatedAt.After(*lastPlayed) {
+			continue
+		}
+		if dryRun {
+			result.ToRemove = append(result.ToRemove, config.DryRunItem{
+				Name:      name,
+				MediaType: item.MediaType,
+				ID:        item.ItemID,
+			})
+			removed++
+			continue
+		}
+		if err := jfClient.SetPlayed(jfAccount.ServerURL, jfAccount.Token, jfAccount.UserID, jfItem.ID, false, item.UpdatedAt); err != nil {
+			log.Printf("[scheduler] Failed to mark %s unplayed in Jellyfin: %v", name, err)
+			continue
+		}
+		removed++
+	}
+
+	log.Printf("[scheduler] Exported %d watched and %d unwatched items to Jellyfin", exported, removed)
+	result.Count = exported + removed
+	return result, nil
+}
+
+// jellyfinLibrary indexes a user's Jellyfin library for matching local items.
+type jellyfinLibrary struct {
+	byID      map[string]*jellyfin.JellyfinItem
+	byKey     map[string]*jellyfin.JellyfinItem // schedulerWatchlistMatchKeys of movies and series
+	episodes  map[string]*jellyfin.JellyfinItem // "<series id>:<season>:<episode>"
+	favorites []*jellyfin.JellyfinItem
+}
+
+func newJellyfinLibrary(items []jellyfin.JellyfinItem) *jellyfinLibrary {
+	lib := &jellyfinLibrary{
+		byID:     make(map[string]*jellyfin.JellyfinItem, len(items)),
+		byKey:    make(map[string]*jellyfin.JellyfinItem),
+		episodes: make(map[string]*jellyfin.JellyfinItem),
+	}
+	for i := range items {
+		item := &items[i]
+		lib.byID[item.ID] = item
+		mediaType := jellyfin.NormalizeMediaType(item.Type)
+		switch mediaType {
+		case "movie", "series":
+			for _, key := range schedulerWatchlistMatchKeys(mediaType, "", item.ProviderIDs) {
+				// Keep the first match so duplicate library copies resolve stably.
+				if _, exists := lib.byKey[key]; !exists {
+					lib.byKey[key] = item
+				}
+			}
+			if item.UserData != nil && item.UserData.IsFavorite {
+				lib.favorites = append(lib.favorites, item)
+			}
+		case "episode":
+			if item.SeriesID != "" {
+				key := jellyfinEpisodeKey(item.SeriesID, item.SeasonNum, item.EpisodeNum)
+				if _, exists := lib.episodes[key]; !exists {
+					lib.episodes[key] = item
+				}
+			}
+		}
+	}
+	return lib
+}
+
+func jellyfinEpisodeKey(seriesID string, season, episode int) string {
+	return fmt.Sprintf("%s:%d:%d", seriesID, season, episode)
+}
+
+// lookup finds the library movie or series for a local item, preferring a
+// stored Jellyfin ID over provider ID matching.
+func (l *jellyfinLibrary) lookup(mediaType, id string, externalIDs map[string]string) *jellyfin.JellyfinItem {
+	if l == nil {
+		return nil
+	}
+	if jfID := externalIDs["jellyfin"]; jfID != "" {
+		if item := l.byID[jfID]; item != nil {
+			return item
+		}
+	}
+	for _, key := range schedulerWatchlistMatchKeys(mediaType, id, externalIDs) {
+		if item := l.byKey[key]; item != nil {
+			return item
+		}
+	}
+	return nil
+}
+
+// lookupHistoryItem finds the library movie or episode for a local watch
+// history entry. Episode entries carry their series' provider IDs.
+func (l *jellyfinLibrary) lookupHistoryItem(item models.WatchHistoryItem) *jellyfin.JellyfinItem {
+	if l == nil {
+		return nil
+	}
+	if item.MediaType == "movie" {
+		return l.lookup("movie", item.ItemID, item.ExternalIDs)
+	}
+	if jfID := item.ExternalIDs["jellyfin"]; jfID != "" {
+		if jfItem := l.byID[jfID]; jfItem != nil && strings.EqualFold(jfItem.Type, "Episode") {
+			return jfItem
+		}
+	}
+	if item.SeasonNumber == 0 && item.EpisodeNumber == 0 {
+		return nil
+	}
+	series := l.lookup("series", item.SeriesID, item.ExternalIDs)
+	if series == nil || !strings.EqualFold(series.Type, "Series") {
+		return nil
+	}
+	return l.episodes[jellyfinEpisodeKey(series.ID, item.SeasonNumber, item.EpisodeNumber)]
+}
@@ -0,0 +1,116 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"novastream/config"
+	"novastream/models"
+	"novastream/services/notifications"
+	"novastream/services/streamingavailability"
+)
+
+// Task config keys recording what earlier runs already reported.
+const (
+	leavingSoonReportedAtKey      = "reportedAt"
+	leavingSoonReportedThroughKey = "reportedThrough"
+)
+
+// leavingSoonSource finds watchlist titles about to leave streaming
+// services.
+type leavingSoonSource interface {
+	IsConfigured() bool
+	LeavingSoon(ctx context.Context, items []models.WatchlistItem, opts streamingavailability.Options) []models.WatchlistItem
+}
+
+// SetLeavingSoonSource sets the source of streaming departure dates used by
+// leaving soon alerts.
+func (s *Service) SetLeavingSoonSource(source leavingSoonSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leavingSoon = source
+}
+
+// executeLeavingSoon alerts a profile to watchlist titles about to leave its
+// streaming services. Each title is reported once: when its departure first
+// falls inside the window, or when it is added to the watchlist while
+// already inside it. Runs with nothing new send no notification.
+func (s *Service) executeLeavingSoon(task config.ScheduledTask) (SyncResult, error) {
+	s.mu.RLock()
+	source := s.leavingSoon
+	watchlistSvc := s.watchlistService
+	userSettings := s.userSettings
+	s.mu.RUnlock()
+	if source == nil || !source.IsConfigured() {
+		return SyncResult{}, fmt.Errorf("streaming availability %w", ErrNotConfigured)
+	}
+	if watchlistSvc == nil {
+		return SyncResult{}, fmt.Errorf("watchlist service %w", ErrNotConfigured)
+	}
+	profileID, err := s.resolveTaskProfileID(task)
+	if err != nil {
+		return SyncResult{}, err
+	}
+	settings, err := s.configManager.Load()
+	if err != nil {
+		return SyncResult{}, fmt.Errorf("load settings: %w", err)
+	}
+	var profile *models.UserSettings
+	if userSettings != nil {
+		profile, _ = userSettings.Get(profileID)
+	}
+	items, err := watchlistSvc.List(profileID)
+	if err != nil {
+		return SyncResult{}, fmt.Errorf("list watchlist: %w", err)
+	}
+
+	now := time.Now().UTC()
+	opts := streamingavailability.OptionsFor(settings, profile)
+	opts.Now = now
+	leaving := source.LeavingSoon(s.ctx, items, opts)
+	fresh := unreportedLeaving(leaving, task.Config)
+
+	result := SyncResult{
+		Count: len(fresh),
+		Config: map[string]string{
+			leavingSoonReportedAtKey:      now.Format(time.RFC3339),
+			leavingSoonReportedThroughKey: now.Add(opts.Within).Format(time.RFC3339),
+		},
+	}
+	if len(fresh) == 0 {
+		result.Quiet = true
+		result.Message = fmt.Sprintf("No new titles leaving soon (%d already reported)", len(leaving))
+		return result, nil
+	}
+
+	titles := make([]notifications.LeavingSoonTitle, 0, len(fresh))
+	for _, item := range fresh {
+		titles = append(titles, notifications.LeavingSoonTitle{
+			Title:    item.Name,
+			Service:  item.Leaving.Service,
+			LeavesAt: item.Leaving.LeavesAt,
+		})
+	}
+	e := notifications.LeavingSoonEvent(s.taskLocale(task), s.profileName(profileID), titles, now)
+	result.Notification = &e
+	result.Message = fmt.Sprintf("%d titles leaving soon", len(fresh))
+	return result, nil
+}
+
+// unreportedLeaving drops the titles an earlier run already reported: those
+// leaving inside its window that were on the watchlist at the time.
+func unreportedLeaving(items []models.WatchlistItem, taskConfig map[string]string) []models.WatchlistItem {
+	reportedAt, errAt := time.Parse(time.RFC3339, taskConfig[leavingSoonReportedAtKey])
+	reportedThrough, errThrough := time.Parse(time.RFC3339, taskConfig[leavingSoonReportedThroughKey])
+	if errAt != nil || errThrough != nil {
+		return items
+	}
+	var fresh []models.WatchlistItem
+	for _, item := range items {
+		if item.Leaving.LeavesAt.After(reportedThrough) || item.AddedAt.After(reportedAt) {
+			fresh = append(fresh, item)
+		}
+	}
+	return fresh
+}
@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"novastream/config"
+	"novastream/models"
+	"novastream/services/notifications"
+)
+
+func TestUnreportedLeavingSkipsEarlierAlerts(t *testing.T) {
+	reportedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	taskConfig := map[string]string{
+		leavingSoonReportedAtKey:      reportedAt.Format(time.RFC3339),
+		leavingSoonReportedThroughKey: reportedAt.Add(14 * 24 * time.Hour).Format(time.RFC3339),
+	}
+	leaving := func(id string, added time.Time, leavesInDays int) models.WatchlistItem {
+		return models.WatchlistItem{ID: id, AddedAt: added, Leaving: &models.ProviderDeparture{
+			LeavesAt: reportedAt.Add(time.Duration(leavesInDays) * 24 * time.Hour),
+		}}
+	}
+	items := []models.WatchlistItem{
+		leaving("reported", reportedAt.Add(-time.Hour), 10),
+		leaving("added-since", reportedAt.Add(time.Hour), 10),
+		leaving("newly-inside", reportedAt.Add(-time.Hour), 15),
+	}
+
+	fresh := unreportedLeaving(items, taskConfig)
+	if len(fresh) != 2 || fresh[0].ID != "added-since" || fresh[1].ID != "newly-inside" {
+		t.Fatalf("unexpected unreported titles %+v", fresh)
+	}
+	if got := unreportedLeaving(items, nil); len(got) != 3 {
+		t.Fatalf("expected a first run to report everything, got %d", len(got))
+	}
+}
+
+func TestNotifyTaskOutcomeUsesResultNotification(t *testing.T) {
+	notifier := &fakeTaskNotifier{sent: make(chan sentNotification, 4)}
+	s := &Service{notifier: notifier}
+	task := config.ScheduledTask{
+		ID:   "task-1",
+		Type: config.ScheduledTaskTypeLeavingSoon,
+		Notifications: []config.TaskNotification{
+			{Type: config.TaskNotificationTypeNtfy, URL: "https://ntfy.test/alerts", OnSuccess: true},
+		},
+	}
+
+	s.notifyTaskOutcome(task, nil, SyncResult{Quiet: true})
+	select {
+	case got := <-notifier.sent:
+		t.Fatalf("expected a quiet run to send nothing, got %+v", got.event)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	alert := notifications.LeavingSoonEvent(s.taskLocale(task), "Kids", []notifications.LeavingSoonTitle{
+		{Title: "Bluey", Service: "Disney+", LeavesAt: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)},
+	}, time.Now())
+	s.notifyTaskOutcome(task, nil, SyncResult{Count: 1, Notification: &alert})
+	select {
+	case got := <-notifier.sent:
+		if got.event.Title() != "Watchlist titles leaving soon for Kids" || got.event.Summary() != "Bluey leaves Disney+ on 10/20/2026" {
+			t.Fatalf("unexpected alert %q / %q", got.event.Title(), got.event.Summary())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the alert to be sent")
+	}
+}
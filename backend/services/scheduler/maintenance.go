@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"novastream/config"
+)
+
+// maintenanceTaskTypes are the scheduled tasks heavy enough to wait for the
+// maintenance window: they move a lot of data or spend external API quota.
+var maintenanceTaskTypes = map[config.ScheduledTaskType]bool{
+	config.ScheduledTaskTypeBackup:              true,
+	config.ScheduledTaskTypeLocalMediaScan:      true,
+	config.ScheduledTaskTypeTraktHistorySync:    true,
+	config.ScheduledTaskTypeSimklHistorySync:    true,
+	config.ScheduledTaskTypePlexHistorySync:     true,
+	config.ScheduledTaskTypeJellyfinHistorySync: true,
+	config.ScheduledTaskTypeJellyfinSync:        true,
+	config.ScheduledTaskTypeMDBListHistorySync:  true,
+	config.ScheduledTaskTypeTVDBReidentify:      true,
+}
+
+// maintenanceMinInterval is the shortest task interval that is deferred;
+// anything more frequent would lose its cadence by waiting for the window.
+const maintenanceMinInterval = 24 * time.Hour
+
+// maintenanceGrace is how far past due a deferred task may slip before it
+// runs regardless of the window, so a short or misconfigured window can't
+// starve it.
+const maintenanceGrace = 24 * time.Hour
+
+// parseClock parses "HH:MM" into minutes after midnight.
+func parseClock(value string) (int, error) {
+	hh, mm, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok {
+		return 0, fmt.Errorf("time %q must be HH:MM", value)
+	}
+	h, errH := strconv.Atoi(hh)
+	m, errM := strconv.Atoi(mm)
+	if errH != nil || errM != nil || h < 0 || h > 23 || m < 0 || m > 59 || len(hh) != 2 || len(mm) != 2 {
+		return 0, fmt.Errorf("time %q must be HH:MM", value)
+	}
+	return h*60 + m, nil
+}
+
+// ValidateMaintenanceWindow checks a window's times and timezone. Disabled
+// windows are still validated so a bad value can't be saved and enabled later.
+func ValidateMaintenanceWindow(window config.MaintenanceWindowSettings) error {
+	start, err := parseClock(window.Start)
+	if err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+	end, err := parseClock(window.End)
+	if err != nil {
+		return fmt.Errorf("end: %w", err)
+	}
+	if start == end {
+		return fmt.Errorf("start and end must differ")
+	}
+	_, err = taskLocation(window.Timezone)
+	return err
+}
+
+// InMaintenanceWindow reports whether t falls inside the window. Invalid
+// windows never contain t.
+func InMaintenanceWindow(window config.MaintenanceWindowSettings, t time.Time) bool {
+	start, err := parseClock(window.Start)
+	if err != nil {
+		return false
+	}
+	end, err := parseClock(window.End)
+	if err != nil {
+		return false
+	}
+	loc, err := taskLocation(window.Timezone)
+	if err != nil {
+		return false
+	}
+	local := t.In(loc)
+	now := local.Hour()*60 + local.Minute()
+	if start < end {
+		return now >= start && now < end
+	}
+	return now >= start || now < end
+}
+
+// OutsideMaintenanceWindow reports whether heavy background work should be
+// held back at t: the window is enabled and valid, and t falls outside it.
+func OutsideMaintenanceWindow(window config.MaintenanceWindowSettings, t time.Time) bool {
+	if !window.Enabled || ValidateMaintenanceWindow(window) != nil {
+		return false
+	}
+	return !InMaintenanceWindow(window, t)
+}
+
+// deferToMaintenanceWindow reports whether a due task should wait for the
+// maintenance window. Cron tasks already run at times the user picked, one-off
+// tasks were asked for explicitly, and tasks that have slipped past
+// maintenanceGrace run anyway.
+func (s *Service) deferToMaintenanceWindow(task config.ScheduledTask, window config.MaintenanceWindowSettings, now time.Time) bool {
+	if !maintenanceTaskTypes[task.Type] {
+		return false
+	}
+	if task.Frequency == config.ScheduledTaskFrequencyCron || task.Frequency == config.ScheduledTaskFrequencyOnce {
+		return false
+	}
+	interval := s.getInterval(task.Type, task.Frequency)
+	if interval < maintenanceMinInterval || !OutsideMaintenanceWindow(window, now) {
+		return false
+	}
+	since := task.CreatedAt
+	if task.LastRunAt != nil {
+		since = *task.LastRunAt
+	}
+	return now.Sub(since) < interval+maintenanceGrace
+}
@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"novastream/config"
+)
+
+func TestInMaintenanceWindowHandlesMidnight(t *testing.T) {
+	day := config.MaintenanceWindowSettings{Start: "02:00", End: "05:00", Timezone: "UTC"}
+	night := config.MaintenanceWindowSettings{Start: "23:30", End: "04:00", Timezone: "UTC"}
+	at := func(h, m int) time.Time { return time.Date(2026, 3, 10, h, m, 0, 0, time.UTC) }
+
+	tests := []struct {
+		window config.MaintenanceWindowSettings
+		t      time.Time
+		want   bool
+	}{
+		{day, at(1, 59), false},
+		{day, at(2, 0), true},
+		{day, at(4, 59), true},
+		{day, at(5, 0), false},
+		{night, at(23, 45), true},
+		{night, at(3, 0), true},
+		{night, at(12, 0), false},
+	}
+	for _, tt := range tests {
+		if got := InMaintenanceWindow(tt.window, tt.t); got != tt.want {
+			t.Errorf("InMaintenanceWindow(%s-%s, %s) = %v, want %v", tt.window.Start, tt.window.End, tt.t.Format("15:04"), got, tt.want)
+		}
+	}
+
+	for _, bad := range []config.MaintenanceWindowSettings{
+		{Start: "2:00", End: "05:00"},
+		{Start: "02:00", End: "24:00"},
+		{Start: "02:00", End: "02:00"},
+		{Start: "02:00", End: "05:00", Timezone: "Mars/Base"},
+	} {
+		if err := ValidateMaintenanceWindow(bad); err == nil {
+			t.Errorf("ValidateMaintenanceWindow(%+v) = nil, want error", bad)
+		}
+	}
+}
+
+func TestDeferToMaintenanceWindow(t *testing.T) {
+	s := &Service{}
+	window := config.MaintenanceWindowSettings{Enabled: true, Start: "02:00", End: "05:00", Timezone: "UTC"}
+	noon := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	lastRun := noon.Add(-25 * time.Hour)
+	backup := config.ScheduledTask{
+		Type:      config.ScheduledTaskTypeBackup,
+		Frequency: config.ScheduledTaskFrequencyDaily,
+		LastRunAt: &lastRun,
+	}
+
+	if !s.deferToMaintenanceWindow(backup, window, noon) {
+		t.Fatal("daily backup due at noon should wait for the window")
+	}
+	if s.deferToMaintenanceWindow(backup, window, time.Date(2026, 3, 10, 3, 0, 0, 0, time.UTC)) {
+		t.Fatal("backup inside the window should run")
+	}
+
+	disabled := window
+	disabled.Enabled = false
+	if s.deferToMaintenanceWindow(backup, disabled, noon) {
+		t.Fatal("disabled window should not defer tasks")
+	}
+
+	overdue := noon.Add(-49 * time.Hour)
+	late := backup
+	late.LastRunAt = &overdue
+	if s.deferToMaintenanceWindow(late, window, noon) {
+		t.Fatal("task past the grace period should run outside the window")
+	}
+
+	frequent := backup
+	frequent.Frequency = config.ScheduledTaskFrequency6Hours
+	if s.deferToMaintenanceWindow(frequent, window, noon) {
+		t.Fatal("sub-daily tasks should keep their cadence")
+	}
+
+	epg := backup
+	epg.Type = config.ScheduledTaskTypeEPGRefresh
+	if s.deferToMaintenanceWindow(epg, window, noon) {
+		t.Fatal("light tasks should not be deferred")
+	}
+
+	cron := backup
+	cron.Frequency = config.ScheduledTaskFrequencyCron
+	cron.CronExpression = "0 12 * * *"
+	if s.deferToMaintenanceWindow(cron, window, noon) {
+		t.Fatal("cron tasks run at their own times")
+	}
+}
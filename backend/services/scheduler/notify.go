@@ -0,0 +1,93 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"novastream/config"
+	"novastream/services/notifications"
+	"novastream/utils/locale"
+)
+
+const taskNotificationTimeout = 30 * time.Second
+
+type taskNotifier interface {
+	Send(ctx context.Context, target config.TaskNotification, e notifications.Event) error
+}
+
+// taskNotificationEvent summarizes a finished run for notification targets.
+func taskNotificationEvent(task config.ScheduledTask, err error, result SyncResult) notifications.Event {
+	e := notifications.Event{
+		TaskID:     task.ID,
+		TaskName:   task.Name,
+		TaskType:   string(task.Type),
+		Success:    err == nil,
+		Count:      result.Count,
+		DryRun:     result.DryRun,
+		ToAdd:      len(result.ToAdd),
+		ToRemove:   len(result.ToRemove),
+		Message:    result.Message,
+		FinishedAt: time.Now().UTC(),
+	}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	return e
+}
+
+// notifyTaskOutcome sends the run's outcome to the task's notification
+// targets in the background; delivery failures are only logged.
+func (s *Service) notifyTaskOutcome(task config.ScheduledTask, err error, result SyncResult) {
+	if s.notifier == nil || len(task.Notifications) == 0 {
+		return
+	}
+	if err == nil && result.Quiet {
+		return
+	}
+	e := taskNotificationEvent(task, err, result)
+	e.Locale = s.taskLocale(task)
+	if err == nil && result.Notification != nil {
+		e = *result.Notification
+	}
+	var targets []config.TaskNotification
+	for _, target := range task.Notifications {
+		if notifications.Wants(target, e) {
+			targets = append(targets, target)
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+
+	go func() {
+		for _, target := range targets {
+			ctx, cancel := context.WithTimeout(context.Background(), taskNotificationTimeout)
+			if sendErr := s.notifier.Send(ctx, target, e); sendErr != nil {
+				log.Printf("[scheduler] Failed to send %s notification for task %s: %v", target.Type, task.ID, sendErr)
+			}
+			cancel()
+		}
+	}()
+}
+
+// taskLocale returns the locale a task's notifications are written in: the
+// locale of the profile the task runs for, or the instance locale.
+func (s *Service) taskLocale(task config.ScheduledTask) locale.Locale {
+	var display config.DisplaySettings
+	if s.configManager != nil {
+		if settings, err := s.configManager.Load(); err == nil {
+			display = settings.Display
+		}
+	}
+	s.mu.RLock()
+	userSettings := s.userSettings
+	s.mu.RUnlock()
+	if profileID := strings.TrimSpace(task.Config["profileId"]); profileID != "" && userSettings != nil {
+		if settings, err := userSettings.Get(profileID); err == nil && settings != nil {
+			return locale.Profile(settings.Display, display)
+		}
+	}
+	return locale.Instance(display)
+}
@@ -0,0 +1,58 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"novastream/config"
+	"novastream/services/notifications"
+)
+
+type sentNotification struct {
+	target config.TaskNotification
+	event  notifications.Event
+}
+
+type fakeTaskNotifier struct {
+	sent chan sentNotification
+}
+
+func (f *fakeTaskNotifier) Send(_ context.Context, target config.TaskNotification, e notifications.Event) error {
+	f.sent <- sentNotification{target: target, event: e}
+	return nil
+}
+
+func TestNotifyTaskOutcomeSendsToMatchingTargets(t *testing.T) {
+	notifier := &fakeTaskNotifier{sent: make(chan sentNotification, 4)}
+	s := &Service{notifier: notifier}
+	task := config.ScheduledTask{
+		ID:   "task-1",
+		Name: "Trakt import",
+		Type: config.ScheduledTaskTypeTraktListSync,
+		Notifications: []config.TaskNotification{
+			{Type: config.TaskNotificationTypeDiscord, URL: "https://discord.test/hook", OnSuccess: true},
+			{Type: config.TaskNotificationTypeNtfy, URL: "https://ntfy.test/alerts", OnFailure: true},
+		},
+	}
+
+	s.notifyTaskOutcome(task, errors.New("trakt unavailable"), SyncResult{Count: 3})
+
+	select {
+	case got := <-notifier.sent:
+		if got.target.Type != config.TaskNotificationTypeNtfy {
+			t.Fatalf("expected failure target, got %s", got.target.Type)
+		}
+		if got.event.Success || got.event.Error != "trakt unavailable" || got.event.Count != 3 || got.event.TaskName != "Trakt import" {
+			t.Fatalf("unexpected event: %+v", got.event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a notification to be sent")
+	}
+	select {
+	case got := <-notifier.sent:
+		t.Fatalf("unexpected extra notification to %s", got.target.Type)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,24 @@
+package scheduler
+
+import (
+	"fmt"
+
+	"novastream/config"
+)
+
+// offlineTaskTypes are the scheduled tasks that keep running in metadata
+// offline mode. Every other task reaches an external service or looks titles
+// up in the metadata providers, so it pauses until offline mode ends.
+var offlineTaskTypes = map[config.ScheduledTaskType]bool{
+	config.ScheduledTaskTypeBackup: true,
+}
+
+// pausedOffline reports whether offline mode holds task back.
+func pausedOffline(settings config.Settings, task config.ScheduledTask) bool {
+	return settings.Metadata.Offline && !offlineTaskTypes[task.Type]
+}
+
+// offlineTaskError explains why a task can't be run by hand.
+func offlineTaskError(task config.ScheduledTask) error {
+	return fmt.Errorf("%s is paused while offline mode is on: %w", task.Name, ErrOffline)
+}
@@ -0,0 +1,38 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+
+	"novastream/config"
+)
+
+func TestOfflineModePausesUpstreamTasks(t *testing.T) {
+	settings := config.DefaultSettings()
+	sync := config.ScheduledTask{ID: "sync", Name: "Trakt list", Type: config.ScheduledTaskTypeTraktListSync, Enabled: true}
+	backup := config.ScheduledTask{ID: "backup", Name: "Backup", Type: config.ScheduledTaskTypeBackup, Enabled: true}
+
+	if pausedOffline(settings, sync) {
+		t.Fatal("expected tasks to run while online")
+	}
+	settings.Metadata.Offline = true
+	if !pausedOffline(settings, sync) {
+		t.Fatal("expected the sync to pause in offline mode")
+	}
+	if pausedOffline(settings, backup) {
+		t.Fatal("expected backups to keep running in offline mode")
+	}
+
+	mgr := config.NewManager(t.TempDir() + "/settings.json")
+	settings.ScheduledTasks.Tasks = []config.ScheduledTask{sync}
+	if err := mgr.Save(settings); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	svc := NewService(mgr, nil, nil, nil)
+	if err := svc.RunTaskNow("sync"); !errors.Is(err, ErrOffline) {
+		t.Fatalf("expected ErrOffline, got %v", err)
+	}
+	if svc.IsTaskRunning("sync") {
+		t.Fatal("paused task was started")
+	}
+}
@@ -0,0 +1,80 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"novastream/config"
+)
+
+const (
+	// maxTaskRunHistory is how many runs are kept per task.
+	maxTaskRunHistory = 20
+	// maxRunHistoryDryRunItems caps each side of a recorded dry-run diff.
+	maxRunHistoryDryRunItems = 100
+)
+
+// newTaskRun builds the history entry for a run that finished at finishedAt.
+func newTaskRun(startedAt *time.Time, finishedAt time.Time, err error, result SyncResult) config.ScheduledTaskRun {
+	run := config.ScheduledTaskRun{
+		StartedAt:     finishedAt,
+		FinishedAt:    finishedAt,
+		Status:        config.ScheduledTaskStatusSuccess,
+		ItemsImported: result.Count,
+		Message:       result.Message,
+		DryRun:        result.DryRun,
+	}
+	if startedAt != nil && !startedAt.After(finishedAt) {
+		run.StartedAt = *startedAt
+		run.DurationMs = finishedAt.Sub(*startedAt).Milliseconds()
+	}
+	if err != nil {
+		run.Status = config.ScheduledTaskStatusError
+		run.Error = err.Error()
+	}
+	if result.DryRun {
+		run.DryRunDetails = &config.DryRunDetails{
+			ToAdd:    truncateDryRunItems(result.ToAdd),
+			ToRemove: truncateDryRunItems(result.ToRemove),
+		}
+	}
+	return run
+}
+
+func truncateDryRunItems(items []config.DryRunItem) []config.DryRunItem {
+	if len(items) <= maxRunHistoryDryRunItems {
+		return items
+	}
+	return items[:maxRunHistoryDryRunItems]
+}
+
+// appendTaskRun records run as the task's newest history entry, dropping the
+// oldest beyond maxTaskRunHistory.
+func appendTaskRun(task *config.ScheduledTask, run config.ScheduledTaskRun) {
+	history := make([]config.ScheduledTaskRun, 0, min(len(task.RunHistory)+1, maxTaskRunHistory))
+	history = append(history, run)
+	for _, prev := range task.RunHistory {
+		if len(history) >= maxTaskRunHistory {
+			break
+		}
+		history = append(history, prev)
+	}
+	task.RunHistory = history
+}
+
+// GetTaskRunHistory returns a task's recent runs, newest first.
+func (s *Service) GetTaskRunHistory(taskID string) ([]config.ScheduledTaskRun, error) {
+	settings, err := s.configManager.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load settings: %w", err)
+	}
+	for _, task := range settings.ScheduledTasks.Tasks {
+		if task.ID == taskID {
+			if task.RunHistory == nil {
+				return []config.ScheduledTaskRun{}, nil
+			}
+			return task.RunHistory, nil
+		}
+	}
+	return nil, fmt.Errorf("task %w", ErrNotFound)
+}
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"novastream/config"
+)
+
+func TestUpdateTaskStatusRecordsRunHistory(t *testing.T) {
+	mgr := config.NewManager(t.TempDir() + "/settings.json")
+	settings := config.DefaultSettings()
+	settings.ScheduledTasks.Tasks = []config.ScheduledTask{{
+		ID:        "task-1",
+		Type:      config.ScheduledTaskTypeTraktListSync,
+		Enabled:   true,
+		Frequency: config.ScheduledTaskFrequencyHourly,
+	}}
+	if err := mgr.Save(settings); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	svc := NewService(mgr, nil, nil, nil)
+
+	svc.markTaskStarted("task-1")
+	svc.updateTaskStatus("task-1", errors.New("trakt unavailable"), SyncResult{})
+
+	toAdd := make([]config.DryRunItem, maxRunHistoryDryRunItems+5)
+	for i := range toAdd {
+		toAdd[i] = config.DryRunItem{Name: fmt.Sprintf("Movie %d", i), MediaType: "movie"}
+	}
+	svc.markTaskStarted("task-1")
+	svc.updateTaskStatus("task-1", nil, SyncResult{Count: 7, DryRun: true, ToAdd: toAdd})
+
+	runs, err := svc.GetTaskRunHistory("task-1")
+	if err != nil {
+		t.Fatalf("GetTaskRunHistory() error = %v", err)
+	}
+	if len(runs) != 2 {
+		t.Fatalf("expected 2 runs, got %d", len(runs))
+	}
+	latest, failed := runs[0], runs[1]
+	if latest.Status != config.ScheduledTaskStatusSuccess || latest.ItemsImported != 7 || !latest.DryRun {
+		t.Fatalf("unexpected latest run: %+v", latest)
+	}
+	if latest.DryRunDetails == nil || len(latest.DryRunDetails.ToAdd) != maxRunHistoryDryRunItems {
+		t.Fatalf("expected dry-run diff truncated to %d items, got %+v", maxRunHistoryDryRunItems, latest.DryRunDetails)
+	}
+	if failed.Status != config.ScheduledTaskStatusError || failed.Error != "trakt unavailable" {
+		t.Fatalf("unexpected failed run: %+v", failed)
+	}
+	if failed.StartedAt.After(failed.FinishedAt) || failed.DurationMs < 0 {
+		t.Fatalf("unexpected run timing: %+v", failed)
+	}
+
+	if tasks := svc.GetTaskStatus(); len(tasks) != 1 || tasks[0].RunHistory != nil {
+		t.Fatalf("expected task list without run history, got %+v", tasks)
+	}
+	if _, err := svc.GetTaskRunHistory("missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for unknown task, got %v", err)
+	}
+}
+
+func TestAppendTaskRunKeepsNewestRuns(t *testing.T) {
+	var task config.ScheduledTask
+	base := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < maxTaskRunHistory+3; i++ {
+		appendTaskRun(&task, config.ScheduledTaskRun{FinishedAt: base.Add(time.Duration(i) * time.Hour)})
+	}
+	if len(task.RunHistory) != maxTaskRunHistory {
+		t.Fatalf("expected %d runs, got %d", maxTaskRunHistory, len(task.RunHistory))
+	}
+	if want := base.Add(time.Duration(maxTaskRunHistory+2) * time.Hour); !task.RunHistory[0].FinishedAt.Equal(want) {
+		t.Fatalf("newest run = %v, want %v", task.RunHistory[0].FinishedAt, want)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"novastream/services/history"
 	"novastream/services/jellyfin"
 	"novastream/services/localmedia"
+	"novastream/services/notifications"
 	"novastream/services/plex"
 	"novastream/services/prewarm"
 	"novastream/services/simkl"
@@ -29,6 +30,28 @@ import (
 	"novastream/services/watchlist"
 )
 
+// Error kinds returned by the scheduler. Callers should match them with
+// errors.Is; the concrete errors keep their descriptive messages.
+var (
+	ErrNotFound            = errors.New("not found")
+	ErrNotConfigured       = errors.New("not configured")
+	ErrUpstreamUnavailable = errors.New("upstream unavailable")
+	ErrTaskRunning         = errors.New("task is already running")
+	// ErrOffline is returned for tasks that offline mode pauses.
+	ErrOffline = errors.New("offline mode")
+)
+
+// upstreamErr marks a failed call to an external service (Plex, Trakt, ...)
+// without changing its message.
+type upstreamErr struct{ err error }
+
+func (e upstreamErr) Error() string   { return e.err.Error() }
+func (e upstreamErr) Unwrap() []error { return []error{ErrUpstreamUnavailable, e.err} }
+
+func upstreamUnavailable(err error) error {
+	return upstreamErr{err: err}
+}
+
 // Service manages scheduled task execution
 type Service struct {
 	configManager      *config.Manager
@@ -45,6 +68,13 @@ type Service struct {
 	prewarmService     *prewarm.Service
 	localMediaService  localMediaScanner
 	livePlaylistWarmer livePlaylistWarmer
+	notifier           taskNotifier
+	mailer             digestMailer
+	userSettings       schedulerUserSettings
+	calendar           digestCalendar
+	watchProviders     digestWatchProviders
+	leavingSoon        leavingSoonSource
+	watchTime          watchTimeSource
 
 	// Runtime state
 	mu      sync.RWMutex
@@ -58,6 +88,10 @@ type Service struct {
 	taskMu              sync.RWMutex
 	lastFullSyncTimes   map[string]time.Time // tracks last full Trakt history sync per task ID
 	lastFullSyncTimesMu sync.Mutex
+
+	// taskStateMu serializes the scheduler's load-modify-save cycles on
+	// persisted task state so concurrent tasks don't overwrite each other.
+	taskStateMu sync.Mutex
 }
 
 type schedulerUsersProvider interface {
@@ -65,6 +99,12 @@ type schedulerUsersProvider interface {
 	ListAll() []models.User
 }
 
+// schedulerUserSettings reads profile settings, e.g. the locale task
+// notifications are written in.
+type schedulerUserSettings interface {
+	Get(userID string) (*models.UserSettings, error)
+}
+
 type localMediaScanner interface {
 	ListLibraries(ctx context.Context) ([]models.LocalMediaLibrary, error)
 	StartScan(ctx context.Context, libraryID string) (models.LocalMediaScanSummary, error)
@@ -90,6 +130,12 @@ type SyncResult struct {
 	ToRemove []config.DryRunItem
 	Message  string // Optional message for display
 	Config   map[string]string
+	// Notification replaces the task outcome notification for a successful
+	// run, e.g. with an alert listing what the run found.
+	Notification *notifications.Event
+	// Quiet skips success notifications when the run found nothing worth
+	// sending.
+	Quiet bool
 }
 
 type traktHistoryState struct {
@@ -98,6 +144,11 @@ type traktHistoryState struct {
 
 const (
 	traktPlaybackWatchedThreshold = 90.0
+
+	// maxSyncWriteKeys bounds the idempotency keys kept for an unfinished run.
+	maxSyncWriteKeys = 5000
+
+	interruptedTaskError = "interrupted by restart; resuming"
 )
 
 // NewService creates a new scheduler service
@@ -107,11 +158,17 @@ func NewService(
 	traktClient *trakt.Client,
 	watchlistService *watchlist.Service,
 ) *Service {
+	sender := notifications.NewSender()
+	if configManager != nil {
+		sender.SetSettingsSource(configManager)
+	}
 	return &Service{
 		configManager:     configManager,
 		plexClient:        plexClient,
 		traktClient:       traktClient,
 		watchlistService:  watchlistService,
+		notifier:          sender,
+		mailer:            sender,
 		taskRunning:       make(map[string]bool),
 		lastFullSyncTimes: make(map[string]time.Time),
 	}
@@ -129,6 +186,10 @@ func (s *Service) Start(ctx context.Context) error {
 	s.ctx, s.cancel = context.WithCancel(ctx)
 	s.running = true
 
+	// Recover tasks whose previous run was cut short before the first check
+	// so their markers aren't mistaken for runs in progress.
+	s.recoverInterruptedTasks()
+
 	// Start the main scheduler loop
 	s.wg.Add(1)
 	go s.schedulerLoop()
@@ -212,12 +273,13 @@ func (s *Service) checkAndRunTasks() {
 		return
 	}
 
+	now := time.Now()
 	for _, task := range settings.ScheduledTasks.Tasks {
-		if !task.Enabled {
+		if !task.Enabled || pausedOffline(settings, task) {
 			continue
 		}
 
-		if s.shouldRun(task) {
+		if s.shouldRun(task) && !s.deferToMaintenanceWindow(task, settings.ScheduledTasks.MaintenanceWindow, now) {
 			// Run task in goroutine to not block other tasks
 			s.wg.Add(1)
 			go func(t config.ScheduledTask) {
@@ -243,6 +305,13 @@ func (s *Service) shouldRun(task config.ScheduledTask) bool {
 		return false
 	}
 
+	// Cron tasks run at fixed wall-clock times rather than drifting with
+	// LastRunAt; a slot missed while the server was down runs once on start.
+	if task.Frequency == config.ScheduledTaskFrequencyCron {
+		next, ok := NextCronRun(task)
+		return ok && !time.Now().Before(next)
+	}
+
 	// Never run before
 	if task.LastRunAt == nil {
 		return true
@@ -275,6 +344,8 @@ func (s *Service) getInterval(taskType config.ScheduledTaskType, freq config.Sch
 		return 12 * time.Hour
 	case config.ScheduledTaskFrequencyDaily:
 		return 24 * time.Hour
+	case config.ScheduledTaskFrequencyWeekly:
+		return 7 * 24 * time.Hour
 	case config.ScheduledTaskFrequencyOnce:
 		return time.Duration(math.MaxInt64)
 	default:
@@ -307,6 +378,10 @@ func (s *Service) executeTask(task config.ScheduledTask) {
 
 	log.Printf("[scheduler] Executing task: %s (%s)", task.Name, task.Type)
 
+	// Persist the start marker before doing any work so a restart mid-run
+	// is detected instead of silently re-running from scratch.
+	s.markTaskStarted(task.ID)
+
 	var err error
 	var result SyncResult
 
@@ -327,6 +402,8 @@ func (s *Service) executeTask(task config.ScheduledTask) {
 		result, err = s.executeTraktHistorySync(task)
 	case config.ScheduledTaskTypeSimklHistorySync:
 		result, err = s.executeSimklHistorySync(task)
+	case config.ScheduledTaskTypeSimklWatchlistSync:
+		result, err = s.executeSimklWatchlistSync(task)
 	case config.ScheduledTaskTypePrewarm:
 		result, err = s.executePrewarm(task)
 	case config.ScheduledTaskTypePlexHistorySync:
@@ -335,17 +412,29 @@ func (s *Service) executeTask(task config.ScheduledTask) {
 		result, err = s.executeJellyfinFavoritesSync(task)
 	case config.ScheduledTaskTypeJellyfinHistorySync:
 		result, err = s.executeJellyfinHistorySync(task)
+	case config.ScheduledTaskTypeJellyfinSync:
+		result, err = s.executeJellyfinSync(task)
 	case config.ScheduledTaskTypeMDBListWatchlistSync:
 		result, err = s.executeMDBListWatchlistSync(task)
 	case config.ScheduledTaskTypeMDBListHistorySync:
 		result, err = s.executeMDBListHistorySync(task)
+	case config.ScheduledTaskTypeEmailDigest:
+		result, err = s.executeEmailDigest(task)
+	case config.ScheduledTaskTypeTVDBReidentify:
+		result, err = s.executeTVDBReidentify(task)
+	case config.ScheduledTaskTypeLeavingSoon:
+		result, err = s.executeLeavingSoon(task)
+	case config.ScheduledTaskTypeWatchTime:
+		result, err = s.executeWatchTime(task)
 	default:
 		log.Printf("[scheduler] Unknown task type: %s", task.Type)
+		s.updateTaskState(task.ID, func(t *config.ScheduledTask) { t.StartedAt = nil })
 		return
 	}
 
 	// Update task status in settings
 	s.updateTaskStatus(task.ID, err, result)
+	s.notifyTaskOutcome(task, err, result)
 
 	// After a successful watchlist sync, enrich items that were imported without
 	// artwork. External sources (Plex/Trakt/MDBList/Jellyfin) provide only IDs,
@@ -363,7 +452,9 @@ func isWatchlistSyncTask(t config.ScheduledTaskType) bool {
 	case config.ScheduledTaskTypePlexWatchlistSync,
 		config.ScheduledTaskTypeTraktListSync,
 		config.ScheduledTaskTypeMDBListWatchlistSync,
-		config.ScheduledTaskTypeJellyfinFavoritesSync:
+		config.ScheduledTaskTypeJellyfinFavoritesSync,
+		config.ScheduledTaskTypeJellyfinSync,
+		config.ScheduledTaskTypeSimklWatchlistSync:
 		return true
 	default:
 		return false
@@ -390,7 +481,7 @@ func (s *Service) enrichSyncedWatchlistArtwork(task config.ScheduledTask) {
 
 func (s *Service) executeLocalMediaScan(task config.ScheduledTask) (SyncResult, error) {
 	if s.localMediaService == nil {
-		return SyncResult{}, errors.New("local media service not configured")
+		return SyncResult{}, fmt.Errorf("local media service %w", ErrNotConfigured)
 	}
 	libraryID := strings.TrimSpace(task.Config["libraryId"])
 	if libraryID == "" {
@@ -446,6 +537,9 @@ func (s *Service) executeLocalMediaScan(task config.ScheduledTask) (SyncResult,
 
 // updateTaskStatus updates a task's status in the settings file
 func (s *Service) updateTaskStatus(taskID string, err error, result SyncResult) {
+	s.taskStateMu.Lock()
+	defer s.taskStateMu.Unlock()
+
 	settings, loadErr := s.configManager.Load()
 	if loadErr != nil {
 		log.Printf("[scheduler] Failed to load settings to update task status: %v", loadErr)
@@ -455,7 +549,9 @@ func (s *Service) updateTaskStatus(taskID string, err error, result SyncResult)
 	now := time.Now().UTC()
 	for i := range settings.ScheduledTasks.Tasks {
 		if settings.ScheduledTasks.Tasks[i].ID == taskID {
+			appendTaskRun(&settings.ScheduledTasks.Tasks[i], newTaskRun(settings.ScheduledTasks.Tasks[i].StartedAt, now, err, result))
 			settings.ScheduledTasks.Tasks[i].LastRunAt = &now
+			settings.ScheduledTasks.Tasks[i].StartedAt = nil
 			settings.ScheduledTasks.Tasks[i].ItemsImported = result.Count
 			if result.Config != nil {
 				if settings.ScheduledTasks.Tasks[i].Config == nil {
@@ -484,6 +580,8 @@ func (s *Service) updateTaskStatus(taskID string, err error, result SyncResult)
 			} else {
 				settings.ScheduledTasks.Tasks[i].LastStatus = config.ScheduledTaskStatusSuccess
 				settings.ScheduledTasks.Tasks[i].LastError = ""
+				// The run finished, so its writes no longer need guarding.
+				settings.ScheduledTasks.Tasks[i].SyncWriteKeys = nil
 				if result.DryRun {
 					log.Printf("[scheduler] Task %s dry run completed: %d items to add, %d items to remove", taskID, len(result.ToAdd), len(result.ToRemove))
 				} else {
@@ -505,6 +603,136 @@ func (s *Service) updateTaskStatus(taskID string, err error, result SyncResult)
 	}
 }
 
+// updateTaskState applies fn to the persisted task and saves the settings.
+func (s *Service) updateTaskState(taskID string, fn func(*config.ScheduledTask)) {
+	if s.configManager == nil || taskID == "" {
+		return
+	}
+	s.taskStateMu.Lock()
+	defer s.taskStateMu.Unlock()
+
+	settings, err := s.configManager.Load()
+	if err != nil {
+		log.Printf("[scheduler] Failed to load settings to update task %s: %v", taskID, err)
+		return
+	}
+	for i := range settings.ScheduledTasks.Tasks {
+		if settings.ScheduledTasks.Tasks[i].ID == taskID {
+			fn(&settings.ScheduledTasks.Tasks[i])
+			if err := s.configManager.Save(settings); err != nil {
+				log.Printf("[scheduler] Failed to save task %s: %v", taskID, err)
+			}
+			return
+		}
+	}
+}
+
+// markTaskStarted persists the "started at" marker for a run.
+func (s *Service) markTaskStarted(taskID string) {
+	now := time.Now().UTC()
+	s.updateTaskState(taskID, func(t *config.ScheduledTask) { t.StartedAt = &now })
+}
+
+// recoverInterruptedTasks clears start markers left behind by a run that the
+// process never finished. Recurring tasks keep their previous LastRunAt, so
+// they resume at the next check and cover the same window again; the sync
+// write keys recorded by the interrupted run make those writes skip items
+// that were already sent. One-time tasks have no LastRunAt yet and resume
+// the same way.
+func (s *Service) recoverInterruptedTasks() {
+	if s.configManager == nil {
+		return
+	}
+	s.taskStateMu.Lock()
+	defer s.taskStateMu.Unlock()
+
+	settings, err := s.configManager.Load()
+	if err != nil {
+		log.Printf("[scheduler] Failed to load settings to recover tasks: %v", err)
+		return
+	}
+	recovered := 0
+	for i := range settings.ScheduledTasks.Tasks {
+		task := &settings.ScheduledTasks.Tasks[i]
+		if task.StartedAt == nil {
+			continue
+		}
+		log.Printf("[scheduler] Task %s was interrupted (started %s, %d writes recorded)",
+			task.ID, task.StartedAt.Format(time.RFC3339), len(task.SyncWriteKeys))
+		appendTaskRun(task, newTaskRun(task.StartedAt, time.Now().UTC(), errors.New(interruptedTaskError), SyncResult{}))
+		task.StartedAt = nil
+		task.LastStatus = config.ScheduledTaskStatusError
+		task.LastError = interruptedTaskError
+		recovered++
+	}
+	if recovered == 0 {
+		return
+	}
+	if err := s.configManager.Save(settings); err != nil {
+		log.Printf("[scheduler] Failed to save recovered tasks: %v", err)
+	}
+}
+
+// recordSyncWrites persists idempotency keys for writes that reached the
+// remote service, so a resumed run doesn't repeat them.
+func (s *Service) recordSyncWrites(taskID string, keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+	s.updateTaskState(taskID, func(t *config.ScheduledTask) {
+		t.SyncWriteKeys = append(t.SyncWriteKeys, keys...)
+		if over := len(t.SyncWriteKeys) - maxSyncWriteKeys; over > 0 {
+			t.SyncWriteKeys = t.SyncWriteKeys[over:]
+		}
+	})
+}
+
+// filterTraktHistoryWrites drops movies and episodes that an interrupted run
+// of task already added to Trakt history, and returns the idempotency keys
+// of the items that remain.
+func filterTraktHistoryWrites(task config.ScheduledTask, req trakt.SyncHistoryRequest) (trakt.SyncHistoryRequest, []string) {
+	done := make(map[string]bool, len(task.SyncWriteKeys))
+	for _, key := range task.SyncWriteKeys {
+		done[key] = true
+	}
+
+	var filtered trakt.SyncHistoryRequest
+	var keys []string
+	for _, movie := range req.Movies {
+		key := "trakt-history:movie:" + syncIDsKey(movie.IDs) + "@" + movie.WatchedAt
+		if done[key] {
+			continue
+		}
+		filtered.Movies = append(filtered.Movies, movie)
+		keys = append(keys, key)
+	}
+	for _, show := range req.Shows {
+		kept := trakt.SyncShow{IDs: show.IDs}
+		for _, season := range show.Seasons {
+			keptSeason := trakt.SyncSeason{Number: season.Number}
+			for _, ep := range season.Episodes {
+				key := fmt.Sprintf("trakt-history:episode:%s:%d:%d@%s", syncIDsKey(show.IDs), season.Number, ep.Number, ep.WatchedAt)
+				if done[key] {
+					continue
+				}
+				keptSeason.Episodes = append(keptSeason.Episodes, ep)
+				keys = append(keys, key)
+			}
+			if len(keptSeason.Episodes) > 0 {
+				kept.Seasons = append(kept.Seasons, keptSeason)
+			}
+		}
+		if len(kept.Seasons) > 0 {
+			filtered.Shows = append(filtered.Shows, kept)
+		}
+	}
+	return filtered, keys
+}
+
+func syncIDsKey(ids trakt.SyncIDs) string {
+	return fmt.Sprintf("%d/%s/%d/%d", ids.Trakt, ids.IMDB, ids.TMDB, ids.TVDB)
+}
+
 // RunTaskNow triggers immediate execution of a task
 func (s *Service) RunTaskNow(taskID string) error {
 	settings, err := s.configManager.Load()
@@ -514,11 +742,15 @@ func (s *Service) RunTaskNow(taskID string) error {
 
 	for _, task := range settings.ScheduledTasks.Tasks {
 		if task.ID == taskID {
+			if pausedOffline(settings, task) {
+				return offlineTaskError(task)
+			}
+
 			// Check if already running
 			s.taskMu.RLock()
 			if s.taskRunning[taskID] {
 				s.taskMu.RUnlock()
-				return errors.New("task is already running")
+				return ErrTaskRunning
 			}
 			s.taskMu.RUnlock()
 
@@ -531,7 +763,7 @@ func (s *Service) RunTaskNow(taskID string) error {
 		}
 	}
 
-	return errors.New("task not found")
+	return fmt.Errorf("task %w", ErrNotFound)
 }
 
 // GetTaskStatus returns all tasks with their current status
@@ -548,6 +780,8 @@ func (s *Service) GetTaskStatus() []config.ScheduledTask {
 	tasks := make([]config.ScheduledTask, len(settings.ScheduledTasks.Tasks))
 	for i, task := range settings.ScheduledTasks.Tasks {
 		tasks[i] = task
+		// Run history is served separately by GetTaskRunHistory.
+		tasks[i].RunHistory = nil
 		if s.taskRunning[task.ID] {
 			tasks[i].LastStatus = config.ScheduledTaskStatusRunning
 		}
@@ -619,6 +853,14 @@ func (s *Service) SetUsersService(usersService schedulerUsersProvider) {
 	s.usersService = usersService
 }
 
+// SetUserSettingsService sets the profile settings used to localize task
+// notifications.
+func (s *Service) SetUserSettingsService(userSettings schedulerUserSettings) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.userSettings = userSettings
+}
+
 func (s *Service) resolveTaskProfileID(task config.ScheduledTask) (string, error) {
 	profileID := strings.TrimSpace(task.Config["profileId"])
 	if profileID == "" {
@@ -641,7 +883,7 @@ func (s *Service) resolveProfileID(profileID string) (string, error) {
 	}
 
 	if profileID != models.DefaultUserID {
-		return "", fmt.Errorf("profile %q not found", profileID)
+		return "", fmt.Errorf("profile %q %w", profileID, ErrNotFound)
 	}
 
 	users := usersService.ListAll()
@@ -707,7 +949,7 @@ func (s *Service) executePlexWatchlistSync(task config.ScheduledTask) (SyncResul
 
 	plexAccount := settings.Plex.GetAccountByID(plexAccountID)
 	if plexAccount == nil {
-		return SyncResult{}, errors.New("plex account not found")
+		return SyncResult{}, fmt.Errorf("plex account %w", ErrNotFound)
 	}
 
 	if plexAccount.AuthToken == "" {
@@ -759,7 +1001,7 @@ func (s *Service) syncPlexToLocal(authToken, profileID, syncSource, deleteBehavi
 	// Fetch watchlist from Plex
 	items, err := s.plexClient.GetWatchlist(authToken)
 	if err != nil {
-		return result, fmt.Errorf("fetch watchlist: %w", err)
+		return result, fmt.Errorf("fetch watchlist: %w", upstreamUnavailable(err))
 	}
 
 	// Build a set of Plex item keys for deletion checking
@@ -902,7 +1144,7 @@ func (s *Service) syncLocalToPlex(authToken, profileID, syncSource, deleteBehavi
 	// Get current Plex watchlist to check what's already there
 	plexItems, err := s.plexClient.GetWatchlist(authToken)
 	if err != nil {
-		return result, fmt.Errorf("fetch plex watchlist: %w", err)
+		return result, fmt.Errorf("fetch plex watchlist: %w", upstreamUnavailable(err))
 	}
 
 	// Build set of Plex ratingKeys for quick lookup
@@ -1018,7 +1260,7 @@ func (s *Service) syncBidirectional(authToken, profileID, syncSource, deleteBeha
 	// Get both watchlists
 	plexItems, err := s.plexClient.GetWatchlist(authToken)
 	if err != nil {
-		return result, fmt.Errorf("fetch plex watchlist: %w", err)
+		return result, fmt.Errorf("fetch plex watchlist: %w", upstreamUnavailable(err))
 	}
 
 	localItems, err := s.watchlistService.List(profileID)
@@ -1223,7 +1465,7 @@ func (s *Service) executeTraktListSync(task config.ScheduledTask) (SyncResult, e
 
 	traktAccount := settings.Trakt.GetAccountByID(traktAccountID)
 	if traktAccount == nil {
-		return SyncResult{}, errors.New("trakt account not found")
+		return SyncResult{}, fmt.Errorf("trakt account %w", ErrNotFound)
 	}
 
 	if traktAccount.AccessToken == "" {
@@ -1271,7 +1513,7 @@ func (s *Service) getTraktListItems(accessToken string, listType string, customL
 	case "watchlist":
 		watchlistItems, err := s.traktClient.GetAllWatchlist(accessToken)
 		if err != nil {
-			return nil, fmt.Errorf("get trakt watchlist: %w", err)
+			return nil, fmt.Errorf("get trakt watchlist: %w", upstreamUnavailable(err))
 		}
 		for _, item := range watchlistItems {
 			if item.Movie != nil {
@@ -1294,7 +1536,7 @@ func (s *Service) getTraktListItems(accessToken string, listType string, customL
 	case "collection":
 		collectionItems, err := s.traktClient.GetAllCollection(accessToken)
 		if err != nil {
-			return nil, fmt.Errorf("get trakt collection: %w", err)
+			return nil, fmt.Errorf("get trakt collection: %w", upstreamUnavailable(err))
 		}
 		for _, item := range collectionItems {
 			if item.Movie != nil {
@@ -1317,7 +1559,7 @@ func (s *Service) getTraktListItems(accessToken string, listType string, customL
 	case "favorites":
 		favoriteItems, err := s.traktClient.GetAllFavorites(accessToken)
 		if err != nil {
-			return nil, fmt.Errorf("get trakt favorites: %w", err)
+			return nil, fmt.Errorf("get trakt favorites: %w", upstreamUnavailable(err))
 		}
 		for _, item := range favoriteItems {
 			if item.Movie != nil {
@@ -1343,7 +1585,7 @@ func (s *Service) getTraktListItems(accessToken string, listType string, customL
 		}
 		listItems, err := s.traktClient.GetAllListItems(accessToken, customListID)
 		if err != nil {
-			return nil, fmt.Errorf("get trakt custom list: %w", err)
+			return nil, fmt.Errorf("get trakt custom list: %w", upstreamUnavailable(err))
 		}
 		for _, item := range listItems {
 			if item.Movie != nil {
@@ -1504,7 +1746,7 @@ func (s *Service) syncLocalToTrakt(traktAccount *config.TraktAccount, profileID,
 	// Get current Trakt watchlist to check what's already there
 	traktItems, err := s.traktClient.GetAllWatchlist(traktAccount.AccessToken)
 	if err != nil {
-		return result, fmt.Errorf("fetch trakt watchlist: %w", err)
+		return result, fmt.Errorf("fetch trakt watchlist: %w", upstreamUnavailable(err))
 	}
 
 	// Build set of Trakt item keys for quick lookup
@@ -1880,7 +2122,7 @@ func (s *Service) executeEPGRefresh(task config.ScheduledTask) (SyncResult, erro
 	s.mu.RUnlock()
 
 	if epgSvc == nil {
-		return SyncResult{}, errors.New("EPG service not configured")
+		return SyncResult{}, fmt.Errorf("EPG service %w", ErrNotConfigured)
 	}
 
 	// Create a context with timeout for the refresh
@@ -1960,7 +2202,7 @@ func (s *Service) executeBackup(task config.ScheduledTask) (SyncResult, error) {
 	s.mu.RUnlock()
 
 	if backupSvc == nil {
-		return SyncResult{}, errors.New("backup service not configured")
+		return SyncResult{}, fmt.Errorf("backup service %w", ErrNotConfigured)
 	}
 
 	// Update global retention settings from task config if present
@@ -2009,7 +2251,7 @@ func (s *Service) executeTraktHistorySync(task config.ScheduledTask) (SyncResult
 	s.mu.RUnlock()
 
 	if historySvc == nil {
-		return SyncResult{}, errors.New("history service not configured")
+		return SyncResult{}, fmt.Errorf("history service %w", ErrNotConfigured)
 	}
 
 	traktAccountID := task.Config["traktAccountId"]
@@ -2040,7 +2282,7 @@ func (s *Service) executeTraktHistorySync(task config.ScheduledTask) (SyncResult
 
 	traktAccount := settings.Trakt.GetAccountByID(traktAccountID)
 	if traktAccount == nil {
-		return SyncResult{}, errors.New("trakt account not found")
+		return SyncResult{}, fmt.Errorf("trakt account %w", ErrNotFound)
 	}
 
 	if traktAccount.AccessToken == "" {
@@ -2120,7 +2362,7 @@ func (s *Service) syncTraktHistoryToLocal(task config.ScheduledTask, traktAccoun
 
 	items, err := s.traktClient.GetWatchHistorySince(traktAccount.AccessToken, since)
 	if err != nil {
-		return result, fmt.Errorf("fetch trakt history: %w", err)
+		return result, fmt.Errorf("fetch trakt history: %w", upstreamUnavailable(err))
 	}
 
 	log.Printf("[scheduler] Fetched %d Trakt history items", len(items))
@@ -2212,7 +2454,7 @@ func (s *Service) syncLocalHistoryToTrakt(task config.ScheduledTask, traktAccoun
 	// Trakt's AddToHistory creates a NEW event each call — it is not idempotent.
 	traktItems, err := s.traktClient.GetWatchHistorySince(traktAccount.AccessToken, since)
 	if err != nil {
-		return result, fmt.Errorf("fetch trakt history for dedup: %w", err)
+		return result, fmt.Errorf("fetch trakt history for dedup: %w", upstreamUnavailable(err))
 	}
 
 	hasRecentLocalUnwatch := false
@@ -2233,7 +2475,7 @@ func (s *Service) syncLocalHistoryToTrakt(task config.ScheduledTask, traktAccoun
 	if hasRecentLocalUnwatch && !since.IsZero() {
 		deletionTraktItems, err = s.traktClient.GetWatchHistorySince(traktAccount.AccessToken, time.Time{})
 		if err != nil {
-			return result, fmt.Errorf("fetch full trakt history for unwatch sync: %w", err)
+			return result, fmt.Errorf("fetch full trakt history for unwatch sync: %w", upstreamUnavailable(err))
 		}
 	}
 
@@ -2489,15 +2731,21 @@ func (s *Service) syncLocalHistoryToTrakt(task config.ScheduledTask, traktAccoun
 
 	shows := buildShows(showEpisodes, showIDs)
 
-	if len(movies) > 0 || len(shows) > 0 {
-		syncReq := trakt.SyncHistoryRequest{
-			Movies: movies,
-			Shows:  shows,
-		}
+	syncReq, writeKeys := filterTraktHistoryWrites(task, trakt.SyncHistoryRequest{
+		Movies: movies,
+		Shows:  shows,
+	})
+	if alreadySent := len(movies) + expectedEpisodes - len(writeKeys); alreadySent > 0 {
+		log.Printf("[scheduler] Skipping %d items already sent to Trakt by an interrupted run", alreadySent)
+	}
+	expectedEpisodes = len(writeKeys) - len(syncReq.Movies)
+
+	if len(syncReq.Movies) > 0 || len(syncReq.Shows) > 0 {
 		resp, err := s.traktClient.AddToHistory(traktAccount.AccessToken, syncReq)
 		if err != nil {
 			return result, fmt.Errorf("add to trakt history: %w", err)
 		}
+		s.recordSyncWrites(task.ID, writeKeys)
 		log.Printf("[scheduler] Synced to Trakt: %d movies, %d episodes added", resp.Added.Movies, resp.Added.Episodes)
 
 		if resp.Added.Episodes < expectedEpisodes && len(absoluteShowEpisodes) > 0 {
@@ -2573,45 +2821,64 @@ func (s *Service) syncHistoryBidirectional(task config.ScheduledTask, traktAccou
 	return combined, nil
 }
 
-// executeSimklHistorySync imports watch history from Simkl into local history.
+// executeSimklHistorySync syncs watch history between Simkl and local history.
 func (s *Service) executeSimklHistorySync(task config.ScheduledTask) (SyncResult, error) {
 	s.mu.RLock()
 	historySvc := s.historyService
-	simklClient := s.simklClient
 	s.mu.RUnlock()
 
 	if historySvc == nil {
-		return SyncResult{}, errors.New("history service not configured")
-	}
-	if simklClient == nil {
-		return SyncResult{}, errors.New("simkl client not configured")
+		return SyncResult{}, fmt.Errorf("history service %w", ErrNotConfigured)
 	}
 
-	simklAccountID := task.Config["simklAccountId"]
-	profileID, err := s.resolveTaskProfileID(task)
-	if simklAccountID == "" || profileID == "" {
-		return SyncResult{}, errors.New("missing simklAccountId or profileId in task config")
-	}
+	simklClient, simklAccount, profileID, err := s.simklTaskAccount(task)
 	if err != nil {
 		return SyncResult{}, err
 	}
-	dryRun := task.Config["dryRun"] == "true"
 
-	settings, err := s.configManager.Load()
-	if err != nil {
-		return SyncResult{}, fmt.Errorf("load settings: %w", err)
-	}
-	simklAccount := settings.Simkl.GetAccountByID(simklAccountID)
-	if simklAccount == nil {
-		return SyncResult{}, errors.New("simkl account not found")
+	syncDirection := task.Config["syncDirection"]
+	if syncDirection == "" {
+		syncDirection = "simkl_to_local"
 	}
-	if simklAccount.ClientID == "" || simklAccount.AccessToken == "" {
-		return SyncResult{}, errors.New("simkl account not authenticated")
+	dryRun := task.Config["dryRun"] == "true"
+
+	switch syncDirection {
+	case "simkl_to_local":
+		return s.syncSimklHistoryToLocal(task, simklClient, simklAccount, profileID, dryRun)
+	case "local_to_simkl":
+		return s.syncLocalHistoryToSimkl(task, simklClient, simklAccount, profileID, dryRun)
+	case "bidirectional":
+		// Export first so items imported in this run are not pushed straight back.
+		exportResult, err := s.syncLocalHistoryToSimkl(task, simklClient, simklAccount, profileID, dryRun)
+		if err != nil {
+			return exportResult, err
+		}
+		importResult, err := s.syncSimklHistoryToLocal(task, simklClient, simklAccount, profileID, dryRun)
+		if err != nil {
+			return importResult, err
+		}
+		return SyncResult{
+			Count:    importResult.Count + exportResult.Count,
+			DryRun:   dryRun,
+			ToAdd:    append(exportResult.ToAdd, importResult.ToAdd...),
+			ToRemove: exportResult.ToRemove,
+			Config:   importResult.Config,
+		}, nil
+	default:
+		return SyncResult{}, fmt.Errorf("unknown sync direction: %s", syncDirection)
 	}
+}
+
+// syncSimklHistoryToLocal imports watch history from Simkl, fetching only the
+// changes since the last recorded Simkl activity.
+func (s *Service) syncSimklHistoryToLocal(task config.ScheduledTask, simklClient *simkl.Client, simklAccount *config.SimklAccount, profileID string, dryRun bool) (SyncResult, error) {
+	s.mu.RLock()
+	historySvc := s.historyService
+	s.mu.RUnlock()
 
 	activities, err := simklClient.GetActivities(simklAccount.ClientID, simklAccount.AccessToken)
 	if err != nil {
-		return SyncResult{}, fmt.Errorf("fetch simkl activities: %w", err)
+		return SyncResult{}, fmt.Errorf("fetch simkl activities: %w", upstreamUnavailable(err))
 	}
 	latestActivity := latestTimeInSimklActivity(activities)
 	savedActivity := strings.TrimSpace(task.Config["lastSimklActivityAt"])
@@ -2629,7 +2896,7 @@ func (s *Service) executeSimklHistorySync(task config.ScheduledTask) (SyncResult
 		for _, bucket := range []string{"movies", "shows", "anime"} {
 			resp, err := simklClient.GetInitialSyncItems(simklAccount.ClientID, simklAccount.AccessToken, bucket)
 			if err != nil {
-				return SyncResult{DryRun: dryRun}, fmt.Errorf("fetch simkl %s history: %w", bucket, err)
+				return SyncResult{DryRun: dryRun}, fmt.Errorf("fetch simkl %s history: %w", bucket, upstreamUnavailable(err))
 			}
 			responses = append(responses, resp)
 		}
@@ -2637,7 +2904,7 @@ func (s *Service) executeSimklHistorySync(task config.ScheduledTask) (SyncResult
 		log.Printf("[scheduler] Fetching Simkl history delta since %s", savedActivity)
 		resp, err := simklClient.GetAllItemsSince(simklAccount.ClientID, simklAccount.AccessToken, savedActivity)
 		if err != nil {
-			return SyncResult{DryRun: dryRun}, fmt.Errorf("fetch simkl history delta: %w", err)
+			return SyncResult{DryRun: dryRun}, fmt.Errorf("fetch simkl history delta: %w", upstreamUnavailable(err))
 		}
 		responses = append(responses, resp)
 	}
@@ -3937,7 +4204,7 @@ func (s *Service) traktHistoryItemToUpdate(item trakt.HistoryItem, watched *bool
 // executePrewarm runs the prewarm task to pre-resolve continue watching items
 func (s *Service) executePrewarm(task config.ScheduledTask) (SyncResult, error) {
 	if s.prewarmService == nil {
-		return SyncResult{}, errors.New("prewarm service not configured")
+		return SyncResult{}, fmt.Errorf("prewarm service %w", ErrNotConfigured)
 	}
 
 	prewarmResult, err := s.prewarmService.RunOnce(s.ctx)
@@ -3958,7 +4225,7 @@ func (s *Service) executePlexHistorySync(task config.ScheduledTask) (SyncResult,
 	s.mu.RUnlock()
 
 	if historySvc == nil {
-		return SyncResult{}, errors.New("history service not configured")
+		return SyncResult{}, fmt.Errorf("history service %w", ErrNotConfigured)
 	}
 
 	plexAccountID := task.Config["plexAccountId"]
@@ -3992,7 +4259,7 @@ func (s *Service) executePlexHistorySync(task config.ScheduledTask) (SyncResult,
 
 	plexAccount := settings.Plex.GetAccountByID(plexAccountID)
 	if plexAccount == nil {
-		return SyncResult{}, errors.New("plex account not found")
+		return SyncResult{}, fmt.Errorf("plex account %w", ErrNotFound)
 	}
 	if plexAccount.AuthToken == "" {
 		return SyncResult{}, errors.New("plex account not authenticated")
@@ -4001,7 +4268,7 @@ func (s *Service) executePlexHistorySync(task config.ScheduledTask) (SyncResult,
 	// Fetch all watch history from Plex
 	historyItems, err := s.plexClient.GetAllWatchHistory(plexAccount.AuthToken, 5000, plexUserID)
 	if err != nil {
-		return SyncResult{}, fmt.Errorf("fetch plex history: %w", err)
+		return SyncResult{}, fmt.Errorf("fetch plex history: %w", upstreamUnavailable(err))
 	}
 
 	log.Printf("[scheduler] Fetched %d Plex history items", len(historyItems))
@@ -4073,20 +4340,7 @@ func (s *Service) executePlexHistorySync(task config.ScheduledTask) (SyncResult,
 
 // executeJellyfinFavoritesSync syncs Jellyfin favorites to a local watchlist.
 func (s *Service) executeJellyfinFavoritesSync(task config.ScheduledTask) (SyncResult, error) {
-	s.mu.RLock()
-	jfClient := s.jellyfinClient
-	s.mu.RUnlock()
-
-	if jfClient == nil {
-		return SyncResult{}, errors.New("jellyfin client not configured")
-	}
-
-	jellyfinAccountID := task.Config["jellyfinAccountId"]
-	profileID, err := s.resolveTaskProfileID(task)
-
-	if jellyfinAccountID == "" || profileID == "" {
-		return SyncResult{}, errors.New("missing jellyfinAccountId or profileId in task config")
-	}
+	jfClient, jfAccount, profileID, err := s.jellyfinTaskAccount(task)
 	if err != nil {
 		return SyncResult{}, err
 	}
@@ -4105,36 +4359,28 @@ func (s *Service) executeJellyfinFavoritesSync(task config.ScheduledTask) (SyncR
 		log.Printf("[scheduler] DRY RUN mode enabled for Jellyfin favorites sync")
 	}
 
-	// Load settings to get Jellyfin account
-	settings, err := s.configManager.Load()
-	if err != nil {
-		return SyncResult{}, fmt.Errorf("load settings: %w", err)
-	}
-
-	jfAccount := settings.Jellyfin.GetAccountByID(jellyfinAccountID)
-	if jfAccount == nil {
-		return SyncResult{}, errors.New("jellyfin account not found")
-	}
-	if jfAccount.Token == "" {
-		return SyncResult{}, errors.New("jellyfin account not authenticated")
-	}
-
-	// Only source_to_target is supported for Jellyfin favorites
+	// Only source_to_target is supported for Jellyfin favorites; the
+	// jellyfin_sync task handles export and bidirectional sync.
 	if syncDirection != "source_to_target" {
 		return SyncResult{}, fmt.Errorf("unsupported sync direction for Jellyfin favorites: %s (only source_to_target supported)", syncDirection)
 	}
 
+	syncSource := fmt.Sprintf("jellyfin:%s:%s", jfAccount.ID, task.ID)
+	return s.syncJellyfinFavoritesToLocal(jfClient, jfAccount, profileID, syncSource, deleteBehavior, dryRun)
+}
+
+// syncJellyfinFavoritesToLocal imports Jellyfin favorites into the local watchlist.
+func (s *Service) syncJellyfinFavoritesToLocal(jfClient *jellyfin.Client, jfAccount *config.JellyfinAccount, profileID, syncSource, deleteBehavior string, dryRun bool) (SyncResult, error) {
 	// Fetch favorites from Jellyfin
 	items, err := jfClient.GetFavorites(jfAccount.ServerURL, jfAccount.Token, jfAccount.UserID)
 	if err != nil {
-		return SyncResult{}, fmt.Errorf("fetch jellyfin favorites: %w", err)
+		return SyncResult{}, fmt.Errorf("fetch jellyfin favorites: %w", upstreamUnavailable(err))
 	}
 
 	log.Printf("[scheduler] Fetched %d Jellyfin favorites", len(items))
 
 	now := time.Now().UTC()
 	result := SyncResult{DryRun: dryRun}
-	syncSource := fmt.Sprintf("jellyfin:%s:%s", jellyfinAccountID, task.ID)
 
 	// Build set of Jellyfin item keys for deletion checking
 	jfItemKeys := make(map[string]bool)
@@ -4248,22 +4494,13 @@ func (s *Service) executeJellyfinFavoritesSync(task config.ScheduledTask) (SyncR
 func (s *Service) executeJellyfinHistorySync(task config.ScheduledTask) (SyncResult, error) {
 	s.mu.RLock()
 	historySvc := s.historyService
-	jfClient := s.jellyfinClient
 	s.mu.RUnlock()
 
 	if historySvc == nil {
-		return SyncResult{}, errors.New("history service not configured")
+		return SyncResult{}, fmt.Errorf("history service %w", ErrNotConfigured)
 	}
-	if jfClient == nil {
-		return SyncResult{}, errors.New("jellyfin client not configured")
-	}
-
-	jellyfinAccountID := task.Config["jellyfinAccountId"]
-	profileID, err := s.resolveTaskProfileID(task)
 
-	if jellyfinAccountID == "" || profileID == "" {
-		return SyncResult{}, errors.New("missing jellyfinAccountId or profileId in task config")
-	}
+	jfClient, jfAccount, profileID, err := s.jellyfinTaskAccount(task)
 	if err != nil {
 		return SyncResult{}, err
 	}
@@ -4273,24 +4510,15 @@ func (s *Service) executeJellyfinHistorySync(task config.ScheduledTask) (SyncRes
 		log.Printf("[scheduler] DRY RUN mode enabled for Jellyfin history sync")
 	}
 
-	// Load settings to get Jellyfin account
-	settings, err := s.configManager.Load()
-	if err != nil {
-		return SyncResult{}, fmt.Errorf("load settings: %w", err)
-	}
-
-	jfAccount := settings.Jellyfin.GetAccountByID(jellyfinAccountID)
-	if jfAccount == nil {
-		return SyncResult{}, errors.New("jellyfin account not found")
-	}
-	if jfAccount.Token == "" {
-		return SyncResult{}, errors.New("jellyfin account not authenticated")
-	}
+	return s.importJellyfinHistory(historySvc, jfClient, jfAccount, profileID, dryRun)
+}
 
+// importJellyfinHistory imports played Jellyfin items into local watch history.
+func (s *Service) importJellyfinHistory(historySvc *history.Service, jfClient *jellyfin.Client, jfAccount *config.JellyfinAccount, profileID string, dryRun bool) (SyncResult, error) {
 	// Fetch watch history from Jellyfin
 	items, err := jfClient.GetWatchHistory(jfAccount.ServerURL, jfAccount.Token, jfAccount.UserID)
 	if err != nil {
-		return SyncResult{}, fmt.Errorf("fetch jellyfin history: %w", err)
+		return SyncResult{}, fmt.Errorf("fetch jellyfin history: %w", upstreamUnavailable(err))
 	}
 
 	log.Printf("[scheduler] Fetched %d Jellyfin history items", len(items))
@@ -4326,6 +4554,8 @@ func (s *Service) executeJellyfinHistorySync(task config.ScheduledTask) (SyncRes
 		watchedAt := time.Now().UTC()
 		if item.DatePlayed != nil {
 			watchedAt = *item.DatePlayed
+		} else if item.UserData != nil && item.UserData.LastPlayedDate != nil {
+			watchedAt = *item.UserData.LastPlayedDate
 		}
 
 		extIDs := item.ProviderIDs
@@ -4399,7 +4629,7 @@ func (s *Service) executeMDBListWatchlistSync(task config.ScheduledTask) (SyncRe
 
 	mdblistAccount := settings.MDBList.GetAccountByID(mdblistAccountID)
 	if mdblistAccount == nil {
-		return SyncResult{}, errors.New("MDBList account not found")
+		return SyncResult{}, fmt.Errorf("MDBList account %w", ErrNotFound)
 	}
 
 	if mdblistAccount.APIKey == "" {
@@ -4422,7 +4652,7 @@ func (s *Service) syncMDBListWatchlistToLocal(account *config.MDBListAccount, pr
 	req.Header.Set("User-Agent", "mediastorm/1.0")
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return result, fmt.Errorf("fetch MDBList watchlist: %w", err)
+		return result, fmt.Errorf("fetch MDBList watchlist: %w", upstreamUnavailable(err))
 	}
 	defer resp.Body.Close()
 
@@ -4645,7 +4875,7 @@ func (s *Service) executeMDBListHistorySync(task config.ScheduledTask) (SyncResu
 	s.mu.RUnlock()
 
 	if historySvc == nil {
-		return SyncResult{}, errors.New("history service not configured")
+		return SyncResult{}, fmt.Errorf("history service %w", ErrNotConfigured)
 	}
 
 	mdblistAccountID := task.Config["mdblistAccountId"]
@@ -4672,7 +4902,7 @@ func (s *Service) executeMDBListHistorySync(task config.ScheduledTask) (SyncResu
 
 	mdblistAccount := settings.MDBList.GetAccountByID(mdblistAccountID)
 	if mdblistAccount == nil {
-		return SyncResult{}, errors.New("MDBList account not found")
+		return SyncResult{}, fmt.Errorf("MDBList account %w", ErrNotFound)
 	}
 
 	if mdblistAccount.APIKey == "" {
@@ -4736,7 +4966,7 @@ func (s *Service) syncMDBListHistoryToLocal(task config.ScheduledTask, account *
 		httpClient := &http.Client{Timeout: 30 * time.Second}
 		resp, err := httpClient.Do(req)
 		if err != nil {
-			return result, fmt.Errorf("fetch MDBList history: %w", err)
+			return result, fmt.Errorf("fetch MDBList history: %w", upstreamUnavailable(err))
 		}
 
 		var page struct {
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -2006,6 +2007,212 @@ func TestExecuteJellyfinFavoritesSync_MirrorModeKeepsCanonicalMergedItem(t *test
 	}
 }
 
+func TestExecuteJellyfinSync_ExportsFavoritesAndWatchedState(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := config.NewManager(tmpDir + "/settings.json")
+	settings := config.DefaultSettings()
+	settings.Jellyfin.Accounts = []config.JellyfinAccount{
+		{ID: "jf-acc-1", Name: "Test Jellyfin", ServerURL: "http://jellyfin.test", Token: "jf-token", UserID: "jf-user"},
+	}
+	if err := manager.Save(settings); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	watchlistSvc, err := watchlist.NewService(tmpDir)
+	if err != nil {
+		t.Fatalf("watchlist.NewService() error = %v", err)
+	}
+	historySvc, err := history.NewService(tmpDir)
+	if err != nil {
+		t.Fatalf("history.NewService() error = %v", err)
+	}
+
+	for _, item := range []models.WatchlistUpsert{
+		{ID: "tmdb:movie:100", MediaType: "movie", Name: "Local Movie", ExternalIDs: map[string]string{"tmdb": "100"}},
+		{ID: "tvdb:series:200", MediaType: "series", Name: "Local Show", ExternalIDs: map[string]string{"tvdb": "200"}},
+		{ID: "tmdb:movie:300", MediaType: "movie", Name: "Not In Library", ExternalIDs: map[string]string{"tmdb": "300"}},
+	} {
+		if _, err := watchlistSvc.AddOrUpdate("profile-1", item); err != nil {
+			t.Fatalf("seed watchlist error = %v", err)
+		}
+	}
+	watched := true
+	watchedAt := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	for _, update := range []models.WatchHistoryUpdate{
+		{MediaType: "movie", ItemID: "tmdb:movie:100", Name: "Local Movie", Watched: &watched, WatchedAt: watchedAt, ExternalIDs: map[string]string{"tmdb": "100"}},
+		{MediaType: "episode", ItemID: "tvdb:series:200:s01e02", Watched: &watched, WatchedAt: watchedAt, SeriesID: "tvdb:series:200", SeriesName: "Local Show", SeasonNumber: 1, EpisodeNumber: 2, ExternalIDs: map[string]string{"tvdb": "200"}},
+	} {
+		if _, err := historySvc.UpdateWatchHistory("profile-1", update); err != nil {
+			t.Fatalf("seed history error = %v", err)
+		}
+	}
+
+	var writes []string
+	origTransport := http.DefaultTransport
+	http.DefaultTransport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		if req.URL.Host != "jellyfin.test" {
+			return nil, io.EOF
+		}
+		if req.Method != http.MethodGet {
+			writes = append(writes, req.Method+" "+req.URL.Path)
+			return jsonResponse(http.StatusOK, `{}`), nil
+		}
+		return jsonResponse(http.StatusOK, `{"Items": [
+			{"Id": "jf-movie", "Name": "Local Movie", "Type": "Movie", "ProviderIds": {"Tmdb": "100"}, "UserData": {"Played": false, "IsFavorite": false}},
+			{"Id": "jf-show", "Name": "Local Show", "Type": "Series", "ProviderIds": {"Tvdb": "200"}, "UserData": {"IsFavorite": true}},
+			{"Id": "jf-ep", "Name": "Episode 2", "Type": "Episode", "SeriesId": "jf-show", "ParentIndexNumber": 1, "IndexNumber": 2, "UserData": {"Played": false}},
+			{"Id": "jf-other", "Name": "Jellyfin Only", "Type": "Movie", "ProviderIds": {"Tmdb": "999"}, "UserData": {"IsFavorite": true}}
+		]}`), nil
+	})
+	defer func() {
+		http.DefaultTransport = origTransport
+	}()
+
+	svc := &Service{
+		configManager:    manager,
+		jellyfinClient:   jellyfin.NewClient(),
+		watchlistService: watchlistSvc,
+		historyService:   historySvc,
+	}
+
+	result, err := svc.executeJellyfinSync(config.ScheduledTask{
+		ID:   "jf-task-1",
+		Type: config.ScheduledTaskTypeJellyfinSync,
+		Config: map[string]string{
+			"jellyfinAccountId": "jf-acc-1",
+			"profileId":         "profile-1",
+			"syncDirection":     "target_to_source",
+			"deleteBehavior":    "mirror",
+		},
+	})
+	if err != nil {
+		t.Fatalf("executeJellyfinSync() error = %v", err)
+	}
+
+	want := []string{
+		"POST /Users/jf-user/FavoriteItems/jf-movie",
+		"DELETE /Users/jf-user/FavoriteItems/jf-other",
+		"POST /Users/jf-user/PlayedItems/jf-movie",
+		"POST /Users/jf-user/PlayedItems/jf-ep",
+	}
+	sort.Strings(writes)
+	sort.Strings(want)
+	if strings.Join(writes, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("writes = %v, want %v", writes, want)
+	}
+	if result.Count != 3 {
+		t.Fatalf("result.Count = %d, want 3 (1 favorite + 2 played)", result.Count)
+	}
+}
+
+func TestExecuteSimklWatchlistSync_ExportsPlanToWatch(t *testing.T) {
+	tmpDir := t.TempDir()
+	manager := config.NewManager(tmpDir + "/settings.json")
+	settings := config.DefaultSettings()
+	settings.Simkl.Accounts = []config.SimklAccount{
+		{ID: "simkl-1", Name: "Test Simkl", ClientID: "client-id", AccessToken: "token"},
+	}
+	if err := manager.Save(settings); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	watchlistSvc, err := watchlist.NewService(tmpDir)
+	if err != nil {
+		t.Fatalf("watchlist.NewService() error = %v", err)
+	}
+	for _, item := range []models.WatchlistUpsert{
+		{ID: "tmdb:movie:100", MediaType: "movie", Name: "Local Movie", ExternalIDs: map[string]string{"tmdb": "100"}},
+		{ID: "tvdb:series:200", MediaType: "series", Name: "Finished Show", ExternalIDs: map[string]string{"tvdb": "200"}},
+	} {
+		if _, err := watchlistSvc.AddOrUpdate("profile-1", item); err != nil {
+			t.Fatalf("seed watchlist error = %v", err)
+		}
+	}
+
+	writes := make(map[string]string)
+	client := simkl.NewClient()
+	client.SetHTTPClientForTest(&http.Client{
+		Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method == http.MethodPost {
+				body, _ := io.ReadAll(req.Body)
+				writes[req.URL.Path] = string(body)
+				return jsonResponse(http.StatusCreated, `{}`), nil
+			}
+			switch req.URL.Path {
+			case "/sync/all-items/movies":
+				return jsonResponse(http.StatusOK, `{"movies":[{"status":"plantowatch","movie":{"title":"Simkl Only","year":2020,"ids":{"simkl":5,"tmdb":999}}}]}`), nil
+			case "/sync/all-items/shows":
+				return jsonResponse(http.StatusOK, `{"shows":[{"status":"completed","show":{"title":"Finished Show","ids":{"simkl":6,"tvdb":200}}}]}`), nil
+			}
+			return jsonResponse(http.StatusOK, `{}`), nil
+		}),
+	})
+
+	svc := &Service{
+		configManager:    manager,
+		simklClient:      client,
+		watchlistService: watchlistSvc,
+	}
+	result, err := svc.executeSimklWatchlistSync(config.ScheduledTask{
+		ID:   "simkl-task-1",
+		Type: config.ScheduledTaskTypeSimklWatchlistSync,
+		Config: map[string]string{
+			"simklAccountId": "simkl-1",
+			"profileId":      "profile-1",
+			"syncDirection":  "target_to_source",
+			"deleteBehavior": "mirror",
+		},
+	})
+	if err != nil {
+		t.Fatalf("executeSimklWatchlistSync() error = %v", err)
+	}
+
+	added := writes["/sync/add-to-list"]
+	if !strings.Contains(added, `"tmdb":100`) || !strings.Contains(added, `"to":"plantowatch"`) {
+		t.Fatalf("add-to-list body = %s, want local movie added to plantowatch", added)
+	}
+	if strings.Contains(added, `"tvdb":200`) {
+		t.Fatalf("add-to-list body = %s, completed show must not move back to plantowatch", added)
+	}
+	if removed := writes["/sync/history/remove"]; !strings.Contains(removed, `"tmdb":999`) {
+		t.Fatalf("history/remove body = %s, want Simkl-only item removed in mirror mode", removed)
+	}
+	if result.Count != 1 {
+		t.Fatalf("result.Count = %d, want 1", result.Count)
+	}
+}
+
+func TestSimklHistoryBuilderGroupsEpisodesBySeason(t *testing.T) {
+	watchedAt := time.Date(2026, 5, 1, 20, 0, 0, 0, time.UTC)
+	b := newSimklHistoryBuilder()
+	for _, item := range []models.WatchHistoryItem{
+		{MediaType: "episode", SeriesName: "Show", SeasonNumber: 2, EpisodeNumber: 1, WatchedAt: watchedAt, ExternalIDs: map[string]string{"tvdb": "200"}},
+		{MediaType: "episode", SeriesName: "Show", SeasonNumber: 1, EpisodeNumber: 3, ExternalIDs: map[string]string{"tvdb": "200"}},
+		{MediaType: "episode", SeriesName: "Show", SeasonNumber: 1, EpisodeNumber: 4, ExternalIDs: map[string]string{"tvdb": "200"}},
+	} {
+		ids, ok := simklIDsFromExternal(item.ExternalIDs)
+		if !ok {
+			t.Fatalf("simklIDsFromExternal(%v) not ok", item.ExternalIDs)
+		}
+		b.addEpisode(item, ids)
+	}
+
+	req := b.request()
+	if len(req.Shows) != 1 {
+		t.Fatalf("shows = %d, want 1", len(req.Shows))
+	}
+	seasons := req.Shows[0].Seasons
+	if len(seasons) != 2 || seasons[0].Number != 1 || len(seasons[0].Episodes) != 2 || seasons[1].Number != 2 {
+		t.Fatalf("unexpected seasons: %+v", seasons)
+	}
+	if got := seasons[1].Episodes[0].WatchedAt; got != "2026-05-01T20:00:00Z" {
+		t.Fatalf("watched_at = %q", got)
+	}
+	if _, ok := simklIDsFromExternal(map[string]string{"imdb": "not-an-id"}); ok {
+		t.Fatal("expected malformed imdb id to be rejected")
+	}
+}
+
 func jsonResponse(status int, body string) *http.Response {
 	return &http.Response{
 		StatusCode: status,
@@ -2168,3 +2375,85 @@ func TestTraktPlaybackItemToHistoryUpdate_Movie(t *testing.T) {
 		t.Fatalf("WatchedAt = %v, want %v", update.WatchedAt, pausedAt)
 	}
 }
+
+func TestRecoverInterruptedTasksClearsMarkerAndKeepsWriteKeys(t *testing.T) {
+	mgr := config.NewManager(t.TempDir() + "/settings.json")
+	settings := config.DefaultSettings()
+	startedAt := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	lastRunAt := startedAt.Add(-time.Hour)
+	settings.ScheduledTasks.Tasks = []config.ScheduledTask{{
+		ID:            "task-1",
+		Type:          config.ScheduledTaskTypeTraktHistorySync,
+		Enabled:       true,
+		Frequency:     config.ScheduledTaskFrequencyHourly,
+		LastRunAt:     &lastRunAt,
+		StartedAt:     &startedAt,
+		SyncWriteKeys: []string{"trakt-history:movie:0/tt0133093/603/0@2026-05-01T11:00:00Z"},
+	}}
+	if err := mgr.Save(settings); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	svc := NewService(mgr, nil, nil, nil)
+	svc.recoverInterruptedTasks()
+
+	saved, err := mgr.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	task := saved.ScheduledTasks.Tasks[0]
+	if task.StartedAt != nil {
+		t.Fatalf("StartedAt = %v, want cleared", task.StartedAt)
+	}
+	if task.LastRunAt == nil || !task.LastRunAt.Equal(lastRunAt) {
+		t.Fatalf("LastRunAt = %v, want previous run %v so the task resumes", task.LastRunAt, lastRunAt)
+	}
+	if task.LastStatus != config.ScheduledTaskStatusError || task.LastError != interruptedTaskError {
+		t.Fatalf("status = %q/%q, want interrupted error", task.LastStatus, task.LastError)
+	}
+	if len(task.SyncWriteKeys) != 1 {
+		t.Fatalf("SyncWriteKeys = %v, want keys kept for the resumed run", task.SyncWriteKeys)
+	}
+	if !svc.shouldRun(task) {
+		t.Fatal("expected interrupted task to be due")
+	}
+}
+
+func TestFilterTraktHistoryWritesSkipsRecordedItems(t *testing.T) {
+	req := trakt.SyncHistoryRequest{
+		Movies: []trakt.SyncMovie{
+			{WatchedAt: "2026-05-01T11:00:00Z", IDs: trakt.SyncIDs{TMDB: 603}},
+			{WatchedAt: "2026-05-01T11:30:00Z", IDs: trakt.SyncIDs{TMDB: 604}},
+		},
+		Shows: []trakt.SyncShow{{
+			IDs: trakt.SyncIDs{TVDB: 81189},
+			Seasons: []trakt.SyncSeason{
+				{Number: 1, Episodes: []trakt.SyncEpisode{{Number: 1, WatchedAt: "2026-05-01T10:00:00Z"}}},
+				{Number: 2, Episodes: []trakt.SyncEpisode{{Number: 1, WatchedAt: "2026-05-01T10:45:00Z"}}},
+			},
+		}},
+	}
+
+	first, keys := filterTraktHistoryWrites(config.ScheduledTask{}, req)
+	if len(keys) != 4 || len(first.Movies) != 2 || len(first.Shows[0].Seasons) != 2 {
+		t.Fatalf("expected all items on first run, got %d keys, %+v", len(keys), first)
+	}
+
+	// Simulate an interrupted run that already sent the first movie and S01E01.
+	task := config.ScheduledTask{SyncWriteKeys: []string{keys[0], keys[2]}}
+	resumed, resumedKeys := filterTraktHistoryWrites(task, req)
+	if len(resumedKeys) != 2 {
+		t.Fatalf("resumed keys = %v, want 2", resumedKeys)
+	}
+	if len(resumed.Movies) != 1 || resumed.Movies[0].IDs.TMDB != 604 {
+		t.Fatalf("resumed movies = %+v, want only tmdb 604", resumed.Movies)
+	}
+	if len(resumed.Shows) != 1 || len(resumed.Shows[0].Seasons) != 1 || resumed.Shows[0].Seasons[0].Number != 2 {
+		t.Fatalf("resumed shows = %+v, want only season 2", resumed.Shows)
+	}
+
+	task.SyncWriteKeys = append(task.SyncWriteKeys, resumedKeys...)
+	if done, doneKeys := filterTraktHistoryWrites(task, req); len(done.Movies) != 0 || len(done.Shows) != 0 || len(doneKeys) != 0 {
+		t.Fatalf("expected nothing left to send, got %+v", done)
+	}
+}
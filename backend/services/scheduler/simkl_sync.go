@@ -0,0 +1,515 @@
+package scheduler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"novastream/config"
+	"novastream/models"
+	"novastream/services/simkl"
+)
+
+// simklTaskAccount resolves the client, authenticated account and target
+// profile for a Simkl task.
+func (s *Service) simklTaskAccount(task config.ScheduledTask) (*simkl.Client, *config.SimklAccount, string, error) {
+	s.mu.RLock()
+	simklClient := s.simklClient
+	s.mu.RUnlock()
+
+	if simklClient == nil {
+		return nil, nil, "", fmt.Errorf("simkl client %w", ErrNotConfigured)
+	}
+
+	simklAccountID := task.Config["simklAccountId"]
+	profileID, err := s.resolveTaskProfileID(task)
+	if simklAccountID == "" || profileID == "" {
+		return nil, nil, "", errors.New("missing simklAccountId or profileId in task config")
+	}
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	settings, err := s.configManager.Load()
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("load settings: %w", err)
+	}
+	simklAccount := settings.Simkl.GetAccountByID(simklAccountID)
+	if simklAccount == nil {
+		return nil, nil, "", fmt.Errorf("simkl account %w", ErrNotFound)
+	}
+	if simklAccount.ClientID == "" || simklAccount.AccessToken == "" {
+		return nil, nil, "", errors.New("simkl account not authenticated")
+	}
+	return simklClient, simklAccount, profileID, nil
+}
+
+// executeSimklWatchlistSync syncs the Simkl "plan to watch" list with the
+// local watchlist. Config:
+//   - syncDirection: source_to_target (import), target_to_source (export) or bidirectional
+//   - deleteBehavior: additive (default), delete or mirror
+//   - dryRun: "true" to report changes without applying them
+func (s *Service) executeSimklWatchlistSync(task config.ScheduledTask) (SyncResult, error) {
+	simklClient, simklAccount, profileID, err := s.simklTaskAccount(task)
+	if err != nil {
+		return SyncResult{}, err
+	}
+
+	syncDirection := task.Config["syncDirection"]
+	if syncDirection == "" {
+		syncDirection = "source_to_target"
+	}
+	switch syncDirection {
+	case "source_to_target", "target_to_source", "bidirectional":
+	default:
+		return SyncResult{}, fmt.Errorf("unknown sync direction: %s", syncDirection)
+	}
+	deleteBehavior := task.Config["deleteBehavior"]
+	if deleteBehavior == "" {
+		deleteBehavior = "additive"
+	}
+	dryRun := task.Config["dryRun"] == "true"
+	if dryRun {
+		log.Printf("[scheduler] DRY RUN mode enabled for Simkl watchlist sync")
+	}
+
+	// All statuses are fetched so exports can skip titles Simkl already
+	// tracks elsewhere (watching, completed, ...) instead of moving them back.
+	var planned, tracked []simkl.ListItem
+	for _, bucket := range []string{"movies", "shows", "anime"} {
+		items, err := simklClient.GetListItems(simklAccount.ClientID, simklAccount.AccessToken, bucket, "")
+		if err != nil {
+			return SyncResult{}, fmt.Errorf("fetch simkl %s list: %w", bucket, upstreamUnavailable(err))
+		}
+		for _, item := range items {
+			if item.Status == "plantowatch" {
+				planned = append(planned, item)
+			}
+			tracked = append(tracked, item)
+		}
+	}
+	log.Printf("[scheduler] Fetched %d Simkl plan-to-watch items (%d tracked in total)", len(planned), len(tracked))
+
+	syncSource := fmt.Sprintf("simkl:%s:%s", simklAccount.ID, task.ID)
+	result := SyncResult{DryRun: dryRun}
+
+	if syncDirection != "target_to_source" {
+		// Bidirectional sync is a union; deletions can't be attributed to
+		// either side without tracking what was previously synced.
+		importBehavior := deleteBehavior
+		if syncDirection == "bidirectional" {
+			importBehavior = "additive"
+		}
+		part, err := s.syncSimklPlanToWatchToLocal(planned, profileID, syncSource, importBehavior, dryRun)
+		if err != nil {
+			return result, err
+		}
+		result.Count += part.Count
+		result.ToAdd = append(result.ToAdd, part.ToAdd...)
+		result.ToRemove = append(result.ToRemove, part.ToRemove...)
+	}
+	if syncDirection != "source_to_target" {
+		exportBehavior := deleteBehavior
+		if syncDirection == "bidirectional" {
+			exportBehavior = "additive"
+		}
+		part, err := s.syncLocalToSimklPlanToWatch(simklClient, simklAccount, planned, tracked, profileID, exportBehavior, dryRun)
+		if err != nil {
+			return result, err
+		}
+		result.Count += part.Count
+		result.ToAdd = append(result.ToAdd, part.ToAdd...)
+		result.ToRemove = append(result.ToRemove, part.ToRemove...)
+	}
+	if syncDirection == "bidirectional" && deleteBehavior != "additive" {
+		log.Printf("[scheduler] Note: delete/mirror behavior in bidirectional Simkl sync only adds items (deletion tracking not yet implemented)")
+	}
+
+	return result, nil
+}
+
+// syncSimklPlanToWatchToLocal imports Simkl plan-to-watch items into the local watchlist.
+func (s *Service) syncSimklPlanToWatchToLocal(planned []simkl.ListItem, profileID, syncSource, deleteBehavior string, dryRun bool) (SyncResult, error) {
+	now := time.Now().UTC()
+	result := SyncResult{DryRun: dryRun}
+
+	existingItems, _ := s.watchlistService.List(profileID)
+	existingKeys := make(map[string]bool)
+	for _, item := range existingItems {
+		for _, key := range schedulerWatchlistMatchKeys(item.MediaType, item.ID, item.ExternalIDs) {
+			existingKeys[key] = true
+		}
+	}
+
+	simklItemKeys := make(map[string]bool)
+	imported := 0
+	for _, item := range planned {
+		mediaType := simklWatchlistMediaType(item.MediaType)
+		extIDs := simklExternalIDs(item.IDs)
+
+		// Prefer TMDB then IMDB then TVDB
+		itemID := extIDs["tmdb"]
+		if itemID == "" {
+			itemID = extIDs["imdb"]
+		}
+		if itemID == "" {
+			itemID = extIDs["tvdb"]
+		}
+		if itemID == "" {
+			continue
+		}
+
+		for _, key := range schedulerWatchlistMatchKeys(mediaType, itemID, extIDs) {
+			simklItemKeys[key] = true
+		}
+
+		if dryRun {
+			if !schedulerWatchlistHasAnyKey(existingKeys, mediaType, itemID, extIDs) {
+				log.Printf("[scheduler] DRY RUN: Would import from Simkl: %s (%s)", item.Title, mediaType)
+				result.ToAdd = append(result.ToAdd, config.DryRunItem{
+					Name:      item.Title,
+					MediaType: mediaType,
+					ID:        itemID,
+				})
+			}
+			imported++
+			continue
+		}
+
+		input := models.WatchlistUpsert{
+			ID:          itemID,
+			MediaType:   mediaType,
+			Name:        item.Title,
+			Year:        item.Year,
+			ExternalIDs: extIDs,
+			SyncSource:  syncSource,
+			SyncedAt:    &now,
+		}
+		if _, err := s.watchlistService.AddOrUpdate(profileID, input); err != nil {
+			logWatchlistImportError("Simkl", item.Title, err)
+			continue
+		}
+		imported++
+	}
+
+	if deleteBehavior != "additive" {
+		removed := 0
+		localItems, err := s.watchlistService.List(profileID)
+		if err != nil {
+			log.Printf("[scheduler] Failed to list local items for deletion check: %v", err)
+		} else {
+			for _, localItem := range localItems {
+				if schedulerWatchlistHasAnyKey(simklItemKeys, localItem.MediaType, localItem.ID, localItem.ExternalIDs) {
+					continue
+				}
+				if deleteBehavior == "delete" && localItem.SyncSource != syncSource {
+					continue
+				}
+
+				if dryRun {
+					result.ToRemove = append(result.ToRemove, config.DryRunItem{
+						Name:      localItem.Name,
+						MediaType: localItem.MediaType,
+						ID:        localItem.ID,
+					})
+					removed++
+					continue
+				}
+
+				if ok, err := s.watchlistService.RemoveSynced(profileID, localItem.MediaType, localItem.ID); err != nil {
+					log.Printf("[scheduler] Failed to remove watchlist item %s: %v", localItem.Name, err)
+				} else if ok {
+					removed++
+				}
+			}
+		}
+		if removed > 0 {
+			log.Printf("[scheduler] Removed %d items no longer on the Simkl plan-to-watch list", removed)
+		}
+	}
+
+	log.Printf("[scheduler] Imported %d items from Simkl plan-to-watch", imported)
+	result.Count = imported
+	return result, nil
+}
+
+// syncLocalToSimklPlanToWatch adds local watchlist items that Simkl doesn't
+// track yet to its plan-to-watch list. In mirror mode, plan-to-watch items
+// missing from the local watchlist are removed from Simkl.
+func (s *Service) syncLocalToSimklPlanToWatch(simklClient *simkl.Client, simklAccount *config.SimklAccount, planned, tracked []simkl.ListItem, profileID, deleteBehavior string, dryRun bool) (SyncResult, error) {
+	result := SyncResult{DryRun: dryRun}
+
+	localItems, err := s.watchlistService.List(profileID)
+	if err != nil {
+		return result, fmt.Errorf("list local items: %w", err)
+	}
+
+	trackedKeys := make(map[string]bool)
+	for _, item := range tracked {
+		mediaType := simklWatchlistMediaType(item.MediaType)
+		for _, key := range schedulerWatchlistMatchKeys(mediaType, "", simklExternalIDs(item.IDs)) {
+			trackedKeys[key] = true
+		}
+	}
+
+	var req simkl.SyncListRequest
+	localKeys := make(map[string]bool)
+	skipped := 0
+	for _, localItem := range localItems {
+		for _, key := range schedulerWatchlistMatchKeys(localItem.MediaType, localItem.ID, localItem.ExternalIDs) {
+			localKeys[key] = true
+		}
+		if schedulerWatchlistHasAnyKey(trackedKeys, localItem.MediaType, localItem.ID, localItem.ExternalIDs) {
+			continue
+		}
+		ids, ok := simklIDsFromExternal(localItem.ExternalIDs)
+		if !ok {
+			skipped++
+			continue
+		}
+
+		if dryRun {
+			log.Printf("[scheduler] DRY RUN: Would add to Simkl plan-to-watch: %s", localItem.Name)
+			result.ToAdd = append(result.ToAdd, config.DryRunItem{
+				Name:      localItem.Name,
+				MediaType: localItem.MediaType,
+				ID:        localItem.ID,
+			})
+			continue
+		}
+
+		entry := simkl.SyncListItem{To: "plantowatch", Title: localItem.Name, Year: localItem.Year, IDs: ids}
+		if localItem.MediaType == "movie" {
+			req.Movies = append(req.Movies, entry)
+		} else {
+			req.Shows = append(req.Shows, entry)
+		}
+	}
+	if skipped > 0 {
+		log.Printf("[scheduler] Skipped %d watchlist items without IDs Simkl can match", skipped)
+	}
+
+	exported := len(result.ToAdd)
+	if !dryRun && len(req.Movies)+len(req.Shows) > 0 {
+		if err := simklClient.AddToList(simklAccount.ClientID, simklAccount.AccessToken, req); err != nil {
+			return result, fmt.Errorf("add to simkl plan-to-watch: %w", upstreamUnavailable(err))
+		}
+		exported = len(req.Movies) + len(req.Shows)
+	}
+
+	// For "delete" mode we can't tell which Simkl items were synced from
+	// local, so only "mirror" removes them.
+	if deleteBehavior == "mirror" {
+		var removeReq simkl.SyncHistoryRequest
+		for _, item := range planned {
+			mediaType := simklWatchlistMediaType(item.MediaType)
+			if schedulerWatchlistHasAnyKey(localKeys, mediaType, "", simklExternalIDs(item.IDs)) {
+				continue
+			}
+			if dryRun {
+				result.ToRemove = append(result.ToRemove, config.DryRunItem{
+					Name:      item.Title,
+					MediaType: mediaType,
+					ID:        strconv.Itoa(item.IDs.Simkl),
+				})
+				continue
+			}
+			if mediaType == "movie" {
+				removeReq.Movies = append(removeReq.Movies, simkl.SyncHistoryMovie{Title: item.Title, Year: item.Year, IDs: item.IDs})
+			} else {
+				removeReq.Shows = append(removeReq.Shows, simkl.SyncHistoryShow{Title: item.Title, Year: item.Year, IDs: item.IDs})
+			}
+		}
+		if removed := len(removeReq.Movies) + len(removeReq.Shows); removed > 0 {
+			if err := simklClient.RemoveFromHistory(simklAccount.ClientID, simklAccount.AccessToken, removeReq); err != nil {
+				return result, fmt.Errorf("remove from simkl plan-to-watch: %w", upstreamUnavailable(err))
+			}
+			log.Printf("[scheduler] Removed %d Simkl plan-to-watch items not in the local watchlist", removed)
+		}
+	}
+
+	log.Printf("[scheduler] Exported %d watchlist items to Simkl plan-to-watch", exported)
+	result.Count = exported
+	return result, nil
+}
+
+// syncLocalHistoryToSimkl pushes locally watched movies and episodes to Simkl.
+// After the first run only changes since the previous run are sent, and local
+// unwatches in that window are removed from Simkl history.
+func (s *Service) syncLocalHistoryToSimkl(task config.ScheduledTask, simklClient *simkl.Client, simklAccount *config.SimklAccount, profileID string, dryRun bool) (SyncResult, error) {
+	s.mu.RLock()
+	historySvc := s.historyService
+	s.mu.RUnlock()
+
+	result := SyncResult{DryRun: dryRun}
+	items, err := historySvc.ListWatchHistory(profileID)
+	if err != nil {
+		return result, fmt.Errorf("list local history: %w", err)
+	}
+
+	// Allow some overlap with the previous run; Simkl ignores duplicate plays
+	// with the same watched_at.
+	var since time.Time
+	if task.LastRunAt != nil {
+		since = task.LastRunAt.Add(-5 * time.Minute)
+	}
+
+	watchedReq := newSimklHistoryBuilder()
+	unwatchedReq := newSimklHistoryBuilder()
+	for _, item := range items {
+		if item.MediaType != "movie" && item.MediaType != "episode" {
+			continue
+		}
+		if !since.IsZero() && item.UpdatedAt.Before(since) {
+			continue
+		}
+		if !item.Watched && since.IsZero() {
+			// Nothing to undo on a first run.
+			continue
+		}
+		ids, ok := simklIDsFromExternal(item.ExternalIDs)
+		if !ok {
+			continue
+		}
+
+		name := item.Name
+		if item.MediaType == "episode" {
+			name = fmt.Sprintf("%s S%02dE%02d", item.SeriesName, item.SeasonNumber, item.EpisodeNumber)
+		}
+		dryRunItem := config.DryRunItem{Name: name, MediaType: item.MediaType, ID: item.ItemID}
+
+		builder := watchedReq
+		if item.Watched {
+			result.ToAdd = append(result.ToAdd, dryRunItem)
+		} else {
+			builder = unwatchedReq
+			result.ToRemove = append(result.ToRemove, dryRunItem)
+		}
+		if item.MediaType == "movie" {
+			builder.addMovie(item, ids)
+		} else {
+			builder.addEpisode(item, ids)
+		}
+	}
+
+	result.Count = len(result.ToAdd) + len(result.ToRemove)
+	if dryRun {
+		return result, nil
+	}
+	result.ToAdd, result.ToRemove = nil, nil
+
+	if req := watchedReq.request(); len(req.Movies)+len(req.Shows) > 0 {
+		if err := simklClient.SyncHistory(simklAccount.ClientID, simklAccount.AccessToken, req); err != nil {
+			return SyncResult{}, fmt.Errorf("add simkl history: %w", upstreamUnavailable(err))
+		}
+	}
+	if req := unwatchedReq.request(); len(req.Movies)+len(req.Shows) > 0 {
+		if err := simklClient.RemoveFromHistory(simklAccount.ClientID, simklAccount.AccessToken, req); err != nil {
+			return SyncResult{}, fmt.Errorf("remove simkl history: %w", upstreamUnavailable(err))
+		}
+	}
+	log.Printf("[scheduler] Exported %d history changes to Simkl", result.Count)
+	return result, nil
+}
+
+// simklHistoryBuilder groups history items into a Simkl sync request,
+// nesting episodes under their show and season.
+type simklHistoryBuilder struct {
+	movies []simkl.SyncHistoryMovie
+	shows  map[string]*simkl.SyncHistoryShow
+	order  []string
+}
+
+func newSimklHistoryBuilder() *simklHistoryBuilder {
+	return &simklHistoryBuilder{shows: make(map[string]*simkl.SyncHistoryShow)}
+}
+
+func (b *simklHistoryBuilder) addMovie(item models.WatchHistoryItem, ids simkl.IDs) {
+	b.movies = append(b.movies, simkl.SyncHistoryMovie{
+		WatchedAt: simklWatchedAt(item.WatchedAt),
+		Title:     item.Name,
+		Year:      item.Year,
+		IDs:       ids,
+	})
+}
+
+func (b *simklHistoryBuilder) addEpisode(item models.WatchHistoryItem, ids simkl.IDs) {
+	if item.SeasonNumber <= 0 || item.EpisodeNumber <= 0 {
+		return
+	}
+	key := fmt.Sprintf("%d|%s|%d|%d", ids.Simkl, ids.IMDB, ids.TMDB, ids.TVDB)
+	show, ok := b.shows[key]
+	if !ok {
+		show = &simkl.SyncHistoryShow{Title: item.SeriesName, IDs: ids}
+		b.shows[key] = show
+		b.order = append(b.order, key)
+	}
+	episode := simkl.SyncHistoryEpisode{Number: item.EpisodeNumber, WatchedAt: simklWatchedAt(item.WatchedAt)}
+	for i := range show.Seasons {
+		if show.Seasons[i].Number == item.SeasonNumber {
+			show.Seasons[i].Episodes = append(show.Seasons[i].Episodes, episode)
+			return
+		}
+	}
+	show.Seasons = append(show.Seasons, simkl.SyncHistorySeason{Number: item.SeasonNumber, Episodes: []simkl.SyncHistoryEpisode{episode}})
+}
+
+func (b *simklHistoryBuilder) request() simkl.SyncHistoryRequest {
+	req := simkl.SyncHistoryRequest{Movies: b.movies}
+	for _, key := range b.order {
+		show := b.shows[key]
+		sort.Slice(show.Seasons, func(i, j int) bool { return show.Seasons[i].Number < show.Seasons[j].Number })
+		req.Shows = append(req.Shows, *show)
+	}
+	return req
+}
+
+func simklWatchedAt(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// simklIDsFromExternal converts local external IDs to Simkl IDs. For
+// episodes these are the series IDs. ok is false when no ID Simkl can match
+// on is present.
+func simklIDsFromExternal(externalIDs map[string]string) (simkl.IDs, bool) {
+	var ids simkl.IDs
+	ids.Simkl, _ = strconv.Atoi(externalIDs["simkl"])
+	ids.TMDB, _ = strconv.Atoi(externalIDs["tmdb"])
+	ids.TVDB, _ = strconv.Atoi(externalIDs["tvdb"])
+	if imdb := strings.TrimSpace(externalIDs["imdb"]); strings.HasPrefix(imdb, "tt") {
+		ids.IMDB = imdb
+	}
+	return ids, ids.Simkl > 0 || ids.TMDB > 0 || ids.TVDB > 0 || ids.IMDB != ""
+}
+
+// simklExternalIDs converts Simkl IDs to local external IDs.
+func simklExternalIDs(ids simkl.IDs) map[string]string {
+	out := make(map[string]string)
+	if ids.Simkl > 0 {
+		out["simkl"] = strconv.Itoa(ids.Simkl)
+	}
+	if ids.IMDB != "" {
+		out["imdb"] = ids.IMDB
+	}
+	if ids.TMDB > 0 {
+		out["tmdb"] = strconv.Itoa(ids.TMDB)
+	}
+	if ids.TVDB > 0 {
+		out["tvdb"] = strconv.Itoa(ids.TVDB)
+	}
+	return out
+}
+
+func simklWatchlistMediaType(mediaType string) string {
+	if mediaType == "movie" {
+		return "movie"
+	}
+	return "series"
+}
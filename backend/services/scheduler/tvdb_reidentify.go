@@ -0,0 +1,159 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+
+	"novastream/config"
+	"novastream/services/watchlist"
+)
+
+// seriesReidentifier re-resolves TVDB series IDs that stopped working. The
+// metadata service implements it; the scheduler asserts it so the task can
+// run without widening schedulerMetadataService.
+type seriesReidentifier interface {
+	ReidentifySeries(ctx context.Context, tvdbID int64, imdbID string, tmdbID int64) (newID int64, gone bool, err error)
+	InvalidateCachedTitle(id string) (int, error)
+}
+
+// storedSeries is one TVDB series ID referenced by stored history or
+// watchlist entries, with the IDs needed to re-resolve it.
+type storedSeries struct {
+	name   string
+	imdbID string
+	tmdbID int64
+}
+
+// executeTVDBReidentify checks every TVDB series ID referenced by profiles'
+// watch history, playback progress and watchlists. IDs that TVDB no longer
+// serves (merged or deleted series) are re-resolved through IMDB/TMDB and
+// stored references are migrated to the replacement; IDs that cannot be
+// re-resolved are reported and left alone.
+func (s *Service) executeTVDBReidentify(task config.ScheduledTask) (SyncResult, error) {
+	s.mu.RLock()
+	meta := s.metadataService
+	historySvc := s.historyService
+	watchlistSvc := s.watchlistService
+	usersService := s.usersService
+	s.mu.RUnlock()
+
+	reidentifier, ok := meta.(seriesReidentifier)
+	if !ok {
+		return SyncResult{}, fmt.Errorf("metadata service %w", ErrNotConfigured)
+	}
+	if usersService == nil {
+		return SyncResult{}, fmt.Errorf("users service %w", ErrNotConfigured)
+	}
+	dryRun := task.Config["dryRun"] == "true"
+
+	stored := make(map[int64]*storedSeries)
+	note := func(tvdbID int64, name, imdbID string, tmdbID int64) {
+		entry := stored[tvdbID]
+		if entry == nil {
+			entry = &storedSeries{}
+			stored[tvdbID] = entry
+		}
+		if entry.name == "" {
+			entry.name = name
+		}
+		if entry.imdbID == "" {
+			entry.imdbID = imdbID
+		}
+		if entry.tmdbID == 0 {
+			entry.tmdbID = tmdbID
+		}
+	}
+	for _, user := range usersService.ListAll() {
+		if historySvc != nil {
+			for tvdbID, ids := range historySvc.SeriesTVDBIDs(user.ID) {
+				tmdbID, _ := strconv.ParseInt(ids["tmdb"], 10, 64)
+				note(tvdbID, "", ids["imdb"], tmdbID)
+			}
+		}
+		if watchlistSvc != nil {
+			items, err := watchlistSvc.List(user.ID)
+			if err != nil {
+				continue
+			}
+			for _, item := range items {
+				if item.MediaType != "series" {
+					continue
+				}
+				tmdbID, tvdbID := watchlist.NumericIDs(item.ExternalIDs)
+				if tvdbID > 0 {
+					note(tvdbID, item.Name, item.ExternalIDs["imdb"], tmdbID)
+				}
+			}
+		}
+	}
+
+	ids := make([]int64, 0, len(stored))
+	for id := range stored {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	result := SyncResult{DryRun: dryRun}
+	remapped, gone, failed := 0, 0, 0
+	for _, oldID := range ids {
+		if s.ctx.Err() != nil {
+			return SyncResult{}, s.ctx.Err()
+		}
+		entry := stored[oldID]
+		newID, dead, err := reidentifier.ReidentifySeries(s.ctx, oldID, entry.imdbID, entry.tmdbID)
+		if err != nil {
+			log.Printf("[scheduler] tvdb re-identify: series %d: %v", oldID, err)
+			failed++
+			continue
+		}
+		if !dead {
+			continue
+		}
+		name := entry.name
+		if name == "" {
+			name = "tvdb:" + strconv.FormatInt(oldID, 10)
+		}
+		result.ToRemove = append(result.ToRemove, config.DryRunItem{Name: name, MediaType: "series", ID: "tvdb:" + strconv.FormatInt(oldID, 10)})
+		if newID <= 0 {
+			log.Printf("[scheduler] tvdb re-identify: series %d is gone and could not be re-resolved", oldID)
+			gone++
+			continue
+		}
+		result.ToAdd = append(result.ToAdd, config.DryRunItem{Name: name, MediaType: "series", ID: "tvdb:" + strconv.FormatInt(newID, 10)})
+		remapped++
+		if dryRun {
+			continue
+		}
+
+		moved := 0
+		if historySvc != nil {
+			n, err := historySvc.RemapSeriesTVDBID(oldID, newID)
+			if err != nil {
+				return SyncResult{}, fmt.Errorf("remap history for tvdb %d: %w", oldID, err)
+			}
+			moved += n
+		}
+		if watchlistSvc != nil {
+			n, err := watchlistSvc.RemapSeriesTVDBID(oldID, newID)
+			if err != nil {
+				return SyncResult{}, fmt.Errorf("remap watchlist for tvdb %d: %w", oldID, err)
+			}
+			moved += n
+		}
+		if _, err := reidentifier.InvalidateCachedTitle("tvdb:" + strconv.FormatInt(oldID, 10)); err != nil {
+			log.Printf("[scheduler] tvdb re-identify: invalidate cache for series %d: %v", oldID, err)
+		}
+		result.Count += moved
+		log.Printf("[scheduler] tvdb re-identify: series %d -> %d, migrated %d entries", oldID, newID, moved)
+	}
+
+	result.Message = fmt.Sprintf("Checked %d series: %d re-identified, %d gone, %d failed", len(ids), remapped, gone, failed)
+	if dryRun {
+		result.Count = remapped
+		result.Message = "Dry run: " + result.Message
+	}
+	return result, nil
+}
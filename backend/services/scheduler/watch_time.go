@@ -0,0 +1,88 @@
+package scheduler
+
+import (
+	"fmt"
+	"time"
+
+	"novastream/config"
+	"novastream/models"
+	"novastream/services/notifications"
+)
+
+// Task config keys recording the last watch time warning sent.
+const (
+	watchTimeReportedWeekKey   = "reportedWeek"
+	watchTimeReportedStatusKey = "reportedStatus"
+)
+
+// watchTimeSource reports a profile's weekly watch time against its goal.
+type watchTimeSource interface {
+	Summary(userID string, now time.Time) models.WatchTimeSummary
+}
+
+// SetWatchTimeService sets the source of weekly watch time used by watch
+// time warnings.
+func (s *Service) SetWatchTimeService(source watchTimeSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.watchTime = source
+}
+
+// executeWatchTime warns when a profile passes its weekly goal's warning
+// threshold and again when it uses the goal up. Each warning is sent once a
+// week; runs with nothing new send no notification.
+func (s *Service) executeWatchTime(task config.ScheduledTask) (SyncResult, error) {
+	s.mu.RLock()
+	source := s.watchTime
+	s.mu.RUnlock()
+	if source == nil {
+		return SyncResult{}, fmt.Errorf("watch time service %w", ErrNotConfigured)
+	}
+	profileID, err := s.resolveTaskProfileID(task)
+	if err != nil {
+		return SyncResult{}, err
+	}
+
+	now := time.Now()
+	summary := source.Summary(profileID, now)
+	week := summary.WeekStart.Format("2006-01-02")
+	reported := ""
+	if task.Config[watchTimeReportedWeekKey] == week {
+		reported = task.Config[watchTimeReportedStatusKey]
+	}
+
+	result := SyncResult{Count: summary.Minutes}
+	if summary.Goal == nil {
+		result.Quiet = true
+		result.Message = "No watch time goal set"
+		return result, nil
+	}
+	if watchTimeStatusRank(summary.Status) <= watchTimeStatusRank(reported) {
+		result.Quiet = true
+		result.Message = fmt.Sprintf("%d of %d minutes watched this week", summary.Minutes, summary.Goal.WeeklyMinutes)
+		return result, nil
+	}
+
+	result.Config = map[string]string{
+		watchTimeReportedWeekKey:   week,
+		watchTimeReportedStatusKey: summary.Status,
+	}
+	e := notifications.WatchTimeEvent(s.taskLocale(task), s.profileName(profileID), summary.Minutes, summary.Goal.WeeklyMinutes,
+		summary.Status == models.WatchTimeStatusReached, summary.Blocked, now.UTC())
+	result.Notification = &e
+	result.Message = fmt.Sprintf("Watch time %s: %d of %d minutes", summary.Status, summary.Minutes, summary.Goal.WeeklyMinutes)
+	return result, nil
+}
+
+// watchTimeStatusRank orders statuses by how far past the goal they are;
+// only warning and reached are worth a notification.
+func watchTimeStatusRank(status string) int {
+	switch status {
+	case models.WatchTimeStatusWarning:
+		return 1
+	case models.WatchTimeStatusReached:
+		return 2
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"novastream/config"
+	"novastream/models"
+)
+
+type fakeWatchTimeSource struct {
+	summary models.WatchTimeSummary
+}
+
+func (f *fakeWatchTimeSource) Summary(string, time.Time) models.WatchTimeSummary {
+	return f.summary
+}
+
+func TestExecuteWatchTimeWarnsOncePerStatus(t *testing.T) {
+	week := time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local)
+	source := &fakeWatchTimeSource{summary: models.WatchTimeSummary{
+		WeekStart: week,
+		Minutes:   100,
+		Goal:      &models.WatchTimeGoal{UserID: "kids", WeeklyMinutes: 120},
+		Status:    models.WatchTimeStatusWarning,
+	}}
+	s := &Service{}
+	s.SetWatchTimeService(source)
+	task := config.ScheduledTask{ID: "wt", Type: config.ScheduledTaskTypeWatchTime, Config: map[string]string{"profileId": "kids"}}
+
+	result, err := s.executeWatchTime(task)
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if result.Quiet || result.Notification == nil {
+		t.Fatalf("expected a warning, got %+v", result)
+	}
+	if got := result.Notification.Title(); got != "kids is nearing this week's watch time goal" {
+		t.Fatalf("unexpected headline %q", got)
+	}
+	for key, value := range result.Config {
+		task.Config[key] = value
+	}
+
+	if result, _ = s.executeWatchTime(task); !result.Quiet {
+		t.Fatalf("expected the warning to be sent once, got %+v", result)
+	}
+
+	source.summary.Minutes = 125
+	source.summary.Status = models.WatchTimeStatusReached
+	source.summary.Blocked = true
+	result, _ = s.executeWatchTime(task)
+	if result.Quiet || result.Notification == nil {
+		t.Fatalf("expected a reached warning, got %+v", result)
+	}
+	if got := result.Notification.Summary(); got != "2 h 05 min of 2 h watched this week. Playback is paused until Monday." {
+		t.Fatalf("unexpected message %q", got)
+	}
+	for key, value := range result.Config {
+		task.Config[key] = value
+	}
+
+	source.summary.WeekStart = week.AddDate(0, 0, 7)
+	source.summary.Minutes = 100
+	source.summary.Status = models.WatchTimeStatusWarning
+	source.summary.Blocked = false
+	if result, _ = s.executeWatchTime(task); result.Quiet {
+		t.Fatal("expected a new week to warn again")
+	}
+}
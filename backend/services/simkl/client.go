@@ -170,6 +170,34 @@ func (c *Client) SyncHistory(clientID, accessToken string, req SyncHistoryReques
 	return err
 }
 
+// SyncListItem is a movie or show entry for the add-to-list endpoint. To
+// names the destination status bucket, e.g. "plantowatch".
+type SyncListItem struct {
+	To    string `json:"to,omitempty"`
+	Title string `json:"title,omitempty"`
+	Year  int    `json:"year,omitempty"`
+	IDs   IDs    `json:"ids"`
+}
+
+type SyncListRequest struct {
+	Movies []SyncListItem `json:"movies,omitempty"`
+	Shows  []SyncListItem `json:"shows,omitempty"`
+}
+
+// AddToList adds items to a status list, moving them if they are already
+// in another one.
+func (c *Client) AddToList(clientID, accessToken string, req SyncListRequest) error {
+	_, err := c.post(apiCredentials{clientID: clientID, accessToken: accessToken}, "/sync/add-to-list", req, nil)
+	return err
+}
+
+// RemoveFromHistory removes watched movies or episodes. Movies and shows sent
+// without seasons are removed from the user's lists entirely.
+func (c *Client) RemoveFromHistory(clientID, accessToken string, req SyncHistoryRequest) error {
+	_, err := c.post(apiCredentials{clientID: clientID, accessToken: accessToken}, "/sync/history/remove", req, nil)
+	return err
+}
+
 func (c *Client) GetActivities(clientID, accessToken string) (ActivityResponse, error) {
 	var out ActivityResponse
 	if err := c.get(apiCredentials{clientID: clientID, accessToken: accessToken}, "/sync/activities", nil, &out); err != nil {
@@ -329,6 +329,56 @@ func TestGetListItemsEmptyStatusReturnsAll(t *testing.T) {
 	}
 }
 
+func TestAddToListSendsTargetList(t *testing.T) {
+	client := NewClient()
+	client.SetHTTPClientForTest(&http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if r.Method != http.MethodPost || r.URL.Path != "/sync/add-to-list" {
+				t.Fatalf("request = %s %s, want POST /sync/add-to-list", r.Method, r.URL.Path)
+			}
+			body, _ := io.ReadAll(r.Body)
+			if !strings.Contains(string(body), `"to":"plantowatch"`) || !strings.Contains(string(body), `"tmdb":83867`) {
+				t.Fatalf("unexpected body: %s", string(body))
+			}
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       io.NopCloser(strings.NewReader(`{"added":{"shows":1}}`)),
+				Header:     make(http.Header),
+			}, nil
+		}),
+	})
+
+	err := client.AddToList("client-id", "token", SyncListRequest{
+		Shows: []SyncListItem{{To: "plantowatch", Title: "Andor", Year: 2022, IDs: IDs{TMDB: 83867}}},
+	})
+	if err != nil {
+		t.Fatalf("AddToList() error = %v", err)
+	}
+}
+
+func TestRemoveFromHistoryReportsErrors(t *testing.T) {
+	client := NewClient()
+	client.SetHTTPClientForTest(&http.Client{
+		Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			if r.URL.Path != "/sync/history/remove" {
+				t.Fatalf("path = %q, want /sync/history/remove", r.URL.Path)
+			}
+			return &http.Response{
+				StatusCode: http.StatusUnauthorized,
+				Body:       io.NopCloser(strings.NewReader(`{"error":"user_token_failed"}`)),
+				Header:     make(http.Header),
+			}, nil
+		}),
+	})
+
+	err := client.RemoveFromHistory("client-id", "token", SyncHistoryRequest{
+		Movies: []SyncHistoryMovie{{Title: "Inception", IDs: IDs{IMDB: "tt1375666"}}},
+	})
+	if err == nil {
+		t.Fatal("expected error for unauthorized response")
+	}
+}
+
 func TestGetListItemsRejectsBadInput(t *testing.T) {
 	client := NewClient()
 	if _, err := client.GetListItems("c", "t", "books", ""); err == nil {
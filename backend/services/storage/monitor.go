@@ -0,0 +1,259 @@
+// Package storage watches free space on the volumes mediastorm writes to and
+// pauses disk-hungry work before a volume fills up.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"novastream/config"
+	"novastream/services/notifications"
+	"novastream/utils/locale"
+)
+
+// Monitored volume names.
+const (
+	VolumeCache      = "cache"
+	VolumeTrailers   = "trailers"
+	VolumeRecordings = "recordings"
+	VolumeBackups    = "backups"
+)
+
+const (
+	defaultCheckInterval = 5 * time.Minute
+	notificationTimeout  = 30 * time.Second
+)
+
+// ErrLowDiskSpace is returned by CheckSpace while a volume is below the
+// configured free space threshold.
+var ErrLowDiskSpace = errors.New("low disk space")
+
+// Volume is a named directory whose filesystem is monitored.
+type Volume struct {
+	Name string
+	Path string
+}
+
+// VolumeStatus is the result of the last check of a volume.
+type VolumeStatus struct {
+	Name       string    `json:"name"`
+	Path       string    `json:"path"`
+	TotalBytes uint64    `json:"totalBytes"`
+	FreeBytes  uint64    `json:"freeBytes"`
+	Low        bool      `json:"low"`
+	Error      string    `json:"error,omitempty"`
+	CheckedAt  time.Time `json:"checkedAt"`
+}
+
+type settingsLoader interface {
+	Load() (config.Settings, error)
+}
+
+type diskNotifier interface {
+	Send(ctx context.Context, target config.TaskNotification, e notifications.Event) error
+}
+
+// Monitor periodically checks free space on its volumes.
+type Monitor struct {
+	settings settingsLoader
+	notifier diskNotifier
+	stat     func(path string) (total, free uint64, err error)
+	volumes  []Volume
+
+	mu     sync.RWMutex
+	status map[string]VolumeStatus
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+// NewMonitor creates a monitor for the given volumes. Volumes with an empty
+// path are ignored.
+func NewMonitor(settings settingsLoader, volumes ...Volume) *Monitor {
+	m := &Monitor{
+		settings: settings,
+		stat:     statVolume,
+		status:   make(map[string]VolumeStatus),
+	}
+	for _, v := range volumes {
+		if v.Path != "" {
+			m.volumes = append(m.volumes, v)
+		}
+	}
+	return m
+}
+
+// SetNotifier sets where low space alerts are sent.
+func (m *Monitor) SetNotifier(notifier diskNotifier) {
+	m.notifier = notifier
+}
+
+// Start checks every volume immediately and then on the configured interval.
+func (m *Monitor) Start() {
+	if m.stopCh != nil {
+		return
+	}
+	m.stopCh = make(chan struct{})
+	m.wg.Add(1)
+	go m.loop()
+}
+
+// Stop ends the background checks.
+func (m *Monitor) Stop() {
+	if m.stopCh == nil {
+		return
+	}
+	close(m.stopCh)
+	m.wg.Wait()
+	m.stopCh = nil
+}
+
+func (m *Monitor) loop() {
+	defer m.wg.Done()
+	for {
+		interval := m.Check()
+		select {
+		case <-m.stopCh:
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Check stats every volume, records the result and notifies on volumes that
+// crossed the threshold since the last check. It returns the interval until
+// the next scheduled check.
+func (m *Monitor) Check() time.Duration {
+	var storage config.StorageSettings
+	var display config.DisplaySettings
+	if m.settings != nil {
+		if settings, err := m.settings.Load(); err == nil {
+			storage = settings.Storage
+			display = settings.Display
+		} else {
+			log.Printf("[storage] failed to load settings: %v", err)
+		}
+	}
+	threshold := uint64(storage.MinFreeGB) << 30
+	now := time.Now().UTC()
+
+	var changed []VolumeStatus
+	for _, v := range m.volumes {
+		st := VolumeStatus{Name: v.Name, Path: v.Path, CheckedAt: now}
+		total, free, err := m.stat(existingDir(v.Path))
+		if err != nil {
+			st.Error = err.Error()
+		} else {
+			st.TotalBytes, st.FreeBytes = total, free
+			st.Low = threshold > 0 && free < threshold
+		}
+
+		m.mu.Lock()
+		prev, seen := m.status[v.Name]
+		m.status[v.Name] = st
+		m.mu.Unlock()
+
+		if st.Error == "" && st.Low != prev.Low && (seen || st.Low) {
+			if st.Low {
+				log.Printf("[storage] %s volume (%s) is low on space: %d MB free, threshold %d GB; pausing disk-heavy work", v.Name, v.Path, free>>20, storage.MinFreeGB)
+			} else {
+				log.Printf("[storage] %s volume (%s) recovered: %d MB free", v.Name, v.Path, free>>20)
+			}
+			changed = append(changed, st)
+		}
+	}
+
+	if len(changed) > 0 {
+		m.notify(storage, locale.Instance(display), threshold, changed)
+	}
+
+	if storage.CheckIntervalSeconds > 0 {
+		return time.Duration(storage.CheckIntervalSeconds) * time.Second
+	}
+	return defaultCheckInterval
+}
+
+// existingDir returns path or its nearest existing ancestor, since a volume's
+// directory may not have been created yet.
+func existingDir(path string) string {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+// notify sends threshold crossings to the configured targets in the
+// background; delivery failures are only logged.
+func (m *Monitor) notify(storage config.StorageSettings, l locale.Locale, threshold uint64, changed []VolumeStatus) {
+	if m.notifier == nil || len(storage.Notifications) == 0 {
+		return
+	}
+	var events []notifications.Event
+	for _, st := range changed {
+		events = append(events, notifications.DiskSpaceEvent(l, st.Name, st.Path, st.FreeBytes, threshold, st.Low, st.CheckedAt))
+	}
+	targets := storage.Notifications
+	go func() {
+		for _, e := range events {
+			for _, target := range targets {
+				if !notifications.Wants(target, e) {
+					continue
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+				if err := m.notifier.Send(ctx, target, e); err != nil {
+					log.Printf("[storage] failed to send %s notification: %v", target.Type, err)
+				}
+				cancel()
+			}
+		}
+	}()
+}
+
+// Status returns the last check result for every volume.
+func (m *Monitor) Status() []VolumeStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	out := make([]VolumeStatus, 0, len(m.volumes))
+	for _, v := range m.volumes {
+		if st, ok := m.status[v.Name]; ok {
+			out = append(out, st)
+		} else {
+			out = append(out, VolumeStatus{Name: v.Name, Path: v.Path})
+		}
+	}
+	return out
+}
+
+// CheckSpace returns an error wrapping ErrLowDiskSpace if the named volume
+// was below the threshold at the last check. Unknown or unchecked volumes
+// are allowed.
+func (m *Monitor) CheckSpace(volume string) error {
+	if m == nil {
+		return nil
+	}
+	m.mu.RLock()
+	st, ok := m.status[volume]
+	m.mu.RUnlock()
+	if !ok || !st.Low {
+		return nil
+	}
+	return fmt.Errorf("%w on %s volume (%d MB free)", ErrLowDiskSpace, volume, st.FreeBytes>>20)
+}
+
+// SpaceCheck returns a function that checks a single volume, for services
+// that only write to one.
+func (m *Monitor) SpaceCheck(volume string) func() error {
+	return func() error { return m.CheckSpace(volume) }
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"novastream/config"
+	"novastream/services/notifications"
+)
+
+type fakeSettings struct{ settings config.Settings }
+
+func (f fakeSettings) Load() (config.Settings, error) { return f.settings, nil }
+
+type recordingNotifier struct {
+	mu     sync.Mutex
+	events []notifications.Event
+	sent   chan struct{}
+}
+
+func (n *recordingNotifier) Send(_ context.Context, _ config.TaskNotification, e notifications.Event) error {
+	n.mu.Lock()
+	n.events = append(n.events, e)
+	n.mu.Unlock()
+	n.sent <- struct{}{}
+	return nil
+}
+
+func (n *recordingNotifier) wait(t *testing.T) notifications.Event {
+	t.Helper()
+	select {
+	case <-n.sent:
+	case <-time.After(2 * time.Second):
+		t.Fatal("notification not sent")
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.events[len(n.events)-1]
+}
+
+func TestMonitorPausesAndNotifiesOnLowSpace(t *testing.T) {
+	settings := config.Settings{Storage: config.StorageSettings{
+		MinFreeGB:            5,
+		CheckIntervalSeconds: 60,
+		Notifications: []config.TaskNotification{
+			{Type: config.TaskNotificationTypeWebhook, URL: "https://example.com/hook", OnSuccess: true, OnFailure: true},
+		},
+	}}
+	dir := t.TempDir()
+	m := NewMonitor(fakeSettings{settings}, Volume{Name: VolumeBackups, Path: dir + "/backups"}, Volume{Name: VolumeCache, Path: ""})
+	notifier := &recordingNotifier{sent: make(chan struct{}, 4)}
+	m.SetNotifier(notifier)
+
+	free := uint64(2 << 30)
+	var statted string
+	m.stat = func(path string) (uint64, uint64, error) {
+		statted = path
+		return 100 << 30, free, nil
+	}
+
+	if got := m.Check(); got != time.Minute {
+		t.Fatalf("Check() interval = %v, want 1m", got)
+	}
+	if statted != dir {
+		t.Fatalf("stat path = %q, want nearest existing parent %q", statted, dir)
+	}
+	if len(m.Status()) != 1 {
+		t.Fatalf("volumes with empty paths should be ignored, got %+v", m.Status())
+	}
+	err := m.CheckSpace(VolumeBackups)
+	if !errors.Is(err, ErrLowDiskSpace) {
+		t.Fatalf("CheckSpace() = %v, want ErrLowDiskSpace", err)
+	}
+	if e := notifier.wait(t); e.Success || e.TaskType != notifications.DiskSpaceEventType {
+		t.Fatalf("low space event = %+v", e)
+	}
+
+	// Staying low does not notify again.
+	m.Check()
+	select {
+	case <-notifier.sent:
+		t.Fatal("unexpected repeat notification")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	free = 20 << 30
+	m.Check()
+	if err := m.CheckSpace(VolumeBackups); err != nil {
+		t.Fatalf("CheckSpace() after recovery = %v", err)
+	}
+	if e := notifier.wait(t); !e.Success {
+		t.Fatalf("recovery event = %+v", e)
+	}
+
+	if err := m.CheckSpace("unknown"); err != nil {
+		t.Fatalf("unknown volumes should be allowed, got %v", err)
+	}
+}
+
+func TestMonitorThresholdZeroDisablesGuardrails(t *testing.T) {
+	m := NewMonitor(fakeSettings{}, Volume{Name: VolumeCache, Path: t.TempDir()})
+	m.stat = func(string) (uint64, uint64, error) { return 100, 0, nil }
+
+	if got := m.Check(); got != defaultCheckInterval {
+		t.Fatalf("Check() interval = %v, want default", got)
+	}
+	if err := m.CheckSpace(VolumeCache); err != nil {
+		t.Fatalf("CheckSpace() = %v, want nil with no threshold", err)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package storage
+
+import "errors"
+
+func statVolume(path string) (total, free uint64, err error) {
+	return 0, 0, errors.New("free space checks are not supported on this platform")
+}
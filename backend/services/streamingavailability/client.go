@@ -0,0 +1,201 @@
+// Package streamingavailability reads per-country streaming catalogs from the
+// Streaming Availability API (movieofthenight.com, served through RapidAPI).
+// Unlike TMDB's watch providers it reports when a title is due to leave a
+// service, which drives the "Leaving soon" shelf and alerts.
+package streamingavailability
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"novastream/models"
+)
+
+const (
+	defaultBaseURL = "https://streaming-availability.p.rapidapi.com"
+	rapidAPIHost   = "streaming-availability.p.rapidapi.com"
+)
+
+// catalogTTL is how long a title's departures are reused. Services announce
+// departures weeks ahead, so twice a day is plenty.
+const catalogTTL = 12 * time.Hour
+
+// ErrNotConfigured is returned when no API key is set.
+var ErrNotConfigured = errors.New("streaming availability not configured")
+
+// Client is a Streaming Availability API client. The API key can be changed
+// at runtime when settings are saved.
+type Client struct {
+	mu         sync.RWMutex
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+
+	cacheMu sync.Mutex
+	cache   map[string]cachedDepartures
+}
+
+type cachedDepartures struct {
+	departures []models.ProviderDeparture
+	expires    time.Time
+}
+
+// NewClient creates a client. An empty key leaves it unconfigured.
+func NewClient(apiKey string) *Client {
+	c := &Client{
+		baseURL:    defaultBaseURL,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+		cache:      make(map[string]cachedDepartures),
+	}
+	c.Configure(apiKey)
+	return c
+}
+
+// Configure updates the API key (e.g., when settings change) and drops
+// cached lookups.
+func (c *Client) Configure(apiKey string) {
+	c.mu.Lock()
+	c.apiKey = strings.TrimSpace(apiKey)
+	c.mu.Unlock()
+
+	c.cacheMu.Lock()
+	c.cache = make(map[string]cachedDepartures)
+	c.cacheMu.Unlock()
+}
+
+// SetBaseURLForTest points the client at a test server.
+func (c *Client) SetBaseURLForTest(baseURL string) {
+	c.mu.Lock()
+	c.baseURL = strings.TrimRight(baseURL, "/")
+	c.mu.Unlock()
+}
+
+// IsConfigured reports whether an API key is set.
+func (c *Client) IsConfigured() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.apiKey != ""
+}
+
+type showResponse struct {
+	StreamingOptions map[string][]streamingOption `json:"streamingOptions"`
+}
+
+type streamingOption struct {
+	Service struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"service"`
+	Type      string `json:"type"` // subscription, free, addon, rent, buy
+	Link      string `json:"link"`
+	ExpiresOn int64  `json:"expiresOn"` // unix seconds; 0 when no end date is announced
+}
+
+// Departures returns the services in country (ISO 3166-1) that have
+// announced an end date for the title with the given IMDB ID. Rentals and
+// purchases are skipped; only catalog (subscription, free and add-on)
+// availability expires.
+func (c *Client) Departures(ctx context.Context, imdbID, country string) ([]models.ProviderDeparture, error) {
+	imdbID = strings.TrimSpace(imdbID)
+	if !strings.HasPrefix(imdbID, "tt") {
+		return nil, errors.New("imdb id required")
+	}
+	country = strings.ToLower(strings.TrimSpace(country))
+	if len(country) != 2 {
+		country = "us"
+	}
+	key := country + ":" + imdbID
+	c.cacheMu.Lock()
+	if cached, ok := c.cache[key]; ok && time.Now().Before(cached.expires) {
+		c.cacheMu.Unlock()
+		return cached.departures, nil
+	}
+	c.cacheMu.Unlock()
+
+	var resp showResponse
+	if err := c.get(ctx, "/shows/"+url.PathEscape(imdbID), url.Values{"country": {country}}, &resp); err != nil {
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+			return nil, err
+		}
+		// Titles the API doesn't track have nothing leaving.
+	}
+
+	var departures []models.ProviderDeparture
+	seen := make(map[string]bool)
+	for _, option := range resp.StreamingOptions[country] {
+		if option.ExpiresOn <= 0 || option.Service.ID == "" || seen[option.Service.ID] {
+			continue
+		}
+		switch option.Type {
+		case "subscription", "free", "addon":
+		default:
+			continue
+		}
+		seen[option.Service.ID] = true
+		departures = append(departures, models.ProviderDeparture{
+			ServiceID: option.Service.ID,
+			Service:   option.Service.Name,
+			Type:      option.Type,
+			Link:      option.Link,
+			LeavesAt:  time.Unix(option.ExpiresOn, 0).UTC(),
+		})
+	}
+	sort.SliceStable(departures, func(i, j int) bool { return departures[i].LeavesAt.Before(departures[j].LeavesAt) })
+
+	c.cacheMu.Lock()
+	c.cache[key] = cachedDepartures{departures: departures, expires: time.Now().Add(catalogTTL)}
+	c.cacheMu.Unlock()
+	return departures, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	c.mu.RLock()
+	baseURL, apiKey := c.baseURL, c.apiKey
+	c.mu.RUnlock()
+	if apiKey == "" {
+		return ErrNotConfigured
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("X-RapidAPI-Key", apiKey)
+	req.Header.Set("X-RapidAPI-Host", rapidAPIHost)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("streaming availability api request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(msg))}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// APIError is a non-2xx response from the API.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("streaming availability api returned %d: %s", e.StatusCode, e.Message)
+}
@@ -0,0 +1,101 @@
+package streamingavailability
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"novastream/config"
+	"novastream/models"
+)
+
+func TestDeparturesAndLeavingSoon(t *testing.T) {
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	in := func(days int) int64 { return now.Add(time.Duration(days) * 24 * time.Hour).Unix() }
+	var lookups int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-RapidAPI-Key") != "key" || r.URL.Query().Get("country") != "gb" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		lookups++
+		switch r.URL.Path {
+		case "/shows/tt0000001":
+			fmt.Fprintf(w, `{"streamingOptions":{"gb":[
+				{"service":{"id":"prime","name":"Prime Video"},"type":"subscription","expiresOn":%d},
+				{"service":{"id":"netflix","name":"Netflix"},"type":"subscription","link":"https://netflix.com/title/1","expiresOn":%d},
+				{"service":{"id":"apple","name":"Apple TV"},"type":"rent","expiresOn":%d},
+				{"service":{"id":"disney","name":"Disney+"},"type":"subscription"}
+			]}}`, in(20), in(5), in(2))
+		case "/shows/tt0000002":
+			fmt.Fprintf(w, `{"streamingOptions":{"gb":[{"service":{"id":"netflix","name":"Netflix"},"type":"subscription","expiresOn":%d}]}}`, in(40))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	if NewClient("").IsConfigured() {
+		t.Fatal("expected client without key to be unconfigured")
+	}
+	client := NewClient("key")
+	client.SetBaseURLForTest(server.URL)
+	ctx := context.Background()
+
+	departures, err := client.Departures(ctx, "tt0000001", "GB")
+	if err != nil {
+		t.Fatalf("Departures() error = %v", err)
+	}
+	if len(departures) != 2 || departures[0].ServiceID != "netflix" || departures[1].ServiceID != "prime" {
+		t.Fatalf("expected netflix then prime catalog departures, got %+v", departures)
+	}
+	if _, err := client.Departures(ctx, "tt0000001", "gb"); err != nil || lookups != 1 {
+		t.Fatalf("expected cached lookup, got %d lookups err=%v", lookups, err)
+	}
+	if departures, err := client.Departures(ctx, "tt9999999", "gb"); err != nil || len(departures) != 0 {
+		t.Fatalf("expected untracked title to have no departures, got %+v err=%v", departures, err)
+	}
+
+	items := []models.WatchlistItem{
+		{ID: "1", Name: "Leaving", ExternalIDs: map[string]string{"imdb": "tt0000001"}},
+		{ID: "2", Name: "Later", ExternalIDs: map[string]string{"imdb": "tt0000002"}},
+		{ID: "3", Name: "No IMDB"},
+	}
+	opts := Options{Country: "GB", Now: now, Within: 14 * 24 * time.Hour}
+	leaving := client.LeavingSoon(ctx, items, opts)
+	if len(leaving) != 1 || leaving[0].ID != "1" || leaving[0].Leaving == nil || leaving[0].Leaving.ServiceID != "netflix" {
+		t.Fatalf("expected only item 1 leaving netflix, got %+v", leaving)
+	}
+	if items[0].Leaving != nil {
+		t.Fatal("LeavingSoon modified the input items")
+	}
+
+	opts.Services = []string{"Prime"}
+	opts.Within = 30 * 24 * time.Hour
+	leaving = client.LeavingSoon(ctx, items, opts)
+	if len(leaving) != 1 || leaving[0].Leaving.ServiceID != "prime" {
+		t.Fatalf("expected item 1 leaving subscribed prime, got %+v", leaving)
+	}
+}
+
+func TestOptionsForPrefersProfile(t *testing.T) {
+	var settings config.Settings
+	settings.StreamingAvailability.Services = []string{"netflix"}
+
+	opts := OptionsFor(settings, nil)
+	if opts.Country != "US" || len(opts.Services) != 1 || opts.Within != 14*24*time.Hour {
+		t.Fatalf("unexpected defaults %+v", opts)
+	}
+
+	settings.Metadata.CertificationCountry = "DE"
+	profile := &models.UserSettings{}
+	profile.Metadata.CertificationCountry = "GB"
+	profile.Metadata.StreamingServices = []string{"prime", "disney"}
+	opts = OptionsFor(settings, profile)
+	if opts.Country != "GB" || len(opts.Services) != 2 {
+		t.Fatalf("expected profile country and services, got %+v", opts)
+	}
+}
@@ -0,0 +1,115 @@
+package streamingavailability
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"novastream/config"
+	"novastream/models"
+)
+
+// lookupWorkers bounds concurrent API lookups when scanning a watchlist.
+const lookupWorkers = 4
+
+// Options selects which departures count as leaving soon.
+type Options struct {
+	Country  string    // ISO 3166-1; empty means US
+	Services []string  // service IDs the profile subscribes to; empty means any
+	Now      time.Time // zero means time.Now
+	Within   time.Duration
+}
+
+// OptionsFor resolves a profile's leaving-soon options. The profile's own
+// rating country and services win over the server's; the country falls back
+// to US.
+func OptionsFor(settings config.Settings, profile *models.UserSettings) Options {
+	opts := Options{
+		Country:  settings.Metadata.CertificationCountry,
+		Services: settings.StreamingAvailability.Services,
+		Within:   settings.StreamingAvailability.LeavingSoonWindow(),
+	}
+	if profile != nil {
+		if country := strings.TrimSpace(profile.Metadata.CertificationCountry); country != "" {
+			opts.Country = country
+		}
+		if len(profile.Metadata.StreamingServices) > 0 {
+			opts.Services = profile.Metadata.StreamingServices
+		}
+	}
+	if strings.TrimSpace(opts.Country) == "" {
+		opts.Country = "US"
+	}
+	return opts
+}
+
+// LeavingSoon returns copies of the items that leave one of opts.Services
+// within opts.Within, soonest first, each carrying its earliest departure.
+// Items without an IMDB ID are skipped, as are lookups that fail.
+func (c *Client) LeavingSoon(ctx context.Context, items []models.WatchlistItem, opts Options) []models.WatchlistItem {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	deadline := now.Add(opts.Within)
+	subscribed := make(map[string]bool, len(opts.Services))
+	for _, id := range opts.Services {
+		if id = strings.ToLower(strings.TrimSpace(id)); id != "" {
+			subscribed[id] = true
+		}
+	}
+
+	var (
+		mu      sync.Mutex
+		leaving []models.WatchlistItem
+		wg      sync.WaitGroup
+		sem     = make(chan struct{}, lookupWorkers)
+	)
+	for _, item := range items {
+		imdbID := strings.TrimSpace(item.ExternalIDs["imdb"])
+		if imdbID == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(item models.WatchlistItem, imdbID string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if ctx.Err() != nil {
+				return
+			}
+			departures, err := c.Departures(ctx, imdbID, opts.Country)
+			if err != nil {
+				log.Printf("[streaming-availability] departures for %s failed: %v", imdbID, err)
+				return
+			}
+			for _, departure := range departures {
+				if len(subscribed) > 0 && !subscribed[strings.ToLower(departure.ServiceID)] {
+					continue
+				}
+				if departure.LeavesAt.Before(now) || departure.LeavesAt.After(deadline) {
+					continue
+				}
+				// Departures are sorted, so the first match is the earliest.
+				departure := departure
+				item.Leaving = &departure
+				mu.Lock()
+				leaving = append(leaving, item)
+				mu.Unlock()
+				return
+			}
+		}(item, imdbID)
+	}
+	wg.Wait()
+
+	sort.SliceStable(leaving, func(i, j int) bool {
+		if !leaving[i].Leaving.LeavesAt.Equal(leaving[j].Leaving.LeavesAt) {
+			return leaving[i].Leaving.LeavesAt.Before(leaving[j].Leaving.LeavesAt)
+		}
+		return leaving[i].Name < leaving[j].Name
+	})
+	return leaving
+}
@@ -0,0 +1,160 @@
+package trakt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"novastream/config"
+	"novastream/services/metadata"
+)
+
+const (
+	// userListPageSize is the page size used when reading a public user list.
+	userListPageSize = 100
+	// maxUserListItems bounds how many entries of a public user list are read.
+	maxUserListItems = 1000
+)
+
+// ParseUserListURL extracts the user and list slug from a public Trakt list
+// URL such as https://trakt.tv/users/{user}/lists/{slug}.
+func ParseUserListURL(rawURL string) (user, list string, ok bool) {
+	rawURL = strings.TrimSpace(rawURL)
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", false
+	}
+	switch strings.ToLower(u.Hostname()) {
+	case "trakt.tv", "www.trakt.tv", "app.trakt.tv":
+	default:
+		return "", "", false
+	}
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) < 4 || parts[0] != "users" || parts[2] != "lists" || parts[1] == "" || parts[3] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[3], true
+}
+
+// IsUserListURL reports whether rawURL is a public Trakt user list URL.
+func IsUserListURL(rawURL string) bool {
+	_, _, ok := ParseUserListURL(rawURL)
+	return ok
+}
+
+// GetPublicUserListItems retrieves a page of another user's public list.
+// Only the app client ID is needed; no account has to be authorized.
+func (c *Client) GetPublicUserListItems(ctx context.Context, clientID, user, list string, page, limit int) ([]ListItem, int, error) {
+	if strings.TrimSpace(clientID) == "" {
+		return nil, 0, fmt.Errorf("trakt client id is required")
+	}
+
+	endpoint := fmt.Sprintf("%s/users/%s/lists/%s/items?page=%d&limit=%d",
+		traktAPIBaseURL, url.PathEscape(user), url.PathEscape(list), page, limit)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("trakt-api-version", traktAPIVersion)
+	req.Header.Set("trakt-api-key", clientID)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("trakt api request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, 0, fmt.Errorf("trakt list %s/%s: %w", user, list, ErrNotFound)
+	}
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, 0, fmt.Errorf("trakt list %s/%s failed: %s - %s", user, list, resp.Status, string(respBody))
+	}
+
+	totalCount := 0
+	if totalHeader := resp.Header.Get("X-Pagination-Item-Count"); totalHeader != "" {
+		totalCount, _ = strconv.Atoi(totalHeader)
+	}
+
+	var items []ListItem
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		return nil, 0, fmt.Errorf("decode response: %w", err)
+	}
+	return items, totalCount, nil
+}
+
+// ListProvider reads public Trakt user lists for custom shelves.
+type ListProvider struct {
+	client     *Client
+	cfgManager *config.Manager
+}
+
+// NewListProvider creates a list provider that authenticates with the client
+// ID of the first configured Trakt account.
+func NewListProvider(client *Client, cfgManager *config.Manager) *ListProvider {
+	return &ListProvider{client: client, cfgManager: cfgManager}
+}
+
+// IsListURL implements the metadata service's Trakt list source.
+func (p *ListProvider) IsListURL(rawURL string) bool {
+	return IsUserListURL(rawURL)
+}
+
+// GetListItems reads every movie and show on a public Trakt list in list
+// order. Seasons, episodes and people on the list are skipped.
+func (p *ListProvider) GetListItems(ctx context.Context, rawURL string) ([]metadata.CuratedItem, error) {
+	user, list, ok := ParseUserListURL(rawURL)
+	if !ok {
+		return nil, fmt.Errorf("invalid trakt list url %q", rawURL)
+	}
+	clientID, err := configuredClientID(p.cfgManager)
+	if err != nil {
+		return nil, err
+	}
+
+	var curated []metadata.CuratedItem
+	read := 0
+	for page := 1; read < maxUserListItems; page++ {
+		items, total, err := p.client.GetPublicUserListItems(ctx, clientID, user, list, page, userListPageSize)
+		if err != nil {
+			return nil, err
+		}
+		read += len(items)
+		for _, item := range items {
+			switch {
+			case item.Type == "movie" && item.Movie != nil:
+				curated = append(curated, metadata.CuratedItem{
+					Title:     item.Movie.Title,
+					Year:      item.Movie.Year,
+					IMDBID:    item.Movie.IDs.IMDB,
+					TMDBID:    int64(item.Movie.IDs.TMDB),
+					TVDBID:    int64(item.Movie.IDs.TVDB),
+					MediaType: "movie",
+				})
+			case item.Type == "show" && item.Show != nil:
+				curated = append(curated, metadata.CuratedItem{
+					Title:     item.Show.Title,
+					Year:      item.Show.Year,
+					IMDBID:    item.Show.IDs.IMDB,
+					TMDBID:    int64(item.Show.IDs.TMDB),
+					TVDBID:    int64(item.Show.IDs.TVDB),
+					MediaType: "show",
+				})
+			}
+		}
+		if len(items) == 0 || read >= total {
+			break
+		}
+	}
+	return curated, nil
+}
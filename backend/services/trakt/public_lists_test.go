@@ -0,0 +1,75 @@
+package trakt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"novastream/config"
+)
+
+func TestParseUserListURL(t *testing.T) {
+	tests := []struct {
+		url        string
+		user, list string
+		ok         bool
+	}{
+		{"https://trakt.tv/users/someone/lists/best-of-2024", "someone", "best-of-2024", true},
+		{"https://app.trakt.tv/users/someone/lists/best-of-2024?sort=rank,asc", "someone", "best-of-2024", true},
+		{"trakt.tv/users/someone/lists/12345/", "someone", "12345", true},
+		{"https://trakt.tv/users/someone/watchlist", "", "", false},
+		{"https://mdblist.com/lists/someone/best-of-2024", "", "", false},
+	}
+	for _, tc := range tests {
+		user, list, ok := ParseUserListURL(tc.url)
+		if user != tc.user || list != tc.list || ok != tc.ok {
+			t.Errorf("ParseUserListURL(%q) = %q, %q, %t; want %q, %q, %t", tc.url, user, list, ok, tc.user, tc.list, tc.ok)
+		}
+	}
+}
+
+func TestListProviderGetListItems(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/someone/lists/crime/items" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Header.Get("trakt-api-key") != "account-client-id" || r.Header.Get("Authorization") != "" {
+			t.Errorf("expected an unauthenticated request with the account client id")
+		}
+		w.Header().Set("X-Pagination-Item-Count", "3")
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Write([]byte(`[{"rank":1,"type":"movie","movie":{"title":"Heat","year":1995,"ids":{"imdb":"tt0113277","tmdb":949}}},
+				{"rank":2,"type":"season","show":{"title":"The Wire","year":2002,"ids":{"tmdb":1438}},"season":{"number":1}}]`))
+		case "2":
+			w.Write([]byte(`[{"rank":3,"type":"show","show":{"title":"The Wire","year":2002,"ids":{"imdb":"tt0306414","tmdb":1438,"tvdb":79126}}}]`))
+		default:
+			w.Write([]byte(`[]`))
+		}
+	}))
+	defer server.Close()
+
+	origURL := traktAPIBaseURL
+	defer func() { setBaseURL(origURL) }()
+	setBaseURL(server.URL)
+
+	mgr := newTestConfigManager(t, config.Settings{
+		Trakt: config.TraktSettings{Accounts: []config.TraktAccount{{ID: "acc1", ClientID: "account-client-id"}}},
+	})
+	provider := NewListProvider(NewClient("", ""), mgr)
+
+	// Page 1 holds two of the three items, so the provider must read page 2.
+	curated, err := provider.GetListItems(context.Background(), "https://trakt.tv/users/someone/lists/crime")
+	if err != nil {
+		t.Fatalf("GetListItems: %v", err)
+	}
+	if len(curated) != 2 {
+		t.Fatalf("expected the season entry to be skipped, got %+v", curated)
+	}
+	if curated[0].TMDBID != 949 || curated[0].MediaType != "movie" || curated[1].TVDBID != 79126 || curated[1].MediaType != "show" {
+		t.Fatalf("unexpected items: %+v", curated)
+	}
+}
@@ -0,0 +1,92 @@
+package trakt
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// recommendationsLimit is the most recommendations Trakt returns per type.
+const recommendationsLimit = 100
+
+// GetRecommendedMovies returns Trakt's personalized movie recommendations for
+// the authenticated user, best match first. Collected titles are left out.
+func (c *Client) GetRecommendedMovies(accessToken string) ([]Movie, error) {
+	var movies []Movie
+	if err := c.getRecommendations(accessToken, "movies", &movies); err != nil {
+		return nil, err
+	}
+	return movies, nil
+}
+
+// GetRecommendedShows is GetRecommendedMovies for shows.
+func (c *Client) GetRecommendedShows(accessToken string) ([]Show, error) {
+	var shows []Show
+	if err := c.getRecommendations(accessToken, "shows", &shows); err != nil {
+		return nil, err
+	}
+	return shows, nil
+}
+
+func (c *Client) getRecommendations(accessToken, mediaType string, out any) error {
+	endpoint := fmt.Sprintf("%s/recommendations/%s?ignore_collected=true&limit=%d", traktAPIBaseURL, mediaType, recommendationsLimit)
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	c.setTraktHeaders(req, accessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("trakt api request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("trakt %s recommendations failed: %s - %s", mediaType, resp.Status, string(respBody))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// HideRecommendation stops Trakt from recommending a title to the
+// authenticated user again. mediaType is "movies" or "shows"; id is a Trakt
+// ID, slug or IMDB ID.
+func (c *Client) HideRecommendation(accessToken, mediaType, id string) error {
+	if mediaType != "movies" && mediaType != "shows" {
+		return fmt.Errorf("unsupported trakt media type %q", mediaType)
+	}
+	if id == "" {
+		return fmt.Errorf("trakt id required")
+	}
+
+	endpoint := fmt.Sprintf("%s/recommendations/%s/%s", traktAPIBaseURL, mediaType, url.PathEscape(id))
+	req, err := http.NewRequest(http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	c.setTraktHeaders(req, accessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("trakt api request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("trakt hide recommendation failed: %s - %s", resp.Status, string(respBody))
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package trakt
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetRecommendations(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			t.Errorf("expected Authorization header")
+		}
+		if r.URL.Query().Get("ignore_collected") != "true" {
+			t.Errorf("expected ignore_collected=true, got %q", r.URL.RawQuery)
+		}
+		switch r.URL.Path {
+		case "/recommendations/movies":
+			w.Write([]byte(`[{"title":"Arrival","year":2016,"ids":{"trakt":1,"imdb":"tt2543164","tmdb":329865}}]`))
+		case "/recommendations/shows":
+			w.Write([]byte(`[{"title":"Severance","year":2022,"ids":{"trakt":2,"imdb":"tt11280740","tvdb":371980}}]`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	origURL := traktAPIBaseURL
+	defer func() { setBaseURL(origURL) }()
+	setBaseURL(server.URL)
+
+	client := NewClient("test-client-id", "test-secret")
+	movies, err := client.GetRecommendedMovies("test-token")
+	if err != nil {
+		t.Fatalf("GetRecommendedMovies: %v", err)
+	}
+	if len(movies) != 1 || movies[0].IDs.IMDB != "tt2543164" {
+		t.Fatalf("unexpected movies: %+v", movies)
+	}
+	shows, err := client.GetRecommendedShows("test-token")
+	if err != nil {
+		t.Fatalf("GetRecommendedShows: %v", err)
+	}
+	if len(shows) != 1 || shows[0].IDs.TVDB != 371980 {
+		t.Fatalf("unexpected shows: %+v", shows)
+	}
+}
+
+func TestHideRecommendation(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("expected DELETE, got %s", r.Method)
+		}
+		gotPath = r.URL.Path
+		if r.URL.Path == "/recommendations/shows/tt0000000" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	origURL := traktAPIBaseURL
+	defer func() { setBaseURL(origURL) }()
+	setBaseURL(server.URL)
+
+	client := NewClient("test-client-id", "test-secret")
+	if err := client.HideRecommendation("test-token", "movies", "tt2543164"); err != nil {
+		t.Fatalf("HideRecommendation: %v", err)
+	}
+	if gotPath != "/recommendations/movies/tt2543164" {
+		t.Fatalf("unexpected path %s", gotPath)
+	}
+	if err := client.HideRecommendation("test-token", "shows", "tt0000000"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if err := client.HideRecommendation("test-token", "episodes", "1"); err == nil {
+		t.Fatal("expected error for unsupported media type")
+	}
+}
@@ -147,6 +147,67 @@ func (s *Scrobbler) ScrobbleEpisode(userID string, showTVDBID, season, episode i
 	return s.client.AddEpisodeToHistoryForShow(accessToken, showIDs, season, absoluteEpisode, watchedAtStr, episodeIDs)
 }
 
+// ScrobbleEpisodes syncs several watched episodes of one show to Trakt in a
+// single /sync/history request. Each episode keeps its own watched_at.
+func (s *Scrobbler) ScrobbleEpisodes(userID string, showTVDBID int, showExternalIDs map[string]string, episodes []models.EpisodeScrobble) error {
+	if len(episodes) == 0 {
+		return nil
+	}
+	if !s.IsEnabledForUser(userID) {
+		log.Printf("[trakt] scrobbling not enabled for user %s", userID)
+		return nil
+	}
+
+	showIDs := ShowSyncIDs(showTVDBID, showExternalIDs)
+	if showIDs == (SyncIDs{}) {
+		log.Printf("[trakt] skipping batch scrobble for user %s: no show IDs available (%d episodes)", userID, len(episodes))
+		return nil
+	}
+
+	accessToken, err := s.getAccessTokenForUser(userID)
+	if err != nil || accessToken == "" {
+		return err
+	}
+
+	// Set client credentials for this account
+	account := s.getAccountForUser(userID)
+	if account != nil {
+		s.client.UpdateCredentials(account.ClientID, account.ClientSecret)
+	}
+
+	resp, err := s.client.AddToHistory(accessToken, SyncHistoryRequest{
+		Shows: []SyncShow{{IDs: showIDs, Seasons: episodeSyncSeasons(episodes)}},
+	})
+	if err != nil {
+		return err
+	}
+	if resp != nil && resp.Added.Episodes < len(episodes) {
+		log.Printf("[trakt] batch scrobble for user %s added %d of %d episodes", userID, resp.Added.Episodes, len(episodes))
+	}
+	return nil
+}
+
+// episodeSyncSeasons groups episodes by season in first-seen order for a
+// /sync/history request.
+func episodeSyncSeasons(episodes []models.EpisodeScrobble) []SyncSeason {
+	var seasons []SyncSeason
+	index := make(map[int]int)
+	for _, episode := range episodes {
+		i, ok := index[episode.SeasonNumber]
+		if !ok {
+			i = len(seasons)
+			index[episode.SeasonNumber] = i
+			seasons = append(seasons, SyncSeason{Number: episode.SeasonNumber})
+		}
+		seasons[i].Episodes = append(seasons[i].Episodes, SyncEpisode{
+			Number:    episode.EpisodeNumber,
+			WatchedAt: episode.WatchedAt.UTC().Format(time.RFC3339),
+			IDs:       episodeSyncIDs(episode.ExternalIDs),
+		})
+	}
+	return seasons
+}
+
 // ShowSyncIDs builds show-level Trakt sync IDs from an explicit TVDB ID plus
 // whatever show identifiers are present in an external-ID map. Episodes from
 // tmdb-only metadata sources must still be addressable on Trakt.
@@ -1,6 +1,12 @@
 package trakt
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"novastream/models"
+)
 
 func TestShowSyncIDs(t *testing.T) {
 	tests := []struct {
@@ -39,3 +45,24 @@ func TestShowSyncIDs(t *testing.T) {
 		})
 	}
 }
+
+func TestEpisodeSyncSeasons(t *testing.T) {
+	at := time.Date(2024, 6, 1, 20, 0, 0, 0, time.UTC)
+	got := episodeSyncSeasons([]models.EpisodeScrobble{
+		{SeasonNumber: 1, EpisodeNumber: 9, WatchedAt: at, ExternalIDs: map[string]string{"tvdb": "121361", "episodeTvdb": "4063481"}},
+		{SeasonNumber: 1, EpisodeNumber: 10, WatchedAt: at.Add(time.Second)},
+		{SeasonNumber: 2, EpisodeNumber: 1, WatchedAt: at.Add(2 * time.Second)},
+	})
+	want := []SyncSeason{
+		{Number: 1, Episodes: []SyncEpisode{
+			{Number: 9, WatchedAt: "2024-06-01T20:00:00Z", IDs: SyncIDs{TVDB: 4063481}},
+			{Number: 10, WatchedAt: "2024-06-01T20:00:01Z"},
+		}},
+		{Number: 2, Episodes: []SyncEpisode{
+			{Number: 1, WatchedAt: "2024-06-01T20:00:02Z"},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("episodeSyncSeasons() = %+v, want %+v", got, want)
+	}
+}
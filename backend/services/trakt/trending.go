@@ -0,0 +1,157 @@
+package trakt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"novastream/config"
+	"novastream/services/metadata"
+)
+
+// publicListLimit is how many items are requested from a public Trakt chart.
+const publicListLimit = 50
+
+// PublicLists are the Trakt charts that only need an app client ID.
+var PublicLists = []string{"trending", "popular", "anticipated"}
+
+// PublicListItem is an entry from a public Trakt chart. Trending and
+// anticipated wrap the media object; popular returns it bare, which lands
+// in the embedded fields.
+type PublicListItem struct {
+	Watchers  int    `json:"watchers,omitempty"`
+	ListCount int    `json:"list_count,omitempty"`
+	Movie     *Movie `json:"movie,omitempty"`
+	Show      *Show  `json:"show,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Year      int    `json:"year,omitempty"`
+	IDs       IDs    `json:"ids"`
+}
+
+// GetPublicList fetches a public Trakt chart ("trending", "popular", "anticipated")
+// for "movies" or "shows". clientID is passed explicitly so callers don't have to
+// swap the shared client's credentials.
+func (c *Client) GetPublicList(ctx context.Context, clientID, mediaType, list string, limit int) ([]PublicListItem, error) {
+	if strings.TrimSpace(clientID) == "" {
+		return nil, errors.New("trakt client id is required")
+	}
+	if mediaType != "movies" && mediaType != "shows" {
+		return nil, fmt.Errorf("unsupported trakt media type %q", mediaType)
+	}
+	if limit <= 0 {
+		limit = publicListLimit
+	}
+
+	url := fmt.Sprintf("%s/%s/%s?page=1&limit=%d", traktAPIBaseURL, mediaType, list, limit)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("trakt-api-version", traktAPIVersion)
+	req.Header.Set("trakt-api-key", clientID)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("trakt api request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("trakt %s/%s failed: %s - %s", mediaType, list, resp.Status, string(respBody))
+	}
+
+	var items []PublicListItem
+	if err := json.NewDecoder(resp.Body).Decode(&items); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	return items, nil
+}
+
+// TrendingProvider exposes Trakt's public charts as a metadata trending source.
+type TrendingProvider struct {
+	client     *Client
+	cfgManager *config.Manager
+}
+
+// NewTrendingProvider creates a trending provider that authenticates with the
+// client ID of the first configured Trakt account.
+func NewTrendingProvider(client *Client, cfgManager *config.Manager) *TrendingProvider {
+	return &TrendingProvider{client: client, cfgManager: cfgManager}
+}
+
+// Name implements metadata.TrendingProvider.
+func (p *TrendingProvider) Name() string { return "trakt" }
+
+// Lists implements metadata.TrendingProvider.
+func (p *TrendingProvider) Lists() []string { return PublicLists }
+
+// FetchTrending implements metadata.TrendingProvider.
+func (p *TrendingProvider) FetchTrending(ctx context.Context, mediaType, list string) ([]metadata.CuratedItem, error) {
+	clientID, err := p.clientID()
+	if err != nil {
+		return nil, err
+	}
+
+	traktType := "shows"
+	if mediaType == "movie" {
+		traktType = "movies"
+	}
+	items, err := p.client.GetPublicList(ctx, clientID, traktType, list, publicListLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	curated := make([]metadata.CuratedItem, 0, len(items))
+	for _, item := range items {
+		title, year, ids := item.Title, item.Year, item.IDs
+		switch {
+		case item.Movie != nil:
+			title, year, ids = item.Movie.Title, item.Movie.Year, item.Movie.IDs
+		case item.Show != nil:
+			title, year, ids = item.Show.Title, item.Show.Year, item.Show.IDs
+		}
+		if title == "" && ids.IMDB == "" && ids.TMDB == 0 && ids.TVDB == 0 {
+			continue
+		}
+		curated = append(curated, metadata.CuratedItem{
+			Title:     title,
+			Year:      year,
+			IMDBID:    ids.IMDB,
+			TMDBID:    int64(ids.TMDB),
+			TVDBID:    int64(ids.TVDB),
+			MediaType: mediaType,
+		})
+	}
+	return curated, nil
+}
+
+func (p *TrendingProvider) clientID() (string, error) {
+	return configuredClientID(p.cfgManager)
+}
+
+// configuredClientID returns the client ID of the first configured Trakt
+// account, which is all public endpoints need.
+func configuredClientID(cfgManager *config.Manager) (string, error) {
+	if cfgManager == nil {
+		return "", errors.New("trakt settings unavailable")
+	}
+	settings, err := cfgManager.Load()
+	if err != nil {
+		return "", fmt.Errorf("load settings: %w", err)
+	}
+	for _, account := range settings.Trakt.Accounts {
+		if id := strings.TrimSpace(account.ClientID); id != "" {
+			return id, nil
+		}
+	}
+	if id := strings.TrimSpace(settings.Trakt.ClientID); id != "" {
+		return id, nil
+	}
+	return "", errors.New("no trakt account with a client id is configured")
+}
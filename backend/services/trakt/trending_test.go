@@ -0,0 +1,64 @@
+package trakt
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"novastream/config"
+)
+
+func TestTrendingProviderFetchTrending(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("trakt-api-key") != "account-client-id" {
+			t.Errorf("expected trakt-api-key from configured account, got %q", r.Header.Get("trakt-api-key"))
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("public lists should not send an Authorization header")
+		}
+		switch r.URL.Path {
+		case "/movies/trending":
+			w.Write([]byte(`[{"watchers":120,"movie":{"title":"Dune: Part Two","year":2024,"ids":{"trakt":1,"imdb":"tt15239678","tmdb":693134}}}]`))
+		case "/shows/popular":
+			w.Write([]byte(`[{"title":"The Bear","year":2022,"ids":{"trakt":2,"imdb":"tt14452776","tmdb":136315,"tvdb":403245}}]`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	origURL := traktAPIBaseURL
+	defer func() { setBaseURL(origURL) }()
+	setBaseURL(server.URL)
+
+	mgr := newTestConfigManager(t, config.Settings{
+		Trakt: config.TraktSettings{Accounts: []config.TraktAccount{{ID: "acc1", ClientID: "account-client-id"}}},
+	})
+	provider := NewTrendingProvider(NewClient("", ""), mgr)
+
+	movies, err := provider.FetchTrending(context.Background(), "movie", "trending")
+	if err != nil {
+		t.Fatalf("FetchTrending movies: %v", err)
+	}
+	if len(movies) != 1 || movies[0].IMDBID != "tt15239678" || movies[0].TMDBID != 693134 || movies[0].MediaType != "movie" {
+		t.Fatalf("unexpected movie items: %+v", movies)
+	}
+
+	shows, err := provider.FetchTrending(context.Background(), "series", "popular")
+	if err != nil {
+		t.Fatalf("FetchTrending shows: %v", err)
+	}
+	if len(shows) != 1 || shows[0].Title != "The Bear" || shows[0].TVDBID != 403245 || shows[0].MediaType != "series" {
+		t.Fatalf("unexpected show items: %+v", shows)
+	}
+}
+
+func TestTrendingProviderRequiresClientID(t *testing.T) {
+	mgr := newTestConfigManager(t, config.Settings{})
+	provider := NewTrendingProvider(NewClient("", ""), mgr)
+	if _, err := provider.FetchTrending(context.Background(), "movie", "trending"); err == nil {
+		t.Fatal("expected error when no trakt client id is configured")
+	}
+}
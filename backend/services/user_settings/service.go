@@ -76,6 +76,26 @@ func sanitizeLanguageCode(code string) string {
 	return code
 }
 
+// sanitizeCountryCode normalises an ISO 3166-1 alpha-2 code, dropping
+// anything that isn't one so the profile inherits the server's country.
+func sanitizeCountryCode(code string) string {
+	code = strings.ToUpper(sanitizeLanguageCode(code))
+	if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+		return ""
+	}
+	return code
+}
+
+// sanitizeLandingTab drops a landing tab the clients do not know, so the
+// profile falls back to the inherited one instead of opening on nothing.
+func sanitizeLandingTab(tab string) string {
+	tab = strings.ToLower(strings.TrimSpace(tab))
+	if !models.IsNavigationTab(tab) {
+		return ""
+	}
+	return tab
+}
+
 func defaultPreferredAudioLanguage(code string) string {
 	code = sanitizeLanguageCode(code)
 	if code == "" {
@@ -161,6 +181,8 @@ func (s *Service) GetWithDefaults(userID string, defaults models.UserSettings) (
 		settings.Playback.PreferredSubtitleLanguage = sanitizeLanguageCode(settings.Playback.PreferredSubtitleLanguage)
 		settings.Playback.PreferredSubtitleMode = strings.TrimSpace(strings.Trim(settings.Playback.PreferredSubtitleMode, "'\""))
 		settings.Metadata.PrimaryLanguage = sanitizeLanguageCode(settings.Metadata.PrimaryLanguage)
+		settings.Metadata.CertificationCountry = sanitizeCountryCode(settings.Metadata.CertificationCountry)
+		settings.Display.LandingTab = sanitizeLandingTab(settings.Display.LandingTab)
 
 		// Fill in missing Playback fields from defaults
 		// Empty strings indicate "not set" and should inherit from defaults
@@ -235,9 +257,15 @@ func (s *Service) GetWithDefaults(userID string, defaults models.UserSettings) (
 		if settings.Playback.MatchFrameRate == nil {
 			settings.Playback.MatchFrameRate = defaults.Playback.MatchFrameRate
 		}
+		if settings.Playback.DisableAutoPlayNext == nil {
+			settings.Playback.DisableAutoPlayNext = defaults.Playback.DisableAutoPlayNext
+		}
 		if settings.Metadata.PrimaryLanguage == "" {
 			settings.Metadata.PrimaryLanguage = defaults.Metadata.PrimaryLanguage
 		}
+		if settings.Metadata.CertificationCountry == "" {
+			settings.Metadata.CertificationCountry = defaults.Metadata.CertificationCountry
+		}
 
 		// Fill in missing Display fields from defaults without overwriting explicit user overrides.
 		if settings.Display.BadgeVisibility == nil {
@@ -258,6 +286,15 @@ func (s *Service) GetWithDefaults(userID string, defaults models.UserSettings) (
 		if settings.Display.AppLanguage == "" {
 			settings.Display.AppLanguage = defaults.Display.AppLanguage
 		}
+		if settings.Display.DateFormat == "" {
+			settings.Display.DateFormat = defaults.Display.DateFormat
+		}
+		if settings.Display.TimeFormat == "" {
+			settings.Display.TimeFormat = defaults.Display.TimeFormat
+		}
+		if settings.Display.LandingTab == "" {
+			settings.Display.LandingTab = defaults.Display.LandingTab
+		}
 		if settings.Display.Appearance.FontScale == nil {
 			settings.Display.Appearance.FontScale = defaults.Display.Appearance.FontScale
 		}
@@ -426,6 +463,7 @@ func shelfConfigEqualsDefault(stored, def models.ShelfConfig) bool {
 		stored.Order == def.Order &&
 		stored.Type == def.Type &&
 		stored.ListURL == def.ListURL &&
+		stored.TrendingSource == def.TrendingSource &&
 		stored.TraktAccountID == def.TraktAccountID &&
 		stored.TraktListType == def.TraktListType &&
 		stored.TraktListID == def.TraktListID &&
@@ -434,6 +472,7 @@ func shelfConfigEqualsDefault(stored, def models.ShelfConfig) bool {
 		stored.SimklMediaType == def.SimklMediaType &&
 		stored.LetterboxdListID == def.LetterboxdListID &&
 		stored.LetterboxdListURL == def.LetterboxdListURL &&
+		stored.SavedFilterID == def.SavedFilterID &&
 		stored.Limit == def.Limit &&
 		stored.HideUnreleased == def.HideUnreleased &&
 		stored.Sort == def.Sort &&
@@ -471,6 +510,7 @@ func (s *Service) Update(userID string, settings models.UserSettings) error {
 	settings.Playback.PreferredSubtitleLanguage = sanitizeLanguageCode(settings.Playback.PreferredSubtitleLanguage)
 	settings.Playback.PreferredSubtitleMode = strings.TrimSpace(strings.Trim(settings.Playback.PreferredSubtitleMode, "'\""))
 	settings.Metadata.PrimaryLanguage = sanitizeLanguageCode(settings.Metadata.PrimaryLanguage)
+	settings.Metadata.CertificationCountry = sanitizeCountryCode(settings.Metadata.CertificationCountry)
 
 	log.Printf("[user-settings] Update(%q): subMode=%q, audioLang=%q, subLang=%q",
 		userID, settings.Playback.PreferredSubtitleMode, settings.Playback.PreferredAudioLanguage, settings.Playback.PreferredSubtitleLanguage)
@@ -517,6 +557,7 @@ func isSettingsEmpty(s models.UserSettings) bool {
 		s.Playback.ForceAACTranscoding ||
 		s.Playback.AutoPlayTrailersTV ||
 		s.Playback.MatchFrameRate != nil ||
+		s.Playback.DisableAutoPlayNext != nil ||
 		s.Playback.DisablePrequeue {
 		return false
 	}
@@ -552,6 +593,7 @@ func isSettingsEmpty(s models.UserSettings) bool {
 	// Check LiveTV
 	if len(s.LiveTV.HiddenChannels) > 0 ||
 		len(s.LiveTV.FavoriteChannels) > 0 ||
+		len(s.LiveTV.ChannelOrder) > 0 ||
 		len(s.LiveTV.SelectedCategories) > 0 ||
 		s.LiveTV.Mode != nil ||
 		s.LiveTV.PlaylistURL != nil ||
@@ -577,6 +619,9 @@ func isSettingsEmpty(s models.UserSettings) bool {
 		s.Display.BypassFilteringForAIOStreamsOnly != nil ||
 		s.Display.DisableMobileTopCarousel != nil ||
 		s.Display.AppLanguage != "" ||
+		s.Display.DateFormat != "" ||
+		s.Display.TimeFormat != "" ||
+		s.Display.LandingTab != "" ||
 		s.Display.Appearance.FontScale != nil ||
 		s.Display.Appearance.AccentColor != "" ||
 		s.Display.Appearance.TextColor != "" ||
@@ -596,7 +641,7 @@ func isSettingsEmpty(s models.UserSettings) bool {
 	}
 
 	// Check Metadata
-	if s.Metadata.PrimaryLanguage != "" {
+	if s.Metadata.PrimaryLanguage != "" || s.Metadata.CertificationCountry != "" || len(s.Metadata.StreamingServices) > 0 {
 		return false
 	}
 
@@ -624,6 +669,7 @@ func isSettingsEmpty(s models.UserSettings) bool {
 		s.Calendar.Trending != nil ||
 		s.Calendar.TopTrending != nil ||
 		s.Calendar.MDBLists != nil ||
+		s.Calendar.ReleaseFeed != nil ||
 		len(s.Calendar.MDBListShelves) > 0 {
 		return false
 	}
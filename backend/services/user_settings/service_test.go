@@ -264,6 +264,56 @@ func TestGetWithDefaults_DisplayAppLanguagePreservesUserOverride(t *testing.T) {
 	}
 }
 
+func TestUpdate_UIPreferencesFollowProfile(t *testing.T) {
+	dir := t.TempDir()
+	svc, err := NewService(dir)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	if err := svc.Update("user1", models.UserSettings{
+		Playback: models.PlaybackSettings{DisableAutoPlayNext: models.BoolPtr(true)},
+		Display:  models.DisplaySettings{LandingTab: " Live "},
+	}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := svc.Update("user2", models.UserSettings{
+		Display: models.DisplaySettings{LandingTab: "nowhere"},
+	}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	// A fresh service reads the preferences back, as another device would.
+	reloaded, err := NewService(dir)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	defaults := models.DefaultUserSettings()
+	defaults.Display.LandingTab = "home"
+
+	got, err := reloaded.GetWithDefaults("user1", defaults)
+	if err != nil {
+		t.Fatalf("GetWithDefaults: %v", err)
+	}
+	if got.Display.LandingTab != "live" {
+		t.Fatalf("display.landingTab = %q, want %q", got.Display.LandingTab, "live")
+	}
+	if got.Playback.DisableAutoPlayNext == nil || !*got.Playback.DisableAutoPlayNext {
+		t.Fatalf("playback.disableAutoPlayNext = %v, want true", got.Playback.DisableAutoPlayNext)
+	}
+
+	got, err = reloaded.GetWithDefaults("user2", defaults)
+	if err != nil {
+		t.Fatalf("GetWithDefaults: %v", err)
+	}
+	if got.Display.LandingTab != "home" {
+		t.Fatalf("unknown landing tab should inherit, got %q", got.Display.LandingTab)
+	}
+	if got.Playback.DisableAutoPlayNext == nil || *got.Playback.DisableAutoPlayNext {
+		t.Fatalf("playback.disableAutoPlayNext = %v, want inherited false", got.Playback.DisableAutoPlayNext)
+	}
+}
+
 func TestClearAppearanceOverrides_RemovesOnlyAppearance(t *testing.T) {
 	dir := t.TempDir()
 	svc, err := NewService(dir)
@@ -580,8 +630,8 @@ func TestGetWithDefaults_BackfillsCalendarShelf(t *testing.T) {
 		t.Fatalf("GetWithDefaults: %v", err)
 	}
 
-	if len(got.HomeShelves.Shelves) != 10 {
-		t.Fatalf("expected 10 shelves after backfill, got %d", len(got.HomeShelves.Shelves))
+	if len(got.HomeShelves.Shelves) != 12 {
+		t.Fatalf("expected 12 shelves after backfill, got %d", len(got.HomeShelves.Shelves))
 	}
 
 	var topTen *models.ShelfConfig
@@ -648,6 +698,14 @@ func TestGetWithDefaults_BackfillsCalendarShelf(t *testing.T) {
 	if !models.BoolVal(recentlyAired.CalendarSources.Watchlist, false) {
 		t.Fatal("expected my recently aired shelf to include watchlist by default")
 	}
+	leavingSoon := got.HomeShelves.Shelves[len(got.HomeShelves.Shelves)-2]
+	if leavingSoon.ID != "leaving-soon" || leavingSoon.Enabled {
+		t.Fatalf("expected a disabled leaving soon shelf, got %+v", leavingSoon)
+	}
+	last := got.HomeShelves.Shelves[len(got.HomeShelves.Shelves)-1]
+	if last.ID != "complete-collection" || !last.Enabled {
+		t.Fatalf("expected the complete the collection shelf last, got %+v", last)
+	}
 	if models.BoolVal(recentlyAired.CalendarSources.History, false) ||
 		models.BoolVal(recentlyAired.CalendarSources.Trending, false) ||
 		models.BoolVal(recentlyAired.CalendarSources.TopTrending, false) ||
@@ -789,8 +847,8 @@ func TestLoad_MigratesMissingCalendarShelf(t *testing.T) {
 	if got == nil {
 		t.Fatal("expected migrated settings")
 	}
-	if len(got.HomeShelves.Shelves) != 10 {
-		t.Fatalf("expected 10 shelves after migration, got %d", len(got.HomeShelves.Shelves))
+	if len(got.HomeShelves.Shelves) != 12 {
+		t.Fatalf("expected 12 shelves after migration, got %d", len(got.HomeShelves.Shelves))
 	}
 
 	var topTen *models.ShelfConfig
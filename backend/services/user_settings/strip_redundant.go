@@ -148,6 +148,7 @@ func globalToUserSettings(g config.Settings) models.UserSettings {
 			CreditsDetectionEnabled:       models.BoolPtr(g.Playback.CreditsDetectionEnabled),
 			CreditsAutoSkip:               g.Playback.CreditsAutoSkip || g.Playback.CreditsDetection,
 			MatchFrameRate:                models.BoolPtr(g.Playback.MatchFrameRate),
+			DisableAutoPlayNext:           models.BoolPtr(g.Playback.DisableAutoPlayNext),
 			MaxResultsPerResolution:       models.IntPtr(g.Playback.MaxResultsPerResolution),
 		},
 		Filtering: models.FilterSettings{
@@ -173,6 +174,9 @@ func globalToUserSettings(g config.Settings) models.UserSettings {
 			BypassFilteringForAIOStreamsOnly: models.BoolPtr(g.Display.BypassFilteringForAIOStreamsOnly),
 			DisableMobileTopCarousel:         models.BoolPtr(g.Display.DisableMobileTopCarousel),
 			AppLanguage:                      g.Display.AppLanguage,
+			DateFormat:                       g.Display.DateFormat,
+			TimeFormat:                       g.Display.TimeFormat,
+			LandingTab:                       g.Display.LandingTab,
 			Appearance: models.AppearanceSettings{
 				FontScale:            g.Display.Appearance.FontScale,
 				AccentColor:          g.Display.Appearance.AccentColor,
@@ -216,6 +220,7 @@ func configShelvesToModel(shelves []config.ShelfConfig) []models.ShelfConfig {
 			Order:                  s.Order,
 			Type:                   s.Type,
 			ListURL:                s.ListURL,
+			TrendingSource:         s.TrendingSource,
 			StreamingServices:      configStreamingServicesToModel(s.StreamingServices),
 			CollectionItems:        configCollectionHubItemsToModel(s.CollectionItems),
 			TraktAccountID:         s.TraktAccountID,
@@ -390,6 +395,9 @@ func mergeWithGlobal(us models.UserSettings, g config.Settings) models.UserSetti
 	if eff.Playback.MatchFrameRate == nil {
 		eff.Playback.MatchFrameRate = models.BoolPtr(g.Playback.MatchFrameRate)
 	}
+	if eff.Playback.DisableAutoPlayNext == nil {
+		eff.Playback.DisableAutoPlayNext = models.BoolPtr(g.Playback.DisableAutoPlayNext)
+	}
 	if eff.Playback.MaxResultsPerResolution == nil {
 		eff.Playback.MaxResultsPerResolution = models.IntPtr(g.Playback.MaxResultsPerResolution)
 	}
@@ -453,6 +461,15 @@ func mergeWithGlobal(us models.UserSettings, g config.Settings) models.UserSetti
 	if eff.Display.AppLanguage == "" {
 		eff.Display.AppLanguage = g.Display.AppLanguage
 	}
+	if eff.Display.DateFormat == "" {
+		eff.Display.DateFormat = g.Display.DateFormat
+	}
+	if eff.Display.TimeFormat == "" {
+		eff.Display.TimeFormat = g.Display.TimeFormat
+	}
+	if eff.Display.LandingTab == "" {
+		eff.Display.LandingTab = g.Display.LandingTab
+	}
 	if eff.Display.Appearance.FontScale == nil {
 		eff.Display.Appearance.FontScale = g.Display.Appearance.FontScale
 	}
@@ -555,7 +572,7 @@ func (s *Service) stripProfileSettings(us *models.UserSettings, g config.Setting
 	changed = stripNetwork(&us.Network, g.Network) || changed
 	changed = stripMetadata(&us.Metadata, g.Metadata) || changed
 	changed = stripRanking(&us.Ranking, g.Ranking) || changed
-	// LiveTV channel lists (HiddenChannels, FavoriteChannels, SelectedCategories) are inherently per-user — never strip.
+	// LiveTV channel lists (HiddenChannels, FavoriteChannels, ChannelOrder, SelectedCategories) are inherently per-user — never strip.
 	// Calendar has no global equivalent — never strip.
 	return changed
 }
@@ -679,6 +696,10 @@ func stripPlayback(p *models.PlaybackSettings, g config.PlaybackSettings) bool {
 		p.MatchFrameRate = nil
 		changed = true
 	}
+	if p.DisableAutoPlayNext != nil && *p.DisableAutoPlayNext == g.DisableAutoPlayNext {
+		p.DisableAutoPlayNext = nil
+		changed = true
+	}
 	if p.DisablePrequeue && p.DisablePrequeue == g.DisablePrequeue {
 		p.DisablePrequeue = false
 		changed = true
@@ -819,6 +840,18 @@ func stripDisplay(d *models.DisplaySettings, g config.DisplaySettings) bool {
 		d.AppLanguage = ""
 		changed = true
 	}
+	if d.DateFormat != "" && d.DateFormat == g.DateFormat {
+		d.DateFormat = ""
+		changed = true
+	}
+	if d.TimeFormat != "" && d.TimeFormat == g.TimeFormat {
+		d.TimeFormat = ""
+		changed = true
+	}
+	if d.LandingTab != "" && d.LandingTab == g.LandingTab {
+		d.LandingTab = ""
+		changed = true
+	}
 	if d.Appearance.FontScale != nil && g.Appearance.FontScale != nil && *d.Appearance.FontScale == *g.Appearance.FontScale {
 		d.Appearance.FontScale = nil
 		changed = true
@@ -1218,6 +1251,7 @@ func shelfConfigsEqual(user []models.ShelfConfig, global []config.ShelfConfig) b
 		if us.Name != gs.Name || us.Enabled != gs.Enabled || us.Order != gs.Order ||
 			(us.Type != "" && us.Type != gs.Type) ||
 			(us.ListURL != "" && us.ListURL != gs.ListURL) ||
+			(us.TrendingSource != "" && us.TrendingSource != gs.TrendingSource) ||
 			us.Limit != gs.Limit ||
 			us.HideUnreleased != gs.HideUnreleased ||
 			(us.Sort != "" && us.Sort != gs.Sort) ||
@@ -1229,6 +1263,7 @@ func shelfConfigsEqual(user []models.ShelfConfig, global []config.ShelfConfig) b
 			(us.SimklMediaType != "" && us.SimklMediaType != gs.SimklMediaType) ||
 			(us.LetterboxdListID != "" && us.LetterboxdListID != gs.LetterboxdListID) ||
 			(us.LetterboxdListURL != "" && us.LetterboxdListURL != gs.LetterboxdListURL) ||
+			us.SavedFilterID != "" ||
 			(us.AnimateLogoOnlyOnFocus != gs.AnimateLogoOnlyOnFocus) ||
 			(us.ShowCollectionTitles != gs.ShowCollectionTitles) ||
 			(us.ShowCollectionCounts != gs.ShowCollectionCounts) ||
@@ -2,6 +2,11 @@ package users
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,6 +14,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -19,6 +25,7 @@ import (
 
 	"novastream/internal/datastore"
 	"novastream/models"
+	"novastream/services/kids"
 )
 
 var (
@@ -31,6 +38,7 @@ var (
 	ErrInvalidIconURL     = errors.New("invalid icon URL")
 	ErrIconDownloadFailed = errors.New("failed to download icon")
 	ErrInvalidImageFormat = errors.New("invalid image format, must be PNG or JPG")
+	ErrInvalidRating      = errors.New("invalid content rating")
 )
 
 // isValidIconFilename validates that an icon filename is safe (no path traversal).
@@ -221,6 +229,52 @@ func (s *Service) CreateForAccount(accountID, name string) (models.User, error)
 	return s.createLocked(accountID, trimmed)
 }
 
+// CreateFromTemplate registers a new profile under the specified account that
+// copies the color and content restrictions of the template profile. PINs,
+// icons and linked service accounts are not copied.
+func (s *Service) CreateFromTemplate(accountID, name, templateID string) (models.User, error) {
+	trimmed := strings.TrimSpace(name)
+	if trimmed == "" {
+		return models.User{}, ErrNameRequired
+	}
+
+	accountID = strings.TrimSpace(accountID)
+	if accountID == "" {
+		accountID = models.DefaultAccountID
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	template, ok := s.users[strings.TrimSpace(templateID)]
+	if !ok {
+		return models.User{}, ErrUserNotFound
+	}
+
+	user, err := s.createLocked(accountID, trimmed)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	user.Color = template.Color
+	user.IsKidsProfile = template.IsKidsProfile
+	user.KidsMode = template.KidsMode
+	user.KidsMaxRating = template.KidsMaxRating
+	user.KidsMaxMovieRating = template.KidsMaxMovieRating
+	user.KidsMaxTVRating = template.KidsMaxTVRating
+	user.KidsAllowedLists = slices.Clone(template.KidsAllowedLists)
+	user.KidsAllowedGenres = slices.Clone(template.KidsAllowedGenres)
+	user.MaxMovieRating = template.MaxMovieRating
+	user.MaxTVRating = template.MaxTVRating
+	s.users[user.ID] = user
+
+	if err := s.saveLocked(); err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
 // Reassign moves a profile to a different account. This is a master-only operation.
 func (s *Service) Reassign(profileID, newAccountID string) (models.User, error) {
 	profileID = strings.TrimSpace(profileID)
@@ -678,6 +732,91 @@ func (s *Service) HasPin(id string) bool {
 	return user.PinHash != ""
 }
 
+// calendarFeedTokenBytes is the entropy of a calendar feed token.
+const calendarFeedTokenBytes = 32
+
+func hashCalendarFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// RotateCalendarFeedToken issues a new token that calendar apps and feed
+// readers can subscribe to the profile's feeds with, replacing any previous
+// one. The token grants nothing beyond reading those feeds. Only its hash is
+// stored, so it is returned here and never again.
+func (s *Service) RotateCalendarFeedToken(id string) (string, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return "", ErrUserNotFound
+	}
+
+	raw := make([]byte, calendarFeedTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate calendar feed token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return "", ErrUserNotFound
+	}
+	user.CalendarFeedTokenHash = hashCalendarFeedToken(token)
+	user.UpdatedAt = time.Now().UTC()
+	s.users[id] = user
+
+	if err := s.saveLocked(); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// RevokeCalendarFeedToken stops the profile's current feed token from
+// working. Subscriptions using it fail until a new token is issued.
+func (s *Service) RevokeCalendarFeedToken(id string) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return ErrUserNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return ErrUserNotFound
+	}
+	if user.CalendarFeedTokenHash == "" {
+		return nil
+	}
+	user.CalendarFeedTokenHash = ""
+	user.UpdatedAt = time.Now().UTC()
+	s.users[id] = user
+
+	return s.saveLocked()
+}
+
+// ValidCalendarFeedToken reports whether token is the profile's current
+// calendar feed token.
+func (s *Service) ValidCalendarFeedToken(id, token string) bool {
+	id = strings.TrimSpace(id)
+	token = strings.TrimSpace(token)
+	if id == "" || token == "" {
+		return false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[id]
+	if !ok || user.CalendarFeedTokenHash == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(hashCalendarFeedToken(token)), []byte(user.CalendarFeedTokenHash)) == 1
+}
+
 // SetKidsProfile sets whether this is a kids profile.
 // When enabling kids profile mode, applies default settings if none are set.
 func (s *Service) SetKidsProfile(id string, isKids bool) (models.User, error) {
@@ -707,6 +846,7 @@ func (s *Service) SetKidsProfile(id string, isKids bool) (models.User, error) {
 		user.KidsMode = ""
 		user.KidsMaxRating = ""
 		user.KidsAllowedLists = nil
+		user.KidsAllowedGenres = nil
 	}
 
 	user.UpdatedAt = time.Now().UTC()
@@ -719,7 +859,7 @@ func (s *Service) SetKidsProfile(id string, isKids bool) (models.User, error) {
 	return user, nil
 }
 
-// SetKidsMode sets the kids mode for a profile ("rating", "content_list", "both", or "").
+// SetKidsMode sets the kids mode for a profile ("rating", "content_list", "allow_list", or "").
 func (s *Service) SetKidsMode(id, mode string) (models.User, error) {
 	id = strings.TrimSpace(id)
 	if id == "" {
@@ -824,6 +964,39 @@ func (s *Service) SetKidsMaxTVRating(id, rating string) (models.User, error) {
 	return user, nil
 }
 
+// SetRatingCeiling sets the maximum movie and TV ratings any profile may see.
+// Empty ratings remove the ceiling.
+func (s *Service) SetRatingCeiling(id, movieRating, tvRating string) (models.User, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return models.User{}, ErrUserNotFound
+	}
+	movieRating = strings.ToUpper(strings.TrimSpace(movieRating))
+	tvRating = strings.ToUpper(strings.TrimSpace(tvRating))
+	if !kids.ValidateCeiling(movieRating, tvRating) {
+		return models.User{}, ErrInvalidRating
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return models.User{}, ErrUserNotFound
+	}
+
+	user.MaxMovieRating = movieRating
+	user.MaxTVRating = tvRating
+	user.UpdatedAt = time.Now().UTC()
+	s.users[id] = user
+
+	if err := s.saveLocked(); err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
 // SetKidsAllowedLists replaces the allowed lists for a kids profile.
 func (s *Service) SetKidsAllowedLists(id string, lists []string) (models.User, error) {
 	id = strings.TrimSpace(id)
@@ -858,6 +1031,43 @@ func (s *Service) SetKidsAllowedLists(id string, lists []string) (models.User, e
 	return user, nil
 }
 
+// SetKidsAllowedGenres replaces the allowed genres for a kids profile.
+func (s *Service) SetKidsAllowedGenres(id string, genres []string) (models.User, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return models.User{}, ErrUserNotFound
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[id]
+	if !ok {
+		return models.User{}, ErrUserNotFound
+	}
+
+	cleaned := make([]string, 0, len(genres))
+	seen := make(map[string]bool, len(genres))
+	for _, genre := range genres {
+		trimmed := strings.TrimSpace(genre)
+		if trimmed == "" || seen[strings.ToLower(trimmed)] {
+			continue
+		}
+		seen[strings.ToLower(trimmed)] = true
+		cleaned = append(cleaned, trimmed)
+	}
+
+	user.KidsAllowedGenres = cleaned
+	user.UpdatedAt = time.Now().UTC()
+	s.users[id] = user
+
+	if err := s.saveLocked(); err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
 // AddKidsAllowedList adds a list URL to the allowed lists for a kids profile.
 func (s *Service) AddKidsAllowedList(id, listURL string) (models.User, error) {
 	id = strings.TrimSpace(id)
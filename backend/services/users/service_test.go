@@ -170,3 +170,90 @@ func TestSetIconURLServerError(t *testing.T) {
 		t.Fatal("expected error for server 403 response")
 	}
 }
+
+func TestCreateFromTemplateCopiesRestrictions(t *testing.T) {
+	svc, err := users.NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	template, err := svc.CreateForAccount("admin-account", "Kids Template")
+	if err != nil {
+		t.Fatalf("create template: %v", err)
+	}
+	if _, err := svc.SetKidsProfile(template.ID, true); err != nil {
+		t.Fatalf("set kids profile: %v", err)
+	}
+	if _, err := svc.SetKidsMode(template.ID, "allow_list"); err != nil {
+		t.Fatalf("set kids mode: %v", err)
+	}
+	if _, err := svc.SetKidsAllowedGenres(template.ID, []string{"Animation"}); err != nil {
+		t.Fatalf("set allowed genres: %v", err)
+	}
+	if _, err := svc.SetPin(template.ID, "1234"); err != nil {
+		t.Fatalf("set pin: %v", err)
+	}
+
+	profile, err := svc.CreateFromTemplate("invited-account", "Sam", template.ID)
+	if err != nil {
+		t.Fatalf("CreateFromTemplate: %v", err)
+	}
+	if profile.AccountID != "invited-account" || profile.Name != "Sam" {
+		t.Fatalf("unexpected profile %+v", profile)
+	}
+	if !profile.IsKidsProfile || profile.KidsMode != "allow_list" || len(profile.KidsAllowedGenres) != 1 {
+		t.Fatalf("expected kids restrictions to be copied, got %+v", profile)
+	}
+	if profile.HasPin() {
+		t.Fatal("expected the template PIN not to be copied")
+	}
+
+	if _, err := svc.CreateFromTemplate("invited-account", "Alex", "missing"); err != users.ErrUserNotFound {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestCalendarFeedTokenRotateAndRevoke(t *testing.T) {
+	dir := t.TempDir()
+	svc, err := users.NewService(dir)
+	if err != nil {
+		t.Fatalf("failed to create service: %v", err)
+	}
+	id := svc.List()[0].ID
+
+	first, err := svc.RotateCalendarFeedToken(id)
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if !svc.ValidCalendarFeedToken(id, first) {
+		t.Fatal("expected issued token to be valid")
+	}
+	if user, _ := svc.Get(id); user.CalendarFeedTokenHash == first {
+		t.Fatal("expected only a hash of the token to be stored")
+	}
+
+	// The token survives a restart and is replaced on rotation.
+	reloaded, err := users.NewService(dir)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	if !reloaded.ValidCalendarFeedToken(id, first) {
+		t.Fatal("expected token to be persisted")
+	}
+	second, err := reloaded.RotateCalendarFeedToken(id)
+	if err != nil {
+		t.Fatalf("rotate: %v", err)
+	}
+	if reloaded.ValidCalendarFeedToken(id, first) || !reloaded.ValidCalendarFeedToken(id, second) {
+		t.Fatal("expected rotation to replace the previous token")
+	}
+
+	if err := reloaded.RevokeCalendarFeedToken(id); err != nil {
+		t.Fatalf("revoke: %v", err)
+	}
+	if reloaded.ValidCalendarFeedToken(id, second) || reloaded.ValidCalendarFeedToken(id, "") {
+		t.Fatal("expected no token to be valid after revoking")
+	}
+	if _, err := reloaded.RotateCalendarFeedToken("missing"); err != users.ErrUserNotFound {
+		t.Fatalf("expected ErrUserNotFound, got %v", err)
+	}
+}
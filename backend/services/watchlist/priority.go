@@ -0,0 +1,177 @@
+package watchlist
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"novastream/models"
+)
+
+// Watchlist sort orders accepted by SortItems.
+const (
+	SortPriority = "priority"
+	SortAdded    = "added"
+	SortRelease  = "release"
+	SortRuntime  = "runtime"
+)
+
+// ErrInvalidSort is returned for an unknown sort order.
+var ErrInvalidSort = errors.New("sort must be priority, added, release or runtime and dir asc or desc")
+
+// NormalizePriority lower-cases a priority level and checks it is one of
+// high, normal or someday. An empty value is returned unchanged.
+func NormalizePriority(priority string) (string, error) {
+	priority = strings.ToLower(strings.TrimSpace(priority))
+	switch priority {
+	case "", models.WatchlistPriorityHigh, models.WatchlistPriorityNormal, models.WatchlistPrioritySomeday:
+		return priority, nil
+	}
+	return "", ErrInvalidPriority
+}
+
+// PriorityRank orders priority levels: 0 for high, 1 for normal (or unset)
+// and 2 for someday.
+func PriorityRank(item models.WatchlistItem) int {
+	switch item.Priority {
+	case models.WatchlistPriorityHigh:
+		return 0
+	case models.WatchlistPrioritySomeday:
+		return 2
+	}
+	return 1
+}
+
+// SetPriority changes the priority of an item already on the watchlist.
+func (s *Service) SetPriority(userID, mediaType, id, priority string) (models.WatchlistItem, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return models.WatchlistItem{}, ErrUserIDRequired
+	}
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	if mediaType == "" || strings.TrimSpace(id) == "" {
+		return models.WatchlistItem{}, ErrIdentifierRequired
+	}
+	priority, err := NormalizePriority(priority)
+	if err != nil {
+		return models.WatchlistItem{}, err
+	}
+	if priority == "" {
+		priority = models.WatchlistPriorityNormal
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	perUser := s.ensureUserLocked(userID)
+	key := ""
+	for _, candidate := range watchlistCandidateKeys(mediaType, id, nil) {
+		if _, ok := perUser[candidate]; ok {
+			key = candidate
+			break
+		}
+	}
+	if key == "" {
+		for candidate, existing := range perUser {
+			if watchlistItemMatchesIdentifier(existing, mediaType, id) {
+				key = candidate
+				break
+			}
+		}
+	}
+	if key == "" {
+		return models.WatchlistItem{}, os.ErrNotExist
+	}
+
+	item := perUser[key]
+	item.Priority = priority
+	perUser[key] = item
+	if err := s.saveLocked(); err != nil {
+		return models.WatchlistItem{}, err
+	}
+	return item, nil
+}
+
+// SortItems orders watchlist items in place by priority, added, release or
+// runtime. dir is "asc" or "desc"; when empty each order uses its natural
+// direction: high priority first, newest addition first, newest release first
+// and shortest runtime first. Priority ties fall back to the newest addition,
+// and items without a release date or runtime stay at the end either way. An
+// empty order sorts by date added.
+func SortItems(items []models.WatchlistItem, order, dir string) error {
+	order = strings.ToLower(strings.TrimSpace(order))
+	if order == "" {
+		order = SortAdded
+	}
+
+	var less func(a, b models.WatchlistItem) bool
+	var missing func(item models.WatchlistItem) bool
+	descending := false
+	switch order {
+	case SortPriority:
+		less = func(a, b models.WatchlistItem) bool { return PriorityRank(a) < PriorityRank(b) }
+	case SortAdded:
+		less = func(a, b models.WatchlistItem) bool { return a.AddedAt.Before(b.AddedAt) }
+		descending = true
+	case SortRelease:
+		less = func(a, b models.WatchlistItem) bool { return releaseSortTime(a).Before(releaseSortTime(b)) }
+		missing = func(item models.WatchlistItem) bool { return releaseSortTime(item).IsZero() }
+		descending = true
+	case SortRuntime:
+		less = func(a, b models.WatchlistItem) bool { return a.RuntimeMinutes < b.RuntimeMinutes }
+		missing = func(item models.WatchlistItem) bool { return item.RuntimeMinutes <= 0 }
+	default:
+		return ErrInvalidSort
+	}
+	switch strings.ToLower(strings.TrimSpace(dir)) {
+	case "":
+	case "asc":
+		descending = false
+	case "desc":
+		descending = true
+	default:
+		return ErrInvalidSort
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if missing != nil {
+			if ma, mb := missing(a), missing(b); ma != mb {
+				return mb
+			}
+		}
+		if descending {
+			a, b = b, a
+		}
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		if !items[i].AddedAt.Equal(items[j].AddedAt) {
+			return items[i].AddedAt.After(items[j].AddedAt)
+		}
+		return items[i].Key() < items[j].Key()
+	})
+	return nil
+}
+
+// releaseSortTime returns the theatrical release date, falling back to the
+// home release date and then to the start of the release year.
+func releaseSortTime(item models.WatchlistItem) time.Time {
+	for _, release := range []*models.Release{item.Theatrical, item.HomeRelease} {
+		if release == nil || len(release.Date) < len("2006-01-02") {
+			continue
+		}
+		if date, err := time.Parse("2006-01-02", release.Date[:len("2006-01-02")]); err == nil {
+			return date
+		}
+	}
+	if item.Year > 0 {
+		return time.Date(item.Year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Time{}
+}
@@ -0,0 +1,104 @@
+package watchlist_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"novastream/models"
+	"novastream/services/watchlist"
+)
+
+func TestServicePriority(t *testing.T) {
+	dir := t.TempDir()
+	svc, err := watchlist.NewService(dir)
+	if err != nil {
+		t.Fatalf("expected service, got error: %v", err)
+	}
+
+	if _, err := svc.AddOrUpdate(models.DefaultUserID, models.WatchlistUpsert{ID: "m1", MediaType: "movie", Name: "Heat", Priority: "urgent"}); !errors.Is(err, watchlist.ErrInvalidPriority) {
+		t.Fatalf("expected ErrInvalidPriority, got %v", err)
+	}
+	if _, err := svc.AddOrUpdate(models.DefaultUserID, models.WatchlistUpsert{ID: "m1", MediaType: "movie", Name: "Heat", Priority: "High"}); err != nil {
+		t.Fatalf("failed to add item: %v", err)
+	}
+
+	// Syncs upsert without a priority and must keep the one the user picked.
+	item, err := svc.AddOrUpdate(models.DefaultUserID, models.WatchlistUpsert{ID: "m1", MediaType: "movie", Name: "Heat", SyncSource: "trakt"})
+	if err != nil {
+		t.Fatalf("failed to update item: %v", err)
+	}
+	if item.Priority != models.WatchlistPriorityHigh {
+		t.Fatalf("expected priority to survive a sync upsert, got %q", item.Priority)
+	}
+
+	item, err = svc.SetPriority(models.DefaultUserID, "movie", "m1", "someday")
+	if err != nil {
+		t.Fatalf("SetPriority returned error: %v", err)
+	}
+	if item.Priority != models.WatchlistPrioritySomeday {
+		t.Fatalf("expected someday priority, got %q", item.Priority)
+	}
+	if _, err := svc.SetPriority(models.DefaultUserID, "movie", "missing", "high"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected ErrNotExist for unknown item, got %v", err)
+	}
+
+	reloaded, err := watchlist.NewService(dir)
+	if err != nil {
+		t.Fatalf("failed to reload service: %v", err)
+	}
+	items, _ := reloaded.List(models.DefaultUserID)
+	if len(items) != 1 || items[0].Priority != models.WatchlistPrioritySomeday {
+		t.Fatalf("expected priority to survive reload, got %+v", items)
+	}
+}
+
+func TestSortItems(t *testing.T) {
+	now := time.Now().UTC()
+	items := func() []models.WatchlistItem {
+		return []models.WatchlistItem{
+			{ID: "a", MediaType: "movie", AddedAt: now.Add(-3 * time.Hour), Year: 1995, RuntimeMinutes: 170},
+			{ID: "b", MediaType: "movie", AddedAt: now.Add(-2 * time.Hour), Priority: models.WatchlistPrioritySomeday, RuntimeMinutes: 95,
+				Theatrical: &models.Release{Type: "theatrical", Date: "2024-03-01T00:00:00Z"}},
+			{ID: "c", MediaType: "series", AddedAt: now.Add(-1 * time.Hour), Priority: models.WatchlistPriorityHigh},
+			{ID: "d", MediaType: "movie", AddedAt: now, Year: 2010, RuntimeMinutes: 148},
+		}
+	}
+	ids := func(items []models.WatchlistItem) string {
+		out := ""
+		for _, item := range items {
+			out += item.ID
+		}
+		return out
+	}
+
+	cases := []struct {
+		sort, dir, want string
+	}{
+		{"", "", "dcba"},
+		{"added", "asc", "abcd"},
+		{"priority", "", "cdab"},
+		{"priority", "desc", "bdac"},
+		{"release", "", "bdac"},
+		{"release", "asc", "adbc"},
+		{"runtime", "", "bdac"},
+		{"runtime", "desc", "adbc"},
+	}
+	for _, tc := range cases {
+		list := items()
+		if err := watchlist.SortItems(list, tc.sort, tc.dir); err != nil {
+			t.Fatalf("sort=%s dir=%s: unexpected error %v", tc.sort, tc.dir, err)
+		}
+		if got := ids(list); got != tc.want {
+			t.Errorf("sort=%s dir=%s: expected %s, got %s", tc.sort, tc.dir, tc.want, got)
+		}
+	}
+
+	if err := watchlist.SortItems(items(), "rating", ""); !errors.Is(err, watchlist.ErrInvalidSort) {
+		t.Fatalf("expected ErrInvalidSort for unknown order, got %v", err)
+	}
+	if err := watchlist.SortItems(items(), "added", "up"); !errors.Is(err, watchlist.ErrInvalidSort) {
+		t.Fatalf("expected ErrInvalidSort for unknown dir, got %v", err)
+	}
+}
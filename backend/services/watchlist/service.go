@@ -25,6 +25,7 @@ var (
 	ErrMediaTypeRequired  = errors.New("media type is required")
 	ErrIdentifierRequired = errors.New("id and media type are required")
 	ErrTombstoned         = errors.New("watchlist item was explicitly removed")
+	ErrInvalidPriority    = errors.New("priority must be high, normal or someday")
 )
 
 // Service manages persistence and retrieval of user watchlist items.
@@ -141,6 +142,11 @@ func (s *Service) AddOrUpdate(userID string, input models.WatchlistUpsert) (mode
 		return models.WatchlistItem{}, ErrMediaTypeRequired
 	}
 
+	priority, err := NormalizePriority(input.Priority)
+	if err != nil {
+		return models.WatchlistItem{}, err
+	}
+
 	mediaType := mediaidentity.NormalizeMediaType(input.MediaType)
 	input.MediaType = mediaType
 	input.ExternalIDs = normaliseExternalIDs(input.ExternalIDs)
@@ -202,6 +208,9 @@ func (s *Service) AddOrUpdate(userID string, input models.WatchlistUpsert) (mode
 	if input.RuntimeMinutes != 0 {
 		item.RuntimeMinutes = input.RuntimeMinutes
 	}
+	if priority != "" {
+		item.Priority = priority
+	}
 
 	// Update sync tracking fields if provided
 	if strings.TrimSpace(input.SyncSource) != "" {
@@ -866,6 +875,9 @@ func mergeWatchlistItems(base, incoming models.WatchlistItem) models.WatchlistIt
 	if strings.TrimSpace(base.SyncSource) == "" {
 		base.SyncSource = incoming.SyncSource
 	}
+	if base.Priority == "" {
+		base.Priority = incoming.Priority
+	}
 	if base.SyncedAt == nil || (incoming.SyncedAt != nil && incoming.SyncedAt.After(*base.SyncedAt)) {
 		base.SyncedAt = incoming.SyncedAt
 	}
@@ -482,3 +482,46 @@ func TestServiceReconcileMergesRowsWithSharedExternalIDs(t *testing.T) {
 		t.Fatalf("expected plex external ID to survive reconcile, got %q", got)
 	}
 }
+
+func TestServiceRemapSeriesTVDBIDMergesIntoExisting(t *testing.T) {
+	svc, err := watchlist.NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("expected service, got error: %v", err)
+	}
+
+	if _, err := svc.AddOrUpdate(models.DefaultUserID, models.WatchlistUpsert{
+		ID:          "tvdb:111",
+		MediaType:   "series",
+		Name:        "Old Listing",
+		ExternalIDs: map[string]string{"tvdb": "111"},
+	}); err != nil {
+		t.Fatalf("failed to add item: %v", err)
+	}
+	if _, err := svc.AddOrUpdate(models.DefaultUserID, models.WatchlistUpsert{
+		ID:          "tvdb:222",
+		MediaType:   "series",
+		Name:        "Merged Listing",
+		ExternalIDs: map[string]string{"tvdb": "222"},
+	}); err != nil {
+		t.Fatalf("failed to add item: %v", err)
+	}
+
+	n, err := svc.RemapSeriesTVDBID(111, 222)
+	if err != nil {
+		t.Fatalf("remap returned error: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 item remapped, got %d", n)
+	}
+
+	items, err := svc.List(models.DefaultUserID)
+	if err != nil {
+		t.Fatalf("list returned error: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected remapped item merged into existing, got %d items", len(items))
+	}
+	if items[0].ExternalIDs["tvdb"] != "222" {
+		t.Fatalf("expected tvdb id 222, got %v", items[0].ExternalIDs)
+	}
+}
@@ -0,0 +1,82 @@
+package watchlist
+
+import (
+	"strconv"
+
+	"novastream/internal/mediaidentity"
+	"novastream/models"
+)
+
+// RemapSeriesTVDBID points watchlist items and tombstones for TVDB series
+// oldID at newID instead, for when TVDB merges or replaces a series. An item
+// that lands on one already in the list is merged with it. Returns the number
+// of items rewritten.
+func (s *Service) RemapSeriesTVDBID(oldID, newID int64) (int, error) {
+	if oldID <= 0 || newID <= 0 || oldID == newID {
+		return 0, nil
+	}
+	oldStr, newStr := strconv.FormatInt(oldID, 10), strconv.FormatInt(newID, 10)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	changed := 0
+	for _, perUser := range s.items {
+		var moved []models.WatchlistItem
+		for key, item := range perUser {
+			if item.MediaType != "series" {
+				continue
+			}
+			id, idChanged := mediaidentity.ReplaceSeriesTVDBID(item.ID, oldStr, newStr)
+			externalIDs, idsChanged := mediaidentity.ReplaceSeriesTVDBExternalID(item.ExternalIDs, oldStr, newStr)
+			if !idChanged && !idsChanged {
+				continue
+			}
+			item.ID, item.ExternalIDs = id, externalIDs
+			delete(perUser, key)
+			moved = append(moved, normaliseItem(item))
+		}
+		for _, item := range moved {
+			if existing, found := s.takeMergedItemLocked(perUser, item.MediaType, item.ID, item.ExternalIDs); found {
+				item = mergeWatchlistItems(existing, item)
+			}
+			perUser[item.Key()] = item
+		}
+		changed += len(moved)
+	}
+
+	tombstonesChanged := false
+	for _, perUser := range s.tombstones {
+		var moved []models.WatchlistTombstone
+		for key, tombstone := range perUser {
+			if tombstone.MediaType != "series" {
+				continue
+			}
+			id, idChanged := mediaidentity.ReplaceSeriesTVDBID(tombstone.ID, oldStr, newStr)
+			externalIDs, idsChanged := mediaidentity.ReplaceSeriesTVDBExternalID(tombstone.ExternalIDs, oldStr, newStr)
+			if !idChanged && !idsChanged {
+				continue
+			}
+			tombstone.ID, tombstone.ExternalIDs = id, externalIDs
+			delete(perUser, key)
+			moved = append(moved, normaliseTombstone(tombstone))
+		}
+		for _, tombstone := range moved {
+			if existing, found := takeMergedTombstone(perUser, tombstone.MediaType, tombstone.ID, tombstone.ExternalIDs); found {
+				tombstone = mergeTombstones(existing, tombstone)
+			}
+			perUser[tombstone.Key()] = tombstone
+		}
+		if len(moved) > 0 {
+			tombstonesChanged = true
+		}
+	}
+
+	if changed == 0 && !tombstonesChanged {
+		return 0, nil
+	}
+	if err := s.saveLocked(); err != nil {
+		return 0, err
+	}
+	return changed, nil
+}
@@ -0,0 +1,400 @@
+package watchparty
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrPartyNotFound  = errors.New("watch party not found")
+	ErrNotMember      = errors.New("profile has not joined this watch party")
+	ErrHostOnly       = errors.New("only the host can control playback in this watch party")
+	ErrNotHost        = errors.New("only the host can end this watch party")
+	ErrUnknownAction  = errors.New("unknown playback action")
+	ErrInvalidRequest = errors.New("invalid watch party request")
+)
+
+// Playback actions a member can broadcast.
+const (
+	ActionPlay  = "play"
+	ActionPause = "pause"
+	ActionSeek  = "seek"
+)
+
+// Event types pushed to members.
+const (
+	EventState  = "state"
+	EventJoined = "member_joined"
+	EventLeft   = "member_left"
+	EventEnded  = "ended"
+)
+
+const (
+	// partyIdleTTL is how long a party with nobody connected is kept around,
+	// so members can reconnect after a dropped connection or app restart.
+	partyIdleTTL = 30 * time.Minute
+	// subscriberBuffer is how many events a member may fall behind before it
+	// is disconnected.
+	subscriberBuffer = 32
+	codeLength       = 6
+	// codeAlphabet leaves out characters that are easy to confuse on a TV.
+	codeAlphabet = "ABCDEFGHJKMNPQRSTUVWXYZ23456789"
+)
+
+// State is the shared playback position. While playing, the position advances
+// with the clock from UpdatedAt.
+type State struct {
+	Position  float64   `json:"position"` // seconds
+	Paused    bool      `json:"paused"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Member is a profile that has joined a party.
+type Member struct {
+	UserID    string    `json:"userId"`
+	Name      string    `json:"name"`
+	IsHost    bool      `json:"isHost"`
+	Connected bool      `json:"connected"`
+	JoinedAt  time.Time `json:"joinedAt"`
+}
+
+// Party is a shared playback session.
+type Party struct {
+	ID         string    `json:"id"` // short join code
+	HostUserID string    `json:"hostUserId"`
+	HostOnly   bool      `json:"hostOnly"`
+	MediaID    string    `json:"mediaId"`
+	Title      string    `json:"title,omitempty"`
+	State      State     `json:"state"`
+	Members    []Member  `json:"members"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// Event is pushed to every connected member when the party changes.
+type Event struct {
+	Type       string    `json:"type"`
+	Action     string    `json:"action,omitempty"`
+	By         string    `json:"by,omitempty"` // profile ID that caused the event
+	State      State     `json:"state"`
+	Members    []Member  `json:"members,omitempty"`
+	ServerTime time.Time `json:"serverTime"`
+}
+
+// CreateRequest describes a new party.
+type CreateRequest struct {
+	HostUserID string
+	HostName   string
+	MediaID    string
+	Title      string
+	HostOnly   bool
+	Position   float64
+	Paused     bool
+}
+
+// Subscription receives a member's events until it is closed.
+type Subscription struct {
+	PartyID string
+	UserID  string
+	Events  <-chan Event
+
+	events chan Event
+	closed bool
+}
+
+type party struct {
+	Party
+	members     map[string]*Member
+	subscribers map[*Subscription]struct{}
+	idleSince   time.Time
+}
+
+// Service keeps watch parties in memory. Parties do not survive a restart.
+type Service struct {
+	mu      sync.Mutex
+	parties map[string]*party
+	now     func() time.Time
+}
+
+// NewService creates an empty watch party service.
+func NewService() *Service {
+	return &Service{
+		parties: make(map[string]*party),
+		now:     time.Now,
+	}
+}
+
+// Create starts a party hosted by req.HostUserID.
+func (s *Service) Create(req CreateRequest) (Party, error) {
+	req.HostUserID = strings.TrimSpace(req.HostUserID)
+	req.MediaID = strings.TrimSpace(req.MediaID)
+	if req.HostUserID == "" || req.MediaID == "" {
+		return Party{}, fmt.Errorf("%w: host and media are required", ErrInvalidRequest)
+	}
+	if req.Position < 0 {
+		req.Position = 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now().UTC()
+	s.pruneLocked(now)
+
+	id, err := s.newCodeLocked()
+	if err != nil {
+		return Party{}, err
+	}
+	p := &party{
+		Party: Party{
+			ID:         id,
+			HostUserID: req.HostUserID,
+			HostOnly:   req.HostOnly,
+			MediaID:    req.MediaID,
+			Title:      strings.TrimSpace(req.Title),
+			State:      State{Position: req.Position, Paused: req.Paused, UpdatedAt: now},
+			CreatedAt:  now,
+		},
+		members:     make(map[string]*Member),
+		subscribers: make(map[*Subscription]struct{}),
+		idleSince:   now,
+	}
+	p.members[req.HostUserID] = &Member{UserID: req.HostUserID, Name: req.HostName, IsHost: true, JoinedAt: now}
+	s.parties[id] = p
+
+	return s.snapshotLocked(p, now), nil
+}
+
+// Get returns a party with its position brought up to date.
+func (s *Service) Get(id string) (Party, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.parties[normalizeCode(id)]
+	if !ok {
+		return Party{}, false
+	}
+	return s.snapshotLocked(p, s.now().UTC()), true
+}
+
+// Join adds a profile to a party and subscribes it to the party's events. The
+// first event on the subscription is the current state. A member may hold
+// several subscriptions, e.g. while a dropped connection times out.
+func (s *Service) Join(id, userID, name string) (*Subscription, error) {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return nil, fmt.Errorf("%w: profile is required", ErrInvalidRequest)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.parties[normalizeCode(id)]
+	if !ok {
+		return nil, ErrPartyNotFound
+	}
+	now := s.now().UTC()
+
+	_, known := p.members[userID]
+	if !known {
+		p.members[userID] = &Member{UserID: userID, Name: name, JoinedAt: now}
+	}
+
+	events := make(chan Event, subscriberBuffer)
+	sub := &Subscription{PartyID: p.ID, UserID: userID, Events: events, events: events}
+	p.subscribers[sub] = struct{}{}
+
+	snapshot := s.snapshotLocked(p, now)
+	sub.events <- Event{Type: EventState, State: snapshot.State, Members: snapshot.Members, ServerTime: now}
+	if !known {
+		s.broadcastLocked(p, Event{Type: EventJoined, By: userID, State: snapshot.State, Members: snapshot.Members, ServerTime: now}, sub)
+	}
+	return sub, nil
+}
+
+// Leave closes a subscription. The member stays on the party so it can
+// reconnect; the party is dropped once it has been idle for partyIdleTTL.
+func (s *Service) Leave(sub *Subscription) {
+	if sub == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.parties[sub.PartyID]
+	if !ok {
+		return
+	}
+	if _, ok := p.subscribers[sub]; !ok {
+		return
+	}
+	s.unsubscribeLocked(p, sub)
+
+	now := s.now().UTC()
+	if !s.connectedLocked(p, sub.UserID) {
+		snapshot := s.snapshotLocked(p, now)
+		s.broadcastLocked(p, Event{Type: EventLeft, By: sub.UserID, State: snapshot.State, Members: snapshot.Members, ServerTime: now}, nil)
+	}
+}
+
+// Control applies a play, pause or seek from a member and broadcasts the new
+// state to the party, sender included. In a host-only party only the host may
+// control playback. Play and pause take the position the sender paused or
+// resumed at, so members that drifted snap back in line.
+func (s *Service) Control(id, userID, action string, position float64) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.parties[normalizeCode(id)]
+	if !ok {
+		return State{}, ErrPartyNotFound
+	}
+	if _, ok := p.members[userID]; !ok {
+		return State{}, ErrNotMember
+	}
+	if p.HostOnly && userID != p.HostUserID {
+		return State{}, ErrHostOnly
+	}
+	if position < 0 {
+		position = 0
+	}
+
+	now := s.now().UTC()
+	state := State{Position: position, Paused: p.State.Paused, UpdatedAt: now}
+	switch action {
+	case ActionPlay:
+		state.Paused = false
+	case ActionPause:
+		state.Paused = true
+	case ActionSeek:
+	default:
+		return State{}, fmt.Errorf("%w: %q", ErrUnknownAction, action)
+	}
+	p.State = state
+
+	s.broadcastLocked(p, Event{Type: EventState, Action: action, By: userID, State: state, ServerTime: now}, nil)
+	return state, nil
+}
+
+// End closes a party for everyone. Only the host may end it.
+func (s *Service) End(id, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.parties[normalizeCode(id)]
+	if !ok {
+		return ErrPartyNotFound
+	}
+	if userID != p.HostUserID {
+		return ErrNotHost
+	}
+	s.endLocked(p, userID, s.now().UTC())
+	return nil
+}
+
+func (s *Service) endLocked(p *party, by string, now time.Time) {
+	s.broadcastLocked(p, Event{Type: EventEnded, By: by, State: currentState(p.State, now), ServerTime: now}, nil)
+	for sub := range p.subscribers {
+		s.unsubscribeLocked(p, sub)
+	}
+	delete(s.parties, p.ID)
+}
+
+// broadcastLocked sends event to every subscriber except skip. A subscriber
+// whose buffer is full is disconnected rather than allowed to stall the party.
+func (s *Service) broadcastLocked(p *party, event Event, skip *Subscription) {
+	for sub := range p.subscribers {
+		if sub == skip {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			s.unsubscribeLocked(p, sub)
+		}
+	}
+}
+
+// unsubscribeLocked disconnects sub. The party starts idling once its last
+// subscriber is gone, however it left.
+func (s *Service) unsubscribeLocked(p *party, sub *Subscription) {
+	delete(p.subscribers, sub)
+	if !sub.closed {
+		sub.closed = true
+		close(sub.events)
+	}
+	if len(p.subscribers) == 0 {
+		p.idleSince = s.now().UTC()
+	}
+}
+
+func (s *Service) connectedLocked(p *party, userID string) bool {
+	for sub := range p.subscribers {
+		if sub.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneLocked drops parties nobody has been connected to for partyIdleTTL.
+func (s *Service) pruneLocked(now time.Time) {
+	for _, p := range s.parties {
+		if len(p.subscribers) == 0 && now.Sub(p.idleSince) > partyIdleTTL {
+			delete(s.parties, p.ID)
+		}
+	}
+}
+
+func (s *Service) snapshotLocked(p *party, now time.Time) Party {
+	out := p.Party
+	out.State = currentState(p.State, now)
+	out.Members = make([]Member, 0, len(p.members))
+	for _, m := range p.members {
+		member := *m
+		member.Connected = s.connectedLocked(p, m.UserID)
+		out.Members = append(out.Members, member)
+	}
+	sort.Slice(out.Members, func(i, j int) bool {
+		if out.Members[i].IsHost != out.Members[j].IsHost {
+			return out.Members[i].IsHost
+		}
+		return out.Members[i].JoinedAt.Before(out.Members[j].JoinedAt)
+	})
+	return out
+}
+
+func (s *Service) newCodeLocked() (string, error) {
+	buf := make([]byte, codeLength)
+	for attempt := 0; attempt < 10; attempt++ {
+		if _, err := rand.Read(buf); err != nil {
+			return "", fmt.Errorf("generate party code: %w", err)
+		}
+		code := make([]byte, codeLength)
+		for i, b := range buf {
+			code[i] = codeAlphabet[int(b)%len(codeAlphabet)]
+		}
+		if _, taken := s.parties[string(code)]; !taken {
+			return string(code), nil
+		}
+	}
+	return "", errors.New("generate party code: no free code")
+}
+
+// currentState advances a playing state to now.
+func currentState(state State, now time.Time) State {
+	if !state.Paused && now.After(state.UpdatedAt) {
+		state.Position += now.Sub(state.UpdatedAt).Seconds()
+		state.UpdatedAt = now
+	}
+	return state
+}
+
+func normalizeCode(id string) string {
+	return strings.ToUpper(strings.TrimSpace(id))
+}
@@ -0,0 +1,187 @@
+package watchparty
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestService(now *time.Time) *Service {
+	svc := NewService()
+	svc.now = func() time.Time { return *now }
+	return svc
+}
+
+func receive(t *testing.T, sub *Subscription) Event {
+	t.Helper()
+	select {
+	case event, ok := <-sub.Events:
+		if !ok {
+			t.Fatal("subscription closed")
+		}
+		return event
+	default:
+		t.Fatal("expected an event")
+		return Event{}
+	}
+}
+
+func TestCreateAndJoin(t *testing.T) {
+	now := time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+	svc := newTestService(&now)
+
+	party, err := svc.Create(CreateRequest{HostUserID: "host", HostName: "Host", MediaID: "tmdb:movie:1", Position: 10})
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if len(party.ID) != codeLength {
+		t.Fatalf("expected a %d character code, got %q", codeLength, party.ID)
+	}
+
+	host, err := svc.Join(party.ID, "host", "Host")
+	if err != nil {
+		t.Fatalf("host join: %v", err)
+	}
+	if event := receive(t, host); event.Type != EventState || event.State.Position != 10 {
+		t.Fatalf("expected initial state at 10s, got %+v", event)
+	}
+
+	now = now.Add(5 * time.Second)
+	guest, err := svc.Join(party.ID, "guest", "Guest")
+	if err != nil {
+		t.Fatalf("guest join: %v", err)
+	}
+	if event := receive(t, guest); event.State.Position != 15 || len(event.Members) != 2 {
+		t.Fatalf("expected guest to start at 15s with 2 members, got %+v", event)
+	}
+	if event := receive(t, host); event.Type != EventJoined || event.By != "guest" {
+		t.Fatalf("expected host to see guest join, got %+v", event)
+	}
+
+	if _, err := svc.Join("nope", "guest", "Guest"); !errors.Is(err, ErrPartyNotFound) {
+		t.Fatalf("expected ErrPartyNotFound, got %v", err)
+	}
+}
+
+func TestControlBroadcastsAndFreezesOnPause(t *testing.T) {
+	now := time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+	svc := newTestService(&now)
+	party, _ := svc.Create(CreateRequest{HostUserID: "host", MediaID: "m"})
+	host, _ := svc.Join(party.ID, "host", "")
+	guest, _ := svc.Join(party.ID, "guest", "")
+	receive(t, host)
+	receive(t, host)
+	receive(t, guest)
+
+	if _, err := svc.Control(party.ID, "guest", ActionPause, 42); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	for _, sub := range []*Subscription{host, guest} {
+		if event := receive(t, sub); event.Action != ActionPause || !event.State.Paused || event.State.Position != 42 {
+			t.Fatalf("unexpected pause event %+v", event)
+		}
+	}
+
+	now = now.Add(time.Minute)
+	if got, _ := svc.Get(party.ID); got.State.Position != 42 {
+		t.Fatalf("paused position moved to %v", got.State.Position)
+	}
+
+	if _, err := svc.Control(party.ID, "host", "rewind", 0); !errors.Is(err, ErrUnknownAction) {
+		t.Fatalf("expected ErrUnknownAction, got %v", err)
+	}
+	if _, err := svc.Control(party.ID, "stranger", ActionPlay, 0); !errors.Is(err, ErrNotMember) {
+		t.Fatalf("expected ErrNotMember, got %v", err)
+	}
+}
+
+func TestHostOnlyParty(t *testing.T) {
+	now := time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+	svc := newTestService(&now)
+	party, _ := svc.Create(CreateRequest{HostUserID: "host", MediaID: "m", HostOnly: true})
+	if _, err := svc.Join(party.ID, "guest", ""); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+
+	if _, err := svc.Control(party.ID, "guest", ActionSeek, 100); !errors.Is(err, ErrHostOnly) {
+		t.Fatalf("expected ErrHostOnly, got %v", err)
+	}
+	if _, err := svc.Control(party.ID, "host", ActionSeek, 100); err != nil {
+		t.Fatalf("host seek: %v", err)
+	}
+	if err := svc.End(party.ID, "guest"); !errors.Is(err, ErrNotHost) {
+		t.Fatalf("expected ErrNotHost, got %v", err)
+	}
+}
+
+func TestEndClosesSubscriptions(t *testing.T) {
+	now := time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+	svc := newTestService(&now)
+	party, _ := svc.Create(CreateRequest{HostUserID: "host", MediaID: "m"})
+	guest, _ := svc.Join(party.ID, "guest", "")
+	receive(t, guest)
+
+	if err := svc.End(party.ID, "host"); err != nil {
+		t.Fatalf("end: %v", err)
+	}
+	if event := receive(t, guest); event.Type != EventEnded {
+		t.Fatalf("expected ended event, got %+v", event)
+	}
+	if _, ok := <-guest.Events; ok {
+		t.Fatal("expected subscription to be closed")
+	}
+	if _, ok := svc.Get(party.ID); ok {
+		t.Fatal("expected party to be gone")
+	}
+	svc.Leave(guest) // must not panic on a closed subscription
+}
+
+func TestIdlePartiesArePruned(t *testing.T) {
+	now := time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+	svc := newTestService(&now)
+	idle, _ := svc.Create(CreateRequest{HostUserID: "host", MediaID: "m"})
+	active, _ := svc.Create(CreateRequest{HostUserID: "other", MediaID: "m"})
+	if _, err := svc.Join(active.ID, "other", ""); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+
+	now = now.Add(partyIdleTTL + time.Minute)
+	if _, err := svc.Create(CreateRequest{HostUserID: "third", MediaID: "m"}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if _, ok := svc.Get(idle.ID); ok {
+		t.Fatal("expected idle party to be pruned")
+	}
+	if _, ok := svc.Get(active.ID); !ok {
+		t.Fatal("expected party with a connected member to be kept")
+	}
+}
+
+func TestDroppedSubscriberStartsIdleTimer(t *testing.T) {
+	now := time.Date(2026, 1, 1, 20, 0, 0, 0, time.UTC)
+	svc := newTestService(&now)
+	party, _ := svc.Create(CreateRequest{HostUserID: "host", MediaID: "m"})
+	if _, err := svc.Join(party.ID, "guest", ""); err != nil {
+		t.Fatalf("join: %v", err)
+	}
+
+	// A guest that stops reading is dropped once its buffer overflows.
+	now = now.Add(partyIdleTTL)
+	for i := 0; i <= subscriberBuffer; i++ {
+		if _, err := svc.Control(party.ID, "guest", ActionSeek, float64(i)); err != nil {
+			t.Fatalf("control: %v", err)
+		}
+	}
+
+	now = now.Add(time.Minute)
+	svc.Create(CreateRequest{HostUserID: "third", MediaID: "m"})
+	if _, ok := svc.Get(party.ID); !ok {
+		t.Fatal("expected the party to idle from when its last subscriber was dropped")
+	}
+
+	now = now.Add(partyIdleTTL)
+	svc.Create(CreateRequest{HostUserID: "fourth", MediaID: "m"})
+	if _, ok := svc.Get(party.ID); ok {
+		t.Fatal("expected the party to be pruned after idling")
+	}
+}
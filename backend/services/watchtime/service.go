@@ -0,0 +1,364 @@
+// Package watchtime tracks how long each profile spends in playback per day
+// and checks it against optional weekly screen-time goals. Goals send soft
+// warnings through the watch time scheduled task; kids profiles can have an
+// admin-enforced hard limit that refuses playback once the week is used up.
+package watchtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"novastream/internal/datastore"
+	"novastream/models"
+)
+
+var (
+	ErrStorageDirRequired = errors.New("storage directory not provided")
+	ErrUserIDRequired     = errors.New("user id is required")
+	ErrInvalidGoal        = errors.New("invalid watch time goal")
+	ErrGoalNotFound       = errors.New("watch time goal not found")
+	ErrLimitReached       = errors.New("weekly watch time limit reached")
+)
+
+const (
+	// DefaultWarnPercent is the share of a goal that triggers the soft
+	// warning when the goal doesn't set one.
+	DefaultWarnPercent = 80
+	// maxWeeklyMinutes is the number of minutes in a week.
+	maxWeeklyMinutes = 7 * 24 * 60
+	// usageRetention is how long daily totals are kept.
+	usageRetention = 8 * 7 * 24 * time.Hour
+	dayLayout      = "2006-01-02"
+)
+
+// Service stores watch time goals and daily playback totals.
+type Service struct {
+	mu    sync.RWMutex
+	path  string
+	store *datastore.DataStore
+	goals map[string]models.WatchTimeGoal // userID -> goal
+	usage map[string]map[string]float64   // userID -> YYYY-MM-DD -> seconds
+}
+
+type fileData struct {
+	Goals []models.WatchTimeGoal `json:"goals"`
+	Days  []models.WatchTimeDay  `json:"days"`
+}
+
+// useDB returns true when the service is backed by PostgreSQL.
+func (s *Service) useDB() bool { return s.store != nil }
+
+// NewServiceWithStore creates a watch time service backed by PostgreSQL.
+func NewServiceWithStore(store *datastore.DataStore) (*Service, error) {
+	svc := &Service{
+		store: store,
+		goals: make(map[string]models.WatchTimeGoal),
+		usage: make(map[string]map[string]float64),
+	}
+	if err := svc.load(); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+// NewService creates a watch time service storing data inside the provided directory.
+func NewService(storageDir string) (*Service, error) {
+	if strings.TrimSpace(storageDir) == "" {
+		return nil, ErrStorageDirRequired
+	}
+	if err := os.MkdirAll(storageDir, 0o755); err != nil {
+		return nil, fmt.Errorf("create watch time dir: %w", err)
+	}
+
+	svc := &Service{
+		path:  filepath.Join(storageDir, "watch_time.json"),
+		goals: make(map[string]models.WatchTimeGoal),
+		usage: make(map[string]map[string]float64),
+	}
+	if err := svc.load(); err != nil {
+		return nil, err
+	}
+	return svc, nil
+}
+
+// WeekStart returns the start of the week containing t: Monday at midnight
+// in the server's time zone.
+func WeekStart(t time.Time) time.Time {
+	t = t.In(time.Local)
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.Local)
+}
+
+// Record adds seconds of playback by userID at the given time. The history
+// service calls it with the time each progress heartbeat adds.
+func (s *Service) Record(userID string, seconds float64, at time.Time) error {
+	userID = strings.TrimSpace(userID)
+	if userID == "" {
+		return ErrUserIDRequired
+	}
+	if seconds <= 0 || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		return nil
+	}
+	day := at.In(time.Local).Format(dayLayout)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	days := s.usage[userID]
+	if days == nil {
+		days = make(map[string]float64)
+		s.usage[userID] = days
+	}
+	days[day] += seconds
+	cutoff := at.Add(-usageRetention).In(time.Local).Format(dayLayout)
+	for date := range days {
+		if date < cutoff {
+			delete(days, date)
+		}
+	}
+
+	if s.useDB() {
+		ctx := context.Background()
+		if err := s.store.WatchTime().UpsertDay(ctx, &models.WatchTimeDay{UserID: userID, Date: day, Seconds: days[day]}); err != nil {
+			return err
+		}
+		return s.store.WatchTime().DeleteDaysBefore(ctx, cutoff)
+	}
+	return s.saveLocked()
+}
+
+// normalizeGoal validates a goal and fills in its defaults.
+func normalizeGoal(goal models.WatchTimeGoal) (models.WatchTimeGoal, error) {
+	goal.UserID = strings.TrimSpace(goal.UserID)
+	if goal.UserID == "" {
+		return models.WatchTimeGoal{}, ErrUserIDRequired
+	}
+	if goal.WeeklyMinutes <= 0 || goal.WeeklyMinutes > maxWeeklyMinutes {
+		return models.WatchTimeGoal{}, fmt.Errorf("%w: weekly minutes must be between 1 and %d", ErrInvalidGoal, maxWeeklyMinutes)
+	}
+	if goal.WarnPercent < 0 || goal.WarnPercent > 100 {
+		return models.WatchTimeGoal{}, fmt.Errorf("%w: warning percent must be between 0 and 100", ErrInvalidGoal)
+	}
+	if goal.WarnPercent == DefaultWarnPercent {
+		goal.WarnPercent = 0
+	}
+	return goal, nil
+}
+
+// SetGoal creates or replaces a profile's goal. Callers decide who may set
+// HardLimit; the service stores it as given.
+func (s *Service) SetGoal(goal models.WatchTimeGoal) (models.WatchTimeGoal, error) {
+	goal, err := normalizeGoal(goal)
+	if err != nil {
+		return models.WatchTimeGoal{}, err
+	}
+	goal.UpdatedAt = time.Now().UTC()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, existed := s.goals[goal.UserID]
+	s.goals[goal.UserID] = goal
+	var saveErr error
+	if s.useDB() {
+		saveErr = s.store.WatchTime().UpsertGoal(context.Background(), &goal)
+	} else {
+		saveErr = s.saveLocked()
+	}
+	if saveErr != nil {
+		if existed {
+			s.goals[goal.UserID] = previous
+		} else {
+			delete(s.goals, goal.UserID)
+		}
+		return models.WatchTimeGoal{}, saveErr
+	}
+	return goal, nil
+}
+
+// Goal returns userID's goal, if any.
+func (s *Service) Goal(userID string) (models.WatchTimeGoal, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	goal, ok := s.goals[strings.TrimSpace(userID)]
+	return goal, ok
+}
+
+// DeleteGoal removes userID's goal. The recorded playback time is kept.
+func (s *Service) DeleteGoal(userID string) error {
+	userID = strings.TrimSpace(userID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	goal, ok := s.goals[userID]
+	if !ok {
+		return ErrGoalNotFound
+	}
+	delete(s.goals, userID)
+	var err error
+	if s.useDB() {
+		err = s.store.WatchTime().DeleteGoal(context.Background(), userID)
+	} else {
+		err = s.saveLocked()
+	}
+	if err != nil {
+		s.goals[userID] = goal
+		return err
+	}
+	return nil
+}
+
+// Summary reports userID's playback time for the week containing now.
+func (s *Service) Summary(userID string, now time.Time) models.WatchTimeSummary {
+	userID = strings.TrimSpace(userID)
+	start := WeekStart(now)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	summary := models.WatchTimeSummary{WeekStart: start, Days: make([]models.WatchTimeDay, 0, 7), Status: models.WatchTimeStatusNone}
+	var seconds float64
+	for i := 0; i < 7; i++ {
+		date := start.AddDate(0, 0, i).Format(dayLayout)
+		daySeconds := s.usage[userID][date]
+		seconds += daySeconds
+		summary.Days = append(summary.Days, models.WatchTimeDay{Date: date, Seconds: daySeconds})
+	}
+	summary.Minutes = int(seconds / 60)
+
+	goal, ok := s.goals[userID]
+	if !ok {
+		return summary
+	}
+	summary.Goal = &goal
+	warnPercent := goal.WarnPercent
+	if warnPercent == 0 {
+		warnPercent = DefaultWarnPercent
+	}
+	switch {
+	case summary.Minutes >= goal.WeeklyMinutes:
+		summary.Status = models.WatchTimeStatusReached
+		summary.Blocked = goal.HardLimit
+	case summary.Minutes*100 >= goal.WeeklyMinutes*warnPercent:
+		summary.Status = models.WatchTimeStatusWarning
+	default:
+		summary.Status = models.WatchTimeStatusOK
+	}
+	if remaining := goal.WeeklyMinutes - summary.Minutes; remaining > 0 {
+		summary.RemainingMinutes = remaining
+	}
+	return summary
+}
+
+// CheckPlayback returns ErrLimitReached when userID has a hard limit and
+// has used up this week's goal.
+func (s *Service) CheckPlayback(userID string, now time.Time) error {
+	if s.Summary(userID, now).Blocked {
+		return ErrLimitReached
+	}
+	return nil
+}
+
+func (s *Service) load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var data fileData
+	if s.useDB() {
+		ctx := context.Background()
+		goals, err := s.store.WatchTime().ListGoals(ctx)
+		if err != nil {
+			return fmt.Errorf("load watch time goals from db: %w", err)
+		}
+		cutoff := time.Now().Add(-usageRetention).In(time.Local).Format(dayLayout)
+		days, err := s.store.WatchTime().ListDays(ctx, cutoff)
+		if err != nil {
+			return fmt.Errorf("load watch time from db: %w", err)
+		}
+		data = fileData{Goals: goals, Days: days}
+	} else {
+		file, err := os.Open(s.path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("open watch time file: %w", err)
+		}
+		defer file.Close()
+		if err := json.NewDecoder(file).Decode(&data); err != nil {
+			return fmt.Errorf("decode watch time: %w", err)
+		}
+	}
+
+	for _, goal := range data.Goals {
+		if strings.TrimSpace(goal.UserID) != "" {
+			s.goals[goal.UserID] = goal
+		}
+	}
+	for _, day := range data.Days {
+		if strings.TrimSpace(day.UserID) == "" || day.Seconds <= 0 {
+			continue
+		}
+		if s.usage[day.UserID] == nil {
+			s.usage[day.UserID] = make(map[string]float64)
+		}
+		s.usage[day.UserID][day.Date] = day.Seconds
+	}
+	return nil
+}
+
+func (s *Service) saveLocked() error {
+	data := fileData{Goals: make([]models.WatchTimeGoal, 0, len(s.goals))}
+	for _, goal := range s.goals {
+		data.Goals = append(data.Goals, goal)
+	}
+	sort.Slice(data.Goals, func(i, j int) bool { return data.Goals[i].UserID < data.Goals[j].UserID })
+	for userID, days := range s.usage {
+		for date, seconds := range days {
+			data.Days = append(data.Days, models.WatchTimeDay{UserID: userID, Date: date, Seconds: seconds})
+		}
+	}
+	sort.Slice(data.Days, func(i, j int) bool {
+		if data.Days[i].UserID != data.Days[j].UserID {
+			return data.Days[i].UserID < data.Days[j].UserID
+		}
+		return data.Days[i].Date < data.Days[j].Date
+	})
+
+	tmp := s.path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("create watch time temp file: %w", err)
+	}
+
+	enc := json.NewEncoder(file)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(data); err != nil {
+		file.Close()
+		_ = os.Remove(tmp)
+		return fmt.Errorf("encode watch time: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		_ = os.Remove(tmp)
+		return fmt.Errorf("sync watch time: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("close watch time temp file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("replace watch time file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package watchtime
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"novastream/models"
+)
+
+func TestWeekStartIsMonday(t *testing.T) {
+	sunday := time.Date(2026, 10, 18, 21, 30, 0, 0, time.Local)
+	if got, want := WeekStart(sunday), time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local); !got.Equal(want) {
+		t.Fatalf("WeekStart(%v) = %v, want %v", sunday, got, want)
+	}
+	monday := time.Date(2026, 10, 12, 0, 0, 0, 0, time.Local)
+	if got := WeekStart(monday); !got.Equal(monday) {
+		t.Fatalf("WeekStart(%v) = %v", monday, got)
+	}
+}
+
+func TestSummaryStatusesAndHardLimit(t *testing.T) {
+	svc, err := NewService(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.Local)
+	if got := svc.Summary("kids", now); got.Status != models.WatchTimeStatusNone || got.Goal != nil {
+		t.Fatalf("expected no goal, got %+v", got)
+	}
+	if _, err := svc.SetGoal(models.WatchTimeGoal{UserID: "kids", WeeklyMinutes: 0}); !errors.Is(err, ErrInvalidGoal) {
+		t.Fatalf("expected ErrInvalidGoal, got %v", err)
+	}
+	if _, err := svc.SetGoal(models.WatchTimeGoal{UserID: "kids", WeeklyMinutes: 100, HardLimit: true}); err != nil {
+		t.Fatalf("SetGoal: %v", err)
+	}
+
+	// Last week's playback doesn't count towards this week.
+	if err := svc.Record("kids", 3600, now.AddDate(0, 0, -7)); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if err := svc.Record("kids", 50*60, now.AddDate(0, 0, -1)); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if got := svc.Summary("kids", now); got.Status != models.WatchTimeStatusOK || got.Minutes != 50 || got.RemainingMinutes != 50 {
+		t.Fatalf("expected ok at 50 minutes, got %+v", got)
+	}
+
+	if err := svc.Record("kids", 35*60, now); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if got := svc.Summary("kids", now); got.Status != models.WatchTimeStatusWarning || got.Blocked {
+		t.Fatalf("expected a warning at 85 minutes, got %+v", got)
+	}
+	if err := svc.CheckPlayback("kids", now); err != nil {
+		t.Fatalf("expected playback below the limit, got %v", err)
+	}
+
+	if err := svc.Record("kids", 15*60, now); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	got := svc.Summary("kids", now)
+	if got.Status != models.WatchTimeStatusReached || !got.Blocked || got.RemainingMinutes != 0 {
+		t.Fatalf("expected the limit reached, got %+v", got)
+	}
+	if len(got.Days) != 7 || got.Days[1].Seconds != 50*60 || got.Days[2].Seconds != 50*60 {
+		t.Fatalf("unexpected daily breakdown %+v", got.Days)
+	}
+	if err := svc.CheckPlayback("kids", now); !errors.Is(err, ErrLimitReached) {
+		t.Fatalf("expected ErrLimitReached, got %v", err)
+	}
+	if err := svc.CheckPlayback("kids", now.AddDate(0, 0, 7)); err != nil {
+		t.Fatalf("expected a new week to allow playback, got %v", err)
+	}
+}
+
+func TestServicePersistsGoalsAndUsage(t *testing.T) {
+	dir := t.TempDir()
+	svc, err := NewService(dir)
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+	now := time.Now()
+	if _, err := svc.SetGoal(models.WatchTimeGoal{UserID: "adult", WeeklyMinutes: 600, WarnPercent: 80}); err != nil {
+		t.Fatalf("SetGoal: %v", err)
+	}
+	if err := svc.Record("adult", 1800, now); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	reloaded, err := NewService(dir)
+	if err != nil {
+		t.Fatalf("reload: %v", err)
+	}
+	goal, ok := reloaded.Goal("adult")
+	if !ok || goal.WeeklyMinutes != 600 || goal.WarnPercent != 0 {
+		t.Fatalf("unexpected reloaded goal %+v", goal)
+	}
+	if got := reloaded.Summary("adult", now).Minutes; got != 30 {
+		t.Fatalf("expected 30 minutes after reload, got %d", got)
+	}
+
+	if err := reloaded.DeleteGoal("adult"); err != nil {
+		t.Fatalf("DeleteGoal: %v", err)
+	}
+	if err := reloaded.DeleteGoal("adult"); !errors.Is(err, ErrGoalNotFound) {
+		t.Fatalf("expected ErrGoalNotFound, got %v", err)
+	}
+}
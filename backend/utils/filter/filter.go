@@ -535,6 +535,7 @@ func ResultsWithDetails(results []models.NZBResult, opts Options) []FilteredResu
 		// Check HDR/DV status
 		hasHDR := len(parsed.HDR) > 0
 		hasDV := hasDolbyVision(parsed.HDR)
+		media := ParseSourceMediaInfo(result.Title, parsed)
 
 		// Apply HDR/DV policy filtering
 		// "none" = exclude all HDR/DV (only SDR allowed)
@@ -551,14 +552,21 @@ func ResultsWithDetails(results []models.NZBResult, opts Options) []FilteredResu
 			}
 		case HDRDVPolicyIncludeHDR:
 			// Allow SDR, HDR, and DV with HDR fallback
-			// DV profile 5 (no HDR fallback) detection requires ffprobe and happens during prequeue
-			// Text-based filtering can't reliably detect DV profile, so we allow all DV here
-			// and let the probe phase reject incompatible profiles
+			// Releases explicitly tagged DV profile 5 are rejected here; otherwise the
+			// profile can't be reliably read from the name, so the probe phase during
+			// prequeue rejects the remaining incompatible profiles
+			if media != nil && media.IsDolbyVisionProfile5() && !media.DVProfileInferred {
+				reason := "HDR/DV policy excludes DV profile 5 (no HDR fallback)"
+				log.Printf("[filter] Rejecting %q: %s", result.Title, reason)
+				reject(result, reason)
+				continue
+			}
 		case HDRDVPolicyIncludeHDRDV:
 			// Allow everything - no HDR/DV filtering
 		}
 
 		// Store HDR info in attributes for downstream sorting
+		result.Media = media
 		if result.Attributes == nil {
 			result.Attributes = make(map[string]string)
 		}
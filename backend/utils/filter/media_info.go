@@ -0,0 +1,108 @@
+package filter
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"novastream/models"
+	"novastream/utils/parsett"
+)
+
+var (
+	// dvProfileRegex captures an explicit DV profile tag (e.g., "DV.P5", "DoVi P07", "Dolby Vision Profile 8")
+	dvProfileRegex = regexp.MustCompile(`(?i)\b(?:dv|dovi|dolby[\s._-]*vision)[\s._-]*p(?:rofile)?[\s._-]*0?([578])\b`)
+	// hlgRegex matches HLG releases, which the title parser doesn't report
+	hlgRegex = regexp.MustCompile(`(?i)\bhlg\b`)
+	// bitDepthRegex matches the parser's bit depth ("10bit", "12bit")
+	bitDepthRegex = regexp.MustCompile(`(\d+)`)
+)
+
+// ParseSourceMediaInfo extracts the HDR format, Dolby Vision profile, bit
+// depth and audio layout advertised by a release name. It returns nil when
+// the name carries none of them.
+func ParseSourceMediaInfo(title string, parsed *parsett.ParsedTitle) *models.SourceMediaInfo {
+	if parsed == nil {
+		return nil
+	}
+
+	info := &models.SourceMediaInfo{}
+	seen := make(map[string]bool)
+	addFormat := func(format string) {
+		if !seen[format] {
+			seen[format] = true
+			info.HDRFormats = append(info.HDRFormats, format)
+		}
+	}
+	for _, format := range parsed.HDR {
+		if normalized := normalizeHDRFormat(format); normalized != "" {
+			addFormat(normalized)
+		}
+	}
+	if hlgRegex.MatchString(title) {
+		addFormat(models.HDRFormatHLG)
+	}
+	sortHDRFormats(info.HDRFormats)
+
+	if info.HasDolbyVision() {
+		if m := dvProfileRegex.FindStringSubmatch(title); m != nil {
+			info.DVProfile, _ = strconv.Atoi(m[1])
+		} else if !info.HasHDRFallback() {
+			// DV with no HDR10 layer named is almost always a profile 5 WEB release.
+			info.DVProfile = 5
+			info.DVProfileInferred = true
+		}
+	}
+
+	if m := bitDepthRegex.FindString(parsed.BitDepth); m != "" {
+		info.BitDepth, _ = strconv.Atoi(m)
+	} else if len(info.HDRFormats) > 0 {
+		// Every HDR format requires at least 10-bit video.
+		info.BitDepth = 10
+	}
+
+	if len(parsed.Audio) > 0 {
+		info.AudioCodecs = append([]string(nil), parsed.Audio...)
+	}
+	if len(parsed.Channels) > 0 {
+		info.AudioChannels = parsed.Channels[0]
+	}
+
+	if len(info.HDRFormats) == 0 && info.BitDepth == 0 && len(info.AudioCodecs) == 0 && info.AudioChannels == "" {
+		return nil
+	}
+	return info
+}
+
+// normalizeHDRFormat maps a parser HDR tag onto one of the models.HDRFormat* values.
+func normalizeHDRFormat(format string) string {
+	lower := strings.ToLower(strings.TrimSpace(format))
+	switch {
+	case lower == "":
+		return ""
+	case lower == "dv" || strings.Contains(lower, "dolby") || strings.Contains(lower, "dovi"):
+		return models.HDRFormatDolbyVision
+	case strings.Contains(lower, "hdr10+") || strings.Contains(lower, "hdr10plus"):
+		return models.HDRFormatHDR10Plus
+	case strings.Contains(lower, "hdr"):
+		return models.HDRFormatHDR10
+	case lower == "hlg":
+		return models.HDRFormatHLG
+	}
+	return ""
+}
+
+// hdrFormatRank orders HDR formats from most to least capable.
+var hdrFormatRank = map[string]int{
+	models.HDRFormatDolbyVision: 0,
+	models.HDRFormatHDR10Plus:   1,
+	models.HDRFormatHDR10:       2,
+	models.HDRFormatHLG:         3,
+}
+
+func sortHDRFormats(formats []string) {
+	sort.SliceStable(formats, func(i, j int) bool {
+		return hdrFormatRank[formats[i]] < hdrFormatRank[formats[j]]
+	})
+}
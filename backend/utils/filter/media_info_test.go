@@ -0,0 +1,109 @@
+package filter
+
+import (
+	"reflect"
+	"testing"
+
+	"novastream/models"
+	"novastream/utils/parsett"
+)
+
+func TestParseSourceMediaInfo(t *testing.T) {
+	tests := []struct {
+		title        string
+		wantFormats  []string
+		wantProfile  int
+		wantInferred bool
+		wantBitDepth int
+		wantChannels string
+	}{
+		{
+			title:        "Movie.2024.2160p.WEB-DL.DDP5.1.Atmos.DV.HDR10+.H.265-GRP",
+			wantFormats:  []string{models.HDRFormatDolbyVision, models.HDRFormatHDR10Plus},
+			wantBitDepth: 10,
+			wantChannels: "5.1",
+		},
+		{
+			title:        "Movie.2024.2160p.WEB-DL.DDP5.1.DV.H.265-GRP",
+			wantFormats:  []string{models.HDRFormatDolbyVision},
+			wantProfile:  5,
+			wantInferred: true,
+			wantBitDepth: 10,
+			wantChannels: "5.1",
+		},
+		{
+			title:        "Movie.2024.2160p.BluRay.REMUX.DV.P7.HDR.TrueHD.7.1-GRP",
+			wantFormats:  []string{models.HDRFormatDolbyVision, models.HDRFormatHDR10},
+			wantProfile:  7,
+			wantBitDepth: 10,
+			wantChannels: "7.1",
+		},
+		{
+			title:        "Movie.2024.2160p.WEB-DL.DoVi.P5.12bit.x265-GRP",
+			wantFormats:  []string{models.HDRFormatDolbyVision},
+			wantProfile:  5,
+			wantBitDepth: 12,
+		},
+		{
+			title:        "Event.2024.2160p.HLG.UHDTV.x265-GRP",
+			wantFormats:  []string{models.HDRFormatHLG},
+			wantBitDepth: 10,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.title, func(t *testing.T) {
+			parsed, _ := parsett.ParseTitle(tc.title)
+			info := ParseSourceMediaInfo(tc.title, parsed)
+			if info == nil {
+				t.Fatal("expected media info")
+			}
+			if !reflect.DeepEqual(info.HDRFormats, tc.wantFormats) {
+				t.Errorf("HDRFormats = %v, want %v", info.HDRFormats, tc.wantFormats)
+			}
+			if info.DVProfile != tc.wantProfile || info.DVProfileInferred != tc.wantInferred {
+				t.Errorf("DV profile = %d (inferred %t), want %d (inferred %t)", info.DVProfile, info.DVProfileInferred, tc.wantProfile, tc.wantInferred)
+			}
+			if info.BitDepth != tc.wantBitDepth {
+				t.Errorf("BitDepth = %d, want %d", info.BitDepth, tc.wantBitDepth)
+			}
+			if info.AudioChannels != tc.wantChannels {
+				t.Errorf("AudioChannels = %q, want %q", info.AudioChannels, tc.wantChannels)
+			}
+		})
+	}
+
+	parsed, _ := parsett.ParseTitle("Movie.2024.1080p.BluRay.x264-GRP")
+	if info := ParseSourceMediaInfo("Movie.2024.1080p.BluRay.x264-GRP", parsed); info != nil {
+		t.Errorf("expected nil media info for a plain SDR release, got %+v", info)
+	}
+}
+
+func TestResultsWithDetails_HDRPolicyRejectsTaggedDVProfile5(t *testing.T) {
+	results := []models.NZBResult{
+		{Title: "Movie.2024.2160p.WEB-DL.DV.P5.H.265-GRP"},
+		{Title: "Movie.2024.2160p.WEB-DL.DV.H.265-GRP"},
+		{Title: "Movie.2024.2160p.BluRay.DV.P7.HDR.H.265-GRP"},
+	}
+	opts := Options{
+		ExpectedTitle: "Movie",
+		ExpectedYear:  2024,
+		IsMovie:       true,
+		HDRDVPolicy:   HDRDVPolicyIncludeHDR,
+	}
+
+	detailed := ResultsWithDetails(results, opts)
+	if len(detailed) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(detailed))
+	}
+	if detailed[0].Passed {
+		t.Error("expected explicitly tagged DV profile 5 to be filtered")
+	}
+	// An untagged DV release is only probably profile 5; the probe decides.
+	if !detailed[1].Passed || detailed[1].Result.Media == nil || !detailed[1].Result.Media.DVProfileInferred {
+		t.Errorf("expected untagged DV release to pass with an inferred profile, got %+v", detailed[1])
+	}
+	if !detailed[2].Passed || detailed[2].Result.Media.DVProfile != 7 {
+		t.Errorf("expected DV profile 7 release to pass, got %+v", detailed[2])
+	}
+}
@@ -0,0 +1,233 @@
+// Package locale formats dates, times and numbers for text the server renders
+// itself (notifications, digests) in the language and date format of the
+// instance or of a profile.
+package locale
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"novastream/config"
+	"novastream/models"
+)
+
+// Supported languages. Other languages fall back to English.
+const (
+	English = "en"
+	French  = "fr"
+)
+
+// Date orders for numeric dates.
+const (
+	DateFormatMDY = "mdy" // 01/31/2026
+	DateFormatDMY = "dmy" // 31/01/2026
+	DateFormatYMD = "ymd" // 2026-01-31
+)
+
+// Clock styles.
+const (
+	TimeFormat12h = "12h"
+	TimeFormat24h = "24h"
+)
+
+// Locale is a resolved language, date order and clock style.
+type Locale struct {
+	Language   string `json:"language"`
+	DateFormat string `json:"dateFormat"`
+	TimeFormat string `json:"timeFormat"`
+}
+
+type languageDefaults struct {
+	dateFormat        string
+	timeFormat        string
+	thousandSeparator string
+	today             string
+	tomorrow          string
+	yesterday         string
+	inDays            string // %d
+	daysAgo           string // %d
+	onWeekday         string // %s
+	weekdays          [7]string
+}
+
+var languages = map[string]languageDefaults{
+	English: {
+		dateFormat:        DateFormatMDY,
+		timeFormat:        TimeFormat12h,
+		thousandSeparator: ",",
+		today:             "today",
+		tomorrow:          "tomorrow",
+		yesterday:         "yesterday",
+		inDays:            "in %d days",
+		daysAgo:           "%d days ago",
+		onWeekday:         "on %s",
+		weekdays:          [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+	},
+	French: {
+		dateFormat:        DateFormatDMY,
+		timeFormat:        TimeFormat24h,
+		thousandSeparator: "\u202f", // narrow no-break space
+		today:             "aujourd'hui",
+		tomorrow:          "demain",
+		yesterday:         "hier",
+		inDays:            "dans %d jours",
+		daysAgo:           "il y a %d jours",
+		onWeekday:         "%s",
+		weekdays:          [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+	},
+}
+
+// Settings are locale values as stored in instance or profile settings.
+// Empty values are unset.
+type Settings struct {
+	Language   string // ISO 639-1, optionally with a region ("fr-CA")
+	DateFormat string
+	TimeFormat string
+}
+
+// Resolve returns the locale made of the first value each of settings sets,
+// so Resolve(profile, instance) lets a profile inherit what it leaves empty.
+// Unset date and time formats follow the resolved language's convention.
+func Resolve(settings ...Settings) Locale {
+	var language, dateFormat, timeFormat string
+	for _, s := range settings {
+		if language == "" {
+			language = strings.TrimSpace(s.Language)
+		}
+		if dateFormat == "" {
+			dateFormat = strings.ToLower(strings.TrimSpace(s.DateFormat))
+		}
+		if timeFormat == "" {
+			timeFormat = strings.ToLower(strings.TrimSpace(s.TimeFormat))
+		}
+	}
+
+	l := Locale{Language: normalizeLanguage(language)}
+	defaults := languages[l.Language]
+	switch dateFormat {
+	case DateFormatMDY, DateFormatDMY, DateFormatYMD:
+		l.DateFormat = dateFormat
+	default:
+		l.DateFormat = defaults.dateFormat
+	}
+	switch timeFormat {
+	case TimeFormat12h, TimeFormat24h:
+		l.TimeFormat = timeFormat
+	default:
+		l.TimeFormat = defaults.timeFormat
+	}
+	return l
+}
+
+// Instance returns the instance locale configured in the global display
+// settings.
+func Instance(display config.DisplaySettings) Locale {
+	return Resolve(instanceSettings(display))
+}
+
+// Profile returns a profile's locale. Values the profile leaves empty are
+// inherited from the instance.
+func Profile(profile models.DisplaySettings, instance config.DisplaySettings) Locale {
+	return Resolve(Settings{
+		Language:   profile.AppLanguage,
+		DateFormat: profile.DateFormat,
+		TimeFormat: profile.TimeFormat,
+	}, instanceSettings(instance))
+}
+
+func instanceSettings(display config.DisplaySettings) Settings {
+	return Settings{
+		Language:   display.AppLanguage,
+		DateFormat: display.DateFormat,
+		TimeFormat: display.TimeFormat,
+	}
+}
+
+func normalizeLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if base, _, ok := strings.Cut(strings.ReplaceAll(language, "_", "-"), "-"); ok {
+		language = base
+	}
+	if _, ok := languages[language]; ok {
+		return language
+	}
+	return English
+}
+
+func (l Locale) defaults() languageDefaults {
+	if defaults, ok := languages[l.Language]; ok {
+		return defaults
+	}
+	return languages[English]
+}
+
+// FormatDate formats the calendar date of t numerically in the locale's
+// date order.
+func (l Locale) FormatDate(t time.Time) string {
+	switch l.DateFormat {
+	case DateFormatDMY:
+		return t.Format("02/01/2006")
+	case DateFormatYMD:
+		return t.Format("2006-01-02")
+	default:
+		return t.Format("01/02/2006")
+	}
+}
+
+// FormatTime formats the time of day of t in the locale's clock style.
+func (l Locale) FormatTime(t time.Time) string {
+	if l.TimeFormat == TimeFormat24h {
+		return t.Format("15:04")
+	}
+	return t.Format("3:04 PM")
+}
+
+// FormatDateTime formats t as date and time.
+func (l Locale) FormatDateTime(t time.Time) string {
+	return l.FormatDate(t) + " " + l.FormatTime(t)
+}
+
+// FormatRelativeDay describes the day of t relative to now ("today",
+// "tomorrow", "on Friday", "in 9 days"), comparing calendar days in now's
+// location.
+func (l Locale) FormatRelativeDay(t, now time.Time) string {
+	d := l.defaults()
+	t = t.In(now.Location())
+	// Midnight UTC of each calendar day keeps DST changes out of the count.
+	day := func(v time.Time) time.Time { return time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, time.UTC) }
+	days := int(day(t).Sub(day(now)).Hours() / 24)
+	switch {
+	case days == 0:
+		return d.today
+	case days == 1:
+		return d.tomorrow
+	case days == -1:
+		return d.yesterday
+	case days > 1 && days < 7:
+		return fmt.Sprintf(d.onWeekday, d.weekdays[t.Weekday()])
+	case days > 0:
+		return fmt.Sprintf(d.inDays, days)
+	default:
+		return fmt.Sprintf(d.daysAgo, -days)
+	}
+}
+
+// FormatNumber formats an integer with the locale's thousands separator.
+func (l Locale) FormatNumber(n int64) string {
+	digits := strconv.FormatInt(n, 10)
+	negative := strings.HasPrefix(digits, "-")
+	digits = strings.TrimPrefix(digits, "-")
+	var b strings.Builder
+	if negative {
+		b.WriteByte('-')
+	}
+	for i, r := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteString(l.defaults().thousandSeparator)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
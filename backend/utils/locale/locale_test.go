@@ -0,0 +1,89 @@
+package locale
+
+import (
+	"testing"
+	"time"
+
+	"novastream/config"
+	"novastream/models"
+)
+
+func TestResolveFallsBackPerField(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings []Settings
+		want     Locale
+	}{
+		{"empty", nil, Locale{English, DateFormatMDY, TimeFormat12h}},
+		{"language defaults", []Settings{{Language: "fr-CA"}}, Locale{French, DateFormatDMY, TimeFormat24h}},
+		{"unsupported language", []Settings{{Language: "de"}}, Locale{English, DateFormatMDY, TimeFormat12h}},
+		{"explicit formats", []Settings{{Language: "fr", DateFormat: "YMD", TimeFormat: "12h"}}, Locale{French, DateFormatYMD, TimeFormat12h}},
+		{"invalid format", []Settings{{DateFormat: "dym"}}, Locale{English, DateFormatMDY, TimeFormat12h}},
+		{"inherits", []Settings{{TimeFormat: "24h"}, {Language: "en", DateFormat: "dmy", TimeFormat: "12h"}}, Locale{English, DateFormatDMY, TimeFormat24h}},
+	}
+	for _, tt := range tests {
+		if got := Resolve(tt.settings...); got != tt.want {
+			t.Errorf("%s: Resolve() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProfileInheritsInstance(t *testing.T) {
+	instance := config.DisplaySettings{AppLanguage: "fr", TimeFormat: "12h"}
+	if got := Profile(models.DisplaySettings{}, instance); got != (Locale{French, DateFormatDMY, TimeFormat12h}) {
+		t.Fatalf("expected the instance locale, got %+v", got)
+	}
+	if got := Profile(models.DisplaySettings{AppLanguage: "en"}, instance); got != (Locale{English, DateFormatMDY, TimeFormat12h}) {
+		t.Fatalf("expected the profile language with the instance clock, got %+v", got)
+	}
+}
+
+func TestFormatting(t *testing.T) {
+	ts := time.Date(2026, 1, 31, 15, 4, 0, 0, time.UTC)
+	en := Resolve(Settings{Language: "en"})
+	fr := Resolve(Settings{Language: "fr"})
+	ymd := Resolve(Settings{DateFormat: DateFormatYMD, TimeFormat: TimeFormat24h})
+
+	if got := en.FormatDateTime(ts); got != "01/31/2026 3:04 PM" {
+		t.Errorf("en FormatDateTime = %q", got)
+	}
+	if got := fr.FormatDateTime(ts); got != "31/01/2026 15:04" {
+		t.Errorf("fr FormatDateTime = %q", got)
+	}
+	if got := ymd.FormatDateTime(ts); got != "2026-01-31 15:04" {
+		t.Errorf("ymd FormatDateTime = %q", got)
+	}
+	for n, want := range map[int64]string{0: "0", 999: "999", 1000: "1,000", -1234567: "-1,234,567"} {
+		if got := en.FormatNumber(n); got != want {
+			t.Errorf("FormatNumber(%d) = %q, want %q", n, got, want)
+		}
+	}
+	if got := fr.FormatNumber(1234567); got != "1\u202f234\u202f567" {
+		t.Errorf("fr FormatNumber = %q", got)
+	}
+}
+
+func TestFormatRelativeDay(t *testing.T) {
+	now := time.Date(2026, 1, 28, 23, 30, 0, 0, time.UTC) // a Wednesday
+	en := Resolve(Settings{Language: "en"})
+	fr := Resolve(Settings{Language: "fr"})
+	tests := []struct {
+		t      time.Time
+		en, fr string
+	}{
+		{now.Add(40 * time.Minute), "tomorrow", "demain"},
+		{now.Add(-23 * time.Hour), "today", "aujourd'hui"},
+		{now.AddDate(0, 0, -1), "yesterday", "hier"},
+		{now.AddDate(0, 0, 2), "on Friday", "vendredi"},
+		{now.AddDate(0, 0, 9), "in 9 days", "dans 9 jours"},
+		{now.AddDate(0, 0, -3), "3 days ago", "il y a 3 jours"},
+	}
+	for _, tt := range tests {
+		if got := en.FormatRelativeDay(tt.t, now); got != tt.en {
+			t.Errorf("en FormatRelativeDay(%v) = %q, want %q", tt.t, got, tt.en)
+		}
+		if got := fr.FormatRelativeDay(tt.t, now); got != tt.fr {
+			t.Errorf("fr FormatRelativeDay(%v) = %q, want %q", tt.t, got, tt.fr)
+		}
+	}
+}
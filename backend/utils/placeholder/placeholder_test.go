@@ -0,0 +1,57 @@
+package placeholder
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func solidImage(width, height int, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestBlurHashOfSolidImage(t *testing.T) {
+	got := BlurHash(solidImage(32, 24, color.Black), 4, 3)
+	if want := "L00000fQfQfQfQfQfQfQfQfQfQfQ"; got != want {
+		t.Fatalf("BlurHash = %q, want %q", got, want)
+	}
+	if got := BlurHash(solidImage(0, 0, color.Black), 4, 3); got != "" {
+		t.Fatalf("BlurHash of empty image = %q, want empty", got)
+	}
+}
+
+func TestComputePicksComponentsByOrientation(t *testing.T) {
+	poster := solidImage(200, 300, color.NRGBA{R: 200, G: 40, B: 40, A: 255})
+	hash, dominant := Compute(poster)
+	// 3x4 components: size flag (3-1)+(4-1)*9 = 29 encodes as "T".
+	if len(hash) != 28 || hash[0] != 'T' {
+		t.Fatalf("poster hash = %q, want 28 chars starting with T", hash)
+	}
+	if dominant != "#c82828" {
+		t.Fatalf("dominant color = %q, want #c82828", dominant)
+	}
+
+	backdrop := solidImage(300, 169, color.White)
+	if hash, _ := Compute(backdrop); hash[0] != 'L' {
+		t.Fatalf("backdrop hash = %q, want 4x3 components", hash)
+	}
+}
+
+func TestDominantColorIgnoresTransparentPixels(t *testing.T) {
+	img := solidImage(10, 10, color.NRGBA{})
+	for x := 0; x < 3; x++ {
+		img.Set(x, 0, color.NRGBA{R: 10, G: 20, B: 30, A: 255})
+	}
+	if got := DominantColor(img); got != "#0a141e" {
+		t.Fatalf("dominant color = %q, want #0a141e", got)
+	}
+	if got := DominantColor(solidImage(4, 4, color.NRGBA{})); got != "" {
+		t.Fatalf("dominant color of transparent image = %q, want empty", got)
+	}
+}